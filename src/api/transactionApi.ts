@@ -68,6 +68,7 @@ import {
   UserProfile,
 } from '@/types/api';
 import { normalizeUsername } from '@/utils/username';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 // Transaction service URL from environment variables with fallback
 const TRANSACTION_SERVICE_URL =
@@ -92,8 +93,8 @@ const TRANSFER_LISTS_QUERY_KEY = 'transferLists';
 
 const toReadableError = (error: unknown): Error => {
   if (axios.isAxiosError(error)) {
-    const apiError = (error.response?.data as { error?: unknown } | undefined)?.error;
-    if (typeof apiError === 'string' && apiError.trim().length > 0) {
+    const apiError = getApiErrorMessage(error.response?.data);
+    if (apiError) {
       return new Error(apiError);
     }
   }
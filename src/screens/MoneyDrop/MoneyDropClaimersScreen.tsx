@@ -129,6 +129,14 @@ const MoneyDropClaimersScreen = () => {
                         <CalendarIcon width={12} height={12} color="#6C6B6B" />
                         <Text style={styles.claimerDate}>{formatDate(claimer.claimed_at)}</Text>
                       </View>
+
+                      {claimer.payout_status === 'payout_pending' ||
+                      claimer.payout_status === 'processing' ? (
+                        <Text style={styles.claimerPayoutStatus}>Payout on its way</Text>
+                      ) : null}
+                      {claimer.payout_status === 'failed' ? (
+                        <Text style={styles.claimerPayoutStatus}>Payout failed</Text>
+                      ) : null}
                     </View>
 
                     <Text
@@ -268,6 +276,12 @@ const styles = StyleSheet.create({
     fontSize: 12,
     fontFamily: 'Montserrat_400Regular',
   },
+  claimerPayoutStatus: {
+    marginTop: 4,
+    color: '#6C6B6B',
+    fontSize: 12,
+    fontFamily: 'Montserrat_400Regular',
+  },
   stateContainer: {
     alignItems: 'center',
     justifyContent: 'center',
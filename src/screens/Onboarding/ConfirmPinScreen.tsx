@@ -7,6 +7,7 @@ import type { AppStackParamList } from '@/types/navigation';
 import { submitTransactionPinSetup } from '@/api/authApi';
 import { useSecurityStore } from '@/store/useSecurityStore';
 import AuthConfirmPin from '@/components/source-auth/auth-confirm-pin';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 type RouteType = RouteProp<AppStackParamList, 'ConfirmPin'>;
 type Navigation = NativeStackNavigationProp<AppStackParamList, 'ConfirmPin'>;
@@ -21,14 +22,9 @@ const getPinSetupErrorMessage = (error: unknown): string => {
     return 'Please try again in a moment.';
   }
 
-  const data = response.data;
-  if (typeof data === 'object' && data !== null) {
-    if ('detail' in data && typeof data.detail === 'string') {
-      return data.detail;
-    }
-    if ('error' in data && typeof data.error === 'string') {
-      return data.error;
-    }
+  const message = getApiErrorMessage(response.data);
+  if (message) {
+    return message;
   }
 
   return 'Please try again in a moment.';
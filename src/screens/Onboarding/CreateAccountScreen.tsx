@@ -22,6 +22,7 @@ import FormInput from '@/components/FormInput';
 import { theme } from '@/constants/theme';
 import apiClient from '@/api/apiClient';
 import { useAuth, useUser } from '@clerk/clerk-expo';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 const isRecord = (value: unknown): value is Record<string, unknown> =>
   typeof value === 'object' && value !== null;
@@ -30,7 +31,7 @@ const extractBvnFailureMessage = (error: unknown): string | null => {
   const response = isRecord(error) ? error.response : undefined;
   const responseData = isRecord(response) ? response.data : undefined;
   const responseMessage = isRecord(responseData)
-    ? responseData.message || responseData.detail
+    ? responseData.message || getApiErrorMessage(responseData)
     : undefined;
   const errorMessage = isRecord(error) ? error.message : undefined;
   const message = responseMessage || errorMessage || 'BVN failed.';
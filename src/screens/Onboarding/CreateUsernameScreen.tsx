@@ -7,6 +7,7 @@ import type { AppStackParamList } from '@/types/navigation';
 import { submitUsernameSetup } from '@/api/authApi';
 import { USERNAME_REGEX, normalizeUsername } from '@/utils/username';
 import AuthCreateUsername from '@/components/source-auth/auth-create-username';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 type Navigation = NativeStackNavigationProp<AppStackParamList, 'CreateUsername'>;
 
@@ -29,14 +30,9 @@ const getUsernameSetupErrorMessage = (error: unknown): string => {
   if (typeof response !== 'object' || response === null || !('data' in response)) {
     return 'Please try again in a moment.';
   }
-  const data = response.data;
-  if (typeof data === 'object' && data !== null) {
-    if ('detail' in data && typeof data.detail === 'string') {
-      return data.detail;
-    }
-    if ('error' in data && typeof data.error === 'string') {
-      return data.error;
-    }
+  const message = getApiErrorMessage(response.data);
+  if (message) {
+    return message;
   }
   return 'Please try again in a moment.';
 };
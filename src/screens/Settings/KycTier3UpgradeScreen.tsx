@@ -20,6 +20,7 @@ import { submitTier3Upgrade } from '@/api/authApi';
 import type { ProfileStackParamList } from '@/types/navigation';
 import type { Tier3UpgradePayload } from '@/types/api';
 import theme from '@/constants/theme';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 type NavigationProp = NativeStackNavigationProp<ProfileStackParamList, 'KycTier3Upgrade'>;
 
@@ -50,12 +51,9 @@ const getMutationErrorMessage = (error: unknown): string => {
   if ('response' in error) {
     const response = error.response;
     if (typeof response === 'object' && response !== null && 'data' in response) {
-      const data = response.data;
-      if (typeof data === 'object' && data !== null && 'detail' in data) {
-        const detail = data.detail;
-        if (typeof detail === 'string' && detail.trim() !== '') {
-          return detail;
-        }
+      const message = getApiErrorMessage(response.data);
+      if (message) {
+        return message;
       }
     }
   }
@@ -7,6 +7,7 @@ import { completePinChange } from '@/api/authApi';
 import type { ProfileStackParamList } from '@/types/navigation';
 import { useSensitiveFlowStore } from '@/store/useSensitiveFlowStore';
 import PinStepTemplate from './components/PinStepTemplate';
+import { getApiErrorMessage } from '@/utils/apiError';
 
 type NavigationProp = NativeStackNavigationProp<ProfileStackParamList, 'PinVerify'>;
 
@@ -18,12 +19,9 @@ const getMutationErrorMessage = (error: unknown): string => {
   if ('response' in error) {
     const response = error.response;
     if (typeof response === 'object' && response !== null && 'data' in response) {
-      const data = response.data;
-      if (typeof data === 'object' && data !== null && 'detail' in data) {
-        const detail = data.detail;
-        if (typeof detail === 'string' && detail.trim() !== '') {
-          return detail;
-        }
+      const message = getApiErrorMessage(response.data);
+      if (message) {
+        return message;
       }
     }
   }
@@ -582,10 +582,13 @@ export interface ClaimMoneyDropPayload {
   idempotencyKey?: string;
 }
 
+export type MoneyDropPayoutStatus = 'payout_pending' | 'processing' | 'completed' | 'failed';
+
 export interface ClaimMoneyDropResponse {
   message: string;
   amount_claimed: number;
   creator_username: string;
+  payout_status?: MoneyDropPayoutStatus;
 }
 
 export interface RevealMoneyDropPasswordPayload {
@@ -619,6 +622,7 @@ export interface MoneyDropDashboardItem {
   amount_per_person: number;
   number_of_people: number;
   claims_made_count: number;
+  remaining_slots: number;
   time_left_label: string;
   users_claimed_label: string;
   expiry_timestamp: string;
@@ -640,6 +644,7 @@ export interface MoneyDropClaimer {
   profile_picture_url?: string;
   amount_claimed: number;
   claimed_at: string;
+  payout_status?: MoneyDropPayoutStatus;
 }
 
 export interface MoneyDropOwnerDetails {
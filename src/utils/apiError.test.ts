@@ -0,0 +1,33 @@
+import { getApiError, getApiErrorCode, getApiErrorMessage } from './apiError';
+
+describe('api error envelope', () => {
+  it('reads code, message and field details from the envelope', () => {
+    const data = {
+      error: {
+        code: 'validation_failed',
+        message: 'amount must be greater than zero',
+        details: [{ field: 'amount', message: 'amount must be greater than zero' }],
+      },
+    };
+
+    expect(getApiError(data)?.details).toEqual([
+      { field: 'amount', message: 'amount must be greater than zero' },
+    ]);
+    expect(getApiErrorCode(data)).toBe('validation_failed');
+    expect(getApiErrorMessage(data)).toBe('amount must be greater than zero');
+  });
+
+  it('falls back to legacy string bodies', () => {
+    expect(getApiErrorMessage({ error: 'Conflict', detail: 'username is already taken' })).toBe(
+      'username is already taken'
+    );
+    expect(getApiErrorMessage({ error: 'Insufficient funds' })).toBe('Insufficient funds');
+    expect(getApiErrorMessage('Unauthorized\n')).toBe('Unauthorized');
+    expect(getApiErrorCode({ error: 'Insufficient funds' })).toBeUndefined();
+  });
+
+  it('ignores bodies without a message', () => {
+    expect(getApiErrorMessage({ error: { code: 'internal_error' } })).toBeUndefined();
+    expect(getApiErrorMessage(undefined)).toBeUndefined();
+  });
+});
@@ -0,0 +1,56 @@
+/**
+ * Reads the JSON error envelope written by the Transfa services:
+ *
+ *   { "error": { "code": "insufficient_funds", "message": "...", "details": [...] } }
+ *
+ * Codes are listed in transfa-backend/pkg/apierror. Older responses that put a plain
+ * string in `error` or `detail` are still understood.
+ */
+
+export interface ApiFieldError {
+  field: string;
+  message: string;
+}
+
+export interface ApiErrorBody {
+  code: string;
+  message: string;
+  details?: ApiFieldError[];
+}
+
+const isRecord = (value: unknown): value is Record<string, unknown> =>
+  typeof value === 'object' && value !== null;
+
+const nonEmpty = (value: unknown): string | undefined =>
+  typeof value === 'string' && value.trim() !== '' ? value.trim() : undefined;
+
+export const getApiError = (data: unknown): ApiErrorBody | undefined => {
+  if (!isRecord(data) || !isRecord(data.error)) {
+    return undefined;
+  }
+  const code = nonEmpty(data.error.code);
+  const message = nonEmpty(data.error.message);
+  if (!code || !message) {
+    return undefined;
+  }
+  const details = Array.isArray(data.error.details)
+    ? data.error.details.filter(
+        (detail): detail is ApiFieldError =>
+          isRecord(detail) && typeof detail.field === 'string' && typeof detail.message === 'string'
+      )
+    : undefined;
+  return { code, message, details };
+};
+
+export const getApiErrorMessage = (data: unknown): string | undefined => {
+  const apiError = getApiError(data);
+  if (apiError) {
+    return apiError.message;
+  }
+  if (!isRecord(data)) {
+    return nonEmpty(data);
+  }
+  return nonEmpty(data.detail) ?? nonEmpty(data.error);
+};
+
+export const getApiErrorCode = (data: unknown): string | undefined => getApiError(data)?.code;
@@ -0,0 +1,45 @@
+/**
+ * Migration: create_favorite_recipients
+ *
+ * Description:
+ * - Lets users pin people they pay often, independent of transfer history.
+ * - Adds an index to serve the "recent recipients" DISTINCT ON lookup over completed P2P transfers.
+ */
+
+CREATE TABLE IF NOT EXISTS public.favorite_recipients (
+  owner_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  recipient_user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (owner_id, recipient_user_id),
+  CONSTRAINT favorite_recipients_not_self CHECK (owner_id <> recipient_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorite_recipients_owner_created
+  ON public.favorite_recipients(owner_id, created_at DESC);
+
+COMMENT ON TABLE public.favorite_recipients IS 'Recipients a user has pinned for quick transfers.';
+COMMENT ON COLUMN public.favorite_recipients.owner_id IS 'The user who pinned the recipient.';
+COMMENT ON COLUMN public.favorite_recipients.recipient_user_id IS 'The pinned user. Kept even if they can no longer receive funds.';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_p2p_completed_recipient
+  ON public.transactions(sender_id, recipient_id, created_at DESC)
+  WHERE type = 'p2p' AND status = 'completed' AND recipient_id IS NOT NULL;
+
+ALTER TABLE public.favorite_recipients ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can manage their own favorite recipients."
+ON public.favorite_recipients FOR ALL
+USING (
+  EXISTS (
+    SELECT 1 FROM public.users
+    WHERE users.id = favorite_recipients.owner_id
+      AND users.clerk_user_id = auth.uid()::text
+  )
+)
+WITH CHECK (
+  EXISTS (
+    SELECT 1 FROM public.users
+    WHERE users.id = favorite_recipients.owner_id
+      AND users.clerk_user_id = auth.uid()::text
+  )
+);
@@ -0,0 +1,31 @@
+/**
+ * Migration: add_savings_pots
+ *
+ * Description:
+ * Adds savings pots. A pot is a named account row with account_type = 'pot',
+ * backed by its own Anchor deposit account. Funds move between a user's primary
+ * account and their pots via book transfers recorded as 'pot_transfer'.
+ *
+ * Pots are closed rather than deleted so historical transactions that reference
+ * them keep their account link.
+ */
+
+ALTER TYPE public.account_type ADD VALUE IF NOT EXISTS 'pot';
+ALTER TYPE public.account_status ADD VALUE IF NOT EXISTS 'closed';
+ALTER TYPE public.transaction_type ADD VALUE IF NOT EXISTS 'pot_transfer';
+
+ALTER TABLE public.accounts
+ADD COLUMN IF NOT EXISTS name VARCHAR(50),
+ADD COLUMN IF NOT EXISTS target_amount BIGINT;
+
+ALTER TABLE public.accounts
+DROP CONSTRAINT IF EXISTS accounts_target_amount_positive;
+
+ALTER TABLE public.accounts
+ADD CONSTRAINT accounts_target_amount_positive CHECK (target_amount IS NULL OR target_amount > 0);
+
+COMMENT ON COLUMN public.accounts.name IS 'Display name for pot accounts. NULL for primary and money_drop accounts.';
+COMMENT ON COLUMN public.accounts.target_amount IS 'Optional savings goal for pot accounts, in kobo.';
+
+CREATE INDEX IF NOT EXISTS idx_accounts_user_type_created
+ON public.accounts(user_id, account_type, created_at DESC);
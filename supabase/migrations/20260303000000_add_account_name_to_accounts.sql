@@ -0,0 +1,14 @@
+/**
+ * Migration: add_account_name_to_accounts
+ *
+ * Description:
+ * Stores the account name registered with Anchor alongside the NUBAN and bank name
+ * so account details can be served without calling Anchor on every request. The
+ * column is backfilled lazily by account-service on read or when Anchor's
+ * account.opened webhook arrives.
+ */
+
+ALTER TABLE public.accounts
+ADD COLUMN IF NOT EXISTS account_name VARCHAR(255);
+
+COMMENT ON COLUMN public.accounts.account_name IS 'Account name registered with Anchor for this deposit account.';
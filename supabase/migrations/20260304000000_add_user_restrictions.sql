@@ -0,0 +1,43 @@
+/**
+ * Migration: add_user_restrictions
+ *
+ * Description:
+ * Lets compliance freeze a user while an investigation is open. A frozen user has
+ * both allow_sending and allow_receiving cleared; unfreezing restores receiving and
+ * resets sending to the default for the user's type.
+ *
+ * Every freeze/unfreeze is recorded in user_restriction_events together with the
+ * reason and the operator who performed it.
+ */
+
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS allow_receiving BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN public.users.allow_receiving IS 'When false, P2P transfers to this user are rejected. Cleared while the user is frozen.';
+
+CREATE TABLE IF NOT EXISTS public.user_restriction_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_user_restriction_events_action CHECK (action IN ('freeze', 'unfreeze')),
+    CONSTRAINT chk_user_restriction_events_reason CHECK (length(btrim(reason)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_restriction_events_user_created
+    ON public.user_restriction_events(user_id, created_at DESC);
+
+COMMENT ON TABLE public.user_restriction_events IS 'Audit trail of compliance freezes and unfreezes applied to users.';
+COMMENT ON COLUMN public.user_restriction_events.actor IS 'Identifier of the operator or system that applied the change.';
+
+ALTER TABLE public.user_restriction_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage user restriction events."
+ON public.user_restriction_events;
+
+CREATE POLICY "Service role can manage user restriction events."
+ON public.user_restriction_events FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
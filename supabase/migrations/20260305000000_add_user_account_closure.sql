@@ -0,0 +1,20 @@
+/**
+ * Migration: add_user_account_closure
+ *
+ * Description:
+ * Lets users close their own account. Closing sets users.closed_at and clears both
+ * allow_sending and allow_receiving; the row is kept so historical transactions
+ * still resolve their counterparties.
+ *
+ * A closed user's username stays reserved for 90 days after closure. After that it
+ * is released the next time another user claims it.
+ */
+
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN public.users.closed_at IS 'When the user closed their account. NULL while the account is open.';
+
+CREATE INDEX IF NOT EXISTS idx_users_closed_at
+    ON public.users(closed_at)
+    WHERE closed_at IS NOT NULL;
@@ -0,0 +1,35 @@
+/**
+ * Migration: create_username_history
+ *
+ * Description:
+ * Records every username change. Users may change their username at most once every
+ * 30 days. For 30 days after a change, the old username stays reserved and P2P
+ * lookups of it resolve to the user's new handle.
+ */
+
+CREATE TABLE IF NOT EXISTS public.username_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    old_username VARCHAR(50) NOT NULL,
+    new_username VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_username_history_changed CHECK (old_username <> new_username)
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_user_changed
+    ON public.username_history(user_id, changed_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_old_username_changed
+    ON public.username_history(lower(old_username), changed_at DESC);
+
+COMMENT ON TABLE public.username_history IS 'Audit trail of username changes, used for the 30-day change limit and old-handle redirects.';
+
+ALTER TABLE public.username_history ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage username history."
+ON public.username_history;
+
+CREATE POLICY "Service role can manage username history."
+ON public.username_history FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
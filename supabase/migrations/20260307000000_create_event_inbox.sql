@@ -0,0 +1,34 @@
+/**
+ * Migration: create_event_inbox
+ *
+ * Description:
+ * Persistent dedup store for event consumers. A consumer claims a message ID before
+ * doing any external work (for example creating an Anchor customer) so duplicate
+ * deliveries of the same event are dropped even across restarts and replicas.
+ *
+ * A claim is 'processing' while the consumer works on it and becomes 'completed' once
+ * the side effects are recorded. Failed attempts delete their claim so a later retry
+ * of the same event can run; a stale 'processing' claim can be taken over.
+ */
+
+CREATE TABLE IF NOT EXISTS public.event_inbox (
+    consumer TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'processing',
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    PRIMARY KEY (consumer, message_id),
+    CONSTRAINT chk_event_inbox_status CHECK (status IN ('processing', 'completed'))
+);
+
+COMMENT ON TABLE public.event_inbox IS 'Message IDs claimed or completed by event consumers, used to drop duplicate deliveries.';
+
+ALTER TABLE public.event_inbox ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage event inbox."
+ON public.event_inbox;
+
+CREATE POLICY "Service role can manage event inbox."
+ON public.event_inbox FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
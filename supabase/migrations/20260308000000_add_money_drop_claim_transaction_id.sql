@@ -0,0 +1,18 @@
+/**
+ * Migration: add_money_drop_claim_transaction_id
+ *
+ * Description:
+ * Links each money_drop_claims row to the money_drop_claim transaction created with
+ * it, so a claim transaction resolves to its drop with a direct lookup instead of
+ * parsing anchor_reason or matching on amount and timing. Rows written before this
+ * migration keep a NULL transaction_id and are still resolved by the old fallback.
+ */
+
+ALTER TABLE public.money_drop_claims
+ADD COLUMN IF NOT EXISTS transaction_id UUID REFERENCES public.transactions(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_money_drop_claims_transaction_id
+    ON public.money_drop_claims(transaction_id)
+    WHERE transaction_id IS NOT NULL;
+
+COMMENT ON COLUMN public.money_drop_claims.transaction_id IS 'The money_drop_claim transaction recorded together with this claim.';
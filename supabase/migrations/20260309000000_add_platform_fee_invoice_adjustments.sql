@@ -0,0 +1,40 @@
+/**
+ * Migration: add_platform_fee_invoice_adjustments
+ *
+ * Description:
+ * Lets support waive a platform fee invoice or correct its amount. Every waiver and
+ * amount change is recorded in platform_fee_invoice_adjustments together with the
+ * reason and the operator who made it.
+ *
+ * An invoice adjusted to zero is never charged and never becomes delinquent.
+ */
+
+CREATE TABLE IF NOT EXISTS public.platform_fee_invoice_adjustments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES public.platform_fee_invoices(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    previous_amount BIGINT NOT NULL,
+    new_amount BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_platform_fee_invoice_adjustments_action CHECK (action IN ('waive', 'adjust')),
+    CONSTRAINT chk_platform_fee_invoice_adjustments_new_amount CHECK (new_amount >= 0),
+    CONSTRAINT chk_platform_fee_invoice_adjustments_reason CHECK (length(btrim(reason)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_platform_fee_invoice_adjustments_invoice_created
+    ON public.platform_fee_invoice_adjustments(invoice_id, created_at DESC);
+
+COMMENT ON TABLE public.platform_fee_invoice_adjustments IS 'Audit trail of platform fee invoice waivers and amount corrections.';
+COMMENT ON COLUMN public.platform_fee_invoice_adjustments.actor IS 'Identifier of the operator who applied the change.';
+
+ALTER TABLE public.platform_fee_invoice_adjustments ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage platform fee invoice adjustments."
+ON public.platform_fee_invoice_adjustments;
+
+CREATE POLICY "Service role can manage platform fee invoice adjustments."
+ON public.platform_fee_invoice_adjustments FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,43 @@
+/**
+ * Migration: create_transaction_disputes
+ *
+ * Description:
+ * Lets a user flag a completed or failed transaction they took part in for review.
+ * Each user can raise at most one dispute per transaction; support works the queue
+ * through the internal disputes listing.
+ */
+
+CREATE TABLE IF NOT EXISTS public.transaction_disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES public.transactions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    dispute_reason VARCHAR(32) NOT NULL,
+    description TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_transaction_dispute_per_user UNIQUE (transaction_id, user_id),
+    CONSTRAINT chk_transaction_disputes_reason CHECK (dispute_reason IN ('unauthorized', 'wrong_amount', 'not_received', 'other')),
+    CONSTRAINT chk_transaction_disputes_status CHECK (status IN ('open', 'resolved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_disputes_status_created
+    ON public.transaction_disputes(status, created_at DESC);
+
+CREATE TRIGGER set_transaction_disputes_updated_at
+BEFORE UPDATE ON public.transaction_disputes
+FOR EACH ROW
+EXECUTE FUNCTION public.trigger_set_timestamp();
+
+COMMENT ON TABLE public.transaction_disputes IS 'Transactions flagged by a participant for review.';
+COMMENT ON COLUMN public.transaction_disputes.user_id IS 'The sender or recipient who raised the dispute.';
+
+ALTER TABLE public.transaction_disputes ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage transaction disputes."
+ON public.transaction_disputes;
+
+CREATE POLICY "Service role can manage transaction disputes."
+ON public.transaction_disputes FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
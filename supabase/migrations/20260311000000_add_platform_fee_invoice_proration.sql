@@ -0,0 +1,29 @@
+/**
+ * Migration: add_platform_fee_invoice_proration
+ *
+ * Description:
+ * Users who join part-way through a billing period are charged only for the days
+ * they were active. base_amount keeps the full configured fee and proration_factor
+ * the share of the period that was billed, so the app can explain a reduced charge.
+ * Existing invoices were billed in full and are backfilled with a factor of 1.
+ */
+
+ALTER TABLE public.platform_fee_invoices
+ADD COLUMN IF NOT EXISTS base_amount BIGINT,
+ADD COLUMN IF NOT EXISTS proration_factor NUMERIC(7, 6) NOT NULL DEFAULT 1;
+
+UPDATE public.platform_fee_invoices
+SET base_amount = amount
+WHERE base_amount IS NULL;
+
+ALTER TABLE public.platform_fee_invoices
+ALTER COLUMN base_amount SET NOT NULL;
+
+ALTER TABLE public.platform_fee_invoices
+DROP CONSTRAINT IF EXISTS chk_platform_fee_invoices_proration_factor;
+
+ALTER TABLE public.platform_fee_invoices
+ADD CONSTRAINT chk_platform_fee_invoices_proration_factor CHECK (proration_factor > 0 AND proration_factor <= 1);
+
+COMMENT ON COLUMN public.platform_fee_invoices.base_amount IS 'Full configured fee for the period before proration.';
+COMMENT ON COLUMN public.platform_fee_invoices.proration_factor IS 'Share of the period the user was active; amount is base_amount times this factor, rounded to the nearest kobo.';
@@ -0,0 +1,37 @@
+/**
+ * Migration: create_short_links
+ *
+ * Description:
+ * Short links let money drop creators share a compact URL instead of the full
+ * claim link. Each row maps an 8-character code to the claim URL of one drop and
+ * expires together with the drop. click_count is incremented on every redirect.
+ */
+
+CREATE TABLE IF NOT EXISTS public.short_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(8) NOT NULL,
+    money_drop_id UUID NOT NULL REFERENCES public.money_drops(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    target_url TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_short_links_code UNIQUE (code),
+    CONSTRAINT chk_short_links_code CHECK (code ~ '^[A-Za-z0-9]{8}$')
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_links_money_drop
+    ON public.short_links(money_drop_id);
+
+COMMENT ON TABLE public.short_links IS 'Shareable short codes that redirect to a money drop claim URL.';
+COMMENT ON COLUMN public.short_links.expires_at IS 'Matches the money drop expiry; expired codes answer 410 Gone.';
+
+ALTER TABLE public.short_links ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage short links."
+ON public.short_links;
+
+CREATE POLICY "Service role can manage short links."
+ON public.short_links FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,35 @@
+/**
+ * Migration: create_scheduler_job_runs
+ *
+ * Description:
+ * Run history for scheduler-service jobs. Every scheduled or manually triggered
+ * run is recorded when it finishes so operators can see the last result of each
+ * job across all scheduler instances.
+ */
+
+CREATE TABLE IF NOT EXISTS public.scheduler_job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(64) NOT NULL,
+    trigger VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error_message TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT chk_scheduler_job_runs_trigger CHECK (trigger IN ('schedule', 'manual')),
+    CONSTRAINT chk_scheduler_job_runs_status CHECK (status IN ('succeeded', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduler_job_runs_job_started
+    ON public.scheduler_job_runs(job_name, started_at DESC);
+
+COMMENT ON TABLE public.scheduler_job_runs IS 'History of scheduler-service job runs, one row per finished run.';
+
+ALTER TABLE public.scheduler_job_runs ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage scheduler job runs."
+ON public.scheduler_job_runs;
+
+CREATE POLICY "Service role can manage scheduler job runs."
+ON public.scheduler_job_runs FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,30 @@
+/**
+ * Migration: create_scheduler_locks
+ *
+ * Description:
+ * Per-job leases for scheduler-service. Before a job runs, an instance claims the
+ * job's row until locked_until and keeps extending it while the run lasts. Another
+ * replica only takes over once the lease has expired, so a job runs on at most one
+ * instance at a time.
+ */
+
+CREATE TABLE IF NOT EXISTS public.scheduler_locks (
+    job_name VARCHAR(64) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE public.scheduler_locks IS 'Job leases held by scheduler-service instances; a row is deleted when its run finishes.';
+COMMENT ON COLUMN public.scheduler_locks.holder IS 'Instance ID of the scheduler replica holding the lease.';
+COMMENT ON COLUMN public.scheduler_locks.locked_until IS 'Lease expiry; extended by the holder while the job is running.';
+
+ALTER TABLE public.scheduler_locks ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage scheduler locks."
+ON public.scheduler_locks;
+
+CREATE POLICY "Service role can manage scheduler locks."
+ON public.scheduler_locks FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,32 @@
+/**
+ * Migration: add_transaction_dispute_tracking
+ *
+ * Description:
+ * Disputes now move through open -> investigating -> resolved/rejected, and the
+ * closing status carries a resolution note for the user. Only one open or
+ * investigating dispute may exist per transaction; once it is closed either
+ * participant can raise a new one.
+ */
+
+ALTER TABLE public.transaction_disputes
+ADD COLUMN IF NOT EXISTS resolution_note TEXT,
+ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ;
+
+ALTER TABLE public.transaction_disputes
+DROP CONSTRAINT IF EXISTS chk_transaction_disputes_status;
+
+ALTER TABLE public.transaction_disputes
+ADD CONSTRAINT chk_transaction_disputes_status CHECK (status IN ('open', 'investigating', 'resolved', 'rejected'));
+
+ALTER TABLE public.transaction_disputes
+DROP CONSTRAINT IF EXISTS unique_transaction_dispute_per_user;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transaction_disputes_one_active
+    ON public.transaction_disputes(transaction_id)
+    WHERE status IN ('open', 'investigating');
+
+CREATE INDEX IF NOT EXISTS idx_transaction_disputes_user_created
+    ON public.transaction_disputes(user_id, created_at DESC);
+
+COMMENT ON COLUMN public.transaction_disputes.resolution_note IS 'Note shown to the user when the dispute is resolved or rejected.';
+COMMENT ON COLUMN public.transaction_disputes.resolved_at IS 'When the dispute reached resolved or rejected.';
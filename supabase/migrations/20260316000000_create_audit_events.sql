@@ -0,0 +1,57 @@
+/**
+ * Migration: create_audit_events
+ *
+ * Description:
+ * Append-only audit trail for money movement and internal/admin actions: who (actor)
+ * did what (action) to which record (subject), from where (ip), with free-form
+ * metadata. Rows are never changed after insert; a trigger rejects UPDATE and DELETE.
+ */
+
+CREATE TABLE IF NOT EXISTS public.audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_type VARCHAR(32) NOT NULL,
+    actor_id VARCHAR(255),
+    action VARCHAR(64) NOT NULL,
+    subject_type VARCHAR(64),
+    subject_id VARCHAR(255),
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ip VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_subject
+ON public.audit_events (subject_type, subject_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor
+ON public.audit_events (actor_type, actor_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at
+ON public.audit_events (created_at DESC);
+
+COMMENT ON TABLE public.audit_events IS 'Append-only audit trail of money movement and internal/admin actions.';
+COMMENT ON COLUMN public.audit_events.actor_type IS 'user for app users, internal for callers using the internal API key.';
+COMMENT ON COLUMN public.audit_events.actor_id IS 'Internal user ID, or the X-Audit-Actor value supplied by an internal caller.';
+COMMENT ON COLUMN public.audit_events.action IS 'Dotted action name, e.g. transfer.p2p or money_drop.refund.';
+
+CREATE OR REPLACE FUNCTION public.reject_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON public.audit_events;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON public.audit_events
+FOR EACH ROW EXECUTE FUNCTION public.reject_audit_event_change();
+
+ALTER TABLE public.audit_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage audit events."
+ON public.audit_events;
+
+CREATE POLICY "Service role can manage audit events."
+ON public.audit_events FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,61 @@
+/**
+ * Migration: create_transactions_archive
+ *
+ * Description:
+ * Cold storage for terminal transactions older than the retention window. The
+ * transaction-service moves rows here monthly (INSERT ... SELECT * from a DELETE),
+ * so the archive must keep exactly the same columns, in the same order, as
+ * public.transactions. Any column added to transactions must be added here too.
+ *
+ * Foreign keys pointing at public.transactions are dropped: archived IDs stay valid
+ * because FindTransactionByID falls back to the archive, whereas ON DELETE SET NULL
+ * would erase the links and ON DELETE CASCADE would delete disputes outright.
+ */
+
+CREATE TABLE IF NOT EXISTS public.transactions_archive (
+    LIKE public.transactions INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_sender_created_at
+ON public.transactions_archive (sender_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_recipient_created_at
+ON public.transactions_archive (recipient_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_sender_recipient_created_at
+ON public.transactions_archive (sender_id, recipient_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_recipient_sender_created_at
+ON public.transactions_archive (recipient_id, sender_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_created_at
+ON public.transactions_archive (created_at);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_terminal_created_at
+ON public.transactions (created_at)
+WHERE status IN ('completed', 'failed');
+
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    FOR fk IN
+        SELECT conrelid::regclass AS table_name, conname
+        FROM pg_constraint
+        WHERE contype = 'f'
+          AND confrelid = 'public.transactions'::regclass
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.table_name, fk.conname);
+    END LOOP;
+END $$;
+
+ALTER TABLE public.transactions_archive ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage transactions archive."
+ON public.transactions_archive;
+
+CREATE POLICY "Service role can manage transactions archive."
+ON public.transactions_archive FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
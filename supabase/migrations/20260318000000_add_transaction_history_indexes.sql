@@ -0,0 +1,29 @@
+/**
+ * Migration: add_transaction_history_indexes
+ *
+ * Description:
+ * Supports the transaction history query, which reads the sender and recipient
+ * sides as two UNION ALL branches ordered by created_at. Each branch walks its own
+ * (party, created_at DESC) index instead of a bitmap OR over the single-column
+ * indexes followed by a sort. Those single-column indexes are prefixes of the new
+ * ones and are dropped.
+ *
+ * (status, created_at) serves the status-filtered scans (stale pending transfers,
+ * the archive run). anchor_transfer_id is already indexed by its UNIQUE
+ * constraint, so the extra idx_transactions_anchor_transfer_id is dropped as well.
+ */
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_created_at
+ON public.transactions (sender_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_recipient_created_at
+ON public.transactions (recipient_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status_created_at
+ON public.transactions (status, created_at);
+
+DROP INDEX IF EXISTS public.idx_transactions_sender_id;
+DROP INDEX IF EXISTS public.idx_transactions_recipient_id;
+DROP INDEX IF EXISTS public.idx_transactions_anchor_transfer_id;
+
+ANALYZE public.transactions;
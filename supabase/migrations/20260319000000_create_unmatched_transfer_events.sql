@@ -0,0 +1,45 @@
+/**
+ * Migration: create_unmatched_transfer_events
+ *
+ * Description:
+ * Transfer status events that the transaction-service consumer could not match to a
+ * transaction after exhausting its retries and an Anchor transfer lookup. The raw
+ * event payload is kept so the event can be replayed once the cause (usually a
+ * matching bug) is fixed. A redelivered event for the same transfer and event key
+ * updates the existing row instead of adding another.
+ */
+
+CREATE TABLE IF NOT EXISTS public.unmatched_transfer_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    anchor_transfer_id VARCHAR(255) NOT NULL,
+    event_key VARCHAR(255) NOT NULL,
+    event_type VARCHAR(64) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'replayed')),
+    park_count INTEGER NOT NULL DEFAULT 1,
+    replay_attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    transaction_id UUID REFERENCES public.transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    replayed_at TIMESTAMPTZ,
+    UNIQUE (anchor_transfer_id, event_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_unmatched_transfer_events_status_created_at
+ON public.unmatched_transfer_events (status, created_at DESC);
+
+COMMENT ON TABLE public.unmatched_transfer_events IS 'Transfer status events parked because no transaction matched them.';
+COMMENT ON COLUMN public.unmatched_transfer_events.event_key IS 'Event ID from the webhook, or event type and status when the webhook had none.';
+COMMENT ON COLUMN public.unmatched_transfer_events.park_count IS 'Times the consumer parked this event, including redeliveries.';
+COMMENT ON COLUMN public.unmatched_transfer_events.transaction_id IS 'Transaction the event was applied to by a successful replay.';
+
+ALTER TABLE public.unmatched_transfer_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage unmatched transfer events."
+ON public.unmatched_transfer_events;
+
+CREATE POLICY "Service role can manage unmatched transfer events."
+ON public.unmatched_transfer_events FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
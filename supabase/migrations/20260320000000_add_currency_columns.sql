@@ -0,0 +1,39 @@
+/**
+ * Migration: add_currency_columns
+ *
+ * Description:
+ * Makes the currency of every money path explicit instead of implied NGN/kobo.
+ * Transactions, accounts and money drops get a currency column defaulting to NGN,
+ * so existing rows and writers that do not set it yet keep their meaning. Amounts
+ * stay in the currency's minor unit.
+ *
+ * transactions_archive must keep the same columns, in the same order, as
+ * public.transactions, so it gets the column as well.
+ */
+
+ALTER TABLE public.transactions
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'NGN';
+
+ALTER TABLE public.transactions_archive
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'NGN';
+
+ALTER TABLE public.accounts
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'NGN';
+
+ALTER TABLE public.money_drops
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'NGN';
+
+ALTER TABLE public.transactions
+DROP CONSTRAINT IF EXISTS chk_transactions_currency;
+ALTER TABLE public.transactions
+ADD CONSTRAINT chk_transactions_currency CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE public.accounts
+DROP CONSTRAINT IF EXISTS chk_accounts_currency;
+ALTER TABLE public.accounts
+ADD CONSTRAINT chk_accounts_currency CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE public.money_drops
+DROP CONSTRAINT IF EXISTS chk_money_drops_currency;
+ALTER TABLE public.money_drops
+ADD CONSTRAINT chk_money_drops_currency CHECK (currency ~ '^[A-Z]{3}$');
@@ -0,0 +1,57 @@
+/**
+ * Migration: add_payment_request_partial_payments
+ *
+ * Description:
+ * Lets an individual payment request be paid in installments:
+ * - allow_partial: set by the creator; without it a payment must cover the
+ *   whole remaining balance.
+ * - amount_paid: credited by the transaction-service when each settlement
+ *   transfer completes. The request is fulfilled once it reaches amount and goes
+ *   back to pending in between.
+ * - payment_request_settlements: one row per transfer made toward a request, so
+ *   the request detail can list them.
+ *
+ * Requests fulfilled before this migration were paid in full, so amount_paid is
+ * backfilled for them. transaction_id has no foreign key for the same reason as
+ * payment_requests.settled_transaction_id: settled transactions move to
+ * transactions_archive.
+ */
+
+ALTER TABLE public.payment_requests
+ADD COLUMN IF NOT EXISTS allow_partial BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE public.payment_requests
+ADD COLUMN IF NOT EXISTS amount_paid BIGINT NOT NULL DEFAULT 0;
+
+UPDATE public.payment_requests
+SET amount_paid = amount
+WHERE status = 'fulfilled' AND amount_paid = 0;
+
+ALTER TABLE public.payment_requests
+DROP CONSTRAINT IF EXISTS chk_payment_requests_amount_paid;
+ALTER TABLE public.payment_requests
+ADD CONSTRAINT chk_payment_requests_amount_paid CHECK (amount_paid >= 0 AND amount_paid <= amount);
+
+CREATE TABLE IF NOT EXISTS public.payment_request_settlements (
+    transaction_id UUID PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES public.payment_requests(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_request_settlements_request_created_at
+ON public.payment_request_settlements (request_id, created_at);
+
+ALTER TABLE public.payment_request_settlements ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage payment request settlements."
+ON public.payment_request_settlements;
+
+CREATE POLICY "Service role can manage payment request settlements."
+ON public.payment_request_settlements FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+COMMENT ON COLUMN public.payment_requests.allow_partial IS 'Whether the request may be paid in more than one transfer.';
+COMMENT ON COLUMN public.payment_requests.amount_paid IS 'Sum of the completed settlement transfers, in the minor unit.';
+COMMENT ON TABLE public.payment_request_settlements IS 'Transfers made toward a payment request, one row per payment.';
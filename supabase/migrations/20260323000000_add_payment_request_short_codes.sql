@@ -0,0 +1,23 @@
+/**
+ * Migration: add_payment_request_short_codes
+ *
+ * Description:
+ * Gives payment requests a short code so their share links and QR codes stay
+ * short. The transaction-service assigns one when a request is created and
+ * retries with a fresh code on a collision; requests created before this
+ * migration have none and keep their long link.
+ */
+
+ALTER TABLE public.payment_requests
+ADD COLUMN IF NOT EXISTS short_code TEXT;
+
+ALTER TABLE public.payment_requests
+DROP CONSTRAINT IF EXISTS chk_payment_requests_short_code;
+ALTER TABLE public.payment_requests
+ADD CONSTRAINT chk_payment_requests_short_code CHECK (short_code IS NULL OR short_code ~ '^[A-Za-z0-9]{6,8}$');
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payment_requests_short_code
+ON public.payment_requests (short_code)
+WHERE short_code IS NOT NULL;
+
+COMMENT ON COLUMN public.payment_requests.short_code IS 'Code used in the short share link (/pay/{code}); unique when set.';
@@ -0,0 +1,44 @@
+/**
+ * Migration: add_payment_request_splits
+ *
+ * Description:
+ * Lets a creator split a bill among several users. The transaction-service
+ * creates one payment_request_splits row as bookkeeping and one individual
+ * payment request per recipient pointing at it through split_id. Each of those
+ * requests is paid or declined on its own; the split's progress is read from
+ * them rather than stored.
+ */
+
+CREATE TABLE IF NOT EXISTS public.payment_request_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    creator_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT,
+    total_amount BIGINT NOT NULL CHECK (total_amount > 0),
+    strategy TEXT NOT NULL CHECK (strategy IN ('equal', 'custom')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_request_splits_creator_created_at
+ON public.payment_request_splits (creator_id, created_at DESC);
+
+ALTER TABLE public.payment_requests
+ADD COLUMN IF NOT EXISTS split_id UUID REFERENCES public.payment_request_splits(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_split_id
+ON public.payment_requests (split_id)
+WHERE split_id IS NOT NULL;
+
+ALTER TABLE public.payment_request_splits ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage payment request splits."
+ON public.payment_request_splits;
+
+CREATE POLICY "Service role can manage payment request splits."
+ON public.payment_request_splits FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+COMMENT ON TABLE public.payment_request_splits IS 'Bills split among several users; each share is an individual payment request.';
+COMMENT ON COLUMN public.payment_requests.split_id IS 'Split this request is a share of, if any.';
@@ -0,0 +1,53 @@
+/**
+ * Migration: add_transaction_notes_and_attachments
+ *
+ * Description:
+ * Lets the sender and the recipient of a transaction each keep a private note
+ * and up to three image attachments (receipts, for example) on it. The app
+ * uploads images to storage itself; only their URLs are recorded here. Rows are
+ * keyed by transaction id without a foreign key because settled transactions
+ * move to transactions_archive.
+ */
+
+CREATE TABLE IF NOT EXISTS public.transaction_notes (
+    transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    note TEXT NOT NULL CHECK (char_length(note) BETWEEN 1 AND 500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (transaction_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.transaction_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_attachments_transaction_user
+ON public.transaction_attachments (transaction_id, user_id);
+
+ALTER TABLE public.transaction_notes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.transaction_attachments ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage transaction notes."
+ON public.transaction_notes;
+
+CREATE POLICY "Service role can manage transaction notes."
+ON public.transaction_notes FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+DROP POLICY IF EXISTS "Service role can manage transaction attachments."
+ON public.transaction_attachments;
+
+CREATE POLICY "Service role can manage transaction attachments."
+ON public.transaction_attachments FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+COMMENT ON TABLE public.transaction_notes IS 'Private note each party keeps on a transaction; only its author sees it.';
+COMMENT ON TABLE public.transaction_attachments IS 'Images a party attached to a transaction; only the uploader sees them.';
+COMMENT ON COLUMN public.transaction_attachments.url IS 'Storage URL; must be on one of ATTACHMENT_ALLOWED_HOSTS.';
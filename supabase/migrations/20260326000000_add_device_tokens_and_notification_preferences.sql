@@ -0,0 +1,53 @@
+/**
+ * Migration: add_device_tokens_and_notification_preferences
+ *
+ * Description:
+ * Stores the Expo and FCM push tokens the app registers for each signed-in
+ * device, and each user's notification preferences. The notification-service
+ * reads both to deliver transfer and money drop notifications. A token belongs
+ * to one user at a time; registering it again moves it to the new user.
+ * Users without a preferences row get transaction alerts on and marketing off.
+ */
+
+CREATE TABLE IF NOT EXISTS public.device_tokens (
+    token TEXT PRIMARY KEY CHECK (char_length(token) BETWEEN 1 AND 512),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL CHECK (provider IN ('expo', 'fcm')),
+    platform TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id
+ON public.device_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS public.notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    transaction_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    marketing BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE public.device_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.notification_preferences ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage device tokens."
+ON public.device_tokens;
+
+CREATE POLICY "Service role can manage device tokens."
+ON public.device_tokens FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+DROP POLICY IF EXISTS "Service role can manage notification preferences."
+ON public.notification_preferences;
+
+CREATE POLICY "Service role can manage notification preferences."
+ON public.notification_preferences FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+COMMENT ON TABLE public.device_tokens IS 'Push tokens registered by the app for each signed-in device.';
+COMMENT ON COLUMN public.device_tokens.provider IS 'Push service the token belongs to: expo or fcm.';
+COMMENT ON TABLE public.notification_preferences IS 'Which push notifications a user wants; absent rows mean the defaults.';
+COMMENT ON COLUMN public.notification_preferences.marketing IS 'Marketing notifications are opt-in.';
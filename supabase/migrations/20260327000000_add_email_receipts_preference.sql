@@ -0,0 +1,12 @@
+/**
+ * Migration: add_email_receipts_preference
+ *
+ * Description:
+ * Lets users opt in to email receipts for completed and failed transfers and
+ * money drop claims. Receipts are off unless the user turns them on.
+ */
+
+ALTER TABLE public.notification_preferences
+ADD COLUMN IF NOT EXISTS email_receipts BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN public.notification_preferences.email_receipts IS 'Whether the user wants email receipts; off by default.';
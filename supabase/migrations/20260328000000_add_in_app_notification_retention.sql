@@ -0,0 +1,13 @@
+/**
+ * Migration: add_in_app_notification_retention
+ *
+ * Description:
+ * Read in-app notifications are deleted 90 days after they were created by a daily
+ * cleanup job. This index lets the job find them without scanning unread ones.
+ */
+
+CREATE INDEX IF NOT EXISTS idx_in_app_notifications_read_created_at
+  ON public.in_app_notifications(created_at)
+  WHERE status = 'read';
+
+COMMENT ON INDEX public.idx_in_app_notifications_read_created_at IS 'Serves the daily cleanup of read notifications older than 90 days.';
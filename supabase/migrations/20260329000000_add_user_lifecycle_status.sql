@@ -0,0 +1,29 @@
+/**
+ * Migration: add_user_lifecycle_status
+ *
+ * Description:
+ * auth-service now receives Clerk's user lifecycle webhooks. A user.created webhook
+ * pre-provisions a users row in 'pending_onboarding' so a Clerk signup is never left
+ * without a Transfa user if the app fails to call /onboarding; onboarding later
+ * upserts onto that row and moves it to 'active'.
+ *
+ * A user.deleted webhook moves the row to 'pending_cleanup' and records when Clerk
+ * deleted the user. The row is kept so historical transactions still resolve.
+ */
+
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS lifecycle_status VARCHAR(32) NOT NULL DEFAULT 'active',
+ADD COLUMN IF NOT EXISTS clerk_deleted_at TIMESTAMPTZ;
+
+ALTER TABLE public.users
+DROP CONSTRAINT IF EXISTS chk_users_lifecycle_status;
+
+ALTER TABLE public.users
+ADD CONSTRAINT chk_users_lifecycle_status CHECK (lifecycle_status IN ('pending_onboarding', 'active', 'pending_cleanup'));
+
+COMMENT ON COLUMN public.users.lifecycle_status IS 'pending_onboarding until /onboarding completes, active afterwards, pending_cleanup once the Clerk user is deleted.';
+COMMENT ON COLUMN public.users.clerk_deleted_at IS 'When Clerk reported the user as deleted. NULL while the Clerk user exists.';
+
+CREATE INDEX IF NOT EXISTS idx_users_pending_cleanup
+    ON public.users(clerk_deleted_at)
+    WHERE lifecycle_status = 'pending_cleanup';
@@ -0,0 +1,42 @@
+/**
+ * Migration: add_tier2_submissions
+ *
+ * Description:
+ * Tracks each piece of a Tier 2 submission (BVN, date of birth, gender) separately so
+ * a rejected verification can be fixed by resubmitting only the failing piece instead
+ * of restarting Tier 2.
+ *
+ * auth-service writes one row per piece when the user submits or resubmits, keeping
+ * the value encrypted so accepted pieces can be replayed to Anchor. customer-service
+ * records the outcome of each piece from the customer.identification.* webhooks.
+ */
+
+CREATE TABLE IF NOT EXISTS public.tier2_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    document_type VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'submitted',
+    value_encrypted TEXT,
+    rejection_reason TEXT,
+    anchor_verification_id VARCHAR(255),
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_tier2_submissions_user_document UNIQUE (user_id, document_type),
+    CONSTRAINT chk_tier2_submissions_document_type CHECK (document_type IN ('bvn', 'date_of_birth', 'gender')),
+    CONSTRAINT chk_tier2_submissions_status CHECK (status IN ('submitted', 'accepted', 'rejected'))
+);
+
+COMMENT ON TABLE public.tier2_submissions IS 'Latest submission of each Tier 2 piece per user and its verification outcome.';
+COMMENT ON COLUMN public.tier2_submissions.value_encrypted IS 'AES-GCM encrypted submitted value. NULL when auth-service has no encryption key configured.';
+COMMENT ON COLUMN public.tier2_submissions.anchor_verification_id IS 'Anchor verification the outcome came from, when the webhook carried one.';
+
+ALTER TABLE public.tier2_submissions ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage tier2 submissions."
+ON public.tier2_submissions;
+
+CREATE POLICY "Service role can manage tier2 submissions."
+ON public.tier2_submissions FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
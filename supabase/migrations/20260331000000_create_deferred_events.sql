@@ -0,0 +1,49 @@
+/**
+ * Migration: create_deferred_events
+ *
+ * Description:
+ * Retry queue for customer-service events that failed for a reason a later attempt
+ * can fix, such as an Anchor outage. Instead of dropping the event, the consumer
+ * stores it here with next_attempt_at set by exponential backoff and acknowledges the
+ * message; a background loop re-processes due rows through the same handler.
+ *
+ * A row is 'pending' until its next attempt, 'processing' while a worker retries it
+ * and 'completed' once it succeeds. After the last attempt fails it becomes
+ * 'needs_manual_review' and is listed on customer-service's internal endpoint.
+ */
+
+CREATE TABLE IF NOT EXISTS public.deferred_events (
+    id BIGSERIAL PRIMARY KEY,
+    consumer TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    user_id TEXT,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_deferred_events_consumer_message UNIQUE (consumer, message_id),
+    CONSTRAINT chk_deferred_events_status CHECK (status IN ('pending', 'processing', 'completed', 'needs_manual_review'))
+);
+
+COMMENT ON TABLE public.deferred_events IS 'Events a consumer will retry later with backoff, or that exhausted their retries.';
+COMMENT ON COLUMN public.deferred_events.attempts IS 'Attempts made so far, including the original delivery.';
+
+CREATE INDEX IF NOT EXISTS idx_deferred_events_due
+    ON public.deferred_events(next_attempt_at)
+    WHERE status IN ('pending', 'processing');
+
+CREATE INDEX IF NOT EXISTS idx_deferred_events_status
+    ON public.deferred_events(status, updated_at DESC);
+
+ALTER TABLE public.deferred_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage deferred events."
+ON public.deferred_events;
+
+CREATE POLICY "Service role can manage deferred events."
+ON public.deferred_events FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
@@ -0,0 +1,42 @@
+/**
+ * Migration: create_account_provisioning_jobs
+ *
+ * Description:
+ * Tracks provisioning of each user's primary deposit account so a failed Anchor call
+ * is retried instead of leaving a verified user without an account.
+ *
+ * account-service creates the row when customer.verified arrives. A failed attempt
+ * goes back to 'pending' with next_attempt_at set by exponential backoff until the
+ * attempts run out, when it becomes 'failed'. anchor_account_id is kept once Anchor
+ * created the deposit account so a retry only finishes the remaining steps.
+ */
+
+CREATE TABLE IF NOT EXISTS public.account_provisioning_jobs (
+    user_id UUID PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    anchor_customer_id VARCHAR(255) NOT NULL,
+    anchor_account_id VARCHAR(255),
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_account_provisioning_jobs_status CHECK (status IN ('pending', 'processing', 'completed', 'failed'))
+);
+
+COMMENT ON TABLE public.account_provisioning_jobs IS 'Primary account provisioning attempts per user, retried with backoff.';
+COMMENT ON COLUMN public.account_provisioning_jobs.anchor_account_id IS 'Anchor deposit account created by an earlier attempt, reused on retry.';
+
+CREATE INDEX IF NOT EXISTS idx_account_provisioning_jobs_due
+    ON public.account_provisioning_jobs(next_attempt_at)
+    WHERE status IN ('pending', 'processing');
+
+ALTER TABLE public.account_provisioning_jobs ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage account provisioning jobs."
+ON public.account_provisioning_jobs;
+
+CREATE POLICY "Service role can manage account provisioning jobs."
+ON public.account_provisioning_jobs FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
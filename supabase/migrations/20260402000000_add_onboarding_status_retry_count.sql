@@ -0,0 +1,13 @@
+/**
+ * Migration: add_onboarding_status_retry_count
+ *
+ * Description:
+ * Counts how often a user has retried a failed onboarding stage. auth-service
+ * increments it when a user retries a failed Tier 1 verification and refuses further
+ * retries once the limit is reached.
+ */
+
+ALTER TABLE public.onboarding_status
+    ADD COLUMN IF NOT EXISTS retry_count INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN public.onboarding_status.retry_count IS 'Number of user-initiated retries of this stage after it failed.';
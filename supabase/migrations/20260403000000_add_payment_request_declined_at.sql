@@ -0,0 +1,18 @@
+/**
+ * Migration: add_payment_request_declined_at
+ *
+ * Description:
+ * Records when the recipient of a payment request declined it, next to the existing
+ * declined_reason. Requests declined before this migration take their responded_at.
+ */
+
+ALTER TABLE public.payment_requests
+    ADD COLUMN IF NOT EXISTS declined_reason TEXT,
+    ADD COLUMN IF NOT EXISTS declined_at TIMESTAMPTZ;
+
+UPDATE public.payment_requests
+SET declined_at = responded_at
+WHERE status = 'declined'
+  AND declined_at IS NULL;
+
+COMMENT ON COLUMN public.payment_requests.declined_at IS 'When the recipient declined the request. NULL unless the request is declined.';
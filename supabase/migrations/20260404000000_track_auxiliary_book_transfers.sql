@@ -0,0 +1,86 @@
+/**
+ * Migration: track_auxiliary_book_transfers
+ *
+ * Description:
+ * Fee collections and money drop funding are book transfers the user's wallet is
+ * debited for up front, but nothing recorded their Anchor transfer IDs, so their
+ * transfer.status.book.* webhooks matched no transaction and a failed transfer went
+ * unnoticed.
+ *
+ * fee_collections records each fee transfer against the transaction it was charged
+ * for; the funding transfer ID goes on the money_drop_funding transaction itself.
+ * When either fails the parent transaction is flagged and a wallet credit is queued
+ * in transfer_compensations, which transaction-service works through.
+ *
+ * parent_transaction_id has no foreign key because transactions are moved to
+ * transactions_archive. transactions_archive must keep the same columns, in the same
+ * order, as public.transactions, so it gets the flags as well.
+ */
+
+ALTER TABLE public.transactions
+ADD COLUMN IF NOT EXISTS fee_collection_failed BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS funding_failed BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE public.transactions_archive
+ADD COLUMN IF NOT EXISTS fee_collection_failed BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS funding_failed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS public.fee_collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL,
+    anchor_transfer_id VARCHAR(255) NOT NULL UNIQUE,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_fee_collections_amount CHECK (amount > 0),
+    CONSTRAINT chk_fee_collections_status CHECK (status IN ('pending', 'completed', 'failed'))
+);
+
+COMMENT ON TABLE public.fee_collections IS 'Fee transfers to the admin account, matched to their transfer status webhooks by anchor_transfer_id.';
+
+CREATE INDEX IF NOT EXISTS idx_fee_collections_parent_transaction
+    ON public.fee_collections(parent_transaction_id);
+
+ALTER TABLE public.fee_collections ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage fee collections."
+ON public.fee_collections;
+
+CREATE POLICY "Service role can manage fee collections."
+ON public.fee_collections FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
+
+CREATE TABLE IF NOT EXISTS public.transfer_compensations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    CONSTRAINT uq_transfer_compensations_transaction_kind UNIQUE (transaction_id, kind),
+    CONSTRAINT chk_transfer_compensations_kind CHECK (kind IN ('fee_collection', 'money_drop_funding')),
+    CONSTRAINT chk_transfer_compensations_amount CHECK (amount > 0),
+    CONSTRAINT chk_transfer_compensations_status CHECK (status IN ('pending', 'completed'))
+);
+
+COMMENT ON TABLE public.transfer_compensations IS 'Wallet credits owed after a fee collection or money drop funding transfer failed.';
+
+CREATE INDEX IF NOT EXISTS idx_transfer_compensations_pending
+    ON public.transfer_compensations(created_at)
+    WHERE status = 'pending';
+
+ALTER TABLE public.transfer_compensations ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage transfer compensations."
+ON public.transfer_compensations;
+
+CREATE POLICY "Service role can manage transfer compensations."
+ON public.transfer_compensations FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
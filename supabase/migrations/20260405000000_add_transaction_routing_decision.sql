@@ -0,0 +1,42 @@
+/**
+ * Migration: add_transaction_routing_decision
+ *
+ * Description:
+ * Records where a P2P transfer was delivered and why, so support can explain why
+ * money landed in a recipient's wallet rather than their external account (or the
+ * other way round).
+ *
+ * routing_decision is 'internal' or 'external'. routing_reason is one of
+ * sender_override (the sender asked for internal delivery), recipient_preference,
+ * quota_exceeded (the transfer was not entitled to external rails) and
+ * no_beneficiary (the recipient prefers an external account but has none usable).
+ * Both stay NULL for other transaction types and for transfers made before this
+ * migration.
+ *
+ * transactions_archive must keep the same columns, in the same order, as
+ * public.transactions, so it gets the columns as well.
+ */
+
+ALTER TABLE public.transactions
+ADD COLUMN IF NOT EXISTS routing_decision VARCHAR(16),
+ADD COLUMN IF NOT EXISTS routing_reason VARCHAR(32);
+
+ALTER TABLE public.transactions_archive
+ADD COLUMN IF NOT EXISTS routing_decision VARCHAR(16),
+ADD COLUMN IF NOT EXISTS routing_reason VARCHAR(32);
+
+ALTER TABLE public.transactions
+DROP CONSTRAINT IF EXISTS chk_transactions_routing_decision;
+ALTER TABLE public.transactions
+ADD CONSTRAINT chk_transactions_routing_decision CHECK (routing_decision IS NULL OR routing_decision IN ('internal', 'external'));
+
+ALTER TABLE public.transactions
+DROP CONSTRAINT IF EXISTS chk_transactions_routing_reason;
+ALTER TABLE public.transactions
+ADD CONSTRAINT chk_transactions_routing_reason CHECK (
+    routing_reason IS NULL
+    OR routing_reason IN ('sender_override', 'recipient_preference', 'quota_exceeded', 'no_beneficiary')
+);
+
+COMMENT ON COLUMN public.transactions.routing_decision IS 'Where a P2P transfer was delivered: internal wallet or external account.';
+COMMENT ON COLUMN public.transactions.routing_reason IS 'Why the P2P transfer was delivered where routing_decision says.';
@@ -0,0 +1,63 @@
+/**
+ * Migration: transaction_routing_reason_enum
+ *
+ * Description:
+ * Turns transactions.routing_reason into the transaction_routing_reason enum and
+ * gives every P2P routing branch its own reason, so a recipient asking why money went
+ * to their wallet instead of their bank can be answered from data:
+ *
+ *   sender_override           the sender asked for internal delivery
+ *   external_preferred        delivered to the recipient's preferred external account
+ *   internal_preferred        the recipient prefers their wallet
+ *   quota_exceeded            the sender is behind on platform fees, which keeps the
+ *                             transfer off external rails
+ *   not_eligible              the recipient is not eligible for external delivery
+ *   beneficiary_missing       the recipient prefers an external account but has none
+ *   preference_lookup_failed  the recipient's preference could not be read
+ *
+ * Values written under the previous CHECK constraint are mapped onto the new set;
+ * older rows stay NULL. transactions_archive gets the same column type so archiving
+ * with INSERT ... SELECT * keeps working.
+ */
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'transaction_routing_reason') THEN
+        CREATE TYPE public.transaction_routing_reason AS ENUM (
+            'sender_override',
+            'external_preferred',
+            'internal_preferred',
+            'quota_exceeded',
+            'not_eligible',
+            'beneficiary_missing',
+            'preference_lookup_failed'
+        );
+    END IF;
+END $$;
+
+ALTER TABLE public.transactions
+DROP CONSTRAINT IF EXISTS chk_transactions_routing_reason;
+
+ALTER TABLE public.transactions
+ALTER COLUMN routing_reason TYPE public.transaction_routing_reason
+USING (
+    CASE routing_reason
+        WHEN 'recipient_preference' THEN
+            CASE WHEN routing_decision = 'external' THEN 'external_preferred' ELSE 'internal_preferred' END
+        WHEN 'no_beneficiary' THEN 'beneficiary_missing'
+        ELSE routing_reason
+    END
+)::public.transaction_routing_reason;
+
+ALTER TABLE public.transactions_archive
+ALTER COLUMN routing_reason TYPE public.transaction_routing_reason
+USING (
+    CASE routing_reason
+        WHEN 'recipient_preference' THEN
+            CASE WHEN routing_decision = 'external' THEN 'external_preferred' ELSE 'internal_preferred' END
+        WHEN 'no_beneficiary' THEN 'beneficiary_missing'
+        ELSE routing_reason
+    END
+)::public.transaction_routing_reason;
+
+COMMENT ON COLUMN public.transactions.routing_reason IS 'Why the P2P transfer was delivered where routing_decision says. Shown to the recipient only.';
@@ -0,0 +1,29 @@
+/**
+ * Migration: add_beneficiary_verification
+ *
+ * Description:
+ * Beneficiaries keep their Anchor counterparty forever, but the bank accounts behind
+ * them get closed or renamed. verification_status records the outcome of the last
+ * time the account was resolved through Anchor:
+ *
+ *   unverified     never re-resolved since it was saved
+ *   verified       the account resolves and its name matches
+ *   name_mismatch  the account resolves to a materially different name
+ *   invalid        the account no longer resolves; transfers to it are rejected
+ *
+ * last_verified_at is when that happened. Existing beneficiaries start unverified.
+ */
+
+ALTER TABLE public.beneficiaries
+ADD COLUMN IF NOT EXISTS verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified',
+ADD COLUMN IF NOT EXISTS last_verified_at TIMESTAMPTZ;
+
+ALTER TABLE public.beneficiaries
+DROP CONSTRAINT IF EXISTS chk_beneficiaries_verification_status;
+ALTER TABLE public.beneficiaries
+ADD CONSTRAINT chk_beneficiaries_verification_status CHECK (
+    verification_status IN ('unverified', 'verified', 'name_mismatch', 'invalid')
+);
+
+COMMENT ON COLUMN public.beneficiaries.verification_status IS 'Outcome of the last Anchor re-resolution of this account.';
+COMMENT ON COLUMN public.beneficiaries.last_verified_at IS 'When the account was last re-resolved through Anchor.';
@@ -0,0 +1,23 @@
+/**
+ * Migration: add_transaction_cancellation
+ *
+ * Description:
+ * Lets a sender cancel a transfer that has not reached Anchor yet:
+ * - 'cancelled' transaction status for a transfer undone before it was initiated.
+ * - transfer_initiated_at, stamped right before the Anchor transfer is initiated. A
+ *   cancellation only applies while it is NULL, so a transfer is either cancelled or
+ *   sent, never both.
+ *
+ * transactions_archive gets the same column so archiving with INSERT ... SELECT *
+ * keeps working.
+ */
+
+ALTER TYPE public.transaction_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+ALTER TABLE public.transactions
+ADD COLUMN IF NOT EXISTS transfer_initiated_at TIMESTAMPTZ;
+
+ALTER TABLE public.transactions_archive
+ADD COLUMN IF NOT EXISTS transfer_initiated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN public.transactions.transfer_initiated_at IS 'When the Anchor transfer was initiated. A pending transaction can be cancelled only while this is NULL.';
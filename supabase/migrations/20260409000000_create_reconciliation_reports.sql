@@ -0,0 +1,47 @@
+/**
+ * Migration: create_reconciliation_reports
+ *
+ * Description:
+ * Each row is one run of the daily reconciliation between Anchor's transfers and our
+ * transactions for a UTC day. Anchor transfers are matched to transactions and fee
+ * collections by anchor_transfer_id and amount; what does not match is kept in
+ * unmatched_anchor (on Anchor, missing or different here) and unmatched_internal
+ * (recorded here, missing or different on Anchor).
+ *
+ * sample_size is how many user accounts were drawn at random, or 0 when every
+ * account was checked. A run is partial when Anchor could not be read for some of
+ * the accounts.
+ */
+
+CREATE TABLE IF NOT EXISTS public.reconciliation_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    sample_size INTEGER NOT NULL DEFAULT 0,
+    accounts_checked INTEGER NOT NULL DEFAULT 0,
+    accounts_failed INTEGER NOT NULL DEFAULT 0,
+    anchor_entries INTEGER NOT NULL DEFAULT 0,
+    internal_entries INTEGER NOT NULL DEFAULT 0,
+    matched INTEGER NOT NULL DEFAULT 0,
+    unmatched_anchor JSONB NOT NULL DEFAULT '[]'::jsonb,
+    unmatched_internal JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_reconciliation_reports_status CHECK (status IN ('completed', 'partial')),
+    CONSTRAINT chk_reconciliation_reports_period CHECK (period_end > period_start)
+);
+
+COMMENT ON TABLE public.reconciliation_reports IS 'Daily comparison of Anchor transfers against transactions and fee collections.';
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_reports_created_at
+    ON public.reconciliation_reports(created_at DESC);
+
+ALTER TABLE public.reconciliation_reports ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Service role can manage reconciliation reports."
+ON public.reconciliation_reports;
+
+CREATE POLICY "Service role can manage reconciliation reports."
+ON public.reconciliation_reports FOR ALL
+USING (auth.role() = 'service_role')
+WITH CHECK (auth.role() = 'service_role');
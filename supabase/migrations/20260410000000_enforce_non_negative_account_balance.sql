@@ -0,0 +1,32 @@
+/**
+ * Migration: enforce_non_negative_account_balance
+ *
+ * Description:
+ * Wallet debits are now a single conditional UPDATE (balance >= amount), and this
+ * constraint is the database-level backstop: no write may leave an account balance
+ * below zero.
+ *
+ * The constraint is added NOT VALID so it applies to every new write straight away
+ * without a long validation scan under lock. Existing rows are then validated; if any
+ * account is already negative the constraint is left unvalidated and a warning is
+ * raised, so those balances can be corrected against Anchor and validated later with
+ * ALTER TABLE public.accounts VALIDATE CONSTRAINT chk_accounts_balance_non_negative.
+ */
+
+ALTER TABLE public.accounts
+    DROP CONSTRAINT IF EXISTS chk_accounts_balance_non_negative;
+
+ALTER TABLE public.accounts
+    ADD CONSTRAINT chk_accounts_balance_non_negative CHECK (balance >= 0) NOT VALID;
+
+DO $$
+DECLARE
+    negative_count BIGINT;
+BEGIN
+    SELECT COUNT(*) INTO negative_count FROM public.accounts WHERE balance < 0;
+    IF negative_count = 0 THEN
+        ALTER TABLE public.accounts VALIDATE CONSTRAINT chk_accounts_balance_non_negative;
+    ELSE
+        RAISE WARNING 'chk_accounts_balance_non_negative left unvalidated: % accounts have a negative balance', negative_count;
+    END IF;
+END $$;
@@ -9,7 +9,8 @@
  * - Establishes and manages a connection pool to the PostgreSQL database.
  * - Initializes clients for external services (Anchor API).
  * - Wires up the core application logic (event handler) with its dependencies (repository, clients).
- * - Starts the message consumer and implements graceful shutdown.
+ * - Starts the message consumer and the account provisioning retry loop.
+ * - Implements graceful shutdown.
  *
  * @dependencies
  * - The service's internal packages for config, app logic, storage, and external clients.
@@ -24,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -34,8 +36,9 @@ import (
 	"github.com/transfa/account-service/internal/app"
 	"github.com/transfa/account-service/internal/config"
 	"github.com/transfa/account-service/internal/store"
-	"github.com/transfa/account-service/pkg/anchorclient"
-	"github.com/transfa/account-service/pkg/rabbitmq"
+	"github.com/transfa/pkg/anchorclient"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
+	"github.com/transfa/pkg/rabbitmq"
 )
 
 func main() {
@@ -63,11 +66,10 @@ func main() {
 	}
 
 	// Configure connection pool for high-traffic scenarios
-	// Increased from 10 to 100 to handle 100k+ concurrent users
-	dbConfig.MaxConns = 100
-	dbConfig.MinConns = 20
-	dbConfig.MaxConnLifetime = 30 * time.Minute
-	dbConfig.MaxConnIdleTime = 5 * time.Minute
+	dbConfig.MaxConns = cfg.DatabasePool.MaxConns
+	dbConfig.MinConns = cfg.DatabasePool.MinConns
+	dbConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	dbConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 
 	// Disable prepared statement caching to prevent conflicts
 	dbConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
@@ -87,10 +89,35 @@ func main() {
 
 	// Setup services
 	accountService := app.NewAccountService(accountRepo, beneficiaryRepo, bankRepo, anchorClient)
-	eventHandler := app.NewAccountEventHandler(accountRepo, anchorClient)
+
+	// Provisioning and onboarding stage events are signed so consumers can reject messages that did not come from here.
+	producer, err := rabbitmq.NewEventProducer(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("Failed to create event publisher: %v", err)
+	}
+	defer producer.Close()
+	var publisher rabbitmq.Publisher = producer
+	if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+		signed, err := rabbitmq.NewSignedEventProducer(producer, secrets...)
+		if err != nil {
+			log.Fatalf("Failed to initialise event signing: %v", err)
+		}
+		publisher = signed
+	}
+	if !cfg.OnboardingStatusDualWrite {
+		log.Println("Onboarding status dual-write disabled; stage changes are only published as onboarding.stage.changed")
+	}
+	eventHandler := app.NewAccountEventHandler(accountRepo, anchorClient, publisher, app.WithOnboardingStatusDualWrite(cfg.OnboardingStatusDualWrite))
 
 	// Setup RabbitMQ consumer.
-	consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL)
+	// account.lifecycle events are published by notification-service and must be signed.
+	var consumerOpts []rabbitmq.ConsumerOption
+	if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+		consumerOpts = append(consumerOpts, rabbitmq.WithSignedEvents(secrets, "account.lifecycle"))
+	} else {
+		log.Println("Warning: EVENT_SIGNING_SECRET not set; account.lifecycle event signatures are not verified")
+	}
+	consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL, consumerOpts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
 	}
@@ -105,6 +132,27 @@ func main() {
 		}
 	}()
 
+	go func() {
+		log.Printf("Starting consumer for event 'account.lifecycle'...")
+		err := consumer.Consume("customer_events", "account_service_account_lifecycle", "account.lifecycle", eventHandler.HandleAccountLifecycleEvent)
+		if err != nil {
+			log.Printf("Consumer error: %v", err) // Log as non-fatal
+		}
+	}()
+
+	go func() {
+		log.Printf("Starting consumer for event 'user.closed'...")
+		err := consumer.Consume("user_events", "account_service_user_closed", "user.closed", eventHandler.HandleUserClosedEvent)
+		if err != nil {
+			log.Printf("Consumer error: %v", err) // Log as non-fatal
+		}
+	}()
+
+	// Retry account provisioning that failed on a previous attempt.
+	retryCtx, stopRetries := context.WithCancel(context.Background())
+	defer stopRetries()
+	go eventHandler.RunProvisioningRetries(retryCtx, 30*time.Second)
+
 	// Start periodic cache cleanup job
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour) // Run every hour
@@ -124,8 +172,13 @@ func main() {
 		}
 	}()
 
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		log.Fatalf("Invalid ALLOWED_ORIGINS: %v", err)
+	}
+
 	// Setup and start HTTP server.
-	router := api.NewRouter(&cfg, accountService)
+	router := api.NewRouter(&cfg, accountService, eventHandler, allowedOrigins)
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.ServerPort),
 		Handler: router,
@@ -146,6 +199,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down account-service...")
+	stopRetries()
 
 	// Create a context with a timeout for shutdown.
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
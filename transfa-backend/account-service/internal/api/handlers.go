@@ -12,14 +12,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/transfa/account-service/internal/app"
+	"github.com/transfa/account-service/internal/domain"
 	"github.com/transfa/account-service/internal/store"
 	"github.com/transfa/account-service/pkg/middleware"
+	"github.com/transfa/pkg/anchorclient"
 )
 
 // BeneficiaryHandler holds the dependencies for beneficiary-related handlers.
@@ -233,6 +237,12 @@ func (h *InternalAccountHandler) CreateMoneyDropAccount(w http.ResponseWriter, r
 
 	account, err := h.service.CreateMoneyDropAccount(r.Context(), req.UserID)
 	if err != nil {
+		var anchorErr *anchorclient.APIError
+		if errors.As(err, &anchorErr) {
+			log.Printf("Anchor rejected money drop account for user %s: %v", req.UserID, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -240,6 +250,58 @@ func (h *InternalAccountHandler) CreateMoneyDropAccount(w http.ResponseWriter, r
 	writeJSON(w, http.StatusOK, account)
 }
 
+// UserRestrictionRequest defines the request payload for freezing or unfreezing a user.
+type UserRestrictionRequest struct {
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"`
+}
+
+// FreezeUser handles the internal endpoint that freezes a user's transfers.
+func (h *InternalAccountHandler) FreezeUser(w http.ResponseWriter, r *http.Request) {
+	h.applyUserRestriction(w, r, h.service.FreezeUser)
+}
+
+// UnfreezeUser handles the internal endpoint that lifts a freeze on a user.
+func (h *InternalAccountHandler) UnfreezeUser(w http.ResponseWriter, r *http.Request) {
+	h.applyUserRestriction(w, r, h.service.UnfreezeUser)
+}
+
+func (h *InternalAccountHandler) applyUserRestriction(
+	w http.ResponseWriter,
+	r *http.Request,
+	apply func(context.Context, app.UserRestrictionInput) (*domain.UserRestrictionState, error),
+) {
+	var req UserRestrictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID := chi.URLParam(r, "id")
+	state, err := apply(r.Context(), app.UserRestrictionInput{
+		UserID: userID,
+		Reason: req.Reason,
+		Actor:  req.Actor,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidRestrictionReason), errors.Is(err, app.ErrInvalidRestrictionActor):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, app.ErrUserNotFound):
+			http.Error(w, "User not found", http.StatusNotFound)
+		case errors.Is(err, store.ErrUserAlreadyFrozen), errors.Is(err, store.ErrUserNotFrozen), errors.Is(err, store.ErrUserClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("level=error component=api endpoint=user_restriction outcome=failed user_id=%s err=%v", userID, err)
+			http.Error(w, "Failed to update user restriction", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Printf("level=info component=api endpoint=user_restriction outcome=applied user_id=%s action=%s actor=%q", userID, state.Event.Action, state.Event.Actor)
+	writeJSON(w, http.StatusOK, state)
+}
+
 // writeJSON is a helper to write JSON responses.
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -249,3 +311,146 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 		http.Error(w, `{"error":"Failed to encode response"}`, http.StatusInternalServerError)
 	}
 }
+
+// AccountHandler holds the dependencies for account-related handlers.
+type AccountHandler struct {
+	service *app.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler.
+func NewAccountHandler(service *app.AccountService) *AccountHandler {
+	return &AccountHandler{service: service}
+}
+
+// GetMyAccount returns the authenticated user's primary account details.
+func (h *AccountHandler) GetMyAccount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	details, err := h.service.GetAccountDetails(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, app.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, app.ErrAccountNotFound) {
+			http.Error(w, "Account not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("level=error component=api endpoint=get_my_account outcome=failed err=%v", err)
+		http.Error(w, "Failed to retrieve account details", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, details)
+}
+
+// PotHandler holds the dependencies for pot-related handlers.
+type PotHandler struct {
+	service *app.AccountService
+}
+
+// NewPotHandler creates a new PotHandler.
+func NewPotHandler(service *app.AccountService) *PotHandler {
+	return &PotHandler{service: service}
+}
+
+// CreatePotRequest defines the expected JSON body for creating a pot.
+type CreatePotRequest struct {
+	Name         string `json:"name"`
+	TargetAmount *int64 `json:"target_amount"`
+}
+
+// CreatePot handles the creation of a new savings pot.
+func (h *PotHandler) CreatePot(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreatePotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pot, err := h.service.CreatePot(r.Context(), app.CreatePotInput{
+		UserID:       userID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+	})
+	if err != nil {
+		if errors.Is(err, app.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, app.ErrInvalidPotName) || errors.Is(err, app.ErrInvalidPotTargetAmount) || errors.Is(err, app.ErrPotLimitReached) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("level=error component=api endpoint=create_pot outcome=failed err=%v", err)
+		http.Error(w, "Failed to create pot", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, pot)
+}
+
+// ListPots handles listing all open pots for the authenticated user.
+func (h *PotHandler) ListPots(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pots, err := h.service.ListPots(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, app.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pots)
+}
+
+// DeletePot handles closing a specific pot. The pot must be empty.
+func (h *PotHandler) DeletePot(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	potID := chi.URLParam(r, "id")
+	if potID == "" {
+		http.Error(w, "Pot ID is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeletePot(r.Context(), userID, potID)
+	if err != nil {
+		if errors.Is(err, app.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrPotNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrPotNotEmpty) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
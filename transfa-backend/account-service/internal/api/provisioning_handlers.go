@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/transfa/account-service/internal/app"
+	"github.com/transfa/account-service/internal/domain"
+)
+
+// AccountProvisioner runs primary account provisioning on demand.
+type AccountProvisioner interface {
+	ForceAccountProvisioning(ctx context.Context, userID string) (*domain.AccountProvisioningJob, error)
+}
+
+// ProvisioningHandler holds the dependencies for the internal provisioning endpoint.
+type ProvisioningHandler struct {
+	provisioner AccountProvisioner
+}
+
+// NewProvisioningHandler creates a new ProvisioningHandler.
+func NewProvisioningHandler(provisioner AccountProvisioner) *ProvisioningHandler {
+	return &ProvisioningHandler{provisioner: provisioner}
+}
+
+// ForceProvisioning lets support retry a user's primary account provisioning now. It
+// responds with the job as the attempt left it; a failed attempt is 502 and carries
+// the job's last error.
+func (h *ProvisioningHandler) ForceProvisioning(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(chi.URLParam(r, "user_id"))
+	if userID == "" {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.provisioner.ForceAccountProvisioning(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrUserNotFound):
+			http.Error(w, "User not found", http.StatusNotFound)
+		case errors.Is(err, app.ErrCustomerNotVerified), errors.Is(err, app.ErrProvisioningInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("level=error component=api endpoint=force_provisioning outcome=failed user_id=%s err=%v", userID, err)
+			http.Error(w, "Failed to provision account", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if job.Status != domain.ProvisioningCompleted {
+		status = http.StatusBadGateway
+	}
+	log.Printf("level=info component=api endpoint=force_provisioning outcome=%s user_id=%s attempts=%d", job.Status, userID, job.Attempts)
+	writeJSON(w, status, job)
+}
@@ -18,16 +18,18 @@ import (
 	"github.com/transfa/account-service/internal/app"
 	"github.com/transfa/account-service/internal/config"
 	appmiddleware "github.com/transfa/account-service/pkg/middleware"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 )
 
 // NewRouter creates and configures a new HTTP router.
-func NewRouter(cfg *config.Config, service *app.AccountService) http.Handler {
+func NewRouter(cfg *config.Config, service *app.AccountService, provisioner AccountProvisioner, allowedOrigins []string) http.Handler {
 	r := chi.NewRouter()
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
 	r.Use(chimiddleware.Logger)
 	r.Use(chimiddleware.Recoverer)
-	r.Use(chimiddleware.Timeout(30 * time.Second))
+	r.Use(sharedmiddleware.RequestTimeout(30 * time.Second))
+	r.Use(sharedmiddleware.CORS(allowedOrigins))
 
 	// Health check endpoint
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -37,13 +39,21 @@ func NewRouter(cfg *config.Config, service *app.AccountService) http.Handler {
 
 	beneficiaryHandler := NewBeneficiaryHandler(service)
 	bankHandler := NewBankHandler(service)
+	accountHandler := NewAccountHandler(service)
+	potHandler := NewPotHandler(service)
 	internalAccountHandler := NewInternalAccountHandler(service)
+	provisioningHandler := NewProvisioningHandler(provisioner)
 
 	// Internal routes (no authentication required for service-to-service communication)
 	r.Route("/internal", func(r chi.Router) {
 		r.Use(appmiddleware.InternalAuthMiddleware(cfg.InternalAPIKey))
 		r.Route("/accounts", func(r chi.Router) {
 			r.Post("/money-drop", internalAccountHandler.CreateMoneyDropAccount)
+			r.Post("/provision/{user_id}", provisioningHandler.ForceProvisioning)
+		})
+		r.Route("/users/{id}", func(r chi.Router) {
+			r.Post("/freeze", internalAccountHandler.FreezeUser)
+			r.Post("/unfreeze", internalAccountHandler.UnfreezeUser)
 		})
 	})
 
@@ -52,6 +62,7 @@ func NewRouter(cfg *config.Config, service *app.AccountService) http.Handler {
 		// Apply rate limiting first (1000 requests per minute per IP)
 		r.Use(appmiddleware.RateLimitMiddleware(1000))
 		r.Use(appmiddleware.AuthMiddleware(cfg))
+		r.Use(appmiddleware.ClosedAccountMiddleware(service.IsUserClosed))
 
 		r.Route("/beneficiaries", func(r chi.Router) {
 			r.Post("/verify", beneficiaryHandler.VerifyBeneficiaryAccount)
@@ -63,6 +74,16 @@ func NewRouter(cfg *config.Config, service *app.AccountService) http.Handler {
 		r.Route("/banks", func(r chi.Router) {
 			r.Get("/", bankHandler.ListBanks)
 		})
+
+		r.Route("/accounts", func(r chi.Router) {
+			r.Get("/me", accountHandler.GetMyAccount)
+
+			r.Route("/pots", func(r chi.Router) {
+				r.Post("/", potHandler.CreatePot)
+				r.Get("/", potHandler.ListPots)
+				r.Delete("/{id}", potHandler.DeletePot)
+			})
+		})
 	})
 
 	return r
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+
+// GetAccountDetails returns the caller's primary account details. Values are served from the
+// accounts table; when the NUBAN, bank name or account name is missing locally they are fetched
+// from Anchor and written back so later reads are served from the database.
+func (s *AccountService) GetAccountDetails(ctx context.Context, clerkUserID string) (*domain.AccountDetails, error) {
+	internalUserID, err := s.resolveInternalUserID(ctx, clerkUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.FindPrimaryAccountByUserID(ctx, internalUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to find primary account: %w", err)
+	}
+
+	details := &domain.AccountDetails{
+		AccountID:     account.ID,
+		AccountNumber: account.VirtualNUBAN,
+		BankName:      account.BankName,
+		AccountName:   account.AccountName,
+		AccountType:   account.Type,
+		CreatedAt:     account.CreatedAt,
+	}
+
+	needsAnchor := details.AccountNumber == "" || details.BankName == "" || details.AccountName == ""
+	if !needsAnchor || account.AnchorAccountID == "" {
+		return details, nil
+	}
+
+	anchorDetails, err := s.anchorClient.GetAccount(ctx, account.AnchorAccountID)
+	if err != nil {
+		// Serve what we have; the missing fields are retried on the next read.
+		log.Printf("level=warn component=account_service flow=account_details msg=\"anchor account lookup failed\" account_id=%s err=%v", account.ID, err)
+		return details, nil
+	}
+
+	if details.AccountNumber == "" {
+		details.AccountNumber = anchorDetails.AccountNumber
+	}
+	if details.BankName == "" {
+		details.BankName = anchorDetails.BankName
+	}
+	if details.AccountName == "" {
+		details.AccountName = anchorDetails.AccountName
+	}
+
+	if _, err := s.accountRepo.UpdateAccountDetailsByAnchorAccountID(ctx, account.AnchorAccountID, *anchorDetails); err != nil {
+		log.Printf("level=warn component=account_service flow=account_details msg=\"account details backfill failed\" account_id=%s err=%v", account.ID, err)
+	}
+
+	return details, nil
+}
+
+// IsUserClosed reports whether the user behind a Clerk User ID has closed their account.
+func (s *AccountService) IsUserClosed(ctx context.Context, clerkUserID string) (bool, error) {
+	return s.accountRepo.IsUserClosedByClerkUserID(ctx, clerkUserID)
+}
@@ -13,28 +13,44 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/transfa/account-service/internal/domain"
 	"github.com/transfa/account-service/internal/store"
-	"github.com/transfa/account-service/pkg/anchorclient"
+	"github.com/transfa/pkg/anchorclient"
 )
 
 // AccountEventHandler handles the processing of account-related events.
 type AccountEventHandler struct {
 	repo         store.AccountRepository
 	anchorClient *anchorclient.Client
+	publisher    EventPublisher
+
+	// onboardingStatusDualWrite also writes onboarding_status directly; see
+	// onboarding_status.go.
+	onboardingStatusDualWrite bool
+}
+
+// EventPublisher publishes account events to the message broker.
+type EventPublisher interface {
+	Publish(ctx context.Context, exchange, routingKey string, payload interface{}) error
 }
 
-// NewAccountEventHandler creates a new instance of AccountEventHandler.
-func NewAccountEventHandler(repo store.AccountRepository, anchorClient *anchorclient.Client) *AccountEventHandler {
-	return &AccountEventHandler{
-		repo:         repo,
-		anchorClient: anchorClient,
+// NewAccountEventHandler creates a new instance of AccountEventHandler. The publisher
+// may be nil, in which case no events are published.
+func NewAccountEventHandler(repo store.AccountRepository, anchorClient *anchorclient.Client, publisher EventPublisher, opts ...HandlerOption) *AccountEventHandler {
+	h := &AccountEventHandler{
+		repo:                      repo,
+		anchorClient:              anchorClient,
+		publisher:                 publisher,
+		onboardingStatusDualWrite: true,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // HandleCustomerVerifiedEvent processes Tier2 approval events.
@@ -76,84 +92,105 @@ func (h *AccountEventHandler) HandleCustomerVerifiedEvent(body []byte) bool {
 	}
 
 	approvedReason := ptr("Tier2 approved")
-	if err := h.repo.UpdateTierStatus(ctx, userID, "tier2", "approved", approvedReason); err != nil {
+	if err := h.recordOnboardingStatus(ctx, userID, "tier2", "approved", approvedReason); err != nil {
 		log.Printf("WARN: Failed to persist tier2 approved status for user %s: %v", userID, err)
 	}
 
-	accountReq := domain.CreateDepositAccountRequest{
-		Data: domain.RequestData{
-			Type: "DepositAccount",
-			Attributes: domain.DepositAccountAttributes{
-				ProductName: "SAVINGS",
-			},
-			Relationships: map[string]interface{}{
-				"customer": domain.CustomerRelationshipData{
-					Data: struct {
-						ID   string `json:"id"`
-						Type string `json:"type"`
-					}{
-						ID:   event.AnchorCustomerID,
-						Type: "IndividualCustomer",
-					},
-				},
-			},
-		},
-	}
-	anchorAccount, err := h.anchorClient.CreateDepositAccount(ctx, accountReq)
+	job, err := h.repo.StartAccountProvisioning(ctx, userID, event.AnchorCustomerID, provisioningClaimTTL)
 	if err != nil {
-		log.Printf("ERROR: Failed to create Anchor DepositAccount for AnchorCustomerID %s: %v", event.AnchorCustomerID, err)
-		reason := fmt.Sprintf("Failed to create Anchor deposit account: %v", err)
-		_ = h.repo.UpdateTierStatus(ctx, userID, "tier2", "failed", ptr(reason))
-		return true // Ack to avoid duplicate provisioning attempts.
+		return false // Retryable database error.
+	}
+	if job == nil {
+		log.Printf("INFO: Account provisioning for user %s is already completed or in progress. Skipping.", userID)
+		return true
 	}
-	log.Printf("Successfully created Anchor DepositAccount %s", anchorAccount.Data.ID)
 
-	nubanInfo, err := h.anchorClient.GetVirtualNUBANForAccount(ctx, anchorAccount.Data.ID)
+	// Failed attempts are retried from account_provisioning_jobs, so the message is
+	// acknowledged either way.
+	h.runProvisioningAttempt(ctx, job)
+	return true
+}
+
+// HandleAccountLifecycleEvent backfills account details when Anchor's account.opened
+// webhook arrives after the account row was created without its NUBAN or bank name.
+func (h *AccountEventHandler) HandleAccountLifecycleEvent(body []byte) bool {
+	var event domain.AccountLifecycleEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling account.lifecycle event: %v", err)
+		return true // Acknowledge malformed message.
+	}
+
+	if event.EventType != "account_opened" {
+		return true
+	}
+	if event.ResourceID == "" {
+		log.Printf("account.lifecycle event missing ResourceID; acking")
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	details, err := h.anchorClient.GetAccount(ctx, event.ResourceID)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch VirtualNUBAN for AnchorAccountID %s: %v", anchorAccount.Data.ID, err)
-		reason := fmt.Sprintf("Failed to fetch virtual account number: %v", err)
-		_ = h.repo.UpdateTierStatus(ctx, userID, "tier2", "failed", ptr(reason))
-		return true // Ack to prevent hot-looping; manual retry can be triggered later.
-	}
-	log.Printf("Successfully fetched VirtualNUBAN: %s, Bank: %s", nubanInfo.AccountNumber, nubanInfo.BankName)
-
-	// Get bank name from the original account creation response if not available from NUBAN fetch
-	bankName := nubanInfo.BankName
-	if bankName == "" {
-		// Type assert Attributes to map[string]interface{} before accessing
-		if attributesMap, ok := anchorAccount.Data.Attributes.(map[string]interface{}); ok {
-			if bankData, exists := attributesMap["bank"]; exists {
-				if bankMap, ok := bankData.(map[string]interface{}); ok {
-					if name, exists := bankMap["name"]; exists {
-						if nameStr, ok := name.(string); ok {
-							bankName = nameStr
-						}
-					}
-				}
-			}
-		}
+		log.Printf("ERROR: Failed to fetch Anchor account %s for account.opened event: %v", event.ResourceID, err)
+		return true // Ack to prevent hot-looping; details are backfilled on the next read.
 	}
 
-	newAccount := &domain.Account{
-		UserID:          userID,
-		AnchorAccountID: anchorAccount.Data.ID,
-		VirtualNUBAN:    nubanInfo.AccountNumber,
-		BankName:        bankName,
-		Type:            domain.PrimaryAccount,
+	updated, err := h.repo.UpdateAccountDetailsByAnchorAccountID(ctx, event.ResourceID, *details)
+	if err != nil {
+		log.Printf("ERROR: Failed to update account details for Anchor account %s: %v", event.ResourceID, err)
+		return false // Retryable database error.
+	}
+	if !updated {
+		// The account row is written with its details during provisioning, so nothing to backfill.
+		log.Printf("INFO: No account found for Anchor account %s; skipping account.opened backfill", event.ResourceID)
+		return true
+	}
+
+	log.Printf("Successfully backfilled account details for Anchor account %s", event.ResourceID)
+	return true
+}
+
+// HandleUserClosedEvent freezes the Anchor deposit accounts of a user who closed their
+// account and marks the local account rows closed. Accounts whose freeze fails are left
+// open so they show up for manual follow-up.
+func (h *AccountEventHandler) HandleUserClosedEvent(body []byte) bool {
+	var event domain.UserClosedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling user.closed event: %v", err)
+		return true // Acknowledge malformed message.
 	}
-	if _, err = h.repo.CreateAccount(ctx, newAccount); err != nil {
-		log.Printf("ERROR: Failed to save new account to DB for UserID %s: %v", userID, err)
-		reason := fmt.Sprintf("Failed to persist account record: %v", err)
-		_ = h.repo.UpdateTierStatus(ctx, userID, "tier2", "failed", ptr(reason))
-		return false // Requeue for transient database errors.
+
+	if event.UserID == "" {
+		log.Printf("user.closed event missing UserID; acking")
+		return true
 	}
 
-	if err := h.repo.UpdateTierStatus(ctx, userID, "tier2", "completed", nil); err != nil {
-		log.Printf("WARN: Failed to persist tier2 completed status for user %s: %v", userID, err)
+	log.Printf("Processing user.closed event for UserID: %s", event.UserID)
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	defer cancel()
+
+	accounts, err := h.repo.ListOpenAccountsByUserID(ctx, event.UserID)
+	if err != nil {
+		log.Printf("ERROR: Failed to list open accounts for closed user %s: %v", event.UserID, err)
+		return false // Retryable database error.
 	}
 
-	log.Printf("Successfully provisioned and saved account for UserID %s", userID)
+	for _, account := range accounts {
+		if account.AnchorAccountID != "" {
+			if err := h.anchorClient.FreezeDepositAccount(ctx, account.AnchorAccountID, "BY_CUSTOMER", "Account closed by customer"); err != nil {
+				log.Printf("ERROR: Failed to freeze Anchor account %s for closed user %s: %v", account.AnchorAccountID, event.UserID, err)
+				continue
+			}
+		}
+		if err := h.repo.CloseAccount(ctx, account.ID); err != nil {
+			log.Printf("ERROR: Failed to close account %s for closed user %s: %v", account.ID, event.UserID, err)
+			return false // Retryable database error; already closed accounts are skipped on retry.
+		}
+	}
 
+	log.Printf("Processed user.closed event for UserID %s (%d accounts)", event.UserID, len(accounts))
 	return true
 }
 
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/transfa/account-service/internal/domain"
+	"github.com/transfa/account-service/internal/store"
+	"github.com/transfa/pkg/anchorclient"
+)
+
+// moneyDropAccountRepoStub keeps a single user's money drop account in memory.
+type moneyDropAccountRepoStub struct {
+	store.AccountRepository
+
+	account *domain.Account
+}
+
+func (s *moneyDropAccountRepoStub) FindAnchorCustomerIDByUserID(ctx context.Context, userID string) (string, error) {
+	return "cust-1", nil
+}
+
+func (s *moneyDropAccountRepoStub) FindMoneyDropAccountByUserID(ctx context.Context, userID string) (*domain.Account, error) {
+	if s.account == nil {
+		return nil, nil
+	}
+	account := *s.account
+	return &account, nil
+}
+
+func (s *moneyDropAccountRepoStub) UpsertMoneyDropAccount(ctx context.Context, account *domain.Account) (*domain.Account, error) {
+	if s.account != nil && s.account.AnchorAccountID != "" {
+		existing := *s.account
+		return &existing, nil
+	}
+	saved := *account
+	saved.ID = "acct-1"
+	s.account = &saved
+	return &saved, nil
+}
+
+// newMoneyDropAnchorServer answers Anchor account creation and lookup, counting
+// creations. When reject is set, creation fails with a validation error.
+func newMoneyDropAnchorServer(t *testing.T, reject bool, created *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/accounts":
+			*created++
+			if reject {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"Invalid customer","detail":"customer is not verified"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"anchor-acct-1","type":"DepositAccount"}}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/accounts/"):
+			_, _ = w.Write([]byte(`{"data":{"id":"anchor-acct-1","attributes":{"bank":{"name":"Anchor Bank"}}},"included":[{"type":"AccountNumber","attributes":{"accountNumber":"0123456789"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCreateMoneyDropAccount_ReturnsExistingAccountOnSecondCall(t *testing.T) {
+	created := 0
+	server := newMoneyDropAnchorServer(t, false, &created)
+	repo := &moneyDropAccountRepoStub{}
+	svc := NewAccountService(repo, nil, nil, anchorclient.NewClient(server.URL, "test-key"))
+
+	first, err := svc.CreateMoneyDropAccount(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("first CreateMoneyDropAccount: %v", err)
+	}
+	if first.AccountID != "acct-1" || first.AnchorAccountID != "anchor-acct-1" || first.VirtualNUBAN != "0123456789" || first.BankName != "Anchor Bank" {
+		t.Fatalf("unexpected account: %+v", first)
+	}
+
+	second, err := svc.CreateMoneyDropAccount(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("second CreateMoneyDropAccount: %v", err)
+	}
+	if *second != *first {
+		t.Fatalf("expected the existing account %+v, got %+v", first, second)
+	}
+	if created != 1 {
+		t.Fatalf("expected one Anchor account to be created, got %d", created)
+	}
+}
+
+func TestCreateMoneyDropAccount_PropagatesAnchorError(t *testing.T) {
+	created := 0
+	server := newMoneyDropAnchorServer(t, true, &created)
+	repo := &moneyDropAccountRepoStub{}
+	svc := NewAccountService(repo, nil, nil, anchorclient.NewClient(server.URL, "test-key"))
+
+	_, err := svc.CreateMoneyDropAccount(context.Background(), "user-1")
+	var anchorErr *anchorclient.APIError
+	if !errors.As(err, &anchorErr) {
+		t.Fatalf("expected an Anchor API error, got %v", err)
+	}
+	if anchorErr.StatusCode != http.StatusUnprocessableEntity || anchorErr.Detail != "customer is not verified" {
+		t.Fatalf("unexpected Anchor error: %+v", anchorErr)
+	}
+	if repo.account != nil {
+		t.Fatalf("expected no account to be recorded, got %+v", repo.account)
+	}
+}
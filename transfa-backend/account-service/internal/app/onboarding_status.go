@@ -0,0 +1,60 @@
+/**
+ * @description
+ * This file reports onboarding stage changes. auth-service owns onboarding_status and
+ * keeps it from the onboarding.stage.changed events published here.
+ *
+ * @notes
+ * - While the migration to events rolls out, the handler also writes onboarding_status
+ *   directly (dual-write). Turn it off with ONBOARDING_STATUS_DUAL_WRITE=false once
+ *   auth-service consumes the events.
+ */
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/transfa/account-service/internal/domain"
+)
+
+// OnboardingStageChangedRoutingKey is published on AccountEventsExchange whenever an
+// onboarding stage changes.
+const OnboardingStageChangedRoutingKey = "onboarding.stage.changed"
+
+// HandlerOption configures an AccountEventHandler.
+type HandlerOption func(*AccountEventHandler)
+
+// WithOnboardingStatusDualWrite sets whether the handler writes onboarding_status
+// itself in addition to publishing onboarding.stage.changed. It is on by default.
+func WithOnboardingStatusDualWrite(enabled bool) HandlerOption {
+	return func(h *AccountEventHandler) {
+		h.onboardingStatusDualWrite = enabled
+	}
+}
+
+// recordOnboardingStatus publishes the new status of a user's onboarding stage and,
+// while dual-write is on, writes it to onboarding_status too. With dual-write off a
+// failed publish is returned, since the change would otherwise be lost.
+func (h *AccountEventHandler) recordOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	event := domain.OnboardingStageChangedEvent{
+		UserID:     userID,
+		Stage:      stage,
+		Status:     status,
+		Reason:     reason,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	var publishErr error
+	if h.publisher != nil {
+		publishErr = h.publisher.Publish(ctx, AccountEventsExchange, OnboardingStageChangedRoutingKey, event)
+		if publishErr != nil {
+			log.Printf("WARN: Failed to publish %s %s for user %s: %v", stage, status, userID, publishErr)
+		}
+	}
+
+	if !h.onboardingStatusDualWrite {
+		return publishErr
+	}
+	return h.repo.UpdateTierStatus(ctx, userID, stage, status, reason)
+}
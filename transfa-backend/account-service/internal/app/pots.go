@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+)
+
+const (
+	maxPotNameLen  = 50
+	maxPotsPerUser = 10
+)
+
+var (
+	ErrInvalidPotName         = errors.New("pot name must be between 1 and 50 characters")
+	ErrInvalidPotTargetAmount = errors.New("pot target amount must be greater than zero")
+	ErrPotLimitReached        = errors.New("you can have a maximum of 10 pots")
+)
+
+// CreatePotInput defines the required input for creating a pot.
+type CreatePotInput struct {
+	UserID       string // Clerk User ID
+	Name         string
+	TargetAmount *int64
+}
+
+// CreatePot provisions a dedicated Anchor deposit account and records it as a pot.
+func (s *AccountService) CreatePot(ctx context.Context, input CreatePotInput) (*domain.Pot, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxPotNameLen {
+		return nil, ErrInvalidPotName
+	}
+	if input.TargetAmount != nil && *input.TargetAmount <= 0 {
+		return nil, ErrInvalidPotTargetAmount
+	}
+
+	internalUserID, err := s.resolveInternalUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.accountRepo.ListPotsByUserID(ctx, internalUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing pots: %w", err)
+	}
+	if len(existing) >= maxPotsPerUser {
+		return nil, ErrPotLimitReached
+	}
+
+	deposit, err := s.provisionDepositAccount(ctx, internalUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	pot, err := s.accountRepo.CreatePot(ctx, &domain.Pot{
+		UserID:          internalUserID,
+		Name:            name,
+		TargetAmount:    input.TargetAmount,
+		AnchorAccountID: deposit.AnchorAccountID,
+		VirtualNUBAN:    deposit.VirtualNUBAN,
+		BankName:        deposit.BankName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save pot to database: %w", err)
+	}
+
+	log.Printf("Successfully created pot %s for user %s", pot.ID, internalUserID)
+	return pot, nil
+}
+
+// ListPots retrieves all open pots for a user.
+func (s *AccountService) ListPots(ctx context.Context, clerkUserID string) ([]domain.Pot, error) {
+	internalUserID, err := s.resolveInternalUserID(ctx, clerkUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.ListPotsByUserID(ctx, internalUserID)
+}
+
+// DeletePot closes a user's pot. Only pots with a zero balance can be closed; the
+// backing Anchor account is left in place and simply stops being used.
+func (s *AccountService) DeletePot(ctx context.Context, clerkUserID, potID string) error {
+	internalUserID, err := s.resolveInternalUserID(ctx, clerkUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.accountRepo.ClosePot(ctx, potID, internalUserID)
+}
+
+// resolveInternalUserID resolves a Clerk User ID to the internal user UUID.
+func (s *AccountService) resolveInternalUserID(ctx context.Context, clerkUserID string) (string, error) {
+	internalUserID, err := s.accountRepo.FindUserIDByClerkUserID(ctx, clerkUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to resolve user: %w", err)
+	}
+	return internalUserID, nil
+}
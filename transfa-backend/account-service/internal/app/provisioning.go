@@ -0,0 +1,293 @@
+/**
+ * @description
+ * This file contains primary account provisioning for verified customers. Attempts
+ * are tracked in account_provisioning_jobs so a failed Anchor call is retried with
+ * backoff instead of leaving a verified user without an account.
+ *
+ * @notes
+ * - The first attempt runs from the customer.verified consumer, later ones from the
+ *   retry loop or the internal force-retry endpoint.
+ * - Each attempt publishes account.provisioned or account.provisioning_failed so
+ *   auth-service can show where provisioning stands.
+ */
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+	"github.com/transfa/pkg/anchorclient"
+)
+
+const (
+	// AccountEventsExchange carries the account provisioning events.
+	AccountEventsExchange = "customer_events"
+	// AccountProvisionedRoutingKey is published once a primary account exists.
+	AccountProvisionedRoutingKey = "account.provisioned"
+	// AccountProvisioningFailedRoutingKey is published after every failed attempt.
+	AccountProvisioningFailedRoutingKey = "account.provisioning_failed"
+)
+
+// provisioningBackoff is the delay after each failed attempt.
+var provisioningBackoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+const (
+	// maxProvisioningAttempts caps the automatic attempts per job.
+	maxProvisioningAttempts = 5
+	// provisioningBatchSize is how many due jobs one retry pass claims.
+	provisioningBatchSize = 20
+	// provisioningClaimTTL is how long an attempt may stay in processing before
+	// another worker takes the job over.
+	provisioningClaimTTL = 5 * time.Minute
+)
+
+var (
+	// ErrProvisioningInProgress is returned when another attempt holds the job.
+	ErrProvisioningInProgress = errors.New("account provisioning is already in progress")
+	// ErrCustomerNotVerified is returned for a user without an Anchor customer.
+	ErrCustomerNotVerified = errors.New("user has no Anchor customer to provision an account for")
+)
+
+// provisioningDelay returns the wait after the given number of failed attempts.
+func provisioningDelay(attempts int) time.Duration {
+	index := attempts - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(provisioningBackoff) {
+		index = len(provisioningBackoff) - 1
+	}
+	return provisioningBackoff[index]
+}
+
+// isRetriableProvisioningError reports whether a later attempt may succeed. Only
+// Anchor's deterministic rejections are final.
+func isRetriableProvisioningError(err error) bool {
+	var apiErr *anchorclient.APIError
+	if errors.As(err, &apiErr) {
+		return !apiErr.IsExplicitRejection()
+	}
+	return true
+}
+
+// RunProvisioningRetries retries due provisioning jobs every interval until ctx is done.
+func (h *AccountEventHandler) RunProvisioningRetries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.retryDueProvisioningJobs(ctx)
+		}
+	}
+}
+
+// retryDueProvisioningJobs claims the due provisioning jobs and runs another attempt of each.
+func (h *AccountEventHandler) retryDueProvisioningJobs(ctx context.Context) {
+	jobs, err := h.repo.ClaimDueAccountProvisioningJobs(ctx, provisioningBatchSize, provisioningClaimTTL)
+	if err != nil {
+		return
+	}
+	for i := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		h.runProvisioningAttempt(attemptCtx, &jobs[i])
+		cancel()
+	}
+}
+
+// ForceAccountProvisioning runs a provisioning attempt for userID now, whatever the
+// job's schedule. A job that failed for good starts over with a fresh attempt count.
+// It returns the job as the attempt left it.
+func (h *AccountEventHandler) ForceAccountProvisioning(ctx context.Context, userID string) (*domain.AccountProvisioningJob, error) {
+	existingAccount, err := h.repo.FindAccountByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if existingAccount != nil {
+		return &domain.AccountProvisioningJob{UserID: userID, AnchorAccountID: &existingAccount.AnchorAccountID, Status: domain.ProvisioningCompleted}, nil
+	}
+
+	anchorCustomerID, err := h.repo.FindAnchorCustomerIDByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if anchorCustomerID == "" {
+		return nil, ErrCustomerNotVerified
+	}
+
+	job, err := h.repo.StartAccountProvisioning(ctx, userID, anchorCustomerID, provisioningClaimTTL)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrProvisioningInProgress
+	}
+
+	log.Printf("Forcing account provisioning attempt %d for user %s", job.Attempts, userID)
+	h.runProvisioningAttempt(ctx, job)
+	return job, nil
+}
+
+// runProvisioningAttempt provisions the job's account and records the outcome on the
+// job, the tier2 onboarding status and the event bus.
+func (h *AccountEventHandler) runProvisioningAttempt(ctx context.Context, job *domain.AccountProvisioningJob) {
+	account, err := h.provisionAccount(ctx, job)
+	if err != nil {
+		h.recordProvisioningFailure(ctx, job, err)
+		return
+	}
+
+	if err := h.repo.CompleteAccountProvisioning(ctx, job.UserID); err != nil {
+		log.Printf("WARN: Failed to mark account provisioning completed for user %s: %v", job.UserID, err)
+	}
+	job.Status = domain.ProvisioningCompleted
+	job.LastError = nil
+	if err := h.recordOnboardingStatus(ctx, job.UserID, "tier2", "completed", nil); err != nil {
+		log.Printf("WARN: Failed to persist tier2 completed status for user %s: %v", job.UserID, err)
+	}
+	log.Printf("Successfully provisioned and saved account for UserID %s", job.UserID)
+
+	h.publish(ctx, AccountProvisionedRoutingKey, domain.AccountProvisionedEvent{
+		UserID:           job.UserID,
+		AnchorCustomerID: job.AnchorCustomerID,
+		AccountID:        account.ID,
+		AnchorAccountID:  account.AnchorAccountID,
+		ProvisionedAt:    time.Now().UTC(),
+	})
+}
+
+// recordProvisioningFailure schedules the job's next attempt, or fails it when the
+// error is final or the attempts ran out.
+func (h *AccountEventHandler) recordProvisioningFailure(ctx context.Context, job *domain.AccountProvisioningJob, cause error) {
+	reason := fmt.Sprintf("Failed to %v", cause)
+	job.LastError = &reason
+
+	willRetry := isRetriableProvisioningError(cause) && job.Attempts < maxProvisioningAttempts
+	if willRetry {
+		nextAttemptAt := time.Now().Add(provisioningDelay(job.Attempts))
+		log.Printf("ERROR: Account provisioning attempt %d for user %s failed; retrying at %s: %v", job.Attempts, job.UserID, nextAttemptAt.Format(time.RFC3339), cause)
+		job.Status = domain.ProvisioningPending
+		job.NextAttemptAt = nextAttemptAt
+		_ = h.repo.RecordAccountProvisioningFailure(ctx, job.UserID, &nextAttemptAt, reason)
+	} else {
+		log.Printf("CRITICAL: Account provisioning for user %s failed after %d attempts: %v", job.UserID, job.Attempts, cause)
+		job.Status = domain.ProvisioningFailed
+		_ = h.repo.RecordAccountProvisioningFailure(ctx, job.UserID, nil, reason)
+		_ = h.recordOnboardingStatus(ctx, job.UserID, "tier2", "failed", ptr(reason))
+	}
+
+	h.publish(ctx, AccountProvisioningFailedRoutingKey, domain.AccountProvisioningFailedEvent{
+		UserID:           job.UserID,
+		AnchorCustomerID: job.AnchorCustomerID,
+		Reason:           reason,
+		Attempts:         job.Attempts,
+		WillRetry:        willRetry,
+		FailedAt:         time.Now().UTC(),
+	})
+}
+
+// provisionAccount creates the Anchor deposit account, unless an earlier attempt did,
+// and stores it with its virtual account number as the user's primary account.
+func (h *AccountEventHandler) provisionAccount(ctx context.Context, job *domain.AccountProvisioningJob) (*domain.Account, error) {
+	var anchorAccountID, attributesBankName string
+	if job.AnchorAccountID != nil {
+		anchorAccountID = *job.AnchorAccountID
+	}
+
+	if anchorAccountID == "" {
+		accountReq := domain.CreateDepositAccountRequest{
+			Data: domain.RequestData{
+				Type: "DepositAccount",
+				Attributes: domain.DepositAccountAttributes{
+					ProductName: "SAVINGS",
+				},
+				Relationships: map[string]interface{}{
+					"customer": domain.CustomerRelationshipData{
+						Data: struct {
+							ID   string `json:"id"`
+							Type string `json:"type"`
+						}{
+							ID:   job.AnchorCustomerID,
+							Type: "IndividualCustomer",
+						},
+					},
+				},
+			},
+		}
+		anchorAccount, err := h.anchorClient.CreateDepositAccount(ctx, accountReq)
+		if err != nil {
+			return nil, fmt.Errorf("create Anchor deposit account: %w", err)
+		}
+		log.Printf("Successfully created Anchor DepositAccount %s", anchorAccount.Data.ID)
+
+		anchorAccountID = anchorAccount.Data.ID
+		attributesBankName = bankNameFromAttributes(anchorAccount.Data.Attributes)
+		job.AnchorAccountID = &anchorAccountID
+		if err := h.repo.SetProvisioningAnchorAccountID(ctx, job.UserID, anchorAccountID); err != nil {
+			log.Printf("WARN: Anchor account %s for user %s is not recorded on its provisioning job: %v", anchorAccountID, job.UserID, err)
+		}
+	}
+
+	nubanInfo, err := h.anchorClient.GetVirtualNUBANForAccount(ctx, anchorAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch virtual account number: %w", err)
+	}
+	log.Printf("Successfully fetched VirtualNUBAN: %s, Bank: %s", nubanInfo.AccountNumber, nubanInfo.BankName)
+
+	// Get bank name from the original account creation response if not available from NUBAN fetch
+	bankName := nubanInfo.BankName
+	if bankName == "" {
+		bankName = attributesBankName
+	}
+
+	newAccount := &domain.Account{
+		UserID:          job.UserID,
+		AnchorAccountID: anchorAccountID,
+		VirtualNUBAN:    nubanInfo.AccountNumber,
+		BankName:        bankName,
+		Type:            domain.PrimaryAccount,
+	}
+	accountID, err := h.repo.CreateAccount(ctx, newAccount)
+	if err != nil {
+		return nil, fmt.Errorf("persist account record: %w", err)
+	}
+	newAccount.ID = accountID
+	return newAccount, nil
+}
+
+// bankNameFromAttributes reads bank.name from an Anchor account's attributes.
+func bankNameFromAttributes(attributes interface{}) string {
+	attributesMap, ok := attributes.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	bankMap, ok := attributesMap["bank"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	name, _ := bankMap["name"].(string)
+	return name
+}
+
+func (h *AccountEventHandler) publish(ctx context.Context, routingKey string, payload interface{}) {
+	if h.publisher == nil {
+		return
+	}
+	if err := h.publisher.Publish(ctx, AccountEventsExchange, routingKey, payload); err != nil {
+		log.Printf("WARN: Failed to publish %s event: %v", routingKey, err)
+	}
+}
@@ -0,0 +1,282 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+	"github.com/transfa/account-service/internal/store"
+	"github.com/transfa/pkg/anchorclient"
+)
+
+const customerVerifiedBody = `{"anchor_customer_id":"cust-1"}`
+
+// provisioningRepoStub keeps one verified user's provisioning job and account in memory.
+type provisioningRepoStub struct {
+	store.AccountRepository
+
+	job      *domain.AccountProvisioningJob
+	account  *domain.Account
+	statuses map[string]string
+}
+
+func newProvisioningRepoStub() *provisioningRepoStub {
+	return &provisioningRepoStub{statuses: map[string]string{}}
+}
+
+func (s *provisioningRepoStub) FindUserIDByAnchorCustomerID(ctx context.Context, anchorID string) (string, error) {
+	return "user-1", nil
+}
+
+func (s *provisioningRepoStub) FindAnchorCustomerIDByUserID(ctx context.Context, userID string) (string, error) {
+	if userID != "user-1" {
+		return "", pgx.ErrNoRows
+	}
+	return "cust-1", nil
+}
+
+func (s *provisioningRepoStub) FindAccountByUserID(ctx context.Context, userID string) (*domain.Account, error) {
+	if s.account == nil || s.account.UserID != userID {
+		return nil, pgx.ErrNoRows
+	}
+	return s.account, nil
+}
+
+func (s *provisioningRepoStub) CreateAccount(ctx context.Context, account *domain.Account) (string, error) {
+	saved := *account
+	saved.ID = "acct-1"
+	s.account = &saved
+	return saved.ID, nil
+}
+
+func (s *provisioningRepoStub) UpdateTierStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	s.statuses[stage] = status
+	return nil
+}
+
+func (s *provisioningRepoStub) StartAccountProvisioning(ctx context.Context, userID, anchorCustomerID string, staleAfter time.Duration) (*domain.AccountProvisioningJob, error) {
+	switch {
+	case s.job == nil:
+		s.job = &domain.AccountProvisioningJob{UserID: userID, AnchorCustomerID: anchorCustomerID, Attempts: 1}
+	case s.job.Status == domain.ProvisioningFailed:
+		s.job.Attempts = 1
+	case s.job.Status == domain.ProvisioningPending:
+		s.job.Attempts++
+	default:
+		return nil, nil
+	}
+	s.job.Status = domain.ProvisioningProcessing
+	job := *s.job
+	return &job, nil
+}
+
+func (s *provisioningRepoStub) ClaimDueAccountProvisioningJobs(ctx context.Context, limit int, staleAfter time.Duration) ([]domain.AccountProvisioningJob, error) {
+	if s.job == nil || s.job.Status != domain.ProvisioningPending {
+		return nil, nil
+	}
+	s.job.Status = domain.ProvisioningProcessing
+	s.job.Attempts++
+	return []domain.AccountProvisioningJob{*s.job}, nil
+}
+
+func (s *provisioningRepoStub) SetProvisioningAnchorAccountID(ctx context.Context, userID, anchorAccountID string) error {
+	s.job.AnchorAccountID = &anchorAccountID
+	return nil
+}
+
+func (s *provisioningRepoStub) CompleteAccountProvisioning(ctx context.Context, userID string) error {
+	s.job.Status = domain.ProvisioningCompleted
+	return nil
+}
+
+func (s *provisioningRepoStub) RecordAccountProvisioningFailure(ctx context.Context, userID string, nextAttemptAt *time.Time, lastError string) error {
+	s.job.LastError = &lastError
+	s.job.Status = domain.ProvisioningFailed
+	if nextAttemptAt != nil {
+		s.job.Status = domain.ProvisioningPending
+		s.job.NextAttemptAt = *nextAttemptAt
+	}
+	return nil
+}
+
+// publisherStub records published events by routing key.
+type publisherStub struct {
+	mu     sync.Mutex
+	events map[string][]interface{}
+}
+
+func (p *publisherStub) Publish(ctx context.Context, exchange, routingKey string, payload interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.events == nil {
+		p.events = map[string][]interface{}{}
+	}
+	p.events[routingKey] = append(p.events[routingKey], payload)
+	return nil
+}
+
+// provisioningAnchorStub serves deposit account creation and NUBAN lookups with the
+// statuses set on it, counting creations.
+type provisioningAnchorStub struct {
+	createStatus int
+	nubanStatus  int
+	created      int
+}
+
+func (a *provisioningAnchorStub) server(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/accounts":
+			a.created++
+			if a.createStatus != 0 {
+				w.WriteHeader(a.createStatus)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"Error","detail":"account creation failed"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"anchor-acct-1","type":"DepositAccount"}}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/accounts/"):
+			if a.nubanStatus != 0 {
+				w.WriteHeader(a.nubanStatus)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"Error","detail":"lookup failed"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"anchor-acct-1","attributes":{"bank":{"name":"Anchor Bank"}}},"included":[{"type":"AccountNumber","attributes":{"accountNumber":"0123456789"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleCustomerVerifiedEvent_RetriesFailedProvisioning(t *testing.T) {
+	anchor := &provisioningAnchorStub{createStatus: http.StatusInternalServerError}
+	repo := newProvisioningRepoStub()
+	publisher := &publisherStub{}
+	handler := NewAccountEventHandler(repo, anchorclient.NewClient(anchor.server(t).URL, "test-key"), publisher)
+
+	before := time.Now()
+	if !handler.HandleCustomerVerifiedEvent([]byte(customerVerifiedBody)) {
+		t.Fatalf("expected the message to be acknowledged")
+	}
+	if repo.job.Status != domain.ProvisioningPending || repo.job.Attempts != 1 {
+		t.Fatalf("expected a pending retry after the first attempt, got %s with %d attempts", repo.job.Status, repo.job.Attempts)
+	}
+	if wait := repo.job.NextAttemptAt.Sub(before); wait < time.Minute || wait > time.Minute+5*time.Second {
+		t.Fatalf("expected the first retry a minute out, got %s", wait)
+	}
+	if repo.statuses["tier2"] != "approved" {
+		t.Fatalf("expected tier2 to stay approved while retrying, got %q", repo.statuses["tier2"])
+	}
+	failed := publisher.events[AccountProvisioningFailedRoutingKey]
+	if len(failed) != 1 || !failed[0].(domain.AccountProvisioningFailedEvent).WillRetry {
+		t.Fatalf("expected a retrying account.provisioning_failed event, got %+v", failed)
+	}
+
+	// The deposit account is created but its NUBAN lookup fails; the retry after
+	// that must reuse the account rather than create another.
+	anchor.createStatus = 0
+	anchor.nubanStatus = http.StatusServiceUnavailable
+	handler.retryDueProvisioningJobs(context.Background())
+	if repo.job.Status != domain.ProvisioningPending || repo.job.AnchorAccountID == nil {
+		t.Fatalf("expected a pending retry remembering the Anchor account, got %+v", repo.job)
+	}
+
+	anchor.nubanStatus = 0
+	handler.retryDueProvisioningJobs(context.Background())
+	if repo.job.Status != domain.ProvisioningCompleted {
+		t.Fatalf("expected provisioning to complete, got %s", repo.job.Status)
+	}
+	if anchor.created != 2 {
+		t.Fatalf("expected the Anchor account to be created once after the outage, got %d creations", anchor.created)
+	}
+	if repo.account == nil || repo.account.VirtualNUBAN != "0123456789" || repo.account.AnchorAccountID != "anchor-acct-1" {
+		t.Fatalf("unexpected account: %+v", repo.account)
+	}
+	if repo.statuses["tier2"] != "completed" {
+		t.Fatalf("expected tier2 completed, got %q", repo.statuses["tier2"])
+	}
+	provisioned := publisher.events[AccountProvisionedRoutingKey]
+	if len(provisioned) != 1 || provisioned[0].(domain.AccountProvisionedEvent).AccountID != "acct-1" {
+		t.Fatalf("expected one account.provisioned event, got %+v", provisioned)
+	}
+	stageChanges := publisher.events[OnboardingStageChangedRoutingKey]
+	if len(stageChanges) == 0 {
+		t.Fatal("expected onboarding.stage.changed events")
+	}
+	if last := stageChanges[len(stageChanges)-1].(domain.OnboardingStageChangedEvent); last.Stage != "tier2" || last.Status != "completed" || last.UserID != "user-1" {
+		t.Fatalf("expected the last stage change to complete tier2, got %+v", last)
+	}
+}
+
+func TestProvisioning_FailsAfterMaxAttempts(t *testing.T) {
+	anchor := &provisioningAnchorStub{createStatus: http.StatusBadGateway}
+	repo := newProvisioningRepoStub()
+	publisher := &publisherStub{}
+	handler := NewAccountEventHandler(repo, anchorclient.NewClient(anchor.server(t).URL, "test-key"), publisher)
+
+	handler.HandleCustomerVerifiedEvent([]byte(customerVerifiedBody))
+	for i := 1; i < maxProvisioningAttempts; i++ {
+		handler.retryDueProvisioningJobs(context.Background())
+	}
+
+	if repo.job.Status != domain.ProvisioningFailed || repo.job.Attempts != maxProvisioningAttempts {
+		t.Fatalf("expected a failed job after %d attempts, got %s with %d", maxProvisioningAttempts, repo.job.Status, repo.job.Attempts)
+	}
+	if repo.statuses["tier2"] != "failed" {
+		t.Fatalf("expected tier2 failed, got %q", repo.statuses["tier2"])
+	}
+	failed := publisher.events[AccountProvisioningFailedRoutingKey]
+	if len(failed) != maxProvisioningAttempts || failed[len(failed)-1].(domain.AccountProvisioningFailedEvent).WillRetry {
+		t.Fatalf("expected the last of %d failure events not to retry, got %+v", maxProvisioningAttempts, failed)
+	}
+
+	handler.retryDueProvisioningJobs(context.Background())
+	if anchor.created != maxProvisioningAttempts {
+		t.Fatalf("expected no attempt after the job failed, got %d Anchor calls", anchor.created)
+	}
+}
+
+func TestProvisioning_RejectionFailsImmediately(t *testing.T) {
+	anchor := &provisioningAnchorStub{createStatus: http.StatusUnprocessableEntity}
+	repo := newProvisioningRepoStub()
+	handler := NewAccountEventHandler(repo, anchorclient.NewClient(anchor.server(t).URL, "test-key"), nil)
+
+	handler.HandleCustomerVerifiedEvent([]byte(customerVerifiedBody))
+
+	if repo.job.Status != domain.ProvisioningFailed || repo.statuses["tier2"] != "failed" {
+		t.Fatalf("expected an explicit rejection to fail the job, got %s (tier2 %q)", repo.job.Status, repo.statuses["tier2"])
+	}
+}
+
+func TestForceAccountProvisioning(t *testing.T) {
+	anchor := &provisioningAnchorStub{createStatus: http.StatusUnprocessableEntity}
+	repo := newProvisioningRepoStub()
+	handler := NewAccountEventHandler(repo, anchorclient.NewClient(anchor.server(t).URL, "test-key"), nil)
+
+	handler.HandleCustomerVerifiedEvent([]byte(customerVerifiedBody))
+	if repo.job.Status != domain.ProvisioningFailed {
+		t.Fatalf("expected the first attempt to fail, got %s", repo.job.Status)
+	}
+
+	anchor.createStatus = 0
+	job, err := handler.ForceAccountProvisioning(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("ForceAccountProvisioning: %v", err)
+	}
+	if job.Status != domain.ProvisioningCompleted || job.Attempts != 1 || repo.account == nil {
+		t.Fatalf("expected a forced retry to provision the account, got %+v", job)
+	}
+
+	if _, err := handler.ForceAccountProvisioning(context.Background(), "user-2"); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound for an unknown user, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+)
+
+const (
+	maxRestrictionReasonLen = 500
+	maxRestrictionActorLen  = 255
+)
+
+var (
+	ErrInvalidRestrictionReason = errors.New("reason is required and cannot exceed 500 characters")
+	ErrInvalidRestrictionActor  = errors.New("actor is required and cannot exceed 255 characters")
+)
+
+// UserRestrictionInput defines the input for freezing or unfreezing a user.
+type UserRestrictionInput struct {
+	UserID string // Internal user UUID
+	Reason string
+	Actor  string
+}
+
+// FreezeUser blocks a user from sending and receiving P2P transfers.
+func (s *AccountService) FreezeUser(ctx context.Context, input UserRestrictionInput) (*domain.UserRestrictionState, error) {
+	return s.applyUserRestriction(ctx, domain.UserRestrictionFreeze, input)
+}
+
+// UnfreezeUser lifts a freeze previously applied with FreezeUser.
+func (s *AccountService) UnfreezeUser(ctx context.Context, input UserRestrictionInput) (*domain.UserRestrictionState, error) {
+	return s.applyUserRestriction(ctx, domain.UserRestrictionUnfreeze, input)
+}
+
+func (s *AccountService) applyUserRestriction(ctx context.Context, action string, input UserRestrictionInput) (*domain.UserRestrictionState, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxRestrictionReasonLen {
+		return nil, ErrInvalidRestrictionReason
+	}
+	actor := strings.TrimSpace(input.Actor)
+	if actor == "" || utf8.RuneCountInString(actor) > maxRestrictionActorLen {
+		return nil, ErrInvalidRestrictionActor
+	}
+
+	state, err := s.accountRepo.ApplyUserRestriction(ctx, strings.TrimSpace(input.UserID), action, reason, actor)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return state, nil
+}
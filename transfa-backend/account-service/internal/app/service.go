@@ -22,7 +22,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/transfa/account-service/internal/domain"
 	"github.com/transfa/account-service/internal/store"
-	"github.com/transfa/account-service/pkg/anchorclient"
+	"github.com/transfa/pkg/anchorclient"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -400,6 +400,7 @@ type CreateMoneyDropAccountResponse struct {
 
 // CreateMoneyDropAccount creates a new Anchor deposit account for money drops.
 // This method creates a separate Anchor account that will be used exclusively for money drop operations.
+// It is idempotent: a user who already has one gets it back without another call to Anchor.
 func (s *AccountService) CreateMoneyDropAccount(ctx context.Context, userID string) (*CreateMoneyDropAccountResponse, error) {
 	// 1. Check if user already has a money drop account
 	existingAccount, err := s.accountRepo.FindMoneyDropAccountByUserID(ctx, userID)
@@ -416,13 +417,55 @@ func (s *AccountService) CreateMoneyDropAccount(ctx context.Context, userID stri
 		}, nil
 	}
 
-	// 2. Get user's Anchor customer ID
+	// 2. Provision the Anchor deposit account and its Virtual NUBAN
+	deposit, err := s.provisionDepositAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Record the account. If a concurrent request recorded one first, keep theirs
+	// so callers always see a single money drop account per user.
+	saved, err := s.accountRepo.UpsertMoneyDropAccount(ctx, &domain.Account{
+		UserID:          userID,
+		AnchorAccountID: deposit.AnchorAccountID,
+		VirtualNUBAN:    deposit.VirtualNUBAN,
+		BankName:        deposit.BankName,
+		Type:            domain.MoneyDropAccount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save account to database: %w", err)
+	}
+	if saved.AnchorAccountID != deposit.AnchorAccountID {
+		log.Printf("Money drop account for user %s was provisioned concurrently; Anchor account %s is unused", userID, deposit.AnchorAccountID)
+	} else {
+		log.Printf("Successfully created money drop account for user %s", userID)
+	}
+
+	return &CreateMoneyDropAccountResponse{
+		AccountID:       saved.ID,
+		AnchorAccountID: saved.AnchorAccountID,
+		VirtualNUBAN:    saved.VirtualNUBAN,
+		BankName:        saved.BankName,
+	}, nil
+}
+
+// provisionedDepositAccount holds the Anchor details of a newly created deposit account.
+type provisionedDepositAccount struct {
+	AnchorAccountID string
+	VirtualNUBAN    string
+	BankName        string
+}
+
+// provisionDepositAccount creates an Anchor SAVINGS deposit account for the user and
+// resolves its Virtual NUBAN and bank name.
+func (s *AccountService) provisionDepositAccount(ctx context.Context, userID string) (*provisionedDepositAccount, error) {
+	// 1. Get user's Anchor customer ID
 	anchorCustomerID, err := s.accountRepo.FindAnchorCustomerIDByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get anchor customer ID: %w", err)
 	}
 
-	// 3. Create Anchor deposit account for money drops
+	// 2. Create Anchor deposit account
 	accountReq := domain.CreateDepositAccountRequest{
 		Data: domain.RequestData{
 			Type: "DepositAccount",
@@ -447,16 +490,16 @@ func (s *AccountService) CreateMoneyDropAccount(ctx context.Context, userID stri
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Anchor deposit account: %w", err)
 	}
-	log.Printf("Successfully created Anchor DepositAccount %s for money drops", anchorAccount.Data.ID)
+	log.Printf("Successfully created Anchor DepositAccount %s for user %s", anchorAccount.Data.ID, userID)
 
-	// 4. Get Virtual NUBAN for the account
+	// 3. Get Virtual NUBAN for the account
 	nubanInfo, err := s.anchorClient.GetVirtualNUBANForAccount(ctx, anchorAccount.Data.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch VirtualNUBAN: %w", err)
 	}
 	log.Printf("Successfully fetched VirtualNUBAN: %s, Bank: %s", nubanInfo.AccountNumber, nubanInfo.BankName)
 
-	// 5. Get bank name
+	// 4. Get bank name
 	bankName := nubanInfo.BankName
 	if bankName == "" {
 		if attributesMap, ok := anchorAccount.Data.Attributes.(map[string]interface{}); ok {
@@ -475,33 +518,7 @@ func (s *AccountService) CreateMoneyDropAccount(ctx context.Context, userID stri
 		}
 	}
 
-	// 6. Create or update account record in database
-	var accountID string
-	if existingAccount != nil {
-		// Update existing account with Anchor details
-		accountID = existingAccount.ID
-		if err := s.accountRepo.UpdateAccount(ctx, accountID, anchorAccount.Data.ID, nubanInfo.AccountNumber, bankName); err != nil {
-			return nil, fmt.Errorf("failed to update account with Anchor details: %w", err)
-		}
-	} else {
-		// Create new account record
-		newAccount := &domain.Account{
-			UserID:          userID,
-			AnchorAccountID: anchorAccount.Data.ID,
-			VirtualNUBAN:    nubanInfo.AccountNumber,
-			BankName:        bankName,
-			Type:            domain.MoneyDropAccount,
-		}
-		accountID, err = s.accountRepo.CreateAccount(ctx, newAccount)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save account to database: %w", err)
-		}
-	}
-
-	log.Printf("Successfully created money drop account for user %s", userID)
-
-	return &CreateMoneyDropAccountResponse{
-		AccountID:       accountID,
+	return &provisionedDepositAccount{
 		AnchorAccountID: anchorAccount.Data.ID,
 		VirtualNUBAN:    nubanInfo.AccountNumber,
 		BankName:        bankName,
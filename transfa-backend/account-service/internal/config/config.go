@@ -4,25 +4,49 @@
  */
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
+)
 
 // Config holds all configuration for the application.
 type Config struct {
-	ServerPort       string `mapstructure:"SERVER_PORT"`
-	DatabaseURL      string `mapstructure:"DATABASE_URL"`
-	ClerkJWKSURL     string `mapstructure:"CLERK_JWKS_URL"`
-	ClerkAudience    string `mapstructure:"CLERK_AUDIENCE"`
-	ClerkIssuer      string `mapstructure:"CLERK_ISSUER"`
-	AnchorAPIKey     string `mapstructure:"ANCHOR_API_KEY"`
-	AnchorAPIBaseURL string `mapstructure:"ANCHOR_API_BASE_URL"`
-	RabbitMQURL      string `mapstructure:"RABBITMQ_URL"`
-	InternalAPIKey   string `mapstructure:"INTERNAL_API_KEY"`
+	ServerPort         string `mapstructure:"SERVER_PORT"`
+	DatabaseURL        string `mapstructure:"DATABASE_URL"`
+	ClerkJWKSURL       string `mapstructure:"CLERK_JWKS_URL"`
+	ClerkAudience      string `mapstructure:"CLERK_AUDIENCE"`
+	ClerkIssuer        string `mapstructure:"CLERK_ISSUER"`
+	AnchorAPIKey       string `mapstructure:"ANCHOR_API_KEY"`
+	AnchorAPIBaseURL   string `mapstructure:"ANCHOR_API_BASE_URL"`
+	RabbitMQURL        string `mapstructure:"RABBITMQ_URL"`
+	InternalAPIKey     string `mapstructure:"INTERNAL_API_KEY"`
+	AppEnv             string `mapstructure:"APP_ENV"`
+	AllowedOrigins     string `mapstructure:"ALLOWED_ORIGINS"`
+	EventSigningSecret string `mapstructure:"EVENT_SIGNING_SECRET"`
+	// OnboardingStatusDualWrite keeps writing onboarding_status directly next to the
+	// onboarding.stage.changed events while auth-service takes the table over.
+	OnboardingStatusDualWrite bool `mapstructure:"ONBOARDING_STATUS_DUAL_WRITE"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        100,
+	MinConns:        20,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from environment variables.
 func LoadConfig() (config Config, err error) {
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("PORT", "8080")
+	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("ONBOARDING_STATUS_DUAL_WRITE", true)
 	viper.AutomaticEnv()
 
 	_ = viper.BindEnv("SERVER_PORT")
@@ -35,6 +59,10 @@ func LoadConfig() (config Config, err error) {
 	_ = viper.BindEnv("ANCHOR_API_BASE_URL")
 	_ = viper.BindEnv("RABBITMQ_URL")
 	_ = viper.BindEnv("INTERNAL_API_KEY")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("ALLOWED_ORIGINS")
+	_ = viper.BindEnv("EVENT_SIGNING_SECRET")
+	_ = viper.BindEnv("ONBOARDING_STATUS_DUAL_WRITE")
 
 	err = viper.Unmarshal(&config)
 	if err != nil {
@@ -49,5 +77,7 @@ func LoadConfig() (config Config, err error) {
 	if config.ServerPort == "" {
 		config.ServerPort = "8080"
 	}
+
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
@@ -18,6 +18,7 @@ type AccountType string
 const (
 	PrimaryAccount   AccountType = "primary"
 	MoneyDropAccount AccountType = "money_drop"
+	PotAccount       AccountType = "pot"
 )
 
 // Account represents a user's wallet in our system.
@@ -27,9 +28,36 @@ type Account struct {
 	AnchorAccountID string      `json:"anchor_account_id"`
 	VirtualNUBAN    string      `json:"virtual_nuban"`
 	BankName        string      `json:"bank_name"`
+	AccountName     string      `json:"account_name"`
 	Type            AccountType `json:"account_type"`
 	Balance         int64       `json:"balance"` // Stored in kobo
 	Status          string      `json:"status"`
 	CreatedAt       time.Time   `json:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at"`
 }
+
+// AccountDetails describes a user's primary account for display and funding instructions.
+type AccountDetails struct {
+	AccountID     string      `json:"account_id"`
+	AccountNumber string      `json:"account_number"`
+	BankName      string      `json:"bank_name"`
+	AccountName   string      `json:"account_name"`
+	AccountType   AccountType `json:"account_type"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// Pot is a named savings account owned by a user. Each pot is an `accounts` row
+// with account_type 'pot', backed by its own Anchor deposit account.
+type Pot struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Name            string    `json:"name"`
+	TargetAmount    *int64    `json:"target_amount,omitempty"` // Stored in kobo
+	AnchorAccountID string    `json:"anchor_account_id"`
+	VirtualNUBAN    string    `json:"virtual_nuban"`
+	BankName        string    `json:"bank_name"`
+	Balance         int64     `json:"balance"` // Stored in kobo
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
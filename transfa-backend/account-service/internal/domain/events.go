@@ -9,6 +9,8 @@
  */
 package domain
 
+import "time"
+
 // CustomerVerifiedEvent is the payload received from RabbitMQ when a customer's
 // KYC has been successfully approved and they are ready for an account to be provisioned.
 type CustomerVerifiedEvent struct {
@@ -20,3 +22,48 @@ type CustomerTierStatusEvent struct {
 	Status           string  `json:"status"`
 	Reason           *string `json:"reason,omitempty"`
 }
+
+// AccountLifecycleEvent is the payload received when Anchor reports a change to a
+// deposit account (for example account_opened). ResourceID is the Anchor account ID.
+type AccountLifecycleEvent struct {
+	AnchorCustomerID string `json:"anchor_customer_id"`
+	EventType        string `json:"event_type"`
+	ResourceID       string `json:"resource_id"`
+}
+
+// UserClosedEvent is the payload received when a user closes their account.
+type UserClosedEvent struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id"`
+	ClosedAt         time.Time `json:"closed_at"`
+}
+
+// AccountProvisionedEvent is published once a verified user's primary account exists.
+type AccountProvisionedEvent struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id"`
+	AccountID        string    `json:"account_id"`
+	AnchorAccountID  string    `json:"anchor_account_id"`
+	ProvisionedAt    time.Time `json:"provisioned_at"`
+}
+
+// AccountProvisioningFailedEvent is published after a failed provisioning attempt.
+// WillRetry reports whether another attempt is scheduled.
+type AccountProvisioningFailedEvent struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id"`
+	Reason           string    `json:"reason"`
+	Attempts         int       `json:"attempts"`
+	WillRetry        bool      `json:"will_retry"`
+	FailedAt         time.Time `json:"failed_at"`
+}
+
+// OnboardingStageChangedEvent is published whenever account-service moves a user's
+// onboarding stage. auth-service consumes it to keep onboarding_status.
+type OnboardingStageChangedEvent struct {
+	UserID     string    `json:"user_id"`
+	Stage      string    `json:"stage"`
+	Status     string    `json:"status"`
+	Reason     *string   `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
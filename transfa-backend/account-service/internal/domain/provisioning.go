@@ -0,0 +1,30 @@
+/**
+ * @description
+ * This file defines the account provisioning job: the state of creating a verified
+ * user's primary deposit account, kept so failed attempts can be retried.
+ */
+package domain
+
+import "time"
+
+// Account provisioning job statuses.
+const (
+	ProvisioningPending    = "pending"
+	ProvisioningProcessing = "processing"
+	ProvisioningCompleted  = "completed"
+	ProvisioningFailed     = "failed"
+)
+
+// AccountProvisioningJob is a row of account_provisioning_jobs. Attempts counts the
+// attempts started so far, including the one in progress.
+type AccountProvisioningJob struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id"`
+	AnchorAccountID  *string   `json:"anchor_account_id,omitempty"`
+	Status           string    `json:"status"`
+	Attempts         int       `json:"attempts"`
+	NextAttemptAt    time.Time `json:"next_attempt_at"`
+	LastError        *string   `json:"last_error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// User restriction actions recorded in user_restriction_events.
+const (
+	UserRestrictionFreeze   = "freeze"
+	UserRestrictionUnfreeze = "unfreeze"
+)
+
+// UserRestrictionEvent is an audit record of a freeze or unfreeze applied to a user.
+type UserRestrictionEvent struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserRestrictionState is a user's transfer permissions after a freeze or unfreeze.
+type UserRestrictionState struct {
+	UserID         string               `json:"user_id"`
+	AllowSending   bool                 `json:"allow_sending"`
+	AllowReceiving bool                 `json:"allow_receiving"`
+	Frozen         bool                 `json:"is_frozen"`
+	Event          UserRestrictionEvent `json:"event"`
+}
@@ -24,7 +24,14 @@ import (
 	"github.com/transfa/account-service/internal/domain"
 )
 
-var ErrTransactionPINNotSet = errors.New("transaction pin not set")
+var (
+	ErrTransactionPINNotSet = errors.New("transaction pin not set")
+	ErrPotNotFound          = errors.New("pot not found")
+	ErrPotNotEmpty          = errors.New("pot balance must be zero before it can be deleted")
+	ErrUserAlreadyFrozen    = errors.New("user is already frozen")
+	ErrUserNotFrozen        = errors.New("user is not frozen")
+	ErrUserClosed           = errors.New("user has closed their account")
+)
 
 // PostgresAccountRepository is the PostgreSQL implementation of the AccountRepository.
 type PostgresAccountRepository struct {
@@ -186,6 +193,59 @@ func (r *PostgresAccountRepository) FindAccountByUserID(ctx context.Context, use
 	return &account, nil
 }
 
+// FindPrimaryAccountByUserID retrieves the user's primary account, including the
+// Anchor-registered account name. Missing NUBAN/bank/name columns scan as empty strings.
+func (r *PostgresAccountRepository) FindPrimaryAccountByUserID(ctx context.Context, userID string) (*domain.Account, error) {
+	query := `
+		SELECT id, user_id, COALESCE(anchor_account_id, ''), COALESCE(virtual_nuban, ''), COALESCE(bank_name, ''),
+		       COALESCE(account_name, ''), account_type, balance, status, created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1 AND account_type = 'primary'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var account domain.Account
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&account.ID,
+		&account.UserID,
+		&account.AnchorAccountID,
+		&account.VirtualNUBAN,
+		&account.BankName,
+		&account.AccountName,
+		&account.Type,
+		&account.Balance,
+		&account.Status,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// UpdateAccountDetailsByAnchorAccountID fills in the NUBAN, bank name and account name for
+// the account backed by the given Anchor deposit account. Values already stored are kept,
+// and empty inputs never overwrite a column. It reports whether a matching account exists.
+func (r *PostgresAccountRepository) UpdateAccountDetailsByAnchorAccountID(ctx context.Context, anchorAccountID string, details domain.AnchorAccountDetails) (bool, error) {
+	query := `
+		UPDATE accounts
+		SET virtual_nuban = COALESCE(NULLIF(virtual_nuban, ''), NULLIF($2, '')),
+		    bank_name = COALESCE(NULLIF(bank_name, ''), NULLIF($3, '')),
+		    account_name = COALESCE(NULLIF(account_name, ''), NULLIF($4, '')),
+		    updated_at = NOW()
+		WHERE anchor_account_id = $1
+	`
+	result, err := r.db.Exec(ctx, query, anchorAccountID, details.AccountNumber, details.BankName, details.AccountName)
+	if err != nil {
+		log.Printf("Error updating account details for anchor account %s: %v", anchorAccountID, err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
 // CreateAccount inserts a new account record into the database.
 func (r *PostgresAccountRepository) CreateAccount(ctx context.Context, account *domain.Account) (string, error) {
 	query := `
@@ -263,6 +323,52 @@ func (r *PostgresAccountRepository) FindAnchorCustomerIDByUserID(ctx context.Con
 	return anchorCustomerID, nil
 }
 
+// UpsertMoneyDropAccount records the Anchor details of a user's money drop account.
+// A placeholder row without an Anchor account is filled in; a row that already has
+// one is left alone, so the account returned may differ from the one passed in when
+// a concurrent request provisioned the account first.
+func (r *PostgresAccountRepository) UpsertMoneyDropAccount(ctx context.Context, account *domain.Account) (*domain.Account, error) {
+	query := `
+		INSERT INTO accounts (user_id, anchor_account_id, virtual_nuban, bank_name, account_type)
+		VALUES ($1, $2, $3, $4, 'money_drop')
+		ON CONFLICT (user_id, account_type) WHERE account_type = 'money_drop'
+		DO UPDATE SET
+			anchor_account_id = EXCLUDED.anchor_account_id,
+			virtual_nuban = EXCLUDED.virtual_nuban,
+			bank_name = EXCLUDED.bank_name,
+			updated_at = NOW()
+		WHERE COALESCE(accounts.anchor_account_id, '') = ''
+		RETURNING id
+	`
+	var accountID string
+	err := r.db.QueryRow(ctx, query,
+		account.UserID,
+		account.AnchorAccountID,
+		account.VirtualNUBAN,
+		account.BankName,
+	).Scan(&accountID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The row already carries an Anchor account; return that one.
+			existing, findErr := r.FindMoneyDropAccountByUserID(ctx, account.UserID)
+			if findErr != nil {
+				return nil, findErr
+			}
+			if existing == nil {
+				return nil, fmt.Errorf("money drop account for user %s disappeared during upsert", account.UserID)
+			}
+			return existing, nil
+		}
+		log.Printf("Error upserting money drop account for user %s: %v", account.UserID, err)
+		return nil, err
+	}
+
+	saved := *account
+	saved.ID = accountID
+	saved.Type = domain.MoneyDropAccount
+	return &saved, nil
+}
+
 // FindMoneyDropAccountByUserID retrieves the money drop account for a user.
 func (r *PostgresAccountRepository) FindMoneyDropAccountByUserID(ctx context.Context, userID string) (*domain.Account, error) {
 	query := `
@@ -295,3 +401,248 @@ func (r *PostgresAccountRepository) FindMoneyDropAccountByUserID(ctx context.Con
 
 	return &account, nil
 }
+
+// CreatePot inserts a new pot account record into the database.
+func (r *PostgresAccountRepository) CreatePot(ctx context.Context, pot *domain.Pot) (*domain.Pot, error) {
+	query := `
+		INSERT INTO accounts (user_id, anchor_account_id, virtual_nuban, bank_name, account_type, name, target_amount)
+		VALUES ($1, $2, $3, $4, 'pot', $5, $6)
+		RETURNING id, balance, status, created_at, updated_at
+	`
+	created := *pot
+	err := r.db.QueryRow(ctx, query,
+		pot.UserID,
+		pot.AnchorAccountID,
+		pot.VirtualNUBAN,
+		pot.BankName,
+		pot.Name,
+		pot.TargetAmount,
+	).Scan(&created.ID, &created.Balance, &created.Status, &created.CreatedAt, &created.UpdatedAt)
+	if err != nil {
+		log.Printf("Error inserting pot into database: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Successfully created pot with ID: %s", created.ID)
+	return &created, nil
+}
+
+// ListPotsByUserID retrieves all open pots for a user, oldest first.
+func (r *PostgresAccountRepository) ListPotsByUserID(ctx context.Context, userID string) ([]domain.Pot, error) {
+	query := `
+		SELECT id, user_id, COALESCE(name, ''), target_amount, anchor_account_id, virtual_nuban, bank_name, balance, status, created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1 AND account_type = 'pot' AND status <> 'closed'
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Error listing pots for user %s: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	pots := make([]domain.Pot, 0)
+	for rows.Next() {
+		var pot domain.Pot
+		if err := rows.Scan(
+			&pot.ID,
+			&pot.UserID,
+			&pot.Name,
+			&pot.TargetAmount,
+			&pot.AnchorAccountID,
+			&pot.VirtualNUBAN,
+			&pot.BankName,
+			&pot.Balance,
+			&pot.Status,
+			&pot.CreatedAt,
+			&pot.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		pots = append(pots, pot)
+	}
+	return pots, rows.Err()
+}
+
+// ClosePot marks a user's pot as closed. The pot must have a zero balance.
+// The row is kept so transactions that reference the pot remain linked.
+func (r *PostgresAccountRepository) ClosePot(ctx context.Context, potID string, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var balance int64
+	err = tx.QueryRow(ctx, `
+		SELECT balance
+		FROM accounts
+		WHERE id = $1 AND user_id = $2 AND account_type = 'pot' AND status <> 'closed'
+		FOR UPDATE
+	`, potID, userID).Scan(&balance)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "22P02") { // invalid_text_representation
+			return ErrPotNotFound
+		}
+		return err
+	}
+	if balance != 0 {
+		return ErrPotNotEmpty
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE accounts SET status = 'closed', updated_at = NOW() WHERE id = $1`, potID); err != nil {
+		log.Printf("Error closing pot %s: %v", potID, err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ApplyUserRestriction freezes or unfreezes a user and records the change in
+// user_restriction_events within the same transaction.
+//
+// Freezing clears both allow_sending and allow_receiving. Unfreezing restores
+// receiving and resets sending to the onboarding default for the user type, so
+// merchants stay unable to send. The users row is locked for the duration, which
+// serialises the change against transfers that re-check the flags at debit time.
+func (r *PostgresAccountRepository) ApplyUserRestriction(ctx context.Context, userID, action, reason, actor string) (*domain.UserRestrictionState, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var allowReceiving, closed bool
+	err = tx.QueryRow(ctx, `SELECT allow_receiving, closed_at IS NOT NULL FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&allowReceiving, &closed)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22P02" { // invalid_text_representation
+			return nil, pgx.ErrNoRows
+		}
+		return nil, err
+	}
+	if closed {
+		return nil, ErrUserClosed
+	}
+
+	var updateQuery string
+	switch action {
+	case domain.UserRestrictionFreeze:
+		if !allowReceiving {
+			return nil, ErrUserAlreadyFrozen
+		}
+		updateQuery = `
+			UPDATE users
+			SET allow_sending = FALSE, allow_receiving = FALSE, updated_at = NOW()
+			WHERE id = $1
+			RETURNING allow_sending, allow_receiving
+		`
+	case domain.UserRestrictionUnfreeze:
+		if allowReceiving {
+			return nil, ErrUserNotFrozen
+		}
+		updateQuery = `
+			UPDATE users
+			SET allow_sending = (user_type = 'personal'), allow_receiving = TRUE, updated_at = NOW()
+			WHERE id = $1
+			RETURNING allow_sending, allow_receiving
+		`
+	default:
+		return nil, fmt.Errorf("unknown restriction action %q", action)
+	}
+
+	state := &domain.UserRestrictionState{UserID: userID}
+	if err := tx.QueryRow(ctx, updateQuery, userID).Scan(&state.AllowSending, &state.AllowReceiving); err != nil {
+		log.Printf("Error applying %s restriction to user %s: %v", action, userID, err)
+		return nil, err
+	}
+	state.Frozen = !state.AllowReceiving
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_restriction_events (user_id, action, reason, actor)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, action, reason, actor, created_at
+	`, userID, action, reason, actor).Scan(
+		&state.Event.ID,
+		&state.Event.UserID,
+		&state.Event.Action,
+		&state.Event.Reason,
+		&state.Event.Actor,
+		&state.Event.CreatedAt,
+	)
+	if err != nil {
+		log.Printf("Error recording %s restriction event for user %s: %v", action, userID, err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// ListOpenAccountsByUserID returns every account of the user that has not been closed,
+// including pots and money drop accounts.
+func (r *PostgresAccountRepository) ListOpenAccountsByUserID(ctx context.Context, userID string) ([]domain.Account, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, COALESCE(anchor_account_id, ''), COALESCE(virtual_nuban, ''), COALESCE(bank_name, ''),
+		       COALESCE(account_name, ''), account_type, balance, status, created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1 AND status <> 'closed'
+		ORDER BY created_at ASC
+	`, userID)
+	if err != nil {
+		log.Printf("Error listing open accounts for user %s: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]domain.Account, 0)
+	for rows.Next() {
+		var account domain.Account
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.AnchorAccountID,
+			&account.VirtualNUBAN,
+			&account.BankName,
+			&account.AccountName,
+			&account.Type,
+			&account.Balance,
+			&account.Status,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// CloseAccount marks an account as closed. The row is kept so transactions that
+// reference it remain linked.
+func (r *PostgresAccountRepository) CloseAccount(ctx context.Context, accountID string) error {
+	_, err := r.db.Exec(ctx, `UPDATE accounts SET status = 'closed', updated_at = NOW() WHERE id = $1`, accountID)
+	if err != nil {
+		log.Printf("Error closing account %s: %v", accountID, err)
+		return err
+	}
+	return nil
+}
+
+// IsUserClosedByClerkUserID reports whether the user has closed their account.
+// Unknown users are reported as not closed.
+func (r *PostgresAccountRepository) IsUserClosedByClerkUserID(ctx context.Context, clerkUserID string) (bool, error) {
+	var closed bool
+	err := r.db.QueryRow(ctx, `SELECT closed_at IS NOT NULL FROM users WHERE clerk_user_id = $1`, clerkUserID).Scan(&closed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return closed, nil
+}
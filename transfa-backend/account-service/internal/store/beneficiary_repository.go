@@ -44,9 +44,11 @@ func (r *PostgresBeneficiaryRepository) CreateBeneficiary(ctx context.Context, b
 	// If this is the first beneficiary, set it as default
 	isDefault := existingCount == 0
 
+	// The account was resolved through Anchor just before it is saved, so it starts
+	// out verified.
 	query := `
-        INSERT INTO beneficiaries (user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO beneficiaries (user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at)
+        VALUES ($1, $2, $3, $4, $5, $6, 'verified', NOW())
         RETURNING id, created_at, updated_at
     `
 	err = r.db.QueryRow(ctx, query,
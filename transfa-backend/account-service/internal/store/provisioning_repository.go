@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/account-service/internal/domain"
+)
+
+const provisioningJobColumns = `user_id, anchor_customer_id, anchor_account_id, status, attempts, next_attempt_at, last_error, created_at, updated_at`
+
+// StartAccountProvisioning claims the user's provisioning job for an attempt, creating
+// it on first use. A pending job is started early and a failed one starts over with a
+// fresh attempt count. It returns nil when the job is completed or another attempt,
+// not older than staleAfter, holds it.
+func (r *PostgresAccountRepository) StartAccountProvisioning(ctx context.Context, userID, anchorCustomerID string, staleAfter time.Duration) (*domain.AccountProvisioningJob, error) {
+	rows, err := r.db.Query(ctx, `
+		INSERT INTO account_provisioning_jobs (user_id, anchor_customer_id, status, attempts)
+		VALUES ($1, $2, 'processing', 1)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			anchor_customer_id = EXCLUDED.anchor_customer_id,
+			status = 'processing',
+			attempts = CASE WHEN account_provisioning_jobs.status = 'failed' THEN 1 ELSE account_provisioning_jobs.attempts + 1 END,
+			updated_at = NOW()
+		WHERE account_provisioning_jobs.status IN ('pending', 'failed')
+		   OR (account_provisioning_jobs.status = 'processing' AND account_provisioning_jobs.updated_at < NOW() - ($3 * INTERVAL '1 second'))
+		RETURNING `+provisioningJobColumns,
+		userID, anchorCustomerID, int(staleAfter.Seconds()))
+	if err != nil {
+		log.Printf("Error starting account provisioning for user %s: %v", userID, err)
+		return nil, err
+	}
+	jobs, err := scanProvisioningJobs(rows)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// ClaimDueAccountProvisioningJobs starts the next attempt of up to limit due jobs. A job
+// left in processing for longer than staleAfter, by a worker that died mid-attempt, is
+// claimed again.
+func (r *PostgresAccountRepository) ClaimDueAccountProvisioningJobs(ctx context.Context, limit int, staleAfter time.Duration) ([]domain.AccountProvisioningJob, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE account_provisioning_jobs
+		SET status = 'processing', attempts = attempts + 1, updated_at = NOW()
+		WHERE user_id IN (
+			SELECT user_id
+			FROM account_provisioning_jobs
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			   OR (status = 'processing' AND updated_at < NOW() - ($2 * INTERVAL '1 second'))
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+provisioningJobColumns,
+		limit, int(staleAfter.Seconds()))
+	if err != nil {
+		log.Printf("Error claiming due account provisioning jobs: %v", err)
+		return nil, err
+	}
+	return scanProvisioningJobs(rows)
+}
+
+// SetProvisioningAnchorAccountID records the Anchor deposit account an attempt created,
+// so a retry does not create a second one.
+func (r *PostgresAccountRepository) SetProvisioningAnchorAccountID(ctx context.Context, userID, anchorAccountID string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE account_provisioning_jobs
+		SET anchor_account_id = $2, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, anchorAccountID)
+	if err != nil {
+		log.Printf("Error recording Anchor account %s for provisioning of user %s: %v", anchorAccountID, userID, err)
+	}
+	return err
+}
+
+// CompleteAccountProvisioning marks the user's provisioning job done.
+func (r *PostgresAccountRepository) CompleteAccountProvisioning(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE account_provisioning_jobs
+		SET status = 'completed', last_error = NULL, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		log.Printf("Error completing account provisioning for user %s: %v", userID, err)
+	}
+	return err
+}
+
+// RecordAccountProvisioningFailure stores a failed attempt. With a nextAttemptAt the job
+// is scheduled again; without one it has failed for good.
+func (r *PostgresAccountRepository) RecordAccountProvisioningFailure(ctx context.Context, userID string, nextAttemptAt *time.Time, lastError string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE account_provisioning_jobs
+		SET status = CASE WHEN $2::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($2::timestamptz, next_attempt_at),
+			last_error = $3,
+			updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, nextAttemptAt, lastError)
+	if err != nil {
+		log.Printf("Error recording failed account provisioning for user %s: %v", userID, err)
+	}
+	return err
+}
+
+func scanProvisioningJobs(rows pgx.Rows) ([]domain.AccountProvisioningJob, error) {
+	defer rows.Close()
+
+	var jobs []domain.AccountProvisioningJob
+	for rows.Next() {
+		var job domain.AccountProvisioningJob
+		if err := rows.Scan(
+			&job.UserID,
+			&job.AnchorCustomerID,
+			&job.AnchorAccountID,
+			&job.Status,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&job.LastError,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
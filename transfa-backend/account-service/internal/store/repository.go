@@ -27,9 +27,24 @@ type AccountRepository interface {
 	RecordFailedTransactionPINAttempt(ctx context.Context, userID string, maxAttempts int, lockoutDurationSeconds int) (*domain.UserSecurityCredential, error)
 	ResetTransactionPINFailureState(ctx context.Context, userID string) error
 	FindAccountByUserID(ctx context.Context, userID string) (*domain.Account, error)
+	FindPrimaryAccountByUserID(ctx context.Context, userID string) (*domain.Account, error)
+	UpdateAccountDetailsByAnchorAccountID(ctx context.Context, anchorAccountID string, details domain.AnchorAccountDetails) (bool, error)
 	UpdateTierStatus(ctx context.Context, userID, stage, status string, reason *string) error
 	FindAnchorCustomerIDByUserID(ctx context.Context, userID string) (string, error)
 	FindMoneyDropAccountByUserID(ctx context.Context, userID string) (*domain.Account, error)
+	UpsertMoneyDropAccount(ctx context.Context, account *domain.Account) (*domain.Account, error)
+	CreatePot(ctx context.Context, pot *domain.Pot) (*domain.Pot, error)
+	ListPotsByUserID(ctx context.Context, userID string) ([]domain.Pot, error)
+	ClosePot(ctx context.Context, potID string, userID string) error
+	ApplyUserRestriction(ctx context.Context, userID, action, reason, actor string) (*domain.UserRestrictionState, error)
+	ListOpenAccountsByUserID(ctx context.Context, userID string) ([]domain.Account, error)
+	CloseAccount(ctx context.Context, accountID string) error
+	IsUserClosedByClerkUserID(ctx context.Context, clerkUserID string) (bool, error)
+	StartAccountProvisioning(ctx context.Context, userID, anchorCustomerID string, staleAfter time.Duration) (*domain.AccountProvisioningJob, error)
+	ClaimDueAccountProvisioningJobs(ctx context.Context, limit int, staleAfter time.Duration) ([]domain.AccountProvisioningJob, error)
+	SetProvisioningAnchorAccountID(ctx context.Context, userID, anchorAccountID string) error
+	CompleteAccountProvisioning(ctx context.Context, userID string) error
+	RecordAccountProvisioningFailure(ctx context.Context, userID string, nextAttemptAt *time.Time, lastError string) error
 }
 
 // BeneficiaryRepository defines the contract for database operations related to beneficiaries.
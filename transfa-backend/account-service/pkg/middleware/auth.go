@@ -90,6 +90,31 @@ func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 	}
 }
 
+// ClosedAccountMiddleware rejects requests from users who have closed their account
+// with 410 Gone. It must run after AuthMiddleware.
+func ClosedAccountMiddleware(isClosed func(ctx context.Context, clerkUserID string) (bool, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserIDFromContext(r.Context())
+			if userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			closed, err := isClosed(r.Context(), userID)
+			if err != nil {
+				http.Error(w, "Failed to verify account status", http.StatusInternalServerError)
+				return
+			}
+			if closed {
+				http.Error(w, "Account closed", http.StatusGone)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // InternalAuthMiddleware validates internal API calls via shared secret.
 func InternalAuthMiddleware(requiredKey string) func(http.Handler) http.Handler {
 	normalizedRequiredKey := strings.TrimSpace(requiredKey)
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/auth-service/pkg/bootstrapclient"
+	"github.com/transfa/pkg/apierror"
+	"golang.org/x/sync/errgroup"
+)
+
+// bootstrapSection is one part of the bootstrap response. Exactly one of Data and
+// Error is set, so one failing source does not fail the whole launch.
+type bootstrapSection struct {
+	Data  any             `json:"data"`
+	Error *apierror.Error `json:"error,omitempty"`
+}
+
+type bootstrapResponse struct {
+	Profile             bootstrapSection `json:"profile"`
+	Onboarding          bootstrapSection `json:"onboarding"`
+	PrimaryAccount      bootstrapSection `json:"primary_account"`
+	ReceivingPreference bootstrapSection `json:"receiving_preference"`
+	Subscription        bootstrapSection `json:"subscription"`
+}
+
+// primaryAccountSummary is the primary account section; its data is null for a user
+// without an account yet.
+type primaryAccountSummary struct {
+	AccountNumber string  `json:"account_number"`
+	BankName      *string `json:"bank_name,omitempty"`
+}
+
+// bootstrapSources loads the sections of GET /me/bootstrap. main wires the first two
+// to the database and the last two to the bootstrap client.
+type bootstrapSources struct {
+	onboarding          func(ctx context.Context, user *domain.User) (onboardingState, error)
+	primaryAccount      func(ctx context.Context, userID string) (*primaryAccountSummary, error)
+	receivingPreference func(ctx context.Context, authorization string) (json.RawMessage, error)
+	subscription        func(ctx context.Context, authorization string) (json.RawMessage, error)
+}
+
+// bootstrapHandler returns everything the app needs on launch in one response. The
+// profile must resolve; the other sections are loaded concurrently and each one
+// reports its own error.
+func bootstrapHandler(userRepo store.UserRepository, sources bootstrapSources) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
+		if err != nil || existing == nil {
+			writeError(w, statusCode, err)
+			return
+		}
+
+		ctx := r.Context()
+		authorization := r.Header.Get("Authorization")
+		response := bootstrapResponse{Profile: bootstrapSection{Data: existing}}
+
+		var g errgroup.Group
+		g.Go(func() error {
+			state, err := sources.onboarding(ctx, existing)
+			response.Onboarding = newBootstrapSection("onboarding", state, err)
+			return nil
+		})
+		g.Go(func() error {
+			account, err := sources.primaryAccount(ctx, existing.ID)
+			response.PrimaryAccount = newBootstrapSection("primary_account", account, err)
+			return nil
+		})
+		g.Go(func() error {
+			preference, err := sources.receivingPreference(ctx, authorization)
+			response.ReceivingPreference = newBootstrapSection("receiving_preference", preference, err)
+			return nil
+		})
+		g.Go(func() error {
+			status, err := sources.subscription(ctx, authorization)
+			response.Subscription = newBootstrapSection("subscription", status, err)
+			return nil
+		})
+		_ = g.Wait()
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// newBootstrapSection wraps data, or the error that kept it from loading. Client
+// errors from the downstream services keep their code; anything else is logged and
+// reported as unavailable.
+func newBootstrapSection(name string, data any, err error) bootstrapSection {
+	if err == nil {
+		return bootstrapSection{Data: data}
+	}
+
+	var statusErr *bootstrapclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode < 500 {
+		code := statusErr.Code
+		if code == "" {
+			code = apierror.CodeForStatus(statusErr.StatusCode)
+		}
+		return bootstrapSection{Error: &apierror.Error{Code: code, Message: statusErr.Message}}
+	}
+
+	log.Printf("WARN: bootstrap section %s failed: %v", name, err)
+	return bootstrapSection{Error: &apierror.Error{
+		Code:    apierror.CodeUnavailable,
+		Message: "This section is temporarily unavailable",
+	}}
+}
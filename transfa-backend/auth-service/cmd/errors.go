@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/pkg/apierror"
+)
+
+// errorCodes gives the store's typed errors their stable error codes.
+var errorCodes = map[error]string{
+	store.ErrTransactionPINAlreadySet: apierror.CodeTransactionPINAlreadySet,
+	store.ErrTransactionPINNotSet:     apierror.CodeTransactionPINNotSet,
+	store.ErrTransactionPINLocked:     apierror.CodeTransactionPINLocked,
+	store.ErrTransactionPINMismatch:   apierror.CodeTransactionPINInvalid,
+	store.ErrTransactionPINUnchanged:  apierror.CodeTransactionPINUnchanged,
+	store.ErrUsernameTaken:            apierror.CodeUsernameTaken,
+	store.ErrUsernameAlreadySet:       apierror.CodeUsernameAlreadySet,
+	store.ErrUsernameNotSet:           apierror.CodeUsernameNotSet,
+	store.ErrUsernameUnchanged:        apierror.CodeUsernameUnchanged,
+	store.ErrUsernameChangeTooSoon:    apierror.CodeUsernameChangeCooldown,
+	store.ErrUserAlreadyClosed:        apierror.CodeAccountClosed,
+}
+
+// errorCode returns the stable code for err, or the generic code for status.
+func errorCode(err error, statusCode int) string {
+	var blocked *store.AccountClosureBlockedError
+	if errors.As(err, &blocked) {
+		return apierror.CodeAccountClosureBlocked
+	}
+	for target, code := range errorCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	return apierror.CodeForStatus(statusCode)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// writeError writes the error envelope for err. Client errors carry err's message;
+// server errors are logged and answered with a generic message.
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	message := "Internal server error"
+	switch statusCode {
+	case http.StatusUnauthorized:
+		message = "Unauthorized"
+	case http.StatusNotFound:
+		message = "User not found"
+	case http.StatusBadRequest:
+		message = "Bad request"
+	case http.StatusPreconditionFailed:
+		message = "Precondition failed"
+	case http.StatusConflict:
+		message = "Conflict"
+	case http.StatusGone:
+		message = "Account closed"
+	}
+
+	if statusCode >= 500 {
+		log.Printf("Request failed with %d: %v", statusCode, err)
+	} else if err != nil {
+		message = err.Error()
+	}
+
+	apierror.Write(w, statusCode, errorCode(err, statusCode), message)
+}
+
+// writeErrorWithCode writes the same envelope as writeError with a specific code.
+func writeErrorWithCode(w http.ResponseWriter, statusCode int, code string, err error) {
+	message := http.StatusText(statusCode)
+	if err != nil {
+		message = err.Error()
+	}
+	apierror.Write(w, statusCode, code, message)
+}
+
+// writeValidationError answers a request whose field failed validation with 422.
+func writeValidationError(w http.ResponseWriter, field string, err error) {
+	apierror.WriteValidation(w, err.Error(), apierror.FieldError{Field: field, Message: err.Error()})
+}
+
+// accountClosureBlockedResponse is the 409 written when open obligations block an
+// account closure; blocking_reasons lists them for display.
+type accountClosureBlockedResponse struct {
+	apierror.Envelope
+	BlockingReasons []string `json:"blocking_reasons"`
+}
@@ -17,9 +17,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/transfa/auth-service/internal/api"
@@ -27,7 +25,10 @@ import (
 	"github.com/transfa/auth-service/internal/config"
 	"github.com/transfa/auth-service/internal/domain"
 	"github.com/transfa/auth-service/internal/store"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/transfa/auth-service/pkg/bootstrapclient"
+	"github.com/transfa/pkg/apierror"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
+	"github.com/transfa/pkg/rabbitmq"
 )
 
 type onboardingState struct {
@@ -103,10 +104,10 @@ func main() {
 	if err != nil {
 		log.Fatalf("Unable to parse database URL: %v", err)
 	}
-	dbConfig.MaxConns = 10
-	dbConfig.MinConns = 2
-	dbConfig.MaxConnLifetime = 30 * time.Minute
-	dbConfig.MaxConnIdleTime = 5 * time.Minute
+	dbConfig.MaxConns = cfg.DatabasePool.MaxConns
+	dbConfig.MinConns = cfg.DatabasePool.MinConns
+	dbConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	dbConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 	dbConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
 
 	dbpool, err := pgxpool.NewWithConfig(context.Background(), dbConfig)
@@ -132,34 +133,61 @@ func main() {
 		dispatcher := authapp.NewOutboxDispatcher(userRepo, cfg.RabbitMQURL)
 		go dispatcher.Run(outboxCtx)
 		log.Println("Outbox dispatcher started")
+
+		// account-service reports primary account provisioning on customer_events.
+		var consumerOpts []rabbitmq.ConsumerOption
+		if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+			consumerOpts = append(consumerOpts, rabbitmq.WithSignedEvents(secrets, "account.provisioned", "account.provisioning_failed", authapp.OnboardingStageChangedRoutingKey))
+		} else {
+			log.Println("Warning: EVENT_SIGNING_SECRET not set; account provisioning and onboarding stage event signatures are not verified")
+		}
+		consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL, consumerOpts...)
+		if err != nil {
+			log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		}
+		defer consumer.Close()
+		provisioningHandler := authapp.NewAccountProvisioningEventHandler(userRepo)
+		go func() {
+			bindings := map[string]func([]byte) bool{
+				"account.provisioned":         provisioningHandler.HandleAccountProvisionedEvent,
+				"account.provisioning_failed": provisioningHandler.HandleAccountProvisioningFailedEvent,
+			}
+			if err := consumer.ConsumeWithBindings("customer_events", "auth_service_account_provisioning", bindings); err != nil {
+				log.Printf("Account provisioning consumer error: %v", err)
+			}
+		}()
+
+		// customer-service and account-service report onboarding stage changes here;
+		// auth-service owns onboarding_status.
+		stageHandler := authapp.NewOnboardingStageEventHandler(userRepo)
+		go func() {
+			if err := consumer.Consume("customer_events", "auth_service_onboarding_stage", authapp.OnboardingStageChangedRoutingKey, stageHandler.HandleOnboardingStageChangedEvent); err != nil {
+				log.Printf("Onboarding stage consumer error: %v", err)
+			}
+		}()
 	}
 
 	pinChangeReverificationMaxAgeSeconds := parseEnvPositiveInt("PIN_CHANGE_REVERIFICATION_MAX_AGE_SECONDS", 600, 3600)
 
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		log.Fatalf("Invalid ALLOWED_ORIGINS: %v", err)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(30 * time.Second))
+	r.Use(sharedmiddleware.RequestTimeout(30 * time.Second))
 	r.Use(securityHeadersMiddleware)
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins: parseAllowedOrigins(cfg.AllowedOrigins),
-		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders: []string{
-			"Accept",
-			"Authorization",
-			"Content-Type",
-			"X-Clerk-User-Id",
-			"X-User-Email",
-			"X-Request-ID",
-		},
-		ExposedHeaders:   []string{"X-Request-ID"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	}))
+	r.Use(sharedmiddleware.CORS(allowedOrigins))
 
-	onboardingHandler := api.NewOnboardingHandler(userRepo)
+	onboardingHandler := api.NewOnboardingHandler(userRepo, cfg.Tier2EncryptionKey)
+	bootstrapClient := bootstrapclient.NewClient(cfg.TransactionServiceURL, cfg.SubscriptionServiceURL)
+	if cfg.TransactionServiceURL == "" || cfg.SubscriptionServiceURL == "" {
+		log.Println("WARNING: TRANSACTION_SERVICE_URL or SUBSCRIPTION_SERVICE_URL is empty; /me/bootstrap will report those sections as unavailable.")
+	}
 	authMiddleware := api.ClerkAuthMiddleware(api.AuthMiddlewareConfig{
 		JWKSURL:          cfg.ClerkJWKSURL,
 		ExpectedAudience: cfg.ClerkAudience,
@@ -170,8 +198,20 @@ func main() {
 		writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
 	})
 
+	// Clerk delivers user lifecycle webhooks through Svix; they are authenticated by
+	// signature rather than by a session token.
+	r.Post("/webhooks/clerk", api.NewClerkWebhookHandler(userRepo, cfg.ClerkWebhookSecret).ServeHTTP)
+
+	r.Group(func(r chi.Router) {
+		r.Use(internalAPIKeyMiddleware(cfg.InternalAPIKey))
+		r.Post("/internal/verify-transaction-pin", internalVerifyTransactionPINHandler(userRepo))
+		r.Get("/internal/users/{id}/contact", internalUserContactHandler(userRepo))
+		r.Get("/admin/onboarding/stuck", stuckOnboardingHandler(userRepo))
+	})
+
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware)
+		r.Use(closedAccountGuard(userRepo))
 		r.Use(middleware.ThrottleBacklog(200, 200, 5*time.Second))
 
 		r.Get("/onboarding/account-types", func(w http.ResponseWriter, r *http.Request) {
@@ -190,7 +230,10 @@ func main() {
 
 		r.Post("/onboarding", onboardingHandler.ServeHTTP)
 		r.Post("/onboarding/tier1/update", onboardingHandler.HandleTier1Update)
+		r.Post("/onboarding/tier1/retry", onboardingHandler.HandleTier1Retry)
 		r.Post("/onboarding/tier2", onboardingHandler.HandleTier2)
+		r.Get("/onboarding/tier2/status", onboardingHandler.HandleTier2Status)
+		r.Post("/onboarding/tier2/resubmit", onboardingHandler.HandleTier2Resubmit)
 		r.Post("/onboarding/tier3", func(w http.ResponseWriter, r *http.Request) {
 			existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
 			if err != nil || existing == nil {
@@ -242,31 +285,37 @@ func main() {
 			expiryDate := strings.TrimSpace(body.ExpiryDate)
 
 			if idType == "" || idNumber == "" || expiryDate == "" {
-				writeError(w, http.StatusBadRequest, errors.New("id_type, id_number and expiry_date are required"))
+				var details []apierror.FieldError
+				for _, field := range []struct{ name, value string }{{"id_type", idType}, {"id_number", idNumber}, {"expiry_date", expiryDate}} {
+					if field.value == "" {
+						details = append(details, apierror.FieldError{Field: field.name, Message: field.name + " is required"})
+					}
+				}
+				apierror.WriteValidation(w, "id_type, id_number and expiry_date are required", details...)
 				return
 			}
 			if !isSupportedTier3IDType(idType) {
-				writeError(w, http.StatusBadRequest, errors.New("unsupported id_type"))
+				writeValidationError(w, "id_type", errors.New("unsupported id_type"))
 				return
 			}
 			if len(idNumber) < 4 || len(idNumber) > 64 {
-				writeError(w, http.StatusBadRequest, errors.New("id_number must be between 4 and 64 characters"))
+				writeValidationError(w, "id_number", errors.New("id_number must be between 4 and 64 characters"))
 				return
 			}
 
 			normalizedExpiryDate, err := normalizeISODate(expiryDate)
 			if err != nil {
-				writeError(w, http.StatusBadRequest, err)
+				writeValidationError(w, "expiry_date", err)
 				return
 			}
 			expiry, parseErr := time.Parse("2006-01-02", normalizedExpiryDate)
 			if parseErr != nil {
-				writeError(w, http.StatusBadRequest, errors.New("expiry_date must be in YYYY-MM-DD format"))
+				writeValidationError(w, "expiry_date", errors.New("expiry_date must be in YYYY-MM-DD format"))
 				return
 			}
 			today := time.Now().UTC().Truncate(24 * time.Hour)
 			if expiry.Before(today) {
-				writeError(w, http.StatusBadRequest, errors.New("expiry_date cannot be in the past"))
+				writeValidationError(w, "expiry_date", errors.New("expiry_date cannot be in the past"))
 				return
 			}
 
@@ -317,20 +366,12 @@ func main() {
 				return
 			}
 
-			status, reason, hasAccount, hasTransactionPIN, err := deriveOnboardingStatus(r.Context(), dbpool, existing.ID)
+			state, err := loadOnboardingState(r.Context(), dbpool, userRepo, existing)
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
-
-			progress, err := userRepo.GetOnboardingProgressByClerkUserID(r.Context(), existing.ClerkUserID)
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			usernameMissing := existing.Username == nil || strings.TrimSpace(*existing.Username) == ""
-			writeJSON(w, http.StatusOK, buildOnboardingState(status, reason, progress, hasAccount, usernameMissing, hasTransactionPIN))
+			writeJSON(w, http.StatusOK, state)
 		})
 
 		r.Get("/me/profile", func(w http.ResponseWriter, r *http.Request) {
@@ -342,6 +383,21 @@ func main() {
 			writeJSON(w, http.StatusOK, existing)
 		})
 
+		r.Get("/me/bootstrap", bootstrapHandler(userRepo, bootstrapSources{
+			onboarding: func(ctx context.Context, user *domain.User) (onboardingState, error) {
+				return loadOnboardingState(ctx, dbpool, userRepo, user)
+			},
+			primaryAccount: func(ctx context.Context, userID string) (*primaryAccountSummary, error) {
+				accountNumber, bankName, err := getPrimaryAccount(ctx, dbpool, userID)
+				if err != nil || accountNumber == nil {
+					return nil, err
+				}
+				return &primaryAccountSummary{AccountNumber: *accountNumber, BankName: bankName}, nil
+			},
+			receivingPreference: bootstrapClient.ReceivingPreference,
+			subscription:        bootstrapClient.SubscriptionStatus,
+		}))
+
 		r.Get("/me/security-status", func(w http.ResponseWriter, r *http.Request) {
 			existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
 			if err != nil || existing == nil {
@@ -447,7 +503,7 @@ func main() {
 				return
 			}
 			if len(query) > 64 {
-				writeError(w, http.StatusBadRequest, errors.New("query must be 64 characters or less"))
+				writeValidationError(w, "q", errors.New("query must be 64 characters or less"))
 				return
 			}
 
@@ -495,7 +551,7 @@ func main() {
 
 			username, err := normalizeAndValidateUsername(body.Username)
 			if err != nil {
-				writeError(w, http.StatusBadRequest, err)
+				writeValidationError(w, "username", err)
 				return
 			}
 
@@ -509,16 +565,10 @@ func main() {
 				return
 			}
 
-			_, err = dbpool.Exec(
-				r.Context(),
-				`UPDATE users SET username = $1, updated_at = NOW() WHERE id = $2`,
-				username,
-				existing.ID,
-			)
+			err = userRepo.ClaimUsername(r.Context(), existing.ID, username)
 			if err != nil {
-				var pgErr *pgconn.PgError
-				if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-					writeError(w, http.StatusConflict, errors.New("username is not available"))
+				if errors.Is(err, store.ErrUsernameTaken) || errors.Is(err, store.ErrUsernameAlreadySet) {
+					writeError(w, http.StatusConflict, err)
 					return
 				}
 				writeError(w, http.StatusInternalServerError, err)
@@ -531,213 +581,91 @@ func main() {
 			})
 		})
 
-		r.Post("/me/transaction-pin", func(w http.ResponseWriter, r *http.Request) {
+		r.Patch("/me/username", usernameChangeHandler(userRepo))
+
+		r.Post("/me/transaction-pin", transactionPINSetupHandler(userRepo, func(ctx context.Context, userID string) (bool, error) {
+			return userHasAccount(ctx, dbpool, userID)
+		}))
+		r.Put("/me/transaction-pin", transactionPINChangeHandler(userRepo, pinChangeReverificationMaxAgeSeconds))
+		// Older app builds change the PIN through this route.
+		r.Post("/me/pin-change/complete", transactionPINChangeHandler(userRepo, pinChangeReverificationMaxAgeSeconds))
+
+		// Deprecated: account-service GET /accounts/me returns the full account details,
+		// backfilled from Anchor. This endpoint remains for older app builds.
+		r.Get("/me/primary-account", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", `</accounts/me>; rel="successor-version"`)
+
 			existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
 			if err != nil || existing == nil {
 				writeError(w, statusCode, err)
 				return
 			}
 
-			var body struct {
-				Pin string `json:"pin"`
-			}
-			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-				writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
-				return
-			}
-
-			pin := strings.TrimSpace(body.Pin)
-			if err := validateTransactionPIN(pin); err != nil {
-				writeError(w, http.StatusBadRequest, err)
-				return
-			}
-
-			hasAccount, err := userHasAccount(r.Context(), dbpool, existing.ID)
+			accountNumber, bankName, err := getPrimaryAccount(r.Context(), dbpool, existing.ID)
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
-			if !hasAccount {
-				writeError(w, http.StatusPreconditionFailed, errors.New("account provisioning is still in progress"))
-				return
-			}
-
-			if existing.Username == nil || strings.TrimSpace(*existing.Username) == "" {
-				writeError(w, http.StatusPreconditionFailed, errors.New("username must be set before transaction pin"))
-				return
-			}
 
-			hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
+			if accountNumber == nil {
+				writeJSON(w, http.StatusOK, map[string]any{})
 				return
 			}
 
-			_, err = dbpool.Exec(
-				r.Context(),
-				`INSERT INTO user_security_credentials (user_id, transaction_pin_hash, pin_set_at)
-				 VALUES ($1, $2, NOW())
-				 ON CONFLICT (user_id)
-				 DO UPDATE SET
-				   transaction_pin_hash = EXCLUDED.transaction_pin_hash,
-				   pin_set_at = NOW(),
-				   updated_at = NOW()`,
-				existing.ID,
-				string(hash),
-			)
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
+			response := map[string]any{"accountNumber": *accountNumber}
+			if bankName != nil && strings.TrimSpace(*bankName) != "" {
+				response["bankName"] = *bankName
 			}
-
-			writeJSON(w, http.StatusOK, map[string]string{"status": "transaction_pin_set"})
+			writeJSON(w, http.StatusOK, response)
 		})
 
-		r.Post("/me/pin-change/complete", func(w http.ResponseWriter, r *http.Request) {
+		r.Get("/me/close-account/eligibility", func(w http.ResponseWriter, r *http.Request) {
 			existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
 			if err != nil || existing == nil {
 				writeError(w, statusCode, err)
 				return
 			}
 
-			var body struct {
-				CurrentPin string `json:"current_pin"`
-				NewPin     string `json:"new_pin"`
-			}
-			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-				writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
-				return
-			}
-
-			currentPin := strings.TrimSpace(body.CurrentPin)
-			newPin := strings.TrimSpace(body.NewPin)
-			if currentPin == "" || newPin == "" {
-				writeError(w, http.StatusBadRequest, errors.New("current_pin and new_pin are required"))
-				return
-			}
-			if !pinPattern.MatchString(currentPin) {
-				writeError(w, http.StatusBadRequest, errors.New("current_pin must be exactly 4 digits"))
-				return
-			}
-			if err := validateTransactionPIN(newPin); err != nil {
-				writeError(w, http.StatusBadRequest, err)
-				return
-			}
-			if err := requireFreshPinChangeReverification(
-				r.Context(),
-				pinChangeReverificationMaxAgeSeconds,
-			); err != nil {
-				writeError(w, http.StatusPreconditionFailed, err)
-				return
-			}
-
-			tx, err := dbpool.Begin(r.Context())
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-			defer tx.Rollback(r.Context())
-
-			now := time.Now().UTC()
-
-			var (
-				storedHash     string
-				failedAttempts int
-				lockedUntil    *time.Time
-			)
-			if err := tx.QueryRow(
-				r.Context(),
-				`SELECT transaction_pin_hash, failed_attempts, locked_until
-				   FROM user_security_credentials
-				  WHERE user_id = $1
-				  FOR UPDATE`,
-				existing.ID,
-			).Scan(&storedHash, &failedAttempts, &lockedUntil); err != nil {
-				if errors.Is(err, pgx.ErrNoRows) {
-					writeError(w, http.StatusPreconditionFailed, errors.New("transaction pin is not set"))
-					return
-				}
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			if lockedUntil != nil && lockedUntil.After(now) {
-				writeError(w, http.StatusLocked, errors.New("transaction pin is temporarily locked"))
-				return
-			}
-
-			if bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(currentPin)) != nil {
-				if err := recordFailedTransactionPINAttemptTx(r.Context(), tx, existing.ID, now); err != nil {
-					writeError(w, http.StatusInternalServerError, err)
-					return
-				}
-				if err := tx.Commit(r.Context()); err != nil {
-					writeError(w, http.StatusInternalServerError, err)
-					return
-				}
-				writeError(w, http.StatusUnauthorized, errors.New("current pin is invalid"))
-				return
-			}
-
-			if bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(newPin)) == nil {
-				writeError(w, http.StatusBadRequest, errors.New("new pin must be different from current pin"))
-				return
-			}
-
-			newHash, err := bcrypt.GenerateFromPassword([]byte(newPin), bcrypt.DefaultCost)
+			eligibility, err := userRepo.GetAccountClosureEligibility(r.Context(), existing.ID)
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
-
-			if _, err := tx.Exec(
-				r.Context(),
-				`UPDATE user_security_credentials
-				    SET transaction_pin_hash = $2,
-				        pin_set_at = NOW(),
-				        failed_attempts = 0,
-				        last_failed_at = NULL,
-				        locked_until = NULL,
-				        updated_at = NOW()
-				  WHERE user_id = $1`,
-				existing.ID,
-				string(newHash),
-			); err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			if err := tx.Commit(r.Context()); err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			writeJSON(w, http.StatusOK, map[string]string{"status": "transaction_pin_changed"})
+			writeJSON(w, http.StatusOK, eligibility)
 		})
 
-		r.Get("/me/primary-account", func(w http.ResponseWriter, r *http.Request) {
+		r.Post("/me/close-account", func(w http.ResponseWriter, r *http.Request) {
 			existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
 			if err != nil || existing == nil {
 				writeError(w, statusCode, err)
 				return
 			}
 
-			accountNumber, bankName, err := getPrimaryAccount(r.Context(), dbpool, existing.ID)
+			closedAt, err := userRepo.CloseUserAndEnqueueEvent(r.Context(), existing.ID, "user_events", "user.closed")
 			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			if accountNumber == nil {
-				writeJSON(w, http.StatusOK, map[string]any{})
+				var blocked *store.AccountClosureBlockedError
+				switch {
+				case errors.As(err, &blocked):
+					writeJSON(w, http.StatusConflict, accountClosureBlockedResponse{
+						Envelope: apierror.Envelope{Error: apierror.Error{
+							Code:    apierror.CodeAccountClosureBlocked,
+							Message: blocked.Error(),
+						}},
+						BlockingReasons: blocked.Reasons,
+					})
+				case errors.Is(err, store.ErrUserAlreadyClosed):
+					writeError(w, http.StatusGone, err)
+				default:
+					writeError(w, http.StatusInternalServerError, err)
+				}
 				return
 			}
 
-			response := map[string]any{"accountNumber": *accountNumber}
-			if bankName != nil && strings.TrimSpace(*bankName) != "" {
-				response["bankName"] = *bankName
-			}
-			writeJSON(w, http.StatusOK, response)
+			writeJSON(w, http.StatusOK, map[string]any{
+				"status":    "account_closed",
+				"closed_at": closedAt,
+			})
 		})
 
 		r.Get("/auth/session", func(w http.ResponseWriter, r *http.Request) {
@@ -765,25 +693,18 @@ func main() {
 				return
 			}
 
-			status, reason, hasAccount, hasTransactionPIN, err := deriveOnboardingStatus(r.Context(), dbpool, existing.ID)
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
-
-			progress, err := userRepo.GetOnboardingProgressByClerkUserID(r.Context(), existing.ClerkUserID)
+			state, err := loadOnboardingState(r.Context(), dbpool, userRepo, existing)
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
 
 			clerkUserID, _ := api.GetClerkUserID(r.Context())
-			usernameMissing := existing.Username == nil || strings.TrimSpace(*existing.Username) == ""
 			writeJSON(w, http.StatusOK, authSessionResponse{
 				Authenticated: true,
 				ClerkUserID:   clerkUserID,
 				User:          existing,
-				Onboarding:    buildOnboardingState(status, reason, progress, hasAccount, usernameMissing, hasTransactionPIN),
+				Onboarding:    state,
 			})
 		})
 	})
@@ -829,25 +750,29 @@ func securityHeadersMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func parseAllowedOrigins(raw string) []string {
-	trimmed := strings.TrimSpace(raw)
-	if trimmed == "" {
-		return []string{"http://localhost:3000", "http://localhost:19006", "http://localhost:8081"}
-	}
-
-	parts := strings.Split(trimmed, ",")
-	origins := make([]string, 0, len(parts))
-	for _, part := range parts {
-		value := strings.TrimSpace(part)
-		if value != "" {
-			origins = append(origins, value)
-		}
-	}
+// closedAccountGuard answers 410 Gone for every authenticated request made by a user
+// who has closed their account. Users that are not onboarded yet pass through.
+func closedAccountGuard(userRepo store.UserRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clerkUserID, ok := api.GetClerkUserID(r.Context())
+			if !ok || strings.TrimSpace(clerkUserID) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-	if len(origins) == 0 {
-		return []string{"*"}
+			existing, err := userRepo.FindByClerkUserID(r.Context(), clerkUserID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			if existing != nil && existing.ClosedAt != nil {
+				writeError(w, http.StatusGone, errors.New("account has been closed"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
 	}
-	return origins
 }
 
 func resolveAuthenticatedUser(r *http.Request, userRepo store.UserRepository) (*domain.User, int, error) {
@@ -916,6 +841,7 @@ func searchUsersByQuery(ctx context.Context, dbpool *pgxpool.Pool, requesterID s
 		`SELECT id, btrim(username) AS username, full_name
 		   FROM users
 		  WHERE id <> $1
+		    AND closed_at IS NULL
 		    AND username IS NOT NULL
 		    AND btrim(username) <> ''
 		    AND (
@@ -961,6 +887,7 @@ func listFrequentUsers(ctx context.Context, dbpool *pgxpool.Pool, requesterID st
 		  WHERE t.sender_id = $1
 		    AND t.type = 'p2p'
 		    AND t.status = 'completed'
+		    AND u.closed_at IS NULL
 		    AND u.username IS NOT NULL
 		    AND btrim(u.username) <> ''
 		  GROUP BY u.id, btrim(u.username), u.full_name
@@ -998,6 +925,7 @@ func listFrequentUsers(ctx context.Context, dbpool *pgxpool.Pool, requesterID st
 		`SELECT id, btrim(username) AS username, full_name
 		   FROM users
 		  WHERE id <> $1
+		    AND closed_at IS NULL
 		    AND username IS NOT NULL
 		    AND btrim(username) <> ''
 		  ORDER BY updated_at DESC
@@ -1108,6 +1036,18 @@ func deriveOnboardingStatus(
 		if t2 == "failed" || t2 == "error" {
 			return "tier2_failed", tier2Reason, hasAccount, hasTransactionPIN, nil
 		}
+		// Tier2 passed but there is no account yet; surface a provisioning failure
+		// reported by account-service instead of a completed Tier2.
+		if t2 == "completed" || t2 == "approved" {
+			accountStatus, accountReason, err := accountProvisioningStatus(ctx, dbpool, userID)
+			if err != nil {
+				return "", nil, hasAccount, hasTransactionPIN, err
+			}
+			switch accountStatus {
+			case authapp.AccountStageRetrying, authapp.AccountStageFailed:
+				return "tier2_account_" + accountStatus, accountReason, hasAccount, hasTransactionPIN, nil
+			}
+		}
 		if t2 == "completed" {
 			return "tier2_completed", tier2Reason, hasAccount, hasTransactionPIN, nil
 		}
@@ -1129,6 +1069,28 @@ func deriveOnboardingStatus(
 	return "new", nil, hasAccount, hasTransactionPIN, nil
 }
 
+// accountProvisioningStatus returns the account onboarding stage recorded from
+// account-service's provisioning events, or an empty status when there is none.
+func accountProvisioningStatus(ctx context.Context, dbpool *pgxpool.Pool, userID string) (string, *string, error) {
+	var (
+		status string
+		reason *string
+	)
+	err := dbpool.QueryRow(
+		ctx,
+		`SELECT status, reason FROM onboarding_status WHERE user_id = $1 AND stage = $2`,
+		userID,
+		authapp.AccountOnboardingStage,
+	).Scan(&status, &reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	return strings.ToLower(strings.TrimSpace(status)), reason, nil
+}
+
 func mapNextStep(status string, hasAccount bool, usernameMissing bool, hasTransactionPIN bool) string {
 	if hasAccount {
 		if usernameMissing {
@@ -1190,6 +1152,23 @@ func buildOnboardingState(
 	return state
 }
 
+// loadOnboardingState derives the onboarding state of an existing user, including
+// any saved draft.
+func loadOnboardingState(ctx context.Context, dbpool *pgxpool.Pool, userRepo store.UserRepository, user *domain.User) (onboardingState, error) {
+	status, reason, hasAccount, hasTransactionPIN, err := deriveOnboardingStatus(ctx, dbpool, user.ID)
+	if err != nil {
+		return onboardingState{}, err
+	}
+
+	progress, err := userRepo.GetOnboardingProgressByClerkUserID(ctx, user.ClerkUserID)
+	if err != nil {
+		return onboardingState{}, err
+	}
+
+	usernameMissing := user.Username == nil || strings.TrimSpace(*user.Username) == ""
+	return buildOnboardingState(status, reason, progress, hasAccount, usernameMissing, hasTransactionPIN), nil
+}
+
 func userHasAccount(ctx context.Context, dbpool *pgxpool.Pool, userID string) (bool, error) {
 	var accountCount int
 	if err := dbpool.QueryRow(ctx, `SELECT COUNT(1) FROM accounts WHERE user_id = $1`, userID).Scan(&accountCount); err != nil {
@@ -1255,7 +1234,7 @@ func getPrimaryAccount(ctx context.Context, dbpool *pgxpool.Pool, userID string)
 	var bankName *string
 	err := dbpool.QueryRow(
 		ctx,
-		`SELECT virtual_nuban, bank_name FROM accounts WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`,
+		`SELECT virtual_nuban, bank_name FROM accounts WHERE user_id = $1 AND account_type = 'primary' ORDER BY created_at DESC LIMIT 1`,
 		userID,
 	).Scan(&accountNumber, &bankName)
 	if err != nil {
@@ -1402,38 +1381,6 @@ func normalizeISODate(value string) (string, error) {
 	return parsed.UTC().Format("2006-01-02"), nil
 }
 
-func recordFailedTransactionPINAttemptTx(ctx context.Context, tx pgx.Tx, userID string, now time.Time) error {
-	var failedAttempts int
-	if err := tx.QueryRow(
-		ctx,
-		`SELECT failed_attempts FROM user_security_credentials WHERE user_id = $1`,
-		userID,
-	).Scan(&failedAttempts); err != nil {
-		return err
-	}
-
-	nextAttempts := failedAttempts + 1
-	var lockedUntil interface{}
-	if nextAttempts >= 5 {
-		lockUntilValue := now.Add(15 * time.Minute)
-		lockedUntil = lockUntilValue
-	}
-
-	_, err := tx.Exec(
-		ctx,
-		`UPDATE user_security_credentials
-		    SET failed_attempts = $2,
-		        last_failed_at = NOW(),
-		        locked_until = $3,
-		        updated_at = NOW()
-		  WHERE user_id = $1`,
-		userID,
-		nextAttempts,
-		lockedUntil,
-	)
-	return err
-}
-
 func toString(value any) string {
 	switch typed := value.(type) {
 	case string:
@@ -1443,38 +1390,6 @@ func toString(value any) string {
 	}
 }
 
-func writeJSON(w http.ResponseWriter, statusCode int, payload any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	_ = json.NewEncoder(w).Encode(payload)
-}
-
-func writeError(w http.ResponseWriter, statusCode int, err error) {
-	message := "Internal server error"
-	switch statusCode {
-	case http.StatusUnauthorized:
-		message = "Unauthorized"
-	case http.StatusNotFound:
-		message = "User not found"
-	case http.StatusBadRequest:
-		message = "Bad request"
-	case http.StatusPreconditionFailed:
-		message = "Precondition failed"
-	case http.StatusConflict:
-		message = "Conflict"
-	}
-
-	if statusCode >= 500 {
-		log.Printf("Request failed with %d: %v", statusCode, err)
-	}
-
-	payload := map[string]string{"error": message}
-	if statusCode < 500 && err != nil {
-		payload["detail"] = err.Error()
-	}
-	writeJSON(w, statusCode, payload)
-}
-
 func verifyRequiredSchema(ctx context.Context, dbpool *pgxpool.Pool) error {
 	requiredTables := []string{
 		"users",
@@ -1483,6 +1398,8 @@ func verifyRequiredSchema(ctx context.Context, dbpool *pgxpool.Pool) error {
 		"onboarding_status",
 		"onboarding_progress",
 		"event_outbox",
+		"username_history",
+		"tier2_submissions",
 	}
 
 	for _, tableName := range requiredTables {
@@ -1496,11 +1413,12 @@ func verifyRequiredSchema(ctx context.Context, dbpool *pgxpool.Pool) error {
 	}
 
 	requiredColumnsByTable := map[string][]string{
-		"users":                     {"id", "clerk_user_id", "email"},
+		"users":                     {"id", "clerk_user_id", "email", "closed_at", "lifecycle_status", "clerk_deleted_at"},
 		"accounts":                  {"user_id", "virtual_nuban", "bank_name"},
 		"user_security_credentials": {"user_id", "transaction_pin_hash", "pin_set_at", "updated_at"},
-		"onboarding_status":         {"user_id", "stage", "status", "reason", "updated_at"},
+		"onboarding_status":         {"user_id", "stage", "status", "reason", "retry_count", "updated_at"},
 		"onboarding_progress":       {"clerk_user_id", "user_id", "user_type", "current_step", "payload", "updated_at"},
+		"tier2_submissions":         {"user_id", "document_type", "status", "value_encrypted", "rejection_reason", "anchor_verification_id"},
 		"event_outbox": {
 			"id",
 			"exchange",
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	api "github.com/transfa/auth-service/internal/api"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+type closedAccountGuardRepoStub struct {
+	store.UserRepository
+
+	user *domain.User
+	err  error
+}
+
+func (s *closedAccountGuardRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.user == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return s.user, nil
+}
+
+func TestClosedAccountGuard(t *testing.T) {
+	closedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		repo       *closedAccountGuardRepoStub
+		wantStatus int
+		wantNext   bool
+	}{
+		{
+			name:       "passes open users through",
+			repo:       &closedAccountGuardRepoStub{user: &domain.User{ID: "user-1"}},
+			wantStatus: http.StatusNoContent,
+			wantNext:   true,
+		},
+		{
+			name:       "passes users that are not onboarded yet through",
+			repo:       &closedAccountGuardRepoStub{},
+			wantStatus: http.StatusNoContent,
+			wantNext:   true,
+		},
+		{
+			name:       "rejects closed users with 410",
+			repo:       &closedAccountGuardRepoStub{user: &domain.User{ID: "user-1", ClosedAt: &closedAt}},
+			wantStatus: http.StatusGone,
+		},
+		{
+			name:       "fails closed on lookup errors",
+			repo:       &closedAccountGuardRepoStub{err: errors.New("db down")},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me/profile", nil)
+			req = req.WithContext(api.WithClerkUserID(req.Context(), "clerk_123"))
+			rec := httptest.NewRecorder()
+
+			closedAccountGuard(tt.repo)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if called != tt.wantNext {
+				t.Fatalf("expected next handler called=%v, got %v", tt.wantNext, called)
+			}
+		})
+	}
+}
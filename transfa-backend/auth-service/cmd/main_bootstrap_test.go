@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/transfa/auth-service/internal/api"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/auth-service/pkg/bootstrapclient"
+	"github.com/transfa/pkg/apierror"
+)
+
+type bootstrapRepoStub struct {
+	store.UserRepository
+
+	user *domain.User
+}
+
+func (s *bootstrapRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	return s.user, nil
+}
+
+func newBootstrapTestSources() bootstrapSources {
+	bankName := "Transfa MFB"
+	return bootstrapSources{
+		onboarding: func(ctx context.Context, user *domain.User) (onboardingState, error) {
+			return onboardingState{Status: "completed", NextStep: "app_tabs"}, nil
+		},
+		primaryAccount: func(ctx context.Context, userID string) (*primaryAccountSummary, error) {
+			return &primaryAccountSummary{AccountNumber: "0123456789", BankName: &bankName}, nil
+		},
+		receivingPreference: func(ctx context.Context, authorization string) (json.RawMessage, error) {
+			return json.RawMessage(`{"use_external_account":false}`), nil
+		},
+		subscription: func(ctx context.Context, authorization string) (json.RawMessage, error) {
+			return json.RawMessage(`{"status":"active"}`), nil
+		},
+	}
+}
+
+type bootstrapTestResponse struct {
+	Profile             bootstrapTestSection `json:"profile"`
+	Onboarding          bootstrapTestSection `json:"onboarding"`
+	PrimaryAccount      bootstrapTestSection `json:"primary_account"`
+	ReceivingPreference bootstrapTestSection `json:"receiving_preference"`
+	Subscription        bootstrapTestSection `json:"subscription"`
+}
+
+type bootstrapTestSection struct {
+	Data  json.RawMessage `json:"data"`
+	Error *apierror.Error `json:"error"`
+}
+
+func serveBootstrap(t *testing.T, sources bootstrapSources) bootstrapTestResponse {
+	t.Helper()
+	repo := &bootstrapRepoStub{user: &domain.User{ID: "user-1", ClerkUserID: "clerk_123"}}
+
+	req := httptest.NewRequest(http.MethodGet, "/me/bootstrap", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req = req.WithContext(api.WithClerkUserID(req.Context(), "clerk_123"))
+	rec := httptest.NewRecorder()
+
+	bootstrapHandler(repo, sources).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body bootstrapTestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return body
+}
+
+func TestBootstrapHandler_ReturnsEverySection(t *testing.T) {
+	sources := newBootstrapTestSources()
+	var forwarded atomic.Value
+	sources.subscription = func(ctx context.Context, authorization string) (json.RawMessage, error) {
+		forwarded.Store(authorization)
+		return json.RawMessage(`{"status":"active"}`), nil
+	}
+
+	body := serveBootstrap(t, sources)
+
+	for name, section := range map[string]bootstrapTestSection{
+		"profile":              body.Profile,
+		"onboarding":           body.Onboarding,
+		"primary_account":      body.PrimaryAccount,
+		"receiving_preference": body.ReceivingPreference,
+		"subscription":         body.Subscription,
+	} {
+		if section.Error != nil || len(section.Data) == 0 || string(section.Data) == "null" {
+			t.Fatalf("expected %s data without error, got %+v", name, section)
+		}
+	}
+	if string(body.Subscription.Data) != `{"status":"active"}` {
+		t.Fatalf("expected the subscription status to pass through, got %s", body.Subscription.Data)
+	}
+	if forwarded.Load() != "Bearer token" {
+		t.Fatalf("expected the caller's Authorization header to be forwarded, got %v", forwarded.Load())
+	}
+}
+
+func TestBootstrapHandler_ToleratesSectionFailures(t *testing.T) {
+	sources := newBootstrapTestSources()
+	sources.receivingPreference = func(ctx context.Context, authorization string) (json.RawMessage, error) {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	sources.subscription = func(ctx context.Context, authorization string) (json.RawMessage, error) {
+		return nil, &bootstrapclient.StatusError{StatusCode: http.StatusNotFound, Code: "subscription_not_found", Message: "No subscription"}
+	}
+
+	body := serveBootstrap(t, sources)
+
+	if body.Profile.Error != nil || body.Onboarding.Error != nil || body.PrimaryAccount.Error != nil {
+		t.Fatalf("expected the local sections to load, got %+v", body)
+	}
+	if body.ReceivingPreference.Error == nil || body.ReceivingPreference.Error.Code != apierror.CodeUnavailable {
+		t.Fatalf("expected receiving_preference to be unavailable, got %+v", body.ReceivingPreference)
+	}
+	if string(body.ReceivingPreference.Data) != "null" {
+		t.Fatalf("expected no receiving_preference data, got %s", body.ReceivingPreference.Data)
+	}
+	if body.Subscription.Error == nil || body.Subscription.Error.Code != "subscription_not_found" {
+		t.Fatalf("expected the subscription service's code, got %+v", body.Subscription)
+	}
+}
+
+func TestBootstrapHandler_PrimaryAccountIsNullWithoutAccount(t *testing.T) {
+	sources := newBootstrapTestSources()
+	sources.primaryAccount = func(ctx context.Context, userID string) (*primaryAccountSummary, error) {
+		return nil, nil
+	}
+
+	body := serveBootstrap(t, sources)
+
+	if body.PrimaryAccount.Error != nil || string(body.PrimaryAccount.Data) != "null" {
+		t.Fatalf("expected null primary_account data, got %+v", body.PrimaryAccount)
+	}
+}
+
+func TestBootstrapHandler_LoadsSectionsConcurrently(t *testing.T) {
+	sources := newBootstrapTestSources()
+	release := make(chan struct{})
+	var waiting atomic.Int32
+	wait := func() {
+		if waiting.Add(1) == 2 {
+			close(release)
+		}
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+	}
+	sources.receivingPreference = func(ctx context.Context, authorization string) (json.RawMessage, error) {
+		wait()
+		return json.RawMessage(`{}`), nil
+	}
+	sources.subscription = func(ctx context.Context, authorization string) (json.RawMessage, error) {
+		wait()
+		return json.RawMessage(`{}`), nil
+	}
+
+	started := time.Now()
+	serveBootstrap(t, sources)
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected the downstream sections to be fetched together, took %s", elapsed)
+	}
+}
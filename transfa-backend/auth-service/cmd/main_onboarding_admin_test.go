@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+// stuckOnboardingRepoStub applies the same predicate as ListStuckOnboardingStatuses
+// to an in-memory onboarding_status table.
+type stuckOnboardingRepoStub struct {
+	store.UserRepository
+
+	rows []domain.StuckOnboardingStatus
+}
+
+func (s *stuckOnboardingRepoStub) ListStuckOnboardingStatuses(ctx context.Context, updatedBefore time.Time) ([]domain.StuckOnboardingStatus, error) {
+	terminal := map[string]bool{"completed": true, "created": true, "failed": true}
+	var stuck []domain.StuckOnboardingStatus
+	for _, row := range s.rows {
+		if row.UpdatedAt.Before(updatedBefore) && !terminal[row.Status] {
+			stuck = append(stuck, row)
+		}
+	}
+	return stuck, nil
+}
+
+type stuckOnboardingResponse struct {
+	SinceHours int                                       `json:"since_hours"`
+	Total      int                                       `json:"total"`
+	Groups     map[string][]domain.StuckOnboardingStatus `json:"groups"`
+}
+
+func TestStuckOnboardingHandler(t *testing.T) {
+	now := time.Now()
+	repo := &stuckOnboardingRepoStub{rows: []domain.StuckOnboardingStatus{
+		{UserID: "user-tier1", Stage: "tier1", Status: "processing", UpdatedAt: now.Add(-30 * time.Hour)},
+		{UserID: "user-tier2", Stage: "tier2", Status: "processing", UpdatedAt: now.Add(-72 * time.Hour)},
+		{UserID: "user-rate-limited", Stage: "tier2", Status: "rate_limited", UpdatedAt: now.Add(-50 * time.Hour)},
+		{UserID: "user-recent", Stage: "tier2", Status: "processing", UpdatedAt: now.Add(-2 * time.Hour)},
+		{UserID: "user-completed", Stage: "tier2", Status: "completed", UpdatedAt: now.Add(-96 * time.Hour)},
+		{UserID: "user-failed", Stage: "tier3", Status: "failed", UpdatedAt: now.Add(-96 * time.Hour)},
+	}}
+	handler := internalAPIKeyMiddleware("internal-key")(stuckOnboardingHandler(repo))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSince  int
+		wantUsers  map[string][]string
+	}{
+		{
+			name:       "defaults to 24 hours",
+			wantStatus: http.StatusOK,
+			wantSince:  24,
+			wantUsers: map[string][]string{
+				"tier1_processing":   {"user-tier1"},
+				"tier2_processing":   {"user-tier2"},
+				"tier2_rate_limited": {"user-rate-limited"},
+			},
+		},
+		{
+			name:       "since narrows to older stuck users",
+			query:      "?since=48",
+			wantStatus: http.StatusOK,
+			wantSince:  48,
+			wantUsers: map[string][]string{
+				"tier2_processing":   {"user-tier2"},
+				"tier2_rate_limited": {"user-rate-limited"},
+			},
+		},
+		{
+			name:       "small since includes recent users",
+			query:      "?since=1",
+			wantStatus: http.StatusOK,
+			wantSince:  1,
+			wantUsers: map[string][]string{
+				"tier1_processing":   {"user-tier1"},
+				"tier2_processing":   {"user-tier2", "user-recent"},
+				"tier2_rate_limited": {"user-rate-limited"},
+			},
+		},
+		{name: "rejects invalid since", query: "?since=abc", wantStatus: http.StatusUnprocessableEntity},
+		{name: "rejects non-positive since", query: "?since=0", wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/onboarding/stuck"+tt.query, nil)
+			req.Header.Set("X-Internal-API-Key", "internal-key")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body stuckOnboardingResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if body.SinceHours != tt.wantSince {
+				t.Fatalf("expected since_hours %d, got %d", tt.wantSince, body.SinceHours)
+			}
+			total := 0
+			for key, users := range tt.wantUsers {
+				total += len(users)
+				group := body.Groups[key]
+				if len(group) != len(users) {
+					t.Fatalf("group %s: expected %v, got %+v", key, users, group)
+				}
+				for i, userID := range users {
+					if group[i].UserID != userID {
+						t.Fatalf("group %s: expected %v, got %+v", key, users, group)
+					}
+				}
+			}
+			if len(body.Groups) != len(tt.wantUsers) || body.Total != total {
+				t.Fatalf("unexpected groups: %+v", body.Groups)
+			}
+			for _, group := range body.Groups {
+				for _, item := range group {
+					if item.Status == "completed" || item.Status == "failed" {
+						t.Fatalf("expected terminal statuses to be excluded, got %+v", item)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("requires the internal API key", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/onboarding/stuck", nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/transfa/auth-service/internal/api"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/pkg/apierror"
+)
+
+type transactionPINRepoStub struct {
+	store.UserRepository
+
+	user *domain.User
+	pins map[string]string
+}
+
+func newTransactionPINRepoStub() *transactionPINRepoStub {
+	username := "alice"
+	return &transactionPINRepoStub{
+		user: &domain.User{ID: "user-1", ClerkUserID: "clerk_123", Username: &username},
+		pins: map[string]string{},
+	}
+}
+
+func (s *transactionPINRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	return s.user, nil
+}
+
+func (s *transactionPINRepoStub) CreateTransactionPIN(ctx context.Context, userID, pin string) error {
+	if _, exists := s.pins[userID]; exists {
+		return store.ErrTransactionPINAlreadySet
+	}
+	s.pins[userID] = pin
+	return nil
+}
+
+func (s *transactionPINRepoStub) VerifyTransactionPIN(ctx context.Context, userID, pin string) error {
+	stored, exists := s.pins[userID]
+	if !exists {
+		return store.ErrTransactionPINNotSet
+	}
+	if stored != pin {
+		return store.ErrTransactionPINMismatch
+	}
+	return nil
+}
+
+func (s *transactionPINRepoStub) ChangeTransactionPIN(ctx context.Context, userID, currentPIN, newPIN string) error {
+	if err := s.VerifyTransactionPIN(ctx, userID, currentPIN); err != nil {
+		return err
+	}
+	s.pins[userID] = newPIN
+	return nil
+}
+
+func accountReady(ctx context.Context, userID string) (bool, error) {
+	return true, nil
+}
+
+func authenticatedPINRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/me/transaction-pin", strings.NewReader(body))
+	ctx := api.WithClerkUserID(req.Context(), "clerk_123")
+	ctx = api.WithClerkSessionSecurity(ctx, &api.ClerkSessionSecurity{FirstFactorAgeMinutes: int64Ptr(1)})
+	return req.WithContext(ctx)
+}
+
+func TestTransactionPINSetupHandler(t *testing.T) {
+	repo := newTransactionPINRepoStub()
+	handler := transactionPINSetupHandler(repo, accountReady)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, authenticatedPINRequest(http.MethodPost, `{"pin":"2580"}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected initial setup to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if repo.pins["user-1"] != "2580" {
+		t.Fatalf("expected pin to be stored")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, authenticatedPINRequest(http.MethodPost, `{"pin":"3691"}`))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected duplicate setup to be rejected with 409, got %d", rec.Code)
+	}
+	if repo.pins["user-1"] != "2580" {
+		t.Fatalf("expected existing pin to be kept, got %q", repo.pins["user-1"])
+	}
+}
+
+func TestTransactionPINChangeHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantPIN    string
+	}{
+		{
+			name:       "rejects wrong current pin",
+			body:       `{"current_pin":"9753","new_pin":"3691"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantPIN:    "2580",
+		},
+		{
+			name:       "changes pin with correct current pin",
+			body:       `{"current_pin":"2580","new_pin":"3691"}`,
+			wantStatus: http.StatusOK,
+			wantPIN:    "3691",
+		},
+		{
+			name:       "rejects predictable new pin",
+			body:       `{"current_pin":"2580","new_pin":"1234"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantPIN:    "2580",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTransactionPINRepoStub()
+			repo.pins["user-1"] = "2580"
+
+			rec := httptest.NewRecorder()
+			transactionPINChangeHandler(repo, 600).ServeHTTP(rec, authenticatedPINRequest(http.MethodPut, tt.body))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if repo.pins["user-1"] != tt.wantPIN {
+				t.Fatalf("expected stored pin %q, got %q", tt.wantPIN, repo.pins["user-1"])
+			}
+		})
+	}
+}
+
+func TestInternalVerifyTransactionPINHandler(t *testing.T) {
+	repo := newTransactionPINRepoStub()
+	repo.pins["user-1"] = "2580"
+	handler := internalAPIKeyMiddleware("secret")(internalVerifyTransactionPINHandler(repo))
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "verifies correct pin", apiKey: "secret", body: `{"user_id":"user-1","pin":"2580"}`, wantStatus: http.StatusOK},
+		{name: "rejects wrong pin", apiKey: "secret", body: `{"user_id":"user-1","pin":"2581"}`, wantStatus: http.StatusUnauthorized, wantCode: apierror.CodeTransactionPINInvalid},
+		{name: "reports missing pin", apiKey: "secret", body: `{"user_id":"user-2","pin":"2580"}`, wantStatus: http.StatusPreconditionFailed, wantCode: apierror.CodeTransactionPINNotSet},
+		{name: "requires internal api key", apiKey: "wrong", body: `{"user_id":"user-1","pin":"2580"}`, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/internal/verify-transaction-pin", strings.NewReader(tt.body))
+			req.Header.Set("X-Internal-API-Key", tt.apiKey)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				var body apierror.Envelope
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != tt.wantCode {
+					t.Fatalf("expected code %q, got %s", tt.wantCode, rec.Body.String())
+				}
+			}
+		})
+	}
+}
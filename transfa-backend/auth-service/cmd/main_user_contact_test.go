@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+type userContactRepoStub struct {
+	store.UserRepository
+
+	contacts map[string]*domain.UserContact
+}
+
+func (s *userContactRepoStub) FindContactByUserID(ctx context.Context, userID string) (*domain.UserContact, error) {
+	contact, ok := s.contacts[userID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return contact, nil
+}
+
+func TestInternalUserContactHandler(t *testing.T) {
+	const userID = "5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f"
+	email := "ada@example.com"
+	username := "ada"
+	repo := &userContactRepoStub{contacts: map[string]*domain.UserContact{
+		userID: {UserID: userID, Email: &email, Username: &username},
+	}}
+
+	r := chi.NewRouter()
+	r.Use(internalAPIKeyMiddleware("secret"))
+	r.Get("/internal/users/{id}/contact", internalUserContactHandler(repo))
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		userID     string
+		wantStatus int
+	}{
+		{name: "returns contact", apiKey: "secret", userID: userID, wantStatus: http.StatusOK},
+		{name: "unknown user", apiKey: "secret", userID: "0b5f2f9e-7a3c-4d1e-8f6a-2c3d4e5f6a7b", wantStatus: http.StatusNotFound},
+		{name: "malformed id", apiKey: "secret", userID: "not-a-uuid", wantStatus: http.StatusUnprocessableEntity},
+		{name: "requires internal api key", apiKey: "wrong", userID: userID, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/internal/users/"+tt.userID+"/contact", nil)
+			req.Header.Set("X-Internal-API-Key", tt.apiKey)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var contact domain.UserContact
+			if err := json.Unmarshal(rec.Body.Bytes(), &contact); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if contact.Email == nil || *contact.Email != email {
+				t.Fatalf("unexpected contact %+v", contact)
+			}
+		})
+	}
+}
@@ -1,6 +1,18 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/transfa/auth-service/internal/api"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
 
 func TestNormalizeAndValidateUsername(t *testing.T) {
 	tests := []struct {
@@ -64,3 +76,71 @@ func TestNormalizeAndValidateUsername(t *testing.T) {
 		})
 	}
 }
+
+type usernameChangeRepoStub struct {
+	store.UserRepository
+
+	user      *domain.User
+	changeErr error
+	changedTo string
+}
+
+func (s *usernameChangeRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	return s.user, nil
+}
+
+func (s *usernameChangeRepoStub) ChangeUsername(ctx context.Context, userID, username, exchange, routingKey string) (*domain.UsernameChangedEvent, error) {
+	if s.changeErr != nil {
+		return nil, s.changeErr
+	}
+	if exchange != "user_events" || routingKey != "user.username_changed" {
+		return nil, errors.New("unexpected event destination")
+	}
+	s.changedTo = username
+	return &domain.UsernameChangedEvent{
+		UserID:      userID,
+		OldUsername: *s.user.Username,
+		NewUsername: username,
+		ChangedAt:   time.Now(),
+	}, nil
+}
+
+func TestUsernameChangeHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		changeErr     error
+		wantStatus    int
+		wantChangedTo string
+	}{
+		{name: "changes username", body: `{"username":"  Alice.New "}`, wantStatus: http.StatusOK, wantChangedTo: "alice.new"},
+		{name: "rejects reserved word", body: `{"username":"transfa"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "rejects too short", body: `{"username":"ab"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "rejects taken username", body: `{"username":"bob"}`, changeErr: store.ErrUsernameTaken, wantStatus: http.StatusConflict},
+		{name: "rejects second change within 30 days", body: `{"username":"alice2"}`, changeErr: store.ErrUsernameChangeTooSoon, wantStatus: http.StatusPreconditionFailed},
+		{name: "rejects same username", body: `{"username":"alice"}`, changeErr: store.ErrUsernameUnchanged, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := "alice"
+			repo := &usernameChangeRepoStub{
+				user:      &domain.User{ID: "user-1", ClerkUserID: "clerk_123", Username: &current},
+				changeErr: tt.changeErr,
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/me/username", strings.NewReader(tt.body))
+			req = req.WithContext(api.WithClerkUserID(req.Context(), "clerk_123"))
+			rec := httptest.NewRecorder()
+
+			usernameChangeHandler(repo).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if repo.changedTo != tt.wantChangedTo {
+				t.Fatalf("expected username changed to %q, got %q", tt.wantChangedTo, repo.changedTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+const defaultStuckOnboardingHours = 24
+
+// stuckOnboardingHandler lists users whose onboarding has not moved out of a
+// non-terminal status for more than ?since hours (default 24), grouped by
+// "<stage>_<status>", e.g. "tier2_processing".
+func stuckOnboardingHandler(userRepo store.UserRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sinceHours := defaultStuckOnboardingHours
+		if raw := strings.TrimSpace(r.URL.Query().Get("since")); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				writeValidationError(w, "since", errors.New("since must be a positive number of hours"))
+				return
+			}
+			sinceHours = parsed
+		}
+
+		updatedBefore := time.Now().Add(-time.Duration(sinceHours) * time.Hour)
+		stuck, err := userRepo.ListStuckOnboardingStatuses(r.Context(), updatedBefore)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		groups := make(map[string][]domain.StuckOnboardingStatus)
+		for _, item := range stuck {
+			key := item.Stage + "_" + item.Status
+			groups[key] = append(groups[key], item)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"since_hours": sinceHours,
+			"total":       len(stuck),
+			"groups":      groups,
+		})
+	}
+}
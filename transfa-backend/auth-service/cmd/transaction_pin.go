@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/pkg/apierror"
+)
+
+// transactionPINSetupHandler sets the user's first transaction PIN. An existing PIN is
+// never overwritten here; it has to be changed with the current PIN.
+func transactionPINSetupHandler(
+	userRepo store.UserRepository,
+	hasAccount func(ctx context.Context, userID string) (bool, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
+		if err != nil || existing == nil {
+			writeError(w, statusCode, err)
+			return
+		}
+
+		var body struct {
+			Pin string `json:"pin"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+
+		pin := strings.TrimSpace(body.Pin)
+		if err := validateTransactionPIN(pin); err != nil {
+			writeValidationError(w, "pin", err)
+			return
+		}
+
+		accountReady, err := hasAccount(r.Context(), existing.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !accountReady {
+			writeError(w, http.StatusPreconditionFailed, errors.New("account provisioning is still in progress"))
+			return
+		}
+
+		if existing.Username == nil || strings.TrimSpace(*existing.Username) == "" {
+			writeError(w, http.StatusPreconditionFailed, errors.New("username must be set before transaction pin"))
+			return
+		}
+
+		if err := userRepo.CreateTransactionPIN(r.Context(), existing.ID, pin); err != nil {
+			if errors.Is(err, store.ErrTransactionPINAlreadySet) {
+				writeError(w, http.StatusConflict, err)
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"status": "transaction_pin_set"})
+	}
+}
+
+// transactionPINChangeHandler replaces the user's PIN after checking the current one
+// and that the session was reverified recently.
+func transactionPINChangeHandler(userRepo store.UserRepository, reverificationMaxAgeSeconds int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
+		if err != nil || existing == nil {
+			writeError(w, statusCode, err)
+			return
+		}
+
+		var body struct {
+			CurrentPin string `json:"current_pin"`
+			NewPin     string `json:"new_pin"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+
+		currentPin := strings.TrimSpace(body.CurrentPin)
+		newPin := strings.TrimSpace(body.NewPin)
+		if currentPin == "" || newPin == "" {
+			field := "current_pin"
+			if currentPin != "" {
+				field = "new_pin"
+			}
+			writeValidationError(w, field, errors.New("current_pin and new_pin are required"))
+			return
+		}
+		if !pinPattern.MatchString(currentPin) {
+			writeValidationError(w, "current_pin", errors.New("current_pin must be exactly 4 digits"))
+			return
+		}
+		if err := validateTransactionPIN(newPin); err != nil {
+			writeValidationError(w, "new_pin", err)
+			return
+		}
+		if err := requireFreshPinChangeReverification(r.Context(), reverificationMaxAgeSeconds); err != nil {
+			writeError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		if err := userRepo.ChangeTransactionPIN(r.Context(), existing.ID, currentPin, newPin); err != nil {
+			writeTransactionPINError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"status": "transaction_pin_changed"})
+	}
+}
+
+// internalVerifyTransactionPINHandler lets other services check a user's PIN. Failed
+// attempts count towards the same lockout as the user-facing endpoints.
+func internalVerifyTransactionPINHandler(userRepo store.UserRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			UserID string `json:"user_id"`
+			Pin    string `json:"pin"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+
+		userID := strings.TrimSpace(body.UserID)
+		pin := strings.TrimSpace(body.Pin)
+		if userID == "" {
+			writeValidationError(w, "user_id", errors.New("user_id is required"))
+			return
+		}
+		if !pinPattern.MatchString(pin) {
+			writeValidationError(w, "pin", errors.New("pin must be exactly 4 digits"))
+			return
+		}
+
+		if err := userRepo.VerifyTransactionPIN(r.Context(), userID, pin); err != nil {
+			writeTransactionPINError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]bool{"valid": true})
+	}
+}
+
+func writeTransactionPINError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrTransactionPINMismatch):
+		writeError(w, http.StatusUnauthorized, err)
+	case errors.Is(err, store.ErrTransactionPINLocked):
+		writeError(w, http.StatusLocked, err)
+	case errors.Is(err, store.ErrTransactionPINNotSet):
+		writeError(w, http.StatusPreconditionFailed, err)
+	case errors.Is(err, store.ErrTransactionPINUnchanged):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// internalAPIKeyMiddleware guards service-to-service routes with the shared internal key.
+func internalAPIKeyMiddleware(requiredKey string) func(http.Handler) http.Handler {
+	normalizedRequiredKey := strings.TrimSpace(requiredKey)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if normalizedRequiredKey == "" {
+				writeErrorWithCode(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, errors.New("Internal API key is not configured"))
+				return
+			}
+
+			provided := strings.TrimSpace(r.Header.Get("X-Internal-API-Key"))
+			if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(normalizedRequiredKey)) != 1 {
+				writeError(w, http.StatusUnauthorized, nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
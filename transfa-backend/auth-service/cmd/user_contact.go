@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+var userIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// internalUserContactHandler returns a user's email and names to other services, for
+// receipts and other messages sent outside the app.
+func internalUserContactHandler(userRepo store.UserRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := strings.TrimSpace(chi.URLParam(r, "id"))
+		if !userIDPattern.MatchString(userID) {
+			writeValidationError(w, "id", errors.New("id must be a user id"))
+			return
+		}
+
+		contact, err := userRepo.FindContactByUserID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				writeError(w, http.StatusNotFound, errors.New("user not found"))
+				return
+			}
+			log.Printf("Error fetching contact for user %s: %v", userID, err)
+			writeError(w, http.StatusInternalServerError, errors.New("failed to load user contact"))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, contact)
+	}
+}
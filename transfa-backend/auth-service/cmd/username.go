@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/transfa/auth-service/internal/store"
+)
+
+// usernameChangeHandler renames an onboarded user. The old username keeps resolving
+// to the new one for 30 days, and a user can rename at most once in that window.
+func usernameChangeHandler(userRepo store.UserRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, statusCode, err := resolveAuthenticatedUser(r, userRepo)
+		if err != nil || existing == nil {
+			writeError(w, statusCode, err)
+			return
+		}
+
+		var body struct {
+			Username string `json:"username"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+
+		username, err := normalizeAndValidateUsername(body.Username)
+		if err != nil {
+			writeValidationError(w, "username", err)
+			return
+		}
+
+		change, err := userRepo.ChangeUsername(r.Context(), existing.ID, username, "user_events", "user.username_changed")
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrUsernameTaken):
+				writeError(w, http.StatusConflict, err)
+			case errors.Is(err, store.ErrUsernameUnchanged):
+				writeError(w, http.StatusBadRequest, err)
+			case errors.Is(err, store.ErrUsernameNotSet), errors.Is(err, store.ErrUsernameChangeTooSoon):
+				writeError(w, http.StatusPreconditionFailed, err)
+			default:
+				writeError(w, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":            "username_changed",
+			"username":          change.NewUsername,
+			"previous_username": change.OldUsername,
+			"changed_at":        change.ChangedAt,
+		})
+	}
+}
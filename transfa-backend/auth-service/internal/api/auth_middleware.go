@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/transfa/pkg/apierror"
 )
 
 type contextKey string
@@ -66,7 +67,7 @@ func ClerkAuthMiddleware(cfg AuthMiddlewareConfig) func(http.Handler) http.Handl
 			if authHeader != "" {
 				tokenString, ok := bearerToken(authHeader)
 				if !ok {
-					http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+					apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid Authorization header format")
 					return
 				}
 
@@ -77,13 +78,13 @@ func ClerkAuthMiddleware(cfg AuthMiddlewareConfig) func(http.Handler) http.Handl
 					strings.TrimSpace(cfg.ExpectedIssuer),
 				)
 				if err != nil {
-					http.Error(w, "Invalid token", http.StatusUnauthorized)
+					apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid token")
 					return
 				}
 
 				headerEmail := strings.ToLower(strings.TrimSpace(r.Header.Get("X-User-Email")))
 				if tokenEmail != "" && headerEmail != "" && tokenEmail != headerEmail {
-					http.Error(w, "Invalid user email context", http.StatusUnauthorized)
+					apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid user email context")
 					return
 				}
 
@@ -103,7 +104,7 @@ func ClerkAuthMiddleware(cfg AuthMiddlewareConfig) func(http.Handler) http.Handl
 				return
 			}
 
-			http.Error(w, "Authorization required", http.StatusUnauthorized)
+			apierror.WriteStatus(w, http.StatusUnauthorized, "Authorization required")
 		})
 	}
 }
@@ -114,6 +115,11 @@ func GetClerkUserID(ctx context.Context) (string, bool) {
 	return userID, ok
 }
 
+// WithClerkUserID stores the authenticated Clerk user ID in context.
+func WithClerkUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, clerkUserIDContextKey, userID)
+}
+
 // GetClerkUserEmail returns the authenticated email from request context when available.
 func GetClerkUserEmail(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value(clerkEmailContextKey).(string)
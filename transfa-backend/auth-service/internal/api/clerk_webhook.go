@@ -0,0 +1,209 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/pkg/apierror"
+)
+
+const (
+	maxClerkWebhookBodyBytes int64 = 1 << 20 // 1 MiB
+	// svixTimestampTolerance bounds how old or early a delivery may be, against replays.
+	svixTimestampTolerance = 5 * time.Minute
+	svixSecretPrefix       = "whsec_"
+)
+
+var (
+	errMissingSvixHeaders = errors.New("missing svix headers")
+	errStaleSvixTimestamp = errors.New("svix timestamp outside tolerance")
+	errInvalidSvixSig     = errors.New("no matching svix signature")
+)
+
+// clerkWebhookEvent is the envelope Clerk sends through Svix.
+type clerkWebhookEvent struct {
+	Type string           `json:"type"`
+	Data clerkWebhookUser `json:"data"`
+}
+
+type clerkWebhookUser struct {
+	ID                    string `json:"id"`
+	PrimaryEmailAddressID string `json:"primary_email_address_id"`
+	EmailAddresses        []struct {
+		ID           string `json:"id"`
+		EmailAddress string `json:"email_address"`
+	} `json:"email_addresses"`
+}
+
+// primaryEmail returns the user's primary email, lowercased, or "" when it has none.
+func (u clerkWebhookUser) primaryEmail() string {
+	for _, address := range u.EmailAddresses {
+		if address.ID == u.PrimaryEmailAddressID {
+			return strings.ToLower(strings.TrimSpace(address.EmailAddress))
+		}
+	}
+	return ""
+}
+
+// ClerkWebhookHandler receives Clerk's user lifecycle webhooks so Transfa users stay
+// in step with Clerk even when the app never reaches /onboarding.
+type ClerkWebhookHandler struct {
+	repo   store.UserRepository
+	secret []byte
+	now    func() time.Time
+}
+
+// NewClerkWebhookHandler creates the handler. signingSecret is the endpoint's Svix
+// secret as shown by Clerk ("whsec_..."); when it is empty or malformed every
+// delivery is rejected with 503.
+func NewClerkWebhookHandler(repo store.UserRepository, signingSecret string) *ClerkWebhookHandler {
+	secret, err := decodeSvixSecret(signingSecret)
+	if err != nil {
+		log.Printf("level=warn component=clerk_webhook msg=\"signing secret is not usable; webhooks will be rejected\" err=%v", err)
+	}
+	return &ClerkWebhookHandler{repo: repo, secret: secret, now: time.Now}
+}
+
+func decodeSvixSecret(raw string) ([]byte, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(raw), svixSecretPrefix)
+	if trimmed == "" {
+		return nil, errors.New("signing secret is not configured")
+	}
+	return base64.StdEncoding.DecodeString(trimmed)
+}
+
+// verifySvixSignature checks the svix-signature header against the HMAC-SHA256 of
+// "<svix-id>.<svix-timestamp>.<body>". The header may carry several space-separated
+// "v1,<base64>" signatures while Clerk rotates secrets; any one matching is enough.
+func verifySvixSignature(secret []byte, header http.Header, body []byte, now time.Time) error {
+	msgID := strings.TrimSpace(header.Get("svix-id"))
+	timestamp := strings.TrimSpace(header.Get("svix-timestamp"))
+	signatures := strings.TrimSpace(header.Get("svix-signature"))
+	if msgID == "" || timestamp == "" || signatures == "" {
+		return errMissingSvixHeaders
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errStaleSvixTimestamp
+	}
+	sentAt := time.Unix(seconds, 0)
+	if now.Sub(sentAt) > svixTimestampTolerance || sentAt.Sub(now) > svixTimestampTolerance {
+		return errStaleSvixTimestamp
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(msgID + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, candidate := range strings.Fields(signatures) {
+		version, encoded, ok := strings.Cut(candidate, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		provided, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(provided, expected) {
+			return nil
+		}
+	}
+	return errInvalidSvixSig
+}
+
+// ServeHTTP handles POST /webhooks/clerk. Failures that a redelivery could fix return
+// 5xx so Svix retries; everything else is acknowledged.
+func (h *ClerkWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(h.secret) == 0 {
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "Clerk webhook secret is not configured")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxClerkWebhookBodyBytes))
+	if err != nil {
+		apierror.WriteStatus(w, http.StatusRequestEntityTooLarge, "Webhook body too large")
+		return
+	}
+	if err := verifySvixSignature(h.secret, r.Header, body, h.now()); err != nil {
+		log.Printf("level=warn component=clerk_webhook outcome=rejected svix_id=%s err=%v", r.Header.Get("svix-id"), err)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid webhook signature")
+		return
+	}
+
+	var event clerkWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || strings.TrimSpace(event.Data.ID) == "" {
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid webhook payload")
+		return
+	}
+	clerkUserID := strings.TrimSpace(event.Data.ID)
+
+	outcome := "processed"
+	switch event.Type {
+	case "user.created":
+		var email *string
+		if primary := event.Data.primaryEmail(); primary != "" {
+			email = &primary
+		}
+		created, err := h.repo.PreProvisionClerkUser(r.Context(), clerkUserID, email)
+		if err != nil {
+			log.Printf("level=error component=clerk_webhook type=%s clerk_user_id=%s err=%v", event.Type, clerkUserID, err)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Could not provision user")
+			return
+		}
+		if !created {
+			outcome = "already_exists"
+		}
+	case "user.updated":
+		email := event.Data.primaryEmail()
+		if email == "" {
+			outcome = "ignored"
+			break
+		}
+		updated, err := h.repo.SyncClerkUserEmail(r.Context(), clerkUserID, email)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				// Another user already has this email; a retry cannot fix that.
+				log.Printf("level=warn component=clerk_webhook type=%s clerk_user_id=%s outcome=email_conflict", event.Type, clerkUserID)
+				outcome = "email_conflict"
+				break
+			}
+			log.Printf("level=error component=clerk_webhook type=%s clerk_user_id=%s err=%v", event.Type, clerkUserID, err)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Could not sync user")
+			return
+		}
+		if !updated {
+			outcome = "unchanged"
+		}
+	case "user.deleted":
+		deleted, err := h.repo.MarkClerkUserDeletedAndEnqueueEvent(r.Context(), clerkUserID, "user_events", "user.deleted")
+		if err != nil {
+			log.Printf("level=error component=clerk_webhook type=%s clerk_user_id=%s err=%v", event.Type, clerkUserID, err)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Could not mark user for cleanup")
+			return
+		}
+		if deleted == nil {
+			outcome = "unchanged"
+		}
+	default:
+		outcome = "ignored"
+	}
+
+	log.Printf("level=info component=clerk_webhook type=%s clerk_user_id=%s svix_id=%s outcome=%s", event.Type, clerkUserID, r.Header.Get("svix-id"), outcome)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": outcome})
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+const testSvixSecret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+const (
+	clerkUserCreatedPayload = `{"data":{"id":"user_2abc","primary_email_address_id":"idn_1","email_addresses":[{"id":"idn_0","email_address":"old@example.com"},{"id":"idn_1","email_address":"Ada@Example.com"}]},"object":"event","type":"user.created"}`
+	clerkUserUpdatedPayload = `{"data":{"id":"user_2abc","primary_email_address_id":"idn_2","email_addresses":[{"id":"idn_2","email_address":"ada.obi@example.com"}]},"object":"event","type":"user.updated"}`
+	clerkUserDeletedPayload = `{"data":{"deleted":true,"id":"user_2abc","object":"user"},"object":"event","type":"user.deleted"}`
+)
+
+// clerkWebhookRepoStub records what the webhook asked the repository to do.
+type clerkWebhookRepoStub struct {
+	store.UserRepository
+
+	provisioned map[string]*string
+	emails      map[string]string
+	deleted     []string
+}
+
+func newClerkWebhookRepoStub() *clerkWebhookRepoStub {
+	return &clerkWebhookRepoStub{provisioned: map[string]*string{}, emails: map[string]string{}}
+}
+
+func (s *clerkWebhookRepoStub) PreProvisionClerkUser(ctx context.Context, clerkUserID string, email *string) (bool, error) {
+	if _, ok := s.provisioned[clerkUserID]; ok {
+		return false, nil
+	}
+	s.provisioned[clerkUserID] = email
+	return true, nil
+}
+
+func (s *clerkWebhookRepoStub) SyncClerkUserEmail(ctx context.Context, clerkUserID string, email string) (bool, error) {
+	s.emails[clerkUserID] = email
+	return true, nil
+}
+
+func (s *clerkWebhookRepoStub) MarkClerkUserDeletedAndEnqueueEvent(ctx context.Context, clerkUserID, exchange, routingKey string) (*domain.ClerkUserDeletedEvent, error) {
+	for _, id := range s.deleted {
+		if id == clerkUserID {
+			return nil, nil
+		}
+	}
+	s.deleted = append(s.deleted, clerkUserID)
+	return &domain.ClerkUserDeletedEvent{UserID: "user-1", ClerkUserID: clerkUserID, DeletedAt: time.Now()}, nil
+}
+
+func signSvix(t *testing.T, secret, msgID string, sentAt time.Time, body string) string {
+	t.Helper()
+	key, err := decodeSvixSecret(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msgID + "." + strconv.FormatInt(sentAt.Unix(), 10) + "." + body))
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newSvixRequest(msgID string, sentAt time.Time, signature, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/clerk", strings.NewReader(body))
+	req.Header.Set("svix-id", msgID)
+	req.Header.Set("svix-timestamp", strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set("svix-signature", signature)
+	return req
+}
+
+func TestVerifySvixSignature_AcceptsSvixReferenceVector(t *testing.T) {
+	secret, err := decodeSvixSecret(testSvixSecret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	header := http.Header{}
+	header.Set("svix-id", "msg_p5jXN8AQM9LWM0D4loKWxJek")
+	header.Set("svix-timestamp", "1614265330")
+	header.Set("svix-signature", "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=")
+
+	if err := verifySvixSignature(secret, header, []byte(`{"test": 2432232314}`), time.Unix(1614265330, 0)); err != nil {
+		t.Fatalf("expected the reference signature to verify, got %v", err)
+	}
+}
+
+func TestVerifySvixSignature_Rejections(t *testing.T) {
+	secret, _ := decodeSvixSecret(testSvixSecret)
+	now := time.Now()
+	valid := signSvix(t, testSvixSecret, "msg_1", now, clerkUserCreatedPayload)
+
+	cases := []struct {
+		name      string
+		sentAt    time.Time
+		signature string
+		body      string
+		want      error
+	}{
+		{name: "tampered body", sentAt: now, signature: valid, body: strings.Replace(clerkUserCreatedPayload, "Ada", "Eve", 1), want: errInvalidSvixSig},
+		{name: "wrong secret", sentAt: now, signature: signSvix(t, "whsec_"+base64.StdEncoding.EncodeToString([]byte("other-secret")), "msg_1", now, clerkUserCreatedPayload), body: clerkUserCreatedPayload, want: errInvalidSvixSig},
+		{name: "stale timestamp", sentAt: now.Add(-10 * time.Minute), signature: signSvix(t, testSvixSecret, "msg_1", now.Add(-10*time.Minute), clerkUserCreatedPayload), body: clerkUserCreatedPayload, want: errStaleSvixTimestamp},
+		{name: "future timestamp", sentAt: now.Add(10 * time.Minute), signature: signSvix(t, testSvixSecret, "msg_1", now.Add(10*time.Minute), clerkUserCreatedPayload), body: clerkUserCreatedPayload, want: errStaleSvixTimestamp},
+		{name: "unsupported version", sentAt: now, signature: strings.Replace(valid, "v1,", "v2,", 1), body: clerkUserCreatedPayload, want: errInvalidSvixSig},
+		{name: "missing signature", sentAt: now, signature: "", body: clerkUserCreatedPayload, want: errMissingSvixHeaders},
+	}
+	for _, tc := range cases {
+		req := newSvixRequest("msg_1", tc.sentAt, tc.signature, tc.body)
+		if err := verifySvixSignature(secret, req.Header, []byte(tc.body), now); err != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	// During secret rotation Svix sends one signature per secret.
+	rotated := "v1,bm90LWEtc2lnbmF0dXJl " + valid
+	if err := verifySvixSignature(secret, newSvixRequest("msg_1", now, rotated, clerkUserCreatedPayload).Header, []byte(clerkUserCreatedPayload), now); err != nil {
+		t.Fatalf("expected one matching signature among several to verify, got %v", err)
+	}
+}
+
+func TestClerkWebhookHandler_RejectsInvalidSignature(t *testing.T) {
+	repo := newClerkWebhookRepoStub()
+	handler := NewClerkWebhookHandler(repo, testSvixSecret)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newSvixRequest("msg_1", time.Now(), "v1,Zm9v", clerkUserCreatedPayload))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.provisioned) != 0 {
+		t.Fatalf("expected no user to be provisioned, got %v", repo.provisioned)
+	}
+}
+
+func TestClerkWebhookHandler_RequiresSecret(t *testing.T) {
+	handler := NewClerkWebhookHandler(newClerkWebhookRepoStub(), "")
+
+	rec := httptest.NewRecorder()
+	now := time.Now()
+	handler.ServeHTTP(rec, newSvixRequest("msg_1", now, signSvix(t, testSvixSecret, "msg_1", now, clerkUserCreatedPayload), clerkUserCreatedPayload))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestClerkWebhookHandler_HandlesUserLifecycle(t *testing.T) {
+	repo := newClerkWebhookRepoStub()
+	handler := NewClerkWebhookHandler(repo, testSvixSecret)
+
+	deliver := func(msgID, body string) string {
+		t.Helper()
+		now := time.Now()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newSvixRequest(msgID, now, signSvix(t, testSvixSecret, msgID, now, body), body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", msgID, rec.Code, rec.Body.String())
+		}
+		return strings.TrimSpace(rec.Body.String())
+	}
+
+	deliver("msg_created", clerkUserCreatedPayload)
+	if email := repo.provisioned["user_2abc"]; email == nil || *email != "ada@example.com" {
+		t.Fatalf("expected the user to be provisioned with the primary email, got %v", email)
+	}
+	if got := deliver("msg_created_again", clerkUserCreatedPayload); got != `{"status":"already_exists"}` {
+		t.Fatalf("expected a redelivered user.created to be a no-op, got %s", got)
+	}
+
+	deliver("msg_updated", clerkUserUpdatedPayload)
+	if repo.emails["user_2abc"] != "ada.obi@example.com" {
+		t.Fatalf("expected the email to be synced, got %q", repo.emails["user_2abc"])
+	}
+
+	deliver("msg_deleted", clerkUserDeletedPayload)
+	if got := deliver("msg_deleted_again", clerkUserDeletedPayload); got != `{"status":"unchanged"}` {
+		t.Fatalf("expected a redelivered user.deleted to be a no-op, got %s", got)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != "user_2abc" {
+		t.Fatalf("expected the user to be marked for cleanup once, got %v", repo.deleted)
+	}
+
+	if got := deliver("msg_session", `{"data":{"id":"sess_1"},"object":"event","type":"session.created"}`); got != `{"status":"ignored"}` {
+		t.Fatalf("expected other events to be ignored, got %s", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/mail"
@@ -15,6 +16,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/transfa/auth-service/internal/domain"
 	"github.com/transfa/auth-service/internal/store"
+	"github.com/transfa/pkg/apierror"
 )
 
 var (
@@ -24,88 +26,65 @@ var (
 
 // OnboardingHandler handles the user onboarding process.
 type OnboardingHandler struct {
-	repo store.UserRepository
+	repo     store.UserRepository
+	tier2Key []byte
 }
 
 // NewOnboardingHandler creates a new handler for the onboarding endpoint.
-func NewOnboardingHandler(repo store.UserRepository) *OnboardingHandler {
-	return &OnboardingHandler{repo: repo}
+// tier2EncryptionKey encrypts submitted Tier 2 details so a rejected piece can be
+// resubmitted on its own; without it every resubmission must resend all pieces.
+func NewOnboardingHandler(repo store.UserRepository, tier2EncryptionKey string) *OnboardingHandler {
+	return &OnboardingHandler{repo: repo, tier2Key: deriveTier2ValueKey(tier2EncryptionKey)}
 }
 
-// HandleTier2 receives BVN/DOB/Gender and records onboarding_status (tier2 -> pending). Returns 202.
+// HandleTier2 receives BVN/DOB/Gender, records each piece in tier2_submissions and
+// onboarding_status (tier2 -> pending). Returns 202.
 func (h *OnboardingHandler) HandleTier2(w http.ResponseWriter, r *http.Request) {
 	clerkUserID, ok := GetClerkUserID(r.Context())
 	if !ok || strings.TrimSpace(clerkUserID) == "" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
 	if err != nil || existing == nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 
 	if existing.AnchorCustomerID == nil || *existing.AnchorCustomerID == "" {
-		http.Error(w, "Tier 1 verification incomplete", http.StatusPreconditionFailed)
+		apierror.WriteStatus(w, http.StatusPreconditionFailed, "Tier 1 verification incomplete")
 		return
 	}
 
-	var body struct {
-		Dob    string `json:"dob"`
-		Gender string `json:"gender"`
-		Bvn    string `json:"bvn"`
-	}
+	var body tier2Request
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	values := body.values()
 
-	body.Dob = strings.TrimSpace(body.Dob)
-	body.Gender = strings.TrimSpace(body.Gender)
-	body.Bvn = strings.TrimSpace(body.Bvn)
-
-	if body.Dob == "" || body.Gender == "" || body.Bvn == "" {
-		http.Error(w, "BVN, date of birth and gender are required", http.StatusBadRequest)
-		return
-	}
-	if !bvnPattern.MatchString(body.Bvn) {
-		http.Error(w, "BVN must be exactly 11 digits", http.StatusBadRequest)
-		return
+	var details []apierror.FieldError
+	for _, documentType := range domain.Tier2Documents {
+		if values[documentType] == "" {
+			field := tier2Fields[documentType]
+			details = append(details, apierror.FieldError{Field: field, Message: field + " is required"})
+		}
 	}
-
-	normalizedDOB, dobErr := normalizeDateOfBirth(body.Dob)
-	if dobErr != nil {
-		http.Error(w, dobErr.Error(), http.StatusBadRequest)
+	if len(details) > 0 {
+		apierror.WriteValidation(w, "BVN, date of birth and gender are required", details...)
 		return
 	}
-
-	genderLower := strings.ToLower(body.Gender)
-	switch genderLower {
-	case "male", "female":
-	default:
-		http.Error(w, "Gender must be 'male' or 'female'", http.StatusBadRequest)
-		return
+	for _, documentType := range domain.Tier2Documents {
+		normalized, err := normalizeTier2Value(documentType, values[documentType])
+		if err != nil {
+			writeValidationError(w, tier2Fields[documentType], err.Error())
+			return
+		}
+		values[documentType] = normalized
 	}
-	normalizedGender := strings.ToUpper(genderLower[:1]) + genderLower[1:]
 
-	event := domain.Tier2VerificationRequestedEvent{
-		UserID:           existing.ID,
-		AnchorCustomerID: *existing.AnchorCustomerID,
-		BVN:              body.Bvn,
-		DateOfBirth:      normalizedDOB,
-		Gender:           normalizedGender,
-	}
-	if err := h.repo.UpsertOnboardingStatusAndEnqueueEvent(
-		r.Context(),
-		existing.ID,
-		"tier2",
-		"pending",
-		nil,
-		"customer_events",
-		"tier2.verification.requested",
-		event,
-	); err != nil {
-		http.Error(w, "Failed to queue tier2 verification", http.StatusInternalServerError)
+	if err := h.submitTier2(r, existing, values, domain.Tier2Documents); err != nil {
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to queue tier2 verification")
 		return
 	}
 	_ = h.repo.ClearOnboardingProgress(r.Context(), clerkUserID)
@@ -120,41 +99,41 @@ func (h *OnboardingHandler) HandleTier2(w http.ResponseWriter, r *http.Request)
 func (h *OnboardingHandler) HandleTier1Update(w http.ResponseWriter, r *http.Request) {
 	clerkUserID, ok := GetClerkUserID(r.Context())
 	if !ok || strings.TrimSpace(clerkUserID) == "" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
 	if err != nil || existing == nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 
 	if existing.AnchorCustomerID == nil || strings.TrimSpace(*existing.AnchorCustomerID) == "" {
-		http.Error(w, "Tier 1 verification incomplete", http.StatusPreconditionFailed)
+		apierror.WriteStatus(w, http.StatusPreconditionFailed, "Tier 1 verification incomplete")
 		return
 	}
 
 	var req domain.OnboardingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	authEmail, err := resolveOnboardingEmail(r, req.Email)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	req.Email = authEmail
 
 	if err := normalizeAndValidateOnboardingRequest(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if req.UserType != domain.PersonalUser {
-		http.Error(w, "tier1 profile updates are currently supported for personal accounts only", http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, "tier1 profile updates are currently supported for personal accounts only")
 		return
 	}
 
@@ -190,7 +169,7 @@ func (h *OnboardingHandler) HandleTier1Update(w http.ResponseWriter, r *http.Req
 		"user.tier1.update.requested",
 		event,
 	); err != nil {
-		http.Error(w, "Failed to process tier1 update", http.StatusInternalServerError)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to process tier1 update")
 		return
 	}
 	_ = h.repo.UpsertOnboardingProgress(
@@ -207,29 +186,128 @@ func (h *OnboardingHandler) HandleTier1Update(w http.ResponseWriter, r *http.Req
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "tier1_update_processing"})
 }
 
+// maxTier1Retries is how many times a user may retry a failed Tier 1 verification.
+const maxTier1Retries = 3
+
+// HandleTier1Retry re-queues Tier 1 verification after it failed. The body is
+// optional: it takes the same shape as POST /onboarding to re-submit corrected
+// details, and without it the details of the failed attempt are sent again.
+func (h *OnboardingHandler) HandleTier1Retry(w http.ResponseWriter, r *http.Request) {
+	clerkUserID, ok := GetClerkUserID(r.Context())
+	if !ok || strings.TrimSpace(clerkUserID) == "" {
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
+	if err != nil || existing == nil {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
+		return
+	}
+
+	var (
+		req                    domain.OnboardingRequest
+		email, phone, fullName *string
+		eventKYC               map[string]interface{}
+	)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
+		return
+	} else if err == nil {
+		authEmail, err := resolveOnboardingEmail(r, req.Email)
+		if err != nil {
+			apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Email = authEmail
+
+		if err := normalizeAndValidateOnboardingRequest(&req); err != nil {
+			apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if value, ok := req.KYCData["fullName"].(string); ok && strings.TrimSpace(value) != "" {
+			trimmed := strings.TrimSpace(value)
+			fullName = &trimmed
+		}
+		email, phone = &req.Email, &req.PhoneNumber
+
+		eventKYC = map[string]interface{}{}
+		for k, v := range req.KYCData {
+			eventKYC[k] = v
+		}
+		eventKYC["email"] = req.Email
+		eventKYC["phoneNumber"] = req.PhoneNumber
+		eventKYC["userType"] = string(req.UserType)
+	}
+
+	retryCount, err := h.repo.RetryTier1AndEnqueueEvent(
+		r.Context(),
+		existing.ID,
+		maxTier1Retries,
+		email,
+		phone,
+		fullName,
+		eventKYC,
+		"customer_events",
+		"tier1.verification.requested",
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, store.ErrTier1NotFailed):
+			apierror.Write(w, http.StatusConflict, apierror.CodeConflict, "Tier 1 verification can only be retried after it has failed")
+		case errors.Is(err, store.ErrTier1RetryLimitReached):
+			apierror.Write(w, http.StatusTooManyRequests, apierror.CodeRateLimited, fmt.Sprintf("Tier 1 verification can be retried at most %d times; please contact support", maxTier1Retries))
+		case errors.Is(err, store.ErrTier1DetailsNotAvailable):
+			apierror.WriteStatus(w, http.StatusUnprocessableEntity, "Please re-submit your details to retry Tier 1 verification")
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			apierror.WriteStatus(w, http.StatusConflict, "This email or phone number is already associated with another account")
+		default:
+			log.Printf("Error retrying tier1 verification for user %s: %v", existing.ID, err)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to retry tier1 verification")
+		}
+		return
+	}
+	if eventKYC != nil {
+		_ = h.repo.UpsertOnboardingProgress(
+			r.Context(),
+			clerkUserID,
+			&existing.ID,
+			string(req.UserType),
+			3,
+			eventKYC,
+		)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "tier1_processing", "retry_count": retryCount})
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	clerkUserID, ok := GetClerkUserID(r.Context())
 	if !ok || strings.TrimSpace(clerkUserID) == "" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.OnboardingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	authEmail, err := resolveOnboardingEmail(r, req.Email)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	req.Email = authEmail
 
 	if err := normalizeAndValidateOnboardingRequest(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -244,7 +322,7 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var internalUserID string
 	existing, findErr := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
 	if findErr != nil && !errors.Is(findErr, pgx.ErrNoRows) {
-		http.Error(w, "Internal server error: could not lookup user", http.StatusInternalServerError)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Internal server error: could not lookup user")
 		return
 	}
 
@@ -260,7 +338,9 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	if findErr == nil && existing != nil {
+	// A row pre-provisioned from Clerk's user.created webhook has no profile yet, so
+	// it goes through the upsert below like a new user.
+	if findErr == nil && existing != nil && existing.LifecycleStatus != domain.UserPendingOnboarding {
 		internalUserID = existing.ID
 
 		if existing.AnchorCustomerID != nil {
@@ -302,10 +382,10 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				case "users_username_key":
 					message = "This username is already taken"
 				}
-				http.Error(w, message, http.StatusConflict)
+				apierror.WriteStatus(w, http.StatusConflict, message)
 				return
 			}
-			http.Error(w, "Internal server error: could not queue onboarding", http.StatusInternalServerError)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Internal server error: could not queue onboarding")
 			return
 		}
 	} else {
@@ -324,7 +404,7 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			AllowSending: req.UserType == domain.PersonalUser,
 		}
 
-		createdID, err := h.repo.CreateUserAndEnqueueUserCreatedEvent(
+		createdID, err := h.repo.UpsertUserAndEnqueueUserCreatedEvent(
 			r.Context(),
 			&newUser,
 			eventKYC,
@@ -343,10 +423,10 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				case "users_username_key":
 					message = "This username is already taken"
 				}
-				http.Error(w, message, http.StatusConflict)
+				apierror.WriteStatus(w, http.StatusConflict, message)
 				return
 			}
-			http.Error(w, "Internal server error: could not create user", http.StatusInternalServerError)
+			apierror.WriteStatus(w, http.StatusInternalServerError, "Internal server error: could not create user")
 			return
 		}
 		internalUserID = createdID
@@ -370,7 +450,7 @@ func (h *OnboardingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *OnboardingHandler) HandleSaveProgress(w http.ResponseWriter, r *http.Request) {
 	clerkUserID, ok := GetClerkUserID(r.Context())
 	if !ok || strings.TrimSpace(clerkUserID) == "" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -380,7 +460,7 @@ func (h *OnboardingHandler) HandleSaveProgress(w http.ResponseWriter, r *http.Re
 		Payload     map[string]interface{} `json:"payload"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -389,18 +469,18 @@ func (h *OnboardingHandler) HandleSaveProgress(w http.ResponseWriter, r *http.Re
 		body.UserType = "personal"
 	}
 	if body.UserType != "personal" && body.UserType != "merchant" {
-		http.Error(w, "user_type must be 'personal' or 'merchant'", http.StatusBadRequest)
+		writeValidationError(w, "user_type", "user_type must be 'personal' or 'merchant'")
 		return
 	}
 	if body.CurrentStep < 1 || body.CurrentStep > 3 {
-		http.Error(w, "current_step must be between 1 and 3", http.StatusBadRequest)
+		writeValidationError(w, "current_step", "current_step must be between 1 and 3")
 		return
 	}
 
 	var internalUserID *string
 	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		http.Error(w, "Failed to resolve user context", http.StatusInternalServerError)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to resolve user context")
 		return
 	}
 	if existing != nil {
@@ -415,7 +495,7 @@ func (h *OnboardingHandler) HandleSaveProgress(w http.ResponseWriter, r *http.Re
 		body.CurrentStep,
 		body.Payload,
 	); err != nil {
-		http.Error(w, "Failed to save onboarding progress", http.StatusInternalServerError)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to save onboarding progress")
 		return
 	}
 
@@ -426,12 +506,12 @@ func (h *OnboardingHandler) HandleSaveProgress(w http.ResponseWriter, r *http.Re
 func (h *OnboardingHandler) HandleClearProgress(w http.ResponseWriter, r *http.Request) {
 	clerkUserID, ok := GetClerkUserID(r.Context())
 	if !ok || strings.TrimSpace(clerkUserID) == "" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	if err := h.repo.ClearOnboardingProgress(r.Context(), clerkUserID); err != nil {
-		http.Error(w, "Failed to clear onboarding progress", http.StatusInternalServerError)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to clear onboarding progress")
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -668,3 +748,8 @@ func normalizePhone(value string) string {
 
 	return digits
 }
+
+// writeValidationError answers a request whose field failed validation with 422.
+func writeValidationError(w http.ResponseWriter, field, message string) {
+	apierror.WriteValidation(w, message, apierror.FieldError{Field: field, Message: message})
+}
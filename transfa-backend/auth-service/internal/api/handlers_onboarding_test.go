@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+const onboardingRequestBody = `{"user_type":"personal","phone_number":"08181664488","kyc_data":{"firstName":"Ada","lastName":"Obi","fullName":"Ada Obi","addressLine1":"1 Test Street","city":"Lagos","state":"Lagos","postalCode":"100001","country":"NG"}}`
+
+// onboardingRepoStub mirrors the upsert-by-clerk_user_id and pending-event check of
+// the Postgres repository.
+type onboardingRepoStub struct {
+	store.UserRepository
+
+	mu     sync.Mutex
+	users  map[string]*domain.User
+	events []domain.UserCreatedEvent
+
+	// lookups holds every request at FindByClerkUserID until all of them have looked
+	// the user up, so they all miss it and take the create path together.
+	lookups *sync.WaitGroup
+}
+
+func (s *onboardingRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	s.mu.Lock()
+	user, ok := s.users[clerkUserID]
+	s.mu.Unlock()
+
+	s.lookups.Done()
+	s.lookups.Wait()
+
+	if ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (s *onboardingRepoStub) FindByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (s *onboardingRepoStub) UpsertUserAndEnqueueUserCreatedEvent(ctx context.Context, user *domain.User, kycData map[string]interface{}, exchange, routingKey string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.users[user.ClerkUserID]
+	if !ok {
+		existing = &domain.User{ID: "user-1", ClerkUserID: user.ClerkUserID}
+		s.users[user.ClerkUserID] = existing
+	}
+	existing.Email, existing.PhoneNumber = user.Email, user.PhoneNumber
+	if existing.LifecycleStatus == domain.UserPendingOnboarding {
+		existing.Type, existing.LifecycleStatus = user.Type, domain.UserActive
+	}
+
+	for _, event := range s.events {
+		if event.UserID == existing.ID {
+			return existing.ID, nil
+		}
+	}
+	s.events = append(s.events, domain.UserCreatedEvent{MessageID: existing.ID, UserID: existing.ID, KYCData: kycData})
+	return existing.ID, nil
+}
+
+func (s *onboardingRepoStub) UpsertOnboardingProgress(ctx context.Context, clerkUserID string, userID *string, userType string, currentStep int, payload map[string]interface{}) error {
+	return nil
+}
+
+func TestOnboardingHandler_ConcurrentIdenticalRequestsCreateOneUser(t *testing.T) {
+	const requests = 2
+	repo := &onboardingRepoStub{users: map[string]*domain.User{}, lookups: &sync.WaitGroup{}}
+	repo.lookups.Add(requests)
+	handler := NewOnboardingHandler(repo, "")
+
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, requests)
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/onboarding", strings.NewReader(onboardingRequestBody))
+		req.Header.Set("X-User-Email", "ada@example.com")
+		req = req.WithContext(WithClerkUserID(req.Context(), "clerk_user_1"))
+
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder, req *http.Request) {
+			defer wg.Done()
+			handler.ServeHTTP(rec, req)
+		}(recorders[i], req)
+	}
+	wg.Wait()
+
+	for i, rec := range recorders {
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("request %d: expected 201, got %d: %s", i+1, rec.Code, rec.Body.String())
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("request %d: invalid response: %v", i+1, err)
+		}
+		if body["user_id"] != "user-1" {
+			t.Fatalf("request %d: expected user-1, got %q", i+1, body["user_id"])
+		}
+	}
+	if len(repo.users) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(repo.users))
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("expected 1 user.created event, got %d", len(repo.events))
+	}
+	if repo.events[0].MessageID != "user-1" {
+		t.Fatalf("expected message ID to be the user ID, got %q", repo.events[0].MessageID)
+	}
+}
+
+func TestOnboardingHandler_UpsertsOntoPreProvisionedUser(t *testing.T) {
+	email := "ada@example.com"
+	repo := &onboardingRepoStub{
+		users: map[string]*domain.User{
+			"clerk_user_1": {ID: "user-pre", ClerkUserID: "clerk_user_1", Email: &email, Type: domain.PersonalUser, LifecycleStatus: domain.UserPendingOnboarding},
+		},
+		lookups: &sync.WaitGroup{},
+	}
+	repo.lookups.Add(1)
+	handler := NewOnboardingHandler(repo, "")
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/onboarding", strings.NewReader(onboardingRequestBody))
+	req.Header.Set("X-User-Email", email)
+	handler.ServeHTTP(rec, req.WithContext(WithClerkUserID(req.Context(), "clerk_user_1")))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"user_id":"user-pre"`) {
+		t.Fatalf("expected the pre-provisioned user to be reused, got %s", rec.Body.String())
+	}
+	if len(repo.users) != 1 || len(repo.events) != 1 {
+		t.Fatalf("expected 1 user and 1 user.created event, got %d and %d", len(repo.users), len(repo.events))
+	}
+	if status := repo.users["clerk_user_1"].LifecycleStatus; status != domain.UserActive {
+		t.Fatalf("expected the user to become active, got %q", status)
+	}
+}
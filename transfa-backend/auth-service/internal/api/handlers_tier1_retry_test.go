@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+// tier1RetryRepoStub mirrors the status and retry-count checks of the Postgres
+// repository for one user's tier1 stage.
+type tier1RetryRepoStub struct {
+	store.UserRepository
+
+	status     string
+	retryCount int
+	lastKYC    map[string]interface{}
+	events     []map[string]interface{}
+	exchange   string
+	routingKey string
+}
+
+func (s *tier1RetryRepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	return &domain.User{ID: "user-1", ClerkUserID: clerkUserID}, nil
+}
+
+func (s *tier1RetryRepoStub) RetryTier1AndEnqueueEvent(ctx context.Context, userID string, maxRetries int, email, phone, fullName *string, kycData map[string]interface{}, exchange, routingKey string) (int, error) {
+	if s.status != "failed" {
+		return s.retryCount, store.ErrTier1NotFailed
+	}
+	if s.retryCount >= maxRetries {
+		return s.retryCount, store.ErrTier1RetryLimitReached
+	}
+	if kycData == nil {
+		kycData = s.lastKYC
+	}
+	s.status = "processing"
+	s.retryCount++
+	s.lastKYC = kycData
+	s.exchange, s.routingKey = exchange, routingKey
+	s.events = append(s.events, kycData)
+	return s.retryCount, nil
+}
+
+func (s *tier1RetryRepoStub) UpsertOnboardingProgress(ctx context.Context, clerkUserID string, userID *string, userType string, currentStep int, payload map[string]interface{}) error {
+	return nil
+}
+
+func serveTier1Retry(handler *OnboardingHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/onboarding/tier1/retry", strings.NewReader(body))
+	req.Header.Set("X-User-Email", "ada@example.com")
+	req = req.WithContext(WithClerkUserID(req.Context(), "clerk_user_1"))
+	rec := httptest.NewRecorder()
+	handler.HandleTier1Retry(rec, req)
+	return rec
+}
+
+func TestHandleTier1Retry_RequeuesWithinLimit(t *testing.T) {
+	repo := &tier1RetryRepoStub{status: "failed", lastKYC: map[string]interface{}{"firstName": "Ada"}}
+	handler := NewOnboardingHandler(repo, "")
+
+	for want := 1; want <= maxTier1Retries; want++ {
+		body := ""
+		if want == maxTier1Retries {
+			body = onboardingRequestBody
+		}
+		rec := serveTier1Retry(handler, body)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("retry %d: expected 202, got %d: %s", want, rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Status     string `json:"status"`
+			RetryCount int    `json:"retry_count"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("retry %d: invalid response: %v", want, err)
+		}
+		if resp.Status != "tier1_processing" || resp.RetryCount != want {
+			t.Fatalf("retry %d: unexpected response %+v", want, resp)
+		}
+		repo.status = "failed"
+	}
+
+	if len(repo.events) != maxTier1Retries {
+		t.Fatalf("expected %d re-queued events, got %d", maxTier1Retries, len(repo.events))
+	}
+	if repo.exchange != "customer_events" || repo.routingKey != "tier1.verification.requested" {
+		t.Fatalf("expected tier1.verification.requested on customer_events, got %q on %q", repo.routingKey, repo.exchange)
+	}
+	if repo.events[0]["firstName"] != "Ada" || repo.events[0]["email"] != nil {
+		t.Fatalf("expected a retry without a body to reuse the stored details, got %+v", repo.events[0])
+	}
+	resubmitted := repo.events[maxTier1Retries-1]
+	if resubmitted["email"] != "ada@example.com" || resubmitted["userType"] != "personal" || resubmitted["lastName"] != "Obi" {
+		t.Fatalf("expected the re-submitted details to be queued, got %+v", resubmitted)
+	}
+}
+
+func TestHandleTier1Retry_RejectsRetriesPastLimit(t *testing.T) {
+	repo := &tier1RetryRepoStub{status: "failed", retryCount: maxTier1Retries, lastKYC: map[string]interface{}{"firstName": "Ada"}}
+	handler := NewOnboardingHandler(repo, "")
+
+	rec := serveTier1Retry(handler, "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"rate_limited"`) {
+		t.Fatalf("expected a rate limited error code, got %s", rec.Body.String())
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("expected no event once the limit is reached, got %d", len(repo.events))
+	}
+}
+
+func TestHandleTier1Retry_RequiresFailedStatus(t *testing.T) {
+	for _, status := range []string{"processing", "created"} {
+		repo := &tier1RetryRepoStub{status: status}
+		rec := serveTier1Retry(NewOnboardingHandler(repo, ""), "")
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("%s: expected 409, got %d: %s", status, rec.Code, rec.Body.String())
+		}
+		if len(repo.events) != 0 || repo.retryCount != 0 {
+			t.Fatalf("%s: expected nothing queued, got %d events and retry count %d", status, len(repo.events), repo.retryCount)
+		}
+	}
+}
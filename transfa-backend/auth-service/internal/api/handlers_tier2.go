@@ -0,0 +1,340 @@
+package api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/pkg/apierror"
+)
+
+// Steps reported by GET /onboarding/tier2/status.
+const (
+	tier2StepSubmit     = "submit"
+	tier2StepProcessing = "processing"
+	tier2StepResubmit   = "resubmit"
+	tier2StepCompleted  = "completed"
+)
+
+// tier2Fields maps each Tier 2 piece to the request field that carries it.
+var tier2Fields = map[string]string{
+	domain.Tier2DocumentBVN:         "bvn",
+	domain.Tier2DocumentDateOfBirth: "dob",
+	domain.Tier2DocumentGender:      "gender",
+}
+
+type tier2Request struct {
+	Dob    string `json:"dob"`
+	Gender string `json:"gender"`
+	Bvn    string `json:"bvn"`
+}
+
+// values returns the trimmed request fields keyed by Tier 2 piece.
+func (req tier2Request) values() map[string]string {
+	return map[string]string{
+		domain.Tier2DocumentBVN:         strings.TrimSpace(req.Bvn),
+		domain.Tier2DocumentDateOfBirth: strings.TrimSpace(req.Dob),
+		domain.Tier2DocumentGender:      strings.TrimSpace(req.Gender),
+	}
+}
+
+type tier2StatusResponse struct {
+	Status    string                   `json:"status"`
+	Step      string                   `json:"step"`
+	Reason    *string                  `json:"reason,omitempty"`
+	Documents []domain.Tier2Submission `json:"documents"`
+	Resubmit  []string                 `json:"resubmit"`
+}
+
+// normalizeTier2Value validates one Tier 2 piece and returns it in the form Anchor expects.
+func normalizeTier2Value(documentType, value string) (string, error) {
+	switch documentType {
+	case domain.Tier2DocumentBVN:
+		if !bvnPattern.MatchString(value) {
+			return "", errors.New("BVN must be exactly 11 digits")
+		}
+		return value, nil
+	case domain.Tier2DocumentDateOfBirth:
+		return normalizeDateOfBirth(value)
+	case domain.Tier2DocumentGender:
+		genderLower := strings.ToLower(value)
+		if genderLower != "male" && genderLower != "female" {
+			return "", errors.New("Gender must be 'male' or 'female'")
+		}
+		return strings.ToUpper(genderLower[:1]) + genderLower[1:], nil
+	default:
+		return "", errors.New("unknown tier2 document type")
+	}
+}
+
+// HandleTier2Status reports which Tier 2 step the user is on and, when verification
+// was rejected, which pieces they have to send again.
+func (h *OnboardingHandler) HandleTier2Status(w http.ResponseWriter, r *http.Request) {
+	clerkUserID, ok := GetClerkUserID(r.Context())
+	if !ok || strings.TrimSpace(clerkUserID) == "" {
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
+	if err != nil || existing == nil {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
+		return
+	}
+
+	progress, err := h.repo.GetTier2Progress(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("level=error component=tier2 msg=\"failed to load tier2 progress\" user_id=%s err=%v", existing.ID, err)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to load tier2 status")
+		return
+	}
+	step, resubmit, _ := h.planTier2(progress)
+
+	documents := make([]domain.Tier2Submission, 0, len(progress.Submissions))
+	for _, documentType := range domain.Tier2Documents {
+		for _, submission := range progress.Submissions {
+			if submission.DocumentType == documentType {
+				documents = append(documents, submission)
+			}
+		}
+	}
+	if resubmit == nil {
+		resubmit = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(tier2StatusResponse{
+		Status:    progress.Status,
+		Step:      step,
+		Reason:    progress.Reason,
+		Documents: documents,
+		Resubmit:  resubmit,
+	})
+}
+
+// HandleTier2Resubmit accepts only the Tier 2 pieces that were rejected (or cannot be
+// replayed) and re-triggers verification with them plus the stored accepted pieces.
+// Returns 202.
+func (h *OnboardingHandler) HandleTier2Resubmit(w http.ResponseWriter, r *http.Request) {
+	clerkUserID, ok := GetClerkUserID(r.Context())
+	if !ok || strings.TrimSpace(clerkUserID) == "" {
+		apierror.WriteStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	existing, err := h.repo.FindByClerkUserID(r.Context(), clerkUserID)
+	if err != nil || existing == nil {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
+		return
+	}
+	if existing.AnchorCustomerID == nil || *existing.AnchorCustomerID == "" {
+		apierror.WriteStatus(w, http.StatusPreconditionFailed, "Tier 1 verification incomplete")
+		return
+	}
+
+	progress, err := h.repo.GetTier2Progress(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("level=error component=tier2 msg=\"failed to load tier2 progress\" user_id=%s err=%v", existing.ID, err)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to load tier2 status")
+		return
+	}
+	step, resubmit, values := h.planTier2(progress)
+	if step != tier2StepResubmit {
+		apierror.WriteStatus(w, http.StatusConflict, "Tier 2 verification is not awaiting resubmission")
+		return
+	}
+
+	var body tier2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apierror.WriteStatus(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	provided := body.values()
+
+	required := make(map[string]bool, len(resubmit))
+	for _, documentType := range resubmit {
+		required[documentType] = true
+	}
+	var details []apierror.FieldError
+	for _, documentType := range domain.Tier2Documents {
+		field := tier2Fields[documentType]
+		switch {
+		case required[documentType] && provided[documentType] == "":
+			details = append(details, apierror.FieldError{Field: field, Message: field + " is required"})
+		case !required[documentType] && provided[documentType] != "":
+			details = append(details, apierror.FieldError{Field: field, Message: field + " was accepted and must not be resubmitted"})
+		}
+	}
+	if len(details) > 0 {
+		apierror.WriteValidation(w, "Only the rejected Tier 2 details can be resubmitted", details...)
+		return
+	}
+
+	for _, documentType := range resubmit {
+		normalized, err := normalizeTier2Value(documentType, provided[documentType])
+		if err != nil {
+			writeValidationError(w, tier2Fields[documentType], err.Error())
+			return
+		}
+		values[documentType] = normalized
+	}
+
+	if err := h.submitTier2(r, existing, values, resubmit); err != nil {
+		log.Printf("level=error component=tier2 msg=\"failed to queue tier2 resubmission\" user_id=%s err=%v", existing.ID, err)
+		apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to queue tier2 verification")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "tier2_processing", "resubmitted": resubmit})
+}
+
+// submitTier2 records the submitted pieces and queues a verification request carrying
+// the full set of values.
+func (h *OnboardingHandler) submitTier2(r *http.Request, user *domain.User, values map[string]string, submitted []string) error {
+	submissions := make([]domain.Tier2Submission, 0, len(submitted))
+	for _, documentType := range submitted {
+		submission := domain.Tier2Submission{DocumentType: documentType}
+		if len(h.tier2Key) == 32 {
+			encrypted, err := h.encryptTier2Value(values[documentType])
+			if err != nil {
+				return err
+			}
+			submission.ValueEncrypted = &encrypted
+		}
+		submissions = append(submissions, submission)
+	}
+
+	event := domain.Tier2VerificationRequestedEvent{
+		UserID:           user.ID,
+		AnchorCustomerID: *user.AnchorCustomerID,
+		BVN:              values[domain.Tier2DocumentBVN],
+		DateOfBirth:      values[domain.Tier2DocumentDateOfBirth],
+		Gender:           values[domain.Tier2DocumentGender],
+	}
+	return h.repo.SubmitTier2AndEnqueueEvent(
+		r.Context(),
+		user.ID,
+		submissions,
+		"customer_events",
+		"tier2.verification.requested",
+		event,
+	)
+}
+
+// planTier2 works out the user's Tier 2 step. On the resubmit step it also returns the
+// pieces the user must send again and the decrypted values of the accepted pieces that
+// can be replayed to Anchor. A piece whose stored value cannot be decrypted has to be
+// resubmitted too, as does everything when Anchor failed without naming a piece.
+func (h *OnboardingHandler) planTier2(progress *domain.Tier2Progress) (string, []string, map[string]string) {
+	status := strings.ToLower(strings.TrimSpace(progress.Status))
+	switch status {
+	case "completed", "approved":
+		return tier2StepCompleted, nil, nil
+	case "":
+		if len(progress.Submissions) == 0 {
+			return tier2StepSubmit, nil, nil
+		}
+	}
+
+	byType := make(map[string]domain.Tier2Submission, len(progress.Submissions))
+	anyRejected := false
+	for _, submission := range progress.Submissions {
+		byType[submission.DocumentType] = submission
+		if submission.Status == domain.Tier2SubmissionRejected {
+			anyRejected = true
+		}
+	}
+	failed := status == "failed" || status == "rejected" || status == "reenter_information"
+	if !anyRejected && !failed {
+		return tier2StepProcessing, nil, nil
+	}
+
+	resubmit := []string{}
+	values := map[string]string{}
+	for _, documentType := range domain.Tier2Documents {
+		submission, ok := byType[documentType]
+		if !ok || !anyRejected || submission.Status == domain.Tier2SubmissionRejected || submission.ValueEncrypted == nil {
+			resubmit = append(resubmit, documentType)
+			continue
+		}
+		value, err := h.decryptTier2Value(*submission.ValueEncrypted)
+		if err != nil {
+			resubmit = append(resubmit, documentType)
+			continue
+		}
+		values[documentType] = value
+	}
+	return tier2StepResubmit, resubmit, values
+}
+
+func deriveTier2ValueKey(rawSecret string) []byte {
+	secret := strings.TrimSpace(rawSecret)
+	if secret == "" {
+		return nil
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(secret); err == nil && len(decoded) == 32 {
+		return decoded
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+func (h *OnboardingHandler) encryptTier2Value(plain string) (string, error) {
+	if len(h.tier2Key) != 32 {
+		return "", errors.New("tier2 encryption key is invalid")
+	}
+	block, err := aes.NewCipher(h.tier2Key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (h *OnboardingHandler) decryptTier2Value(encrypted string) (string, error) {
+	if len(h.tier2Key) != 32 {
+		return "", errors.New("tier2 encryption key is invalid")
+	}
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(h.tier2Key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonceSize := gcm.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("invalid encrypted payload")
+	}
+	nonce, ciphertext := raw[:nonceSize], raw[nonceSize:]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
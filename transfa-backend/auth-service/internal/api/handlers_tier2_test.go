@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfa/auth-service/internal/domain"
+	"github.com/transfa/auth-service/internal/store"
+)
+
+const testTier2Key = "test-tier2-secret"
+
+// tier2RepoStub keeps one user's Tier 2 progress in memory.
+type tier2RepoStub struct {
+	store.UserRepository
+
+	progress  domain.Tier2Progress
+	submitted []domain.Tier2Submission
+	events    []domain.Tier2VerificationRequestedEvent
+}
+
+func (s *tier2RepoStub) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
+	anchorID := "anchor-1"
+	return &domain.User{ID: "user-1", ClerkUserID: clerkUserID, AnchorCustomerID: &anchorID}, nil
+}
+
+func (s *tier2RepoStub) GetTier2Progress(ctx context.Context, userID string) (*domain.Tier2Progress, error) {
+	progress := s.progress
+	return &progress, nil
+}
+
+func (s *tier2RepoStub) SubmitTier2AndEnqueueEvent(ctx context.Context, userID string, submissions []domain.Tier2Submission, exchange, routingKey string, payload interface{}) error {
+	s.submitted = append(s.submitted, submissions...)
+	s.events = append(s.events, payload.(domain.Tier2VerificationRequestedEvent))
+	return nil
+}
+
+func (s *tier2RepoStub) ClearOnboardingProgress(ctx context.Context, clerkUserID string) error {
+	return nil
+}
+
+// newRejectedDOBRepo returns a user whose date of birth was rejected while BVN and
+// gender were accepted, with values sealed under handler's key.
+func newRejectedDOBRepo(t *testing.T, handler *OnboardingHandler) *tier2RepoStub {
+	t.Helper()
+	reason := "date of birth does not match BVN record"
+	repo := &tier2RepoStub{progress: domain.Tier2Progress{Status: "rejected", Reason: &reason}}
+	for _, piece := range []struct{ documentType, status, value string }{
+		{domain.Tier2DocumentGender, domain.Tier2SubmissionAccepted, "Female"},
+		{domain.Tier2DocumentBVN, domain.Tier2SubmissionAccepted, "22222222222"},
+		{domain.Tier2DocumentDateOfBirth, domain.Tier2SubmissionRejected, "1990-01-01"},
+	} {
+		encrypted, err := handler.encryptTier2Value(piece.value)
+		if err != nil {
+			t.Fatalf("encrypt %s: %v", piece.documentType, err)
+		}
+		repo.progress.Submissions = append(repo.progress.Submissions, domain.Tier2Submission{
+			DocumentType:   piece.documentType,
+			Status:         piece.status,
+			SubmittedAt:    time.Now(),
+			ValueEncrypted: &encrypted,
+		})
+	}
+	repo.progress.Submissions[2].RejectionReason = &reason
+	return repo
+}
+
+func serveTier2(handle http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req = req.WithContext(WithClerkUserID(req.Context(), "clerk_user_1"))
+	rec := httptest.NewRecorder()
+	handle(rec, req)
+	return rec
+}
+
+func TestHandleTier2Status_ReportsRejectedPieces(t *testing.T) {
+	handler := NewOnboardingHandler(nil, testTier2Key)
+	handler.repo = newRejectedDOBRepo(t, handler)
+
+	rec := serveTier2(handler.HandleTier2Status, http.MethodGet, "/onboarding/tier2/status", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body tier2StatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if body.Step != tier2StepResubmit {
+		t.Fatalf("expected the resubmit step, got %q", body.Step)
+	}
+	if len(body.Resubmit) != 1 || body.Resubmit[0] != domain.Tier2DocumentDateOfBirth {
+		t.Fatalf("expected only the date of birth to be resubmitted, got %v", body.Resubmit)
+	}
+	if len(body.Documents) != 3 || body.Documents[0].DocumentType != domain.Tier2DocumentBVN {
+		t.Fatalf("expected documents in collection order, got %+v", body.Documents)
+	}
+	if strings.Contains(rec.Body.String(), "22222222222") || strings.Contains(rec.Body.String(), "value_encrypted") {
+		t.Fatalf("status must not expose submitted values: %s", rec.Body.String())
+	}
+}
+
+func TestHandleTier2Resubmit_AcceptsOnlyRejectedPieces(t *testing.T) {
+	handler := NewOnboardingHandler(nil, testTier2Key)
+	repo := newRejectedDOBRepo(t, handler)
+	handler.repo = repo
+
+	rec := serveTier2(handler.HandleTier2Resubmit, http.MethodPost, "/onboarding/tier2/resubmit", `{"dob":"1990-02-01","bvn":"33333333333"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for an accepted piece, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("expected nothing to be queued, got %v", repo.events)
+	}
+
+	rec = serveTier2(handler.HandleTier2Resubmit, http.MethodPost, "/onboarding/tier2/resubmit", `{"dob":"1990-02-01"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.submitted) != 1 || repo.submitted[0].DocumentType != domain.Tier2DocumentDateOfBirth {
+		t.Fatalf("expected only the date of birth to be recorded, got %+v", repo.submitted)
+	}
+	event := repo.events[0]
+	if event.BVN != "22222222222" || event.Gender != "Female" || event.DateOfBirth != "1990-02-01" {
+		t.Fatalf("expected the accepted pieces to be replayed with the new date of birth, got %+v", event)
+	}
+}
+
+func TestHandleTier2Resubmit_RequiresEveryPieceWithoutKey(t *testing.T) {
+	sealed := NewOnboardingHandler(nil, testTier2Key)
+	repo := newRejectedDOBRepo(t, sealed)
+	handler := NewOnboardingHandler(repo, "")
+
+	rec := serveTier2(handler.HandleTier2Resubmit, http.MethodPost, "/onboarding/tier2/resubmit", `{"dob":"1990-02-01"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 when stored pieces cannot be replayed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveTier2(handler.HandleTier2Resubmit, http.MethodPost, "/onboarding/tier2/resubmit", `{"dob":"1990-02-01","bvn":"22222222222","gender":"female"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.submitted) != 3 || repo.submitted[0].ValueEncrypted != nil {
+		t.Fatalf("expected all pieces recorded without stored values, got %+v", repo.submitted)
+	}
+}
+
+func TestHandleTier2Resubmit_RejectsWhileProcessing(t *testing.T) {
+	repo := &tier2RepoStub{progress: domain.Tier2Progress{
+		Status:      "pending",
+		Submissions: []domain.Tier2Submission{{DocumentType: domain.Tier2DocumentBVN, Status: domain.Tier2SubmissionSubmitted}},
+	}}
+	handler := NewOnboardingHandler(repo, testTier2Key)
+
+	rec := serveTier2(handler.HandleTier2Resubmit, http.MethodPost, "/onboarding/tier2/resubmit", `{"bvn":"22222222222"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleTier2_RecordsEveryPiece(t *testing.T) {
+	repo := &tier2RepoStub{}
+	handler := NewOnboardingHandler(repo, testTier2Key)
+
+	rec := serveTier2(handler.HandleTier2, http.MethodPost, "/onboarding/tier2", `{"bvn":"22222222222","dob":"1990-01-01","gender":"male"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.submitted) != 3 {
+		t.Fatalf("expected three pieces recorded, got %+v", repo.submitted)
+	}
+	for _, submission := range repo.submitted {
+		if submission.ValueEncrypted == nil {
+			t.Fatalf("expected %s to be stored encrypted", submission.DocumentType)
+		}
+	}
+	if plain, err := handler.decryptTier2Value(*repo.submitted[0].ValueEncrypted); err != nil || plain != "22222222222" {
+		t.Fatalf("expected the stored BVN to decrypt, got %q (%v)", plain, err)
+	}
+	if repo.events[0].Gender != "Male" {
+		t.Fatalf("expected a normalized gender, got %q", repo.events[0].Gender)
+	}
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// AccountOnboardingStage is the onboarding_status stage tracking primary account
+	// provisioning after Tier 2.
+	AccountOnboardingStage = "account"
+
+	AccountStageProvisioned = "provisioned"
+	AccountStageRetrying    = "retrying"
+	AccountStageFailed      = "failed"
+)
+
+// OnboardingStatusWriter is the part of the user repository the provisioning
+// consumer needs.
+type OnboardingStatusWriter interface {
+	UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error
+}
+
+// AccountProvisioningEventHandler records account-service's provisioning outcomes on
+// the account onboarding stage, so onboarding status reflects whether the account
+// exists rather than stopping at Tier 2.
+type AccountProvisioningEventHandler struct {
+	repo OnboardingStatusWriter
+}
+
+// NewAccountProvisioningEventHandler creates a new AccountProvisioningEventHandler.
+func NewAccountProvisioningEventHandler(repo OnboardingStatusWriter) *AccountProvisioningEventHandler {
+	return &AccountProvisioningEventHandler{repo: repo}
+}
+
+type accountProvisioningEvent struct {
+	UserID    string `json:"user_id"`
+	Reason    string `json:"reason"`
+	WillRetry bool   `json:"will_retry"`
+}
+
+// HandleAccountProvisionedEvent handles account.provisioned.
+func (h *AccountProvisioningEventHandler) HandleAccountProvisionedEvent(body []byte) bool {
+	return h.record(body, "account.provisioned", func(event accountProvisioningEvent) (string, *string) {
+		return AccountStageProvisioned, nil
+	})
+}
+
+// HandleAccountProvisioningFailedEvent handles account.provisioning_failed.
+func (h *AccountProvisioningEventHandler) HandleAccountProvisioningFailedEvent(body []byte) bool {
+	return h.record(body, "account.provisioning_failed", func(event accountProvisioningEvent) (string, *string) {
+		status := AccountStageFailed
+		if event.WillRetry {
+			status = AccountStageRetrying
+		}
+		var reason *string
+		if trimmed := strings.TrimSpace(event.Reason); trimmed != "" {
+			reason = &trimmed
+		}
+		return status, reason
+	})
+}
+
+func (h *AccountProvisioningEventHandler) record(body []byte, routingKey string, outcome func(accountProvisioningEvent) (string, *string)) bool {
+	var event accountProvisioningEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling %s event: %v", routingKey, err)
+		return true // Acknowledge malformed message.
+	}
+	if strings.TrimSpace(event.UserID) == "" {
+		log.Printf("%s event missing user_id; acking", routingKey)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status, reason := outcome(event)
+	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, AccountOnboardingStage, status, reason); err != nil {
+		log.Printf("ERROR: Failed to record %s for user %s: %v", routingKey, event.UserID, err)
+		return false // Retryable database error.
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type onboardingStatusRecorder struct {
+	stage  string
+	status string
+	reason *string
+	err    error
+}
+
+func (r *onboardingStatusRecorder) UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	r.stage, r.status, r.reason = stage, status, reason
+	return r.err
+}
+
+func TestAccountProvisioningEventHandler_RecordsAccountStage(t *testing.T) {
+	cases := []struct {
+		name       string
+		handle     func(h *AccountProvisioningEventHandler, body []byte) bool
+		body       string
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "provisioned",
+			handle:     (*AccountProvisioningEventHandler).HandleAccountProvisionedEvent,
+			body:       `{"user_id":"user-1","account_id":"acct-1"}`,
+			wantStatus: AccountStageProvisioned,
+		},
+		{
+			name:       "failed with retry",
+			handle:     (*AccountProvisioningEventHandler).HandleAccountProvisioningFailedEvent,
+			body:       `{"user_id":"user-1","reason":"Failed to create Anchor deposit account","will_retry":true}`,
+			wantStatus: AccountStageRetrying,
+			wantReason: "Failed to create Anchor deposit account",
+		},
+		{
+			name:       "failed for good",
+			handle:     (*AccountProvisioningEventHandler).HandleAccountProvisioningFailedEvent,
+			body:       `{"user_id":"user-1","reason":"Failed to create Anchor deposit account","will_retry":false}`,
+			wantStatus: AccountStageFailed,
+			wantReason: "Failed to create Anchor deposit account",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &onboardingStatusRecorder{}
+			if !tc.handle(NewAccountProvisioningEventHandler(repo), []byte(tc.body)) {
+				t.Fatalf("expected the event to be acknowledged")
+			}
+			gotReason := ""
+			if repo.reason != nil {
+				gotReason = *repo.reason
+			}
+			if repo.stage != AccountOnboardingStage || repo.status != tc.wantStatus || gotReason != tc.wantReason {
+				t.Fatalf("expected %s %s (%q), got %s %s (%q)", AccountOnboardingStage, tc.wantStatus, tc.wantReason, repo.stage, repo.status, gotReason)
+			}
+		})
+	}
+}
+
+func TestAccountProvisioningEventHandler_AcksBadEventsAndRequeuesOnDatabaseError(t *testing.T) {
+	repo := &onboardingStatusRecorder{}
+	handler := NewAccountProvisioningEventHandler(repo)
+
+	for _, body := range []string{`not json`, `{"reason":"missing user"}`} {
+		if !handler.HandleAccountProvisioningFailedEvent([]byte(body)) {
+			t.Fatalf("expected %q to be acknowledged", body)
+		}
+	}
+	if repo.stage != "" {
+		t.Fatalf("expected nothing recorded for invalid events, got %s", repo.stage)
+	}
+
+	repo.err = errors.New("db down")
+	if handler.HandleAccountProvisionedEvent([]byte(`{"user_id":"user-1"}`)) {
+		t.Fatalf("expected a database error to requeue the event")
+	}
+}
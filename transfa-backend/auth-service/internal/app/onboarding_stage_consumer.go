@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+// OnboardingStageChangedRoutingKey is the customer_events routing key other services
+// publish onboarding stage changes on.
+const OnboardingStageChangedRoutingKey = "onboarding.stage.changed"
+
+// onboardingStages are the stages other services may report.
+var onboardingStages = map[string]bool{
+	"tier1":                true,
+	"tier2":                true,
+	"tier3":                true,
+	AccountOnboardingStage: true,
+}
+
+// OnboardingStageApplier is the part of the user repository the stage consumer needs.
+type OnboardingStageApplier interface {
+	ApplyOnboardingStageChange(ctx context.Context, userID, stage, status string, reason *string, occurredAt time.Time) (bool, error)
+}
+
+// OnboardingStageEventHandler keeps onboarding_status from the onboarding.stage.changed
+// events of customer-service and account-service, so auth-service is the only
+// service that needs to own the table.
+type OnboardingStageEventHandler struct {
+	repo OnboardingStageApplier
+}
+
+// NewOnboardingStageEventHandler creates a new OnboardingStageEventHandler.
+func NewOnboardingStageEventHandler(repo OnboardingStageApplier) *OnboardingStageEventHandler {
+	return &OnboardingStageEventHandler{repo: repo}
+}
+
+// HandleOnboardingStageChangedEvent handles onboarding.stage.changed.
+func (h *OnboardingStageEventHandler) HandleOnboardingStageChangedEvent(body []byte) bool {
+	var event domain.OnboardingStageChangedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling %s event: %v", OnboardingStageChangedRoutingKey, err)
+		return true // Acknowledge malformed message.
+	}
+
+	userID := strings.TrimSpace(event.UserID)
+	stage := strings.ToLower(strings.TrimSpace(event.Stage))
+	status := strings.ToLower(strings.TrimSpace(event.Status))
+	if _, err := uuid.Parse(userID); err != nil || !onboardingStages[stage] || status == "" {
+		log.Printf("%s event with invalid user_id %q, stage %q or status %q; acking", OnboardingStageChangedRoutingKey, event.UserID, event.Stage, event.Status)
+		return true
+	}
+	var reason *string
+	if event.Reason != nil {
+		if trimmed := strings.TrimSpace(*event.Reason); trimmed != "" {
+			reason = &trimmed
+		}
+	}
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	applied, err := h.repo.ApplyOnboardingStageChange(ctx, userID, stage, status, reason, occurredAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to apply %s %s for user %s: %v", stage, status, userID, err)
+		return false // Retryable database error.
+	}
+	if !applied {
+		log.Printf("Skipped %s %s for user %s: the user is gone or a newer status is recorded", stage, status, userID)
+	}
+	return true
+}
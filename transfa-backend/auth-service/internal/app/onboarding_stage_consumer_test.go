@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const stageUserID = "3f2b8c1e-7a4d-4e2f-9b6a-1c2d3e4f5a6b"
+
+type appliedStageChange struct {
+	userID, stage, status string
+	reason                *string
+	occurredAt            time.Time
+}
+
+type stageApplierStub struct {
+	changes []appliedStageChange
+	applied bool
+	err     error
+}
+
+func (s *stageApplierStub) ApplyOnboardingStageChange(ctx context.Context, userID, stage, status string, reason *string, occurredAt time.Time) (bool, error) {
+	s.changes = append(s.changes, appliedStageChange{userID, stage, status, reason, occurredAt})
+	return s.applied, s.err
+}
+
+func TestOnboardingStageEventHandler_AppliesStageChange(t *testing.T) {
+	repo := &stageApplierStub{applied: true}
+	handler := NewOnboardingStageEventHandler(repo)
+
+	body := `{"user_id":"` + stageUserID + `","stage":"Tier1","status":"FAILED","reason":"  BVN mismatch ","occurred_at":"2026-04-01T10:00:00Z"}`
+	if !handler.HandleOnboardingStageChangedEvent([]byte(body)) {
+		t.Fatal("expected the event to be acknowledged")
+	}
+	if len(repo.changes) != 1 {
+		t.Fatalf("expected one applied change, got %d", len(repo.changes))
+	}
+	change := repo.changes[0]
+	want := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	if change.userID != stageUserID || change.stage != "tier1" || change.status != "failed" || change.reason == nil || *change.reason != "BVN mismatch" || !change.occurredAt.Equal(want) {
+		t.Fatalf("unexpected change %+v", change)
+	}
+
+	// A stale event is acknowledged even though nothing changed.
+	repo.applied = false
+	if !handler.HandleOnboardingStageChangedEvent([]byte(body)) {
+		t.Fatal("expected a stale event to be acknowledged")
+	}
+}
+
+func TestOnboardingStageEventHandler_AcksInvalidEventsAndRequeuesOnDatabaseError(t *testing.T) {
+	repo := &stageApplierStub{applied: true}
+	handler := NewOnboardingStageEventHandler(repo)
+
+	for _, body := range []string{
+		`not json`,
+		`{"user_id":"user-1","stage":"tier1","status":"created"}`,
+		`{"user_id":"` + stageUserID + `","stage":"tier9","status":"created"}`,
+		`{"user_id":"` + stageUserID + `","stage":"tier1","status":" "}`,
+	} {
+		if !handler.HandleOnboardingStageChangedEvent([]byte(body)) {
+			t.Fatalf("expected %q to be acknowledged", body)
+		}
+	}
+	if len(repo.changes) != 0 {
+		t.Fatalf("expected nothing applied for invalid events, got %+v", repo.changes)
+	}
+
+	repo.err = errors.New("db down")
+	if handler.HandleOnboardingStageChangedEvent([]byte(`{"user_id":"` + stageUserID + `","stage":"tier2","status":"completed"}`)) {
+		t.Fatal("expected a database error to requeue the event")
+	}
+	if repo.changes[0].occurredAt.IsZero() {
+		t.Fatal("expected a missing occurred_at to default to now")
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config stores all configuration for the application.
@@ -16,8 +18,26 @@ type Config struct {
 	ClerkJWKSURL            string `mapstructure:"CLERK_JWKS_URL"`
 	ClerkAudience           string `mapstructure:"CLERK_AUDIENCE"`
 	ClerkIssuer             string `mapstructure:"CLERK_ISSUER"`
+	ClerkWebhookSecret      string `mapstructure:"CLERK_WEBHOOK_SECRET"`
+	Tier2EncryptionKey      string `mapstructure:"TIER2_ENCRYPTION_KEY"`
 	AllowedOrigins          string `mapstructure:"ALLOWED_ORIGINS"`
+	AppEnv                  string `mapstructure:"APP_ENV"`
+	InternalAPIKey          string `mapstructure:"INTERNAL_API_KEY"`
+	TransactionServiceURL   string `mapstructure:"TRANSACTION_SERVICE_URL"`
+	SubscriptionServiceURL  string `mapstructure:"SUBSCRIPTION_SERVICE_URL"`
 	AllowInsecureHeaderAuth bool   `mapstructure:"ALLOW_INSECURE_HEADER_AUTH"`
+	EventSigningSecret      string `mapstructure:"EVENT_SIGNING_SECRET"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        10,
+	MinConns:        2,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -41,8 +61,15 @@ func LoadConfig() (config Config, err error) {
 	_ = viper.BindEnv("CLERK_JWKS_URL")
 	_ = viper.BindEnv("CLERK_AUDIENCE")
 	_ = viper.BindEnv("CLERK_ISSUER")
+	_ = viper.BindEnv("CLERK_WEBHOOK_SECRET")
+	_ = viper.BindEnv("TIER2_ENCRYPTION_KEY")
 	_ = viper.BindEnv("ALLOWED_ORIGINS")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("INTERNAL_API_KEY")
+	_ = viper.BindEnv("TRANSACTION_SERVICE_URL")
+	_ = viper.BindEnv("SUBSCRIPTION_SERVICE_URL")
 	_ = viper.BindEnv("ALLOW_INSECURE_HEADER_AUTH")
+	_ = viper.BindEnv("EVENT_SIGNING_SECRET")
 
 	// Read the config file (optional)
 	err = viper.ReadInConfig()
@@ -60,5 +87,6 @@ func LoadConfig() (config Config, err error) {
 		log.Fatalf("Unable to decode into struct: %v", err)
 	}
 
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Tier2VerificationRequestedEvent struct {
 	UserID           string `json:"user_id"`
 	AnchorCustomerID string `json:"anchor_customer_id"`
@@ -21,3 +23,50 @@ type Tier1ProfileUpdateRequestedEvent struct {
 	AnchorCustomerID string                 `json:"anchor_customer_id"`
 	KYCData          map[string]interface{} `json:"kyc_data"`
 }
+
+// Tier1VerificationRequestedEvent is published when a user retries a failed tier1
+// verification. MessageID is unique per retry so customer-service does not drop it as
+// a redelivery of the original user.created event. AnchorCustomerID is set when the
+// user was already linked to an Anchor customer before tier1 failed.
+type Tier1VerificationRequestedEvent struct {
+	MessageID        string                 `json:"message_id"`
+	UserID           string                 `json:"user_id"`
+	AnchorCustomerID string                 `json:"anchor_customer_id,omitempty"`
+	KYCData          map[string]interface{} `json:"kyc_data"`
+}
+
+// UserClosedEvent is published once a user has closed their account so downstream
+// services can deactivate the Anchor customer and its deposit accounts.
+type UserClosedEvent struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id,omitempty"`
+	ClosedAt         time.Time `json:"closed_at"`
+}
+
+// UsernameChangedEvent is published when a user renames themselves so other services
+// can keep resolving the old username during the grace period.
+type UsernameChangedEvent struct {
+	UserID      string    `json:"user_id"`
+	OldUsername string    `json:"old_username"`
+	NewUsername string    `json:"new_username"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// ClerkUserDeletedEvent is published when Clerk reports a user as deleted so other
+// services can clean up what they hold for the user.
+type ClerkUserDeletedEvent struct {
+	UserID      string    `json:"user_id"`
+	ClerkUserID string    `json:"clerk_user_id"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
+
+// OnboardingStageChangedEvent is published by customer-service and account-service
+// whenever they move a user's onboarding stage. auth-service applies it to
+// onboarding_status, which it owns.
+type OnboardingStageChangedEvent struct {
+	UserID     string    `json:"user_id"`
+	Stage      string    `json:"stage"`
+	Status     string    `json:"status"`
+	Reason     *string   `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
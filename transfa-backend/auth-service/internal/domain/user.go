@@ -10,20 +10,32 @@ const (
 	MerchantUser UserType = "merchant"
 )
 
+// Lifecycle statuses of a users row. A row pre-provisioned from Clerk's user.created
+// webhook stays pending_onboarding until /onboarding upserts onto it.
+const (
+	UserPendingOnboarding = "pending_onboarding"
+	UserActive            = "active"
+	UserPendingCleanup    = "pending_cleanup"
+)
+
 // User represents the core user model in our system.
 type User struct {
-	ID                string    `json:"id"`
-	ClerkUserID       string    `json:"clerk_user_id"`
-	AnchorCustomerID  *string   `json:"anchor_customer_id,omitempty"` // Pointer to handle null
-	Username          *string   `json:"username,omitempty"`
-	Email             *string   `json:"email,omitempty"`
-	PhoneNumber       *string   `json:"phone_number,omitempty"`
-	FullName          *string   `json:"full_name,omitempty"`
-	ProfilePictureURL *string   `json:"profile_picture_url,omitempty"`
-	Type              UserType  `json:"user_type"`
-	AllowSending      bool      `json:"allow_sending"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                string     `json:"id"`
+	ClerkUserID       string     `json:"clerk_user_id"`
+	AnchorCustomerID  *string    `json:"anchor_customer_id,omitempty"` // Pointer to handle null
+	Username          *string    `json:"username,omitempty"`
+	Email             *string    `json:"email,omitempty"`
+	PhoneNumber       *string    `json:"phone_number,omitempty"`
+	FullName          *string    `json:"full_name,omitempty"`
+	ProfilePictureURL *string    `json:"profile_picture_url,omitempty"`
+	Type              UserType   `json:"user_type"`
+	AllowSending      bool       `json:"allow_sending"`
+	AllowReceiving    bool       `json:"allow_receiving"`
+	IsFrozen          bool       `json:"is_frozen"` // Set while compliance has frozen the user's transfers
+	LifecycleStatus   string     `json:"lifecycle_status,omitempty"`
+	ClosedAt          *time.Time `json:"closed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // OnboardingRequest represents the data received from the client during the onboarding process.
@@ -36,6 +48,71 @@ type OnboardingRequest struct {
 
 // UserCreatedEvent represents the payload published to RabbitMQ after a user is created.
 type UserCreatedEvent struct {
-	UserID  string                 `json:"user_id"` // Our internal UUID
-	KYCData map[string]interface{} `json:"kyc_data"`
+	MessageID string                 `json:"message_id"` // Deterministic: always the user ID
+	UserID    string                 `json:"user_id"`    // Our internal UUID
+	KYCData   map[string]interface{} `json:"kyc_data"`
+}
+
+// Reasons reported by the account closure eligibility check.
+const (
+	ClosureBlockedWalletBalance       = "wallet_balance_not_zero"
+	ClosureBlockedPotBalance          = "pot_balance_not_zero"
+	ClosureBlockedPendingTransactions = "pending_transactions"
+	ClosureBlockedActiveMoneyDrops    = "active_money_drops"
+)
+
+// AccountClosureEligibility reports whether a user can close their account right now.
+type AccountClosureEligibility struct {
+	Eligible        bool     `json:"eligible"`
+	BlockingReasons []string `json:"blocking_reasons"`
+}
+
+// StuckOnboardingStatus is an onboarding_status row that has sat in a non-terminal
+// state for longer than expected.
+type StuckOnboardingStatus struct {
+	UserID    string    `json:"user_id"`
+	Stage     string    `json:"stage"`
+	Status    string    `json:"current_status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserContact is what other services need to reach a user outside the app, such as
+// the notification-service sending email receipts.
+type UserContact struct {
+	UserID   string  `json:"user_id"`
+	Email    *string `json:"email,omitempty"`
+	Username *string `json:"username,omitempty"`
+	FullName *string `json:"full_name,omitempty"`
+}
+
+// Pieces of a Tier 2 submission that Anchor verifies, and their review states.
+const (
+	Tier2DocumentBVN         = "bvn"
+	Tier2DocumentDateOfBirth = "date_of_birth"
+	Tier2DocumentGender      = "gender"
+
+	Tier2SubmissionSubmitted = "submitted"
+	Tier2SubmissionAccepted  = "accepted"
+	Tier2SubmissionRejected  = "rejected"
+)
+
+// Tier2Documents lists every Tier 2 piece in the order the app collects them.
+var Tier2Documents = []string{Tier2DocumentBVN, Tier2DocumentDateOfBirth, Tier2DocumentGender}
+
+// Tier2Submission is the latest submission of one Tier 2 piece and its outcome.
+type Tier2Submission struct {
+	DocumentType         string     `json:"document_type"`
+	Status               string     `json:"status"`
+	RejectionReason      *string    `json:"rejection_reason,omitempty"`
+	AnchorVerificationID *string    `json:"anchor_verification_id,omitempty"`
+	SubmittedAt          time.Time  `json:"submitted_at"`
+	ReviewedAt           *time.Time `json:"reviewed_at,omitempty"`
+	ValueEncrypted       *string    `json:"-"`
+}
+
+// Tier2Progress is the tier2 onboarding_status together with its per-piece submissions.
+type Tier2Progress struct {
+	Status      string
+	Reason      *string
+	Submissions []Tier2Submission
 }
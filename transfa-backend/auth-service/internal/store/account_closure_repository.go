@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+var (
+	ErrUserAlreadyClosed = errors.New("account is already closed")
+)
+
+// AccountClosureBlockedError is returned when a close is attempted while one or more
+// blocking conditions still hold.
+type AccountClosureBlockedError struct {
+	Reasons []string
+}
+
+func (e *AccountClosureBlockedError) Error() string {
+	return "account cannot be closed yet"
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// GetAccountClosureEligibility lists the conditions that currently prevent the user
+// from closing their account. It does not change anything.
+func (r *PostgresUserRepository) GetAccountClosureEligibility(ctx context.Context, userID string) (*domain.AccountClosureEligibility, error) {
+	reasons, err := accountClosureBlockers(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AccountClosureEligibility{
+		Eligible:        len(reasons) == 0,
+		BlockingReasons: reasons,
+	}, nil
+}
+
+// CloseUserAndEnqueueEvent marks the user closed and enqueues the user.closed event in
+// the same transaction. The users row is locked before the blockers are re-checked so
+// the check cannot race a concurrent transfer that re-reads the user's flags.
+func (r *PostgresUserRepository) CloseUserAndEnqueueEvent(ctx context.Context, userID, exchange, routingKey string) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var anchorCustomerID *string
+	var closedAt *time.Time
+	if err := tx.QueryRow(ctx, `
+		SELECT anchor_customer_id, closed_at
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`, userID).Scan(&anchorCustomerID, &closedAt); err != nil {
+		return time.Time{}, err
+	}
+	if closedAt != nil {
+		return time.Time{}, ErrUserAlreadyClosed
+	}
+
+	reasons, err := accountClosureBlockers(ctx, tx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(reasons) > 0 {
+		return time.Time{}, &AccountClosureBlockedError{Reasons: reasons}
+	}
+
+	var closed time.Time
+	if err := tx.QueryRow(ctx, `
+		UPDATE users
+		SET closed_at = NOW(), allow_sending = FALSE, allow_receiving = FALSE, updated_at = NOW()
+		WHERE id = $1
+		RETURNING closed_at
+	`, userID).Scan(&closed); err != nil {
+		return time.Time{}, err
+	}
+
+	event := domain.UserClosedEvent{
+		UserID:   userID,
+		ClosedAt: closed,
+	}
+	if anchorCustomerID != nil {
+		event.AnchorCustomerID = *anchorCustomerID
+	}
+	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, err
+	}
+	return closed, nil
+}
+
+// accountClosureBlockers lists what keeps userID from closing the account. A
+// transaction counts while it is pending or still settling in processing.
+func accountClosureBlockers(ctx context.Context, db rowQuerier, userID string) ([]string, error) {
+	var walletBalance, potBalance, pendingTransactions, activeMoneyDrops bool
+	err := db.QueryRow(ctx, `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM accounts
+				WHERE user_id = $1 AND account_type = 'primary' AND status <> 'closed' AND balance <> 0
+			),
+			EXISTS (
+				SELECT 1 FROM accounts
+				WHERE user_id = $1 AND account_type = 'pot' AND status <> 'closed' AND balance <> 0
+			),
+			EXISTS (
+				SELECT 1 FROM transactions
+				WHERE (sender_id = $1 OR recipient_id = $1) AND status IN ('pending', 'processing')
+			),
+			EXISTS (
+				SELECT 1 FROM money_drops
+				WHERE creator_id = $1 AND status = 'active'
+			)
+	`, userID).Scan(&walletBalance, &potBalance, &pendingTransactions, &activeMoneyDrops)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make([]string, 0, 4)
+	if walletBalance {
+		reasons = append(reasons, domain.ClosureBlockedWalletBalance)
+	}
+	if potBalance {
+		reasons = append(reasons, domain.ClosureBlockedPotBalance)
+	}
+	if pendingTransactions {
+		reasons = append(reasons, domain.ClosureBlockedPendingTransactions)
+	}
+	if activeMoneyDrops {
+		reasons = append(reasons, domain.ClosureBlockedActiveMoneyDrops)
+	}
+	return reasons, nil
+}
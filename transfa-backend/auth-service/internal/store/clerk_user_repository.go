@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+// PreProvisionClerkUser inserts a pending_onboarding users row for a Clerk user that
+// has not onboarded yet. user_type is a placeholder until onboarding sets it. It
+// returns false when the Clerk user already has a row or the email belongs to
+// another user; onboarding resolves either case.
+func (r *PostgresUserRepository) PreProvisionClerkUser(ctx context.Context, clerkUserID string, email *string) (bool, error) {
+	var userID string
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO users (clerk_user_id, email, user_type, allow_sending, lifecycle_status)
+		VALUES ($1, $2, 'personal', TRUE, 'pending_onboarding')
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`, clerkUserID, email).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SyncClerkUserEmail stores the Clerk user's current primary email. It returns false
+// when there is no row for the Clerk user or the email is unchanged.
+func (r *PostgresUserRepository) SyncClerkUserEmail(ctx context.Context, clerkUserID string, email string) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE users
+		SET email = $2, updated_at = NOW()
+		WHERE clerk_user_id = $1 AND email IS DISTINCT FROM $2
+	`, clerkUserID, email)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// MarkClerkUserDeletedAndEnqueueEvent moves the Clerk user's row to pending_cleanup
+// and queues the deletion event in the same transaction. It returns nil when there is
+// no row for the Clerk user or it was already marked, so redelivered webhooks do not
+// publish the event twice.
+func (r *PostgresUserRepository) MarkClerkUserDeletedAndEnqueueEvent(ctx context.Context, clerkUserID, exchange, routingKey string) (*domain.ClerkUserDeletedEvent, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	event := domain.ClerkUserDeletedEvent{ClerkUserID: clerkUserID}
+	err = tx.QueryRow(ctx, `
+		UPDATE users
+		SET lifecycle_status = 'pending_cleanup', clerk_deleted_at = NOW(), updated_at = NOW()
+		WHERE clerk_user_id = $1 AND lifecycle_status <> 'pending_cleanup'
+		RETURNING id, clerk_deleted_at
+	`, clerkUserID).Scan(&event.UserID, &event.DeletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -26,7 +27,13 @@ type OutboxMessage struct {
 	Attempts   int
 }
 
-func (r *PostgresUserRepository) CreateUserAndEnqueueUserCreatedEvent(
+// UpsertUserAndEnqueueUserCreatedEvent creates the user keyed on clerk_user_id, or
+// refreshes the contact details of the row a concurrent request already inserted, and
+// queues user.created. Retried onboarding requests therefore resolve to the same user
+// and never queue a second event while the first is still waiting to be published.
+// A row pre-provisioned from Clerk's user.created webhook also takes the user type and
+// becomes active.
+func (r *PostgresUserRepository) UpsertUserAndEnqueueUserCreatedEvent(
 	ctx context.Context,
 	user *domain.User,
 	kycData map[string]interface{},
@@ -42,6 +49,15 @@ func (r *PostgresUserRepository) CreateUserAndEnqueueUserCreatedEvent(
 	query := `
 		INSERT INTO users (clerk_user_id, username, email, phone_number, full_name, user_type, allow_sending)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (clerk_user_id)
+		DO UPDATE SET
+			email = EXCLUDED.email,
+			phone_number = EXCLUDED.phone_number,
+			full_name = COALESCE(EXCLUDED.full_name, users.full_name),
+			user_type = CASE WHEN users.lifecycle_status = 'pending_onboarding' THEN EXCLUDED.user_type ELSE users.user_type END,
+			allow_sending = CASE WHEN users.lifecycle_status = 'pending_onboarding' THEN EXCLUDED.allow_sending ELSE users.allow_sending END,
+			lifecycle_status = CASE WHEN users.lifecycle_status = 'pending_onboarding' THEN 'active' ELSE users.lifecycle_status END,
+			updated_at = NOW()
 		RETURNING id
 	`
 	var userID string
@@ -60,11 +76,7 @@ func (r *PostgresUserRepository) CreateUserAndEnqueueUserCreatedEvent(
 		return "", err
 	}
 
-	event := domain.UserCreatedEvent{
-		UserID:  userID,
-		KYCData: kycData,
-	}
-	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+	if err := enqueueUserCreatedEventTx(ctx, tx, userID, kycData, exchange, routingKey); err != nil {
 		return "", err
 	}
 
@@ -94,12 +106,7 @@ func (r *PostgresUserRepository) UpdateUserProfileAndEnqueueUserCreatedEvent(
 	if err := updateAnchorCustomerInfoTx(ctx, tx, userID, "", fullName); err != nil {
 		return err
 	}
-
-	event := domain.UserCreatedEvent{
-		UserID:  userID,
-		KYCData: kycData,
-	}
-	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+	if err := enqueueUserCreatedEventTx(ctx, tx, userID, kycData, exchange, routingKey); err != nil {
 		return err
 	}
 
@@ -131,6 +138,37 @@ func (r *PostgresUserRepository) UpsertOnboardingStatusAndEnqueueEvent(
 	return tx.Commit(ctx)
 }
 
+// terminalOnboardingStatuses are the statuses an onboarding stage does not move on
+// from by itself. Tier1 finishes as "created" rather than "completed".
+var terminalOnboardingStatuses = []string{"completed", "created", "failed"}
+
+// ListStuckOnboardingStatuses returns onboarding stages that are still in a
+// non-terminal status and have not changed since updatedBefore, oldest first.
+func (r *PostgresUserRepository) ListStuckOnboardingStatuses(ctx context.Context, updatedBefore time.Time) ([]domain.StuckOnboardingStatus, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id::text, stage, status, updated_at
+		FROM onboarding_status
+		WHERE updated_at < $1
+		  AND NOT (status = ANY($2))
+		ORDER BY updated_at ASC
+		LIMIT 1000
+	`, updatedBefore, terminalOnboardingStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stuck := make([]domain.StuckOnboardingStatus, 0)
+	for rows.Next() {
+		var item domain.StuckOnboardingStatus
+		if err := rows.Scan(&item.UserID, &item.Stage, &item.Status, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		stuck = append(stuck, item)
+	}
+	return stuck, rows.Err()
+}
+
 func (r *PostgresUserRepository) UpdateTier1ProfileAndEnqueueEvent(
 	ctx context.Context,
 	userID string,
@@ -354,6 +392,43 @@ func enqueueEventTx(ctx context.Context, tx pgx.Tx, exchange, routingKey string,
 	return nil
 }
 
+// enqueueUserCreatedEventTx queues user.created for userID unless an earlier one is
+// still waiting in the outbox. The caller must already hold the users row lock so
+// concurrent onboarding requests serialize on this check. The message ID is the user
+// ID, which lets customer-service drop duplicate deliveries before calling Anchor.
+func enqueueUserCreatedEventTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	kycData map[string]interface{},
+	exchange string,
+	routingKey string,
+) error {
+	var pending bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM event_outbox
+			WHERE routing_key = $1
+			  AND status IN ('pending', 'processing')
+			  AND payload->>'user_id' = $2
+		)
+	`, strings.TrimSpace(routingKey), userID).Scan(&pending); err != nil {
+		return fmt.Errorf("failed to check pending user.created event: %w", err)
+	}
+	if pending {
+		log.Printf("user.created already queued for user %s; skipping duplicate enqueue", userID)
+		return nil
+	}
+
+	event := domain.UserCreatedEvent{
+		MessageID: userID,
+		UserID:    userID,
+		KYCData:   kycData,
+	}
+	return enqueueEventTx(ctx, tx, exchange, routingKey, event)
+}
+
 func upsertOnboardingStatusTx(ctx context.Context, tx pgx.Tx, userID, stage, status string, reason *string) error {
 	_, err := tx.Exec(ctx, `
 		INSERT INTO onboarding_status (user_id, stage, status, reason)
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+var (
+	ErrTier1NotFailed           = errors.New("tier1 verification has not failed")
+	ErrTier1RetryLimitReached   = errors.New("tier1 verification retry limit reached")
+	ErrTier1DetailsNotAvailable = errors.New("no earlier tier1 details to retry with")
+)
+
+// RetryTier1AndEnqueueEvent moves a failed tier1 stage back to processing, counts the
+// retry and queues a Tier1VerificationRequestedEvent under routingKey in one
+// transaction. kycData replaces the details of the earlier attempt when given;
+// otherwise the details of the last queued user.created or retry event are reused. It
+// returns the stage's retry count after this retry.
+func (r *PostgresUserRepository) RetryTier1AndEnqueueEvent(
+	ctx context.Context,
+	userID string,
+	maxRetries int,
+	email, phone, fullName *string,
+	kycData map[string]interface{},
+	exchange string,
+	routingKey string,
+) (int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The users row lock serializes retries with concurrent onboarding requests.
+	var anchorCustomerID *string
+	if err := tx.QueryRow(ctx, `SELECT anchor_customer_id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&anchorCustomerID); err != nil {
+		return 0, err
+	}
+
+	var (
+		status     string
+		retryCount int
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT status, retry_count
+		FROM onboarding_status
+		WHERE user_id = $1 AND stage = 'tier1'
+		FOR UPDATE
+	`, userID).Scan(&status, &retryCount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrTier1NotFailed
+	}
+	if err != nil {
+		return 0, err
+	}
+	if strings.ToLower(strings.TrimSpace(status)) != "failed" {
+		return retryCount, ErrTier1NotFailed
+	}
+	if retryCount >= maxRetries {
+		return retryCount, ErrTier1RetryLimitReached
+	}
+
+	if kycData == nil {
+		var blob []byte
+		err := tx.QueryRow(ctx, `
+			SELECT payload->'kyc_data'
+			FROM event_outbox
+			WHERE routing_key IN ('user.created', $1)
+			  AND payload->>'user_id' = $2
+			  AND jsonb_typeof(payload->'kyc_data') = 'object'
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1
+		`, strings.TrimSpace(routingKey), userID).Scan(&blob)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return retryCount, ErrTier1DetailsNotAvailable
+		}
+		if err != nil {
+			return retryCount, err
+		}
+		if err := json.Unmarshal(blob, &kycData); err != nil || len(kycData) == 0 {
+			return retryCount, ErrTier1DetailsNotAvailable
+		}
+	} else {
+		if err := updateContactTx(ctx, tx, userID, email, phone); err != nil {
+			return retryCount, err
+		}
+		if err := updateAnchorCustomerInfoTx(ctx, tx, userID, "", fullName); err != nil {
+			return retryCount, err
+		}
+	}
+
+	if err := tx.QueryRow(ctx, `
+		UPDATE onboarding_status
+		SET status = 'processing', reason = NULL, retry_count = retry_count + 1, updated_at = NOW()
+		WHERE user_id = $1 AND stage = 'tier1'
+		RETURNING retry_count
+	`, userID).Scan(&retryCount); err != nil {
+		return 0, fmt.Errorf("failed to record tier1 retry: %w", err)
+	}
+
+	event := domain.Tier1VerificationRequestedEvent{
+		MessageID: fmt.Sprintf("%s:tier1-retry:%d", userID, retryCount),
+		UserID:    userID,
+		KYCData:   kycData,
+	}
+	if anchorCustomerID != nil {
+		event.AnchorCustomerID = *anchorCustomerID
+	}
+	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return retryCount, nil
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+// SubmitTier2AndEnqueueEvent moves tier2 back to pending, records the submitted pieces
+// as awaiting review and queues the verification request in one transaction. Pieces
+// not in submissions keep their previous outcome.
+func (r *PostgresUserRepository) SubmitTier2AndEnqueueEvent(
+	ctx context.Context,
+	userID string,
+	submissions []domain.Tier2Submission,
+	exchange string,
+	routingKey string,
+	payload interface{},
+) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := upsertOnboardingStatusTx(ctx, tx, userID, "tier2", "pending", nil); err != nil {
+		return err
+	}
+	for _, submission := range submissions {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO tier2_submissions (user_id, document_type, status, value_encrypted)
+			VALUES ($1, $2, 'submitted', $3)
+			ON CONFLICT (user_id, document_type)
+			DO UPDATE SET
+				status = 'submitted',
+				value_encrypted = EXCLUDED.value_encrypted,
+				rejection_reason = NULL,
+				anchor_verification_id = NULL,
+				submitted_at = NOW(),
+				reviewed_at = NULL,
+				updated_at = NOW()
+		`, userID, submission.DocumentType, submission.ValueEncrypted); err != nil {
+			return err
+		}
+	}
+	if err := enqueueEventTx(ctx, tx, exchange, routingKey, payload); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetTier2Progress returns the user's tier2 onboarding status and the latest
+// submission of each piece. Status is empty when Tier 2 was never started.
+func (r *PostgresUserRepository) GetTier2Progress(ctx context.Context, userID string) (*domain.Tier2Progress, error) {
+	progress := &domain.Tier2Progress{Submissions: []domain.Tier2Submission{}}
+	err := r.db.QueryRow(ctx, `
+		SELECT status, reason FROM onboarding_status WHERE user_id = $1 AND stage = 'tier2'
+	`, userID).Scan(&progress.Status, &progress.Reason)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT document_type, status, rejection_reason, anchor_verification_id, submitted_at, reviewed_at, value_encrypted
+		FROM tier2_submissions
+		WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var submission domain.Tier2Submission
+		if err := rows.Scan(
+			&submission.DocumentType,
+			&submission.Status,
+			&submission.RejectionReason,
+			&submission.AnchorVerificationID,
+			&submission.SubmittedAt,
+			&submission.ReviewedAt,
+			&submission.ValueEncrypted,
+		); err != nil {
+			return nil, err
+		}
+		progress.Submissions = append(progress.Submissions, submission)
+	}
+	return progress, rows.Err()
+}
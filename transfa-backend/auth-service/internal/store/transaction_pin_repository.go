@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	maxFailedTransactionPINAttempts = 5
+	transactionPINLockoutDuration   = 15 * time.Minute
+)
+
+var (
+	ErrTransactionPINAlreadySet = errors.New("transaction pin is already set")
+	ErrTransactionPINNotSet     = errors.New("transaction pin is not set")
+	ErrTransactionPINLocked     = errors.New("transaction pin is temporarily locked")
+	ErrTransactionPINMismatch   = errors.New("transaction pin is invalid")
+	ErrTransactionPINUnchanged  = errors.New("new pin must be different from current pin")
+)
+
+// CreateTransactionPIN stores the user's first transaction PIN. It never overwrites an
+// existing PIN; changing a PIN goes through ChangeTransactionPIN.
+func (r *PostgresUserRepository) CreateTransactionPIN(ctx context.Context, userID, pin string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO user_security_credentials (user_id, transaction_pin_hash, pin_set_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, string(hash))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTransactionPINAlreadySet
+	}
+	return nil
+}
+
+// VerifyTransactionPIN checks the PIN against the stored hash. A wrong PIN counts
+// towards the lockout; a correct one resets the counter.
+func (r *PostgresUserRepository) VerifyTransactionPIN(ctx context.Context, userID, pin string) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := verifyTransactionPINTx(ctx, tx, userID, pin); err != nil {
+		if errors.Is(err, ErrTransactionPINMismatch) {
+			if commitErr := tx.Commit(ctx); commitErr != nil {
+				return commitErr
+			}
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_security_credentials
+		SET failed_attempts = 0, last_failed_at = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND (failed_attempts <> 0 OR locked_until IS NOT NULL)
+	`, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ChangeTransactionPIN replaces the user's PIN after verifying the current one.
+func (r *PostgresUserRepository) ChangeTransactionPIN(ctx context.Context, userID, currentPIN, newPIN string) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := verifyTransactionPINTx(ctx, tx, userID, currentPIN); err != nil {
+		if errors.Is(err, ErrTransactionPINMismatch) {
+			if commitErr := tx.Commit(ctx); commitErr != nil {
+				return commitErr
+			}
+		}
+		return err
+	}
+	if currentPIN == newPIN {
+		return ErrTransactionPINUnchanged
+	}
+
+	newHash, err := bcrypt.GenerateFromPassword([]byte(newPIN), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_security_credentials
+		SET transaction_pin_hash = $2,
+		    pin_set_at = NOW(),
+		    failed_attempts = 0,
+		    last_failed_at = NULL,
+		    locked_until = NULL,
+		    updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, string(newHash)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// verifyTransactionPINTx locks the user's credentials row and compares the PIN. On a
+// mismatch the failed attempt is recorded in tx and ErrTransactionPINMismatch is
+// returned; the caller must commit so the attempt is kept.
+func verifyTransactionPINTx(ctx context.Context, tx pgx.Tx, userID, pin string) error {
+	var (
+		storedHash     string
+		failedAttempts int
+		lockedUntil    *time.Time
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT transaction_pin_hash, failed_attempts, locked_until
+		FROM user_security_credentials
+		WHERE user_id = $1
+		FOR UPDATE
+	`, userID).Scan(&storedHash, &failedAttempts, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTransactionPINNotSet
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	if lockedUntil != nil && lockedUntil.After(now) {
+		return ErrTransactionPINLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(pin)) == nil {
+		return nil
+	}
+
+	nextAttempts := failedAttempts + 1
+	var nextLockedUntil *time.Time
+	if nextAttempts >= maxFailedTransactionPINAttempts {
+		until := now.Add(transactionPINLockoutDuration)
+		nextLockedUntil = &until
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_security_credentials
+		SET failed_attempts = $2, last_failed_at = NOW(), locked_until = $3, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, nextAttempts, nextLockedUntil); err != nil {
+		return err
+	}
+	return ErrTransactionPINMismatch
+}
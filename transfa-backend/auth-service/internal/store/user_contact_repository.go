@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+// FindContactByUserID returns the email and names of an open account. Closed accounts
+// return pgx.ErrNoRows so they are not contacted.
+func (r *PostgresUserRepository) FindContactByUserID(ctx context.Context, userID string) (*domain.UserContact, error) {
+	var contact domain.UserContact
+	err := r.db.QueryRow(ctx, `
+		SELECT id::text, NULLIF(btrim(email), ''), btrim(username), full_name
+		FROM users
+		WHERE id = $1::uuid AND closed_at IS NULL
+	`, userID).Scan(&contact.UserID, &contact.Email, &contact.Username, &contact.FullName)
+	if err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
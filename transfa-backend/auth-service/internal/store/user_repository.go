@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,15 +16,18 @@ import (
 // UserRepository defines the interface for user data storage.
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) (string, error)
-	CreateUserAndEnqueueUserCreatedEvent(ctx context.Context, user *domain.User, kycData map[string]interface{}, exchange, routingKey string) (string, error)
+	UpsertUserAndEnqueueUserCreatedEvent(ctx context.Context, user *domain.User, kycData map[string]interface{}, exchange, routingKey string) (string, error)
 	FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindByPhone(ctx context.Context, phone string) (*domain.User, error)
+	FindContactByUserID(ctx context.Context, userID string) (*domain.UserContact, error)
 	UpdateClerkUserID(ctx context.Context, userID, clerkUserID string) error
 	UpdateContactInfo(ctx context.Context, userID string, email *string, phone *string) error
 	UpdateAnchorCustomerInfo(ctx context.Context, userID string, anchorCustomerID string, fullName *string) error
 	UpdateUserProfileAndEnqueueUserCreatedEvent(ctx context.Context, userID string, email, phone, fullName *string, kycData map[string]interface{}, exchange, routingKey string) error
 	UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error
+	ApplyOnboardingStageChange(ctx context.Context, userID, stage, status string, reason *string, occurredAt time.Time) (bool, error)
+	ListStuckOnboardingStatuses(ctx context.Context, updatedBefore time.Time) ([]domain.StuckOnboardingStatus, error)
 	UpsertOnboardingStatusAndEnqueueEvent(ctx context.Context, userID, stage, status string, reason *string, exchange, routingKey string, payload interface{}) error
 	UpdateTier1ProfileAndEnqueueEvent(ctx context.Context, userID string, email, phone, fullName *string, stage, status string, reason *string, exchange, routingKey string, payload interface{}) error
 	UpsertOnboardingProgress(ctx context.Context, clerkUserID string, userID *string, userType string, currentStep int, payload map[string]interface{}) error
@@ -32,6 +36,19 @@ type UserRepository interface {
 	ClaimOutboxMessages(ctx context.Context, limit int, staleAfterSeconds int) ([]OutboxMessage, error)
 	MarkOutboxPublished(ctx context.Context, id int64) error
 	MarkOutboxFailed(ctx context.Context, id int64, retryAfterSeconds int, reason string) error
+	ClaimUsername(ctx context.Context, userID, username string) error
+	ChangeUsername(ctx context.Context, userID, username, exchange, routingKey string) (*domain.UsernameChangedEvent, error)
+	GetAccountClosureEligibility(ctx context.Context, userID string) (*domain.AccountClosureEligibility, error)
+	CloseUserAndEnqueueEvent(ctx context.Context, userID, exchange, routingKey string) (time.Time, error)
+	CreateTransactionPIN(ctx context.Context, userID, pin string) error
+	VerifyTransactionPIN(ctx context.Context, userID, pin string) error
+	ChangeTransactionPIN(ctx context.Context, userID, currentPIN, newPIN string) error
+	PreProvisionClerkUser(ctx context.Context, clerkUserID string, email *string) (bool, error)
+	SyncClerkUserEmail(ctx context.Context, clerkUserID string, email string) (bool, error)
+	MarkClerkUserDeletedAndEnqueueEvent(ctx context.Context, clerkUserID, exchange, routingKey string) (*domain.ClerkUserDeletedEvent, error)
+	SubmitTier2AndEnqueueEvent(ctx context.Context, userID string, submissions []domain.Tier2Submission, exchange, routingKey string, payload interface{}) error
+	GetTier2Progress(ctx context.Context, userID string) (*domain.Tier2Progress, error)
+	RetryTier1AndEnqueueEvent(ctx context.Context, userID string, maxRetries int, email, phone, fullName *string, kycData map[string]interface{}, exchange, routingKey string) (int, error)
 }
 
 // PostgresUserRepository is the PostgreSQL implementation of the UserRepository.
@@ -80,7 +97,7 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *domain.Us
 // FindByClerkUserID retrieves a user by their Clerk User ID.
 func (r *PostgresUserRepository) FindByClerkUserID(ctx context.Context, clerkUserID string) (*domain.User, error) {
 	query := `
-		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, created_at, updated_at
+		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, allow_receiving, NOT allow_receiving AS is_frozen, lifecycle_status, closed_at, created_at, updated_at
 		FROM users WHERE clerk_user_id = $1 LIMIT 1
 	`
 	var u domain.User
@@ -97,6 +114,10 @@ func (r *PostgresUserRepository) FindByClerkUserID(ctx context.Context, clerkUse
 		&u.FullName,
 		&u.Type,
 		&u.AllowSending,
+		&u.AllowReceiving,
+		&u.IsFrozen,
+		&u.LifecycleStatus,
+		&u.ClosedAt,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -117,7 +138,7 @@ func (r *PostgresUserRepository) FindByClerkUserID(ctx context.Context, clerkUse
 // FindByEmail retrieves a user by their email address.
 func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, created_at, updated_at
+		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, allow_receiving, NOT allow_receiving AS is_frozen, lifecycle_status, closed_at, created_at, updated_at
 		FROM users WHERE email = $1 LIMIT 1
 	`
 	var u domain.User
@@ -134,6 +155,10 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string)
 		&u.FullName,
 		&u.Type,
 		&u.AllowSending,
+		&u.AllowReceiving,
+		&u.IsFrozen,
+		&u.LifecycleStatus,
+		&u.ClosedAt,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -154,7 +179,7 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string)
 // FindByPhone retrieves a user by their phone number.
 func (r *PostgresUserRepository) FindByPhone(ctx context.Context, phone string) (*domain.User, error) {
 	query := `
-		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, created_at, updated_at
+		SELECT id, clerk_user_id, anchor_customer_id, btrim(username) AS username, email, phone_number, full_name, user_type, allow_sending, allow_receiving, NOT allow_receiving AS is_frozen, lifecycle_status, closed_at, created_at, updated_at
 		FROM users WHERE phone_number = $1 LIMIT 1
 	`
 	var u domain.User
@@ -171,6 +196,10 @@ func (r *PostgresUserRepository) FindByPhone(ctx context.Context, phone string)
 		&u.FullName,
 		&u.Type,
 		&u.AllowSending,
+		&u.AllowReceiving,
+		&u.IsFrozen,
+		&u.LifecycleStatus,
+		&u.ClosedAt,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -264,6 +293,28 @@ func (r *PostgresUserRepository) UpsertOnboardingStatus(ctx context.Context, use
 	return nil
 }
 
+// ApplyOnboardingStageChange records a stage change reported by another service. The
+// row takes occurredAt as its updated_at, and a change older than the row is ignored
+// so late or redelivered events never overwrite a newer status. It reports whether the
+// change was applied; a user that no longer exists is skipped.
+func (r *PostgresUserRepository) ApplyOnboardingStageChange(ctx context.Context, userID, stage, status string, reason *string, occurredAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO onboarding_status (user_id, stage, status, reason, updated_at)
+		SELECT u.id, $2, $3, $4, $5
+		FROM users u
+		WHERE u.id = $1
+		ON CONFLICT (user_id, stage)
+		DO UPDATE SET status = EXCLUDED.status, reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at
+		WHERE onboarding_status.updated_at <= EXCLUDED.updated_at
+	`
+	tag, err := r.db.Exec(ctx, query, userID, stage, status, reason, occurredAt)
+	if err != nil {
+		log.Printf("Error applying onboarding stage change for user %s: %v", userID, err)
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func nullableUsername(username *string) interface{} {
 	if username == nil {
 		return nil
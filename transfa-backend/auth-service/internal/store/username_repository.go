@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/transfa/auth-service/internal/domain"
+)
+
+var (
+	ErrUsernameTaken         = errors.New("username is not available")
+	ErrUsernameAlreadySet    = errors.New("username is already set; change it with PATCH /me/username")
+	ErrUsernameNotSet        = errors.New("username is not set yet")
+	ErrUsernameUnchanged     = errors.New("new username must be different from the current username")
+	ErrUsernameChangeTooSoon = errors.New("username can only be changed once every 30 days")
+)
+
+// ClaimUsername sets the user's first username. Repeating the call with the same
+// username is a no-op; a different username must go through ChangeUsername.
+func (r *PostgresUserRepository) ClaimUsername(ctx context.Context, userID, username string) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockUsernameTx(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		if *current == username {
+			return nil
+		}
+		return ErrUsernameAlreadySet
+	}
+
+	if err := setUsernameTx(ctx, tx, userID, username); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ChangeUsername renames the user, records the change in username_history and
+// enqueues the user.username_changed event in the same transaction.
+func (r *PostgresUserRepository) ChangeUsername(ctx context.Context, userID, username, exchange, routingKey string) (*domain.UsernameChangedEvent, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockUsernameTx(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrUsernameNotSet
+	}
+	if *current == username {
+		return nil, ErrUsernameUnchanged
+	}
+
+	var changedRecently bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE user_id = $1 AND changed_at > NOW() - INTERVAL '30 days'
+		)
+	`, userID).Scan(&changedRecently); err != nil {
+		return nil, err
+	}
+	if changedRecently {
+		return nil, ErrUsernameChangeTooSoon
+	}
+
+	if err := setUsernameTx(ctx, tx, userID, username); err != nil {
+		return nil, err
+	}
+
+	event := &domain.UsernameChangedEvent{
+		UserID:      userID,
+		OldUsername: *current,
+		NewUsername: username,
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO username_history (user_id, old_username, new_username)
+		VALUES ($1, $2, $3)
+		RETURNING changed_at
+	`, userID, event.OldUsername, event.NewUsername).Scan(&event.ChangedAt); err != nil {
+		return nil, err
+	}
+
+	if err := enqueueEventTx(ctx, tx, exchange, routingKey, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func lockUsernameTx(ctx context.Context, tx pgx.Tx, userID string) (*string, error) {
+	var current *string
+	err := tx.QueryRow(ctx, `
+		SELECT NULLIF(btrim(username), '')
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`, userID).Scan(&current)
+	return current, err
+}
+
+// setUsernameTx assigns username to the user. A username given up in the last 30 days
+// stays reserved for its previous owner's redirect; one held by a user who closed
+// their account more than 90 days ago is released first.
+func setUsernameTx(ctx context.Context, tx pgx.Tx, userID, username string) error {
+	var reserved bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE lower(old_username) = lower($1)
+			  AND user_id <> $2
+			  AND changed_at > NOW() - INTERVAL '30 days'
+		)
+	`, username, userID).Scan(&reserved); err != nil {
+		return err
+	}
+	if reserved {
+		return ErrUsernameTaken
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE users
+		SET username = NULL, updated_at = NOW()
+		WHERE username = $1
+		  AND id <> $2
+		  AND closed_at IS NOT NULL
+		  AND closed_at <= NOW() - INTERVAL '90 days'
+	`, username, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET username = $1, updated_at = NOW() WHERE id = $2`, username, userID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	return nil
+}
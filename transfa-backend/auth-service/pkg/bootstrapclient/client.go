@@ -0,0 +1,116 @@
+/**
+ * @description
+ * This package provides the client GET /me/bootstrap uses to read the sections the
+ * auth-service does not own: the receiving preference from the transaction-service
+ * and the subscription status from the subscription-service.
+ *
+ * @notes
+ * - Calls are made on the user's behalf: the caller's Authorization header is
+ *   forwarded and each service authenticates it as it would a direct app request,
+ *   so neither service needs to trust the auth-service with user identities.
+ * - Bodies are passed through as raw JSON; the auth-service does not interpret them.
+ */
+package bootstrapclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when the service a section comes from has no base URL.
+var ErrNotConfigured = errors.New("service url is not configured")
+
+// StatusError is returned when a service answers with an error status. Code and
+// Message come from the service's error envelope when it sent one.
+type StatusError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("service returned status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("service returned status %d", e.StatusCode)
+}
+
+// Client reads bootstrap sections from the transaction and subscription services.
+type Client struct {
+	transactionServiceURL  string
+	subscriptionServiceURL string
+	httpClient             *http.Client
+}
+
+// NewClient creates a new bootstrap client. An empty URL leaves that section
+// unavailable rather than failing the whole bootstrap.
+func NewClient(transactionServiceURL string, subscriptionServiceURL string) *Client {
+	return &Client{
+		transactionServiceURL:  strings.TrimRight(strings.TrimSpace(transactionServiceURL), "/"),
+		subscriptionServiceURL: strings.TrimRight(strings.TrimSpace(subscriptionServiceURL), "/"),
+		httpClient:             &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// ReceivingPreference returns the caller's receiving preference.
+func (c *Client) ReceivingPreference(ctx context.Context, authorization string) (json.RawMessage, error) {
+	return c.get(ctx, c.transactionServiceURL, "/transactions/receiving-preference", authorization)
+}
+
+// SubscriptionStatus returns the caller's subscription status.
+func (c *Client) SubscriptionStatus(ctx context.Context, authorization string) (json.RawMessage, error) {
+	return c.get(ctx, c.subscriptionServiceURL, "/status", authorization)
+}
+
+func (c *Client) get(ctx context.Context, baseURL string, path string, authorization string) (json.RawMessage, error) {
+	if baseURL == "" {
+		return nil, ErrNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request to %s: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		var envelope struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &envelope) == nil {
+			statusErr.Code = envelope.Error.Code
+			statusErr.Message = envelope.Error.Message
+		}
+		if statusErr.Message == "" {
+			statusErr.Message = strings.TrimSpace(string(body))
+		}
+		return nil, statusErr
+	}
+
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("%s%s returned a non-JSON body", baseURL, path)
+	}
+	return json.RawMessage(body), nil
+}
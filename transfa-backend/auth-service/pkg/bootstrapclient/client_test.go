@@ -0,0 +1,53 @@
+package bootstrapclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReceivingPreference_ForwardsAuthorization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/transactions/receiving-preference" || r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unexpected request", http.StatusTeapot)
+			return
+		}
+		_, _ = io.WriteString(w, `{"use_external_account":true}`)
+	}))
+	defer server.Close()
+
+	body, err := NewClient(server.URL+"/", "").ReceivingPreference(context.Background(), "Bearer token")
+	if err != nil {
+		t.Fatalf("ReceivingPreference: %v", err)
+	}
+	if string(body) != `{"use_external_account":true}` {
+		t.Fatalf("expected the body to pass through, got %s", body)
+	}
+}
+
+func TestSubscriptionStatus_ReadsErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"error":{"code":"unauthorized","message":"Invalid token"}}`)
+	}))
+	defer server.Close()
+
+	_, err := NewClient("", server.URL).SubscriptionStatus(context.Background(), "Bearer token")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected a StatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusUnauthorized || statusErr.Code != "unauthorized" || statusErr.Message != "Invalid token" {
+		t.Fatalf("unexpected status error %+v", statusErr)
+	}
+}
+
+func TestSubscriptionStatus_NotConfigured(t *testing.T) {
+	if _, err := NewClient("http://transactions", "").SubscriptionStatus(context.Background(), ""); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -10,6 +10,8 @@
  * - Sets up a RabbitMQ consumer to listen on a dedicated queue for 'user.created' events.
  * - Initializes the Anchor API client and the user repository.
  * - Wires up the event handler for processing incoming messages.
+ * - Retries deferred user.created events in the background.
+ * - Serves the admin listener listing deferred events, unless ADMIN_PORT is empty.
  * - Implements graceful shutdown to ensure clean resource cleanup.
  *
  * @dependencies
@@ -18,14 +20,15 @@
  * - github.com/transfa/customer-service/internal/app: Contains the core consumer logic.
  * - github.com/transfa/customer-service/internal/config: For loading application configuration.
  * - github.com/transfa/customer-service/internal/store: Contains the database repository implementation.
- * - github.com/transfa/customer-service/pkg/anchorclient: The client for interacting with the Anchor API.
- * - github.com/transfa/customer-service/pkg/rabbitmq: The shared RabbitMQ consumer logic.
+ * - github.com/transfa/pkg/anchorclient: The client for interacting with the Anchor API.
+ * - github.com/transfa/pkg/rabbitmq: The shared RabbitMQ producer and consumer.
  */
 package main
 
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -34,11 +37,12 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/transfa/customer-service/internal/api"
 	"github.com/transfa/customer-service/internal/app"
 	"github.com/transfa/customer-service/internal/config"
 	"github.com/transfa/customer-service/internal/store"
-	"github.com/transfa/customer-service/pkg/anchorclient"
-	"github.com/transfa/customer-service/pkg/rabbitmq"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/pkg/rabbitmq"
 )
 
 func main() {
@@ -60,10 +64,10 @@ func main() {
 	}
 
 	// Configure connection pool to prevent prepared statement conflicts
-	dbConfig.MaxConns = 10
-	dbConfig.MinConns = 2
-	dbConfig.MaxConnLifetime = 30 * time.Minute
-	dbConfig.MaxConnIdleTime = 5 * time.Minute
+	dbConfig.MaxConns = cfg.DatabasePool.MaxConns
+	dbConfig.MinConns = cfg.DatabasePool.MinConns
+	dbConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	dbConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 
 	// Disable prepared statement caching to prevent conflicts
 	dbConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
@@ -78,20 +82,35 @@ func main() {
 	// Set up dependencies
 	userRepo := store.NewPostgresUserRepository(dbpool)
 	anchorClient := anchorclient.NewClient(cfg.AnchorAPIBaseURL, cfg.AnchorAPIKey)
-	publisher, err := rabbitmq.NewEventProducer(cfg.RabbitMQURL)
+	// Events are signed so consumers such as auth-service's onboarding.stage.changed
+	// consumer can reject messages that did not come from here.
+	producer, err := rabbitmq.NewEventProducer(cfg.RabbitMQURL)
 	if err != nil {
 		log.Fatalf("Failed to create event publisher: %v", err)
 	}
-	defer publisher.Close()
-	eventHandler := app.NewUserEventHandler(userRepo, anchorClient, publisher)
-
-	// Ensure onboarding status table exists
-	if err := userRepo.EnsureOnboardingStatusTable(context.Background()); err != nil {
-		log.Fatalf("Failed ensuring onboarding_status table: %v", err)
+	defer producer.Close()
+	var publisher rabbitmq.Publisher = producer
+	if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+		signed, err := rabbitmq.NewSignedEventProducer(producer, secrets...)
+		if err != nil {
+			log.Fatalf("Failed to initialise event signing: %v", err)
+		}
+		publisher = signed
+	}
+	if !cfg.OnboardingStatusDualWrite {
+		log.Println("Onboarding status dual-write disabled; stage changes are only published as onboarding.stage.changed")
 	}
+	eventHandler := app.NewUserEventHandler(userRepo, anchorClient, publisher, app.WithOnboardingStatusDualWrite(cfg.OnboardingStatusDualWrite))
 
 	// Set up and start RabbitMQ consumer
-	consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL)
+	// customer.tier.status events are published by notification-service and must be signed.
+	var consumerOpts []rabbitmq.ConsumerOption
+	if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+		consumerOpts = append(consumerOpts, rabbitmq.WithSignedEvents(secrets, "customer.tier.status"))
+	} else {
+		log.Println("Warning: EVENT_SIGNING_SECRET not set; customer.tier.status event signatures are not verified")
+	}
+	consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL, consumerOpts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
 	}
@@ -121,10 +140,11 @@ func main() {
 		}
 	}()
 
-	// Consume tier status events and tier2 verification requests on dedicated queues
+	// Consume tier status events and tier verification requests on dedicated queues
 	go func() {
 		tier2Queue := "customer_service_tier2_requested"
 		bindings := map[string]func([]byte) bool{
+			"tier1.verification.requested": eventHandler.HandleTier1VerificationRequestedEvent,
 			"tier2.verification.requested": eventHandler.HandleTier2VerificationRequestedEvent,
 			"tier3.verification.requested": eventHandler.HandleTier3VerificationRequestedEvent,
 		}
@@ -134,6 +154,10 @@ func main() {
 		}
 	}()
 
+	if err := consumer.DeclareQueue("customer_events", app.PlatformReviewQueue, app.PlatformReviewRoutingKey); err != nil {
+		log.Fatalf("Failed to declare platform review queue: %v", err)
+	}
+
 	go func() {
 		tierStatusQueue := "customer_service_tier_status"
 		bindings := map[string]func([]byte) bool{
@@ -145,6 +169,35 @@ func main() {
 		}
 	}()
 
+	go func() {
+		closedQueue := "customer_service_user_closed"
+		log.Printf("Starting consumer for queue '%s'...", closedQueue)
+		if err := consumer.Consume(exchangeName, closedQueue, "user.closed", eventHandler.HandleUserClosedEvent); err != nil {
+			log.Fatalf("User closed consumer error: %v", err)
+		}
+	}()
+
+	// Retry user.created events deferred after a retriable failure
+	retryCtx, stopRetries := context.WithCancel(context.Background())
+	defer stopRetries()
+	go eventHandler.RunDeferredEventRetries(retryCtx, 30*time.Second)
+
+	// Start the admin listener unless it is disabled with an empty ADMIN_PORT
+	var adminServer *http.Server
+	if cfg.AdminPort != "" {
+		adminServer = &http.Server{
+			Addr:              ":" + cfg.AdminPort,
+			Handler:           api.AdminRoutes(eventHandler, cfg.InternalAPIKey),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			log.Printf("Admin listener started on %s", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("ERROR: Admin listener failed: %v", err)
+			}
+		}()
+	}
+
 	log.Println("Customer service is running. Waiting for events.")
 
 	// Wait for termination signal for graceful shutdown
@@ -152,4 +205,12 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down customer-service...")
+	stopRetries()
+	if adminServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("WARNING: Admin listener shutdown failed: %v", err)
+		}
+		cancel()
+	}
 }
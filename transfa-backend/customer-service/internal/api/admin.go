@@ -0,0 +1,80 @@
+/**
+ * @description
+ * Admin HTTP listener for customer-service. Lists deferred events so operators can
+ * follow up on the ones that exhausted their retries.
+ *
+ * @notes
+ * - Every route except /health requires the X-Internal-API-Key header.
+ */
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/transfa/customer-service/internal/domain"
+	"github.com/transfa/pkg/httpjson"
+	"github.com/transfa/pkg/middleware"
+)
+
+const (
+	defaultDeferredEventListLimit = 50
+	maxDeferredEventListLimit     = 200
+)
+
+// DeferredEventLister is the part of the event handler the admin API needs.
+type DeferredEventLister interface {
+	ListDeferredEvents(ctx context.Context, status string, limit int) ([]domain.DeferredEvent, error)
+}
+
+// AdminRoutes returns the admin HTTP handler.
+func AdminRoutes(lister DeferredEventLister, internalAPIKey string) http.Handler {
+	internalAuth := middleware.InternalAuth(internalAPIKey)
+	h := &adminHandler{lister: lister}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("healthy"))
+	})
+	mux.Handle("GET /internal/deferred-events", internalAuth(http.HandlerFunc(h.listDeferredEvents)))
+	return mux
+}
+
+type adminHandler struct {
+	lister DeferredEventLister
+}
+
+func (h *adminHandler) listDeferredEvents(w http.ResponseWriter, r *http.Request) {
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+	if status == "" {
+		status = domain.DeferredEventNeedsManualReview
+	}
+	switch status {
+	case domain.DeferredEventPending, domain.DeferredEventProcessing, domain.DeferredEventCompleted, domain.DeferredEventNeedsManualReview:
+	default:
+		httpjson.Write(w, http.StatusBadRequest, map[string]string{"error": "Invalid status"})
+		return
+	}
+
+	limit := defaultDeferredEventListLimit
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			httpjson.Write(w, http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+			return
+		}
+		limit = min(parsed, maxDeferredEventListLimit)
+	}
+
+	events, err := h.lister.ListDeferredEvents(r.Context(), status, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to list deferred events: %v", err)
+		httpjson.Write(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list deferred events"})
+		return
+	}
+	httpjson.Write(w, http.StatusOK, map[string]interface{}{"events": events})
+}
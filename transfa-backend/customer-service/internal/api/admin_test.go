@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/transfa/customer-service/internal/domain"
+)
+
+type deferredEventListerStub struct {
+	status string
+	limit  int
+}
+
+func (s *deferredEventListerStub) ListDeferredEvents(ctx context.Context, status string, limit int) ([]domain.DeferredEvent, error) {
+	s.status, s.limit = status, limit
+	return []domain.DeferredEvent{{ID: 7, Consumer: "customer_service.user_created", MessageID: "user-1", Status: status, Attempts: 5}}, nil
+}
+
+func newAdminTestRequest(path, key string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set("X-Internal-API-Key", key)
+	}
+	return req
+}
+
+func TestAdminRoutes_RequireInternalAPIKey(t *testing.T) {
+	lister := &deferredEventListerStub{}
+
+	for _, key := range []string{"", "wrong-key"} {
+		rec := httptest.NewRecorder()
+		AdminRoutes(lister, "admin-key").ServeHTTP(rec, newAdminTestRequest("/internal/deferred-events", key))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("key %q: expected 401, got %d", key, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	AdminRoutes(lister, "").ServeHTTP(rec, newAdminTestRequest("/internal/deferred-events", "admin-key"))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a configured key, got %d", rec.Code)
+	}
+	if lister.status != "" {
+		t.Fatalf("expected no listing without authorization, got status %q", lister.status)
+	}
+}
+
+func TestAdminRoutes_ListDeferredEvents(t *testing.T) {
+	lister := &deferredEventListerStub{}
+	handler := AdminRoutes(lister, "admin-key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newAdminTestRequest("/internal/deferred-events", "admin-key"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if lister.status != domain.DeferredEventNeedsManualReview || lister.limit != defaultDeferredEventListLimit {
+		t.Fatalf("expected the manual review queue by default, got status %q limit %d", lister.status, lister.limit)
+	}
+	var body struct {
+		Events []domain.DeferredEvent `json:"events"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Events) != 1 || body.Events[0].MessageID != "user-1" {
+		t.Fatalf("unexpected events: %+v", body.Events)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newAdminTestRequest("/internal/deferred-events?status=pending&limit=1000", "admin-key"))
+	if rec.Code != http.StatusOK || lister.status != domain.DeferredEventPending || lister.limit != maxDeferredEventListLimit {
+		t.Fatalf("expected pending events capped at %d, got %d status %q limit %d", maxDeferredEventListLimit, rec.Code, lister.status, lister.limit)
+	}
+
+	for _, query := range []string{"?status=unknown", "?limit=0", "?limit=abc"} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, newAdminTestRequest("/internal/deferred-events"+query, "admin-key"))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
@@ -8,44 +8,81 @@
  * - log: For logging application events and errors.
  * - github.com/transfa/customer-service/internal/domain: For domain model definitions.
  * - github.com/transfa/customer-service/internal/store: For the user repository interface.
- * - github.com/transfa/customer-service/pkg/anchorclient: For the Anchor API client.
+ * - github.com/transfa/pkg/anchorclient: For the Anchor API client.
  *
  * @notes
  * - The handler contains the primary business logic: creating a customer on the BaaS
  *   platform and updating the internal user record.
- * - Robust error handling is crucial here. If any step fails (e.g., Anchor API call,
- *   database update), the message should ideally be re-queued or sent to a dead-letter
- *   queue for manual inspection to prevent data inconsistencies.
+ * - Robust error handling is crucial here. A user.created event that fails for a
+ *   retriable reason (Anchor outage or rate limit) is stored in deferred_events and
+ *   retried with backoff (see deferred_events.go) instead of being dropped.
  */
 package app
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/transfa/customer-service/internal/domain"
 	"github.com/transfa/customer-service/internal/store"
-	"github.com/transfa/customer-service/pkg/anchorclient"
+	"github.com/transfa/pkg/anchorclient"
 )
 
 var anchorNigerianPhonePattern = regexp.MustCompile(`^0[0-9]{10}$`)
 
+// errMissingRequiredFields marks user.created KYC data that lacks the fields Anchor needs.
+var errMissingRequiredFields = errors.New("missing required fields")
+
+// insufficientVerificationBalanceMessage replaces Anchor's insufficient balance error,
+// which refers to the platform's verification account rather than the user.
+const insufficientVerificationBalanceMessage = "The platform's verification account has insufficient funds. Please contact support or try again later."
+
+// anchorRetryPendingMessage is the tier1 reason while customer creation waits for a
+// retry, and anchorRetryExhaustedMessage once the retries ran out.
+const (
+	anchorRetryPendingMessage   = "Anchor is temporarily unavailable. Retrying automatically."
+	anchorRetryExhaustedMessage = "Failed to create customer on Anchor. Please try again later."
+)
+
+const (
+	// userCreatedConsumer names this consumer in the event inbox.
+	userCreatedConsumer = "customer_service.user_created"
+	// userCreatedClaimTTL is how long an unfinished claim blocks redeliveries before
+	// another worker may take it over.
+	userCreatedClaimTTL = 2 * time.Minute
+	// tier1RetryConsumer names the Tier 1 retry consumer in the event inbox.
+	tier1RetryConsumer = "customer_service.tier1_retry"
+)
+
 // UserEventHandler handles processing of user-related events.
 type UserEventHandler struct {
 	repo         store.UserRepository
 	anchorClient *anchorclient.Client
 	publisher    EventPublisher
+
+	// onboardingStatusDualWrite also writes onboarding_status directly; see
+	// onboarding_status.go.
+	onboardingStatusDualWrite bool
 }
 
 type EventPublisher interface {
 	Publish(ctx context.Context, exchange, routingKey string, payload interface{}) error
 }
 
+const (
+	// PlatformReviewQueue holds customers waiting for a manual review by the operations team.
+	PlatformReviewQueue = "platform_review_queue"
+	// PlatformReviewRoutingKey is the customer_events routing key bound to PlatformReviewQueue.
+	PlatformReviewRoutingKey = "platform.review.requested"
+)
+
 // TierStatusEvent represents tier status updates received from other services.
 type TierStatusEvent struct {
 	UserID           string  `json:"user_id"`
@@ -53,15 +90,32 @@ type TierStatusEvent struct {
 	Stage            string  `json:"stage"`
 	Status           string  `json:"status"`
 	Reason           *string `json:"reason"`
+	VerificationID   string  `json:"verification_id,omitempty"`
+}
+
+// tier2DocumentKeywords maps each Tier 2 piece to the words Anchor uses for it in
+// rejection reasons.
+var tier2DocumentKeywords = []struct {
+	documentType string
+	keywords     []string
+}{
+	{documentType: "bvn", keywords: []string{"bvn", "bank verification"}},
+	{documentType: "date_of_birth", keywords: []string{"date of birth", "dateofbirth", "date_of_birth", "dob", "birth"}},
+	{documentType: "gender", keywords: []string{"gender", "sex"}},
 }
 
 // NewUserEventHandler creates a new instance of UserEventHandler.
-func NewUserEventHandler(repo store.UserRepository, anchorClient *anchorclient.Client, publisher EventPublisher) *UserEventHandler {
-	return &UserEventHandler{
-		repo:         repo,
-		anchorClient: anchorClient,
-		publisher:    publisher,
+func NewUserEventHandler(repo store.UserRepository, anchorClient *anchorclient.Client, publisher EventPublisher, opts ...HandlerOption) *UserEventHandler {
+	h := &UserEventHandler{
+		repo:                      repo,
+		anchorClient:              anchorClient,
+		publisher:                 publisher,
+		onboardingStatusDualWrite: true,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // HandleUserCreatedEvent is the callback function that processes a `user.created` event.
@@ -77,10 +131,69 @@ func (h *UserEventHandler) HandleUserCreatedEvent(body []byte) bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	return h.runOrDeferUserCreatedEvent(ctx, event, userCreatedMessageID(event), body)
+}
+
+// runOrDeferUserCreatedEvent runs a user.created event and, when it failed for a
+// reason a later attempt can fix, queues payload in deferred_events. It reports
+// whether the message should be acknowledged.
+func (h *UserEventHandler) runOrDeferUserCreatedEvent(ctx context.Context, event domain.UserCreatedEvent, messageID string, payload []byte) bool {
+	ack, retryErr := h.runUserCreatedEvent(ctx, event, messageID)
+	if retryErr == nil {
+		return ack
+	}
+	if err := h.deferUserCreatedEvent(ctx, event, messageID, payload, retryErr); err != nil {
+		log.Printf("ERROR: Failed to defer user.created message %s for retry; requeueing: %v", messageID, err)
+		return false
+	}
+	return true
+}
+
+// userCreatedMessageID returns the inbox key of a user.created event. Events queued
+// before message IDs were introduced fall back to the user ID, which is what
+// auth-service now sends as the message ID anyway.
+func userCreatedMessageID(event domain.UserCreatedEvent) string {
+	if messageID := strings.TrimSpace(event.MessageID); messageID != "" {
+		return messageID
+	}
+	return event.UserID
+}
+
+// runUserCreatedEvent claims the message and processes it. It reports whether the
+// message should be acknowledged and, when the attempt failed for a reason a later
+// attempt can fix, the error to retry on.
+func (h *UserEventHandler) runUserCreatedEvent(ctx context.Context, event domain.UserCreatedEvent, messageID string) (bool, error) {
+	claimed, err := h.repo.ClaimEvent(ctx, userCreatedConsumer, messageID, userCreatedClaimTTL)
+	if err != nil {
+		log.Printf("ERROR: Failed to claim user.created message %s: %v", messageID, err)
+		return false, nil
+	}
+	if !claimed {
+		log.Printf("Duplicate user.created message %s for UserID %s. Skipping.", messageID, event.UserID)
+		return true, nil
+	}
+
+	ack, linked, retryErr := h.processUserCreatedEvent(ctx, event)
+	if linked {
+		if err := h.repo.CompleteEvent(ctx, userCreatedConsumer, messageID); err != nil {
+			log.Printf("WARNING: Failed to mark user.created message %s completed: %v", messageID, err)
+		}
+	} else if err := h.repo.ReleaseEvent(ctx, userCreatedConsumer, messageID); err != nil {
+		// The claim expires after userCreatedClaimTTL, after which a retry can proceed.
+		log.Printf("WARNING: Failed to release user.created message %s: %v", messageID, err)
+	}
+	return ack, retryErr
+}
+
+// processUserCreatedEvent creates and links the Anchor customer for a claimed
+// user.created event. It reports whether the message should be acknowledged, whether
+// the user ended up linked to an Anchor customer and, for a failure worth retrying
+// later, the error.
+func (h *UserEventHandler) processUserCreatedEvent(ctx context.Context, event domain.UserCreatedEvent) (bool, bool, error) {
 	userType, ok := event.KYCData["userType"].(string)
 	if !ok {
 		log.Printf("ERROR: 'userType' missing or not a string in KYCData for UserID: %s", event.UserID)
-		return true // Acknowledge, can't be processed.
+		return true, false, nil // Acknowledge, can't be processed.
 	}
 
 	var anchorCustomerID string
@@ -89,7 +202,7 @@ func (h *UserEventHandler) HandleUserCreatedEvent(body []byte) bool {
 	// If this user already has an Anchor Customer ID, skip creating again (idempotent)
 	if anchorIDPtr, getErr := h.repo.GetAnchorCustomerIDByUserID(ctx, event.UserID); getErr == nil && anchorIDPtr != nil && *anchorIDPtr != "" {
 		log.Printf("Anchor customer already linked (%s) for UserID %s. Skipping creation.", *anchorIDPtr, event.UserID)
-		return true
+		return true, true, nil
 	}
 
 	// Create customer on Anchor based on user type
@@ -98,98 +211,14 @@ func (h *UserEventHandler) HandleUserCreatedEvent(body []byte) bool {
 		anchorCustomerID, err = h.createPersonalCustomerWithIdempotency(ctx, event)
 	case domain.MerchantUser:
 		log.Printf("Merchant user onboarding is not yet implemented. UserID: %s", event.UserID)
-		return true
+		return true, false, nil
 	default:
 		log.Printf("ERROR: Unknown user type '%s' for UserID: %s", userType, event.UserID)
-		return true
+		return true, false, nil
 	}
 
 	if err != nil {
-		// On known validation errors, acknowledge to prevent infinite requeue
-		if strings.Contains(err.Error(), "missing required fields") {
-			log.Printf("ACK after validation failure for UserID %s: %v", event.UserID, err)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", ptr(err.Error()))
-			return true
-		}
-
-		// Handle "customer already exists" errors - this means customer was created but DB update failed
-		if strings.Contains(err.Error(), "CUSTOMER_ALREADY_EXISTS") {
-			log.Printf("Customer already exists on Anchor for UserID %s. This indicates a previous creation succeeded but DB update failed.", event.UserID)
-
-			// Check if we can extract the customer ID from the error for automatic recovery
-			if strings.Contains(err.Error(), "CUSTOMER_ALREADY_EXISTS_WITH_ID") {
-				// Extract customer ID from error message
-				parts := strings.Split(err.Error(), "|")
-				if len(parts) >= 2 {
-					customerID := strings.TrimPrefix(parts[0], "CUSTOMER_ALREADY_EXISTS_WITH_ID: ")
-					log.Printf("Attempting automatic recovery for UserID %s with extracted customer ID: %s", event.UserID, customerID)
-
-					// Extract and construct full name from structured KYC data for database update
-					firstName, _ := event.KYCData["firstName"].(string)
-					lastName, _ := event.KYCData["lastName"].(string)
-					middleName, _ := event.KYCData["middleName"].(string)
-					maidenName, _ := event.KYCData["maidenName"].(string)
-
-					var fullNamePtr *string
-					if firstName != "" && lastName != "" {
-						fullNameParts := []string{firstName}
-						if middleName != "" {
-							fullNameParts = append(fullNameParts, middleName)
-						}
-						fullNameParts = append(fullNameParts, lastName)
-						if maidenName != "" {
-							fullNameParts = append(fullNameParts, "("+maidenName+")")
-						}
-						constructedFullName := strings.Join(fullNameParts, " ")
-						fullNamePtr = &constructedFullName
-					}
-
-					// Update the database with the existing customer ID and full name
-					if updateErr := h.repo.UpdateAnchorCustomerInfo(ctx, event.UserID, customerID, fullNamePtr); updateErr != nil {
-						log.Printf("ERROR: Failed to update user record with existing Anchor customer ID %s for UserID %s: %v", customerID, event.UserID, updateErr)
-						_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "system_error", ptr("Customer exists on Anchor but failed to link in database. Manual intervention required."))
-						return true // ACK to prevent infinite requeue
-					}
-
-					log.Printf("Successfully recovered and linked existing Anchor customer %s to UserID %s", customerID, event.UserID)
-					_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "created", nil)
-					return true // ACK - recovery successful
-				}
-			}
-
-			// If we can't extract customer ID, mark as system error requiring manual intervention
-			log.Printf("CRITICAL: Customer exists on Anchor but not in our DB for UserID %s. Manual intervention required to link the customer.", event.UserID)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "system_error", ptr("Customer exists on Anchor but not linked in database. Manual intervention required."))
-			return true // ACK to prevent infinite requeue
-		}
-
-		// Non-retriable client errors from Anchor (4xx): ACK to stop requeue storm
-		if strings.Contains(err.Error(), "status 400") ||
-			strings.Contains(err.Error(), "status 401") ||
-			strings.Contains(err.Error(), "status 403") ||
-			strings.Contains(err.Error(), "status 404") ||
-			strings.Contains(err.Error(), "status 409") ||
-			strings.Contains(err.Error(), "status 422") {
-			log.Printf("Non-retriable client error from Anchor (ACK). UserID %s: %v", event.UserID, err)
-			msg := err.Error()
-			if strings.Contains(strings.ToLower(msg), "insufficient balance") {
-				msg = "The platform's verification account has insufficient funds. Please contact support or try again later."
-			}
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &msg)
-			return true
-		}
-		// Rate limit from Anchor: ACK to avoid hot-looping and API limits
-		if strings.Contains(err.Error(), "status 429") || strings.Contains(strings.ToLower(err.Error()), "too many requests") {
-			log.Printf("Rate limited by Anchor (ACK). UserID %s: %v", event.UserID, err)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
-			return true
-		}
-
-		// For any other errors (5xx, network issues, etc.), ACK to prevent API rate limiting
-		// This prevents hitting Anchor's API limits with repeated failed requests
-		log.Printf("ERROR: Failed to create Anchor customer for UserID %s (ACK to prevent API limits): %v", event.UserID, err)
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", ptr("Failed to create customer on Anchor. Please try again later."))
-		return true // ACK to prevent API rate limiting
+		return h.handleCustomerCreationError(ctx, event, err)
 	}
 
 	log.Printf("Successfully created Anchor customer %s for UserID %s", anchorCustomerID, event.UserID)
@@ -197,15 +226,101 @@ func (h *UserEventHandler) HandleUserCreatedEvent(body []byte) bool {
 	// Update our internal user record with the new Anchor Customer ID
 	if err := h.repo.UpdateAnchorCustomerID(ctx, event.UserID, anchorCustomerID); err != nil {
 		log.Printf("ERROR: Failed to update user record for UserID %s with AnchorID %s: %v", event.UserID, anchorCustomerID, err)
-		return false
+		return false, false, nil
 	}
 	log.Printf("Successfully updated user record for UserID %s", event.UserID)
 
 	// Record success status for Tier 1 so frontend can surface it
-	_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "created", nil)
+	_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "created", nil)
 
 	// Tier 2 is handled later in the account creation flow
-	return true
+	return true, true, nil
+}
+
+// handleCustomerCreationError records the outcome of a failed Anchor customer creation
+// and reports, like processUserCreatedEvent, whether to acknowledge the message,
+// whether the user ended up linked and the error to retry on. Every failure is
+// acknowledged so a bad payload or an Anchor outage cannot turn into a requeue storm
+// against Anchor's rate limits; rate limits and outages are retried from the
+// deferred_events queue instead.
+func (h *UserEventHandler) handleCustomerCreationError(ctx context.Context, event domain.UserCreatedEvent, err error) (bool, bool, error) {
+	var existsErr *anchorclient.CustomerExistsError
+	var apiErr *anchorclient.APIError
+
+	switch {
+	case errors.Is(err, errMissingRequiredFields):
+		log.Printf("ACK after validation failure for UserID %s: %v", event.UserID, err)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "failed", ptr(err.Error()))
+		return true, false, nil
+
+	case errors.As(err, &existsErr):
+		// The customer was created on a previous attempt but the DB update failed.
+		log.Printf("Customer already exists on Anchor for UserID %s. This indicates a previous creation succeeded but DB update failed.", event.UserID)
+		if existsErr.CustomerID == "" {
+			log.Printf("CRITICAL: Customer exists on Anchor but not in our DB for UserID %s. Manual intervention required to link the customer.", event.UserID)
+			_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "system_error", ptr("Customer exists on Anchor but not linked in database. Manual intervention required."))
+			return true, false, nil
+		}
+		ack, linked := h.linkExistingAnchorCustomer(ctx, event, existsErr.CustomerID)
+		return ack, linked, nil
+
+	case errors.As(err, &apiErr) && apiErr.IsRateLimited():
+		log.Printf("Rate limited by Anchor (ACK, retry deferred). UserID %s: %v", event.UserID, err)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
+		return true, false, err
+
+	case errors.As(err, &apiErr) && apiErr.IsExplicitRejection():
+		log.Printf("Non-retriable client error from Anchor (ACK). UserID %s: %v", event.UserID, err)
+		msg := err.Error()
+		if apiErr.Mentions("insufficient balance") {
+			msg = insufficientVerificationBalanceMessage
+		}
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "failed", &msg)
+		return true, false, nil
+
+	default:
+		// 5xx, network issues, etc.
+		log.Printf("ERROR: Failed to create Anchor customer for UserID %s (ACK, retry deferred): %v", event.UserID, err)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "processing", ptr(anchorRetryPendingMessage))
+		return true, false, err
+	}
+}
+
+// linkExistingAnchorCustomer links the user to a customer Anchor reported as already
+// existing, recovering from a creation whose DB update failed.
+func (h *UserEventHandler) linkExistingAnchorCustomer(ctx context.Context, event domain.UserCreatedEvent, customerID string) (bool, bool) {
+	log.Printf("Attempting automatic recovery for UserID %s with extracted customer ID: %s", event.UserID, customerID)
+
+	// Extract and construct full name from structured KYC data for database update
+	firstName, _ := event.KYCData["firstName"].(string)
+	lastName, _ := event.KYCData["lastName"].(string)
+	middleName, _ := event.KYCData["middleName"].(string)
+	maidenName, _ := event.KYCData["maidenName"].(string)
+
+	var fullNamePtr *string
+	if firstName != "" && lastName != "" {
+		fullNameParts := []string{firstName}
+		if middleName != "" {
+			fullNameParts = append(fullNameParts, middleName)
+		}
+		fullNameParts = append(fullNameParts, lastName)
+		if maidenName != "" {
+			fullNameParts = append(fullNameParts, "("+maidenName+")")
+		}
+		constructedFullName := strings.Join(fullNameParts, " ")
+		fullNamePtr = &constructedFullName
+	}
+
+	// Update the database with the existing customer ID and full name
+	if updateErr := h.repo.UpdateAnchorCustomerInfo(ctx, event.UserID, customerID, fullNamePtr); updateErr != nil {
+		log.Printf("ERROR: Failed to update user record with existing Anchor customer ID %s for UserID %s: %v", customerID, event.UserID, updateErr)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "system_error", ptr("Customer exists on Anchor but failed to link in database. Manual intervention required."))
+		return true, false // ACK to prevent infinite requeue
+	}
+
+	log.Printf("Successfully recovered and linked existing Anchor customer %s to UserID %s", customerID, event.UserID)
+	_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "created", nil)
+	return true, true
 }
 
 // HandleTier1ProfileUpdateRequestedEvent updates an existing Anchor customer profile
@@ -225,74 +340,136 @@ func (h *UserEventHandler) HandleTier1ProfileUpdateRequestedEvent(body []byte) b
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "processing", nil); err != nil {
-		log.Printf("Failed to mark tier1 processing for user %s: %v", event.UserID, err)
+	h.updateAnchorProfile(ctx, event.UserID, event.AnchorCustomerID, event.KYCData)
+	return true
+}
+
+// HandleTier1VerificationRequestedEvent re-runs Tier 1 verification after the user
+// retried a failed attempt. A user without an Anchor customer goes through customer
+// creation again under the retry's message ID; for a linked customer the profile is
+// re-submitted to Anchor, which re-runs its Tier 1 checks.
+func (h *UserEventHandler) HandleTier1VerificationRequestedEvent(body []byte) bool {
+	var event domain.Tier1VerificationRequestedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling tier1.verification.requested event: %v", err)
+		return true
 	}
 
-	firstName, err := requireKYCString(event.KYCData, "firstName")
+	if strings.TrimSpace(event.UserID) == "" || strings.TrimSpace(event.MessageID) == "" {
+		log.Printf("Invalid tier1.verification.requested event: missing user_id or message_id")
+		return true
+	}
+
+	log.Printf("Processing tier1.verification.requested message %s for UserID: %s", event.MessageID, event.UserID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	anchorCustomerID := strings.TrimSpace(event.AnchorCustomerID)
+	if anchorIDPtr, err := h.repo.GetAnchorCustomerIDByUserID(ctx, event.UserID); err == nil && anchorIDPtr != nil && *anchorIDPtr != "" {
+		anchorCustomerID = *anchorIDPtr
+	}
+
+	if anchorCustomerID == "" {
+		created := domain.UserCreatedEvent{MessageID: event.MessageID, UserID: event.UserID, KYCData: event.KYCData}
+		payload, err := json.Marshal(created)
+		if err != nil {
+			log.Printf("ERROR: Failed to encode user.created retry for message %s: %v", event.MessageID, err)
+			return true
+		}
+		return h.runOrDeferUserCreatedEvent(ctx, created, event.MessageID, payload)
+	}
+
+	claimed, err := h.repo.ClaimEvent(ctx, tier1RetryConsumer, event.MessageID, userCreatedClaimTTL)
 	if err != nil {
-		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
+		log.Printf("ERROR: Failed to claim tier1.verification.requested message %s: %v", event.MessageID, err)
+		return false
+	}
+	if !claimed {
+		log.Printf("Duplicate tier1.verification.requested message %s for UserID %s. Skipping.", event.MessageID, event.UserID)
 		return true
 	}
-	lastName, err := requireKYCString(event.KYCData, "lastName")
+
+	h.updateAnchorProfile(ctx, event.UserID, anchorCustomerID, event.KYCData)
+
+	// Every outcome is recorded on tier1, so the retry is done either way; a failure
+	// needs another retry from the user.
+	if err := h.repo.CompleteEvent(ctx, tier1RetryConsumer, event.MessageID); err != nil {
+		log.Printf("WARNING: Failed to mark tier1.verification.requested message %s completed: %v", event.MessageID, err)
+	}
+	return true
+}
+
+// updateAnchorProfile submits kycData as the profile of an existing Anchor customer and
+// records the tier1 outcome.
+func (h *UserEventHandler) updateAnchorProfile(ctx context.Context, userID, anchorCustomerID string, kycData map[string]interface{}) {
+	if err := h.recordOnboardingStatus(ctx, userID, "tier1", "processing", nil); err != nil {
+		log.Printf("Failed to mark tier1 processing for user %s: %v", userID, err)
+	}
+
+	firstName, err := requireKYCString(kycData, "firstName")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	email, err := requireKYCString(event.KYCData, "email")
+	lastName, err := requireKYCString(kycData, "lastName")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	phoneNumber, err := requireKYCString(event.KYCData, "phoneNumber")
+	email, err := requireKYCString(kycData, "email")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
+	}
+	phoneNumber, err := requireKYCString(kycData, "phoneNumber")
+	if err != nil {
+		reason := err.Error()
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
 	normalizedPhoneNumber, err := normalizeAnchorNigerianPhone(phoneNumber)
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	addressLine1, err := requireKYCString(event.KYCData, "addressLine1")
+	addressLine1, err := requireKYCString(kycData, "addressLine1")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	city, err := requireKYCString(event.KYCData, "city")
+	city, err := requireKYCString(kycData, "city")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	state, err := requireKYCString(event.KYCData, "state")
+	state, err := requireKYCString(kycData, "state")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	postalCode, err := requireKYCString(event.KYCData, "postalCode")
+	postalCode, err := requireKYCString(kycData, "postalCode")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
-	country, err := requireKYCString(event.KYCData, "country")
+	country, err := requireKYCString(kycData, "country")
 	if err != nil {
 		reason := err.Error()
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
 
-	middleName, _ := optionalKYCString(event.KYCData, "middleName")
-	maidenName, _ := optionalKYCString(event.KYCData, "maidenName")
-	addressLine2, _ := optionalKYCString(event.KYCData, "addressLine2")
+	middleName, _ := optionalKYCString(kycData, "middleName")
+	maidenName, _ := optionalKYCString(kycData, "maidenName")
+	addressLine2, _ := optionalKYCString(kycData, "addressLine2")
 
 	req := domain.AnchorCreateIndividualCustomerRequest{
 		Data: domain.RequestData{
@@ -318,11 +495,11 @@ func (h *UserEventHandler) HandleTier1ProfileUpdateRequestedEvent(body []byte) b
 		},
 	}
 
-	if err := h.anchorClient.UpdateIndividualCustomer(ctx, event.AnchorCustomerID, req); err != nil {
+	if err := h.anchorClient.UpdateIndividualCustomer(ctx, anchorCustomerID, req); err != nil {
 		reason := fmt.Sprintf("Failed to update Anchor customer profile: %v", err)
-		log.Printf("ERROR: %s (user_id=%s, anchor_customer_id=%s)", reason, event.UserID, event.AnchorCustomerID)
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "failed", &reason)
-		return true
+		log.Printf("ERROR: %s (user_id=%s, anchor_customer_id=%s)", reason, userID, anchorCustomerID)
+		_ = h.recordOnboardingStatus(ctx, userID, "tier1", "failed", &reason)
+		return
 	}
 
 	fullNameParts := []string{firstName}
@@ -334,14 +511,13 @@ func (h *UserEventHandler) HandleTier1ProfileUpdateRequestedEvent(body []byte) b
 		fullNameParts = append(fullNameParts, "("+maidenName+")")
 	}
 	updatedFullName := strings.Join(fullNameParts, " ")
-	_ = h.repo.UpdateAnchorCustomerInfo(ctx, event.UserID, "", &updatedFullName)
+	_ = h.repo.UpdateAnchorCustomerInfo(ctx, userID, "", &updatedFullName)
 
-	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier1", "created", nil); err != nil {
-		log.Printf("Failed to mark tier1 created after update for user %s: %v", event.UserID, err)
+	if err := h.recordOnboardingStatus(ctx, userID, "tier1", "created", nil); err != nil {
+		log.Printf("Failed to mark tier1 created after update for user %s: %v", userID, err)
 	}
 
-	log.Printf("Successfully updated Anchor customer profile for user %s", event.UserID)
-	return true
+	log.Printf("Successfully updated Anchor customer profile for user %s", userID)
 }
 
 func (h *UserEventHandler) HandleTier2VerificationRequestedEvent(body []byte) bool {
@@ -363,7 +539,7 @@ func (h *UserEventHandler) HandleTier2VerificationRequestedEvent(body []byte) bo
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier2", "processing", nil); err != nil {
+	if err := h.recordOnboardingStatus(ctx, event.UserID, "tier2", "processing", nil); err != nil {
 		log.Printf("Failed to mark tier2 processing for user %s: %v", event.UserID, err)
 	}
 
@@ -382,42 +558,38 @@ func (h *UserEventHandler) HandleTier2VerificationRequestedEvent(body []byte) bo
 	}
 
 	if err := h.anchorClient.TriggerIndividualKYC(ctx, event.AnchorCustomerID, req); err != nil {
-		lowerErr := strings.ToLower(err.Error())
-		if strings.Contains(lowerErr, "status 412") && strings.Contains(lowerErr, "kyc already completed") {
+		var apiErr *anchorclient.APIError
+		isAPIErr := errors.As(err, &apiErr)
+		if isAPIErr && isKYCAlreadyCompleted(apiErr) {
 			log.Printf("Tier2 KYC already completed on Anchor for user %s. Marking as completed.", event.UserID)
-			if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier2", "completed", nil); err != nil {
+			if err := h.recordOnboardingStatus(ctx, event.UserID, "tier2", "completed", nil); err != nil {
 				log.Printf("Failed to mark tier2 completed for user %s: %v", event.UserID, err)
 			}
 			go h.triggerAccountRecovery(event)
 			return true
 		}
 
+		// Rate limit from Anchor: ACK to avoid hot-looping and API limits
+		if isAPIErr && apiErr.IsRateLimited() {
+			log.Printf("Rate limited by Anchor for Tier2 KYC (ACK). UserID %s: %v", event.UserID, err)
+			_ = h.recordOnboardingStatus(ctx, event.UserID, "tier2", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
+			return true
+		}
+
 		// Non-retriable client errors from Anchor (4xx): ACK to stop requeue storm
-		if strings.Contains(lowerErr, "status 400") ||
-			strings.Contains(lowerErr, "status 401") ||
-			strings.Contains(lowerErr, "status 403") ||
-			strings.Contains(lowerErr, "status 404") ||
-			strings.Contains(lowerErr, "status 409") ||
-			strings.Contains(lowerErr, "status 422") {
+		if isAPIErr && apiErr.IsExplicitRejection() {
 			log.Printf("Non-retriable client error from Anchor for Tier2 KYC (ACK). UserID %s: %v", event.UserID, err)
 			msg := err.Error()
-			if strings.Contains(strings.ToLower(msg), "insufficient balance") {
-				msg = "The platform's verification account has insufficient funds. Please contact support or try again later."
+			if apiErr.Mentions("insufficient balance") {
+				msg = insufficientVerificationBalanceMessage
 			}
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier2", "failed", &msg)
-			return true
-		}
-
-		// Rate limit from Anchor: ACK to avoid hot-looping and API limits
-		if strings.Contains(lowerErr, "status 429") || strings.Contains(lowerErr, "too many requests") {
-			log.Printf("Rate limited by Anchor for Tier2 KYC (ACK). UserID %s: %v", event.UserID, err)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier2", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
+			_ = h.recordOnboardingStatus(ctx, event.UserID, "tier2", "failed", &msg)
 			return true
 		}
 
 		log.Printf("ERROR: Failed to trigger Anchor Tier2 KYC for user %s: %v", event.UserID, err)
 		reason := fmt.Sprintf("Failed to trigger Anchor Tier2 KYC: %v", err)
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier2", "failed", &reason)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier2", "failed", &reason)
 		return false
 	}
 
@@ -444,7 +616,7 @@ func (h *UserEventHandler) HandleTier3VerificationRequestedEvent(body []byte) bo
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier3", "processing", nil); err != nil {
+	if err := h.recordOnboardingStatus(ctx, event.UserID, "tier3", "processing", nil); err != nil {
 		log.Printf("Failed to mark tier3 processing for user %s: %v", event.UserID, err)
 	}
 
@@ -463,38 +635,34 @@ func (h *UserEventHandler) HandleTier3VerificationRequestedEvent(body []byte) bo
 	}
 
 	if err := h.anchorClient.TriggerIndividualKYC(ctx, event.AnchorCustomerID, req); err != nil {
-		lowerErr := strings.ToLower(err.Error())
-		if strings.Contains(lowerErr, "status 412") && strings.Contains(lowerErr, "kyc already completed") {
+		var apiErr *anchorclient.APIError
+		isAPIErr := errors.As(err, &apiErr)
+		if isAPIErr && isKYCAlreadyCompleted(apiErr) {
 			log.Printf("Tier3 KYC already completed on Anchor for user %s. Marking as completed.", event.UserID)
-			if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier3", "completed", nil); err != nil {
+			if err := h.recordOnboardingStatus(ctx, event.UserID, "tier3", "completed", nil); err != nil {
 				log.Printf("Failed to mark tier3 completed for user %s: %v", event.UserID, err)
 			}
 			return true
 		}
 
-		// Non-retriable client errors from Anchor (4xx): ACK to stop requeue storm
-		if strings.Contains(lowerErr, "status 400") ||
-			strings.Contains(lowerErr, "status 401") ||
-			strings.Contains(lowerErr, "status 403") ||
-			strings.Contains(lowerErr, "status 404") ||
-			strings.Contains(lowerErr, "status 409") ||
-			strings.Contains(lowerErr, "status 422") {
-			log.Printf("Non-retriable client error from Anchor for Tier3 KYC (ACK). UserID %s: %v", event.UserID, err)
-			reason := fmt.Sprintf("Anchor API error: %v", err)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier3", "failed", &reason)
+		// Rate limit from Anchor: ACK to avoid hot-looping and API limits
+		if isAPIErr && apiErr.IsRateLimited() {
+			log.Printf("Rate limited by Anchor for Tier3 KYC (ACK). UserID %s: %v", event.UserID, err)
+			_ = h.recordOnboardingStatus(ctx, event.UserID, "tier3", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
 			return true
 		}
 
-		// Rate limit from Anchor: ACK to avoid hot-looping and API limits
-		if strings.Contains(lowerErr, "status 429") || strings.Contains(lowerErr, "too many requests") {
-			log.Printf("Rate limited by Anchor for Tier3 KYC (ACK). UserID %s: %v", event.UserID, err)
-			_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier3", "rate_limited", ptr("Rate limited by Anchor API. Please try again later."))
+		// Non-retriable client errors from Anchor (4xx): ACK to stop requeue storm
+		if isAPIErr && apiErr.IsExplicitRejection() {
+			log.Printf("Non-retriable client error from Anchor for Tier3 KYC (ACK). UserID %s: %v", event.UserID, err)
+			reason := fmt.Sprintf("Anchor API error: %v", err)
+			_ = h.recordOnboardingStatus(ctx, event.UserID, "tier3", "failed", &reason)
 			return true
 		}
 
 		log.Printf("ERROR: Failed to trigger Anchor Tier3 KYC for user %s: %v", event.UserID, err)
 		reason := fmt.Sprintf("Failed to trigger Anchor Tier3 KYC: %v", err)
-		_ = h.repo.UpsertOnboardingStatus(ctx, event.UserID, "tier3", "failed", &reason)
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier3", "failed", &reason)
 		return false
 	}
 
@@ -602,11 +770,27 @@ func (h *UserEventHandler) HandleTierStatusEvent(body []byte) bool {
 		}
 	}
 
-	if err := h.repo.UpsertOnboardingStatus(ctx, event.UserID, stage, normalizedStatus, event.Reason); err != nil {
+	if err := h.recordOnboardingStatus(ctx, event.UserID, stage, normalizedStatus, event.Reason); err != nil {
 		log.Printf("Failed to persist tier status %s/%s for user %s: %v", stage, normalizedStatus, event.UserID, err)
 		return false
 	}
 
+	if stage == "tier2" {
+		if rejected, ok := tier2RejectedDocuments(normalizedStatus, event.Reason); ok {
+			if err := h.repo.RecordTier2Outcome(ctx, event.UserID, rejected, event.Reason, event.VerificationID); err != nil {
+				log.Printf("Failed to record tier2 outcome %s for user %s: %v", normalizedStatus, event.UserID, err)
+				return false
+			}
+		}
+	}
+
+	if stage == "tier2" && normalizedStatus == "manual_review" {
+		if err := h.publishPlatformReviewRequested(ctx, event, stage, normalizedStatus); err != nil {
+			log.Printf("Failed to publish platform review request for user %s: %v", event.UserID, err)
+			return false
+		}
+	}
+
 	if shouldPublishCustomerVerifiedForTier2(stage, normalizedStatus, stageFromFallback) {
 		if err := h.publishCustomerVerifiedForTier2(ctx, event); err != nil {
 			log.Printf("Failed to publish customer.verified for user %s after tier2 %s: %v", event.UserID, normalizedStatus, err)
@@ -623,6 +807,32 @@ func (h *UserEventHandler) HandleTierStatusEvent(body []byte) bool {
 	return true
 }
 
+// HandleUserClosedEvent deactivates the Anchor customer of a user who closed their account.
+func (h *UserEventHandler) HandleUserClosedEvent(body []byte) bool {
+	var event domain.UserClosedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Error unmarshaling user.closed event: %v", err)
+		return true
+	}
+
+	if strings.TrimSpace(event.AnchorCustomerID) == "" {
+		log.Printf("user.closed event for UserID %s has no Anchor customer; nothing to deactivate", event.UserID)
+		return true
+	}
+
+	log.Printf("Processing user.closed event for UserID: %s", event.UserID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.anchorClient.DeleteCustomer(ctx, event.AnchorCustomerID); err != nil {
+		log.Printf("ERROR: Failed to deactivate Anchor customer %s for closed UserID %s: %v", event.AnchorCustomerID, event.UserID, err)
+		return true // Ack to prevent hot-looping; the customer must be deactivated manually.
+	}
+
+	log.Printf("Deactivated Anchor customer %s for closed UserID %s", event.AnchorCustomerID, event.UserID)
+	return true
+}
+
 func (h *UserEventHandler) publishCustomerVerifiedForTier2(ctx context.Context, event TierStatusEvent) error {
 	if h.publisher == nil {
 		return nil
@@ -653,6 +863,56 @@ func (h *UserEventHandler) publishCustomerVerifiedForTier2(ctx context.Context,
 	return h.publisher.Publish(ctx, "customer_events", "customer.verified", payload)
 }
 
+// publishPlatformReviewRequested asks the operations team to review a customer whose
+// verification Anchor has sent to manual review. Requests land on PlatformReviewQueue.
+func (h *UserEventHandler) publishPlatformReviewRequested(ctx context.Context, event TierStatusEvent, stage, status string) error {
+	if h.publisher == nil {
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"user_id":            event.UserID,
+		"anchor_customer_id": strings.TrimSpace(event.AnchorCustomerID),
+		"stage":              stage,
+		"status":             status,
+		"reason":             event.Reason,
+	}
+
+	return h.publisher.Publish(ctx, "customer_events", PlatformReviewRoutingKey, payload)
+}
+
+// tier2RejectedDocuments returns the Tier 2 pieces a verdict rejects, and false when
+// the status is not a verdict. A rejection whose reason names no piece rejects all
+// of them.
+func tier2RejectedDocuments(normalizedStatus string, reason *string) ([]string, bool) {
+	switch normalizedStatus {
+	case "completed", "approved":
+		return nil, true
+	case "rejected", "reenter_information":
+	default:
+		return nil, false
+	}
+
+	var rejected []string
+	if reason != nil {
+		lowered := strings.ToLower(*reason)
+		for _, document := range tier2DocumentKeywords {
+			for _, keyword := range document.keywords {
+				if strings.Contains(lowered, keyword) {
+					rejected = append(rejected, document.documentType)
+					break
+				}
+			}
+		}
+	}
+	if len(rejected) == 0 {
+		for _, document := range tier2DocumentKeywords {
+			rejected = append(rejected, document.documentType)
+		}
+	}
+	return rejected, true
+}
+
 func normalizeTierStage(stage, status string) string {
 	normalizedStage := strings.ToLower(strings.TrimSpace(stage))
 	normalizedStage = strings.ReplaceAll(normalizedStage, "-", "_")
@@ -775,7 +1035,7 @@ func (h *UserEventHandler) createPersonalCustomer(ctx context.Context, event dom
 	phoneNumber, _ := event.KYCData["phoneNumber"].(string)
 
 	if fullName == "" || email == "" || phoneNumber == "" {
-		return "", fmt.Errorf("missing required fields (fullName, email, phoneNumber) in KYCData")
+		return "", fmt.Errorf("%w (fullName, email, phoneNumber) in KYCData", errMissingRequiredFields)
 	}
 	normalizedPhoneNumber, err := normalizeAnchorNigerianPhone(phoneNumber)
 	if err != nil {
@@ -849,7 +1109,7 @@ func (h *UserEventHandler) createPersonalCustomerWithIdempotency(ctx context.Con
 	phoneNumber, _ := event.KYCData["phoneNumber"].(string)
 
 	if firstName == "" || lastName == "" || email == "" || phoneNumber == "" {
-		return "", fmt.Errorf("missing required fields (firstName, lastName, email, phoneNumber) in KYCData")
+		return "", fmt.Errorf("%w (firstName, lastName, email, phoneNumber) in KYCData", errMissingRequiredFields)
 	}
 	normalizedPhoneNumber, err := normalizeAnchorNigerianPhone(phoneNumber)
 	if err != nil {
@@ -958,3 +1218,9 @@ func normalizeAnchorNigerianPhone(value string) (string, error) {
 }
 
 func ptr(s string) *string { return &s }
+
+// isKYCAlreadyCompleted reports whether Anchor refused a KYC request because the
+// customer already passed that verification level.
+func isKYCAlreadyCompleted(apiErr *anchorclient.APIError) bool {
+	return apiErr.StatusCode == http.StatusPreconditionFailed && apiErr.Mentions("kyc already completed")
+}
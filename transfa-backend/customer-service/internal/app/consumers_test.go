@@ -1,6 +1,12 @@
 package app
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/transfa/pkg/anchorclient"
+)
 
 func TestNormalizeTierStage(t *testing.T) {
 	tests := []struct {
@@ -202,3 +208,63 @@ func TestNormalizeAnchorNigerianPhone(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleUserClosedEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		anchorStatus int
+		wantCalls    int
+	}{
+		{
+			name:         "deletes anchor customer",
+			body:         `{"user_id":"user-1","anchor_customer_id":"cust-1","closed_at":"2026-03-05T10:00:00Z"}`,
+			anchorStatus: http.StatusNoContent,
+			wantCalls:    1,
+		},
+		{
+			name:         "treats missing anchor customer as deleted",
+			body:         `{"user_id":"user-1","anchor_customer_id":"cust-1","closed_at":"2026-03-05T10:00:00Z"}`,
+			anchorStatus: http.StatusNotFound,
+			wantCalls:    1,
+		},
+		{
+			name:         "acks when anchor rejects the delete",
+			body:         `{"user_id":"user-1","anchor_customer_id":"cust-1","closed_at":"2026-03-05T10:00:00Z"}`,
+			anchorStatus: http.StatusBadRequest,
+			wantCalls:    1,
+		},
+		{
+			name:      "skips users without an anchor customer",
+			body:      `{"user_id":"user-1","closed_at":"2026-03-05T10:00:00Z"}`,
+			wantCalls: 0,
+		},
+		{
+			name:      "acks malformed payloads",
+			body:      `{`,
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/customers/cust-1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.anchorStatus)
+			}))
+			defer server.Close()
+
+			handler := NewUserEventHandler(nil, anchorclient.NewClient(server.URL, "test-key"), nil)
+			if !handler.HandleUserClosedEvent([]byte(tt.body)) {
+				t.Fatalf("expected event to be acknowledged")
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("expected %d Anchor calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/**
+ * @description
+ * This file contains the retry queue for user.created events that failed for a reason
+ * a later attempt can fix, such as an Anchor outage or rate limit. Instead of dropping
+ * the message, the consumer stores it in deferred_events and acknowledges it; a
+ * background loop re-runs due events through the same handler logic with backoff.
+ *
+ * @notes
+ * - The original delivery counts as the first attempt. After maxDeferredEventAttempts
+ *   the event is marked needs_manual_review and tier1 is failed.
+ * - Retries stay idempotent through the event inbox and the anchor_customer_id check.
+ */
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/transfa/customer-service/internal/domain"
+)
+
+// deferredEventBackoff is the delay before each retry; the first entry follows the
+// original delivery.
+var deferredEventBackoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+const (
+	// maxDeferredEventAttempts caps the attempts per event, the original delivery included.
+	maxDeferredEventAttempts = 5
+	// deferredEventBatchSize is how many due events one retry pass claims.
+	deferredEventBatchSize = 20
+	// deferredEventClaimTTL is how long a retry may stay in processing before another
+	// worker takes it over.
+	deferredEventClaimTTL = 5 * time.Minute
+)
+
+// errDeferredEventNotProcessed marks a retry that neither succeeded nor reported a
+// retriable error, e.g. because the event inbox was unavailable.
+var errDeferredEventNotProcessed = errors.New("event was not processed")
+
+// deferredEventDelay returns the wait before the attempt following attempts.
+func deferredEventDelay(attempts int) time.Duration {
+	index := attempts - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(deferredEventBackoff) {
+		index = len(deferredEventBackoff) - 1
+	}
+	return deferredEventBackoff[index]
+}
+
+// deferUserCreatedEvent queues a user.created message that failed with a retriable error.
+func (h *UserEventHandler) deferUserCreatedEvent(ctx context.Context, event domain.UserCreatedEvent, messageID string, body []byte, cause error) error {
+	var userID *string
+	if event.UserID != "" {
+		userID = &event.UserID
+	}
+	nextAttemptAt := time.Now().Add(deferredEventDelay(1))
+	if err := h.repo.DeferEvent(ctx, userCreatedConsumer, messageID, userID, body, nextAttemptAt, cause.Error()); err != nil {
+		return err
+	}
+	log.Printf("Deferred user.created message %s for UserID %s until %s: %v", messageID, event.UserID, nextAttemptAt.Format(time.RFC3339), cause)
+	return nil
+}
+
+// RunDeferredEventRetries retries due deferred events every interval until ctx is done.
+func (h *UserEventHandler) RunDeferredEventRetries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.retryDueUserCreatedEvents(ctx)
+		}
+	}
+}
+
+// retryDueUserCreatedEvents claims the due user.created events and runs each again.
+func (h *UserEventHandler) retryDueUserCreatedEvents(ctx context.Context) {
+	events, err := h.repo.ClaimDueDeferredEvents(ctx, userCreatedConsumer, deferredEventBatchSize, deferredEventClaimTTL)
+	if err != nil {
+		log.Printf("ERROR: Failed to claim deferred user.created events: %v", err)
+		return
+	}
+	for _, deferred := range events {
+		if ctx.Err() != nil {
+			return
+		}
+		h.retryUserCreatedEvent(ctx, deferred)
+	}
+}
+
+// retryUserCreatedEvent runs one deferred user.created event and records the outcome.
+func (h *UserEventHandler) retryUserCreatedEvent(ctx context.Context, deferred domain.DeferredEvent) {
+	var event domain.UserCreatedEvent
+	if err := json.Unmarshal(deferred.Payload, &event); err != nil {
+		log.Printf("ERROR: Deferred user.created event %d has a malformed payload: %v", deferred.ID, err)
+		_ = h.repo.RecordDeferredEventFailure(ctx, deferred.ID, deferred.Attempts, nil, err.Error())
+		return
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ack, retryErr := h.runUserCreatedEvent(attemptCtx, event, deferred.MessageID)
+	if retryErr == nil && ack {
+		if err := h.repo.CompleteDeferredEvent(ctx, deferred.ID); err != nil {
+			log.Printf("WARNING: Failed to mark deferred event %d completed: %v", deferred.ID, err)
+		}
+		return
+	}
+	if retryErr == nil {
+		retryErr = errDeferredEventNotProcessed
+	}
+
+	attempts := deferred.Attempts + 1
+	if attempts >= maxDeferredEventAttempts {
+		log.Printf("CRITICAL: user.created message %s for UserID %s failed after %d attempts; needs manual review: %v", deferred.MessageID, event.UserID, attempts, retryErr)
+		if err := h.repo.RecordDeferredEventFailure(ctx, deferred.ID, attempts, nil, retryErr.Error()); err != nil {
+			return
+		}
+		_ = h.recordOnboardingStatus(ctx, event.UserID, "tier1", "failed", ptr(anchorRetryExhaustedMessage))
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(deferredEventDelay(attempts))
+	log.Printf("Retry %d of user.created message %s for UserID %s failed; next attempt at %s: %v", attempts, deferred.MessageID, event.UserID, nextAttemptAt.Format(time.RFC3339), retryErr)
+	_ = h.repo.RecordDeferredEventFailure(ctx, deferred.ID, attempts, &nextAttemptAt, retryErr.Error())
+}
+
+// ListDeferredEvents returns deferred events in status, most recently updated first.
+func (h *UserEventHandler) ListDeferredEvents(ctx context.Context, status string, limit int) ([]domain.DeferredEvent, error) {
+	return h.repo.ListDeferredEvents(ctx, status, limit)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/transfa/customer-service/internal/domain"
+	"github.com/transfa/pkg/anchorclient"
+)
+
+// newFlakyAnchorCustomerServer answers customer creation with a 500 until healthy is set.
+func newFlakyAnchorCustomerServer(t *testing.T, healthy *atomic.Bool, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"errors":[{"title":"Server Error","detail":"upstream failure","status":"500"}]}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"data":{"id":"cust-1","type":"IndividualCustomer"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleUserCreatedEvent_DefersRetriableFailure(t *testing.T) {
+	repo := newInboxRepoStub()
+	var healthy atomic.Bool
+	var calls int32
+	server := newFlakyAnchorCustomerServer(t, &healthy, &calls)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	before := time.Now()
+	if !handler.HandleUserCreatedEvent([]byte(userCreatedBody)) {
+		t.Fatalf("expected the failed delivery to be acknowledged once deferred")
+	}
+	if len(repo.deferred) != 1 {
+		t.Fatalf("expected the event to be deferred, got %d deferred events", len(repo.deferred))
+	}
+	deferred := repo.deferred[0]
+	if deferred.MessageID != "user-1" || deferred.Attempts != 1 || string(deferred.Payload) != userCreatedBody {
+		t.Fatalf("unexpected deferred event: %+v", deferred)
+	}
+	if wait := deferred.NextAttemptAt.Sub(before); wait < time.Minute || wait > time.Minute+5*time.Second {
+		t.Fatalf("expected the first retry a minute out, got %s", wait)
+	}
+
+	healthy.Store(true)
+	handler.retryDueUserCreatedEvents(context.Background())
+
+	if repo.deferred[0].Status != domain.DeferredEventCompleted {
+		t.Fatalf("expected the retried event to complete, got %s", repo.deferred[0].Status)
+	}
+	if repo.anchorID["user-1"] != "cust-1" {
+		t.Fatalf("expected the retry to link the Anchor customer, got %q", repo.anchorID["user-1"])
+	}
+	if calls != 2 {
+		t.Fatalf("expected one Anchor call per attempt, got %d", calls)
+	}
+}
+
+func TestHandleUserCreatedEvent_DoesNotDeferRejections(t *testing.T) {
+	repo := newInboxRepoStub()
+	var calls int32
+	server := newAnchorCustomerServer(t, http.StatusUnprocessableEntity, &calls, nil)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	if !handler.HandleUserCreatedEvent([]byte(userCreatedBody)) {
+		t.Fatalf("expected message to be acknowledged")
+	}
+	if len(repo.deferred) != 0 {
+		t.Fatalf("expected an explicit rejection not to be retried, got %+v", repo.deferred)
+	}
+}
+
+func TestRetryDueUserCreatedEvents_BacksOffThenNeedsManualReview(t *testing.T) {
+	repo := newInboxRepoStub()
+	var healthy atomic.Bool
+	var calls int32
+	server := newFlakyAnchorCustomerServer(t, &healthy, &calls)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	if !handler.HandleUserCreatedEvent([]byte(userCreatedBody)) {
+		t.Fatalf("expected the failed delivery to be acknowledged once deferred")
+	}
+
+	for attempt := 2; attempt < maxDeferredEventAttempts; attempt++ {
+		before := time.Now()
+		handler.retryDueUserCreatedEvents(context.Background())
+
+		deferred := repo.deferred[0]
+		if deferred.Status != domain.DeferredEventPending || deferred.Attempts != attempt {
+			t.Fatalf("attempt %d: expected pending with %d attempts, got %s with %d", attempt, attempt, deferred.Status, deferred.Attempts)
+		}
+		want := deferredEventBackoff[attempt-1]
+		if wait := deferred.NextAttemptAt.Sub(before); wait < want || wait > want+5*time.Second {
+			t.Fatalf("attempt %d: expected the next retry %s out, got %s", attempt, want, wait)
+		}
+		if status := repo.statuses["user-1/tier1"]; status.status != "processing" {
+			t.Fatalf("attempt %d: expected tier1 to stay processing, got %s", attempt, status.status)
+		}
+	}
+
+	handler.retryDueUserCreatedEvents(context.Background())
+
+	deferred := repo.deferred[0]
+	if deferred.Status != domain.DeferredEventNeedsManualReview || deferred.Attempts != maxDeferredEventAttempts {
+		t.Fatalf("expected needs_manual_review after %d attempts, got %s with %d", maxDeferredEventAttempts, deferred.Status, deferred.Attempts)
+	}
+	if calls != maxDeferredEventAttempts {
+		t.Fatalf("expected %d Anchor calls, got %d", maxDeferredEventAttempts, calls)
+	}
+	status := repo.statuses["user-1/tier1"]
+	if status.status != "failed" || status.reason != anchorRetryExhaustedMessage {
+		t.Fatalf("expected tier1 failed (%q), got %s (%q)", anchorRetryExhaustedMessage, status.status, status.reason)
+	}
+
+	handler.retryDueUserCreatedEvents(context.Background())
+	if calls != maxDeferredEventAttempts {
+		t.Fatalf("expected no retry after manual review, got %d Anchor calls", calls)
+	}
+}
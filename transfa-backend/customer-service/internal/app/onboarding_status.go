@@ -0,0 +1,63 @@
+/**
+ * @description
+ * This file reports onboarding stage changes. auth-service owns onboarding_status and
+ * keeps it from the onboarding.stage.changed events published here.
+ *
+ * @notes
+ * - While the migration to events rolls out, the handler also writes onboarding_status
+ *   directly (dual-write). Turn it off with ONBOARDING_STATUS_DUAL_WRITE=false once
+ *   auth-service consumes the events.
+ */
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/transfa/customer-service/internal/domain"
+)
+
+const (
+	// OnboardingEventsExchange carries onboarding.stage.changed.
+	OnboardingEventsExchange = "customer_events"
+	// OnboardingStageChangedRoutingKey is published whenever an onboarding stage changes.
+	OnboardingStageChangedRoutingKey = "onboarding.stage.changed"
+)
+
+// HandlerOption configures a UserEventHandler.
+type HandlerOption func(*UserEventHandler)
+
+// WithOnboardingStatusDualWrite sets whether the handler writes onboarding_status
+// itself in addition to publishing onboarding.stage.changed. It is on by default.
+func WithOnboardingStatusDualWrite(enabled bool) HandlerOption {
+	return func(h *UserEventHandler) {
+		h.onboardingStatusDualWrite = enabled
+	}
+}
+
+// recordOnboardingStatus publishes the new status of a user's onboarding stage and,
+// while dual-write is on, writes it to onboarding_status too. With dual-write off a
+// failed publish is returned, since the change would otherwise be lost.
+func (h *UserEventHandler) recordOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	event := domain.OnboardingStageChangedEvent{
+		UserID:     userID,
+		Stage:      stage,
+		Status:     status,
+		Reason:     reason,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	var publishErr error
+	if h.publisher != nil {
+		publishErr = h.publisher.Publish(ctx, OnboardingEventsExchange, OnboardingStageChangedRoutingKey, event)
+		if publishErr != nil {
+			log.Printf("WARN: Failed to publish %s %s for user %s: %v", stage, status, userID, publishErr)
+		}
+	}
+
+	if !h.onboardingStatusDualWrite {
+		return publishErr
+	}
+	return h.repo.UpsertOnboardingStatus(ctx, userID, stage, status, reason)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/transfa/customer-service/internal/domain"
+	"github.com/transfa/customer-service/internal/store"
+)
+
+// statusWriteRepoStub counts direct onboarding_status writes.
+type statusWriteRepoStub struct {
+	store.UserRepository
+	writes int
+}
+
+func (s *statusWriteRepoStub) UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	s.writes++
+	return nil
+}
+
+func TestRecordOnboardingStatus_PublishesStageChange(t *testing.T) {
+	for _, dualWrite := range []bool{true, false} {
+		repo := &statusWriteRepoStub{}
+		publisher := &recordingPublisher{}
+		handler := NewUserEventHandler(repo, nil, publisher, WithOnboardingStatusDualWrite(dualWrite))
+
+		if err := handler.recordOnboardingStatus(context.Background(), "user-1", "tier1", "failed", ptr("BVN mismatch")); err != nil {
+			t.Fatalf("dual-write %t: unexpected error %v", dualWrite, err)
+		}
+		if len(publisher.stageChanges) != 1 {
+			t.Fatalf("dual-write %t: expected one onboarding.stage.changed event, got %d", dualWrite, len(publisher.stageChanges))
+		}
+		change := publisher.stageChanges[0]
+		event := change.payload.(domain.OnboardingStageChangedEvent)
+		if change.exchange != OnboardingEventsExchange || event.UserID != "user-1" || event.Stage != "tier1" || event.Status != "failed" ||
+			event.Reason == nil || *event.Reason != "BVN mismatch" || event.OccurredAt.IsZero() {
+			t.Fatalf("dual-write %t: unexpected event %+v on %s", dualWrite, event, change.exchange)
+		}
+
+		wantWrites := 0
+		if dualWrite {
+			wantWrites = 1
+		}
+		if repo.writes != wantWrites {
+			t.Fatalf("dual-write %t: expected %d direct writes, got %d", dualWrite, wantWrites, repo.writes)
+		}
+	}
+}
+
+func TestRecordOnboardingStatus_PublishFailure(t *testing.T) {
+	publishErr := errors.New("broker down")
+
+	repo := &statusWriteRepoStub{}
+	handler := NewUserEventHandler(repo, nil, &recordingPublisher{err: publishErr})
+	if err := handler.recordOnboardingStatus(context.Background(), "user-1", "tier2", "completed", nil); err != nil {
+		t.Fatalf("expected the direct write to cover a failed publish, got %v", err)
+	}
+	if repo.writes != 1 {
+		t.Fatalf("expected a direct write, got %d", repo.writes)
+	}
+
+	handler = NewUserEventHandler(repo, nil, &recordingPublisher{err: publishErr}, WithOnboardingStatusDualWrite(false))
+	if err := handler.recordOnboardingStatus(context.Background(), "user-1", "tier2", "completed", nil); !errors.Is(err, publishErr) {
+		t.Fatalf("expected the publish error without dual-write, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/transfa/pkg/anchorclient"
+)
+
+const tier1RetryBody = `{"message_id":"user-1:tier1-retry:1","user_id":"user-1","kyc_data":{"userType":"personal","firstName":"Ada","lastName":"Obi","email":"ada@example.com","phoneNumber":"08181664488","addressLine1":"1 Test Street","city":"Lagos","state":"Lagos","postalCode":"100001","country":"NG"}}`
+
+func TestHandleTier1VerificationRequestedEvent_ResubmitsLinkedCustomer(t *testing.T) {
+	repo := newInboxRepoStub()
+	repo.anchorID["user-1"] = "cust-1"
+	repo.inbox[userCreatedConsumer+"/user-1"] = "completed"
+	repo.statuses["user-1/tier1"] = onboardingStatus{status: "processing"}
+
+	var updates, creates int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/customers/update/cust-1":
+			atomic.AddInt32(&updates, 1)
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/customers":
+			atomic.AddInt32(&creates, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	for i := 0; i < 2; i++ {
+		if !handler.HandleTier1VerificationRequestedEvent([]byte(tier1RetryBody)) {
+			t.Fatalf("expected delivery %d to be acknowledged", i+1)
+		}
+	}
+
+	if got := atomic.LoadInt32(&updates); got != 1 {
+		t.Fatalf("expected the linked customer's profile to be re-submitted once, got %d", got)
+	}
+	if got := atomic.LoadInt32(&creates); got != 0 {
+		t.Fatalf("expected no customer creation for a linked user, got %d", got)
+	}
+	if got := repo.statuses["user-1/tier1"].status; got != "created" {
+		t.Fatalf("expected tier1 to leave processing, got %q", got)
+	}
+	if status := repo.inbox[tier1RetryConsumer+"/user-1:tier1-retry:1"]; status != "completed" {
+		t.Fatalf("expected retry message to be completed, got %q", status)
+	}
+}
+
+func TestHandleTier1VerificationRequestedEvent_RecordsFailedResubmission(t *testing.T) {
+	repo := newInboxRepoStub()
+	repo.anchorID["user-1"] = "cust-1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"Unprocessable Entity","detail":"phoneNumber is invalid","status":"422"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	if !handler.HandleTier1VerificationRequestedEvent([]byte(tier1RetryBody)) {
+		t.Fatal("expected the retry to be acknowledged")
+	}
+	if got := repo.statuses["user-1/tier1"].status; got != "failed" {
+		t.Fatalf("expected a rejected re-submission to fail tier1 again, got %q", got)
+	}
+}
+
+func TestHandleTier1VerificationRequestedEvent_CreatesUnlinkedCustomer(t *testing.T) {
+	repo := newInboxRepoStub()
+	repo.inbox[userCreatedConsumer+"/user-1"] = "completed"
+
+	var calls int32
+	server := newAnchorCustomerServer(t, http.StatusCreated, &calls, nil)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	if !handler.HandleTier1VerificationRequestedEvent([]byte(tier1RetryBody)) {
+		t.Fatal("expected the retry to be acknowledged")
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected the retry to reach Anchor despite the earlier user.created message, got %d calls", got)
+	}
+	if repo.anchorID["user-1"] != "cust-1" {
+		t.Fatalf("expected user to be linked to cust-1, got %q", repo.anchorID["user-1"])
+	}
+	if got := repo.statuses["user-1/tier1"].status; got != "created" {
+		t.Fatalf("expected tier1 created, got %q", got)
+	}
+}
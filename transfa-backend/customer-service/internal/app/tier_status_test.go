@@ -0,0 +1,236 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// publishedEvent is one message sent through recordingPublisher.
+type publishedEvent struct {
+	exchange   string
+	routingKey string
+	payload    interface{}
+}
+
+// recordingPublisher records published events, keeping onboarding.stage.changed
+// events apart in stageChanges.
+type recordingPublisher struct {
+	events       []publishedEvent
+	stageChanges []publishedEvent
+	err          error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, exchange, routingKey string, payload interface{}) error {
+	event := publishedEvent{exchange: exchange, routingKey: routingKey, payload: payload}
+	if routingKey == OnboardingStageChangedRoutingKey {
+		p.stageChanges = append(p.stageChanges, event)
+	} else {
+		p.events = append(p.events, event)
+	}
+	return p.err
+}
+
+func (p *recordingPublisher) routingKeys() []string {
+	keys := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		keys = append(keys, event.routingKey)
+	}
+	return keys
+}
+
+func TestHandleTierStatusEvent_Tier2Statuses(t *testing.T) {
+	cases := []struct {
+		name           string
+		body           string
+		wantStatus     string
+		wantReason     string
+		wantRoutingKey string
+	}{
+		{
+			name:           "approved publishes customer.verified",
+			body:           `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"approved"}`,
+			wantStatus:     "approved",
+			wantRoutingKey: "customer.verified",
+		},
+		{
+			name:           "completed publishes customer.verified",
+			body:           `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"tier2_completed"}`,
+			wantStatus:     "completed",
+			wantRoutingKey: "customer.verified",
+		},
+		{
+			name:       "rejected keeps the reason",
+			body:       `{"user_id":"user-1","anchor_customer_id":"cust-1","status":"tier2_rejected","reason":"BVN name mismatch"}`,
+			wantStatus: "rejected",
+			wantReason: "BVN name mismatch",
+		},
+		{
+			name:           "manual review requests a platform review",
+			body:           `{"user_id":"user-1","anchor_customer_id":"cust-1","status":"tier2_manual_review","reason":"document unclear"}`,
+			wantStatus:     "manual_review",
+			wantReason:     "document unclear",
+			wantRoutingKey: PlatformReviewRoutingKey,
+		},
+		{
+			name:       "awaiting document",
+			body:       `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"awaiting-document"}`,
+			wantStatus: "awaiting_document",
+		},
+		{
+			name:       "reenter information",
+			body:       `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"reenterinformation","reason":"date of birth invalid"}`,
+			wantStatus: "reenter_information",
+			wantReason: "date of birth invalid",
+		},
+		{
+			name:       "error",
+			body:       `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"tier2_error","reason":"upstream timeout"}`,
+			wantStatus: "error",
+			wantReason: "upstream timeout",
+		},
+		{
+			name:       "pending",
+			body:       `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"pending"}`,
+			wantStatus: "pending",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newInboxRepoStub()
+			publisher := &recordingPublisher{}
+			handler := NewUserEventHandler(repo, nil, publisher)
+
+			if !handler.HandleTierStatusEvent([]byte(tc.body)) {
+				t.Fatal("expected the event to be acknowledged")
+			}
+
+			got, ok := repo.statuses["user-1/tier2"]
+			if !ok {
+				t.Fatalf("expected a tier2 onboarding status, got %v", repo.statuses)
+			}
+			if got.status != tc.wantStatus || got.reason != tc.wantReason {
+				t.Fatalf("expected %s (reason %q), got %s (reason %q)", tc.wantStatus, tc.wantReason, got.status, got.reason)
+			}
+
+			keys := publisher.routingKeys()
+			if tc.wantRoutingKey == "" {
+				if len(keys) != 0 {
+					t.Fatalf("expected nothing to be published, got %v", keys)
+				}
+				return
+			}
+			if len(keys) != 1 || keys[0] != tc.wantRoutingKey {
+				t.Fatalf("expected one %s event, got %v", tc.wantRoutingKey, keys)
+			}
+		})
+	}
+}
+
+func TestHandleTierStatusEvent_PlatformReviewCarriesReason(t *testing.T) {
+	repo := newInboxRepoStub()
+	publisher := &recordingPublisher{}
+	handler := NewUserEventHandler(repo, nil, publisher)
+
+	body := `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"manual_review","reason":"selfie mismatch"}`
+	if !handler.HandleTierStatusEvent([]byte(body)) {
+		t.Fatal("expected the event to be acknowledged")
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one published event, got %d", len(publisher.events))
+	}
+	event := publisher.events[0]
+	if event.exchange != "customer_events" {
+		t.Fatalf("expected customer_events exchange, got %s", event.exchange)
+	}
+	payload, ok := event.payload.(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected payload type %T", event.payload)
+	}
+	reason, _ := payload["reason"].(*string)
+	if reason == nil || *reason != "selfie mismatch" {
+		t.Fatalf("expected the review request to carry the reason, got %v", payload["reason"])
+	}
+	if payload["user_id"] != "user-1" || payload["anchor_customer_id"] != "cust-1" {
+		t.Fatalf("unexpected review payload %v", payload)
+	}
+}
+
+func TestHandleTierStatusEvent_Tier3ManualReviewIsNotEscalated(t *testing.T) {
+	repo := newInboxRepoStub()
+	publisher := &recordingPublisher{}
+	handler := NewUserEventHandler(repo, nil, publisher)
+
+	body := `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier3","status":"manual_review"}`
+	if !handler.HandleTierStatusEvent([]byte(body)) {
+		t.Fatal("expected the event to be acknowledged")
+	}
+	if repo.statuses["user-1/tier3"].status != "manual_review" {
+		t.Fatalf("expected tier3 manual_review, got %v", repo.statuses)
+	}
+	if len(publisher.events) != 0 {
+		t.Fatalf("expected no platform review request for tier3, got %v", publisher.routingKeys())
+	}
+}
+
+func TestHandleTierStatusEvent_RecordsTier2PieceOutcomes(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "rejection naming the date of birth",
+			body: `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"rejected","reason":"Date of birth mismatch"}`,
+			want: []string{"date_of_birth"},
+		},
+		{
+			name: "reenter information naming the gender",
+			body: `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"reenter_information","reason":"gender mismatch"}`,
+			want: []string{"gender"},
+		},
+		{
+			name: "rejection without a recognisable piece",
+			body: `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"rejected","reason":"verification failed"}`,
+			want: []string{"bvn", "date_of_birth", "gender"},
+		},
+		{
+			name: "approval accepts everything",
+			body: `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"completed"}`,
+			want: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newInboxRepoStub()
+			handler := NewUserEventHandler(repo, nil, &recordingPublisher{})
+
+			if !handler.HandleTierStatusEvent([]byte(tc.body)) {
+				t.Fatal("expected the event to be acknowledged")
+			}
+			got, ok := repo.tier2Outcomes["user-1"]
+			if !ok {
+				t.Fatal("expected a tier2 outcome to be recorded")
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected rejected pieces %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleTierStatusEvent_PendingDoesNotRecordTier2Outcome(t *testing.T) {
+	repo := newInboxRepoStub()
+	handler := NewUserEventHandler(repo, nil, &recordingPublisher{})
+
+	body := `{"user_id":"user-1","anchor_customer_id":"cust-1","stage":"tier2","status":"manual_review"}`
+	if !handler.HandleTierStatusEvent([]byte(body)) {
+		t.Fatal("expected the event to be acknowledged")
+	}
+	if _, ok := repo.tier2Outcomes["user-1"]; ok {
+		t.Fatalf("expected no tier2 outcome while review is ongoing, got %v", repo.tier2Outcomes)
+	}
+}
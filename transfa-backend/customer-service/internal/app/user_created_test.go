@@ -0,0 +1,367 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/transfa/customer-service/internal/domain"
+	"github.com/transfa/customer-service/internal/store"
+	"github.com/transfa/pkg/anchorclient"
+)
+
+const userCreatedBody = `{"message_id":"user-1","user_id":"user-1","kyc_data":{"userType":"personal","firstName":"Ada","lastName":"Obi","email":"ada@example.com","phoneNumber":"08181664488","addressLine1":"1 Test Street","city":"Lagos","state":"Lagos","postalCode":"100001","country":"NG"}}`
+
+// inboxRepoStub keeps the event inbox and Anchor links in memory.
+type inboxRepoStub struct {
+	store.UserRepository
+
+	mu       sync.Mutex
+	inbox    map[string]string
+	anchorID map[string]string
+	statuses map[string]onboardingStatus
+
+	// tier2Outcomes holds the pieces each user's latest Tier 2 verdict rejected.
+	tier2Outcomes map[string][]string
+
+	// deferred is the deferred_events retry queue.
+	deferred []domain.DeferredEvent
+
+	// claimed is signalled after every ClaimEvent call so tests can hold Anchor
+	// until all racing deliveries have attempted their claim.
+	claimed *sync.WaitGroup
+}
+
+func newInboxRepoStub() *inboxRepoStub {
+	return &inboxRepoStub{inbox: map[string]string{}, anchorID: map[string]string{}, statuses: map[string]onboardingStatus{}}
+}
+
+func (s *inboxRepoStub) ClaimEvent(ctx context.Context, consumer, messageID string, staleAfter time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimed != nil {
+		defer s.claimed.Done()
+	}
+	key := consumer + "/" + messageID
+	if _, exists := s.inbox[key]; exists {
+		return false, nil
+	}
+	s.inbox[key] = "processing"
+	return true, nil
+}
+
+func (s *inboxRepoStub) CompleteEvent(ctx context.Context, consumer, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inbox[consumer+"/"+messageID] = "completed"
+	return nil
+}
+
+func (s *inboxRepoStub) ReleaseEvent(ctx context.Context, consumer, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := consumer + "/" + messageID
+	if s.inbox[key] == "processing" {
+		delete(s.inbox, key)
+	}
+	return nil
+}
+
+func (s *inboxRepoStub) GetAnchorCustomerIDByUserID(ctx context.Context, userID string) (*string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.anchorID[userID]; ok {
+		return &id, nil
+	}
+	return nil, nil
+}
+
+func (s *inboxRepoStub) UpdateAnchorCustomerID(ctx context.Context, userID, anchorCustomerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anchorID[userID] = anchorCustomerID
+	return nil
+}
+
+// UpdateAnchorCustomerInfo leaves the link alone when anchorCustomerID is empty, as
+// the Postgres repository only updates the name then.
+func (s *inboxRepoStub) UpdateAnchorCustomerInfo(ctx context.Context, userID, anchorCustomerID string, fullName *string) error {
+	if anchorCustomerID == "" {
+		return nil
+	}
+	return s.UpdateAnchorCustomerID(ctx, userID, anchorCustomerID)
+}
+
+func (s *inboxRepoStub) UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recorded := onboardingStatus{status: status}
+	if reason != nil {
+		recorded.reason = *reason
+	}
+	s.statuses[userID+"/"+stage] = recorded
+	return nil
+}
+
+func (s *inboxRepoStub) RecordTier2Outcome(ctx context.Context, userID string, rejected []string, reason *string, verificationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tier2Outcomes == nil {
+		s.tier2Outcomes = map[string][]string{}
+	}
+	s.tier2Outcomes[userID] = append([]string{}, rejected...)
+	return nil
+}
+
+func (s *inboxRepoStub) DeferEvent(ctx context.Context, consumer, messageID string, userID *string, payload []byte, nextAttemptAt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.deferred {
+		if event.Consumer == consumer && event.MessageID == messageID {
+			return nil
+		}
+	}
+	s.deferred = append(s.deferred, domain.DeferredEvent{
+		ID:            int64(len(s.deferred) + 1),
+		Consumer:      consumer,
+		MessageID:     messageID,
+		UserID:        userID,
+		Payload:       payload,
+		Status:        domain.DeferredEventPending,
+		Attempts:      1,
+		NextAttemptAt: nextAttemptAt,
+		LastError:     &lastError,
+	})
+	return nil
+}
+
+func (s *inboxRepoStub) ClaimDueDeferredEvents(ctx context.Context, consumer string, limit int, staleAfter time.Duration) ([]domain.DeferredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []domain.DeferredEvent
+	for i := range s.deferred {
+		if s.deferred[i].Consumer == consumer && s.deferred[i].Status == domain.DeferredEventPending && len(due) < limit {
+			s.deferred[i].Status = domain.DeferredEventProcessing
+			due = append(due, s.deferred[i])
+		}
+	}
+	return due, nil
+}
+
+func (s *inboxRepoStub) CompleteDeferredEvent(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deferred[id-1].Status = domain.DeferredEventCompleted
+	return nil
+}
+
+func (s *inboxRepoStub) RecordDeferredEventFailure(ctx context.Context, id int64, attempts int, nextAttemptAt *time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event := &s.deferred[id-1]
+	event.Attempts = attempts
+	event.LastError = &lastError
+	event.Status = domain.DeferredEventNeedsManualReview
+	if nextAttemptAt != nil {
+		event.Status = domain.DeferredEventPending
+		event.NextAttemptAt = *nextAttemptAt
+	}
+	return nil
+}
+
+type onboardingStatus struct {
+	status string
+	reason string
+}
+
+func newAnchorCustomerServer(t *testing.T, status int, calls *int32, before func()) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/customers" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		if before != nil {
+			before()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status >= http.StatusBadRequest {
+			_, _ = io.WriteString(w, `{"errors":[{"title":"Server Error","detail":"upstream failure","status":"500"}]}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"id":"cust-1","type":"IndividualCustomer"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleUserCreatedEvent_ConcurrentDuplicatesCallAnchorOnce(t *testing.T) {
+	repo := newInboxRepoStub()
+	repo.claimed = &sync.WaitGroup{}
+	repo.claimed.Add(2)
+
+	var calls int32
+	server := newAnchorCustomerServer(t, http.StatusCreated, &calls, repo.claimed.Wait)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	var wg sync.WaitGroup
+	acks := make([]bool, 2)
+	for i := range acks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			acks[i] = handler.HandleUserCreatedEvent([]byte(userCreatedBody))
+		}(i)
+	}
+	wg.Wait()
+
+	if !acks[0] || !acks[1] {
+		t.Fatalf("expected both deliveries to be acknowledged, got %v", acks)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected 1 Anchor customer creation, got %d", got)
+	}
+	if repo.anchorID["user-1"] != "cust-1" {
+		t.Fatalf("expected user to be linked to cust-1, got %q", repo.anchorID["user-1"])
+	}
+	if status := repo.inbox[userCreatedConsumer+"/user-1"]; status != "completed" {
+		t.Fatalf("expected message to be completed, got %q", status)
+	}
+}
+
+func TestHandleUserCreatedEvent_ReleasesClaimWhenAnchorFails(t *testing.T) {
+	repo := newInboxRepoStub()
+
+	var calls int32
+	server := newAnchorCustomerServer(t, http.StatusInternalServerError, &calls, nil)
+	handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+	for i := 0; i < 2; i++ {
+		if !handler.HandleUserCreatedEvent([]byte(userCreatedBody)) {
+			t.Fatalf("expected delivery %d to be acknowledged", i+1)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected a retry after a failed attempt to reach Anchor, got %d calls", got)
+	}
+	if len(repo.inbox) != 0 {
+		t.Fatalf("expected failed claims to be released, got %v", repo.inbox)
+	}
+}
+
+func TestHandleUserCreatedEvent_AnchorErrorHandling(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		status     int
+		response   string
+		wantCalls  int32
+		wantLinked string
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "missing required fields",
+			body:       `{"message_id":"user-1","user_id":"user-1","kyc_data":{"userType":"personal","firstName":"Ada"}}`,
+			wantStatus: "failed",
+			wantReason: "missing required fields (firstName, lastName, email, phoneNumber) in KYCData",
+		},
+		{
+			name:       "customer already exists with ID",
+			status:     http.StatusBadRequest,
+			response:   `{"errors":[{"title":"Bad Request","detail":"Customer already exists: 17587033450610-anc_ind_cst","status":"400"}]}`,
+			wantCalls:  1,
+			wantLinked: "17587033450610-anc_ind_cst",
+			wantStatus: "created",
+		},
+		{
+			name:       "customer already exists without ID",
+			status:     http.StatusBadRequest,
+			response:   `{"errors":[{"title":"Bad Request","detail":"Customer already exists","status":"400"}]}`,
+			wantCalls:  1,
+			wantStatus: "system_error",
+			wantReason: "Customer exists on Anchor but not linked in database. Manual intervention required.",
+		},
+		{
+			name:       "rate limited",
+			status:     http.StatusTooManyRequests,
+			response:   `{"errors":[{"title":"Too Many Requests","detail":"slow down","status":"429"}]}`,
+			wantCalls:  1,
+			wantStatus: "rate_limited",
+			wantReason: "Rate limited by Anchor API. Please try again later.",
+		},
+		{
+			name:       "validation error",
+			status:     http.StatusUnprocessableEntity,
+			response:   `{"errors":[{"title":"Unprocessable Entity","detail":"phoneNumber is invalid","status":"422"}]}`,
+			wantCalls:  1,
+			wantStatus: "failed",
+			wantReason: "anchor API request failed with status 422: Unprocessable Entity - phoneNumber is invalid",
+		},
+		{
+			name:       "insufficient verification balance",
+			status:     http.StatusBadRequest,
+			response:   `{"errors":[{"title":"Bad Request","detail":"Insufficient balance for verification","status":"400"}]}`,
+			wantCalls:  1,
+			wantStatus: "failed",
+			wantReason: insufficientVerificationBalanceMessage,
+		},
+		{
+			name:       "server error",
+			status:     http.StatusInternalServerError,
+			response:   `{"errors":[{"title":"Server Error","detail":"upstream failure","status":"500"}]}`,
+			wantCalls:  1,
+			wantStatus: "processing",
+			wantReason: anchorRetryPendingMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newInboxRepoStub()
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.response)
+			}))
+			defer server.Close()
+			handler := NewUserEventHandler(repo, anchorclient.NewClient(server.URL, "test-key"), nil)
+
+			body := tc.body
+			if body == "" {
+				body = userCreatedBody
+			}
+			if !handler.HandleUserCreatedEvent([]byte(body)) {
+				t.Fatalf("expected message to be acknowledged")
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tc.wantCalls {
+				t.Fatalf("expected %d Anchor calls, got %d", tc.wantCalls, got)
+			}
+			if got := repo.anchorID["user-1"]; got != tc.wantLinked {
+				t.Fatalf("expected user linked to %q, got %q", tc.wantLinked, got)
+			}
+			got := repo.statuses["user-1/tier1"]
+			if got.status != tc.wantStatus || got.reason != tc.wantReason {
+				t.Fatalf("expected tier1 %s (%q), got %s (%q)", tc.wantStatus, tc.wantReason, got.status, got.reason)
+			}
+			wantInbox := ""
+			if tc.wantLinked != "" {
+				wantInbox = "completed"
+			}
+			if status := repo.inbox[userCreatedConsumer+"/user-1"]; status != wantInbox {
+				t.Fatalf("expected inbox status %q, got %q", wantInbox, status)
+			}
+		})
+	}
+}
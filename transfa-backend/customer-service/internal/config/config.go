@@ -17,17 +17,37 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config stores all configuration for the application.
 // The values are read by viper from a config file or environment variables.
 type Config struct {
-	DatabaseURL      string `mapstructure:"DATABASE_URL"`
-	RabbitMQURL      string `mapstructure:"RABBITMQ_URL"`
-	AnchorAPIKey     string `mapstructure:"ANCHOR_API_KEY"`
-	AnchorAPIBaseURL string `mapstructure:"ANCHOR_API_BASE_URL"`
+	DatabaseURL        string `mapstructure:"DATABASE_URL"`
+	RabbitMQURL        string `mapstructure:"RABBITMQ_URL"`
+	AnchorAPIKey       string `mapstructure:"ANCHOR_API_KEY"`
+	AnchorAPIBaseURL   string `mapstructure:"ANCHOR_API_BASE_URL"`
+	EventSigningSecret string `mapstructure:"EVENT_SIGNING_SECRET"`
+	InternalAPIKey     string `mapstructure:"INTERNAL_API_KEY"`
+	// AdminPort serves the admin listener; empty disables it.
+	AdminPort string `mapstructure:"ADMIN_PORT"`
+	// OnboardingStatusDualWrite keeps writing onboarding_status directly next to the
+	// onboarding.stage.changed events while auth-service takes the table over.
+	OnboardingStatusDualWrite bool `mapstructure:"ONBOARDING_STATUS_DUAL_WRITE"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        10,
+	MinConns:        2,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -49,6 +69,11 @@ func LoadConfig() (config Config, err error) {
 	_ = viper.BindEnv("RABBITMQ_URL")
 	_ = viper.BindEnv("ANCHOR_API_KEY")
 	_ = viper.BindEnv("ANCHOR_API_BASE_URL")
+	_ = viper.BindEnv("EVENT_SIGNING_SECRET")
+	_ = viper.BindEnv("INTERNAL_API_KEY")
+	_ = viper.BindEnv("ADMIN_PORT")
+	_ = viper.BindEnv("ONBOARDING_STATUS_DUAL_WRITE")
+	viper.SetDefault("ONBOARDING_STATUS_DUAL_WRITE", true)
 
 	// Read the config file
 	err = viper.ReadInConfig()
@@ -66,5 +91,6 @@ func LoadConfig() (config Config, err error) {
 		log.Fatalf("Unable to decode into struct: %v", err)
 	}
 
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
@@ -0,0 +1,35 @@
+/**
+ * @description
+ * This file defines the deferred event model: an event a consumer failed to process
+ * for a retriable reason and will try again later with backoff.
+ */
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Deferred event statuses.
+const (
+	DeferredEventPending           = "pending"
+	DeferredEventProcessing        = "processing"
+	DeferredEventCompleted         = "completed"
+	DeferredEventNeedsManualReview = "needs_manual_review"
+)
+
+// DeferredEvent is a row of the deferred_events retry queue. The payload carries the
+// original message and is not exposed over the internal API.
+type DeferredEvent struct {
+	ID            int64           `json:"id"`
+	Consumer      string          `json:"consumer"`
+	MessageID     string          `json:"message_id"`
+	UserID        *string         `json:"user_id,omitempty"`
+	Payload       json.RawMessage `json:"-"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     *string         `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+/**
+ * @description
+ * This file defines the onboarding stage event every service publishes when a user's
+ * onboarding stage changes. auth-service consumes it to keep onboarding_status.
+ */
+package domain
+
+import "time"
+
+// OnboardingStageChangedEvent reports the new status of one onboarding stage.
+type OnboardingStageChangedEvent struct {
+	UserID     string    `json:"user_id"`
+	Stage      string    `json:"stage"`
+	Status     string    `json:"status"`
+	Reason     *string   `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -13,6 +13,8 @@
  */
 package domain
 
+import "time"
+
 // UserType defines the type of a user account.
 type UserType string
 
@@ -24,8 +26,9 @@ const (
 // UserCreatedEvent represents the payload published to RabbitMQ after a user is created in the auth-service.
 // This is the message that the customer-service will consume.
 type UserCreatedEvent struct {
-	UserID  string                 `json:"user_id"`
-	KYCData map[string]interface{} `json:"kyc_data"`
+	MessageID string                 `json:"message_id"`
+	UserID    string                 `json:"user_id"`
+	KYCData   map[string]interface{} `json:"kyc_data"`
 }
 
 // Tier2VerificationRequestedEvent is emitted by the auth-service when the user submits Tier2 details.
@@ -50,3 +53,20 @@ type Tier1ProfileUpdateRequestedEvent struct {
 	AnchorCustomerID string                 `json:"anchor_customer_id"`
 	KYCData          map[string]interface{} `json:"kyc_data"`
 }
+
+// Tier1VerificationRequestedEvent is emitted by the auth-service when a user retries a
+// failed Tier 1 verification. MessageID is unique per retry; AnchorCustomerID is set
+// when the user was already linked to an Anchor customer.
+type Tier1VerificationRequestedEvent struct {
+	MessageID        string                 `json:"message_id"`
+	UserID           string                 `json:"user_id"`
+	AnchorCustomerID string                 `json:"anchor_customer_id"`
+	KYCData          map[string]interface{} `json:"kyc_data"`
+}
+
+// UserClosedEvent is emitted by the auth-service after a user closes their account.
+type UserClosedEvent struct {
+	UserID           string    `json:"user_id"`
+	AnchorCustomerID string    `json:"anchor_customer_id"`
+	ClosedAt         time.Time `json:"closed_at"`
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/customer-service/internal/domain"
+)
+
+const deferredEventColumns = `id, consumer, message_id, user_id, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at`
+
+// DeferEvent queues a failed message for another attempt at nextAttemptAt, counting the
+// failed delivery as the first attempt. A message already waiting in the queue keeps
+// its schedule; one that completed earlier is queued again.
+func (r *PostgresUserRepository) DeferEvent(ctx context.Context, consumer, messageID string, userID *string, payload []byte, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO deferred_events (consumer, message_id, user_id, payload, next_attempt_at, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (consumer, message_id)
+		DO UPDATE SET
+			status = 'pending',
+			attempts = 1,
+			payload = EXCLUDED.payload,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			last_error = EXCLUDED.last_error,
+			updated_at = NOW()
+		WHERE deferred_events.status = 'completed'
+	`, consumer, messageID, userID, payload, nextAttemptAt, lastError)
+	if err != nil {
+		log.Printf("Error deferring event %s for consumer %s: %v", messageID, consumer, err)
+	}
+	return err
+}
+
+// ClaimDueDeferredEvents moves up to limit of consumer's due events to processing and
+// returns them. A row left in processing for longer than staleAfter, by a worker that
+// died mid-retry, is claimed again.
+func (r *PostgresUserRepository) ClaimDueDeferredEvents(ctx context.Context, consumer string, limit int, staleAfter time.Duration) ([]domain.DeferredEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE deferred_events
+		SET status = 'processing', updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM deferred_events
+			WHERE consumer = $1
+			  AND (
+				(status = 'pending' AND next_attempt_at <= NOW())
+				OR (status = 'processing' AND updated_at < NOW() - ($3 * INTERVAL '1 second'))
+			  )
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deferredEventColumns,
+		consumer, limit, int(staleAfter.Seconds()))
+	if err != nil {
+		log.Printf("Error claiming deferred events for consumer %s: %v", consumer, err)
+		return nil, err
+	}
+	return scanDeferredEvents(rows)
+}
+
+// CompleteDeferredEvent marks a retried event as processed.
+func (r *PostgresUserRepository) CompleteDeferredEvent(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE deferred_events
+		SET status = 'completed', updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		log.Printf("Error completing deferred event %d: %v", id, err)
+	}
+	return err
+}
+
+// RecordDeferredEventFailure stores a failed retry. With a nextAttemptAt the event is
+// scheduled again; without one it has exhausted its retries and needs manual review.
+func (r *PostgresUserRepository) RecordDeferredEventFailure(ctx context.Context, id int64, attempts int, nextAttemptAt *time.Time, lastError string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE deferred_events
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'needs_manual_review' ELSE 'pending' END,
+			attempts = $2,
+			next_attempt_at = COALESCE($3::timestamptz, next_attempt_at),
+			last_error = $4,
+			updated_at = NOW()
+		WHERE id = $1
+	`, id, attempts, nextAttemptAt, lastError)
+	if err != nil {
+		log.Printf("Error recording failure of deferred event %d: %v", id, err)
+	}
+	return err
+}
+
+// ListDeferredEvents returns deferred events in status, most recently updated first.
+func (r *PostgresUserRepository) ListDeferredEvents(ctx context.Context, status string, limit int) ([]domain.DeferredEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+deferredEventColumns+`
+		FROM deferred_events
+		WHERE status = $1
+		ORDER BY updated_at DESC
+		LIMIT $2
+	`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeferredEvents(rows)
+}
+
+func scanDeferredEvents(rows pgx.Rows) ([]domain.DeferredEvent, error) {
+	defer rows.Close()
+
+	events := []domain.DeferredEvent{}
+	for rows.Next() {
+		var event domain.DeferredEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.Consumer,
+			&event.MessageID,
+			&event.UserID,
+			&event.Payload,
+			&event.Status,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&event.LastError,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
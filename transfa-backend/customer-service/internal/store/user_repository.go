@@ -23,6 +23,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/transfa/customer-service/internal/domain"
 )
 
 // UserRepository defines the interface for user data storage operations needed by this service.
@@ -31,10 +32,18 @@ type UserRepository interface {
 	UpdateAnchorCustomerInfo(ctx context.Context, userID string, anchorCustomerID string, fullName *string) error
 	GetAnchorCustomerIDByUserID(ctx context.Context, userID string) (*string, error)
 	FindUserIDByAnchorCustomerID(ctx context.Context, anchorCustomerID string) (string, error)
-	EnsureOnboardingStatusTable(ctx context.Context) error
 	UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error
+	RecordTier2Outcome(ctx context.Context, userID string, rejected []string, reason *string, verificationID string) error
 	InferTierStageFromOnboarding(ctx context.Context, userID string) (string, error)
 	UserHasAccount(ctx context.Context, userID string) (bool, error)
+	ClaimEvent(ctx context.Context, consumer, messageID string, staleAfter time.Duration) (bool, error)
+	CompleteEvent(ctx context.Context, consumer, messageID string) error
+	ReleaseEvent(ctx context.Context, consumer, messageID string) error
+	DeferEvent(ctx context.Context, consumer, messageID string, userID *string, payload []byte, nextAttemptAt time.Time, lastError string) error
+	ClaimDueDeferredEvents(ctx context.Context, consumer string, limit int, staleAfter time.Duration) ([]domain.DeferredEvent, error)
+	CompleteDeferredEvent(ctx context.Context, id int64) error
+	RecordDeferredEventFailure(ctx context.Context, id int64, attempts int, nextAttemptAt *time.Time, lastError string) error
+	ListDeferredEvents(ctx context.Context, status string, limit int) ([]domain.DeferredEvent, error)
 }
 
 type onboardingStageRecord struct {
@@ -207,26 +216,6 @@ func (r *PostgresUserRepository) FindUserIDByAnchorCustomerID(ctx context.Contex
 	return userID, nil
 }
 
-// EnsureOnboardingStatusTable creates the onboarding_status table if it doesn't exist.
-func (r *PostgresUserRepository) EnsureOnboardingStatusTable(ctx context.Context) error {
-	query := `
-        CREATE TABLE IF NOT EXISTS onboarding_status (
-            user_id UUID NOT NULL,
-            stage TEXT NOT NULL,
-            status TEXT NOT NULL,
-            reason TEXT,
-            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-            PRIMARY KEY (user_id, stage)
-        )
-    `
-	_, err := r.db.Exec(ctx, query)
-	if err != nil {
-		log.Printf("Error ensuring onboarding_status table: %v", err)
-		return err
-	}
-	return nil
-}
-
 // UpsertOnboardingStatus writes or updates the onboarding status for a user and stage.
 func (r *PostgresUserRepository) UpsertOnboardingStatus(ctx context.Context, userID, stage, status string, reason *string) error {
 	query := `
@@ -243,6 +232,30 @@ func (r *PostgresUserRepository) UpsertOnboardingStatus(ctx context.Context, use
 	return nil
 }
 
+// RecordTier2Outcome stores Anchor's verdict on the user's Tier 2 pieces. Pieces in
+// rejected are marked rejected with reason; every other piece still awaiting review is
+// marked accepted.
+func (r *PostgresUserRepository) RecordTier2Outcome(ctx context.Context, userID string, rejected []string, reason *string, verificationID string) error {
+	if rejected == nil {
+		rejected = []string{}
+	}
+	query := `
+		UPDATE tier2_submissions
+		SET status = CASE WHEN document_type = ANY($2::text[]) THEN 'rejected' ELSE 'accepted' END,
+			rejection_reason = CASE WHEN document_type = ANY($2::text[]) THEN $3 ELSE NULL END,
+			anchor_verification_id = COALESCE(NULLIF($4, ''), anchor_verification_id),
+			reviewed_at = NOW(),
+			updated_at = NOW()
+		WHERE user_id = $1
+		  AND (status = 'submitted' OR document_type = ANY($2::text[]))
+	`
+	if _, err := r.db.Exec(ctx, query, userID, rejected, reason, verificationID); err != nil {
+		log.Printf("Error recording tier2 outcome for user %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
 // InferTierStageFromOnboarding infers whether an incoming stage-less identification update
 // belongs to tier2 or tier3 by inspecting current onboarding states.
 func (r *PostgresUserRepository) InferTierStageFromOnboarding(ctx context.Context, userID string) (string, error) {
@@ -291,6 +304,56 @@ func (r *PostgresUserRepository) UserHasAccount(ctx context.Context, userID stri
 	return exists, nil
 }
 
+// ClaimEvent records that consumer is processing messageID. It returns false when the
+// message was already completed or is being processed by another delivery; a claim
+// left in processing for longer than staleAfter is taken over.
+func (r *PostgresUserRepository) ClaimEvent(ctx context.Context, consumer, messageID string, staleAfter time.Duration) (bool, error) {
+	query := `
+		INSERT INTO event_inbox (consumer, message_id)
+		VALUES ($1, $2)
+		ON CONFLICT (consumer, message_id)
+		DO UPDATE SET claimed_at = NOW()
+		WHERE event_inbox.status = 'processing'
+		  AND event_inbox.claimed_at < NOW() - ($3 * INTERVAL '1 second')
+		RETURNING message_id
+	`
+	var claimed string
+	err := r.db.QueryRow(ctx, query, consumer, messageID, int(staleAfter.Seconds())).Scan(&claimed)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		log.Printf("Error claiming event %s for consumer %s: %v", messageID, consumer, err)
+		return false, err
+	}
+	return true, nil
+}
+
+// CompleteEvent marks a claimed message as processed so redeliveries are dropped.
+func (r *PostgresUserRepository) CompleteEvent(ctx context.Context, consumer, messageID string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE event_inbox
+		SET status = 'completed', completed_at = NOW()
+		WHERE consumer = $1 AND message_id = $2
+	`, consumer, messageID)
+	if err != nil {
+		log.Printf("Error completing event %s for consumer %s: %v", messageID, consumer, err)
+	}
+	return err
+}
+
+// ReleaseEvent drops an unfinished claim so a later delivery of the message can retry.
+func (r *PostgresUserRepository) ReleaseEvent(ctx context.Context, consumer, messageID string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM event_inbox
+		WHERE consumer = $1 AND message_id = $2 AND status = 'processing'
+	`, consumer, messageID)
+	if err != nil {
+		log.Printf("Error releasing event %s for consumer %s: %v", messageID, consumer, err)
+	}
+	return err
+}
+
 func nullIfEmpty(val string) interface{} {
 	if val == "" {
 		return nil
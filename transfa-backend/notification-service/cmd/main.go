@@ -6,6 +6,10 @@
  * Key features:
  * - Loads application configuration from environment variables.
  * - Initializes a RabbitMQ producer to publish internal events based on received webhooks.
+ * - Consumes internal transfer and money drop events and relays them to users as push notifications.
+ * - With a database configured, stores device tokens and notification preferences and
+ *   delivers pushes directly through FCM and Expo.
+ * - Optionally emails receipts for transfers and money drop claims to users who opted in.
  * - Sets up an HTTP router (`chi`) to direct webhook traffic to the appropriate handler.
  * - Implements graceful shutdown to ensure clean resource cleanup on termination.
  *
@@ -22,15 +26,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/transfa/notification-service/internal/api"
+	"github.com/transfa/notification-service/internal/app"
 	"github.com/transfa/notification-service/internal/config"
-	"github.com/transfa/notification-service/pkg/rabbitmq"
+	"github.com/transfa/notification-service/internal/domain"
+	"github.com/transfa/notification-service/internal/store"
+	"github.com/transfa/notification-service/pkg/authclient"
+	"github.com/transfa/notification-service/pkg/emailclient"
+	"github.com/transfa/notification-service/pkg/pushclient"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
+	"github.com/transfa/pkg/rabbitmq"
 )
 
 func main() {
@@ -61,14 +75,151 @@ func main() {
 	defer producer.Close()
 	log.Println("level=info component=bootstrap msg=\"rabbitmq connected\"")
 
+	// Sign published events so consumers can reject messages that did not come from here.
+	var publisher rabbitmq.Publisher = producer
+	if secrets := rabbitmq.ParseSigningSecrets(cfg.EventSigningSecret); len(secrets) > 0 {
+		signed, err := rabbitmq.NewSignedEventProducer(producer, secrets...)
+		if err != nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"event signing init failed\" err=%v", err)
+		}
+		publisher = signed
+	} else {
+		log.Println("level=warn component=bootstrap msg=\"event signing secret missing; published events are unsigned\" env=EVENT_SIGNING_SECRET")
+	}
+
+	// With a database, device tokens and preferences are stored here and pushes go
+	// straight to FCM and Expo. Without one, the HTTP push provider is used if set;
+	// otherwise notifications are logged and skipped.
+	var repo store.Repository
+	if strings.TrimSpace(cfg.DatabaseURL) != "" {
+		poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+		if err != nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"database url parse failed\" err=%v", err)
+		}
+		poolConfig.MaxConns = cfg.DatabasePool.MaxConns
+		poolConfig.MinConns = cfg.DatabasePool.MinConns
+		poolConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+		poolConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
+
+		// Disable prepared statement caching to prevent conflicts
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+		dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+		if err != nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"database connection failed\" err=%v", err)
+		}
+		defer dbpool.Close()
+		log.Println("level=info component=bootstrap msg=\"database connected\"")
+		repo = store.NewPostgresRepository(dbpool)
+	}
+
+	var pushProvider app.PushProvider
+	switch {
+	case repo != nil:
+		senders := map[string]app.PushSender{
+			domain.DeviceProviderExpo: pushclient.NewExpoSender(cfg.ExpoAccessToken),
+		}
+		if strings.TrimSpace(cfg.FCMServiceAccountJSON) == "" {
+			log.Println("level=warn component=bootstrap msg=\"fcm service account missing; fcm tokens will be skipped\" env=FCM_SERVICE_ACCOUNT_JSON")
+		} else {
+			fcmSender, err := pushclient.NewFCMSender(cfg.FCMServiceAccountJSON)
+			if err != nil {
+				log.Fatalf("level=fatal component=bootstrap msg=\"fcm sender init failed\" env=FCM_SERVICE_ACCOUNT_JSON err=%v", err)
+			}
+			senders[domain.DeviceProviderFCM] = fcmSender
+		}
+		pushProvider = app.NewDevicePushProvider(repo, senders)
+	case strings.TrimSpace(cfg.PushProviderURL) != "":
+		pushProvider = pushclient.NewClient(cfg.PushProviderURL, cfg.PushProviderAPIKey)
+	default:
+		log.Println("level=warn component=bootstrap msg=\"push provider url missing; push notifications disabled\" env=PUSH_PROVIDER_URL")
+	}
+	notificationService := app.NewNotificationService(pushProvider)
+	if repo != nil {
+		notificationService.ConfigureStore(repo)
+	}
+	transferEventHandler := app.NewTransferEventHandler(notificationService)
+
+	// Consume internal transfer events that should reach users as push notifications.
+	consumer, err := rabbitmq.NewConsumer(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("level=fatal component=bootstrap msg=\"rabbitmq consumer init failed\" err=%v", err)
+	}
+	defer consumer.Close()
+
+	transferBindings := map[string]func([]byte) bool{
+		"transfer.initiated.p2p":   transferEventHandler.HandleP2PTransferInitiated,
+		"payment_request.received": transferEventHandler.HandlePaymentRequestReceived,
+	}
+	// These need the database to find who to notify.
+	if repo != nil {
+		transferBindings["transfer.status.nip.successful"] = transferEventHandler.HandleTransferStatus
+		transferBindings["transfer.status.nip.failed"] = transferEventHandler.HandleTransferStatus
+		transferBindings["transfer.status.book.failed"] = transferEventHandler.HandleTransferStatus
+		transferBindings["transfer.rerouted.internal"] = transferEventHandler.HandleReroutedInternal
+		transferBindings["money_drop.claimed"] = transferEventHandler.HandleMoneyDropClaimed
+	}
+	if err := consumer.ConsumeWithBindings("transfa.events", cfg.TransferEventQueue, transferBindings); err != nil {
+		log.Fatalf("level=fatal component=bootstrap msg=\"transfer event consumer start failed\" err=%v", err)
+	}
+
+	// Email receipts read the same events from their own queue, so a slow mail
+	// provider never holds up push notifications.
+	if cfg.EmailReceiptsEnabled {
+		if repo == nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"email receipts require a database\" env=DATABASE_URL")
+		}
+		if strings.TrimSpace(cfg.AuthServiceURL) == "" {
+			log.Fatalf("level=fatal component=bootstrap msg=\"email receipts require the auth service\" env=AUTH_SERVICE_URL")
+		}
+
+		var emailSender app.EmailSender
+		switch strings.ToLower(strings.TrimSpace(cfg.EmailProvider)) {
+		case "resend":
+			emailSender = emailclient.NewResendSender(cfg.ResendAPIKey, cfg.EmailFrom)
+		case "smtp":
+			emailSender = emailclient.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.EmailFrom)
+		default:
+			log.Fatalf("level=fatal component=bootstrap msg=\"unknown email provider\" env=EMAIL_PROVIDER value=%q", cfg.EmailProvider)
+		}
+
+		emailDispatcher, err := app.NewEmailReceiptDispatcher(repo, authclient.NewClient(cfg.AuthServiceURL, cfg.InternalAPIKey), emailSender)
+		if err != nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"email dispatcher init failed\" err=%v", err)
+		}
+		emailCtx, cancelEmail := context.WithCancel(context.Background())
+		defer cancelEmail()
+		emailDispatcher.Start(emailCtx, 2)
+
+		emailBindings := map[string]func([]byte) bool{
+			"transfer.status.nip.successful": emailDispatcher.HandleTransferStatus,
+			"transfer.status.nip.failed":     emailDispatcher.HandleTransferStatus,
+			"transfer.status.book.failed":    emailDispatcher.HandleTransferStatus,
+			"money_drop.claimed":             emailDispatcher.HandleMoneyDropClaimed,
+		}
+		if err := consumer.ConsumeWithBindings("transfa.events", cfg.EmailEventQueue, emailBindings); err != nil {
+			log.Fatalf("level=fatal component=bootstrap msg=\"email event consumer start failed\" err=%v", err)
+		}
+		log.Printf("level=info component=bootstrap msg=\"email receipts enabled\" provider=%s", cfg.EmailProvider)
+	} else {
+		log.Println("level=info component=bootstrap msg=\"email receipts disabled\" env=EMAIL_RECEIPTS_ENABLED")
+	}
+
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		log.Fatalf("level=fatal component=bootstrap msg=\"invalid allowed origins\" env=ALLOWED_ORIGINS err=%v", err)
+	}
+
 	// Set up router and handlers.
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(sharedmiddleware.RequestTimeout(10 * time.Second))
+	r.Use(sharedmiddleware.CORS(allowedOrigins))
 
 	// Create the webhook handler with its dependencies.
 	webhookHandler := api.NewWebhookHandler(
-		producer,
+		publisher,
 		cfg.AnchorWebhookSecret,
 		cfg.AnchorAPIKey,
 		cfg.AnchorAPIBaseURL,
@@ -81,6 +232,22 @@ func main() {
 		w.Write([]byte("Notification service is healthy"))
 	})
 
+	// Device and notification preference endpoints, for signed-in users.
+	if repo != nil {
+		if strings.TrimSpace(cfg.ClerkJWKSURL) == "" {
+			log.Fatalf("level=fatal component=bootstrap msg=\"clerk jwks url required with DATABASE_URL\" env=CLERK_JWKS_URL")
+		}
+		deviceHandler := api.NewDeviceHandler(notificationService)
+		r.Group(func(r chi.Router) {
+			r.Use(api.ClerkAuthMiddleware(cfg.ClerkJWKSURL))
+
+			r.Post("/me/devices", deviceHandler.RegisterDevice)
+			r.Delete("/me/devices/{token}", deviceHandler.UnregisterDevice)
+			r.Get("/me/notification-preferences", deviceHandler.GetNotificationPreferences)
+			r.Put("/me/notification-preferences", deviceHandler.UpdateNotificationPreferences)
+		})
+	}
+
 	// Start the HTTP server.
 	server := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -0,0 +1,156 @@
+/**
+ * @description
+ * This file contains the authenticated HTTP handlers for registering push device
+ * tokens and managing notification preferences.
+ */
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/transfa/notification-service/internal/app"
+	"github.com/transfa/notification-service/internal/domain"
+	"github.com/transfa/notification-service/internal/store"
+)
+
+// DeviceService is the part of app.NotificationService the device handlers use.
+type DeviceService interface {
+	ResolveUserID(ctx context.Context, clerkUserID string) (string, error)
+	RegisterDevice(ctx context.Context, userID string, payload domain.RegisterDevicePayload) (*domain.DeviceToken, error)
+	UnregisterDevice(ctx context.Context, userID, token string) error
+	GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, userID string, payload domain.UpdateNotificationPreferencesPayload) (*domain.NotificationPreferences, error)
+}
+
+// DeviceHandler serves the /me device and notification preference endpoints.
+type DeviceHandler struct {
+	service DeviceService
+}
+
+// NewDeviceHandler creates a new DeviceHandler.
+func NewDeviceHandler(service DeviceService) *DeviceHandler {
+	return &DeviceHandler{service: service}
+}
+
+// RegisterDevice handles POST /me/devices.
+func (h *DeviceHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.resolveUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload domain.RegisterDevicePayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	device, err := h.service.RegisterDevice(r.Context(), userID, payload)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidDeviceToken) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("level=error component=api endpoint=register_device outcome=failed user_id=%s err=%v", userID, err)
+		http.Error(w, "Could not register the device", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, device)
+}
+
+// UnregisterDevice handles DELETE /me/devices/{token}.
+func (h *DeviceHandler) UnregisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.resolveUserID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.UnregisterDevice(r.Context(), userID, chi.URLParam(r, "token"))
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, app.ErrInvalidDeviceToken):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, app.ErrDeviceNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		log.Printf("level=error component=api endpoint=unregister_device outcome=failed user_id=%s err=%v", userID, err)
+		http.Error(w, "Could not remove the device", http.StatusInternalServerError)
+	}
+}
+
+// GetNotificationPreferences handles GET /me/notification-preferences.
+func (h *DeviceHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.resolveUserID(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.service.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=get_notification_preferences outcome=failed user_id=%s err=%v", userID, err)
+		http.Error(w, "Could not load notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences handles PUT /me/notification-preferences.
+func (h *DeviceHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.resolveUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload domain.UpdateNotificationPreferencesPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.UpdateNotificationPreferences(r.Context(), userID, payload)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=update_notification_preferences outcome=failed user_id=%s err=%v", userID, err)
+		http.Error(w, "Could not save notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// resolveUserID maps the authenticated Clerk user to the internal user id, writing
+// the error response itself when it cannot.
+func (h *DeviceHandler) resolveUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	clerkUserID, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+
+	userID, err := h.service.ResolveUserID(r.Context(), clerkUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return "", false
+		}
+		log.Printf("level=error component=api msg=\"user lookup failed\" clerk_user_id=%s err=%v", clerkUserID, err)
+		http.Error(w, "Could not resolve the user", http.StatusInternalServerError)
+		return "", false
+	}
+	return userID, true
+}
+
+func respondWithJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("level=warn component=api msg=\"response encode failed\" err=%v", err)
+	}
+}
@@ -38,7 +38,7 @@ import (
 	"time"
 
 	"github.com/transfa/notification-service/internal/domain"
-	"github.com/transfa/notification-service/pkg/rabbitmq"
+	"github.com/transfa/pkg/rabbitmq"
 )
 
 const (
@@ -49,7 +49,7 @@ const (
 
 // WebhookHandler processes incoming webhooks from Anchor.
 type WebhookHandler struct {
-	producer        *rabbitmq.EventProducer
+	producer        rabbitmq.Publisher
 	secrets         []string
 	anchorAPIKey    string
 	anchorAPIBase   string
@@ -176,7 +176,7 @@ func toString(value interface{}) string {
 }
 
 // NewWebhookHandler creates a new handler for the webhook endpoint.
-func NewWebhookHandler(producer *rabbitmq.EventProducer, secret string, anchorAPIKey string, anchorAPIBaseURL string) *WebhookHandler {
+func NewWebhookHandler(producer rabbitmq.Publisher, secret string, anchorAPIKey string, anchorAPIBaseURL string) *WebhookHandler {
 	anchorAPIBaseURL = strings.TrimSpace(anchorAPIBaseURL)
 	if anchorAPIBaseURL == "" {
 		anchorAPIBaseURL = "https://api.sandbox.getanchor.co"
@@ -325,6 +325,15 @@ func extractAnchorCustomerID(event domain.AnchorWebhookEvent) string {
 	return ""
 }
 
+// extractVerificationID returns the verification an identification event reports on:
+// the event's own resource when it differs from the customer, "" otherwise.
+func extractVerificationID(event domain.AnchorWebhookEvent, anchorCustomerID string) string {
+	if event.Data.ID == "" || event.Data.ID == anchorCustomerID {
+		return ""
+	}
+	return event.Data.ID
+}
+
 func decodeAnchorWebhook(body []byte) (domain.AnchorWebhookEvent, error) {
 	var event domain.AnchorWebhookEvent
 	if err := json.Unmarshal(body, &event); err != nil {
@@ -812,10 +821,11 @@ func (h *WebhookHandler) buildCustomerEvent(event domain.AnchorWebhookEvent, anc
 			AnchorCustomerID: anchorCustomerID,
 			Stage:            stage,
 			Status:           "completed",
+			VerificationID:   extractVerificationID(event, anchorCustomerID),
 		}
 	case "customer.identification.rejected":
 		reason := extractReason(event.Data.Attributes)
-		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "rejected", Reason: nullableString(reason)}
+		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "rejected", Reason: nullableString(reason), VerificationID: extractVerificationID(event, anchorCustomerID)}
 	case "customer.identification.manualReview":
 		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "manual_review"}
 	case "customer.identification.awaitingDocument":
@@ -827,7 +837,7 @@ func (h *WebhookHandler) buildCustomerEvent(event domain.AnchorWebhookEvent, anc
 		fallthrough
 	case "customer.identification.reenterInformation":
 		reason := extractReason(event.Data.Attributes)
-		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "reenter_information", Reason: nullableString(reason)}
+		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "reenter_information", Reason: nullableString(reason), VerificationID: extractVerificationID(event, anchorCustomerID)}
 	case "customer.identification.pending":
 		message = domain.CustomerTierStatusEvent{AnchorCustomerID: anchorCustomerID, Stage: stage, Status: "pending"}
 	case "customer.identification.error":
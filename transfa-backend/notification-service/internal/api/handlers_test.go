@@ -61,3 +61,28 @@ func TestInferTierStatusFromAttrs(t *testing.T) {
 		t.Fatalf("expected manual_review, got %q", got)
 	}
 }
+
+func TestBuildCustomerEventCarriesVerificationID(t *testing.T) {
+	event := domain.AnchorWebhookEvent{
+		Event: "customer.identification.rejected",
+		Data: domain.EventResource{
+			ID:         "verification-1",
+			Attributes: map[string]interface{}{"message": "date of birth mismatch"},
+		},
+	}
+
+	_, message, ok := (&WebhookHandler{}).buildCustomerEvent(event, "customer-1")
+	if !ok {
+		t.Fatal("expected the identification event to be routed")
+	}
+	status, _ := message.(domain.CustomerTierStatusEvent)
+	if status.VerificationID != "verification-1" || status.AnchorCustomerID != "customer-1" {
+		t.Fatalf("expected the verification and customer IDs to be kept apart, got %+v", status)
+	}
+
+	event.Data.ID = "customer-1"
+	_, message, _ = (&WebhookHandler{}).buildCustomerEvent(event, "customer-1")
+	if status, _ := message.(domain.CustomerTierStatusEvent); status.VerificationID != "" {
+		t.Fatalf("expected no verification ID when the event is about the customer, got %q", status.VerificationID)
+	}
+}
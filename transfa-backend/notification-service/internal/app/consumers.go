@@ -0,0 +1,254 @@
+/**
+ * @description
+ * This file contains the RabbitMQ handlers for internal events that should surface
+ * to users as push notifications.
+ *
+ * @dependencies
+ * - context, encoding/json, fmt, log, strings, time: Standard Go libraries.
+ * - internal/domain: For the event payload definitions.
+ */
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+const pushSendTimeout = 10 * time.Second
+
+// TransferEventHandler turns transfer lifecycle events into push notifications.
+type TransferEventHandler struct {
+	notifications *NotificationService
+}
+
+// NewTransferEventHandler creates a new TransferEventHandler.
+func NewTransferEventHandler(notifications *NotificationService) *TransferEventHandler {
+	return &TransferEventHandler{notifications: notifications}
+}
+
+// HandleP2PTransferInitiated processes a `transfer.initiated.p2p` event and notifies the recipient.
+// It returns a boolean indicating whether the message should be acknowledged.
+func (h *TransferEventHandler) HandleP2PTransferInitiated(body []byte) bool {
+	var event domain.P2PTransferInitiatedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=consumer flow=p2p_transfer_initiated outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.RecipientID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=consumer flow=p2p_transfer_initiated outcome=ack reason=invalid_payload transaction_id=%s", event.TransactionID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	sender := strings.TrimSpace(event.SenderUsername)
+	if sender == "" {
+		sender = "Someone"
+	}
+	title := "Money on the way"
+	message := fmt.Sprintf("%s sent you %s.", sender, formatKoboAsNaira(event.Amount))
+
+	// Push delivery is best-effort; a failed send is logged and acknowledged so a
+	// provider outage cannot cause the queue to redeliver indefinitely.
+	if err := h.notifications.SendPushNotification(ctx, event.RecipientID, title, message); err != nil {
+		log.Printf("level=warn component=consumer flow=p2p_transfer_initiated outcome=ack msg=\"push notification failed\" recipient_id=%s transaction_id=%s err=%v", event.RecipientID, event.TransactionID, err)
+		return true
+	}
+
+	log.Printf("level=info component=consumer flow=p2p_transfer_initiated outcome=ack recipient_id=%s transaction_id=%s", event.RecipientID, event.TransactionID)
+	return true
+}
+
+// HandlePaymentRequestReceived processes a `payment_request.received` event and notifies
+// the recipient. It returns a boolean indicating whether the message should be acknowledged.
+func (h *TransferEventHandler) HandlePaymentRequestReceived(body []byte) bool {
+	var event domain.PaymentRequestReceivedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=consumer flow=payment_request_received outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.RecipientID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=consumer flow=payment_request_received outcome=ack reason=invalid_payload request_id=%s", event.RequestID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	creator := strings.TrimSpace(event.CreatorUsername)
+	if creator == "" {
+		creator = "Someone"
+	}
+	title := "New payment request"
+	message := fmt.Sprintf("%s requested %s from you.", creator, formatKoboAsNaira(event.Amount))
+	if event.Description != nil && strings.TrimSpace(*event.Description) != "" {
+		message = fmt.Sprintf("%s requested %s from you: %s", creator, formatKoboAsNaira(event.Amount), strings.TrimSpace(*event.Description))
+	}
+
+	if err := h.notifications.SendPushNotification(ctx, event.RecipientID, title, message); err != nil {
+		log.Printf("level=warn component=consumer flow=payment_request_received outcome=ack msg=\"push notification failed\" recipient_id=%s request_id=%s err=%v", event.RecipientID, event.RequestID, err)
+		return true
+	}
+
+	log.Printf("level=info component=consumer flow=payment_request_received outcome=ack recipient_id=%s request_id=%s", event.RecipientID, event.RequestID)
+	return true
+}
+
+// formatKoboAsNaira renders a kobo amount as a naira string, e.g. 150000 -> "₦1,500.00".
+func formatKoboAsNaira(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	whole := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s₦%s.%02d", sign, grouped.String(), amount%100)
+}
+
+// HandleTransferStatus processes a `transfer.status.*` event and tells the owner of
+// the source wallet that their transfer went out or failed. Only the routing keys
+// bound in main reach it. It returns a boolean indicating whether the message should
+// be acknowledged.
+func (h *TransferEventHandler) HandleTransferStatus(body []byte) bool {
+	var event domain.TransferStatusEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=consumer flow=transfer_status outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.AnchorAccountID) == "" {
+		log.Printf("level=warn component=consumer flow=transfer_status outcome=ack reason=invalid_payload anchor_transfer_id=%s", event.AnchorTransferID)
+		return true
+	}
+
+	var title, message string
+	switch strings.ToLower(strings.TrimSpace(event.Status)) {
+	case "failed", "failure", "fail":
+		title = "Transfer failed"
+		message = "Your transfer could not be completed."
+		if event.Amount > 0 {
+			message = fmt.Sprintf("Your transfer of %s could not be completed.", formatKoboAsNaira(event.Amount))
+		}
+	case "successful", "success", "completed":
+		title = "Transfer sent"
+		message = "Your transfer has been delivered."
+		if event.Amount > 0 {
+			message = fmt.Sprintf("Your transfer of %s has been delivered.", formatKoboAsNaira(event.Amount))
+		}
+	default:
+		return true
+	}
+
+	repo := h.notifications.store
+	if repo == nil {
+		log.Printf("level=warn component=consumer flow=transfer_status outcome=ack reason=store_not_configured anchor_transfer_id=%s", event.AnchorTransferID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	userID, err := repo.FindUserIDByAnchorAccountID(ctx, event.AnchorAccountID)
+	if err != nil {
+		log.Printf("level=info component=consumer flow=transfer_status outcome=ack reason=owner_not_found anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+		return true
+	}
+
+	if err := h.notifications.SendPushNotification(ctx, userID, title, message); err != nil {
+		log.Printf("level=warn component=consumer flow=transfer_status outcome=ack msg=\"push notification failed\" user_id=%s anchor_transfer_id=%s err=%v", userID, event.AnchorTransferID, err)
+		return true
+	}
+
+	log.Printf("level=info component=consumer flow=transfer_status outcome=ack user_id=%s anchor_transfer_id=%s status=%s", userID, event.AnchorTransferID, event.Status)
+	return true
+}
+
+// HandleReroutedInternal processes a `transfer.rerouted.internal` event and tells the
+// recipient that a transfer meant for their bank was paid into their wallet. It
+// returns a boolean indicating whether the message should be acknowledged.
+func (h *TransferEventHandler) HandleReroutedInternal(body []byte) bool {
+	var event domain.ReroutedInternalEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=consumer flow=transfer_rerouted outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.RecipientID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=consumer flow=transfer_rerouted outcome=ack reason=invalid_payload recipient_id=%s", event.RecipientID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	title := "Paid to your wallet"
+	message := fmt.Sprintf("%s's transfer of %s went to your Transfa wallet because it couldn't be sent to your bank.", h.displayName(ctx, event.SenderID), formatKoboAsNaira(event.Amount))
+
+	if err := h.notifications.SendPushNotification(ctx, event.RecipientID, title, message); err != nil {
+		log.Printf("level=warn component=consumer flow=transfer_rerouted outcome=ack msg=\"push notification failed\" recipient_id=%s err=%v", event.RecipientID, err)
+		return true
+	}
+
+	log.Printf("level=info component=consumer flow=transfer_rerouted outcome=ack recipient_id=%s", event.RecipientID)
+	return true
+}
+
+// HandleMoneyDropClaimed processes a `money_drop.claimed` event and tells the drop's
+// creator who claimed from it. It returns a boolean indicating whether the message
+// should be acknowledged.
+func (h *TransferEventHandler) HandleMoneyDropClaimed(body []byte) bool {
+	var event domain.MoneyDropClaimedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=consumer flow=money_drop_claimed outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.CreatorID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=consumer flow=money_drop_claimed outcome=ack reason=invalid_payload drop_id=%s", event.DropID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	claimant := h.displayName(ctx, event.ClaimantID)
+	title := "Money drop claimed"
+	message := fmt.Sprintf("%s claimed %s from your money drop.", claimant, formatKoboAsNaira(event.Amount))
+	if dropTitle := strings.TrimSpace(event.Title); dropTitle != "" {
+		message = fmt.Sprintf("%s claimed %s from %q.", claimant, formatKoboAsNaira(event.Amount), dropTitle)
+	}
+
+	if err := h.notifications.SendPushNotification(ctx, event.CreatorID, title, message); err != nil {
+		log.Printf("level=warn component=consumer flow=money_drop_claimed outcome=ack msg=\"push notification failed\" creator_id=%s drop_id=%s err=%v", event.CreatorID, event.DropID, err)
+		return true
+	}
+
+	log.Printf("level=info component=consumer flow=money_drop_claimed outcome=ack creator_id=%s drop_id=%s transaction_id=%s", event.CreatorID, event.DropID, event.TransactionID)
+	return true
+}
+
+// displayName returns "@username" for the user, or "Someone" when the username
+// cannot be resolved.
+func (h *TransferEventHandler) displayName(ctx context.Context, userID string) string {
+	if h.notifications.store == nil || strings.TrimSpace(userID) == "" {
+		return "Someone"
+	}
+	username, err := h.notifications.store.FindUsernameByUserID(ctx, userID)
+	if err != nil || strings.TrimSpace(username) == "" {
+		return "Someone"
+	}
+	return "@" + strings.TrimSpace(username)
+}
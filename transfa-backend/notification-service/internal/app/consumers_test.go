@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type pushCall struct {
+	userID string
+	title  string
+	body   string
+}
+
+type recordingPushProvider struct {
+	calls   []pushCall
+	sendErr error
+}
+
+func (p *recordingPushProvider) Send(ctx context.Context, userID, title, body string) error {
+	p.calls = append(p.calls, pushCall{userID: userID, title: title, body: body})
+	return p.sendErr
+}
+
+func TestHandleP2PTransferInitiated_SendsPushToRecipient(t *testing.T) {
+	provider := &recordingPushProvider{}
+	handler := NewTransferEventHandler(NewNotificationService(provider))
+
+	body := []byte(`{"sender_username":"alice","recipient_id":"5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f","amount":150000,"transaction_id":"0b5f2f9e-7a3c-4d1e-8f6a-2c3d4e5f6a7b"}`)
+	if ack := handler.HandleP2PTransferInitiated(body); !ack {
+		t.Fatal("expected message to be acknowledged")
+	}
+
+	if len(provider.calls) != 1 {
+		t.Fatalf("expected one push notification, got %d", len(provider.calls))
+	}
+	call := provider.calls[0]
+	if call.userID != "5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f" {
+		t.Fatalf("expected push to recipient, got %q", call.userID)
+	}
+	if call.body != "alice sent you ₦1,500.00." {
+		t.Fatalf("unexpected push body %q", call.body)
+	}
+}
+
+func TestHandleP2PTransferInitiated_AcksWithoutPushForInvalidPayloads(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{"recipient_id":`},
+		{name: "missing recipient", body: `{"sender_username":"alice","amount":1000}`},
+		{name: "non-positive amount", body: `{"sender_username":"alice","recipient_id":"abc","amount":0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingPushProvider{}
+			handler := NewTransferEventHandler(NewNotificationService(provider))
+
+			if ack := handler.HandleP2PTransferInitiated([]byte(tt.body)); !ack {
+				t.Fatal("expected invalid payload to be acknowledged")
+			}
+			if len(provider.calls) != 0 {
+				t.Fatalf("expected no push notification, got %d", len(provider.calls))
+			}
+		})
+	}
+}
+
+func TestHandleP2PTransferInitiated_AcksWhenProviderFails(t *testing.T) {
+	provider := &recordingPushProvider{sendErr: errors.New("provider unavailable")}
+	handler := NewTransferEventHandler(NewNotificationService(provider))
+
+	body := []byte(`{"sender_username":"alice","recipient_id":"abc","amount":500,"transaction_id":"tx"}`)
+	if ack := handler.HandleP2PTransferInitiated(body); !ack {
+		t.Fatal("expected provider failure to be acknowledged")
+	}
+	if len(provider.calls) != 1 {
+		t.Fatalf("expected one push attempt, got %d", len(provider.calls))
+	}
+}
+
+func TestHandlePaymentRequestReceived_SendsPushToRecipient(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantBody string
+	}{
+		{
+			name:     "with description",
+			body:     `{"creator_username":"alice","recipient_id":"5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f","amount":250000,"description":"Dinner on Friday","request_id":"0b5f2f9e-7a3c-4d1e-8f6a-2c3d4e5f6a7b"}`,
+			wantBody: "alice requested ₦2,500.00 from you: Dinner on Friday",
+		},
+		{
+			name:     "without description",
+			body:     `{"creator_username":"alice","recipient_id":"5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f","amount":250000,"request_id":"0b5f2f9e-7a3c-4d1e-8f6a-2c3d4e5f6a7b"}`,
+			wantBody: "alice requested ₦2,500.00 from you.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingPushProvider{}
+			handler := NewTransferEventHandler(NewNotificationService(provider))
+
+			if ack := handler.HandlePaymentRequestReceived([]byte(tt.body)); !ack {
+				t.Fatal("expected message to be acknowledged")
+			}
+			if len(provider.calls) != 1 {
+				t.Fatalf("expected one push notification, got %d", len(provider.calls))
+			}
+			call := provider.calls[0]
+			if call.userID != "5f0c6a8e-3c2f-4a8e-9a55-1b7c2f3d4e5f" {
+				t.Fatalf("expected push to recipient, got %q", call.userID)
+			}
+			if call.title != "New payment request" {
+				t.Fatalf("unexpected push title %q", call.title)
+			}
+			if call.body != tt.wantBody {
+				t.Fatalf("unexpected push body %q", call.body)
+			}
+		})
+	}
+}
+
+func TestHandlePaymentRequestReceived_AcksWithoutPushForInvalidPayloads(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{"recipient_id":`},
+		{name: "missing recipient", body: `{"creator_username":"alice","amount":1000}`},
+		{name: "non-positive amount", body: `{"creator_username":"alice","recipient_id":"abc","amount":0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingPushProvider{}
+			handler := NewTransferEventHandler(NewNotificationService(provider))
+
+			if ack := handler.HandlePaymentRequestReceived([]byte(tt.body)); !ack {
+				t.Fatal("expected invalid payload to be acknowledged")
+			}
+			if len(provider.calls) != 0 {
+				t.Fatalf("expected no push notification, got %d", len(provider.calls))
+			}
+		})
+	}
+}
+
+func TestSendPushNotification_RejectsIncompleteInput(t *testing.T) {
+	provider := &recordingPushProvider{}
+	svc := NewNotificationService(provider)
+
+	err := svc.SendPushNotification(context.Background(), " ", "title", "body")
+	if !errors.Is(err, ErrInvalidPushNotification) {
+		t.Fatalf("expected ErrInvalidPushNotification, got %v", err)
+	}
+	if len(provider.calls) != 0 {
+		t.Fatal("did not expect provider to be called")
+	}
+}
+
+func TestFormatKoboAsNaira(t *testing.T) {
+	tests := []struct {
+		amount int64
+		want   string
+	}{
+		{amount: 5, want: "₦0.05"},
+		{amount: 150000, want: "₦1,500.00"},
+		{amount: 123456789, want: "₦1,234,567.89"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatKoboAsNaira(tt.amount); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
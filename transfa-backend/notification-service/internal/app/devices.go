@@ -0,0 +1,183 @@
+/**
+ * @description
+ * This file contains device registration, notification preferences, and the
+ * PushProvider that delivers to the device tokens stored for a user.
+ *
+ * @dependencies
+ * - context, errors, fmt, log, strings: Standard Go libraries.
+ * - internal/domain, internal/store: For device tokens and preferences.
+ * - pkg/pushclient: For the unregistered-token sentinel returned by push senders.
+ */
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/transfa/notification-service/internal/domain"
+	"github.com/transfa/notification-service/internal/store"
+	"github.com/transfa/notification-service/pkg/pushclient"
+)
+
+const maxDeviceTokenLen = 512
+
+var (
+	ErrStoreNotConfigured = errors.New("notification store is not configured")
+	ErrInvalidDeviceToken = errors.New("device token is invalid")
+	ErrDeviceNotFound     = errors.New("device not found")
+)
+
+// PushSender delivers a notification to a single device token. There is one per
+// push provider (FCM, Expo) so they can be swapped for a fake in tests.
+type PushSender interface {
+	Send(ctx context.Context, token, title, body string) error
+}
+
+// DevicePushProvider is a PushProvider that sends to every device token registered
+// for the user, through the sender for each token's provider.
+type DevicePushProvider struct {
+	store   store.Repository
+	senders map[string]PushSender
+}
+
+// NewDevicePushProvider creates a DevicePushProvider. senders is keyed by
+// domain.DeviceProviderExpo / domain.DeviceProviderFCM; tokens for a provider
+// without a sender are skipped.
+func NewDevicePushProvider(repo store.Repository, senders map[string]PushSender) *DevicePushProvider {
+	return &DevicePushProvider{store: repo, senders: senders}
+}
+
+// Send delivers the notification to each of the user's devices. Tokens the provider
+// reports as unregistered are removed. It fails only if no device could be reached.
+func (p *DevicePushProvider) Send(ctx context.Context, userID, title, body string) error {
+	devices, err := p.store.ListDeviceTokens(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list device tokens: %w", err)
+	}
+	if len(devices) == 0 {
+		log.Printf("level=info component=push msg=\"no registered devices; notification skipped\" user_id=%s", userID)
+		return nil
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, device := range devices {
+		sender, ok := p.senders[device.Provider]
+		if !ok {
+			log.Printf("level=warn component=push msg=\"push sender not configured\" provider=%s user_id=%s", device.Provider, userID)
+			continue
+		}
+
+		err := sender.Send(ctx, device.Token, title, body)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, pushclient.ErrUnregisteredToken):
+			if err := p.store.DeleteDeviceTokenByValue(ctx, device.Token); err != nil {
+				log.Printf("level=warn component=push msg=\"stale device token cleanup failed\" provider=%s user_id=%s err=%v", device.Provider, userID, err)
+			}
+		default:
+			lastErr = err
+			log.Printf("level=warn component=push msg=\"push send failed\" provider=%s user_id=%s err=%v", device.Provider, userID, err)
+		}
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+// ResolveUserID maps an authenticated Clerk user id to the internal user id.
+func (s *NotificationService) ResolveUserID(ctx context.Context, clerkUserID string) (string, error) {
+	if s.store == nil {
+		return "", ErrStoreNotConfigured
+	}
+	return s.store.FindUserIDByClerkUserID(ctx, clerkUserID)
+}
+
+// RegisterDevice stores a push token for the user. Tokens that look like Expo push
+// tokens default to the expo provider; anything else defaults to fcm.
+func (s *NotificationService) RegisterDevice(ctx context.Context, userID string, payload domain.RegisterDevicePayload) (*domain.DeviceToken, error) {
+	if s.store == nil {
+		return nil, ErrStoreNotConfigured
+	}
+
+	token := strings.TrimSpace(payload.Token)
+	if token == "" || len(token) > maxDeviceTokenLen {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(payload.Provider))
+	if provider == "" {
+		provider = domain.DeviceProviderFCM
+		if strings.HasPrefix(token, "ExponentPushToken[") || strings.HasPrefix(token, "ExpoPushToken[") {
+			provider = domain.DeviceProviderExpo
+		}
+	}
+	if provider != domain.DeviceProviderExpo && provider != domain.DeviceProviderFCM {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	return s.store.UpsertDeviceToken(ctx, domain.DeviceToken{
+		Token:    token,
+		UserID:   userID,
+		Provider: provider,
+		Platform: strings.ToLower(strings.TrimSpace(payload.Platform)),
+	})
+}
+
+// UnregisterDevice removes one of the user's push tokens, e.g. on sign-out.
+func (s *NotificationService) UnregisterDevice(ctx context.Context, userID, token string) error {
+	if s.store == nil {
+		return ErrStoreNotConfigured
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidDeviceToken
+	}
+
+	deleted, err := s.store.DeleteDeviceToken(ctx, userID, token)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// GetNotificationPreferences returns the user's notification preferences.
+func (s *NotificationService) GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	if s.store == nil {
+		return nil, ErrStoreNotConfigured
+	}
+	return s.store.GetNotificationPreferences(ctx, userID)
+}
+
+// UpdateNotificationPreferences applies the fields set in payload on top of the
+// user's current preferences.
+func (s *NotificationService) UpdateNotificationPreferences(ctx context.Context, userID string, payload domain.UpdateNotificationPreferencesPayload) (*domain.NotificationPreferences, error) {
+	if s.store == nil {
+		return nil, ErrStoreNotConfigured
+	}
+
+	prefs, err := s.store.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if payload.TransactionAlerts != nil {
+		prefs.TransactionAlerts = *payload.TransactionAlerts
+	}
+	if payload.Marketing != nil {
+		prefs.Marketing = *payload.Marketing
+	}
+	if payload.EmailReceipts != nil {
+		prefs.EmailReceipts = *payload.EmailReceipts
+	}
+	return s.store.UpsertNotificationPreferences(ctx, userID, *prefs)
+}
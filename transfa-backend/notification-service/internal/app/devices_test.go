@@ -0,0 +1,316 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/transfa/notification-service/internal/domain"
+	"github.com/transfa/notification-service/internal/store"
+	"github.com/transfa/notification-service/pkg/pushclient"
+)
+
+// memoryStore keeps device tokens, preferences and the user and account lookups
+// the event handlers need in memory.
+type memoryStore struct {
+	store.Repository
+
+	devices     map[string]domain.DeviceToken
+	preferences map[string]domain.NotificationPreferences
+	usernames   map[string]string
+	wallets     map[string]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		devices:     map[string]domain.DeviceToken{},
+		preferences: map[string]domain.NotificationPreferences{},
+		usernames:   map[string]string{},
+		wallets:     map[string]string{},
+	}
+}
+
+func (s *memoryStore) FindUsernameByUserID(ctx context.Context, userID string) (string, error) {
+	username, ok := s.usernames[userID]
+	if !ok {
+		return "", store.ErrUserNotFound
+	}
+	return username, nil
+}
+
+func (s *memoryStore) FindUserIDByAnchorAccountID(ctx context.Context, anchorAccountID string) (string, error) {
+	userID, ok := s.wallets[anchorAccountID]
+	if !ok {
+		return "", store.ErrAccountNotFound
+	}
+	return userID, nil
+}
+
+func (s *memoryStore) UpsertDeviceToken(ctx context.Context, device domain.DeviceToken) (*domain.DeviceToken, error) {
+	s.devices[device.Token] = device
+	return &device, nil
+}
+
+func (s *memoryStore) DeleteDeviceToken(ctx context.Context, userID, token string) (bool, error) {
+	device, ok := s.devices[token]
+	if !ok || device.UserID != userID {
+		return false, nil
+	}
+	delete(s.devices, token)
+	return true, nil
+}
+
+func (s *memoryStore) DeleteDeviceTokenByValue(ctx context.Context, token string) error {
+	delete(s.devices, token)
+	return nil
+}
+
+func (s *memoryStore) ListDeviceTokens(ctx context.Context, userID string) ([]domain.DeviceToken, error) {
+	var devices []domain.DeviceToken
+	for _, device := range s.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (s *memoryStore) GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	prefs, ok := s.preferences[userID]
+	if !ok {
+		prefs = domain.DefaultNotificationPreferences()
+	}
+	return &prefs, nil
+}
+
+func (s *memoryStore) UpsertNotificationPreferences(ctx context.Context, userID string, prefs domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	s.preferences[userID] = prefs
+	return &prefs, nil
+}
+
+// fakePushSender records the tokens it was asked to deliver to, failing with the
+// configured error for any token in errs.
+type fakePushSender struct {
+	tokens []string
+	bodies []string
+	errs   map[string]error
+}
+
+func (s *fakePushSender) Send(ctx context.Context, token, title, body string) error {
+	s.tokens = append(s.tokens, token)
+	s.bodies = append(s.bodies, body)
+	return s.errs[token]
+}
+
+func newDeviceNotificationService() (*NotificationService, *memoryStore, *fakePushSender, *fakePushSender) {
+	repo := newMemoryStore()
+	expo := &fakePushSender{}
+	fcm := &fakePushSender{}
+	svc := NewNotificationService(NewDevicePushProvider(repo, map[string]PushSender{
+		domain.DeviceProviderExpo: expo,
+		domain.DeviceProviderFCM:  fcm,
+	}))
+	svc.ConfigureStore(repo)
+	return svc, repo, expo, fcm
+}
+
+func TestRegisterDevice_InfersProviderFromToken(t *testing.T) {
+	svc, _, _, _ := newDeviceNotificationService()
+	ctx := context.Background()
+
+	device, err := svc.RegisterDevice(ctx, "user-1", domain.RegisterDevicePayload{Token: " ExponentPushToken[abc] ", Platform: "iOS"})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	if device.Provider != domain.DeviceProviderExpo || device.Token != "ExponentPushToken[abc]" || device.Platform != "ios" {
+		t.Fatalf("unexpected device %+v", device)
+	}
+
+	device, err = svc.RegisterDevice(ctx, "user-1", domain.RegisterDevicePayload{Token: "fcm-registration-token"})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	if device.Provider != domain.DeviceProviderFCM {
+		t.Fatalf("expected fcm provider, got %q", device.Provider)
+	}
+
+	for _, payload := range []domain.RegisterDevicePayload{
+		{Token: " "},
+		{Token: "token", Provider: "apns"},
+	} {
+		if _, err := svc.RegisterDevice(ctx, "user-1", payload); !errors.Is(err, ErrInvalidDeviceToken) {
+			t.Fatalf("%+v: expected ErrInvalidDeviceToken, got %v", payload, err)
+		}
+	}
+}
+
+func TestUnregisterDevice_OnlyRemovesTheCallersToken(t *testing.T) {
+	svc, repo, _, _ := newDeviceNotificationService()
+	ctx := context.Background()
+	repo.devices["token"] = domain.DeviceToken{Token: "token", UserID: "user-1", Provider: domain.DeviceProviderFCM}
+
+	if err := svc.UnregisterDevice(ctx, "user-2", "token"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Fatalf("expected ErrDeviceNotFound for another user's token, got %v", err)
+	}
+	if err := svc.UnregisterDevice(ctx, "user-1", "token"); err != nil {
+		t.Fatalf("UnregisterDevice: %v", err)
+	}
+	if len(repo.devices) != 0 {
+		t.Fatalf("expected the token to be removed, got %d", len(repo.devices))
+	}
+}
+
+func TestUpdateNotificationPreferences_KeepsOmittedFields(t *testing.T) {
+	svc, _, _, _ := newDeviceNotificationService()
+	ctx := context.Background()
+
+	prefs, err := svc.GetNotificationPreferences(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("GetNotificationPreferences: %v", err)
+	}
+	if !prefs.TransactionAlerts || prefs.Marketing {
+		t.Fatalf("expected transaction alerts on and marketing off by default, got %+v", prefs)
+	}
+
+	on := true
+	prefs, err = svc.UpdateNotificationPreferences(ctx, "user-1", domain.UpdateNotificationPreferencesPayload{Marketing: &on})
+	if err != nil {
+		t.Fatalf("UpdateNotificationPreferences: %v", err)
+	}
+	if !prefs.TransactionAlerts || !prefs.Marketing {
+		t.Fatalf("expected only marketing to change, got %+v", prefs)
+	}
+}
+
+func TestSendPushNotification_DeliversToEveryDeviceAndDropsUnregisteredTokens(t *testing.T) {
+	svc, repo, expo, fcm := newDeviceNotificationService()
+	repo.devices["ExponentPushToken[a]"] = domain.DeviceToken{Token: "ExponentPushToken[a]", UserID: "user-1", Provider: domain.DeviceProviderExpo}
+	repo.devices["fcm-live"] = domain.DeviceToken{Token: "fcm-live", UserID: "user-1", Provider: domain.DeviceProviderFCM}
+	repo.devices["fcm-stale"] = domain.DeviceToken{Token: "fcm-stale", UserID: "user-1", Provider: domain.DeviceProviderFCM}
+	repo.devices["fcm-other"] = domain.DeviceToken{Token: "fcm-other", UserID: "user-2", Provider: domain.DeviceProviderFCM}
+	fcm.errs = map[string]error{"fcm-stale": pushclient.ErrUnregisteredToken}
+
+	if err := svc.SendPushNotification(context.Background(), "user-1", "Title", "Body"); err != nil {
+		t.Fatalf("SendPushNotification: %v", err)
+	}
+
+	if len(expo.tokens) != 1 || len(fcm.tokens) != 2 {
+		t.Fatalf("expected one expo and two fcm sends, got expo=%v fcm=%v", expo.tokens, fcm.tokens)
+	}
+	if _, ok := repo.devices["fcm-stale"]; ok {
+		t.Fatal("expected the unregistered token to be removed")
+	}
+	if _, ok := repo.devices["fcm-live"]; !ok {
+		t.Fatal("expected the live token to be kept")
+	}
+}
+
+func TestSendPushNotification_FailsOnlyWhenNoDeviceIsReached(t *testing.T) {
+	svc, repo, _, fcm := newDeviceNotificationService()
+	repo.devices["fcm-a"] = domain.DeviceToken{Token: "fcm-a", UserID: "user-1", Provider: domain.DeviceProviderFCM}
+	repo.devices["fcm-b"] = domain.DeviceToken{Token: "fcm-b", UserID: "user-1", Provider: domain.DeviceProviderFCM}
+	outage := errors.New("fcm unavailable")
+
+	fcm.errs = map[string]error{"fcm-a": outage}
+	if err := svc.SendPushNotification(context.Background(), "user-1", "Title", "Body"); err != nil {
+		t.Fatalf("expected a partial delivery to succeed, got %v", err)
+	}
+
+	fcm.errs = map[string]error{"fcm-a": outage, "fcm-b": outage}
+	if err := svc.SendPushNotification(context.Background(), "user-1", "Title", "Body"); !errors.Is(err, outage) {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+}
+
+func TestSendPushNotification_RespectsTransactionAlertsPreference(t *testing.T) {
+	svc, repo, expo, _ := newDeviceNotificationService()
+	repo.devices["ExponentPushToken[a]"] = domain.DeviceToken{Token: "ExponentPushToken[a]", UserID: "user-1", Provider: domain.DeviceProviderExpo}
+	repo.preferences["user-1"] = domain.NotificationPreferences{TransactionAlerts: false}
+
+	if err := svc.SendPushNotification(context.Background(), "user-1", "Title", "Body"); err != nil {
+		t.Fatalf("SendPushNotification: %v", err)
+	}
+	if len(expo.tokens) != 0 {
+		t.Fatalf("expected no push with transaction alerts off, got %v", expo.tokens)
+	}
+}
+
+func TestHandleMoneyDropClaimed_NotifiesCreatorWithClaimantUsername(t *testing.T) {
+	svc, repo, expo, _ := newDeviceNotificationService()
+	repo.devices["ExponentPushToken[a]"] = domain.DeviceToken{Token: "ExponentPushToken[a]", UserID: "creator", Provider: domain.DeviceProviderExpo}
+	repo.usernames["claimant"] = "ada"
+	handler := NewTransferEventHandler(svc)
+
+	body := []byte(`{"drop_id":"drop","title":"Birthday","creator_id":"creator","claimant_id":"claimant","amount":500000,"transaction_id":"tx"}`)
+	if ack := handler.HandleMoneyDropClaimed(body); !ack {
+		t.Fatal("expected message to be acknowledged")
+	}
+	if len(expo.bodies) != 1 || expo.bodies[0] != `@ada claimed ₦5,000.00 from "Birthday".` {
+		t.Fatalf("unexpected pushes %v", expo.bodies)
+	}
+}
+
+func TestHandleReroutedInternal_NotifiesRecipient(t *testing.T) {
+	svc, repo, expo, _ := newDeviceNotificationService()
+	repo.devices["ExponentPushToken[a]"] = domain.DeviceToken{Token: "ExponentPushToken[a]", UserID: "recipient", Provider: domain.DeviceProviderExpo}
+	repo.usernames["sender"] = "ada"
+	handler := NewTransferEventHandler(svc)
+
+	body := []byte(`{"recipient_id":"recipient","sender_id":"sender","amount":500000,"reason":"P2P transfer"}`)
+	if ack := handler.HandleReroutedInternal(body); !ack {
+		t.Fatal("expected message to be acknowledged")
+	}
+	want := "@ada's transfer of ₦5,000.00 went to your Transfa wallet because it couldn't be sent to your bank."
+	if len(expo.bodies) != 1 || expo.bodies[0] != want {
+		t.Fatalf("unexpected pushes %v", expo.bodies)
+	}
+}
+
+func TestHandleTransferStatus_NotifiesWalletOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantBody string
+	}{
+		{
+			name:     "failed",
+			body:     `{"status":"failed","transfer_type":"nip","anchor_transfer_id":"t1","anchor_account_id":"anchor-wallet","amount":250000}`,
+			wantBody: "Your transfer of ₦2,500.00 could not be completed.",
+		},
+		{
+			name:     "successful",
+			body:     `{"status":"successful","transfer_type":"nip","anchor_transfer_id":"t1","anchor_account_id":"anchor-wallet","amount":250000}`,
+			wantBody: "Your transfer of ₦2,500.00 has been delivered.",
+		},
+		{
+			name: "unknown account",
+			body: `{"status":"failed","transfer_type":"nip","anchor_transfer_id":"t1","anchor_account_id":"anchor-drop","amount":250000}`,
+		},
+		{
+			name: "processing",
+			body: `{"status":"processing","transfer_type":"nip","anchor_transfer_id":"t1","anchor_account_id":"anchor-wallet","amount":250000}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _, fcm := newDeviceNotificationService()
+			repo.devices["fcm"] = domain.DeviceToken{Token: "fcm", UserID: "owner", Provider: domain.DeviceProviderFCM}
+			repo.wallets["anchor-wallet"] = "owner"
+			handler := NewTransferEventHandler(svc)
+
+			if ack := handler.HandleTransferStatus([]byte(tt.body)); !ack {
+				t.Fatal("expected message to be acknowledged")
+			}
+			if tt.wantBody == "" {
+				if len(fcm.bodies) != 0 {
+					t.Fatalf("expected no push, got %v", fcm.bodies)
+				}
+				return
+			}
+			if len(fcm.bodies) != 1 || fcm.bodies[0] != tt.wantBody {
+				t.Fatalf("unexpected pushes %v", fcm.bodies)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+/**
+ * @description
+ * This file contains the email receipt dispatcher. It turns transfer and money drop
+ * events into emails for users who have opted in, rendering them from the templates
+ * in templates/ and sending them through an EmailSender.
+ *
+ * Event handlers only queue a receipt and acknowledge; workers resolve the user,
+ * render and send in the background, retrying failed sends with backoff, so SMTP
+ * latency never holds up the queue.
+ *
+ * @dependencies
+ * - bytes, context, embed, encoding/json, errors, fmt, html/template, log, strings, time: Standard Go libraries.
+ * - internal/domain, internal/store: For event payloads, preferences and lookups.
+ */
+package app
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+	"github.com/transfa/notification-service/internal/store"
+)
+
+//go:embed templates/*.html
+var emailTemplateFS embed.FS
+
+// Receipt templates, by file name under templates/.
+const (
+	emailTemplateTransferCompleted = "transfer_completed.html"
+	emailTemplateTransferFailed    = "transfer_failed.html"
+	emailTemplateMoneyDropClaimed  = "money_drop_claimed.html"
+)
+
+const (
+	emailQueueSize           = 256
+	defaultEmailMaxAttempts  = 4
+	defaultEmailRetryBackoff = 2 * time.Second
+	emailSendTimeout         = 30 * time.Second
+)
+
+var receiptLocation = time.FixedZone("WAT", 60*60)
+
+// EmailSender is implemented by clients that can deliver a rendered email.
+type EmailSender interface {
+	Send(ctx context.Context, msg domain.EmailMessage) error
+}
+
+// ContactLookup finds a user's email address.
+type ContactLookup interface {
+	GetUserContact(ctx context.Context, userID string) (*domain.UserContact, error)
+}
+
+// emailJob is a receipt waiting to be delivered. Transfer receipts carry the Anchor
+// account instead of the user, who is resolved by the worker.
+type emailJob struct {
+	template        string
+	userID          string
+	anchorAccountID string
+	claimantID      string
+	amount          int64
+	reason          string
+	reference       string
+	dropTitle       string
+	occurredAt      time.Time
+}
+
+type receiptDetail struct {
+	Label string
+	Value string
+}
+
+// receiptData is what the receipt templates render.
+type receiptData struct {
+	Subject   string
+	Name      string
+	Amount    string
+	Reason    string
+	Claimant  string
+	DropTitle string
+	Details   []receiptDetail
+}
+
+// EmailReceiptDispatcher queues and delivers email receipts.
+type EmailReceiptDispatcher struct {
+	store     store.Repository
+	contacts  ContactLookup
+	sender    EmailSender
+	templates *template.Template
+	jobs      chan emailJob
+
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// NewEmailReceiptDispatcher creates an EmailReceiptDispatcher. Call Start to begin
+// delivering queued receipts.
+func NewEmailReceiptDispatcher(repo store.Repository, contacts ContactLookup, sender EmailSender) (*EmailReceiptDispatcher, error) {
+	templates, err := template.ParseFS(emailTemplateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email templates: %w", err)
+	}
+	return &EmailReceiptDispatcher{
+		store:        repo,
+		contacts:     contacts,
+		sender:       sender,
+		templates:    templates,
+		jobs:         make(chan emailJob, emailQueueSize),
+		maxAttempts:  defaultEmailMaxAttempts,
+		retryBackoff: defaultEmailRetryBackoff,
+	}, nil
+}
+
+// Start runs workers that deliver queued receipts until ctx is cancelled.
+func (d *EmailReceiptDispatcher) Start(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-d.jobs:
+					d.process(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+// HandleTransferStatus processes a `transfer.status.*` event and queues a completed or
+// failed transfer receipt for the owner of the source wallet. It always acknowledges.
+func (d *EmailReceiptDispatcher) HandleTransferStatus(body []byte) bool {
+	var event domain.TransferStatusEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=email flow=transfer_status outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.AnchorAccountID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=email flow=transfer_status outcome=ack reason=invalid_payload anchor_transfer_id=%s", event.AnchorTransferID)
+		return true
+	}
+
+	job := emailJob{
+		anchorAccountID: event.AnchorAccountID,
+		amount:          event.Amount,
+		reference:       event.AnchorTransferID,
+		occurredAt:      event.OccurredAt,
+	}
+	switch strings.ToLower(strings.TrimSpace(event.Status)) {
+	case "failed", "failure", "fail":
+		job.template = emailTemplateTransferFailed
+		job.reason = strings.TrimSpace(event.Reason)
+	case "successful", "success", "completed":
+		job.template = emailTemplateTransferCompleted
+	default:
+		return true
+	}
+
+	d.enqueue(job)
+	return true
+}
+
+// HandleMoneyDropClaimed processes a `money_drop.claimed` event and queues a receipt for
+// the drop's creator. It always acknowledges.
+func (d *EmailReceiptDispatcher) HandleMoneyDropClaimed(body []byte) bool {
+	var event domain.MoneyDropClaimedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=email flow=money_drop_claimed outcome=ack reason=malformed_payload err=%v", err)
+		return true
+	}
+	if strings.TrimSpace(event.CreatorID) == "" || event.Amount <= 0 {
+		log.Printf("level=warn component=email flow=money_drop_claimed outcome=ack reason=invalid_payload drop_id=%s", event.DropID)
+		return true
+	}
+
+	d.enqueue(emailJob{
+		template:   emailTemplateMoneyDropClaimed,
+		userID:     event.CreatorID,
+		claimantID: event.ClaimantID,
+		amount:     event.Amount,
+		reference:  event.TransactionID,
+		dropTitle:  strings.TrimSpace(event.Title),
+		occurredAt: time.Now(),
+	})
+	return true
+}
+
+// enqueue hands job to the workers without blocking. When the queue is full the
+// receipt is dropped: it is a courtesy copy and the event must still be acknowledged.
+func (d *EmailReceiptDispatcher) enqueue(job emailJob) {
+	select {
+	case d.jobs <- job:
+	default:
+		log.Printf("level=warn component=email msg=\"email queue full; receipt dropped\" template=%s reference=%s", job.template, job.reference)
+	}
+}
+
+// process delivers job, retrying with exponential backoff up to maxAttempts times.
+func (d *EmailReceiptDispatcher) process(ctx context.Context, job emailJob) {
+	backoff := d.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.deliver(ctx, job)
+		if err == nil {
+			return
+		}
+		if attempt >= d.maxAttempts {
+			log.Printf("level=error component=email outcome=dropped msg=\"email receipt failed\" template=%s reference=%s attempts=%d err=%v", job.template, job.reference, attempt, err)
+			return
+		}
+		log.Printf("level=warn component=email outcome=retry msg=\"email receipt failed\" template=%s reference=%s attempt=%d err=%v", job.template, job.reference, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver resolves the recipient, checks their preference, renders and sends job. It
+// returns nil without sending when the user should not get the receipt.
+func (d *EmailReceiptDispatcher) deliver(ctx context.Context, job emailJob) error {
+	ctx, cancel := context.WithTimeout(ctx, emailSendTimeout)
+	defer cancel()
+
+	userID := job.userID
+	if userID == "" {
+		owner, err := d.store.FindUserIDByAnchorAccountID(ctx, job.anchorAccountID)
+		if errors.Is(err, store.ErrAccountNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("owner lookup failed: %w", err)
+		}
+		userID = owner
+	}
+
+	prefs, err := d.store.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("preference lookup failed: %w", err)
+	}
+	if !prefs.EmailReceipts {
+		return nil
+	}
+
+	contact, err := d.contacts.GetUserContact(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("contact lookup failed: %w", err)
+	}
+	if contact == nil || contact.Email == nil || strings.TrimSpace(*contact.Email) == "" {
+		log.Printf("level=info component=email msg=\"user has no email; receipt skipped\" user_id=%s", userID)
+		return nil
+	}
+
+	msg, err := d.render(ctx, job, contact)
+	if err != nil {
+		return err
+	}
+	msg.To = strings.TrimSpace(*contact.Email)
+
+	if err := d.sender.Send(ctx, msg); err != nil {
+		return err
+	}
+	log.Printf("level=info component=email outcome=sent template=%s user_id=%s reference=%s", job.template, userID, job.reference)
+	return nil
+}
+
+// render builds the email for job from its template.
+func (d *EmailReceiptDispatcher) render(ctx context.Context, job emailJob, contact *domain.UserContact) (domain.EmailMessage, error) {
+	data := receiptData{
+		Name:   recipientName(contact),
+		Amount: formatKoboAsNaira(job.amount),
+		Reason: job.reason,
+	}
+
+	var headline string
+	switch job.template {
+	case emailTemplateTransferCompleted:
+		data.Subject = fmt.Sprintf("Receipt: %s transfer sent", data.Amount)
+		headline = fmt.Sprintf("Your transfer of %s has been delivered.", data.Amount)
+	case emailTemplateTransferFailed:
+		data.Subject = fmt.Sprintf("Your %s transfer failed", data.Amount)
+		headline = fmt.Sprintf("Your transfer of %s could not be completed.", data.Amount)
+	case emailTemplateMoneyDropClaimed:
+		data.Claimant = "Someone"
+		if username, err := d.store.FindUsernameByUserID(ctx, job.claimantID); err == nil && strings.TrimSpace(username) != "" {
+			data.Claimant = "@" + strings.TrimSpace(username)
+		}
+		data.DropTitle = job.dropTitle
+		data.Subject = fmt.Sprintf("%s claimed %s from your money drop", data.Claimant, data.Amount)
+		headline = data.Subject + "."
+	default:
+		return domain.EmailMessage{}, fmt.Errorf("unknown email template %q", job.template)
+	}
+
+	data.Details = append(data.Details, receiptDetail{Label: "Amount", Value: data.Amount})
+	if !job.occurredAt.IsZero() {
+		data.Details = append(data.Details, receiptDetail{Label: "Date", Value: job.occurredAt.In(receiptLocation).Format("02 Jan 2006, 15:04 MST")})
+	}
+	if job.reference != "" {
+		data.Details = append(data.Details, receiptDetail{Label: "Reference", Value: job.reference})
+	}
+
+	var html bytes.Buffer
+	if err := d.templates.ExecuteTemplate(&html, job.template, data); err != nil {
+		return domain.EmailMessage{}, fmt.Errorf("failed to render %s: %w", job.template, err)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Hi %s,\n\n%s\n", data.Name, headline)
+	if data.Reason != "" {
+		fmt.Fprintf(&text, "%s\n", data.Reason)
+	}
+	text.WriteString("\n")
+	for _, detail := range data.Details {
+		fmt.Fprintf(&text, "%s: %s\n", detail.Label, detail.Value)
+	}
+
+	return domain.EmailMessage{Subject: data.Subject, HTML: html.String(), Text: text.String()}, nil
+}
+
+// recipientName greets the user by first name, falling back to their username.
+func recipientName(contact *domain.UserContact) string {
+	if contact.FullName != nil {
+		if fields := strings.Fields(*contact.FullName); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	if contact.Username != nil && strings.TrimSpace(*contact.Username) != "" {
+		return "@" + strings.TrimSpace(*contact.Username)
+	}
+	return "there"
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+type recordingEmailSender struct {
+	sent     []domain.EmailMessage
+	failures int
+}
+
+func (s *recordingEmailSender) Send(ctx context.Context, msg domain.EmailMessage) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("smtp unavailable")
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+type contactLookupStub map[string]*domain.UserContact
+
+func (s contactLookupStub) GetUserContact(ctx context.Context, userID string) (*domain.UserContact, error) {
+	contact, ok := s[userID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return contact, nil
+}
+
+func newEmailTestDispatcher(t *testing.T) (*EmailReceiptDispatcher, *memoryStore, *recordingEmailSender) {
+	t.Helper()
+	repo := newMemoryStore()
+	repo.wallets["anchor-wallet"] = "owner"
+	repo.preferences["owner"] = domain.NotificationPreferences{TransactionAlerts: true, EmailReceipts: true}
+	email := "ada@example.com"
+	fullName := "Ada Obi"
+	contacts := contactLookupStub{"owner": {UserID: "owner", Email: &email, FullName: &fullName}}
+	sender := &recordingEmailSender{}
+
+	dispatcher, err := NewEmailReceiptDispatcher(repo, contacts, sender)
+	if err != nil {
+		t.Fatalf("NewEmailReceiptDispatcher: %v", err)
+	}
+	dispatcher.retryBackoff = time.Millisecond
+	return dispatcher, repo, sender
+}
+
+// drain delivers every queued receipt synchronously.
+func drain(d *EmailReceiptDispatcher) {
+	for {
+		select {
+		case job := <-d.jobs:
+			d.process(context.Background(), job)
+		default:
+			return
+		}
+	}
+}
+
+func TestEmailReceipts_RenderTransferTemplates(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantSubject string
+		wantHTML    []string
+	}{
+		{
+			name:        "completed",
+			body:        `{"status":"successful","anchor_transfer_id":"tr_1","anchor_account_id":"anchor-wallet","amount":500000,"occurred_at":"2026-03-01T09:30:00Z"}`,
+			wantSubject: "Receipt: ₦5,000.00 transfer sent",
+			wantHTML:    []string{"Hi Ada,", "₦5,000.00", "tr_1", "01 Mar 2026, 10:30 WAT"},
+		},
+		{
+			name:        "failed",
+			body:        `{"status":"failed","anchor_transfer_id":"tr_2","anchor_account_id":"anchor-wallet","amount":500000,"reason":"Beneficiary bank unavailable <retry>"}`,
+			wantSubject: "Your ₦5,000.00 transfer failed",
+			wantHTML:    []string{"could not be completed", "Beneficiary bank unavailable &lt;retry&gt;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher, _, sender := newEmailTestDispatcher(t)
+
+			if ack := dispatcher.HandleTransferStatus([]byte(tt.body)); !ack {
+				t.Fatal("expected message to be acknowledged")
+			}
+			drain(dispatcher)
+
+			if len(sender.sent) != 1 {
+				t.Fatalf("expected one email, got %d", len(sender.sent))
+			}
+			msg := sender.sent[0]
+			if msg.To != "ada@example.com" || msg.Subject != tt.wantSubject {
+				t.Fatalf("unexpected email to=%q subject=%q", msg.To, msg.Subject)
+			}
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(msg.HTML, want) {
+					t.Fatalf("expected html to contain %q:\n%s", want, msg.HTML)
+				}
+			}
+			if !strings.Contains(msg.Text, "Amount: ₦5,000.00") {
+				t.Fatalf("expected a plain text part, got %q", msg.Text)
+			}
+		})
+	}
+}
+
+func TestEmailReceipts_RenderMoneyDropClaimed(t *testing.T) {
+	dispatcher, repo, sender := newEmailTestDispatcher(t)
+	repo.usernames["claimant"] = "tunde"
+
+	body := []byte(`{"drop_id":"drop","title":"Birthday","creator_id":"owner","claimant_id":"claimant","amount":100000,"transaction_id":"tx"}`)
+	if ack := dispatcher.HandleMoneyDropClaimed(body); !ack {
+		t.Fatal("expected message to be acknowledged")
+	}
+	drain(dispatcher)
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one email, got %d", len(sender.sent))
+	}
+	if sender.sent[0].Subject != "@tunde claimed ₦1,000.00 from your money drop" {
+		t.Fatalf("unexpected subject %q", sender.sent[0].Subject)
+	}
+	if !strings.Contains(sender.sent[0].HTML, `from "Birthday".`) {
+		t.Fatalf("expected the drop title in the html:\n%s", sender.sent[0].HTML)
+	}
+}
+
+func TestEmailReceipts_SkipUsersWhoHaveNotOptedIn(t *testing.T) {
+	dispatcher, repo, sender := newEmailTestDispatcher(t)
+	repo.preferences["owner"] = domain.DefaultNotificationPreferences()
+
+	dispatcher.HandleTransferStatus([]byte(`{"status":"successful","anchor_transfer_id":"tr_1","anchor_account_id":"anchor-wallet","amount":500000}`))
+	drain(dispatcher)
+
+	if len(sender.sent) != 0 {
+		t.Fatalf("expected no email without opt-in, got %d", len(sender.sent))
+	}
+}
+
+func TestEmailReceipts_RetryFailedSends(t *testing.T) {
+	dispatcher, _, sender := newEmailTestDispatcher(t)
+	sender.failures = defaultEmailMaxAttempts - 1
+
+	dispatcher.HandleTransferStatus([]byte(`{"status":"successful","anchor_transfer_id":"tr_1","anchor_account_id":"anchor-wallet","amount":500000}`))
+	drain(dispatcher)
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected the last attempt to succeed, got %d emails", len(sender.sent))
+	}
+}
+
+func TestEmailReceipts_HandlersNeverBlockOnAFullQueue(t *testing.T) {
+	dispatcher, _, sender := newEmailTestDispatcher(t)
+	body := []byte(`{"status":"successful","anchor_transfer_id":"tr_1","anchor_account_id":"anchor-wallet","amount":500000}`)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < emailQueueSize+10; i++ {
+			if ack := dispatcher.HandleTransferStatus(body); !ack {
+				t.Error("expected message to be acknowledged")
+			}
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler blocked with no worker running")
+	}
+	if len(dispatcher.jobs) != emailQueueSize || len(sender.sent) != 0 {
+		t.Fatalf("expected a full queue and no sends, got queued=%d sent=%d", len(dispatcher.jobs), len(sender.sent))
+	}
+}
@@ -0,0 +1,79 @@
+/**
+ * @description
+ * This file contains the push notification entry point for the notification-service.
+ * Delivery is delegated to a PushProvider so the concrete provider can be swapped
+ * via configuration without touching the event handlers.
+ *
+ * @dependencies
+ * - context, errors, log, strings: Standard Go libraries.
+ * - internal/store: For device tokens and notification preferences.
+ */
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/transfa/notification-service/internal/store"
+)
+
+var ErrInvalidPushNotification = errors.New("push notification requires a user id, title and body")
+
+// PushProvider is implemented by clients that can deliver a push notification to a user's devices.
+type PushProvider interface {
+	Send(ctx context.Context, userID, title, body string) error
+}
+
+// LogPushProvider is a no-op provider used when no push provider is configured.
+type LogPushProvider struct{}
+
+// Send logs the notification instead of delivering it.
+func (LogPushProvider) Send(ctx context.Context, userID, title, body string) error {
+	log.Printf("level=warn component=push mode=fallback msg=\"push provider not configured; notification skipped\" user_id=%s title=%q", userID, title)
+	return nil
+}
+
+// NotificationService sends user-facing notifications.
+type NotificationService struct {
+	pushProvider PushProvider
+	store        store.Repository
+}
+
+// NewNotificationService creates a NotificationService. A nil provider falls back to LogPushProvider.
+func NewNotificationService(pushProvider PushProvider) *NotificationService {
+	if pushProvider == nil {
+		pushProvider = LogPushProvider{}
+	}
+	return &NotificationService{pushProvider: pushProvider}
+}
+
+// ConfigureStore enables device registration, notification preferences and the
+// event handlers that need to look users up.
+func (s *NotificationService) ConfigureStore(repo store.Repository) {
+	s.store = repo
+}
+
+// SendPushNotification delivers a transaction alert to every device registered for
+// userID, unless the user has turned transaction alerts off.
+func (s *NotificationService) SendPushNotification(ctx context.Context, userID, title, body string) error {
+	userID = strings.TrimSpace(userID)
+	title = strings.TrimSpace(title)
+	body = strings.TrimSpace(body)
+	if userID == "" || title == "" || body == "" {
+		return ErrInvalidPushNotification
+	}
+
+	if s.store != nil {
+		prefs, err := s.store.GetNotificationPreferences(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !prefs.TransactionAlerts {
+			log.Printf("level=info component=push msg=\"transaction alerts disabled; notification skipped\" user_id=%s", userID)
+			return nil
+		}
+	}
+	return s.pushProvider.Send(ctx, userID, title, body)
+}
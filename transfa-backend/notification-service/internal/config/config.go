@@ -12,8 +12,10 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config stores all configuration for the application.
@@ -24,6 +26,42 @@ type Config struct {
 	AnchorWebhookSecret string `mapstructure:"ANCHOR_WEBHOOK_SECRET"`
 	AnchorAPIKey        string `mapstructure:"ANCHOR_API_KEY"`
 	AnchorAPIBaseURL    string `mapstructure:"ANCHOR_API_BASE_URL"`
+	PushProviderURL     string `mapstructure:"PUSH_PROVIDER_URL"`
+	PushProviderAPIKey  string `mapstructure:"PUSH_PROVIDER_API_KEY"`
+	TransferEventQueue  string `mapstructure:"TRANSFER_EVENT_QUEUE"`
+	AppEnv              string `mapstructure:"APP_ENV"`
+	AllowedOrigins      string `mapstructure:"ALLOWED_ORIGINS"`
+	EventSigningSecret  string `mapstructure:"EVENT_SIGNING_SECRET"`
+	DatabaseURL         string `mapstructure:"DATABASE_URL"`
+	ClerkJWKSURL        string `mapstructure:"CLERK_JWKS_URL"`
+
+	FCMServiceAccountJSON string `mapstructure:"FCM_SERVICE_ACCOUNT_JSON"`
+	ExpoAccessToken       string `mapstructure:"EXPO_ACCESS_TOKEN"`
+
+	// EmailReceiptsEnabled is the global switch for email receipts; users must also
+	// have opted in.
+	EmailReceiptsEnabled bool   `mapstructure:"EMAIL_RECEIPTS_ENABLED"`
+	EmailEventQueue      string `mapstructure:"EMAIL_EVENT_QUEUE"`
+	EmailProvider        string `mapstructure:"EMAIL_PROVIDER"`
+	EmailFrom            string `mapstructure:"EMAIL_FROM"`
+	ResendAPIKey         string `mapstructure:"RESEND_API_KEY"`
+	SMTPHost             string `mapstructure:"SMTP_HOST"`
+	SMTPPort             string `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string `mapstructure:"SMTP_PASSWORD"`
+	AuthServiceURL       string `mapstructure:"AUTH_SERVICE_URL"`
+	InternalAPIKey       string `mapstructure:"INTERNAL_API_KEY"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        10,
+	MinConns:        2,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -38,6 +76,12 @@ func LoadConfig() (config Config, err error) {
 	// Set default values
 	viper.SetDefault("SERVER_PORT", "8081")
 	viper.SetDefault("ANCHOR_API_BASE_URL", "https://api.sandbox.getanchor.co")
+	viper.SetDefault("TRANSFER_EVENT_QUEUE", "notification_service.transfer_events")
+	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("EMAIL_RECEIPTS_ENABLED", false)
+	viper.SetDefault("EMAIL_EVENT_QUEUE", "notification_service.email_events")
+	viper.SetDefault("EMAIL_PROVIDER", "resend")
+	viper.SetDefault("SMTP_PORT", "587")
 
 	// Bind env vars explicitly
 	_ = viper.BindEnv("SERVER_PORT")
@@ -45,6 +89,27 @@ func LoadConfig() (config Config, err error) {
 	_ = viper.BindEnv("ANCHOR_WEBHOOK_SECRET")
 	_ = viper.BindEnv("ANCHOR_API_KEY")
 	_ = viper.BindEnv("ANCHOR_API_BASE_URL")
+	_ = viper.BindEnv("PUSH_PROVIDER_URL")
+	_ = viper.BindEnv("PUSH_PROVIDER_API_KEY")
+	_ = viper.BindEnv("TRANSFER_EVENT_QUEUE")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("ALLOWED_ORIGINS")
+	_ = viper.BindEnv("EVENT_SIGNING_SECRET")
+	_ = viper.BindEnv("DATABASE_URL")
+	_ = viper.BindEnv("CLERK_JWKS_URL")
+	_ = viper.BindEnv("FCM_SERVICE_ACCOUNT_JSON")
+	_ = viper.BindEnv("EXPO_ACCESS_TOKEN")
+	_ = viper.BindEnv("EMAIL_RECEIPTS_ENABLED")
+	_ = viper.BindEnv("EMAIL_EVENT_QUEUE")
+	_ = viper.BindEnv("EMAIL_PROVIDER")
+	_ = viper.BindEnv("EMAIL_FROM")
+	_ = viper.BindEnv("RESEND_API_KEY")
+	_ = viper.BindEnv("SMTP_HOST")
+	_ = viper.BindEnv("SMTP_PORT")
+	_ = viper.BindEnv("SMTP_USERNAME")
+	_ = viper.BindEnv("SMTP_PASSWORD")
+	_ = viper.BindEnv("AUTH_SERVICE_URL")
+	_ = viper.BindEnv("INTERNAL_API_KEY")
 
 	// Read the config file if it exists.
 	if err = viper.ReadInConfig(); err != nil {
@@ -58,5 +123,6 @@ func LoadConfig() (config Config, err error) {
 		return config, fmt.Errorf("decode config: %w", err)
 	}
 
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Push token providers a device can register with.
+const (
+	DeviceProviderExpo = "expo"
+	DeviceProviderFCM  = "fcm"
+)
+
+// DeviceToken is a push token the app registered for one of a user's devices.
+type DeviceToken struct {
+	Token     string    `json:"token"`
+	UserID    string    `json:"-"`
+	Provider  string    `json:"provider"`
+	Platform  string    `json:"platform,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RegisterDevicePayload is the body of POST /me/devices. Provider defaults to expo
+// for Expo push tokens and fcm otherwise.
+type RegisterDevicePayload struct {
+	Token    string `json:"token"`
+	Provider string `json:"provider,omitempty"`
+	Platform string `json:"platform,omitempty"`
+}
+
+// NotificationPreferences is a user's choice of which notifications to receive.
+// Users without a stored record get DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	TransactionAlerts bool       `json:"transaction_alerts"`
+	Marketing         bool       `json:"marketing"`
+	EmailReceipts     bool       `json:"email_receipts"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultNotificationPreferences has transaction alerts on, and marketing and email
+// receipts off.
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{TransactionAlerts: true}
+}
+
+// UpdateNotificationPreferencesPayload is the body of PUT /me/notification-preferences.
+// Omitted fields keep their current value.
+type UpdateNotificationPreferencesPayload struct {
+	TransactionAlerts *bool `json:"transaction_alerts,omitempty"`
+	Marketing         *bool `json:"marketing,omitempty"`
+	EmailReceipts     *bool `json:"email_receipts,omitempty"`
+}
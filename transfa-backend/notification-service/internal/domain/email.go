@@ -0,0 +1,17 @@
+package domain
+
+// EmailMessage is a rendered email ready to hand to an email provider.
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// UserContact is a user's contact details as returned by the auth-service.
+type UserContact struct {
+	UserID   string  `json:"user_id"`
+	Email    *string `json:"email,omitempty"`
+	Username *string `json:"username,omitempty"`
+	FullName *string `json:"full_name,omitempty"`
+}
@@ -20,3 +20,41 @@ type TransferStatusEvent struct {
 	OccurredAt       time.Time `json:"occurred_at"`
 }
 
+// P2PTransferInitiatedEvent is published by the transaction-service once a P2P
+// transfer has been accepted by Anchor and is awaiting settlement.
+type P2PTransferInitiatedEvent struct {
+	SenderUsername string `json:"sender_username"`
+	RecipientID    string `json:"recipient_id"`
+	Amount         int64  `json:"amount"`
+	TransactionID  string `json:"transaction_id"`
+}
+
+// PaymentRequestReceivedEvent is published by the transaction-service once an
+// individual payment request has been created for a recipient.
+type PaymentRequestReceivedEvent struct {
+	CreatorUsername string  `json:"creator_username"`
+	RecipientID     string  `json:"recipient_id"`
+	Amount          int64   `json:"amount"`
+	Description     *string `json:"description,omitempty"`
+	RequestID       string  `json:"request_id"`
+}
+
+// ReroutedInternalEvent is published by the transaction-service when a P2P transfer
+// to a recipient who asked for bank payouts is paid into their wallet instead.
+type ReroutedInternalEvent struct {
+	RecipientID string `json:"recipient_id"`
+	SenderID    string `json:"sender_id"`
+	Amount      int64  `json:"amount"`
+	Reason      string `json:"reason"`
+}
+
+// MoneyDropClaimedEvent is published by the transaction-service once a claim on a
+// money drop has been recorded.
+type MoneyDropClaimedEvent struct {
+	DropID        string `json:"drop_id"`
+	Title         string `json:"title"`
+	CreatorID     string `json:"creator_id"`
+	ClaimantID    string `json:"claimant_id"`
+	Amount        int64  `json:"amount"`
+	TransactionID string `json:"transaction_id"`
+}
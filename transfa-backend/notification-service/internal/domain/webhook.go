@@ -56,6 +56,7 @@ type CustomerTierStatusEvent struct {
 	Stage            string  `json:"stage,omitempty"`
 	Status           string  `json:"status"`
 	Reason           *string `json:"reason,omitempty"`
+	VerificationID   string  `json:"verification_id,omitempty"`
 }
 
 // AccountLifecycleEvent represents account-related events from Anchor.
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrAccountNotFound = errors.New("account not found")
+)
+
+// PostgresRepository implements Repository on PostgreSQL.
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgresRepository.
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// FindUserIDByClerkUserID resolves the internal user id for a Clerk user id.
+func (r *PostgresRepository) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	var id string
+	err := r.db.QueryRow(ctx, `SELECT id::text FROM users WHERE clerk_user_id = $1`, clerkUserID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	return id, err
+}
+
+// FindUsernameByUserID returns the user's current username.
+func (r *PostgresRepository) FindUsernameByUserID(ctx context.Context, userID string) (string, error) {
+	var username *string
+	err := r.db.QueryRow(ctx, `SELECT username FROM users WHERE id = $1::uuid`, userID).Scan(&username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if username == nil {
+		return "", nil
+	}
+	return *username, nil
+}
+
+// FindUserIDByAnchorAccountID returns the owner of the primary wallet with the given
+// Anchor account id. Money drop and pot accounts are not matched, since their
+// transfers are not ones the user made themselves.
+func (r *PostgresRepository) FindUserIDByAnchorAccountID(ctx context.Context, anchorAccountID string) (string, error) {
+	var id string
+	err := r.db.QueryRow(ctx, `SELECT user_id::text FROM accounts WHERE anchor_account_id = $1 AND account_type = 'primary' LIMIT 1`, anchorAccountID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrAccountNotFound
+	}
+	return id, err
+}
+
+// UpsertDeviceToken registers a push token for a user. A token already registered to
+// another user moves to this one, since a device signs in to one account at a time.
+func (r *PostgresRepository) UpsertDeviceToken(ctx context.Context, device domain.DeviceToken) (*domain.DeviceToken, error) {
+	query := `
+		INSERT INTO device_tokens (token, user_id, provider, platform)
+		VALUES ($1, $2::uuid, $3, NULLIF($4, ''))
+		ON CONFLICT (token)
+		DO UPDATE SET user_id = EXCLUDED.user_id, provider = EXCLUDED.provider, platform = EXCLUDED.platform, updated_at = NOW()
+		RETURNING token, user_id::text, provider, COALESCE(platform, ''), created_at, updated_at
+	`
+	var item domain.DeviceToken
+	err := r.db.QueryRow(ctx, query, device.Token, device.UserID, device.Provider, device.Platform).Scan(
+		&item.Token, &item.UserID, &item.Provider, &item.Platform, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteDeviceToken removes one of the user's tokens and reports whether it existed.
+func (r *PostgresRepository) DeleteDeviceToken(ctx context.Context, userID, token string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE user_id = $1::uuid AND token = $2`, userID, token)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteDeviceTokenByValue removes a token the push provider reported as no longer valid.
+func (r *PostgresRepository) DeleteDeviceTokenByValue(ctx context.Context, token string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE token = $1`, token)
+	return err
+}
+
+// ListDeviceTokens returns every token registered for the user.
+func (r *PostgresRepository) ListDeviceTokens(ctx context.Context, userID string) ([]domain.DeviceToken, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT token, user_id::text, provider, COALESCE(platform, ''), created_at, updated_at
+		FROM device_tokens
+		WHERE user_id = $1::uuid
+		ORDER BY updated_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []domain.DeviceToken
+	for rows.Next() {
+		var item domain.DeviceToken
+		if err := rows.Scan(&item.Token, &item.UserID, &item.Provider, &item.Platform, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, item)
+	}
+	return devices, rows.Err()
+}
+
+// GetNotificationPreferences returns the user's stored preferences, or the defaults
+// when they have never changed them.
+func (r *PostgresRepository) GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	err := r.db.QueryRow(ctx, `
+		SELECT transaction_alerts, marketing, email_receipts, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1::uuid
+	`, userID).Scan(&prefs.TransactionAlerts, &prefs.Marketing, &prefs.EmailReceipts, &prefs.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		defaults := domain.DefaultNotificationPreferences()
+		return &defaults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// UpsertNotificationPreferences stores the user's preferences.
+func (r *PostgresRepository) UpsertNotificationPreferences(ctx context.Context, userID string, prefs domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	var stored domain.NotificationPreferences
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO notification_preferences (user_id, transaction_alerts, marketing, email_receipts)
+		VALUES ($1::uuid, $2, $3, $4)
+		ON CONFLICT (user_id)
+		DO UPDATE SET transaction_alerts = EXCLUDED.transaction_alerts, marketing = EXCLUDED.marketing, email_receipts = EXCLUDED.email_receipts, updated_at = NOW()
+		RETURNING transaction_alerts, marketing, email_receipts, updated_at
+	`, userID, prefs.TransactionAlerts, prefs.Marketing, prefs.EmailReceipts).Scan(&stored.TransactionAlerts, &stored.Marketing, &stored.EmailReceipts, &stored.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
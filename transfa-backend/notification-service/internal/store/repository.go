@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+// Repository is the notification-service's view of the shared database: the device
+// tokens and preferences it owns, and the users and accounts it reads to address
+// notifications.
+type Repository interface {
+	FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error)
+	FindUsernameByUserID(ctx context.Context, userID string) (string, error)
+	FindUserIDByAnchorAccountID(ctx context.Context, anchorAccountID string) (string, error)
+
+	UpsertDeviceToken(ctx context.Context, device domain.DeviceToken) (*domain.DeviceToken, error)
+	DeleteDeviceToken(ctx context.Context, userID, token string) (bool, error)
+	DeleteDeviceTokenByValue(ctx context.Context, token string) error
+	ListDeviceTokens(ctx context.Context, userID string) ([]domain.DeviceToken, error)
+
+	GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, userID string, prefs domain.NotificationPreferences) (*domain.NotificationPreferences, error)
+}
@@ -0,0 +1,75 @@
+/**
+ * @description
+ * This package provides a client for the auth-service's internal user endpoints,
+ * used to find where to send email receipts.
+ */
+package authclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+// ErrUserNotFound is returned when the auth-service has no open account for the user.
+var ErrUserNotFound = errors.New("user not found")
+
+// Client is a client for the auth service.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a new auth service client.
+func NewClient(baseURL string, apiKey string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:     strings.TrimSpace(apiKey),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetUserContact returns the email and names of the user with the internal user id.
+func (c *Client) GetUserContact(ctx context.Context, userID string) (*domain.UserContact, error) {
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("auth service base url is empty")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/%s/contact", c.baseURL, url.PathEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-Internal-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request to auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("auth service returned error status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var contact domain.UserContact
+	if err := json.NewDecoder(resp.Body).Decode(&contact); err != nil {
+		return nil, fmt.Errorf("failed to decode user contact: %w", err)
+	}
+	return &contact, nil
+}
@@ -0,0 +1,83 @@
+/**
+ * @description
+ * This package provides the email providers the notification-service can send
+ * receipts through: the Resend HTTP API and plain SMTP.
+ */
+package emailclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+const defaultResendBaseURL = "https://api.resend.com"
+
+// ResendSender sends email through the Resend API.
+type ResendSender struct {
+	BaseURL    string
+	apiKey     string
+	from       string
+	httpClient *http.Client
+}
+
+type resendRequest struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	HTML    string   `json:"html"`
+	Text    string   `json:"text,omitempty"`
+}
+
+// NewResendSender creates a ResendSender that sends from the given address, e.g.
+// "Transfa <receipts@trytransfa.com>".
+func NewResendSender(apiKey, from string) *ResendSender {
+	return &ResendSender{
+		BaseURL: defaultResendBaseURL,
+		apiKey:  strings.TrimSpace(apiKey),
+		from:    strings.TrimSpace(from),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send delivers a single email.
+func (s *ResendSender) Send(ctx context.Context, msg domain.EmailMessage) error {
+	payload, err := json.Marshal(resendRequest{
+		From:    s.from,
+		To:      []string{msg.To},
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal resend request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.BaseURL, "/")+"/emails", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create resend request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send resend request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("resend returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package emailclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/transfa/notification-service/internal/domain"
+)
+
+// SMTPSender sends email through an SMTP server that supports STARTTLS.
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender creates an SMTPSender. Username and password may be empty for
+// servers that do not require authentication.
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	host = strings.TrimSpace(host)
+	var auth smtp.Auth
+	if strings.TrimSpace(username) != "" {
+		auth = smtp.PlainAuth("", strings.TrimSpace(username), password, host)
+	}
+	return &SMTPSender{
+		addr: net.JoinHostPort(host, strings.TrimSpace(port)),
+		host: host,
+		auth: auth,
+		from: strings.TrimSpace(from),
+	}
+}
+
+// Send delivers a single email. net/smtp does not take a context, so a cancelled ctx
+// only stops a send that has not started yet.
+func (s *SMTPSender) Send(ctx context.Context, msg domain.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	body, err := buildMIMEMessage(s.from, msg)
+	if err != nil {
+		return err
+	}
+	if err := smtp.SendMail(s.addr, s.auth, envelopeAddress(s.from), []string{msg.To}, body); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return nil
+}
+
+// buildMIMEMessage renders msg as a multipart/alternative message with text and HTML parts.
+func buildMIMEMessage(from string, msg domain.EmailMessage) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+// envelopeAddress extracts the bare address from a "Name <address>" sender.
+func envelopeAddress(from string) string {
+	if start := strings.LastIndex(from, "<"); start >= 0 {
+		if end := strings.LastIndex(from, ">"); end > start {
+			return from[start+1 : end]
+		}
+	}
+	return from
+}
+
+func randomBoundary() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate mime boundary: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
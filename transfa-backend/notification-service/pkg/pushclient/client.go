@@ -0,0 +1,75 @@
+/**
+ * @description
+ * This package provides an HTTP client for the push notification provider.
+ * The provider is responsible for resolving a Transfa user ID to its registered
+ * device tokens and fanning the notification out to them.
+ *
+ * @dependencies
+ * - bytes, context, encoding/json, fmt, io, net/http, time: Standard Go libraries.
+ */
+package pushclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client sends push notifications through a configurable HTTP provider.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	httpClient *http.Client
+}
+
+type sendRequest struct {
+	UserID string `json:"user_id"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// NewClient creates a new push provider client.
+func NewClient(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		APIKey:  strings.TrimSpace(apiKey),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send delivers a single notification to every device registered for the user.
+func (c *Client) Send(ctx context.Context, userID, title, body string) error {
+	payload, err := json.Marshal(sendRequest{UserID: userID, Title: title, Body: body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal push request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/notifications", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("push provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
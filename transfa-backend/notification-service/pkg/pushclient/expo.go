@@ -0,0 +1,98 @@
+package pushclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUnregisteredToken is returned when the provider reports that a device token is
+// no longer valid, e.g. because the app was uninstalled. The token should be removed.
+var ErrUnregisteredToken = errors.New("device token is no longer registered")
+
+const defaultExpoPushURL = "https://exp.host/--/api/v2/push/send"
+
+// ExpoSender sends notifications to Expo push tokens through the Expo push service.
+type ExpoSender struct {
+	URL         string
+	AccessToken string
+	httpClient  *http.Client
+}
+
+type expoMessage struct {
+	To    string `json:"to"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Sound string `json:"sound,omitempty"`
+}
+
+type expoResponse struct {
+	Data struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details struct {
+			Error string `json:"error"`
+		} `json:"details"`
+	} `json:"data"`
+}
+
+// NewExpoSender creates an ExpoSender. The access token is only required when push
+// security is enabled for the Expo project.
+func NewExpoSender(accessToken string) *ExpoSender {
+	return &ExpoSender{
+		URL:         defaultExpoPushURL,
+		AccessToken: strings.TrimSpace(accessToken),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send delivers a notification to a single Expo push token.
+func (s *ExpoSender) Send(ctx context.Context, token, title, body string) error {
+	payload, err := json.Marshal(expoMessage{To: token, Title: title, Body: body, Sound: "default"})
+	if err != nil {
+		return fmt.Errorf("failed to marshal expo push request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create expo push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send expo push request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("expo push service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	// Expo reports per-message failures in a 200 response.
+	var ticket expoResponse
+	if err := json.Unmarshal(respBody, &ticket); err != nil {
+		return fmt.Errorf("failed to decode expo push response: %w", err)
+	}
+	if ticket.Data.Status == "error" {
+		if ticket.Data.Details.Error == "DeviceNotRegistered" {
+			return ErrUnregisteredToken
+		}
+		return fmt.Errorf("expo push failed: %s", ticket.Data.Message)
+	}
+
+	return nil
+}
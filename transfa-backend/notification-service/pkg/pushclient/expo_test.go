@@ -0,0 +1,46 @@
+package pushclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpoSender_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  error
+	}{
+		{name: "ok", response: `{"data":{"status":"ok","id":"ticket"}}`},
+		{name: "unregistered", response: `{"data":{"status":"error","message":"not registered","details":{"error":"DeviceNotRegistered"}}}`, wantErr: ErrUnregisteredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got expoMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					t.Errorf("expected access token, got %q", r.Header.Get("Authorization"))
+				}
+				_ = json.NewDecoder(r.Body).Decode(&got)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			sender := NewExpoSender("secret")
+			sender.URL = server.URL
+
+			err := sender.Send(context.Background(), "ExponentPushToken[a]", "Title", "Body")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got.To != "ExponentPushToken[a]" || got.Title != "Title" || got.Body != "Body" {
+				t.Fatalf("unexpected message %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package pushclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+	defaultFCMTokenURL = "https://oauth2.googleapis.com/token"
+	defaultFCMBaseURL  = "https://fcm.googleapis.com"
+)
+
+// FCMSender sends notifications to FCM registration tokens through the FCM HTTP v1
+// API, authenticating as a Google service account.
+type FCMSender struct {
+	BaseURL  string
+	TokenURL string
+
+	projectID   string
+	clientEmail string
+	privateKey  any
+	httpClient  *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string          `json:"token"`
+	Notification fcmNotification `json:"notification"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// NewFCMSender creates an FCMSender from the JSON key of a Google service account
+// with the Firebase Cloud Messaging permission.
+func NewFCMSender(serviceAccountJSON string) (*FCMSender, error) {
+	var account serviceAccount
+	if err := json.Unmarshal([]byte(serviceAccountJSON), &account); err != nil {
+		return nil, fmt.Errorf("failed to parse fcm service account: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("fcm service account requires project_id, client_email and private_key")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fcm service account key: %w", err)
+	}
+
+	return &FCMSender{
+		BaseURL:     defaultFCMBaseURL,
+		TokenURL:    defaultFCMTokenURL,
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		privateKey:  key,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Send delivers a notification to a single FCM registration token.
+func (s *FCMSender) Send(ctx context.Context, token, title, body string) error {
+	accessToken, err := s.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: title, Body: body},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal fcm request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimRight(s.BaseURL, "/"), url.PathEscape(s.projectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create fcm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send fcm request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(respBody, &fcmErr)
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrUnregisteredToken
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUnregisteredToken
+	}
+	return fmt.Errorf("fcm returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// getAccessToken returns a cached OAuth access token, exchanging a freshly signed
+// service account assertion for a new one shortly before the current one expires.
+func (s *FCMSender) getAccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-time.Minute)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.clientEmail,
+		"scope": fcmScope,
+		"aud":   s.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign fcm token assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create fcm token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("fcm token endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode fcm access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("fcm token endpoint returned no access token")
+	}
+
+	s.accessToken = token.AccessToken
+	s.expiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
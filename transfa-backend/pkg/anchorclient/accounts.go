@@ -0,0 +1,283 @@
+package anchorclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// --- Freeze Deposit Account ---
+
+// FreezeDepositAccountRequest is the top-level request structure for freezing a deposit account.
+type FreezeDepositAccountRequest struct {
+	Data RequestData `json:"data"`
+}
+
+// FreezeDepositAccountAttributes defines the attributes for freezing a deposit account.
+type FreezeDepositAccountAttributes struct {
+	FreezeReason      string `json:"freezeReason"` // FRAUD, BY_CUSTOMER or OTHER
+	FreezeDescription string `json:"freezeDescription"`
+}
+
+// --- Create Deposit Account ---
+
+// CreateDepositAccountRequest is the top-level request structure.
+type CreateDepositAccountRequest struct {
+	Data RequestData `json:"data"`
+}
+
+// DepositAccountAttributes defines the attributes for creating a new deposit account.
+type DepositAccountAttributes struct {
+	ProductName string `json:"productName"` // "SAVINGS" or "CURRENT"
+}
+
+// CustomerRelationshipData defines the structure for linking a resource to a customer.
+type CustomerRelationshipData struct {
+	Data struct {
+		ID   string `json:"id"`
+		Type string `json:"type"` // "IndividualCustomer" or "BusinessCustomer"
+	} `json:"data"`
+}
+
+// CreateDepositAccountResponse is the top-level response structure.
+type CreateDepositAccountResponse struct {
+	Data ResponseData `json:"data"`
+}
+
+// --- Get Account Numbers (Virtual NUBAN) ---
+
+// GetDepositAccountResponse captures the deposit account resource and any included
+// related resources (such as AccountNumber records) returned by Anchor.
+type GetDepositAccountResponse struct {
+	Data struct {
+		ID         string                 `json:"id"`
+		Type       string                 `json:"type"`
+		Attributes map[string]interface{} `json:"attributes"`
+	} `json:"data"`
+	Included []struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			AccountNumber string `json:"accountNumber"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+// VirtualNUBANInfo contains both the Virtual NUBAN and associated bank name.
+type VirtualNUBANInfo struct {
+	AccountNumber string `json:"accountNumber"`
+	BankName      string `json:"bankName"`
+}
+
+// AccountDetails is the subset of an Anchor deposit account shown to users.
+type AccountDetails struct {
+	AnchorAccountID string
+	AccountNumber   string
+	AccountName     string
+	BankName        string
+}
+
+// --- Bank & Counterparty Management ---
+
+// Bank represents a single bank returned from the Anchor API.
+type Bank struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name    string `json:"name"`
+		NipCode string `json:"nipCode"`
+	} `json:"attributes"`
+}
+
+// ListBanksResponse is the response structure for the list banks endpoint.
+type ListBanksResponse struct {
+	Data []Bank `json:"data"`
+}
+
+// VerifyAccountResponse is the response from Anchor's account verification endpoint.
+type VerifyAccountResponse struct {
+	Data struct {
+		Attributes struct {
+			AccountName string `json:"accountName"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// CreateCounterPartyRequest defines the payload for creating a new CounterParty.
+type CreateCounterPartyRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			BankCode      string `json:"bankCode"`
+			AccountNumber string `json:"accountNumber"`
+			AccountName   string `json:"accountName"`
+			VerifyName    bool   `json:"verifyName"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// CreateCounterPartyResponse is the response from Anchor after creating a CounterParty.
+type CreateCounterPartyResponse struct {
+	Data struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"data"`
+}
+
+// GetCounterPartyResponse is the response from Anchor when fetching a CounterParty.
+type GetCounterPartyResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			AccountName   string `json:"accountName"`
+			AccountNumber string `json:"accountNumber"`
+			Bank          struct {
+				ID      string `json:"id"`
+				Name    string `json:"name"`
+				NipCode string `json:"nipCode"`
+			} `json:"bank"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// CreateDepositAccount creates a new deposit account on Anchor.
+func (c *Client) CreateDepositAccount(ctx context.Context, req CreateDepositAccountRequest) (*CreateDepositAccountResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts", c.BaseURL)
+	var resp CreateDepositAccountResponse
+
+	err := c.do(ctx, http.MethodPost, url, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAccount fetches a deposit account with its account number and returns the
+// details Transfa displays: NUBAN, bank name and the account name registered with Anchor.
+func (c *Client) GetAccount(ctx context.Context, depositAccountID string) (*AccountDetails, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts/%s?include=AccountNumber", c.BaseURL, depositAccountID)
+	var resp GetDepositAccountResponse
+
+	err := c.do(ctx, http.MethodGet, url, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &AccountDetails{AnchorAccountID: resp.Data.ID}
+	if details.AnchorAccountID == "" {
+		details.AnchorAccountID = depositAccountID
+	}
+
+	// Extract bank name from main account data
+	if bankData, exists := resp.Data.Attributes["bank"]; exists {
+		if bankMap, ok := bankData.(map[string]interface{}); ok {
+			if name, exists := bankMap["name"]; exists {
+				if nameStr, ok := name.(string); ok {
+					details.BankName = nameStr
+				}
+			}
+		}
+	}
+
+	for _, key := range []string{"accountName", "name"} {
+		if name, ok := resp.Data.Attributes[key].(string); ok && strings.TrimSpace(name) != "" {
+			details.AccountName = strings.TrimSpace(name)
+			break
+		}
+	}
+
+	// Extract Virtual NUBAN from included section
+	for _, included := range resp.Included {
+		if strings.EqualFold(included.Type, "AccountNumber") && included.Attributes.AccountNumber != "" {
+			details.AccountNumber = included.Attributes.AccountNumber
+			break
+		}
+	}
+
+	return details, nil
+}
+
+// GetVirtualNUBANForAccount fetches the virtual account number (NUBAN) and bank name for a given deposit account ID.
+func (c *Client) GetVirtualNUBANForAccount(ctx context.Context, depositAccountID string) (*VirtualNUBANInfo, error) {
+	details, err := c.GetAccount(ctx, depositAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if details.AccountNumber == "" {
+		return nil, fmt.Errorf("no virtual account number found for deposit account %s", depositAccountID)
+	}
+
+	return &VirtualNUBANInfo{
+		AccountNumber: details.AccountNumber,
+		BankName:      details.BankName,
+	}, nil
+}
+
+// FreezeDepositAccount freezes a deposit account on Anchor. Anchor has no endpoint for
+// closing a deposit account, so frozen is the terminal state for closed users.
+func (c *Client) FreezeDepositAccount(ctx context.Context, depositAccountID, reason, description string) error {
+	url := fmt.Sprintf("%s/api/v1/accounts/%s/freeze", c.BaseURL, depositAccountID)
+	req := FreezeDepositAccountRequest{
+		Data: RequestData{
+			Type: "DepositAccount",
+			Attributes: FreezeDepositAccountAttributes{
+				FreezeReason:      reason,
+				FreezeDescription: description,
+			},
+		},
+	}
+	return c.do(ctx, http.MethodPost, url, req, nil)
+}
+
+// VerifyBankAccount verifies the details of an external bank account.
+func (c *Client) VerifyBankAccount(ctx context.Context, bankCode, accountNumber string) (*VerifyAccountResponse, error) {
+	var resp VerifyAccountResponse
+	url := fmt.Sprintf("%s/api/v1/payments/verify-account/%s/%s", c.BaseURL, bankCode, accountNumber)
+	err := c.do(ctx, http.MethodGet, url, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateCounterParty creates a new counterparty (beneficiary) on Anchor.
+func (c *Client) CreateCounterParty(ctx context.Context, req CreateCounterPartyRequest) (*CreateCounterPartyResponse, error) {
+	var resp CreateCounterPartyResponse
+	url := fmt.Sprintf("%s/api/v1/counterparties", c.BaseURL)
+	err := c.do(ctx, http.MethodPost, url, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCounterParty fetches a counterparty, including the full account number and bank
+// code needed to re-verify the account.
+func (c *Client) GetCounterParty(ctx context.Context, counterpartyID string) (*GetCounterPartyResponse, error) {
+	var resp GetCounterPartyResponse
+	url := fmt.Sprintf("%s/api/v1/counterparties/%s", c.BaseURL, counterpartyID)
+	err := c.do(ctx, http.MethodGet, url, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteCounterParty deletes a counterparty from Anchor.
+func (c *Client) DeleteCounterParty(ctx context.Context, counterpartyID string) error {
+	url := fmt.Sprintf("%s/api/v1/counterparties/%s", c.BaseURL, counterpartyID)
+	return c.do(ctx, http.MethodDelete, url, nil, nil)
+}
+
+// ListBanks fetches the list of supported banks from Anchor.
+func (c *Client) ListBanks(ctx context.Context) (*ListBanksResponse, error) {
+	var resp ListBanksResponse
+	url := fmt.Sprintf("%s/api/v1/banks", c.BaseURL)
+	err := c.do(ctx, http.MethodGet, url, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,131 @@
+package anchorclient
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBalanceCacheSize bounds how many account balances CachingAnchorClient keeps.
+	DefaultBalanceCacheSize = 500
+	// DefaultBalanceCacheTTL is how long a cached balance is served before Anchor is asked again.
+	DefaultBalanceCacheTTL = 5 * time.Second
+)
+
+// CachingAnchorClient wraps Client with a small in-process LRU cache of account
+// balances, so one request that reads a balance several times calls Anchor once.
+// Transfers through the wrapper drop the cached balance of every account they touch.
+type CachingAnchorClient struct {
+	*Client
+
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	// epoch increases on every invalidation, so a fetch that raced a transfer does not
+	// cache the balance from before it.
+	epoch uint64
+}
+
+type balanceCacheEntry struct {
+	accountID string
+	balance   BalanceResponse
+	expiresAt time.Time
+}
+
+// NewCachingAnchorClient wraps client. Non-positive maxEntries or ttl fall back to
+// DefaultBalanceCacheSize and DefaultBalanceCacheTTL.
+func NewCachingAnchorClient(client *Client, maxEntries int, ttl time.Duration) *CachingAnchorClient {
+	if maxEntries <= 0 {
+		maxEntries = DefaultBalanceCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultBalanceCacheTTL
+	}
+	return &CachingAnchorClient{
+		Client:     client,
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// GetAccountBalance returns the cached balance for accountID while it is fresh and
+// fetches it from Anchor otherwise. Errors are never cached.
+func (c *CachingAnchorClient) GetAccountBalance(ctx context.Context, accountID string) (*BalanceResponse, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[accountID]; ok {
+		entry := element.Value.(*balanceCacheEntry)
+		if c.now().Before(entry.expiresAt) {
+			c.order.MoveToFront(element)
+			balance := entry.balance
+			c.mu.Unlock()
+			return &balance, nil
+		}
+		c.removeLocked(element)
+	}
+	epoch := c.epoch
+	c.mu.Unlock()
+
+	balance, err := c.Client.GetAccountBalance(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.epoch == epoch {
+		c.storeLocked(accountID, *balance)
+	}
+	return balance, nil
+}
+
+// InitiateBookTransfer performs the transfer and drops the cached balances of both accounts.
+func (c *CachingAnchorClient) InitiateBookTransfer(ctx context.Context, sourceAccountID, destAccountID, reason string, amount int64, opts ...TransferOption) (*TransferResponse, error) {
+	defer c.InvalidateBalance(sourceAccountID, destAccountID)
+	return c.Client.InitiateBookTransfer(ctx, sourceAccountID, destAccountID, reason, amount, opts...)
+}
+
+// InitiateNIPTransfer performs the transfer and drops the cached balance of the source account.
+func (c *CachingAnchorClient) InitiateNIPTransfer(ctx context.Context, sourceAccountID, counterPartyID, reason string, amount int64, opts ...TransferOption) (*TransferResponse, error) {
+	defer c.InvalidateBalance(sourceAccountID)
+	return c.Client.InitiateNIPTransfer(ctx, sourceAccountID, counterPartyID, reason, amount, opts...)
+}
+
+// InvalidateBalance drops the cached balances of accountIDs.
+func (c *CachingAnchorClient) InvalidateBalance(accountIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	for _, accountID := range accountIDs {
+		if element, ok := c.entries[accountID]; ok {
+			c.removeLocked(element)
+		}
+	}
+}
+
+func (c *CachingAnchorClient) storeLocked(accountID string, balance BalanceResponse) {
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[accountID]; ok {
+		entry := element.Value.(*balanceCacheEntry)
+		entry.balance, entry.expiresAt = balance, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[accountID] = c.order.PushFront(&balanceCacheEntry{accountID: accountID, balance: balance, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+func (c *CachingAnchorClient) removeLocked(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*balanceCacheEntry).accountID)
+}
@@ -0,0 +1,138 @@
+package anchorclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBalanceServer answers balance requests with an available balance that grows by
+// one on every call, so a test can tell a cached response from a fresh one.
+func newBalanceServer(t *testing.T, balanceCalls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/api/v1/accounts/balance/") {
+			calls := atomic.AddInt32(balanceCalls, 1)
+			_, _ = fmt.Fprintf(w, `{"data":{"availableBalance":%d}}`, calls)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"id":"tr-1","type":"BookTransfer","attributes":{"status":"PENDING"}}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCachingAnchorClient_ServesRepeatedBalanceFromCache(t *testing.T) {
+	var calls int32
+	client := NewCachingAnchorClient(NewClient(newBalanceServer(t, &calls).URL, "test-key"), 0, 0)
+	ctx := context.Background()
+
+	first, err := client.GetAccountBalance(ctx, "acct-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := client.GetAccountBalance(ctx, "acct-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || second.Data.AvailableBalance != first.Data.AvailableBalance {
+		t.Fatalf("expected the second read to hit the cache, got %d Anchor calls", calls)
+	}
+
+	if _, err := client.GetAccountBalance(ctx, "acct-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected balances to be cached per account, got %d Anchor calls", calls)
+	}
+}
+
+func TestCachingAnchorClient_TransfersInvalidateTouchedAccounts(t *testing.T) {
+	var calls int32
+	client := NewCachingAnchorClient(NewClient(newBalanceServer(t, &calls).URL, "test-key"), 0, 0)
+	ctx := context.Background()
+
+	for _, accountID := range []string{"acct-1", "acct-2", "acct-3"} {
+		if _, err := client.GetAccountBalance(ctx, accountID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := client.InitiateBookTransfer(ctx, "acct-1", "acct-2", "test", 100); err != nil {
+		t.Fatalf("unexpected book transfer error: %v", err)
+	}
+	for _, accountID := range []string{"acct-1", "acct-2", "acct-3"} {
+		if _, err := client.GetAccountBalance(ctx, accountID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 5 {
+		t.Fatalf("expected only the book transfer's accounts to be refetched, got %d Anchor calls", calls)
+	}
+
+	if _, err := client.InitiateNIPTransfer(ctx, "acct-3", "cp-1", "test", 100); err != nil {
+		t.Fatalf("unexpected NIP transfer error: %v", err)
+	}
+	balance, err := client.GetAccountBalance(ctx, "acct-3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 6 || balance.Data.AvailableBalance != 6 {
+		t.Fatalf("expected the NIP source balance to be refetched, got %d Anchor calls", calls)
+	}
+}
+
+func TestCachingAnchorClient_ExpiresAfterTTL(t *testing.T) {
+	var calls int32
+	client := NewCachingAnchorClient(NewClient(newBalanceServer(t, &calls).URL, "test-key"), 0, 0)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := client.GetAccountBalance(ctx, "acct-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(DefaultBalanceCacheTTL - time.Millisecond)
+	if _, err := client.GetAccountBalance(ctx, "acct-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a fresh entry to be served from cache, got %d Anchor calls", calls)
+	}
+
+	now = now.Add(time.Millisecond)
+	if _, err := client.GetAccountBalance(ctx, "acct-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected an expired entry to be refetched, got %d Anchor calls", calls)
+	}
+}
+
+func TestCachingAnchorClient_EvictsLeastRecentlyUsed(t *testing.T) {
+	var calls int32
+	client := NewCachingAnchorClient(NewClient(newBalanceServer(t, &calls).URL, "test-key"), 2, 0)
+	ctx := context.Background()
+
+	for _, accountID := range []string{"acct-1", "acct-2", "acct-1", "acct-3", "acct-1"} {
+		if _, err := client.GetAccountBalance(ctx, accountID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("expected the recently used account to survive eviction, got %d Anchor calls", calls)
+	}
+	if _, err := client.GetAccountBalance(ctx, "acct-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 4 {
+		t.Fatalf("expected the least recently used account to be evicted, got %d Anchor calls", calls)
+	}
+}
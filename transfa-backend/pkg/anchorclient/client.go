@@ -0,0 +1,117 @@
+/**
+ * @description
+ * This package provides the client for the Anchor BaaS API shared by the Transfa
+ * services. It covers the customer, deposit account, counterparty and transfer
+ * endpoints and carries the JSON:API request and response models for each.
+ *
+ * @dependencies
+ * - bytes, context, encoding/json, fmt, io, log, net/http, time: Standard Go libraries.
+ *
+ * @notes
+ * - Every call returns an *APIError for non-2xx responses so callers can branch on
+ *   the status and error code with errors.As instead of matching error strings.
+ */
+package anchorclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Client is a client for the Anchor API.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Anchor API client.
+func NewClient(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// --- Generic JSON:API Structures ---
+
+// RequestData is a generic container for a JSON:API request payload.
+type RequestData struct {
+	Type          string                 `json:"type"`
+	Attributes    interface{}            `json:"attributes"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+}
+
+// ResponseData is a generic container for a JSON:API response payload.
+type ResponseData struct {
+	ID            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	Attributes    interface{}            `json:"attributes"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+}
+
+// setHeaders adds the necessary authentication and content-type headers to the request.
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-anchor-key", c.APIKey)
+}
+
+// do is a helper function to make HTTP requests to the Anchor API. Any non-2xx status
+// is returned as an *APIError.
+func (c *Client) do(ctx context.Context, method, url string, body, target interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create http request: %w", err)
+	}
+	c.setHeaders(req)
+
+	log.Printf("Making Anchor API request: %s %s", method, url)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Anchor API returned non-success status code %d: %s", resp.StatusCode, string(respBody))
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if target != nil {
+		if err := json.Unmarshal(respBody, target); err != nil {
+			return fmt.Errorf("failed to unmarshal response body: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// handleErrorResponse reads the body of a failed API call and returns it as an *APIError.
+func (c *Client) handleErrorResponse(resp *http.Response) error {
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("Failed to read error response body: %v", err)
+	}
+	return newAPIError(resp.StatusCode, bodyBytes)
+}
@@ -1,20 +1,3 @@
-/**
- * @description
- * This package provides a client for interacting with the Anchor BaaS API.
- * It encapsulates the logic for making authenticated HTTP requests, handling
- * request/response bodies, and managing errors from the API.
- *
- * @dependencies
- * - bytes, context, encoding/json, fmt, io, log, net/http, time: Standard Go libraries.
- * - github.com/transfa/customer-service/internal/domain: For the Anchor API request/response structs.
- *
- * @notes
- * - The client is designed to be reusable and can be shared across different microservices
- *   that need to communicate with Anchor.
- * - It includes a default HTTP client with a timeout to prevent requests from hanging indefinitely.
- * - Error handling is designed to provide context, returning a formatted error string
- *   that includes the status code and response body for easier debugging.
- */
 package anchorclient
 
 import (
@@ -26,31 +9,84 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
-
-	"github.com/transfa/customer-service/internal/domain"
 )
 
-// Client is a client for interacting with the Anchor API.
-type Client struct {
-	BaseURL    string
-	APIKey     string
-	httpClient *http.Client
+// --- Create Individual Customer ---
+
+// CreateIndividualCustomerRequest is the top-level request structure for creating a customer.
+type CreateIndividualCustomerRequest struct {
+	Data RequestData `json:"data"`
 }
 
-// NewClient creates a new Anchor API client.
-func NewClient(baseURL, apiKey string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-	}
+// IndividualCustomerAttributes defines the attributes for creating a new individual customer.
+type IndividualCustomerAttributes struct {
+	FullName    FullName `json:"fullName"`
+	Address     Address  `json:"address"`
+	Email       string   `json:"email"`
+	PhoneNumber string   `json:"phoneNumber"`
+}
+
+// IndividualCustomerResponse is the top-level response structure after creating a customer.
+type IndividualCustomerResponse struct {
+	Data ResponseData `json:"data"`
+}
+
+// FullName represents a person's full name.
+type FullName struct {
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName,omitempty"`
+	MiddleName string `json:"middleName,omitempty"`
+	MaidenName string `json:"maidenName,omitempty"`
+}
+
+// Address represents a physical address.
+type Address struct {
+	AddressLine1 string `json:"addressLine_1"`
+	AddressLine2 string `json:"addressLine_2,omitempty"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	PostalCode   string `json:"postalCode,omitempty"`
+	Country      string `json:"country"` // e.g., "NG"
+}
+
+// --- Individual KYC Verification ---
+
+// IndividualKYCRequest is the top-level request for triggering KYC.
+type IndividualKYCRequest struct {
+	Data RequestData `json:"data"`
+}
+
+// IndividualKYCAttributes defines the attributes for the KYC request.
+type IndividualKYCAttributes struct {
+	Level  string     `json:"level"` // e.g., "TIER_1" or "TIER_2"
+	Level1 *KYCLevel1 `json:"level1,omitempty"`
+	Level2 *KYCLevel2 `json:"level2,omitempty"`
+	Level3 *KYCLevel3 `json:"level3,omitempty"`
+}
+
+// KYCLevel1 contains the specific details required for a Tier 1 KYC check.
+type KYCLevel1 struct {
+	BVN         string `json:"bvn"`
+	DateOfBirth string `json:"dateOfBirth"` // Format: "YYYY-MM-DD"
+	Gender      string `json:"gender"`      // e.g., "Male", "Female"
+}
+
+// KYCLevel2 mirrors the attributes for Tier 2 submissions.
+type KYCLevel2 struct {
+	BVN         string `json:"bvn"`
+	DateOfBirth string `json:"dateOfBirth"`
+	Gender      string `json:"gender"`
+}
+
+// KYCLevel3 holds the identity document submitted for Tier 3.
+type KYCLevel3 struct {
+	IDType     string `json:"idType"`
+	IDNumber   string `json:"idNumber"`
+	ExpiryDate string `json:"expiryDate"`
 }
 
 // CreateIndividualCustomer sends a request to Anchor to create a new individual customer.
-func (c *Client) CreateIndividualCustomer(ctx context.Context, req domain.AnchorCreateIndividualCustomerRequest) (*domain.AnchorIndividualCustomerResponse, error) {
+func (c *Client) CreateIndividualCustomer(ctx context.Context, req CreateIndividualCustomerRequest) (*IndividualCustomerResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/customers", c.BaseURL)
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -64,7 +100,7 @@ func (c *Client) CreateIndividualCustomer(ctx context.Context, req domain.Anchor
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to Anchor: %w", err)
 	}
@@ -75,7 +111,7 @@ func (c *Client) CreateIndividualCustomer(ctx context.Context, req domain.Anchor
 		return nil, c.handleErrorResponse(resp)
 	}
 
-	var customerResp domain.AnchorIndividualCustomerResponse
+	var customerResp IndividualCustomerResponse
 	if err := json.NewDecoder(resp.Body).Decode(&customerResp); err != nil {
 		return nil, fmt.Errorf("failed to decode successful response: %w", err)
 	}
@@ -84,7 +120,7 @@ func (c *Client) CreateIndividualCustomer(ctx context.Context, req domain.Anchor
 }
 
 // TriggerIndividualKYC sends a request to Anchor to trigger the KYC verification process for a customer.
-func (c *Client) TriggerIndividualKYC(ctx context.Context, customerID string, req domain.AnchorIndividualKYCRequest) error {
+func (c *Client) TriggerIndividualKYC(ctx context.Context, customerID string, req IndividualKYCRequest) error {
 	url := fmt.Sprintf("%s/api/v1/customers/%s/verification/individual", c.BaseURL, customerID)
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -98,7 +134,7 @@ func (c *Client) TriggerIndividualKYC(ctx context.Context, customerID string, re
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to send kyc request to Anchor: %w", err)
 	}
@@ -114,7 +150,7 @@ func (c *Client) TriggerIndividualKYC(ctx context.Context, customerID string, re
 
 // UpdateIndividualCustomer updates an existing individual customer profile on Anchor.
 // Anchor expects the same payload structure as create customer.
-func (c *Client) UpdateIndividualCustomer(ctx context.Context, customerID string, req domain.AnchorCreateIndividualCustomerRequest) error {
+func (c *Client) UpdateIndividualCustomer(ctx context.Context, customerID string, req CreateIndividualCustomerRequest) error {
 	url := fmt.Sprintf("%s/api/v1/customers/update/%s", c.BaseURL, customerID)
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -128,7 +164,7 @@ func (c *Client) UpdateIndividualCustomer(ctx context.Context, customerID string
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to send update request to Anchor: %w", err)
 	}
@@ -141,26 +177,54 @@ func (c *Client) UpdateIndividualCustomer(ctx context.Context, customerID string
 	return nil
 }
 
-// setHeaders adds the necessary authentication and content-type headers to the request.
-func (c *Client) setHeaders(req *http.Request) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("x-anchor-key", c.APIKey)
-}
+// DeleteCustomer deactivates a customer on Anchor. A customer that no longer exists
+// is treated as already deleted.
+func (c *Client) DeleteCustomer(ctx context.Context, customerID string) error {
+	url := fmt.Sprintf("%s/api/v1/customers/%s", c.BaseURL, customerID)
 
-// handleErrorResponse reads the body of a failed API call and returns a formatted error.
-func (c *Client) handleErrorResponse(resp *http.Response) error {
-	bodyBytes, err := io.ReadAll(resp.Body)
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
-		log.Printf("Failed to read error response body: %v", err)
-		return fmt.Errorf("anchor API error with status %d, but failed to read response body", resp.StatusCode)
+		return fmt.Errorf("failed to create delete http request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send delete request to Anchor: %w", err)
 	}
-	return fmt.Errorf("anchor API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return c.handleErrorResponse(resp)
+	}
+}
+
+// CustomerExistsError is returned by CreateIndividualCustomerWithIdempotency when Anchor
+// reports that the customer already exists. CustomerID is the existing customer's ID
+// when it could be read from the response, and empty otherwise.
+type CustomerExistsError struct {
+	CustomerID string
+	Err        *APIError
+}
+
+func (e *CustomerExistsError) Error() string {
+	if e.CustomerID != "" {
+		return fmt.Sprintf("anchor customer already exists (%s): %v", e.CustomerID, e.Err)
+	}
+	return fmt.Sprintf("anchor customer already exists: %v", e.Err)
+}
+
+func (e *CustomerExistsError) Unwrap() error {
+	return e.Err
 }
 
 // CreateIndividualCustomerWithIdempotency handles customer creation with proper idempotency.
-// If the customer already exists, it returns a special error that can be handled by the caller.
-func (c *Client) CreateIndividualCustomerWithIdempotency(ctx context.Context, req domain.AnchorCreateIndividualCustomerRequest) (*domain.AnchorIndividualCustomerResponse, error) {
+// If the customer already exists, it returns a *CustomerExistsError that can be handled by the caller.
+func (c *Client) CreateIndividualCustomerWithIdempotency(ctx context.Context, req CreateIndividualCustomerRequest) (*IndividualCustomerResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/customers", c.BaseURL)
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -174,7 +238,7 @@ func (c *Client) CreateIndividualCustomerWithIdempotency(ctx context.Context, re
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to Anchor: %w", err)
 	}
@@ -182,7 +246,7 @@ func (c *Client) CreateIndividualCustomerWithIdempotency(ctx context.Context, re
 
 	// Handle successful responses (both 200 OK and 201 Created are valid)
 	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
-		var customerResp domain.AnchorIndividualCustomerResponse
+		var customerResp IndividualCustomerResponse
 		if err := json.NewDecoder(resp.Body).Decode(&customerResp); err != nil {
 			return nil, fmt.Errorf("failed to decode successful response: %w", err)
 		}
@@ -193,20 +257,20 @@ func (c *Client) CreateIndividualCustomerWithIdempotency(ctx context.Context, re
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Printf("Failed to read error response body: %v", err)
-		return nil, fmt.Errorf("anchor API error with status %d, but failed to read response body", resp.StatusCode)
 	}
+	apiErr := newAPIError(resp.StatusCode, bodyBytes)
 
 	// Check if this is a "customer already exists" error
-	if resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(string(bodyBytes)), "already exist") {
-		// Try to extract customer ID from the error response
-		customerID := extractCustomerIDFromError(string(bodyBytes))
-		if customerID != "" {
-			return nil, fmt.Errorf("CUSTOMER_ALREADY_EXISTS_WITH_ID: %s|%s", customerID, string(bodyBytes))
+	if resp.StatusCode == http.StatusBadRequest && apiErr.Mentions("already exist") {
+		// Try to extract customer ID from the error detail, then the raw response
+		customerID := extractCustomerIDFromError(apiErr.Detail)
+		if customerID == "" {
+			customerID = extractCustomerIDFromError(apiErr.Body)
 		}
-		return nil, fmt.Errorf("CUSTOMER_ALREADY_EXISTS: %s", string(bodyBytes))
+		return nil, &CustomerExistsError{CustomerID: customerID, Err: apiErr}
 	}
 
-	return nil, fmt.Errorf("anchor API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	return nil, apiErr
 }
 
 // extractCustomerIDFromError attempts to extract a customer ID from Anchor error responses.
@@ -0,0 +1,97 @@
+package anchorclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by every Client method when Anchor answers with a non-2xx
+// status. Code, Title and Detail come from the first entry of the JSON:API errors
+// array and are empty when the body was not a JSON:API error document.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Title      string
+	Detail     string
+	// Body is the raw response body, kept for logging.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.Title != "" && e.Detail != "":
+		return fmt.Sprintf("anchor API request failed with status %d: %s - %s", e.StatusCode, e.Title, e.Detail)
+	case e.Title != "" || e.Detail != "":
+		return fmt.Sprintf("anchor API request failed with status %d: %s%s", e.StatusCode, e.Title, e.Detail)
+	default:
+		return fmt.Sprintf("anchor API request failed with status %d: %s", e.StatusCode, e.Body)
+	}
+}
+
+// IsRateLimited reports whether Anchor throttled the request.
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsValidation reports whether Anchor rejected the request payload.
+func (e *APIError) IsValidation() bool {
+	return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// IsNotFound reports whether the requested resource does not exist on Anchor.
+func (e *APIError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether the request conflicts with existing state on Anchor.
+func (e *APIError) IsConflict() bool {
+	return e.StatusCode == http.StatusConflict
+}
+
+// IsExplicitRejection returns true only for deterministic client-side rejections.
+// 5xx and throttling/timeouts are treated as ambiguous and should not be compensated
+// immediately, as are 4xx responses whose body is not a JSON:API error from Anchor.
+func (e *APIError) IsExplicitRejection() bool {
+	if e.StatusCode < 400 || e.StatusCode >= 500 {
+		return false
+	}
+	if e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests {
+		return false
+	}
+	return e.Code != "" || e.Title != "" || e.Detail != ""
+}
+
+// Mentions reports whether the error title, detail or body contains s, ignoring case.
+func (e *APIError) Mentions(s string) bool {
+	needle := strings.ToLower(s)
+	for _, text := range []string{e.Title, e.Detail, e.Body} {
+		if strings.Contains(strings.ToLower(text), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorDocument is the JSON:API error body Anchor returns.
+type errorDocument struct {
+	Errors []struct {
+		Code   string `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+		Status string `json:"status"`
+	} `json:"errors"`
+}
+
+// newAPIError builds an APIError from a non-2xx response status and body.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: string(body)}
+	var doc errorDocument
+	if err := json.Unmarshal(body, &doc); err == nil && len(doc.Errors) > 0 {
+		apiErr.Code = strings.TrimSpace(doc.Errors[0].Code)
+		apiErr.Title = strings.TrimSpace(doc.Errors[0].Title)
+		apiErr.Detail = strings.TrimSpace(doc.Errors[0].Detail)
+	}
+	return apiErr
+}
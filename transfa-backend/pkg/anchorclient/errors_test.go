@@ -0,0 +1,135 @@
+package anchorclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewAPIError_ParsesJSONAPIBody(t *testing.T) {
+	body := []byte(`{"errors":[{"code":"VALIDATION_ERROR","title":"Bad Request","detail":"phoneNumber is invalid","status":"400"}]}`)
+
+	apiErr := newAPIError(http.StatusBadRequest, body)
+
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "VALIDATION_ERROR" || apiErr.Title != "Bad Request" || apiErr.Detail != "phoneNumber is invalid" {
+		t.Fatalf("unexpected parsed error: %+v", apiErr)
+	}
+	if !apiErr.Mentions("PHONENUMBER") {
+		t.Fatalf("expected case-insensitive match on detail")
+	}
+}
+
+func TestAPIError_Classification(t *testing.T) {
+	jsonAPIBody := []byte(`{"errors":[{"title":"Error","detail":"rejected"}]}`)
+
+	cases := []struct {
+		name       string
+		status     int
+		body       []byte
+		rateLimit  bool
+		validation bool
+		conflict   bool
+		explicit   bool
+	}{
+		{name: "bad request", status: http.StatusBadRequest, body: jsonAPIBody, validation: true, explicit: true},
+		{name: "unprocessable", status: http.StatusUnprocessableEntity, body: jsonAPIBody, validation: true, explicit: true},
+		{name: "conflict", status: http.StatusConflict, body: jsonAPIBody, conflict: true, explicit: true},
+		{name: "not found", status: http.StatusNotFound, body: jsonAPIBody, explicit: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: jsonAPIBody, rateLimit: true},
+		{name: "request timeout", status: http.StatusRequestTimeout, body: jsonAPIBody},
+		{name: "server error", status: http.StatusInternalServerError, body: jsonAPIBody},
+		{name: "bad request without JSON:API body", status: http.StatusBadRequest, body: []byte(`<html>bad gateway</html>`), validation: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := newAPIError(tc.status, tc.body)
+			if got := apiErr.IsRateLimited(); got != tc.rateLimit {
+				t.Fatalf("IsRateLimited() = %v, want %v", got, tc.rateLimit)
+			}
+			if got := apiErr.IsValidation(); got != tc.validation {
+				t.Fatalf("IsValidation() = %v, want %v", got, tc.validation)
+			}
+			if got := apiErr.IsConflict(); got != tc.conflict {
+				t.Fatalf("IsConflict() = %v, want %v", got, tc.conflict)
+			}
+			if got := apiErr.IsExplicitRejection(); got != tc.explicit {
+				t.Fatalf("IsExplicitRejection() = %v, want %v", got, tc.explicit)
+			}
+		})
+	}
+}
+
+func TestClientMethods_ReturnAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"errors":[{"title":"Too Many Requests","detail":"slow down","status":"429"}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "test-key")
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"CreateIndividualCustomer": func() error {
+			_, err := client.CreateIndividualCustomer(ctx, CreateIndividualCustomerRequest{})
+			return err
+		},
+		"CreateIndividualCustomerWithIdempotency": func() error {
+			_, err := client.CreateIndividualCustomerWithIdempotency(ctx, CreateIndividualCustomerRequest{})
+			return err
+		},
+		"TriggerIndividualKYC": func() error {
+			return client.TriggerIndividualKYC(ctx, "cust-1", IndividualKYCRequest{})
+		},
+		"GetAccount": func() error {
+			_, err := client.GetAccount(ctx, "acct-1")
+			return err
+		},
+		"InitiateBookTransfer": func() error {
+			_, err := client.InitiateBookTransfer(ctx, "acct-1", "acct-2", "test", 100)
+			return err
+		},
+		"GetAccountBalance": func() error {
+			_, err := client.GetAccountBalance(ctx, "acct-1")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			var apiErr *APIError
+			if err := call(); !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T: %v", err, err)
+			}
+			if !apiErr.IsRateLimited() || apiErr.Detail != "slow down" {
+				t.Fatalf("unexpected error: %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestCreateIndividualCustomerWithIdempotency_CustomerExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"errors":[{"title":"Bad Request","detail":"Customer already exists: 17587033450610-anc_ind_cst","status":"400"}]}`)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-key").CreateIndividualCustomerWithIdempotency(context.Background(), CreateIndividualCustomerRequest{})
+
+	var existsErr *CustomerExistsError
+	if !errors.As(err, &existsErr) {
+		t.Fatalf("expected *CustomerExistsError, got %T: %v", err, err)
+	}
+	if existsErr.CustomerID != "17587033450610-anc_ind_cst" {
+		t.Fatalf("expected extracted customer ID, got %q", existsErr.CustomerID)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected wrapped *APIError with status 400, got %v", err)
+	}
+}
@@ -0,0 +1,369 @@
+package anchorclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BookTransferRequest represents the payload for an Anchor Book Transfer.
+type BookTransferRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Currency string `json:"currency"`
+			Amount   int64  `json:"amount"`
+			Reason   string `json:"reason"`
+		} `json:"attributes"`
+		Relationships struct {
+			Account struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			} `json:"account"`
+			DestinationAccount struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			} `json:"destinationAccount"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// NIPTransferRequest represents the payload for an Anchor NIP Transfer.
+type NIPTransferRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Currency string `json:"currency"`
+			Amount   int64  `json:"amount"`
+			Reason   string `json:"reason"`
+		} `json:"attributes"`
+		Relationships struct {
+			Account struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			} `json:"account"`
+			CounterParty struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			} `json:"counterParty"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// TransferResponse is the expected response from Anchor's transfer endpoints.
+type TransferResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			Status string `json:"status"`
+			Fee    int64  `json:"fee"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// transferRelationship is a JSON:API relationship pointing at another Anchor resource.
+type transferRelationship struct {
+	Data struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"data"`
+}
+
+// transferResource is a transfer as Anchor returns it, alone or in a list.
+type transferResource struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Status        string                 `json:"status"`
+		Amount        int64                  `json:"amount"`
+		Currency      string                 `json:"currency"`
+		Reason        string                 `json:"reason"`
+		Reference     string                 `json:"reference"`
+		FailureReason string                 `json:"failureReason"`
+		Metadata      map[string]interface{} `json:"metadata"`
+		CreatedAt     string                 `json:"createdAt"`
+	} `json:"attributes"`
+	Relationships struct {
+		Account            transferRelationship `json:"account"`
+		DestinationAccount transferRelationship `json:"destinationAccount"`
+		CounterParty       transferRelationship `json:"counterParty"`
+	} `json:"relationships"`
+}
+
+// GetTransferResponse is Anchor's response for a single transfer.
+type GetTransferResponse struct {
+	Data transferResource `json:"data"`
+}
+
+// ListTransfersResponse is Anchor's response for a page of transfers.
+type ListTransfersResponse struct {
+	Data []transferResource `json:"data"`
+	Meta struct {
+		Pagination struct {
+			Page       int `json:"page"`
+			Size       int `json:"size"`
+			Total      int `json:"total"`
+			TotalPages int `json:"totalPages"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// TransferDetails is the subset of an Anchor transfer used to match transfer events
+// back to Transfa transactions. Reason is the description sent when the transfer was
+// initiated, which carries Transfa's matching tokens.
+type TransferDetails struct {
+	ID                   string
+	Type                 string
+	Status               string
+	Amount               int64
+	Currency             string
+	Reason               string
+	Reference            string
+	FailureReason        string
+	Metadata             map[string]interface{}
+	AccountID            string
+	DestinationAccountID string
+	CounterPartyID       string
+	CreatedAt            time.Time
+}
+
+// TransferPage is one page of an account's transfers. Page is zero-based; TotalPages
+// is what Anchor reported and may be zero when it sent no pagination metadata.
+type TransferPage struct {
+	Transfers  []TransferDetails
+	Page       int
+	TotalPages int
+}
+
+// BalanceResponse represents the balance response from Anchor API.
+type BalanceResponse struct {
+	Data struct {
+		AvailableBalance int64 `json:"availableBalance"`
+		LedgerBalance    int64 `json:"ledgerBalance"`
+		Hold             int64 `json:"hold"`
+		Pending          int64 `json:"pending"`
+	} `json:"data"`
+}
+
+// IdempotencyKeyHeader is the header Anchor reads idempotency keys from. Anchor saves
+// the result of a successful request and returns it for any later request carrying
+// the same key, for up to 48 hours.
+const IdempotencyKeyHeader = "x-anchor-idempotent-key"
+
+// TransferOption configures a single transfer request.
+type TransferOption func(*transferOptions)
+
+type transferOptions struct {
+	idempotencyKey string
+}
+
+// WithIdempotencyKey sends key as the transfer's idempotency key, so a retry with the
+// same key cannot create a second transfer. Keys are scoped to the transfers endpoint,
+// so book and NIP transfers must not share one. An empty key is ignored.
+func WithIdempotencyKey(key string) TransferOption {
+	return func(o *transferOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// InitiateBookTransfer sends a request to Anchor to perform a book transfer.
+func (c *Client) InitiateBookTransfer(ctx context.Context, sourceAccountID, destAccountID, reason string, amount int64, opts ...TransferOption) (*TransferResponse, error) {
+	reqPayload := BookTransferRequest{}
+	reqPayload.Data.Type = "BookTransfer"
+	reqPayload.Data.Attributes.Currency = "NGN"
+	reqPayload.Data.Attributes.Amount = amount
+	reqPayload.Data.Attributes.Reason = reason
+	reqPayload.Data.Relationships.Account.Data.Type = "DepositAccount"
+	reqPayload.Data.Relationships.Account.Data.ID = sourceAccountID
+	reqPayload.Data.Relationships.DestinationAccount.Data.Type = "DepositAccount"
+	reqPayload.Data.Relationships.DestinationAccount.Data.ID = destAccountID
+
+	return c.doTransfer(ctx, reqPayload, opts)
+}
+
+// InitiateNIPTransfer sends a request to Anchor to perform an external NIP transfer.
+func (c *Client) InitiateNIPTransfer(ctx context.Context, sourceAccountID, counterPartyID, reason string, amount int64, opts ...TransferOption) (*TransferResponse, error) {
+	reqPayload := NIPTransferRequest{}
+	reqPayload.Data.Type = "NIPTransfer"
+	reqPayload.Data.Attributes.Currency = "NGN"
+	reqPayload.Data.Attributes.Amount = amount
+	reqPayload.Data.Attributes.Reason = reason
+	reqPayload.Data.Relationships.Account.Data.Type = "DepositAccount"
+	reqPayload.Data.Relationships.Account.Data.ID = sourceAccountID
+	reqPayload.Data.Relationships.CounterParty.Data.Type = "CounterParty"
+	reqPayload.Data.Relationships.CounterParty.Data.ID = counterPartyID
+
+	return c.doTransfer(ctx, reqPayload, opts)
+}
+
+// doTransfer is a generic helper function to execute transfer requests.
+func (c *Client) doTransfer(ctx context.Context, payload interface{}, opts []TransferOption) (*TransferResponse, error) {
+	var options transferOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transfer request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/api/v1/transfers", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer request: %w", err)
+	}
+
+	c.setHeaders(req)
+	if options.idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, options.idempotencyKey)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transfer request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transfer response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(resp.StatusCode, bodyBytes)
+		log.Printf("level=warn component=anchor_client op=transfer status=%d code=%q title=%q detail=%q", resp.StatusCode, apiErr.Code, apiErr.Title, apiErr.Detail)
+		return nil, apiErr
+	}
+
+	var successResp TransferResponse
+	if err := json.Unmarshal(bodyBytes, &successResp); err != nil {
+		return nil, fmt.Errorf("failed to decode success response: %w", err)
+	}
+
+	return &successResp, nil
+}
+
+// GetAccountBalance fetches the balance for a specific account from Anchor API.
+func (c *Client) GetAccountBalance(ctx context.Context, accountID string) (*BalanceResponse, error) {
+	url := c.BaseURL + "/api/v1/accounts/balance/" + accountID
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create balance request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-anchor-key", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute balance request: %w", err)
+	}
+	defer resp.Body.Close()
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balance response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(resp.StatusCode, bodyBytes)
+		log.Printf("level=warn component=anchor_client op=get_balance account_id=%s status=%d code=%q title=%q detail=%q", accountID, resp.StatusCode, apiErr.Code, apiErr.Title, apiErr.Detail)
+		return nil, apiErr
+	}
+
+	var balanceResp BalanceResponse
+	if err := json.Unmarshal(bodyBytes, &balanceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode balance response: %w", err)
+	}
+
+	return &balanceResp, nil
+}
+
+// GetTransfer fetches a single transfer from Anchor.
+func (c *Client) GetTransfer(ctx context.Context, transferID string) (*TransferDetails, error) {
+	url := c.BaseURL + "/api/v1/transfers/" + transferID
+	var resp GetTransferResponse
+
+	if err := c.do(ctx, http.MethodGet, url, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	details := newTransferDetails(resp.Data)
+	if details.ID == "" {
+		details.ID = transferID
+	}
+	return &details, nil
+}
+
+// ListTransfers fetches one page of the transfers made from or to accountID between
+// from and to (inclusive dates, UTC). Pages are zero-based and hold at most size
+// transfers; callers page until a short page or the reported last page.
+func (c *Client) ListTransfers(ctx context.Context, accountID string, from, to time.Time, page, size int) (*TransferPage, error) {
+	query := url.Values{}
+	query.Set("accountId", accountID)
+	query.Set("from", from.UTC().Format(time.DateOnly))
+	query.Set("to", to.UTC().Format(time.DateOnly))
+	query.Set("page", strconv.Itoa(page))
+	query.Set("size", strconv.Itoa(size))
+
+	var resp ListTransfersResponse
+	if err := c.do(ctx, http.MethodGet, c.BaseURL+"/api/v1/transfers?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &TransferPage{
+		Transfers:  make([]TransferDetails, 0, len(resp.Data)),
+		Page:       page,
+		TotalPages: resp.Meta.Pagination.TotalPages,
+	}
+	for _, transfer := range resp.Data {
+		result.Transfers = append(result.Transfers, newTransferDetails(transfer))
+	}
+	return result, nil
+}
+
+func newTransferDetails(transfer transferResource) TransferDetails {
+	attrs := transfer.Attributes
+	return TransferDetails{
+		ID:                   transfer.ID,
+		Type:                 transfer.Type,
+		Status:               attrs.Status,
+		Amount:               attrs.Amount,
+		Currency:             attrs.Currency,
+		Reason:               attrs.Reason,
+		Reference:            attrs.Reference,
+		FailureReason:        attrs.FailureReason,
+		Metadata:             attrs.Metadata,
+		AccountID:            transfer.Relationships.Account.Data.ID,
+		DestinationAccountID: transfer.Relationships.DestinationAccount.Data.ID,
+		CounterPartyID:       transfer.Relationships.CounterParty.Data.ID,
+		CreatedAt:            parseTransferTime(attrs.CreatedAt),
+	}
+}
+
+// parseTransferTime reads Anchor's createdAt, which is not always sent with a zone
+// offset; zoneless times are UTC. It returns the zero time for anything else.
+func parseTransferTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,145 @@
+package anchorclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInitiateTransfer_SendsIdempotencyKey(t *testing.T) {
+	var gotKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKeys = append(gotKeys, r.Header.Get(IdempotencyKeyHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":{"id":"tr-1","type":"BookTransfer","attributes":{"status":"PENDING"}}}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "test-key")
+	ctx := context.Background()
+
+	if _, err := client.InitiateBookTransfer(ctx, "acct-1", "acct-2", "test", 100, WithIdempotencyKey("book-key")); err != nil {
+		t.Fatalf("unexpected book transfer error: %v", err)
+	}
+	if _, err := client.InitiateNIPTransfer(ctx, "acct-1", "cp-1", "test", 100, WithIdempotencyKey("nip-key")); err != nil {
+		t.Fatalf("unexpected NIP transfer error: %v", err)
+	}
+	if _, err := client.InitiateBookTransfer(ctx, "acct-1", "acct-2", "test", 100); err != nil {
+		t.Fatalf("unexpected book transfer error: %v", err)
+	}
+
+	want := []string{"book-key", "nip-key", ""}
+	if len(gotKeys) != len(want) {
+		t.Fatalf("expected %d requests, got %d", len(want), len(gotKeys))
+	}
+	for i := range want {
+		if gotKeys[i] != want[i] {
+			t.Fatalf("request %d: expected idempotency key %q, got %q", i, want[i], gotKeys[i])
+		}
+	}
+}
+
+func TestGetTransfer_ReturnsDescriptionAndParticipants(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":{"id":"tr-9","type":"NIPTransfer","attributes":{"status":"COMPLETED","amount":25000,"currency":"NGN","reason":"Money drop claim [md-claim:abc]","reference":"ref-9","metadata":{"source":"transfa"}},"relationships":{"account":{"data":{"id":"acct-1","type":"DepositAccount"}},"counterParty":{"data":{"id":"cp-1","type":"CounterParty"}}}}}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "test-key")
+
+	transfer, err := client.GetTransfer(context.Background(), "tr-9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/api/v1/transfers/tr-9" {
+		t.Fatalf("unexpected request path %q", gotPath)
+	}
+	if transfer.Reason != "Money drop claim [md-claim:abc]" || transfer.Amount != 25000 || transfer.Status != "COMPLETED" {
+		t.Fatalf("unexpected transfer attributes: %+v", transfer)
+	}
+	if transfer.AccountID != "acct-1" || transfer.CounterPartyID != "cp-1" || transfer.DestinationAccountID != "" {
+		t.Fatalf("unexpected transfer participants: %+v", transfer)
+	}
+	if transfer.Metadata["source"] != "transfa" {
+		t.Fatalf("expected metadata to be returned, got %v", transfer.Metadata)
+	}
+}
+
+func TestGetTransfer_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"errors":[{"status":"404","title":"Not Found","detail":"Transfer not found"}]}`)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-key").GetTransfer(context.Background(), "missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestListTransfers_SendsFiltersAndReadsPage(t *testing.T) {
+	var gotQuery map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/transfers" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+		}
+		gotQuery = map[string]string{}
+		for key := range r.URL.Query() {
+			gotQuery[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[`+
+			`{"id":"tr-1","type":"BookTransfer","attributes":{"status":"COMPLETED","amount":5000,"createdAt":"2026-10-14T09:30:00Z"},"relationships":{"account":{"data":{"id":"acct-1","type":"DepositAccount"}},"destinationAccount":{"data":{"id":"acct-2","type":"DepositAccount"}}}},`+
+			`{"id":"tr-2","type":"NIPTransfer","attributes":{"status":"FAILED","amount":1200,"createdAt":"2026-10-14T18:05:00"}}`+
+			`],"meta":{"pagination":{"page":1,"size":2,"total":5,"totalPages":3}}}`)
+	}))
+	defer server.Close()
+
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	page, err := NewClient(server.URL, "test-key").ListTransfers(context.Background(), "acct-1", day, day, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantQuery := map[string]string{"accountId": "acct-1", "from": "2026-10-14", "to": "2026-10-14", "page": "1", "size": "2"}
+	for key, want := range wantQuery {
+		if gotQuery[key] != want {
+			t.Fatalf("expected query %s=%q, got %q", key, want, gotQuery[key])
+		}
+	}
+	if page.Page != 1 || page.TotalPages != 3 || len(page.Transfers) != 2 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	first := page.Transfers[0]
+	if first.ID != "tr-1" || first.Amount != 5000 || first.AccountID != "acct-1" || first.DestinationAccountID != "acct-2" {
+		t.Fatalf("unexpected first transfer: %+v", first)
+	}
+	if !first.CreatedAt.Equal(time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first transfer time %s", first.CreatedAt)
+	}
+	if !page.Transfers[1].CreatedAt.Equal(time.Date(2026, 10, 14, 18, 5, 0, 0, time.UTC)) {
+		t.Fatalf("expected a zoneless time to be read as UTC, got %s", page.Transfers[1].CreatedAt)
+	}
+}
+
+func TestListTransfers_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"errors":[{"status":"429","title":"Too Many Requests"}]}`)
+	}))
+	defer server.Close()
+
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	_, err := NewClient(server.URL, "test-key").ListTransfers(context.Background(), "acct-1", day, day, 0, 100)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsRateLimited() {
+		t.Fatalf("expected a rate limited error, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+/**
+ * @description
+ * This package writes the JSON error envelope shared by the Transfa HTTP services:
+ *
+ *   {"error": {"code": "insufficient_funds", "message": "Insufficient funds", "details": [...]}}
+ *
+ * Code is a stable, machine-readable identifier clients can branch on; message is
+ * for display and may change. Details is only present on validation errors and lists
+ * the offending request fields.
+ *
+ * @dependencies
+ * - encoding/json, net/http: Standard Go libraries.
+ *
+ * @notes
+ * - Every code a service may return is listed in Codes so the frontend can generate
+ *   its error types from a single place. Add new codes there.
+ */
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Generic codes, used when a response has no more specific code.
+const (
+	CodeBadRequest         = "bad_request"
+	CodeUnauthorized       = "unauthorized"
+	CodePaymentRequired    = "payment_required"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeConflict           = "conflict"
+	CodeGone               = "gone"
+	CodePreconditionFailed = "precondition_failed"
+	CodePayloadTooLarge    = "payload_too_large"
+	CodeValidationFailed   = "validation_failed"
+	CodeLocked             = "locked"
+	CodeRateLimited        = "rate_limited"
+	CodeInternal           = "internal_error"
+	CodeUpstreamFailed     = "upstream_error"
+	CodeUnavailable        = "service_unavailable"
+	CodeTimeout            = "timeout"
+)
+
+// Domain codes returned by the transaction and auth services.
+const (
+	CodeInsufficientFunds          = "insufficient_funds"
+	CodeTransferLimitExceeded      = "transfer_limit_exceeded"
+	CodePlatformFeeOverdue         = "platform_fee_overdue"
+	CodeSendingRestricted          = "sending_restricted"
+	CodeReceivingRestricted        = "receiving_restricted"
+	CodeRecipientNotFound          = "recipient_not_found"
+	CodeRecipientUsernameChanged   = "recipient_username_changed"
+	CodeSelfTransferNotAllowed     = "self_transfer_not_allowed"
+	CodeDuplicateRecipient         = "duplicate_recipient"
+	CodeUnsupportedCurrency        = "unsupported_currency"
+	CodeCurrencyMismatch           = "currency_mismatch"
+	CodeBeneficiaryNotFound        = "beneficiary_not_found"
+	CodeBeneficiaryAccountInvalid  = "beneficiary_account_invalid"
+	CodeAccountNotFound            = "account_not_found"
+	CodeUserNotFound               = "user_not_found"
+	CodeTransactionNotFound        = "transaction_not_found"
+	CodeTransactionNotDisputable   = "transaction_not_disputable"
+	CodeTransactionNotCancellable  = "transaction_not_cancellable"
+	CodeDisputeExists              = "dispute_exists"
+	CodeDisputeNotFound            = "dispute_not_found"
+	CodeInvalidDisputeTransition   = "invalid_dispute_transition"
+	CodeTransferListNotFound       = "transfer_list_not_found"
+	CodeFavoriteNotFound           = "favorite_not_found"
+	CodeFavoriteLimitReached       = "favorite_limit_reached"
+	CodeAttachmentLimitReached     = "attachment_limit_reached"
+	CodePotNotFound                = "pot_not_found"
+	CodePaymentRequestNotFound     = "payment_request_not_found"
+	CodePaymentRequestNotPending   = "payment_request_not_pending"
+	CodeMoneyDropNotFound          = "money_drop_not_found"
+	CodeMoneyDropPasswordRequired  = "money_drop_password_required"
+	CodeMoneyDropPasswordMismatch  = "money_drop_password_mismatch"
+	CodeMoneyDropPasswordLocked    = "money_drop_password_locked"
+	CodeMoneyDropEndNotAllowed     = "money_drop_end_not_allowed"
+	CodeMoneyDropShareNotAllowed   = "money_drop_share_not_allowed"
+	CodeMoneyDropNotLocked         = "money_drop_not_locked"
+	CodeShortLinkExpired           = "short_link_expired"
+	CodeInvalidIdempotencyKey      = "invalid_idempotency_key"
+	CodeIdempotencyConflict        = "idempotency_conflict"
+	CodeIdempotencyInProgress      = "idempotency_in_progress"
+	CodeOperationInProgress        = "operation_in_progress"
+	CodeTransactionPINAlreadySet   = "transaction_pin_already_set"
+	CodeTransactionPINNotSet       = "transaction_pin_not_set"
+	CodeTransactionPINInvalid      = "transaction_pin_invalid"
+	CodeTransactionPINLocked       = "transaction_pin_locked"
+	CodeTransactionPINUnchanged    = "transaction_pin_unchanged"
+	CodeUsernameTaken              = "username_taken"
+	CodeUsernameAlreadySet         = "username_already_set"
+	CodeUsernameNotSet             = "username_not_set"
+	CodeUsernameUnchanged          = "username_unchanged"
+	CodeUsernameChangeCooldown     = "username_change_cooldown"
+	CodeAccountClosed              = "account_closed"
+	CodeAccountClosureBlocked      = "account_closure_blocked"
+	CodeUnmatchedEventReplayed     = "unmatched_event_already_replayed"
+	CodeUnmatchedEventStillPending = "unmatched_event_still_unmatched"
+)
+
+// Codes documents every error code, with the situation it describes. It is the source
+// the frontend generates its error code union from.
+var Codes = map[string]string{
+	CodeBadRequest:         "The request could not be parsed.",
+	CodeUnauthorized:       "Authentication is missing or invalid.",
+	CodePaymentRequired:    "The request needs a payment the user cannot make.",
+	CodeForbidden:          "The user may not perform this action.",
+	CodeNotFound:           "The requested resource does not exist.",
+	CodeMethodNotAllowed:   "The HTTP method is not supported on this route.",
+	CodeConflict:           "The request conflicts with the resource's current state.",
+	CodeGone:               "The resource no longer exists.",
+	CodePreconditionFailed: "A required earlier step has not been completed.",
+	CodePayloadTooLarge:    "The request body is too large.",
+	CodeValidationFailed:   "One or more request fields are invalid; see details.",
+	CodeLocked:             "The resource is temporarily locked.",
+	CodeRateLimited:        "Too many requests; retry after the Retry-After header.",
+	CodeInternal:           "An unexpected server error occurred.",
+	CodeUpstreamFailed:     "A downstream provider returned an error.",
+	CodeUnavailable:        "The service or one of its dependencies is unavailable.",
+	CodeTimeout:            "A downstream provider did not respond in time.",
+
+	CodeInsufficientFunds:          "The wallet balance does not cover the amount and fees.",
+	CodeTransferLimitExceeded:      "The transfer exceeds a per-request or per-period limit.",
+	CodePlatformFeeOverdue:         "The user has an overdue platform fee and cannot send money.",
+	CodeSendingRestricted:          "The sender's account is restricted from sending money.",
+	CodeReceivingRestricted:        "The recipient's account is restricted from receiving money.",
+	CodeRecipientNotFound:          "No user has the recipient username.",
+	CodeRecipientUsernameChanged:   "The recipient changed their username; retry with new_username.",
+	CodeSelfTransferNotAllowed:     "The user tried to send money to themselves.",
+	CodeDuplicateRecipient:         "A recipient appears more than once in the request.",
+	CodeUnsupportedCurrency:        "The currency is not supported.",
+	CodeCurrencyMismatch:           "The event currency does not match the transaction currency.",
+	CodeBeneficiaryNotFound:        "The beneficiary does not exist or belongs to another user.",
+	CodeBeneficiaryAccountInvalid:  "The beneficiary's bank account no longer resolves; the user must update it.",
+	CodeAccountNotFound:            "The user has no wallet account yet.",
+	CodeUserNotFound:               "The authenticated user has no Transfa profile.",
+	CodeTransactionNotFound:        "The transaction does not exist or is not visible to the user.",
+	CodeTransactionNotDisputable:   "Only completed or failed transactions can be disputed.",
+	CodeTransactionNotCancellable:  "Only a pending transfer that has not been sent yet can be cancelled.",
+	CodeDisputeExists:              "The transaction already has an open dispute.",
+	CodeDisputeNotFound:            "The dispute does not exist.",
+	CodeInvalidDisputeTransition:   "The dispute cannot move to that status.",
+	CodeTransferListNotFound:       "The transfer list does not exist.",
+	CodeFavoriteNotFound:           "The favorite recipient does not exist.",
+	CodeFavoriteLimitReached:       "The user has pinned the maximum number of favorites.",
+	CodeAttachmentLimitReached:     "The user has attached the maximum number of files to the transaction.",
+	CodePotNotFound:                "The pot does not exist.",
+	CodePaymentRequestNotFound:     "The payment request does not exist.",
+	CodePaymentRequestNotPending:   "The payment request has already been paid or declined.",
+	CodeMoneyDropNotFound:          "The money drop does not exist.",
+	CodeMoneyDropPasswordRequired:  "The money drop is locked and needs its password.",
+	CodeMoneyDropPasswordMismatch:  "The money drop password is wrong.",
+	CodeMoneyDropPasswordLocked:    "Too many wrong money drop passwords; wait and retry.",
+	CodeMoneyDropEndNotAllowed:     "The money drop cannot be ended in its current state.",
+	CodeMoneyDropShareNotAllowed:   "Only active money drops can be shared.",
+	CodeMoneyDropNotLocked:         "The money drop is not password protected.",
+	CodeShortLinkExpired:           "The short link has expired.",
+	CodeInvalidIdempotencyKey:      "The Idempotency-Key header is malformed.",
+	CodeIdempotencyConflict:        "The idempotency key was reused with a different request.",
+	CodeIdempotencyInProgress:      "A request with this idempotency key is still being processed.",
+	CodeOperationInProgress:        "The same operation is already running.",
+	CodeTransactionPINAlreadySet:   "The user already has a transaction PIN; change it instead.",
+	CodeTransactionPINNotSet:       "The user has not created a transaction PIN.",
+	CodeTransactionPINInvalid:      "The transaction PIN is wrong.",
+	CodeTransactionPINLocked:       "Too many wrong PIN attempts; wait and retry.",
+	CodeTransactionPINUnchanged:    "The new PIN matches the current one.",
+	CodeUsernameTaken:              "The username is already in use.",
+	CodeUsernameAlreadySet:         "The user already has a username; change it instead.",
+	CodeUsernameNotSet:             "The user has no username to change yet.",
+	CodeUsernameUnchanged:          "The new username matches the current one.",
+	CodeUsernameChangeCooldown:     "The username was changed too recently.",
+	CodeAccountClosed:              "The account has been closed.",
+	CodeAccountClosureBlocked:      "The account cannot be closed yet; see blocking_reasons.",
+	CodeUnmatchedEventReplayed:     "The unmatched transfer event was already replayed.",
+	CodeUnmatchedEventStillPending: "No transaction matches the transfer event yet.",
+}
+
+// FieldError describes one invalid request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is the body of the error envelope.
+type Error struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// Envelope is the JSON document written for every error response.
+type Envelope struct {
+	Error Error `json:"error"`
+}
+
+// Write sends an error envelope with the given status, code and message.
+func Write(w http.ResponseWriter, status int, code, message string) {
+	write(w, status, Error{Code: code, Message: message})
+}
+
+// WriteStatus sends an error envelope whose code is the generic code for status.
+func WriteStatus(w http.ResponseWriter, status int, message string) {
+	Write(w, status, CodeForStatus(status), message)
+}
+
+// WriteValidation sends a 422 validation_failed envelope listing the invalid fields.
+func WriteValidation(w http.ResponseWriter, message string, details ...FieldError) {
+	write(w, http.StatusUnprocessableEntity, Error{Code: CodeValidationFailed, Message: message, Details: details})
+}
+
+func write(w http.ResponseWriter, status int, body Error) {
+	if body.Message == "" {
+		body.Message = http.StatusText(status)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Envelope{Error: body})
+}
+
+// CodeForStatus returns the generic code for an HTTP status.
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusPaymentRequired:
+		return CodePaymentRequired
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGone:
+		return CodeGone
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusLocked:
+		return CodeLocked
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusBadGateway:
+		return CodeUpstreamFailed
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
@@ -0,0 +1,48 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteValidation_ListsFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteValidation(rec, "Request validation failed",
+		FieldError{Field: "amount", Message: "amount must be greater than zero"},
+		FieldError{Field: "description", Message: "description is too short"},
+	)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rec.Code)
+	}
+	var body Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	if body.Error.Code != CodeValidationFailed || len(body.Error.Details) != 2 || body.Error.Details[1].Field != "description" {
+		t.Fatalf("unexpected envelope %s", rec.Body.String())
+	}
+}
+
+func TestWriteStatus_OmitsDetailsAndDefaultsMessage(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteStatus(rec, http.StatusNotFound, "")
+
+	want := `{"error":{"code":"not_found","message":"Not Found"}}` + "\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", got)
+	}
+}
+
+func TestCodeForStatus_IsDocumented(t *testing.T) {
+	for status := 400; status < 600; status++ {
+		if _, ok := Codes[CodeForStatus(status)]; !ok {
+			t.Fatalf("code %s for status %d is missing from Codes", CodeForStatus(status), status)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * @description
+ * This package reads and validates the pgx connection pool settings shared by the
+ * Transfa services:
+ *
+ *   DB_MAX_CONNS, DB_MIN_CONNS, DB_MAX_CONN_LIFETIME_MINUTES, DB_MAX_CONN_IDLE_MINUTES
+ *
+ * Each service passes its own defaults for settings that are not set.
+ *
+ * @notes
+ * - Values are looked up through a caller-supplied function so services keep reading
+ *   them the way they read the rest of their configuration (viper in all of them).
+ */
+package dbpool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config sizes the pgx connection pool.
+type Config struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+}
+
+// Load reads the DB_* pool settings through lookup, falling back to defaults for
+// values that are unset or blank, and validates the result.
+func Load(defaults Config, lookup func(key string) string) (Config, error) {
+	pool := defaults
+
+	settings := []struct {
+		key   string
+		apply func(value int64)
+	}{
+		{"DB_MAX_CONNS", func(value int64) { pool.MaxConns = int32(value) }},
+		{"DB_MIN_CONNS", func(value int64) { pool.MinConns = int32(value) }},
+		{"DB_MAX_CONN_LIFETIME_MINUTES", func(value int64) { pool.MaxConnLifetime = time.Duration(value) * time.Minute }},
+		{"DB_MAX_CONN_IDLE_MINUTES", func(value int64) { pool.MaxConnIdleTime = time.Duration(value) * time.Minute }},
+	}
+	for _, setting := range settings {
+		raw := strings.TrimSpace(lookup(setting.key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return pool, fmt.Errorf("invalid %s %q: must be a whole number", setting.key, raw)
+		}
+		setting.apply(value)
+	}
+
+	if err := pool.Validate(); err != nil {
+		return pool, err
+	}
+	return pool, nil
+}
+
+// Validate rejects pool settings pgxpool would refuse or silently misbehave with.
+func (c Config) Validate() error {
+	if c.MaxConns < 1 {
+		return fmt.Errorf("invalid DB_MAX_CONNS %d: must be at least 1", c.MaxConns)
+	}
+	if c.MinConns < 0 {
+		return fmt.Errorf("invalid DB_MIN_CONNS %d: must not be negative", c.MinConns)
+	}
+	if c.MinConns > c.MaxConns {
+		return fmt.Errorf("invalid DB_MIN_CONNS %d: must not exceed DB_MAX_CONNS %d", c.MinConns, c.MaxConns)
+	}
+	if c.MaxConnLifetime <= 0 {
+		return fmt.Errorf("invalid DB_MAX_CONN_LIFETIME_MINUTES %d: must be positive", int64(c.MaxConnLifetime/time.Minute))
+	}
+	if c.MaxConnIdleTime <= 0 {
+		return fmt.Errorf("invalid DB_MAX_CONN_IDLE_MINUTES %d: must be positive", int64(c.MaxConnIdleTime/time.Minute))
+	}
+	return nil
+}
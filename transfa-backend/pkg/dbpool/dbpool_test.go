@@ -0,0 +1,60 @@
+package dbpool
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	defaults := Config{MaxConns: 10, MinConns: 2, MaxConnLifetime: 30 * time.Minute, MaxConnIdleTime: 5 * time.Minute}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "defaults when unset",
+			env:  map[string]string{"DB_MAX_CONNS": "", "DB_MIN_CONNS": "  "},
+			want: defaults,
+		},
+		{
+			name: "reads every setting",
+			env: map[string]string{
+				"DB_MAX_CONNS":                 "40",
+				"DB_MIN_CONNS":                 "8",
+				"DB_MAX_CONN_LIFETIME_MINUTES": "45",
+				"DB_MAX_CONN_IDLE_MINUTES":     "3",
+			},
+			want: Config{MaxConns: 40, MinConns: 8, MaxConnLifetime: 45 * time.Minute, MaxConnIdleTime: 3 * time.Minute},
+		},
+		{name: "min equals max", env: map[string]string{"DB_MAX_CONNS": "4", "DB_MIN_CONNS": "4"}, want: Config{MaxConns: 4, MinConns: 4, MaxConnLifetime: 30 * time.Minute, MaxConnIdleTime: 5 * time.Minute}},
+		{name: "non-numeric max", env: map[string]string{"DB_MAX_CONNS": "lots"}, wantErr: "DB_MAX_CONNS"},
+		{name: "fractional idle", env: map[string]string{"DB_MAX_CONN_IDLE_MINUTES": "1.5"}, wantErr: "DB_MAX_CONN_IDLE_MINUTES"},
+		{name: "min above default max", env: map[string]string{"DB_MIN_CONNS": "1000"}, wantErr: "must not exceed DB_MAX_CONNS"},
+		{name: "zero max", env: map[string]string{"DB_MAX_CONNS": "0", "DB_MIN_CONNS": "0"}, wantErr: "DB_MAX_CONNS"},
+		{name: "negative min", env: map[string]string{"DB_MIN_CONNS": "-1"}, wantErr: "DB_MIN_CONNS"},
+		{name: "negative lifetime", env: map[string]string{"DB_MAX_CONN_LIFETIME_MINUTES": "-1"}, wantErr: "DB_MAX_CONN_LIFETIME_MINUTES"},
+		{name: "zero idle time", env: map[string]string{"DB_MAX_CONN_IDLE_MINUTES": "0"}, wantErr: "DB_MAX_CONN_IDLE_MINUTES"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := Load(defaults, func(key string) string { return tt.env[key] })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pool != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, pool)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/transfa/pkg
+
+go 1.24
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/rabbitmq/amqp091-go v1.10.0
+)
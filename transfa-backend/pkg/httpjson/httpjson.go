@@ -0,0 +1,18 @@
+/**
+ * @description
+ * This package writes plain JSON responses for the Transfa services' internal and
+ * admin endpoints. Client-facing errors go through apierror instead.
+ */
+package httpjson
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Write sends payload as JSON with the given status.
+func Write(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
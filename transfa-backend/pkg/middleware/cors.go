@@ -0,0 +1,112 @@
+/**
+ * @description
+ * CORS middleware shared by the Transfa HTTP services. Requests from a listed
+ * origin get the origin echoed back with the allowed methods and headers;
+ * preflight OPTIONS requests are answered here and never reach the router.
+ *
+ * @notes
+ * - Requests from unlisted origins are served without CORS headers, so the
+ *   browser blocks the response; their preflights are refused with a 403.
+ * - A "*" entry allows every origin and is refused by ParseAllowedOrigins in
+ *   production.
+ */
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const corsMaxAgeSeconds = 300
+
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}, ", ")
+	corsAllowedHeaders = strings.Join([]string{
+		"Accept", "Authorization", "Content-Type", "Idempotency-Key", "X-Clerk-User-Id", "X-Request-ID", "X-User-Email",
+	}, ", ")
+)
+
+// defaultDevelopmentOrigins are the local web and Expo dev servers, used when
+// ALLOWED_ORIGINS is unset outside production.
+var defaultDevelopmentOrigins = []string{"http://localhost:3000", "http://localhost:19006", "http://localhost:8081"}
+
+// ErrWildcardOriginInProduction is returned by ParseAllowedOrigins for a "*" origin
+// in production.
+var ErrWildcardOriginInProduction = errors.New("wildcard CORS origin is not allowed in production")
+
+// ParseAllowedOrigins splits a comma-separated ALLOWED_ORIGINS value. An empty value
+// falls back to the local development origins, or to no origins in production.
+func ParseAllowedOrigins(raw string, production bool) ([]string, error) {
+	origins := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		origin := strings.TrimRight(strings.TrimSpace(part), "/")
+		if origin == "" {
+			continue
+		}
+		if origin == "*" && production {
+			return nil, ErrWildcardOriginInProduction
+		}
+		origins = append(origins, origin)
+	}
+
+	if len(origins) == 0 && !production {
+		return append([]string(nil), defaultDevelopmentOrigins...), nil
+	}
+	return origins, nil
+}
+
+// CORS allows cross-origin requests from allowedOrigins. Preflight requests are
+// answered with 204 for listed origins and 403 otherwise.
+func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAll := false
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		allowed[strings.ToLower(origin)] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			_, listed := allowed[strings.ToLower(origin)]
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if !allowAll && !listed {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAll {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAgeSeconds))
+
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
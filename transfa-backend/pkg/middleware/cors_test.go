@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	listed := []string{"https://app.trytransfa.com", "http://localhost:3000"}
+
+	cases := []struct {
+		name        string
+		origins     []string
+		method      string
+		origin      string
+		wantStatus  int
+		wantReached bool
+		wantAllow   string
+	}{
+		{
+			name:       "preflight from listed origin",
+			origins:    listed,
+			method:     http.MethodOptions,
+			origin:     "https://app.trytransfa.com",
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "https://app.trytransfa.com",
+		},
+		{
+			name:        "request from listed origin",
+			origins:     listed,
+			method:      http.MethodGet,
+			origin:      "http://localhost:3000",
+			wantStatus:  http.StatusOK,
+			wantReached: true,
+			wantAllow:   "http://localhost:3000",
+		},
+		{
+			name:        "request from unlisted origin",
+			origins:     listed,
+			method:      http.MethodGet,
+			origin:      "https://evil.example.com",
+			wantStatus:  http.StatusOK,
+			wantReached: true,
+		},
+		{
+			name:       "preflight from unlisted origin",
+			origins:    listed,
+			method:     http.MethodOptions,
+			origin:     "https://evil.example.com",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:        "wildcard origin",
+			origins:     []string{"*"},
+			method:      http.MethodGet,
+			origin:      "https://anywhere.example.com",
+			wantStatus:  http.StatusOK,
+			wantReached: true,
+			wantAllow:   "*",
+		},
+		{
+			name:        "request without origin",
+			origins:     listed,
+			method:      http.MethodGet,
+			wantStatus:  http.StatusOK,
+			wantReached: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tc.method, "/transactions", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			CORS(tc.origins)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if reached != tc.wantReached {
+				t.Fatalf("expected handler reached %v, got %v", tc.wantReached, reached)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.wantAllow {
+				t.Fatalf("expected allow-origin %q, got %q", tc.wantAllow, got)
+			}
+			if tc.origin != "" && rec.Header().Get("Vary") != "Origin" {
+				t.Fatalf("expected Vary: Origin, got %q", rec.Header().Get("Vary"))
+			}
+			if tc.wantAllow != "" {
+				for _, header := range []string{"Access-Control-Allow-Methods", "Access-Control-Allow-Headers", "Access-Control-Max-Age"} {
+					if rec.Header().Get(header) == "" {
+						t.Fatalf("expected %s on the response", header)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseAllowedOrigins(t *testing.T) {
+	cases := []struct {
+		name       string
+		raw        string
+		production bool
+		want       []string
+		wantErr    error
+	}{
+		{
+			name:       "trims spaces and trailing slashes",
+			raw:        " https://app.trytransfa.com/ , ,http://localhost:3000",
+			production: true,
+			want:       []string{"https://app.trytransfa.com", "http://localhost:3000"},
+		},
+		{
+			name:       "wildcard in production",
+			raw:        "https://app.trytransfa.com,*",
+			production: true,
+			wantErr:    ErrWildcardOriginInProduction,
+		},
+		{
+			name: "wildcard outside production",
+			raw:  "*",
+			want: []string{"*"},
+		},
+		{
+			name: "unset outside production",
+			want: defaultDevelopmentOrigins,
+		},
+		{
+			name:       "unset in production",
+			production: true,
+			want:       []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseAllowedOrigins(tc.raw, tc.production)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected origins %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
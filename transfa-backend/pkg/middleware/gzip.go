@@ -0,0 +1,182 @@
+/**
+ * @description
+ * Response compression middleware shared by the Transfa HTTP services. JSON and
+ * text responses go out gzip-encoded to clients that accept it, which keeps large
+ * payloads such as transaction history small on mobile connections.
+ *
+ * @notes
+ * - Output is held back until it reaches gzipMinSize; smaller responses are sent
+ *   as they are since compressing them saves little and costs CPU.
+ * - Responses that already carry a Content-Encoding, or whose Content-Type is not
+ *   JSON or text (PDF statements, for example), are never compressed.
+ */
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const gzipMinSize = 1024
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(io.Discard) },
+}
+
+// GzipMiddleware compresses responses of at least gzipMinSize bytes for clients
+// whose Accept-Encoding allows gzip.
+func GzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Not deferred: after a panic the recoverer upstream writes the response.
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(gw, r)
+		gw.close()
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, honouring an
+// explicit q=0 refusal.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether the
+// response is worth compressing, then either streams it through a gzip.Writer or
+// passes it along unchanged.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	status  int
+	buf     []byte
+	gz      *gzip.Writer
+	decided bool
+}
+
+func (gw *gzipResponseWriter) WriteHeader(status int) {
+	if gw.decided || gw.status != 0 {
+		return
+	}
+	gw.status = status
+}
+
+func (gw *gzipResponseWriter) Write(p []byte) (int, error) {
+	if gw.status == 0 {
+		gw.status = http.StatusOK
+	}
+	if gw.decided {
+		if gw.gz != nil {
+			return gw.gz.Write(p)
+		}
+		return gw.ResponseWriter.Write(p)
+	}
+
+	gw.buf = append(gw.buf, p...)
+	if len(gw.buf) >= gzipMinSize {
+		if err := gw.start(gw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends everything written so far. A flush before the size threshold commits
+// to compression if the response type qualifies, since the handler is streaming.
+func (gw *gzipResponseWriter) Flush() {
+	if !gw.decided {
+		if gw.status == 0 {
+			gw.status = http.StatusOK
+		}
+		if err := gw.start(gw.compressible()); err != nil {
+			return
+		}
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Flush()
+	}
+	if flusher, ok := gw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// compressible reports whether the response so far may be gzip-encoded.
+func (gw *gzipResponseWriter) compressible() bool {
+	header := gw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if gw.status < http.StatusOK || gw.status == http.StatusNoContent || gw.status == http.StatusNotModified {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(gw.buf)
+	}
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "application/problem+json") ||
+		strings.HasPrefix(contentType, "text/")
+}
+
+// start writes the status line and the buffered output, compressed or not.
+func (gw *gzipResponseWriter) start(compress bool) error {
+	gw.decided = true
+	if compress {
+		header := gw.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		gw.gz = gzipWriterPool.Get().(*gzip.Writer)
+		gw.gz.Reset(gw.ResponseWriter)
+	}
+	if gw.status != 0 {
+		gw.ResponseWriter.WriteHeader(gw.status)
+	}
+
+	buf := gw.buf
+	gw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if gw.gz != nil {
+		_, err := gw.gz.Write(buf)
+		return err
+	}
+	_, err := gw.ResponseWriter.Write(buf)
+	return err
+}
+
+// close sends a response that never reached the threshold as it is and finishes
+// the gzip stream of one that did.
+func (gw *gzipResponseWriter) close() {
+	if !gw.decided {
+		_ = gw.start(false)
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Close()
+		gw.gz.Reset(io.Discard)
+		gzipWriterPool.Put(gw.gz)
+		gw.gz = nil
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveGzip(t *testing.T, acceptEncoding string, contentType string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		// Write in pieces so the threshold is crossed part way through.
+		for len(body) > 0 {
+			n := min(len(body), 300)
+			_, _ = io.WriteString(w, body[:n])
+			body = body[n:]
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/history", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	GzipMiddleware(next).ServeHTTP(rec, req)
+	return rec
+}
+
+func largeJSON(t *testing.T) string {
+	t.Helper()
+	items := make([]map[string]any, 100)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "description": "Transfer to ada", "amount": 150000, "status": "completed"}
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(payload)
+}
+
+func TestGzipMiddleware_CompressesLargeJSON(t *testing.T) {
+	body := largeJSON(t)
+
+	rec := serveGzip(t, "br, gzip;q=0.8", "application/json", body)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+	}
+	if rec.Body.Len() >= len(body) {
+		t.Fatalf("expected a smaller body, got %d bytes for %d", rec.Body.Len(), len(body))
+	}
+
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if string(decoded) != body {
+		t.Fatalf("decompressed body does not match the original JSON")
+	}
+}
+
+func TestGzipMiddleware_LeavesSmallResponsesUncompressed(t *testing.T) {
+	body := `{"status":"ok"}`
+
+	rec := serveGzip(t, "gzip", "application/json", body)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected no encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Body.String() != body {
+		t.Fatalf("expected the original body, got %q", rec.Body.String())
+	}
+}
+
+func TestGzipMiddleware_SkipsWhenNotAccepted(t *testing.T) {
+	body := largeJSON(t)
+
+	for _, acceptEncoding := range []string{"", "br", "gzip;q=0"} {
+		rec := serveGzip(t, acceptEncoding, "application/json", body)
+
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != body {
+			t.Fatalf("Accept-Encoding %q: expected an uncompressed response, got encoding %q", acceptEncoding, rec.Header().Get("Content-Encoding"))
+		}
+	}
+}
+
+func TestGzipMiddleware_SkipsNonTextContent(t *testing.T) {
+	body := "%PDF-1.4" + strings.Repeat("x", 4096)
+
+	rec := serveGzip(t, "gzip", "application/pdf", body)
+
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != body {
+		t.Fatalf("expected the PDF to pass through, got encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+}
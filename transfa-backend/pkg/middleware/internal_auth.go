@@ -0,0 +1,39 @@
+/**
+ * @description
+ * Internal API key check for service-to-service and operator routes. Requests must
+ * carry the shared secret in the X-Internal-API-Key header.
+ *
+ * @notes
+ * - A service without a configured key answers 503 rather than leaving the routes
+ *   open.
+ */
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// InternalAuth rejects requests whose X-Internal-API-Key header does not match
+// requiredKey with 401, and every request with 503 when requiredKey is empty.
+func InternalAuth(requiredKey string) func(http.Handler) http.Handler {
+	normalizedRequiredKey := strings.TrimSpace(requiredKey)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if normalizedRequiredKey == "" {
+				http.Error(w, "Internal API key is not configured", http.StatusServiceUnavailable)
+				return
+			}
+
+			provided := strings.TrimSpace(r.Header.Get("X-Internal-API-Key"))
+			if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(normalizedRequiredKey)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
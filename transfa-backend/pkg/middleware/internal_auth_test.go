@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInternalAuth(t *testing.T) {
+	cases := []struct {
+		name        string
+		requiredKey string
+		providedKey string
+		wantStatus  int
+	}{
+		{name: "matching key", requiredKey: "admin-key", providedKey: "admin-key", wantStatus: http.StatusOK},
+		{name: "surrounding spaces", requiredKey: " admin-key ", providedKey: "admin-key ", wantStatus: http.StatusOK},
+		{name: "wrong key", requiredKey: "admin-key", providedKey: "wrong-key", wantStatus: http.StatusUnauthorized},
+		{name: "missing key", requiredKey: "admin-key", wantStatus: http.StatusUnauthorized},
+		{name: "no configured key", providedKey: "admin-key", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/internal/jobs", nil)
+			if tc.providedKey != "" {
+				req.Header.Set("X-Internal-API-Key", tc.providedKey)
+			}
+			rec := httptest.NewRecorder()
+			InternalAuth(tc.requiredKey)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if reached != (tc.wantStatus == http.StatusOK) {
+				t.Fatalf("expected handler reached %v, got %v", tc.wantStatus == http.StatusOK, reached)
+			}
+		})
+	}
+}
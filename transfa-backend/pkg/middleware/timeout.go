@@ -0,0 +1,147 @@
+/**
+ * @description
+ * Request timeout middleware shared by the Transfa HTTP services. Each request runs
+ * with a context deadline and the client receives a 503 as soon as the deadline
+ * passes, even if the handler is still blocked (for example on a slow database query).
+ *
+ * @notes
+ * - Handlers should still honour r.Context() so their goroutine exits promptly
+ *   once the deadline fires; anything they write after that point is discarded.
+ * - Output is buffered until the handler returns or calls Flush. After a flush the
+ *   response streams straight to the client, so a later timeout can only cut it
+ *   short rather than replace it with the 503.
+ * - Mount it on user-facing routes. Internal batch endpoints called by the
+ *   scheduler may legitimately run for longer.
+ */
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const requestTimeoutBody = `{"error":"request_timeout"}`
+
+// RequestTimeout bounds every request to the given duration. If the timeout fires
+// before the handler has returned or flushed, the buffered output is dropped and a
+// 503 with {"error":"request_timeout"} is written instead.
+func RequestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicChan := make(chan interface{}, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- fmt.Sprintf("%v\n%s", p, debug.Stack())
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				// Re-panic on the serving goroutine so upstream recoverers still see it.
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				if !tw.streaming {
+					tw.commitLocked()
+				}
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				if tw.streaming {
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(requestTimeoutBody))
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers a handler's response so it can be discarded on timeout, until
+// the handler flushes it to the client.
+type timeoutWriter struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	header    http.Header
+	body      bytes.Buffer
+	status    int
+	timedOut  bool
+	streaming bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	if tw.streaming {
+		return tw.w.Write(p)
+	}
+	return tw.body.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.status != 0 {
+		return
+	}
+	tw.status = status
+}
+
+// Flush sends the status, headers and buffered body to the client and switches the
+// writer to streaming, then flushes the underlying writer if it supports it.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	if !tw.streaming {
+		tw.commitLocked()
+		tw.streaming = true
+	}
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// commitLocked copies the buffered response to the underlying writer. tw.mu must be held.
+func (tw *timeoutWriter) commitLocked() {
+	dst := tw.w.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.status)
+	_, _ = tw.w.Write(tw.body.Bytes())
+	tw.body.Reset()
+}
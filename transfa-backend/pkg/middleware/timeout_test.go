@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeout_SlowHandlerReturns503(t *testing.T) {
+	handlerExited := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(handlerExited)
+		select {
+		case <-time.After(2 * time.Second):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+
+	start := time.Now()
+	RequestTimeout(50*time.Millisecond)(slow).ServeHTTP(rec, req)
+	elapsed := time.Since(start)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	if got := rec.Body.String(); got != `{"error":"request_timeout"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", got)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("expected timeout response within the timeout window, took %s", elapsed)
+	}
+
+	select {
+	case <-handlerExited:
+	case <-time.After(time.Second):
+		t.Fatal("expected handler context to be cancelled")
+	}
+}
+
+func TestRequestTimeout_FastHandlerPassesThrough(t *testing.T) {
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "ok")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/fast", nil)
+	RequestTimeout(time.Second)(fast).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if rec.Body.String() != "created" {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+	if rec.Header().Get("X-Test") != "ok" {
+		t.Fatalf("expected handler headers to be copied")
+	}
+}
+
+func TestRequestTimeout_PropagatesHandlerPanic(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic to propagate to the serving goroutine")
+		}
+	}()
+
+	RequestTimeout(time.Second)(panicking).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRequestTimeout_FlushStreamsToClient(t *testing.T) {
+	flushed := make(chan struct{})
+	release := make(chan struct{})
+	streaming := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("part1"))
+		w.(http.Flusher).Flush()
+		close(flushed)
+		<-release
+		_, _ = w.Write([]byte("part2"))
+	})
+
+	rec := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		RequestTimeout(time.Second)(streaming).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	}()
+
+	<-flushed
+	if !rec.Flushed || rec.Body.String() != "part1" || rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Fatalf("expected the first part to reach the client on flush, got flushed=%v body=%q", rec.Flushed, rec.Body.String())
+	}
+	close(release)
+	<-served
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "part1part2" {
+		t.Fatalf("expected the streamed response, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequestTimeout_TimeoutAfterFlushKeepsStreamedStatus(t *testing.T) {
+	handlerExited := make(chan struct{})
+	streaming := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(handlerExited)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		if _, err := w.Write([]byte("late")); err != http.ErrHandlerTimeout {
+			t.Errorf("expected writes after the timeout to fail, got %v", err)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	RequestTimeout(50*time.Millisecond)(streaming).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	<-handlerExited
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Fatalf("expected the streamed prefix without a 503, got %d %q", rec.Code, rec.Body.String())
+	}
+}
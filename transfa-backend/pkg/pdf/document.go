@@ -0,0 +1,194 @@
+/**
+ * @description
+ * Minimal PDF 1.4 writer used for generated documents such as account statements
+ * and platform fee invoices. It supports text in the standard Type 1 fonts, ruled
+ * lines, multiple A4 pages and optional password protection.
+ *
+ * @notes
+ * - Only the three standard fonts registered below are available, so text is limited
+ *   to WinAnsi characters; anything else is replaced with "?".
+ */
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// Font resource names available to page content.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontMono    = "F3"
+)
+
+var fontBaseNames = []struct {
+	resource string
+	baseFont string
+}{
+	{fontRegular, "Helvetica"},
+	{fontBold, "Helvetica-Bold"},
+	{fontMono, "Courier"},
+}
+
+// monoCharWidth is the advance of one Courier glyph per point of font size.
+const monoCharWidth = 0.6
+
+// page accumulates the content stream of one page.
+type page struct {
+	content bytes.Buffer
+}
+
+// text draws s with its baseline starting at (x, y).
+func (p *page) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapeText(s))
+}
+
+// monoTextRight draws s in the monospaced font so that it ends at x.
+func (p *page) monoTextRight(size, x, y float64, s string) {
+	p.text(fontMono, size, x-float64(len(s))*size*monoCharWidth, y, s)
+}
+
+// line draws a thin horizontal or vertical rule.
+func (p *page) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// document is an ordered list of pages written out as a single PDF file.
+type document struct {
+	pages []*page
+}
+
+func (d *document) addPage() *page {
+	p := &page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// write serialises the document. When enc is non-nil every content stream is
+// encrypted and the file carries the matching /Encrypt dictionary.
+func (d *document) write(w io.Writer, enc *encryption) error {
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+
+	beginObject := func() int {
+		offsets = append(offsets, out.n)
+		num := len(offsets)
+		fmt.Fprintf(out, "%d 0 obj\n", num)
+		return num
+	}
+	endObject := func() {
+		fmt.Fprint(out, "endobj\n")
+	}
+
+	// Object numbers are fixed up front: catalog, page tree, fonts, then one page
+	// and one content stream per page, and finally the encryption dictionary.
+	const catalogNum, pagesNum = 1, 2
+	firstFontNum := 3
+	firstPageNum := firstFontNum + len(fontBaseNames)
+
+	_, _ = io.WriteString(out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	beginObject()
+	fmt.Fprintf(out, "<< /Type /Catalog /Pages %d 0 R >>\n", pagesNum)
+	endObject()
+
+	beginObject()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageNum+2*i)
+	}
+	fmt.Fprintf(out, "<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	endObject()
+
+	fontRefs := make([]string, len(fontBaseNames))
+	for i, font := range fontBaseNames {
+		beginObject()
+		fmt.Fprintf(out, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", font.baseFont)
+		endObject()
+		fontRefs[i] = fmt.Sprintf("/%s %d 0 R", font.resource, firstFontNum+i)
+	}
+
+	for _, p := range d.pages {
+		pageNum := beginObject()
+		fmt.Fprintf(out, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>\n",
+			pagesNum, pageWidth, pageHeight, strings.Join(fontRefs, " "), pageNum+1)
+		endObject()
+
+		contentNum := beginObject()
+		stream := p.content.Bytes()
+		if enc != nil {
+			stream = enc.encrypt(contentNum, stream)
+		}
+		fmt.Fprintf(out, "<< /Length %d >>\nstream\n", len(stream))
+		_, _ = out.Write(stream)
+		fmt.Fprint(out, "\nendstream\n")
+		endObject()
+	}
+
+	encryptRef := ""
+	if enc != nil {
+		encryptNum := beginObject()
+		fmt.Fprintf(out, "<< /Filter /Standard /V 2 /R 3 /Length 128 /O <%x> /U <%x> /P %d >>\n", enc.owner, enc.user, enc.permissions)
+		endObject()
+		encryptRef = fmt.Sprintf(" /Encrypt %d 0 R /ID [<%x> <%x>]", encryptNum, enc.fileID, enc.fileID)
+	}
+
+	xrefOffset := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root %d 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogNum, encryptRef, xrefOffset)
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// escapeText converts s to a WinAnsi literal string body.
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// countingWriter tracks byte offsets for the cross-reference table and keeps the
+// first write error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
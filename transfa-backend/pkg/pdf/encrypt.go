@@ -0,0 +1,148 @@
+package pdf
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"encoding/binary"
+	"errors"
+)
+
+// passwordPadding is the fixed padding string from the PDF standard security handler.
+var passwordPadding = []byte{
+	0x28, 0xbf, 0x4e, 0x5e, 0x4e, 0x75, 0x8a, 0x41, 0x64, 0x00, 0x4e, 0x56, 0xff, 0xfa, 0x01, 0x08,
+	0x2e, 0x2e, 0x00, 0xb6, 0xd0, 0x68, 0x3e, 0x80, 0x2f, 0x0c, 0xa9, 0xfe, 0x64, 0x53, 0x69, 0x7a,
+}
+
+// statementPermissions allows printing but not editing or copying (revision 3 bits).
+const statementPermissions int32 = -1852
+
+const encryptionKeyLen = 16
+
+// MaxPasswordLength is the longest password the standard security handler can use;
+// longer passwords would be silently truncated by readers.
+const MaxPasswordLength = 32
+
+// ErrInvalidPassword is returned for passwords the standard security handler cannot represent.
+var ErrInvalidPassword = errors.New("pdf password must be 1-32 printable ASCII characters")
+
+// encryption holds the values of a 128-bit RC4 standard security handler
+// (PDF 1.4, revision 3).
+type encryption struct {
+	key         []byte
+	owner       []byte
+	user        []byte
+	permissions int32
+	fileID      []byte
+}
+
+// newEncryption derives the file key for userPassword. The owner password is random,
+// so permissions cannot be lifted with the user password.
+func newEncryption(userPassword string) (*encryption, error) {
+	if !validPassword(userPassword) {
+		return nil, ErrInvalidPassword
+	}
+
+	fileID := make([]byte, 16)
+	ownerPassword := make([]byte, MaxPasswordLength)
+	if _, err := rand.Read(fileID); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(ownerPassword); err != nil {
+		return nil, err
+	}
+
+	enc := &encryption{permissions: statementPermissions, fileID: fileID}
+	enc.owner = computeOwnerEntry(ownerPassword, []byte(userPassword))
+	enc.key = computeFileKey([]byte(userPassword), enc.owner, enc.permissions, fileID)
+	enc.user = computeUserEntry(enc.key, fileID)
+	return enc, nil
+}
+
+// encrypt encrypts the data of object num (generation 0).
+func (e *encryption) encrypt(num int, data []byte) []byte {
+	seed := make([]byte, 0, len(e.key)+5)
+	seed = append(seed, e.key...)
+	seed = append(seed, byte(num), byte(num>>8), byte(num>>16), 0, 0)
+	objectKey := md5.Sum(seed)
+	return rc4Crypt(objectKey[:min(len(e.key)+5, 16)], data)
+}
+
+func validPassword(password string) bool {
+	if len(password) == 0 || len(password) > MaxPasswordLength {
+		return false
+	}
+	for i := 0; i < len(password); i++ {
+		if password[i] < 0x20 || password[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func padPassword(password []byte) []byte {
+	padded := make([]byte, 0, 32)
+	padded = append(padded, password[:min(len(password), 32)]...)
+	return append(padded, passwordPadding[:32-len(padded)]...)
+}
+
+// computeOwnerEntry implements algorithm 3 of the standard security handler.
+func computeOwnerEntry(ownerPassword, userPassword []byte) []byte {
+	hash := md5.Sum(padPassword(ownerPassword))
+	for i := 0; i < 50; i++ {
+		hash = md5.Sum(hash[:encryptionKeyLen])
+	}
+	key := hash[:encryptionKeyLen]
+
+	result := rc4Crypt(key, padPassword(userPassword))
+	for i := 1; i <= 19; i++ {
+		result = rc4Crypt(xorKey(key, byte(i)), result)
+	}
+	return result
+}
+
+// computeFileKey implements algorithm 2 of the standard security handler.
+func computeFileKey(userPassword, ownerEntry []byte, permissions int32, fileID []byte) []byte {
+	input := padPassword(userPassword)
+	input = append(input, ownerEntry...)
+	input = binary.LittleEndian.AppendUint32(input, uint32(permissions))
+	input = append(input, fileID...)
+
+	hash := md5.Sum(input)
+	for i := 0; i < 50; i++ {
+		hash = md5.Sum(hash[:encryptionKeyLen])
+	}
+	return append([]byte(nil), hash[:encryptionKeyLen]...)
+}
+
+// computeUserEntry implements algorithm 5 of the standard security handler.
+func computeUserEntry(key, fileID []byte) []byte {
+	seed := append(append([]byte(nil), passwordPadding...), fileID...)
+	hash := md5.Sum(seed)
+
+	result := rc4Crypt(key, hash[:])
+	for i := 1; i <= 19; i++ {
+		result = rc4Crypt(xorKey(key, byte(i)), result)
+	}
+	// Only the first 16 bytes are checked by readers; the rest is arbitrary padding.
+	return append(result, passwordPadding[:16]...)
+}
+
+func xorKey(key []byte, value byte) []byte {
+	out := make([]byte, len(key))
+	for i, b := range key {
+		out[i] = b ^ value
+	}
+	return out
+}
+
+func rc4Crypt(key, data []byte) []byte {
+	cipher, err := rc4.NewCipher(key)
+	if err != nil {
+		// Keys here are always 5-16 bytes, within RC4's accepted range.
+		panic(err)
+	}
+	out := make([]byte, len(data))
+	cipher.XORKeyStream(out, data)
+	return out
+}
@@ -0,0 +1,95 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Invoice is a platform fee invoice ready to be rendered. Amounts are in kobo.
+type Invoice struct {
+	Number      string
+	Status      string
+	Currency    string
+	BaseAmount  int64
+	Amount      int64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	DueAt       time.Time
+	PaidAt      *time.Time
+	GeneratedAt time.Time
+
+	// ProrationFactor is the share of BaseAmount charged, from 0 to 1.
+	ProrationFactor float64
+
+	CustomerName     string
+	CustomerUsername string
+	CustomerEmail    string
+}
+
+// Render writes the invoice as a single-page PDF.
+func (inv Invoice) Render(w io.Writer) error {
+	doc := &document{}
+	p := doc.addPage()
+
+	y := pageHeight - 60
+	p.text(fontBold, 18, marginLeft, y, "Transfa")
+	p.text(fontRegular, 12, marginLeft, y-20, "Platform Fee Invoice")
+
+	y -= 55
+	details := [][2]string{
+		{"Invoice number", inv.Number},
+		{"Status", strings.ToUpper(inv.Status)},
+		{"Period", fmt.Sprintf("%s - %s", inv.PeriodStart.Format("02 Jan 2006"), inv.PeriodEnd.Format("02 Jan 2006"))},
+		{"Due date", inv.DueAt.Format("02 Jan 2006")},
+	}
+	if inv.PaidAt != nil {
+		details = append(details, [2]string{"Paid on", inv.PaidAt.Format("02 Jan 2006 15:04 MST")})
+	}
+	y = writeDetails(p, y, details)
+
+	y -= 10
+	p.text(fontBold, 11, marginLeft, y, "Billed to")
+	y -= 18
+	billedTo := [][2]string{{"Name", inv.CustomerName}}
+	if inv.CustomerUsername != "" {
+		billedTo = append(billedTo, [2]string{"Username", "@" + inv.CustomerUsername})
+	}
+	if inv.CustomerEmail != "" {
+		billedTo = append(billedTo, [2]string{"Email", inv.CustomerEmail})
+	}
+	y = writeDetails(p, y, billedTo)
+
+	y -= 10
+	p.line(marginLeft, y, marginRight, y)
+	y -= 18
+	currency := inv.Currency
+	if currency == "" {
+		currency = "NGN"
+	}
+	if inv.ProrationFactor > 0 && inv.ProrationFactor < 1 {
+		p.text(fontRegular, 10, marginLeft, y, "Monthly platform fee")
+		p.monoTextRight(10, marginRight, y, currency+" "+formatKobo(inv.BaseAmount))
+		y -= 15
+		p.text(fontRegular, 10, marginLeft, y, fmt.Sprintf("Prorated for partial month (%.0f%%)", inv.ProrationFactor*100))
+		p.monoTextRight(10, marginRight, y, currency+" "+formatKobo(inv.Amount-inv.BaseAmount))
+		y -= 15
+	}
+	p.text(fontBold, 11, marginLeft, y, "Total")
+	p.monoTextRight(11, marginRight, y, currency+" "+formatKobo(inv.Amount))
+
+	p.text(fontRegular, 8, marginLeft, 30, fmt.Sprintf("Generated %s", inv.GeneratedAt.Format("02 Jan 2006 15:04 MST")))
+
+	return doc.write(w, nil)
+}
+
+// writeDetails draws label/value rows and returns the baseline below them.
+func writeDetails(p *page, y float64, rows [][2]string) float64 {
+	for _, row := range rows {
+		p.text(fontBold, 10, marginLeft, y, row[0])
+		p.text(fontRegular, 10, marginLeft+110, y, row[1])
+		y -= 15
+	}
+	return y
+}
@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testInvoice() Invoice {
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	paidAt := start.AddDate(0, 1, 2)
+	return Invoice{
+		Number:           "INV-202602-1A2B3C4D",
+		Status:           "paid",
+		Currency:         "NGN",
+		BaseAmount:       50000,
+		Amount:           50000,
+		ProrationFactor:  1,
+		PeriodStart:      start,
+		PeriodEnd:        start.AddDate(0, 1, 0).Add(-time.Second),
+		DueAt:            start.AddDate(0, 1, 0),
+		PaidAt:           &paidAt,
+		GeneratedAt:      paidAt,
+		CustomerName:     "Ada Obi",
+		CustomerUsername: "ada",
+		CustomerEmail:    "ada@example.com",
+	}
+}
+
+func TestInvoiceRender_WritesPDF(t *testing.T) {
+	var out bytes.Buffer
+	if err := testInvoice().Render(&out); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	body := out.String()
+	if !strings.HasPrefix(body, "%PDF-1.4\n") || !strings.HasSuffix(body, "%%EOF\n") {
+		t.Fatal("expected a complete PDF document")
+	}
+	for _, want := range []string{
+		"(INV-202602-1A2B3C4D)",
+		"(PAID)",
+		"(01 Feb 2026 - 28 Feb 2026)",
+		"(03 Mar 2026 00:00 UTC)",
+		"(Ada Obi)",
+		"(@ada)",
+		"(ada@example.com)",
+		"(NGN 500.00)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected invoice content %s", want)
+		}
+	}
+	if strings.Contains(body, "Prorated") {
+		t.Fatal("did not expect a proration line on a full-month invoice")
+	}
+}
+
+func TestInvoiceRender_ShowsProrationAndOmitsUnpaidDate(t *testing.T) {
+	invoice := testInvoice()
+	invoice.Status = "pending"
+	invoice.PaidAt = nil
+	invoice.ProrationFactor = 0.5
+	invoice.Amount = 25000
+
+	var out bytes.Buffer
+	if err := invoice.Render(&out); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	body := out.String()
+	for _, want := range []string{"(Prorated for partial month \\(50%\\))", "(NGN -250.00)", "(NGN 250.00)"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected invoice content %s", want)
+		}
+	}
+	if strings.Contains(body, "(Paid on)") {
+		t.Fatal("did not expect a paid date on an unpaid invoice")
+	}
+}
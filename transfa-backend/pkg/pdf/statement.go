@@ -0,0 +1,161 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Statement is an account statement ready to be rendered. Amounts are in kobo.
+type Statement struct {
+	AccountName    string
+	AccountNumber  string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	GeneratedAt    time.Time
+	OpeningBalance int64
+	TotalCredits   int64
+	TotalDebits    int64
+	ClosingBalance int64
+	Lines          []StatementLine
+}
+
+// StatementLine is one transaction on a statement with the running balance after it.
+type StatementLine struct {
+	Date        time.Time
+	Description string
+	Debit       int64
+	Credit      int64
+	Balance     int64
+}
+
+// Statement layout, in points.
+const (
+	marginLeft       = 40.0
+	marginRight      = pageWidth - 40.0
+	marginBottom     = 60.0
+	tableFontSize    = 8.0
+	tableRowHeight   = 13.0
+	descriptionX     = 110.0
+	debitRightX      = 380.0
+	creditRightX     = 465.0
+	balanceRightX    = marginRight
+	maxDescriptionCh = 36
+)
+
+// Render writes the statement as a PDF. A non-empty password encrypts the file so it
+// can only be opened with that password.
+func (s Statement) Render(w io.Writer, password string) error {
+	var enc *encryption
+	if password != "" {
+		var err error
+		if enc, err = newEncryption(password); err != nil {
+			return err
+		}
+	}
+
+	doc := &document{}
+	p := doc.addPage()
+	y := s.writeHeader(p)
+	y = writeTableHeader(p, y)
+
+	for _, line := range s.Lines {
+		if y < marginBottom {
+			p = doc.addPage()
+			y = writeTableHeader(p, pageHeight-50)
+		}
+		p.text(fontMono, tableFontSize, marginLeft, y, line.Date.Format("02 Jan 2006"))
+		p.text(fontMono, tableFontSize, descriptionX, y, truncate(line.Description, maxDescriptionCh))
+		if line.Debit != 0 {
+			p.monoTextRight(tableFontSize, debitRightX, y, formatKobo(line.Debit))
+		}
+		if line.Credit != 0 {
+			p.monoTextRight(tableFontSize, creditRightX, y, formatKobo(line.Credit))
+		}
+		p.monoTextRight(tableFontSize, balanceRightX, y, formatKobo(line.Balance))
+		y -= tableRowHeight
+	}
+	if len(s.Lines) == 0 {
+		p.text(fontRegular, 9, marginLeft, y, "No transactions in this period.")
+	}
+
+	for i, pg := range doc.pages {
+		pg.text(fontRegular, 8, marginLeft, 30, fmt.Sprintf("Generated %s", s.GeneratedAt.Format("02 Jan 2006 15:04 MST")))
+		label := fmt.Sprintf("Page %d of %d", i+1, len(doc.pages))
+		pg.text(fontRegular, 8, marginRight-float64(len(label))*4.5, 30, label)
+	}
+
+	return doc.write(w, enc)
+}
+
+func (s Statement) writeHeader(p *page) float64 {
+	y := pageHeight - 60
+	p.text(fontBold, 18, marginLeft, y, "Transfa")
+	p.text(fontRegular, 12, marginLeft, y-20, "Account Statement")
+
+	y -= 55
+	details := [][2]string{
+		{"Account name", s.AccountName},
+		{"Account number", s.AccountNumber},
+		{"Period", fmt.Sprintf("%s - %s", s.PeriodStart.Format("02 Jan 2006"), s.PeriodEnd.Format("02 Jan 2006"))},
+	}
+	for _, item := range details {
+		p.text(fontBold, 10, marginLeft, y, item[0])
+		p.text(fontRegular, 10, marginLeft+110, y, item[1])
+		y -= 15
+	}
+
+	y -= 10
+	summary := [][2]string{
+		{"Opening balance", "NGN " + formatKobo(s.OpeningBalance)},
+		{"Total credits", "NGN " + formatKobo(s.TotalCredits)},
+		{"Total debits", "NGN " + formatKobo(s.TotalDebits)},
+		{"Closing balance", "NGN " + formatKobo(s.ClosingBalance)},
+	}
+	for _, item := range summary {
+		p.text(fontBold, 10, marginLeft, y, item[0])
+		p.monoTextRight(10, marginLeft+260, y, item[1])
+		y -= 15
+	}
+	return y - 15
+}
+
+func writeTableHeader(p *page, y float64) float64 {
+	p.text(fontBold, 9, marginLeft, y, "Date")
+	p.text(fontBold, 9, descriptionX, y, "Description")
+	p.text(fontBold, 9, debitRightX-30, y, "Debit")
+	p.text(fontBold, 9, creditRightX-33, y, "Credit")
+	p.text(fontBold, 9, balanceRightX-38, y, "Balance")
+	p.line(marginLeft, y-5, marginRight, y-5)
+	return y - 18
+}
+
+func truncate(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+// formatKobo renders a kobo amount as naira with thousands separators, e.g.
+// 150000 -> "1,500.00".
+func formatKobo(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	whole := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, grouped.String(), amount%100)
+}
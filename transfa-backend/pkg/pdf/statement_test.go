@@ -0,0 +1,142 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testStatement(lines int) Statement {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	statement := Statement{
+		AccountName:    "Ada Obi",
+		AccountNumber:  "0123456789",
+		PeriodStart:    start,
+		PeriodEnd:      start.AddDate(0, 1, -1),
+		GeneratedAt:    start.AddDate(0, 1, 0),
+		OpeningBalance: 100000,
+		ClosingBalance: 100000,
+	}
+	for i := 0; i < lines; i++ {
+		statement.ClosingBalance += 500
+		statement.TotalCredits += 500
+		statement.Lines = append(statement.Lines, StatementLine{
+			Date:        start.Add(time.Duration(i) * time.Hour),
+			Description: "Transfer from (bob)",
+			Credit:      500,
+			Balance:     statement.ClosingBalance,
+		})
+	}
+	return statement
+}
+
+func TestStatementRender_WritesPDF(t *testing.T) {
+	var out bytes.Buffer
+	if err := testStatement(3).Render(&out, ""); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	body := out.String()
+	if !strings.HasPrefix(body, "%PDF-1.4\n") {
+		t.Fatalf("expected PDF header, got %q", body[:min(len(body), 16)])
+	}
+	if !strings.HasSuffix(body, "%%EOF\n") {
+		t.Fatal("expected PDF to end with the EOF marker")
+	}
+	for _, want := range []string{"(Ada Obi)", "(0123456789)", "(NGN 1,015.00)", `(Transfer from \(bob\))`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected statement content %s", want)
+		}
+	}
+	if strings.Contains(body, "/Encrypt") {
+		t.Fatal("did not expect an unprotected statement to be encrypted")
+	}
+}
+
+func TestStatementRender_SplitsLongStatementsAcrossPages(t *testing.T) {
+	var out bytes.Buffer
+	if err := testStatement(120).Render(&out, ""); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "/Count 3") {
+		t.Fatal("expected 120 lines to span three pages")
+	}
+	if !strings.Contains(out.String(), "(Page 3 of 3)") {
+		t.Fatal("expected page numbers in the footer")
+	}
+}
+
+func TestStatementRender_EncryptsWithPassword(t *testing.T) {
+	var out bytes.Buffer
+	if err := testStatement(3).Render(&out, "s3cret"); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	body := out.String()
+	if !strings.HasPrefix(body, "%PDF-1.4\n") {
+		t.Fatal("expected PDF header on an encrypted statement")
+	}
+	if strings.Contains(body, "Ada Obi") || strings.Contains(body, "0123456789") {
+		t.Fatal("expected statement content to be encrypted")
+	}
+
+	owner := hexField(t, body, `/O <([0-9a-f]+)>`)
+	user := hexField(t, body, `/U <([0-9a-f]+)>`)
+	fileID := hexField(t, body, `/ID \[<([0-9a-f]+)>`)
+
+	// A reader authenticates the user password by recomputing /U (algorithm 6).
+	key := computeFileKey([]byte("s3cret"), owner, statementPermissions, fileID)
+	if !bytes.Equal(computeUserEntry(key, fileID)[:16], user[:16]) {
+		t.Fatal("expected the password to authenticate against /U")
+	}
+	wrongKey := computeFileKey([]byte("wrong"), owner, statementPermissions, fileID)
+	if bytes.Equal(computeUserEntry(wrongKey, fileID)[:16], user[:16]) {
+		t.Fatal("expected a wrong password to be rejected")
+	}
+
+	// The first page's content stream is object 7: catalog, pages, three fonts, page.
+	stream := regexp.MustCompile(`(?s)7 0 obj\n<< /Length \d+ >>\nstream\n(.*?)\nendstream`).FindStringSubmatch(body)
+	if stream == nil {
+		t.Fatal("expected a content stream for the first page")
+	}
+	seed := append(append([]byte(nil), key...), 7, 0, 0, 0, 0)
+	objectKey := md5.Sum(seed)
+	plain := rc4Crypt(objectKey[:], []byte(stream[1]))
+	if !bytes.Contains(plain, []byte("(Ada Obi)")) {
+		t.Fatal("expected the decrypted content stream to contain the account name")
+	}
+}
+
+func TestStatementRender_RejectsUnsupportedPassword(t *testing.T) {
+	for _, password := range []string{strings.Repeat("a", 33), "pässword"} {
+		if err := testStatement(1).Render(&bytes.Buffer{}, password); err != ErrInvalidPassword {
+			t.Fatalf("expected ErrInvalidPassword for %q, got %v", password, err)
+		}
+	}
+}
+
+func TestFormatKobo(t *testing.T) {
+	tests := map[int64]string{5: "0.05", 150000: "1,500.00", -123456789: "-1,234,567.89"}
+	for amount, want := range tests {
+		if got := formatKobo(amount); got != want {
+			t.Fatalf("formatKobo(%d) = %q, want %q", amount, got, want)
+		}
+	}
+}
+
+func hexField(t *testing.T, body, pattern string) []byte {
+	t.Helper()
+	match := regexp.MustCompile(pattern).FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("expected %s in the PDF", pattern)
+	}
+	value, err := hex.DecodeString(match[1])
+	if err != nil {
+		t.Fatalf("invalid hex for %s: %v", pattern, err)
+	}
+	return value
+}
@@ -0,0 +1,226 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// MessageHandler processes a single RabbitMQ message. It returns true to acknowledge
+// the message, or false to reject and requeue it.
+type MessageHandler func(body []byte) bool
+
+// consumerChannel is the subset of *amqp091.Channel the consumer uses, so tests can
+// substitute a stub.
+type consumerChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp091.Table) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
+	Close() error
+}
+
+// Consumer holds the connection and channel used to consume events.
+type Consumer struct {
+	conn *amqp091.Connection
+	ch   consumerChannel
+
+	// signingSecrets verify deliveries whose routing key is in signedRoutingKeys.
+	signingSecrets    []string
+	signedRoutingKeys map[string]struct{}
+}
+
+// ConsumerOption configures a Consumer.
+type ConsumerOption func(*Consumer)
+
+// WithSignedEvents requires deliveries on routingKeys to carry a SignatureHeader that
+// verifies under one of secrets. Deliveries that fail verification are rejected
+// without requeue and never reach their handler.
+func WithSignedEvents(secrets []string, routingKeys ...string) ConsumerOption {
+	return func(c *Consumer) {
+		c.signingSecrets = secrets
+		if c.signedRoutingKeys == nil {
+			c.signedRoutingKeys = make(map[string]struct{}, len(routingKeys))
+		}
+		for _, routingKey := range routingKeys {
+			c.signedRoutingKeys[routingKey] = struct{}{}
+		}
+	}
+}
+
+// NewConsumer creates and returns a new RabbitMQ consumer.
+func NewConsumer(amqpURL string, opts ...ConsumerOption) (*Consumer, error) {
+	cleanURL, err := sanitizeAMQPURL(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := amqp091.Dial(cleanURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &Consumer{conn: conn, ch: ch}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Consume binds a durable queue to exchange with routingKey and passes each delivery
+// to handler. It blocks for the life of the consumer, so callers run it in its own
+// goroutine.
+func (c *Consumer) Consume(exchange, queueName, routingKey string, handler MessageHandler) error {
+	msgs, err := c.declareAndConsume(exchange, queueName, []string{routingKey})
+	if err != nil {
+		return err
+	}
+
+	for d := range msgs {
+		c.dispatch(d, handler)
+	}
+	return nil
+}
+
+// ConsumeWithBindings binds the queue to every routing key in bindings and dispatches
+// each delivery to the handler for its routing key, with the same ack rules as
+// Consume. Deliveries are handled on a single background goroutine and the call
+// returns once consumption has started.
+func (c *Consumer) ConsumeWithBindings(exchange, queueName string, bindings map[string]func([]byte) bool) error {
+	if len(bindings) == 0 {
+		return fmt.Errorf("no bindings provided")
+	}
+
+	handlers := make(map[string]MessageHandler)
+	routingKeys := make([]string, 0, len(bindings))
+	for routingKey, handler := range bindings {
+		if handler == nil {
+			continue
+		}
+		handlers[routingKey] = handler
+		routingKeys = append(routingKeys, routingKey)
+	}
+
+	msgs, err := c.declareAndConsume(exchange, queueName, routingKeys)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for d := range msgs {
+			handler, ok := handlers[d.RoutingKey]
+			if !ok {
+				log.Printf("level=warn component=rabbitmq_consumer outcome=ack reason=no_handler routing_key=%s", d.RoutingKey)
+				d.Ack(false)
+				continue
+			}
+			c.dispatch(d, handler)
+		}
+	}()
+
+	return nil
+}
+
+// dispatch passes d to handler and acks it, or requeues it when the handler returns
+// false. A handler that panics has failed for good: the delivery is rejected without
+// requeue so one bad message cannot crash the consumer on every redelivery.
+func (c *Consumer) dispatch(d amqp091.Delivery, handler MessageHandler) {
+	if !c.verified(d) {
+		return
+	}
+
+	ok, panicked := runHandler(handler, d.Body)
+	switch {
+	case panicked:
+		log.Printf("level=error component=rabbitmq_consumer outcome=reject reason=handler_panic routing_key=%s", d.RoutingKey)
+		d.Nack(false, false)
+	case ok:
+		d.Ack(false)
+	default:
+		log.Printf("level=warn component=rabbitmq_consumer outcome=requeue routing_key=%s", d.RoutingKey)
+		d.Nack(false, true)
+	}
+}
+
+func runHandler(handler MessageHandler, body []byte) (ok bool, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("level=error component=rabbitmq_consumer msg=\"handler panicked\" panic=%v", r)
+			ok, panicked = false, true
+		}
+	}()
+	return handler(body), false
+}
+
+// verified checks the delivery signature when its routing key requires one. A
+// delivery that fails the check is rejected without requeue, since redelivering it
+// cannot make the signature valid.
+func (c *Consumer) verified(d amqp091.Delivery) bool {
+	if _, ok := c.signedRoutingKeys[d.RoutingKey]; !ok {
+		return true
+	}
+	for _, secret := range c.signingSecrets {
+		if VerifySignedEvent(d.Body, d.Headers, secret) {
+			return true
+		}
+	}
+	log.Printf("level=warn component=rabbitmq_consumer outcome=reject reason=invalid_signature routing_key=%s", d.RoutingKey)
+	d.Nack(false, false)
+	return false
+}
+
+// DeclareQueue declares a durable queue bound to exchange for each routing key
+// without consuming from it, so messages published before any consumer attaches are
+// kept rather than dropped.
+func (c *Consumer) DeclareQueue(exchange, queueName string, routingKeys ...string) error {
+	if err := c.ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if _, err := c.ch.QueueDeclare(queueName, true, false, false, false, nil); err != nil {
+		return err
+	}
+	for _, routingKey := range routingKeys {
+		if err := c.ch.QueueBind(queueName, routingKey, exchange, false, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declareAndConsume declares the durable topic exchange and queue, binds the queue
+// for each routing key and starts a manually acknowledged consumer on it.
+func (c *Consumer) declareAndConsume(exchange, queueName string, routingKeys []string) (<-chan amqp091.Delivery, error) {
+	if err := c.ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		return nil, err
+	}
+
+	q, err := c.ch.QueueDeclare(queueName, true, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, routingKey := range routingKeys {
+		if err := c.ch.QueueBind(q.Name, routingKey, exchange, false, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.ch.Consume(q.Name, "", false, false, false, false, nil)
+}
+
+// Close gracefully closes the channel and connection to RabbitMQ.
+func (c *Consumer) Close() {
+	if c.ch != nil {
+		c.ch.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
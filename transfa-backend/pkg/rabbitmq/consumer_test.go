@@ -0,0 +1,200 @@
+package rabbitmq
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// consumerChannelStub records the topology declared on it and hands out deliveries
+// pushed onto its channel.
+type consumerChannelStub struct {
+	exchange    string
+	queue       string
+	bindings    []string
+	deliveries  chan amqp091.Delivery
+	consumeArgs struct{ autoAck bool }
+}
+
+func (c *consumerChannelStub) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error {
+	c.exchange = name
+	return nil
+}
+
+func (c *consumerChannelStub) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+	c.queue = name
+	return amqp091.Queue{Name: name}, nil
+}
+
+func (c *consumerChannelStub) QueueBind(name, key, exchange string, noWait bool, args amqp091.Table) error {
+	c.bindings = append(c.bindings, key)
+	return nil
+}
+
+func (c *consumerChannelStub) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+	c.consumeArgs.autoAck = autoAck
+	return c.deliveries, nil
+}
+
+func (c *consumerChannelStub) Close() error {
+	return nil
+}
+
+// ackRecorder records how each delivery tag was settled.
+type ackRecorder struct {
+	mu      sync.Mutex
+	results map[uint64]string
+	done    chan struct{}
+}
+
+func newAckRecorder() *ackRecorder {
+	return &ackRecorder{results: map[uint64]string{}, done: make(chan struct{}, 16)}
+}
+
+func (a *ackRecorder) settle(tag uint64, result string) error {
+	a.mu.Lock()
+	a.results[tag] = result
+	a.mu.Unlock()
+	a.done <- struct{}{}
+	return nil
+}
+
+func (a *ackRecorder) Ack(tag uint64, multiple bool) error {
+	return a.settle(tag, "ack")
+}
+
+func (a *ackRecorder) Nack(tag uint64, multiple, requeue bool) error {
+	if requeue {
+		return a.settle(tag, "requeue")
+	}
+	return a.settle(tag, "reject")
+}
+
+func (a *ackRecorder) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func (a *ackRecorder) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-a.done:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for delivery %d of %d to be settled", i+1, n)
+		}
+	}
+}
+
+func (a *ackRecorder) result(tag uint64) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.results[tag]
+}
+
+func TestConsumeWithBindings_DispatchesByRoutingKey(t *testing.T) {
+	ch := &consumerChannelStub{deliveries: make(chan amqp091.Delivery, 4)}
+	defer close(ch.deliveries)
+	consumer := &Consumer{ch: ch}
+
+	var mu sync.Mutex
+	calls := map[string][]string{}
+	handlerFor := func(name string) func([]byte) bool {
+		return func(body []byte) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[name] = append(calls[name], string(body))
+			return true
+		}
+	}
+
+	err := consumer.ConsumeWithBindings("transfa.events", "transaction_transfer_status", map[string]func([]byte) bool{
+		"transfer.status.nip.successful":  handlerFor("nip"),
+		"transfer.status.book.successful": handlerFor("book"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(ch.bindings)
+	if ch.exchange != "transfa.events" || ch.queue != "transaction_transfer_status" {
+		t.Fatalf("unexpected topology: exchange=%q queue=%q", ch.exchange, ch.queue)
+	}
+	if len(ch.bindings) != 2 || ch.bindings[0] != "transfer.status.book.successful" || ch.bindings[1] != "transfer.status.nip.successful" {
+		t.Fatalf("expected both routing keys to be bound, got %v", ch.bindings)
+	}
+	if ch.consumeArgs.autoAck {
+		t.Fatal("expected manual acknowledgement")
+	}
+
+	acks := newAckRecorder()
+	ch.deliveries <- amqp091.Delivery{Acknowledger: acks, DeliveryTag: 1, RoutingKey: "transfer.status.nip.successful", Body: []byte("nip-event")}
+	acks.wait(t, 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls["nip"]) != 1 || calls["nip"][0] != "nip-event" {
+		t.Fatalf("expected nip handler to receive the event, got %v", calls["nip"])
+	}
+	if len(calls["book"]) != 0 {
+		t.Fatalf("expected book handler not to be called, got %v", calls["book"])
+	}
+	if acks.result(1) != "ack" {
+		t.Fatalf("expected delivery to be acked, got %q", acks.result(1))
+	}
+}
+
+func TestConsumeWithBindings_SettlesFailedHandlers(t *testing.T) {
+	ch := &consumerChannelStub{deliveries: make(chan amqp091.Delivery, 4)}
+	defer close(ch.deliveries)
+	consumer := &Consumer{ch: ch}
+
+	err := consumer.ConsumeWithBindings("transfa.events", "transaction_transfer_status", map[string]func([]byte) bool{
+		"transfer.status.nip.failed":  func([]byte) bool { return false },
+		"transfer.status.book.failed": func([]byte) bool { panic("bad payload") },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acks := newAckRecorder()
+	ch.deliveries <- amqp091.Delivery{Acknowledger: acks, DeliveryTag: 1, RoutingKey: "transfer.status.nip.failed"}
+	ch.deliveries <- amqp091.Delivery{Acknowledger: acks, DeliveryTag: 2, RoutingKey: "transfer.status.book.failed"}
+	ch.deliveries <- amqp091.Delivery{Acknowledger: acks, DeliveryTag: 3, RoutingKey: "transfer.status.unknown"}
+	acks.wait(t, 3)
+
+	if got := acks.result(1); got != "requeue" {
+		t.Fatalf("expected handler returning false to requeue, got %q", got)
+	}
+	if got := acks.result(2); got != "reject" {
+		t.Fatalf("expected panicking handler to be rejected without requeue, got %q", got)
+	}
+	if got := acks.result(3); got != "ack" {
+		t.Fatalf("expected delivery without a handler to be acked, got %q", got)
+	}
+}
+
+func TestConsumeWithBindings_RequiresBindings(t *testing.T) {
+	consumer := &Consumer{ch: &consumerChannelStub{}}
+	if err := consumer.ConsumeWithBindings("transfa.events", "queue", nil); err == nil {
+		t.Fatal("expected an error without bindings")
+	}
+}
+
+func TestDeclareQueue_BindsRoutingKeys(t *testing.T) {
+	ch := &consumerChannelStub{}
+	consumer := &Consumer{ch: ch}
+
+	if err := consumer.DeclareQueue("customer_events", "platform_review_queue", "platform.review.requested"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ch.exchange != "customer_events" || ch.queue != "platform_review_queue" {
+		t.Fatalf("expected customer_events/platform_review_queue, got %s/%s", ch.exchange, ch.queue)
+	}
+	if len(ch.bindings) != 1 || ch.bindings[0] != "platform.review.requested" {
+		t.Fatalf("expected one binding, got %v", ch.bindings)
+	}
+}
@@ -0,0 +1,344 @@
+/**
+ * @description
+ * This package is the RabbitMQ client shared by the Transfa services. It provides an
+ * event producer for publishing JSON messages to topic exchanges and a consumer for
+ * reading them back off durable queues.
+ *
+ * @dependencies
+ * - context, encoding/json, sync, time: Standard Go libraries.
+ * - github.com/rabbitmq/amqp091-go: The RabbitMQ client library.
+ *
+ * @notes
+ * - Services that cannot reach RabbitMQ at startup can publish through
+ *   EventProducerFallback, which logs and drops every message.
+ * - SignedEventProducer adds an HMAC-SHA256 signature header to every message;
+ *   consumers check it with VerifySignedEvent or the WithSignedEvents option.
+ */
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// PlatformFeeEvent represents the payload published to RabbitMQ when a platform fee is debited.
+type PlatformFeeEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Amount    int64     `json:"amount"`
+	Reason    string    `json:"reason"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ErrPublishNacked is returned when the broker negatively acknowledges a message
+// published with confirms enabled.
+var ErrPublishNacked = errors.New("rabbitmq: publish was nacked by the broker")
+
+// publishRetryBackoff is the wait before each retry of a failed publish.
+var publishRetryBackoff = []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond}
+
+// amqpChannel is the part of *amqp091.Channel the producer uses.
+type amqpChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) error
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp091.Confirmation) chan amqp091.Confirmation
+	Close() error
+}
+
+// Publisher is the interface implemented by types that can publish events.
+type Publisher interface {
+	Publish(ctx context.Context, exchange, routingKey string, body interface{}) error
+	Close()
+}
+
+// PlatformFeePublisher is a Publisher that can also publish platform fee events.
+type PlatformFeePublisher interface {
+	Publisher
+	PublishPlatformFeeEvent(ctx context.Context, event PlatformFeeEvent) error
+}
+
+// ProducerOption configures an EventProducer.
+type ProducerOption func(*EventProducer)
+
+// WithPublisherConfirms puts the producer's channel into confirm mode. Publish then
+// waits for the broker to ack each message and treats a nack as a failed attempt.
+func WithPublisherConfirms() ProducerOption {
+	return func(p *EventProducer) {
+		p.confirmMode = true
+	}
+}
+
+// EventProducer holds the RabbitMQ connection and channel for publishing messages.
+type EventProducer struct {
+	conn *amqp091.Connection
+
+	mu          sync.Mutex
+	channel     amqpChannel
+	openChannel func() (amqpChannel, error)
+	backoff     []time.Duration
+
+	// Confirm mode state. publishMu keeps publish-and-wait pairs in delivery tag
+	// order; confirms and deliveryTag belong to the current channel.
+	confirmMode bool
+	publishMu   sync.Mutex
+	confirms    chan amqp091.Confirmation
+	deliveryTag uint64
+}
+
+// EventProducerFallback is a minimal no-op publisher used when RabbitMQ is unavailable at startup.
+type EventProducerFallback struct{}
+
+func (p *EventProducerFallback) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+	log.Printf("level=warn component=rabbitmq_producer mode=fallback msg=\"publish skipped\" exchange=%s routing_key=%s", exchange, routingKey)
+	return nil
+}
+
+func (p *EventProducerFallback) Close() {}
+
+func (p *EventProducerFallback) PublishPlatformFeeEvent(ctx context.Context, event PlatformFeeEvent) error {
+	log.Printf("level=warn component=rabbitmq_producer mode=fallback msg=\"platform fee event publish skipped\" user_id=%s", event.UserID)
+	return nil
+}
+
+// sanitizeAMQPURL strips whitespace, quotes and anything before the scheme from a
+// configured AMQP URL, and rejects URLs that are not amqp:// or amqps://.
+func sanitizeAMQPURL(raw string) (string, error) {
+	clean := strings.TrimSpace(raw)
+	clean = strings.Trim(clean, "\"'")
+	// If any stray characters precede the scheme, slice from first occurrence of amqp
+	idx := strings.Index(strings.ToLower(clean), "amqp")
+	if idx > 0 {
+		clean = clean[idx:]
+	}
+	u, err := url.Parse(clean)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "amqp" && u.Scheme != "amqps" {
+		return "", errors.New("AMQP scheme must be either 'amqp://' or 'amqps://'")
+	}
+	return clean, nil
+}
+
+// NewEventProducer creates and returns a new EventProducer.
+func NewEventProducer(amqpURL string, opts ...ProducerOption) (*EventProducer, error) {
+	cleanURL, err := sanitizeAMQPURL(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use a bounded dial timeout so startup does not hang indefinitely
+	conn, err := amqp091.DialConfig(cleanURL, amqp091.Config{Dial: amqp091.DefaultDial(10 * time.Second)})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &EventProducer{
+		conn: conn,
+		openChannel: func() (amqpChannel, error) {
+			return conn.Channel()
+		},
+		backoff: publishRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	ch, err := p.openChannel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := p.useChannel(ch); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Publish sends a persistent message to a durable topic exchange with a routing key.
+// A failed attempt is retried after each delay in publishRetryBackoff (100ms, 300ms,
+// 900ms). The channel is reopened before the first retry, and before later ones if
+// it was closed, since a broker error closes the channel it occurred on. Retries stop
+// early when ctx is done.
+func (p *EventProducer) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("level=error component=rabbitmq_producer msg=\"json marshal failed\" exchange=%s routing_key=%s err=%v", exchange, routingKey, err)
+		return err
+	}
+	return p.publish(ctx, exchange, routingKey, jsonBody, nil)
+}
+
+// publish sends an already marshalled JSON body with the given headers, retrying as
+// described on Publish.
+func (p *EventProducer) publish(ctx context.Context, exchange, routingKey string, jsonBody []byte, headers amqp091.Table) error {
+	msg := amqp091.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: amqp091.Persistent,
+		Timestamp:    time.Now(),
+		Body:         jsonBody,
+	}
+
+	err := p.publishOnce(ctx, exchange, routingKey, msg)
+	reopen := true
+	for attempt, delay := range p.backoff {
+		if err == nil {
+			return nil
+		}
+		log.Printf("level=warn component=rabbitmq_producer msg=\"publish failed; retrying\" exchange=%s routing_key=%s retry=%d backoff_ms=%d err=%v", exchange, routingKey, attempt+1, delay.Milliseconds(), err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		if reopen || errors.Is(err, amqp091.ErrClosed) {
+			if reopenErr := p.reopenChannel(); reopenErr != nil {
+				err = reopenErr
+				continue
+			}
+			reopen = false
+		}
+		err = p.publishOnce(ctx, exchange, routingKey, msg)
+	}
+	if err != nil {
+		log.Printf("level=error component=rabbitmq_producer msg=\"publish failed after retries\" exchange=%s routing_key=%s retries=%d err=%v", exchange, routingKey, len(p.backoff), err)
+	}
+	return err
+}
+
+func (p *EventProducer) publishOnce(ctx context.Context, exchange, routingKey string, msg amqp091.Publishing) error {
+	if p.confirmMode {
+		p.publishMu.Lock()
+		defer p.publishMu.Unlock()
+	}
+
+	p.mu.Lock()
+	ch := p.channel
+	confirms := p.confirms
+	p.mu.Unlock()
+	if ch == nil {
+		return amqp091.ErrClosed
+	}
+
+	// Ensure the exchange exists (durable topic)
+	if err := ch.ExchangeDeclare(
+		exchange, // name
+		"topic",  // type
+		true,     // durable
+		false,    // autoDelete
+		false,    // internal
+		false,    // noWait
+		nil,      // args
+	); err != nil {
+		return err
+	}
+	if err := ch.PublishWithContext(ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		msg,
+	); err != nil {
+		return err
+	}
+	if confirms == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.deliveryTag++
+	tag := p.deliveryTag
+	p.mu.Unlock()
+	return waitForConfirm(ctx, confirms, tag)
+}
+
+// waitForConfirm waits for the broker's confirmation of the message with the given
+// delivery tag. Confirmations for earlier tags, left over from publishes whose
+// context ended first, are skipped.
+func waitForConfirm(ctx context.Context, confirms <-chan amqp091.Confirmation, tag uint64) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case confirm, ok := <-confirms:
+			if !ok {
+				return amqp091.ErrClosed
+			}
+			if confirm.DeliveryTag < tag {
+				continue
+			}
+			if !confirm.Ack {
+				return ErrPublishNacked
+			}
+			return nil
+		}
+	}
+}
+
+// useChannel makes ch the producer's channel, putting it into confirm mode first
+// when confirms are enabled.
+func (p *EventProducer) useChannel(ch amqpChannel) error {
+	var confirms chan amqp091.Confirmation
+	if p.confirmMode {
+		if err := ch.Confirm(false); err != nil {
+			_ = ch.Close()
+			return err
+		}
+		confirms = ch.NotifyPublish(make(chan amqp091.Confirmation, 64))
+	}
+
+	p.mu.Lock()
+	previous := p.channel
+	p.channel = ch
+	p.confirms = confirms
+	p.deliveryTag = 0
+	p.mu.Unlock()
+	if previous != nil && previous != ch {
+		_ = previous.Close()
+	}
+	return nil
+}
+
+// reopenChannel replaces the producer's channel with a fresh one from the connection.
+func (p *EventProducer) reopenChannel() error {
+	if p.openChannel == nil {
+		return amqp091.ErrClosed
+	}
+	ch, err := p.openChannel()
+	if err != nil {
+		return err
+	}
+	return p.useChannel(ch)
+}
+
+// PublishPlatformFeeEvent publishes a platform fee event to the transaction_events exchange.
+func (p *EventProducer) PublishPlatformFeeEvent(ctx context.Context, event PlatformFeeEvent) error {
+	return p.Publish(ctx, "transaction_events", "platform.fee.debited", event)
+}
+
+// Close gracefully closes the channel and connection to RabbitMQ.
+func (p *EventProducer) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.channel != nil {
+		p.channel.Close()
+	}
+	if p.conn != nil {
+		p.conn.Close()
+	}
+}
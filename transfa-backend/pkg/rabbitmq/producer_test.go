@@ -0,0 +1,205 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// channelStub fails its first `failures` publishes and records when each attempt was made.
+// In confirm mode it acks each publish, or nacks the first `nacks` of them.
+type channelStub struct {
+	mu       sync.Mutex
+	failures int
+	attempts []time.Time
+	messages []amqp091.Publishing
+	closed   bool
+
+	nacks       int
+	confirming  bool
+	confirms    chan amqp091.Confirmation
+	deliveryTag uint64
+}
+
+func (c *channelStub) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error {
+	return nil
+}
+
+func (c *channelStub) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts = append(c.attempts, time.Now())
+	if len(c.attempts) <= c.failures {
+		return errors.New("connection blocked")
+	}
+	c.messages = append(c.messages, msg)
+	if c.confirms != nil {
+		c.deliveryTag++
+		c.confirms <- amqp091.Confirmation{DeliveryTag: c.deliveryTag, Ack: len(c.messages) > c.nacks}
+	}
+	return nil
+}
+
+func (c *channelStub) Confirm(noWait bool) error {
+	c.confirming = true
+	return nil
+}
+
+func (c *channelStub) NotifyPublish(confirm chan amqp091.Confirmation) chan amqp091.Confirmation {
+	c.confirms = confirm
+	return confirm
+}
+
+func (c *channelStub) Close() error {
+	c.closed = true
+	return nil
+}
+
+// newStubProducer returns a producer whose initial and reopened channels all share
+// one stub, so attempts are counted across reopens.
+func newStubProducer(ch *channelStub) (*EventProducer, *int) {
+	reopens := 0
+	return &EventProducer{
+		channel: ch,
+		openChannel: func() (amqpChannel, error) {
+			reopens++
+			return ch, nil
+		},
+		backoff: publishRetryBackoff,
+	}, &reopens
+}
+
+func TestPublish_RetriesWithBackoffUntilSuccess(t *testing.T) {
+	ch := &channelStub{failures: 3}
+	producer, reopens := newStubProducer(ch)
+
+	if err := producer.Publish(context.Background(), "transaction_events", "transfer.completed", map[string]string{"id": "tx-1"}); err != nil {
+		t.Fatalf("expected publish to succeed on the last retry, got %v", err)
+	}
+
+	if len(ch.attempts) != 4 {
+		t.Fatalf("expected 1 attempt and 3 retries, got %d attempts", len(ch.attempts))
+	}
+	for i, want := range publishRetryBackoff {
+		gap := ch.attempts[i+1].Sub(ch.attempts[i])
+		if gap < want*8/10 || gap > want*12/10 {
+			t.Fatalf("retry %d: expected backoff %s ±20%%, got %s", i+1, want, gap)
+		}
+	}
+	if *reopens != 1 {
+		t.Fatalf("expected the channel to be reopened once, on the first retry, got %d", *reopens)
+	}
+	if len(ch.messages) != 1 || ch.messages[0].DeliveryMode != amqp091.Persistent {
+		t.Fatalf("expected one persistent message, got %+v", ch.messages)
+	}
+}
+
+func TestPublish_ReturnsErrorAfterThreeRetries(t *testing.T) {
+	producer, _ := newStubProducer(&channelStub{failures: 10})
+	producer.backoff = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+
+	err := producer.Publish(context.Background(), "transaction_events", "transfer.completed", "payload")
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if got := len(producer.channel.(*channelStub).attempts); got != 4 {
+		t.Fatalf("expected 4 attempts, got %d", got)
+	}
+}
+
+func TestPublish_StopsRetryingWhenContextIsCancelled(t *testing.T) {
+	ch := &channelStub{failures: 10}
+	producer, _ := newStubProducer(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := producer.Publish(ctx, "transaction_events", "transfer.completed", "payload")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Fatalf("expected retries to stop with the context, took %s", elapsed)
+	}
+	// Initial attempt plus the retry after 100ms; the 300ms wait is cut short.
+	if len(ch.attempts) != 2 {
+		t.Fatalf("expected 2 attempts before cancellation, got %d", len(ch.attempts))
+	}
+}
+
+func TestPublish_KeepsReopeningWhileChannelCannotBeOpened(t *testing.T) {
+	ch := &channelStub{failures: 1}
+	opens := 0
+	producer := &EventProducer{
+		channel: ch,
+		openChannel: func() (amqpChannel, error) {
+			opens++
+			if opens == 1 {
+				return nil, amqp091.ErrClosed
+			}
+			return ch, nil
+		},
+		backoff: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond},
+	}
+
+	if err := producer.Publish(context.Background(), "transaction_events", "transfer.completed", "payload"); err != nil {
+		t.Fatalf("expected publish to succeed once the channel reopens, got %v", err)
+	}
+	if opens != 2 || len(ch.attempts) != 2 {
+		t.Fatalf("expected 2 reopen attempts and 2 publishes, got %d and %d", opens, len(ch.attempts))
+	}
+}
+
+func TestPublish_WithConfirmsWaitsForAck(t *testing.T) {
+	ch := &channelStub{}
+	producer, _ := newStubProducer(ch)
+	WithPublisherConfirms()(producer)
+	if err := producer.useChannel(ch); err != nil {
+		t.Fatalf("enable confirms: %v", err)
+	}
+
+	if err := producer.Publish(context.Background(), "transaction_events", "transfer.completed", "payload"); err != nil {
+		t.Fatalf("expected an acked publish to succeed, got %v", err)
+	}
+	if !ch.confirming || len(ch.attempts) != 1 {
+		t.Fatalf("expected one publish on a confirming channel, got %d attempts (confirming: %v)", len(ch.attempts), ch.confirming)
+	}
+}
+
+func TestPublish_WithConfirmsRetriesNackedMessage(t *testing.T) {
+	ch := &channelStub{nacks: 1}
+	producer, reopens := newStubProducer(ch)
+	producer.backoff = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	WithPublisherConfirms()(producer)
+	if err := producer.useChannel(ch); err != nil {
+		t.Fatalf("enable confirms: %v", err)
+	}
+
+	if err := producer.Publish(context.Background(), "transaction_events", "transfer.completed", "payload"); err != nil {
+		t.Fatalf("expected the retry to be acked, got %v", err)
+	}
+	if len(ch.attempts) != 2 || *reopens != 1 {
+		t.Fatalf("expected the nacked message to be republished on a reopened channel, got %d attempts and %d reopens", len(ch.attempts), *reopens)
+	}
+}
+
+func TestWaitForConfirm_SkipsStaleConfirmations(t *testing.T) {
+	confirms := make(chan amqp091.Confirmation, 2)
+	confirms <- amqp091.Confirmation{DeliveryTag: 1, Ack: false}
+	confirms <- amqp091.Confirmation{DeliveryTag: 2, Ack: true}
+
+	if err := waitForConfirm(context.Background(), confirms, 2); err != nil {
+		t.Fatalf("expected the confirmation for tag 2 to be used, got %v", err)
+	}
+
+	nacked := make(chan amqp091.Confirmation, 1)
+	nacked <- amqp091.Confirmation{DeliveryTag: 1, Ack: false}
+	if err := waitForConfirm(context.Background(), nacked, 1); !errors.Is(err, ErrPublishNacked) {
+		t.Fatalf("expected ErrPublishNacked, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// publishSigned publishes body through a SignedEventProducer backed by a stub channel
+// and returns the message the channel received.
+func publishSigned(t *testing.T, body interface{}, secrets ...string) amqp091.Publishing {
+	t.Helper()
+	ch := &channelStub{}
+	producer, _ := newStubProducer(ch)
+	signed, err := NewSignedEventProducer(producer, secrets...)
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %v", err)
+	}
+	if err := signed.Publish(context.Background(), "customer_events", "customer.tier.status", body); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+	if len(ch.messages) != 1 {
+		t.Fatalf("expected one published message, got %d", len(ch.messages))
+	}
+	return ch.messages[0]
+}
+
+func TestVerifySignedEvent_ValidSignature(t *testing.T) {
+	msg := publishSigned(t, map[string]string{"anchor_customer_id": "cust-1", "status": "completed"}, "current-secret")
+
+	if !VerifySignedEvent(msg.Body, msg.Headers, "current-secret") {
+		t.Fatalf("expected signature to verify, headers=%v", msg.Headers)
+	}
+	if VerifySignedEvent(msg.Body, msg.Headers, "other-secret") {
+		t.Fatalf("expected signature not to verify under a different secret")
+	}
+}
+
+func TestVerifySignedEvent_TamperedBody(t *testing.T) {
+	msg := publishSigned(t, map[string]string{"anchor_customer_id": "cust-1", "status": "rejected"}, "current-secret")
+	tampered := []byte(`{"anchor_customer_id":"cust-1","status":"completed"}`)
+
+	if VerifySignedEvent(tampered, msg.Headers, "current-secret") {
+		t.Fatalf("expected tampered body to fail verification")
+	}
+}
+
+func TestVerifySignedEvent_MissingHeader(t *testing.T) {
+	body := []byte(`{"anchor_customer_id":"cust-1"}`)
+
+	cases := map[string]amqp091.Table{
+		"nil headers":     nil,
+		"no signature":    {"x-other": "value"},
+		"empty signature": {SignatureHeader: ""},
+		"wrong type":      {SignatureHeader: int32(1)},
+		"malformed":       {SignatureHeader: "sha256=not-hex"},
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			if VerifySignedEvent(body, headers, "current-secret") {
+				t.Fatalf("expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestVerifySignedEvent_KeyRotation(t *testing.T) {
+	body := map[string]string{"anchor_customer_id": "cust-1"}
+
+	// During rotation the producer signs with the new and the previous secret, so
+	// consumers on either secret accept the message.
+	rotating := publishSigned(t, body, "new-secret", "old-secret")
+	for _, secret := range []string{"new-secret", "old-secret"} {
+		if !VerifySignedEvent(rotating.Body, rotating.Headers, secret) {
+			t.Fatalf("expected message signed during rotation to verify under %q", secret)
+		}
+	}
+
+	// Once the previous secret is dropped, consumers still on it reject new messages.
+	rotated := publishSigned(t, body, "new-secret")
+	if VerifySignedEvent(rotated.Body, rotated.Headers, "old-secret") {
+		t.Fatalf("expected message signed only with the new secret to fail under the old one")
+	}
+
+	// A consumer configured with both secrets accepts messages from a producer that
+	// has not rotated yet.
+	consumer := &Consumer{}
+	WithSignedEvents(ParseSigningSecrets("new-secret, old-secret"), "customer.tier.status")(consumer)
+	legacy := publishSigned(t, body, "old-secret")
+	delivery := amqp091.Delivery{RoutingKey: "customer.tier.status", Headers: legacy.Headers, Body: legacy.Body}
+	if !consumer.verified(delivery) {
+		t.Fatalf("expected consumer holding the old secret to accept a message signed with it")
+	}
+}
+
+func TestConsumerVerified_OnlyChecksSignedRoutingKeys(t *testing.T) {
+	consumer := &Consumer{}
+	WithSignedEvents([]string{"current-secret"}, "account.lifecycle")(consumer)
+	body := []byte(`{"anchor_customer_id":"cust-1"}`)
+
+	if consumer.verified(amqp091.Delivery{RoutingKey: "account.lifecycle", Body: body}) {
+		t.Fatalf("expected unsigned delivery on a signed routing key to be rejected")
+	}
+	if !consumer.verified(amqp091.Delivery{RoutingKey: "customer.verified", Body: body}) {
+		t.Fatalf("expected delivery on an unsigned routing key to pass through")
+	}
+}
+
+func TestNewSignedEventProducer_RequiresSecret(t *testing.T) {
+	producer, _ := newStubProducer(&channelStub{})
+	if _, err := NewSignedEventProducer(producer); err == nil {
+		t.Fatalf("expected an error without secrets")
+	}
+}
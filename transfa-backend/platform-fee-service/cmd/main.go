@@ -11,12 +11,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 	"github.com/transfa/platform-fee-service/internal/api"
 	"github.com/transfa/platform-fee-service/internal/app"
 	"github.com/transfa/platform-fee-service/internal/config"
@@ -49,10 +51,10 @@ func main() {
 		logger.Error("unable to parse database URL", "error", err)
 		os.Exit(1)
 	}
-	pgConfig.MaxConns = 100
-	pgConfig.MinConns = 20
-	pgConfig.MaxConnLifetime = 30 * time.Minute
-	pgConfig.MaxConnIdleTime = 5 * time.Minute
+	pgConfig.MaxConns = cfg.DatabasePool.MaxConns
+	pgConfig.MinConns = cfg.DatabasePool.MinConns
+	pgConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	pgConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 	pgConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
 
 	dbpool, err := pgxpool.NewWithConfig(ctx, pgConfig)
@@ -76,9 +78,14 @@ func main() {
 		}
 	}
 
-	service := app.NewService(repository, txClient, publisher, cfg.BusinessTimezone)
+	service := app.NewService(repository, txClient, publisher, cfg.BusinessTimezone, cfg.MinChargeKobo)
 	handler := api.NewHandler(service)
-	router := api.NewRouter(handler, cfg.ClerkJWKSURL, cfg.InternalAPIKey)
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		logger.Error("invalid ALLOWED_ORIGINS", "error", err)
+		os.Exit(1)
+	}
+	router := api.NewRouter(handler, cfg.ClerkJWKSURL, cfg.InternalAPIKey, allowedOrigins)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.ServerPort),
@@ -4,11 +4,11 @@ go 1.24
 
 require (
 	github.com/go-chi/chi/v5 v5.0.12
-	github.com/go-chi/cors v1.2.2
 	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/jackc/pgx/v5 v5.5.5
 	github.com/rabbitmq/amqp091-go v1.10.0
 	github.com/spf13/viper v1.18.2
+	github.com/transfa/pkg v0.0.0
 )
 
 require (
@@ -37,3 +37,5 @@ require (
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/transfa/pkg => ../pkg
@@ -5,12 +5,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/transfa/platform-fee-service/internal/app"
+	"github.com/transfa/platform-fee-service/internal/domain"
+	"github.com/transfa/platform-fee-service/internal/store"
 )
 
 // Handler holds the application service that handlers will interact with.
@@ -57,6 +63,39 @@ func (h *Handler) handleListInvoices(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, invoices)
 }
 
+// handleGetInvoicePDF returns one of the user's invoices as a PDF download. Another
+// user's invoice is reported as not found so invoice IDs cannot be probed.
+func (h *Handler) handleGetInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	invoiceID := chi.URLParam(r, "id")
+	if invoiceID == "" {
+		http.Error(w, "Invoice ID is required", http.StatusBadRequest)
+		return
+	}
+
+	document, err := h.service.GetInvoicePDF(r.Context(), userID, invoiceID)
+	if err != nil {
+		if errors.Is(err, store.ErrInvoiceNotFound) || errors.Is(err, app.ErrInvoiceNotOwned) {
+			http.Error(w, "Invoice not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error rendering platform fee invoice %s for user %s: %v", invoiceID, userID, err)
+		http.Error(w, "Failed to generate invoice", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="invoice_%s.pdf"`, invoiceID))
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
 func (h *Handler) handleGenerateInvoices(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.GenerateMonthlyInvoices(r.Context())
 	if err != nil {
@@ -107,6 +146,70 @@ func (h *Handler) handleChargeInvoice(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, result)
 }
 
+// InvoiceAdjustmentRequest defines the request payload for waiving or adjusting an invoice.
+type InvoiceAdjustmentRequest struct {
+	Amount *int64 `json:"amount,omitempty"`
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"`
+}
+
+func (h *Handler) handleWaiveInvoice(w http.ResponseWriter, r *http.Request) {
+	h.adjustInvoice(w, r, domain.InvoiceAdjustmentWaive, h.service.WaiveInvoice)
+}
+
+func (h *Handler) handleAdjustInvoice(w http.ResponseWriter, r *http.Request) {
+	h.adjustInvoice(w, r, domain.InvoiceAdjustmentAmount, h.service.AdjustInvoice)
+}
+
+func (h *Handler) adjustInvoice(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	apply func(context.Context, app.InvoiceAdjustmentInput) (*app.InvoiceAdjustmentResult, error),
+) {
+	invoiceID := chi.URLParam(r, "id")
+	if invoiceID == "" {
+		http.Error(w, "Invoice ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req InvoiceAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input := app.InvoiceAdjustmentInput{InvoiceID: invoiceID, Reason: req.Reason, Actor: req.Actor}
+	if action == domain.InvoiceAdjustmentAmount {
+		if req.Amount == nil {
+			http.Error(w, "amount is required", http.StatusBadRequest)
+			return
+		}
+		input.Amount = *req.Amount
+	}
+
+	result, err := apply(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidAdjustmentReason),
+			errors.Is(err, app.ErrInvalidAdjustmentActor),
+			errors.Is(err, app.ErrInvalidAdjustmentAmount):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, store.ErrInvoiceNotFound):
+			http.Error(w, "Invoice not found", http.StatusNotFound)
+		case errors.Is(err, store.ErrInvoiceNotWaivable), errors.Is(err, store.ErrInvoiceNotAdjustable):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("Error applying %s to invoice %s: %v", action, invoiceID, err)
+			http.Error(w, "Failed to update invoice", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Printf("Platform fee invoice %s: %s applied by %q (amount %d -> %d)", invoiceID, action, result.Adjustment.Actor, result.Adjustment.PreviousAmount, result.Adjustment.NewAmount)
+	respondWithJSON(w, http.StatusOK, result)
+}
+
 func (h *Handler) handleGetUserStatusInternal(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
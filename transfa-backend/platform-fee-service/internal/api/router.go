@@ -10,24 +10,16 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 )
 
 // NewRouter creates a new Chi router and registers platform-fee routes.
-func NewRouter(h *Handler, jwksURL string, internalKey string) *chi.Mux {
+func NewRouter(h *Handler, jwksURL string, internalKey string, allowedOrigins []string) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Internal-API-Key"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	r.Use(sharedmiddleware.CORS(allowedOrigins))
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Platform fee service is healthy"))
@@ -39,13 +31,19 @@ func NewRouter(h *Handler, jwksURL string, internalKey string) *chi.Mux {
 		r.Post("/attempts/run", h.handleRunChargeAttempts)
 		r.Post("/delinquency/run", h.handleMarkDelinquent)
 		r.Post("/invoices/{id}/charge", h.handleChargeInvoice)
+		r.Post("/invoices/{id}/waive", h.handleWaiveInvoice)
+		r.Post("/invoices/{id}/adjust", h.handleAdjustInvoice)
 		r.Get("/users/{userID}/status", h.handleGetUserStatusInternal)
 	})
 
+	// Only user-facing routes are bounded; the internal invoice, charge and
+	// delinquency runs are batch jobs the scheduler waits on.
 	r.Group(func(r chi.Router) {
+		r.Use(sharedmiddleware.RequestTimeout(60 * time.Second))
 		r.Use(ClerkAuthMiddleware(jwksURL))
 		r.Get("/platform-fees/status", h.handleGetStatus)
 		r.Get("/platform-fees/invoices", h.handleListInvoices)
+		r.Get("/platform-fees/invoices/{id}/pdf", h.handleGetInvoicePDF)
 	})
 
 	return r
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/transfa/platform-fee-service/internal/domain"
+)
+
+const (
+	maxAdjustmentReasonLen = 500
+	maxAdjustmentActorLen  = 255
+)
+
+var (
+	ErrInvalidAdjustmentReason = errors.New("reason is required and cannot exceed 500 characters")
+	ErrInvalidAdjustmentActor  = errors.New("actor is required and cannot exceed 255 characters")
+	ErrInvalidAdjustmentAmount = errors.New("amount cannot be negative")
+)
+
+// InvoiceAdjustmentInput defines the input for waiving or adjusting an invoice.
+type InvoiceAdjustmentInput struct {
+	InvoiceID string
+	Amount    int64 // New amount in kobo; ignored when waiving.
+	Reason    string
+	Actor     string
+}
+
+// InvoiceAdjustmentResult is the invoice after a waiver or adjustment and its audit record.
+type InvoiceAdjustmentResult struct {
+	Invoice    domain.PlatformFeeInvoice           `json:"invoice"`
+	Adjustment domain.PlatformFeeInvoiceAdjustment `json:"adjustment"`
+}
+
+// WaiveInvoice waives an unpaid invoice so it is never charged.
+func (s Service) WaiveInvoice(ctx context.Context, input InvoiceAdjustmentInput) (*InvoiceAdjustmentResult, error) {
+	reason, actor, err := validateAdjustment(input)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, adjustment, err := s.repo.WaiveInvoice(ctx, strings.TrimSpace(input.InvoiceID), reason, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, "platform_fee.waived", *invoice, nil)
+	return &InvoiceAdjustmentResult{Invoice: *invoice, Adjustment: *adjustment}, nil
+}
+
+// AdjustInvoice corrects the amount of a pending or failed invoice. An invoice
+// adjusted to zero is treated as settled and is never charged.
+func (s Service) AdjustInvoice(ctx context.Context, input InvoiceAdjustmentInput) (*InvoiceAdjustmentResult, error) {
+	if input.Amount < 0 {
+		return nil, ErrInvalidAdjustmentAmount
+	}
+	reason, actor, err := validateAdjustment(input)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, adjustment, err := s.repo.AdjustInvoiceAmount(ctx, strings.TrimSpace(input.InvoiceID), input.Amount, reason, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, "platform_fee.adjusted", *invoice, nil)
+	return &InvoiceAdjustmentResult{Invoice: *invoice, Adjustment: *adjustment}, nil
+}
+
+func validateAdjustment(input InvoiceAdjustmentInput) (string, string, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxAdjustmentReasonLen {
+		return "", "", ErrInvalidAdjustmentReason
+	}
+	actor := strings.TrimSpace(input.Actor)
+	if actor == "" || utf8.RuneCountInString(actor) > maxAdjustmentActorLen {
+		return "", "", ErrInvalidAdjustmentActor
+	}
+	return reason, actor, nil
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/transfa/platform-fee-service/internal/domain"
+)
+
+// adjustmentRepoStub serves a single invoice and records waivers and adjustments.
+type adjustmentRepoStub struct {
+	Repository
+
+	invoice domain.PlatformFeeInvoice
+	changes int
+}
+
+func (s *adjustmentRepoStub) GetInvoiceByID(ctx context.Context, invoiceID string) (*domain.PlatformFeeInvoice, error) {
+	invoice := s.invoice
+	return &invoice, nil
+}
+
+func (s *adjustmentRepoStub) GetLatestInvoiceByUserID(ctx context.Context, userID string) (*domain.PlatformFeeInvoice, error) {
+	invoice := s.invoice
+	return &invoice, nil
+}
+
+func (s *adjustmentRepoStub) HasSuccessfulAttempt(ctx context.Context, invoiceID string) (bool, error) {
+	return false, nil
+}
+
+func (s *adjustmentRepoStub) WaiveInvoice(ctx context.Context, invoiceID, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error) {
+	s.changes++
+	s.invoice.Status = "waived"
+	invoice := s.invoice
+	return &invoice, &domain.PlatformFeeInvoiceAdjustment{InvoiceID: invoiceID, Action: domain.InvoiceAdjustmentWaive, Reason: reason, Actor: actor}, nil
+}
+
+func (s *adjustmentRepoStub) AdjustInvoiceAmount(ctx context.Context, invoiceID string, newAmount int64, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error) {
+	s.changes++
+	previous := s.invoice.Amount
+	s.invoice.Amount = newAmount
+	invoice := s.invoice
+	return &invoice, &domain.PlatformFeeInvoiceAdjustment{InvoiceID: invoiceID, Action: domain.InvoiceAdjustmentAmount, PreviousAmount: previous, NewAmount: newAmount, Reason: reason, Actor: actor}, nil
+}
+
+type countingTransactionClient struct {
+	debits int
+}
+
+func (c *countingTransactionClient) DebitPlatformFee(ctx context.Context, userID string, amount int64, invoiceID string) (string, error) {
+	c.debits++
+	return "tx-1", nil
+}
+
+type recordingPublisher struct {
+	routingKeys []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+	p.routingKeys = append(p.routingKeys, routingKey)
+	return nil
+}
+
+func newAdjustmentTestService(status string, amount int64) (Service, *adjustmentRepoStub, *countingTransactionClient, *recordingPublisher) {
+	now := time.Now().UTC()
+	repo := &adjustmentRepoStub{invoice: domain.PlatformFeeInvoice{
+		ID:         "inv-1",
+		UserID:     "user-1",
+		Amount:     amount,
+		Currency:   "NGN",
+		Status:     status,
+		DueAt:      now.Add(-time.Hour),
+		GraceUntil: now.Add(7 * 24 * time.Hour),
+	}}
+	txClient := &countingTransactionClient{}
+	publisher := &recordingPublisher{}
+	return NewService(repo, txClient, publisher, "UTC", 0), repo, txClient, publisher
+}
+
+func TestWaiveInvoice_PublishesWaivedEvent(t *testing.T) {
+	svc, repo, _, publisher := newAdjustmentTestService("failed", 50000)
+
+	result, err := svc.WaiveInvoice(context.Background(), InvoiceAdjustmentInput{InvoiceID: "inv-1", Reason: " comped after outage ", Actor: "support@transfa"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Invoice.Status != "waived" {
+		t.Fatalf("expected waived invoice, got %q", result.Invoice.Status)
+	}
+	if result.Adjustment.Reason != "comped after outage" {
+		t.Fatalf("expected trimmed reason, got %q", result.Adjustment.Reason)
+	}
+	if repo.changes != 1 {
+		t.Fatalf("expected 1 waiver, got %d", repo.changes)
+	}
+	if len(publisher.routingKeys) != 1 || publisher.routingKeys[0] != "platform_fee.waived" {
+		t.Fatalf("expected platform_fee.waived event, got %v", publisher.routingKeys)
+	}
+}
+
+func TestWaiveInvoice_RequiresReasonAndActor(t *testing.T) {
+	svc, repo, _, _ := newAdjustmentTestService("pending", 50000)
+
+	if _, err := svc.WaiveInvoice(context.Background(), InvoiceAdjustmentInput{InvoiceID: "inv-1", Reason: "  ", Actor: "support@transfa"}); err != ErrInvalidAdjustmentReason {
+		t.Fatalf("expected ErrInvalidAdjustmentReason, got %v", err)
+	}
+	if _, err := svc.WaiveInvoice(context.Background(), InvoiceAdjustmentInput{InvoiceID: "inv-1", Reason: "comped"}); err != ErrInvalidAdjustmentActor {
+		t.Fatalf("expected ErrInvalidAdjustmentActor, got %v", err)
+	}
+	if repo.changes != 0 {
+		t.Fatalf("expected invalid requests not to reach the repository, got %d changes", repo.changes)
+	}
+}
+
+func TestAdjustInvoice_RejectsNegativeAmount(t *testing.T) {
+	svc, repo, _, _ := newAdjustmentTestService("pending", 50000)
+
+	if _, err := svc.AdjustInvoice(context.Background(), InvoiceAdjustmentInput{InvoiceID: "inv-1", Amount: -1, Reason: "typo", Actor: "support@transfa"}); err != ErrInvalidAdjustmentAmount {
+		t.Fatalf("expected ErrInvalidAdjustmentAmount, got %v", err)
+	}
+	if repo.changes != 0 {
+		t.Fatalf("expected no adjustment, got %d", repo.changes)
+	}
+}
+
+func TestChargeInvoice_NeverChargesWaivedOrZeroInvoices(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		amount int64
+	}{
+		{name: "waived", status: "waived", amount: 50000},
+		{name: "adjusted to zero", status: "failed", amount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, txClient, publisher := newAdjustmentTestService(tt.status, tt.amount)
+
+			if _, err := svc.ChargeInvoice(context.Background(), "inv-1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if txClient.debits != 0 {
+				t.Fatalf("expected no debit, got %d", txClient.debits)
+			}
+			if len(publisher.routingKeys) != 0 {
+				t.Fatalf("expected no events, got %v", publisher.routingKeys)
+			}
+		})
+	}
+}
+
+func TestGetStatusByUserID_ZeroAmountInvoiceIsInGoodStanding(t *testing.T) {
+	svc, repo, _, _ := newAdjustmentTestService("failed", 0)
+	repo.invoice.GraceUntil = time.Now().UTC().Add(-time.Hour)
+
+	status, err := svc.GetStatusByUserID(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.IsDelinquent || !status.IsWithinGrace {
+		t.Fatalf("expected good standing, got delinquent=%v within_grace=%v", status.IsDelinquent, status.IsWithinGrace)
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transfa/pkg/pdf"
+	"github.com/transfa/platform-fee-service/internal/domain"
+)
+
+// ErrInvoiceNotOwned is returned when a user asks for an invoice billed to someone else.
+var ErrInvoiceNotOwned = errors.New("invoice does not belong to user")
+
+// GetInvoicePDF renders one of the user's invoices as a PDF with the billing period,
+// amount, payment date and the user's details.
+func (s Service) GetInvoicePDF(ctx context.Context, clerkUserID, invoiceID string) ([]byte, error) {
+	internalID, err := s.repo.FindUserIDByClerkUserID(ctx, clerkUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.repo.GetInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.UserID != internalID {
+		return nil, ErrInvoiceNotOwned
+	}
+
+	if invoice.Status == "pending" || invoice.Status == "failed" || invoice.Status == "delinquent" {
+		ok, err := s.repo.HasSuccessfulAttempt(ctx, invoice.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			invoice.Status = "paid"
+		}
+	}
+
+	profile, err := s.repo.GetBillingProfile(ctx, internalID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.Invoice{
+		Number:           invoiceNumber(*invoice, s.loc),
+		Status:           invoice.Status,
+		Currency:         invoice.Currency,
+		BaseAmount:       invoice.BaseAmount,
+		Amount:           invoice.Amount,
+		ProrationFactor:  invoice.ProrationFactor,
+		PeriodStart:      invoice.PeriodStart.In(s.loc),
+		PeriodEnd:        invoice.PeriodEnd.In(s.loc),
+		DueAt:            invoice.DueAt.In(s.loc),
+		GeneratedAt:      time.Now().In(s.loc),
+		CustomerName:     profile.FullName,
+		CustomerUsername: profile.Username,
+		CustomerEmail:    profile.Email,
+	}
+	if invoice.PaidAt != nil {
+		paidAt := invoice.PaidAt.In(s.loc)
+		doc.PaidAt = &paidAt
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Render(&buf); err != nil {
+		return nil, fmt.Errorf("render invoice %s: %w", invoice.ID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// invoiceNumber builds a human-readable invoice number from the billing month and
+// the start of the invoice ID, e.g. "INV-202602-1A2B3C4D".
+func invoiceNumber(invoice domain.PlatformFeeInvoice, loc *time.Location) string {
+	id := strings.ToUpper(strings.ReplaceAll(invoice.ID, "-", ""))
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("INV-%s-%s", invoice.PeriodStart.In(loc).Format("200601"), id)
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/transfa/platform-fee-service/internal/domain"
+	"github.com/transfa/platform-fee-service/internal/store"
+)
+
+// invoicePDFRepoStub serves one invoice, owned by the user mapped to "clerk_owner".
+type invoicePDFRepoStub struct {
+	Repository
+
+	invoice         domain.PlatformFeeInvoice
+	paidByAttempt   bool
+	profileLookups  int
+	clerkToInternal map[string]string
+}
+
+func (s *invoicePDFRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	id, ok := s.clerkToInternal[clerkUserID]
+	if !ok {
+		return "", store.ErrUserNotFound
+	}
+	return id, nil
+}
+
+func (s *invoicePDFRepoStub) GetInvoiceByID(ctx context.Context, invoiceID string) (*domain.PlatformFeeInvoice, error) {
+	if invoiceID != s.invoice.ID {
+		return nil, store.ErrInvoiceNotFound
+	}
+	invoice := s.invoice
+	return &invoice, nil
+}
+
+func (s *invoicePDFRepoStub) HasSuccessfulAttempt(ctx context.Context, invoiceID string) (bool, error) {
+	return s.paidByAttempt, nil
+}
+
+func (s *invoicePDFRepoStub) GetBillingProfile(ctx context.Context, userID string) (*domain.BillingProfile, error) {
+	s.profileLookups++
+	return &domain.BillingProfile{UserID: userID, FullName: "Ada Obi", Username: "ada", Email: "ada@example.com"}, nil
+}
+
+func newInvoicePDFTestService() (Service, *invoicePDFRepoStub) {
+	periodStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2026, time.March, 3, 9, 30, 0, 0, time.UTC)
+	repo := &invoicePDFRepoStub{
+		invoice: domain.PlatformFeeInvoice{
+			ID:              "1a2b3c4d-0000-4000-8000-000000000001",
+			UserID:          "user-1",
+			PeriodStart:     periodStart,
+			PeriodEnd:       periodStart.AddDate(0, 1, 0).Add(-time.Second),
+			DueAt:           periodStart.AddDate(0, 1, 0),
+			GraceUntil:      periodStart.AddDate(0, 1, 7),
+			BaseAmount:      50000,
+			ProrationFactor: 1,
+			Amount:          50000,
+			Currency:        "NGN",
+			Status:          "paid",
+			PaidAt:          &paidAt,
+		},
+		clerkToInternal: map[string]string{"clerk_owner": "user-1", "clerk_other": "user-2"},
+	}
+	return NewService(repo, nil, nil, "UTC", 0), repo
+}
+
+func TestGetInvoicePDF_RendersInvoiceDetails(t *testing.T) {
+	svc, _ := newInvoicePDFTestService()
+
+	document, err := svc.GetInvoicePDF(context.Background(), "clerk_owner", "1a2b3c4d-0000-4000-8000-000000000001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4\n")) {
+		t.Fatal("expected a PDF document")
+	}
+	for _, want := range []string{
+		"(INV-202602-1A2B3C4D)",
+		"(01 Feb 2026 - 28 Feb 2026)",
+		"(03 Mar 2026 09:30 UTC)",
+		"(NGN 500.00)",
+		"(Ada Obi)",
+		"(ada@example.com)",
+	} {
+		if !bytes.Contains(document, []byte(want)) {
+			t.Fatalf("expected invoice PDF to contain %s", want)
+		}
+	}
+}
+
+func TestGetInvoicePDF_TreatsSuccessfulAttemptAsPaid(t *testing.T) {
+	svc, repo := newInvoicePDFTestService()
+	repo.invoice.Status = "failed"
+	repo.invoice.PaidAt = nil
+	repo.paidByAttempt = true
+
+	document, err := svc.GetInvoicePDF(context.Background(), "clerk_owner", repo.invoice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(document, []byte("(PAID)")) {
+		t.Fatal("expected an invoice with a successful charge attempt to be shown as paid")
+	}
+}
+
+func TestGetInvoicePDF_RejectsOtherUsersInvoice(t *testing.T) {
+	svc, repo := newInvoicePDFTestService()
+
+	_, err := svc.GetInvoicePDF(context.Background(), "clerk_other", repo.invoice.ID)
+	if !errors.Is(err, ErrInvoiceNotOwned) {
+		t.Fatalf("expected ErrInvoiceNotOwned, got %v", err)
+	}
+	if repo.profileLookups != 0 {
+		t.Fatalf("expected no billing profile lookup for another user's invoice, got %d", repo.profileLookups)
+	}
+}
+
+func TestGetInvoicePDF_InvoiceNotFound(t *testing.T) {
+	svc, _ := newInvoicePDFTestService()
+
+	_, err := svc.GetInvoicePDF(context.Background(), "clerk_owner", "9f9f9f9f-0000-4000-8000-000000000009")
+	if !errors.Is(err, store.ErrInvoiceNotFound) {
+		t.Fatalf("expected store.ErrInvoiceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/transfa/platform-fee-service/internal/domain"
+)
+
+// prorationRepoStub records invoice generation arguments and serves a single
+// prorated invoice through the charge path.
+type prorationRepoStub struct {
+	Repository
+
+	periodStart   time.Time
+	periodEnd     time.Time
+	timezone      string
+	minChargeKobo int64
+
+	invoice  domain.PlatformFeeInvoice
+	attempts []int64
+}
+
+func (s *prorationRepoStub) GenerateInvoicesForPeriod(ctx context.Context, periodStart, periodEnd, dueAt, graceUntil time.Time, timezone string, minChargeKobo int64) ([]domain.PlatformFeeInvoice, error) {
+	s.periodStart, s.periodEnd = periodStart, periodEnd
+	s.timezone, s.minChargeKobo = timezone, minChargeKobo
+	return []domain.PlatformFeeInvoice{s.invoice}, nil
+}
+
+func (s *prorationRepoStub) GetInvoiceByID(ctx context.Context, invoiceID string) (*domain.PlatformFeeInvoice, error) {
+	invoice := s.invoice
+	return &invoice, nil
+}
+
+func (s *prorationRepoStub) ClaimInvoiceAttempt(ctx context.Context, invoiceID string, attemptAt time.Time, attemptWindowStart time.Time) (*domain.PlatformFeeInvoice, error) {
+	invoice := s.invoice
+	return &invoice, nil
+}
+
+func (s *prorationRepoStub) InsertAttempt(ctx context.Context, invoiceID string, amount int64, status string, failureReason, providerRef *string) error {
+	s.attempts = append(s.attempts, amount)
+	return nil
+}
+
+func (s *prorationRepoStub) MarkInvoicePaid(ctx context.Context, invoiceID string, paidAt time.Time) error {
+	s.invoice.Status = "paid"
+	return nil
+}
+
+type amountRecordingTransactionClient struct {
+	amounts []int64
+}
+
+func (c *amountRecordingTransactionClient) DebitPlatformFee(ctx context.Context, userID string, amount int64, invoiceID string) (string, error) {
+	c.amounts = append(c.amounts, amount)
+	return "tx-1", nil
+}
+
+func TestGenerateMonthlyInvoices_PassesBusinessDatesAndMinimumCharge(t *testing.T) {
+	repo := &prorationRepoStub{}
+	svc := NewService(repo, &amountRecordingTransactionClient{}, &recordingPublisher{}, "Africa/Lagos", 5000)
+
+	if _, err := svc.GenerateMonthlyInvoices(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.timezone != "Africa/Lagos" {
+		t.Fatalf("expected business timezone, got %q", repo.timezone)
+	}
+	if repo.minChargeKobo != 5000 {
+		t.Fatalf("expected minimum charge 5000, got %d", repo.minChargeKobo)
+	}
+	if repo.periodStart.Day() != 1 || repo.periodStart.Hour() != 0 {
+		t.Fatalf("expected period to start at local midnight on the 1st, got %s", repo.periodStart)
+	}
+	if next := repo.periodEnd.AddDate(0, 0, 1); next.Day() != 1 {
+		t.Fatalf("expected period to end on the last day of the month, got %s", repo.periodEnd)
+	}
+}
+
+func TestChargeInvoice_DebitsProratedAmount(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &prorationRepoStub{invoice: domain.PlatformFeeInvoice{
+		ID:              "inv-1",
+		UserID:          "user-1",
+		BaseAmount:      100000,
+		ProrationFactor: 0.333333,
+		Amount:          33333,
+		Currency:        "NGN",
+		Status:          "pending",
+		DueAt:           now.Add(-time.Minute),
+		GraceUntil:      now.Add(7 * 24 * time.Hour),
+	}}
+	txClient := &amountRecordingTransactionClient{}
+	svc := NewService(repo, txClient, &recordingPublisher{}, "UTC", 0)
+
+	invoice, err := svc.ChargeInvoice(context.Background(), "inv-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invoice.Status != "paid" {
+		t.Fatalf("expected paid invoice, got %q", invoice.Status)
+	}
+	if len(txClient.amounts) != 1 || txClient.amounts[0] != 33333 {
+		t.Fatalf("expected a single debit of the prorated amount, got %v", txClient.amounts)
+	}
+	if len(repo.attempts) != 1 || repo.attempts[0] != 33333 {
+		t.Fatalf("expected the attempt to record the prorated amount, got %v", repo.attempts)
+	}
+}
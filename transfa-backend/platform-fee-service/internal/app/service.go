@@ -20,7 +20,8 @@ var attemptDays = map[int]bool{0: true, 1: true, 3: true, 5: true, 7: true}
 // Repository defines the database operations the service needs.
 type Repository interface {
 	FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error)
-	GenerateInvoicesForPeriod(ctx context.Context, periodStart, periodEnd, dueAt, graceUntil time.Time) ([]domain.PlatformFeeInvoice, error)
+	GetBillingProfile(ctx context.Context, userID string) (*domain.BillingProfile, error)
+	GenerateInvoicesForPeriod(ctx context.Context, periodStart, periodEnd, dueAt, graceUntil time.Time, timezone string, minChargeKobo int64) ([]domain.PlatformFeeInvoice, error)
 	ListInvoicesByUserID(ctx context.Context, userID string, limit int) ([]domain.PlatformFeeInvoice, error)
 	GetLatestInvoiceByUserID(ctx context.Context, userID string) (*domain.PlatformFeeInvoice, error)
 	GetInvoiceByID(ctx context.Context, invoiceID string) (*domain.PlatformFeeInvoice, error)
@@ -31,6 +32,8 @@ type Repository interface {
 	MarkInvoicePaid(ctx context.Context, invoiceID string, paidAt time.Time) error
 	MarkInvoiceFailed(ctx context.Context, invoiceID string, failureReason string) error
 	MarkInvoicesDelinquent(ctx context.Context, now time.Time) ([]domain.PlatformFeeInvoice, error)
+	WaiveInvoice(ctx context.Context, invoiceID, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error)
+	AdjustInvoiceAmount(ctx context.Context, invoiceID string, newAmount int64, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error)
 }
 
 // TransactionClient defines the interface for charging platform fees.
@@ -49,17 +52,20 @@ type Service struct {
 	txClient  TransactionClient
 	publisher EventPublisher
 	loc       *time.Location
+
+	// minChargeKobo suppresses prorated invoices below this amount.
+	minChargeKobo int64
 }
 
 // NewService creates a new platform fee service.
-func NewService(repo Repository, txClient TransactionClient, publisher EventPublisher, timezone string) Service {
+func NewService(repo Repository, txClient TransactionClient, publisher EventPublisher, timezone string, minChargeKobo int64) Service {
 	loc, err := time.LoadLocation(timezone)
 	if err != nil {
 		log.Printf("WARN: invalid timezone %q, defaulting to UTC", timezone)
 		loc = time.UTC
 	}
 
-	return Service{repo: repo, txClient: txClient, publisher: publisher, loc: loc}
+	return Service{repo: repo, txClient: txClient, publisher: publisher, loc: loc, minChargeKobo: minChargeKobo}
 }
 
 // InvoiceGenerationResult summarizes invoice generation output.
@@ -136,7 +142,8 @@ func (s Service) GetStatusByUserID(ctx context.Context, userID string) (*domain.
 		return &status, nil
 	}
 
-	if invoice.Status == "paid" || invoice.Status == "waived" {
+	// Nothing is owed on a waived invoice or one adjusted down to zero.
+	if invoice.Status == "paid" || invoice.Status == "waived" || invoice.Amount <= 0 {
 		status.IsDelinquent = false
 		status.IsWithinGrace = true
 		return &status, nil
@@ -188,7 +195,9 @@ func (s Service) GenerateMonthlyInvoices(ctx context.Context) (*InvoiceGeneratio
 	dueAt := time.Date(periodEnd.Year(), periodEnd.Month(), 1, 0, 5, 0, 0, s.loc).AddDate(0, 1, 0)
 	graceUntil := dueAt.AddDate(0, 0, 7)
 
-	invoices, err := s.repo.GenerateInvoicesForPeriod(ctx, periodStart.UTC(), periodEnd.UTC(), dueAt.UTC(), graceUntil.UTC())
+	// Period bounds are DATE parameters, which take the calendar day of the value's
+	// own location; keep them in the business timezone so they are not shifted.
+	invoices, err := s.repo.GenerateInvoicesForPeriod(ctx, periodStart, periodEnd, dueAt.UTC(), graceUntil.UTC(), s.loc.String(), s.minChargeKobo)
 	if err != nil {
 		return nil, err
 	}
@@ -269,6 +278,9 @@ func (s Service) attemptInvoiceCharge(ctx context.Context, invoice domain.Platfo
 	if invoice.Status == "paid" || invoice.Status == "waived" || invoice.Status == "delinquent" {
 		return false, nil
 	}
+	if invoice.Amount <= 0 {
+		return false, nil
+	}
 	if now.Before(invoice.DueAt) || now.After(invoice.GraceUntil) {
 		return false, nil
 	}
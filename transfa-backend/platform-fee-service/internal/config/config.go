@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config holds all configuration for the application.
@@ -22,12 +24,27 @@ type Config struct {
 	InternalAPIKey                   string `mapstructure:"INTERNAL_API_KEY"`
 	BusinessTimezone                 string `mapstructure:"BUSINESS_TIMEZONE"`
 	RabbitMQURL                      string `mapstructure:"RABBITMQ_URL"`
+	MinChargeKobo                    int64  `mapstructure:"PLATFORM_FEE_MIN_CHARGE_KOBO"`
+	AppEnv                           string `mapstructure:"APP_ENV"`
+	AllowedOrigins                   string `mapstructure:"ALLOWED_ORIGINS"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        100,
+	MinConns:        20,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from environment variables.
 func LoadConfig() (config Config, err error) {
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("BUSINESS_TIMEZONE", "Africa/Lagos")
+	viper.SetDefault("APP_ENV", "development")
 	viper.AutomaticEnv()
 
 	_ = viper.BindEnv("SERVER_PORT")
@@ -39,8 +56,13 @@ func LoadConfig() (config Config, err error) {
 	_ = viper.BindEnv("INTERNAL_API_KEY")
 	_ = viper.BindEnv("BUSINESS_TIMEZONE")
 	_ = viper.BindEnv("RABBITMQ_URL")
+	_ = viper.BindEnv("PLATFORM_FEE_MIN_CHARGE_KOBO")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("ALLOWED_ORIGINS")
 
-	err = viper.Unmarshal(&config)
+	if err = viper.Unmarshal(&config); err != nil {
+		return config, err
+	}
 	if port := os.Getenv("PORT"); port != "" {
 		config.ServerPort = port
 	}
@@ -53,6 +75,9 @@ func LoadConfig() (config Config, err error) {
 	config.InternalAPIKey = strings.TrimSpace(config.InternalAPIKey)
 	config.BusinessTimezone = strings.TrimSpace(config.BusinessTimezone)
 	config.RabbitMQURL = strings.TrimSpace(config.RabbitMQURL)
+	if config.MinChargeKobo < 0 {
+		config.MinChargeKobo = 0
+	}
 
 	// Backward-compatible fallback: reuse INTERNAL_API_KEY when a dedicated
 	// transaction-service key is not configured.
@@ -77,5 +102,6 @@ func LoadConfig() (config Config, err error) {
 		return config, fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
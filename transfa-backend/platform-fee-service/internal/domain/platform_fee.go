@@ -6,24 +6,27 @@ package domain
 
 import "time"
 
-// PlatformFeeInvoice represents a platform fee invoice row.
+// PlatformFeeInvoice represents a platform fee invoice row. BaseAmount is the full
+// configured fee; Amount is what is charged after applying ProrationFactor.
 type PlatformFeeInvoice struct {
-	ID            string     `json:"id"`
-	UserID        string     `json:"user_id"`
-	UserType      string     `json:"user_type"`
-	PeriodStart   time.Time  `json:"period_start"`
-	PeriodEnd     time.Time  `json:"period_end"`
-	DueAt         time.Time  `json:"due_at"`
-	GraceUntil    time.Time  `json:"grace_until"`
-	Amount        int64      `json:"amount"`
-	Currency      string     `json:"currency"`
-	Status        string     `json:"status"`
-	PaidAt        *time.Time `json:"paid_at,omitempty"`
-	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
-	RetryCount    int        `json:"retry_count"`
-	FailureReason *string    `json:"failure_reason,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	UserType        string     `json:"user_type"`
+	PeriodStart     time.Time  `json:"period_start"`
+	PeriodEnd       time.Time  `json:"period_end"`
+	DueAt           time.Time  `json:"due_at"`
+	GraceUntil      time.Time  `json:"grace_until"`
+	BaseAmount      int64      `json:"base_amount"`
+	ProrationFactor float64    `json:"proration_factor"`
+	Amount          int64      `json:"amount"`
+	Currency        string     `json:"currency"`
+	Status          string     `json:"status"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	LastAttemptAt   *time.Time `json:"last_attempt_at,omitempty"`
+	RetryCount      int        `json:"retry_count"`
+	FailureReason   *string    `json:"failure_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // PlatformFeeAttempt represents an audit record for a charge attempt.
@@ -38,6 +41,32 @@ type PlatformFeeAttempt struct {
 	CreatedAt         time.Time  `json:"created_at"`
 }
 
+// Platform fee invoice adjustment actions recorded in platform_fee_invoice_adjustments.
+const (
+	InvoiceAdjustmentWaive  = "waive"
+	InvoiceAdjustmentAmount = "adjust"
+)
+
+// PlatformFeeInvoiceAdjustment is an audit record of a waiver or amount correction.
+type PlatformFeeInvoiceAdjustment struct {
+	ID             string    `json:"id"`
+	InvoiceID      string    `json:"invoice_id"`
+	Action         string    `json:"action"`
+	PreviousAmount int64     `json:"previous_amount"`
+	NewAmount      int64     `json:"new_amount"`
+	Reason         string    `json:"reason"`
+	Actor          string    `json:"actor"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BillingProfile holds the user details printed on an invoice.
+type BillingProfile struct {
+	UserID   string
+	FullName string
+	Username string
+	Email    string
+}
+
 // PlatformFeeStatus summarizes a user's current platform fee state.
 type PlatformFeeStatus struct {
 	Status        string     `json:"status"`
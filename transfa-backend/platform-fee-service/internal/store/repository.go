@@ -15,10 +15,44 @@ import (
 )
 
 var (
-	ErrInvoiceNotFound = errors.New("invoice not found")
-	ErrUserNotFound    = errors.New("user not found")
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvoiceNotWaivable   = errors.New("invoice is already paid or waived")
+	ErrInvoiceNotAdjustable = errors.New("invoice amount can only be adjusted while pending or failed")
 )
 
+// invoiceColumns lists the platform_fee_invoices columns read by scanInvoice, in order.
+const invoiceColumns = `id, user_id, user_type, period_start, period_end, due_at, grace_until,
+	base_amount, proration_factor, amount, currency, status, paid_at, last_attempt_at,
+	retry_count, failure_reason, created_at, updated_at`
+
+func scanInvoice(row pgx.Row) (*domain.PlatformFeeInvoice, error) {
+	var invoice domain.PlatformFeeInvoice
+	if err := row.Scan(
+		&invoice.ID,
+		&invoice.UserID,
+		&invoice.UserType,
+		&invoice.PeriodStart,
+		&invoice.PeriodEnd,
+		&invoice.DueAt,
+		&invoice.GraceUntil,
+		&invoice.BaseAmount,
+		&invoice.ProrationFactor,
+		&invoice.Amount,
+		&invoice.Currency,
+		&invoice.Status,
+		&invoice.PaidAt,
+		&invoice.LastAttemptAt,
+		&invoice.RetryCount,
+		&invoice.FailureReason,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
 // Repository handles database operations for platform fees.
 type Repository struct {
 	db *pgxpool.Pool
@@ -42,9 +76,66 @@ func (r *Repository) FindUserIDByClerkUserID(ctx context.Context, clerkUserID st
 	return id, nil
 }
 
-// GenerateInvoicesForPeriod creates invoices for all users for the given period.
-func (r *Repository) GenerateInvoicesForPeriod(ctx context.Context, periodStart, periodEnd, dueAt, graceUntil time.Time) ([]domain.PlatformFeeInvoice, error) {
+// GetBillingProfile loads the user details printed on an invoice.
+func (r *Repository) GetBillingProfile(ctx context.Context, userID string) (*domain.BillingProfile, error) {
+	var fullName, username, email *string
+	err := r.db.QueryRow(ctx, "SELECT full_name, username, email FROM users WHERE id = $1", userID).Scan(&fullName, &username, &email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	profile := &domain.BillingProfile{UserID: userID}
+	if fullName != nil {
+		profile.FullName = *fullName
+	}
+	if username != nil {
+		profile.Username = *username
+	}
+	if email != nil {
+		profile.Email = *email
+	}
+	return profile, nil
+}
+
+// GenerateInvoicesForPeriod creates invoices for all users for the given period. Users who
+// joined during the period are charged for the days from their sign-up date (in timezone)
+// to the end of the period, rounded to the nearest kobo; no invoice is created when that
+// comes to less than minChargeKobo.
+func (r *Repository) GenerateInvoicesForPeriod(ctx context.Context, periodStart, periodEnd, dueAt, graceUntil time.Time, timezone string, minChargeKobo int64) ([]domain.PlatformFeeInvoice, error) {
 	query := `
+		WITH candidates AS (
+			SELECT
+				u.id AS user_id,
+				u.user_type,
+				cfg.fee_amount,
+				cfg.currency,
+				($2::DATE - GREATEST($1::DATE, (u.created_at AT TIME ZONE $5)::DATE) + 1)::NUMERIC AS active_days,
+				($2::DATE - $1::DATE + 1)::NUMERIC AS period_days
+			FROM users u
+			JOIN LATERAL (
+				SELECT fee_amount, currency
+				FROM platform_fee_config
+				WHERE user_type = u.user_type
+				  AND active = TRUE
+				  AND effective_from <= $1::DATE
+				ORDER BY effective_from DESC
+				LIMIT 1
+			) cfg ON TRUE
+			WHERE (u.created_at AT TIME ZONE $5)::DATE <= $2::DATE
+		),
+		prorated AS (
+			SELECT
+				user_id,
+				user_type,
+				fee_amount,
+				currency,
+				ROUND(active_days / period_days, 6) AS proration_factor,
+				ROUND(fee_amount * active_days / period_days)::BIGINT AS amount
+			FROM candidates
+		)
 		INSERT INTO platform_fee_invoices (
 			user_id,
 			user_type,
@@ -52,34 +143,29 @@ func (r *Repository) GenerateInvoicesForPeriod(ctx context.Context, periodStart,
 			period_end,
 			due_at,
 			grace_until,
+			base_amount,
+			proration_factor,
 			amount,
 			currency
 		)
 		SELECT
-			u.id,
-			u.user_type,
+			user_id,
+			user_type,
 			$1::DATE,
 			$2::DATE,
 			$3,
 			$4,
-			cfg.fee_amount,
-			cfg.currency
-		FROM users u
-		JOIN LATERAL (
-			SELECT fee_amount, currency
-			FROM platform_fee_config
-			WHERE user_type = u.user_type
-			  AND active = TRUE
-			  AND effective_from <= $1::DATE
-			ORDER BY effective_from DESC
-			LIMIT 1
-		) cfg ON TRUE
+			fee_amount,
+			proration_factor,
+			amount,
+			currency
+		FROM prorated
+		WHERE amount > 0
+		  AND amount >= $6
 		ON CONFLICT (user_id, period_start) DO NOTHING
-		RETURNING id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		          amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		          created_at, updated_at
+		RETURNING ` + invoiceColumns + `
 	`
-	rows, err := r.db.Query(ctx, query, periodStart, periodEnd, dueAt, graceUntil)
+	rows, err := r.db.Query(ctx, query, periodStart, periodEnd, dueAt, graceUntil, timezone, minChargeKobo)
 	if err != nil {
 		return nil, err
 	}
@@ -87,28 +173,11 @@ func (r *Repository) GenerateInvoicesForPeriod(ctx context.Context, periodStart,
 
 	var invoices []domain.PlatformFeeInvoice
 	for rows.Next() {
-		var invoice domain.PlatformFeeInvoice
-		if err := rows.Scan(
-			&invoice.ID,
-			&invoice.UserID,
-			&invoice.UserType,
-			&invoice.PeriodStart,
-			&invoice.PeriodEnd,
-			&invoice.DueAt,
-			&invoice.GraceUntil,
-			&invoice.Amount,
-			&invoice.Currency,
-			&invoice.Status,
-			&invoice.PaidAt,
-			&invoice.LastAttemptAt,
-			&invoice.RetryCount,
-			&invoice.FailureReason,
-			&invoice.CreatedAt,
-			&invoice.UpdatedAt,
-		); err != nil {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
 			return nil, err
 		}
-		invoices = append(invoices, invoice)
+		invoices = append(invoices, *invoice)
 	}
 
 	return invoices, nil
@@ -117,9 +186,7 @@ func (r *Repository) GenerateInvoicesForPeriod(ctx context.Context, periodStart,
 // ListInvoicesByUserID retrieves recent invoices for a user.
 func (r *Repository) ListInvoicesByUserID(ctx context.Context, userID string, limit int) ([]domain.PlatformFeeInvoice, error) {
 	query := `
-		SELECT id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		       amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		       created_at, updated_at
+		SELECT ` + invoiceColumns + `
 		FROM platform_fee_invoices
 		WHERE user_id = $1
 		ORDER BY period_start DESC
@@ -133,28 +200,11 @@ func (r *Repository) ListInvoicesByUserID(ctx context.Context, userID string, li
 
 	var invoices []domain.PlatformFeeInvoice
 	for rows.Next() {
-		var invoice domain.PlatformFeeInvoice
-		if err := rows.Scan(
-			&invoice.ID,
-			&invoice.UserID,
-			&invoice.UserType,
-			&invoice.PeriodStart,
-			&invoice.PeriodEnd,
-			&invoice.DueAt,
-			&invoice.GraceUntil,
-			&invoice.Amount,
-			&invoice.Currency,
-			&invoice.Status,
-			&invoice.PaidAt,
-			&invoice.LastAttemptAt,
-			&invoice.RetryCount,
-			&invoice.FailureReason,
-			&invoice.CreatedAt,
-			&invoice.UpdatedAt,
-		); err != nil {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
 			return nil, err
 		}
-		invoices = append(invoices, invoice)
+		invoices = append(invoices, *invoice)
 	}
 
 	return invoices, nil
@@ -163,87 +213,48 @@ func (r *Repository) ListInvoicesByUserID(ctx context.Context, userID string, li
 // GetLatestInvoiceByUserID retrieves the most recent invoice for a user.
 func (r *Repository) GetLatestInvoiceByUserID(ctx context.Context, userID string) (*domain.PlatformFeeInvoice, error) {
 	query := `
-		SELECT id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		       amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		       created_at, updated_at
+		SELECT ` + invoiceColumns + `
 		FROM platform_fee_invoices
 		WHERE user_id = $1
 		ORDER BY period_start DESC
 		LIMIT 1
 	`
-	var invoice domain.PlatformFeeInvoice
-	if err := r.db.QueryRow(ctx, query, userID).Scan(
-		&invoice.ID,
-		&invoice.UserID,
-		&invoice.UserType,
-		&invoice.PeriodStart,
-		&invoice.PeriodEnd,
-		&invoice.DueAt,
-		&invoice.GraceUntil,
-		&invoice.Amount,
-		&invoice.Currency,
-		&invoice.Status,
-		&invoice.PaidAt,
-		&invoice.LastAttemptAt,
-		&invoice.RetryCount,
-		&invoice.FailureReason,
-		&invoice.CreatedAt,
-		&invoice.UpdatedAt,
-	); err != nil {
+	invoice, err := scanInvoice(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrInvoiceNotFound
 		}
 		return nil, err
 	}
 
-	return &invoice, nil
+	return invoice, nil
 }
 
 // GetInvoiceByID retrieves a specific invoice.
 func (r *Repository) GetInvoiceByID(ctx context.Context, invoiceID string) (*domain.PlatformFeeInvoice, error) {
 	query := `
-		SELECT id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		       amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		       created_at, updated_at
+		SELECT ` + invoiceColumns + `
 		FROM platform_fee_invoices
 		WHERE id = $1
 	`
-	var invoice domain.PlatformFeeInvoice
-	if err := r.db.QueryRow(ctx, query, invoiceID).Scan(
-		&invoice.ID,
-		&invoice.UserID,
-		&invoice.UserType,
-		&invoice.PeriodStart,
-		&invoice.PeriodEnd,
-		&invoice.DueAt,
-		&invoice.GraceUntil,
-		&invoice.Amount,
-		&invoice.Currency,
-		&invoice.Status,
-		&invoice.PaidAt,
-		&invoice.LastAttemptAt,
-		&invoice.RetryCount,
-		&invoice.FailureReason,
-		&invoice.CreatedAt,
-		&invoice.UpdatedAt,
-	); err != nil {
+	invoice, err := scanInvoice(r.db.QueryRow(ctx, query, invoiceID))
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrInvoiceNotFound
 		}
 		return nil, err
 	}
 
-	return &invoice, nil
+	return invoice, nil
 }
 
 // ListChargeableInvoices fetches invoices that are due and within grace.
 func (r *Repository) ListChargeableInvoices(ctx context.Context, now time.Time) ([]domain.PlatformFeeInvoice, error) {
 	query := `
-		SELECT id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		       amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		       created_at, updated_at
+		SELECT ` + invoiceColumns + `
 		FROM platform_fee_invoices
 		WHERE status IN ('pending', 'failed')
+		  AND amount > 0
 		  AND due_at <= $1
 		  AND grace_until >= $1
 		  AND NOT EXISTS (
@@ -262,28 +273,11 @@ func (r *Repository) ListChargeableInvoices(ctx context.Context, now time.Time)
 
 	var invoices []domain.PlatformFeeInvoice
 	for rows.Next() {
-		var invoice domain.PlatformFeeInvoice
-		if err := rows.Scan(
-			&invoice.ID,
-			&invoice.UserID,
-			&invoice.UserType,
-			&invoice.PeriodStart,
-			&invoice.PeriodEnd,
-			&invoice.DueAt,
-			&invoice.GraceUntil,
-			&invoice.Amount,
-			&invoice.Currency,
-			&invoice.Status,
-			&invoice.PaidAt,
-			&invoice.LastAttemptAt,
-			&invoice.RetryCount,
-			&invoice.FailureReason,
-			&invoice.CreatedAt,
-			&invoice.UpdatedAt,
-		); err != nil {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
 			return nil, err
 		}
-		invoices = append(invoices, invoice)
+		invoices = append(invoices, *invoice)
 	}
 
 	return invoices, nil
@@ -298,6 +292,7 @@ func (r *Repository) ClaimInvoiceAttempt(ctx context.Context, invoiceID string,
 		    updated_at = NOW()
 		WHERE id = $2
 		  AND status IN ('pending', 'failed')
+		  AND amount > 0
 		  AND NOT EXISTS (
 			SELECT 1
 			FROM platform_fee_attempts
@@ -305,36 +300,17 @@ func (r *Repository) ClaimInvoiceAttempt(ctx context.Context, invoiceID string,
 			  AND status = 'success'
 		  )
 		  AND (last_attempt_at IS NULL OR last_attempt_at < $3)
-		RETURNING id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		          amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		          created_at, updated_at
+		RETURNING ` + invoiceColumns + `
 	`
-	var invoice domain.PlatformFeeInvoice
-	if err := r.db.QueryRow(ctx, query, attemptAt, invoiceID, attemptWindowStart).Scan(
-		&invoice.ID,
-		&invoice.UserID,
-		&invoice.UserType,
-		&invoice.PeriodStart,
-		&invoice.PeriodEnd,
-		&invoice.DueAt,
-		&invoice.GraceUntil,
-		&invoice.Amount,
-		&invoice.Currency,
-		&invoice.Status,
-		&invoice.PaidAt,
-		&invoice.LastAttemptAt,
-		&invoice.RetryCount,
-		&invoice.FailureReason,
-		&invoice.CreatedAt,
-		&invoice.UpdatedAt,
-	); err != nil {
+	invoice, err := scanInvoice(r.db.QueryRow(ctx, query, attemptAt, invoiceID, attemptWindowStart))
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	return &invoice, nil
+	return invoice, nil
 }
 
 // InsertAttempt writes a platform fee attempt record.
@@ -398,6 +374,7 @@ func (r *Repository) MarkInvoicesDelinquent(ctx context.Context, now time.Time)
 		SET status = 'delinquent',
 		    updated_at = NOW()
 		WHERE status IN ('pending', 'failed')
+		  AND amount > 0
 		  AND grace_until < $1
 		  AND NOT EXISTS (
 			SELECT 1
@@ -405,9 +382,7 @@ func (r *Repository) MarkInvoicesDelinquent(ctx context.Context, now time.Time)
 			WHERE invoice_id = platform_fee_invoices.id
 			  AND status = 'success'
 		  )
-		RETURNING id, user_id, user_type, period_start, period_end, due_at, grace_until,
-		          amount, currency, status, paid_at, last_attempt_at, retry_count, failure_reason,
-		          created_at, updated_at
+		RETURNING ` + invoiceColumns + `
 	`
 	rows, err := r.db.Query(ctx, query, now)
 	if err != nil {
@@ -417,29 +392,109 @@ func (r *Repository) MarkInvoicesDelinquent(ctx context.Context, now time.Time)
 
 	var invoices []domain.PlatformFeeInvoice
 	for rows.Next() {
-		var invoice domain.PlatformFeeInvoice
-		if err := rows.Scan(
-			&invoice.ID,
-			&invoice.UserID,
-			&invoice.UserType,
-			&invoice.PeriodStart,
-			&invoice.PeriodEnd,
-			&invoice.DueAt,
-			&invoice.GraceUntil,
-			&invoice.Amount,
-			&invoice.Currency,
-			&invoice.Status,
-			&invoice.PaidAt,
-			&invoice.LastAttemptAt,
-			&invoice.RetryCount,
-			&invoice.FailureReason,
-			&invoice.CreatedAt,
-			&invoice.UpdatedAt,
-		); err != nil {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
 			return nil, err
 		}
-		invoices = append(invoices, invoice)
+		invoices = append(invoices, *invoice)
 	}
 
 	return invoices, nil
 }
+
+// WaiveInvoice marks an unpaid invoice as waived and records the waiver.
+func (r *Repository) WaiveInvoice(ctx context.Context, invoiceID, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error) {
+	return r.adjustInvoice(ctx, invoiceID, domain.InvoiceAdjustmentWaive, 0, reason, actor)
+}
+
+// AdjustInvoiceAmount changes the amount of a pending or failed invoice and records the change.
+func (r *Repository) AdjustInvoiceAmount(ctx context.Context, invoiceID string, newAmount int64, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error) {
+	return r.adjustInvoice(ctx, invoiceID, domain.InvoiceAdjustmentAmount, newAmount, reason, actor)
+}
+
+// adjustInvoice locks the invoice, checks it can still be changed, applies the waiver or
+// new amount and writes the audit row in one transaction. An invoice with a successful
+// attempt counts as paid even if its status has not caught up yet.
+func (r *Repository) adjustInvoice(ctx context.Context, invoiceID, action string, newAmount int64, reason, actor string) (*domain.PlatformFeeInvoice, *domain.PlatformFeeInvoiceAdjustment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		status         string
+		previousAmount int64
+		paid           bool
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT status, amount, EXISTS (
+			SELECT 1
+			FROM platform_fee_attempts
+			WHERE invoice_id = platform_fee_invoices.id
+			  AND status = 'success'
+		)
+		FROM platform_fee_invoices
+		WHERE id = $1
+		FOR UPDATE
+	`, invoiceID).Scan(&status, &previousAmount, &paid)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, ErrInvoiceNotFound
+		}
+		return nil, nil, err
+	}
+
+	var (
+		update string
+		args   []interface{}
+	)
+	switch action {
+	case domain.InvoiceAdjustmentWaive:
+		if paid || (status != "pending" && status != "failed" && status != "delinquent") {
+			return nil, nil, ErrInvoiceNotWaivable
+		}
+		newAmount = previousAmount
+		update = "status = 'waived', failure_reason = NULL"
+		args = []interface{}{invoiceID}
+	default:
+		if paid || (status != "pending" && status != "failed") {
+			return nil, nil, ErrInvoiceNotAdjustable
+		}
+		update = "amount = $2"
+		args = []interface{}{invoiceID, newAmount}
+	}
+
+	invoice, err := scanInvoice(tx.QueryRow(ctx, `
+		UPDATE platform_fee_invoices
+		SET `+update+`,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+invoiceColumns+`
+	`, args...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	adjustment := domain.PlatformFeeInvoiceAdjustment{
+		InvoiceID:      invoiceID,
+		Action:         action,
+		PreviousAmount: previousAmount,
+		NewAmount:      newAmount,
+		Reason:         reason,
+		Actor:          actor,
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO platform_fee_invoice_adjustments (invoice_id, action, previous_amount, new_amount, reason, actor)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, invoiceID, action, previousAmount, newAmount, reason, actor).Scan(&adjustment.ID, &adjustment.CreatedAt); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return invoice, &adjustment, nil
+}
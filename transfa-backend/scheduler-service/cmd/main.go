@@ -1,14 +1,16 @@
 /**
  * @description
  * This is the main entry point for the scheduler-service.
- * This service is a non-HTTP, long-running process that executes scheduled tasks (cron jobs).
+ * This service is a long-running process that executes scheduled tasks (cron jobs).
  * It initializes the configuration, database connection, and the cron scheduler, then starts it.
+ * A small admin HTTP listener exposes job status and manual runs.
  */
 package main
 
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/transfa/scheduler-service/internal/api"
 	"github.com/transfa/scheduler-service/internal/app"
 	"github.com/transfa/scheduler-service/internal/config"
 	"github.com/transfa/scheduler-service/internal/store"
@@ -43,10 +46,10 @@ func main() {
 	}
 
 	// Configure connection pool for high-traffic scenarios
-	config.MaxConns = 100
-	config.MinConns = 20
-	config.MaxConnLifetime = 30 * time.Minute
-	config.MaxConnIdleTime = 5 * time.Minute
+	config.MaxConns = cfg.DatabasePool.MaxConns
+	config.MinConns = cfg.DatabasePool.MinConns
+	config.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	config.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 
 	// Disable prepared statement caching to prevent conflicts
 	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
@@ -68,15 +71,38 @@ func main() {
 
 	// Start the cron scheduler in the background
 	scheduler.Start()
-	logger.Info("scheduler started")
+	logger.Info("scheduler started", "instance", cfg.InstanceID, "job_lock_ttl", cfg.JobLockTTL)
+
+	// Start the admin listener unless it is disabled with an empty ADMIN_PORT
+	var adminServer *http.Server
+	if cfg.AdminPort != "" {
+		adminServer = &http.Server{
+			Addr:              ":" + cfg.AdminPort,
+			Handler:           api.AdminRoutes(scheduler, cfg.InternalAPIKey, logger),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info("admin listener started", "addr", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("admin listener failed", "error", err)
+			}
+		}()
+	}
 
 	// Wait for termination signal to gracefully shut down
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
-	// Stop the scheduler
+	// Stop the admin listener and the scheduler
 	logger.Info("shutdown signal received, stopping scheduler")
+	if adminServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("admin listener shutdown failed", "error", err)
+		}
+		cancel()
+	}
 	stopCtx := scheduler.Stop()
 	<-stopCtx.Done() // Wait for scheduler to fully stop
 	logger.Info("scheduler stopped gracefully")
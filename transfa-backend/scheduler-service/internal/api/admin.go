@@ -0,0 +1,75 @@
+/**
+ * @description
+ * Admin HTTP listener for scheduler-service. Lists the registered jobs with their
+ * schedules and latest runs, and lets operators trigger a job manually.
+ *
+ * @notes
+ * - Every route requires the X-Internal-API-Key header.
+ */
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/transfa/pkg/httpjson"
+	"github.com/transfa/pkg/middleware"
+	"github.com/transfa/scheduler-service/internal/app"
+	"github.com/transfa/scheduler-service/internal/domain"
+)
+
+// JobScheduler is the part of the scheduler the admin API needs.
+type JobScheduler interface {
+	JobStatuses(ctx context.Context) ([]domain.JobStatus, error)
+	RunJob(name string) error
+}
+
+// AdminRoutes returns the admin HTTP handler.
+func AdminRoutes(scheduler JobScheduler, internalAPIKey string, logger *slog.Logger) http.Handler {
+	internalAuth := middleware.InternalAuth(internalAPIKey)
+	h := &adminHandler{scheduler: scheduler, logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("healthy"))
+	})
+	mux.Handle("GET /jobs", internalAuth(http.HandlerFunc(h.listJobs)))
+	mux.Handle("POST /jobs/{name}/run", internalAuth(http.HandlerFunc(h.runJob)))
+	return mux
+}
+
+type adminHandler struct {
+	scheduler JobScheduler
+	logger    *slog.Logger
+}
+
+func (h *adminHandler) listJobs(w http.ResponseWriter, r *http.Request) {
+	statuses, err := h.scheduler.JobStatuses(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list jobs", "error", err)
+		httpjson.Write(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list jobs"})
+		return
+	}
+	httpjson.Write(w, http.StatusOK, map[string]interface{}{"jobs": statuses})
+}
+
+func (h *adminHandler) runJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := h.scheduler.RunJob(name); err != nil {
+		switch {
+		case errors.Is(err, app.ErrJobNotFound):
+			httpjson.Write(w, http.StatusNotFound, map[string]string{"error": "Job not found"})
+		case errors.Is(err, app.ErrJobRunning):
+			httpjson.Write(w, http.StatusConflict, map[string]string{"error": "Job is already running"})
+		default:
+			httpjson.Write(w, http.StatusInternalServerError, map[string]string{"error": "Failed to start job"})
+		}
+		return
+	}
+
+	h.logger.Info("manual job run started", "job", name)
+	httpjson.Write(w, http.StatusAccepted, map[string]string{"job": name, "status": "started"})
+}
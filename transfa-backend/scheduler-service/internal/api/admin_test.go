@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/transfa/scheduler-service/internal/app"
+	"github.com/transfa/scheduler-service/internal/domain"
+)
+
+type schedulerStub struct {
+	started []string
+}
+
+func (s *schedulerStub) JobStatuses(ctx context.Context) ([]domain.JobStatus, error) {
+	return []domain.JobStatus{{Name: app.JobMoneyDropExpiry, Schedule: "* * * * *", Enabled: true}}, nil
+}
+
+func (s *schedulerStub) RunJob(name string) error {
+	if name != app.JobMoneyDropExpiry {
+		return app.ErrJobNotFound
+	}
+	s.started = append(s.started, name)
+	return nil
+}
+
+func newAdminTestRequest(method, path, key string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set("X-Internal-API-Key", key)
+	}
+	return req
+}
+
+func TestAdminRoutes_RequireInternalAPIKey(t *testing.T) {
+	scheduler := &schedulerStub{}
+	handler := AdminRoutes(scheduler, "admin-key", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, req := range []*http.Request{
+		newAdminTestRequest(http.MethodGet, "/jobs", ""),
+		newAdminTestRequest(http.MethodPost, "/jobs/money_drop_expiry/run", "wrong-key"),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s %s: expected 401, got %d", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+	if len(scheduler.started) != 0 {
+		t.Fatalf("expected no job to start, got %v", scheduler.started)
+	}
+}
+
+func TestAdminRoutes_ListAndRunJobs(t *testing.T) {
+	scheduler := &schedulerStub{}
+	handler := AdminRoutes(scheduler, "admin-key", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newAdminTestRequest(http.MethodGet, "/jobs", "admin-key"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Jobs []domain.JobStatus `json:"jobs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(body.Jobs) != 1 || body.Jobs[0].Name != app.JobMoneyDropExpiry {
+		t.Fatalf("unexpected jobs: %+v", body.Jobs)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newAdminTestRequest(http.MethodPost, "/jobs/money_drop_expiry/run", "admin-key"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if len(scheduler.started) != 1 {
+		t.Fatalf("expected job to start once, got %v", scheduler.started)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newAdminTestRequest(http.MethodPost, "/jobs/unknown/run", "admin-key"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown job, got %d", rec.Code)
+	}
+}
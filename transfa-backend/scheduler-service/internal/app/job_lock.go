@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultJobLockTTL  = 2 * time.Minute
+	jobLockCallTimeout = 5 * time.Second
+)
+
+// jobLease is a held scheduler_locks row. The lease is renewed in the background
+// while the job runs; if it is lost to another instance the run's context is
+// cancelled so the job stops instead of running twice.
+type jobLease struct {
+	scheduler *Scheduler
+	job       *scheduledJob
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// acquireJobLease claims the job's lease for this instance. It returns nil when
+// another instance holds the lease or the lock could not be checked; the run is
+// skipped in both cases.
+func (s *Scheduler) acquireJobLease(job *scheduledJob) *jobLease {
+	ctx, cancel := context.WithTimeout(context.Background(), jobLockCallTimeout)
+	acquired, err := s.jobs.repo.AcquireJobLock(ctx, job.name, s.config.InstanceID, s.lockTTL())
+	cancel()
+	if err != nil {
+		s.logger.Error("failed to acquire job lock; skipping run", "job", job.name, "instance", s.config.InstanceID, "error", err)
+		return nil
+	}
+	if !acquired {
+		skips := job.lockSkips.Add(1)
+		s.logger.Info("job lock held by another instance; skipping run", "job", job.name, "instance", s.config.InstanceID, "lock_skips", skips)
+		return nil
+	}
+
+	runCtx, runCancel := context.WithCancel(context.Background())
+	lease := &jobLease{scheduler: s, job: job, ctx: runCtx, cancel: runCancel, done: make(chan struct{})}
+	go lease.heartbeat()
+	return lease
+}
+
+func (l *jobLease) heartbeat() {
+	defer close(l.done)
+
+	s := l.scheduler
+	ttl := s.lockTTL()
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), jobLockCallTimeout)
+			renewed, err := s.jobs.repo.RenewJobLock(ctx, l.job.name, s.config.InstanceID, ttl)
+			cancel()
+			if err != nil {
+				// The lease is still valid until it expires; try again on the next tick.
+				s.logger.Warn("failed to renew job lock", "job", l.job.name, "instance", s.config.InstanceID, "error", err)
+				continue
+			}
+			if !renewed {
+				s.logger.Error("job lock lost to another instance; cancelling run", "job", l.job.name, "instance", s.config.InstanceID)
+				l.cancel()
+				return
+			}
+		}
+	}
+}
+
+// release stops the heartbeat and frees the lease for the next run.
+func (l *jobLease) release() {
+	l.cancel()
+	<-l.done
+
+	s := l.scheduler
+	ctx, cancel := context.WithTimeout(context.Background(), jobLockCallTimeout)
+	defer cancel()
+	if err := s.jobs.repo.ReleaseJobLock(ctx, l.job.name, s.config.InstanceID); err != nil {
+		s.logger.Warn("failed to release job lock; it will expire", "job", l.job.name, "instance", s.config.InstanceID, "error", err)
+	}
+}
+
+func (s *Scheduler) lockTTL() time.Duration {
+	if s.config.JobLockTTL > 0 {
+		return s.config.JobLockTTL
+	}
+	return defaultJobLockTTL
+}
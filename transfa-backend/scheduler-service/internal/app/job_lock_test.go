@@ -0,0 +1,203 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/transfa/scheduler-service/internal/config"
+	"github.com/transfa/scheduler-service/internal/domain"
+)
+
+type lease struct {
+	holder      string
+	lockedUntil time.Time
+}
+
+// leaseTableStub mirrors the scheduler_locks upsert: a lease can be taken when it is
+// free, expired, or already held by the caller.
+type leaseTableStub struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+func newLeaseTableStub() *leaseTableStub {
+	return &leaseTableStub{leases: map[string]lease{}}
+}
+
+func (t *leaseTableStub) acquire(jobName, holder string, ttl time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current, ok := t.leases[jobName]
+	if ok && current.holder != holder && time.Now().Before(current.lockedUntil) {
+		return false
+	}
+	t.leases[jobName] = lease{holder: holder, lockedUntil: time.Now().Add(ttl)}
+	return true
+}
+
+func (t *leaseTableStub) renew(jobName, holder string, ttl time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current, ok := t.leases[jobName]
+	if !ok || current.holder != holder {
+		return false
+	}
+	t.leases[jobName] = lease{holder: holder, lockedUntil: time.Now().Add(ttl)}
+	return true
+}
+
+func (t *leaseTableStub) release(jobName, holder string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if current, ok := t.leases[jobName]; ok && current.holder == holder {
+		delete(t.leases, jobName)
+	}
+}
+
+func (t *leaseTableStub) steal(jobName, holder string, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.leases[jobName] = lease{holder: holder, lockedUntil: time.Now().Add(ttl)}
+}
+
+// blockingTxClient holds ExpireMoneyDrops open until released or until the run's
+// context is cancelled.
+type blockingTxClient struct {
+	jobsTxClientStub
+
+	mu      sync.Mutex
+	calls   int
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingTxClient() *blockingTxClient {
+	return &blockingTxClient{started: make(chan struct{}, 2), release: make(chan struct{})}
+}
+
+func (c *blockingTxClient) ExpireMoneyDrops(ctx context.Context) (*domain.MoneyDropExpirySummary, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	c.started <- struct{}{}
+
+	select {
+	case <-c.release:
+		return &domain.MoneyDropExpirySummary{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *blockingTxClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func newLockedScheduler(locks *leaseTableStub, txClient TransactionClient, instanceID string, ttl time.Duration) (*Scheduler, *jobsRepoStub) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.Config{InstanceID: instanceID, JobLockTTL: ttl}
+	repo := &jobsRepoStub{locks: locks}
+	return NewScheduler(NewJobs(repo, txClient, jobsFeeClientStub{}, logger, cfg), logger, cfg), repo
+}
+
+func waitForStart(t *testing.T, client *blockingTxClient) {
+	t.Helper()
+	select {
+	case <-client.started:
+	case <-time.After(time.Second):
+		t.Fatal("expected the job to start")
+	}
+}
+
+func TestSchedulerExecute_OnlyOneInstanceRunsALockedJob(t *testing.T) {
+	locks := newLeaseTableStub()
+	txClient := newBlockingTxClient()
+	first, firstRepo := newLockedScheduler(locks, txClient, "scheduler-a", time.Minute)
+	second, secondRepo := newLockedScheduler(locks, txClient, "scheduler-b", time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		first.execute(first.find(JobMoneyDropExpiry), jobTriggerSchedule)
+	}()
+	waitForStart(t, txClient)
+
+	second.execute(second.find(JobMoneyDropExpiry), jobTriggerSchedule)
+
+	close(txClient.release)
+	<-done
+
+	if got := txClient.callCount(); got != 1 {
+		t.Fatalf("expected the job to run once across both instances, got %d", got)
+	}
+	if len(firstRepo.runs) != 1 || firstRepo.runs[0].Status != jobStatusSucceeded {
+		t.Fatalf("expected the lock holder to record a successful run, got %+v", firstRepo.runs)
+	}
+	if len(secondRepo.runs) != 0 {
+		t.Fatalf("expected the skipped instance to record no run, got %+v", secondRepo.runs)
+	}
+	if skips := second.find(JobMoneyDropExpiry).lockSkips.Load(); skips != 1 {
+		t.Fatalf("expected one lock skip on the second instance, got %d", skips)
+	}
+	if _, held := locks.leases[JobMoneyDropExpiry]; held {
+		t.Fatal("expected the lease to be released after the run")
+	}
+
+	// With the lease released, the other instance can take the next tick.
+	txClient.release = make(chan struct{})
+	close(txClient.release)
+	second.execute(second.find(JobMoneyDropExpiry), jobTriggerSchedule)
+	if got := txClient.callCount(); got != 2 {
+		t.Fatalf("expected the second instance to run after release, got %d calls", got)
+	}
+}
+
+func TestSchedulerExecute_TakesOverExpiredLease(t *testing.T) {
+	locks := newLeaseTableStub()
+	locks.steal(JobMoneyDropExpiry, "crashed-instance", -time.Second)
+
+	txClient := &jobsTxClientStub{}
+	scheduler, repo := newLockedScheduler(locks, txClient, "scheduler-a", time.Minute)
+
+	scheduler.execute(scheduler.find(JobMoneyDropExpiry), jobTriggerSchedule)
+
+	if txClient.expireCalls != 1 {
+		t.Fatalf("expected the job to run after the stale lease expired, got %d calls", txClient.expireCalls)
+	}
+	if len(repo.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(repo.runs))
+	}
+}
+
+func TestSchedulerExecute_CancelsRunWhenLeaseIsLost(t *testing.T) {
+	locks := newLeaseTableStub()
+	txClient := newBlockingTxClient()
+	scheduler, repo := newLockedScheduler(locks, txClient, "scheduler-a", 30*time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.execute(scheduler.find(JobMoneyDropExpiry), jobTriggerSchedule)
+	}()
+	waitForStart(t, txClient)
+
+	locks.steal(JobMoneyDropExpiry, "scheduler-b", time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the run to be cancelled after losing the lease")
+	}
+	if len(repo.runs) != 1 || repo.runs[0].Status != jobStatusFailed {
+		t.Fatalf("expected a failed run after losing the lease, got %+v", repo.runs)
+	}
+	if locks.leases[JobMoneyDropExpiry].holder != "scheduler-b" {
+		t.Fatal("expected the new holder's lease to be left in place")
+	}
+}
@@ -6,23 +6,34 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/transfa/scheduler-service/internal/config"
 	"github.com/transfa/scheduler-service/internal/domain"
+	"github.com/transfa/scheduler-service/pkg/transactionclient"
 )
 
 // Repository defines database operations needed by the jobs.
 type Repository interface {
-	GetExpiredAndCompletedMoneyDrops(ctx context.Context) ([]domain.MoneyDrop, error)
 	HasPendingMoneyDropClaimReconciliationCandidates(ctx context.Context, olderThan time.Time) (bool, error)
+	RecordJobRun(ctx context.Context, run domain.JobRun) error
+	ListLatestJobRuns(ctx context.Context) (map[string]domain.JobRun, error)
+	AcquireJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error)
+	RenewJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error)
+	ReleaseJobLock(ctx context.Context, jobName, holder string) error
 }
 
 // TransactionClient defines the interface for communicating with the transaction service.
 type TransactionClient interface {
-	RefundMoneyDrop(ctx context.Context, dropID, creatorID string, amount int64) error
+	ExpireMoneyDrops(ctx context.Context) (*domain.MoneyDropExpirySummary, error)
 	ReconcileMoneyDropClaims(ctx context.Context, limit int) error
+	SyncAccountBalances(ctx context.Context) error
+	ArchiveTransactions(ctx context.Context) error
+	CleanupNotifications(ctx context.Context) (int64, error)
+	RunReconciliation(ctx context.Context, sampleSize int) error
 }
 
 // PlatformFeeClient defines the interface for platform fee operations.
@@ -53,97 +64,76 @@ func NewJobs(repo Repository, txClient TransactionClient, feeClient PlatformFeeC
 }
 
 // GeneratePlatformFeeInvoices triggers invoice generation.
-func (j *Jobs) GeneratePlatformFeeInvoices() {
+func (j *Jobs) GeneratePlatformFeeInvoices(ctx context.Context) error {
 	j.logger.Info("starting platform fee invoice generation job")
-	ctx := context.Background()
 
 	if err := j.feeClient.GenerateInvoices(ctx); err != nil {
 		j.logger.Error("failed to generate platform fee invoices", "error", err)
-		return
+		return err
 	}
 
 	j.logger.Info("platform fee invoice generation job finished")
+	return nil
 }
 
 // ProcessPlatformFeeAttempts triggers charge attempts.
-func (j *Jobs) ProcessPlatformFeeAttempts() {
+func (j *Jobs) ProcessPlatformFeeAttempts(ctx context.Context) error {
 	j.logger.Info("starting platform fee charge attempts job")
-	ctx := context.Background()
 
 	if err := j.feeClient.RunChargeAttempts(ctx); err != nil {
 		j.logger.Error("failed to run platform fee charge attempts", "error", err)
-		return
+		return err
 	}
 
 	j.logger.Info("platform fee charge attempts job finished")
+	return nil
 }
 
 // ProcessPlatformFeeDelinquency updates delinquent invoices.
-func (j *Jobs) ProcessPlatformFeeDelinquency() {
+func (j *Jobs) ProcessPlatformFeeDelinquency(ctx context.Context) error {
 	j.logger.Info("starting platform fee delinquency job")
-	ctx := context.Background()
 
 	if err := j.feeClient.MarkDelinquent(ctx); err != nil {
 		j.logger.Error("failed to mark delinquent invoices", "error", err)
-		return
+		return err
 	}
 
 	j.logger.Info("platform fee delinquency job finished")
+	return nil
 }
 
-// ProcessMoneyDropExpiry is the job that handles refunding expired or completed money drops.
-func (j *Jobs) ProcessMoneyDropExpiry() {
+// ProcessMoneyDropExpiry asks transaction-service to finalize expired or fully claimed
+// money drops and refund their remaining balances. Finalization takes a per-drop lock
+// there, so overlapping runs from several scheduler instances are safe. Drops that
+// fail to finalize are logged and fail the run; they are retried on the next one.
+func (j *Jobs) ProcessMoneyDropExpiry(ctx context.Context) error {
 	j.logger.Info("starting money drop expiry job")
-	ctx := context.Background()
 
-	drops, err := j.repo.GetExpiredAndCompletedMoneyDrops(ctx)
+	summary, err := j.txClient.ExpireMoneyDrops(ctx)
 	if err != nil {
-		j.logger.Error("failed to get expired money drops", "error", err)
-		return
+		j.logger.Error("failed to expire money drops", "error", err)
+		return err
 	}
 
-	if len(drops) == 0 {
-		j.logger.Info("no expired or completed money drops to process")
-		return
+	for _, failure := range summary.Failures {
+		j.logger.Error("failed to finalize money drop", "drop_id", failure.DropID, "error", failure.Error)
 	}
 
-	j.logger.Info("found money drops to process", "count", len(drops))
-
-	for _, drop := range drops {
-		j.logger.Info("processing money drop", "drop_id", drop.ID, "creator_id", drop.CreatorID)
-
-		totalAmount := drop.TotalAmount
-		if totalAmount <= 0 {
-			// Backward-compatible fallback for legacy rows without total_amount.
-			totalAmount = drop.AmountPerClaim * int64(drop.TotalClaimsAllowed)
-		}
-		claimedAmount := drop.AmountPerClaim * int64(drop.ClaimsMadeCount)
-		remainingBalance := totalAmount - claimedAmount
-		if remainingBalance > 0 {
-			j.logger.Info("refunding remaining balance", "drop_id", drop.ID, "creator_id", drop.CreatorID, "amount", remainingBalance)
-
-			if err := j.txClient.RefundMoneyDrop(ctx, drop.ID, drop.CreatorID, remainingBalance); err != nil {
-				j.logger.Error("failed to refund money drop", "drop_id", drop.ID, "creator_id", drop.CreatorID, "amount", remainingBalance, "error", err)
-				continue
-			}
-			j.logger.Info("successfully refunded money drop", "drop_id", drop.ID, "amount", remainingBalance)
-		} else {
-			if err := j.txClient.RefundMoneyDrop(ctx, drop.ID, drop.CreatorID, 0); err != nil {
-				j.logger.Error("failed to finalize fully-claimed money drop", "drop_id", drop.ID, "creator_id", drop.CreatorID, "error", err)
-				continue
-			}
-		}
-
-		j.logger.Info("successfully processed money drop", "drop_id", drop.ID)
+	j.logger.Info("money drop expiry job finished",
+		"processed", summary.Processed,
+		"expired", summary.Expired,
+		"refunded_amount", summary.RefundedAmount,
+		"failed", summary.Failed,
+	)
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d money drops failed to finalize", summary.Failed, summary.Processed)
 	}
-
-	j.logger.Info("money drop expiry job finished")
+	return nil
 }
 
 // ProcessMoneyDropClaimReconciliation retries stale pending claim payouts in transaction-service.
-func (j *Jobs) ProcessMoneyDropClaimReconciliation() {
+func (j *Jobs) ProcessMoneyDropClaimReconciliation(ctx context.Context) error {
 	j.logger.Info("starting money drop claim reconciliation job")
-	ctx := context.Background()
 
 	cutoff := time.Now().UTC().Add(-2 * time.Minute)
 	hasCandidates, err := j.repo.HasPendingMoneyDropClaimReconciliationCandidates(ctx, cutoff)
@@ -151,14 +141,86 @@ func (j *Jobs) ProcessMoneyDropClaimReconciliation() {
 		j.logger.Warn("failed to pre-check money drop claim reconciliation candidates; continuing", "error", err)
 	} else if !hasCandidates {
 		j.logger.Info("no pending money drop claim reconciliation candidates; skipping")
-		return
+		return nil
 	}
 
 	const limit = 100
 	if err := j.txClient.ReconcileMoneyDropClaims(ctx, limit); err != nil {
 		j.logger.Error("failed to reconcile money drop claims", "error", err)
-		return
+		return err
 	}
 
 	j.logger.Info("money drop claim reconciliation job finished")
+	return nil
+}
+
+// SyncAllAccountBalances triggers a full refresh of internal account balances from Anchor so
+// balances of idle accounts do not drift. The sync itself runs inside transaction-service.
+func (j *Jobs) SyncAllAccountBalances(ctx context.Context) error {
+	j.logger.Info("starting account balance sync job")
+
+	if err := j.txClient.SyncAccountBalances(ctx); err != nil {
+		if errors.Is(err, transactionclient.ErrBalanceSyncInProgress) {
+			j.logger.Info("account balance sync already running; skipping")
+			return nil
+		}
+		j.logger.Error("failed to start account balance sync", "error", err)
+		return err
+	}
+
+	j.logger.Info("account balance sync job started")
+	return nil
+}
+
+// ArchiveOldTransactions triggers the monthly move of old completed and failed
+// transactions into transactions_archive. The run itself happens inside
+// transaction-service.
+func (j *Jobs) ArchiveOldTransactions(ctx context.Context) error {
+	j.logger.Info("starting transaction archive job")
+
+	if err := j.txClient.ArchiveTransactions(ctx); err != nil {
+		if errors.Is(err, transactionclient.ErrTransactionArchiveInProgress) {
+			j.logger.Info("transaction archive already running; skipping")
+			return nil
+		}
+		j.logger.Error("failed to start transaction archive", "error", err)
+		return err
+	}
+
+	j.logger.Info("transaction archive job started")
+	return nil
+}
+
+// CleanupReadNotifications asks transaction-service to delete read in-app
+// notifications that are past their 90-day retention.
+func (j *Jobs) CleanupReadNotifications(ctx context.Context) error {
+	j.logger.Info("starting notification cleanup job")
+
+	deleted, err := j.txClient.CleanupNotifications(ctx)
+	if err != nil {
+		j.logger.Error("failed to clean up notifications", "error", err)
+		return err
+	}
+
+	j.logger.Info("notification cleanup job finished", "deleted", deleted)
+	return nil
+}
+
+// ReconcileAnchorTransfers triggers the daily comparison of yesterday's Anchor
+// transfers with transaction-service's records. The run and its report happen inside
+// transaction-service.
+func (j *Jobs) ReconcileAnchorTransfers(ctx context.Context) error {
+	j.logger.Info("starting reconciliation job", "sample_size", j.config.ReconciliationSampleSize)
+
+	if err := j.txClient.RunReconciliation(ctx, j.config.ReconciliationSampleSize); err != nil {
+		if errors.Is(err, transactionclient.ErrReconciliationInProgress) {
+			j.logger.Info("reconciliation already running; skipping")
+			return nil
+		}
+		j.logger.Error("failed to start reconciliation", "error", err)
+		return err
+	}
+
+	j.logger.Info("reconciliation job started")
+	return nil
 }
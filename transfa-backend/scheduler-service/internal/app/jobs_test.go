@@ -10,20 +10,49 @@ import (
 
 	"github.com/transfa/scheduler-service/internal/config"
 	"github.com/transfa/scheduler-service/internal/domain"
+	"github.com/transfa/scheduler-service/pkg/transactionclient"
 )
 
 type jobsRepoStub struct {
-	drops         []domain.MoneyDrop
-	dropsErr      error
 	hasCandidates bool
 	candidateErr  error
+	runs          []domain.JobRun
+	// locks is the lease table shared between scheduler instances; nil grants every lock.
+	locks *leaseTableStub
 }
 
-func (s *jobsRepoStub) GetExpiredAndCompletedMoneyDrops(ctx context.Context) ([]domain.MoneyDrop, error) {
-	if s.dropsErr != nil {
-		return nil, s.dropsErr
+func (s *jobsRepoStub) RecordJobRun(ctx context.Context, run domain.JobRun) error {
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *jobsRepoStub) ListLatestJobRuns(ctx context.Context) (map[string]domain.JobRun, error) {
+	latest := make(map[string]domain.JobRun)
+	for _, run := range s.runs {
+		latest[run.JobName] = run
+	}
+	return latest, nil
+}
+
+func (s *jobsRepoStub) AcquireJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
+	if s.locks == nil {
+		return true, nil
+	}
+	return s.locks.acquire(jobName, holder, ttl), nil
+}
+
+func (s *jobsRepoStub) RenewJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
+	if s.locks == nil {
+		return true, nil
 	}
-	return s.drops, nil
+	return s.locks.renew(jobName, holder, ttl), nil
+}
+
+func (s *jobsRepoStub) ReleaseJobLock(ctx context.Context, jobName, holder string) error {
+	if s.locks != nil {
+		s.locks.release(jobName, holder)
+	}
+	return nil
 }
 
 func (s *jobsRepoStub) HasPendingMoneyDropClaimReconciliationCandidates(ctx context.Context, olderThan time.Time) (bool, error) {
@@ -34,11 +63,29 @@ func (s *jobsRepoStub) HasPendingMoneyDropClaimReconciliationCandidates(ctx cont
 }
 
 type jobsTxClientStub struct {
+	expireCalls     int
+	expireSummary   *domain.MoneyDropExpirySummary
+	expireErr       error
 	reconcileCalled bool
+	syncCalled      bool
+	syncErr         error
+	archiveCalled   bool
+	archiveErr      error
+	cleanupCalled   bool
+	cleanupErr      error
+	reconcileSample []int
+	reconcileErr    error
 }
 
-func (s *jobsTxClientStub) RefundMoneyDrop(ctx context.Context, dropID, creatorID string, amount int64) error {
-	return nil
+func (s *jobsTxClientStub) ExpireMoneyDrops(ctx context.Context) (*domain.MoneyDropExpirySummary, error) {
+	s.expireCalls++
+	if s.expireErr != nil {
+		return nil, s.expireErr
+	}
+	if s.expireSummary == nil {
+		return &domain.MoneyDropExpirySummary{}, nil
+	}
+	return s.expireSummary, nil
 }
 
 func (s *jobsTxClientStub) ReconcileMoneyDropClaims(ctx context.Context, limit int) error {
@@ -46,6 +93,26 @@ func (s *jobsTxClientStub) ReconcileMoneyDropClaims(ctx context.Context, limit i
 	return nil
 }
 
+func (s *jobsTxClientStub) SyncAccountBalances(ctx context.Context) error {
+	s.syncCalled = true
+	return s.syncErr
+}
+
+func (s *jobsTxClientStub) ArchiveTransactions(ctx context.Context) error {
+	s.archiveCalled = true
+	return s.archiveErr
+}
+
+func (s *jobsTxClientStub) CleanupNotifications(ctx context.Context) (int64, error) {
+	s.cleanupCalled = true
+	return 0, s.cleanupErr
+}
+
+func (s *jobsTxClientStub) RunReconciliation(ctx context.Context, sampleSize int) error {
+	s.reconcileSample = append(s.reconcileSample, sampleSize)
+	return s.reconcileErr
+}
+
 type jobsFeeClientStub struct{}
 
 func (jobsFeeClientStub) GenerateInvoices(ctx context.Context) error  { return nil }
@@ -62,7 +129,7 @@ func TestProcessMoneyDropClaimReconciliation_SkipsWhenNoCandidates(t *testing.T)
 	txClient := &jobsTxClientStub{}
 	jobs := newTestJobs(repo, txClient)
 
-	jobs.ProcessMoneyDropClaimReconciliation()
+	jobs.ProcessMoneyDropClaimReconciliation(context.Background())
 
 	if txClient.reconcileCalled {
 		t.Fatal("expected reconcile call to be skipped when there are no candidates")
@@ -74,7 +141,7 @@ func TestProcessMoneyDropClaimReconciliation_CallsWhenCandidatesExist(t *testing
 	txClient := &jobsTxClientStub{}
 	jobs := newTestJobs(repo, txClient)
 
-	jobs.ProcessMoneyDropClaimReconciliation()
+	jobs.ProcessMoneyDropClaimReconciliation(context.Background())
 
 	if !txClient.reconcileCalled {
 		t.Fatal("expected reconcile call when candidates exist")
@@ -86,9 +153,121 @@ func TestProcessMoneyDropClaimReconciliation_ContinuesOnPrecheckError(t *testing
 	txClient := &jobsTxClientStub{}
 	jobs := newTestJobs(repo, txClient)
 
-	jobs.ProcessMoneyDropClaimReconciliation()
+	jobs.ProcessMoneyDropClaimReconciliation(context.Background())
 
 	if !txClient.reconcileCalled {
 		t.Fatal("expected reconcile call even when candidate pre-check fails")
 	}
 }
+
+func TestSyncAllAccountBalances_TriggersTransactionServiceSync(t *testing.T) {
+	for _, syncErr := range []error{nil, transactionclient.ErrBalanceSyncInProgress, errors.New("unavailable")} {
+		txClient := &jobsTxClientStub{syncErr: syncErr}
+		jobs := newTestJobs(&jobsRepoStub{}, txClient)
+
+		jobs.SyncAllAccountBalances(context.Background())
+
+		if !txClient.syncCalled {
+			t.Fatalf("expected balance sync to be requested (sync error %v)", syncErr)
+		}
+	}
+}
+
+func TestArchiveOldTransactions_TreatsRunInProgressAsSuccess(t *testing.T) {
+	tests := []struct {
+		archiveErr error
+		wantErr    bool
+	}{
+		{archiveErr: nil},
+		{archiveErr: transactionclient.ErrTransactionArchiveInProgress},
+		{archiveErr: errors.New("unavailable"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		txClient := &jobsTxClientStub{archiveErr: tt.archiveErr}
+		jobs := newTestJobs(&jobsRepoStub{}, txClient)
+
+		err := jobs.ArchiveOldTransactions(context.Background())
+
+		if !txClient.archiveCalled {
+			t.Fatalf("expected archive to be requested (archive error %v)", tt.archiveErr)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("archive error %v: expected error %v, got %v", tt.archiveErr, tt.wantErr, err)
+		}
+	}
+}
+
+func TestCleanupReadNotifications_ReportsFailure(t *testing.T) {
+	for _, cleanupErr := range []error{nil, errors.New("unavailable")} {
+		txClient := &jobsTxClientStub{cleanupErr: cleanupErr}
+		jobs := newTestJobs(&jobsRepoStub{}, txClient)
+
+		err := jobs.CleanupReadNotifications(context.Background())
+
+		if !txClient.cleanupCalled {
+			t.Fatalf("expected cleanup to be requested (cleanup error %v)", cleanupErr)
+		}
+		if !errors.Is(err, cleanupErr) {
+			t.Fatalf("expected error %v, got %v", cleanupErr, err)
+		}
+	}
+}
+
+func TestProcessMoneyDropExpiry_DelegatesToTransactionService(t *testing.T) {
+	tests := []struct {
+		name     string
+		txClient *jobsTxClientStub
+		wantErr  bool
+	}{
+		{name: "all drops finalized", txClient: &jobsTxClientStub{expireSummary: &domain.MoneyDropExpirySummary{Processed: 2, Expired: 2}}},
+		{name: "some drops failed", txClient: &jobsTxClientStub{expireSummary: &domain.MoneyDropExpirySummary{
+			Processed: 2,
+			Expired:   1,
+			Failed:    1,
+			Failures:  []domain.MoneyDropExpiryFailure{{DropID: "drop-1", Error: "anchor unavailable"}},
+		}}, wantErr: true},
+		{name: "transaction service unavailable", txClient: &jobsTxClientStub{expireErr: errors.New("unavailable")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := newTestJobs(&jobsRepoStub{}, tt.txClient)
+
+			err := jobs.ProcessMoneyDropExpiry(context.Background())
+
+			if tt.txClient.expireCalls != 1 {
+				t.Fatalf("expected one expiry request, got %d", tt.txClient.expireCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReconcileAnchorTransfers_SendsSampleSizeAndSkipsRunInProgress(t *testing.T) {
+	tests := []struct {
+		reconcileErr error
+		wantErr      bool
+	}{
+		{reconcileErr: nil},
+		{reconcileErr: transactionclient.ErrReconciliationInProgress},
+		{reconcileErr: errors.New("unavailable"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		txClient := &jobsTxClientStub{reconcileErr: tt.reconcileErr}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		jobs := NewJobs(&jobsRepoStub{}, txClient, jobsFeeClientStub{}, logger, config.Config{ReconciliationSampleSize: 50})
+
+		err := jobs.ReconcileAnchorTransfers(context.Background())
+
+		if len(txClient.reconcileSample) != 1 || txClient.reconcileSample[0] != 50 {
+			t.Fatalf("expected one reconciliation run for 50 accounts, got %v", txClient.reconcileSample)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("reconcile error %v: expected error %v, got %v", tt.reconcileErr, tt.wantErr, err)
+		}
+	}
+}
@@ -1,23 +1,76 @@
 /**
  * @description
  * Cron scheduler setup for scheduled jobs.
+ *
+ * @notes
+ * - Every job run, scheduled or manual, is recorded in scheduler_job_runs.
+ * - A job never overlaps with itself within one instance; a tick that arrives while
+ *   the previous run is still going is skipped.
+ * - Across replicas, a run first claims the job's lease in scheduler_locks; when
+ *   another instance holds it the run is skipped and counted in lock_skips.
  */
 package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/transfa/scheduler-service/internal/config"
+	"github.com/transfa/scheduler-service/internal/domain"
 )
 
+// Job names used in logs, the admin API and scheduler_job_runs.
+const (
+	JobPlatformFeeInvoices     = "platform_fee_invoices"
+	JobPlatformFeeCharges      = "platform_fee_charges"
+	JobPlatformFeeDelinquency  = "platform_fee_delinquency"
+	JobMoneyDropExpiry         = "money_drop_expiry"
+	JobMoneyDropClaimReconcile = "money_drop_claim_reconcile"
+	JobAccountBalanceSync      = "account_balance_sync"
+	JobTransactionArchive      = "transaction_archive"
+	JobNotificationCleanup     = "notification_cleanup"
+	JobReconciliation          = "reconciliation"
+)
+
+// Job run triggers and statuses stored in scheduler_job_runs.
+const (
+	jobTriggerSchedule  = "schedule"
+	jobTriggerManual    = "manual"
+	jobStatusSucceeded  = "succeeded"
+	jobStatusFailed     = "failed"
+	jobRunRecordTimeout = 5 * time.Second
+)
+
+var (
+	// ErrJobNotFound is returned for an unknown job name.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a manual run is requested while the job is running.
+	ErrJobRunning = errors.New("job is already running")
+)
+
+type scheduledJob struct {
+	name     string
+	schedule string
+	enabled  bool
+	run      func(ctx context.Context) error
+	entryID  cron.EntryID
+	running  atomic.Bool
+	// lockSkips counts runs skipped because another instance held the job's lease.
+	lockSkips atomic.Int64
+}
+
 // Scheduler manages the cron jobs.
 type Scheduler struct {
 	cron   *cron.Cron
 	jobs   *Jobs
 	logger *slog.Logger
 	config config.Config
+	list   []*scheduledJob
 }
 
 // NewScheduler creates a new scheduler instance.
@@ -30,45 +83,141 @@ func NewScheduler(jobs *Jobs, logger *slog.Logger, cfg config.Config) *Scheduler
 		jobs:   jobs,
 		logger: logger,
 		config: cfg,
+		list: []*scheduledJob{
+			{name: JobPlatformFeeInvoices, schedule: cfg.PlatformFeeInvoiceJobSchedule, enabled: cfg.PlatformFeeInvoiceJobEnabled, run: jobs.GeneratePlatformFeeInvoices},
+			{name: JobPlatformFeeCharges, schedule: cfg.PlatformFeeChargeJobSchedule, enabled: cfg.PlatformFeeChargeJobEnabled, run: jobs.ProcessPlatformFeeAttempts},
+			{name: JobPlatformFeeDelinquency, schedule: cfg.PlatformFeeDelinqJobSchedule, enabled: cfg.PlatformFeeDelinqJobEnabled, run: jobs.ProcessPlatformFeeDelinquency},
+			{name: JobMoneyDropExpiry, schedule: cfg.MoneyDropExpirySchedule, enabled: cfg.MoneyDropExpiryEnabled, run: jobs.ProcessMoneyDropExpiry},
+			{name: JobMoneyDropClaimReconcile, schedule: cfg.MoneyDropClaimReconcileSchedule, enabled: cfg.MoneyDropClaimReconcileEnabled, run: jobs.ProcessMoneyDropClaimReconciliation},
+			{name: JobAccountBalanceSync, schedule: cfg.AccountBalanceSyncSchedule, enabled: cfg.AccountBalanceSyncEnabled, run: jobs.SyncAllAccountBalances},
+			{name: JobTransactionArchive, schedule: cfg.TransactionArchiveSchedule, enabled: cfg.TransactionArchiveEnabled, run: jobs.ArchiveOldTransactions},
+			{name: JobNotificationCleanup, schedule: cfg.NotificationCleanupSchedule, enabled: cfg.NotificationCleanupEnabled, run: jobs.CleanupReadNotifications},
+			{name: JobReconciliation, schedule: cfg.ReconciliationSchedule, enabled: cfg.ReconciliationEnabled, run: jobs.ReconcileAnchorTransfers},
+		},
 	}
 }
 
-// Start registers the jobs and starts the cron scheduler.
+// Start registers the enabled jobs and starts the cron scheduler.
 func (s *Scheduler) Start() {
-	if _, err := s.cron.AddFunc(s.config.PlatformFeeInvoiceJobSchedule, s.jobs.GeneratePlatformFeeInvoices); err != nil {
-		s.logger.Error("failed to schedule platform fee invoice job", "error", err)
-	} else {
-		s.logger.Info("scheduled platform fee invoice job", "schedule", s.config.PlatformFeeInvoiceJobSchedule)
+	for _, job := range s.list {
+		if !job.enabled {
+			s.logger.Warn("job disabled by configuration", "job", job.name, "schedule", job.schedule)
+			continue
+		}
+
+		job := job
+		entryID, err := s.cron.AddFunc(job.schedule, func() { s.execute(job, jobTriggerSchedule) })
+		if err != nil {
+			s.logger.Error("failed to schedule job", "job", job.name, "schedule", job.schedule, "error", err)
+			continue
+		}
+		job.entryID = entryID
+		s.logger.Info("scheduled job", "job", job.name, "schedule", job.schedule)
+	}
+
+	s.cron.Start()
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+// RunJob starts a manual run of the named job in the background. Disabled jobs can
+// still be run manually.
+func (s *Scheduler) RunJob(name string) error {
+	job := s.find(name)
+	if job == nil {
+		return ErrJobNotFound
+	}
+	if !job.running.CompareAndSwap(false, true) {
+		return ErrJobRunning
 	}
 
-	if _, err := s.cron.AddFunc(s.config.PlatformFeeChargeJobSchedule, s.jobs.ProcessPlatformFeeAttempts); err != nil {
-		s.logger.Error("failed to schedule platform fee charge job", "error", err)
-	} else {
-		s.logger.Info("scheduled platform fee charge job", "schedule", s.config.PlatformFeeChargeJobSchedule)
+	go func() {
+		defer job.running.Store(false)
+		s.runAndRecord(job, jobTriggerManual)
+	}()
+	return nil
+}
+
+// JobStatuses describes every registered job with its latest recorded run.
+func (s *Scheduler) JobStatuses(ctx context.Context) ([]domain.JobStatus, error) {
+	latest, err := s.jobs.repo.ListLatestJobRuns(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, err := s.cron.AddFunc(s.config.PlatformFeeDelinqJobSchedule, s.jobs.ProcessPlatformFeeDelinquency); err != nil {
-		s.logger.Error("failed to schedule platform fee delinquency job", "error", err)
-	} else {
-		s.logger.Info("scheduled platform fee delinquency job", "schedule", s.config.PlatformFeeDelinqJobSchedule)
+	statuses := make([]domain.JobStatus, 0, len(s.list))
+	for _, job := range s.list {
+		status := domain.JobStatus{
+			Name:      job.name,
+			Schedule:  job.schedule,
+			Enabled:   job.enabled,
+			Running:   job.running.Load(),
+			LockSkips: job.lockSkips.Load(),
+		}
+		if run, ok := latest[job.name]; ok {
+			status.LastRun = &run
+		}
+		if job.entryID != 0 {
+			if next := s.cron.Entry(job.entryID).Next; !next.IsZero() {
+				status.NextRun = &next
+			}
+		}
+		statuses = append(statuses, status)
 	}
+	return statuses, nil
+}
 
-	if _, err := s.cron.AddFunc(s.config.MoneyDropExpirySchedule, s.jobs.ProcessMoneyDropExpiry); err != nil {
-		s.logger.Error("failed to schedule money drop expiry job", "error", err)
-	} else {
-		s.logger.Info("scheduled money drop expiry job", "schedule", s.config.MoneyDropExpirySchedule)
+func (s *Scheduler) find(name string) *scheduledJob {
+	for _, job := range s.list {
+		if job.name == name {
+			return job
+		}
 	}
+	return nil
+}
 
-	if _, err := s.cron.AddFunc(s.config.MoneyDropClaimReconcileSchedule, s.jobs.ProcessMoneyDropClaimReconciliation); err != nil {
-		s.logger.Error("failed to schedule money drop claim reconciliation job", "error", err)
-	} else {
-		s.logger.Info("scheduled money drop claim reconciliation job", "schedule", s.config.MoneyDropClaimReconcileSchedule)
+func (s *Scheduler) execute(job *scheduledJob, trigger string) {
+	if !job.running.CompareAndSwap(false, true) {
+		s.logger.Warn("previous run still in progress; skipping", "job", job.name)
+		return
 	}
+	defer job.running.Store(false)
+	s.runAndRecord(job, trigger)
+}
 
-	s.cron.Start()
+func (s *Scheduler) runAndRecord(job *scheduledJob, trigger string) {
+	lease := s.acquireJobLease(job)
+	if lease == nil {
+		return
+	}
+	defer lease.release()
+
+	run := domain.JobRun{JobName: job.name, Trigger: trigger, StartedAt: time.Now().UTC()}
+	err := runRecovered(lease.ctx, job.run)
+	run.FinishedAt = time.Now().UTC()
+	run.Status = jobStatusSucceeded
+	if err != nil {
+		message := err.Error()
+		run.Status = jobStatusFailed
+		run.Error = &message
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobRunRecordTimeout)
+	defer cancel()
+	if recordErr := s.jobs.repo.RecordJobRun(ctx, run); recordErr != nil {
+		s.logger.Error("failed to record job run", "job", job.name, "status", run.Status, "error", recordErr)
+	}
 }
 
-// Stop gracefully stops the cron scheduler.
-func (s *Scheduler) Stop() context.Context {
-	return s.cron.Stop()
+// runRecovered turns a panic in a job into a failed run so it is still recorded.
+func runRecovered(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return run(ctx)
 }
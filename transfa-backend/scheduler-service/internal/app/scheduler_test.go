@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/transfa/scheduler-service/internal/config"
+	"github.com/transfa/scheduler-service/internal/domain"
+)
+
+func newTestScheduler(repo *jobsRepoStub, txClient *jobsTxClientStub, cfg config.Config) *Scheduler {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewScheduler(NewJobs(repo, txClient, jobsFeeClientStub{}, logger, cfg), logger, cfg)
+}
+
+func TestSchedulerExecute_RecordsFailedRun(t *testing.T) {
+	repo := &jobsRepoStub{}
+	scheduler := newTestScheduler(repo, &jobsTxClientStub{expireErr: errors.New("unavailable")}, config.Config{})
+
+	scheduler.execute(scheduler.find(JobMoneyDropExpiry), jobTriggerSchedule)
+
+	if len(repo.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(repo.runs))
+	}
+	run := repo.runs[0]
+	if run.JobName != JobMoneyDropExpiry || run.Trigger != jobTriggerSchedule || run.Status != jobStatusFailed {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if run.Error == nil || *run.Error != "unavailable" {
+		t.Fatalf("expected run error to be recorded, got %v", run.Error)
+	}
+}
+
+func TestSchedulerRunJob_RunsDisabledJobManually(t *testing.T) {
+	repo := &jobsRepoStub{}
+	txClient := &jobsTxClientStub{}
+	scheduler := newTestScheduler(repo, txClient, config.Config{MoneyDropExpirySchedule: "* * * * *", MoneyDropExpiryEnabled: false})
+
+	if err := scheduler.RunJob(JobMoneyDropExpiry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job := scheduler.find(JobMoneyDropExpiry)
+	deadline := time.Now().Add(time.Second)
+	for job.running.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	statuses, err := scheduler.JobStatuses(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var status domain.JobStatus
+	for _, item := range statuses {
+		if item.Name == JobMoneyDropExpiry {
+			status = item
+		}
+	}
+	if status.Enabled || status.NextRun != nil {
+		t.Fatalf("expected a disabled job without a next run, got %+v", status)
+	}
+	if status.LastRun == nil || status.LastRun.Trigger != jobTriggerManual || status.LastRun.Status != jobStatusSucceeded {
+		t.Fatalf("expected a successful manual run, got %+v", status.LastRun)
+	}
+	if txClient.expireCalls != 1 {
+		t.Fatalf("expected the job to run once, got %d", txClient.expireCalls)
+	}
+}
+
+func TestSchedulerRunJob_RejectsUnknownAndRunningJobs(t *testing.T) {
+	scheduler := newTestScheduler(&jobsRepoStub{}, &jobsTxClientStub{}, config.Config{})
+
+	if err := scheduler.RunJob("unknown"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+
+	scheduler.find(JobAccountBalanceSync).running.Store(true)
+	if err := scheduler.RunJob(JobAccountBalanceSync); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("expected ErrJobRunning, got %v", err)
+	}
+}
@@ -6,24 +6,69 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config holds all configuration for the scheduler service.
 type Config struct {
-	DatabaseURL                      string `mapstructure:"DATABASE_URL"`
-	InternalAPIKey                   string `mapstructure:"INTERNAL_API_KEY"`
-	TransactionServiceURL            string `mapstructure:"TRANSACTION_SERVICE_URL"`
-	TransactionServiceInternalAPIKey string `mapstructure:"TRANSACTION_SERVICE_INTERNAL_API_KEY"`
-	PlatformFeeServiceURL            string `mapstructure:"PLATFORM_FEE_SERVICE_URL"`
-	PlatformFeeInternalAPIKey        string `mapstructure:"PLATFORM_FEE_INTERNAL_API_KEY"`
-	PlatformFeeInvoiceJobSchedule    string `mapstructure:"PLATFORM_FEE_INVOICE_JOB_SCHEDULE"`
-	PlatformFeeChargeJobSchedule     string `mapstructure:"PLATFORM_FEE_CHARGE_JOB_SCHEDULE"`
-	PlatformFeeDelinqJobSchedule     string `mapstructure:"PLATFORM_FEE_DELINQ_JOB_SCHEDULE"`
-	MoneyDropExpirySchedule          string `mapstructure:"MONEY_DROP_EXPIRY_SCHEDULE"`
-	MoneyDropClaimReconcileSchedule  string `mapstructure:"MONEY_DROP_CLAIM_RECONCILE_SCHEDULE"`
+	DatabaseURL                      string        `mapstructure:"DATABASE_URL"`
+	InternalAPIKey                   string        `mapstructure:"INTERNAL_API_KEY"`
+	TransactionServiceURL            string        `mapstructure:"TRANSACTION_SERVICE_URL"`
+	TransactionServiceInternalAPIKey string        `mapstructure:"TRANSACTION_SERVICE_INTERNAL_API_KEY"`
+	PlatformFeeServiceURL            string        `mapstructure:"PLATFORM_FEE_SERVICE_URL"`
+	PlatformFeeInternalAPIKey        string        `mapstructure:"PLATFORM_FEE_INTERNAL_API_KEY"`
+	PlatformFeeInvoiceJobSchedule    string        `mapstructure:"PLATFORM_FEE_INVOICE_JOB_SCHEDULE"`
+	PlatformFeeChargeJobSchedule     string        `mapstructure:"PLATFORM_FEE_CHARGE_JOB_SCHEDULE"`
+	PlatformFeeDelinqJobSchedule     string        `mapstructure:"PLATFORM_FEE_DELINQ_JOB_SCHEDULE"`
+	MoneyDropExpirySchedule          string        `mapstructure:"MONEY_DROP_EXPIRY_SCHEDULE"`
+	MoneyDropClaimReconcileSchedule  string        `mapstructure:"MONEY_DROP_CLAIM_RECONCILE_SCHEDULE"`
+	AccountBalanceSyncSchedule       string        `mapstructure:"ACCOUNT_BALANCE_SYNC_SCHEDULE"`
+	TransactionArchiveSchedule       string        `mapstructure:"TRANSACTION_ARCHIVE_SCHEDULE"`
+	NotificationCleanupSchedule      string        `mapstructure:"NOTIFICATION_CLEANUP_SCHEDULE"`
+	ReconciliationSchedule           string        `mapstructure:"RECONCILIATION_SCHEDULE"`
+	PlatformFeeInvoiceJobEnabled     bool          `mapstructure:"PLATFORM_FEE_INVOICE_JOB_ENABLED"`
+	PlatformFeeChargeJobEnabled      bool          `mapstructure:"PLATFORM_FEE_CHARGE_JOB_ENABLED"`
+	PlatformFeeDelinqJobEnabled      bool          `mapstructure:"PLATFORM_FEE_DELINQ_JOB_ENABLED"`
+	MoneyDropExpiryEnabled           bool          `mapstructure:"MONEY_DROP_EXPIRY_ENABLED"`
+	MoneyDropClaimReconcileEnabled   bool          `mapstructure:"MONEY_DROP_CLAIM_RECONCILE_ENABLED"`
+	AccountBalanceSyncEnabled        bool          `mapstructure:"ACCOUNT_BALANCE_SYNC_ENABLED"`
+	TransactionArchiveEnabled        bool          `mapstructure:"TRANSACTION_ARCHIVE_ENABLED"`
+	NotificationCleanupEnabled       bool          `mapstructure:"NOTIFICATION_CLEANUP_ENABLED"`
+	ReconciliationEnabled            bool          `mapstructure:"RECONCILIATION_ENABLED"`
+	ReconciliationSampleSize         int           `mapstructure:"RECONCILIATION_SAMPLE_SIZE"`
+	AdminPort                        string        `mapstructure:"ADMIN_PORT"`
+	InstanceID                       string        `mapstructure:"INSTANCE_ID"`
+	JobLockTTL                       time.Duration `mapstructure:"JOB_LOCK_TTL"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// jobEnabledKeys are the per-job switches; every job is enabled unless turned off.
+var jobEnabledKeys = []string{
+	"PLATFORM_FEE_INVOICE_JOB_ENABLED",
+	"PLATFORM_FEE_CHARGE_JOB_ENABLED",
+	"PLATFORM_FEE_DELINQ_JOB_ENABLED",
+	"MONEY_DROP_EXPIRY_ENABLED",
+	"MONEY_DROP_CLAIM_RECONCILE_ENABLED",
+	"ACCOUNT_BALANCE_SYNC_ENABLED",
+	"TRANSACTION_ARCHIVE_ENABLED",
+	"NOTIFICATION_CLEANUP_ENABLED",
+	"RECONCILIATION_ENABLED",
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        100,
+	MinConns:        20,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from environment variables.
@@ -31,8 +76,20 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("PLATFORM_FEE_INVOICE_JOB_SCHEDULE", "5 0 1 * *")
 	viper.SetDefault("PLATFORM_FEE_CHARGE_JOB_SCHEDULE", "15 0 * * *")
 	viper.SetDefault("PLATFORM_FEE_DELINQ_JOB_SCHEDULE", "30 0 * * *")
-	viper.SetDefault("MONEY_DROP_EXPIRY_SCHEDULE", "*/5 * * * *")
+	viper.SetDefault("MONEY_DROP_EXPIRY_SCHEDULE", "* * * * *")
 	viper.SetDefault("MONEY_DROP_CLAIM_RECONCILE_SCHEDULE", "*/2 * * * *")
+	viper.SetDefault("ACCOUNT_BALANCE_SYNC_SCHEDULE", "0 2 * * *") // 02:00 server local time
+	viper.SetDefault("TRANSACTION_ARCHIVE_SCHEDULE", "0 3 1 * *")  // 03:00 on the 1st of each month
+	// Read notifications are cleaned up daily at 03:30 server local time.
+	viper.SetDefault("NOTIFICATION_CLEANUP_SCHEDULE", "30 3 * * *")
+	// Yesterday's (UTC) Anchor transfers are reconciled daily at 04:00 server local time.
+	viper.SetDefault("RECONCILIATION_SCHEDULE", "0 4 * * *")
+	viper.SetDefault("RECONCILIATION_SAMPLE_SIZE", 0)
+	for _, key := range jobEnabledKeys {
+		viper.SetDefault(key, true)
+	}
+	viper.SetDefault("ADMIN_PORT", "8090")
+	viper.SetDefault("JOB_LOCK_TTL", "2m")
 	viper.AutomaticEnv()
 
 	_ = viper.BindEnv("DATABASE_URL")
@@ -46,6 +103,17 @@ func LoadConfig() (*Config, error) {
 	_ = viper.BindEnv("PLATFORM_FEE_DELINQ_JOB_SCHEDULE")
 	_ = viper.BindEnv("MONEY_DROP_EXPIRY_SCHEDULE")
 	_ = viper.BindEnv("MONEY_DROP_CLAIM_RECONCILE_SCHEDULE")
+	_ = viper.BindEnv("ACCOUNT_BALANCE_SYNC_SCHEDULE")
+	_ = viper.BindEnv("TRANSACTION_ARCHIVE_SCHEDULE")
+	_ = viper.BindEnv("NOTIFICATION_CLEANUP_SCHEDULE")
+	_ = viper.BindEnv("RECONCILIATION_SCHEDULE")
+	_ = viper.BindEnv("RECONCILIATION_SAMPLE_SIZE")
+	for _, key := range jobEnabledKeys {
+		_ = viper.BindEnv(key)
+	}
+	_ = viper.BindEnv("ADMIN_PORT")
+	_ = viper.BindEnv("INSTANCE_ID")
+	_ = viper.BindEnv("JOB_LOCK_TTL")
 
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
@@ -58,6 +126,13 @@ func LoadConfig() (*Config, error) {
 	config.PlatformFeeServiceURL = strings.TrimSpace(config.PlatformFeeServiceURL)
 	config.TransactionServiceInternalAPIKey = strings.TrimSpace(config.TransactionServiceInternalAPIKey)
 	config.PlatformFeeInternalAPIKey = strings.TrimSpace(config.PlatformFeeInternalAPIKey)
+	config.AdminPort = strings.TrimSpace(config.AdminPort)
+	config.InstanceID = strings.TrimSpace(config.InstanceID)
+
+	// Job leases are keyed by instance, so every replica needs a distinct ID.
+	if config.InstanceID == "" {
+		config.InstanceID = defaultInstanceID()
+	}
 
 	// Backward-compatible fallback: allow a shared internal key when per-service
 	// keys are not explicitly configured.
@@ -87,6 +162,66 @@ func LoadConfig() (*Config, error) {
 	if len(missing) > 0 {
 		return nil, fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
+	if config.ReconciliationSampleSize < 0 {
+		return nil, fmt.Errorf("invalid RECONCILIATION_SAMPLE_SIZE %d: must not be negative", config.ReconciliationSampleSize)
+	}
+	if config.JobLockTTL <= 0 {
+		return nil, fmt.Errorf("invalid JOB_LOCK_TTL %q: must be positive", config.JobLockTTL)
+	}
+
+	if err := validateSchedules(&config); err != nil {
+		return nil, err
+	}
+
+	pool, err := dbpool.Load(databasePoolDefaults, viper.GetString)
+	if err != nil {
+		return nil, err
+	}
+	config.DatabasePool = pool
 
 	return &config, nil
 }
+
+// validateSchedules rejects invalid cron expressions for enabled jobs so a typo fails
+// startup instead of silently leaving a job unscheduled.
+func validateSchedules(config *Config) error {
+	schedules := []struct {
+		key      string
+		schedule *string
+		enabled  bool
+	}{
+		{"PLATFORM_FEE_INVOICE_JOB_SCHEDULE", &config.PlatformFeeInvoiceJobSchedule, config.PlatformFeeInvoiceJobEnabled},
+		{"PLATFORM_FEE_CHARGE_JOB_SCHEDULE", &config.PlatformFeeChargeJobSchedule, config.PlatformFeeChargeJobEnabled},
+		{"PLATFORM_FEE_DELINQ_JOB_SCHEDULE", &config.PlatformFeeDelinqJobSchedule, config.PlatformFeeDelinqJobEnabled},
+		{"MONEY_DROP_EXPIRY_SCHEDULE", &config.MoneyDropExpirySchedule, config.MoneyDropExpiryEnabled},
+		{"MONEY_DROP_CLAIM_RECONCILE_SCHEDULE", &config.MoneyDropClaimReconcileSchedule, config.MoneyDropClaimReconcileEnabled},
+		{"ACCOUNT_BALANCE_SYNC_SCHEDULE", &config.AccountBalanceSyncSchedule, config.AccountBalanceSyncEnabled},
+		{"TRANSACTION_ARCHIVE_SCHEDULE", &config.TransactionArchiveSchedule, config.TransactionArchiveEnabled},
+		{"NOTIFICATION_CLEANUP_SCHEDULE", &config.NotificationCleanupSchedule, config.NotificationCleanupEnabled},
+		{"RECONCILIATION_SCHEDULE", &config.ReconciliationSchedule, config.ReconciliationEnabled},
+	}
+
+	var invalid []string
+	for _, item := range schedules {
+		*item.schedule = strings.TrimSpace(*item.schedule)
+		if !item.enabled {
+			continue
+		}
+		if _, err := cron.ParseStandard(*item.schedule); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s=%q (%v)", item.key, *item.schedule, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid cron schedule: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// defaultInstanceID identifies this process as hostname-pid.
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || strings.TrimSpace(hostname) == "" {
+		hostname = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
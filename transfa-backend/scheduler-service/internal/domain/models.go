@@ -4,22 +4,52 @@
  */
 package domain
 
-// MoneyDrop represents the data needed by the scheduler service
-// to process expired or completed money drops.
-type MoneyDrop struct {
-	ID                     string `json:"id"`
-	CreatorID              string `json:"creator_id"`
-	TotalAmount            int64  `json:"total_amount"`
-	AmountPerClaim         int64  `json:"amount_per_claim"`
-	TotalClaimsAllowed     int    `json:"total_claims_allowed"`
-	ClaimsMadeCount        int    `json:"claims_made_count"`
-	FundingSourceAccountID string `json:"funding_source_account_id"`
-	MoneyDropAccountID     string `json:"money_drop_account_id"`
+import "time"
+
+// MoneyDropExpirySummary is the result of a transaction-service money drop expiry run.
+type MoneyDropExpirySummary struct {
+	Processed      int                      `json:"processed"`
+	Expired        int                      `json:"expired"`
+	RefundedAmount int64                    `json:"refunded_amount"`
+	Failed         int                      `json:"failed"`
+	Failures       []MoneyDropExpiryFailure `json:"failures"`
+}
+
+// MoneyDropExpiryFailure identifies a drop transaction-service could not finalize.
+type MoneyDropExpiryFailure struct {
+	DropID string `json:"drop_id"`
+	Error  string `json:"error"`
+}
+
+// ExpiredMoneyDrop is an active drop past its expiry that transaction-service has not
+// finalized yet.
+type ExpiredMoneyDrop struct {
+	ID                 string    `json:"id"`
+	CreatorID          string    `json:"creator_id"`
+	ExpiryTimestamp    time.Time `json:"expiry_timestamp"`
+	ClaimsMadeCount    int       `json:"claims_made_count"`
+	TotalClaimsAllowed int       `json:"total_claims_allowed"`
+	RefundedAmount     int64     `json:"refunded_amount"`
+}
+
+// JobRun is one finished run of a scheduled job.
+type JobRun struct {
+	JobName    string    `json:"job_name"`
+	Trigger    string    `json:"trigger"`
+	Status     string    `json:"status"`
+	Error      *string   `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
 }
 
-// RefundPayload defines the structure for the money drop refund request.
-type RefundPayload struct {
-	DropID    string `json:"drop_id"`
-	CreatorID string `json:"creator_id"`
-	Amount    int64  `json:"amount"`
+// JobStatus describes a registered job for the admin API.
+type JobStatus struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Enabled  bool   `json:"enabled"`
+	Running  bool   `json:"running"`
+	// LockSkips counts runs this instance skipped because another replica held the lease.
+	LockSkips int64      `json:"lock_skips"`
+	LastRun   *JobRun    `json:"last_run,omitempty"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
 }
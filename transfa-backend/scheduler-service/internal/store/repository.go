@@ -1,6 +1,6 @@
 /**
  * @description
- * Data access layer for scheduler-service (money drop jobs).
+ * Data access layer for scheduler-service (money drop jobs, job run history and job leases).
  */
 package store
 
@@ -57,19 +57,22 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
-// GetExpiredAndCompletedMoneyDrops finds all expired or fully claimed money drops.
-func (r *Repository) GetExpiredAndCompletedMoneyDrops(ctx context.Context) ([]domain.MoneyDrop, error) {
-	var drops []domain.MoneyDrop
+// RecordJobRun stores a finished job run.
+func (r *Repository) RecordJobRun(ctx context.Context, run domain.JobRun) error {
 	query := `
-		SELECT id, creator_id, total_amount, amount_per_claim, total_claims_allowed,
-		       claims_made_count, funding_source_account_id, money_drop_account_id
-		FROM money_drops
-		WHERE (status = 'active' AND (expiry_timestamp <= NOW() OR claims_made_count >= total_claims_allowed))
-		   OR (
-		       status = 'completed'
-		       AND ended_reason IN ('refund_retry_pending', 'refund_processing', 'refund_payout_inflight')
-		       AND ended_at <= (NOW() - INTERVAL '5 minutes')
-		   )
+		INSERT INTO scheduler_job_runs (job_name, trigger, status, error_message, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(ctx, query, run.JobName, run.Trigger, run.Status, run.Error, run.StartedAt, run.FinishedAt)
+	return err
+}
+
+// ListLatestJobRuns returns the most recent run of every job, keyed by job name.
+func (r *Repository) ListLatestJobRuns(ctx context.Context) (map[string]domain.JobRun, error) {
+	query := `
+		SELECT DISTINCT ON (job_name) job_name, trigger, status, error_message, started_at, finished_at
+		FROM scheduler_job_runs
+		ORDER BY job_name, started_at DESC
 	`
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -77,36 +80,54 @@ func (r *Repository) GetExpiredAndCompletedMoneyDrops(ctx context.Context) ([]do
 	}
 	defer rows.Close()
 
+	runs := make(map[string]domain.JobRun)
 	for rows.Next() {
-		var drop domain.MoneyDrop
-		err := rows.Scan(
-			&drop.ID, &drop.CreatorID, &drop.TotalAmount, &drop.AmountPerClaim, &drop.TotalClaimsAllowed,
-			&drop.ClaimsMadeCount, &drop.FundingSourceAccountID, &drop.MoneyDropAccountID)
-		if err != nil {
+		var run domain.JobRun
+		if err := rows.Scan(&run.JobName, &run.Trigger, &run.Status, &run.Error, &run.StartedAt, &run.FinishedAt); err != nil {
 			return nil, err
 		}
-		drops = append(drops, drop)
+		runs[run.JobName] = run
 	}
+	return runs, rows.Err()
+}
 
-	return drops, nil
+// AcquireJobLock claims the lease for a job. It succeeds when no lease exists, the
+// current lease has expired, or holder already owns it.
+func (r *Repository) AcquireJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO scheduler_locks (job_name, holder, acquired_at, locked_until)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (job_name) DO UPDATE
+		SET holder = EXCLUDED.holder,
+			acquired_at = EXCLUDED.acquired_at,
+			locked_until = EXCLUDED.locked_until
+		WHERE scheduler_locks.locked_until < NOW()
+		   OR scheduler_locks.holder = EXCLUDED.holder
+	`
+	tag, err := r.db.Exec(ctx, query, jobName, holder, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
 }
 
-// UpdateMoneyDropStatus updates the status of a money drop.
-func (r *Repository) UpdateMoneyDropStatus(ctx context.Context, dropID string, status string) error {
+// RenewJobLock extends a lease held by holder. It returns false when the lease has
+// been lost to another instance.
+func (r *Repository) RenewJobLock(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
 	query := `
-		UPDATE money_drops
-		SET status = $1,
-		    ended_at = COALESCE(ended_at, NOW()),
-		    ended_reason = COALESCE(
-		        ended_reason,
-		        CASE
-		            WHEN $1 = 'completed' THEN 'completed'
-		            WHEN $1 = 'expired_and_refunded' THEN 'expired'
-		            ELSE 'ended'
-		        END
-		    )
-		WHERE id = $2
+		UPDATE scheduler_locks
+		SET locked_until = NOW() + make_interval(secs => $3)
+		WHERE job_name = $1 AND holder = $2
 	`
-	_, err := r.db.Exec(ctx, query, status, dropID)
+	tag, err := r.db.Exec(ctx, query, jobName, holder, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseJobLock drops a lease held by holder.
+func (r *Repository) ReleaseJobLock(ctx context.Context, jobName, holder string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM scheduler_locks WHERE job_name = $1 AND holder = $2`, jobName, holder)
 	return err
 }
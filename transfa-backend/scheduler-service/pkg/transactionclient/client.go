@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,6 +17,15 @@ import (
 	"github.com/transfa/scheduler-service/internal/domain"
 )
 
+// ErrBalanceSyncInProgress is returned when transaction-service is already running a balance sync.
+var ErrBalanceSyncInProgress = errors.New("balance sync already in progress")
+
+// ErrTransactionArchiveInProgress is returned when transaction-service is already archiving transactions.
+var ErrTransactionArchiveInProgress = errors.New("transaction archive already in progress")
+
+// ErrReconciliationInProgress is returned when transaction-service is already running a reconciliation.
+var ErrReconciliationInProgress = errors.New("reconciliation already in progress")
+
 // Client is a client for the transaction service.
 type Client struct {
 	baseURL    string
@@ -33,46 +43,75 @@ func NewClient(baseURL string, apiKey string) *Client {
 	}
 }
 
-// RefundMoneyDrop calls the transaction-service to refund a money drop.
-func (c *Client) RefundMoneyDrop(ctx context.Context, dropID, creatorID string, amount int64) error {
+// ExpireMoneyDrops asks transaction-service to finalize every expired or fully claimed
+// money drop and refund the remaining balances. The returned summary lists drops that
+// could not be finalized.
+func (c *Client) ExpireMoneyDrops(ctx context.Context) (*domain.MoneyDropExpirySummary, error) {
 	if c.baseURL == "" {
-		return fmt.Errorf("transaction service base URL is not configured")
+		return nil, fmt.Errorf("transaction service base URL is not configured")
 	}
 	if c.apiKey == "" {
-		return fmt.Errorf("transaction service internal api key is not configured")
+		return nil, fmt.Errorf("transaction service internal api key is not configured")
 	}
 
-	url := c.internalMoneyDropURL("/refund")
-
-	payload := domain.RefundPayload{
-		DropID:    dropID,
-		CreatorID: creatorID,
-		Amount:    amount,
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.internalMoneyDropURL("/expire"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
 
-	body, err := json.Marshal(payload)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal refund payload: %w", err)
+		return nil, fmt.Errorf("failed to execute expiry request to transaction service: %w", err)
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+	}
+
+	var summary domain.MoneyDropExpirySummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to decode expiry summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// ListExpiredActiveMoneyDrops returns up to limit active money drops past their expiry,
+// oldest first. A non-positive limit leaves the default to transaction-service.
+func (c *Client) ListExpiredActiveMoneyDrops(ctx context.Context, limit int) ([]domain.ExpiredMoneyDrop, error) {
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("transaction service base URL is not configured")
+	}
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("transaction service internal api key is not configured")
+	}
+
+	url := c.internalMoneyDropURL("/expired")
+	if limit > 0 {
+		url = fmt.Sprintf("%s?limit=%d", url, limit)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Internal-API-Key", c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request to transaction service: %w", err)
+		return nil, fmt.Errorf("failed to execute expired money drop request to transaction service: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+		return nil, fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
 	}
 
-	return nil
+	var drops []domain.ExpiredMoneyDrop
+	if err := json.NewDecoder(resp.Body).Decode(&drops); err != nil {
+		return nil, fmt.Errorf("failed to decode expired money drops: %w", err)
+	}
+	return drops, nil
 }
 
 // ReconcileMoneyDropClaims triggers internal reconciliation for stale pending money-drop claims.
@@ -114,9 +153,154 @@ func (c *Client) ReconcileMoneyDropClaims(ctx context.Context, limit int) error
 	return nil
 }
 
+// SyncAccountBalances asks transaction-service to refresh every account balance from Anchor.
+// The sync runs in the background there; ErrBalanceSyncInProgress is returned when a
+// previous run has not finished yet.
+func (c *Client) SyncAccountBalances(ctx context.Context) error {
+	if c.baseURL == "" {
+		return fmt.Errorf("transaction service base URL is not configured")
+	}
+	if c.apiKey == "" {
+		return fmt.Errorf("transaction service internal api key is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.internalURL("/accounts/sync-balances"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute balance sync request to transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return ErrBalanceSyncInProgress
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ArchiveTransactions asks transaction-service to move old completed and failed
+// transactions into the archive. The run happens in the background there;
+// ErrTransactionArchiveInProgress is returned when a previous run has not finished yet.
+func (c *Client) ArchiveTransactions(ctx context.Context) error {
+	if c.baseURL == "" {
+		return fmt.Errorf("transaction service base URL is not configured")
+	}
+	if c.apiKey == "" {
+		return fmt.Errorf("transaction service internal api key is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.internalURL("/transactions/archive"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute archive request to transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return ErrTransactionArchiveInProgress
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CleanupNotifications asks transaction-service to delete read notifications past
+// their retention and returns how many were deleted.
+func (c *Client) CleanupNotifications(ctx context.Context) (int64, error) {
+	if c.baseURL == "" {
+		return 0, fmt.Errorf("transaction service base URL is not configured")
+	}
+	if c.apiKey == "" {
+		return 0, fmt.Errorf("transaction service internal api key is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.internalURL("/notifications/cleanup"), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute notification cleanup request to transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode notification cleanup response: %w", err)
+	}
+	return result.Deleted, nil
+}
+
+// RunReconciliation asks transaction-service to reconcile yesterday's Anchor transfers
+// against its transactions, checking sampleSize random user accounts or all of them
+// when sampleSize is 0. The run happens in the background there;
+// ErrReconciliationInProgress is returned when a previous run has not finished yet.
+func (c *Client) RunReconciliation(ctx context.Context, sampleSize int) error {
+	if c.baseURL == "" {
+		return fmt.Errorf("transaction service base URL is not configured")
+	}
+	if c.apiKey == "" {
+		return fmt.Errorf("transaction service internal api key is not configured")
+	}
+
+	body, err := json.Marshal(map[string]int{"sample_size": sampleSize})
+	if err != nil {
+		return fmt.Errorf("failed to marshal reconciliation payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.internalURL("/reconciliation/run"), bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute reconciliation run request to transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return ErrReconciliationInProgress
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("transaction service returned error status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (c *Client) internalMoneyDropURL(pathSuffix string) string {
+	return c.internalURL("/money-drops" + pathSuffix)
+}
+
+func (c *Client) internalURL(pathSuffix string) string {
 	if strings.HasSuffix(c.baseURL, "/transactions") {
-		return fmt.Sprintf("%s/internal/money-drops%s", c.baseURL, pathSuffix)
+		return fmt.Sprintf("%s/internal%s", c.baseURL, pathSuffix)
 	}
-	return fmt.Sprintf("%s/transactions/internal/money-drops%s", c.baseURL, pathSuffix)
+	return fmt.Sprintf("%s/transactions/internal%s", c.baseURL, pathSuffix)
 }
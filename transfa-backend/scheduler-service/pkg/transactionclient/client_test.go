@@ -0,0 +1,73 @@
+package transactionclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListExpiredActiveMoneyDrops_SendsLimitAndAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/transactions/internal/money-drops/expired" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "25" {
+			t.Errorf("expected limit=25, got %q", r.URL.RawQuery)
+		}
+		if r.Header.Get("X-Internal-API-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"drop-1","creator_id":"user-1","expiry_timestamp":"2026-10-01T10:00:00Z","claims_made_count":2,"total_claims_allowed":5,"refunded_amount":0}]`))
+	}))
+	defer server.Close()
+
+	drops, err := NewClient(server.URL+"/transactions", "secret").ListExpiredActiveMoneyDrops(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("ListExpiredActiveMoneyDrops: %v", err)
+	}
+	if len(drops) != 1 || drops[0].ID != "drop-1" || drops[0].ClaimsMadeCount != 2 || drops[0].TotalClaimsAllowed != 5 {
+		t.Fatalf("unexpected drops: %+v", drops)
+	}
+
+	_, err = NewClient(server.URL+"/transactions", "wrong").ListExpiredActiveMoneyDrops(context.Background(), 25)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected the 401 to surface, got %v", err)
+	}
+}
+
+func TestRunReconciliation_SendsSampleSizeAndMapsConflict(t *testing.T) {
+	status := http.StatusAccepted
+	var gotSampleSize int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/transactions/internal/reconciliation/run" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			SampleSize int `json:"sample_size"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		gotSampleSize = body.SampleSize
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL+"/transactions", "secret")
+
+	if err := client.RunReconciliation(context.Background(), 40); err != nil {
+		t.Fatalf("RunReconciliation: %v", err)
+	}
+	if gotSampleSize != 40 {
+		t.Fatalf("expected sample_size 40, got %d", gotSampleSize)
+	}
+
+	status = http.StatusConflict
+	if err := client.RunReconciliation(context.Background(), 40); !errors.Is(err, ErrReconciliationInProgress) {
+		t.Fatalf("expected ErrReconciliationInProgress, got %v", err)
+	}
+}
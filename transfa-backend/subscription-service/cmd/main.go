@@ -14,12 +14,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
     "github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 	"github.com/transfa/subscription-service/internal/api"
 	"github.com/transfa/subscription-service/internal/app"
 	"github.com/transfa/subscription-service/internal/config"
@@ -53,10 +55,10 @@ func main() {
 	}
 	
 	// Configure connection pool for high-traffic scenarios
-	config.MaxConns = 100
-	config.MinConns = 20
-	config.MaxConnLifetime = 30 * time.Minute
-	config.MaxConnIdleTime = 5 * time.Minute
+	config.MaxConns = cfg.DatabasePool.MaxConns
+	config.MinConns = cfg.DatabasePool.MinConns
+	config.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	config.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 
     // IMPORTANT: Disable prepared statements to work with PgBouncer transaction pooling
     // Use simple protocol to avoid statement cache errors (SQLSTATE 42P05)
@@ -77,7 +79,12 @@ func main() {
 	repository := store.NewRepository(dbpool)
 	service := app.NewService(repository)
 	handler := api.NewHandler(service)
-	router := api.NewRouter(handler, cfg.ClerkJWKSURL)
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		logger.Error("invalid ALLOWED_ORIGINS", "error", err)
+		os.Exit(1)
+	}
+	router := api.NewRouter(handler, cfg.ClerkJWKSURL, allowedOrigins)
 
 	// Configure and start the HTTP server
 	server := &http.Server{
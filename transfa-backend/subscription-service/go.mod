@@ -4,7 +4,6 @@ go 1.24
 
 require (
 	github.com/go-chi/chi/v5 v5.0.12
-	github.com/go-chi/cors v1.2.2
 	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/jackc/pgx/v5 v5.5.5
 	github.com/spf13/viper v1.18.2
@@ -26,6 +25,7 @@ require (
 	github.com/spf13/cast v1.6.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
+	github.com/transfa/pkg v0.0.0-00010101000000-000000000000
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/crypto v0.17.0 // indirect
@@ -36,3 +36,5 @@ require (
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/transfa/pkg => ../pkg
@@ -12,25 +12,18 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 )
 
 // NewRouter creates a new Chi router and registers the subscription-service routes.
-func NewRouter(h *Handler, jwksURL string) *chi.Mux {
+func NewRouter(h *Handler, jwksURL string, allowedOrigins []string) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Setup middleware
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300, // Maximum value not ignored by any major browsers
-	}))
+	r.Use(sharedmiddleware.RequestTimeout(60 * time.Second))
+	r.Use(sharedmiddleware.CORS(allowedOrigins))
 
 	// Health check endpoint
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
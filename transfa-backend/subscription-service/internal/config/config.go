@@ -7,26 +7,49 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config holds all configuration for the application.
 type Config struct {
-	ServerPort   string `mapstructure:"SERVER_PORT"`
-	DatabaseURL  string `mapstructure:"DATABASE_URL"`
-	ClerkJWKSURL string `mapstructure:"CLERK_JWKS_URL"`
+	ServerPort     string `mapstructure:"SERVER_PORT"`
+	DatabaseURL    string `mapstructure:"DATABASE_URL"`
+	ClerkJWKSURL   string `mapstructure:"CLERK_JWKS_URL"`
+	AppEnv         string `mapstructure:"APP_ENV"`
+	AllowedOrigins string `mapstructure:"ALLOWED_ORIGINS"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
+}
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        100,
+	MinConns:        20,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
 }
 
 // LoadConfig reads configuration from environment variables.
 func LoadConfig() (config Config, err error) {
 	viper.SetDefault("SERVER_PORT", "8085")
+	viper.SetDefault("APP_ENV", "development")
 	viper.AutomaticEnv()
 
 	// Bind environment variables explicitly to ensure they appear in Unmarshal
 	_ = viper.BindEnv("SERVER_PORT")
 	_ = viper.BindEnv("DATABASE_URL")
 	_ = viper.BindEnv("CLERK_JWKS_URL")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("ALLOWED_ORIGINS")
+
+	if err = viper.Unmarshal(&config); err != nil {
+		return
+	}
 
-	err = viper.Unmarshal(&config)
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
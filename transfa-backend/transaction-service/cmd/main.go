@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -31,13 +32,16 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/transfa/pkg/anchorclient"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
+	rmrabbit "github.com/transfa/pkg/rabbitmq"
 	"github.com/transfa/transaction-service/internal/api"
 	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/audit"
 	"github.com/transfa/transaction-service/internal/config"
 	"github.com/transfa/transaction-service/internal/store"
 	"github.com/transfa/transaction-service/pkg/accountclient"
-	"github.com/transfa/transaction-service/pkg/anchorclient"
-	rmrabbit "github.com/transfa/transaction-service/pkg/rabbitmq"
+	"github.com/transfa/transaction-service/pkg/authclient"
 )
 
 func main() {
@@ -61,11 +65,10 @@ func main() {
 	}
 
 	// Configure connection pool for high-traffic scenarios (100k+ users)
-	// Align with account-service configuration for consistency
-	poolConfig.MaxConns = 100
-	poolConfig.MinConns = 20
-	poolConfig.MaxConnLifetime = 30 * time.Minute
-	poolConfig.MaxConnIdleTime = 5 * time.Minute
+	poolConfig.MaxConns = cfg.DatabasePool.MaxConns
+	poolConfig.MinConns = cfg.DatabasePool.MinConns
+	poolConfig.MaxConnLifetime = cfg.DatabasePool.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.DatabasePool.MaxConnIdleTime
 
 	// Disable prepared statement caching to prevent conflicts
 	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
@@ -103,6 +106,17 @@ func main() {
 		accountClient = accountclient.NewClient(cfg.AccountServiceURL, cfg.AccountServiceInternalAPIKey)
 	}
 
+	// Without the auth-service, PINs are checked against the shared credentials table.
+	var authClient *authclient.Client
+	if strings.TrimSpace(cfg.AuthServiceURL) == "" || strings.TrimSpace(cfg.AuthServiceInternalAPIKey) == "" {
+		log.Printf("level=warn component=bootstrap msg=\"auth-service client not configured; verifying transaction pins locally\" auth_service_url_set=%t auth_service_internal_key_set=%t",
+			strings.TrimSpace(cfg.AuthServiceURL) != "",
+			strings.TrimSpace(cfg.AuthServiceInternalAPIKey) != "",
+		)
+	} else {
+		authClient = authclient.NewClient(cfg.AuthServiceURL, cfg.AuthServiceInternalAPIKey)
+	}
+
 	var redisClient *redis.Client
 	rateLimitingEnabled := cfg.MoneyDropClaimRateLimitPerMinute > 0 || cfg.MoneyDropDetailsRateLimitPerMinute > 0
 	if rateLimitingEnabled {
@@ -151,18 +165,74 @@ func main() {
 		cfg.MoneyDropPasswordLockoutSeconds,
 		cfg.MoneyDropClaimIdempotencyTTLMin,
 	)
+	transactionService.ConfigurePlatformFeeEnforcement(cfg.PlatformFeeEnforcement)
+	transactionService.ConfigureTransactionArchive(cfg.TransactionArchiveAfterMonths)
+	if strings.TrimSpace(cfg.AttachmentAllowedHosts) == "" {
+		log.Printf("level=warn component=bootstrap msg=\"ATTACHMENT_ALLOWED_HOSTS not set; transaction attachments will be rejected\"")
+	}
+	transactionService.ConfigureTransactionAttachmentHosts(strings.Split(cfg.AttachmentAllowedHosts, ","))
+	if authClient != nil {
+		transactionService.ConfigureAuthClient(authClient)
+	}
 	if redisClient != nil {
 		transactionService.SetMoneyDropRateLimiter(
 			app.NewRedisMoneyDropRateLimiter(redisClient, cfg.RedisRateLimitPrefix),
 		)
 	}
 
+	// Start the audit trail writer. Handlers only queue events; this goroutine owns the
+	// database writes and flushes what is left on shutdown.
+	auditRecorder := audit.NewRecorder(repository, audit.DefaultBufferSize)
+	auditCtx, stopAudit := context.WithCancel(context.Background())
+	auditDone := make(chan struct{})
+	go func() {
+		defer close(auditDone)
+		auditRecorder.Run(auditCtx)
+	}()
+
+	// Start the money drop payout workers. Claims are recorded as payout_pending and
+	// paid out here, off the request path.
+	payoutCtx, stopPayouts := context.WithCancel(context.Background())
+	payoutsDone := make(chan struct{})
+	go func() {
+		defer close(payoutsDone)
+		transactionService.RunMoneyDropPayoutWorkers(payoutCtx, cfg.MoneyDropPayoutWorkers)
+	}()
+
+	// Credit back wallets debited for fee collection or money drop funding transfers
+	// that later failed.
+	compensationCtx, stopCompensations := context.WithCancel(context.Background())
+	compensationsDone := make(chan struct{})
+	go func() {
+		defer close(compensationsDone)
+		transactionService.RunTransferCompensations(compensationCtx)
+	}()
+
 	// Initialize the API handlers.
-	transactionHandlers := api.NewTransactionHandlers(transactionService, cfg.InternalAPIKey)
+	transactionHandlers := api.NewTransactionHandlers(transactionService, cfg.InternalAPIKey, auditRecorder)
+
+	// Request bodies are only logged (redacted) when LOG_LEVEL=debug.
+	var logLevel slog.Level
+	_ = logLevel.UnmarshalText([]byte(cfg.LogLevel))
+	bodyLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+
+	allowedOrigins, err := sharedmiddleware.ParseAllowedOrigins(cfg.AllowedOrigins, strings.EqualFold(cfg.AppEnv, "production"))
+	if err != nil {
+		log.Fatalf("level=fatal component=bootstrap msg=\"invalid allowed origins\" env=ALLOWED_ORIGINS err=%v", err)
+	}
+
+	// The Swagger UI is for local and staging use only.
+	docsEnabled := cfg.OpenAPIDocsEnabled
+	if docsEnabled && strings.EqualFold(cfg.AppEnv, "production") {
+		log.Printf("level=warn component=bootstrap msg=\"ignoring OPENAPI_DOCS_ENABLED in production\"")
+		docsEnabled = false
+	}
 
 	// Set up the HTTP router and define the API routes.
 	router := chi.NewRouter()
-	router.Mount("/transactions", api.TransactionRoutes(transactionHandlers, cfg.ClerkJWKSURL))
+	router.Use(sharedmiddleware.CORS(allowedOrigins))
+	router.Mount("/transactions", api.TransactionRoutes(transactionHandlers, cfg.ClerkJWKSURL, bodyLogger, docsEnabled))
+	router.Mount("/s", api.ShortLinkRoutes(transactionHandlers))
 
 	// Start the HTTP server.
 	// Use the same pattern as account-service - bind to all interfaces
@@ -185,6 +255,9 @@ func main() {
 		"transfer.status.book.processing": transferConsumer.HandleMessage,
 		"transfer.status.book.successful": transferConsumer.HandleMessage,
 		"transfer.status.book.failed":     transferConsumer.HandleMessage,
+		// Platform fee invoices land in the user's notification inbox.
+		"platform_fee.due":  transactionService.HandlePlatformFeeDue,
+		"platform_fee.paid": transactionService.HandlePlatformFeePaid,
 	}
 
 	if err := rabbitConsumer.ConsumeWithBindings("transfa.events", cfg.TransferEventQueue, transferBindings); err != nil {
@@ -214,5 +287,14 @@ func main() {
 		log.Printf("level=error component=http msg=\"shutdown failed\" err=%v", err)
 	}
 
+	stopPayouts()
+	<-payoutsDone
+
+	stopCompensations()
+	<-compensationsDone
+
+	stopAudit()
+	<-auditDone
+
 	log.Println("level=info component=http msg=\"shutdown complete\"")
 }
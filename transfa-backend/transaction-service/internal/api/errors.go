@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/transfa/pkg/apierror"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// validationFields maps the app's request validation errors to the request field they
+// reject. Handlers answer these with 422 and a details entry for the field.
+var validationFields = map[error]string{
+	app.ErrInvalidTransferAmount:            "amount",
+	app.ErrInvalidDescription:               "description",
+	app.ErrInvalidRecipient:                 "recipient_username",
+	app.ErrInvalidDelivery:                  "delivery",
+	app.ErrBulkTransferEmpty:                "transfers",
+	app.ErrTransferListNameRequired:         "name",
+	app.ErrTransferListNameLength:           "name",
+	app.ErrTransferListEmpty:                "member_usernames",
+	app.ErrInvalidDisputeReason:             "dispute_reason",
+	app.ErrInvalidDisputeDescription:        "description",
+	app.ErrInvalidDisputeStatus:             "status",
+	app.ErrDisputeResolutionNoteRequired:    "resolution_note",
+	app.ErrInvalidDisputeResolutionNote:     "resolution_note",
+	app.ErrInvalidStatementPeriod:           "from",
+	app.ErrStatementPeriodTooLong:           "to",
+	app.ErrInvalidStatementPassword:         "password",
+	app.ErrInvalidPotTransferDirection:      "direction",
+	app.ErrInvalidPaymentRequestType:        "request_type",
+	app.ErrInvalidPaymentRequestTitle:       "title",
+	app.ErrInvalidPaymentRequestDescription: "description",
+	app.ErrInvalidPaymentRequestRecipient:   "recipient_username",
+	app.ErrInvalidPaymentRequestDecline:     "reason",
+	app.ErrPaymentAmountExceedsRemaining:    "amount",
+	app.ErrPartialPaymentNotAllowed:         "amount",
+	app.ErrInvalidMoneyDropTitle:            "title",
+	app.ErrInvalidMoneyDropTotalAmount:      "total_amount",
+	app.ErrInvalidMoneyDropPeopleCount:      "number_of_people",
+	app.ErrInvalidMoneyDropExpiry:           "expiry_in_minutes",
+	app.ErrMissingMoneyDropPassword:         "lock_password",
+	app.ErrInvalidMoneyDropPassword:         "lock_password",
+	app.ErrInvalidNotificationCursor:        "cursor",
+	app.ErrInvalidNotificationIDs:           "ids",
+
+	// Payment request splits.
+	app.ErrInvalidPaymentRequestSplitStrategy:   "strategy",
+	app.ErrInvalidPaymentRequestSplitRecipients: "recipients",
+	app.ErrInvalidPaymentRequestSplitAmounts:    "recipients",
+
+	// Transaction notes and attachments.
+	app.ErrInvalidTransactionNote:          "note",
+	app.ErrInvalidTransactionAttachmentURL: "url",
+}
+
+// errorCodes gives every other typed error of the app and store packages its stable
+// error code. Errors without an entry fall back to the generic code for the status.
+var errorCodes = map[error]string{
+	store.ErrInsufficientFunds:                   apierror.CodeInsufficientFunds,
+	store.ErrPlatformFeeDelinquent:               apierror.CodePlatformFeeOverdue,
+	store.ErrSendingRestricted:                   apierror.CodeSendingRestricted,
+	store.ErrReceivingRestricted:                 apierror.CodeReceivingRestricted,
+	store.ErrUserNotFound:                        apierror.CodeUserNotFound,
+	store.ErrAccountNotFound:                     apierror.CodeAccountNotFound,
+	store.ErrBeneficiaryNotFound:                 apierror.CodeBeneficiaryNotFound,
+	store.ErrTransactionNotFound:                 apierror.CodeTransactionNotFound,
+	store.ErrTransactionNotCancellable:           apierror.CodeTransactionNotCancellable,
+	store.ErrTransactionDisputeExists:            apierror.CodeDisputeExists,
+	store.ErrTransactionDisputeNotFound:          apierror.CodeDisputeNotFound,
+	store.ErrTransactionDisputeStatusChanged:     apierror.CodeConflict,
+	store.ErrTransactionPINNotSet:                apierror.CodeTransactionPINNotSet,
+	store.ErrPaymentRequestNotFound:              apierror.CodePaymentRequestNotFound,
+	store.ErrPaymentRequestNotReady:              apierror.CodePaymentRequestNotPending,
+	store.ErrPaymentRequestSplitNotFound:         apierror.CodeNotFound,
+	store.ErrTransferListNotFound:                apierror.CodeTransferListNotFound,
+	store.ErrMoneyDropNotFound:                   apierror.CodeMoneyDropNotFound,
+	store.ErrMoneyDropClaimIdempotencyConflict:   apierror.CodeIdempotencyConflict,
+	store.ErrMoneyDropClaimIdempotencyInProgress: apierror.CodeIdempotencyInProgress,
+	store.ErrShortLinkNotFound:                   apierror.CodeNotFound,
+	store.ErrUnmatchedTransferEventNotFound:      apierror.CodeNotFound,
+	store.ErrReconciliationReportNotFound:        apierror.CodeNotFound,
+
+	app.ErrInvalidTransactionPIN:                   apierror.CodeTransactionPINInvalid,
+	app.ErrTransactionPINLocked:                    apierror.CodeTransactionPINLocked,
+	app.ErrSelfTransferNotAllowed:                  apierror.CodeSelfTransferNotAllowed,
+	app.ErrBeneficiaryAccountInvalid:               apierror.CodeBeneficiaryAccountInvalid,
+	app.ErrBeneficiaryVerificationUnavailable:      apierror.CodeUpstreamFailed,
+	app.ErrTransactionCancelled:                    apierror.CodeConflict,
+	app.ErrBulkTransferLimit:                       apierror.CodeTransferLimitExceeded,
+	app.ErrDuplicateRecipient:                      apierror.CodeDuplicateRecipient,
+	app.ErrTransferListMemberLimit:                 apierror.CodeTransferLimitExceeded,
+	app.ErrTransferListDuplicateMember:             apierror.CodeDuplicateRecipient,
+	app.ErrTransferListSelfMember:                  apierror.CodeSelfTransferNotAllowed,
+	app.ErrTransferListNotFound:                    apierror.CodeTransferListNotFound,
+	app.ErrFavoriteRecipientSelf:                   apierror.CodeSelfTransferNotAllowed,
+	app.ErrFavoriteRecipientLimit:                  apierror.CodeFavoriteLimitReached,
+	app.ErrFavoriteRecipientNotFound:               apierror.CodeFavoriteNotFound,
+	app.ErrTransactionNotDisputable:                apierror.CodeTransactionNotDisputable,
+	app.ErrInvalidDisputeTransition:                apierror.CodeInvalidDisputeTransition,
+	app.ErrPotNotFound:                             apierror.CodePotNotFound,
+	app.ErrSelfPaymentRequest:                      apierror.CodeSelfTransferNotAllowed,
+	app.ErrPaymentRequestNotFound:                  apierror.CodePaymentRequestNotFound,
+	app.ErrPaymentRequestNotPending:                apierror.CodePaymentRequestNotPending,
+	app.ErrPaymentRequestInProcessing:              apierror.CodeOperationInProgress,
+	app.ErrMoneyDropPasswordRequiredForClaim:       apierror.CodeMoneyDropPasswordRequired,
+	app.ErrMoneyDropPasswordMismatch:               apierror.CodeMoneyDropPasswordMismatch,
+	app.ErrMoneyDropPasswordClaimLocked:            apierror.CodeMoneyDropPasswordLocked,
+	app.ErrMoneyDropPasswordEncryptionUnavailable:  apierror.CodeUnavailable,
+	app.ErrMoneyDropAccountProvisioningUnavailable: apierror.CodeUnavailable,
+	app.ErrMoneyDropEndNotAllowed:                  apierror.CodeMoneyDropEndNotAllowed,
+	app.ErrMoneyDropShareNotAllowed:                apierror.CodeMoneyDropShareNotAllowed,
+	app.ErrMoneyDropNotLocked:                      apierror.CodeMoneyDropNotLocked,
+	app.ErrShortLinkExpired:                        apierror.CodeShortLinkExpired,
+	app.ErrInvalidIdempotencyKey:                   apierror.CodeInvalidIdempotencyKey,
+	app.ErrMoneyDropIdempotencyConflict:            apierror.CodeIdempotencyConflict,
+	app.ErrMoneyDropIdempotencyInProgress:          apierror.CodeIdempotencyInProgress,
+	app.ErrTransactionArchiveInProgress:            apierror.CodeOperationInProgress,
+	app.ErrBalanceSyncInProgress:                   apierror.CodeOperationInProgress,
+	app.ErrReconciliationInProgress:                apierror.CodeOperationInProgress,
+	app.ErrTransferCurrencyMismatch:                apierror.CodeCurrencyMismatch,
+	app.ErrUnmatchedTransferEventAlreadyReplayed:   apierror.CodeUnmatchedEventReplayed,
+	app.ErrUnmatchedTransferEventStillUnmatched:    apierror.CodeUnmatchedEventStillPending,
+
+	app.ErrDuplicatePaymentRequestSplitRecipient: apierror.CodeDuplicateRecipient,
+
+	store.ErrTransactionAttachmentLimit: apierror.CodeAttachmentLimitReached,
+}
+
+// errorCode returns the stable code for err, or the generic code for status when err
+// is not one of the typed errors.
+func errorCode(err error, status int) string {
+	var rateLimitErr *app.RateLimitError
+	var renamed *app.RecipientUsernameChangedError
+	var unsupportedCurrency *app.UnsupportedCurrencyError
+	switch {
+	case errors.As(err, &rateLimitErr):
+		return apierror.CodeRateLimited
+	case errors.As(err, &renamed):
+		return apierror.CodeRecipientUsernameChanged
+	case errors.As(err, &unsupportedCurrency):
+		return apierror.CodeUnsupportedCurrency
+	}
+	for target, code := range errorCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	return apierror.CodeForStatus(status)
+}
+
+// validationField reports the request field a validation error rejects.
+func validationField(err error) (string, bool) {
+	var unsupportedCurrency *app.UnsupportedCurrencyError
+	if errors.As(err, &unsupportedCurrency) {
+		return "currency", true
+	}
+	for target, field := range validationFields {
+		if errors.Is(err, target) {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// writeJSON is a helper for writing JSON responses.
+func (h *TransactionHandlers) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if data != nil {
+		_ = json.NewEncoder(w).Encode(data)
+	}
+}
+
+// writeError writes an error envelope with the generic code for status.
+func (h *TransactionHandlers) writeError(w http.ResponseWriter, status int, message string) {
+	apierror.WriteStatus(w, status, message)
+}
+
+// writeErrorCode writes an error envelope with a specific code.
+func (h *TransactionHandlers) writeErrorCode(w http.ResponseWriter, status int, code, message string) {
+	apierror.Write(w, status, code, message)
+}
+
+// writeAppError writes err with its stable code and its own message. Validation
+// errors are written as 422 with the rejected field, whatever status was asked for.
+func (h *TransactionHandlers) writeAppError(w http.ResponseWriter, status int, err error) {
+	if field, ok := validationField(err); ok {
+		h.writeValidationError(w, apierror.FieldError{Field: field, Message: err.Error()})
+		return
+	}
+	apierror.Write(w, status, errorCode(err, status), err.Error())
+}
+
+// writeValidationError writes a 422 listing the rejected request fields.
+func (h *TransactionHandlers) writeValidationError(w http.ResponseWriter, details ...apierror.FieldError) {
+	message := "Request validation failed"
+	if len(details) == 1 {
+		message = details[0].Message
+	}
+	apierror.WriteValidation(w, message, details...)
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+	"github.com/transfa/transaction-service/pkg/authclient"
+)
+
+// errorEnvelopeRepoStub knows one sender, optionally one recipient and one renamed
+// username, and no money drops.
+type errorEnvelopeRepoStub struct {
+	store.Repository
+
+	sender    domain.User
+	recipient *domain.User
+	renamedTo string
+}
+
+func (s *errorEnvelopeRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	return s.sender.ID.String(), nil
+}
+
+func (s *errorEnvelopeRepoStub) FindUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	sender := s.sender
+	return &sender, nil
+}
+
+func (s *errorEnvelopeRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if s.recipient == nil {
+		return nil, store.ErrUserNotFound
+	}
+	recipient := *s.recipient
+	return &recipient, nil
+}
+
+func (s *errorEnvelopeRepoStub) FindCurrentUsernameByPreviousUsername(ctx context.Context, username string) (string, error) {
+	if s.renamedTo == "" {
+		return "", store.ErrUserNotFound
+	}
+	return s.renamedTo, nil
+}
+
+func (s *errorEnvelopeRepoStub) FindMoneyDropByID(ctx context.Context, id uuid.UUID) (*domain.MoneyDrop, error) {
+	return nil, store.ErrMoneyDropNotFound
+}
+
+func newErrorEnvelopeHandlers(t *testing.T, repo *errorEnvelopeRepoStub) *TransactionHandlers {
+	t.Helper()
+	var gotUserID string
+	authService := newAuthServiceStub(t, http.StatusOK, `{"valid":true}`, &gotUserID)
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	service.ConfigureAuthClient(authclient.NewClient(authService.URL, "internal-key"))
+	return NewTransactionHandlers(service, "", nil)
+}
+
+type errorEnvelopeBody struct {
+	Error struct {
+		Code    string                `json:"code"`
+		Message string                `json:"message"`
+		Details []apierror.FieldError `json:"details"`
+	} `json:"error"`
+	NewUsername string `json:"new_username"`
+}
+
+func decodeErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder, wantStatus int, wantCode string) errorEnvelopeBody {
+	t.Helper()
+	if rec.Code != wantStatus {
+		t.Fatalf("expected %d, got %d: %s", wantStatus, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected a JSON error, got Content-Type %q", got)
+	}
+	var body errorEnvelopeBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error envelope: %v: %s", err, rec.Body.String())
+	}
+	if body.Error.Code != wantCode {
+		t.Fatalf("expected code %s, got %s", wantCode, rec.Body.String())
+	}
+	if body.Error.Message == "" {
+		t.Fatalf("expected an error message, got %s", rec.Body.String())
+	}
+	if _, documented := apierror.Codes[body.Error.Code]; !documented {
+		t.Fatalf("code %s is missing from apierror.Codes", body.Error.Code)
+	}
+	return body
+}
+
+func TestP2PTransferHandler_ErrorEnvelope(t *testing.T) {
+	senderID := uuid.New()
+	tests := []struct {
+		name       string
+		repo       *errorEnvelopeRepoStub
+		body       string
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{
+			name:       "malformed body",
+			repo:       &errorEnvelopeRepoStub{},
+			body:       `{"amount":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   apierror.CodeBadRequest,
+		},
+		{
+			name:       "invalid amount",
+			repo:       &errorEnvelopeRepoStub{},
+			body:       `{"recipient_username":"bola","amount":0,"transaction_pin":"1234"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apierror.CodeValidationFailed,
+			wantField:  "amount",
+		},
+		{
+			name:       "unsupported currency",
+			repo:       &errorEnvelopeRepoStub{},
+			body:       `{"recipient_username":"bola","amount":1000,"currency":"USD","description":"Lunch","transaction_pin":"1234"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apierror.CodeValidationFailed,
+			wantField:  "currency",
+		},
+		{
+			name:       "unknown recipient",
+			repo:       &errorEnvelopeRepoStub{},
+			body:       `{"recipient_username":"bola","amount":1000,"description":"Lunch","transaction_pin":"1234"}`,
+			wantStatus: http.StatusNotFound,
+			wantCode:   apierror.CodeRecipientNotFound,
+		},
+		{
+			name:       "transfer to self",
+			repo:       &errorEnvelopeRepoStub{recipient: &domain.User{ID: senderID}},
+			body:       `{"recipient_username":"bola","amount":1000,"description":"Lunch","transaction_pin":"1234"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   apierror.CodeSelfTransferNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.repo.sender = domain.User{ID: senderID}
+			h := newErrorEnvelopeHandlers(t, tt.repo)
+
+			rec := postP2PTransfer(h, tt.body)
+
+			body := decodeErrorEnvelope(t, rec, tt.wantStatus, tt.wantCode)
+			if tt.wantField == "" {
+				if len(body.Error.Details) != 0 {
+					t.Fatalf("expected no details, got %+v", body.Error.Details)
+				}
+				return
+			}
+			if len(body.Error.Details) != 1 || body.Error.Details[0].Field != tt.wantField {
+				t.Fatalf("expected one detail for %s, got %+v", tt.wantField, body.Error.Details)
+			}
+		})
+	}
+}
+
+func TestP2PTransferHandler_RenamedRecipientEnvelope(t *testing.T) {
+	repo := &errorEnvelopeRepoStub{sender: domain.User{ID: uuid.New()}, renamedTo: "bola_new"}
+	h := newErrorEnvelopeHandlers(t, repo)
+
+	rec := postP2PTransfer(h, `{"recipient_username":"bola","amount":1000,"description":"Lunch","transaction_pin":"1234"}`)
+
+	body := decodeErrorEnvelope(t, rec, http.StatusConflict, apierror.CodeRecipientUsernameChanged)
+	if body.NewUsername != "bola_new" {
+		t.Fatalf("expected new_username bola_new, got %q", body.NewUsername)
+	}
+}
+
+func TestCreateMoneyDropHandler_ListsEveryInvalidField(t *testing.T) {
+	h := newErrorEnvelopeHandlers(t, &errorEnvelopeRepoStub{sender: domain.User{ID: uuid.New()}})
+
+	req := httptest.NewRequest(http.MethodPost, "/money-drops", strings.NewReader(`{"title":" ","total_amount":0,"number_of_people":2,"expiry_in_minutes":5000,"lock_drop":true}`))
+	req = req.WithContext(context.WithValue(req.Context(), clerkUserIDKey, "user_test"))
+	rec := httptest.NewRecorder()
+	h.CreateMoneyDropHandler(rec, req)
+
+	body := decodeErrorEnvelope(t, rec, http.StatusUnprocessableEntity, apierror.CodeValidationFailed)
+	var fields []string
+	for _, detail := range body.Error.Details {
+		if detail.Message == "" {
+			t.Fatalf("expected a message for %s", detail.Field)
+		}
+		fields = append(fields, detail.Field)
+	}
+	if got, want := strings.Join(fields, ","), "title,total_amount,expiry_in_minutes,lock_password"; got != want {
+		t.Fatalf("expected details for %s, got %s", want, got)
+	}
+}
+
+func TestCreatePaymentRequestSplitHandler_DuplicateRecipient(t *testing.T) {
+	h := newErrorEnvelopeHandlers(t, &errorEnvelopeRepoStub{sender: domain.User{ID: uuid.New()}})
+
+	req := httptest.NewRequest(http.MethodPost, "/payment-requests/split", strings.NewReader(`{"title":"Dinner","total_amount":9000,"strategy":"equal","recipients":[{"username":"ada"},{"username":"Ada"}]}`))
+	req = req.WithContext(context.WithValue(req.Context(), clerkUserIDKey, "user_test"))
+	rec := httptest.NewRecorder()
+	h.CreatePaymentRequestSplitHandler(rec, req)
+
+	decodeErrorEnvelope(t, rec, http.StatusBadRequest, apierror.CodeDuplicateRecipient)
+}
+
+func TestClaimMoneyDropHandler_ErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name       string
+		dropID     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "malformed drop id", dropID: "not-a-uuid", wantStatus: http.StatusBadRequest, wantCode: apierror.CodeBadRequest},
+		{name: "unknown drop", dropID: uuid.NewString(), wantStatus: http.StatusBadRequest, wantCode: apierror.CodeMoneyDropNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newErrorEnvelopeHandlers(t, &errorEnvelopeRepoStub{sender: domain.User{ID: uuid.New()}})
+
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("drop_id", tt.dropID)
+			req := httptest.NewRequest(http.MethodPost, "/money-drops/"+tt.dropID+"/claim", nil)
+			ctx := context.WithValue(req.Context(), clerkUserIDKey, "user_test")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, routeCtx))
+			rec := httptest.NewRecorder()
+			h.ClaimMoneyDropHandler(rec, req)
+
+			decodeErrorEnvelope(t, rec, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestErrorCodes_AreDocumented(t *testing.T) {
+	for target, code := range errorCodes {
+		if _, ok := apierror.Codes[code]; !ok {
+			t.Errorf("code %s for %q is missing from apierror.Codes", code, target)
+		}
+	}
+}
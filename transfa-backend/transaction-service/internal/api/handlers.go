@@ -19,11 +19,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
 	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/audit"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
 )
@@ -32,6 +35,7 @@ import (
 type TransactionHandlers struct {
 	service        *app.Service
 	internalAPIKey string
+	auditLog       *audit.Recorder
 }
 
 // transferInitiationResponse is sent back to the mobile client immediately after a transfer
@@ -50,6 +54,7 @@ type transferInitiationResponse struct {
 	FailureReason    *string `json:"failure_reason,omitempty"`
 	AnchorSessionID  *string `json:"anchor_session_id,omitempty"`
 	AnchorReason     *string `json:"anchor_reason,omitempty"`
+	RoutingDecision  string  `json:"routing_decision,omitempty"`
 }
 
 type bulkTransferFailureResponse struct {
@@ -84,9 +89,42 @@ func buildTransferInitiationResponse(tx *domain.Transaction, message string) tra
 		FailureReason:    tx.FailureReason,
 		AnchorSessionID:  tx.AnchorSessionID,
 		AnchorReason:     tx.AnchorReason,
+		RoutingDecision:  tx.RoutingDecision,
 	}
 }
 
+type setDefaultBeneficiaryRequest struct {
+	BeneficiaryID uuid.UUID `json:"beneficiary_id"`
+}
+
+type updateReceivingPreferenceRequest struct {
+	UseExternalAccount   bool       `json:"use_external_account"`
+	DefaultBeneficiaryID *uuid.UUID `json:"default_beneficiary_id,omitempty"`
+}
+
+// platformFeeRequest is sent by platform-fee-service to debit a monthly fee.
+type platformFeeRequest struct {
+	UserID    string `json:"user_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+	InvoiceID string `json:"invoice_id"`
+}
+
+// pinSetupPath is where clients send a user who has not created a transaction PIN yet.
+const pinSetupPath = "/setup-pin"
+
+type pinNotSetResponse struct {
+	apierror.Envelope
+	Redirect string `json:"redirect"`
+}
+
+// recipientUsernameChangedResponse adds the recipient's current username to the
+// error envelope so the client can retry the transfer with it.
+type recipientUsernameChangedResponse struct {
+	apierror.Envelope
+	NewUsername string `json:"new_username"`
+}
+
 func (h *TransactionHandlers) authorizeTransactionPIN(r *http.Request, w http.ResponseWriter, userID uuid.UUID, pin string) bool {
 	err := h.service.VerifyTransactionPIN(r.Context(), userID, pin)
 	if err == nil {
@@ -94,15 +132,21 @@ func (h *TransactionHandlers) authorizeTransactionPIN(r *http.Request, w http.Re
 	}
 
 	if errors.Is(err, store.ErrTransactionPINNotSet) {
-		h.writeError(w, http.StatusPreconditionFailed, "Transaction PIN is not set. Please create your PIN first.")
+		h.writeJSON(w, http.StatusForbidden, pinNotSetResponse{
+			Envelope: apierror.Envelope{Error: apierror.Error{
+				Code:    apierror.CodeTransactionPINNotSet,
+				Message: "Transaction PIN is not set. Please create your PIN first.",
+			}},
+			Redirect: pinSetupPath,
+		})
 		return false
 	}
 	if errors.Is(err, app.ErrTransactionPINLocked) {
-		h.writeError(w, http.StatusLocked, "Too many incorrect PIN attempts. Please wait and try again.")
+		h.writeErrorCode(w, http.StatusLocked, apierror.CodeTransactionPINLocked, "Too many incorrect PIN attempts. Please wait and try again.")
 		return false
 	}
 	if errors.Is(err, app.ErrInvalidTransactionPIN) {
-		h.writeError(w, http.StatusUnauthorized, "Invalid transaction PIN.")
+		h.writeErrorCode(w, http.StatusUnauthorized, apierror.CodeTransactionPINInvalid, "Invalid transaction PIN.")
 		return false
 	}
 
@@ -114,23 +158,25 @@ func (h *TransactionHandlers) authorizeTransactionPIN(r *http.Request, w http.Re
 func (h *TransactionHandlers) authorizeInternalRequest(w http.ResponseWriter, r *http.Request) bool {
 	requiredKey := strings.TrimSpace(h.internalAPIKey)
 	if requiredKey == "" {
-		http.Error(w, "Internal API key is not configured", http.StatusServiceUnavailable)
+		h.writeError(w, http.StatusServiceUnavailable, "Internal API key is not configured")
 		return false
 	}
 
 	providedKey := strings.TrimSpace(r.Header.Get("X-Internal-API-Key"))
 	if providedKey == "" || subtle.ConstantTimeCompare([]byte(providedKey), []byte(requiredKey)) != 1 {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		h.writeError(w, http.StatusUnauthorized, "Unauthorized")
 		return false
 	}
 	return true
 }
 
-// NewTransactionHandlers creates a new instance of TransactionHandlers.
-func NewTransactionHandlers(service *app.Service, internalAPIKey string) *TransactionHandlers {
+// NewTransactionHandlers creates a new instance of TransactionHandlers. auditLog may be
+// nil, in which case audit events are discarded.
+func NewTransactionHandlers(service *app.Service, internalAPIKey string, auditLog *audit.Recorder) *TransactionHandlers {
 	return &TransactionHandlers{
 		service:        service,
 		internalAPIKey: strings.TrimSpace(internalAPIKey),
+		auditLog:       auditLog,
 	}
 }
 
@@ -139,7 +185,7 @@ func (h *TransactionHandlers) P2PTransferHandler(w http.ResponseWriter, r *http.
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
@@ -147,20 +193,20 @@ func (h *TransactionHandlers) P2PTransferHandler(w http.ResponseWriter, r *http.
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=p2p_transfer outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	senderID, err := uuid.Parse(internalIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=p2p_transfer outcome=reject reason=invalid_user_id internal_user_id=%s", internalIDStr)
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	var req domain.P2PTransferRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("level=warn component=api endpoint=p2p_transfer outcome=reject reason=invalid_json err=%v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
 		return
 	}
 	if !h.authorizeTransactionPIN(r, w, senderID, req.TransactionPIN) {
@@ -174,21 +220,55 @@ func (h *TransactionHandlers) P2PTransferHandler(w http.ResponseWriter, r *http.
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=p2p_transfer outcome=failed sender_id=%s err=%v", senderID, err)
 		if errors.Is(err, store.ErrInsufficientFunds) {
-			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			h.writeAppError(w, http.StatusPaymentRequired, err)
+			return
+		}
+		var renamed *app.RecipientUsernameChangedError
+		if errors.As(err, &renamed) {
+			h.writeJSON(w, http.StatusConflict, recipientUsernameChangedResponse{
+				Envelope: apierror.Envelope{Error: apierror.Error{
+					Code:    apierror.CodeRecipientUsernameChanged,
+					Message: renamed.Error(),
+				}},
+				NewUsername: renamed.NewUsername,
+			})
 			return
 		}
 		if errors.Is(err, store.ErrUserNotFound) {
-			http.Error(w, "Recipient user not found", http.StatusNotFound)
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeRecipientNotFound, "Recipient user not found")
+			return
+		}
+		if errors.Is(err, store.ErrPlatformFeeDelinquent) {
+			h.writeErrorCode(w, http.StatusPaymentRequired, apierror.CodePlatformFeeOverdue, "Platform fee overdue: pay the outstanding fee to send money")
+			return
+		}
+		if errors.Is(err, store.ErrSendingRestricted) || errors.Is(err, store.ErrReceivingRestricted) {
+			h.writeAppError(w, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, app.ErrTransactionCancelled) {
+			h.writeAppError(w, http.StatusConflict, err)
 			return
 		}
-		if errors.Is(err, app.ErrInvalidTransferAmount) || errors.Is(err, app.ErrInvalidDescription) || errors.Is(err, app.ErrInvalidRecipient) || errors.Is(err, app.ErrSelfTransferNotAllowed) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, app.ErrInvalidTransferAmount) || errors.Is(err, app.ErrInvalidDescription) || errors.Is(err, app.ErrInvalidRecipient) || errors.Is(err, app.ErrSelfTransferNotAllowed) || errors.Is(err, app.ErrInvalidDelivery) {
+			h.writeAppError(w, http.StatusBadRequest, err)
 			return
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		var unsupportedCurrency *app.UnsupportedCurrencyError
+		if errors.As(err, &unsupportedCurrency) {
+			h.writeAppError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	h.auditUser(r, senderID, "transfer.p2p", "transaction", tx.ID.String(), map[string]interface{}{
+		"recipient_username": req.RecipientUsername,
+		"amount":             tx.Amount,
+		"fee":                tx.Fee,
+	})
+
 	response := buildTransferInitiationResponse(tx, "Transfer initiated")
 	h.writeJSON(w, http.StatusCreated, response)
 }
@@ -197,27 +277,27 @@ func (h *TransactionHandlers) P2PTransferHandler(w http.ResponseWriter, r *http.
 func (h *TransactionHandlers) BulkP2PTransferHandler(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=bulk_p2p_transfer outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	senderID, err := uuid.Parse(internalIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=bulk_p2p_transfer outcome=reject reason=invalid_user_id internal_user_id=%s", internalIDStr)
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	var req domain.BulkP2PTransferRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("level=warn component=api endpoint=bulk_p2p_transfer outcome=reject reason=invalid_json err=%v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
 		return
 	}
 	if !h.authorizeTransactionPIN(r, w, senderID, req.TransactionPIN) {
@@ -231,7 +311,13 @@ func (h *TransactionHandlers) BulkP2PTransferHandler(w http.ResponseWriter, r *h
 		log.Printf("level=warn component=api endpoint=bulk_p2p_transfer outcome=failed sender_id=%s err=%v", senderID, err)
 		switch {
 		case errors.Is(err, store.ErrInsufficientFunds):
-			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			h.writeAppError(w, http.StatusPaymentRequired, err)
+			return
+		case errors.Is(err, store.ErrPlatformFeeDelinquent):
+			h.writeErrorCode(w, http.StatusPaymentRequired, apierror.CodePlatformFeeOverdue, "Platform fee overdue: pay the outstanding fee to send money")
+			return
+		case errors.Is(err, store.ErrSendingRestricted):
+			h.writeAppError(w, http.StatusForbidden, err)
 			return
 		case errors.Is(err, app.ErrBulkTransferEmpty),
 			errors.Is(err, app.ErrBulkTransferLimit),
@@ -239,10 +325,10 @@ func (h *TransactionHandlers) BulkP2PTransferHandler(w http.ResponseWriter, r *h
 			errors.Is(err, app.ErrInvalidTransferAmount),
 			errors.Is(err, app.ErrInvalidDescription),
 			errors.Is(err, app.ErrInvalidRecipient):
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			h.writeAppError(w, http.StatusBadRequest, err)
 			return
 		default:
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			h.writeError(w, http.StatusInternalServerError, "Internal server error")
 			return
 		}
 	}
@@ -268,6 +354,15 @@ func (h *TransactionHandlers) BulkP2PTransferHandler(w http.ResponseWriter, r *h
 		})
 	}
 
+	if len(result.Successful) > 0 {
+		h.auditUser(r, senderID, "transfer.bulk_p2p", "transfer_batch", result.BatchID.String(), map[string]interface{}{
+			"transaction_ids": successfulTransactionIDs,
+			"total_amount":    totalAmount,
+			"total_fee":       totalFee,
+			"failure_count":   len(result.Failed),
+		})
+	}
+
 	status := "completed"
 	message := "All transfers initiated successfully"
 	if len(result.Successful) == 0 {
@@ -300,7 +395,7 @@ func (h *TransactionHandlers) SelfTransferHandler(w http.ResponseWriter, r *http
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
@@ -308,20 +403,20 @@ func (h *TransactionHandlers) SelfTransferHandler(w http.ResponseWriter, r *http
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=self_transfer outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	senderID, err := uuid.Parse(internalIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=self_transfer outcome=reject reason=invalid_user_id internal_user_id=%s", internalIDStr)
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	var req domain.SelfTransferRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("level=warn component=api endpoint=self_transfer outcome=reject reason=invalid_json err=%v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 	if !h.authorizeTransactionPIN(r, w, senderID, req.TransactionPIN) {
@@ -335,25 +430,48 @@ func (h *TransactionHandlers) SelfTransferHandler(w http.ResponseWriter, r *http
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=self_transfer outcome=failed sender_id=%s err=%v", senderID, err)
 		if errors.Is(err, store.ErrInsufficientFunds) {
-			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			h.writeAppError(w, http.StatusPaymentRequired, err)
 			return
 		}
 		if errors.Is(err, store.ErrBeneficiaryNotFound) {
-			http.Error(w, "Beneficiary not found or does not belong to user", http.StatusNotFound)
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeBeneficiaryNotFound, "Beneficiary not found or does not belong to user")
+			return
+		}
+		if errors.Is(err, app.ErrBeneficiaryAccountInvalid) {
+			h.writeAppError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if errors.Is(err, app.ErrTransactionCancelled) {
+			h.writeAppError(w, http.StatusConflict, err)
 			return
 		}
 		if errors.Is(err, store.ErrPlatformFeeDelinquent) {
-			http.Error(w, "Platform fee overdue: external transfers are disabled", http.StatusForbidden)
+			h.writeErrorCode(w, http.StatusPaymentRequired, apierror.CodePlatformFeeOverdue, "Platform fee overdue: external transfers are disabled")
+			return
+		}
+		if errors.Is(err, store.ErrSendingRestricted) {
+			h.writeAppError(w, http.StatusForbidden, err)
 			return
 		}
 		if errors.Is(err, app.ErrInvalidTransferAmount) || errors.Is(err, app.ErrInvalidDescription) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			h.writeAppError(w, http.StatusBadRequest, err)
+			return
+		}
+		var unsupportedCurrency *app.UnsupportedCurrencyError
+		if errors.As(err, &unsupportedCurrency) {
+			h.writeAppError(w, http.StatusBadRequest, err)
 			return
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	h.auditUser(r, senderID, "transfer.self", "transaction", tx.ID.String(), map[string]interface{}{
+		"beneficiary_id": req.BeneficiaryID,
+		"amount":         tx.Amount,
+		"fee":            tx.Fee,
+	})
+
 	response := buildTransferInitiationResponse(tx, "Transfer initiated")
 	h.writeJSON(w, http.StatusCreated, response)
 }
@@ -363,7 +481,7 @@ func (h *TransactionHandlers) ListBeneficiariesHandler(w http.ResponseWriter, r
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
@@ -371,12 +489,12 @@ func (h *TransactionHandlers) ListBeneficiariesHandler(w http.ResponseWriter, r
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=list_beneficiaries outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
@@ -384,7 +502,7 @@ func (h *TransactionHandlers) ListBeneficiariesHandler(w http.ResponseWriter, r
 	beneficiaries, err := h.service.GetUserBeneficiaries(r.Context(), userID)
 	if err != nil {
 		log.Printf("level=error component=api endpoint=list_beneficiaries outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -399,19 +517,19 @@ func (h *TransactionHandlers) GetDefaultBeneficiaryHandler(w http.ResponseWriter
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_default_beneficiary outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
@@ -419,11 +537,11 @@ func (h *TransactionHandlers) GetDefaultBeneficiaryHandler(w http.ResponseWriter
 	beneficiary, err := h.service.GetDefaultBeneficiary(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, store.ErrBeneficiaryNotFound) {
-			http.Error(w, "No default beneficiary found", http.StatusNotFound)
+			h.writeError(w, http.StatusNotFound, "No default beneficiary found")
 			return
 		}
 		log.Printf("level=error component=api endpoint=get_default_beneficiary outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -438,28 +556,26 @@ func (h *TransactionHandlers) SetDefaultBeneficiaryHandler(w http.ResponseWriter
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=set_default_beneficiary outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	// Parse the request body to get the beneficiary ID
-	var req struct {
-		BeneficiaryID uuid.UUID `json:"beneficiary_id"`
-	}
+	var req setDefaultBeneficiaryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -467,11 +583,11 @@ func (h *TransactionHandlers) SetDefaultBeneficiaryHandler(w http.ResponseWriter
 	err = h.service.SetDefaultBeneficiary(r.Context(), userID, req.BeneficiaryID)
 	if err != nil {
 		if errors.Is(err, store.ErrBeneficiaryNotFound) {
-			http.Error(w, "Beneficiary not found or does not belong to user", http.StatusNotFound)
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeBeneficiaryNotFound, "Beneficiary not found or does not belong to user")
 			return
 		}
 		log.Printf("level=error component=api endpoint=set_default_beneficiary outcome=failed user_id=%s beneficiary_id=%s err=%v", userID, req.BeneficiaryID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -481,24 +597,70 @@ func (h *TransactionHandlers) SetDefaultBeneficiaryHandler(w http.ResponseWriter
 	json.NewEncoder(w).Encode(map[string]string{"message": "Default beneficiary updated successfully"})
 }
 
+// VerifyBeneficiaryHandler re-resolves one of the user's beneficiaries through Anchor
+// and returns it with its updated verification status.
+func (h *TransactionHandlers) VerifyBeneficiaryHandler(w http.ResponseWriter, r *http.Request) {
+	userIDStr, ok := GetClerkUserID(r.Context())
+	if !ok {
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
+		return
+	}
+
+	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
+	if err != nil {
+		log.Printf("level=warn component=api endpoint=verify_beneficiary outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
+		return
+	}
+	userID, err := uuid.Parse(internalIDStr)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	beneficiaryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid beneficiary ID format")
+		return
+	}
+
+	beneficiary, err := h.service.VerifyBeneficiary(r.Context(), userID, beneficiaryID)
+	if err != nil {
+		if errors.Is(err, store.ErrBeneficiaryNotFound) {
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeBeneficiaryNotFound, "Beneficiary not found or does not belong to user")
+			return
+		}
+		if errors.Is(err, app.ErrBeneficiaryVerificationUnavailable) {
+			log.Printf("level=warn component=api endpoint=verify_beneficiary outcome=failed user_id=%s beneficiary_id=%s err=%v", userID, beneficiaryID, err)
+			h.writeAppError(w, http.StatusBadGateway, app.ErrBeneficiaryVerificationUnavailable)
+			return
+		}
+		log.Printf("level=error component=api endpoint=verify_beneficiary outcome=failed user_id=%s beneficiary_id=%s err=%v", userID, beneficiaryID, err)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, beneficiary)
+}
+
 // GetReceivingPreferenceHandler handles requests to get user's receiving preference.
 func (h *TransactionHandlers) GetReceivingPreferenceHandler(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_receiving_preference outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
@@ -506,7 +668,7 @@ func (h *TransactionHandlers) GetReceivingPreferenceHandler(w http.ResponseWrite
 	preference, err := h.service.GetReceivingPreference(r.Context(), userID)
 	if err != nil {
 		log.Printf("level=error component=api endpoint=get_receiving_preference outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -521,29 +683,26 @@ func (h *TransactionHandlers) UpdateReceivingPreferenceHandler(w http.ResponseWr
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=update_receiving_preference outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	// Parse the request body
-	var req struct {
-		UseExternalAccount   bool       `json:"use_external_account"`
-		DefaultBeneficiaryID *uuid.UUID `json:"default_beneficiary_id,omitempty"`
-	}
+	var req updateReceivingPreferenceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -551,11 +710,11 @@ func (h *TransactionHandlers) UpdateReceivingPreferenceHandler(w http.ResponseWr
 	err = h.service.UpdateReceivingPreference(r.Context(), userID, req.UseExternalAccount, req.DefaultBeneficiaryID)
 	if err != nil {
 		if errors.Is(err, store.ErrBeneficiaryNotFound) {
-			http.Error(w, "Beneficiary not found or does not belong to user", http.StatusNotFound)
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeBeneficiaryNotFound, "Beneficiary not found or does not belong to user")
 			return
 		}
 		log.Printf("level=error component=api endpoint=update_receiving_preference outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -570,20 +729,20 @@ func (h *TransactionHandlers) GetAccountBalanceHandler(w http.ResponseWriter, r
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_balance outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_balance outcome=reject reason=invalid_user_id internal_user_id=%s err=%v", internalIDStr, err)
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
@@ -592,11 +751,11 @@ func (h *TransactionHandlers) GetAccountBalanceHandler(w http.ResponseWriter, r
 	if err != nil {
 		if errors.Is(err, store.ErrAccountNotFound) {
 			log.Printf("level=warn component=api endpoint=get_balance outcome=not_found user_id=%s", userID)
-			http.Error(w, "Account not found", http.StatusNotFound)
+			h.writeError(w, http.StatusNotFound, "Account not found")
 			return
 		}
 		log.Printf("level=error component=api endpoint=get_balance outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -613,6 +772,7 @@ func (h *TransactionHandlers) GetFeesHandler(w http.ResponseWriter, r *http.Requ
 		"self_fee_kobo":          h.service.GetTransactionFee(),
 		"money_drop_fee_kobo":    h.service.GetMoneyDropFee(),
 		"money_drop_fee_percent": h.service.GetMoneyDropFeePercent(),
+		"schedules":              h.service.GetFeeSchedules(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -625,37 +785,73 @@ func (h *TransactionHandlers) GetTransactionHistoryHandler(w http.ResponseWriter
 	// Retrieve the authenticated user's ID from the context.
 	userIDStr, ok := GetClerkUserID(r.Context())
 	if !ok {
-		http.Error(w, "Could not get user ID from context", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Could not get user ID from context")
 		return
 	}
 
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_history outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		http.Error(w, "User not found", http.StatusBadRequest)
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_history outcome=reject reason=invalid_user_id internal_user_id=%s err=%v", internalIDStr, err)
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	from, err := parseDisputeTimeFilter(r.URL.Query().Get("from"), false)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	to, err := parseDisputeTimeFilter(r.URL.Query().Get("to"), true)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	if status != "" && !slices.Contains(domain.TransactionStatuses, status) {
+		h.writeError(w, http.StatusBadRequest, "Invalid status")
 		return
 	}
 
 	// Get user's transaction history
-	transactions, err := h.service.GetTransactionHistory(r.Context(), userID)
+	transactions, err := h.service.GetTransactionHistory(r.Context(), userID, domain.TransactionHistoryFilter{
+		From:   from,
+		To:     to,
+		Types:  parseTransactionTypeFilter(r.URL.Query()["type"]),
+		Status: status,
+	})
 	if err != nil {
 		log.Printf("level=error component=api endpoint=get_history outcome=failed user_id=%s err=%v", userID, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	// Respond with the transaction history
+	setArchivedIncludedHeader(w, transactions)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(transactions)
 }
 
+// parseTransactionTypeFilter collects the requested transaction types. Types may be
+// repeated (?type=p2p&type=self_transfer) or comma-separated (?type=p2p,self_transfer).
+func parseTransactionTypeFilter(values []string) []string {
+	var types []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if t := strings.ToLower(strings.TrimSpace(part)); t != "" && !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
 // GetTransactionHistoryWithUserHandler handles requests for bilateral history with one username.
 func (h *TransactionHandlers) GetTransactionHistoryWithUserHandler(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := GetClerkUserID(r.Context())
@@ -667,7 +863,7 @@ func (h *TransactionHandlers) GetTransactionHistoryWithUserHandler(w http.Respon
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_history_with_user outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		h.writeError(w, http.StatusBadRequest, "User not found")
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 
@@ -698,9 +894,9 @@ func (h *TransactionHandlers) GetTransactionHistoryWithUserHandler(w http.Respon
 	if err != nil {
 		switch {
 		case errors.Is(err, store.ErrUserNotFound):
-			h.writeError(w, http.StatusNotFound, "User not found")
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeUserNotFound, "User not found")
 		case errors.Is(err, app.ErrInvalidRecipient):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		case errors.Is(err, app.ErrSelfTransferNotAllowed):
 			h.writeError(w, http.StatusBadRequest, "Cannot view bilateral history with yourself")
 		default:
@@ -721,6 +917,7 @@ func (h *TransactionHandlers) GetTransactionHistoryWithUserHandler(w http.Respon
 		"transactions":   transactions,
 	}
 
+	setArchivedIncludedHeader(w, transactions)
 	h.writeJSON(w, http.StatusOK, response)
 }
 
@@ -735,7 +932,7 @@ func (h *TransactionHandlers) GetTransactionByIDHandler(w http.ResponseWriter, r
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_transaction_by_id outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		h.writeError(w, http.StatusBadRequest, "User not found")
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	requestorID, err := uuid.Parse(internalIDStr)
@@ -756,7 +953,7 @@ func (h *TransactionHandlers) GetTransactionByIDHandler(w http.ResponseWriter, r
 		return
 	}
 
-	tx, err := h.service.GetTransactionByID(r.Context(), requestorID, transactionID)
+	tx, err := h.service.GetTransactionDetail(r.Context(), requestorID, transactionID)
 	if err != nil {
 		if errors.Is(err, store.ErrTransactionNotFound) {
 			h.writeError(w, http.StatusNotFound, "Transaction not found")
@@ -770,6 +967,42 @@ func (h *TransactionHandlers) GetTransactionByIDHandler(w http.ResponseWriter, r
 	h.writeJSON(w, http.StatusOK, tx)
 }
 
+// CancelTransactionHandler cancels one of the caller's transfers that has not been
+// sent to Anchor yet and refunds it to their wallet.
+func (h *TransactionHandlers) CancelTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid transaction ID format")
+		return
+	}
+
+	tx, err := h.service.CancelTransaction(r.Context(), userID, transactionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrTransactionNotFound):
+			h.writeAppError(w, http.StatusNotFound, err)
+		case errors.Is(err, store.ErrTransactionNotCancellable):
+			h.writeAppError(w, http.StatusConflict, err)
+		default:
+			log.Printf("level=error component=api endpoint=cancel_transaction outcome=failed user_id=%s transaction_id=%s err=%v", userID, transactionID, err)
+			h.writeError(w, http.StatusInternalServerError, "Could not cancel the transfer.")
+		}
+		return
+	}
+
+	h.auditUser(r, userID, "transfer.cancel", "transaction", tx.ID.String(), map[string]interface{}{
+		"amount": tx.Amount,
+		"fee":    tx.Fee,
+	})
+	h.writeJSON(w, http.StatusOK, tx)
+}
+
 // PlatformFeeHandler handles internal requests to debit platform fees.
 // This is called by the platform-fee service for monthly billing.
 func (h *TransactionHandlers) PlatformFeeHandler(w http.ResponseWriter, r *http.Request) {
@@ -777,21 +1010,16 @@ func (h *TransactionHandlers) PlatformFeeHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	var req struct {
-		UserID    string `json:"user_id"`
-		Amount    int64  `json:"amount"`
-		Reason    string `json:"reason"`
-		InvoiceID string `json:"invoice_id"`
-	}
+	var req platformFeeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Parse the user ID as UUID
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
@@ -808,29 +1036,21 @@ func (h *TransactionHandlers) PlatformFeeHandler(w http.ResponseWriter, r *http.
 			log.Printf("level=warn component=api endpoint=platform_fee outcome=failed user_id=%s err=%v", userID, err)
 		}
 		if errors.Is(err, store.ErrInsufficientFunds) {
-			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			h.writeAppError(w, http.StatusPaymentRequired, err)
 			return
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	metadata := map[string]interface{}{"user_id": userID.String(), "amount": tx.Amount, "reason": req.Reason}
+	if req.InvoiceID != "" {
+		metadata["invoice_id"] = req.InvoiceID
+	}
+	h.auditInternal(r, "platform_fee.debit", "transaction", tx.ID.String(), metadata)
+
 	// Respond with the created transaction
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(tx)
 }
-
-// writeJSON is a helper for writing JSON responses.
-func (h *TransactionHandlers) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if data != nil {
-		json.NewEncoder(w).Encode(data)
-	}
-}
-
-// writeError is a helper for writing JSON error responses.
-func (h *TransactionHandlers) writeError(w http.ResponseWriter, status int, message string) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
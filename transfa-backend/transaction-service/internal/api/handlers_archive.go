@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// archivedIncludedHeader is set to "true" on history and statement responses built
+// partly from transactions_archive, so clients know older data was read.
+const archivedIncludedHeader = "X-Archived-Included"
+
+func setArchivedIncludedHeader(w http.ResponseWriter, transactions []domain.Transaction) {
+	for _, tx := range transactions {
+		if tx.Archived {
+			w.Header().Set(archivedIncludedHeader, "true")
+			return
+		}
+	}
+}
+
+// ArchiveTransactionsHandler starts a background run that moves old completed and
+// failed transactions into the archive. Called monthly by the scheduler; responds 202
+// once the run has started.
+func (h *TransactionHandlers) ArchiveTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	if err := h.service.StartTransactionArchive(); err != nil {
+		if errors.Is(err, app.ErrTransactionArchiveInProgress) {
+			h.writeAppError(w, http.StatusConflict, err)
+			return
+		}
+		log.Printf("level=error component=api endpoint=archive_transactions outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to start transaction archive")
+		return
+	}
+
+	h.auditInternal(r, "transaction.archive", "", "", nil)
+	log.Printf("level=info component=api endpoint=archive_transactions outcome=accepted")
+	h.writeJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
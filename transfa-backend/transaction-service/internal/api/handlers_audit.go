@@ -0,0 +1,109 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// auditActorHeader lets internal callers name the person or job behind a request,
+// e.g. a support agent's email or "scheduler".
+const auditActorHeader = "X-Audit-Actor"
+
+type auditEventsResponse struct {
+	Events  []domain.AuditEvent `json:"events"`
+	Dropped uint64              `json:"dropped_since_start"`
+}
+
+// auditUser records an action an app user took. It never blocks.
+func (h *TransactionHandlers) auditUser(r *http.Request, userID uuid.UUID, action, subjectType, subjectID string, metadata map[string]interface{}) {
+	h.auditLog.Record(domain.AuditEvent{
+		ActorType:   domain.AuditActorUser,
+		ActorID:     userID.String(),
+		Action:      action,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Metadata:    metadata,
+		IP:          clientIP(r),
+	})
+}
+
+// auditInternal records an action taken through an internal endpoint. It never blocks.
+func (h *TransactionHandlers) auditInternal(r *http.Request, action, subjectType, subjectID string, metadata map[string]interface{}) {
+	h.auditLog.Record(domain.AuditEvent{
+		ActorType:   domain.AuditActorInternal,
+		ActorID:     strings.TrimSpace(r.Header.Get(auditActorHeader)),
+		Action:      action,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Metadata:    metadata,
+		IP:          clientIP(r),
+	})
+}
+
+// clientIP prefers the first X-Forwarded-For hop, which is the client address when
+// running behind the Railway proxy.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// ListAuditEventsHandler returns audit trail entries filtered by subject, actor,
+// action and time range.
+func (h *TransactionHandlers) ListAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseDisputeTimeFilter(query.Get("from"), false)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	to, err := parseDisputeTimeFilter(query.Get("to"), true)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+	limit, err := parseOptionalPositiveInt(query.Get("limit"), 50)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	offset, err := parseOptionalPositiveInt(query.Get("offset"), 0)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+
+	events, err := h.service.ListAuditEvents(r.Context(), domain.AuditEventFilter{
+		SubjectType: query.Get("subject_type"),
+		SubjectID:   query.Get("subject_id"),
+		ActorID:     query.Get("actor_id"),
+		Action:      query.Get("action"),
+		From:        from,
+		To:          to,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_audit_events outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Could not load audit events.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, auditEventsResponse{Events: events, Dropped: h.auditLog.Dropped()})
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/transfa/transaction-service/internal/app"
+)
+
+// SyncAccountBalancesHandler starts a background sync of every account balance with Anchor.
+// Called nightly by the scheduler; responds 202 once the run has started.
+func (h *TransactionHandlers) SyncAccountBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	if err := h.service.StartAccountBalanceSync(); err != nil {
+		if errors.Is(err, app.ErrBalanceSyncInProgress) {
+			h.writeAppError(w, http.StatusConflict, err)
+			return
+		}
+		log.Printf("level=error component=api endpoint=sync_account_balances outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to start balance sync")
+		return
+	}
+
+	log.Printf("level=info component=api endpoint=sync_account_balances outcome=accepted")
+	h.writeJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
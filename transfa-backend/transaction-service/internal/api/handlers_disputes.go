@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func mapTransactionDisputeError(err error) (int, string) {
+	switch {
+	case errors.Is(err, store.ErrTransactionNotFound):
+		return http.StatusNotFound, "Transaction not found"
+	case errors.Is(err, store.ErrTransactionDisputeNotFound):
+		return http.StatusNotFound, "Dispute not found"
+	case errors.Is(err, store.ErrTransactionDisputeExists):
+		return http.StatusConflict, "This transaction already has an open dispute."
+	case errors.Is(err, app.ErrTransactionNotDisputable),
+		errors.Is(err, app.ErrInvalidDisputeTransition):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, app.ErrInvalidDisputeReason),
+		errors.Is(err, app.ErrInvalidDisputeDescription),
+		errors.Is(err, app.ErrInvalidDisputeStatus),
+		errors.Is(err, app.ErrDisputeResolutionNoteRequired),
+		errors.Is(err, app.ErrInvalidDisputeResolutionNote):
+		return http.StatusBadRequest, err.Error()
+	}
+	return http.StatusInternalServerError, "Could not process transaction dispute."
+}
+
+// parseDisputeTimeFilter accepts an RFC3339 timestamp or a YYYY-MM-DD date. A bare
+// date used as an upper bound covers the whole day.
+func parseDisputeTimeFilter(raw string, upperBound bool) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if value, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &value, nil
+	}
+	value, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, err
+	}
+	if upperBound {
+		value = value.AddDate(0, 0, 1)
+	}
+	return &value, nil
+}
+
+func (h *TransactionHandlers) CreateTransactionDisputeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid transaction ID format")
+		return
+	}
+
+	var payload domain.CreateTransactionDisputePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	dispute, err := h.service.CreateTransactionDispute(r.Context(), userID, transactionID, payload)
+	if err != nil {
+		status, msg := mapTransactionDisputeError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=create_transaction_dispute outcome=failed user_id=%s transaction_id=%s err=%v", userID, transactionID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	log.Printf("level=info component=api endpoint=create_transaction_dispute outcome=created user_id=%s transaction_id=%s dispute_id=%s reason=%s", userID, transactionID, dispute.ID, dispute.Reason)
+	h.writeJSON(w, http.StatusCreated, dispute)
+}
+
+func (h *TransactionHandlers) ListTransactionDisputesHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseDisputeTimeFilter(query.Get("from"), false)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	to, err := parseDisputeTimeFilter(query.Get("to"), true)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+	limit, err := parseOptionalPositiveInt(query.Get("limit"), 50)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	offset, err := parseOptionalPositiveInt(query.Get("offset"), 0)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+
+	disputes, err := h.service.ListTransactionDisputes(r.Context(), domain.TransactionDisputeFilter{
+		Status: query.Get("status"),
+		From:   from,
+		To:     to,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		status, msg := mapTransactionDisputeError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=list_transaction_disputes outcome=failed err=%v", err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, disputes)
+}
+
+// ListMyTransactionDisputesHandler lists the disputes the caller has raised.
+func (h *TransactionHandlers) ListMyTransactionDisputesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseOptionalPositiveInt(query.Get("limit"), 50)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	offset, err := parseOptionalPositiveInt(query.Get("offset"), 0)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+
+	disputes, err := h.service.ListUserTransactionDisputes(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_my_transaction_disputes outcome=failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not load disputes.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, disputes)
+}
+
+// UpdateTransactionDisputeStatusHandler lets support move a dispute along its review
+// workflow.
+func (h *TransactionHandlers) UpdateTransactionDisputeStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	disputeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid dispute ID format")
+		return
+	}
+
+	var payload domain.UpdateTransactionDisputeStatusPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	dispute, err := h.service.UpdateTransactionDisputeStatus(r.Context(), disputeID, payload)
+	if err != nil {
+		status, msg := mapTransactionDisputeError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=update_transaction_dispute_status outcome=failed dispute_id=%s err=%v", disputeID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	h.auditInternal(r, "dispute.update_status", "dispute", dispute.ID.String(), map[string]interface{}{
+		"transaction_id": dispute.TransactionID.String(),
+		"status":         dispute.Status,
+	})
+
+	log.Printf("level=info component=api endpoint=update_transaction_dispute_status outcome=updated dispute_id=%s status=%s", dispute.ID, dispute.Status)
+	h.writeJSON(w, http.StatusOK, dispute)
+}
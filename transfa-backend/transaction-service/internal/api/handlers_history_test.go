@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// historyRepoStub records the filter the history handler passes down.
+type historyRepoStub struct {
+	store.Repository
+
+	userID  uuid.UUID
+	filters []domain.TransactionHistoryFilter
+}
+
+func (s *historyRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	return s.userID.String(), nil
+}
+
+func (s *historyRepoStub) LatestArchivedTransactionAt(ctx context.Context) (*time.Time, error) {
+	return nil, nil
+}
+
+func (s *historyRepoStub) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error) {
+	s.filters = append(s.filters, filter)
+	return []domain.Transaction{}, nil
+}
+
+func getTransactionHistory(t *testing.T, target string) (*httptest.ResponseRecorder, *historyRepoStub) {
+	t.Helper()
+	repo := &historyRepoStub{userID: uuid.New()}
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	h := NewTransactionHandlers(service, "", nil)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = req.WithContext(context.WithValue(req.Context(), clerkUserIDKey, "user_test"))
+	rec := httptest.NewRecorder()
+	h.GetTransactionHistoryHandler(rec, req)
+	return rec, repo
+}
+
+func TestGetTransactionHistoryHandler_FiltersByType(t *testing.T) {
+	rec, repo := getTransactionHistory(t, "/transactions?type=p2p,self_transfer&type=money_drop_claim")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.filters) != 1 {
+		t.Fatalf("expected one history lookup, got %d", len(repo.filters))
+	}
+	want := []string{"p2p", "self_transfer", "money_drop_claim"}
+	if got := repo.filters[0].Types; !slices.Equal(got, want) {
+		t.Fatalf("expected types %v, got %v", want, got)
+	}
+	if repo.filters[0].Status != "" {
+		t.Fatalf("expected no status filter, got %q", repo.filters[0].Status)
+	}
+}
+
+func TestGetTransactionHistoryHandler_FiltersByStatus(t *testing.T) {
+	rec, repo := getTransactionHistory(t, "/transactions?status=Completed")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := repo.filters[0].Status; got != "completed" {
+		t.Fatalf("expected status completed, got %q", got)
+	}
+	if len(repo.filters[0].Types) != 0 {
+		t.Fatalf("expected all types without a type filter, got %v", repo.filters[0].Types)
+	}
+}
+
+func TestGetTransactionHistoryHandler_CombinesTypeAndStatus(t *testing.T) {
+	rec, repo := getTransactionHistory(t, "/transactions?type=subscription_fee&status=failed&from=2026-01-01")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	filter := repo.filters[0]
+	if !slices.Equal(filter.Types, []string{"subscription_fee"}) || filter.Status != "failed" {
+		t.Fatalf("expected subscription_fee/failed filter, got %+v", filter)
+	}
+	if filter.From == nil {
+		t.Fatal("expected the date range to be kept alongside type and status")
+	}
+}
+
+func TestGetTransactionHistoryHandler_RejectsInvalidStatus(t *testing.T) {
+	rec, repo := getTransactionHistory(t, "/transactions?status=settled")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.filters) != 0 {
+		t.Fatalf("expected no history lookup for an invalid status, got %d", len(repo.filters))
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// incomingPaymentRequestRepoStub serves one individual payment request to its
+// recipient, like the Postgres recipient lookup.
+type incomingPaymentRequestRepoStub struct {
+	store.Repository
+
+	callerID uuid.UUID
+	request  domain.PaymentRequest
+}
+
+func (s *incomingPaymentRequestRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	return s.callerID.String(), nil
+}
+
+func (s *incomingPaymentRequestRepoStub) GetIncomingPaymentRequestByID(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error) {
+	if requestID != s.request.ID || s.request.RecipientUserID == nil || *s.request.RecipientUserID != recipientID {
+		return nil, nil
+	}
+	request := s.request
+	return &request, nil
+}
+
+func (s *incomingPaymentRequestRepoStub) ListPaymentRequestSettlements(ctx context.Context, requestID uuid.UUID) ([]domain.PaymentRequestSettlement, error) {
+	return nil, nil
+}
+
+func newIncomingPaymentRequestTestRouter() (http.Handler, *incomingPaymentRequestRepoStub) {
+	recipientID := uuid.New()
+	creatorUsername := "ada"
+	repo := &incomingPaymentRequestRepoStub{
+		callerID: recipientID,
+		request: domain.PaymentRequest{
+			ID:              uuid.New(),
+			CreatorID:       uuid.New(),
+			CreatorUsername: &creatorUsername,
+			Status:          "pending",
+			RequestType:     "individual",
+			Title:           "Dinner",
+			RecipientUserID: &recipientID,
+			Amount:          250000,
+			CreatedAt:       time.Now().Add(-time.Hour),
+		},
+	}
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	h := NewTransactionHandlers(service, "", nil)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clerkUserIDKey, "user_test")))
+		})
+	})
+	r.Get("/payment-requests/incoming/{id}", h.GetIncomingPaymentRequestByIDHandler)
+	return r, repo
+}
+
+func getIncomingPaymentRequest(router http.Handler, requestID uuid.UUID) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payment-requests/incoming/"+requestID.String(), nil))
+	return rec
+}
+
+func TestGetIncomingPaymentRequestByIDHandler_ServesRecipient(t *testing.T) {
+	router, repo := newIncomingPaymentRequestTestRouter()
+
+	rec := getIncomingPaymentRequest(router, repo.request.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body domain.PaymentRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != repo.request.ID || body.CreatorUsername == nil || *body.CreatorUsername != "ada" || body.DisplayStatus != "pending" {
+		t.Fatalf("unexpected request %+v", body)
+	}
+}
+
+func TestGetIncomingPaymentRequestByIDHandler_HidesRequestFromOtherUsers(t *testing.T) {
+	router, repo := newIncomingPaymentRequestTestRouter()
+
+	for _, caller := range []uuid.UUID{repo.request.CreatorID, uuid.New()} {
+		repo.callerID = caller
+		rec := getIncomingPaymentRequest(router, repo.request.ID)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("caller %s: expected 404, got %d: %s", caller, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// Payment requests have no expiry of their own; a request the recipient already
+// answered long ago stays readable so its history can be shown.
+func TestGetIncomingPaymentRequestByIDHandler_ServesOldAnsweredRequest(t *testing.T) {
+	router, repo := newIncomingPaymentRequestTestRouter()
+	answeredAt := time.Now().AddDate(0, -3, 0)
+	reason := "Already settled in cash"
+	repo.request.Status = "declined"
+	repo.request.CreatedAt = answeredAt.AddDate(0, 0, -7)
+	repo.request.RespondedAt = &answeredAt
+	repo.request.DeclinedAt = &answeredAt
+	repo.request.DeclinedReason = &reason
+
+	rec := getIncomingPaymentRequest(router, repo.request.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body domain.PaymentRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.DisplayStatus != "declined" || body.DeclinedReason == nil || *body.DeclinedReason != reason {
+		t.Fatalf("unexpected request %+v", body)
+	}
+}
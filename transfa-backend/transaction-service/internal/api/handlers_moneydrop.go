@@ -23,11 +23,22 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
 	"github.com/transfa/transaction-service/internal/app"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
 )
 
+type reconcileMoneyDropClaimsRequest struct {
+	Limit int `json:"limit"`
+}
+
+type refundMoneyDropRequest struct {
+	DropID    string `json:"drop_id"`
+	CreatorID string `json:"creator_id"`
+	Amount    int64  `json:"amount"`
+}
+
 func (h *TransactionHandlers) writeRateLimitError(w http.ResponseWriter, err error) bool {
 	var rateLimitErr *app.RateLimitError
 	if !errors.As(err, &rateLimitErr) {
@@ -53,7 +64,7 @@ func (h *TransactionHandlers) CreateMoneyDropHandler(w http.ResponseWriter, r *h
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=create_money_drop outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		h.writeError(w, http.StatusBadRequest, "User not found")
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	userID, err := uuid.Parse(internalIDStr)
@@ -69,24 +80,24 @@ func (h *TransactionHandlers) CreateMoneyDropHandler(w http.ResponseWriter, r *h
 	}
 
 	// Validate request
+	var invalid []apierror.FieldError
 	if strings.TrimSpace(req.Title) == "" {
-		h.writeError(w, http.StatusBadRequest, "Title is required")
-		return
+		invalid = append(invalid, apierror.FieldError{Field: "title", Message: "Title is required"})
 	}
 	if req.TotalAmount <= 0 {
-		h.writeError(w, http.StatusBadRequest, "Total amount must be greater than 0")
-		return
+		invalid = append(invalid, apierror.FieldError{Field: "total_amount", Message: "Total amount must be greater than 0"})
 	}
 	if req.NumberOfPeople <= 0 {
-		h.writeError(w, http.StatusBadRequest, "Number of people must be greater than 0")
-		return
+		invalid = append(invalid, apierror.FieldError{Field: "number_of_people", Message: "Number of people must be greater than 0"})
 	}
 	if req.ExpiryInMinutes <= 0 || req.ExpiryInMinutes > 1440 {
-		h.writeError(w, http.StatusBadRequest, "Expiry time must be between 1 and 1440 minutes")
-		return
+		invalid = append(invalid, apierror.FieldError{Field: "expiry_in_minutes", Message: "Expiry time must be between 1 and 1440 minutes"})
 	}
 	if req.LockDrop && strings.TrimSpace(req.LockPassword) == "" {
-		h.writeError(w, http.StatusBadRequest, "Drop password is required when lock drop is enabled")
+		invalid = append(invalid, apierror.FieldError{Field: "lock_password", Message: "Drop password is required when lock drop is enabled"})
+	}
+	if len(invalid) > 0 {
+		h.writeValidationError(w, invalid...)
 		return
 	}
 	if !h.authorizeTransactionPIN(r, w, userID, req.TransactionPIN) {
@@ -103,8 +114,9 @@ func (h *TransactionHandlers) CreateMoneyDropHandler(w http.ResponseWriter, r *h
 			errors.Is(err, app.ErrInvalidMoneyDropPeopleCount),
 			errors.Is(err, app.ErrInvalidMoneyDropExpiry),
 			errors.Is(err, app.ErrMissingMoneyDropPassword),
-			errors.Is(err, app.ErrInvalidMoneyDropPassword):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			errors.Is(err, app.ErrInvalidMoneyDropPassword),
+			errors.As(err, new(*app.UnsupportedCurrencyError)):
+			h.writeAppError(w, http.StatusBadRequest, err)
 			return
 		case errors.Is(err, app.ErrMoneyDropPasswordEncryptionUnavailable):
 			h.writeError(w, http.StatusServiceUnavailable, "Locked money drops are temporarily unavailable")
@@ -112,18 +124,26 @@ func (h *TransactionHandlers) CreateMoneyDropHandler(w http.ResponseWriter, r *h
 		case errors.Is(err, app.ErrMoneyDropAccountProvisioningUnavailable):
 			h.writeError(w, http.StatusServiceUnavailable, "Money drop account provisioning is temporarily unavailable")
 			return
-		case strings.Contains(err.Error(), "must be divisible equally"),
-			strings.Contains(err.Error(), "insufficient funds"):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+		case strings.Contains(err.Error(), "must be divisible equally"):
+			h.writeValidationError(w, apierror.FieldError{Field: "total_amount", Message: err.Error()})
+			return
+		case strings.Contains(err.Error(), "insufficient funds"):
+			h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeInsufficientFunds, err.Error())
 			return
 		case errors.Is(err, store.ErrAccountNotFound):
-			h.writeError(w, http.StatusBadRequest, "Primary account not found")
+			h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeAccountNotFound, "Primary account not found")
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, "Failed to create money drop")
 		return
 	}
 
+	h.auditUser(r, userID, "money_drop.create", "money_drop", response.MoneyDropID, map[string]interface{}{
+		"total_amount":     response.TotalAmount,
+		"fee":              response.Fee,
+		"number_of_people": response.NumberOfPeople,
+	})
+
 	h.writeJSON(w, http.StatusCreated, response)
 }
 
@@ -140,7 +160,7 @@ func (h *TransactionHandlers) ClaimMoneyDropHandler(w http.ResponseWriter, r *ht
 	internalIDStr, err := h.service.ResolveInternalUserID(r.Context(), userIDStr)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=claim_money_drop outcome=reject reason=user_resolution_failed clerk_user_id=%s err=%v", userIDStr, err)
-		h.writeError(w, http.StatusBadRequest, "User not found")
+		h.writeErrorCode(w, http.StatusBadRequest, apierror.CodeUserNotFound, "User not found")
 		return
 	}
 	claimantID, err := uuid.Parse(internalIDStr)
@@ -182,19 +202,23 @@ func (h *TransactionHandlers) ClaimMoneyDropHandler(w http.ResponseWriter, r *ht
 		}
 		switch {
 		case errors.Is(err, app.ErrMoneyDropPasswordClaimLocked):
-			h.writeError(w, http.StatusLocked, err.Error())
+			h.writeAppError(w, http.StatusLocked, err)
 		case errors.Is(err, app.ErrMoneyDropIdempotencyInProgress):
-			h.writeError(w, http.StatusConflict, err.Error())
+			h.writeAppError(w, http.StatusConflict, err)
 		case errors.Is(err, app.ErrMoneyDropIdempotencyConflict):
-			h.writeError(w, http.StatusConflict, err.Error())
+			h.writeAppError(w, http.StatusConflict, err)
 		case errors.Is(err, app.ErrInvalidIdempotencyKey):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		default:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		}
 		return
 	}
 
+	h.auditUser(r, claimantID, "money_drop.claim", "money_drop", dropID.String(), map[string]interface{}{
+		"amount": response.AmountClaimed,
+	})
+
 	h.writeJSON(w, http.StatusOK, response)
 }
 
@@ -224,7 +248,7 @@ func (h *TransactionHandlers) GetMoneyDropDetailsHandler(w http.ResponseWriter,
 	details, err := h.service.GetMoneyDropDetails(r.Context(), dropID)
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_money_drop_details outcome=failed drop_id=%s err=%v", dropID, err)
-		h.writeError(w, http.StatusNotFound, "Money drop not found")
+		h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
 		return
 	}
 
@@ -275,7 +299,7 @@ func (h *TransactionHandlers) GetMoneyDropOwnerDetailsHandler(w http.ResponseWri
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_money_drop_owner_details outcome=failed user_id=%s drop_id=%s err=%v", userID, dropID, err)
 		if errors.Is(err, store.ErrMoneyDropNotFound) {
-			h.writeError(w, http.StatusNotFound, "Money drop not found")
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, "Failed to fetch money drop details")
@@ -315,14 +339,14 @@ func (h *TransactionHandlers) RevealMoneyDropPasswordHandler(w http.ResponseWrit
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=reveal_money_drop_password outcome=failed user_id=%s drop_id=%s err=%v", userID, dropID, err)
 		if errors.Is(err, store.ErrMoneyDropNotFound) {
-			h.writeError(w, http.StatusNotFound, "Money drop not found")
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
 			return
 		}
 		if errors.Is(err, app.ErrMoneyDropPasswordEncryptionUnavailable) {
 			h.writeError(w, http.StatusServiceUnavailable, "Drop password reveal is temporarily unavailable")
 			return
 		}
-		h.writeError(w, http.StatusBadRequest, err.Error())
+		h.writeAppError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -364,7 +388,7 @@ func (h *TransactionHandlers) GetMoneyDropClaimersHandler(w http.ResponseWriter,
 	if err != nil {
 		log.Printf("level=warn component=api endpoint=get_money_drop_claimers outcome=failed user_id=%s drop_id=%s err=%v", userID, dropID, err)
 		if errors.Is(err, store.ErrMoneyDropNotFound) {
-			h.writeError(w, http.StatusNotFound, "Money drop not found")
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, "Failed to fetch money drop claimers")
@@ -394,9 +418,9 @@ func (h *TransactionHandlers) EndMoneyDropHandler(w http.ResponseWriter, r *http
 		log.Printf("level=warn component=api endpoint=end_money_drop outcome=failed user_id=%s drop_id=%s err=%v", userID, dropID, err)
 		switch {
 		case errors.Is(err, store.ErrMoneyDropNotFound):
-			h.writeError(w, http.StatusNotFound, "Money drop not found")
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
 		default:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -429,9 +453,7 @@ func (h *TransactionHandlers) ReconcileMoneyDropClaimsHandler(w http.ResponseWri
 		return
 	}
 
-	var req struct {
-		Limit int `json:"limit"`
-	}
+	var req reconcileMoneyDropClaimsRequest
 	if r.Body != nil {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
 			h.writeError(w, http.StatusBadRequest, "Invalid request body")
@@ -452,9 +474,63 @@ func (h *TransactionHandlers) ReconcileMoneyDropClaimsHandler(w http.ResponseWri
 		return
 	}
 
+	h.auditInternal(r, "money_drop.reconcile_claims", "", "", map[string]interface{}{
+		"processed":    result.Processed,
+		"retried":      result.Retried,
+		"retry_failed": result.RetryFailed,
+	})
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// ExpireMoneyDropsHandler finalizes all expired or fully claimed money drops and
+// refunds what is left to their creators. Called by the scheduler.
+func (h *TransactionHandlers) ExpireMoneyDropsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	result, err := h.service.ExpireMoneyDrops(r.Context())
+	if err != nil {
+		log.Printf("level=error component=api endpoint=expire_money_drops outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to expire money drops")
+		return
+	}
+
+	h.auditInternal(r, "money_drop.expire", "", "", map[string]interface{}{
+		"processed":       result.Processed,
+		"expired":         result.Expired,
+		"failed":          result.Failed,
+		"refunded_amount": result.RefundedAmount,
+	})
+
+	log.Printf("level=info component=api endpoint=expire_money_drops outcome=completed processed=%d expired=%d failed=%d refunded_amount=%d", result.Processed, result.Expired, result.Failed, result.RefundedAmount)
 	h.writeJSON(w, http.StatusOK, result)
 }
 
+// ListExpiredMoneyDropsHandler lists active money drops past their expiry for the
+// scheduler. The optional limit query parameter defaults to 100 and is capped at 500.
+func (h *TransactionHandlers) ListExpiredMoneyDropsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	limit, err := parseOptionalPositiveInt(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	drops, err := h.service.ListExpiredActiveMoneyDrops(r.Context(), limit)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_expired_money_drops outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to list expired money drops")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, drops)
+}
+
 // RefundMoneyDropHandler handles internal requests to refund a money drop.
 // This is called by internal trusted services (scheduler).
 func (h *TransactionHandlers) RefundMoneyDropHandler(w http.ResponseWriter, r *http.Request) {
@@ -464,15 +540,11 @@ func (h *TransactionHandlers) RefundMoneyDropHandler(w http.ResponseWriter, r *h
 
 	log.Printf("level=info component=api endpoint=refund_money_drop outcome=accepted path=%s", r.URL.Path)
 
-	var req struct {
-		DropID    string `json:"drop_id"`
-		CreatorID string `json:"creator_id"`
-		Amount    int64  `json:"amount"`
-	}
+	var req refundMoneyDropRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("level=warn component=api endpoint=refund_money_drop outcome=reject reason=invalid_json err=%v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -480,27 +552,32 @@ func (h *TransactionHandlers) RefundMoneyDropHandler(w http.ResponseWriter, r *h
 
 	dropID, err := uuid.Parse(req.DropID)
 	if err != nil {
-		http.Error(w, "Invalid drop ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid drop ID format")
 		return
 	}
 
 	creatorID, err := uuid.Parse(req.CreatorID)
 	if err != nil {
-		http.Error(w, "Invalid creator ID format", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid creator ID format")
 		return
 	}
 	if req.Amount < 0 {
-		http.Error(w, "Invalid amount", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, "Invalid amount")
 		return
 	}
 
 	// Process the refund
 	if err := h.service.RefundMoneyDrop(r.Context(), dropID, creatorID, req.Amount); err != nil {
 		log.Printf("level=warn component=api endpoint=refund_money_drop outcome=failed drop_id=%s creator_id=%s err=%v", dropID, creatorID, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeAppError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	h.auditInternal(r, "money_drop.refund", "money_drop", dropID.String(), map[string]interface{}{
+		"creator_id": creatorID.String(),
+		"amount":     req.Amount,
+	})
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Refund processed successfully"))
 }
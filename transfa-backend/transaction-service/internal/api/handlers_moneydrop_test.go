@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// expiredDropsRepoStub returns a fixed set of expired drops, honouring the limit.
+type expiredDropsRepoStub struct {
+	store.Repository
+
+	drops     []domain.ExpiredMoneyDrop
+	lastLimit int
+}
+
+func (s *expiredDropsRepoStub) ListExpiredActiveMoneyDrops(ctx context.Context, limit int) ([]domain.ExpiredMoneyDrop, error) {
+	s.lastLimit = limit
+	if len(s.drops) > limit {
+		return s.drops[:limit], nil
+	}
+	return s.drops, nil
+}
+
+func newExpiredDropsTestHandlers(apiKey string, count int) (*TransactionHandlers, *expiredDropsRepoStub) {
+	repo := &expiredDropsRepoStub{}
+	expiry := time.Now().Add(-time.Duration(count) * time.Hour)
+	for i := 0; i < count; i++ {
+		repo.drops = append(repo.drops, domain.ExpiredMoneyDrop{
+			ID:                 uuid.New(),
+			CreatorID:          uuid.New(),
+			ExpiryTimestamp:    expiry.Add(time.Duration(i) * time.Hour),
+			TotalClaimsAllowed: 5,
+		})
+	}
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	return NewTransactionHandlers(service, apiKey, nil), repo
+}
+
+func listExpiredDrops(h *TransactionHandlers, query, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/internal/money-drops/expired"+query, nil)
+	if apiKey != "" {
+		req.Header.Set("X-Internal-API-Key", apiKey)
+	}
+	rec := httptest.NewRecorder()
+	h.ListExpiredMoneyDropsHandler(rec, req)
+	return rec
+}
+
+func TestListExpiredMoneyDropsHandler_RejectsMissingAPIKey(t *testing.T) {
+	h, repo := newExpiredDropsTestHandlers("internal-key", 1)
+
+	for _, key := range []string{"", "wrong-key"} {
+		rec := listExpiredDrops(h, "", key)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("key %q: expected 401, got %d: %s", key, rec.Code, rec.Body.String())
+		}
+	}
+	if repo.lastLimit != 0 {
+		t.Fatalf("expected the repository not to be queried, got limit %d", repo.lastLimit)
+	}
+}
+
+func TestListExpiredMoneyDropsHandler_EnforcesLimit(t *testing.T) {
+	h, repo := newExpiredDropsTestHandlers("internal-key", 3)
+
+	cases := []struct {
+		query     string
+		wantLimit int
+		wantCount int
+	}{
+		{query: "", wantLimit: 100, wantCount: 3},
+		{query: "?limit=2", wantLimit: 2, wantCount: 2},
+		{query: "?limit=10000", wantLimit: 500, wantCount: 3},
+	}
+	for _, tc := range cases {
+		rec := listExpiredDrops(h, tc.query, "internal-key")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tc.query, rec.Code, rec.Body.String())
+		}
+		var drops []domain.ExpiredMoneyDrop
+		if err := json.Unmarshal(rec.Body.Bytes(), &drops); err != nil {
+			t.Fatalf("%q: decode response: %v", tc.query, err)
+		}
+		if repo.lastLimit != tc.wantLimit || len(drops) != tc.wantCount {
+			t.Fatalf("%q: expected limit %d and %d drops, got limit %d and %d drops", tc.query, tc.wantLimit, tc.wantCount, repo.lastLimit, len(drops))
+		}
+	}
+
+	if rec := listExpiredDrops(h, "?limit=abc", "internal-key"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid limit, got %d", rec.Code)
+	}
+}
+
+func TestListExpiredMoneyDropsHandler_ReturnsEmptyArray(t *testing.T) {
+	h, _ := newExpiredDropsTestHandlers("internal-key", 0)
+
+	rec := listExpiredDrops(h, "", "internal-key")
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Fatalf("expected 200 with an empty array, got %d: %q", rec.Code, rec.Body.String())
+	}
+}
@@ -8,12 +8,22 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
 	"github.com/transfa/transaction-service/internal/domain"
 )
 
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 100
+
+	// nextCursorHeader carries the cursor for the next page of a list response.
+	nextCursorHeader = "X-Next-Cursor"
+)
+
 type markAllReadPayload struct {
 	Category *string `json:"category,omitempty"`
 }
@@ -58,7 +68,9 @@ func normalizeNotificationStatusFilter(raw string) (string, error) {
 	}
 }
 
-// ListInAppNotificationsHandler lists inbox notifications for the authenticated user.
+// ListInAppNotificationsHandler lists inbox notifications for the authenticated user,
+// newest first. Pages can be walked with offset or, preferably, with the cursor
+// returned in the X-Next-Cursor header, which is stable while new notifications arrive.
 func (h *TransactionHandlers) ListInAppNotificationsHandler(w http.ResponseWriter, r *http.Request) {
 	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
 	if statusCode != 0 {
@@ -66,17 +78,36 @@ func (h *TransactionHandlers) ListInAppNotificationsHandler(w http.ResponseWrite
 		return
 	}
 
-	limit, err := parseOptionalPositiveInt(r.URL.Query().Get("limit"), 50)
+	limit, err := parseOptionalPositiveInt(r.URL.Query().Get("limit"), defaultNotificationsLimit)
 	if err != nil {
 		h.writeError(w, http.StatusBadRequest, "Invalid limit")
 		return
 	}
+	if limit == 0 {
+		limit = defaultNotificationsLimit
+	}
+	if limit > maxNotificationsLimit {
+		limit = maxNotificationsLimit
+	}
 	offset, err := parseOptionalPositiveInt(r.URL.Query().Get("offset"), 0)
 	if err != nil {
 		h.writeError(w, http.StatusBadRequest, "Invalid offset")
 		return
 	}
 
+	var cursor *domain.NotificationCursor
+	if raw := strings.TrimSpace(r.URL.Query().Get("cursor")); raw != "" {
+		if offset > 0 {
+			h.writeError(w, http.StatusBadRequest, "Use either cursor or offset, not both")
+			return
+		}
+		cursor, err = app.DecodeNotificationCursor(raw)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
 	categoryFilter, err := normalizeNotificationCategoryFilter(r.URL.Query().Get("category"))
 	if err != nil {
 		h.writeError(w, http.StatusBadRequest, "Invalid category")
@@ -87,6 +118,20 @@ func (h *TransactionHandlers) ListInAppNotificationsHandler(w http.ResponseWrite
 		h.writeError(w, http.StatusBadRequest, "Invalid status")
 		return
 	}
+	if rawUnreadOnly := strings.TrimSpace(r.URL.Query().Get("unread_only")); rawUnreadOnly != "" {
+		unreadOnly, err := strconv.ParseBool(rawUnreadOnly)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid unread_only")
+			return
+		}
+		if unreadOnly {
+			if statusFilter == "read" {
+				h.writeError(w, http.StatusBadRequest, "unread_only conflicts with status=read")
+				return
+			}
+			statusFilter = "unread"
+		}
+	}
 
 	opts := domain.NotificationListOptions{
 		Limit:    limit,
@@ -94,6 +139,7 @@ func (h *TransactionHandlers) ListInAppNotificationsHandler(w http.ResponseWrite
 		Search:   strings.TrimSpace(r.URL.Query().Get("q")),
 		Category: categoryFilter,
 		Status:   statusFilter,
+		Cursor:   cursor,
 	}
 
 	items, err := h.service.ListInAppNotifications(r.Context(), userID, opts)
@@ -103,6 +149,9 @@ func (h *TransactionHandlers) ListInAppNotificationsHandler(w http.ResponseWrite
 		return
 	}
 
+	if len(items) == limit {
+		w.Header().Set(nextCursorHeader, app.EncodeNotificationCursor(items[len(items)-1]))
+	}
 	h.writeJSON(w, http.StatusOK, items)
 }
 
@@ -124,6 +173,24 @@ func (h *TransactionHandlers) GetInAppNotificationUnreadCountsHandler(w http.Res
 	h.writeJSON(w, http.StatusOK, counts)
 }
 
+// GetInAppNotificationUnreadCountHandler returns the total unread count for the inbox badge.
+func (h *TransactionHandlers) GetInAppNotificationUnreadCountHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	count, err := h.service.GetInAppNotificationUnreadCount(r.Context(), userID)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=get_notification_unread_count outcome=failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not retrieve unread count.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]int64{"unread_count": count})
+}
+
 // MarkInAppNotificationReadHandler marks one notification as read.
 func (h *TransactionHandlers) MarkInAppNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
 	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
@@ -184,3 +251,53 @@ func (h *TransactionHandlers) MarkAllInAppNotificationsReadHandler(w http.Respon
 
 	h.writeJSON(w, http.StatusOK, map[string]int64{"updated": updated})
 }
+
+// MarkInAppNotificationsReadHandler marks a list of notifications as read.
+func (h *TransactionHandlers) MarkInAppNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	var payload domain.MarkInAppNotificationsReadPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	updated, err := h.service.MarkInAppNotificationsRead(r.Context(), userID, payload.IDs)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidNotificationIDs) {
+			h.writeAppError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		log.Printf("level=error component=api endpoint=mark_notifications_read outcome=failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not update notifications.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]int64{"updated": updated})
+}
+
+// CleanupNotificationsHandler deletes read notifications past their retention.
+// Called daily by the scheduler.
+func (h *TransactionHandlers) CleanupNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	result, err := h.service.CleanupReadNotifications(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("level=error component=api endpoint=cleanup_notifications outcome=failed deleted=%d err=%v", result.Deleted, err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to clean up notifications")
+		return
+	}
+
+	h.auditInternal(r, "notification.cleanup", "", "", map[string]interface{}{
+		"cutoff":  result.Cutoff,
+		"deleted": result.Deleted,
+	})
+	log.Printf("level=info component=api endpoint=cleanup_notifications outcome=completed cutoff=%s deleted=%d batches=%d", result.Cutoff.Format(time.RFC3339), result.Deleted, result.Batches)
+	h.writeJSON(w, http.StatusOK, result)
+}
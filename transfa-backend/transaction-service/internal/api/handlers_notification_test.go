@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// notificationRepoStub keeps one user's notifications in memory and applies the same
+// status and cursor filtering as the Postgres query.
+type notificationRepoStub struct {
+	store.Repository
+
+	userID        uuid.UUID
+	notifications []domain.InAppNotification
+	lastOpts      domain.NotificationListOptions
+}
+
+func (s *notificationRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	return s.userID.String(), nil
+}
+
+func (s *notificationRepoStub) ListInAppNotifications(ctx context.Context, userID uuid.UUID, opts domain.NotificationListOptions) ([]domain.InAppNotification, error) {
+	s.lastOpts = opts
+	items := append([]domain.InAppNotification(nil), s.notifications...)
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID.String() > items[j].ID.String()
+	})
+
+	result := []domain.InAppNotification{}
+	for _, item := range items {
+		if opts.Status != "" && item.Status != opts.Status {
+			continue
+		}
+		if c := opts.Cursor; c != nil {
+			older := item.CreatedAt.Before(c.CreatedAt) || (item.CreatedAt.Equal(c.CreatedAt) && item.ID.String() < c.ID.String())
+			if !older {
+				continue
+			}
+		}
+		result = append(result, item)
+	}
+	if opts.Offset < len(result) {
+		result = result[opts.Offset:]
+	} else {
+		result = nil
+	}
+	if len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+func (s *notificationRepoStub) MarkInAppNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) (bool, error) {
+	for i := range s.notifications {
+		if s.notifications[i].ID == notificationID && s.notifications[i].UserID == userID {
+			s.notifications[i].Status = "read"
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *notificationRepoStub) MarkInAppNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var updated int64
+	for _, id := range ids {
+		for i := range s.notifications {
+			if s.notifications[i].ID == id && s.notifications[i].UserID == userID && s.notifications[i].Status == "unread" {
+				s.notifications[i].Status = "read"
+				updated++
+			}
+		}
+	}
+	return updated, nil
+}
+
+func (s *notificationRepoStub) GetInAppNotificationUnreadCounts(ctx context.Context, userID uuid.UUID) (*domain.NotificationUnreadCounts, error) {
+	counts := &domain.NotificationUnreadCounts{}
+	for _, item := range s.notifications {
+		if item.UserID == userID && item.Status == "unread" {
+			counts.Total++
+		}
+	}
+	return counts, nil
+}
+
+func newNotificationTestRouter(repo *notificationRepoStub) http.Handler {
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	h := NewTransactionHandlers(service, "", nil)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clerkUserIDKey, "user_test")))
+		})
+	})
+	r.Get("/notifications", h.ListInAppNotificationsHandler)
+	r.Get("/notifications/unread-count", h.GetInAppNotificationUnreadCountHandler)
+	r.Post("/notifications/read", h.MarkInAppNotificationsReadHandler)
+	r.Post("/notifications/{id}/read", h.MarkInAppNotificationReadHandler)
+	return r
+}
+
+// seedNotifications creates n notifications a minute apart, newest first; every
+// other one is already read.
+func seedNotifications(userID uuid.UUID, n int) []domain.InAppNotification {
+	base := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	items := make([]domain.InAppNotification, n)
+	for i := range items {
+		status := "unread"
+		if i%2 == 1 {
+			status = "read"
+		}
+		items[i] = domain.InAppNotification{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Category:  "system",
+			Type:      "system.notice",
+			Title:     "Notice",
+			Status:    status,
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return items
+}
+
+func getNotifications(t *testing.T, router http.Handler, target string) ([]domain.InAppNotification, *httptest.ResponseRecorder) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d: %s", target, rec.Code, rec.Body.String())
+	}
+	var items []domain.InAppNotification
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatalf("decode notifications: %v", err)
+	}
+	return items, rec
+}
+
+func getUnreadCount(t *testing.T, router http.Handler) int64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications/unread-count", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]int64
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode unread count: %v", err)
+	}
+	return body["unread_count"]
+}
+
+func TestListInAppNotificationsHandler_UnreadOnly(t *testing.T) {
+	userID := uuid.New()
+	repo := &notificationRepoStub{userID: userID, notifications: seedNotifications(userID, 5)}
+	router := newNotificationTestRouter(repo)
+
+	items, _ := getNotifications(t, router, "/notifications?unread_only=true")
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 unread notifications, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.Status != "unread" {
+			t.Fatalf("expected only unread notifications, got %s", item.Status)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications?unread_only=true&status=read", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for conflicting filters, got %d", rec.Code)
+	}
+}
+
+func TestListInAppNotificationsHandler_CursorPagination(t *testing.T) {
+	userID := uuid.New()
+	seeded := seedNotifications(userID, 5)
+	// Two notifications created at the same instant must still page without overlap.
+	seeded[2].CreatedAt = seeded[1].CreatedAt
+	repo := &notificationRepoStub{userID: userID, notifications: seeded}
+	router := newNotificationTestRouter(repo)
+
+	seen := map[uuid.UUID]bool{}
+	var pages []int
+	target := "/notifications?limit=2"
+	for target != "" {
+		items, rec := getNotifications(t, router, target)
+		pages = append(pages, len(items))
+		for _, item := range items {
+			if seen[item.ID] {
+				t.Fatalf("notification %s returned on more than one page", item.ID)
+			}
+			seen[item.ID] = true
+		}
+		target = ""
+		if cursor := rec.Header().Get(nextCursorHeader); cursor != "" {
+			target = "/notifications?limit=2&cursor=" + cursor
+		}
+		if len(pages) > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 notifications across pages, got %d (pages %v)", len(seen), pages)
+	}
+	if pages[len(pages)-1] == 2 {
+		t.Fatalf("expected the last page to be short, got pages %v", pages)
+	}
+}
+
+func TestListInAppNotificationsHandler_RejectsBadCursor(t *testing.T) {
+	userID := uuid.New()
+	router := newNotificationTestRouter(&notificationRepoStub{userID: userID})
+
+	for _, target := range []string{
+		"/notifications?cursor=not-a-cursor",
+		"/notifications?offset=2&cursor=" + app.EncodeNotificationCursor(domain.InAppNotification{ID: uuid.New(), CreatedAt: time.Now()}),
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("GET %s: expected 400, got %d", target, rec.Code)
+		}
+	}
+}
+
+func TestMarkInAppNotificationReadHandler_UpdatesUnreadCount(t *testing.T) {
+	userID := uuid.New()
+	seeded := seedNotifications(userID, 4)
+	repo := &notificationRepoStub{userID: userID, notifications: seeded}
+	router := newNotificationTestRouter(repo)
+
+	if got := getUnreadCount(t, router); got != 2 {
+		t.Fatalf("expected 2 unread before marking, got %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/"+seeded[0].ID.String()+"/read", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := getUnreadCount(t, router); got != 1 {
+		t.Fatalf("expected 1 unread after marking, got %d", got)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/"+uuid.NewString()+"/read", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown notification, got %d", rec.Code)
+	}
+}
+
+func TestMarkInAppNotificationsReadHandler_MarksOnlyTheCallersUnread(t *testing.T) {
+	userID := uuid.New()
+	seeded := seedNotifications(userID, 4)
+	otherUsers := seedNotifications(uuid.New(), 1)
+	repo := &notificationRepoStub{userID: userID, notifications: append(seeded, otherUsers...)}
+	router := newNotificationTestRouter(repo)
+
+	// seeded[0] and seeded[2] are unread, seeded[1] is already read.
+	body := fmt.Sprintf(`{"ids":["%s","%s","%s","%s"]}`, seeded[0].ID, seeded[1].ID, seeded[2].ID, otherUsers[0].ID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/read", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]int64
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["updated"] != 2 {
+		t.Fatalf("expected 2 notifications updated, got %d", resp["updated"])
+	}
+	if got := getUnreadCount(t, router); got != 0 {
+		t.Fatalf("expected no unread notifications left, got %d", got)
+	}
+	if repo.notifications[4].Status != "unread" {
+		t.Fatalf("expected another user's notification to stay unread")
+	}
+}
+
+func TestMarkInAppNotificationsReadHandler_RejectsEmptyAndOversizedLists(t *testing.T) {
+	repo := &notificationRepoStub{userID: uuid.New()}
+	router := newNotificationTestRouter(repo)
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = `"` + uuid.NewString() + `"`
+	}
+	for _, body := range []string{`{"ids":[]}`, `{}`, `{"ids":[` + strings.Join(ids, ",") + `]}`} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/read", strings.NewReader(body)))
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("%.40s: expected 422, got %d", body, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/read", strings.NewReader(`{"ids":["not-a-uuid"]}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a malformed id, got %d", rec.Code)
+	}
+}
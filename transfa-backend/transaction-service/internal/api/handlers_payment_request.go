@@ -18,6 +18,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
 	"github.com/transfa/transaction-service/internal/app"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
@@ -90,7 +91,7 @@ func (h *TransactionHandlers) CreatePaymentRequestHandler(w http.ResponseWriter,
 			errors.Is(err, app.ErrInvalidPaymentRequestDescription),
 			errors.Is(err, app.ErrInvalidPaymentRequestRecipient),
 			errors.Is(err, app.ErrSelfPaymentRequest):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		case errors.Is(err, store.ErrUserNotFound):
 			h.writeError(w, http.StatusNotFound, "Recipient not found")
 		default:
@@ -185,6 +186,10 @@ func (h *TransactionHandlers) DeletePaymentRequestHandler(w http.ResponseWriter,
 
 	deleted, err := h.service.DeletePaymentRequest(r.Context(), requestID, userID)
 	if err != nil {
+		if errors.Is(err, app.ErrPaymentRequestInProcessing) {
+			h.writeAppError(w, http.StatusConflict, err)
+			return
+		}
 		log.Printf("level=error component=api endpoint=delete_payment_request outcome=failed request_id=%s user_id=%s err=%v", requestID, userID, err)
 		h.writeError(w, http.StatusInternalServerError, "Could not delete payment request.")
 		return
@@ -287,23 +292,39 @@ func (h *TransactionHandlers) PayIncomingPaymentRequestHandler(w http.ResponseWr
 		return
 	}
 
+	var amount int64
+	if payload.Amount != nil {
+		if *payload.Amount <= 0 {
+			h.writeAppError(w, http.StatusUnprocessableEntity, app.ErrInvalidTransferAmount)
+			return
+		}
+		amount = *payload.Amount
+	}
+
 	if !h.authorizeTransactionPIN(r, w, userID, payload.TransactionPIN) {
 		return
 	}
 
-	result, err := h.service.PayIncomingPaymentRequest(r.Context(), requestID, userID)
+	result, err := h.service.PayIncomingPaymentRequest(r.Context(), requestID, userID, amount)
 	if err != nil {
 		switch {
 		case errors.Is(err, store.ErrInsufficientFunds):
-			h.writeError(w, http.StatusPaymentRequired, err.Error())
+			h.writeAppError(w, http.StatusPaymentRequired, err)
+		case errors.Is(err, store.ErrPlatformFeeDelinquent):
+			h.writeErrorCode(w, http.StatusPaymentRequired, apierror.CodePlatformFeeOverdue, "Platform fee overdue: pay the outstanding fee to send money")
+		case errors.Is(err, store.ErrSendingRestricted),
+			errors.Is(err, store.ErrReceivingRestricted):
+			h.writeAppError(w, http.StatusForbidden, err)
 		case errors.Is(err, app.ErrInvalidTransferAmount),
 			errors.Is(err, app.ErrInvalidDescription),
-			errors.Is(err, app.ErrInvalidRecipient):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			errors.Is(err, app.ErrInvalidRecipient),
+			errors.Is(err, app.ErrPaymentAmountExceedsRemaining),
+			errors.Is(err, app.ErrPartialPaymentNotAllowed):
+			h.writeAppError(w, http.StatusBadRequest, err)
 		case errors.Is(err, app.ErrPaymentRequestNotPending):
-			h.writeError(w, http.StatusConflict, err.Error())
+			h.writeAppError(w, http.StatusConflict, err)
 		case errors.Is(err, app.ErrPaymentRequestNotFound):
-			h.writeError(w, http.StatusNotFound, err.Error())
+			h.writeAppError(w, http.StatusNotFound, err)
 		case errors.Is(err, store.ErrUserNotFound):
 			h.writeError(w, http.StatusNotFound, "Request creator not found")
 		default:
@@ -313,6 +334,12 @@ func (h *TransactionHandlers) PayIncomingPaymentRequestHandler(w http.ResponseWr
 		return
 	}
 
+	h.auditUser(r, userID, "payment_request.pay", "payment_request", requestID.String(), map[string]interface{}{
+		"transaction_id": result.Transaction.ID.String(),
+		"amount":         result.Transaction.Amount,
+		"fee":            result.Transaction.Fee,
+	})
+
 	response := map[string]interface{}{
 		"request":     result.Request,
 		"transaction": buildTransferInitiationResponse(result.Transaction, "Transfer initiated"),
@@ -344,11 +371,11 @@ func (h *TransactionHandlers) DeclineIncomingPaymentRequestHandler(w http.Respon
 	if err != nil {
 		switch {
 		case errors.Is(err, app.ErrInvalidPaymentRequestDecline):
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeAppError(w, http.StatusBadRequest, err)
 		case errors.Is(err, app.ErrPaymentRequestNotPending):
-			h.writeError(w, http.StatusConflict, err.Error())
+			h.writeAppError(w, http.StatusConflict, err)
 		case errors.Is(err, app.ErrPaymentRequestNotFound):
-			h.writeError(w, http.StatusNotFound, err.Error())
+			h.writeAppError(w, http.StatusNotFound, err)
 		default:
 			log.Printf("level=error component=api endpoint=decline_incoming_payment_request outcome=failed request_id=%s recipient_id=%s err=%v", requestID, userID, err)
 			h.writeError(w, http.StatusInternalServerError, "Could not decline payment request.")
@@ -358,3 +385,122 @@ func (h *TransactionHandlers) DeclineIncomingPaymentRequestHandler(w http.Respon
 
 	h.writeJSON(w, http.StatusOK, request)
 }
+
+// CreatePaymentRequestSplitHandler splits a bill into one payment request per recipient.
+func (h *TransactionHandlers) CreatePaymentRequestSplitHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	var payload domain.CreatePaymentRequestSplitPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	split, err := h.service.CreatePaymentRequestSplit(r.Context(), userID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidTransferAmount),
+			errors.Is(err, app.ErrInvalidPaymentRequestTitle),
+			errors.Is(err, app.ErrInvalidPaymentRequestDescription),
+			errors.Is(err, app.ErrInvalidPaymentRequestRecipient),
+			errors.Is(err, app.ErrInvalidPaymentRequestSplitStrategy),
+			errors.Is(err, app.ErrInvalidPaymentRequestSplitRecipients),
+			errors.Is(err, app.ErrInvalidPaymentRequestSplitAmounts),
+			errors.Is(err, app.ErrDuplicatePaymentRequestSplitRecipient),
+			errors.Is(err, app.ErrSelfPaymentRequest):
+			h.writeAppError(w, http.StatusBadRequest, err)
+		case errors.Is(err, store.ErrUserNotFound):
+			h.writeError(w, http.StatusNotFound, "Recipient not found")
+		default:
+			log.Printf("level=error component=api endpoint=create_payment_request_split outcome=failed user_id=%s err=%v", userID, err)
+			h.writeError(w, http.StatusInternalServerError, "Could not create payment request split.")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, split)
+}
+
+// GetPaymentRequestSplitHandler returns a creator-owned split with each member's status.
+func (h *TransactionHandlers) GetPaymentRequestSplitHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	splitID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid payment request split ID.")
+		return
+	}
+
+	split, err := h.service.GetPaymentRequestSplit(r.Context(), splitID, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrPaymentRequestSplitNotFound) {
+			h.writeAppError(w, http.StatusNotFound, err)
+			return
+		}
+		log.Printf("level=error component=api endpoint=get_payment_request_split outcome=failed split_id=%s user_id=%s err=%v", splitID, userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not retrieve payment request split.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, split)
+}
+
+// GetPublicPaymentRequestHandler serves the unauthenticated pay page view of a
+// payment request. Lookups are rate limited per client address.
+func (h *TransactionHandlers) GetPublicPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.enforcePaymentRequestPublicRateLimit(w, r) {
+		return
+	}
+
+	requestID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid payment request ID.")
+		return
+	}
+
+	request, err := h.service.GetPublicPaymentRequest(r.Context(), requestID)
+	h.writePublicPaymentRequest(w, "get_public_payment_request", request, err)
+}
+
+// GetPublicPaymentRequestByCodeHandler is GetPublicPaymentRequestHandler for the short
+// code in a payment request's share link.
+func (h *TransactionHandlers) GetPublicPaymentRequestByCodeHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.enforcePaymentRequestPublicRateLimit(w, r) {
+		return
+	}
+
+	request, err := h.service.GetPublicPaymentRequestByShortCode(r.Context(), chi.URLParam(r, "code"))
+	h.writePublicPaymentRequest(w, "get_public_payment_request_by_code", request, err)
+}
+
+func (h *TransactionHandlers) enforcePaymentRequestPublicRateLimit(w http.ResponseWriter, r *http.Request) bool {
+	if _, err := h.service.EnforcePaymentRequestPublicRateLimit(r.Context(), clientIP(r)); err != nil {
+		if h.writeRateLimitError(w, err) {
+			return false
+		}
+		log.Printf("level=warn component=api endpoint=public_payment_request outcome=rate_limit_check_failed err=%v", err)
+	}
+	return true
+}
+
+func (h *TransactionHandlers) writePublicPaymentRequest(w http.ResponseWriter, endpoint string, request *domain.PublicPaymentRequest, err error) {
+	if err != nil {
+		if errors.Is(err, store.ErrPaymentRequestNotFound) {
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodePaymentRequestNotFound, "Payment request not found.")
+			return
+		}
+		log.Printf("level=error component=api endpoint=%s outcome=failed err=%v", endpoint, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not retrieve payment request.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, request)
+}
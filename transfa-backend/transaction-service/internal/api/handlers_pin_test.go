@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/store"
+	"github.com/transfa/transaction-service/pkg/authclient"
+)
+
+type pinRepoStub struct {
+	store.Repository
+
+	userID uuid.UUID
+}
+
+func (s *pinRepoStub) FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error) {
+	return s.userID.String(), nil
+}
+
+// newAuthServiceStub answers /internal/verify-transaction-pin with status and body,
+// recording the user id it was asked about.
+func newAuthServiceStub(t *testing.T, status int, body string, gotUserID *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/verify-transaction-pin" || r.Header.Get("X-Internal-API-Key") != "internal-key" {
+			http.Error(w, "unexpected request", http.StatusNotFound)
+			return
+		}
+		var payload struct {
+			UserID string `json:"user_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		*gotUserID = payload.UserID
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newPINTestHandlers(repo *pinRepoStub, authServiceURL string) *TransactionHandlers {
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	service.ConfigureAuthClient(authclient.NewClient(authServiceURL, "internal-key"))
+	return NewTransactionHandlers(service, "", nil)
+}
+
+func postP2PTransfer(h *TransactionHandlers, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/p2p", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), clerkUserIDKey, "user_test"))
+	rec := httptest.NewRecorder()
+	h.P2PTransferHandler(rec, req)
+	return rec
+}
+
+func TestP2PTransferHandler_RedirectsToPINSetupWhenAuthServiceReportsPINNotSet(t *testing.T) {
+	repo := &pinRepoStub{userID: uuid.New()}
+	var gotUserID string
+	authService := newAuthServiceStub(t, http.StatusPreconditionFailed,
+		`{"error":{"code":"transaction_pin_not_set","message":"transaction pin is not set"}}`, &gotUserID)
+	h := newPINTestHandlers(repo, authService.URL)
+
+	rec := postP2PTransfer(h, `{"recipient_username":"bola","amount":1000,"transaction_pin":"1234"}`)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+		Redirect string `json:"redirect"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Error.Code != "transaction_pin_not_set" || body.Redirect != "/setup-pin" {
+		t.Fatalf("expected transaction_pin_not_set with /setup-pin redirect, got %s", rec.Body.String())
+	}
+	if gotUserID != repo.userID.String() {
+		t.Fatalf("expected the auth-service to be asked about %s, got %q", repo.userID, gotUserID)
+	}
+}
+
+func TestP2PTransferHandler_MapsAuthServicePINErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+	}{
+		{name: "wrong pin", status: http.StatusUnauthorized, body: `{"error":{"code":"transaction_pin_invalid","message":"transaction pin is invalid"}}`, wantStatus: http.StatusUnauthorized},
+		{name: "locked pin", status: http.StatusLocked, body: `{"error":{"code":"transaction_pin_locked","message":"transaction pin is temporarily locked"}}`, wantStatus: http.StatusLocked},
+		{name: "legacy wrong pin", status: http.StatusUnauthorized, body: `{"error":"Unauthorized","code":"PIN_INVALID"}`, wantStatus: http.StatusUnauthorized},
+		{name: "auth-service failure", status: http.StatusInternalServerError, body: `{"error":{"code":"internal_error","message":"Internal server error"}}`, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			authService := newAuthServiceStub(t, tt.status, tt.body, &gotUserID)
+			h := newPINTestHandlers(&pinRepoStub{userID: uuid.New()}, authService.URL)
+
+			rec := postP2PTransfer(h, `{"recipient_username":"bola","amount":1000,"transaction_pin":"1234"}`)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func mapPotTransferError(err error) (int, string) {
+	switch {
+	case errors.Is(err, app.ErrPotNotFound):
+		return http.StatusNotFound, "Pot not found."
+	case errors.Is(err, store.ErrAccountNotFound):
+		return http.StatusNotFound, "Account not found."
+	case errors.Is(err, store.ErrInsufficientFunds):
+		return http.StatusPaymentRequired, "Insufficient funds"
+	case errors.Is(err, app.ErrInvalidTransferAmount),
+		errors.Is(err, app.ErrInvalidPotTransferDirection),
+		errors.Is(err, app.ErrInvalidDescription):
+		return http.StatusBadRequest, err.Error()
+	}
+	return http.StatusInternalServerError, "Could not complete pot transfer."
+}
+
+// PotTransferHandler moves funds between the caller's primary account and one of their pots.
+func (h *TransactionHandlers) PotTransferHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	potID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid pot ID")
+		return
+	}
+
+	var payload domain.PotTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	tx, err := h.service.TransferPotFunds(r.Context(), userID, potID, payload)
+	if err != nil {
+		status, msg := mapPotTransferError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=pot_transfer outcome=failed user_id=%s pot_id=%s err=%v", userID, potID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	h.auditUser(r, userID, "transfer.pot", "transaction", tx.ID.String(), map[string]interface{}{
+		"pot_id":    potID.String(),
+		"direction": payload.Direction,
+		"amount":    tx.Amount,
+	})
+
+	h.writeJSON(w, http.StatusCreated, tx)
+}
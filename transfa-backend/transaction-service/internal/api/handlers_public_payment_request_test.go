@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// publicPaymentRequestRepoStub serves one payment request by ID and short code.
+type publicPaymentRequestRepoStub struct {
+	store.Repository
+
+	request domain.PaymentRequest
+}
+
+func (s *publicPaymentRequestRepoStub) GetPublicPaymentRequestByID(ctx context.Context, requestID uuid.UUID) (*domain.PaymentRequest, error) {
+	if requestID != s.request.ID {
+		return nil, store.ErrPaymentRequestNotFound
+	}
+	request := s.request
+	return &request, nil
+}
+
+func (s *publicPaymentRequestRepoStub) GetPublicPaymentRequestByShortCode(ctx context.Context, code string) (*domain.PaymentRequest, error) {
+	if s.request.ShortCode == nil || code != *s.request.ShortCode {
+		return nil, store.ErrPaymentRequestNotFound
+	}
+	request := s.request
+	return &request, nil
+}
+
+// exhaustedRateLimiter reports every subject as over its limit.
+type exhaustedRateLimiter struct{}
+
+func (exhaustedRateLimiter) ConsumeRateLimit(ctx context.Context, scope string, subject string, limit int, window time.Duration) (int, int, error) {
+	return limit + 1, 30, nil
+}
+
+func newPublicPaymentRequestTestRouter() (http.Handler, *app.Service, *publicPaymentRequestRepoStub) {
+	code := "Pq7rS2tU"
+	username := "ada"
+	description := "Dinner on Friday"
+	repo := &publicPaymentRequestRepoStub{request: domain.PaymentRequest{
+		ID:              uuid.New(),
+		CreatorUsername: &username,
+		Status:          "pending",
+		Title:           "Dinner",
+		Amount:          250000,
+		AmountPaid:      50000,
+		ShortCode:       &code,
+		Description:     &description,
+	}}
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	return TransactionRoutes(NewTransactionHandlers(service, "", nil), "", nil, false), service, repo
+}
+
+func TestPublicPaymentRequestHandlers_ServeWithoutAuth(t *testing.T) {
+	router, _, repo := newPublicPaymentRequestTestRouter()
+
+	for _, path := range []string{
+		"/payment-requests/" + repo.request.ID.String() + "/public",
+		"/payment-requests/by-code/Pq7rS2tU",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode response: %v", path, err)
+		}
+		if body["creator_username"] != "ada" || body["amount"] != float64(250000) || body["remaining_amount"] != float64(200000) || body["status"] != "pending" {
+			t.Fatalf("%s: unexpected public view %v", path, body)
+		}
+		for _, key := range []string{"creator_id", "recipient_user_id", "settled_transaction_id"} {
+			if _, ok := body[key]; ok {
+				t.Fatalf("%s: expected %s not to be exposed, got %v", path, key, body)
+			}
+		}
+	}
+}
+
+func TestPublicPaymentRequestHandlers_UnknownRequestReturnsNotFound(t *testing.T) {
+	router, _, _ := newPublicPaymentRequestTestRouter()
+
+	for _, path := range []string{
+		"/payment-requests/" + uuid.NewString() + "/public",
+		"/payment-requests/by-code/Zz9yX8wV",
+		"/payment-requests/by-code/no",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestPublicPaymentRequestHandlers_RateLimited(t *testing.T) {
+	router, service, _ := newPublicPaymentRequestTestRouter()
+	service.SetMoneyDropRateLimiter(exhaustedRateLimiter{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payment-requests/by-code/Pq7rS2tU", nil))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") != "30" {
+		t.Fatalf("expected Retry-After 30, got %q", rec.Header().Get("Retry-After"))
+	}
+}
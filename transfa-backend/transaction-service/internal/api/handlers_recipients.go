@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func mapFavoriteRecipientError(err error) (int, string) {
+	switch {
+	case errors.Is(err, app.ErrFavoriteRecipientNotFound):
+		return http.StatusNotFound, "Favorite not found."
+	case errors.Is(err, store.ErrUserNotFound):
+		return http.StatusNotFound, "User not found."
+	case errors.Is(err, app.ErrInvalidRecipient),
+		errors.Is(err, app.ErrFavoriteRecipientSelf),
+		errors.Is(err, app.ErrFavoriteRecipientLimit):
+		return http.StatusBadRequest, err.Error()
+	}
+	return http.StatusInternalServerError, "Could not process favorite recipient request."
+}
+
+func (h *TransactionHandlers) ListRecentRecipientsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	limit, err := parseOptionalPositiveInt(r.URL.Query().Get("limit"), 10)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	items, err := h.service.ListRecentRecipients(r.Context(), userID, limit)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_recent_recipients outcome=failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not retrieve recent recipients.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, items)
+}
+
+func (h *TransactionHandlers) ListFavoriteRecipientsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	items, err := h.service.ListFavoriteRecipients(r.Context(), userID)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_favorite_recipients outcome=failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not retrieve favorite recipients.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, items)
+}
+
+func (h *TransactionHandlers) AddFavoriteRecipientHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	var payload domain.AddFavoriteRecipientPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	favorite, err := h.service.AddFavoriteRecipient(r.Context(), userID, payload.Username)
+	if err != nil {
+		status, msg := mapFavoriteRecipientError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=add_favorite_recipient outcome=failed user_id=%s err=%v", userID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, favorite)
+}
+
+func (h *TransactionHandlers) RemoveFavoriteRecipientHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	recipientID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveFavoriteRecipient(r.Context(), userID, recipientID); err != nil {
+		status, msg := mapFavoriteRecipientError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=remove_favorite_recipient outcome=failed user_id=%s recipient_id=%s err=%v", userID, recipientID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	h.writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// runReconciliationRequest is the optional body of a reconciliation run. Date is a UTC
+// day (YYYY-MM-DD) and defaults to yesterday; a sample size of 0 checks every account.
+type runReconciliationRequest struct {
+	Date       string `json:"date,omitempty"`
+	SampleSize int    `json:"sample_size,omitempty"`
+}
+
+// RunReconciliationHandler starts a background comparison of a day's Anchor transfers
+// with our transactions. Called daily by the scheduler; responds 202 once the run has
+// started.
+func (h *TransactionHandlers) RunReconciliationHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	var req runReconciliationRequest
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.SampleSize < 0 {
+		h.writeError(w, http.StatusBadRequest, "sample_size must not be negative")
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := today.AddDate(0, 0, -1)
+	if date := strings.TrimSpace(req.Date); date != "" {
+		parsed, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+			return
+		}
+		if !parsed.Before(today) {
+			h.writeError(w, http.StatusBadRequest, "date must be before today")
+			return
+		}
+		day = parsed
+	}
+
+	if err := h.service.StartReconciliation(day, req.SampleSize); err != nil {
+		if errors.Is(err, app.ErrReconciliationInProgress) {
+			h.writeAppError(w, http.StatusConflict, err)
+			return
+		}
+		log.Printf("level=error component=api endpoint=run_reconciliation outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Failed to start reconciliation")
+		return
+	}
+
+	h.auditInternal(r, "reconciliation.run", "", "", map[string]interface{}{
+		"date":        day.Format(time.DateOnly),
+		"sample_size": req.SampleSize,
+	})
+	log.Printf("level=info component=api endpoint=run_reconciliation outcome=accepted date=%s sample_size=%d", day.Format(time.DateOnly), req.SampleSize)
+	h.writeJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
+
+// GetLatestReconciliationReportHandler returns the most recent reconciliation report.
+func (h *TransactionHandlers) GetLatestReconciliationReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	report, err := h.service.GetLatestReconciliationReport(r.Context())
+	if err != nil {
+		if errors.Is(err, store.ErrReconciliationReportNotFound) {
+			h.writeError(w, http.StatusNotFound, "No reconciliation report yet")
+			return
+		}
+		log.Printf("level=error component=api endpoint=latest_reconciliation_report outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Could not load reconciliation report")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
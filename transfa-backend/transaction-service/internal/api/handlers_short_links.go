@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// ShareMoneyDropHandler creates a short link for a creator-owned active money drop.
+func (h *TransactionHandlers) ShareMoneyDropHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	dropID, err := uuid.Parse(chi.URLParam(r, "drop_id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid money drop ID format")
+		return
+	}
+
+	link, err := h.service.GenerateShareableLink(r.Context(), dropID, userID)
+	if err != nil {
+		log.Printf("level=warn component=api endpoint=share_money_drop outcome=failed user_id=%s drop_id=%s err=%v", userID, dropID, err)
+		switch {
+		case errors.Is(err, store.ErrMoneyDropNotFound):
+			h.writeErrorCode(w, http.StatusNotFound, apierror.CodeMoneyDropNotFound, "Money drop not found")
+		case errors.Is(err, app.ErrMoneyDropShareNotAllowed):
+			h.writeAppError(w, http.StatusConflict, err)
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to create share link")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, link)
+}
+
+// ResolveShortLinkHandler redirects a short link code to its money drop claim URL.
+func (h *TransactionHandlers) ResolveShortLinkHandler(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+	targetURL, err := h.service.ResolveShortLink(r.Context(), code)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrShortLinkNotFound):
+			h.writeError(w, http.StatusNotFound, "Link not found")
+		case errors.Is(err, app.ErrShortLinkExpired):
+			h.writeError(w, http.StatusGone, "This link has expired")
+		default:
+			log.Printf("level=error component=api endpoint=resolve_short_link outcome=failed code=%s err=%v", code, err)
+			h.writeError(w, http.StatusInternalServerError, "Failed to resolve link")
+		}
+		return
+	}
+
+	http.Redirect(w, r, targetURL, http.StatusFound)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// shortLinkRepoStub keeps short links in memory keyed by code.
+type shortLinkRepoStub struct {
+	store.Repository
+
+	links map[string]*domain.ShortLink
+}
+
+func (s *shortLinkRepoStub) FindShortLinkByCode(ctx context.Context, code string) (*domain.ShortLink, error) {
+	link, ok := s.links[code]
+	if !ok {
+		return nil, store.ErrShortLinkNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *shortLinkRepoStub) IncrementShortLinkClickCount(ctx context.Context, code string) error {
+	s.links[code].ClickCount++
+	return nil
+}
+
+func newShortLinkTestRouter(links ...domain.ShortLink) (http.Handler, *shortLinkRepoStub) {
+	repo := &shortLinkRepoStub{links: map[string]*domain.ShortLink{}}
+	for i := range links {
+		repo.links[links[i].Code] = &links[i]
+	}
+	service := app.NewService(repo, nil, nil, nil, "", 0, 0, 0, "https://trytransfa.com", "")
+	return ShortLinkRoutes(NewTransactionHandlers(service, "", nil)), repo
+}
+
+func TestResolveShortLinkHandler_RedirectsToClaimURL(t *testing.T) {
+	target := "https://trytransfa.com/ada?drop_id=5f0c6a1e-0000-4000-8000-000000000001"
+	router, repo := newShortLinkTestRouter(domain.ShortLink{Code: "Ab3dE6gH", TargetURL: target, ExpiresAt: time.Now().Add(time.Hour)})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/Ab3dE6gH", nil))
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Location"); got != target {
+		t.Fatalf("expected redirect to %q, got %q", target, got)
+	}
+	if repo.links["Ab3dE6gH"].ClickCount != 1 {
+		t.Fatalf("expected click to be counted, got %d", repo.links["Ab3dE6gH"].ClickCount)
+	}
+}
+
+func TestResolveShortLinkHandler_ExpiredLinkReturnsGone(t *testing.T) {
+	router, repo := newShortLinkTestRouter(domain.ShortLink{Code: "Xy7zW2qR", TargetURL: "https://trytransfa.com/ada", ExpiresAt: time.Now().Add(-time.Minute)})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/Xy7zW2qR", nil))
+
+	if rec.Code != http.StatusGone {
+		t.Fatalf("expected 410, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if repo.links["Xy7zW2qR"].ClickCount != 0 {
+		t.Fatalf("expected expired link visits not to be counted, got %d", repo.links["Xy7zW2qR"].ClickCount)
+	}
+}
+
+func TestResolveShortLinkHandler_UnknownCodeReturnsNotFound(t *testing.T) {
+	router, _ := newShortLinkTestRouter()
+
+	for _, path := range []string{"/Nope1234", "/not-a-code"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/transfa/pkg/pdf"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func mapAccountStatementError(err error) (int, string) {
+	switch {
+	case errors.Is(err, app.ErrInvalidStatementPeriod),
+		errors.Is(err, app.ErrStatementPeriodTooLong),
+		errors.Is(err, app.ErrInvalidStatementPassword):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, store.ErrUserNotFound), errors.Is(err, store.ErrAccountNotFound):
+		return http.StatusNotFound, "Account not found"
+	}
+	return http.StatusInternalServerError, "Could not generate account statement."
+}
+
+// GenerateAccountStatementHandler returns the caller's wallet statement for a period
+// as a PDF download, optionally password protected.
+func (h *TransactionHandlers) GenerateAccountStatementHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	var payload domain.AccountStatementRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	statement, err := h.service.GenerateAccountStatement(r.Context(), userID, payload)
+	if err != nil {
+		status, msg := mapAccountStatementError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("level=error component=api endpoint=generate_account_statement outcome=failed user_id=%s err=%v", userID, err)
+		}
+		h.writeError(w, status, msg)
+		return
+	}
+
+	// Render fully before writing so a failure can still be reported as JSON.
+	var body bytes.Buffer
+	if err := statementDocument(statement).Render(&body, payload.Password); err != nil {
+		log.Printf("level=error component=api endpoint=generate_account_statement outcome=render_failed user_id=%s err=%v", userID, err)
+		h.writeError(w, http.StatusInternalServerError, "Could not generate account statement.")
+		return
+	}
+
+	filename := fmt.Sprintf("transfa-statement-%s-%s.pdf", statement.PeriodStart.Format("2006-01-02"), statement.PeriodEnd.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	w.Header().Set("Cache-Control", "no-store")
+	if statement.IncludesArchived {
+		w.Header().Set(archivedIncludedHeader, "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = body.WriteTo(w)
+}
+
+func statementDocument(statement *domain.AccountStatement) pdf.Statement {
+	lines := make([]pdf.StatementLine, len(statement.Entries))
+	for i, entry := range statement.Entries {
+		lines[i] = pdf.StatementLine{
+			Date:        entry.Date,
+			Description: entry.Description,
+			Debit:       entry.Debit,
+			Credit:      entry.Credit,
+			Balance:     entry.Balance,
+		}
+	}
+	return pdf.Statement{
+		AccountName:    statement.AccountName,
+		AccountNumber:  statement.AccountNumber,
+		PeriodStart:    statement.PeriodStart,
+		PeriodEnd:      statement.PeriodEnd,
+		GeneratedAt:    time.Now().In(statement.PeriodStart.Location()),
+		OpeningBalance: statement.OpeningBalance,
+		TotalCredits:   statement.TotalCredits,
+		TotalDebits:    statement.TotalDebits,
+		ClosingBalance: statement.ClosingBalance,
+		Lines:          lines,
+	}
+}
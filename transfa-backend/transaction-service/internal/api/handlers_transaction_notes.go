@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// UpdateTransactionNoteHandler sets or clears the caller's private note on a transaction.
+func (h *TransactionHandlers) UpdateTransactionNoteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid transaction ID format")
+		return
+	}
+
+	var payload domain.UpdateTransactionNotePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	note, err := h.service.UpdateTransactionNote(r.Context(), userID, transactionID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidTransactionNote):
+			h.writeAppError(w, http.StatusBadRequest, err)
+		case errors.Is(err, store.ErrTransactionNotFound):
+			h.writeAppError(w, http.StatusNotFound, err)
+		default:
+			log.Printf("level=error component=api endpoint=update_transaction_note outcome=failed user_id=%s transaction_id=%s err=%v", userID, transactionID, err)
+			h.writeError(w, http.StatusInternalServerError, "Could not save the note.")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, note)
+}
+
+// AddTransactionAttachmentHandler records an uploaded image against a transaction.
+func (h *TransactionHandlers) AddTransactionAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	userID, statusCode, message := h.resolveAuthenticatedInternalUserID(r)
+	if statusCode != 0 {
+		h.writeError(w, statusCode, message)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid transaction ID format")
+		return
+	}
+
+	var payload domain.AddTransactionAttachmentPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request payload.")
+		return
+	}
+
+	attachment, err := h.service.AddTransactionAttachment(r.Context(), userID, transactionID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidTransactionAttachmentURL):
+			h.writeAppError(w, http.StatusBadRequest, err)
+		case errors.Is(err, store.ErrTransactionNotFound):
+			h.writeAppError(w, http.StatusNotFound, err)
+		case errors.Is(err, store.ErrTransactionAttachmentLimit):
+			h.writeAppError(w, http.StatusConflict, err)
+		default:
+			log.Printf("level=error component=api endpoint=add_transaction_attachment outcome=failed user_id=%s transaction_id=%s err=%v", userID, transactionID, err)
+			h.writeError(w, http.StatusInternalServerError, "Could not save the attachment.")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, attachment)
+}
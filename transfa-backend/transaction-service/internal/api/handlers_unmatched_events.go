@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/app"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type unmatchedTransferEventsResponse struct {
+	Events    []domain.UnmatchedTransferEvent `json:"events"`
+	Unmatched int64                           `json:"unmatched_since_start"`
+}
+
+// ListUnmatchedTransferEventsHandler returns transfer status events parked because no
+// transaction matched them, with the number this instance has parked since start.
+func (h *TransactionHandlers) ListUnmatchedTransferEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	status := strings.TrimSpace(query.Get("status"))
+	if status != "" && status != domain.UnmatchedTransferEventPending && status != domain.UnmatchedTransferEventReplayed {
+		h.writeError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+	limit, err := parseOptionalPositiveInt(query.Get("limit"), 50)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	events, err := h.service.ListUnmatchedTransferEvents(r.Context(), status, limit)
+	if err != nil {
+		log.Printf("level=error component=api endpoint=list_unmatched_events outcome=failed err=%v", err)
+		h.writeError(w, http.StatusInternalServerError, "Could not load unmatched transfer events.")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, unmatchedTransferEventsResponse{Events: events, Unmatched: h.service.UnmatchedTransferEventCount()})
+}
+
+// ReplayUnmatchedTransferEventHandler re-runs matching for a parked transfer event and
+// applies it when a transaction now matches.
+func (h *TransactionHandlers) ReplayUnmatchedTransferEventHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeInternalRequest(w, r) {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid unmatched event ID")
+		return
+	}
+
+	event, err := h.service.ReplayUnmatchedTransferEvent(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrUnmatchedTransferEventNotFound):
+			h.writeError(w, http.StatusNotFound, "Unmatched event not found")
+		case errors.Is(err, app.ErrUnmatchedTransferEventAlreadyReplayed):
+			h.writeAppError(w, http.StatusConflict, err)
+		case errors.Is(err, app.ErrTransferCurrencyMismatch):
+			h.auditInternal(r, "transfer_event.replay", "unmatched_transfer_event", id.String(), map[string]interface{}{"outcome": "currency_mismatch"})
+			h.writeAppError(w, http.StatusConflict, err)
+		case errors.Is(err, app.ErrUnmatchedTransferEventStillUnmatched):
+			h.auditInternal(r, "transfer_event.replay", "unmatched_transfer_event", id.String(), map[string]interface{}{"outcome": "unmatched"})
+			h.writeAppError(w, http.StatusUnprocessableEntity, err)
+		default:
+			log.Printf("level=error component=api endpoint=replay_unmatched_event unmatched_event_id=%s outcome=failed err=%v", id, err)
+			h.writeError(w, http.StatusInternalServerError, "Failed to replay unmatched event")
+		}
+		return
+	}
+
+	metadata := map[string]interface{}{"outcome": "replayed", "anchor_transfer_id": event.AnchorTransferID}
+	if event.TransactionID != nil {
+		metadata["transaction_id"] = event.TransactionID.String()
+	}
+	h.auditInternal(r, "transfer_event.replay", "unmatched_transfer_event", id.String(), metadata)
+	h.writeJSON(w, http.StatusOK, event)
+}
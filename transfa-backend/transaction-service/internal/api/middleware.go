@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/transfa/pkg/apierror"
 )
 
 // UserIDContextKey is a custom type for the context key to avoid collisions.
@@ -37,14 +38,14 @@ func ClerkAuthMiddleware(jwksURL string) func(http.Handler) http.Handler {
 			// Get the Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, "Authorization header required")
 				return
 			}
 
 			// Extract the token from "Bearer <token>"
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 			if tokenString == authHeader {
-				http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid Authorization header format")
 				return
 			}
 
@@ -71,33 +72,33 @@ func ClerkAuthMiddleware(jwksURL string) func(http.Handler) http.Handler {
 			})
 
 			if err != nil {
-				http.Error(w, fmt.Sprintf("Invalid token: %v", err), http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, fmt.Sprintf("Invalid token: %v", err))
 				return
 			}
 
 			// Check if token is valid
 			if !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid token")
 				return
 			}
 
 			// Extract user ID from claims
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid token claims")
 				return
 			}
 
 			// Optional audience / issuer enforcement via env
 			if expectedAud := os.Getenv("CLERK_AUDIENCE"); expectedAud != "" {
 				if aud, ok := claims["aud"].(string); !ok || aud != expectedAud {
-					http.Error(w, "Invalid audience", http.StatusUnauthorized)
+					apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid audience")
 					return
 				}
 			}
 			if expectedIss := os.Getenv("CLERK_ISSUER"); expectedIss != "" {
 				if iss, ok := claims["iss"].(string); !ok || iss != expectedIss {
-					http.Error(w, "Invalid issuer", http.StatusUnauthorized)
+					apierror.WriteStatus(w, http.StatusUnauthorized, "Invalid issuer")
 					return
 				}
 			}
@@ -105,7 +106,7 @@ func ClerkAuthMiddleware(jwksURL string) func(http.Handler) http.Handler {
 			// Get the user ID from the 'sub' claim (standard JWT claim for subject)
 			userID, ok := claims["sub"].(string)
 			if !ok {
-				http.Error(w, "User ID not found in token", http.StatusUnauthorized)
+				apierror.WriteStatus(w, http.StatusUnauthorized, "User ID not found in token")
 				return
 			}
 
@@ -116,6 +117,31 @@ func ClerkAuthMiddleware(jwksURL string) func(http.Handler) http.Handler {
 	}
 }
 
+// ClosedAccountMiddleware rejects requests from users who have closed their account
+// with 410 Gone. It must run after ClerkAuthMiddleware.
+func ClosedAccountMiddleware(isClosed func(ctx context.Context, clerkUserID string) (bool, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetClerkUserID(r.Context())
+			if !ok || userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			closed, err := isClosed(r.Context(), userID)
+			if err != nil {
+				apierror.WriteStatus(w, http.StatusInternalServerError, "Failed to verify account status")
+				return
+			}
+			if closed {
+				apierror.WriteStatus(w, http.StatusGone, "Account closed")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // getPublicKeyFromJWKS fetches the public key from Clerk's JWKS endpoint
 func getPublicKeyFromJWKS(jwksURL, kid string) (interface{}, error) {
 	// This is a simplified implementation
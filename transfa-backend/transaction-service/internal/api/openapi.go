@@ -0,0 +1,446 @@
+/**
+ * @description
+ * This file holds the OpenAPI 3 description of the transaction-service HTTP API,
+ * served at GET /transactions/openapi.json. Operations are listed by hand in
+ * openAPIOperations; request and response schemas are derived from the Go types
+ * the handlers decode and encode, so field changes show up without editing the spec.
+ *
+ * @notes
+ * - TestOpenAPISpec_CoversEveryRoute fails when a route is mounted without an entry
+ *   here (or an entry outlives its route). Add new routes to openAPIOperations.
+ * - The Swagger UI at /transactions/docs is only mounted when OPENAPI_DOCS_ENABLED is
+ *   set outside production.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/apierror"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// openAPISecurity names who may call an operation.
+type openAPISecurity int
+
+const (
+	securityPublic   openAPISecurity = iota
+	securityUser                     // Clerk session JWT
+	securityInternal                 // X-Internal-API-Key
+)
+
+// openAPIOperation describes one mounted route. request and response hold a value of
+// the Go type decoded from and encoded to the body; nil means there is none.
+type openAPIOperation struct {
+	method      string
+	path        string
+	tag         string
+	summary     string
+	security    openAPISecurity
+	query       []string
+	request     any
+	status      int
+	response    any
+	contentType string
+}
+
+var openAPIOperations = []openAPIOperation{
+	{method: http.MethodGet, path: "/transactions/health", tag: "meta", summary: "Health check", status: http.StatusOK, contentType: "text/plain"},
+	{method: http.MethodGet, path: "/transactions/openapi.json", tag: "meta", summary: "This OpenAPI document", status: http.StatusOK, response: map[string]any{}},
+	{method: http.MethodGet, path: "/transactions/docs", tag: "meta", summary: "Swagger UI (non-production only)", status: http.StatusOK, contentType: "text/html"},
+
+	{method: http.MethodPost, path: "/transactions/p2p", tag: "transfers", summary: "Send money to another user", security: securityUser, request: domain.P2PTransferRequest{}, status: http.StatusCreated, response: transferInitiationResponse{}},
+	{method: http.MethodPost, path: "/transactions/p2p/bulk", tag: "transfers", summary: "Send money to several users", security: securityUser, request: domain.BulkP2PTransferRequest{}, status: http.StatusOK, response: bulkTransferInitiationResponse{}},
+	{method: http.MethodPost, path: "/transactions/self-transfer", tag: "transfers", summary: "Withdraw to one of the user's beneficiaries", security: securityUser, request: domain.SelfTransferRequest{}, status: http.StatusCreated, response: transferInitiationResponse{}},
+	{method: http.MethodPost, path: "/transactions/pots/{id}/transfer", tag: "transfers", summary: "Move funds between the wallet and a pot", security: securityUser, request: domain.PotTransferRequest{}, status: http.StatusCreated, response: domain.Transaction{}},
+
+	{method: http.MethodGet, path: "/transactions/beneficiaries", tag: "beneficiaries", summary: "List beneficiaries", security: securityUser, status: http.StatusOK, response: []domain.Beneficiary{}},
+	{method: http.MethodGet, path: "/transactions/beneficiaries/default", tag: "beneficiaries", summary: "Get the default beneficiary", security: securityUser, status: http.StatusOK, response: domain.Beneficiary{}},
+	{method: http.MethodPut, path: "/transactions/beneficiaries/default", tag: "beneficiaries", summary: "Set the default beneficiary", security: securityUser, request: setDefaultBeneficiaryRequest{}, status: http.StatusOK, response: map[string]string{}},
+	{method: http.MethodPost, path: "/transactions/beneficiaries/{id}/verify", tag: "beneficiaries", summary: "Re-verify a beneficiary's bank account", security: securityUser, status: http.StatusOK, response: domain.Beneficiary{}},
+	{method: http.MethodGet, path: "/transactions/receiving-preference", tag: "beneficiaries", summary: "Get the receiving preference", security: securityUser, status: http.StatusOK, response: domain.UserReceivingPreference{}},
+	{method: http.MethodPut, path: "/transactions/receiving-preference", tag: "beneficiaries", summary: "Update the receiving preference", security: securityUser, request: updateReceivingPreferenceRequest{}, status: http.StatusOK, response: map[string]string{}},
+
+	{method: http.MethodGet, path: "/transactions/account/balance", tag: "account", summary: "Get the wallet balance", security: securityUser, status: http.StatusOK, response: domain.AccountBalance{}},
+	{method: http.MethodGet, path: "/transactions/fees", tag: "account", summary: "Get the configured fees", security: securityUser, status: http.StatusOK, response: map[string]any{}},
+
+	{method: http.MethodGet, path: "/transactions/transactions", tag: "history", summary: "List transaction history", security: securityUser, query: []string{"from", "to", "status", "type"}, status: http.StatusOK, response: []domain.Transaction{}},
+	{method: http.MethodGet, path: "/transactions/transactions/with/{username}", tag: "history", summary: "List transactions with one counterparty", security: securityUser, query: []string{"limit", "offset"}, status: http.StatusOK, response: map[string]any{}},
+	{method: http.MethodPost, path: "/transactions/transactions/statements", tag: "history", summary: "Generate a PDF account statement", security: securityUser, request: domain.AccountStatementRequest{}, status: http.StatusOK, contentType: "application/pdf"},
+	{method: http.MethodGet, path: "/transactions/transactions/{id}", tag: "history", summary: "Get one transaction", security: securityUser, status: http.StatusOK, response: domain.Transaction{}},
+	{method: http.MethodGet, path: "/transactions/transactions/disputes", tag: "disputes", summary: "List the user's disputes", security: securityUser, query: []string{"limit", "offset"}, status: http.StatusOK, response: []domain.TransactionDispute{}},
+	{method: http.MethodPost, path: "/transactions/transactions/{id}/disputes", tag: "disputes", summary: "Dispute a transaction", security: securityUser, request: domain.CreateTransactionDisputePayload{}, status: http.StatusCreated, response: domain.TransactionDispute{}},
+	{method: http.MethodPost, path: "/transactions/transactions/{id}/dispute", tag: "disputes", summary: "Dispute a transaction (legacy path)", security: securityUser, request: domain.CreateTransactionDisputePayload{}, status: http.StatusCreated, response: domain.TransactionDispute{}},
+	{method: http.MethodPatch, path: "/transactions/transactions/{id}/note", tag: "history", summary: "Set or clear the user's private note on a transaction", security: securityUser, request: domain.UpdateTransactionNotePayload{}, status: http.StatusOK, response: domain.TransactionNote{}},
+	{method: http.MethodPost, path: "/transactions/transactions/{id}/attachments", tag: "history", summary: "Attach an uploaded image to a transaction", security: securityUser, request: domain.AddTransactionAttachmentPayload{}, status: http.StatusCreated, response: domain.TransactionAttachment{}},
+	{method: http.MethodPost, path: "/transactions/transactions/{id}/cancel", tag: "history", summary: "Cancel a pending transfer that has not been sent yet", security: securityUser, status: http.StatusOK, response: domain.Transaction{}},
+
+	{method: http.MethodPost, path: "/transactions/payment-requests", tag: "payment-requests", summary: "Create a payment request", security: securityUser, request: domain.CreatePaymentRequestPayload{}, status: http.StatusCreated, response: domain.PaymentRequest{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests", tag: "payment-requests", summary: "List the user's payment requests", security: securityUser, query: []string{"limit", "offset", "q"}, status: http.StatusOK, response: []domain.PaymentRequest{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests/incoming", tag: "payment-requests", summary: "List payment requests sent to the user", security: securityUser, query: []string{"limit", "offset", "status", "q"}, status: http.StatusOK, response: []domain.PaymentRequest{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests/incoming/{id}", tag: "payment-requests", summary: "Get an incoming payment request", security: securityUser, status: http.StatusOK, response: domain.PaymentRequest{}},
+	{method: http.MethodPost, path: "/transactions/payment-requests/incoming/{id}/pay", tag: "payment-requests", summary: "Pay an incoming payment request", security: securityUser, request: domain.PayIncomingPaymentRequestPayload{}, status: http.StatusOK, response: domain.PayIncomingPaymentRequestResult{}},
+	{method: http.MethodPost, path: "/transactions/payment-requests/incoming/{id}/decline", tag: "payment-requests", summary: "Decline an incoming payment request", security: securityUser, request: domain.DeclineIncomingPaymentRequestPayload{}, status: http.StatusOK, response: domain.PaymentRequest{}},
+	{method: http.MethodPost, path: "/transactions/payment-requests/split", tag: "payment-requests", summary: "Split a bill into one payment request per recipient", security: securityUser, request: domain.CreatePaymentRequestSplitPayload{}, status: http.StatusCreated, response: domain.PaymentRequestSplit{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests/splits/{id}", tag: "payment-requests", summary: "Get one of the user's payment request splits", security: securityUser, status: http.StatusOK, response: domain.PaymentRequestSplit{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests/{id}", tag: "payment-requests", summary: "Get one of the user's payment requests", security: securityUser, status: http.StatusOK, response: domain.PaymentRequest{}},
+	{method: http.MethodDelete, path: "/transactions/payment-requests/{id}", tag: "payment-requests", summary: "Delete one of the user's payment requests", security: securityUser, status: http.StatusNoContent},
+	{method: http.MethodGet, path: "/transactions/payment-requests/{id}/public", tag: "payment-requests", summary: "Get the public pay page view of a payment request", status: http.StatusOK, response: domain.PublicPaymentRequest{}},
+	{method: http.MethodGet, path: "/transactions/payment-requests/by-code/{code}", tag: "payment-requests", summary: "Get the public view of a payment request by short code", status: http.StatusOK, response: domain.PublicPaymentRequest{}},
+
+	{method: http.MethodGet, path: "/transactions/notifications", tag: "notifications", summary: "List in-app notifications", security: securityUser, query: []string{"limit", "offset", "cursor", "category", "status", "unread_only", "q"}, status: http.StatusOK, response: []domain.InAppNotification{}},
+	{method: http.MethodGet, path: "/transactions/notifications/unread-counts", tag: "notifications", summary: "Count unread notifications per category", security: securityUser, status: http.StatusOK, response: domain.NotificationUnreadCounts{}},
+	{method: http.MethodGet, path: "/transactions/notifications/unread-count", tag: "notifications", summary: "Count unread notifications", security: securityUser, status: http.StatusOK, response: map[string]int64{}},
+	{method: http.MethodPost, path: "/transactions/notifications/read", tag: "notifications", summary: "Mark several notifications read", security: securityUser, request: domain.MarkInAppNotificationsReadPayload{}, status: http.StatusOK, response: map[string]int64{}},
+	{method: http.MethodPost, path: "/transactions/notifications/read-all", tag: "notifications", summary: "Mark all notifications read", security: securityUser, request: markAllReadPayload{}, status: http.StatusOK, response: map[string]int64{}},
+	{method: http.MethodPost, path: "/transactions/notifications/{id}/read", tag: "notifications", summary: "Mark a notification read", security: securityUser, status: http.StatusOK, response: map[string]bool{}},
+
+	{method: http.MethodGet, path: "/transactions/transfer-lists", tag: "transfer-lists", summary: "List transfer lists", security: securityUser, query: []string{"limit", "offset", "q"}, status: http.StatusOK, response: []domain.TransferListSummary{}},
+	{method: http.MethodPost, path: "/transactions/transfer-lists", tag: "transfer-lists", summary: "Create a transfer list", security: securityUser, request: domain.CreateTransferListPayload{}, status: http.StatusCreated, response: domain.TransferList{}},
+	{method: http.MethodGet, path: "/transactions/transfer-lists/{id}", tag: "transfer-lists", summary: "Get a transfer list", security: securityUser, status: http.StatusOK, response: domain.TransferList{}},
+	{method: http.MethodPut, path: "/transactions/transfer-lists/{id}", tag: "transfer-lists", summary: "Update a transfer list", security: securityUser, request: domain.UpdateTransferListPayload{}, status: http.StatusOK, response: domain.TransferList{}},
+	{method: http.MethodDelete, path: "/transactions/transfer-lists/{id}", tag: "transfer-lists", summary: "Delete a transfer list", security: securityUser, status: http.StatusNoContent},
+	{method: http.MethodPost, path: "/transactions/transfer-lists/{id}/members/toggle", tag: "transfer-lists", summary: "Add or remove a transfer list member", security: securityUser, request: domain.ToggleTransferListMemberPayload{}, status: http.StatusOK, response: domain.ToggleTransferListMemberResult{}},
+
+	{method: http.MethodGet, path: "/transactions/recipients/recent", tag: "recipients", summary: "List recent recipients", security: securityUser, query: []string{"limit"}, status: http.StatusOK, response: []domain.RecentRecipient{}},
+	{method: http.MethodGet, path: "/transactions/recipients/favorites", tag: "recipients", summary: "List favorite recipients", security: securityUser, status: http.StatusOK, response: []domain.FavoriteRecipient{}},
+	{method: http.MethodPost, path: "/transactions/recipients/favorites", tag: "recipients", summary: "Pin a favorite recipient", security: securityUser, request: domain.AddFavoriteRecipientPayload{}, status: http.StatusCreated, response: domain.FavoriteRecipient{}},
+	{method: http.MethodDelete, path: "/transactions/recipients/favorites/{user_id}", tag: "recipients", summary: "Unpin a favorite recipient", security: securityUser, status: http.StatusNoContent},
+
+	{method: http.MethodPost, path: "/transactions/money-drops", tag: "money-drops", summary: "Create a money drop", security: securityUser, request: domain.CreateMoneyDropRequest{}, status: http.StatusCreated, response: domain.CreateMoneyDropResponse{}},
+	{method: http.MethodGet, path: "/transactions/money-drops/dashboard", tag: "money-drops", summary: "Owner dashboard", security: securityUser, status: http.StatusOK, response: domain.MoneyDropDashboardResponse{}},
+	{method: http.MethodGet, path: "/transactions/money-drops/claimed", tag: "money-drops", summary: "Money drops the user has claimed", security: securityUser, status: http.StatusOK, response: domain.ClaimedMoneyDropHistoryResponse{}},
+	{method: http.MethodPost, path: "/transactions/money-drops/{drop_id}/claim", tag: "money-drops", summary: "Claim a money drop; send an Idempotency-Key header to retry safely", security: securityUser, request: domain.ClaimMoneyDropRequest{}, status: http.StatusOK, response: domain.ClaimMoneyDropResponse{}},
+	{method: http.MethodPost, path: "/transactions/money-drops/{drop_id}/end", tag: "money-drops", summary: "End an active money drop", security: securityUser, status: http.StatusOK, response: domain.EndMoneyDropResponse{}},
+	{method: http.MethodGet, path: "/transactions/money-drops/{drop_id}/details", tag: "money-drops", summary: "Public details for the claim flow", security: securityUser, status: http.StatusOK, response: domain.MoneyDropDetails{}},
+	{method: http.MethodGet, path: "/transactions/money-drops/{drop_id}/owner-details", tag: "money-drops", summary: "Full details for the owner", security: securityUser, query: []string{"claimers_limit"}, status: http.StatusOK, response: domain.MoneyDropOwnerDetails{}},
+	{method: http.MethodPost, path: "/transactions/money-drops/{drop_id}/reveal-password", tag: "money-drops", summary: "Reveal a locked drop's password to its owner", security: securityUser, request: domain.RevealMoneyDropPasswordRequest{}, status: http.StatusOK, response: domain.RevealMoneyDropPasswordResponse{}},
+	{method: http.MethodGet, path: "/transactions/money-drops/{drop_id}/claimers", tag: "money-drops", summary: "List a drop's claimers", security: securityUser, query: []string{"search", "limit", "offset"}, status: http.StatusOK, response: domain.MoneyDropClaimersResponse{}},
+	{method: http.MethodPost, path: "/transactions/money-drops/{drop_id}/share", tag: "money-drops", summary: "Create a short share link", security: securityUser, status: http.StatusCreated, response: domain.ShortLink{}},
+
+	{method: http.MethodPost, path: "/transactions/platform-fee", tag: "internal", summary: "Debit a platform fee", security: securityInternal, request: platformFeeRequest{}, status: http.StatusCreated, response: domain.Transaction{}},
+	{method: http.MethodPost, path: "/transactions/internal/money-drops/refund", tag: "internal", summary: "Refund a money drop's balance to its creator", security: securityInternal, request: refundMoneyDropRequest{}, status: http.StatusOK, contentType: "text/plain"},
+	{method: http.MethodPost, path: "/transactions/internal/money-drops/expire", tag: "internal", summary: "Expire due money drops", security: securityInternal, status: http.StatusOK, response: domain.MoneyDropExpiryResponse{}},
+	{method: http.MethodGet, path: "/transactions/internal/money-drops/expired", tag: "internal", summary: "List expired active money drops", security: securityInternal, query: []string{"limit"}, status: http.StatusOK, response: []domain.ExpiredMoneyDrop{}},
+	{method: http.MethodPost, path: "/transactions/internal/money-drops/reconcile-claims", tag: "internal", summary: "Retry stuck money drop claim payouts", security: securityInternal, request: reconcileMoneyDropClaimsRequest{}, status: http.StatusOK, response: domain.MoneyDropClaimReconcileResponse{}},
+	{method: http.MethodPost, path: "/transactions/internal/accounts/sync-balances", tag: "internal", summary: "Start a wallet balance sync", security: securityInternal, status: http.StatusAccepted, response: map[string]string{}},
+	{method: http.MethodPost, path: "/transactions/internal/transactions/archive", tag: "internal", summary: "Start archiving old transactions", security: securityInternal, status: http.StatusAccepted, response: map[string]string{}},
+	{method: http.MethodPost, path: "/transactions/internal/notifications/cleanup", tag: "internal", summary: "Delete old read notifications", security: securityInternal, status: http.StatusOK, response: domain.NotificationCleanupResult{}},
+	{method: http.MethodPost, path: "/transactions/internal/reconciliation/run", tag: "internal", summary: "Start reconciling a day of Anchor transfers", security: securityInternal, request: runReconciliationRequest{}, status: http.StatusAccepted, response: map[string]string{}},
+	{method: http.MethodGet, path: "/transactions/internal/reconciliation/latest", tag: "internal", summary: "Latest reconciliation report", security: securityInternal, status: http.StatusOK, response: domain.ReconciliationReport{}},
+	{method: http.MethodGet, path: "/transactions/internal/audit-events", tag: "internal", summary: "List audit events", security: securityInternal, query: []string{"from", "to", "limit", "offset", "subject_type", "subject_id", "actor_id", "action"}, status: http.StatusOK, response: auditEventsResponse{}},
+	{method: http.MethodGet, path: "/transactions/internal/unmatched-events", tag: "internal", summary: "List parked transfer events", security: securityInternal, query: []string{"status", "limit"}, status: http.StatusOK, response: unmatchedTransferEventsResponse{}},
+	{method: http.MethodPost, path: "/transactions/internal/unmatched-events/{id}/replay", tag: "internal", summary: "Replay a parked transfer event", security: securityInternal, status: http.StatusOK, response: domain.UnmatchedTransferEvent{}},
+	{method: http.MethodGet, path: "/transactions/admin/disputes", tag: "internal", summary: "List all disputes", security: securityInternal, query: []string{"from", "to", "limit", "offset", "status"}, status: http.StatusOK, response: []domain.TransactionDispute{}},
+	{method: http.MethodPost, path: "/transactions/admin/disputes/{id}/status", tag: "internal", summary: "Move a dispute to a new status", security: securityInternal, request: domain.UpdateTransactionDisputeStatusPayload{}, status: http.StatusOK, response: domain.TransactionDispute{}},
+
+	{method: http.MethodGet, path: "/s/{code}", tag: "short-links", summary: "Redirect a short link to its money drop", status: http.StatusFound},
+}
+
+// openAPIDocument is the subset of the OpenAPI 3 document model the spec uses.
+type openAPIDocument struct {
+	OpenAPI    string                                    `json:"openapi"`
+	Info       openAPIInfo                               `json:"info"`
+	Paths      map[string]map[string]openAPIOperationDoc `json:"paths"`
+	Components openAPIComponents                         `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type openAPIOperationDoc struct {
+	Tags        []string                      `json:"tags,omitempty"`
+	Summary     string                        `json:"summary"`
+	OperationID string                        `json:"operationId"`
+	Security    []map[string][]string         `json:"security"`
+	Parameters  []openAPIParameter            `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                  `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponseDoc `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPIResponseDoc struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIJSON []byte
+)
+
+// OpenAPISpec returns the transaction-service OpenAPI document as JSON.
+func OpenAPISpec() []byte {
+	openAPIOnce.Do(func() {
+		openAPIJSON, _ = json.Marshal(buildOpenAPIDocument())
+	})
+	return openAPIJSON
+}
+
+// OpenAPIHandler serves the OpenAPI document.
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(OpenAPISpec())
+}
+
+// SwaggerUIHandler serves a Swagger UI page for the OpenAPI document next to it.
+func SwaggerUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Transfa transaction-service API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });</script>
+</body>
+</html>
+`
+
+var openAPIPathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func buildOpenAPIDocument() openAPIDocument {
+	schemas := openAPISchemaRegistry{schemas: map[string]*openAPISchema{}}
+	errorSchema := schemas.schemaFor(reflect.TypeOf(apierror.Envelope{}))
+
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Transfa transaction-service",
+			Version:     "1.0.0",
+			Description: "Amounts are in kobo. Errors use the Envelope schema; codes are listed in transfa-backend/pkg/apierror.",
+		},
+		Paths: map[string]map[string]openAPIOperationDoc{},
+		Components: openAPIComponents{
+			Schemas: schemas.schemas,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"clerkAuth":      {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"internalAPIKey": {Type: "apiKey", In: "header", Name: "X-Internal-API-Key"},
+			},
+		},
+	}
+
+	for _, op := range openAPIOperations {
+		operation := openAPIOperationDoc{
+			Tags:        []string{op.tag},
+			Summary:     op.summary,
+			OperationID: openAPIOperationID(op.method, op.path),
+			Security:    []map[string][]string{},
+			Responses: map[string]openAPIResponseDoc{
+				"default": {Description: "Error", Content: map[string]openAPIMediaType{"application/json": {Schema: errorSchema}}},
+			},
+		}
+		switch op.security {
+		case securityUser:
+			operation.Security = []map[string][]string{{"clerkAuth": {}}}
+		case securityInternal:
+			operation.Security = []map[string][]string{{"internalAPIKey": {}}}
+		}
+		for _, match := range openAPIPathParam.FindAllStringSubmatch(op.path, -1) {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{Name: match[1], In: "path", Required: true, Schema: &openAPISchema{Type: "string"}})
+		}
+		for _, name := range op.query {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{Name: name, In: "query", Schema: &openAPISchema{Type: "string"}})
+		}
+		if op.request != nil {
+			operation.RequestBody = &openAPIBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(op.request))}},
+			}
+		}
+
+		success := openAPIResponseDoc{Description: http.StatusText(op.status)}
+		switch {
+		case op.contentType != "":
+			success.Content = map[string]openAPIMediaType{op.contentType: {Schema: &openAPISchema{Type: "string"}}}
+		case op.response != nil:
+			success.Content = map[string]openAPIMediaType{"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(op.response))}}
+		}
+		operation.Responses[strconv.Itoa(op.status)] = success
+
+		if doc.Paths[op.path] == nil {
+			doc.Paths[op.path] = map[string]openAPIOperationDoc{}
+		}
+		doc.Paths[op.path][strings.ToLower(op.method)] = operation
+	}
+	return doc
+}
+
+// openAPIOperationID turns "POST /transactions/money-drops/{drop_id}/claim" into
+// "postMoneyDropsDropIdClaim".
+func openAPIOperationID(method, path string) string {
+	path = strings.TrimPrefix(path, "/transactions")
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '{' || r == '}' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// openAPISchemaRegistry turns Go types into schemas, registering named structs as
+// components so they are described once.
+type openAPISchemaRegistry struct {
+	schemas map[string]*openAPISchema
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (reg openAPISchemaRegistry) schemaFor(t reflect.Type) *openAPISchema {
+	switch t {
+	case timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &openAPISchema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &openAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := *reg.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			return &schema
+		}
+		schema.Nullable = true
+		return &schema
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: reg.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: reg.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return reg.structSchema(t)
+		}
+		name := openAPISchemaName(t)
+		if _, ok := reg.schemas[name]; !ok {
+			reg.schemas[name] = &openAPISchema{} // placeholder for recursive types
+			*reg.schemas[name] = *reg.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	return &openAPISchema{}
+}
+
+func (reg openAPISchemaRegistry) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, property := range reg.structSchema(embedded).Properties {
+					schema.Properties[key] = property
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = reg.schemaFor(field.Type)
+	}
+	return schema
+}
+
+// openAPISchemaName is the component name for a named struct: its type name with the
+// first letter upper-cased, so unexported response types read like the rest.
+func openAPISchemaName(t reflect.Type) string {
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// openAPIRoutes lists "METHOD path" for every documented operation, sorted.
+func openAPIRoutes() []string {
+	routes := make([]string, 0, len(openAPIOperations))
+	for _, op := range openAPIOperations {
+		routes = append(routes, op.method+" "+op.path)
+	}
+	sort.Strings(routes)
+	return routes
+}
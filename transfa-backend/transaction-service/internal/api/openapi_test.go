@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// mountedRoutes walks the routers the way cmd/main.go mounts them and returns
+// "METHOD path" for every route, sorted.
+func mountedRoutes(t *testing.T) []string {
+	t.Helper()
+	h := NewTransactionHandlers(nil, "", nil)
+	mounts := map[string]http.Handler{
+		"/transactions": TransactionRoutes(h, "", nil, true),
+		"/s":            ShortLinkRoutes(h),
+	}
+
+	var routes []string
+	for prefix, handler := range mounts {
+		router, ok := handler.(chi.Routes)
+		if !ok {
+			t.Fatalf("router mounted at %s is not a chi router", prefix)
+		}
+		err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			path := prefix + route
+			if len(path) > 1 {
+				path = strings.TrimSuffix(path, "/")
+			}
+			routes = append(routes, method+" "+path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walk %s: %v", prefix, err)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func TestOpenAPISpec_CoversEveryRoute(t *testing.T) {
+	documented := map[string]bool{}
+	for _, route := range openAPIRoutes() {
+		if documented[route] {
+			t.Errorf("%s is documented twice", route)
+		}
+		documented[route] = true
+	}
+
+	mounted := map[string]bool{}
+	for _, route := range mountedRoutes(t) {
+		mounted[route] = true
+		if !documented[route] {
+			t.Errorf("%s is mounted but missing from openAPIOperations", route)
+		}
+	}
+	for route := range documented {
+		if !mounted[route] {
+			t.Errorf("%s is documented but not mounted", route)
+		}
+	}
+}
+
+func TestOpenAPISpec_MarksInternalRoutes(t *testing.T) {
+	var doc openAPIDocument
+	if err := json.Unmarshal(OpenAPISpec(), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+
+	for path, operations := range doc.Paths {
+		internal := strings.HasPrefix(path, "/transactions/internal/") ||
+			strings.HasPrefix(path, "/transactions/admin/") ||
+			path == "/transactions/platform-fee"
+		for method, operation := range operations {
+			_, usesKey := firstSecurityScheme(operation)["internalAPIKey"]
+			if internal != usesKey {
+				t.Errorf("%s %s: internal=%t but internalAPIKey security=%t", method, path, internal, usesKey)
+			}
+		}
+	}
+}
+
+func firstSecurityScheme(operation openAPIOperationDoc) map[string][]string {
+	if len(operation.Security) == 0 {
+		return nil
+	}
+	return operation.Security[0]
+}
+
+func TestOpenAPISpec_ReferencesResolve(t *testing.T) {
+	spec := string(OpenAPISpec())
+	var doc openAPIDocument
+	if err := json.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Fatalf("expected OpenAPI 3.0.3, got %q", doc.OpenAPI)
+	}
+
+	const prefix = `"$ref":"#/components/schemas/`
+	for rest := spec; ; {
+		i := strings.Index(rest, prefix)
+		if i < 0 {
+			break
+		}
+		rest = rest[i+len(prefix):]
+		name := rest[:strings.IndexByte(rest, '"')]
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s is referenced but not defined", name)
+		}
+	}
+
+	claim := doc.Paths["/transactions/money-drops/{drop_id}/claim"]["post"]
+	if len(claim.Parameters) != 1 || claim.Parameters[0].Name != "drop_id" || claim.Parameters[0].In != "path" {
+		t.Fatalf("expected a drop_id path parameter, got %+v", claim.Parameters)
+	}
+	if _, ok := doc.Components.Schemas["P2PTransferRequest"].Properties["recipient_username"]; !ok {
+		t.Fatalf("expected P2PTransferRequest to describe recipient_username, got %+v", doc.Components.Schemas["P2PTransferRequest"])
+	}
+}
+
+func TestOpenAPIHandler_ServesJSONWithoutAuth(t *testing.T) {
+	h := NewTransactionHandlers(nil, "", nil)
+	router := TransactionRoutes(h, "", nil, false)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected the JSON spec, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the Swagger UI to be off by default, got %d", rec.Code)
+	}
+}
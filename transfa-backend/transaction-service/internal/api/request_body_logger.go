@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxLoggedBodyBytes caps how much of a request body is captured for debug logging.
+const maxLoggedBodyBytes = 4 << 10
+
+const redactedValue = "[REDACTED]"
+
+// redactedBodyFields are matched case-insensitively with underscores ignored, so
+// "transaction_pin" also covers "transactionPin".
+var redactedBodyFields = map[string]bool{
+	"transactionpin": true,
+	"lockpassword":   true,
+	"password":       true,
+	"bvn":            true,
+	"dateofbirth":    true,
+}
+
+// RequestBodyLogger logs request bodies at debug level with sensitive fields
+// redacted. Nothing is read when debug logging is disabled, and the body is
+// restored in full for the next handler.
+//
+// Only JSON bodies that fit in maxLoggedBodyBytes are logged; anything else cannot
+// be redacted reliably, so only its size is reported.
+func RequestBodyLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || !logger.Enabled(r.Context(), slog.LevelDebug) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			captured, err := io.ReadAll(io.LimitReader(r.Body, maxLoggedBodyBytes+1))
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			}
+			switch {
+			case len(captured) > maxLoggedBodyBytes:
+				attrs = append(attrs, slog.Bool("truncated", true), slog.String("body", "[omitted: larger than 4 KB]"))
+			default:
+				body, ok := redactJSONBody(captured)
+				if !ok {
+					body = "[omitted: not JSON]"
+				}
+				attrs = append(attrs, slog.Int("bytes", len(captured)), slog.String("body", body))
+			}
+			logger.LogAttrs(r.Context(), slog.LevelDebug, "request body", attrs...)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// redactJSONBody re-encodes body with sensitive field values replaced. It reports
+// false when body is not valid JSON.
+func redactJSONBody(body []byte) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", false
+	}
+
+	redacted, err := json.Marshal(redactJSONValue(value))
+	if err != nil {
+		return "", false
+	}
+	return string(redacted), true
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if redactedBodyFields[strings.ReplaceAll(strings.ToLower(key), "_", "")] {
+				typed[key] = redactedValue
+				continue
+			}
+			typed[key] = redactJSONValue(field)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactJSONValue(item)
+		}
+	}
+	return value
+}
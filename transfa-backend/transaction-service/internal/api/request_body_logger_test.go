@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveWithBodyLogger runs body through RequestBodyLogger and returns the log output
+// and the body the downstream handler received.
+func serveWithBodyLogger(t *testing.T, level slog.Level, body string) (string, string) {
+	t.Helper()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: level}))
+
+	var received string
+	handler := RequestBodyLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read restored body: %v", err)
+		}
+		received = string(data)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/p2p", strings.NewReader(body))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return logs.String(), received
+}
+
+func TestRequestBodyLogger_RedactsPINAndKeepsOtherFields(t *testing.T) {
+	body := `{"recipient_username":"bola","amount":150000,"description":"rent","transaction_pin":"1234"}`
+	logs, received := serveWithBodyLogger(t, slog.LevelDebug, body)
+
+	if strings.Contains(logs, "1234") {
+		t.Fatalf("expected PIN to be redacted, got log %q", logs)
+	}
+	if !strings.Contains(logs, `\"transaction_pin\":\"[REDACTED]\"`) {
+		t.Fatalf("expected redacted transaction_pin in log, got %q", logs)
+	}
+	for _, want := range []string{`\"recipient_username\":\"bola\"`, `\"amount\":150000`, `\"description\":\"rent\"`} {
+		if !strings.Contains(logs, want) {
+			t.Fatalf("expected %s to be preserved in log, got %q", want, logs)
+		}
+	}
+	if received != body {
+		t.Fatalf("expected handler to receive the original body, got %q", received)
+	}
+}
+
+func TestRequestBodyLogger_RedactsNestedAndCamelCaseFields(t *testing.T) {
+	body := `{"transfers":[{"amount":500,"transactionPin":"9999"}],"kyc":{"bvn":"22222222222","date_of_birth":"1990-01-01","city":"Lagos"},"lock_password":"secret"}`
+	logs, _ := serveWithBodyLogger(t, slog.LevelDebug, body)
+
+	for _, leaked := range []string{"9999", "22222222222", "1990-01-01", "secret"} {
+		if strings.Contains(logs, leaked) {
+			t.Fatalf("expected %q to be redacted, got log %q", leaked, logs)
+		}
+	}
+	if !strings.Contains(logs, `\"city\":\"Lagos\"`) {
+		t.Fatalf("expected non-sensitive nested field to be preserved, got %q", logs)
+	}
+}
+
+func TestRequestBodyLogger_OmitsBodiesItCannotRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not JSON", body: "transaction_pin=1234"},
+		{name: "larger than the capture limit", body: `{"transaction_pin":"1234","note":"` + strings.Repeat("x", maxLoggedBodyBytes) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, received := serveWithBodyLogger(t, slog.LevelDebug, tt.body)
+			if strings.Contains(logs, "1234") {
+				t.Fatalf("expected body to be omitted, got log %q", logs)
+			}
+			if !strings.Contains(logs, "omitted") {
+				t.Fatalf("expected an omitted-body log line, got %q", logs)
+			}
+			if received != tt.body {
+				t.Fatalf("expected handler to receive the full body (%d bytes), got %d bytes", len(tt.body), len(received))
+			}
+		})
+	}
+}
+
+func TestRequestBodyLogger_SkipsLoggingAboveDebug(t *testing.T) {
+	body := `{"amount":100}`
+	logs, received := serveWithBodyLogger(t, slog.LevelInfo, body)
+
+	if logs != "" {
+		t.Fatalf("expected no log output at info level, got %q", logs)
+	}
+	if received != body {
+		t.Fatalf("expected handler to receive the body, got %q", received)
+	}
+}
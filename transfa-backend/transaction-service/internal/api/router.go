@@ -12,21 +12,29 @@
 package api
 
 import (
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	sharedmiddleware "github.com/transfa/pkg/middleware"
 )
 
+// userRequestTimeout bounds the authenticated and public routes.
+const userRequestTimeout = 30 * time.Second
+
 // TransactionRoutes creates and returns a new router for the transaction service.
-func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
+// Request bodies are logged through bodyLogger when it has debug level enabled. The
+// Swagger UI is only mounted when docsEnabled is set.
+func TransactionRoutes(h *TransactionHandlers, jwksURL string, bodyLogger *slog.Logger, docsEnabled bool) http.Handler {
 	r := chi.NewRouter()
 
-	// Add standard middleware for logging, panic recovery, and timeouts.
+	// Add standard middleware for logging, panic recovery and compression.
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(sharedmiddleware.GzipMiddleware)
+	r.Use(RequestBodyLogger(bodyLogger))
 
 	// Health check endpoint (effective path when mounted: /transactions/health)
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -34,20 +42,32 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 		w.Write([]byte("healthy"))
 	})
 
-	// Group routes that require authentication.
+	// API description (see openapi.go). Keep openAPIOperations in step with the routes below.
+	r.Get("/openapi.json", OpenAPIHandler)
+	if docsEnabled {
+		r.Get("/docs", SwaggerUIHandler)
+	}
+
+	// Group routes that require authentication. User-facing routes are bounded by
+	// userRequestTimeout; the internal batch endpoints below are not, so a long
+	// expiry or archive run is never cut short.
 	r.Group(func(r chi.Router) {
+		r.Use(sharedmiddleware.RequestTimeout(userRequestTimeout))
 		// Apply JWT authentication middleware for production
 		r.Use(ClerkAuthMiddleware(jwksURL))
+		r.Use(ClosedAccountMiddleware(h.service.IsUserClosed))
 
 		// Define the protected API endpoints.
 		r.Post("/p2p", h.P2PTransferHandler)
 		r.Post("/p2p/bulk", h.BulkP2PTransferHandler)
 		r.Post("/self-transfer", h.SelfTransferHandler)
+		r.Post("/pots/{id}/transfer", h.PotTransferHandler)
 
 		// Beneficiary management endpoints
 		r.Get("/beneficiaries", h.ListBeneficiariesHandler)
 		r.Get("/beneficiaries/default", h.GetDefaultBeneficiaryHandler)
 		r.Put("/beneficiaries/default", h.SetDefaultBeneficiaryHandler)
+		r.Post("/beneficiaries/{id}/verify", h.VerifyBeneficiaryHandler)
 
 		// Receiving preference endpoints
 		r.Get("/receiving-preference", h.GetReceivingPreferenceHandler)
@@ -60,7 +80,14 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 		// Transaction history endpoint
 		r.Get("/transactions", h.GetTransactionHistoryHandler)
 		r.Get("/transactions/with/{username}", h.GetTransactionHistoryWithUserHandler)
+		r.Post("/transactions/statements", h.GenerateAccountStatementHandler)
 		r.Get("/transactions/{id}", h.GetTransactionByIDHandler)
+		r.Get("/transactions/disputes", h.ListMyTransactionDisputesHandler)
+		r.Post("/transactions/{id}/disputes", h.CreateTransactionDisputeHandler)
+		r.Post("/transactions/{id}/dispute", h.CreateTransactionDisputeHandler) // Legacy singular path
+		r.Patch("/transactions/{id}/note", h.UpdateTransactionNoteHandler)
+		r.Post("/transactions/{id}/attachments", h.AddTransactionAttachmentHandler)
+		r.Post("/transactions/{id}/cancel", h.CancelTransactionHandler)
 
 		// Payment Request routes
 		r.Route("/payment-requests", func(r chi.Router) {
@@ -73,6 +100,10 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 			r.Post("/incoming/{id}/pay", h.PayIncomingPaymentRequestHandler)
 			r.Post("/incoming/{id}/decline", h.DeclineIncomingPaymentRequestHandler)
 
+			// Split bills (one individual request per recipient)
+			r.Post("/split", h.CreatePaymentRequestSplitHandler)
+			r.Get("/splits/{id}", h.GetPaymentRequestSplitHandler)
+
 			r.Get("/{id}", h.GetPaymentRequestByIDHandler)   // Get a specific creator-owned payment request
 			r.Delete("/{id}", h.DeletePaymentRequestHandler) // Soft-delete a creator-owned payment request
 		})
@@ -81,6 +112,8 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 		r.Route("/notifications", func(r chi.Router) {
 			r.Get("/", h.ListInAppNotificationsHandler)
 			r.Get("/unread-counts", h.GetInAppNotificationUnreadCountsHandler)
+			r.Get("/unread-count", h.GetInAppNotificationUnreadCountHandler)
+			r.Post("/read", h.MarkInAppNotificationsReadHandler)
 			r.Post("/read-all", h.MarkAllInAppNotificationsReadHandler)
 			r.Post("/{id}/read", h.MarkInAppNotificationReadHandler)
 		})
@@ -95,6 +128,14 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 			r.Post("/{id}/members/toggle", h.ToggleTransferListMemberHandler)
 		})
 
+		// Recipient shortcut routes
+		r.Route("/recipients", func(r chi.Router) {
+			r.Get("/recent", h.ListRecentRecipientsHandler)
+			r.Get("/favorites", h.ListFavoriteRecipientsHandler)
+			r.Post("/favorites", h.AddFavoriteRecipientHandler)
+			r.Delete("/favorites/{user_id}", h.RemoveFavoriteRecipientHandler)
+		})
+
 		// Money Drop routes
 		r.Route("/money-drops", func(r chi.Router) {
 			r.Post("/", h.CreateMoneyDropHandler)                                // Create a new money drop
@@ -106,13 +147,43 @@ func TransactionRoutes(h *TransactionHandlers, jwksURL string) http.Handler {
 			r.Get("/{drop_id}/owner-details", h.GetMoneyDropOwnerDetailsHandler) // Full owner details
 			r.Post("/{drop_id}/reveal-password", h.RevealMoneyDropPasswordHandler)
 			r.Get("/{drop_id}/claimers", h.GetMoneyDropClaimersHandler) // Paginated claimers list
+			r.Post("/{drop_id}/share", h.ShareMoneyDropHandler)         // Create a short share link
 		})
 	})
 
+	// Public payment request pages for share links and QR codes. Unauthenticated,
+	// so they are rate limited per client address and never expose account details.
+	r.Group(func(r chi.Router) {
+		r.Use(sharedmiddleware.RequestTimeout(userRequestTimeout))
+		r.Get("/payment-requests/by-code/{code}", h.GetPublicPaymentRequestByCodeHandler)
+		r.Get("/payment-requests/{id}/public", h.GetPublicPaymentRequestHandler)
+	})
+
 	// Internal endpoints (authenticated via X-Internal-API-Key).
 	r.Post("/platform-fee", h.PlatformFeeHandler)
 	r.Post("/internal/money-drops/refund", h.RefundMoneyDropHandler)
+	r.Post("/internal/money-drops/expire", h.ExpireMoneyDropsHandler)
+	r.Get("/internal/money-drops/expired", h.ListExpiredMoneyDropsHandler)
 	r.Post("/internal/money-drops/reconcile-claims", h.ReconcileMoneyDropClaimsHandler)
+	r.Post("/internal/accounts/sync-balances", h.SyncAccountBalancesHandler)
+	r.Post("/internal/transactions/archive", h.ArchiveTransactionsHandler)
+	r.Post("/internal/notifications/cleanup", h.CleanupNotificationsHandler)
+	r.Post("/internal/reconciliation/run", h.RunReconciliationHandler)
+	r.Get("/internal/reconciliation/latest", h.GetLatestReconciliationReportHandler)
+	r.Get("/internal/audit-events", h.ListAuditEventsHandler)
+	r.Get("/internal/unmatched-events", h.ListUnmatchedTransferEventsHandler)
+	r.Post("/internal/unmatched-events/{id}/replay", h.ReplayUnmatchedTransferEventHandler)
+	r.Get("/admin/disputes", h.ListTransactionDisputesHandler)
+	r.Post("/admin/disputes/{id}/status", h.UpdateTransactionDisputeStatusHandler)
+
+	return r
+}
 
+// ShortLinkRoutes serves the public short link redirects (mounted at /s).
+func ShortLinkRoutes(h *TransactionHandlers) http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+	r.Get("/{code}", h.ResolveShortLinkHandler)
 	return r
 }
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const (
+	defaultArchiveAfterMonths = 12
+	archiveBatchSize          = 1000
+	archiveTimeout            = 2 * time.Hour
+)
+
+var ErrTransactionArchiveInProgress = errors.New("transaction archive already in progress")
+
+// StartTransactionArchive runs ArchiveOldTransactions in the background so the caller
+// is not held for the whole run. Only one archive run may be in progress at a time.
+func (s *Service) StartTransactionArchive() error {
+	if !s.archiveRunning.CompareAndSwap(false, true) {
+		return ErrTransactionArchiveInProgress
+	}
+
+	go func() {
+		defer s.archiveRunning.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+		defer cancel()
+
+		startedAt := time.Now()
+		result, err := s.ArchiveOldTransactions(ctx, startedAt)
+		if err != nil {
+			log.Printf("level=error component=service flow=transaction_archive msg=\"transaction archive aborted\" cutoff=%s archived=%d err=%v", result.Cutoff.Format(time.RFC3339), result.Archived, err)
+			return
+		}
+		log.Printf(
+			"level=info component=service flow=transaction_archive msg=\"transaction archive finished\" cutoff=%s archived=%d batches=%d duration_ms=%d",
+			result.Cutoff.Format(time.RFC3339),
+			result.Archived,
+			result.Batches,
+			time.Since(startedAt).Milliseconds(),
+		)
+	}()
+	return nil
+}
+
+// ArchiveOldTransactions moves completed and failed transactions created more than
+// archiveAfterMonths before now into the archive, archiveBatchSize rows per statement
+// so no single transaction holds locks on the whole backlog.
+func (s *Service) ArchiveOldTransactions(ctx context.Context, now time.Time) (*domain.TransactionArchiveResult, error) {
+	result := &domain.TransactionArchiveResult{Cutoff: now.UTC().AddDate(0, -s.archiveAfterMonths, 0)}
+	for {
+		moved, err := s.repo.ArchiveTransactionsBefore(ctx, result.Cutoff, archiveBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if moved > 0 {
+			result.Batches++
+			result.Archived += moved
+		}
+		if moved < archiveBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// historyNeedsArchive reports whether a history read starting at from can reach
+// archived transactions. A nil from means the full history.
+func (s *Service) historyNeedsArchive(ctx context.Context, from *time.Time) (bool, error) {
+	latest, err := s.repo.LatestArchivedTransactionAt(ctx)
+	if err != nil || latest == nil {
+		return false, err
+	}
+	return from == nil || !from.After(*latest), nil
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type archiveRepoStub struct {
+	store.Repository
+
+	// moves is what each successive ArchiveTransactionsBefore call reports.
+	moves   []int64
+	err     error
+	cutoffs []time.Time
+}
+
+func (s *archiveRepoStub) ArchiveTransactionsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	s.cutoffs = append(s.cutoffs, cutoff)
+	if s.err != nil {
+		return 0, s.err
+	}
+	if len(s.moves) == 0 {
+		return 0, nil
+	}
+	moved := s.moves[0]
+	s.moves = s.moves[1:]
+	return moved, nil
+}
+
+func TestArchiveOldTransactions_MovesBatchesUntilBacklogIsDrained(t *testing.T) {
+	repo := &archiveRepoStub{moves: []int64{archiveBatchSize, archiveBatchSize, 250}}
+	svc := &Service{repo: repo, archiveAfterMonths: 12}
+	now := time.Date(2026, time.April, 1, 3, 0, 0, 0, time.UTC)
+
+	result, err := svc.ArchiveOldTransactions(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := time.Date(2025, time.April, 1, 3, 0, 0, 0, time.UTC); !result.Cutoff.Equal(want) {
+		t.Fatalf("expected cutoff %s, got %s", want, result.Cutoff)
+	}
+	if result.Archived != 2*archiveBatchSize+250 || result.Batches != 3 {
+		t.Fatalf("expected %d rows in 3 batches, got %d in %d", 2*archiveBatchSize+250, result.Archived, result.Batches)
+	}
+	for _, cutoff := range repo.cutoffs {
+		if !cutoff.Equal(result.Cutoff) {
+			t.Fatalf("expected every batch to use cutoff %s, got %s", result.Cutoff, cutoff)
+		}
+	}
+}
+
+func TestArchiveOldTransactions_StopsOnError(t *testing.T) {
+	repo := &archiveRepoStub{err: errors.New("database unavailable")}
+	svc := &Service{repo: repo, archiveAfterMonths: 12}
+
+	if _, err := svc.ArchiveOldTransactions(context.Background(), time.Now()); err == nil {
+		t.Fatal("expected the repository error to be returned")
+	}
+	if len(repo.cutoffs) != 1 {
+		t.Fatalf("expected a single attempt, got %d", len(repo.cutoffs))
+	}
+}
+
+func TestGetTransactionHistory_IncludesArchiveOnlyWhenRangeReachesIt(t *testing.T) {
+	latest := time.Date(2025, time.March, 31, 23, 0, 0, 0, time.UTC)
+	before := latest.AddDate(0, -1, 0)
+	after := latest.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name           string
+		latestArchived *time.Time
+		from           *time.Time
+		want           bool
+	}{
+		{name: "nothing archived yet", from: nil, want: false},
+		{name: "full history", latestArchived: &latest, from: nil, want: true},
+		{name: "range starts inside the archive", latestArchived: &latest, from: &before, want: true},
+		{name: "range starts after the archive", latestArchived: &latest, from: &after, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &statementRepoStub{latestArchived: tt.latestArchived}
+			svc := &Service{repo: repo}
+
+			if _, err := svc.GetTransactionHistory(context.Background(), uuid.New(), domain.TransactionHistoryFilter{From: tt.from}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.filter.IncludeArchive != tt.want {
+				t.Fatalf("expected IncludeArchive %v, got %v", tt.want, repo.filter.IncludeArchive)
+			}
+		})
+	}
+}
+
+func TestGenerateAccountStatement_FlagsArchivedTransactions(t *testing.T) {
+	accountID := uuid.New()
+	userID := uuid.New()
+	latest := watTime(31, 0).AddDate(0, -1, 0)
+	repo := &statementRepoStub{
+		user:           &domain.User{ID: userID, Username: "ada"},
+		account:        &domain.Account{ID: accountID, UserID: userID, Balance: 1000},
+		latestArchived: &latest,
+		history: []domain.Transaction{
+			{ID: uuid.New(), SourceAccountID: uuid.New(), DestinationAccountID: &accountID, Status: "completed", Amount: 1000, CreatedAt: watTime(2, 9), Archived: true},
+		},
+	}
+	svc := &Service{repo: repo}
+
+	statement, err := svc.GenerateAccountStatement(context.Background(), userID, domain.AccountStatementRequest{From: "2026-02-01", To: "2026-03-10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.filter.IncludeArchive || repo.filter.From == nil {
+		t.Fatalf("expected the statement to read the archive from the period start, got %+v", repo.filter)
+	}
+	if !statement.IncludesArchived {
+		t.Fatal("expected the statement to be flagged as including archived transactions")
+	}
+	if statement.OpeningBalance != 0 || statement.ClosingBalance != 1000 {
+		t.Fatalf("expected archived credit to count toward balances, got opening %d closing %d", statement.OpeningBalance, statement.ClosingBalance)
+	}
+}
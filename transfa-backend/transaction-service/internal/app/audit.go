@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ListAuditEvents returns audit trail entries for the internal query endpoint,
+// newest first.
+func (s *Service) ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error) {
+	filter.SubjectType = strings.TrimSpace(filter.SubjectType)
+	filter.SubjectID = strings.TrimSpace(filter.SubjectID)
+	filter.ActorID = strings.TrimSpace(filter.ActorID)
+	filter.Action = strings.TrimSpace(filter.Action)
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditEventsLimit
+	}
+	if filter.Limit > maxAuditEventsLimit {
+		filter.Limit = maxAuditEventsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListAuditEvents(ctx, filter)
+}
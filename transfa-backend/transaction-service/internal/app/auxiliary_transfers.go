@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+const (
+	// transferCompensationInterval is how often queued compensations are paid out.
+	transferCompensationInterval = time.Minute
+	// transferCompensationBatchSize is how many compensations one pass pays out.
+	transferCompensationBatchSize = 50
+)
+
+// applyFeeCollectionEvent applies a status event for a recorded fee collection
+// transfer. It reports false when the transfer is not a fee collection, leaving the
+// event to the transaction lookup.
+func (c *TransferStatusConsumer) applyFeeCollectionEvent(ctx context.Context, event domain.TransferStatusEvent) (bool, error) {
+	fee, err := c.repo.FindFeeCollectionByAnchorTransferID(ctx, event.AnchorTransferID)
+	if err != nil {
+		if errors.Is(err, store.ErrFeeCollectionNotFound) {
+			return false, nil
+		}
+		return true, fmt.Errorf("lookup fee collection: %w", err)
+	}
+
+	switch normalizeStatus(event.Status) {
+	case "completed":
+		if err := c.repo.MarkFeeCollectionCompleted(ctx, fee.ID); err != nil {
+			return true, fmt.Errorf("mark fee collection completed: %w", err)
+		}
+		return true, nil
+	case "failed":
+		return true, c.handleFeeCollectionFailure(ctx, fee, event)
+	default:
+		return true, nil
+	}
+}
+
+// handleFeeCollectionFailure marks the fee collection failed, flags its parent
+// transaction and queues a credit of the fee: the wallet was debited for a fee that
+// never left the user's account.
+func (c *TransferStatusConsumer) handleFeeCollectionFailure(ctx context.Context, fee *domain.FeeCollection, event domain.TransferStatusEvent) error {
+	marked := false
+	if err := c.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		var err error
+		marked, err = txRepo.MarkFeeCollectionFailed(ctx, fee.ID, event.Reason)
+		if err != nil {
+			return fmt.Errorf("mark fee collection failed: %w", err)
+		}
+		if !marked {
+			return nil
+		}
+		if err := txRepo.FlagTransactionFeeCollectionFailed(ctx, fee.ParentTransactionID); err != nil {
+			return fmt.Errorf("flag parent transaction: %w", err)
+		}
+		return txRepo.EnqueueTransferCompensation(ctx, domain.TransferCompensation{
+			TransactionID: fee.ParentTransactionID,
+			UserID:        fee.UserID,
+			Kind:          domain.CompensationFeeCollection,
+			Amount:        fee.Amount,
+		})
+	}); err != nil {
+		return err
+	}
+
+	if marked {
+		log.Printf("level=warn component=transfer_consumer msg=\"fee collection failed; compensation queued\" transaction_id=%s anchor_transfer_id=%s amount=%d reason=%q", fee.ParentTransactionID, event.AnchorTransferID, fee.Amount, event.Reason)
+	}
+	return nil
+}
+
+// applyMoneyDropFundingEvent applies a status event for a money drop's funding
+// transfer. The funding transaction is written as completed when the wallet is
+// debited, so only a failure changes anything: the transaction is flagged and the
+// funded amount queued for credit back to the creator's wallet.
+func (c *TransferStatusConsumer) applyMoneyDropFundingEvent(ctx context.Context, tx *domain.Transaction, event domain.TransferStatusEvent) error {
+	if normalizeStatus(event.Status) != "failed" {
+		return nil
+	}
+
+	marked := false
+	if err := c.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		var err error
+		marked, err = txRepo.MarkMoneyDropFundingFailed(ctx, tx.ID, event.Reason)
+		if err != nil {
+			return fmt.Errorf("mark money drop funding failed: %w", err)
+		}
+		if !marked {
+			return nil
+		}
+		return txRepo.EnqueueTransferCompensation(ctx, domain.TransferCompensation{
+			TransactionID: tx.ID,
+			UserID:        tx.SenderID,
+			Kind:          domain.CompensationMoneyDropFunding,
+			Amount:        tx.Amount,
+		})
+	}); err != nil {
+		return err
+	}
+
+	if marked {
+		log.Printf("level=error component=transfer_consumer msg=\"money drop funding failed; compensation queued\" transaction_id=%s anchor_transfer_id=%s amount=%d reason=%q", tx.ID, event.AnchorTransferID, tx.Amount, event.Reason)
+	}
+	return nil
+}
+
+// RunTransferCompensations credits queued compensations to users' wallets until ctx
+// is cancelled.
+func (s *Service) RunTransferCompensations(ctx context.Context) {
+	ticker := time.NewTicker(transferCompensationInterval)
+	defer ticker.Stop()
+	for {
+		s.processTransferCompensations(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processTransferCompensations credits one batch of queued compensations. Each credit
+// commits together with completing its compensation, so none is paid twice.
+func (s *Service) processTransferCompensations(ctx context.Context) {
+	items, err := s.repo.ListPendingTransferCompensations(ctx, transferCompensationBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("level=warn component=service flow=transfer_compensation msg=\"failed to list pending compensations\" err=%v", err)
+		}
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.repo.WithTx(ctx, func(txRepo store.Repository) error {
+			completed, err := txRepo.CompleteTransferCompensation(ctx, item.ID)
+			if err != nil || !completed {
+				return err
+			}
+			return txRepo.CreditWallet(ctx, item.UserID, item.Amount)
+		}); err != nil {
+			log.Printf("level=error component=service flow=transfer_compensation msg=\"compensation credit failed\" compensation_id=%s transaction_id=%s kind=%s err=%v", item.ID, item.TransactionID, item.Kind, err)
+			continue
+		}
+		log.Printf("level=info component=service flow=transfer_compensation msg=\"compensation credited\" compensation_id=%s transaction_id=%s kind=%s user_id=%s amount=%d", item.ID, item.TransactionID, item.Kind, item.UserID, item.Amount)
+	}
+}
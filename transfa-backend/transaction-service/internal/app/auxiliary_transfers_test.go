@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type auxiliaryTransferRepoStub struct {
+	store.Repository
+
+	transactions   map[string]*domain.Transaction
+	feeCollections map[string]*domain.FeeCollection
+
+	feeCompleted       []uuid.UUID
+	feeFailed          map[uuid.UUID]bool
+	flaggedFeeParents  []uuid.UUID
+	fundingFailed      map[uuid.UUID]bool
+	compensations      []domain.TransferCompensation
+	pending            []domain.TransferCompensation
+	completedPending   map[uuid.UUID]bool
+	credits            map[uuid.UUID]int64
+	metadataUpdateSeen bool
+}
+
+func newAuxiliaryTransferRepoStub() *auxiliaryTransferRepoStub {
+	return &auxiliaryTransferRepoStub{
+		transactions:     map[string]*domain.Transaction{},
+		feeCollections:   map[string]*domain.FeeCollection{},
+		feeFailed:        map[uuid.UUID]bool{},
+		fundingFailed:    map[uuid.UUID]bool{},
+		completedPending: map[uuid.UUID]bool{},
+		credits:          map[uuid.UUID]int64{},
+	}
+}
+
+func (s *auxiliaryTransferRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
+func (s *auxiliaryTransferRepoStub) FindTransactionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.Transaction, error) {
+	if tx, ok := s.transactions[anchorTransferID]; ok {
+		return tx, nil
+	}
+	return nil, store.ErrTransactionNotFound
+}
+
+func (s *auxiliaryTransferRepoStub) FindPendingMoneyDropClaimByAnchorParticipantsAndAmount(ctx context.Context, sourceAnchorAccountID string, destinationAnchorAccountID string, amount int64) (*domain.Transaction, error) {
+	return nil, store.ErrTransactionNotFound
+}
+
+func (s *auxiliaryTransferRepoStub) UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata store.UpdateTransactionMetadataParams) error {
+	s.metadataUpdateSeen = true
+	return nil
+}
+
+func (s *auxiliaryTransferRepoStub) FindFeeCollectionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.FeeCollection, error) {
+	if fee, ok := s.feeCollections[anchorTransferID]; ok {
+		return fee, nil
+	}
+	return nil, store.ErrFeeCollectionNotFound
+}
+
+func (s *auxiliaryTransferRepoStub) MarkFeeCollectionCompleted(ctx context.Context, id uuid.UUID) error {
+	s.feeCompleted = append(s.feeCompleted, id)
+	return nil
+}
+
+func (s *auxiliaryTransferRepoStub) MarkFeeCollectionFailed(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	if s.feeFailed[id] {
+		return false, nil
+	}
+	s.feeFailed[id] = true
+	return true, nil
+}
+
+func (s *auxiliaryTransferRepoStub) FlagTransactionFeeCollectionFailed(ctx context.Context, transactionID uuid.UUID) error {
+	s.flaggedFeeParents = append(s.flaggedFeeParents, transactionID)
+	return nil
+}
+
+func (s *auxiliaryTransferRepoStub) MarkMoneyDropFundingFailed(ctx context.Context, transactionID uuid.UUID, reason string) (bool, error) {
+	if s.fundingFailed[transactionID] {
+		return false, nil
+	}
+	s.fundingFailed[transactionID] = true
+	return true, nil
+}
+
+func (s *auxiliaryTransferRepoStub) EnqueueTransferCompensation(ctx context.Context, compensation domain.TransferCompensation) error {
+	s.compensations = append(s.compensations, compensation)
+	return nil
+}
+
+func (s *auxiliaryTransferRepoStub) ListPendingTransferCompensations(ctx context.Context, limit int) ([]domain.TransferCompensation, error) {
+	return s.pending, nil
+}
+
+func (s *auxiliaryTransferRepoStub) CompleteTransferCompensation(ctx context.Context, id uuid.UUID) (bool, error) {
+	if s.completedPending[id] {
+		return false, nil
+	}
+	s.completedPending[id] = true
+	return true, nil
+}
+
+func (s *auxiliaryTransferRepoStub) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	s.credits[userID] += amount
+	return nil
+}
+
+func bookTransferEventBody(t *testing.T, anchorTransferID, status string) []byte {
+	t.Helper()
+	body, err := json.Marshal(domain.TransferStatusEvent{
+		EventType:        "transfer.status.book." + status,
+		AnchorTransferID: anchorTransferID,
+		Status:           status,
+		TransferType:     "BOOK_TRANSFER",
+		Reason:           "Insufficient balance",
+	})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return body
+}
+
+func TestHandleMessage_FailedFeeCollectionFlagsParentAndQueuesCompensationOnce(t *testing.T) {
+	repo := newAuxiliaryTransferRepoStub()
+	fee := &domain.FeeCollection{
+		ID:                  uuid.New(),
+		ParentTransactionID: uuid.New(),
+		UserID:              uuid.New(),
+		Amount:              2500,
+		AnchorTransferID:    "fee-transfer-1",
+		Status:              domain.FeeCollectionPending,
+	}
+	repo.feeCollections[fee.AnchorTransferID] = fee
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	body := bookTransferEventBody(t, fee.AnchorTransferID, "failed")
+	for i := 0; i < 2; i++ {
+		if !consumer.HandleMessage(body) {
+			t.Fatalf("expected delivery %d to be acknowledged", i+1)
+		}
+	}
+
+	if !repo.feeFailed[fee.ID] {
+		t.Fatalf("expected the fee collection to be marked failed")
+	}
+	if len(repo.flaggedFeeParents) != 1 || repo.flaggedFeeParents[0] != fee.ParentTransactionID {
+		t.Fatalf("expected the parent transaction flagged once, got %v", repo.flaggedFeeParents)
+	}
+	if len(repo.compensations) != 1 {
+		t.Fatalf("expected one compensation for a redelivered event, got %d", len(repo.compensations))
+	}
+	got := repo.compensations[0]
+	if got.Kind != domain.CompensationFeeCollection || got.TransactionID != fee.ParentTransactionID || got.UserID != fee.UserID || got.Amount != fee.Amount {
+		t.Fatalf("unexpected compensation %+v", got)
+	}
+	if repo.metadataUpdateSeen {
+		t.Fatalf("expected the fee event not to touch any transaction's metadata")
+	}
+}
+
+func TestHandleMessage_CompletedFeeCollectionIsMarkedCompleted(t *testing.T) {
+	repo := newAuxiliaryTransferRepoStub()
+	fee := &domain.FeeCollection{ID: uuid.New(), ParentTransactionID: uuid.New(), UserID: uuid.New(), Amount: 1000, AnchorTransferID: "fee-transfer-2"}
+	repo.feeCollections[fee.AnchorTransferID] = fee
+
+	if !NewTransferStatusConsumer(repo, nil).HandleMessage(bookTransferEventBody(t, fee.AnchorTransferID, "completed")) {
+		t.Fatalf("expected the event to be acknowledged")
+	}
+	if len(repo.feeCompleted) != 1 || repo.feeCompleted[0] != fee.ID {
+		t.Fatalf("expected the fee collection marked completed, got %v", repo.feeCompleted)
+	}
+	if len(repo.compensations) != 0 {
+		t.Fatalf("expected no compensation for a completed fee, got %d", len(repo.compensations))
+	}
+}
+
+func TestHandleMessage_MoneyDropFundingFailureQueuesCompensation(t *testing.T) {
+	repo := newAuxiliaryTransferRepoStub()
+	transferID := "funding-transfer-1"
+	funding := &domain.Transaction{
+		ID:               uuid.New(),
+		SenderID:         uuid.New(),
+		Type:             "money_drop_funding",
+		Status:           "completed",
+		Amount:           50000,
+		Currency:         "NGN",
+		AnchorTransferID: &transferID,
+	}
+	repo.transactions[transferID] = funding
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	if !consumer.HandleMessage(bookTransferEventBody(t, transferID, "completed")) {
+		t.Fatalf("expected the completed event to be acknowledged")
+	}
+	if len(repo.compensations) != 0 || repo.fundingFailed[funding.ID] {
+		t.Fatalf("expected a completed funding transfer to change nothing")
+	}
+
+	for i := 0; i < 2; i++ {
+		if !consumer.HandleMessage(bookTransferEventBody(t, transferID, "failed")) {
+			t.Fatalf("expected failed delivery %d to be acknowledged", i+1)
+		}
+	}
+	if !repo.fundingFailed[funding.ID] {
+		t.Fatalf("expected the funding transaction to be flagged")
+	}
+	if len(repo.compensations) != 1 {
+		t.Fatalf("expected one compensation, got %d", len(repo.compensations))
+	}
+	got := repo.compensations[0]
+	if got.Kind != domain.CompensationMoneyDropFunding || got.TransactionID != funding.ID || got.UserID != funding.SenderID || got.Amount != funding.Amount {
+		t.Fatalf("unexpected compensation %+v", got)
+	}
+}
+
+func TestProcessTransferCompensations_CreditsEachCompensationOnce(t *testing.T) {
+	repo := newAuxiliaryTransferRepoStub()
+	userID := uuid.New()
+	item := domain.TransferCompensation{ID: uuid.New(), TransactionID: uuid.New(), UserID: userID, Kind: domain.CompensationFeeCollection, Amount: 2500}
+	repo.pending = []domain.TransferCompensation{item}
+	service := &Service{repo: repo}
+
+	service.processTransferCompensations(context.Background())
+	service.processTransferCompensations(context.Background())
+
+	if repo.credits[userID] != 2500 {
+		t.Fatalf("expected the wallet credited 2500 once, got %d", repo.credits[userID])
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	balanceSyncBatchSize   = 50
+	balanceSyncConcurrency = 10
+	balanceSyncTimeout     = 30 * time.Minute
+)
+
+var ErrBalanceSyncInProgress = errors.New("balance sync already in progress")
+
+// StartAccountBalanceSync runs SyncAllAccountBalances in the background so the caller
+// is not held for the whole run. Only one sync may run at a time.
+func (s *Service) StartAccountBalanceSync() error {
+	if !s.balanceSyncRunning.CompareAndSwap(false, true) {
+		return ErrBalanceSyncInProgress
+	}
+
+	go func() {
+		defer s.balanceSyncRunning.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), balanceSyncTimeout)
+		defer cancel()
+
+		startedAt := time.Now()
+		result, err := s.SyncAllAccountBalances(ctx)
+		if err != nil {
+			log.Printf("level=error component=service flow=balance_sync msg=\"balance sync aborted\" scanned=%d err=%v", result.Scanned, err)
+			return
+		}
+		log.Printf(
+			"level=info component=service flow=balance_sync msg=\"balance sync finished\" scanned=%d updated=%d unchanged=%d skipped=%d failed=%d duration_ms=%d",
+			result.Scanned,
+			result.Updated,
+			result.Unchanged,
+			result.Skipped,
+			result.Failed,
+			time.Since(startedAt).Milliseconds(),
+		)
+	}()
+	return nil
+}
+
+// SyncAllAccountBalances refreshes every open account's balance from Anchor. Accounts are
+// read in batches of balanceSyncBatchSize and each batch is fetched with at most
+// balanceSyncConcurrency Anchor calls in flight. A failure on one account is counted and
+// logged without aborting the rest of the run.
+func (s *Service) SyncAllAccountBalances(ctx context.Context) (*domain.BalanceSyncResult, error) {
+	result := &domain.BalanceSyncResult{}
+	var mu sync.Mutex
+
+	afterID := uuid.Nil
+	for {
+		accounts, err := s.repo.ListAccountsForBalanceSync(ctx, afterID, balanceSyncBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(accounts) == 0 {
+			return result, nil
+		}
+
+		var g errgroup.Group
+		sem := make(chan struct{}, balanceSyncConcurrency)
+		for _, account := range accounts {
+			sem <- struct{}{}
+			g.Go(func() error {
+				defer func() { <-sem }()
+
+				outcome := s.syncAccountBalanceFromAnchor(ctx, account)
+				mu.Lock()
+				defer mu.Unlock()
+				switch outcome {
+				case balanceSyncUpdated:
+					result.Updated++
+				case balanceSyncUnchanged:
+					result.Unchanged++
+				case balanceSyncSkipped:
+					result.Skipped++
+				default:
+					result.Failed++
+				}
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		result.Scanned += len(accounts)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if len(accounts) < balanceSyncBatchSize {
+			return result, nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+type balanceSyncOutcome int
+
+const (
+	balanceSyncFailed balanceSyncOutcome = iota
+	balanceSyncUpdated
+	balanceSyncUnchanged
+	balanceSyncSkipped
+)
+
+func (s *Service) syncAccountBalanceFromAnchor(ctx context.Context, account domain.Account) balanceSyncOutcome {
+	anchorBalance, err := s.anchorClient.GetAccountBalance(ctx, account.AnchorAccountID)
+	if err != nil {
+		log.Printf("level=warn component=service flow=balance_sync msg=\"anchor balance fetch failed\" account_id=%s err=%v", account.ID, err)
+		return balanceSyncFailed
+	}
+
+	newBalance := anchorBalance.Data.AvailableBalance
+	if newBalance == account.Balance {
+		return balanceSyncUnchanged
+	}
+
+	updated, err := s.repo.SyncAccountBalance(ctx, account, newBalance)
+	if err != nil {
+		log.Printf("level=warn component=service flow=balance_sync msg=\"balance update failed\" account_id=%s err=%v", account.ID, err)
+		return balanceSyncFailed
+	}
+	if !updated {
+		return balanceSyncSkipped
+	}
+	return balanceSyncUpdated
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type balanceSyncRepoStub struct {
+	store.Repository
+
+	accounts []domain.Account // sorted by id
+
+	mu         sync.Mutex
+	pageAfter  []uuid.UUID
+	pageLimits []int
+	updates    map[uuid.UUID]int64
+	staleIDs   map[uuid.UUID]bool
+}
+
+func newBalanceSyncRepoStub(count int) *balanceSyncRepoStub {
+	accounts := make([]domain.Account, count)
+	for i := range accounts {
+		accounts[i] = domain.Account{ID: uuid.New(), UserID: uuid.New(), AnchorAccountID: fmt.Sprintf("anc_%d", i), Balance: 100}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].ID[:], accounts[j].ID[:]) < 0
+	})
+	return &balanceSyncRepoStub{accounts: accounts, updates: map[uuid.UUID]int64{}, staleIDs: map[uuid.UUID]bool{}}
+}
+
+func (s *balanceSyncRepoStub) ListAccountsForBalanceSync(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Account, error) {
+	s.mu.Lock()
+	s.pageAfter = append(s.pageAfter, afterID)
+	s.pageLimits = append(s.pageLimits, limit)
+	s.mu.Unlock()
+
+	page := make([]domain.Account, 0, limit)
+	for _, account := range s.accounts {
+		if bytes.Compare(account.ID[:], afterID[:]) <= 0 {
+			continue
+		}
+		page = append(page, account)
+		if len(page) == limit {
+			break
+		}
+	}
+	return page, nil
+}
+
+func (s *balanceSyncRepoStub) SyncAccountBalance(ctx context.Context, snapshot domain.Account, balance int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.staleIDs[snapshot.ID] {
+		return false, nil
+	}
+	s.updates[snapshot.ID] = balance
+	return true, nil
+}
+
+// newBalanceSyncTestService serves Anchor balances of 500 kobo, failing for any account ID in
+// failing, and records the peak number of concurrent balance requests.
+func newBalanceSyncTestService(t *testing.T, repo *balanceSyncRepoStub, failing map[string]bool) (*Service, *int32) {
+	t.Helper()
+
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prev := atomic.LoadInt32(&peak)
+			if current <= prev || atomic.CompareAndSwapInt32(&peak, prev, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		accountID := strings.TrimPrefix(r.URL.Path, "/api/v1/accounts/balance/")
+		w.Header().Set("Content-Type", "application/json")
+		if failing[accountID] {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"errors":[{"title":"Server Error","detail":"balance unavailable","status":"500"}]}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"availableBalance":500,"ledgerBalance":500,"hold":0,"pending":0}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Service{repo: repo, anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0)}, &peak
+}
+
+func TestSyncAllAccountBalances_ReadsAccountsInBatches(t *testing.T) {
+	repo := newBalanceSyncRepoStub(120)
+	svc, _ := newBalanceSyncTestService(t, repo, nil)
+
+	result, err := svc.SyncAllAccountBalances(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.pageAfter) != 3 {
+		t.Fatalf("expected 3 batches for 120 accounts, got %d", len(repo.pageAfter))
+	}
+	wantAfter := []uuid.UUID{uuid.Nil, repo.accounts[49].ID, repo.accounts[99].ID}
+	for i, after := range repo.pageAfter {
+		if repo.pageLimits[i] != balanceSyncBatchSize {
+			t.Fatalf("expected batch size %d, got %d", balanceSyncBatchSize, repo.pageLimits[i])
+		}
+		if after != wantAfter[i] {
+			t.Fatalf("batch %d: expected cursor %s, got %s", i, wantAfter[i], after)
+		}
+	}
+	if result.Scanned != 120 || result.Updated != 120 || len(repo.updates) != 120 {
+		t.Fatalf("expected all 120 accounts updated, got result=%+v updates=%d", result, len(repo.updates))
+	}
+}
+
+func TestSyncAllAccountBalances_LimitsConcurrentAnchorCalls(t *testing.T) {
+	repo := newBalanceSyncRepoStub(balanceSyncBatchSize)
+	svc, peak := newBalanceSyncTestService(t, repo, nil)
+
+	if _, err := svc.SyncAllAccountBalances(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(peak); got > balanceSyncConcurrency {
+		t.Fatalf("expected at most %d concurrent Anchor calls, saw %d", balanceSyncConcurrency, got)
+	}
+}
+
+func TestSyncAllAccountBalances_OneFailureDoesNotAbortBatch(t *testing.T) {
+	repo := newBalanceSyncRepoStub(20)
+	bad := repo.accounts[7]
+	stale := repo.accounts[12]
+	repo.staleIDs[stale.ID] = true
+	svc, _ := newBalanceSyncTestService(t, repo, map[string]bool{bad.AnchorAccountID: true})
+
+	result, err := svc.SyncAllAccountBalances(context.Background())
+	if err != nil {
+		t.Fatalf("expected run to complete despite one failure, got %v", err)
+	}
+
+	want := domain.BalanceSyncResult{Scanned: 20, Updated: 18, Skipped: 1, Failed: 1}
+	if *result != want {
+		t.Fatalf("expected %+v, got %+v", want, *result)
+	}
+	if _, ok := repo.updates[bad.ID]; ok {
+		t.Fatalf("expected failed account to be left untouched")
+	}
+	for _, account := range repo.accounts {
+		if account.ID == bad.ID || account.ID == stale.ID {
+			continue
+		}
+		if repo.updates[account.ID] != 500 {
+			t.Fatalf("expected account %s to be synced to 500, got %d", account.ID, repo.updates[account.ID])
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// beneficiaryReverifyTimeout bounds the re-verification a failed NIP transfer triggers.
+const beneficiaryReverifyTimeout = 15 * time.Second
+
+// invalidAccountFailureMarkers are substrings of Anchor NIP failure reasons that mean
+// the destination account itself is unusable rather than the transfer failing.
+var invalidAccountFailureMarkers = []string{
+	"invalid account",
+	"invalid beneficiary",
+	"account does not exist",
+	"account not found",
+	"no such account",
+	"account closed",
+	"closed account",
+	"dormant account",
+	"account is dormant",
+}
+
+// beneficiaryVerifier re-resolves a beneficiary's account. *Service satisfies it.
+type beneficiaryVerifier interface {
+	VerifyBeneficiary(ctx context.Context, userID, beneficiaryID uuid.UUID) (*domain.Beneficiary, error)
+}
+
+// VerifyBeneficiary re-resolves a beneficiary's bank account through Anchor and records
+// the outcome. A changed account name is stored; if it differs materially from the
+// saved one the beneficiary is flagged name_mismatch. An account Anchor no longer
+// resolves is flagged invalid, which blocks transfers to it.
+func (s *Service) VerifyBeneficiary(ctx context.Context, userID, beneficiaryID uuid.UUID) (*domain.Beneficiary, error) {
+	beneficiary, err := s.repo.FindBeneficiaryByID(ctx, beneficiaryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, resolvedName, err := s.resolveBeneficiaryAccount(ctx, beneficiary)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateBeneficiaryVerification(ctx, beneficiary.ID, status, resolvedName); err != nil {
+		return nil, fmt.Errorf("record beneficiary verification: %w", err)
+	}
+
+	now := time.Now().UTC()
+	beneficiary.VerificationStatus = status
+	beneficiary.LastVerifiedAt = &now
+	if resolvedName != "" {
+		beneficiary.AccountName = resolvedName
+	}
+	log.Printf("level=info component=service flow=beneficiary_verification msg=\"beneficiary verified\" beneficiary_id=%s user_id=%s status=%s", beneficiary.ID, userID, status)
+	return beneficiary, nil
+}
+
+// resolveBeneficiaryAccount asks Anchor for the beneficiary's account and returns its
+// verification status and the name the account resolves to. Anchor being unreachable
+// or failing is an error, never a verdict on the account.
+func (s *Service) resolveBeneficiaryAccount(ctx context.Context, beneficiary *domain.Beneficiary) (string, string, error) {
+	if s.anchorClient == nil {
+		return "", "", ErrBeneficiaryVerificationUnavailable
+	}
+
+	counterparty, err := s.anchorClient.GetCounterParty(ctx, beneficiary.AnchorCounterpartyID)
+	if err != nil {
+		var apiErr *anchorclient.APIError
+		if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+			return domain.BeneficiaryVerificationInvalid, "", nil
+		}
+		return "", "", fmt.Errorf("%w: %v", ErrBeneficiaryVerificationUnavailable, err)
+	}
+
+	attrs := counterparty.Data.Attributes
+	if attrs.AccountNumber == "" || attrs.Bank.NipCode == "" {
+		return "", "", fmt.Errorf("%w: counterparty %s has no account number or bank code", ErrBeneficiaryVerificationUnavailable, beneficiary.AnchorCounterpartyID)
+	}
+
+	resolved, err := s.anchorClient.VerifyBankAccount(ctx, attrs.Bank.NipCode, attrs.AccountNumber)
+	if err != nil {
+		var apiErr *anchorclient.APIError
+		if errors.As(err, &apiErr) && apiErr.IsExplicitRejection() {
+			return domain.BeneficiaryVerificationInvalid, "", nil
+		}
+		return "", "", fmt.Errorf("%w: %v", ErrBeneficiaryVerificationUnavailable, err)
+	}
+
+	resolvedName := strings.TrimSpace(resolved.Data.Attributes.AccountName)
+	if resolvedName == "" {
+		return domain.BeneficiaryVerificationInvalid, "", nil
+	}
+	if !accountNamesMatch(beneficiary.AccountName, resolvedName) {
+		return domain.BeneficiaryVerificationNameMismatch, resolvedName, nil
+	}
+	return domain.BeneficiaryVerificationVerified, resolvedName, nil
+}
+
+// accountNamesMatch reports whether two account names belong to the same holder.
+// Case, punctuation and word order are ignored, and a middle name added or dropped
+// still matches: the names must share at least two words, or every word of the
+// shorter one.
+func accountNamesMatch(saved, resolved string) bool {
+	savedWords := accountNameWords(saved)
+	resolvedWords := accountNameWords(resolved)
+	if len(savedWords) == 0 {
+		return true
+	}
+
+	shared := 0
+	for word := range savedWords {
+		if resolvedWords[word] {
+			shared++
+		}
+	}
+	return shared >= 2 || shared == min(len(savedWords), len(resolvedWords))
+}
+
+func accountNameWords(name string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// isInvalidAccountFailure reports whether a NIP failure reason says the destination
+// account is unusable.
+func isInvalidAccountFailure(reason string) bool {
+	reason = strings.ToLower(reason)
+	for _, marker := range invalidAccountFailureMarkers {
+		if strings.Contains(reason, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// reverifyBeneficiaryAfterFailure re-verifies the beneficiary a NIP transfer went to
+// when Anchor failed it for an invalid account, so the next transfer to a closed
+// account is rejected up front. It only logs on failure.
+func (c *TransferStatusConsumer) reverifyBeneficiaryAfterFailure(ctx context.Context, tx *domain.Transaction, event domain.TransferStatusEvent) {
+	if c.beneficiaries == nil || tx.DestinationBeneficiaryID == nil || !isInvalidAccountFailure(event.Reason) {
+		return
+	}
+
+	// Self transfers go to the sender's own beneficiary; P2P transfers routed
+	// externally go to the recipient's.
+	ownerID := tx.SenderID
+	if tx.RecipientID != nil {
+		ownerID = *tx.RecipientID
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, beneficiaryReverifyTimeout)
+	defer cancel()
+	beneficiary, err := c.beneficiaries.VerifyBeneficiary(verifyCtx, ownerID, *tx.DestinationBeneficiaryID)
+	if err != nil {
+		log.Printf("level=warn component=transfer_consumer msg=\"beneficiary re-verification failed\" transaction_id=%s beneficiary_id=%s err=%v", tx.ID, *tx.DestinationBeneficiaryID, err)
+		return
+	}
+	log.Printf("level=info component=transfer_consumer msg=\"beneficiary re-verified after failed transfer\" transaction_id=%s beneficiary_id=%s status=%s", tx.ID, beneficiary.ID, beneficiary.VerificationStatus)
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type beneficiaryVerificationRepoStub struct {
+	store.Repository
+
+	beneficiary domain.Beneficiary
+
+	updates      int
+	status       string
+	resolvedName string
+	debits       int
+}
+
+func (s *beneficiaryVerificationRepoStub) FindBeneficiaryByID(ctx context.Context, beneficiaryID uuid.UUID, userID uuid.UUID) (*domain.Beneficiary, error) {
+	if beneficiaryID != s.beneficiary.ID || userID != s.beneficiary.UserID {
+		return nil, store.ErrBeneficiaryNotFound
+	}
+	beneficiary := s.beneficiary
+	return &beneficiary, nil
+}
+
+func (s *beneficiaryVerificationRepoStub) UpdateBeneficiaryVerification(ctx context.Context, beneficiaryID uuid.UUID, status string, accountName string) error {
+	s.updates++
+	s.status = status
+	s.resolvedName = accountName
+	return nil
+}
+
+func (s *beneficiaryVerificationRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: userID, AllowSending: true}, nil
+}
+
+func (s *beneficiaryVerificationRepoStub) IsUserDelinquent(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *beneficiaryVerificationRepoStub) DebitWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	s.debits++
+	return nil
+}
+
+// newBeneficiaryVerificationTestService serves the beneficiary's counterparty and the
+// account verification from a fake Anchor. verifyStatus and verifyBody are what the
+// verification endpoint answers.
+func newBeneficiaryVerificationTestService(t *testing.T, savedName string, verifyStatus int, verifyBody string) (*Service, *beneficiaryVerificationRepoStub) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/counterparties/cp_saved":
+			_, _ = io.WriteString(w, `{"data":{"id":"cp_saved","type":"CounterParty","attributes":{"accountName":"ADA OBI","accountNumber":"0123456789","bank":{"id":"bank_1","name":"Test Bank","nipCode":"000013"}}}}`)
+		case "/api/v1/payments/verify-account/000013/0123456789":
+			w.WriteHeader(verifyStatus)
+			_, _ = io.WriteString(w, verifyBody)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	repo := &beneficiaryVerificationRepoStub{beneficiary: domain.Beneficiary{
+		ID:                   uuid.New(),
+		UserID:               uuid.New(),
+		AnchorCounterpartyID: "cp_saved",
+		AccountName:          savedName,
+		AccountNumberMasked:  "******6789",
+		BankName:             "Test Bank",
+		VerificationStatus:   domain.BeneficiaryVerificationUnverified,
+	}}
+	svc := &Service{
+		repo:         repo,
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
+	}
+	return svc, repo
+}
+
+func verifiedAccountBody(name string) string {
+	return `{"data":{"attributes":{"accountName":"` + name + `"}}}`
+}
+
+func TestVerifyBeneficiary_RecordsOutcome(t *testing.T) {
+	tests := []struct {
+		name         string
+		savedName    string
+		verifyStatus int
+		verifyBody   string
+		wantStatus   string
+		wantName     string
+	}{
+		{
+			name:         "same holder with reordered and extra names",
+			savedName:    "Ada Obi",
+			verifyStatus: http.StatusOK,
+			verifyBody:   verifiedAccountBody("OBI ADA CHIOMA"),
+			wantStatus:   domain.BeneficiaryVerificationVerified,
+			wantName:     "OBI ADA CHIOMA",
+		},
+		{
+			name:         "materially different holder",
+			savedName:    "Ada Obi",
+			verifyStatus: http.StatusOK,
+			verifyBody:   verifiedAccountBody("EMEKA NWOSU"),
+			wantStatus:   domain.BeneficiaryVerificationNameMismatch,
+			wantName:     "EMEKA NWOSU",
+		},
+		{
+			name:         "account no longer resolves",
+			savedName:    "Ada Obi",
+			verifyStatus: http.StatusBadRequest,
+			verifyBody:   `{"errors":[{"title":"Bad Request","detail":"Invalid account number","status":"400"}]}`,
+			wantStatus:   domain.BeneficiaryVerificationInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newBeneficiaryVerificationTestService(t, tt.savedName, tt.verifyStatus, tt.verifyBody)
+
+			beneficiary, err := svc.VerifyBeneficiary(context.Background(), repo.beneficiary.UserID, repo.beneficiary.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.updates != 1 || repo.status != tt.wantStatus || repo.resolvedName != tt.wantName {
+				t.Fatalf("expected status %s with name %q recorded once, got %d updates status=%s name=%q", tt.wantStatus, tt.wantName, repo.updates, repo.status, repo.resolvedName)
+			}
+			if beneficiary.VerificationStatus != tt.wantStatus || beneficiary.LastVerifiedAt == nil {
+				t.Fatalf("expected the returned beneficiary to carry the outcome, got %+v", beneficiary)
+			}
+		})
+	}
+}
+
+func TestVerifyBeneficiary_ProviderFailureIsNotAVerdict(t *testing.T) {
+	svc, repo := newBeneficiaryVerificationTestService(t, "Ada Obi", http.StatusInternalServerError, `{"errors":[{"title":"Internal Server Error","status":"500"}]}`)
+
+	_, err := svc.VerifyBeneficiary(context.Background(), repo.beneficiary.UserID, repo.beneficiary.ID)
+	if !errors.Is(err, ErrBeneficiaryVerificationUnavailable) {
+		t.Fatalf("expected ErrBeneficiaryVerificationUnavailable, got %v", err)
+	}
+	if repo.updates != 0 {
+		t.Fatalf("expected nothing recorded when Anchor fails, got %d updates", repo.updates)
+	}
+}
+
+func TestProcessSelfTransfer_RejectsInvalidBeneficiary(t *testing.T) {
+	svc, repo := newBeneficiaryVerificationTestService(t, "Ada Obi", http.StatusOK, verifiedAccountBody("ADA OBI"))
+	repo.beneficiary.VerificationStatus = domain.BeneficiaryVerificationInvalid
+
+	_, err := svc.ProcessSelfTransfer(context.Background(), repo.beneficiary.UserID, domain.SelfTransferRequest{
+		BeneficiaryID: repo.beneficiary.ID,
+		Amount:        5000,
+		Description:   "Rent",
+	})
+	if !errors.Is(err, ErrBeneficiaryAccountInvalid) {
+		t.Fatalf("expected ErrBeneficiaryAccountInvalid, got %v", err)
+	}
+	if repo.debits != 0 {
+		t.Fatalf("expected no debit, got %d", repo.debits)
+	}
+}
+
+func TestAccountNamesMatch(t *testing.T) {
+	tests := []struct {
+		saved, resolved string
+		want            bool
+	}{
+		{"Ada Obi", "ADA OBI", true},
+		{"Ada Obi", "Obi, Ada", true},
+		{"Ada Chioma Obi", "ADA OBI", true},
+		{"Ada", "ADA OBI", true},
+		{"Ada Obi", "ADA NWOSU", false},
+		{"Ada Obi", "EMEKA NWOSU", false},
+		{"", "EMEKA NWOSU", true},
+	}
+	for _, tt := range tests {
+		if got := accountNamesMatch(tt.saved, tt.resolved); got != tt.want {
+			t.Errorf("accountNamesMatch(%q, %q) = %v, want %v", tt.saved, tt.resolved, got, tt.want)
+		}
+	}
+}
+
+type recordingBeneficiaryVerifier struct {
+	calls []uuid.UUID
+	users []uuid.UUID
+}
+
+func (v *recordingBeneficiaryVerifier) VerifyBeneficiary(ctx context.Context, userID, beneficiaryID uuid.UUID) (*domain.Beneficiary, error) {
+	v.calls = append(v.calls, beneficiaryID)
+	v.users = append(v.users, userID)
+	return &domain.Beneficiary{ID: beneficiaryID, UserID: userID, VerificationStatus: domain.BeneficiaryVerificationInvalid}, nil
+}
+
+func TestProcessEvent_FailedTransferToInvalidAccountReverifiesBeneficiary(t *testing.T) {
+	tests := []struct {
+		name       string
+		reason     string
+		wantVerify bool
+	}{
+		{name: "invalid account", reason: "Invalid account number", wantVerify: true},
+		{name: "closed account", reason: "Beneficiary account closed", wantVerify: true},
+		{name: "unrelated failure", reason: "Insufficient balance", wantVerify: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beneficiaryID := uuid.New()
+			senderID := uuid.New()
+			repo := &consumerStatusTransitionRepoStub{
+				tx: &domain.Transaction{
+					ID:                       uuid.New(),
+					SenderID:                 senderID,
+					DestinationBeneficiaryID: &beneficiaryID,
+					Type:                     "self_transfer",
+					Status:                   "pending",
+					Amount:                   5000,
+					Fee:                      100,
+				},
+			}
+			verifier := &recordingBeneficiaryVerifier{}
+			consumer := NewTransferStatusConsumer(repo, nil)
+			consumer.beneficiaries = verifier
+
+			err := consumer.processEvent(context.Background(), domain.TransferStatusEvent{
+				AnchorTransferID: "atr_self_failed",
+				Status:           "failed",
+				Reason:           tt.reason,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !repo.markFailedCalled || !repo.creditCalled {
+				t.Fatal("expected the transfer to be failed and refunded")
+			}
+
+			if !tt.wantVerify {
+				if len(verifier.calls) != 0 {
+					t.Fatalf("expected no re-verification, got %d", len(verifier.calls))
+				}
+				return
+			}
+			if len(verifier.calls) != 1 || verifier.calls[0] != beneficiaryID || verifier.users[0] != senderID {
+				t.Fatalf("expected the sender's beneficiary to be re-verified once, got %v for %v", verifier.calls, verifier.users)
+			}
+		})
+	}
+}
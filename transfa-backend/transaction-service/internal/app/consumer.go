@@ -8,29 +8,52 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/transfa/pkg/anchorclient"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
 )
 
 const maxMissingTransferRetries = 20
 
+// transferLookup fetches a transfer from Anchor. *anchorclient.Client satisfies it.
+type transferLookup interface {
+	GetTransfer(ctx context.Context, transferID string) (*anchorclient.TransferDetails, error)
+}
+
 type TransferStatusConsumer struct {
 	repo              store.Repository
+	anchor            transferLookup
 	mu                sync.Mutex
 	missingTxAttempts map[string]int
+
+	// unmatched counts events parked because no transaction matched them.
+	unmatched atomic.Int64
+
+	// beneficiaries re-verifies the beneficiary of a NIP transfer that failed for an
+	// invalid account. Nil skips re-verification.
+	beneficiaries beneficiaryVerifier
 }
 
-func NewTransferStatusConsumer(repo store.Repository) *TransferStatusConsumer {
+// NewTransferStatusConsumer creates the consumer. anchor may be nil, in which case
+// unmatched events are parked without first asking Anchor for the transfer.
+func NewTransferStatusConsumer(repo store.Repository, anchor transferLookup) *TransferStatusConsumer {
 	return &TransferStatusConsumer{
 		repo:              repo,
+		anchor:            anchor,
 		missingTxAttempts: make(map[string]int),
 	}
 }
 
+// UnmatchedEvents returns how many events have been parked as unmatched since start.
+func (c *TransferStatusConsumer) UnmatchedEvents() int64 {
+	return c.unmatched.Load()
+}
+
 func (c *TransferStatusConsumer) HandleMessage(body []byte) bool {
 	var event domain.TransferStatusEvent
 	if err := json.Unmarshal(body, &event); err != nil {
@@ -47,6 +70,9 @@ func (c *TransferStatusConsumer) HandleMessage(body []byte) bool {
 	defer cancel()
 
 	if err := c.processEvent(ctx, event); err != nil {
+		if errors.Is(err, ErrTransferCurrencyMismatch) {
+			return c.parkCurrencyMismatch(ctx, event, body, err)
+		}
 		if errors.Is(err, store.ErrTransactionNotFound) {
 			// Fee transfer webhooks are expected to not map to a primary user-facing transaction.
 			if looksLikeFeeEvent(event) {
@@ -64,9 +90,7 @@ func (c *TransferStatusConsumer) HandleMessage(body []byte) bool {
 				return false
 			}
 
-			log.Printf("level=warn component=transfer_consumer outcome=ack reason=transaction_not_found anchor_transfer_id=%s attempts=%d", event.AnchorTransferID, attempt)
-			c.clearMissingAttempt(event.AnchorTransferID)
-			return true
+			return c.resolveOrParkUnmatched(ctx, event, body, attempt)
 		}
 
 		log.Printf("level=error component=transfer_consumer outcome=requeue reason=processing_error anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
@@ -77,11 +101,113 @@ func (c *TransferStatusConsumer) HandleMessage(body []byte) bool {
 	return true
 }
 
+// resolveOrParkUnmatched handles an event that exhausted its retries without a
+// matching transaction. Matching runs once more with Anchor's copy of the transfer;
+// if that also fails the event is parked in unmatched_transfer_events so it can be
+// replayed later instead of being lost.
+func (c *TransferStatusConsumer) resolveOrParkUnmatched(ctx context.Context, event domain.TransferStatusEvent, body []byte, attempts int) bool {
+	tx, err := c.findTransactionWithAnchorLookup(ctx, event)
+	if err == nil {
+		if err := c.applyEvent(ctx, tx, event); err != nil {
+			if errors.Is(err, ErrTransferCurrencyMismatch) {
+				return c.parkCurrencyMismatch(ctx, event, body, err)
+			}
+			log.Printf("level=error component=transfer_consumer outcome=requeue reason=processing_error anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+			return false
+		}
+		c.clearMissingAttempt(event.AnchorTransferID)
+		return true
+	}
+	if !errors.Is(err, store.ErrTransactionNotFound) {
+		log.Printf("level=error component=transfer_consumer outcome=requeue reason=processing_error anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+		return false
+	}
+
+	parked, err := c.repo.ParkUnmatchedTransferEvent(ctx, domain.UnmatchedTransferEvent{
+		AnchorTransferID: event.AnchorTransferID,
+		EventKey:         unmatchedTransferEventKey(event),
+		EventType:        event.EventType,
+		Payload:          body,
+		LastError:        fmt.Sprintf("transaction not found after %d attempts", attempts),
+	})
+	if err != nil {
+		log.Printf("level=error component=transfer_consumer outcome=requeue reason=park_unmatched_failed anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+		return false
+	}
+
+	total := c.unmatched.Add(1)
+	log.Printf("level=warn component=transfer_consumer outcome=parked reason=transaction_not_found anchor_transfer_id=%s unmatched_event_id=%s attempts=%d unmatched_total=%d", event.AnchorTransferID, parked.ID, attempts, total)
+	c.clearMissingAttempt(event.AnchorTransferID)
+	return true
+}
+
+// parkCurrencyMismatch parks an event whose currency differs from its transaction's,
+// without applying it: amounts in another currency must never move a wallet's
+// status or balance. Parked events can be inspected and replayed like unmatched ones.
+func (c *TransferStatusConsumer) parkCurrencyMismatch(ctx context.Context, event domain.TransferStatusEvent, body []byte, mismatch error) bool {
+	parked, err := c.repo.ParkUnmatchedTransferEvent(ctx, domain.UnmatchedTransferEvent{
+		AnchorTransferID: event.AnchorTransferID,
+		EventKey:         unmatchedTransferEventKey(event),
+		EventType:        event.EventType,
+		Payload:          body,
+		LastError:        mismatch.Error(),
+	})
+	if err != nil {
+		log.Printf("level=error component=transfer_consumer outcome=requeue reason=park_currency_mismatch_failed anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+		return false
+	}
+
+	total := c.unmatched.Add(1)
+	log.Printf("level=warn component=transfer_consumer outcome=parked reason=currency_mismatch anchor_transfer_id=%s unmatched_event_id=%s event_currency=%s unmatched_total=%d err=%v", event.AnchorTransferID, parked.ID, event.Currency, total, mismatch)
+	c.clearMissingAttempt(event.AnchorTransferID)
+	return true
+}
+
+// unmatchedTransferEventKey identifies an event within its transfer, so a redelivery
+// updates the parked row while other status events for the transfer get their own.
+func unmatchedTransferEventKey(event domain.TransferStatusEvent) string {
+	if id := strings.TrimSpace(event.EventID); id != "" {
+		return id
+	}
+	return event.EventType + ":" + normalizeStatus(event.Status)
+}
+
+// replay re-runs matching for a parked event and applies it, returning the matched
+// transaction. It returns store.ErrTransactionNotFound if nothing matches yet.
+func (c *TransferStatusConsumer) replay(ctx context.Context, event domain.TransferStatusEvent) (*domain.Transaction, error) {
+	tx, err := c.findTransactionWithAnchorLookup(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.applyEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
 func (c *TransferStatusConsumer) processEvent(ctx context.Context, event domain.TransferStatusEvent) error {
 	tx, err := c.findTransactionForEvent(ctx, event)
+	if errors.Is(err, store.ErrTransactionNotFound) {
+		// Fee transfers have no transaction of their own; they are tracked as fee
+		// collections of the transaction they were charged for.
+		if handled, feeErr := c.applyFeeCollectionEvent(ctx, event); handled {
+			return feeErr
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("lookup transaction: %w", err)
 	}
+	return c.applyEvent(ctx, tx, event)
+}
+
+func (c *TransferStatusConsumer) applyEvent(ctx context.Context, tx *domain.Transaction, event domain.TransferStatusEvent) error {
+	if err := checkEventCurrency(tx, event); err != nil {
+		return err
+	}
+
+	if tx.Type == "money_drop_funding" {
+		return c.applyMoneyDropFundingEvent(ctx, tx, event)
+	}
 
 	status := normalizeStatus(event.Status)
 	transferType := normalizeTransferType(event.TransferType)
@@ -175,6 +301,59 @@ func (c *TransferStatusConsumer) findTransactionForEvent(ctx context.Context, ev
 	return fallbackTx, nil
 }
 
+// findTransactionWithAnchorLookup is findTransactionForEvent with one more attempt
+// after filling in what the event is missing from Anchor's copy of the transfer.
+func (c *TransferStatusConsumer) findTransactionWithAnchorLookup(ctx context.Context, event domain.TransferStatusEvent) (*domain.Transaction, error) {
+	tx, err := c.findTransactionForEvent(ctx, event)
+	if !errors.Is(err, store.ErrTransactionNotFound) {
+		return tx, err
+	}
+
+	enriched, ok := c.enrichEventFromAnchor(ctx, event)
+	if !ok {
+		return nil, err
+	}
+	tx, err = c.findTransactionForEvent(ctx, enriched)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("level=info component=transfer_consumer msg=\"resolved transaction via anchor transfer lookup\" transaction_id=%s anchor_transfer_id=%s", tx.ID, event.AnchorTransferID)
+	return tx, nil
+}
+
+// enrichEventFromAnchor fills the reason, amount and participants an event is missing
+// from the transfer as Anchor has it. The enriched event is only for matching; the
+// original event is what gets applied. It reports false when there is no Anchor
+// client, the lookup fails or Anchor has nothing to add.
+func (c *TransferStatusConsumer) enrichEventFromAnchor(ctx context.Context, event domain.TransferStatusEvent) (domain.TransferStatusEvent, bool) {
+	if c.anchor == nil {
+		return event, false
+	}
+	transfer, err := c.anchor.GetTransfer(ctx, event.AnchorTransferID)
+	if err != nil {
+		log.Printf("level=warn component=transfer_consumer msg=\"anchor transfer lookup failed\" anchor_transfer_id=%s err=%v", event.AnchorTransferID, err)
+		return event, false
+	}
+
+	enriched := event
+	if strings.TrimSpace(enriched.Reason) == "" {
+		enriched.Reason = transfer.Reason
+	}
+	if enriched.Amount <= 0 {
+		enriched.Amount = transfer.Amount
+	}
+	if enriched.AnchorAccountID == "" {
+		enriched.AnchorAccountID = transfer.AccountID
+	}
+	if enriched.CounterpartyID == "" {
+		enriched.CounterpartyID = transfer.CounterPartyID
+		if enriched.CounterpartyID == "" {
+			enriched.CounterpartyID = transfer.DestinationAccountID
+		}
+	}
+	return enriched, enriched != event
+}
+
 func isValidMoneyDropClaimReasonTokenTransaction(tx *domain.Transaction, event domain.TransferStatusEvent) bool {
 	if !isValidMoneyDropClaimFallbackBase(tx, event) {
 		return false
@@ -225,17 +404,36 @@ func (c *TransferStatusConsumer) handleFailure(ctx context.Context, tx *domain.T
 		return nil
 	}
 
-	if err := c.repo.MarkTransactionAsFailed(ctx, tx.ID, event.AnchorTransferID, event.Reason); err != nil {
-		return fmt.Errorf("mark failed: %w", err)
-	}
-
-	if err := c.repo.CreditWallet(ctx, tx.SenderID, tx.Amount+tx.Fee); err != nil {
-		return fmt.Errorf("refund wallet: %w", err)
+	// Marking the transaction failed and refunding commit together: the status check
+	// above skips already-failed transactions on redelivery, so a refund that did not
+	// land with the status change would never be retried. The fee is refunded
+	// separately from the amount so the stored fee is zeroed and cannot be credited
+	// twice.
+	if err := c.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		if err := txRepo.MarkTransactionAsFailed(ctx, tx.ID, event.AnchorTransferID, event.Reason); err != nil {
+			return fmt.Errorf("mark failed: %w", err)
+		}
+		if err := txRepo.CreditWallet(ctx, tx.SenderID, tx.Amount); err != nil {
+			return fmt.Errorf("refund wallet: %w", err)
+		}
+		refunded, err := txRepo.RefundTransactionFee(ctx, tx.ID, tx.SenderID, tx.Fee)
+		if err != nil {
+			return fmt.Errorf("refund fee: %w", err)
+		}
+		if !refunded && tx.Fee > 0 {
+			log.Printf("level=info component=transfer_consumer msg=\"fee already refunded\" transaction_id=%s", tx.ID)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, store.ErrTransactionAlreadyFinal) {
+			// A concurrent delivery settled the transaction first and owns the refund.
+			log.Printf("level=info component=transfer_consumer msg=\"ignoring failed event for settled transaction\" transaction_id=%s anchor_transfer_id=%s", tx.ID, event.AnchorTransferID)
+			return nil
+		}
+		return err
 	}
 
-	if err := c.repo.RefundTransactionFee(ctx, tx.ID, tx.SenderID, tx.Fee); err != nil {
-		log.Printf("level=warn component=transfer_consumer msg=\"fee refund failed\" transaction_id=%s err=%v", tx.ID, err)
-	}
+	c.reverifyBeneficiaryAfterFailure(ctx, tx, event)
 
 	if err := c.repo.ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx, tx.ID); err != nil {
 		return fmt.Errorf("release processing payment request: %w", err)
@@ -318,6 +516,10 @@ func (c *TransferStatusConsumer) markMoneyDropClaimAsFailedWithoutRevert(ctx con
 	combinedReason = fmt.Sprintf("%s; %s", combinedReason, detail)
 
 	if err := c.repo.MarkTransactionAsFailed(ctx, tx.ID, event.AnchorTransferID, combinedReason); err != nil {
+		if errors.Is(err, store.ErrTransactionAlreadyFinal) {
+			log.Printf("level=info component=transfer_consumer msg=\"money-drop claim already settled; not marking failed\" transaction_id=%s anchor_transfer_id=%s", tx.ID, event.AnchorTransferID)
+			return nil
+		}
 		return fmt.Errorf("mark failed money_drop_claim: %w", err)
 	}
 
@@ -519,17 +721,21 @@ func (c *TransferStatusConsumer) emitRequestPaidNotification(ctx context.Context
 		return
 	}
 
-	body := fmt.Sprintf("Your request \"%s\" has been paid.", request.Title)
+	notificationType, title, body := requestPaidNotification(request)
 	relatedEntityType := "payment_request"
-	dedupeKey := fmt.Sprintf("request.paid:%s:%s", request.ID, tx.ID)
+	dedupeKey := fmt.Sprintf("%s:%s:%s", notificationType, request.ID, tx.ID)
 	payerID := tx.SenderID
+	displayStatus := "pending"
+	if isPaymentRequestPaid(request) {
+		displayStatus = "paid"
+	}
 
-	c.emitInAppNotification(ctx, "request.paid", domain.InAppNotification{
+	c.emitInAppNotification(ctx, notificationType, domain.InAppNotification{
 		ID:                uuid.New(),
 		UserID:            request.CreatorID,
 		Category:          "request",
-		Type:              "request.paid",
-		Title:             "Request Paid",
+		Type:              notificationType,
+		Title:             title,
 		Body:              &body,
 		Status:            "unread",
 		RelatedEntityType: &relatedEntityType,
@@ -539,8 +745,10 @@ func (c *TransferStatusConsumer) emitRequestPaidNotification(ctx context.Context
 			"request_id":        request.ID.String(),
 			"transaction_id":    tx.ID.String(),
 			"amount":            request.Amount,
+			"amount_paid":       request.AmountPaid,
+			"remaining_amount":  paymentRequestRemaining(request),
 			"status":            request.Status,
-			"display_status":    "paid",
+			"display_status":    displayStatus,
 			"paid_by_user_id":   payerID.String(),
 			"paid_by_username":  payerUsername,
 			"title":             request.Title,
@@ -47,6 +47,10 @@ func (s *consumerFailureRepoStub) MarkMoneyDropClaimReconcileRequested(ctx conte
 	return true, nil
 }
 
+func (s *consumerFailureRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
 func (s *consumerFailureRepoStub) MarkTransactionAsFailed(ctx context.Context, transactionID uuid.UUID, anchorTransferID, failureReason string) error {
 	s.markFailed = true
 	return s.markErr
@@ -57,9 +61,9 @@ func (s *consumerFailureRepoStub) CreditWallet(ctx context.Context, userID uuid.
 	return nil
 }
 
-func (s *consumerFailureRepoStub) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) error {
+func (s *consumerFailureRepoStub) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) (bool, error) {
 	s.refundFeeCalled = true
-	return nil
+	return fee > 0, nil
 }
 
 func (s *consumerFailureRepoStub) ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error {
@@ -146,7 +150,7 @@ func TestHandleFailure_MoneyDropClaimMarksRetryRequestedWithoutWalletRefund(t *t
 		dropID:         uuid.New(),
 		requestRetryOK: true,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_test",
 		Status:           "failed",
@@ -184,7 +188,7 @@ func TestHandleFailure_MoneyDropClaimUsesGenericRetryMarkerWhenDropLookupFails(t
 		resolveErr:     errors.New("drop mapping missing"),
 		requestRetryOK: true,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_test",
 		Status:           "failed",
@@ -224,7 +228,7 @@ func TestHandleFailure_MoneyDropClaimRequeuesOnRetryableRetryMarkError(t *testin
 		dropID:          uuid.New(),
 		requestRetryErr: context.DeadlineExceeded,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_test",
 		Status:           "failed",
@@ -266,7 +270,7 @@ func TestHandleFailure_MoneyDropClaimUsesAnchorReasonTokenBeforeRepoLookup(t *te
 		resolveErr:     errors.New("should not be called when token exists"),
 		requestRetryOK: true,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_test",
 		Status:           "failed",
@@ -304,7 +308,7 @@ func TestHandleFailure_MoneyDropClaimDoesNotRevertCompletedClaim(t *testing.T) {
 		dropID:         uuid.New(),
 		requestRetryOK: true,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_test",
 		Status:           "failed",
@@ -337,7 +341,7 @@ func TestProcessEvent_MoneyDropClaimFailedReplaySkipsMetadataUpdateWhenCompleted
 	repo := &consumerLookupRepoStub{
 		byAnchorTx: tx,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_completed_replay",
 		Status:           "failed",
@@ -368,7 +372,7 @@ func TestHandleFailure_MoneyDropClaimRetriesReconcileMarkWhenStatusFailed(t *tes
 		dropID:         uuid.New(),
 		requestRetryOK: true,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_retry_failed_status",
 		Status:           "failed",
@@ -400,7 +404,7 @@ func TestFindTransactionForEvent_UsesParticipantFallbackWhenReasonTokenMissing(t
 		byAnchorErr:      store.ErrTransactionNotFound,
 		byParticipantsTx: tx,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_fallback",
 		AnchorAccountID:  "anchor_src",
@@ -447,7 +451,7 @@ func TestFindTransactionForEvent_PrefersReasonTokenMatchWhenTransactionAlreadyFa
 		byReasonTx:       reasonMatchedTx,
 		byParticipantsTx: participantFallbackTx,
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_fallback",
 		AnchorAccountID:  "anchor_src",
@@ -17,9 +17,12 @@ type consumerStatusTransitionRepoStub struct {
 	updateMetadataCalled bool
 	updatedMetadata      store.UpdateTransactionMetadataParams
 
+	markFailedErr    error
 	markFailedCalled bool
 	creditCalled     bool
+	creditedAmount   int64
 	refundFeeCalled  bool
+	refundedFee      int64
 	releaseReqCalled bool
 }
 
@@ -36,19 +39,28 @@ func (s *consumerStatusTransitionRepoStub) UpdateTransactionMetadata(ctx context
 	return nil
 }
 
+func (s *consumerStatusTransitionRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
 func (s *consumerStatusTransitionRepoStub) MarkTransactionAsFailed(ctx context.Context, transactionID uuid.UUID, anchorTransferID, failureReason string) error {
 	s.markFailedCalled = true
-	return nil
+	return s.markFailedErr
 }
 
 func (s *consumerStatusTransitionRepoStub) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
 	s.creditCalled = true
+	s.creditedAmount += amount
 	return nil
 }
 
-func (s *consumerStatusTransitionRepoStub) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) error {
+func (s *consumerStatusTransitionRepoStub) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) (bool, error) {
 	s.refundFeeCalled = true
-	return nil
+	if fee <= 0 {
+		return false, nil
+	}
+	s.refundedFee += fee
+	return true, nil
 }
 
 func (s *consumerStatusTransitionRepoStub) ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error {
@@ -73,7 +85,7 @@ func TestProcessEvent_IgnoresPendingReplayForCompletedTransaction(t *testing.T)
 			Fee:         10,
 		},
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_completed_replay",
@@ -111,7 +123,7 @@ func TestProcessEvent_IgnoresFailedReplayForCompletedTransaction(t *testing.T) {
 			Fee:         10,
 		},
 	}
-	consumer := NewTransferStatusConsumer(repo)
+	consumer := NewTransferStatusConsumer(repo, nil)
 
 	event := domain.TransferStatusEvent{
 		AnchorTransferID: "atr_failed_replay",
@@ -132,3 +144,64 @@ func TestProcessEvent_IgnoresFailedReplayForCompletedTransaction(t *testing.T) {
 		t.Fatal("did not expect failed replay to reverse a completed transfer")
 	}
 }
+
+func TestProcessEvent_FailedEventSkipsRefundWhenSettledConcurrently(t *testing.T) {
+	recipientID := uuid.New()
+	repo := &consumerStatusTransitionRepoStub{
+		tx: &domain.Transaction{
+			ID:          uuid.New(),
+			SenderID:    uuid.New(),
+			RecipientID: &recipientID,
+			Type:        "p2p_transfer",
+			Status:      "pending",
+			Amount:      1000,
+			Fee:         10,
+		},
+		// Another delivery marked the transaction failed after it was read above.
+		markFailedErr: store.ErrTransactionAlreadyFinal,
+	}
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	event := domain.TransferStatusEvent{
+		AnchorTransferID: "atr_duplicate_failure",
+		Status:           "failed",
+		Reason:           "insufficient balance",
+	}
+
+	if err := consumer.processEvent(context.Background(), event); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !repo.markFailedCalled {
+		t.Fatal("expected the failure to be attempted")
+	}
+	if repo.creditCalled || repo.refundFeeCalled || repo.releaseReqCalled {
+		t.Fatal("did not expect a second refund for a transaction settled concurrently")
+	}
+}
+
+func TestProcessEvent_FailedEventRefundsAmountAndFeeOnce(t *testing.T) {
+	repo := &consumerStatusTransitionRepoStub{
+		tx: &domain.Transaction{
+			ID:       uuid.New(),
+			SenderID: uuid.New(),
+			Type:     "self_transfer",
+			Status:   "pending",
+			Amount:   1000,
+			Fee:      10,
+		},
+	}
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	event := domain.TransferStatusEvent{
+		AnchorTransferID: "atr_failed_refund",
+		Status:           "failed",
+		Reason:           "insufficient balance",
+	}
+
+	if err := consumer.processEvent(context.Background(), event); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if repo.creditedAmount != 1000 || repo.refundedFee != 10 {
+		t.Fatalf("expected amount 1000 credited and fee 10 refunded, got amount=%d fee=%d", repo.creditedAmount, repo.refundedFee)
+	}
+}
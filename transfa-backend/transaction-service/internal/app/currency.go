@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ErrTransferCurrencyMismatch is returned when a transfer status event carries a
+// different currency from the transaction it matched.
+var ErrTransferCurrencyMismatch = errors.New("transfer event currency does not match transaction currency")
+
+// UnsupportedCurrencyError is returned when a request names a currency the service
+// cannot move money in yet.
+type UnsupportedCurrencyError struct {
+	Currency string
+}
+
+func (e *UnsupportedCurrencyError) Error() string {
+	return fmt.Sprintf("currency %s is not supported; only %s is available", e.Currency, domain.DefaultCurrency)
+}
+
+// normalizeCurrency upper-cases a requested currency code, treating an empty code as
+// the default. Only the default currency is accepted for now.
+func normalizeCurrency(raw string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(raw))
+	if currency == "" {
+		return domain.DefaultCurrency, nil
+	}
+	if currency != domain.DefaultCurrency {
+		return "", &UnsupportedCurrencyError{Currency: currency}
+	}
+	return currency, nil
+}
+
+// checkEventCurrency reports ErrTransferCurrencyMismatch when event names a currency
+// other than tx's. Events without a currency are accepted, as are transactions
+// loaded without one, which predate the currency column and are in the default.
+func checkEventCurrency(tx *domain.Transaction, event domain.TransferStatusEvent) error {
+	eventCurrency := strings.ToUpper(strings.TrimSpace(event.Currency))
+	if eventCurrency == "" {
+		return nil
+	}
+	txCurrency := strings.ToUpper(strings.TrimSpace(tx.Currency))
+	if txCurrency == "" {
+		txCurrency = domain.DefaultCurrency
+	}
+	if eventCurrency != txCurrency {
+		return fmt.Errorf("%w: event=%s transaction=%s", ErrTransferCurrencyMismatch, eventCurrency, txCurrency)
+	}
+	return nil
+}
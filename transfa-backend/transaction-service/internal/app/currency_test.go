@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	for _, raw := range []string{"", " ngn ", "NGN"} {
+		got, err := normalizeCurrency(raw)
+		if err != nil || got != domain.DefaultCurrency {
+			t.Fatalf("normalizeCurrency(%q) = %q, %v; want NGN", raw, got, err)
+		}
+	}
+
+	_, err := normalizeCurrency("usd")
+	var unsupported *UnsupportedCurrencyError
+	if !errors.As(err, &unsupported) || unsupported.Currency != "USD" {
+		t.Fatalf("expected UnsupportedCurrencyError for USD, got %v", err)
+	}
+}
+
+func TestMoneyPaths_RejectUnsupportedCurrency(t *testing.T) {
+	svc := &Service{}
+	ctx := context.Background()
+	var unsupported *UnsupportedCurrencyError
+
+	_, err := svc.ProcessP2PTransfer(ctx, uuid.New(), domain.P2PTransferRequest{
+		RecipientUsername: "bola",
+		Amount:            1000,
+		Currency:          "USD",
+		Description:       "Lunch",
+	})
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected p2p transfer in USD to be rejected, got %v", err)
+	}
+
+	_, err = svc.ProcessSelfTransfer(ctx, uuid.New(), domain.SelfTransferRequest{
+		BeneficiaryID: uuid.New(),
+		Amount:        1000,
+		Currency:      "GBP",
+		Description:   "Rent",
+	})
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected self transfer in GBP to be rejected, got %v", err)
+	}
+
+	_, err = svc.CreateMoneyDrop(ctx, uuid.New(), domain.CreateMoneyDropRequest{
+		Title:           "Birthday",
+		TotalAmount:     10000,
+		Currency:        "EUR",
+		NumberOfPeople:  2,
+		ExpiryInMinutes: 60,
+	})
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected money drop in EUR to be rejected, got %v", err)
+	}
+}
+
+func TestHandleMessage_ParksEventWithMismatchedCurrency(t *testing.T) {
+	repo := newUnmatchedEventsRepoStub()
+	claim := pendingMoneyDropClaim()
+	claim.Currency = "NGN"
+	repo.transactions[claim.ID] = claim
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	body := []byte(`{"event_id":"evt-usd","event_type":"book.transfer.successful","status":"successful","anchor_transfer_id":"atr_usd","amount":2500,"currency":"USD","reason":"` + buildMoneyDropClaimTransferReason(claim.ID, "") + `"}`)
+	if !consumer.HandleMessage(body) {
+		t.Fatal("expected mismatched event to be parked and acked")
+	}
+
+	if repo.metadataUpdates != 0 {
+		t.Fatalf("expected the event not to be applied, got %d metadata updates", repo.metadataUpdates)
+	}
+	if len(repo.parked) != 1 {
+		t.Fatalf("expected one parked event, got %d", len(repo.parked))
+	}
+	for _, parked := range repo.parked {
+		if !strings.Contains(parked.LastError, "event=USD transaction=NGN") {
+			t.Fatalf("expected currency mismatch as the parked error, got %q", parked.LastError)
+		}
+	}
+	if consumer.UnmatchedEvents() != 1 {
+		t.Fatalf("expected unmatched counter 1, got %d", consumer.UnmatchedEvents())
+	}
+}
+
+func TestHandleMessage_AppliesEventInTransactionCurrency(t *testing.T) {
+	repo := newUnmatchedEventsRepoStub()
+	claim := pendingMoneyDropClaim()
+	repo.transactions[claim.ID] = claim
+	consumer := NewTransferStatusConsumer(repo, nil)
+
+	// A transaction loaded without a currency predates the column and is in NGN.
+	body := []byte(`{"event_type":"book.transfer.processing","status":"processing","anchor_transfer_id":"atr_ngn","amount":2500,"currency":"ngn","reason":"` + buildMoneyDropClaimTransferReason(claim.ID, "") + `"}`)
+	if !consumer.HandleMessage(body) {
+		t.Fatal("expected event to be acked")
+	}
+	if repo.metadataUpdates != 1 || len(repo.parked) != 0 {
+		t.Fatalf("expected the event to be applied, got %d metadata updates and %d parked", repo.metadataUpdates, len(repo.parked))
+	}
+}
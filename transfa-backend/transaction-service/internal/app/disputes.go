@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+var (
+	disputeReasons  = map[string]bool{"unauthorized": true, "wrong_amount": true, "not_received": true, "other": true}
+	disputeStatuses = map[string]bool{"open": true, "investigating": true, "resolved": true, "rejected": true}
+
+	// disputeTransitions lists the statuses each status may move to. Resolved and
+	// rejected disputes are closed.
+	disputeTransitions = map[string]map[string]bool{
+		"open":          {"investigating": true, "resolved": true, "rejected": true},
+		"investigating": {"resolved": true, "rejected": true},
+	}
+)
+
+// CreateTransactionDispute flags a completed or failed transaction the caller sent or
+// received for review and publishes dispute.created. A transaction can have only one
+// open or investigating dispute at a time.
+func (s *Service) CreateTransactionDispute(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, payload domain.CreateTransactionDisputePayload) (*domain.TransactionDispute, error) {
+	reason := strings.ToLower(strings.TrimSpace(payload.Reason))
+	if !disputeReasons[reason] {
+		return nil, ErrInvalidDisputeReason
+	}
+	var description *string
+	if trimmed := strings.TrimSpace(payload.Description); trimmed != "" {
+		if utf8.RuneCountInString(trimmed) > maxDisputeDescriptionLen {
+			return nil, ErrInvalidDisputeDescription
+		}
+		description = &trimmed
+	}
+
+	tx, err := s.GetTransactionByID(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status != "completed" && tx.Status != "failed" {
+		return nil, ErrTransactionNotDisputable
+	}
+
+	dispute, err := s.repo.CreateTransactionDispute(ctx, domain.TransactionDispute{
+		TransactionID: transactionID,
+		UserID:        userID,
+		Reason:        reason,
+		Description:   description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.eventProducer != nil {
+		if err := s.eventProducer.Publish(ctx, "transfa.events", "dispute.created", dispute); err != nil {
+			log.Printf("level=warn component=service flow=transaction_dispute msg=\"failed to publish dispute.created\" dispute_id=%s err=%v", dispute.ID, err)
+		}
+	}
+
+	return dispute, nil
+}
+
+// ListTransactionDisputes returns disputes for the internal review queue.
+func (s *Service) ListTransactionDisputes(ctx context.Context, filter domain.TransactionDisputeFilter) ([]domain.TransactionDispute, error) {
+	filter.Status = strings.ToLower(strings.TrimSpace(filter.Status))
+	if filter.Status != "" && !disputeStatuses[filter.Status] {
+		return nil, ErrInvalidDisputeStatus
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDisputesLimit
+	}
+	if filter.Limit > maxDisputesLimit {
+		filter.Limit = maxDisputesLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListTransactionDisputes(ctx, filter)
+}
+
+// ListUserTransactionDisputes returns the disputes the caller has raised, newest first.
+func (s *Service) ListUserTransactionDisputes(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.TransactionDispute, error) {
+	if limit <= 0 {
+		limit = defaultDisputesLimit
+	}
+	if limit > maxDisputesLimit {
+		limit = maxDisputesLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.ListTransactionDisputesByUser(ctx, userID, limit, offset)
+}
+
+// UpdateTransactionDisputeStatus moves a dispute along open -> investigating ->
+// resolved/rejected and publishes dispute.status_changed. Closing a dispute requires
+// a resolution note, which is shown to the user.
+func (s *Service) UpdateTransactionDisputeStatus(ctx context.Context, disputeID uuid.UUID, payload domain.UpdateTransactionDisputeStatusPayload) (*domain.TransactionDispute, error) {
+	status := strings.ToLower(strings.TrimSpace(payload.Status))
+	if !disputeStatuses[status] {
+		return nil, ErrInvalidDisputeStatus
+	}
+	var note *string
+	if trimmed := strings.TrimSpace(payload.ResolutionNote); trimmed != "" {
+		if utf8.RuneCountInString(trimmed) > maxDisputeDescriptionLen {
+			return nil, ErrInvalidDisputeResolutionNote
+		}
+		note = &trimmed
+	}
+	if (status == "resolved" || status == "rejected") && note == nil {
+		return nil, ErrDisputeResolutionNoteRequired
+	}
+
+	current, err := s.repo.FindTransactionDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if !disputeTransitions[current.Status][status] {
+		return nil, ErrInvalidDisputeTransition
+	}
+
+	updated, err := s.repo.UpdateTransactionDisputeStatus(ctx, disputeID, current.Status, status, note)
+	if err != nil {
+		if errors.Is(err, store.ErrTransactionDisputeStatusChanged) {
+			return nil, ErrInvalidDisputeTransition
+		}
+		return nil, err
+	}
+
+	if s.eventProducer != nil {
+		event := domain.TransactionDisputeStatusChangedEvent{
+			DisputeID:      updated.ID,
+			TransactionID:  updated.TransactionID,
+			UserID:         updated.UserID,
+			PreviousStatus: current.Status,
+			Status:         updated.Status,
+			ResolutionNote: updated.ResolutionNote,
+		}
+		if err := s.eventProducer.Publish(ctx, "transfa.events", "dispute.status_changed", event); err != nil {
+			log.Printf("level=warn component=service flow=transaction_dispute msg=\"failed to publish dispute.status_changed\" dispute_id=%s status=%s err=%v", updated.ID, updated.Status, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// GetTransactionDetail returns a transaction the caller took part in together with
+// a summary of the caller's latest dispute on it, if any, and the caller's own note
+// and attachments.
+func (s *Service) GetTransactionDetail(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*domain.Transaction, error) {
+	tx, err := s.GetTransactionByID(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	// The routing reason describes the recipient's preference and eligibility, so
+	// only the recipient sees it.
+	if tx.RecipientID == nil || *tx.RecipientID != userID {
+		tx.RoutingReason = ""
+	}
+
+	dispute, err := s.repo.FindLatestTransactionDisputeByUser(ctx, transactionID, userID)
+	switch {
+	case err == nil:
+		tx.Dispute = &domain.TransactionDisputeSummary{
+			ID:             dispute.ID,
+			Reason:         dispute.Reason,
+			Status:         dispute.Status,
+			ResolutionNote: dispute.ResolutionNote,
+			CreatedAt:      dispute.CreatedAt,
+			UpdatedAt:      dispute.UpdatedAt,
+		}
+	case !errors.Is(err, store.ErrTransactionDisputeNotFound):
+		// The transaction itself loaded fine; serve it without the summary.
+		log.Printf("level=warn component=service flow=transaction_detail msg=\"dispute lookup failed\" transaction_id=%s user_id=%s err=%v", transactionID, userID, err)
+	}
+
+	annotated := []domain.Transaction{*tx}
+	s.annotateTransactions(ctx, userID, annotated)
+	return &annotated[0], nil
+}
@@ -0,0 +1,318 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// disputesRepoStub serves one transaction and allows one open or investigating
+// dispute per transaction like the partial unique index on transaction_disputes.
+type disputesRepoStub struct {
+	store.Repository
+
+	transaction domain.Transaction
+	disputes    map[uuid.UUID]domain.TransactionDispute
+}
+
+func (s *disputesRepoStub) FindTransactionByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	if id != s.transaction.ID {
+		return nil, store.ErrTransactionNotFound
+	}
+	tx := s.transaction
+	return &tx, nil
+}
+
+func (s *disputesRepoStub) CreateTransactionDispute(ctx context.Context, dispute domain.TransactionDispute) (*domain.TransactionDispute, error) {
+	for _, existing := range s.disputes {
+		if existing.TransactionID == dispute.TransactionID && (existing.Status == "open" || existing.Status == "investigating") {
+			return nil, store.ErrTransactionDisputeExists
+		}
+	}
+	dispute.ID = uuid.New()
+	dispute.Status = "open"
+	dispute.CreatedAt = time.Now()
+	s.disputes[dispute.ID] = dispute
+	return &dispute, nil
+}
+
+func (s *disputesRepoStub) FindTransactionDisputeByID(ctx context.Context, disputeID uuid.UUID) (*domain.TransactionDispute, error) {
+	dispute, ok := s.disputes[disputeID]
+	if !ok {
+		return nil, store.ErrTransactionDisputeNotFound
+	}
+	return &dispute, nil
+}
+
+func (s *disputesRepoStub) FindLatestTransactionDisputeByUser(ctx context.Context, transactionID, userID uuid.UUID) (*domain.TransactionDispute, error) {
+	var latest *domain.TransactionDispute
+	for _, dispute := range s.disputes {
+		if dispute.TransactionID == transactionID && dispute.UserID == userID && (latest == nil || dispute.CreatedAt.After(latest.CreatedAt)) {
+			item := dispute
+			latest = &item
+		}
+	}
+	if latest == nil {
+		return nil, store.ErrTransactionDisputeNotFound
+	}
+	return latest, nil
+}
+
+func (s *disputesRepoStub) UpdateTransactionDisputeStatus(ctx context.Context, disputeID uuid.UUID, fromStatus, toStatus string, resolutionNote *string) (*domain.TransactionDispute, error) {
+	dispute, ok := s.disputes[disputeID]
+	if !ok || dispute.Status != fromStatus {
+		return nil, store.ErrTransactionDisputeStatusChanged
+	}
+	dispute.Status = toStatus
+	if resolutionNote != nil {
+		dispute.ResolutionNote = resolutionNote
+	}
+	s.disputes[disputeID] = dispute
+	return &dispute, nil
+}
+
+func (s *disputesRepoStub) FindTransactionNotes(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	return map[uuid.UUID]string{}, nil
+}
+
+func (s *disputesRepoStub) FindTransactionAttachments(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID][]domain.TransactionAttachment, error) {
+	return map[uuid.UUID][]domain.TransactionAttachment{}, nil
+}
+
+func newDisputesTestService(status string) (*Service, *disputesRepoStub, *recordingPublisher, uuid.UUID) {
+	senderID := uuid.New()
+	repo := &disputesRepoStub{
+		transaction: domain.Transaction{ID: uuid.New(), SenderID: senderID, Status: status, Amount: 150000},
+		disputes:    map[uuid.UUID]domain.TransactionDispute{},
+	}
+	publisher := &recordingPublisher{}
+	return &Service{repo: repo, eventProducer: publisher}, repo, publisher, senderID
+}
+
+func TestCreateTransactionDispute_PublishesDisputeCreated(t *testing.T) {
+	svc, repo, publisher, senderID := newDisputesTestService("completed")
+
+	dispute, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, domain.CreateTransactionDisputePayload{
+		Reason:      " Not_Received ",
+		Description: "Recipient says the money never arrived",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dispute.Reason != "not_received" || dispute.Status != "open" {
+		t.Fatalf("expected open not_received dispute, got %+v", dispute)
+	}
+
+	events := publisher.find("dispute.created")
+	if len(events) != 1 {
+		t.Fatalf("expected 1 dispute.created event, got %d", len(events))
+	}
+	if events[0].exchange != "transfa.events" {
+		t.Fatalf("expected transfa.events exchange, got %q", events[0].exchange)
+	}
+	body, ok := events[0].body.(*domain.TransactionDispute)
+	if !ok || body.ID != dispute.ID || body.TransactionID != repo.transaction.ID {
+		t.Fatalf("unexpected event body: %#v", events[0].body)
+	}
+}
+
+func TestCreateTransactionDispute_RejectsDuplicate(t *testing.T) {
+	svc, repo, publisher, senderID := newDisputesTestService("failed")
+	payload := domain.CreateTransactionDisputePayload{Reason: "wrong_amount"}
+
+	if _, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, payload); err != nil {
+		t.Fatalf("unexpected error on first dispute: %v", err)
+	}
+	_, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, payload)
+	if !errors.Is(err, store.ErrTransactionDisputeExists) {
+		t.Fatalf("expected ErrTransactionDisputeExists, got %v", err)
+	}
+	if got := len(publisher.find("dispute.created")); got != 1 {
+		t.Fatalf("expected only the first dispute to be published, got %d events", got)
+	}
+}
+
+func TestCreateTransactionDispute_ValidatesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		payload domain.CreateTransactionDisputePayload
+		caller  func(senderID uuid.UUID) uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "invalid reason",
+			status:  "completed",
+			payload: domain.CreateTransactionDisputePayload{Reason: "changed_my_mind"},
+			wantErr: ErrInvalidDisputeReason,
+		},
+		{
+			name:    "pending transaction",
+			status:  "pending",
+			payload: domain.CreateTransactionDisputePayload{Reason: "unauthorized"},
+			wantErr: ErrTransactionNotDisputable,
+		},
+		{
+			name:    "transaction of another user",
+			status:  "completed",
+			payload: domain.CreateTransactionDisputePayload{Reason: "unauthorized"},
+			caller:  func(uuid.UUID) uuid.UUID { return uuid.New() },
+			wantErr: store.ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, publisher, senderID := newDisputesTestService(tt.status)
+			callerID := senderID
+			if tt.caller != nil {
+				callerID = tt.caller(senderID)
+			}
+
+			_, err := svc.CreateTransactionDispute(context.Background(), callerID, repo.transaction.ID, tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(repo.disputes) != 0 || len(publisher.events) != 0 {
+				t.Fatalf("expected no dispute or event, got %d disputes and %d events", len(repo.disputes), len(publisher.events))
+			}
+		})
+	}
+}
+
+func TestCreateTransactionDispute_AllowsNewDisputeOnceClosed(t *testing.T) {
+	svc, repo, _, senderID := newDisputesTestService("completed")
+	payload := domain.CreateTransactionDisputePayload{Reason: "not_received"}
+
+	first, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, payload)
+	if err != nil {
+		t.Fatalf("unexpected error on first dispute: %v", err)
+	}
+	if _, err := svc.UpdateTransactionDisputeStatus(context.Background(), first.ID, domain.UpdateTransactionDisputeStatusPayload{
+		Status:         "rejected",
+		ResolutionNote: "Recipient was credited on the same day.",
+	}); err != nil {
+		t.Fatalf("unexpected error closing dispute: %v", err)
+	}
+
+	if _, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, payload); err != nil {
+		t.Fatalf("expected a new dispute after the first was closed, got %v", err)
+	}
+}
+
+func TestUpdateTransactionDisputeStatus_WalksWorkflowAndPublishesEvents(t *testing.T) {
+	svc, repo, publisher, senderID := newDisputesTestService("completed")
+	dispute, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, domain.CreateTransactionDisputePayload{Reason: "wrong_amount"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps := []domain.UpdateTransactionDisputeStatusPayload{
+		{Status: "Investigating"},
+		{Status: "resolved", ResolutionNote: "  Difference refunded to your wallet.  "},
+	}
+	for _, step := range steps {
+		if _, err := svc.UpdateTransactionDisputeStatus(context.Background(), dispute.ID, step); err != nil {
+			t.Fatalf("unexpected error moving to %s: %v", step.Status, err)
+		}
+	}
+
+	events := publisher.find("dispute.status_changed")
+	if len(events) != 2 {
+		t.Fatalf("expected 2 dispute.status_changed events, got %d", len(events))
+	}
+	first := events[0].body.(domain.TransactionDisputeStatusChangedEvent)
+	if first.PreviousStatus != "open" || first.Status != "investigating" || first.UserID != senderID {
+		t.Fatalf("unexpected first event: %+v", first)
+	}
+	last := events[1].body.(domain.TransactionDisputeStatusChangedEvent)
+	if last.PreviousStatus != "investigating" || last.Status != "resolved" {
+		t.Fatalf("unexpected last event: %+v", last)
+	}
+	if last.ResolutionNote == nil || *last.ResolutionNote != "Difference refunded to your wallet." {
+		t.Fatalf("expected trimmed resolution note on the event, got %v", last.ResolutionNote)
+	}
+}
+
+func TestUpdateTransactionDisputeStatus_RejectsInvalidChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		payload domain.UpdateTransactionDisputeStatusPayload
+		wantErr error
+	}{
+		{name: "unknown status", from: "open", payload: domain.UpdateTransactionDisputeStatusPayload{Status: "escalated"}, wantErr: ErrInvalidDisputeStatus},
+		{name: "missing note", from: "investigating", payload: domain.UpdateTransactionDisputeStatusPayload{Status: "rejected"}, wantErr: ErrDisputeResolutionNoteRequired},
+		{name: "back to open", from: "investigating", payload: domain.UpdateTransactionDisputeStatusPayload{Status: "open"}, wantErr: ErrInvalidDisputeTransition},
+		{name: "reopen closed", from: "resolved", payload: domain.UpdateTransactionDisputeStatusPayload{Status: "investigating"}, wantErr: ErrInvalidDisputeTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, publisher, senderID := newDisputesTestService("completed")
+			disputeID := uuid.New()
+			repo.disputes[disputeID] = domain.TransactionDispute{ID: disputeID, TransactionID: repo.transaction.ID, UserID: senderID, Status: tt.from}
+
+			_, err := svc.UpdateTransactionDisputeStatus(context.Background(), disputeID, tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.disputes[disputeID].Status != tt.from || len(publisher.events) != 0 {
+				t.Fatalf("expected dispute to stay %s without events", tt.from)
+			}
+		})
+	}
+}
+
+func TestGetTransactionDetail_IncludesCallersDispute(t *testing.T) {
+	svc, repo, _, senderID := newDisputesTestService("completed")
+
+	tx, err := svc.GetTransactionDetail(context.Background(), senderID, repo.transaction.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.Dispute != nil {
+		t.Fatalf("expected no dispute summary before a dispute is raised, got %+v", tx.Dispute)
+	}
+
+	dispute, err := svc.CreateTransactionDispute(context.Background(), senderID, repo.transaction.ID, domain.CreateTransactionDisputePayload{Reason: "unauthorized"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tx, err = svc.GetTransactionDetail(context.Background(), senderID, repo.transaction.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.Dispute == nil || tx.Dispute.ID != dispute.ID || tx.Dispute.Status != "open" || tx.Dispute.Reason != "unauthorized" {
+		t.Fatalf("expected open dispute summary, got %+v", tx.Dispute)
+	}
+}
+
+func TestGetTransactionDetail_ShowsRoutingReasonToRecipientOnly(t *testing.T) {
+	svc, repo, _, senderID := newDisputesTestService("completed")
+	recipientID := uuid.New()
+	repo.transaction.RecipientID = &recipientID
+	repo.transaction.RoutingDecision = domain.RoutingInternal
+	repo.transaction.RoutingReason = domain.RoutingReasonNotEligible
+
+	tx, err := svc.GetTransactionDetail(context.Background(), recipientID, repo.transaction.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.RoutingReason != domain.RoutingReasonNotEligible {
+		t.Fatalf("expected the recipient to see the routing reason, got %q", tx.RoutingReason)
+	}
+
+	tx, err = svc.GetTransactionDetail(context.Background(), senderID, repo.transaction.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.RoutingReason != "" || tx.RoutingDecision != domain.RoutingInternal {
+		t.Fatalf("expected the sender to see the decision without the reason, got decision=%q reason=%q", tx.RoutingDecision, tx.RoutingReason)
+	}
+}
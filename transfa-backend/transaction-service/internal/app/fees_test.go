@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+func feeScheduleByName(schedules []domain.FeeSchedule, name string) (domain.FeeSchedule, bool) {
+	for _, schedule := range schedules {
+		if schedule.Name == name {
+			return schedule, true
+		}
+	}
+	return domain.FeeSchedule{}, false
+}
+
+func TestGetFeeSchedules(t *testing.T) {
+	svc := &Service{transactionFeeKobo: 500, moneyDropFeeKobo: 1000, moneyDropFeePercent: 1.5}
+
+	schedules := svc.GetFeeSchedules()
+	want := map[string]int64{"p2p_transfer": 500, "self_transfer": 500, "money_drop_creation": 1000}
+	if len(schedules) != len(want) {
+		t.Fatalf("expected %d schedules, got %+v", len(want), schedules)
+	}
+	for name, amount := range want {
+		schedule, ok := feeScheduleByName(schedules, name)
+		if !ok {
+			t.Fatalf("missing schedule %s", name)
+		}
+		if schedule.AmountKobo != amount || schedule.AmountKobo < 0 {
+			t.Fatalf("schedule %s: expected %d kobo, got %d", name, amount, schedule.AmountKobo)
+		}
+		if schedule.Description == "" || schedule.AppliesTo == "" {
+			t.Fatalf("schedule %s is missing description or applies_to: %+v", name, schedule)
+		}
+	}
+	if drop, _ := feeScheduleByName(schedules, "money_drop_creation"); drop.Percent != 1.5 {
+		t.Fatalf("expected money drop percent 1.5, got %v", drop.Percent)
+	}
+}
+
+func TestGetFeeSchedules_ReflectsConfiguredFees(t *testing.T) {
+	svc := &Service{transactionFeeKobo: 500, moneyDropFeeKobo: 1000}
+	before, _ := feeScheduleByName(svc.GetFeeSchedules(), "money_drop_creation")
+
+	svc.moneyDropFeeKobo = 2500
+	after, _ := feeScheduleByName(svc.GetFeeSchedules(), "money_drop_creation")
+
+	if before.AmountKobo != 1000 || after.AmountKobo != 2500 {
+		t.Fatalf("expected money drop fee to follow config, got before=%d after=%d", before.AmountKobo, after.AmountKobo)
+	}
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type claimedHistoryClaim struct {
+	claimantID uuid.UUID
+	item       domain.ClaimedMoneyDropHistoryItem
+}
+
+// claimedHistoryRepoStub serves claims across several drops, filtered and ordered the
+// way the query does.
+type claimedHistoryRepoStub struct {
+	store.Repository
+
+	claims    []claimedHistoryClaim
+	lastLimit int
+}
+
+func (s *claimedHistoryRepoStub) ListClaimedMoneyDropsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.ClaimedMoneyDropHistoryItem, error) {
+	s.lastLimit = limit
+	var items []domain.ClaimedMoneyDropHistoryItem
+	for _, claim := range s.claims {
+		if claim.claimantID == userID {
+			items = append(items, claim.item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ClaimedAt.After(items[j].ClaimedAt) })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func TestGetClaimedMoneyDropHistory_ReturnsClaimsAcrossDropsNewestFirst(t *testing.T) {
+	claimantID := uuid.New()
+	now := time.Now().UTC()
+	older := domain.ClaimedMoneyDropHistoryItem{DropID: uuid.New(), Title: "Lunch", CreatorUsername: "ada", AmountClaimed: 1000, ClaimedAt: now.Add(-2 * time.Hour), DropStatus: "completed"}
+	newer := domain.ClaimedMoneyDropHistoryItem{DropID: uuid.New(), Title: "Birthday", CreatorUsername: "tunde", AmountClaimed: 2500, ClaimedAt: now.Add(-time.Hour), DropStatus: "active"}
+	repo := &claimedHistoryRepoStub{claims: []claimedHistoryClaim{
+		{claimantID: claimantID, item: older},
+		{claimantID: uuid.New(), item: domain.ClaimedMoneyDropHistoryItem{DropID: uuid.New(), ClaimedAt: now}},
+		{claimantID: claimantID, item: newer},
+	}}
+	svc := &Service{repo: repo}
+
+	history, err := svc.GetClaimedMoneyDropHistory(context.Background(), claimantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.lastLimit != claimedMoneyDropHistoryLimit {
+		t.Fatalf("expected limit %d, got %d", claimedMoneyDropHistoryLimit, repo.lastLimit)
+	}
+	if len(history.Items) != 2 {
+		t.Fatalf("expected the claimant's two claims, got %d", len(history.Items))
+	}
+	if history.Items[0].DropID != newer.DropID || history.Items[1].DropID != older.DropID {
+		t.Fatalf("expected newest claim first, got %+v", history.Items)
+	}
+	if history.Items[0].CreatorUsername != "tunde" || history.Items[0].AmountClaimed != 2500 {
+		t.Fatalf("unexpected item %+v", history.Items[0])
+	}
+}
+
+func TestGetClaimedMoneyDropHistory_EmptyForUserWithoutClaims(t *testing.T) {
+	repo := &claimedHistoryRepoStub{}
+	svc := &Service{repo: repo}
+
+	history, err := svc.GetClaimedMoneyDropHistory(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.Items == nil || len(history.Items) != 0 {
+		t.Fatalf("expected an empty, non-nil list so it encodes as [], got %#v", history.Items)
+	}
+}
+
+func TestGetClaimedMoneyDropHistory_IncludesExpiredDrops(t *testing.T) {
+	claimantID := uuid.New()
+	expired := domain.ClaimedMoneyDropHistoryItem{DropID: uuid.New(), Title: "Weekend", CreatorUsername: "ada", AmountClaimed: 1000, ClaimedAt: time.Now().UTC().Add(-48 * time.Hour), DropStatus: "expired_and_refunded"}
+	repo := &claimedHistoryRepoStub{claims: []claimedHistoryClaim{{claimantID: claimantID, item: expired}}}
+	svc := &Service{repo: repo}
+
+	history, err := svc.GetClaimedMoneyDropHistory(context.Background(), claimantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Items) != 1 || history.Items[0].DropStatus != "expired_and_refunded" {
+		t.Fatalf("expected the expired drop in history, got %+v", history.Items)
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type moneyDropDashboardRepoStub struct {
+	store.Repository
+
+	account      *domain.Account
+	activeDrops  []domain.MoneyDrop
+	endedDrops   []domain.MoneyDrop
+	historyLimit int
+}
+
+func (s *moneyDropDashboardRepoStub) FindMoneyDropAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
+	if s.account == nil {
+		return nil, store.ErrAccountNotFound
+	}
+	account := *s.account
+	return &account, nil
+}
+
+func (s *moneyDropDashboardRepoStub) UpdateMoneyDropAccountBalance(ctx context.Context, accountID uuid.UUID, balance int64) error {
+	s.account.Balance = balance
+	return nil
+}
+
+func (s *moneyDropDashboardRepoStub) ListActiveMoneyDropsByCreator(ctx context.Context, creatorID uuid.UUID) ([]domain.MoneyDrop, error) {
+	return s.activeDrops, nil
+}
+
+func (s *moneyDropDashboardRepoStub) ListEndedMoneyDropsByCreator(ctx context.Context, creatorID uuid.UUID, limit int) ([]domain.MoneyDrop, error) {
+	s.historyLimit = limit
+	if len(s.endedDrops) > limit {
+		return s.endedDrops[:limit], nil
+	}
+	return s.endedDrops, nil
+}
+
+// newMoneyDropDashboardTestService serves an Anchor balance of 9000 kobo, or a 500 error
+// when anchorFails is set.
+func newMoneyDropDashboardTestService(t *testing.T, repo *moneyDropDashboardRepoStub, anchorFails bool) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if anchorFails {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"errors":[{"title":"Server Error","detail":"balance unavailable","status":"500"}]}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"availableBalance":9000,"ledgerBalance":9000,"hold":0,"pending":0}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Service{repo: repo, anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0)}
+}
+
+func TestGetMoneyDropDashboard_ReturnsActiveDropsWithRemainingSlots(t *testing.T) {
+	creatorID := uuid.New()
+	now := time.Now().UTC()
+	ended := make([]domain.MoneyDrop, 8)
+	for i := range ended {
+		ended[i] = domain.MoneyDrop{ID: uuid.New(), CreatorID: creatorID, Status: "completed", TotalClaimsAllowed: 2, ClaimsMadeCount: 2, ExpiryTimestamp: now.Add(-time.Hour), CreatedAt: now.Add(-2 * time.Hour)}
+	}
+	repo := &moneyDropDashboardRepoStub{
+		account: &domain.Account{ID: uuid.New(), UserID: creatorID, AnchorAccountID: "anc_drop", Balance: 4000},
+		activeDrops: []domain.MoneyDrop{
+			{ID: uuid.New(), CreatorID: creatorID, Title: "Lunch", Status: "active", TotalAmount: 5000, AmountPerClaim: 1000, TotalClaimsAllowed: 5, ClaimsMadeCount: 2, ExpiryTimestamp: now.Add(time.Hour), CreatedAt: now},
+		},
+		endedDrops: ended,
+	}
+	svc := newMoneyDropDashboardTestService(t, repo, false)
+
+	dashboard, err := svc.GetMoneyDropDashboard(context.Background(), creatorID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dashboard.CurrentBalance != 9000 {
+		t.Fatalf("expected Anchor balance 9000, got %d", dashboard.CurrentBalance)
+	}
+	if len(dashboard.ActiveDrops) != 1 {
+		t.Fatalf("expected 1 active drop, got %d", len(dashboard.ActiveDrops))
+	}
+	if got := dashboard.ActiveDrops[0].RemainingSlots; got != 3 {
+		t.Fatalf("expected 3 remaining slots, got %d", got)
+	}
+	if repo.historyLimit != moneyDropDashboardHistoryLimit || len(dashboard.DropHistory) != moneyDropDashboardHistoryLimit {
+		t.Fatalf("expected history limited to %d, got limit=%d len=%d", moneyDropDashboardHistoryLimit, repo.historyLimit, len(dashboard.DropHistory))
+	}
+}
+
+func TestGetMoneyDropDashboard_NoDrops(t *testing.T) {
+	creatorID := uuid.New()
+	repo := &moneyDropDashboardRepoStub{}
+	svc := newMoneyDropDashboardTestService(t, repo, false)
+
+	dashboard, err := svc.GetMoneyDropDashboard(context.Background(), creatorID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dashboard.CurrentBalance != 0 {
+		t.Fatalf("expected zero balance without a money drop account, got %d", dashboard.CurrentBalance)
+	}
+	if dashboard.ActiveDrops == nil || len(dashboard.ActiveDrops) != 0 {
+		t.Fatalf("expected empty active drops, got %#v", dashboard.ActiveDrops)
+	}
+	if dashboard.DropHistory == nil || len(dashboard.DropHistory) != 0 {
+		t.Fatalf("expected empty drop history, got %#v", dashboard.DropHistory)
+	}
+}
+
+func TestGetMoneyDropDashboard_AnchorFailureFallsBackToStoredBalance(t *testing.T) {
+	creatorID := uuid.New()
+	repo := &moneyDropDashboardRepoStub{
+		account: &domain.Account{ID: uuid.New(), UserID: creatorID, AnchorAccountID: "anc_drop", Balance: 4000},
+	}
+	svc := newMoneyDropDashboardTestService(t, repo, true)
+
+	dashboard, err := svc.GetMoneyDropDashboard(context.Background(), creatorID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dashboard.CurrentBalance != 4000 {
+		t.Fatalf("expected stored balance 4000, got %d", dashboard.CurrentBalance)
+	}
+	if repo.account.Balance != 4000 {
+		t.Fatalf("expected stored balance to be left unchanged, got %d", repo.account.Balance)
+	}
+}
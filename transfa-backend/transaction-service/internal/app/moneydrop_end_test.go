@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func TestEndMoneyDrop_RejectsOtherUsers(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(time.Hour))
+	svc := &Service{repo: repo}
+
+	_, err := svc.EndMoneyDrop(context.Background(), uuid.New(), repo.drop.ID)
+	if !errors.Is(err, store.ErrMoneyDropNotFound) {
+		t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+	}
+	if repo.finalized != "" {
+		t.Fatalf("expected another user's request not to end the drop, got %q", repo.finalized)
+	}
+}
+
+func TestEndMoneyDrop_RejectsEndedDrop(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(time.Hour))
+	repo.drop.Status = "expired_and_refunded"
+	svc := &Service{repo: repo}
+
+	_, err := svc.EndMoneyDrop(context.Background(), repo.drop.CreatorID, repo.drop.ID)
+	if !errors.Is(err, ErrMoneyDropEndNotAllowed) {
+		t.Fatalf("expected ErrMoneyDropEndNotAllowed, got %v", err)
+	}
+	if repo.finalized != "" {
+		t.Fatalf("expected an ended drop not to be finalized again, got %q", repo.finalized)
+	}
+}
+
+func TestEndMoneyDrop_FullyClaimedDropEndsWithoutRefund(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(time.Hour))
+	repo.drop.ClaimsMadeCount = repo.drop.TotalClaimsAllowed
+	repo.drop.RefundedAmount = 0
+	// No Anchor client: a refund transfer would panic.
+	svc := &Service{repo: repo}
+
+	result, err := svc.EndMoneyDrop(context.Background(), repo.drop.CreatorID, repo.drop.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "completed" || result.RefundedAmount != 0 || result.RemainingBalance != 0 {
+		t.Fatalf("expected a completed drop with nothing refunded, got %+v", result)
+	}
+	if repo.finalized != "completed" {
+		t.Fatalf("expected the drop to be finalized as completed, got %q", repo.finalized)
+	}
+}
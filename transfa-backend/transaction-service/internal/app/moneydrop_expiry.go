@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const (
+	defaultExpiredMoneyDropsLimit = 100
+	maxExpiredMoneyDropsLimit     = 500
+)
+
+// ExpireMoneyDrops finalizes every active drop past its expiry or fully claimed, plus
+// completed drops whose refund is stuck in a retryable state, refunding what is left
+// to the creator. Each drop goes through finalizeMoneyDropWithRefund, so a drop that
+// another caller is already finalizing is left to that caller. A failure on one drop
+// is recorded in the summary and does not stop the batch.
+func (s *Service) ExpireMoneyDrops(ctx context.Context) (*domain.MoneyDropExpiryResponse, error) {
+	drops, err := s.repo.FindExpiredAndCompletedMoneyDrops(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list money drops to expire: %w", err)
+	}
+
+	result := &domain.MoneyDropExpiryResponse{
+		Processed: len(drops),
+		Failures:  []domain.MoneyDropExpiryFailure{},
+	}
+	for _, drop := range drops {
+		status, refundedAmount, remaining, err := s.finalizeMoneyDropWithRefund(ctx, drop.ID, drop.CreatorID, "expired")
+		if err != nil {
+			result.Failed++
+			result.Failures = append(result.Failures, domain.MoneyDropExpiryFailure{DropID: drop.ID, Error: err.Error()})
+			log.Printf("level=error component=service flow=money_drop_expiry msg=\"failed to finalize money drop\" money_drop_id=%s creator_id=%s err=%v", drop.ID, drop.CreatorID, err)
+			continue
+		}
+
+		result.Expired++
+		result.RefundedAmount += refundedAmount
+		log.Printf("level=info component=service flow=money_drop_expiry msg=\"money drop finalized\" money_drop_id=%s creator_id=%s status=%s refunded_amount=%d outstanding=%d", drop.ID, drop.CreatorID, status, refundedAmount, remaining)
+	}
+
+	return result, nil
+}
+
+// ListExpiredActiveMoneyDrops lists active drops past their expiry that have not been
+// finalized, oldest first. A non-positive limit uses the default; larger limits are
+// capped at maxExpiredMoneyDropsLimit.
+func (s *Service) ListExpiredActiveMoneyDrops(ctx context.Context, limit int) ([]domain.ExpiredMoneyDrop, error) {
+	if limit <= 0 {
+		limit = defaultExpiredMoneyDropsLimit
+	}
+	if limit > maxExpiredMoneyDropsLimit {
+		limit = maxExpiredMoneyDropsLimit
+	}
+	drops, err := s.repo.ListExpiredActiveMoneyDrops(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired money drops: %w", err)
+	}
+	if drops == nil {
+		drops = []domain.ExpiredMoneyDrop{}
+	}
+	return drops, nil
+}
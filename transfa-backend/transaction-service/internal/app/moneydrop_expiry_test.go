@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// expiryRepoStub serves fully claimed drops so finalization needs no payout. Drops in
+// lockErr fail to lock and drops in lockedElsewhere are held by another caller.
+type expiryRepoStub struct {
+	store.Repository
+
+	drops           []domain.MoneyDrop
+	lockErr         map[uuid.UUID]error
+	lockedElsewhere map[uuid.UUID]bool
+	finalized       map[uuid.UUID]string
+}
+
+func (s *expiryRepoStub) FindExpiredAndCompletedMoneyDrops(ctx context.Context) ([]domain.MoneyDrop, error) {
+	return s.drops, nil
+}
+
+func (s *expiryRepoStub) AcquireMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID) (bool, bool, error) {
+	if err := s.lockErr[dropID]; err != nil {
+		return false, false, err
+	}
+	return !s.lockedElsewhere[dropID], true, nil
+}
+
+func (s *expiryRepoStub) FindMoneyDropByID(ctx context.Context, dropID uuid.UUID) (*domain.MoneyDrop, error) {
+	for i := range s.drops {
+		if s.drops[i].ID == dropID {
+			drop := s.drops[i]
+			return &drop, nil
+		}
+	}
+	return nil, store.ErrMoneyDropNotFound
+}
+
+func (s *expiryRepoStub) UpdateMoneyDropEndMetadata(ctx context.Context, dropID uuid.UUID, status string, endedReason string, endedAt time.Time) error {
+	s.finalized[dropID] = status
+	return nil
+}
+
+func (s *expiryRepoStub) ReleaseMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID, restoreActive bool) error {
+	return nil
+}
+
+func newExpiryTestDrop() domain.MoneyDrop {
+	return domain.MoneyDrop{
+		ID:                 uuid.New(),
+		CreatorID:          uuid.New(),
+		Status:             "active",
+		TotalAmount:        10000,
+		AmountPerClaim:     1000,
+		TotalClaimsAllowed: 10,
+		ClaimsMadeCount:    10,
+	}
+}
+
+func TestExpireMoneyDrops_ContinuesPastFailedDrops(t *testing.T) {
+	failing, finalized, lockedElsewhere := newExpiryTestDrop(), newExpiryTestDrop(), newExpiryTestDrop()
+	repo := &expiryRepoStub{
+		drops:           []domain.MoneyDrop{failing, finalized, lockedElsewhere},
+		lockErr:         map[uuid.UUID]error{failing.ID: errors.New("db unavailable")},
+		lockedElsewhere: map[uuid.UUID]bool{lockedElsewhere.ID: true},
+		finalized:       map[uuid.UUID]string{},
+	}
+	svc := &Service{repo: repo}
+
+	result, err := svc.ExpireMoneyDrops(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Processed != 3 || result.Expired != 2 || result.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", result)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].DropID != failing.ID {
+		t.Fatalf("expected the failing drop to be reported, got %+v", result.Failures)
+	}
+	if repo.finalized[finalized.ID] != "completed" {
+		t.Fatalf("expected fully claimed drop to be completed, got %q", repo.finalized[finalized.ID])
+	}
+	if _, ok := repo.finalized[lockedElsewhere.ID]; ok {
+		t.Fatal("expected a drop locked by another caller to be left alone")
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// ownerDetailsRepoStub serves one drop to its creator along with its claimers and the
+// creator's money drop account. Finalizing the drop records the status it ended with.
+type ownerDetailsRepoStub struct {
+	moneyDropDashboardRepoStub
+
+	drop          domain.MoneyDrop
+	claimers      []domain.MoneyDropClaimer
+	claimersLimit int
+	finalized     string
+}
+
+func (s *ownerDetailsRepoStub) FindMoneyDropByIDAndCreatorID(ctx context.Context, dropID, creatorID uuid.UUID) (*domain.MoneyDrop, error) {
+	if dropID != s.drop.ID || creatorID != s.drop.CreatorID {
+		return nil, store.ErrMoneyDropNotFound
+	}
+	drop := s.drop
+	return &drop, nil
+}
+
+func (s *ownerDetailsRepoStub) FindMoneyDropByID(ctx context.Context, dropID uuid.UUID) (*domain.MoneyDrop, error) {
+	drop := s.drop
+	return &drop, nil
+}
+
+func (s *ownerDetailsRepoStub) ListMoneyDropClaimsByDropID(ctx context.Context, dropID uuid.UUID, search string, limit int, offset int) ([]domain.MoneyDropClaimer, int, error) {
+	s.claimersLimit = limit
+	if len(s.claimers) > limit {
+		return s.claimers[:limit], len(s.claimers), nil
+	}
+	return s.claimers, len(s.claimers), nil
+}
+
+func (s *ownerDetailsRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: userID, Username: "ada"}, nil
+}
+
+func (s *ownerDetailsRepoStub) AcquireMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID) (bool, bool, error) {
+	return true, true, nil
+}
+
+func (s *ownerDetailsRepoStub) ReleaseMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID, restoreActive bool) error {
+	return nil
+}
+
+func (s *ownerDetailsRepoStub) UpdateMoneyDropEndMetadata(ctx context.Context, dropID uuid.UUID, status string, endedReason string, endedAt time.Time) error {
+	s.finalized = status
+	s.drop.Status = status
+	s.drop.EndedReason = &endedReason
+	s.drop.EndedAt = &endedAt
+	return nil
+}
+
+func newOwnerDetailsRepoStub(expiry time.Time) *ownerDetailsRepoStub {
+	creatorID := uuid.New()
+	claimers := make([]domain.MoneyDropClaimer, 4)
+	for i := range claimers {
+		claimers[i] = domain.MoneyDropClaimer{UserID: uuid.New(), Username: "claimer", AmountClaimed: 1000}
+	}
+	return &ownerDetailsRepoStub{
+		moneyDropDashboardRepoStub: moneyDropDashboardRepoStub{
+			account: &domain.Account{ID: uuid.New(), UserID: creatorID, AnchorAccountID: "anc_drop", Balance: 4000},
+		},
+		drop: domain.MoneyDrop{
+			ID:                 uuid.New(),
+			CreatorID:          creatorID,
+			Title:              "Lunch",
+			Status:             "active",
+			TotalAmount:        5000,
+			AmountPerClaim:     1000,
+			TotalClaimsAllowed: 5,
+			ClaimsMadeCount:    4,
+			// The unclaimed share was already refunded, so finalizing needs no payout.
+			RefundedAmount:  1000,
+			ExpiryTimestamp: expiry,
+		},
+		claimers: claimers,
+	}
+}
+
+func TestGetMoneyDropOwnerDetails_ActiveDrop(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(time.Hour))
+	svc := newMoneyDropDashboardTestService(t, &repo.moneyDropDashboardRepoStub, false)
+	svc.repo = repo
+
+	details, err := svc.GetMoneyDropOwnerDetails(context.Background(), repo.drop.CreatorID, repo.drop.ID, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if details.Status != "active" || details.StatusLabel != "Live" || !details.CanEndDrop {
+		t.Fatalf("expected a live drop the owner can end, got status=%q label=%q can_end=%v", details.Status, details.StatusLabel, details.CanEndDrop)
+	}
+	if repo.finalized != "" {
+		t.Fatalf("expected an unexpired drop not to be finalized, got %q", repo.finalized)
+	}
+	if details.CurrentBalance != 9000 {
+		t.Fatalf("expected the balance synced from Anchor, got %d", details.CurrentBalance)
+	}
+	if len(details.Claimers) != 2 || !details.ClaimersHasMore {
+		t.Fatalf("expected two claimers with more available, got %d (has_more=%v)", len(details.Claimers), details.ClaimersHasMore)
+	}
+}
+
+func TestGetMoneyDropOwnerDetails_FinalizesExpiredDrop(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(-time.Minute))
+	svc := newMoneyDropDashboardTestService(t, &repo.moneyDropDashboardRepoStub, false)
+	svc.repo = repo
+
+	details, err := svc.GetMoneyDropOwnerDetails(context.Background(), repo.drop.CreatorID, repo.drop.ID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.finalized != "expired_and_refunded" {
+		t.Fatalf("expected the expired drop to be finalized, got %q", repo.finalized)
+	}
+	if details.Status != "expired_and_refunded" || details.StatusLabel != "Ended" || details.CanEndDrop {
+		t.Fatalf("expected an ended drop, got status=%q label=%q can_end=%v", details.Status, details.StatusLabel, details.CanEndDrop)
+	}
+	if details.EndedReason == nil || *details.EndedReason != "expired" {
+		t.Fatalf("expected ended reason expired, got %v", details.EndedReason)
+	}
+	if repo.claimersLimit != defaultMoneyDropOwnerClaimers || len(details.Claimers) != 4 || details.ClaimersHasMore {
+		t.Fatalf("expected every claimer under the default limit, got limit=%d claimers=%d has_more=%v", repo.claimersLimit, len(details.Claimers), details.ClaimersHasMore)
+	}
+}
+
+func TestGetMoneyDropOwnerDetails_CapsClaimersLimit(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(time.Hour))
+	svc := newMoneyDropDashboardTestService(t, &repo.moneyDropDashboardRepoStub, true)
+	svc.repo = repo
+
+	details, err := svc.GetMoneyDropOwnerDetails(context.Background(), repo.drop.CreatorID, repo.drop.ID, 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.claimersLimit != maxMoneyDropOwnerClaimers {
+		t.Fatalf("expected claimers limit capped at %d, got %d", maxMoneyDropOwnerClaimers, repo.claimersLimit)
+	}
+	if details.CurrentBalance != 4000 {
+		t.Fatalf("expected the stored balance when Anchor fails, got %d", details.CurrentBalance)
+	}
+}
+
+func TestGetMoneyDropOwnerDetails_RejectsOtherUsers(t *testing.T) {
+	repo := newOwnerDetailsRepoStub(time.Now().UTC().Add(-time.Minute))
+	svc := newMoneyDropDashboardTestService(t, &repo.moneyDropDashboardRepoStub, false)
+	svc.repo = repo
+
+	_, err := svc.GetMoneyDropOwnerDetails(context.Background(), uuid.New(), repo.drop.ID, 20)
+	if !errors.Is(err, store.ErrMoneyDropNotFound) {
+		t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+	}
+	if repo.finalized != "" {
+		t.Fatalf("expected another user's lookup not to finalize the drop, got %q", repo.finalized)
+	}
+}
@@ -0,0 +1,241 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+const (
+	defaultMoneyDropPayoutWorkers = 4
+	moneyDropPayoutQueueSize      = 256
+	moneyDropPayoutTimeout        = 30 * time.Second
+	moneyDropPayoutSweepInterval  = 30 * time.Second
+	moneyDropPayoutSweepLimit     = 100
+	// moneyDropPayoutRetryAfter is how long a claim must sit untouched before the sweep
+	// picks it up again: a queued claim the workers have not reached yet, one whose
+	// transfer outcome was ambiguous, or one left in flight by a stopped worker.
+	moneyDropPayoutRetryAfter = time.Minute
+	// moneyDropPayoutRetryWindow bounds automatic payout retries. Every retry sends the
+	// claim's original idempotency key, which Anchor honours for 48 hours; older claims
+	// are left for an operator to move to reconcile_retry_requested.
+	moneyDropPayoutRetryWindow = 24 * time.Hour
+)
+
+// enqueueMoneyDropPayout hands a recorded claim to the payout workers. It never
+// blocks: when the queue is full (or no workers run) the sweep picks the claim up.
+func (s *Service) enqueueMoneyDropPayout(claimTxID uuid.UUID) {
+	select {
+	case s.moneyDropPayouts <- claimTxID:
+	default:
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"payout queue full; claim left for sweep\" claim_transaction_id=%s", claimTxID)
+	}
+}
+
+// RunMoneyDropPayoutWorkers pays out recorded money drop claims with a pool of
+// workers fed by ClaimMoneyDrop and by a periodic sweep of payout_pending claims, so
+// claims queued before a restart are still paid. It blocks until ctx is cancelled
+// and the workers have finished their current payout.
+func (s *Service) RunMoneyDropPayoutWorkers(ctx context.Context, workers int) {
+	if workers <= 0 {
+		workers = defaultMoneyDropPayoutWorkers
+	}
+	log.Printf("level=info component=service flow=money_drop_payout msg=\"payout workers started\" workers=%d", workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case claimTxID := <-s.moneyDropPayouts:
+					s.payOutMoneyDropClaim(claimTxID)
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(moneyDropPayoutSweepInterval)
+	defer ticker.Stop()
+	for {
+		s.sweepMoneyDropPayouts(ctx)
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			log.Printf("level=info component=service flow=money_drop_payout msg=\"payout workers stopped\"")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) sweepMoneyDropPayouts(ctx context.Context) {
+	now := time.Now().UTC()
+	claimTxIDs, err := s.repo.ListPendingMoneyDropClaimPayouts(ctx, now.Add(-moneyDropPayoutRetryWindow), now.Add(-moneyDropPayoutRetryAfter), moneyDropPayoutSweepLimit)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("level=warn component=service flow=money_drop_payout msg=\"payout sweep failed\" err=%v", err)
+		}
+		return
+	}
+	for _, claimTxID := range claimTxIDs {
+		s.enqueueMoneyDropPayout(claimTxID)
+	}
+}
+
+// payOutMoneyDropClaim sends the book transfer for one claim. Outcomes follow the
+// claim state machine: an accepted transfer moves the claim to transfer_initiated, an
+// explicit Anchor rejection reverts the claim, and an ambiguous failure puts it back
+// to payout_pending for the sweep to retry with the same idempotency key.
+func (s *Service) payOutMoneyDropClaim(claimTxID uuid.UUID) {
+	// The payout must not be cut short by shutdown once the transfer is sent, so it
+	// runs on its own deadline rather than the worker context.
+	ctx, cancel := context.WithTimeout(context.Background(), moneyDropPayoutTimeout)
+	defer cancel()
+
+	payout, err := s.repo.FindMoneyDropClaimPayout(ctx, claimTxID)
+	if err != nil {
+		log.Printf("level=error component=service flow=money_drop_payout msg=\"payout lookup failed\" claim_transaction_id=%s err=%v", claimTxID, err)
+		return
+	}
+
+	inFlightReason := buildMoneyDropClaimAnchorReason(payout.DropID, moneyDropClaimStatePayoutInflight)
+	marked, err := s.repo.MarkMoneyDropClaimPayoutInFlight(ctx, claimTxID, inFlightReason, time.Now().UTC().Add(-moneyDropPayoutRetryAfter))
+	if err != nil {
+		log.Printf("level=error component=service flow=money_drop_payout msg=\"failed to mark payout in-flight\" claim_transaction_id=%s err=%v", claimTxID, err)
+		return
+	}
+	if !marked {
+		// Another worker has it, or it is no longer waiting for a payout.
+		return
+	}
+
+	reason := buildMoneyDropClaimTransferReason(claimTxID, payout.CreatorUsername)
+	transferResp, err := s.anchorClient.InitiateBookTransfer(
+		ctx,
+		payout.SourceAnchorAccountID,
+		payout.DestinationAnchorAccountID,
+		reason,
+		payout.Amount,
+		transferIdempotencyKey(claimTxID, ""),
+	)
+	if err != nil {
+		var anchorErr *anchorclient.APIError
+		if errors.As(err, &anchorErr) && anchorErr.IsExplicitRejection() {
+			log.Printf("level=warn component=service flow=money_drop_payout msg=\"payout rejected by anchor\" money_drop_id=%s claim_transaction_id=%s err=%v", payout.DropID, claimTxID, err)
+			s.revertRejectedMoneyDropPayout(ctx, payout.DropID, payout.ClaimantID, claimTxID, err)
+			return
+		}
+
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"payout outcome unknown; requeued for retry\" money_drop_id=%s claim_transaction_id=%s err=%v", payout.DropID, claimTxID, err)
+		s.returnMoneyDropPayoutToPending(ctx, payout.DropID, claimTxID, fmt.Sprintf("money_drop_claim_payout_ambiguous: %v", err))
+		return
+	}
+
+	anchorTransferID := strings.TrimSpace(transferResp.Data.ID)
+	transferType := "book"
+	anchorReason := buildMoneyDropClaimAnchorReason(payout.DropID, moneyDropClaimStateTransferInit)
+	clearedFailureReason := ""
+	metadata := store.UpdateTransactionMetadataParams{
+		TransferType:  &transferType,
+		FailureReason: &clearedFailureReason,
+		AnchorReason:  &anchorReason,
+	}
+	if anchorTransferID != "" {
+		metadata.AnchorTransferID = &anchorTransferID
+	}
+	if metaErr := s.repo.UpdateTransactionMetadata(ctx, claimTxID, metadata); metaErr != nil {
+		// Persisting anchor_transfer_id is what stops the sweep from sending the
+		// transfer again; the simpler status update is enough for that.
+		if anchorTransferID == "" {
+			log.Printf("level=error component=service flow=money_drop_payout msg=\"payout sent but metadata update failed; claim left in-flight\" claim_transaction_id=%s err=%v", claimTxID, metaErr)
+			return
+		}
+		if fallbackErr := s.repo.UpdateTransactionStatus(ctx, claimTxID, anchorTransferID, "pending"); fallbackErr != nil {
+			log.Printf("level=error component=service flow=money_drop_payout msg=\"payout sent but transfer reference persistence failed; claim left in-flight\" claim_transaction_id=%s anchor_transfer_id=%s metadata_err=%v fallback_err=%v", claimTxID, anchorTransferID, metaErr, fallbackErr)
+			return
+		}
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"metadata update failed; persisted anchor transfer id via fallback\" claim_transaction_id=%s anchor_transfer_id=%s err=%v", claimTxID, anchorTransferID, metaErr)
+	}
+	log.Printf("level=info component=service flow=money_drop_payout msg=\"anchor transfer created\" money_drop_id=%s claim_transaction_id=%s amount=%d anchor_transfer_id=%s", payout.DropID, claimTxID, payout.Amount, anchorTransferID)
+
+	if err := s.syncMoneyDropAccountBalance(ctx, payout.MoneyDropAccountID, payout.SourceAnchorAccountID); err != nil {
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"money-drop account sync failed after payout\" account_id=%s err=%v", payout.MoneyDropAccountID, err)
+	}
+	if err := s.syncAccountBalance(ctx, payout.ClaimantID); err != nil {
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"claimant balance sync failed\" claimant_id=%s err=%v", payout.ClaimantID, err)
+	}
+}
+
+// revertRejectedMoneyDropPayout gives the claim slot back after Anchor refused the
+// payout. If the revert fails transiently the claim returns to payout_pending: the
+// retry is rejected again and the revert runs again.
+func (s *Service) revertRejectedMoneyDropPayout(ctx context.Context, dropID, claimantID, claimTxID uuid.UUID, transferErr error) {
+	revertErr := s.repo.RevertMoneyDropClaimAtomic(ctx, dropID, claimantID, claimTxID)
+	if revertErr == nil {
+		log.Printf("level=info component=service flow=money_drop_payout msg=\"reverted rejected claim\" money_drop_id=%s claim_transaction_id=%s claimant_id=%s", dropID, claimTxID, claimantID)
+		// The claimant was told the payout is on its way, so tell them it is not.
+		body := "We couldn't pay out your money drop claim, so it was cancelled. You can claim again while the drop is active."
+		relatedEntityType := "money_drop"
+		dedupeKey := fmt.Sprintf("money_drop.claim_failed:%s", claimTxID)
+		s.emitInAppNotification(ctx, "money_drop_payout", domain.InAppNotification{
+			ID:                uuid.New(),
+			UserID:            claimantID,
+			Category:          "system",
+			Type:              "money_drop.claim_failed",
+			Title:             "Money Drop Claim Failed",
+			Body:              &body,
+			Status:            "unread",
+			RelatedEntityType: &relatedEntityType,
+			RelatedEntityID:   &dropID,
+			DedupeKey:         &dedupeKey,
+			Data: map[string]interface{}{
+				"money_drop_id":  dropID.String(),
+				"transaction_id": claimTxID.String(),
+				"status":         "failed",
+			},
+		})
+		return
+	}
+	if isRetryableMoneyDropClaimCompensationError(revertErr) {
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"revert of rejected claim failed transiently; requeued\" claim_transaction_id=%s err=%v", claimTxID, revertErr)
+		s.returnMoneyDropPayoutToPending(ctx, dropID, claimTxID, fmt.Sprintf("money_drop_claim_payout_revert_retry: %v", revertErr))
+		return
+	}
+
+	log.Printf("level=error component=service flow=money_drop_payout msg=\"failed to revert rejected claim; marking failed\" claim_transaction_id=%s err=%v", claimTxID, revertErr)
+	status := "failed"
+	failureReason := fmt.Sprintf("money_drop_claim_payout_rejected: %v", transferErr)
+	anchorReason := buildMoneyDropClaimAnchorReason(dropID, "payout_rejected")
+	if err := s.repo.UpdateTransactionMetadata(ctx, claimTxID, store.UpdateTransactionMetadataParams{
+		Status:        &status,
+		FailureReason: &failureReason,
+		AnchorReason:  &anchorReason,
+	}); err != nil {
+		log.Printf("level=error component=service flow=money_drop_payout msg=\"failed to persist rejected payout state\" claim_transaction_id=%s err=%v", claimTxID, err)
+	}
+}
+
+func (s *Service) returnMoneyDropPayoutToPending(ctx context.Context, dropID, claimTxID uuid.UUID, failureReason string) {
+	anchorReason := buildMoneyDropClaimAnchorReason(dropID, moneyDropClaimStatePayoutPending)
+	if err := s.repo.UpdateTransactionMetadata(ctx, claimTxID, store.UpdateTransactionMetadataParams{
+		FailureReason: &failureReason,
+		AnchorReason:  &anchorReason,
+	}); err != nil {
+		// The claim stays in flight; the sweep takes it over once it has been idle
+		// for moneyDropPayoutRetryAfter.
+		log.Printf("level=warn component=service flow=money_drop_payout msg=\"failed to return payout to pending\" claim_transaction_id=%s err=%v", claimTxID, err)
+	}
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type moneyDropPayoutRepoStub struct {
+	store.Repository
+
+	payout    domain.MoneyDropClaimPayout
+	notMarked bool
+	revertErr error
+
+	markInFlightReason  string
+	revertCalled        bool
+	notifications       []domain.InAppNotification
+	updateMetadataCalls []store.UpdateTransactionMetadataParams
+}
+
+func (s *moneyDropPayoutRepoStub) FindMoneyDropClaimPayout(ctx context.Context, transactionID uuid.UUID) (*domain.MoneyDropClaimPayout, error) {
+	payout := s.payout
+	return &payout, nil
+}
+
+func (s *moneyDropPayoutRepoStub) MarkMoneyDropClaimPayoutInFlight(ctx context.Context, transactionID uuid.UUID, anchorReason string, idleBefore time.Time) (bool, error) {
+	if s.notMarked {
+		return false, nil
+	}
+	s.markInFlightReason = anchorReason
+	return true, nil
+}
+
+func (s *moneyDropPayoutRepoStub) UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata store.UpdateTransactionMetadataParams) error {
+	s.updateMetadataCalls = append(s.updateMetadataCalls, metadata)
+	return nil
+}
+
+func (s *moneyDropPayoutRepoStub) RevertMoneyDropClaimAtomic(ctx context.Context, dropID, claimantID, claimTransactionID uuid.UUID) error {
+	s.revertCalled = true
+	return s.revertErr
+}
+
+func (s *moneyDropPayoutRepoStub) CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error {
+	s.notifications = append(s.notifications, item)
+	return nil
+}
+
+func (s *moneyDropPayoutRepoStub) FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
+	return nil, errors.New("not found")
+}
+
+// newMoneyDropPayoutTestService answers transfer creation with status and body, and
+// counts the transfer requests it receives.
+func newMoneyDropPayoutTestService(t *testing.T, repo *moneyDropPayoutRepoStub, status int, body string) (*Service, *int) {
+	t.Helper()
+	transfers := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/transfers" {
+			transfers++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, body)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Service{repo: repo, anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0)}, &transfers
+}
+
+func newMoneyDropPayoutRepoStub() *moneyDropPayoutRepoStub {
+	return &moneyDropPayoutRepoStub{
+		payout: domain.MoneyDropClaimPayout{
+			TransactionID:              uuid.New(),
+			DropID:                     uuid.New(),
+			ClaimantID:                 uuid.New(),
+			Amount:                     1500,
+			MoneyDropAccountID:         uuid.New(),
+			SourceAnchorAccountID:      "anc_source",
+			DestinationAnchorAccountID: "anc_dest",
+			CreatorUsername:            "ada",
+		},
+	}
+}
+
+func TestPayOutMoneyDropClaim_MarksTransferInitiated(t *testing.T) {
+	repo := newMoneyDropPayoutRepoStub()
+	svc, transfers := newMoneyDropPayoutTestService(t, repo, http.StatusCreated, `{"data":{"id":"anc_tr_1","type":"BOOK_TRANSFER","attributes":{"status":"PENDING"}}}`)
+
+	svc.payOutMoneyDropClaim(repo.payout.TransactionID)
+
+	if *transfers != 1 {
+		t.Fatalf("expected one transfer request, got %d", *transfers)
+	}
+	if !strings.Contains(repo.markInFlightReason, ";state:"+moneyDropClaimStatePayoutInflight) {
+		t.Fatalf("expected payout to be marked in-flight, got %q", repo.markInFlightReason)
+	}
+	if len(repo.updateMetadataCalls) != 1 {
+		t.Fatalf("expected one metadata update, got %d", len(repo.updateMetadataCalls))
+	}
+	got := repo.updateMetadataCalls[0]
+	if got.AnchorTransferID == nil || *got.AnchorTransferID != "anc_tr_1" {
+		t.Fatalf("expected anchor transfer id to be persisted, got %v", got.AnchorTransferID)
+	}
+	expectedReason := buildMoneyDropClaimAnchorReason(repo.payout.DropID, moneyDropClaimStateTransferInit)
+	if got.AnchorReason == nil || *got.AnchorReason != expectedReason {
+		t.Fatalf("expected anchor reason %q, got %v", expectedReason, got.AnchorReason)
+	}
+	if repo.revertCalled {
+		t.Fatal("expected accepted payout not to revert the claim")
+	}
+}
+
+func TestPayOutMoneyDropClaim_RevertsOnExplicitRejection(t *testing.T) {
+	repo := newMoneyDropPayoutRepoStub()
+	svc, _ := newMoneyDropPayoutTestService(t, repo, http.StatusBadRequest, `{"errors":[{"title":"Rejected","detail":"insufficient funds","status":"400"}]}`)
+
+	svc.payOutMoneyDropClaim(repo.payout.TransactionID)
+
+	if !repo.revertCalled {
+		t.Fatal("expected rejected payout to revert the claim")
+	}
+	if len(repo.updateMetadataCalls) != 0 {
+		t.Fatalf("expected no metadata update after a successful revert, got %d", len(repo.updateMetadataCalls))
+	}
+	if len(repo.notifications) != 1 || repo.notifications[0].UserID != repo.payout.ClaimantID {
+		t.Fatalf("expected the claimant to be notified once, got %+v", repo.notifications)
+	}
+}
+
+func TestPayOutMoneyDropClaim_RequeuesWhenRevertFailsTransiently(t *testing.T) {
+	repo := newMoneyDropPayoutRepoStub()
+	repo.revertErr = context.DeadlineExceeded
+	svc, _ := newMoneyDropPayoutTestService(t, repo, http.StatusBadRequest, `{"errors":[{"title":"Rejected","detail":"insufficient funds","status":"400"}]}`)
+
+	svc.payOutMoneyDropClaim(repo.payout.TransactionID)
+
+	if len(repo.updateMetadataCalls) != 1 {
+		t.Fatalf("expected one metadata update, got %d", len(repo.updateMetadataCalls))
+	}
+	expectedReason := buildMoneyDropClaimAnchorReason(repo.payout.DropID, moneyDropClaimStatePayoutPending)
+	if got := repo.updateMetadataCalls[0].AnchorReason; got == nil || *got != expectedReason {
+		t.Fatalf("expected anchor reason %q, got %v", expectedReason, got)
+	}
+	if len(repo.notifications) != 0 {
+		t.Fatalf("expected no notification while the claim is still pending, got %d", len(repo.notifications))
+	}
+}
+
+func TestPayOutMoneyDropClaim_ReturnsAmbiguousFailureToPending(t *testing.T) {
+	repo := newMoneyDropPayoutRepoStub()
+	svc, _ := newMoneyDropPayoutTestService(t, repo, http.StatusBadGateway, `{"errors":[{"title":"Bad Gateway","status":"502"}]}`)
+
+	svc.payOutMoneyDropClaim(repo.payout.TransactionID)
+
+	if repo.revertCalled {
+		t.Fatal("expected ambiguous failure not to revert the claim")
+	}
+	if len(repo.updateMetadataCalls) != 1 {
+		t.Fatalf("expected one metadata update, got %d", len(repo.updateMetadataCalls))
+	}
+	got := repo.updateMetadataCalls[0]
+	expectedReason := buildMoneyDropClaimAnchorReason(repo.payout.DropID, moneyDropClaimStatePayoutPending)
+	if got.AnchorReason == nil || *got.AnchorReason != expectedReason {
+		t.Fatalf("expected anchor reason %q, got %v", expectedReason, got.AnchorReason)
+	}
+	if got.FailureReason == nil || !strings.Contains(*got.FailureReason, "payout_ambiguous") {
+		t.Fatalf("expected ambiguous failure reason, got %v", got.FailureReason)
+	}
+}
+
+func TestPayOutMoneyDropClaim_SkipsClaimNotWaitingForPayout(t *testing.T) {
+	repo := newMoneyDropPayoutRepoStub()
+	repo.notMarked = true
+	svc, transfers := newMoneyDropPayoutTestService(t, repo, http.StatusCreated, `{"data":{"id":"anc_tr_1"}}`)
+
+	svc.payOutMoneyDropClaim(repo.payout.TransactionID)
+
+	if *transfers != 0 {
+		t.Fatalf("expected no transfer for a claim another worker owns, got %d", *transfers)
+	}
+	if len(repo.updateMetadataCalls) != 0 {
+		t.Fatalf("expected no metadata update, got %d", len(repo.updateMetadataCalls))
+	}
+}
+
+func TestEnqueueMoneyDropPayout_DoesNotBlockWhenQueueIsFull(t *testing.T) {
+	svc := &Service{moneyDropPayouts: make(chan uuid.UUID, 1)}
+	svc.enqueueMoneyDropPayout(uuid.New())
+
+	done := make(chan struct{})
+	go func() {
+		svc.enqueueMoneyDropPayout(uuid.New())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected enqueue to return when the queue is full")
+	}
+}
@@ -10,20 +10,22 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
-	"github.com/transfa/transaction-service/pkg/anchorclient"
 )
 
 const (
 	moneyDropClaimReasonTokenPrefix   = "md_claim_tx:"
 	moneyDropClaimDropTokenPrefix     = "md_drop:"
 	moneyDropClaimStateCreated        = "created"
+	moneyDropClaimStatePayoutPending  = "payout_pending"
+	moneyDropClaimStatePayoutInflight = "payout_inflight"
 	moneyDropClaimStateTransferInit   = "transfer_initiated"
 	moneyDropClaimStateRetryInflight  = "reconcile_retry_inflight"
 	moneyDropClaimStateRetryInit      = "reconcile_retry_initiated"
 	moneyDropClaimStateRetryRequested = "reconcile_retry_requested"
-	moneyDropClaimStateRetryUnknown   = "reconcile_retry_unknown"
+	moneyDropClaimRetryKeyPurpose     = "reconcile_retry"
 	defaultClaimReconcileLimit        = 100
 	maxClaimReconcileLimit            = 500
 	claimReconcileRetryEligibilityAge = 2 * time.Minute
@@ -277,6 +279,8 @@ func (s *Service) ReconcilePendingMoneyDropClaims(ctx context.Context, limit int
 			continue
 		}
 
+		// Every reconcile retry of a claim sends the same idempotency key, so a retry
+		// that Anchor already accepted is returned rather than paid out again.
 		reason := buildMoneyDropClaimTransferReason(tx.ID, "")
 		transferResp, transferErr := s.anchorClient.InitiateBookTransfer(
 			ctx,
@@ -284,11 +288,12 @@ func (s *Service) ReconcilePendingMoneyDropClaims(ctx context.Context, limit int
 			item.DestinationAnchorAccountID,
 			reason,
 			item.Amount,
+			transferIdempotencyKey(tx.ID, moneyDropClaimRetryKeyPurpose),
 		)
 		if transferErr != nil {
 			result.RetryFailed++
 
-			var anchorErr *anchorclient.ErrorResponse
+			var anchorErr *anchorclient.APIError
 			if errors.As(transferErr, &anchorErr) && anchorErr.IsExplicitRejection() {
 				result.ExplicitAnchorRejects++
 				if rejectErr := s.handleExplicitMoneyDropClaimReconcileReject(ctx, tx, dropID, hasDropID, transferErr); rejectErr != nil {
@@ -307,11 +312,13 @@ func (s *Service) ReconcilePendingMoneyDropClaims(ctx context.Context, limit int
 				result.AmbiguousFailures++
 			}
 
-			anchorReason := "money_drop_claim_reconcile_retry_unknown"
+			// The retry key makes an ambiguous failure safe to send again, so the claim
+			// goes back to retry_requested for the next reconcile run.
+			anchorReason := buildGenericMoneyDropClaimAnchorReason(moneyDropClaimStateRetryRequested)
 			if hasDropID {
-				anchorReason = buildMoneyDropClaimAnchorReason(dropID, moneyDropClaimStateRetryUnknown)
+				anchorReason = buildMoneyDropClaimAnchorReason(dropID, moneyDropClaimStateRetryRequested)
 			}
-			failureReason := fmt.Sprintf("money_drop_claim_reconcile_retry_unknown: %v", transferErr)
+			failureReason := fmt.Sprintf("money_drop_claim_reconcile_retry_ambiguous: %v", transferErr)
 			if metaErr := s.repo.UpdateTransactionMetadata(ctx, tx.ID, store.UpdateTransactionMetadataParams{
 				FailureReason: &failureReason,
 				AnchorReason:  &anchorReason,
@@ -319,7 +326,7 @@ func (s *Service) ReconcilePendingMoneyDropClaims(ctx context.Context, limit int
 				log.Printf("level=warn component=service flow=money_drop_claim_reconcile msg=\"failed to persist retry failure metadata\" transaction_id=%s err=%v", tx.ID, metaErr)
 			}
 
-			log.Printf("level=warn component=service flow=money_drop_claim_reconcile msg=\"retry initiation failed\" transaction_id=%s err=%v", tx.ID, transferErr)
+			log.Printf("level=warn component=service flow=money_drop_claim_reconcile msg=\"retry initiation failed; requeued for retry\" transaction_id=%s err=%v", tx.ID, transferErr)
 			continue
 		}
 
@@ -11,9 +11,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
-	"github.com/transfa/transaction-service/pkg/anchorclient"
 )
 
 type reconcileRejectRepoStub struct {
@@ -187,7 +187,7 @@ func TestReconcilePendingMoneyDropClaims_RequeuesRetryableExplicitRejectCompensa
 
 	svc := &Service{
 		repo:         repo,
-		anchorClient: anchorclient.NewClient(server.URL, "test-key"),
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
 	}
 
 	resp, err := svc.ReconcilePendingMoneyDropClaims(context.Background(), 1)
@@ -263,7 +263,7 @@ func TestReconcilePendingMoneyDropClaims_PropagatesRequeueFailure(t *testing.T)
 
 	svc := &Service{
 		repo:         repo,
-		anchorClient: anchorclient.NewClient(server.URL, "test-key"),
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
 	}
 
 	resp, err := svc.ReconcilePendingMoneyDropClaims(context.Background(), 1)
@@ -280,3 +280,70 @@ func TestReconcilePendingMoneyDropClaims_PropagatesRequeueFailure(t *testing.T)
 		t.Fatalf("expected %d requeue metadata update attempts, got %d", requeueRetryAttempts, len(repo.updateMetadataCalls))
 	}
 }
+
+func TestReconcilePendingMoneyDropClaims_RequeuesAmbiguousFailureWithStableIdempotencyKey(t *testing.T) {
+	txID := uuid.New()
+	dropID := uuid.New()
+	claimantID := uuid.New()
+	anchorReason := buildMoneyDropClaimAnchorReason(dropID, moneyDropClaimStateRetryRequested)
+
+	repo := &reconcileLoopRepoStub{
+		candidate: domain.PendingMoneyDropClaimReconciliationCandidate{
+			TransactionID:              txID,
+			SourceAnchorAccountID:      "anc_source",
+			DestinationAnchorAccountID: "anc_dest",
+			Amount:                     1500,
+		},
+		tx: &domain.Transaction{
+			ID:           txID,
+			Type:         "money_drop_claim",
+			Status:       "pending",
+			Amount:       1500,
+			RecipientID:  &claimantID,
+			AnchorReason: &anchorReason,
+		},
+	}
+
+	var idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/transfers" {
+			idempotencyKeys = append(idempotencyKeys, r.Header.Get(anchorclient.IdempotencyKeyHeader))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = io.WriteString(w, `{"errors":[{"title":"Bad Gateway","detail":"upstream timeout","status":"502"}]}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	svc := &Service{
+		repo:         repo,
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
+	}
+
+	for run := 0; run < 2; run++ {
+		resp, err := svc.ReconcilePendingMoneyDropClaims(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run+1, err)
+		}
+		if resp.AmbiguousFailures != 1 || resp.ExplicitAnchorRejects != 0 {
+			t.Fatalf("run %d: unexpected reconcile summary: %+v", run+1, *resp)
+		}
+	}
+
+	if repo.revertCalled {
+		t.Fatal("ambiguous failure must not revert the claim")
+	}
+	wantKey := txID.String() + ":" + moneyDropClaimRetryKeyPurpose
+	if len(idempotencyKeys) != 2 || idempotencyKeys[0] != wantKey || idempotencyKeys[1] != wantKey {
+		t.Fatalf("expected both retries to send idempotency key %q, got %v", wantKey, idempotencyKeys)
+	}
+	last := repo.updateMetadataCalls[len(repo.updateMetadataCalls)-1]
+	if last.AnchorReason == nil || *last.AnchorReason != anchorReason {
+		t.Fatalf("expected claim requeued as %q, got %v", anchorReason, last.AnchorReason)
+	}
+	if last.FailureReason == nil || !strings.Contains(*last.FailureReason, "retry_ambiguous") {
+		t.Fatalf("expected ambiguous failure reason, got %v", last.FailureReason)
+	}
+}
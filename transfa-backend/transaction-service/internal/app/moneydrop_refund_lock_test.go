@@ -11,9 +11,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
-	"github.com/transfa/transaction-service/pkg/anchorclient"
 )
 
 type refundLockRepoStub struct {
@@ -35,7 +35,7 @@ type refundLockRepoStub struct {
 	endMetadataReason            string
 	creditWalletCalled           bool
 	updateMoneyDropBalanceCalled bool
-	updateAccountBalanceCalled   bool
+	syncAccountBalanceCalled     bool
 	createTransactionCalled      bool
 }
 
@@ -88,9 +88,9 @@ func (s *refundLockRepoStub) UpdateMoneyDropAccountBalance(ctx context.Context,
 	return nil
 }
 
-func (s *refundLockRepoStub) UpdateAccountBalance(ctx context.Context, userID uuid.UUID, balance int64) error {
-	s.updateAccountBalanceCalled = true
-	return nil
+func (s *refundLockRepoStub) SyncAccountBalance(ctx context.Context, snapshot domain.Account, balance int64) (bool, error) {
+	s.syncAccountBalanceCalled = true
+	return true, nil
 }
 
 func (s *refundLockRepoStub) CreateTransaction(ctx context.Context, tx *domain.Transaction) error {
@@ -146,7 +146,7 @@ func TestFinalizeMoneyDropWithRefund_KeepsLockClosedAfterPayoutUntilPersistence(
 
 	svc := &Service{
 		repo:         repo,
-		anchorClient: anchorclient.NewClient(server.URL, "test-key"),
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
 	}
 
 	_, _, _, err := svc.finalizeMoneyDropWithRefund(context.Background(), dropID, creatorID, "expired")
@@ -228,7 +228,7 @@ func TestFinalizeMoneyDropWithRefund_DoesNotReopenDropWhenRetryPendingMetadataWr
 
 	svc := &Service{
 		repo:         repo,
-		anchorClient: anchorclient.NewClient(server.URL, "test-key"),
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
 	}
 
 	_, _, _, err := svc.finalizeMoneyDropWithRefund(context.Background(), dropID, creatorID, "manual_end")
@@ -300,7 +300,7 @@ func TestFinalizeMoneyDropWithRefund_StopsBeforePayoutWhenInflightMarkerWriteFai
 
 	svc := &Service{
 		repo:         repo,
-		anchorClient: anchorclient.NewClient(server.URL, "test-key"),
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
 	}
 
 	_, _, _, err := svc.finalizeMoneyDropWithRefund(context.Background(), dropID, creatorID, "expired")
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// revealPasswordRepoStub returns its drop for any lookup, leaving the owner check to
+// the service.
+type revealPasswordRepoStub struct {
+	store.Repository
+
+	drop domain.MoneyDrop
+}
+
+func (s *revealPasswordRepoStub) FindMoneyDropByIDAndCreatorID(ctx context.Context, dropID, creatorID uuid.UUID) (*domain.MoneyDrop, error) {
+	drop := s.drop
+	return &drop, nil
+}
+
+func newRevealPasswordTestService(t *testing.T, plain string) (*Service, *revealPasswordRepoStub) {
+	t.Helper()
+	repo := &revealPasswordRepoStub{drop: domain.MoneyDrop{ID: uuid.New(), CreatorID: uuid.New(), LockEnabled: true}}
+	svc := &Service{repo: repo, moneyDropPasswordKey: deriveMoneyDropPasswordKey("test-money-drop-secret")}
+	encrypted, err := svc.encryptMoneyDropPassword(plain)
+	if err != nil {
+		t.Fatalf("encrypt password: %v", err)
+	}
+	repo.drop.LockPasswordEncrypted = &encrypted
+	return svc, repo
+}
+
+func TestRevealMoneyDropPassword_ReturnsOriginalPlaintext(t *testing.T) {
+	svc, repo := newRevealPasswordTestService(t, "open-sesame")
+
+	plain, err := svc.RevealMoneyDropPassword(context.Background(), repo.drop.CreatorID, repo.drop.ID)
+	if err != nil {
+		t.Fatalf("RevealMoneyDropPassword: %v", err)
+	}
+	if plain != "open-sesame" {
+		t.Fatalf("expected the original password, got %q", plain)
+	}
+}
+
+func TestRevealMoneyDropPassword_RejectsNonOwner(t *testing.T) {
+	svc, repo := newRevealPasswordTestService(t, "open-sesame")
+
+	_, err := svc.RevealMoneyDropPassword(context.Background(), uuid.New(), repo.drop.ID)
+	if !errors.Is(err, store.ErrMoneyDropNotFound) {
+		t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+	}
+}
+
+func TestRevealMoneyDropPassword_RejectsUnlockedDrop(t *testing.T) {
+	svc, repo := newRevealPasswordTestService(t, "open-sesame")
+	repo.drop.LockEnabled = false
+	repo.drop.LockPasswordEncrypted = nil
+
+	_, err := svc.RevealMoneyDropPassword(context.Background(), repo.drop.CreatorID, repo.drop.ID)
+	if !errors.Is(err, ErrMoneyDropNotLocked) {
+		t.Fatalf("expected ErrMoneyDropNotLocked, got %v", err)
+	}
+}
+
+func TestRevealMoneyDropPassword_RequiresEncryptionKey(t *testing.T) {
+	svc, repo := newRevealPasswordTestService(t, "open-sesame")
+	svc.moneyDropPasswordKey = nil
+
+	_, err := svc.RevealMoneyDropPassword(context.Background(), repo.drop.CreatorID, repo.drop.ID)
+	if !errors.Is(err, ErrMoneyDropPasswordEncryptionUnavailable) {
+		t.Fatalf("expected ErrMoneyDropPasswordEncryptionUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+var ErrInvalidNotificationCursor = errors.New("invalid notification cursor")
+
+// EncodeNotificationCursor returns an opaque cursor that resumes a notification list
+// after item.
+func EncodeNotificationCursor(item domain.InAppNotification) string {
+	raw := item.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + item.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeNotificationCursor parses a cursor produced by EncodeNotificationCursor.
+func DecodeNotificationCursor(cursor string) (*domain.NotificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(cursor))
+	if err != nil {
+		return nil, ErrInvalidNotificationCursor
+	}
+	createdAtRaw, idRaw, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidNotificationCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtRaw)
+	if err != nil {
+		return nil, ErrInvalidNotificationCursor
+	}
+	id, err := uuid.Parse(idRaw)
+	if err != nil {
+		return nil, ErrInvalidNotificationCursor
+	}
+	return &domain.NotificationCursor{CreatedAt: createdAt, ID: id}, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const (
+	// maxBulkNotificationIDs caps how many notifications one bulk mark-read may name.
+	maxBulkNotificationIDs = 100
+	// readNotificationRetention is how long read notifications are kept.
+	readNotificationRetention = 90 * 24 * time.Hour
+	notificationCleanupBatch  = 1000
+	platformFeeEventTimeout   = 10 * time.Second
+)
+
+var ErrInvalidNotificationIDs = errors.New("ids must list between 1 and 100 notification ids")
+
+// MarkInAppNotificationsRead marks the listed notifications as read. IDs that are
+// not the user's, or already read, are ignored; the count of newly read ones is
+// returned.
+func (s *Service) MarkInAppNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 || len(ids) > maxBulkNotificationIDs {
+		return 0, ErrInvalidNotificationIDs
+	}
+	return s.repo.MarkInAppNotificationsRead(ctx, userID, ids)
+}
+
+// CleanupReadNotifications deletes read notifications created more than
+// readNotificationRetention before now, notificationCleanupBatch rows per statement.
+func (s *Service) CleanupReadNotifications(ctx context.Context, now time.Time) (*domain.NotificationCleanupResult, error) {
+	result := &domain.NotificationCleanupResult{Cutoff: now.UTC().Add(-readNotificationRetention)}
+	for {
+		deleted, err := s.repo.DeleteReadInAppNotificationsBefore(ctx, result.Cutoff, notificationCleanupBatch)
+		if err != nil {
+			return result, err
+		}
+		if deleted > 0 {
+			result.Batches++
+			result.Deleted += deleted
+		}
+		if deleted < notificationCleanupBatch {
+			return result, nil
+		}
+	}
+}
+
+// notifyMoneyDropClaimed adds a claim on a money drop to its creator's inbox.
+func (s *Service) notifyMoneyDropClaimed(ctx context.Context, drop *domain.MoneyDrop, claimantID uuid.UUID, claimTxID uuid.UUID) {
+	if drop == nil {
+		return
+	}
+
+	body := "Someone claimed from your money drop."
+	if title := strings.TrimSpace(drop.Title); title != "" {
+		body = fmt.Sprintf("Someone claimed from your money drop \"%s\".", title)
+	}
+	relatedEntityType := "money_drop"
+	dedupeKey := fmt.Sprintf("money_drop.claimed:%s", claimTxID)
+	s.emitInAppNotification(ctx, "money_drop_claim", domain.InAppNotification{
+		ID:                uuid.New(),
+		UserID:            drop.CreatorID,
+		Category:          "system",
+		Type:              "money_drop.claimed",
+		Title:             "Money Drop Claimed",
+		Body:              &body,
+		Status:            "unread",
+		RelatedEntityType: &relatedEntityType,
+		RelatedEntityID:   &drop.ID,
+		DedupeKey:         &dedupeKey,
+		Data: map[string]interface{}{
+			"money_drop_id":    drop.ID.String(),
+			"transaction_id":   claimTxID.String(),
+			"claimant_user_id": claimantID.String(),
+			"amount":           drop.AmountPerClaim,
+		},
+	})
+}
+
+// HandlePlatformFeeDue processes platform-fee-service's `platform_fee.due` event and
+// adds the invoice to the user's inbox. It returns a boolean indicating whether the
+// message should be acknowledged.
+func (s *Service) HandlePlatformFeeDue(body []byte) bool {
+	return s.handlePlatformFeeEvent(body, "platform_fee.due", "Platform Fee Due", func(event domain.PlatformFeeEvent) string {
+		return fmt.Sprintf("Your platform fee is due on %s.", event.DueAt.Format("2 Jan 2006"))
+	})
+}
+
+// HandlePlatformFeePaid processes platform-fee-service's `platform_fee.paid` event and
+// tells the user their wallet was billed. It returns a boolean indicating whether the
+// message should be acknowledged.
+func (s *Service) HandlePlatformFeePaid(body []byte) bool {
+	return s.handlePlatformFeeEvent(body, "platform_fee.paid", "Platform Fee Paid", func(domain.PlatformFeeEvent) string {
+		return "Your platform fee was charged to your wallet."
+	})
+}
+
+func (s *Service) handlePlatformFeeEvent(body []byte, notificationType, title string, message func(domain.PlatformFeeEvent) string) bool {
+	var event domain.PlatformFeeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("level=warn component=service flow=platform_fee_notification outcome=ack reason=malformed_payload type=%s err=%v", notificationType, err)
+		return true
+	}
+	userID, err := uuid.Parse(strings.TrimSpace(event.UserID))
+	if err != nil || strings.TrimSpace(event.InvoiceID) == "" {
+		log.Printf("level=warn component=service flow=platform_fee_notification outcome=ack reason=invalid_payload type=%s invoice_id=%s", notificationType, event.InvoiceID)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), platformFeeEventTimeout)
+	defer cancel()
+
+	text := message(event)
+	relatedEntityType := "platform_fee_invoice"
+	dedupeKey := fmt.Sprintf("%s:%s", notificationType, event.InvoiceID)
+	item := domain.InAppNotification{
+		ID:                uuid.New(),
+		UserID:            userID,
+		Category:          "system",
+		Type:              notificationType,
+		Title:             title,
+		Body:              &text,
+		Status:            "unread",
+		RelatedEntityType: &relatedEntityType,
+		DedupeKey:         &dedupeKey,
+		Data: map[string]interface{}{
+			"invoice_id":  event.InvoiceID,
+			"amount":      event.Amount,
+			"currency":    event.Currency,
+			"status":      event.Status,
+			"due_at":      event.DueAt.UTC().Format(time.RFC3339),
+			"grace_until": event.GraceUntil.UTC().Format(time.RFC3339),
+		},
+	}
+	if invoiceID, err := uuid.Parse(event.InvoiceID); err == nil {
+		item.RelatedEntityID = &invoiceID
+	}
+
+	s.emitInAppNotification(ctx, "platform_fee", item)
+	log.Printf("level=info component=service flow=platform_fee_notification outcome=ack type=%s user_id=%s invoice_id=%s", notificationType, userID, event.InvoiceID)
+	return true
+}
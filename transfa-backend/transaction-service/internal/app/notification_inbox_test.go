@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type notificationInboxRepoStub struct {
+	store.Repository
+
+	// deletions is what each successive DeleteReadInAppNotificationsBefore call reports.
+	deletions []int64
+	cutoffs   []time.Time
+	created   []domain.InAppNotification
+}
+
+func (s *notificationInboxRepoStub) DeleteReadInAppNotificationsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	s.cutoffs = append(s.cutoffs, cutoff)
+	if len(s.deletions) == 0 {
+		return 0, nil
+	}
+	deleted := s.deletions[0]
+	s.deletions = s.deletions[1:]
+	return deleted, nil
+}
+
+func (s *notificationInboxRepoStub) CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error {
+	s.created = append(s.created, item)
+	return nil
+}
+
+func TestCleanupReadNotifications_DeletesBatchesOlderThanRetention(t *testing.T) {
+	repo := &notificationInboxRepoStub{deletions: []int64{notificationCleanupBatch, 40}}
+	svc := &Service{repo: repo}
+	now := time.Date(2026, time.April, 30, 3, 30, 0, 0, time.UTC)
+
+	result, err := svc.CleanupReadNotifications(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := time.Date(2026, time.January, 30, 3, 30, 0, 0, time.UTC); !result.Cutoff.Equal(want) {
+		t.Fatalf("expected cutoff %s, got %s", want, result.Cutoff)
+	}
+	if result.Deleted != notificationCleanupBatch+40 || result.Batches != 2 || len(repo.cutoffs) != 2 {
+		t.Fatalf("expected %d rows in 2 batches, got %d in %d", notificationCleanupBatch+40, result.Deleted, result.Batches)
+	}
+}
+
+func TestMarkInAppNotificationsRead_ValidatesIDs(t *testing.T) {
+	svc := &Service{repo: &notificationInboxRepoStub{}}
+
+	for _, ids := range [][]uuid.UUID{nil, make([]uuid.UUID, maxBulkNotificationIDs+1)} {
+		if _, err := svc.MarkInAppNotificationsRead(context.Background(), uuid.New(), ids); !errors.Is(err, ErrInvalidNotificationIDs) {
+			t.Fatalf("%d ids: expected ErrInvalidNotificationIDs, got %v", len(ids), err)
+		}
+	}
+}
+
+func TestHandlePlatformFeeDue_AddsInvoiceToInbox(t *testing.T) {
+	repo := &notificationInboxRepoStub{}
+	svc := &Service{repo: repo}
+	userID := uuid.New()
+	invoiceID := uuid.New()
+
+	body := []byte(`{"user_id":"` + userID.String() + `","invoice_id":"` + invoiceID.String() + `","amount":50000,"currency":"NGN","status":"pending","due_at":"2026-05-01T00:00:00Z","grace_until":"2026-05-08T00:00:00Z"}`)
+	if !svc.HandlePlatformFeeDue(body) {
+		t.Fatalf("expected the event to be acknowledged")
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one notification, got %d", len(repo.created))
+	}
+	item := repo.created[0]
+	if item.UserID != userID || item.Type != "platform_fee.due" || item.Category != "system" {
+		t.Fatalf("unexpected notification: %+v", item)
+	}
+	if item.RelatedEntityID == nil || *item.RelatedEntityID != invoiceID || item.Data["invoice_id"] != invoiceID.String() {
+		t.Fatalf("expected the notification to link to invoice %s, got %+v", invoiceID, item)
+	}
+	if item.Body == nil || *item.Body != "Your platform fee is due on 1 May 2026." {
+		t.Fatalf("unexpected body: %v", item.Body)
+	}
+
+	if !svc.HandlePlatformFeeDue([]byte(`{"user_id":"not-a-uuid","invoice_id":"inv"}`)) || len(repo.created) != 1 {
+		t.Fatalf("expected an invalid event to be acknowledged without a notification")
+	}
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+	"github.com/transfa/pkg/anchorclient"
+	rmrabbit "github.com/transfa/pkg/rabbitmq"
+)
+
+type p2pTransferRepoStub struct {
+	store.Repository
+
+	sender     *domain.User
+	recipient  *domain.User
+	accounts   map[uuid.UUID]*domain.Account
+	delinquent map[uuid.UUID]bool
+
+	// beforeDebit and afterDebit let tests interleave a concurrent freeze with the debit.
+	beforeDebit func()
+	afterDebit  func()
+	debits      int
+
+	createdTx *domain.Transaction
+
+	// beforeClaim runs just before the transaction is claimed for its Anchor transfer,
+	// so tests can land a concurrent cancellation there.
+	beforeClaim     func()
+	claimed         bool
+	cancelled       bool
+	credits         int
+	statusUpdates   []string
+	anchorTransfers atomic.Int32
+
+	// onAnchorTransfer runs as Anchor receives the nth transfer request.
+	onAnchorTransfer func(n int32)
+	feeCollections   []domain.FeeCollection
+}
+
+// FindUserByID and FindUserByUsername return snapshots, as a real read would, so a
+// later change to the stub's users is only visible to the locked debit re-check.
+func (s *p2pTransferRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	sender := *s.sender
+	return &sender, nil
+}
+
+func (s *p2pTransferRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	recipient := *s.recipient
+	return &recipient, nil
+}
+
+func (s *p2pTransferRepoStub) IsUserDelinquent(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return s.delinquent[userID], nil
+}
+
+func (s *p2pTransferRepoStub) FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
+	account, ok := s.accounts[userID]
+	if !ok {
+		return nil, store.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (s *p2pTransferRepoStub) DebitWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	return nil
+}
+
+func (s *p2pTransferRepoStub) DebitWalletForP2PTransfer(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64) error {
+	if s.beforeDebit != nil {
+		s.beforeDebit()
+	}
+	if !s.sender.AllowSending {
+		return store.ErrSendingRestricted
+	}
+	if !s.recipient.AllowReceiving {
+		return store.ErrReceivingRestricted
+	}
+	s.debits++
+	if s.afterDebit != nil {
+		s.afterDebit()
+	}
+	return nil
+}
+
+func (s *p2pTransferRepoStub) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	s.credits++
+	return nil
+}
+
+func (s *p2pTransferRepoStub) ClaimTransactionForTransfer(ctx context.Context, transactionID uuid.UUID) error {
+	if s.beforeClaim != nil {
+		s.beforeClaim()
+	}
+	if s.cancelled {
+		return store.ErrTransactionNotPending
+	}
+	s.claimed = true
+	return nil
+}
+
+func (s *p2pTransferRepoStub) FindTransactionByID(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error) {
+	if s.createdTx == nil || s.createdTx.ID != transactionID {
+		return nil, store.ErrTransactionNotFound
+	}
+	tx := *s.createdTx
+	return &tx, nil
+}
+
+// CancelPendingTransaction loses to a claim, as the conditional update does.
+func (s *p2pTransferRepoStub) CancelPendingTransaction(ctx context.Context, transactionID uuid.UUID, senderID uuid.UUID) (int64, error) {
+	if s.claimed || s.cancelled {
+		return 0, store.ErrTransactionNotCancellable
+	}
+	s.cancelled = true
+	return s.createdTx.Amount + s.createdTx.Fee, nil
+}
+
+func (s *p2pTransferRepoStub) CreateFeeCollection(ctx context.Context, fee domain.FeeCollection) error {
+	s.feeCollections = append(s.feeCollections, fee)
+	return nil
+}
+
+func (s *p2pTransferRepoStub) CreateTransaction(ctx context.Context, tx *domain.Transaction) error {
+	s.createdTx = tx
+	return nil
+}
+
+func (s *p2pTransferRepoStub) FindOrCreateReceivingPreference(ctx context.Context, userID uuid.UUID) (*domain.UserReceivingPreference, error) {
+	return &domain.UserReceivingPreference{UserID: userID}, nil
+}
+
+func (s *p2pTransferRepoStub) UpdateTransactionDestinations(ctx context.Context, transactionID uuid.UUID, destinationAccountID *uuid.UUID, destinationBeneficiaryID *uuid.UUID) error {
+	return nil
+}
+
+func (s *p2pTransferRepoStub) UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata store.UpdateTransactionMetadataParams) error {
+	return nil
+}
+
+func (s *p2pTransferRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
+func (s *p2pTransferRepoStub) UpdateTransactionStatus(ctx context.Context, transactionID uuid.UUID, anchorTransferID, status string) error {
+	s.statusUpdates = append(s.statusUpdates, status)
+	return nil
+}
+
+type publishedEvent struct {
+	exchange   string
+	routingKey string
+	body       interface{}
+}
+
+type recordingPublisher struct {
+	mu         sync.Mutex
+	events     []publishedEvent
+	publishErr error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, publishedEvent{exchange: exchange, routingKey: routingKey, body: body})
+	return p.publishErr
+}
+
+func (p *recordingPublisher) PublishPlatformFeeEvent(ctx context.Context, event rmrabbit.PlatformFeeEvent) error {
+	return nil
+}
+
+func (p *recordingPublisher) Close() {}
+
+func (p *recordingPublisher) find(routingKey string) []publishedEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var matches []publishedEvent
+	for _, event := range p.events {
+		if event.routingKey == routingKey {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+// waitFor returns the routingKey events once want of them were published by the
+// service's background publishers, failing the test after a second.
+func (p *recordingPublisher) waitFor(t *testing.T, routingKey string, want int) []publishedEvent {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		events := p.find(routingKey)
+		if len(events) >= want || time.Now().After(deadline) {
+			return events
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newP2PTransferTestService(t *testing.T, transferStatus int, publisher *recordingPublisher) (*Service, *p2pTransferRepoStub) {
+	t.Helper()
+
+	var repo *p2pTransferRepoStub
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/transfers" {
+			n := repo.anchorTransfers.Add(1)
+			if repo.onAnchorTransfer != nil {
+				repo.onAnchorTransfer(n)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(transferStatus)
+			if transferStatus >= http.StatusBadRequest {
+				_, _ = io.WriteString(w, `{"errors":[{"title":"Rejected","detail":"transfer rejected","status":"400"}]}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":{"id":"atr_p2p_123","type":"BookTransfer","attributes":{"status":"pending","fee":0}}}`)
+			return
+		}
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/accounts/balance/") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"data":{"availableBalance":0,"ledgerBalance":0,"hold":0,"pending":0}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	senderID := uuid.New()
+	recipientID := uuid.New()
+	repo = &p2pTransferRepoStub{
+		sender:    &domain.User{ID: senderID, Username: "alice", AllowSending: true, AllowReceiving: true},
+		recipient: &domain.User{ID: recipientID, Username: "bob", AllowSending: true, AllowReceiving: true},
+		accounts: map[uuid.UUID]*domain.Account{
+			senderID:    {ID: uuid.New(), UserID: senderID, AnchorAccountID: "anc_sender"},
+			recipientID: {ID: uuid.New(), UserID: recipientID, AnchorAccountID: "anc_recipient"},
+		},
+	}
+
+	svc := &Service{
+		repo:          repo,
+		anchorClient:  anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
+		eventProducer: publisher,
+	}
+	return svc, repo
+}
+
+func TestProcessP2PTransfer_PublishesTransferInitiatedEvent(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	tx, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            150000,
+		Description:       "Lunch money",
+	})
+	if err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+
+	events := publisher.waitFor(t, "transfer.initiated.p2p", 1)
+	if len(events) != 1 {
+		t.Fatalf("expected exactly one transfer.initiated.p2p event, got %d", len(events))
+	}
+	if events[0].exchange != "transfa.events" {
+		t.Fatalf("expected transfa.events exchange, got %q", events[0].exchange)
+	}
+
+	payload, ok := events[0].body.(domain.P2PTransferInitiatedPayload)
+	if !ok {
+		t.Fatalf("expected P2PTransferInitiatedPayload, got %T", events[0].body)
+	}
+	want := domain.P2PTransferInitiatedPayload{
+		SenderUsername: "alice",
+		RecipientID:    repo.recipient.ID,
+		Amount:         150000,
+		TransactionID:  tx.ID,
+	}
+	if payload != want {
+		t.Fatalf("expected payload %+v, got %+v", want, payload)
+	}
+}
+
+func TestProcessP2PTransfer_PublishFailureDoesNotFailTransfer(t *testing.T) {
+	publisher := &recordingPublisher{publishErr: errors.New("broker unavailable")}
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	tx, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	if err != nil {
+		t.Fatalf("expected transfer to succeed despite publish failure, got %v", err)
+	}
+	if tx.Status != "pending" {
+		t.Fatalf("expected pending status, got %q", tx.Status)
+	}
+	if len(publisher.waitFor(t, "transfer.initiated.p2p", 1)) != 1 {
+		t.Fatal("expected a transfer.initiated.p2p publish attempt")
+	}
+}
+
+func TestProcessP2PTransfer_DoesNotPublishWhenAnchorRejects(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, repo := newP2PTransferTestService(t, http.StatusBadRequest, publisher)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	if err == nil {
+		t.Fatal("expected anchor rejection to fail the transfer")
+	}
+	if got := len(publisher.find("transfer.initiated.p2p")); got != 0 {
+		t.Fatalf("expected no transfer.initiated.p2p event, got %d", got)
+	}
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// p2pRoutingRepoStub is a p2pTransferRepoStub whose recipient prefers an external
+// account unless told otherwise, recording the routing metadata the transfer persists.
+type p2pRoutingRepoStub struct {
+	*p2pTransferRepoStub
+
+	prefersWallet      bool
+	preferenceErr      error
+	eligibilityErr     error
+	beneficiaryMissing bool
+	preferenceLookups  int
+	metadata           store.UpdateTransactionMetadataParams
+}
+
+func (s *p2pRoutingRepoStub) FindOrCreateReceivingPreference(ctx context.Context, userID uuid.UUID) (*domain.UserReceivingPreference, error) {
+	s.preferenceLookups++
+	if s.preferenceErr != nil {
+		return nil, s.preferenceErr
+	}
+	return &domain.UserReceivingPreference{UserID: userID, UseExternalAccount: !s.prefersWallet}, nil
+}
+
+func (s *p2pRoutingRepoStub) IsUserDelinquent(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if s.eligibilityErr != nil && userID == s.recipient.ID {
+		return false, s.eligibilityErr
+	}
+	return s.p2pTransferRepoStub.IsUserDelinquent(ctx, userID)
+}
+
+func (s *p2pRoutingRepoStub) FindOrCreateDefaultBeneficiary(ctx context.Context, userID uuid.UUID) (*domain.Beneficiary, error) {
+	if s.beneficiaryMissing {
+		return nil, store.ErrBeneficiaryNotFound
+	}
+	return &domain.Beneficiary{ID: uuid.New(), UserID: userID, AnchorCounterpartyID: "cp_recipient"}, nil
+}
+
+func (s *p2pRoutingRepoStub) UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata store.UpdateTransactionMetadataParams) error {
+	s.metadata = metadata
+	return nil
+}
+
+func (s *p2pRoutingRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
+func newP2PRoutingTestService(t *testing.T) (*Service, *p2pRoutingRepoStub) {
+	t.Helper()
+	svc, base := newP2PTransferTestService(t, http.StatusCreated, &recordingPublisher{})
+	repo := &p2pRoutingRepoStub{p2pTransferRepoStub: base}
+	svc.repo = repo
+	return svc, repo
+}
+
+func TestProcessP2PTransfer_SenderOverrideDeliversInternally(t *testing.T) {
+	svc, repo := newP2PRoutingTestService(t)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	tx, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Birthday gift",
+		Delivery:          "internal",
+	})
+	if err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+
+	if repo.preferenceLookups != 0 {
+		t.Fatalf("expected the recipient's preference to be skipped, looked up %d times", repo.preferenceLookups)
+	}
+	if tx.TransferType != "book" || tx.RoutingDecision != domain.RoutingInternal || tx.RoutingReason != domain.RoutingReasonSenderOverride {
+		t.Fatalf("expected an internal book transfer for a sender override, got type=%s decision=%s reason=%s", tx.TransferType, tx.RoutingDecision, tx.RoutingReason)
+	}
+	if repo.metadata.RoutingDecision == nil || *repo.metadata.RoutingDecision != domain.RoutingInternal ||
+		repo.metadata.RoutingReason == nil || *repo.metadata.RoutingReason != domain.RoutingReasonSenderOverride {
+		t.Fatalf("expected the routing decision to be persisted, got %+v", repo.metadata)
+	}
+}
+
+func TestProcessP2PTransfer_RecordsRoutingReasonForEachBranch(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(repo *p2pRoutingRepoStub)
+		wantDecision string
+		wantReason   string
+	}{
+		{
+			name:         "external preference followed",
+			setup:        func(repo *p2pRoutingRepoStub) {},
+			wantDecision: domain.RoutingExternal,
+			wantReason:   domain.RoutingReasonExternalPreferred,
+		},
+		{
+			name:         "wallet preference followed",
+			setup:        func(repo *p2pRoutingRepoStub) { repo.prefersWallet = true },
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonInternalPreferred,
+		},
+		{
+			name: "sender behind on platform fees",
+			setup: func(repo *p2pRoutingRepoStub) {
+				repo.delinquent = map[uuid.UUID]bool{repo.sender.ID: true}
+			},
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonQuotaExceeded,
+		},
+		{
+			name: "recipient not eligible",
+			setup: func(repo *p2pRoutingRepoStub) {
+				repo.delinquent = map[uuid.UUID]bool{repo.recipient.ID: true}
+			},
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonNotEligible,
+		},
+		{
+			name:         "recipient eligibility lookup failed",
+			setup:        func(repo *p2pRoutingRepoStub) { repo.eligibilityErr = errors.New("db unavailable") },
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonNotEligible,
+		},
+		{
+			name:         "recipient has no beneficiary",
+			setup:        func(repo *p2pRoutingRepoStub) { repo.beneficiaryMissing = true },
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonBeneficiaryMissing,
+		},
+		{
+			name:         "preference lookup failed",
+			setup:        func(repo *p2pRoutingRepoStub) { repo.preferenceErr = errors.New("db unavailable") },
+			wantDecision: domain.RoutingInternal,
+			wantReason:   domain.RoutingReasonPreferenceLookupFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newP2PRoutingTestService(t)
+			tt.setup(repo)
+			ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+			tx, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+				RecipientUsername: "bob",
+				Amount:            5000,
+				Description:       "Lunch money",
+			})
+			if err != nil {
+				t.Fatalf("expected transfer to succeed, got %v", err)
+			}
+
+			if tx.RoutingDecision != tt.wantDecision || tx.RoutingReason != tt.wantReason {
+				t.Fatalf("expected decision=%s reason=%s, got decision=%s reason=%s", tt.wantDecision, tt.wantReason, tx.RoutingDecision, tx.RoutingReason)
+			}
+			if repo.metadata.RoutingReason == nil || *repo.metadata.RoutingReason != tt.wantReason {
+				t.Fatalf("expected reason %s to be persisted, got %+v", tt.wantReason, repo.metadata)
+			}
+		})
+	}
+}
+
+func TestProcessP2PTransfer_RejectsUnknownDelivery(t *testing.T) {
+	svc, repo := newP2PRoutingTestService(t)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Lunch money",
+		Delivery:          "external",
+	})
+	if !errors.Is(err, ErrInvalidDelivery) {
+		t.Fatalf("expected ErrInvalidDelivery, got %v", err)
+	}
+	if repo.debits != 0 {
+		t.Fatalf("expected no debit for an invalid request, got %d", repo.debits)
+	}
+}
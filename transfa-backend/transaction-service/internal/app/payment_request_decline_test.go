@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// declineRequestRepoStub holds one individual payment request and mirrors the
+// recipient and status checks of the Postgres decline query.
+type declineRequestRepoStub struct {
+	store.Repository
+
+	request       domain.PaymentRequest
+	notifications []domain.InAppNotification
+}
+
+func (s *declineRequestRepoStub) DeclineIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, reason *string) (*domain.PaymentRequest, error) {
+	if s.request.ID != requestID || s.request.RecipientUserID == nil || *s.request.RecipientUserID != recipientID || s.request.Status != "pending" {
+		return nil, store.ErrPaymentRequestNotReady
+	}
+	now := time.Now()
+	s.request.Status = "declined"
+	s.request.DeclinedReason = reason
+	s.request.DeclinedAt = &now
+	s.request.RespondedAt = &now
+	declined := s.request
+	return &declined, nil
+}
+
+func (s *declineRequestRepoStub) GetIncomingPaymentRequestByID(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error) {
+	if s.request.ID != requestID || s.request.RecipientUserID == nil || *s.request.RecipientUserID != recipientID {
+		return nil, nil
+	}
+	request := s.request
+	return &request, nil
+}
+
+func (s *declineRequestRepoStub) CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error {
+	s.notifications = append(s.notifications, item)
+	return nil
+}
+
+func newDeclineTestService() (*Service, *declineRequestRepoStub, *capturingPublisher) {
+	recipientID := uuid.New()
+	recipientUsername := "bob"
+	repo := &declineRequestRepoStub{request: domain.PaymentRequest{
+		ID:                uuid.New(),
+		CreatorID:         uuid.New(),
+		Status:            "pending",
+		RequestType:       "individual",
+		Title:             "Dinner",
+		RecipientUserID:   &recipientID,
+		RecipientUsername: &recipientUsername,
+		Amount:            250000,
+	}}
+	publisher := newCapturingPublisher()
+	return &Service{repo: repo, eventProducer: publisher}, repo, publisher
+}
+
+func TestDeclineIncomingPaymentRequest_PublishesDeclinedEvent(t *testing.T) {
+	svc, repo, publisher := newDeclineTestService()
+	recipientID := *repo.request.RecipientUserID
+	reason := "  Already paid you in cash  "
+
+	declined, err := svc.DeclineIncomingPaymentRequest(context.Background(), repo.request.ID, recipientID, &reason)
+	if err != nil {
+		t.Fatalf("expected the request to be declined, got %v", err)
+	}
+	if declined.Status != "declined" || declined.DeclinedAt == nil || declined.DeclinedReason == nil || *declined.DeclinedReason != "Already paid you in cash" {
+		t.Fatalf("unexpected declined request %+v", declined)
+	}
+	if len(repo.notifications) != 1 || repo.notifications[0].UserID != repo.request.CreatorID {
+		t.Fatalf("expected the creator to be notified, got %+v", repo.notifications)
+	}
+
+	var event publishedEvent
+	select {
+	case event = <-publisher.published:
+	case <-time.After(time.Second):
+		t.Fatal("expected payment_request.declined event to be published")
+	}
+	if event.exchange != "transfa.events" || event.routingKey != "payment_request.declined" {
+		t.Fatalf("expected payment_request.declined on transfa.events, got %q on %q", event.routingKey, event.exchange)
+	}
+	payload, ok := event.body.(domain.PaymentRequestDeclinedPayload)
+	if !ok {
+		t.Fatalf("expected PaymentRequestDeclinedPayload, got %T", event.body)
+	}
+	if payload.RequestID != repo.request.ID || payload.CreatorID != repo.request.CreatorID || payload.RecipientID != recipientID || payload.Amount != 250000 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if payload.Reason == nil || *payload.Reason != "Already paid you in cash" || !payload.DeclinedAt.Equal(*declined.DeclinedAt) {
+		t.Fatalf("expected the reason and decline time in the payload, got %+v", payload)
+	}
+}
+
+func TestDeclineIncomingPaymentRequest_OnlyRecipientCanDecline(t *testing.T) {
+	svc, repo, publisher := newDeclineTestService()
+
+	_, err := svc.DeclineIncomingPaymentRequest(context.Background(), repo.request.ID, repo.request.CreatorID, nil)
+	if !errors.Is(err, ErrPaymentRequestNotFound) {
+		t.Fatalf("expected ErrPaymentRequestNotFound for someone other than the recipient, got %v", err)
+	}
+	if repo.request.Status != "pending" {
+		t.Fatalf("expected the request to stay pending, got %s", repo.request.Status)
+	}
+	select {
+	case event := <-publisher.published:
+		t.Fatalf("expected no event, got %q", event.routingKey)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDeclineIncomingPaymentRequest_RejectsRequestThatIsNotPending(t *testing.T) {
+	for _, status := range []string{"processing", "fulfilled", "declined"} {
+		svc, repo, publisher := newDeclineTestService()
+		repo.request.Status = status
+
+		_, err := svc.DeclineIncomingPaymentRequest(context.Background(), repo.request.ID, *repo.request.RecipientUserID, nil)
+		if !errors.Is(err, ErrPaymentRequestNotPending) {
+			t.Fatalf("%s: expected ErrPaymentRequestNotPending, got %v", status, err)
+		}
+		if repo.request.Status != status || len(repo.notifications) != 0 {
+			t.Fatalf("%s: expected the request to be left alone, got %s with %d notifications", status, repo.request.Status, len(repo.notifications))
+		}
+		select {
+		case event := <-publisher.published:
+			t.Fatalf("%s: expected no event, got %q", status, event.routingKey)
+		default:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// deleteRequestRepoStub holds one payment request and mirrors the owner, deleted and
+// processing checks of the Postgres soft-delete query.
+type deleteRequestRepoStub struct {
+	store.Repository
+
+	request domain.PaymentRequest
+}
+
+func (s *deleteRequestRepoStub) SoftDeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error) {
+	if s.request.ID != requestID || s.request.CreatorID != creatorID || s.request.DeletedAt != nil {
+		return false, nil
+	}
+	if s.request.Status == "processing" {
+		return false, store.ErrPaymentRequestInProcessing
+	}
+	now := time.Now()
+	s.request.DeletedAt = &now
+	return true, nil
+}
+
+func newDeleteTestService() (*Service, *deleteRequestRepoStub, *capturingPublisher) {
+	repo := &deleteRequestRepoStub{request: domain.PaymentRequest{
+		ID:          uuid.New(),
+		CreatorID:   uuid.New(),
+		Status:      "pending",
+		RequestType: "individual",
+		Title:       "Dinner",
+		Amount:      250000,
+	}}
+	publisher := newCapturingPublisher()
+	return &Service{repo: repo, eventProducer: publisher}, repo, publisher
+}
+
+func expectNoPaymentRequestEvent(t *testing.T, publisher *capturingPublisher) {
+	t.Helper()
+	select {
+	case event := <-publisher.published:
+		t.Fatalf("expected no event, got %q", event.routingKey)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDeletePaymentRequest_PublishesDeletedEvent(t *testing.T) {
+	svc, repo, publisher := newDeleteTestService()
+
+	deleted, err := svc.DeletePaymentRequest(context.Background(), repo.request.ID, repo.request.CreatorID)
+	if err != nil || !deleted {
+		t.Fatalf("expected the request to be deleted, got deleted=%v err=%v", deleted, err)
+	}
+	if repo.request.DeletedAt == nil {
+		t.Fatal("expected the request to be soft-deleted")
+	}
+
+	var event publishedEvent
+	select {
+	case event = <-publisher.published:
+	case <-time.After(time.Second):
+		t.Fatal("expected payment_request.deleted event to be published")
+	}
+	if event.exchange != "transfa.events" || event.routingKey != "payment_request.deleted" {
+		t.Fatalf("expected payment_request.deleted on transfa.events, got %q on %q", event.routingKey, event.exchange)
+	}
+	payload, ok := event.body.(domain.PaymentRequestDeletedPayload)
+	if !ok {
+		t.Fatalf("expected PaymentRequestDeletedPayload, got %T", event.body)
+	}
+	if payload.RequestID != repo.request.ID || payload.CreatorID != repo.request.CreatorID || payload.DeletedAt.IsZero() {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestDeletePaymentRequest_OnlyCreatorCanDelete(t *testing.T) {
+	svc, repo, publisher := newDeleteTestService()
+
+	deleted, err := svc.DeletePaymentRequest(context.Background(), repo.request.ID, uuid.New())
+	if err != nil || deleted {
+		t.Fatalf("expected nothing deleted for someone other than the creator, got deleted=%v err=%v", deleted, err)
+	}
+	if repo.request.DeletedAt != nil {
+		t.Fatal("expected the request to be left alone")
+	}
+	expectNoPaymentRequestEvent(t, publisher)
+}
+
+func TestDeletePaymentRequest_BlockedWhilePaymentInProgress(t *testing.T) {
+	svc, repo, publisher := newDeleteTestService()
+	repo.request.Status = "processing"
+
+	deleted, err := svc.DeletePaymentRequest(context.Background(), repo.request.ID, repo.request.CreatorID)
+	if !errors.Is(err, ErrPaymentRequestInProcessing) || deleted {
+		t.Fatalf("expected ErrPaymentRequestInProcessing, got deleted=%v err=%v", deleted, err)
+	}
+	if repo.request.DeletedAt != nil {
+		t.Fatal("expected the request to be left alone")
+	}
+	expectNoPaymentRequestEvent(t, publisher)
+}
+
+func TestDeletePaymentRequest_SecondDeleteReportsNothingDeleted(t *testing.T) {
+	svc, repo, publisher := newDeleteTestService()
+
+	if deleted, err := svc.DeletePaymentRequest(context.Background(), repo.request.ID, repo.request.CreatorID); err != nil || !deleted {
+		t.Fatalf("expected the first delete to succeed, got deleted=%v err=%v", deleted, err)
+	}
+	<-publisher.published
+
+	deleted, err := svc.DeletePaymentRequest(context.Background(), repo.request.ID, repo.request.CreatorID)
+	if err != nil || deleted {
+		t.Fatalf("expected the second delete to return false, nil, got deleted=%v err=%v", deleted, err)
+	}
+	expectNoPaymentRequestEvent(t, publisher)
+}
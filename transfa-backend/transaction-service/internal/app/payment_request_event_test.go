@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type paymentRequestRepoStub struct {
+	store.Repository
+
+	creator   *domain.User
+	recipient *domain.User
+}
+
+func (s *paymentRequestRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	creator := *s.creator
+	return &creator, nil
+}
+
+func (s *paymentRequestRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	recipient := *s.recipient
+	return &recipient, nil
+}
+
+func (s *paymentRequestRepoStub) CreatePaymentRequest(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentRequest, error) {
+	created := *req
+	created.CreatedAt = time.Now()
+	return &created, nil
+}
+
+func (s *paymentRequestRepoStub) CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error {
+	return nil
+}
+
+// capturingPublisher hands each published event, and the context it was published
+// with, to the test so background publishes can be awaited.
+type capturingPublisher struct {
+	recordingPublisher
+	published chan publishedEvent
+	ctxErr    chan error
+}
+
+func newCapturingPublisher() *capturingPublisher {
+	return &capturingPublisher{published: make(chan publishedEvent, 1), ctxErr: make(chan error, 1)}
+}
+
+func (p *capturingPublisher) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+	p.ctxErr <- ctx.Err()
+	p.published <- publishedEvent{exchange: exchange, routingKey: routingKey, body: body}
+	return nil
+}
+
+func newPaymentRequestTestService(publisher *capturingPublisher) (*Service, *paymentRequestRepoStub) {
+	repo := &paymentRequestRepoStub{
+		creator:   &domain.User{ID: uuid.New(), Username: "alice"},
+		recipient: &domain.User{ID: uuid.New(), Username: "bob"},
+	}
+	return &Service{repo: repo, eventProducer: publisher}, repo
+}
+
+func TestCreatePaymentRequest_PublishesPaymentRequestReceivedEvent(t *testing.T) {
+	publisher := newCapturingPublisher()
+	svc, repo := newPaymentRequestTestService(publisher)
+
+	// The caller's context is already cancelled by the time the event goes out,
+	// as happens once the HTTP handler has responded.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	recipient := "bob"
+	description := "Dinner on Friday"
+	created, err := svc.CreatePaymentRequest(ctx, repo.creator.ID, domain.CreatePaymentRequestPayload{
+		RequestType:       "individual",
+		Title:             "Dinner",
+		RecipientUsername: &recipient,
+		Amount:            250000,
+		Description:       &description,
+	})
+	if err != nil {
+		t.Fatalf("expected payment request to be created, got %v", err)
+	}
+
+	var event publishedEvent
+	select {
+	case event = <-publisher.published:
+	case <-time.After(time.Second):
+		t.Fatal("expected payment_request.received event to be published")
+	}
+	if ctxErr := <-publisher.ctxErr; ctxErr != nil {
+		t.Fatalf("expected publish context to be detached from the caller, got %v", ctxErr)
+	}
+	if event.exchange != "transfa.events" {
+		t.Fatalf("expected transfa.events exchange, got %q", event.exchange)
+	}
+	if event.routingKey != "payment_request.received" {
+		t.Fatalf("expected payment_request.received routing key, got %q", event.routingKey)
+	}
+
+	payload, ok := event.body.(domain.PaymentRequestReceivedPayload)
+	if !ok {
+		t.Fatalf("expected PaymentRequestReceivedPayload, got %T", event.body)
+	}
+	if payload.CreatorUsername != "alice" {
+		t.Fatalf("expected creator username alice, got %q", payload.CreatorUsername)
+	}
+	if payload.RecipientID != repo.recipient.ID {
+		t.Fatalf("expected recipient %s, got %s", repo.recipient.ID, payload.RecipientID)
+	}
+	if payload.Amount != 250000 {
+		t.Fatalf("expected amount 250000, got %d", payload.Amount)
+	}
+	if payload.Description == nil || *payload.Description != description {
+		t.Fatalf("expected description %q, got %v", description, payload.Description)
+	}
+	if payload.RequestID != created.ID {
+		t.Fatalf("expected request id %s, got %s", created.ID, payload.RequestID)
+	}
+}
+
+func TestCreatePaymentRequest_GeneralRequestPublishesNoEvent(t *testing.T) {
+	publisher := newCapturingPublisher()
+	svc, repo := newPaymentRequestTestService(publisher)
+
+	if _, err := svc.CreatePaymentRequest(context.Background(), repo.creator.ID, domain.CreatePaymentRequestPayload{
+		RequestType: "general",
+		Title:       "Group gift",
+		Amount:      100000,
+	}); err != nil {
+		t.Fatalf("expected payment request to be created, got %v", err)
+	}
+
+	select {
+	case event := <-publisher.published:
+		t.Fatalf("expected no event for a general request, got %q", event.routingKey)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
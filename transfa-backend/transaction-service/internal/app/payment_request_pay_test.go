@@ -0,0 +1,333 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// payRequestRepoStub adds one incoming payment request to the ledger stub and
+// stages its changes inside WithTx alongside the wallet and transaction writes.
+type payRequestRepoStub struct {
+	*ledgerRepoStub
+
+	request     domain.PaymentRequest
+	settlements []domain.PaymentRequestSettlement
+	attachErr   error
+}
+
+func (s *payRequestRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	staged := &payRequestRepoStub{
+		ledgerRepoStub: &ledgerRepoStub{
+			p2pTransferRepoStub:  s.p2pTransferRepoStub,
+			state:                s.state.clone(),
+			createTransactionErr: s.createTransactionErr,
+			creditWalletErr:      s.creditWalletErr,
+		},
+		request:     s.request,
+		settlements: append([]domain.PaymentRequestSettlement(nil), s.settlements...),
+		attachErr:   s.attachErr,
+	}
+	if err := fn(staged); err != nil {
+		return err
+	}
+	s.state = staged.state
+	s.request = staged.request
+	s.settlements = staged.settlements
+	return nil
+}
+
+func (s *payRequestRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	if userID == s.recipient.ID {
+		creator := *s.recipient
+		return &creator, nil
+	}
+	return s.p2pTransferRepoStub.FindUserByID(ctx, userID)
+}
+
+func (s *payRequestRepoStub) GetIncomingPaymentRequestByID(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error) {
+	if requestID != s.request.ID || recipientID != *s.request.RecipientUserID {
+		return nil, nil
+	}
+	request := s.request
+	return &request, nil
+}
+
+func (s *payRequestRepoStub) ClaimIncomingPaymentRequestForPayment(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, amount int64) (*domain.PaymentRequest, error) {
+	if s.request.Status != "pending" || s.request.AmountPaid+amount > s.request.Amount {
+		return nil, store.ErrPaymentRequestNotReady
+	}
+	now := time.Now()
+	s.request.Status = "processing"
+	s.request.ProcessingStarted = &now
+	request := s.request
+	return &request, nil
+}
+
+func (s *payRequestRepoStub) AttachProcessingPaymentRequestSettlementTransaction(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error) {
+	if s.attachErr != nil {
+		return nil, s.attachErr
+	}
+	if s.request.Status != "processing" {
+		return nil, store.ErrPaymentRequestNotReady
+	}
+	s.request.SettledTxID = &settledTransactionID
+	request := s.request
+	return &request, nil
+}
+
+func (s *payRequestRepoStub) ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error {
+	if s.request.Status == "processing" && s.request.SettledTxID != nil && *s.request.SettledTxID == settledTransactionID {
+		s.request.Status = "pending"
+		s.request.ProcessingStarted = nil
+		s.request.SettledTxID = nil
+	}
+	return nil
+}
+
+func (s *payRequestRepoStub) RecordPaymentRequestSettlement(ctx context.Context, requestID uuid.UUID, transactionID uuid.UUID, amount int64) error {
+	s.settlements = append(s.settlements, domain.PaymentRequestSettlement{
+		TransactionID: transactionID,
+		Amount:        amount,
+		Status:        "pending",
+		CreatedAt:     time.Now(),
+	})
+	return nil
+}
+
+func (s *payRequestRepoStub) ListPaymentRequestSettlements(ctx context.Context, requestID uuid.UUID) ([]domain.PaymentRequestSettlement, error) {
+	return s.settlements, nil
+}
+
+func newPayRequestTestService(t *testing.T, transferStatus int) (*Service, *payRequestRepoStub) {
+	t.Helper()
+	svc, ledger := newLedgerTestService(t, transferStatus)
+	payerID := ledger.sender.ID
+	repo := &payRequestRepoStub{
+		ledgerRepoStub: ledger,
+		request: domain.PaymentRequest{
+			ID:              uuid.New(),
+			CreatorID:       ledger.recipient.ID,
+			Status:          "pending",
+			RequestType:     "individual",
+			Title:           "Dinner",
+			RecipientUserID: &payerID,
+			Amount:          2000,
+			CreatedAt:       time.Now().Add(-time.Hour),
+		},
+	}
+	svc.repo = repo
+	svc.eventProducer = nil
+	return svc, repo
+}
+
+func payTestRequest(svc *Service, repo *payRequestRepoStub) (*domain.PayIncomingPaymentRequestResult, error) {
+	return payTestRequestAmount(svc, repo, 0)
+}
+
+func payTestRequestAmount(svc *Service, repo *payRequestRepoStub, amount int64) (*domain.PayIncomingPaymentRequestResult, error) {
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+	return svc.PayIncomingPaymentRequest(ctx, repo.request.ID, repo.sender.ID, amount)
+}
+
+func TestPayIncomingPaymentRequest_ClaimsWithTransferRecord(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	// Room for the synchronous transfer events published before the background one.
+	publisher := &capturingPublisher{published: make(chan publishedEvent, 4), ctxErr: make(chan error, 4)}
+	svc.eventProducer = publisher
+
+	result, err := payTestRequest(svc, repo)
+	if err != nil {
+		t.Fatalf("expected payment to start, got %v", err)
+	}
+
+	if repo.request.Status != "processing" || repo.request.SettledTxID == nil || *repo.request.SettledTxID != result.Transaction.ID {
+		t.Fatalf("expected the request to be processing with its transfer attached, got %+v", repo.request)
+	}
+	if _, ok := repo.state.transactions[result.Transaction.ID]; !ok {
+		t.Fatal("expected the transfer record to be committed")
+	}
+	if got := repo.state.balances[repo.sender.ID]; got != 7500 {
+		t.Fatalf("expected amount and fee to be debited, balance is %d", got)
+	}
+
+	for {
+		var event publishedEvent
+		select {
+		case event = <-publisher.published:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for payment_request.processing")
+		}
+		if event.routingKey != "payment_request.processing" {
+			continue
+		}
+		payload, ok := event.body.(domain.PaymentRequestProcessingPayload)
+		if !ok {
+			t.Fatalf("expected PaymentRequestProcessingPayload, got %T", event.body)
+		}
+		if payload.RequestID != repo.request.ID || payload.PayerID != repo.sender.ID || payload.CreatorID != repo.recipient.ID || payload.TransactionID != result.Transaction.ID {
+			t.Fatalf("unexpected payload %+v", payload)
+		}
+		return
+	}
+}
+
+func TestPayIncomingPaymentRequest_RecordFailureLeavesRequestPending(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	insertErr := errors.New("insert failed")
+	repo.createTransactionErr = insertErr
+
+	if _, err := payTestRequest(svc, repo); !errors.Is(err, insertErr) {
+		t.Fatalf("expected the insert error, got %v", err)
+	}
+	if repo.request.Status != "pending" || repo.request.SettledTxID != nil {
+		t.Fatalf("expected the request to stay pending, got %+v", repo.request)
+	}
+	if got := repo.state.balances[repo.sender.ID]; got != 10000 {
+		t.Fatalf("expected the debit to be rolled back, balance is %d", got)
+	}
+}
+
+func TestPayIncomingPaymentRequest_StatusUpdateFailureRollsBackTransfer(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	attachErr := errors.New("update failed")
+	repo.attachErr = attachErr
+
+	if _, err := payTestRequest(svc, repo); !errors.Is(err, attachErr) {
+		t.Fatalf("expected the update error, got %v", err)
+	}
+
+	// The claim, debit and transfer record share one transaction: none may survive.
+	if repo.request.Status != "pending" || repo.request.ProcessingStarted != nil {
+		t.Fatalf("expected the claim to be rolled back, got %+v", repo.request)
+	}
+	if len(repo.state.transactions) != 0 {
+		t.Fatalf("expected no transaction record, got %d", len(repo.state.transactions))
+	}
+	if got := repo.state.balances[repo.sender.ID]; got != 10000 {
+		t.Fatalf("expected the debit to be rolled back, balance is %d", got)
+	}
+}
+
+func TestPayIncomingPaymentRequest_AnchorFailureRevertsToPending(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusBadRequest)
+
+	if _, err := payTestRequest(svc, repo); err == nil {
+		t.Fatal("expected the payment to fail")
+	}
+	if repo.request.Status != "pending" || repo.request.SettledTxID != nil {
+		t.Fatalf("expected the request to be back to pending, got %+v", repo.request)
+	}
+	if got := repo.state.balances[repo.sender.ID]; got != 10000 {
+		t.Fatalf("expected the debit to be refunded, balance is %d", got)
+	}
+}
+
+func TestPayIncomingPaymentRequest_RejectsRequestThatIsNotPending(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	repo.request.Status = "declined"
+
+	if _, err := payTestRequest(svc, repo); !errors.Is(err, ErrPaymentRequestNotPending) {
+		t.Fatalf("expected ErrPaymentRequestNotPending, got %v", err)
+	}
+	if repo.debits != 0 || len(repo.state.transactions) != 0 {
+		t.Fatalf("expected no transfer, got %d debits and %d records", repo.debits, len(repo.state.transactions))
+	}
+}
+
+func TestPayIncomingPaymentRequest_UnknownRequestIsNotFound(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+	if _, err := svc.PayIncomingPaymentRequest(ctx, repo.request.ID, uuid.New(), 0); !errors.Is(err, ErrPaymentRequestNotFound) {
+		t.Fatalf("expected ErrPaymentRequestNotFound for another user's request, got %v", err)
+	}
+}
+
+func TestPayIncomingPaymentRequest_PartialPaymentRecordsSettlement(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	repo.request.AllowPartial = true
+
+	result, err := payTestRequestAmount(svc, repo, 500)
+	if err != nil {
+		t.Fatalf("expected the partial payment to start, got %v", err)
+	}
+
+	if result.Transaction.Amount != 500 {
+		t.Fatalf("expected a transfer for the partial amount, got %d", result.Transaction.Amount)
+	}
+	if got := repo.state.balances[repo.sender.ID]; got != 10000-500-result.Transaction.Fee {
+		t.Fatalf("expected only the partial amount and fee to be debited, balance is %d", got)
+	}
+	if len(repo.settlements) != 1 || repo.settlements[0].TransactionID != result.Transaction.ID || repo.settlements[0].Amount != 500 {
+		t.Fatalf("expected the transfer to be recorded as a settlement, got %+v", repo.settlements)
+	}
+	if repo.request.Status != "processing" || repo.request.AmountPaid != 0 {
+		t.Fatalf("expected the request to be processing with nothing credited yet, got %+v", repo.request)
+	}
+}
+
+func TestPayIncomingPaymentRequest_DefaultsToRemainingBalance(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	repo.request.AllowPartial = true
+	repo.request.AmountPaid = 1500
+
+	result, err := payTestRequest(svc, repo)
+	if err != nil {
+		t.Fatalf("expected the payment to start, got %v", err)
+	}
+	if result.Transaction.Amount != 500 {
+		t.Fatalf("expected a transfer for the remaining 500, got %d", result.Transaction.Amount)
+	}
+	if result.Request.RemainingAmount != 500 {
+		t.Fatalf("expected the remaining balance to be reported, got %d", result.Request.RemainingAmount)
+	}
+}
+
+func TestPayIncomingPaymentRequest_RejectsPartialPaymentWhenNotAllowed(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+
+	if _, err := payTestRequestAmount(svc, repo, 500); !errors.Is(err, ErrPartialPaymentNotAllowed) {
+		t.Fatalf("expected ErrPartialPaymentNotAllowed, got %v", err)
+	}
+	if repo.debits != 0 || len(repo.settlements) != 0 {
+		t.Fatalf("expected no transfer, got %d debits and %d settlements", repo.debits, len(repo.settlements))
+	}
+}
+
+func TestPayIncomingPaymentRequest_RejectsAmountAboveRemaining(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	repo.request.AllowPartial = true
+	repo.request.AmountPaid = 1500
+
+	if _, err := payTestRequestAmount(svc, repo, 600); !errors.Is(err, ErrPaymentAmountExceedsRemaining) {
+		t.Fatalf("expected ErrPaymentAmountExceedsRemaining, got %v", err)
+	}
+	if repo.debits != 0 {
+		t.Fatalf("expected no debit, got %d", repo.debits)
+	}
+}
+
+func TestGetIncomingPaymentRequestByID_ListsSettlements(t *testing.T) {
+	svc, repo := newPayRequestTestService(t, http.StatusCreated)
+	repo.request.AllowPartial = true
+	repo.request.AmountPaid = 500
+	repo.settlements = []domain.PaymentRequestSettlement{{TransactionID: uuid.New(), Amount: 500, Status: "completed", CreatedAt: time.Now()}}
+
+	request, err := svc.GetIncomingPaymentRequestByID(context.Background(), repo.request.ID, repo.sender.ID)
+	if err != nil {
+		t.Fatalf("GetIncomingPaymentRequestByID: %v", err)
+	}
+	if request.RemainingAmount != 1500 {
+		t.Fatalf("expected 1500 remaining, got %d", request.RemainingAmount)
+	}
+	if len(request.Settlements) != 1 || request.Settlements[0].Amount != 500 {
+		t.Fatalf("expected the settlement to be listed, got %+v", request.Settlements)
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+const (
+	paymentRequestShortCodeMinLength       = 6
+	paymentRequestShortCodeMaxLength       = 8
+	paymentRequestPublicRateLimitPerMinute = 60
+)
+
+// createPaymentRequestWithShortCode stores req through repo under a fresh short code,
+// retrying codes that are already taken. The code is optional: when none can be
+// allocated the request is stored without one and shares its long link instead.
+func (s *Service) createPaymentRequestWithShortCode(ctx context.Context, repo store.PaymentRequestStore, req *domain.PaymentRequest) (*domain.PaymentRequest, error) {
+	for attempt := 0; attempt < shortLinkCodeAttempts; attempt++ {
+		code, err := generateShortLinkCode()
+		if err != nil {
+			log.Printf("level=warn component=service flow=payment_request msg=\"short code generation failed\" request_id=%s err=%v", req.ID, err)
+			break
+		}
+
+		req.ShortCode = &code
+		created, err := repo.CreatePaymentRequest(ctx, req)
+		if errors.Is(err, store.ErrPaymentRequestShortCodeExists) {
+			continue
+		}
+		return created, err
+	}
+
+	log.Printf("level=warn component=service flow=payment_request msg=\"storing payment request without a short code\" request_id=%s", req.ID)
+	req.ShortCode = nil
+	return repo.CreatePaymentRequest(ctx, req)
+}
+
+// GetPublicPaymentRequest returns the public view of a payment request for its pay
+// page. Deleted requests return store.ErrPaymentRequestNotFound.
+func (s *Service) GetPublicPaymentRequest(ctx context.Context, requestID uuid.UUID) (*domain.PublicPaymentRequest, error) {
+	req, err := s.repo.GetPublicPaymentRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.publicPaymentRequest(req), nil
+}
+
+// GetPublicPaymentRequestByShortCode is GetPublicPaymentRequest for a short link code.
+func (s *Service) GetPublicPaymentRequestByShortCode(ctx context.Context, code string) (*domain.PublicPaymentRequest, error) {
+	if !isPaymentRequestShortCode(code) {
+		return nil, store.ErrPaymentRequestNotFound
+	}
+	req, err := s.repo.GetPublicPaymentRequestByShortCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.publicPaymentRequest(req), nil
+}
+
+// EnforcePaymentRequestPublicRateLimit limits unauthenticated payment request lookups
+// per client address.
+func (s *Service) EnforcePaymentRequestPublicRateLimit(ctx context.Context, clientAddress string) (int, error) {
+	return s.enforceRateLimit(
+		ctx,
+		"payment_request_public",
+		clientAddress,
+		paymentRequestPublicRateLimitPerMinute,
+		time.Minute,
+		"Too many payment request lookups. Please try again shortly.",
+	)
+}
+
+func (s *Service) publicPaymentRequest(req *domain.PaymentRequest) *domain.PublicPaymentRequest {
+	req = s.decoratePaymentRequest(req)
+	return &domain.PublicPaymentRequest{
+		ID:              req.ID,
+		ShortCode:       req.ShortCode,
+		CreatorUsername: req.CreatorUsername,
+		Title:           req.Title,
+		Amount:          req.Amount,
+		RemainingAmount: req.RemainingAmount,
+		Description:     req.Description,
+		Status:          req.DisplayStatus,
+	}
+}
+
+// paymentRequestShareableLink is the web pay page for a request, on its short code
+// when it has one.
+func (s *Service) paymentRequestShareableLink(req *domain.PaymentRequest) string {
+	if req.ShortCode != nil && *req.ShortCode != "" {
+		return fmt.Sprintf("%s/pay/%s", s.moneyDropShareBaseURL, *req.ShortCode)
+	}
+	return fmt.Sprintf("%s/pay?request_id=%s", s.moneyDropShareBaseURL, req.ID)
+}
+
+// paymentRequestDeepLink opens the request in the app, as transfa://claim-drop/...
+// does for money drops. QR codes carry it so scanning goes straight to payment.
+func paymentRequestDeepLink(req *domain.PaymentRequest) string {
+	return fmt.Sprintf("transfa://pay-request/%s", req.ID)
+}
+
+func isPaymentRequestShortCode(code string) bool {
+	if len(code) < paymentRequestShortCodeMinLength || len(code) > paymentRequestShortCodeMaxLength {
+		return false
+	}
+	for _, c := range code {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
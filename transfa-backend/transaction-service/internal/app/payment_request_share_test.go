@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// shortCodeRepoStub rejects the first short codes it is given like the unique index
+// on payment_requests.short_code.
+type shortCodeRepoStub struct {
+	store.Repository
+
+	collisions int
+	attempts   []*string
+}
+
+func (s *shortCodeRepoStub) CreatePaymentRequest(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentRequest, error) {
+	s.attempts = append(s.attempts, req.ShortCode)
+	if req.ShortCode != nil && s.collisions > 0 {
+		s.collisions--
+		return nil, store.ErrPaymentRequestShortCodeExists
+	}
+	created := *req
+	return &created, nil
+}
+
+func newShortCodeTestService(collisions int) (*Service, *shortCodeRepoStub) {
+	repo := &shortCodeRepoStub{collisions: collisions}
+	return &Service{repo: repo, moneyDropShareBaseURL: "https://trytransfa.com"}, repo
+}
+
+func createGeneralPaymentRequest(t *testing.T, svc *Service) *domain.PaymentRequest {
+	t.Helper()
+	created, err := svc.CreatePaymentRequest(context.Background(), uuid.New(), domain.CreatePaymentRequestPayload{
+		RequestType: "general",
+		Title:       "Dinner",
+		Amount:      250000,
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentRequest: %v", err)
+	}
+	return created
+}
+
+func TestCreatePaymentRequest_RetriesTakenShortCodes(t *testing.T) {
+	svc, repo := newShortCodeTestService(2)
+
+	created := createGeneralPaymentRequest(t, svc)
+
+	if len(repo.attempts) != 3 {
+		t.Fatalf("expected two retries after collisions, got %d attempts", len(repo.attempts))
+	}
+	if created.ShortCode == nil || !isPaymentRequestShortCode(*created.ShortCode) {
+		t.Fatalf("expected a valid short code, got %v", created.ShortCode)
+	}
+	if want := "https://trytransfa.com/pay/" + *created.ShortCode; created.ShareableLink != want {
+		t.Fatalf("expected shareable link %q, got %q", want, created.ShareableLink)
+	}
+	if want := "transfa://pay-request/" + created.ID.String(); created.QRCodeContent != want {
+		t.Fatalf("expected QR code content %q, got %q", want, created.QRCodeContent)
+	}
+}
+
+func TestCreatePaymentRequest_StoresWithoutShortCodeWhenAllTaken(t *testing.T) {
+	svc, repo := newShortCodeTestService(shortLinkCodeAttempts)
+
+	created := createGeneralPaymentRequest(t, svc)
+
+	if last := repo.attempts[len(repo.attempts)-1]; last != nil {
+		t.Fatalf("expected the final attempt without a short code, got %q", *last)
+	}
+	if created.ShortCode != nil {
+		t.Fatalf("expected no short code, got %q", *created.ShortCode)
+	}
+	if !strings.HasSuffix(created.ShareableLink, "/pay?request_id="+created.ID.String()) {
+		t.Fatalf("expected the long link, got %q", created.ShareableLink)
+	}
+}
+
+func TestIsPaymentRequestShortCode(t *testing.T) {
+	for code, want := range map[string]bool{
+		"Ab3dE6":    true,
+		"Ab3dE6gH":  true,
+		"Ab3dE":     false,
+		"Ab3dE6gHi": false,
+		"Ab3-E6gH":  false,
+		"":          false,
+	} {
+		if got := isPaymentRequestShortCode(code); got != want {
+			t.Fatalf("isPaymentRequestShortCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
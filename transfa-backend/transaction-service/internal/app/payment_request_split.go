@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+const maxPaymentRequestSplitRecipients = 20
+
+// CreatePaymentRequestSplit splits a bill among several users by creating one
+// individual payment request per recipient, all linked to a parent split record.
+// The requests are created together or not at all, and are then paid and declined
+// like any other individual request.
+func (s *Service) CreatePaymentRequestSplit(ctx context.Context, creatorID uuid.UUID, payload domain.CreatePaymentRequestSplitPayload) (*domain.PaymentRequestSplit, error) {
+	title := strings.TrimSpace(payload.Title)
+	description := normalizeOptionalString(payload.Description)
+	strategy := strings.ToLower(strings.TrimSpace(payload.Strategy))
+
+	if err := validatePaymentRequestDetails(title, description); err != nil {
+		return nil, err
+	}
+	if payload.TotalAmount <= 0 {
+		return nil, ErrInvalidTransferAmount
+	}
+	if strategy != "equal" && strategy != "custom" {
+		return nil, ErrInvalidPaymentRequestSplitStrategy
+	}
+	if len(payload.Recipients) == 0 || len(payload.Recipients) > maxPaymentRequestSplitRecipients {
+		return nil, ErrInvalidPaymentRequestSplitRecipients
+	}
+
+	seen := make(map[string]struct{}, len(payload.Recipients))
+	for _, recipient := range payload.Recipients {
+		username, err := normalizeAndValidateUsernameInput(recipient.Username)
+		if err != nil {
+			return nil, ErrInvalidPaymentRequestRecipient
+		}
+		if _, exists := seen[username]; exists {
+			return nil, ErrDuplicatePaymentRequestSplitRecipient
+		}
+		seen[username] = struct{}{}
+	}
+
+	amounts, err := paymentRequestSplitAmounts(strategy, payload.TotalAmount, payload.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]*domain.User, len(payload.Recipients))
+	for i, recipient := range payload.Recipients {
+		user, err := s.resolvePaymentRequestRecipient(ctx, creatorID, recipient.Username)
+		if err != nil {
+			return nil, err
+		}
+		recipients[i] = user
+	}
+
+	var split *domain.PaymentRequestSplit
+	requests := make([]*domain.PaymentRequest, 0, len(recipients))
+	err = s.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		created, err := txRepo.CreatePaymentRequestSplit(ctx, &domain.PaymentRequestSplit{
+			ID:          uuid.New(),
+			CreatorID:   creatorID,
+			Title:       title,
+			Description: description,
+			TotalAmount: payload.TotalAmount,
+			Strategy:    strategy,
+		})
+		if err != nil {
+			return err
+		}
+		split = created
+
+		for i, recipient := range recipients {
+			splitID := split.ID
+			child := &domain.PaymentRequest{
+				ID:          uuid.New(),
+				CreatorID:   creatorID,
+				Status:      "pending",
+				RequestType: "individual",
+				Title:       title,
+				Amount:      amounts[i],
+				Description: description,
+				SplitID:     &splitID,
+			}
+			setPaymentRequestRecipient(child, recipient)
+
+			createdChild, err := s.createPaymentRequestWithShortCode(ctx, txRepo, child)
+			if err != nil {
+				return err
+			}
+			requests = append(requests, createdChild)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	creator, creatorErr := s.repo.FindUserByID(ctx, creatorID)
+	if creatorErr != nil {
+		log.Printf("level=warn component=service flow=payment_request_split msg=\"creator lookup failed for notification\" creator_id=%s split_id=%s err=%v", creatorID, split.ID, creatorErr)
+	}
+
+	split.Members = make([]domain.PaymentRequestSplitMember, 0, len(requests))
+	for _, req := range requests {
+		decorated := s.decoratePaymentRequest(req)
+		if creator != nil {
+			s.notifyIncomingPaymentRequest(ctx, "create_payment_request_split", creator, decorated)
+		}
+		split.Members = append(split.Members, domain.PaymentRequestSplitMember{
+			RequestID:       decorated.ID,
+			RecipientUserID: decorated.RecipientUserID,
+			Username:        decorated.RecipientUsername,
+			FullName:        decorated.RecipientFullName,
+			Amount:          decorated.Amount,
+			AmountPaid:      decorated.AmountPaid,
+			RemainingAmount: decorated.RemainingAmount,
+			Status:          decorated.DisplayStatus,
+		})
+	}
+
+	return split, nil
+}
+
+// GetPaymentRequestSplit returns a creator's split with each member's status and the
+// amount collected across all of its requests.
+func (s *Service) GetPaymentRequestSplit(ctx context.Context, splitID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequestSplit, error) {
+	split, err := s.repo.GetPaymentRequestSplit(ctx, splitID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListPaymentRequestSplitMembers(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		req := &domain.PaymentRequest{
+			Status:     members[i].Status,
+			Amount:     members[i].Amount,
+			AmountPaid: members[i].AmountPaid,
+		}
+		members[i].RemainingAmount = paymentRequestRemaining(req)
+		members[i].Status = paymentRequestDisplayStatus(req.Status)
+		split.CollectedAmount += members[i].Amount - members[i].RemainingAmount
+	}
+	split.Members = members
+
+	return split, nil
+}
+
+// paymentRequestSplitAmounts returns each recipient's share in list order. Equal
+// splits give the remainder of the division one unit at a time to the first
+// recipients, so the same list always splits the same way.
+func paymentRequestSplitAmounts(strategy string, total int64, recipients []domain.PaymentRequestSplitRecipient) ([]int64, error) {
+	amounts := make([]int64, len(recipients))
+
+	if strategy == "equal" {
+		count := int64(len(recipients))
+		share := total / count
+		if share == 0 {
+			return nil, ErrInvalidPaymentRequestSplitAmounts
+		}
+		remainder := total % count
+		for i := range amounts {
+			amounts[i] = share
+			if int64(i) < remainder {
+				amounts[i]++
+			}
+		}
+		return amounts, nil
+	}
+
+	var sum int64
+	for i, recipient := range recipients {
+		if recipient.Amount <= 0 || recipient.Amount > total {
+			return nil, ErrInvalidPaymentRequestSplitAmounts
+		}
+		amounts[i] = recipient.Amount
+		sum += recipient.Amount
+	}
+	if sum != total {
+		return nil, ErrInvalidPaymentRequestSplitAmounts
+	}
+	return amounts, nil
+}
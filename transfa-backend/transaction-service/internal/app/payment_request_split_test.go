@@ -0,0 +1,208 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// paymentRequestSplitRepoStub knows a fixed set of users and records the split and
+// payment requests it is asked to create.
+type paymentRequestSplitRepoStub struct {
+	store.Repository
+
+	users         map[string]*domain.User
+	split         *domain.PaymentRequestSplit
+	requests      []*domain.PaymentRequest
+	notifications []domain.InAppNotification
+	members       []domain.PaymentRequestSplitMember
+}
+
+func newPaymentRequestSplitRepoStub(usernames ...string) *paymentRequestSplitRepoStub {
+	repo := &paymentRequestSplitRepoStub{users: map[string]*domain.User{}}
+	for _, username := range append(usernames, "creator") {
+		repo.users[username] = &domain.User{ID: uuid.New(), Username: username}
+	}
+	return repo
+}
+
+func (s *paymentRequestSplitRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
+func (s *paymentRequestSplitRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if user, ok := s.users[username]; ok {
+		return user, nil
+	}
+	return nil, store.ErrUserNotFound
+}
+
+func (s *paymentRequestSplitRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.ID == userID {
+			return user, nil
+		}
+	}
+	return nil, store.ErrUserNotFound
+}
+
+func (s *paymentRequestSplitRepoStub) CreatePaymentRequestSplit(ctx context.Context, split *domain.PaymentRequestSplit) (*domain.PaymentRequestSplit, error) {
+	created := *split
+	s.split = &created
+	return &created, nil
+}
+
+func (s *paymentRequestSplitRepoStub) CreatePaymentRequest(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentRequest, error) {
+	created := *req
+	s.requests = append(s.requests, &created)
+	return &created, nil
+}
+
+func (s *paymentRequestSplitRepoStub) CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error {
+	s.notifications = append(s.notifications, item)
+	return nil
+}
+
+func (s *paymentRequestSplitRepoStub) GetPaymentRequestSplit(ctx context.Context, splitID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequestSplit, error) {
+	if s.split == nil || s.split.ID != splitID || s.split.CreatorID != creatorID {
+		return nil, store.ErrPaymentRequestSplitNotFound
+	}
+	split := *s.split
+	return &split, nil
+}
+
+func (s *paymentRequestSplitRepoStub) ListPaymentRequestSplitMembers(ctx context.Context, splitID uuid.UUID) ([]domain.PaymentRequestSplitMember, error) {
+	return append([]domain.PaymentRequestSplitMember(nil), s.members...), nil
+}
+
+func splitRecipients(usernames ...string) []domain.PaymentRequestSplitRecipient {
+	recipients := make([]domain.PaymentRequestSplitRecipient, len(usernames))
+	for i, username := range usernames {
+		recipients[i] = domain.PaymentRequestSplitRecipient{Username: username}
+	}
+	return recipients
+}
+
+func TestCreatePaymentRequestSplit_EqualSplitGivesRemainderToFirstRecipients(t *testing.T) {
+	repo := newPaymentRequestSplitRepoStub("ada", "bola", "chidi")
+	svc := &Service{repo: repo}
+
+	split, err := svc.CreatePaymentRequestSplit(context.Background(), repo.users["creator"].ID, domain.CreatePaymentRequestSplitPayload{
+		Title:       "Dinner",
+		TotalAmount: 10000,
+		Strategy:    "equal",
+		Recipients:  splitRecipients("ada", "bola", "chidi"),
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentRequestSplit: %v", err)
+	}
+
+	want := []int64{3334, 3333, 3333}
+	if len(repo.requests) != len(want) {
+		t.Fatalf("expected %d payment requests, got %d", len(want), len(repo.requests))
+	}
+	for i, req := range repo.requests {
+		if req.Amount != want[i] {
+			t.Fatalf("request %d: expected amount %d, got %d", i, want[i], req.Amount)
+		}
+		if req.SplitID == nil || *req.SplitID != split.ID {
+			t.Fatalf("request %d: expected split_id %s, got %v", i, split.ID, req.SplitID)
+		}
+		if req.RequestType != "individual" || req.RecipientUserID == nil {
+			t.Fatalf("request %d: expected an individual request with a recipient, got %+v", i, req)
+		}
+	}
+	if len(split.Members) != 3 || split.Members[0].Status != "pending" {
+		t.Fatalf("expected three pending members, got %+v", split.Members)
+	}
+	if len(repo.notifications) != 3 {
+		t.Fatalf("expected each recipient to be notified, got %d notifications", len(repo.notifications))
+	}
+}
+
+func TestCreatePaymentRequestSplit_RejectsInvalidInput(t *testing.T) {
+	cases := map[string]struct {
+		payload domain.CreatePaymentRequestSplitPayload
+		want    error
+	}{
+		"duplicate usernames": {
+			payload: domain.CreatePaymentRequestSplitPayload{Strategy: "equal", Recipients: splitRecipients("ada", " ADA ")},
+			want:    ErrDuplicatePaymentRequestSplitRecipient,
+		},
+		"custom amounts not adding up": {
+			payload: domain.CreatePaymentRequestSplitPayload{Strategy: "custom", Recipients: []domain.PaymentRequestSplitRecipient{
+				{Username: "ada", Amount: 6000},
+				{Username: "bola", Amount: 3000},
+			}},
+			want: ErrInvalidPaymentRequestSplitAmounts,
+		},
+		"equal share below one unit": {
+			payload: domain.CreatePaymentRequestSplitPayload{Strategy: "equal", TotalAmount: 1, Recipients: splitRecipients("ada", "bola")},
+			want:    ErrInvalidPaymentRequestSplitAmounts,
+		},
+		"unknown strategy": {
+			payload: domain.CreatePaymentRequestSplitPayload{Strategy: "weighted", Recipients: splitRecipients("ada")},
+			want:    ErrInvalidPaymentRequestSplitStrategy,
+		},
+		"creator in the list": {
+			payload: domain.CreatePaymentRequestSplitPayload{Strategy: "equal", Recipients: splitRecipients("ada", "creator")},
+			want:    ErrSelfPaymentRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := newPaymentRequestSplitRepoStub("ada", "bola")
+			svc := &Service{repo: repo}
+			tc.payload.Title = "Dinner"
+			if tc.payload.TotalAmount == 0 {
+				tc.payload.TotalAmount = 10000
+			}
+
+			_, err := svc.CreatePaymentRequestSplit(context.Background(), repo.users["creator"].ID, tc.payload)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+			if repo.split != nil || len(repo.requests) != 0 {
+				t.Fatalf("expected nothing to be created, got split %v and %d requests", repo.split, len(repo.requests))
+			}
+		})
+	}
+}
+
+func TestGetPaymentRequestSplit_AggregatesMemberProgress(t *testing.T) {
+	repo := newPaymentRequestSplitRepoStub()
+	creatorID := repo.users["creator"].ID
+	repo.split = &domain.PaymentRequestSplit{ID: uuid.New(), CreatorID: creatorID, TotalAmount: 9000, Strategy: "equal"}
+	repo.members = []domain.PaymentRequestSplitMember{
+		{RequestID: uuid.New(), Amount: 3000, AmountPaid: 3000, Status: "fulfilled"},
+		{RequestID: uuid.New(), Amount: 3000, AmountPaid: 1000, Status: "pending"},
+		{RequestID: uuid.New(), Amount: 3000, Status: "declined"},
+	}
+	svc := &Service{repo: repo}
+
+	split, err := svc.GetPaymentRequestSplit(context.Background(), repo.split.ID, creatorID)
+	if err != nil {
+		t.Fatalf("GetPaymentRequestSplit: %v", err)
+	}
+
+	if split.CollectedAmount != 4000 {
+		t.Fatalf("expected collected amount 4000, got %d", split.CollectedAmount)
+	}
+	for i, want := range []string{"paid", "pending", "declined"} {
+		if split.Members[i].Status != want {
+			t.Fatalf("member %d: expected status %q, got %q", i, want, split.Members[i].Status)
+		}
+	}
+	if split.Members[1].RemainingAmount != 2000 {
+		t.Fatalf("expected remaining amount 2000, got %d", split.Members[1].RemainingAmount)
+	}
+
+	if _, err := svc.GetPaymentRequestSplit(context.Background(), repo.split.ID, uuid.New()); !errors.Is(err, store.ErrPaymentRequestSplitNotFound) {
+		t.Fatalf("expected ErrPaymentRequestSplitNotFound for another user, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+func TestProcessP2PTransfer_PlatformFeeEnforcement(t *testing.T) {
+	tests := []struct {
+		name                string
+		mode                string
+		recipientDelinquent bool
+		wantErr             error
+		wantDebits          int
+	}{
+		{name: "enforce rejects delinquent sender", mode: PlatformFeeEnforcementEnforce, wantErr: store.ErrPlatformFeeDelinquent},
+		{name: "log-only lets delinquent sender through", mode: PlatformFeeEnforcementLogOnly, wantDebits: 1},
+		{name: "unset mode behaves as log-only", wantDebits: 1},
+		{name: "enforce still lets delinquent recipient receive", mode: PlatformFeeEnforcementEnforce, recipientDelinquent: true, wantDebits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+			svc.ConfigurePlatformFeeEnforcement(tt.mode)
+			delinquentID := repo.sender.ID
+			if tt.recipientDelinquent {
+				delinquentID = repo.recipient.ID
+			}
+			repo.delinquent = map[uuid.UUID]bool{delinquentID: true}
+			ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+			_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+				RecipientUsername: "bob",
+				Amount:            5000,
+				Description:       "Airtime",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.debits != tt.wantDebits {
+				t.Fatalf("expected %d debits, got %d", tt.wantDebits, repo.debits)
+			}
+		})
+	}
+}
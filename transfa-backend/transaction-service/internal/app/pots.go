@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// TransferPotFunds moves funds between the user's primary account and one of their pots
+// via an Anchor book transfer. Both accounts belong to the same user, so no fee is charged.
+func (s *Service) TransferPotFunds(ctx context.Context, userID uuid.UUID, potID uuid.UUID, req domain.PotTransferRequest) (*domain.Transaction, error) {
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Amount <= 0 {
+		return nil, ErrInvalidTransferAmount
+	}
+	if req.Direction != domain.PotTransferToPot && req.Direction != domain.PotTransferFromPot {
+		return nil, ErrInvalidPotTransferDirection
+	}
+	if req.Description != "" && !isValidTransferDescription(req.Description) {
+		return nil, ErrInvalidDescription
+	}
+
+	primaryAccount, err := s.repo.FindAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find primary account: %w", err)
+	}
+	pot, err := s.repo.FindPotByIDAndUserID(ctx, potID, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrAccountNotFound) {
+			return nil, ErrPotNotFound
+		}
+		return nil, fmt.Errorf("failed to find pot: %w", err)
+	}
+	if pot.AnchorAccountID == "" {
+		return nil, fmt.Errorf("pot %s has no Anchor account", pot.ID)
+	}
+
+	source, destination := primaryAccount, pot
+	defaultDescription := "Transfer to pot"
+	if req.Direction == domain.PotTransferFromPot {
+		source, destination = pot, primaryAccount
+		defaultDescription = "Transfer from pot"
+	}
+	if req.Description == "" {
+		req.Description = defaultDescription
+	}
+
+	if req.Direction == domain.PotTransferToPot {
+		// Sync the internal primary balance with Anchor before the ledger move validates funds.
+		if err := s.syncAccountBalance(ctx, userID); err != nil {
+			log.Printf("level=warn component=service flow=pot_transfer msg=\"balance sync failed\" user_id=%s err=%v", userID, err)
+		}
+	}
+
+	// 1. Move funds in the ledger first so the source balance is locked and validated.
+	if err := s.repo.MoveFundsBetweenAccounts(ctx, source.ID, destination.ID, req.Amount); err != nil {
+		return nil, fmt.Errorf("failed to move funds: %w", err)
+	}
+
+	// 2. Book transfer between the two Anchor accounts.
+	if _, err := s.anchorClient.InitiateBookTransfer(ctx, source.AnchorAccountID, destination.AnchorAccountID, req.Description, req.Amount); err != nil {
+		if revertErr := s.repo.MoveFundsBetweenAccounts(ctx, destination.ID, source.ID, req.Amount); revertErr != nil {
+			log.Printf("level=error component=service flow=pot_transfer msg=\"ledger revert failed after anchor transfer error\" user_id=%s pot_id=%s err=%v", userID, pot.ID, revertErr)
+		}
+		return nil, fmt.Errorf("anchor book transfer failed: %w", err)
+	}
+
+	// 3. Log the transfer. Funds have already moved, so a logging failure is not fatal.
+	destinationID := destination.ID
+	txRecord := &domain.Transaction{
+		ID:                   uuid.New(),
+		SenderID:             userID,
+		SourceAccountID:      source.ID,
+		DestinationAccountID: &destinationID,
+		Type:                 "pot_transfer",
+		Category:             "Savings Pot",
+		Status:               "completed",
+		Amount:               req.Amount,
+		Fee:                  0,
+		Description:          req.Description,
+		TransferType:         "book",
+	}
+	if err := s.repo.CreateTransaction(ctx, txRecord); err != nil {
+		log.Printf("level=warn component=service flow=pot_transfer msg=\"failed to persist pot transfer log\" user_id=%s pot_id=%s err=%v", userID, pot.ID, err)
+	}
+
+	log.Printf("level=info component=service flow=pot_transfer msg=\"pot transfer completed\" user_id=%s pot_id=%s direction=%s amount=%d", userID, pot.ID, req.Direction, req.Amount)
+	return txRecord, nil
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type fundsMove struct {
+	source      uuid.UUID
+	destination uuid.UUID
+	amount      int64
+}
+
+type potTransferRepoStub struct {
+	store.Repository
+
+	primary *domain.Account
+	pot     *domain.Account
+
+	moveErr   error
+	moves     []fundsMove
+	createdTx *domain.Transaction
+}
+
+func (s *potTransferRepoStub) FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
+	return s.primary, nil
+}
+
+func (s *potTransferRepoStub) FindPotByIDAndUserID(ctx context.Context, potID uuid.UUID, userID uuid.UUID) (*domain.Account, error) {
+	if s.pot == nil || s.pot.ID != potID {
+		return nil, store.ErrAccountNotFound
+	}
+	return s.pot, nil
+}
+
+func (s *potTransferRepoStub) SyncAccountBalance(ctx context.Context, snapshot domain.Account, balance int64) (bool, error) {
+	return true, nil
+}
+
+func (s *potTransferRepoStub) MoveFundsBetweenAccounts(ctx context.Context, sourceAccountID uuid.UUID, destinationAccountID uuid.UUID, amount int64) error {
+	if s.moveErr != nil {
+		return s.moveErr
+	}
+	s.moves = append(s.moves, fundsMove{source: sourceAccountID, destination: destinationAccountID, amount: amount})
+	return nil
+}
+
+func (s *potTransferRepoStub) CreateTransaction(ctx context.Context, tx *domain.Transaction) error {
+	s.createdTx = tx
+	return nil
+}
+
+func newPotTransferTestService(t *testing.T, transferStatus int) (*Service, *potTransferRepoStub) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/transfers" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(transferStatus)
+			if transferStatus >= http.StatusBadRequest {
+				_, _ = io.WriteString(w, `{"errors":[{"title":"Rejected","detail":"transfer rejected","status":"400"}]}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":{"id":"atr_pot_123","type":"BookTransfer","attributes":{"status":"pending","fee":0}}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	userID := uuid.New()
+	repo := &potTransferRepoStub{
+		primary: &domain.Account{ID: uuid.New(), UserID: userID, AnchorAccountID: "anc_primary", Balance: 100000},
+		pot:     &domain.Account{ID: uuid.New(), UserID: userID, AnchorAccountID: "anc_pot"},
+	}
+
+	svc := &Service{
+		repo:         repo,
+		anchorClient: anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
+	}
+	return svc, repo
+}
+
+func TestTransferPotFunds_MovesFundsInRequestedDirection(t *testing.T) {
+	tests := []struct {
+		name            string
+		direction       string
+		wantDescription string
+		fromPot         bool
+	}{
+		{name: "to pot", direction: domain.PotTransferToPot, wantDescription: "Transfer to pot"},
+		{name: "from pot", direction: domain.PotTransferFromPot, wantDescription: "Transfer from pot", fromPot: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newPotTransferTestService(t, http.StatusCreated)
+
+			tx, err := svc.TransferPotFunds(context.Background(), repo.primary.UserID, repo.pot.ID, domain.PotTransferRequest{
+				Amount:    2500,
+				Direction: tt.direction,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wantSource, wantDestination := repo.primary.ID, repo.pot.ID
+			if tt.fromPot {
+				wantSource, wantDestination = repo.pot.ID, repo.primary.ID
+			}
+			if len(repo.moves) != 1 {
+				t.Fatalf("expected 1 ledger move, got %d", len(repo.moves))
+			}
+			if repo.moves[0].source != wantSource || repo.moves[0].destination != wantDestination {
+				t.Fatalf("unexpected ledger move: %+v", repo.moves[0])
+			}
+			if repo.createdTx == nil || repo.createdTx != tx {
+				t.Fatalf("expected transaction to be recorded")
+			}
+			if tx.Type != "pot_transfer" || tx.Status != "completed" || tx.Fee != 0 {
+				t.Fatalf("unexpected transaction: type=%s status=%s fee=%d", tx.Type, tx.Status, tx.Fee)
+			}
+			if tx.SourceAccountID != wantSource || tx.DestinationAccountID == nil || *tx.DestinationAccountID != wantDestination {
+				t.Fatalf("unexpected transaction accounts: source=%s destination=%v", tx.SourceAccountID, tx.DestinationAccountID)
+			}
+			if tx.Description != tt.wantDescription {
+				t.Fatalf("expected description %q, got %q", tt.wantDescription, tx.Description)
+			}
+		})
+	}
+}
+
+func TestTransferPotFunds_RevertsLedgerWhenAnchorTransferFails(t *testing.T) {
+	svc, repo := newPotTransferTestService(t, http.StatusBadRequest)
+
+	_, err := svc.TransferPotFunds(context.Background(), repo.primary.UserID, repo.pot.ID, domain.PotTransferRequest{
+		Amount:    2500,
+		Direction: domain.PotTransferToPot,
+	})
+	if err == nil {
+		t.Fatalf("expected error when Anchor rejects the transfer")
+	}
+
+	if len(repo.moves) != 2 {
+		t.Fatalf("expected ledger move and revert, got %d moves", len(repo.moves))
+	}
+	if repo.moves[1].source != repo.pot.ID || repo.moves[1].destination != repo.primary.ID {
+		t.Fatalf("expected revert from pot to primary, got %+v", repo.moves[1])
+	}
+	if repo.createdTx != nil {
+		t.Fatalf("expected no transaction record on failure")
+	}
+}
+
+func TestTransferPotFunds_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      domain.PotTransferRequest
+		wrongPot bool
+		moveErr  error
+		wantErr  error
+	}{
+		{name: "zero amount", req: domain.PotTransferRequest{Amount: 0, Direction: domain.PotTransferToPot}, wantErr: ErrInvalidTransferAmount},
+		{name: "unknown direction", req: domain.PotTransferRequest{Amount: 100, Direction: "sideways"}, wantErr: ErrInvalidPotTransferDirection},
+		{name: "short description", req: domain.PotTransferRequest{Amount: 100, Direction: domain.PotTransferToPot, Description: "ab"}, wantErr: ErrInvalidDescription},
+		{name: "pot not owned", req: domain.PotTransferRequest{Amount: 100, Direction: domain.PotTransferToPot}, wrongPot: true, wantErr: ErrPotNotFound},
+		{name: "insufficient funds", req: domain.PotTransferRequest{Amount: 100, Direction: domain.PotTransferFromPot}, moveErr: store.ErrInsufficientFunds, wantErr: store.ErrInsufficientFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newPotTransferTestService(t, http.StatusCreated)
+			repo.moveErr = tt.moveErr
+
+			potID := repo.pot.ID
+			if tt.wrongPot {
+				potID = uuid.New()
+			}
+
+			_, err := svc.TransferPotFunds(context.Background(), repo.primary.UserID, potID, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.createdTx != nil {
+				t.Fatalf("expected no transaction record")
+			}
+		})
+	}
+}
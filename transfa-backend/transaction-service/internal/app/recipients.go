@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ListRecentRecipients returns the distinct users the caller has recently completed P2P transfers to.
+func (s *Service) ListRecentRecipients(ctx context.Context, userID uuid.UUID, limit int) ([]domain.RecentRecipient, error) {
+	if limit <= 0 {
+		limit = defaultRecentRecipientsLimit
+	}
+	if limit > maxRecentRecipientsLimit {
+		limit = maxRecentRecipientsLimit
+	}
+	return s.repo.ListRecentRecipients(ctx, userID, limit)
+}
+
+// ListFavoriteRecipients returns the caller's pinned recipients. Favorites whose user can no
+// longer receive funds are still returned with CanReceive set to false.
+func (s *Service) ListFavoriteRecipients(ctx context.Context, userID uuid.UUID) ([]domain.FavoriteRecipient, error) {
+	return s.repo.ListFavoriteRecipients(ctx, userID)
+}
+
+// AddFavoriteRecipient pins a user by username. Re-adding an existing favorite is idempotent.
+func (s *Service) AddFavoriteRecipient(ctx context.Context, userID uuid.UUID, username string) (*domain.FavoriteRecipient, error) {
+	normalized, err := normalizeAndValidateUsernameInput(username)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.repo.FindUserByUsername(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == userID {
+		return nil, ErrFavoriteRecipientSelf
+	}
+
+	existing, err := s.repo.ListFavoriteRecipients(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, favorite := range existing {
+		if favorite.UserID == recipient.ID {
+			favoriteCopy := favorite
+			return &favoriteCopy, nil
+		}
+	}
+	if len(existing) >= maxFavoriteRecipients {
+		return nil, ErrFavoriteRecipientLimit
+	}
+
+	return s.repo.AddFavoriteRecipient(ctx, userID, recipient.ID)
+}
+
+// RemoveFavoriteRecipient unpins a user from the caller's favorites.
+func (s *Service) RemoveFavoriteRecipient(ctx context.Context, userID uuid.UUID, recipientID uuid.UUID) error {
+	removed, err := s.repo.RemoveFavoriteRecipient(ctx, userID, recipientID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrFavoriteRecipientNotFound
+	}
+	return nil
+}
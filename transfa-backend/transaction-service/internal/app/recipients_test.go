@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type recipientsRepoStub struct {
+	store.Repository
+
+	users     map[string]*domain.User
+	favorites []domain.FavoriteRecipient
+	removed   bool
+
+	recentLimit int
+	addCalls    int
+}
+
+func (s *recipientsRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	user, ok := s.users[username]
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *recipientsRepoStub) ListRecentRecipients(ctx context.Context, senderID uuid.UUID, limit int) ([]domain.RecentRecipient, error) {
+	s.recentLimit = limit
+	return nil, nil
+}
+
+func (s *recipientsRepoStub) ListFavoriteRecipients(ctx context.Context, ownerID uuid.UUID) ([]domain.FavoriteRecipient, error) {
+	return s.favorites, nil
+}
+
+func (s *recipientsRepoStub) AddFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (*domain.FavoriteRecipient, error) {
+	s.addCalls++
+	return &domain.FavoriteRecipient{UserID: recipientID, CanReceive: true}, nil
+}
+
+func (s *recipientsRepoStub) RemoveFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (bool, error) {
+	return s.removed, nil
+}
+
+func TestListRecentRecipients_ClampsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "zero uses default", limit: 0, want: defaultRecentRecipientsLimit},
+		{name: "within range is kept", limit: 5, want: 5},
+		{name: "above max is capped", limit: 500, want: maxRecentRecipientsLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recipientsRepoStub{}
+			svc := &Service{repo: repo}
+			if _, err := svc.ListRecentRecipients(context.Background(), uuid.New(), tt.limit); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.recentLimit != tt.want {
+				t.Fatalf("expected limit %d, got %d", tt.want, repo.recentLimit)
+			}
+		})
+	}
+}
+
+func TestAddFavoriteRecipient(t *testing.T) {
+	ownerID := uuid.New()
+	bobID := uuid.New()
+	users := map[string]*domain.User{
+		"alice": {ID: ownerID, Username: "alice"},
+		"bob":   {ID: bobID, Username: "bob"},
+	}
+
+	fullFavorites := make([]domain.FavoriteRecipient, maxFavoriteRecipients)
+	for i := range fullFavorites {
+		fullFavorites[i] = domain.FavoriteRecipient{UserID: uuid.New()}
+	}
+
+	tests := []struct {
+		name         string
+		username     string
+		favorites    []domain.FavoriteRecipient
+		wantErr      error
+		wantAddCalls int
+	}{
+		{name: "adds new favorite", username: " Bob ", wantAddCalls: 1},
+		{name: "rejects self", username: "alice", wantErr: ErrFavoriteRecipientSelf},
+		{name: "rejects invalid username", username: "", wantErr: ErrInvalidRecipient},
+		{name: "unknown user", username: "carol", wantErr: store.ErrUserNotFound},
+		{name: "rejects when limit reached", username: "bob", favorites: fullFavorites, wantErr: ErrFavoriteRecipientLimit},
+		{
+			name:      "existing favorite is idempotent",
+			username:  "bob",
+			favorites: []domain.FavoriteRecipient{{UserID: bobID, Username: "bob", CanReceive: false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recipientsRepoStub{users: users, favorites: tt.favorites}
+			svc := &Service{repo: repo}
+
+			favorite, err := svc.AddFavoriteRecipient(context.Background(), ownerID, tt.username)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if favorite.UserID != bobID {
+				t.Fatalf("expected favorite for bob, got %s", favorite.UserID)
+			}
+			if repo.addCalls != tt.wantAddCalls {
+				t.Fatalf("expected %d add calls, got %d", tt.wantAddCalls, repo.addCalls)
+			}
+		})
+	}
+}
+
+func TestRemoveFavoriteRecipient_NotFound(t *testing.T) {
+	svc := &Service{repo: &recipientsRepoStub{removed: false}}
+	err := svc.RemoveFavoriteRecipient(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, ErrFavoriteRecipientNotFound) {
+		t.Fatalf("expected ErrFavoriteRecipientNotFound, got %v", err)
+	}
+}
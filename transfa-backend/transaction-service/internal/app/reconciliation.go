@@ -0,0 +1,361 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	reconciliationTimeout        = 2 * time.Hour
+	reconciliationBatchSize      = 200
+	reconciliationConcurrency    = 4
+	reconciliationPageSize       = 100
+	reconciliationMaxPages       = 200
+	reconciliationMaxAttempts    = 4
+	reconciliationRetryBaseDelay = 250 * time.Millisecond
+)
+
+var ErrReconciliationInProgress = errors.New("reconciliation already in progress")
+
+// StartReconciliation runs RunReconciliation for day in the background so the caller
+// is not held for the whole run. Only one reconciliation may run at a time.
+func (s *Service) StartReconciliation(day time.Time, sampleSize int) error {
+	if !s.reconciliationRunning.CompareAndSwap(false, true) {
+		return ErrReconciliationInProgress
+	}
+
+	go func() {
+		defer s.reconciliationRunning.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), reconciliationTimeout)
+		defer cancel()
+
+		startedAt := time.Now()
+		report, err := s.RunReconciliation(ctx, day, sampleSize)
+		if err != nil {
+			log.Printf("level=error component=service flow=reconciliation msg=\"reconciliation aborted\" day=%s err=%v", day.UTC().Format(time.DateOnly), err)
+			return
+		}
+		log.Printf(
+			"level=info component=service flow=reconciliation msg=\"reconciliation finished\" report_id=%s day=%s status=%s accounts=%d accounts_failed=%d matched=%d unmatched_anchor=%d unmatched_internal=%d duration_ms=%d",
+			report.ID,
+			report.PeriodStart.Format(time.DateOnly),
+			report.Status,
+			report.AccountsChecked,
+			report.AccountsFailed,
+			report.Matched,
+			len(report.UnmatchedAnchor),
+			len(report.UnmatchedInternal),
+			time.Since(startedAt).Milliseconds(),
+		)
+	}()
+	return nil
+}
+
+// GetLatestReconciliationReport returns the most recent reconciliation report.
+func (s *Service) GetLatestReconciliationReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	return s.repo.GetLatestReconciliationReport(ctx)
+}
+
+// RunReconciliation compares Anchor's transfers on the UTC day containing day with our
+// transactions and fee collections, and stores the report. The admin account is always
+// checked; user accounts are a random sample of sampleSize, or all of them when
+// sampleSize is 0.
+//
+// Anchor is read with at most reconciliationConcurrency accounts in flight, a page at a
+// time, and throttled or failed calls are retried with exponential backoff. An account
+// that still cannot be read is counted as failed and left out of the comparison, which
+// makes the report partial.
+func (s *Service) RunReconciliation(ctx context.Context, day time.Time, sampleSize int) (*domain.ReconciliationReport, error) {
+	day = day.UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	accounts, err := s.reconciliationAccounts(ctx, sampleSize)
+	if err != nil {
+		return nil, fmt.Errorf("list accounts: %w", err)
+	}
+
+	fetched := s.fetchAnchorTransfers(ctx, accounts, start)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	internal, err := s.repo.ListReconciliationEntries(ctx, start, end, fetched.userAccounts, fetched.adminChecked)
+	if err != nil {
+		return nil, fmt.Errorf("list internal entries: %w", err)
+	}
+
+	report := domain.ReconciliationReport{
+		PeriodStart:     start,
+		PeriodEnd:       end,
+		Status:          domain.ReconciliationCompleted,
+		SampleSize:      sampleSize,
+		AccountsChecked: len(accounts),
+		AccountsFailed:  fetched.failed,
+		AnchorEntries:   len(fetched.transfers),
+		InternalEntries: len(internal),
+	}
+	if fetched.failed > 0 {
+		report.Status = domain.ReconciliationPartial
+	}
+	if err := s.matchReconciliationEntries(ctx, &report, fetched.transfers, internal); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.CreateReconciliationReport(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("save reconciliation report: %w", err)
+	}
+	return saved, nil
+}
+
+// reconciliationAccounts returns the Anchor account IDs to check, the admin account
+// first.
+func (s *Service) reconciliationAccounts(ctx context.Context, sampleSize int) ([]string, error) {
+	var accounts []string
+	if s.adminAccountID != "" {
+		accounts = append(accounts, s.adminAccountID)
+	}
+
+	if sampleSize > 0 {
+		sampled, err := s.repo.SampleAccountsForReconciliation(ctx, sampleSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range sampled {
+			accounts = append(accounts, account.AnchorAccountID)
+		}
+		return accounts, nil
+	}
+
+	afterID := uuid.Nil
+	for {
+		batch, err := s.repo.ListAccountsForBalanceSync(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range batch {
+			accounts = append(accounts, account.AnchorAccountID)
+		}
+		if len(batch) < reconciliationBatchSize {
+			return accounts, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+type reconciliationFetch struct {
+	transfers    map[string]anchorclient.TransferDetails
+	userAccounts []string
+	adminChecked bool
+	failed       int
+}
+
+// fetchAnchorTransfers reads every account's transfers for the day, keyed by transfer
+// ID so a transfer between two checked accounts is counted once.
+func (s *Service) fetchAnchorTransfers(ctx context.Context, accounts []string, day time.Time) *reconciliationFetch {
+	fetched := &reconciliationFetch{transfers: map[string]anchorclient.TransferDetails{}}
+	var mu sync.Mutex
+
+	var g errgroup.Group
+	sem := make(chan struct{}, reconciliationConcurrency)
+	for _, accountID := range accounts {
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+
+			transfers, err := s.listAccountTransfers(ctx, accountID, day)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("level=warn component=service flow=reconciliation msg=\"anchor transfers fetch failed\" anchor_account_id=%s err=%v", accountID, err)
+				fetched.failed++
+				return nil
+			}
+			if accountID == s.adminAccountID {
+				fetched.adminChecked = true
+			} else {
+				fetched.userAccounts = append(fetched.userAccounts, accountID)
+			}
+			for _, transfer := range transfers {
+				fetched.transfers[transfer.ID] = transfer
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return fetched
+}
+
+// listAccountTransfers pages through one account's transfers for the day.
+func (s *Service) listAccountTransfers(ctx context.Context, accountID string, day time.Time) ([]anchorclient.TransferDetails, error) {
+	var transfers []anchorclient.TransferDetails
+	for page := 0; page < reconciliationMaxPages; page++ {
+		result, err := retryAnchorCall(ctx, func() (*anchorclient.TransferPage, error) {
+			return s.anchorClient.ListTransfers(ctx, accountID, day, day, page, reconciliationPageSize)
+		})
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, result.Transfers...)
+		// Trust Anchor's page count when it sends one; otherwise a short page is the last.
+		if result.TotalPages > 0 {
+			if page+1 >= result.TotalPages {
+				return transfers, nil
+			}
+		} else if len(result.Transfers) < reconciliationPageSize {
+			return transfers, nil
+		}
+	}
+	return nil, fmt.Errorf("more than %d pages of transfers", reconciliationMaxPages)
+}
+
+// matchReconciliationEntries matches Anchor transfers to internal entries by transfer
+// ID and amount and records what is left on either side. A transfer with no entry in
+// the checked accounts is looked up across all transactions before it is reported
+// missing, and an entry with no transfer in the listings is looked up on Anchor, so
+// transfers near midnight or to unchecked accounts are not reported.
+func (s *Service) matchReconciliationEntries(ctx context.Context, report *domain.ReconciliationReport, transfers map[string]anchorclient.TransferDetails, internal []domain.ReconciliationEntry) error {
+	entries := make(map[string]domain.ReconciliationEntry, len(internal))
+	for _, entry := range internal {
+		entries[entry.AnchorTransferID] = entry
+	}
+
+	for _, transferID := range sortedKeys(transfers) {
+		transfer := transfers[transferID]
+		entry, ok := entries[transferID]
+		if ok {
+			delete(entries, transferID)
+		} else {
+			found, err := s.findReconciliationEntry(ctx, transferID)
+			if err != nil {
+				return fmt.Errorf("look up anchor transfer %s: %w", transferID, err)
+			}
+			if found == nil {
+				report.UnmatchedAnchor = append(report.UnmatchedAnchor, reconciliationItem(domain.ReconciliationMissingInternal, nil, &transfer))
+				continue
+			}
+			entry = *found
+		}
+
+		if entry.Amount != transfer.Amount {
+			report.UnmatchedAnchor = append(report.UnmatchedAnchor, reconciliationItem(domain.ReconciliationAmountMismatch, &entry, &transfer))
+			continue
+		}
+		report.Matched++
+	}
+
+	for _, transferID := range sortedKeys(entries) {
+		entry := entries[transferID]
+		transfer, err := retryAnchorCall(ctx, func() (*anchorclient.TransferDetails, error) {
+			return s.anchorClient.GetTransfer(ctx, transferID)
+		})
+		switch {
+		case err != nil:
+			reason := domain.ReconciliationLookupFailed
+			var apiErr *anchorclient.APIError
+			if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+				reason = domain.ReconciliationMissingAnchor
+			} else {
+				log.Printf("level=warn component=service flow=reconciliation msg=\"anchor transfer lookup failed\" anchor_transfer_id=%s err=%v", transferID, err)
+			}
+			report.UnmatchedInternal = append(report.UnmatchedInternal, reconciliationItem(reason, &entry, nil))
+		case transfer.Amount != entry.Amount:
+			report.UnmatchedInternal = append(report.UnmatchedInternal, reconciliationItem(domain.ReconciliationAmountMismatch, &entry, transfer))
+		default:
+			report.Matched++
+		}
+	}
+	return nil
+}
+
+// findReconciliationEntry finds the transaction or fee collection for an Anchor
+// transfer anywhere in our records, or returns nil.
+func (s *Service) findReconciliationEntry(ctx context.Context, anchorTransferID string) (*domain.ReconciliationEntry, error) {
+	tx, err := s.repo.FindTransactionByAnchorTransferID(ctx, anchorTransferID)
+	if err == nil {
+		return &domain.ReconciliationEntry{ID: tx.ID, Source: domain.ReconciliationSourceTransaction, AnchorTransferID: anchorTransferID, Amount: tx.Amount}, nil
+	}
+	if !errors.Is(err, store.ErrTransactionNotFound) {
+		return nil, err
+	}
+
+	fee, err := s.repo.FindFeeCollectionByAnchorTransferID(ctx, anchorTransferID)
+	if err == nil {
+		return &domain.ReconciliationEntry{ID: fee.ID, Source: domain.ReconciliationSourceFeeCollection, AnchorTransferID: anchorTransferID, Amount: fee.Amount}, nil
+	}
+	if !errors.Is(err, store.ErrFeeCollectionNotFound) {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func reconciliationItem(reason string, entry *domain.ReconciliationEntry, transfer *anchorclient.TransferDetails) domain.ReconciliationItem {
+	item := domain.ReconciliationItem{Reason: reason}
+	if entry != nil {
+		id := entry.ID
+		item.AnchorTransferID = entry.AnchorTransferID
+		item.TransactionID = &id
+		item.Source = entry.Source
+		item.Amount = entry.Amount
+	}
+	if transfer != nil {
+		item.AnchorTransferID = transfer.ID
+		item.AnchorAccountID = transfer.AccountID
+		item.AnchorAmount = transfer.Amount
+		item.AnchorStatus = transfer.Status
+	}
+	return item
+}
+
+// retryAnchorCall runs call, retrying throttled, server-side and network failures up
+// to reconciliationMaxAttempts times with exponential backoff.
+func retryAnchorCall[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt >= reconciliationMaxAttempts || !isRetryableAnchorError(ctx, err) {
+			return result, err
+		}
+
+		backoff := reconciliationRetryBaseDelay << (attempt - 1)
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func isRetryableAnchorError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *anchorclient.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsRateLimited() || apiErr.StatusCode == http.StatusRequestTimeout || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type reconciliationRepoStub struct {
+	store.Repository
+
+	sampled      []domain.Account
+	entries      []domain.ReconciliationEntry
+	transactions map[string]domain.Transaction
+
+	entryAccounts []string
+	entryAdmin    bool
+	saved         *domain.ReconciliationReport
+}
+
+func (s *reconciliationRepoStub) SampleAccountsForReconciliation(ctx context.Context, limit int) ([]domain.Account, error) {
+	return s.sampled[:min(limit, len(s.sampled))], nil
+}
+
+func (s *reconciliationRepoStub) ListReconciliationEntries(ctx context.Context, from, to time.Time, anchorAccountIDs []string, includeAdmin bool) ([]domain.ReconciliationEntry, error) {
+	s.entryAccounts = anchorAccountIDs
+	s.entryAdmin = includeAdmin
+	return s.entries, nil
+}
+
+func (s *reconciliationRepoStub) FindTransactionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.Transaction, error) {
+	tx, ok := s.transactions[anchorTransferID]
+	if !ok {
+		return nil, store.ErrTransactionNotFound
+	}
+	return &tx, nil
+}
+
+func (s *reconciliationRepoStub) FindFeeCollectionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.FeeCollection, error) {
+	return nil, store.ErrFeeCollectionNotFound
+}
+
+func (s *reconciliationRepoStub) CreateReconciliationReport(ctx context.Context, report domain.ReconciliationReport) (*domain.ReconciliationReport, error) {
+	report.ID = uuid.New()
+	s.saved = &report
+	return &report, nil
+}
+
+// fakeAnchorLedger serves transfer listings per account, split into pages of one, and
+// single transfer lookups.
+type fakeAnchorLedger struct {
+	mu          sync.Mutex
+	byAccount   map[string][]string // account -> transfer JSON resources
+	transfers   map[string]string   // transfer id -> resource JSON
+	rejected    map[string]bool     // accounts whose listing Anchor rejects
+	throttleOne map[string]bool     // accounts whose first listing is throttled
+	listCalls   map[string]int
+}
+
+func transferJSON(id, accountID string, amount int64) string {
+	return fmt.Sprintf(`{"id":%q,"type":"BookTransfer","attributes":{"status":"COMPLETED","amount":%d},"relationships":{"account":{"data":{"id":%q,"type":"DepositAccount"}}}}`, id, amount, accountID)
+}
+
+func (l *fakeAnchorLedger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	if id, ok := strings.CutPrefix(r.URL.Path, "/api/v1/transfers/"); ok {
+		resource, found := l.transfers[id]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors":[{"status":"404","title":"Not Found"}]}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":`+resource+`}`)
+		return
+	}
+
+	account := r.URL.Query().Get("accountId")
+	l.listCalls[account]++
+	if l.rejected[account] {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"errors":[{"status":"400","title":"Bad Request","detail":"Unknown account"}]}`)
+		return
+	}
+	if l.throttleOne[account] {
+		delete(l.throttleOne, account)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"errors":[{"status":"429","title":"Too Many Requests"}]}`)
+		return
+	}
+
+	resources := l.byAccount[account]
+	var page int
+	_, _ = fmt.Sscan(r.URL.Query().Get("page"), &page)
+	data := ""
+	if page < len(resources) {
+		data = resources[page]
+	}
+	_, _ = fmt.Fprintf(w, `{"data":[%s],"meta":{"pagination":{"page":%d,"size":1,"total":%d,"totalPages":%d}}}`, data, page, len(resources), len(resources))
+}
+
+func TestRunReconciliation_ReportsUnmatchedOnEitherSide(t *testing.T) {
+	ledger := &fakeAnchorLedger{
+		byAccount: map[string][]string{
+			"admin-acct": {transferJSON("tr-fee", "acct-a", 100)},
+			"acct-a": {
+				transferJSON("tr-match", "acct-a", 5000),
+				transferJSON("tr-mismatch", "acct-a", 3000),
+				transferJSON("tr-orphan", "acct-a", 700),
+				transferJSON("tr-elsewhere", "acct-a", 900),
+				transferJSON("tr-match", "acct-a", 5000), // listed again on a later page
+			},
+		},
+		transfers: map[string]string{
+			"tr-late": transferJSON("tr-late", "acct-a", 400),
+		},
+		rejected:    map[string]bool{"acct-b": true},
+		throttleOne: map[string]bool{"acct-a": true},
+		listCalls:   map[string]int{},
+	}
+	server := httptest.NewServer(ledger)
+	t.Cleanup(server.Close)
+
+	entry := func(transferID string, amount int64) domain.ReconciliationEntry {
+		return domain.ReconciliationEntry{ID: uuid.New(), Source: domain.ReconciliationSourceTransaction, AnchorTransferID: transferID, Amount: amount}
+	}
+	internalOnly := entry("tr-internal-only", 1500)
+	repo := &reconciliationRepoStub{
+		sampled: []domain.Account{
+			{ID: uuid.New(), AnchorAccountID: "acct-a"},
+			{ID: uuid.New(), AnchorAccountID: "acct-b"},
+		},
+		entries: []domain.ReconciliationEntry{
+			entry("tr-match", 5000),
+			entry("tr-mismatch", 2500),
+			{ID: uuid.New(), Source: domain.ReconciliationSourceFeeCollection, AnchorTransferID: "tr-fee", Amount: 100},
+			internalOnly,
+			entry("tr-late", 400),
+		},
+		transactions: map[string]domain.Transaction{
+			"tr-elsewhere": {ID: uuid.New(), Amount: 900},
+		},
+	}
+	svc := &Service{
+		repo:           repo,
+		anchorClient:   anchorclient.NewCachingAnchorClient(anchorclient.NewClient(server.URL, "test-key"), 0, 0),
+		adminAccountID: "admin-acct",
+	}
+
+	day := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	report, err := svc.RunReconciliation(context.Background(), day, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.saved == nil {
+		t.Fatal("expected the report to be saved")
+	}
+
+	if !report.PeriodStart.Equal(time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)) || !report.PeriodEnd.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected period %s - %s", report.PeriodStart, report.PeriodEnd)
+	}
+	if report.Status != domain.ReconciliationPartial || report.AccountsChecked != 3 || report.AccountsFailed != 1 {
+		t.Fatalf("expected a partial report over 3 accounts with 1 failure, got %+v", report)
+	}
+	if len(repo.entryAccounts) != 1 || repo.entryAccounts[0] != "acct-a" || !repo.entryAdmin {
+		t.Fatalf("expected internal entries for acct-a and the admin account only, got %v admin=%v", repo.entryAccounts, repo.entryAdmin)
+	}
+	if report.AnchorEntries != 5 || report.InternalEntries != 5 {
+		t.Fatalf("expected 5 deduplicated Anchor entries and 5 internal entries, got %d and %d", report.AnchorEntries, report.InternalEntries)
+	}
+	if report.Matched != 4 {
+		t.Fatalf("expected tr-match, tr-fee, tr-elsewhere and tr-late to match, got %d", report.Matched)
+	}
+
+	if len(report.UnmatchedAnchor) != 2 {
+		t.Fatalf("expected 2 unmatched Anchor entries, got %+v", report.UnmatchedAnchor)
+	}
+	mismatch, orphan := report.UnmatchedAnchor[0], report.UnmatchedAnchor[1]
+	if mismatch.AnchorTransferID != "tr-mismatch" || mismatch.Reason != domain.ReconciliationAmountMismatch || mismatch.Amount != 2500 || mismatch.AnchorAmount != 3000 || mismatch.TransactionID == nil {
+		t.Fatalf("unexpected amount mismatch item: %+v", mismatch)
+	}
+	if orphan.AnchorTransferID != "tr-orphan" || orphan.Reason != domain.ReconciliationMissingInternal || orphan.AnchorAccountID != "acct-a" || orphan.TransactionID != nil {
+		t.Fatalf("unexpected missing internal item: %+v", orphan)
+	}
+
+	if len(report.UnmatchedInternal) != 1 {
+		t.Fatalf("expected 1 unmatched internal entry, got %+v", report.UnmatchedInternal)
+	}
+	missing := report.UnmatchedInternal[0]
+	if missing.AnchorTransferID != "tr-internal-only" || missing.Reason != domain.ReconciliationMissingAnchor || missing.TransactionID == nil || *missing.TransactionID != internalOnly.ID {
+		t.Fatalf("unexpected missing Anchor item: %+v", missing)
+	}
+
+	// One throttled call retried, then one call per page.
+	if got := ledger.listCalls["acct-a"]; got != 6 {
+		t.Fatalf("expected 6 listing calls for acct-a, got %d", got)
+	}
+	if got := ledger.listCalls["acct-b"]; got != 1 {
+		t.Fatalf("expected a rejected listing not to be retried, got %d calls", got)
+	}
+}
+
+func TestStartReconciliation_RejectsConcurrentRun(t *testing.T) {
+	svc := &Service{}
+	svc.reconciliationRunning.Store(true)
+
+	if err := svc.StartReconciliation(time.Now(), 0); err != ErrReconciliationInProgress {
+		t.Fatalf("expected ErrReconciliationInProgress, got %v", err)
+	}
+}
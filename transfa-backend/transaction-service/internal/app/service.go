@@ -34,14 +34,16 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	rmrabbit "github.com/transfa/pkg/rabbitmq"
 	"github.com/transfa/transaction-service/internal/domain"
 	"github.com/transfa/transaction-service/internal/store"
 	"github.com/transfa/transaction-service/pkg/accountclient"
-	"github.com/transfa/transaction-service/pkg/anchorclient"
-	rmrabbit "github.com/transfa/transaction-service/pkg/rabbitmq"
+	"github.com/transfa/transaction-service/pkg/authclient"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -53,6 +55,16 @@ const (
 	maxTransferListMembers           = 10
 	minTransferListNameLen           = 1
 	maxTransferListNameLen           = 80
+	defaultRecentRecipientsLimit     = 10
+	maxRecentRecipientsLimit         = 50
+	maxFavoriteRecipients            = 50
+	maxDisputeDescriptionLen         = 1000
+	defaultDisputesLimit             = 50
+	maxDisputesLimit                 = 200
+	defaultAuditEventsLimit          = 50
+	maxAuditEventsLimit              = 500
+	defaultUnmatchedEventsLimit      = 50
+	maxUnmatchedEventsLimit          = 200
 	maxPaymentRequestTitleLen        = 80
 	maxPaymentRequestDescriptionLen  = 500
 	maxPaymentRequestDeclineLen      = 240
@@ -73,6 +85,14 @@ const (
 	moneyDropRetryPendingReason      = "refund_retry_pending"
 	moneyDropRefundPayoutInFlight    = "refund_payout_inflight"
 	moneyDropRefundPersistFailReason = "refund_persistence_failed"
+	eventPublishTimeout              = 10 * time.Second
+	moneyDropDashboardHistoryLimit   = 5
+	moneyDropBalanceSyncTimeout      = 3 * time.Second
+	defaultMoneyDropOwnerClaimers    = 20
+	maxMoneyDropOwnerClaimers        = 100
+	maxTransactionNoteLen            = 500
+	maxTransactionAttachmentsPerUser = 3
+	claimedMoneyDropHistoryLimit     = 100
 )
 
 type serviceContextKey string
@@ -86,6 +106,10 @@ var (
 	ErrInvalidDescription                      = errors.New("description must be between 3 and 100 characters")
 	ErrInvalidRecipient                        = errors.New("recipient username is required")
 	ErrSelfTransferNotAllowed                  = errors.New("self transfer is not allowed on p2p endpoint")
+	ErrInvalidDelivery                         = errors.New("delivery must be internal or recipient_preference")
+	ErrBeneficiaryAccountInvalid               = errors.New("this bank account can no longer receive transfers; update the account details and try again")
+	ErrBeneficiaryVerificationUnavailable      = errors.New("the bank account could not be verified right now")
+	ErrTransactionCancelled                    = errors.New("the transfer was cancelled before it was sent")
 	ErrBulkTransferEmpty                       = errors.New("at least one transfer item is required")
 	ErrBulkTransferLimit                       = errors.New("bulk transfer supports a maximum of 10 recipients")
 	ErrDuplicateRecipient                      = errors.New("duplicate recipient in bulk transfer request")
@@ -96,6 +120,23 @@ var (
 	ErrTransferListDuplicateMember             = errors.New("duplicate user in transfer list")
 	ErrTransferListSelfMember                  = errors.New("you cannot add yourself to a transfer list")
 	ErrTransferListNotFound                    = errors.New("transfer list not found")
+	ErrFavoriteRecipientSelf                   = errors.New("you cannot add yourself as a favorite")
+	ErrFavoriteRecipientLimit                  = errors.New("you can pin a maximum of 50 favorites")
+	ErrFavoriteRecipientNotFound               = errors.New("favorite recipient not found")
+	ErrInvalidDisputeReason                    = errors.New("dispute reason must be unauthorized, wrong_amount, not_received or other")
+	ErrInvalidDisputeDescription               = errors.New("dispute description cannot exceed 1000 characters")
+	ErrInvalidDisputeStatus                    = errors.New("dispute status must be open, investigating, resolved or rejected")
+	ErrTransactionNotDisputable                = errors.New("only completed or failed transactions can be disputed")
+	ErrInvalidDisputeTransition                = errors.New("dispute cannot move to that status from its current status")
+	ErrDisputeResolutionNoteRequired           = errors.New("a resolution note is required to resolve or reject a dispute")
+	ErrInvalidDisputeResolutionNote            = errors.New("resolution note cannot exceed 1000 characters")
+	ErrInvalidTransactionNote                  = errors.New("note cannot exceed 500 characters")
+	ErrInvalidTransactionAttachmentURL         = errors.New("attachment url must be an https link to Transfa storage")
+	ErrInvalidStatementPeriod                  = errors.New("from and to must be YYYY-MM-DD dates with from on or before to")
+	ErrStatementPeriodTooLong                  = errors.New("statement period cannot exceed 366 days")
+	ErrInvalidStatementPassword                = errors.New("statement password must be 1-32 printable ASCII characters")
+	ErrInvalidPotTransferDirection             = errors.New("direction must be to_pot or from_pot")
+	ErrPotNotFound                             = errors.New("pot not found")
 	ErrInvalidPaymentRequestType               = errors.New("request type must be general or individual")
 	ErrInvalidPaymentRequestTitle              = errors.New("request title must be between 3 and 80 characters")
 	ErrInvalidPaymentRequestDescription        = errors.New("request description cannot exceed 500 characters")
@@ -103,7 +144,14 @@ var (
 	ErrSelfPaymentRequest                      = errors.New("cannot create an individual request for yourself")
 	ErrPaymentRequestNotFound                  = errors.New("payment request not found")
 	ErrPaymentRequestNotPending                = errors.New("payment request is not pending")
+	ErrPaymentRequestInProcessing              = errors.New("payment request cannot be deleted while a payment is in progress")
+	ErrPaymentAmountExceedsRemaining           = errors.New("payment amount exceeds the remaining balance")
+	ErrPartialPaymentNotAllowed                = errors.New("this request must be paid in full")
 	ErrInvalidPaymentRequestDecline            = errors.New("decline reason cannot exceed 240 characters")
+	ErrInvalidPaymentRequestSplitStrategy      = errors.New("split strategy must be equal or custom")
+	ErrInvalidPaymentRequestSplitRecipients    = errors.New("a split needs between 1 and 20 recipients")
+	ErrDuplicatePaymentRequestSplitRecipient   = errors.New("duplicate recipient in payment request split")
+	ErrInvalidPaymentRequestSplitAmounts       = errors.New("split amounts must be positive and add up to the total amount")
 	ErrInvalidMoneyDropTitle                   = errors.New("money drop title must be between 3 and 80 characters")
 	ErrInvalidMoneyDropTotalAmount             = errors.New("money drop total amount must be greater than zero")
 	ErrInvalidMoneyDropPeopleCount             = errors.New("money drop number of people must be greater than zero")
@@ -116,6 +164,9 @@ var (
 	ErrMoneyDropPasswordEncryptionUnavailable  = errors.New("money drop password encryption is not configured")
 	ErrMoneyDropAccountProvisioningUnavailable = errors.New("money drop account provisioning is temporarily unavailable")
 	ErrMoneyDropEndNotAllowed                  = errors.New("money drop cannot be ended in its current state")
+	ErrMoneyDropShareNotAllowed                = errors.New("only active money drops can be shared")
+	ErrMoneyDropNotLocked                      = errors.New("this drop is not password protected")
+	ErrShortLinkExpired                        = errors.New("short link has expired")
 	ErrInvalidIdempotencyKey                   = errors.New("invalid idempotency key")
 	ErrMoneyDropIdempotencyConflict            = errors.New("idempotency key reuse with a different request is not allowed")
 	ErrMoneyDropIdempotencyInProgress          = errors.New("a claim with this idempotency key is already being processed")
@@ -123,6 +174,12 @@ var (
 	idempotencyKeyPattern                      = regexp.MustCompile(`^[A-Za-z0-9:_.-]+$`)
 )
 
+// Platform fee enforcement modes for P2P transfers from delinquent senders.
+const (
+	PlatformFeeEnforcementLogOnly = "log_only"
+	PlatformFeeEnforcementEnforce = "enforce"
+)
+
 type RateLimitError struct {
 	message           string
 	RetryAfterSeconds int
@@ -138,13 +195,26 @@ func (e *RateLimitError) Error() string {
 	return "too many requests. please try again shortly"
 }
 
+// RecipientUsernameChangedError is returned when a transfer targets a username the
+// recipient gave up recently. NewUsername is the handle the sender should use instead.
+type RecipientUsernameChangedError struct {
+	OldUsername string
+	NewUsername string
+}
+
+func (e *RecipientUsernameChangedError) Error() string {
+	return fmt.Sprintf("recipient username changed to %s", e.NewUsername)
+}
+
 // Service provides the core business logic for transactions.
 type Service struct {
 	repo                               store.Repository
-	anchorClient                       *anchorclient.Client
+	anchorClient                       *anchorclient.CachingAnchorClient
 	accountClient                      *accountclient.Client
-	eventProducer                      rmrabbit.Publisher
+	authClient                         *authclient.Client
+	eventProducer                      rmrabbit.PlatformFeePublisher
 	transferConsumer                   *TransferStatusConsumer
+	moneyDropPayouts                   chan uuid.UUID
 	adminAccountID                     string
 	transactionFeeKobo                 int64
 	moneyDropFeeKobo                   int64
@@ -158,16 +228,26 @@ type Service struct {
 	moneyDropIdempotencyTTL            time.Duration
 	moneyDropIdempotencyStaleWindow    time.Duration
 	moneyDropRateLimiter               moneyDropRateLimiter
+	platformFeeEnforcement             string
 
 	balanceFetchCircuitMu       sync.Mutex
 	balanceFetchCircuitOpenTill time.Time
+
+	balanceSyncRunning atomic.Bool
+
+	archiveAfterMonths int
+	archiveRunning     atomic.Bool
+
+	reconciliationRunning atomic.Bool
+
+	attachmentHosts map[string]bool
 }
 
 func NewService(
 	repo store.Repository,
 	anchor *anchorclient.Client,
 	accountClient *accountclient.Client,
-	producer rmrabbit.Publisher,
+	producer rmrabbit.PlatformFeePublisher,
 	adminAccountID string,
 	transactionFeeKobo int64,
 	moneyDropFeeKobo int64,
@@ -199,9 +279,15 @@ func NewService(
 		encryptionKey = nil
 	}
 
+	var anchorClient *anchorclient.CachingAnchorClient
+	if anchor != nil {
+		// Requests read the same balance more than once; transfers drop the cached entry.
+		anchorClient = anchorclient.NewCachingAnchorClient(anchor, anchorclient.DefaultBalanceCacheSize, anchorclient.DefaultBalanceCacheTTL)
+	}
+
 	svc := &Service{
 		repo:                               repo,
-		anchorClient:                       anchor,
+		anchorClient:                       anchorClient,
 		accountClient:                      accountClient,
 		eventProducer:                      producer,
 		adminAccountID:                     adminAccountID,
@@ -216,9 +302,16 @@ func NewService(
 		moneyDropPasswordLockoutSeconds:    defaultMoneyDropPwdLockoutSecs,
 		moneyDropIdempotencyTTL:            time.Duration(defaultMoneyDropIdempotencyMins) * time.Minute,
 		moneyDropIdempotencyStaleWindow:    time.Duration(defaultMoneyDropStaleClaimSecs) * time.Second,
+		archiveAfterMonths:                 defaultArchiveAfterMonths,
+		moneyDropPayouts:                   make(chan uuid.UUID, moneyDropPayoutQueueSize),
 	}
 
-	svc.transferConsumer = NewTransferStatusConsumer(repo)
+	var transfers transferLookup
+	if anchor != nil {
+		transfers = anchor
+	}
+	svc.transferConsumer = NewTransferStatusConsumer(repo, transfers)
+	svc.transferConsumer.beneficiaries = svc
 
 	return svc
 }
@@ -241,6 +334,31 @@ func (s *Service) GetMoneyDropFeePercent() float64 {
 	return s.moneyDropFeePercent
 }
 
+// GetFeeSchedules lists every fee this service charges with its configured amount.
+func (s *Service) GetFeeSchedules() []domain.FeeSchedule {
+	return []domain.FeeSchedule{
+		{
+			Name:        "p2p_transfer",
+			Description: "Charged on each transfer to another Transfa user",
+			AmountKobo:  s.transactionFeeKobo,
+			AppliesTo:   "p2p",
+		},
+		{
+			Name:        "self_transfer",
+			Description: "Charged on each withdrawal to an external bank account",
+			AmountKobo:  s.transactionFeeKobo,
+			AppliesTo:   "self_transfer",
+		},
+		{
+			Name:        "money_drop_creation",
+			Description: "Charged when a money drop is created",
+			AmountKobo:  s.moneyDropFeeKobo,
+			Percent:     s.moneyDropFeePercent,
+			AppliesTo:   "money_drop",
+		},
+	}
+}
+
 func (s *Service) ConfigureMoneyDropHardening(
 	claimRateLimitPerMinute int,
 	detailsRateLimitPerMinute int,
@@ -265,6 +383,39 @@ func (s *Service) ConfigureMoneyDropHardening(
 	}
 }
 
+// ConfigurePlatformFeeEnforcement sets whether P2P transfers from senders with an overdue
+// platform fee are rejected ("enforce") or only logged ("log_only"). Withdrawals to
+// external accounts are always blocked for delinquent users.
+func (s *Service) ConfigurePlatformFeeEnforcement(mode string) {
+	s.platformFeeEnforcement = mode
+}
+
+// ConfigureTransactionArchive sets how many months a completed or failed transaction
+// stays in the live table before the archive run moves it out.
+func (s *Service) ConfigureTransactionArchive(afterMonths int) {
+	if afterMonths > 0 {
+		s.archiveAfterMonths = afterMonths
+	}
+}
+
+// ConfigureTransactionAttachmentHosts sets the storage hosts attachment URLs may
+// point at. With none configured every attachment is rejected.
+func (s *Service) ConfigureTransactionAttachmentHosts(hosts []string) {
+	s.attachmentHosts = make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			s.attachmentHosts[host] = true
+		}
+	}
+}
+
+// ConfigureAuthClient makes VerifyTransactionPIN check PINs through the auth-service,
+// which owns the PIN and its lockout. Without it PINs are checked against the shared
+// credentials table directly.
+func (s *Service) ConfigureAuthClient(client *authclient.Client) {
+	s.authClient = client
+}
+
 func (s *Service) SetMoneyDropRateLimiter(rateLimiter moneyDropRateLimiter) {
 	s.moneyDropRateLimiter = rateLimiter
 }
@@ -276,6 +427,11 @@ func (s *Service) ResolveInternalUserID(ctx context.Context, clerkUserID string)
 	return s.repo.FindUserIDByClerkUserID(ctx, clerkUserID)
 }
 
+// IsUserClosed reports whether the user behind a Clerk user id has closed their account.
+func (s *Service) IsUserClosed(ctx context.Context, clerkUserID string) (bool, error) {
+	return s.repo.IsUserClosedByClerkUserID(ctx, clerkUserID)
+}
+
 // VerifyTransactionPIN validates the user-provided PIN against server-side hash and lockout state.
 func (s *Service) VerifyTransactionPIN(ctx context.Context, userID uuid.UUID, pin string) error {
 	if len(pin) != 4 {
@@ -287,6 +443,19 @@ func (s *Service) VerifyTransactionPIN(ctx context.Context, userID uuid.UUID, pi
 		}
 	}
 
+	if s.authClient != nil {
+		err := s.authClient.VerifyTransactionPIN(ctx, userID.String(), pin)
+		switch {
+		case errors.Is(err, authclient.ErrPINNotSet):
+			return store.ErrTransactionPINNotSet
+		case errors.Is(err, authclient.ErrPINLocked):
+			return ErrTransactionPINLocked
+		case errors.Is(err, authclient.ErrPINInvalid):
+			return ErrInvalidTransactionPIN
+		}
+		return err
+	}
+
 	credential, err := s.repo.GetUserSecurityCredentialByUserID(ctx, userID)
 	if err != nil {
 		return err
@@ -317,8 +486,28 @@ func (s *Service) VerifyTransactionPIN(ctx context.Context, userID uuid.UUID, pi
 	return nil
 }
 
+// normalizeP2PDelivery validates a P2P transfer's delivery option. Empty means
+// recipient_preference.
+func normalizeP2PDelivery(delivery string) (string, error) {
+	switch normalized := strings.TrimSpace(strings.ToLower(delivery)); normalized {
+	case "", domain.P2PDeliveryRecipientPreference:
+		return domain.P2PDeliveryRecipientPreference, nil
+	case domain.P2PDeliveryInternal:
+		return normalized, nil
+	default:
+		return "", ErrInvalidDelivery
+	}
+}
+
 // ProcessP2PTransfer handles the logic for a peer-to-peer transfer.
 func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, req domain.P2PTransferRequest) (*domain.Transaction, error) {
+	return s.processP2PTransfer(ctx, senderID, req, nil)
+}
+
+// processP2PTransfer is ProcessP2PTransfer with a hook: a non-nil recorded runs in
+// the database transaction that debits the sender and inserts txRecord, before
+// any money moves at Anchor.
+func (s *Service) processP2PTransfer(ctx context.Context, senderID uuid.UUID, req domain.P2PTransferRequest, recorded func(txRepo store.Repository, txRecord *domain.Transaction) error) (*domain.Transaction, error) {
 	normalizedRecipient, err := normalizeAndValidateUsernameInput(req.RecipientUsername)
 	if err != nil {
 		return nil, err
@@ -328,9 +517,17 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 	if req.Amount <= 0 {
 		return nil, ErrInvalidTransferAmount
 	}
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
 	if !isValidTransferDescription(req.Description) {
 		return nil, ErrInvalidDescription
 	}
+	delivery, err := normalizeP2PDelivery(req.Delivery)
+	if err != nil {
+		return nil, err
+	}
 
 	// 1. Get sender and recipient details
 	sender, err := s.repo.FindUserByID(ctx, senderID)
@@ -340,23 +537,37 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 
 	recipient, err := s.repo.FindUserByUsername(ctx, req.RecipientUsername)
 	if err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			err = s.renamedRecipientError(ctx, req.RecipientUsername, err)
+		}
 		return nil, fmt.Errorf("failed to find recipient: %w", err)
 	}
 	if recipient.ID == sender.ID {
 		return nil, ErrSelfTransferNotAllowed
 	}
+	// Frozen recipients are rejected outright; routing the credit elsewhere would
+	// move funds the recipient has been barred from receiving.
+	if !recipient.AllowReceiving {
+		return nil, store.ErrReceivingRestricted
+	}
 
+	// A failed lookup only keeps the transfer off external rails; the sender is
+	// rejected solely when delinquency is confirmed and enforcement is on.
 	senderDelinquent := false
 	if delinquent, err := s.repo.IsUserDelinquent(ctx, sender.ID); err != nil {
 		log.Printf("level=warn component=service flow=p2p_transfer msg=\"platform-fee status lookup failed; treating sender as delinquent\" sender_id=%s err=%v", sender.ID, err)
 		senderDelinquent = true
-	} else {
-		senderDelinquent = delinquent
+	} else if delinquent {
+		if s.platformFeeEnforcement == PlatformFeeEnforcementEnforce {
+			return nil, store.ErrPlatformFeeDelinquent
+		}
+		log.Printf("level=warn component=service flow=p2p_transfer msg=\"platform fee delinquent sender allowed in log-only mode\" sender_id=%s", sender.ID)
+		senderDelinquent = true
 	}
 
 	// 2. Validate sender permissions and funds
 	if !sender.AllowSending {
-		return nil, errors.New("sender account is not permitted to send funds")
+		return nil, store.ErrSendingRestricted
 	}
 	senderAccount, err := s.repo.FindAccountByUserID(ctx, sender.ID)
 	if err != nil {
@@ -383,12 +594,9 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 		}
 	}
 
-	// 3. Debit the sender's wallet immediately to lock funds
-	if err := s.repo.DebitWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); err != nil {
-		return nil, fmt.Errorf("failed to debit sender wallet: %w", err)
-	}
-
-	// 4. Create initial transaction record
+	// 3. Debit the sender's wallet to lock funds and 4. create the initial transaction
+	// record, atomically. The restriction flags are re-checked inside the debit so a
+	// freeze landing after the checks above still wins.
 	txRecord := &domain.Transaction{
 		ID:              uuid.New(),
 		SenderID:        sender.ID,
@@ -398,15 +606,30 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 		Status:          "pending",
 		Amount:          req.Amount,
 		Fee:             s.transactionFeeKobo,
+		Currency:        currency,
 		Description:     req.Description,
 		Category:        "p2p_transfer",
 	}
-	if err := s.repo.CreateTransaction(ctx, txRecord); err != nil {
-		// Refund the debited amount since transaction creation failed
-		if refundErr := s.repo.CreditWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
-			log.Printf("level=error component=service flow=p2p_transfer msg=\"wallet refund failed after tx record creation error\" sender_id=%s err=%v", sender.ID, refundErr)
+	var recordedInTx func(txRepo store.Repository) error
+	if recorded != nil {
+		recordedInTx = func(txRepo store.Repository) error { return recorded(txRepo, txRecord) }
+	}
+	if err := s.debitAndRecordTransaction(ctx, txRecord, func(txRepo store.Repository) error {
+		return txRepo.DebitWalletForP2PTransfer(ctx, sender.ID, recipient.ID, req.Amount+s.transactionFeeKobo)
+	}, recordedInTx); err != nil {
+		return nil, err
+	}
+
+	// 3.4. Close the cancellation window before any money moves on Anchor, starting
+	// with the fee below; a cancel landing after this point is rejected.
+	if err := s.claimTransactionForTransfer(ctx, txRecord.ID); err != nil {
+		if errors.Is(err, ErrTransactionCancelled) {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+		if refundErr := s.failAndRefundTransaction(ctx, txRecord.ID, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
+			log.Printf("level=error component=service flow=p2p_transfer msg=\"wallet refund failed after claim error\" sender_id=%s transaction_id=%s err=%v", sender.ID, txRecord.ID, refundErr)
+		}
+		return nil, err
 	}
 
 	// 3.5. Collect the transaction fee to admin account
@@ -424,11 +647,19 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 		}
 	}
 
-	// 5. Determine routing based on recipient's receiving preference and eligibility
-	recipientPreference, err := s.repo.FindOrCreateReceivingPreference(ctx, recipient.ID)
-	if err != nil {
-		log.Printf("level=warn component=service flow=p2p_transfer msg=\"recipient preference lookup failed; routing internal\" recipient_id=%s err=%v", recipient.ID, err)
-		recipientPreference = &domain.UserReceivingPreference{UseExternalAccount: false}
+	// 5. Determine routing: a sender asking for internal delivery skips the recipient's
+	// receiving preference; otherwise it and the recipient's eligibility decide. Every
+	// branch records its routing reason so support can explain the outcome.
+	recipientPreference := &domain.UserReceivingPreference{UseExternalAccount: false}
+	internalReason := domain.RoutingReasonSenderOverride
+	if delivery == domain.P2PDeliveryRecipientPreference {
+		internalReason = domain.RoutingReasonInternalPreferred
+		recipientPreference, err = s.repo.FindOrCreateReceivingPreference(ctx, recipient.ID)
+		if err != nil {
+			log.Printf("level=warn component=service flow=p2p_transfer msg=\"recipient preference lookup failed; routing internal\" recipient_id=%s err=%v", recipient.ID, err)
+			recipientPreference = &domain.UserReceivingPreference{UseExternalAccount: false}
+			internalReason = domain.RoutingReasonPreferenceLookupFailed
+		}
 	}
 
 	var anchorResp *anchorclient.TransferResponse
@@ -456,21 +687,27 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 				recipientBeneficiary, err = s.repo.FindOrCreateDefaultBeneficiary(ctx, recipient.ID)
 			}
 
+			if err == nil && recipientBeneficiary != nil && recipientBeneficiary.VerificationStatus == domain.BeneficiaryVerificationInvalid {
+				// The sender cannot fix the recipient's account, so deliver to the wallet.
+				recipientBeneficiary = nil
+			}
 			if err != nil || recipientBeneficiary == nil {
 				log.Printf("level=warn component=service flow=p2p_transfer msg=\"recipient external preference set but no beneficiary; routing internal\" recipient_id=%s", recipient.ID)
+				txRecord.RoutingDecision, txRecord.RoutingReason = domain.RoutingInternal, domain.RoutingReasonBeneficiaryMissing
 				reason := fmt.Sprintf("P2P Transfer to %s", req.RecipientUsername)
 				if req.Description != "" {
 					reason = fmt.Sprintf("P2P Transfer to %s: %s", req.RecipientUsername, req.Description)
 				}
-				anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason)
+				anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason, true)
 			} else {
 				txRecord.DestinationBeneficiaryID = &recipientBeneficiary.ID
+				txRecord.RoutingDecision, txRecord.RoutingReason = domain.RoutingExternal, domain.RoutingReasonExternalPreferred
 				// Create a proper reason for Anchor API
 				reason := fmt.Sprintf("P2P Transfer to %s", req.RecipientUsername)
 				if req.Description != "" {
 					reason = fmt.Sprintf("P2P Transfer to %s: %s", req.RecipientUsername, req.Description)
 				}
-				anchorResp, err = s.anchorClient.InitiateNIPTransfer(ctx, senderAccount.AnchorAccountID, recipientBeneficiary.AnchorCounterpartyID, reason, req.Amount)
+				anchorResp, err = s.anchorClient.InitiateNIPTransfer(ctx, senderAccount.AnchorAccountID, recipientBeneficiary.AnchorCounterpartyID, reason, req.Amount, transferIdempotencyKey(txRecord.ID, ""))
 				if err == nil {
 					if updateErr := s.repo.UpdateTransactionDestinations(ctx, txRecord.ID, nil, &recipientBeneficiary.ID); updateErr != nil {
 						log.Printf("level=warn component=service flow=p2p_transfer msg=\"failed to persist destination beneficiary\" transaction_id=%s err=%v", txRecord.ID, updateErr)
@@ -479,28 +716,32 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 
 			}
 		} else {
-			// Recipient wants external but is not eligible - route internally
+			// Recipient wants external but is not eligible, or the sender is behind on
+			// platform fees - route internally
+			txRecord.RoutingDecision, txRecord.RoutingReason = domain.RoutingInternal, domain.RoutingReasonQuotaExceeded
+			if !isEligibleForExternal {
+				txRecord.RoutingReason = domain.RoutingReasonNotEligible
+			}
 			reason := fmt.Sprintf("P2P Transfer to %s", req.RecipientUsername)
 			if req.Description != "" {
 				reason = fmt.Sprintf("P2P Transfer to %s: %s", req.RecipientUsername, req.Description)
 			}
-			anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason)
+			anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason, true)
 		}
 	} else {
-		// Recipient prefers internal wallet - route internally
+		// Recipient prefers internal wallet, or the sender asked for it - route internally
+		txRecord.RoutingDecision, txRecord.RoutingReason = domain.RoutingInternal, internalReason
 		reason := fmt.Sprintf("P2P Transfer to %s", req.RecipientUsername)
 		if req.Description != "" {
 			reason = fmt.Sprintf("P2P Transfer to %s: %s", req.RecipientUsername, req.Description)
 		}
-		anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason)
+		anchorResp, err = s.performInternalTransfer(ctx, txRecord, senderAccount, recipient, reason, false)
 	}
 
 	// 6. Handle Anchor API response
 	if err != nil {
-		// If Anchor transfer fails, mark our transaction as failed.
-		s.repo.UpdateTransactionStatus(ctx, txRecord.ID, "", "failed")
-		// Refund the debited amount since Anchor transfer failed
-		if refundErr := s.repo.CreditWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
+		// If Anchor transfer fails, mark our transaction as failed and refund the debit.
+		if refundErr := s.failAndRefundTransaction(ctx, txRecord.ID, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
 			log.Printf("level=error component=service flow=p2p_transfer msg=\"wallet refund failed after anchor transfer error\" sender_id=%s transaction_id=%s err=%v", sender.ID, txRecord.ID, refundErr)
 		}
 		return nil, fmt.Errorf("anchor transfer failed: %w", err)
@@ -516,6 +757,8 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 
 		metadata := store.UpdateTransactionMetadataParams{
 			AnchorTransferID: &transferID,
+			RoutingDecision:  optionalString(txRecord.RoutingDecision),
+			RoutingReason:    optionalString(txRecord.RoutingReason),
 		}
 		statusPending := "pending"
 		metadata.Status = &statusPending
@@ -531,9 +774,34 @@ func (s *Service) ProcessP2PTransfer(ctx context.Context, senderID uuid.UUID, re
 
 	txRecord.Status = "pending"
 
+	// 8. Let downstream consumers (push notifications) know the transfer is in flight.
+	s.publishP2PTransferInitiated(ctx, sender, recipient, txRecord)
+
 	return txRecord, nil
 }
 
+// publishP2PTransferInitiated emits the transfer.initiated.p2p event. Publishing is
+// best-effort: the transfer has already been accepted by Anchor at this point.
+func (s *Service) publishP2PTransferInitiated(ctx context.Context, sender, recipient *domain.User, txRecord *domain.Transaction) {
+	if s.eventProducer == nil || sender == nil || recipient == nil || txRecord == nil {
+		return
+	}
+
+	payload := domain.P2PTransferInitiatedPayload{
+		SenderUsername: sender.Username,
+		RecipientID:    recipient.ID,
+		Amount:         txRecord.Amount,
+		TransactionID:  txRecord.ID,
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "transfer.initiated.p2p", payload); err != nil {
+			log.Printf("level=warn component=service flow=p2p_transfer msg=\"transfer initiated event publish failed\" transaction_id=%s err=%v", payload.TransactionID, err)
+		}
+	}()
+}
+
 // ProcessBulkP2PTransfer processes up to 10 P2P transfers in one authenticated request.
 // It performs shared pre-validation and best-effort execution per item so one recipient
 // failure does not block other valid recipients.
@@ -552,7 +820,7 @@ func (s *Service) ProcessBulkP2PTransfer(ctx context.Context, senderID uuid.UUID
 		return nil, fmt.Errorf("failed to find sender: %w", err)
 	}
 	if !sender.AllowSending {
-		return nil, errors.New("sender account is not permitted to send funds")
+		return nil, store.ErrSendingRestricted
 	}
 
 	senderAccount, err := s.repo.FindAccountByUserID(ctx, senderID)
@@ -680,13 +948,33 @@ func (s *Service) ProcessBulkP2PTransfer(ctx context.Context, senderID uuid.UUID
 	return result, nil
 }
 
+// renamedRecipientError turns a failed recipient lookup into a
+// RecipientUsernameChangedError when the username was given up recently, so the
+// sender learns the new handle instead of getting "not found".
+func (s *Service) renamedRecipientError(ctx context.Context, username string, notFound error) error {
+	current, err := s.repo.FindCurrentUsernameByPreviousUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, store.ErrUserNotFound) {
+			log.Printf("level=warn component=service flow=p2p_transfer msg=\"previous username lookup failed\" username=%s err=%v", username, err)
+		}
+		return notFound
+	}
+	return &RecipientUsernameChangedError{OldUsername: strings.TrimSpace(username), NewUsername: current}
+}
+
 func isValidTransferDescription(description string) bool {
 	length := len(strings.TrimSpace(description))
 	return length >= 3 && length <= 100
 }
 
+// platformFeeOverdueMessage is shown when a transfer is refused over an unpaid platform fee.
+const platformFeeOverdueMessage = "Platform fee overdue: pay the outstanding fee to send money"
+
 func mapTransferError(err error) string {
+	var renamed *RecipientUsernameChangedError
 	switch {
+	case errors.As(err, &renamed):
+		return renamed.Error()
 	case errors.Is(err, store.ErrInsufficientFunds):
 		return "Insufficient funds"
 	case errors.Is(err, store.ErrUserNotFound):
@@ -699,6 +987,12 @@ func mapTransferError(err error) string {
 		return ErrInvalidRecipient.Error()
 	case errors.Is(err, ErrSelfTransferNotAllowed):
 		return ErrSelfTransferNotAllowed.Error()
+	case errors.Is(err, store.ErrReceivingRestricted):
+		return store.ErrReceivingRestricted.Error()
+	case errors.Is(err, store.ErrSendingRestricted):
+		return store.ErrSendingRestricted.Error()
+	case errors.Is(err, store.ErrPlatformFeeDelinquent):
+		return platformFeeOverdueMessage
 	default:
 		return "Transfer failed"
 	}
@@ -753,7 +1047,9 @@ func (s *Service) markBatchItemFailedWithRetry(ctx context.Context, itemID uuid.
 }
 
 // performInternalTransfer executes a book transfer and updates the transaction record.
-func (s *Service) performInternalTransfer(ctx context.Context, txRecord *domain.Transaction, senderAccount *domain.Account, recipient *domain.User, reason string) (*anchorclient.TransferResponse, error) {
+// rerouted marks a transfer to a recipient who asked for bank payouts but is paid into
+// their wallet instead; only those publish transfer.rerouted.internal.
+func (s *Service) performInternalTransfer(ctx context.Context, txRecord *domain.Transaction, senderAccount *domain.Account, recipient *domain.User, reason string, rerouted bool) (*anchorclient.TransferResponse, error) {
 	recipientAccount, err := s.repo.FindAccountByUserID(ctx, recipient.ID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find recipient's internal account: %w", err)
@@ -764,7 +1060,7 @@ func (s *Service) performInternalTransfer(ctx context.Context, txRecord *domain.
 	txRecord.RecipientID = &recipient.ID
 
 	// Publish event that transfer was rerouted
-	if s.eventProducer != nil {
+	if rerouted && s.eventProducer != nil {
 		s.eventProducer.Publish(ctx, "transfa.events", "transfer.rerouted.internal", domain.ReroutedInternalPayload{
 			RecipientID: recipient.ID,
 			SenderID:    txRecord.SenderID,
@@ -773,7 +1069,7 @@ func (s *Service) performInternalTransfer(ctx context.Context, txRecord *domain.
 		})
 	}
 
-	transferResp, err := s.anchorClient.InitiateBookTransfer(ctx, senderAccount.AnchorAccountID, recipientAccount.AnchorAccountID, reason, txRecord.Amount)
+	transferResp, err := s.anchorClient.InitiateBookTransfer(ctx, senderAccount.AnchorAccountID, recipientAccount.AnchorAccountID, reason, txRecord.Amount, transferIdempotencyKey(txRecord.ID, ""))
 	if err != nil {
 		return nil, err
 	}
@@ -807,6 +1103,10 @@ func (s *Service) ProcessSelfTransfer(ctx context.Context, senderID uuid.UUID, r
 	if req.Amount <= 0 {
 		return nil, ErrInvalidTransferAmount
 	}
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
 	if !isValidTransferDescription(req.Description) {
 		return nil, ErrInvalidDescription
 	}
@@ -828,10 +1128,13 @@ func (s *Service) ProcessSelfTransfer(ctx context.Context, senderID uuid.UUID, r
 	if err != nil {
 		return nil, fmt.Errorf("failed to find beneficiary: %w", err)
 	}
+	if beneficiary.VerificationStatus == domain.BeneficiaryVerificationInvalid {
+		return nil, ErrBeneficiaryAccountInvalid
+	}
 
 	// 2. Validate sender permissions and funds
 	if !sender.AllowSending {
-		return nil, errors.New("sender account is not permitted to send funds")
+		return nil, store.ErrSendingRestricted
 	}
 	senderAccount, err := s.repo.FindAccountByUserID(ctx, sender.ID)
 	if err != nil {
@@ -857,12 +1160,8 @@ func (s *Service) ProcessSelfTransfer(ctx context.Context, senderID uuid.UUID, r
 		return nil, store.ErrInsufficientFunds
 	}
 
-	// 3. Debit sender's wallet to lock funds
-	if err := s.repo.DebitWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); err != nil {
-		return nil, fmt.Errorf("failed to debit sender wallet: %w", err)
-	}
-
-	// 4. Create initial transaction record
+	// 3. Debit sender's wallet to lock funds and 4. create the initial transaction
+	// record, atomically.
 	txRecord := &domain.Transaction{
 		ID:                       uuid.New(),
 		SenderID:                 sender.ID,
@@ -872,15 +1171,25 @@ func (s *Service) ProcessSelfTransfer(ctx context.Context, senderID uuid.UUID, r
 		Status:                   "pending",
 		Amount:                   req.Amount,
 		Fee:                      s.transactionFeeKobo,
+		Currency:                 currency,
 		Description:              req.Description,
 		Category:                 "self_transfer",
 	}
-	if err := s.repo.CreateTransaction(ctx, txRecord); err != nil {
-		// Refund the debited amount since transaction creation failed
-		if refundErr := s.repo.CreditWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
-			log.Printf("level=error component=service flow=self_transfer msg=\"wallet refund failed after tx record creation error\" sender_id=%s err=%v", sender.ID, refundErr)
+	if err := s.debitAndRecordTransaction(ctx, txRecord, func(txRepo store.Repository) error {
+		return txRepo.DebitWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo)
+	}, nil); err != nil {
+		return nil, err
+	}
+
+	// 3.4. Close the cancellation window before the fee moves on Anchor.
+	if err := s.claimTransactionForTransfer(ctx, txRecord.ID); err != nil {
+		if errors.Is(err, ErrTransactionCancelled) {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+		if refundErr := s.failAndRefundTransaction(ctx, txRecord.ID, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
+			log.Printf("level=error component=service flow=self_transfer msg=\"wallet refund failed after claim error\" sender_id=%s transaction_id=%s err=%v", sender.ID, txRecord.ID, refundErr)
+		}
+		return nil, err
 	}
 
 	// 3.5. Collect the transaction fee to admin account
@@ -905,12 +1214,10 @@ func (s *Service) ProcessSelfTransfer(ctx context.Context, senderID uuid.UUID, r
 		reason = fmt.Sprintf("Self Transfer: %s", req.Description)
 	}
 
-	anchorResp, err := s.anchorClient.InitiateNIPTransfer(ctx, senderAccount.AnchorAccountID, beneficiary.AnchorCounterpartyID, reason, req.Amount)
+	anchorResp, err := s.anchorClient.InitiateNIPTransfer(ctx, senderAccount.AnchorAccountID, beneficiary.AnchorCounterpartyID, reason, req.Amount, transferIdempotencyKey(txRecord.ID, ""))
 	if err != nil {
-		// Mark transaction as failed and refund
-		s.repo.UpdateTransactionStatus(ctx, txRecord.ID, "", "failed")
-		// Refund the debited amount since Anchor transfer failed
-		if refundErr := s.repo.CreditWallet(ctx, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
+		// Mark transaction as failed and refund the debit.
+		if refundErr := s.failAndRefundTransaction(ctx, txRecord.ID, sender.ID, req.Amount+s.transactionFeeKobo); refundErr != nil {
 			log.Printf("level=error component=service flow=self_transfer msg=\"wallet refund failed after anchor transfer error\" sender_id=%s transaction_id=%s err=%v", sender.ID, txRecord.ID, refundErr)
 		}
 		return nil, fmt.Errorf("anchor NIP transfer failed: %w", err)
@@ -989,8 +1296,9 @@ func (s *Service) GetAccountBalance(ctx context.Context, userID uuid.UUID) (*dom
 	}
 
 	// Keep cached internal balance in sync using the same Anchor response (avoid a second Anchor call).
+	// A debit or credit that lands after the read wins over the Anchor value.
 	if account.Balance != anchorBalance.Data.AvailableBalance {
-		if err := s.repo.UpdateAccountBalance(ctx, userID, anchorBalance.Data.AvailableBalance); err != nil {
+		if _, err := s.repo.SyncAccountBalance(ctx, *account, anchorBalance.Data.AvailableBalance); err != nil {
 			log.Printf("level=warn component=service flow=get_balance msg=\"failed to update cached balance\" user_id=%s err=%v", userID, err)
 		}
 	}
@@ -1062,9 +1370,27 @@ func (s *Service) markAnchorBalanceFetchSuccess() {
 	s.balanceFetchCircuitOpenTill = time.Time{}
 }
 
-// GetTransactionHistory retrieves the transaction history for a user.
-func (s *Service) GetTransactionHistory(ctx context.Context, userID uuid.UUID) ([]domain.Transaction, error) {
-	return s.repo.FindTransactionsByUserID(ctx, userID)
+// GetTransactionHistory retrieves the transaction history for a user, optionally
+// limited to [filter.From, filter.To). Archived transactions are read as well when
+// the range reaches back into the archive; those rows have Archived set. Each
+// transaction carries the user's own note and attachments.
+func (s *Service) GetTransactionHistory(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error) {
+	transactions, err := s.transactionHistory(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.annotateTransactions(ctx, userID, transactions)
+	return transactions, nil
+}
+
+// transactionHistory is GetTransactionHistory without the user's notes and attachments.
+func (s *Service) transactionHistory(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error) {
+	includeArchive, err := s.historyNeedsArchive(ctx, filter.From)
+	if err != nil {
+		return nil, err
+	}
+	filter.IncludeArchive = includeArchive
+	return s.repo.FindTransactionsByUserID(ctx, userID, filter)
 }
 
 // GetTransactionHistoryWithUser retrieves transactions between the authenticated user and one counterparty.
@@ -1082,10 +1408,15 @@ func (s *Service) GetTransactionHistoryWithUser(ctx context.Context, userID uuid
 		return nil, nil, ErrSelfTransferNotAllowed
 	}
 
-	transactions, err := s.repo.FindTransactionsBetweenUsers(ctx, userID, counterparty.ID, limit, offset)
+	includeArchive, err := s.historyNeedsArchive(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	transactions, err := s.repo.FindTransactionsBetweenUsers(ctx, userID, counterparty.ID, limit, offset, includeArchive)
 	if err != nil {
 		return nil, nil, err
 	}
+	s.annotateTransactions(ctx, userID, transactions)
 
 	return counterparty, transactions, nil
 }
@@ -1197,64 +1528,38 @@ func (s *Service) CreatePaymentRequest(ctx context.Context, creatorID uuid.UUID,
 	if requestType != "general" && requestType != "individual" {
 		return nil, ErrInvalidPaymentRequestType
 	}
-	if len(title) < 3 || len(title) > maxPaymentRequestTitleLen {
-		return nil, ErrInvalidPaymentRequestTitle
-	}
-	if description != nil && len(*description) > maxPaymentRequestDescriptionLen {
-		return nil, ErrInvalidPaymentRequestDescription
+	if err := validatePaymentRequestDetails(title, description); err != nil {
+		return nil, err
 	}
 	if payload.Amount <= 0 {
 		return nil, ErrInvalidTransferAmount
 	}
 
-	var recipientUserID *uuid.UUID
-	var recipientUsername *string
-	var recipientFullName *string
+	newRequest := &domain.PaymentRequest{
+		ID:           uuid.New(),
+		CreatorID:    creatorID,
+		Status:       "pending", // Initial status is always pending.
+		RequestType:  requestType,
+		Title:        title,
+		Amount:       payload.Amount,
+		AllowPartial: payload.AllowPartial,
+		Description:  description,
+		ImageURL:     imageURL,
+	}
 
 	if requestType == "individual" {
 		if payload.RecipientUsername == nil || strings.TrimSpace(*payload.RecipientUsername) == "" {
 			return nil, ErrInvalidPaymentRequestRecipient
 		}
-
-		recipientLookup, normalizeErr := normalizeAndValidateUsernameInput(*payload.RecipientUsername)
-		if normalizeErr != nil {
-			return nil, ErrInvalidPaymentRequestRecipient
-		}
-		recipient, err := s.repo.FindUserByUsername(ctx, recipientLookup)
+		recipient, err := s.resolvePaymentRequestRecipient(ctx, creatorID, *payload.RecipientUsername)
 		if err != nil {
 			return nil, err
 		}
-		if recipient.ID == creatorID {
-			return nil, ErrSelfPaymentRequest
-		}
-
-		recipientUserID = &recipient.ID
-		username := recipient.Username
-		recipientUsername = &username
-		if recipient.FullName != nil {
-			fullName := strings.TrimSpace(*recipient.FullName)
-			if fullName != "" {
-				recipientFullName = &fullName
-			}
-		}
-	}
-
-	newRequest := &domain.PaymentRequest{
-		ID:                uuid.New(),
-		CreatorID:         creatorID,
-		Status:            "pending", // Initial status is always pending.
-		RequestType:       requestType,
-		Title:             title,
-		RecipientUserID:   recipientUserID,
-		RecipientUsername: recipientUsername,
-		RecipientFullName: recipientFullName,
-		Amount:            payload.Amount,
-		Description:       description,
-		ImageURL:          imageURL,
+		setPaymentRequestRecipient(newRequest, recipient)
 	}
 
 	// Persist the new request to the database via the repository.
-	created, err := s.repo.CreatePaymentRequest(ctx, newRequest)
+	created, err := s.createPaymentRequestWithShortCode(ctx, s.repo, newRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -1265,43 +1570,115 @@ func (s *Service) CreatePaymentRequest(ctx context.Context, creatorID uuid.UUID,
 		if creatorErr != nil {
 			log.Printf("level=warn component=service flow=payment_request msg=\"creator lookup failed for notification\" creator_id=%s err=%v", creatorID, creatorErr)
 		} else {
-			recipientID := *decorated.RecipientUserID
-			body := fmt.Sprintf("%s sent you a payment request.", formatUsername(creator.Username))
-			dedupeKey := fmt.Sprintf("request.incoming:%s:%s", decorated.ID, recipientID)
-			relatedEntityType := "payment_request"
-
-			s.emitInAppNotification(ctx, "create_payment_request", domain.InAppNotification{
-				ID:                uuid.New(),
-				UserID:            recipientID,
-				Category:          "request",
-				Type:              "request.incoming",
-				Title:             "Incoming Request",
-				Body:              &body,
-				Status:            "unread",
-				RelatedEntityType: &relatedEntityType,
-				RelatedEntityID:   &decorated.ID,
-				DedupeKey:         &dedupeKey,
-				Data: map[string]interface{}{
-					"request_id":        decorated.ID.String(),
-					"amount":            decorated.Amount,
-					"request_type":      decorated.RequestType,
-					"title":             decorated.Title,
-					"description":       decorated.Description,
-					"image_url":         decorated.ImageURL,
-					"actor_user_id":     creator.ID.String(),
-					"actor_username":    formatUsername(creator.Username),
-					"actor_full_name":   optionalTrimmedString(creator.FullName),
-					"display_status":    "pending",
-					"created_at":        decorated.CreatedAt.UTC().Format(time.RFC3339),
-					"recipient_user_id": recipientID.String(),
-				},
-			})
+			s.notifyIncomingPaymentRequest(ctx, "create_payment_request", creator, decorated)
 		}
 	}
 
 	return decorated, nil
 }
 
+func validatePaymentRequestDetails(title string, description *string) error {
+	if len(title) < 3 || len(title) > maxPaymentRequestTitleLen {
+		return ErrInvalidPaymentRequestTitle
+	}
+	if description != nil && len(*description) > maxPaymentRequestDescriptionLen {
+		return ErrInvalidPaymentRequestDescription
+	}
+	return nil
+}
+
+// resolvePaymentRequestRecipient looks up the user an individual request is sent to.
+// Creators cannot request money from themselves.
+func (s *Service) resolvePaymentRequestRecipient(ctx context.Context, creatorID uuid.UUID, rawUsername string) (*domain.User, error) {
+	recipientLookup, normalizeErr := normalizeAndValidateUsernameInput(rawUsername)
+	if normalizeErr != nil {
+		return nil, ErrInvalidPaymentRequestRecipient
+	}
+	recipient, err := s.repo.FindUserByUsername(ctx, recipientLookup)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == creatorID {
+		return nil, ErrSelfPaymentRequest
+	}
+	return recipient, nil
+}
+
+// setPaymentRequestRecipient snapshots the recipient's username and name onto req.
+func setPaymentRequestRecipient(req *domain.PaymentRequest, recipient *domain.User) {
+	recipientID := recipient.ID
+	username := recipient.Username
+	req.RecipientUserID = &recipientID
+	req.RecipientUsername = &username
+	if recipient.FullName != nil {
+		fullName := strings.TrimSpace(*recipient.FullName)
+		if fullName != "" {
+			req.RecipientFullName = &fullName
+		}
+	}
+}
+
+// notifyIncomingPaymentRequest tells the recipient of an individual request about it,
+// in the app and through the payment_request.received event.
+func (s *Service) notifyIncomingPaymentRequest(ctx context.Context, source string, creator *domain.User, req *domain.PaymentRequest) {
+	recipientID := *req.RecipientUserID
+	body := fmt.Sprintf("%s sent you a payment request.", formatUsername(creator.Username))
+	dedupeKey := fmt.Sprintf("request.incoming:%s:%s", req.ID, recipientID)
+	relatedEntityType := "payment_request"
+
+	s.emitInAppNotification(ctx, source, domain.InAppNotification{
+		ID:                uuid.New(),
+		UserID:            recipientID,
+		Category:          "request",
+		Type:              "request.incoming",
+		Title:             "Incoming Request",
+		Body:              &body,
+		Status:            "unread",
+		RelatedEntityType: &relatedEntityType,
+		RelatedEntityID:   &req.ID,
+		DedupeKey:         &dedupeKey,
+		Data: map[string]interface{}{
+			"request_id":        req.ID.String(),
+			"amount":            req.Amount,
+			"request_type":      req.RequestType,
+			"title":             req.Title,
+			"description":       req.Description,
+			"image_url":         req.ImageURL,
+			"actor_user_id":     creator.ID.String(),
+			"actor_username":    formatUsername(creator.Username),
+			"actor_full_name":   optionalTrimmedString(creator.FullName),
+			"display_status":    "pending",
+			"created_at":        req.CreatedAt.UTC().Format(time.RFC3339),
+			"recipient_user_id": recipientID.String(),
+		},
+	})
+	s.publishPaymentRequestReceived(ctx, creator, req)
+}
+
+// publishPaymentRequestReceived emits the payment_request.received event in the
+// background. The request is already persisted, so the publish runs on a context
+// detached from the caller's cancellation and a failure is only logged.
+func (s *Service) publishPaymentRequestReceived(ctx context.Context, creator *domain.User, req *domain.PaymentRequest) {
+	if s.eventProducer == nil || creator == nil || req == nil || req.RecipientUserID == nil {
+		return
+	}
+
+	payload := domain.PaymentRequestReceivedPayload{
+		CreatorUsername: creator.Username,
+		RecipientID:     *req.RecipientUserID,
+		Amount:          req.Amount,
+		Description:     req.Description,
+		RequestID:       req.ID,
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "payment_request.received", payload); err != nil {
+			log.Printf("level=warn component=service flow=payment_request msg=\"payment request received event publish failed\" request_id=%s err=%v", payload.RequestID, err)
+		}
+	}()
+}
+
 // ListPaymentRequests retrieves all payment requests for a given user.
 func (s *Service) ListPaymentRequests(ctx context.Context, creatorID uuid.UUID, opts domain.PaymentRequestListOptions) ([]domain.PaymentRequest, error) {
 	requests, err := s.repo.ListPaymentRequestsByCreator(ctx, creatorID, opts)
@@ -1314,18 +1691,32 @@ func (s *Service) ListPaymentRequests(ctx context.Context, creatorID uuid.UUID,
 	return requests, nil
 }
 
-// GetPaymentRequestByID retrieves a single payment request by its ID.
+// GetPaymentRequestByID retrieves a single payment request by its ID, with the
+// transfers made toward it.
 func (s *Service) GetPaymentRequestByID(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequest, error) {
 	request, err := s.repo.GetPaymentRequestByID(ctx, requestID, creatorID)
 	if err != nil || request == nil {
 		return request, err
 	}
-	return s.decoratePaymentRequest(request), nil
+	return s.loadPaymentRequestSettlements(ctx, request)
 }
 
-// DeletePaymentRequest soft-deletes a payment request owned by creatorID.
+// DeletePaymentRequest soft-deletes a payment request owned by creatorID and
+// publishes payment_request.deleted. It reports false when there is nothing to
+// delete, including a request deleted before, and returns
+// ErrPaymentRequestInProcessing while a payment of the request is in flight.
 func (s *Service) DeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error) {
-	return s.repo.DeletePaymentRequest(ctx, requestID, creatorID)
+	deleted, err := s.repo.SoftDeletePaymentRequest(ctx, requestID, creatorID)
+	if err != nil {
+		if errors.Is(err, store.ErrPaymentRequestInProcessing) {
+			return false, ErrPaymentRequestInProcessing
+		}
+		return false, err
+	}
+	if deleted {
+		s.publishPaymentRequestDeleted(ctx, requestID, creatorID)
+	}
+	return deleted, nil
 }
 
 // ListIncomingPaymentRequests retrieves incoming request cards for a recipient.
@@ -1340,17 +1731,38 @@ func (s *Service) ListIncomingPaymentRequests(ctx context.Context, recipientID u
 	return requests, nil
 }
 
-// GetIncomingPaymentRequestByID retrieves one incoming request detail by id.
+// GetIncomingPaymentRequestByID retrieves one incoming request detail by id, with
+// the transfers made toward it.
 func (s *Service) GetIncomingPaymentRequestByID(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error) {
 	request, err := s.repo.GetIncomingPaymentRequestByID(ctx, requestID, recipientID)
 	if err != nil || request == nil {
 		return request, err
 	}
+	return s.loadPaymentRequestSettlements(ctx, request)
+}
+
+func (s *Service) loadPaymentRequestSettlements(ctx context.Context, request *domain.PaymentRequest) (*domain.PaymentRequest, error) {
+	settlements, err := s.repo.ListPaymentRequestSettlements(ctx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	request.Settlements = settlements
 	return s.decoratePaymentRequest(request), nil
 }
 
 // PayIncomingPaymentRequest settles an incoming request by initiating a P2P transfer to the creator.
-func (s *Service) PayIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PayIncomingPaymentRequestResult, error) {
+// The request moves to processing in the database transaction that debits the payer
+// and records the transfer, so a request is never processing without its transfer;
+// if the transfer then fails at Anchor the request goes back to pending.
+//
+// amount is the part of the remaining balance to pay, or 0 for all of it. A request
+// that allows partial payment goes back to pending after each transfer completes
+// until the payments add up to its amount.
+func (s *Service) PayIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, amount int64) (*domain.PayIncomingPaymentRequestResult, error) {
+	if amount < 0 {
+		return nil, ErrInvalidTransferAmount
+	}
+
 	// Idempotency fast-path: return the already-settled request if client retries.
 	settledResult, err := s.resolveSettledIncomingPaymentRequest(ctx, requestID, recipientID)
 	if err != nil {
@@ -1360,96 +1772,142 @@ func (s *Service) PayIncomingPaymentRequest(ctx context.Context, requestID uuid.
 		return settledResult, nil
 	}
 
-	// Reconciliation fast-path: recover processing requests that already initiated transfer.
 	existing, err := s.repo.GetIncomingPaymentRequestByID(ctx, requestID, recipientID)
 	if err != nil {
 		return nil, err
 	}
-	if existing != nil && strings.ToLower(existing.Status) == "processing" {
-		reconciledResult, reconcileErr := s.tryReconcileProcessingIncomingRequest(ctx, existing, recipientID)
-		if reconcileErr != nil {
-			return nil, reconcileErr
-		}
-		if reconciledResult != nil {
-			return reconciledResult, nil
-		}
+	if existing == nil {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if result, err := s.resolveUnpayableIncomingPaymentRequest(ctx, existing, recipientID); result != nil || err != nil {
+		return result, err
 	}
 
-	request, err := s.repo.ClaimIncomingPaymentRequestForPayment(ctx, requestID, recipientID)
-	if err != nil {
-		if errors.Is(err, store.ErrPaymentRequestNotReady) {
-			settledResult, settledErr := s.resolveSettledIncomingPaymentRequest(ctx, requestID, recipientID)
-			if settledErr != nil {
-				return nil, settledErr
-			}
-			if settledResult != nil {
-				return settledResult, nil
-			}
-
-			existing, lookupErr := s.repo.GetIncomingPaymentRequestByID(ctx, requestID, recipientID)
-			if lookupErr != nil {
-				return nil, lookupErr
-			}
-			if existing == nil {
-				return nil, ErrPaymentRequestNotFound
-			}
-
-			if strings.ToLower(existing.Status) == "processing" {
-				reconciledResult, reconcileErr := s.tryReconcileProcessingIncomingRequest(ctx, existing, recipientID)
-				if reconcileErr != nil {
-					return nil, reconcileErr
-				}
-				if reconciledResult != nil {
-					return reconciledResult, nil
-				}
-			}
-
-			return nil, ErrPaymentRequestNotPending
-		}
-		return nil, err
+	remaining := paymentRequestRemaining(existing)
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining {
+		return nil, ErrPaymentAmountExceedsRemaining
+	}
+	if amount < remaining && !existing.AllowPartial {
+		return nil, ErrPartialPaymentNotAllowed
 	}
 
-	creator, err := s.repo.FindUserByID(ctx, request.CreatorID)
+	creator, err := s.repo.FindUserByID(ctx, existing.CreatorID)
 	if err != nil {
-		_ = s.repo.ReleasePaymentRequestFromProcessing(ctx, requestID, recipientID)
 		if errors.Is(err, store.ErrUserNotFound) {
 			return nil, ErrPaymentRequestNotFound
 		}
 		return nil, err
 	}
 
-	description := buildRequestSettlementDescription(request.Title)
-	txRecord, err := s.ProcessP2PTransfer(ctx, recipientID, domain.P2PTransferRequest{
+	var request *domain.PaymentRequest
+	description := buildRequestSettlementDescription(existing.Title)
+	txRecord, err := s.processP2PTransfer(ctx, recipientID, domain.P2PTransferRequest{
 		RecipientUsername: creator.Username,
-		Amount:            request.Amount,
+		Amount:            amount,
 		Description:       description,
+	}, func(txRepo store.Repository, txRecord *domain.Transaction) error {
+		if _, err := txRepo.ClaimIncomingPaymentRequestForPayment(ctx, requestID, recipientID, amount); err != nil {
+			return err
+		}
+		attached, err := txRepo.AttachProcessingPaymentRequestSettlementTransaction(ctx, requestID, recipientID, txRecord.ID)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.RecordPaymentRequestSettlement(ctx, requestID, txRecord.ID, amount); err != nil {
+			return err
+		}
+		request = attached
+		return nil
 	})
 	if err != nil {
-		_ = s.repo.ReleasePaymentRequestFromProcessing(ctx, requestID, recipientID)
-		return nil, err
-	}
-
-	attachedRequest, err := s.repo.AttachProcessingPaymentRequestSettlementTransaction(ctx, requestID, recipientID, txRecord.ID)
-	if err != nil {
+		if request != nil && request.SettledTxID != nil {
+			// The claim committed with the transfer record; the transfer failed afterwards.
+			if releaseErr := s.repo.ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx, *request.SettledTxID); releaseErr != nil {
+				log.Printf("level=error component=service flow=payment_request_pay msg=\"failed to release request after transfer failure\" request_id=%s payer_id=%s tx_id=%s err=%v", requestID, recipientID, *request.SettledTxID, releaseErr)
+			}
+		}
 		if errors.Is(err, store.ErrPaymentRequestNotReady) {
-			settledResult, settledErr := s.resolveSettledIncomingPaymentRequest(ctx, requestID, recipientID)
-			if settledErr != nil {
-				return nil, settledErr
+			// Another payment claimed the request first; report where it stands now.
+			current, lookupErr := s.repo.GetIncomingPaymentRequestByID(ctx, requestID, recipientID)
+			if lookupErr != nil {
+				return nil, lookupErr
 			}
-			if settledResult != nil {
-				return settledResult, nil
+			if current == nil {
+				return nil, ErrPaymentRequestNotFound
 			}
+			if result, err := s.resolveUnpayableIncomingPaymentRequest(ctx, current, recipientID); result != nil || err != nil {
+				return result, err
+			}
+			if amount > paymentRequestRemaining(current) {
+				// Another payment completed first and left less to pay.
+				return nil, ErrPaymentAmountExceedsRemaining
+			}
+			return nil, ErrPaymentRequestNotPending
 		}
-		log.Printf("level=warn component=service flow=payment_request_pay msg=\"failed to attach settlement transaction to processing request\" request_id=%s payer_id=%s tx_id=%s err=%v", requestID, recipientID, txRecord.ID, err)
-		attachedRequest = request
+		return nil, err
 	}
 
+	s.publishPaymentRequestProcessing(ctx, request, txRecord)
+
 	return &domain.PayIncomingPaymentRequestResult{
-		Request:     s.decoratePaymentRequest(attachedRequest),
+		Request:     s.decoratePaymentRequest(request),
 		Transaction: txRecord,
 	}, nil
 }
 
+// resolveUnpayableIncomingPaymentRequest returns the outcome for a request that is
+// not pending: the settled result once its transfer completed, or
+// ErrPaymentRequestNotPending. It returns nil, nil when the request may be paid.
+func (s *Service) resolveUnpayableIncomingPaymentRequest(ctx context.Context, request *domain.PaymentRequest, recipientID uuid.UUID) (*domain.PayIncomingPaymentRequestResult, error) {
+	switch strings.ToLower(strings.TrimSpace(request.Status)) {
+	case "pending":
+		return nil, nil
+	case "processing":
+		// Reconciliation fast-path: recover processing requests whose transfer settled.
+		reconciledResult, err := s.tryReconcileProcessingIncomingRequest(ctx, request, recipientID)
+		if err != nil || reconciledResult != nil {
+			return reconciledResult, err
+		}
+		if request.SettledTxID == nil {
+			// Claimed before claims carried their transfer; the claim decides whether it is stale.
+			return nil, nil
+		}
+	case "fulfilled", "paid":
+		settledResult, err := s.resolveSettledIncomingPaymentRequest(ctx, request.ID, recipientID)
+		if err != nil || settledResult != nil {
+			return settledResult, err
+		}
+	}
+	return nil, ErrPaymentRequestNotPending
+}
+
+// publishPaymentRequestProcessing emits the payment_request.processing event once
+// the settlement transfer is in flight. Like publishPaymentRequestReceived it runs
+// in the background and a failure is only logged.
+func (s *Service) publishPaymentRequestProcessing(ctx context.Context, req *domain.PaymentRequest, txRecord *domain.Transaction) {
+	if s.eventProducer == nil || req == nil || req.RecipientUserID == nil || txRecord == nil {
+		return
+	}
+
+	payload := domain.PaymentRequestProcessingPayload{
+		RequestID:     req.ID,
+		CreatorID:     req.CreatorID,
+		PayerID:       *req.RecipientUserID,
+		Amount:        txRecord.Amount,
+		TransactionID: txRecord.ID,
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "payment_request.processing", payload); err != nil {
+			log.Printf("level=warn component=service flow=payment_request msg=\"payment request processing event publish failed\" request_id=%s err=%v", payload.RequestID, err)
+		}
+	}()
+}
+
 func (s *Service) resolveSettledIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PayIncomingPaymentRequestResult, error) {
 	request, err := s.repo.GetIncomingPaymentRequestByID(ctx, requestID, recipientID)
 	if err != nil {
@@ -1508,7 +1966,7 @@ func (s *Service) tryReconcileProcessingIncomingRequest(ctx context.Context, req
 			ctx,
 			recipientID,
 			request.CreatorID,
-			request.Amount,
+			paymentRequestRemaining(request),
 			description,
 			since,
 		)
@@ -1565,16 +2023,16 @@ func (s *Service) publishRequestPaidNotification(ctx context.Context, request *d
 		}
 	}
 
-	body := fmt.Sprintf("Your request \"%s\" has been paid.", request.Title)
-	dedupeKey := fmt.Sprintf("request.paid:%s:%s", request.ID, txRecord.ID)
+	notificationType, title, body := requestPaidNotification(request)
+	dedupeKey := fmt.Sprintf("%s:%s:%s", notificationType, request.ID, txRecord.ID)
 	relatedEntityType := "payment_request"
 
 	if err := s.repo.CreateInAppNotification(ctx, domain.InAppNotification{
 		ID:                uuid.New(),
 		UserID:            request.CreatorID,
 		Category:          "request",
-		Type:              "request.paid",
-		Title:             "Request Paid",
+		Type:              notificationType,
+		Title:             title,
 		Body:              &body,
 		Status:            "unread",
 		RelatedEntityType: &relatedEntityType,
@@ -1584,6 +2042,8 @@ func (s *Service) publishRequestPaidNotification(ctx context.Context, request *d
 			"request_id":        request.ID.String(),
 			"transaction_id":    txRecord.ID.String(),
 			"amount":            request.Amount,
+			"amount_paid":       request.AmountPaid,
+			"remaining_amount":  paymentRequestRemaining(request),
 			"status":            request.Status,
 			"display_status":    request.DisplayStatus,
 			"paid_by_user_id":   payerID.String(),
@@ -1594,10 +2054,20 @@ func (s *Service) publishRequestPaidNotification(ctx context.Context, request *d
 			"recipient_user_id": optionalUUIDString(request.RecipientUserID),
 		},
 	}); err != nil {
-		log.Printf("level=warn component=service flow=payment_request_pay msg=\"failed to emit request.paid notification\" request_id=%s payer_id=%s tx_id=%s err=%v", request.ID, payerID, txRecord.ID, err)
+		log.Printf("level=warn component=service flow=payment_request_pay msg=\"failed to emit %s notification\" request_id=%s payer_id=%s tx_id=%s err=%v", notificationType, request.ID, payerID, txRecord.ID, err)
 	}
 }
 
+// requestPaidNotification returns the in-app notification type, title and body for
+// a completed transfer toward request: request.paid once the request is fulfilled,
+// request.partially_paid while part of it is still owed.
+func requestPaidNotification(request *domain.PaymentRequest) (string, string, string) {
+	if isPaymentRequestPaid(request) {
+		return "request.paid", "Request Paid", fmt.Sprintf("Your request \"%s\" has been paid.", request.Title)
+	}
+	return "request.partially_paid", "Request Partly Paid", fmt.Sprintf("Your request \"%s\" received a payment toward the total.", request.Title)
+}
+
 // DeclineIncomingPaymentRequest declines one pending incoming request.
 func (s *Service) DeclineIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, reason *string) (*domain.PaymentRequest, error) {
 	normalizedReason := normalizeOptionalString(reason)
@@ -1659,10 +2129,62 @@ func (s *Service) DeclineIncomingPaymentRequest(ctx context.Context, requestID u
 			"request_original_status": "pending",
 		},
 	})
+	s.publishPaymentRequestDeclined(ctx, request, recipientID)
 
 	return request, nil
 }
 
+// publishPaymentRequestDeclined emits the payment_request.declined event. Like
+// publishPaymentRequestReceived it runs in the background and a failure is only
+// logged.
+func (s *Service) publishPaymentRequestDeclined(ctx context.Context, req *domain.PaymentRequest, recipientID uuid.UUID) {
+	if s.eventProducer == nil || req == nil {
+		return
+	}
+
+	declinedAt := time.Now().UTC()
+	if req.DeclinedAt != nil {
+		declinedAt = req.DeclinedAt.UTC()
+	}
+	payload := domain.PaymentRequestDeclinedPayload{
+		RequestID:   req.ID,
+		CreatorID:   req.CreatorID,
+		RecipientID: recipientID,
+		Amount:      req.Amount,
+		Reason:      req.DeclinedReason,
+		DeclinedAt:  declinedAt,
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "payment_request.declined", payload); err != nil {
+			log.Printf("level=warn component=service flow=payment_request msg=\"payment request declined event publish failed\" request_id=%s err=%v", payload.RequestID, err)
+		}
+	}()
+}
+
+// publishPaymentRequestDeleted emits the payment_request.deleted event. Like the
+// other payment request events it runs in the background and a failure is only
+// logged.
+func (s *Service) publishPaymentRequestDeleted(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) {
+	if s.eventProducer == nil {
+		return
+	}
+
+	payload := domain.PaymentRequestDeletedPayload{
+		RequestID: requestID,
+		CreatorID: creatorID,
+		DeletedAt: time.Now().UTC(),
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "payment_request.deleted", payload); err != nil {
+			log.Printf("level=warn component=service flow=payment_request msg=\"payment request deleted event publish failed\" request_id=%s err=%v", payload.RequestID, err)
+		}
+	}()
+}
+
 func (s *Service) ListInAppNotifications(ctx context.Context, userID uuid.UUID, opts domain.NotificationListOptions) ([]domain.InAppNotification, error) {
 	return s.repo.ListInAppNotifications(ctx, userID, opts)
 }
@@ -1679,6 +2201,16 @@ func (s *Service) GetInAppNotificationUnreadCounts(ctx context.Context, userID u
 	return s.repo.GetInAppNotificationUnreadCounts(ctx, userID)
 }
 
+// GetInAppNotificationUnreadCount returns the total number of unread notifications,
+// for the inbox badge.
+func (s *Service) GetInAppNotificationUnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	counts, err := s.repo.GetInAppNotificationUnreadCounts(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return counts.Total, nil
+}
+
 func (s *Service) emitInAppNotification(ctx context.Context, source string, item domain.InAppNotification) {
 	if err := s.repo.CreateInAppNotification(ctx, item); err != nil {
 		log.Printf(
@@ -1697,27 +2229,46 @@ func (s *Service) decoratePaymentRequest(req *domain.PaymentRequest) *domain.Pay
 		return nil
 	}
 
-	switch strings.ToLower(req.Status) {
-	case "fulfilled", "paid":
-		req.DisplayStatus = "paid"
-	case "declined":
-		req.DisplayStatus = "declined"
-	case "processing":
-		req.DisplayStatus = "pending"
-	default:
-		req.DisplayStatus = "pending"
-	}
+	req.RemainingAmount = paymentRequestRemaining(req)
+	req.DisplayStatus = paymentRequestDisplayStatus(req.Status)
 
 	if req.ShareableLink == "" {
-		req.ShareableLink = fmt.Sprintf("https://transfa.app/pay?request_id=%s", req.ID.String())
+		req.ShareableLink = s.paymentRequestShareableLink(req)
 	}
 	if req.QRCodeContent == "" {
-		req.QRCodeContent = req.ShareableLink
+		req.QRCodeContent = paymentRequestDeepLink(req)
 	}
 
 	return req
 }
 
+// paymentRequestDisplayStatus collapses a stored request status into what users see:
+// pending, paid or declined.
+func paymentRequestDisplayStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "fulfilled", "paid":
+		return "paid"
+	case "declined":
+		return "declined"
+	default:
+		return "pending"
+	}
+}
+
+func isPaymentRequestPaid(req *domain.PaymentRequest) bool {
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	return status == "fulfilled" || status == "paid"
+}
+
+// paymentRequestRemaining is what is still owed on a request. A paid request owes
+// nothing even if it was settled before amount_paid was tracked.
+func paymentRequestRemaining(req *domain.PaymentRequest) int64 {
+	if isPaymentRequestPaid(req) || req.AmountPaid >= req.Amount {
+		return 0
+	}
+	return req.Amount - req.AmountPaid
+}
+
 func normalizeOptionalString(value *string) *string {
 	if value == nil {
 		return nil
@@ -1789,6 +2340,17 @@ func buildRequestSettlementDescription(title string) string {
 	return fmt.Sprintf("%s: %s", base, trimmed[:remaining])
 }
 
+// transferIdempotencyKey returns the Anchor idempotency key option for a transfer made
+// on behalf of transaction txID. purpose tells apart further transfers the same
+// transaction makes, such as its fee; the transaction's own transfer passes "".
+func transferIdempotencyKey(txID uuid.UUID, purpose string) anchorclient.TransferOption {
+	key := txID.String()
+	if purpose != "" {
+		key += ":" + purpose
+	}
+	return anchorclient.WithIdempotencyKey(key)
+}
+
 // collectTransactionFee transfers the transaction fee to the admin account.
 func (s *Service) collectTransactionFee(ctx context.Context, parentTx *domain.Transaction, sourceAccount *domain.Account, amount int64, description string) error {
 	if s.adminAccountID == "" {
@@ -1809,10 +2371,15 @@ func (s *Service) collectTransactionFee(ctx context.Context, parentTx *domain.Tr
 	_ = adminBalance
 
 	// Perform the actual transfer from source account to admin account with one retry.
+	// Both attempts share an idempotency key so a retry cannot collect the fee twice.
+	var opts []anchorclient.TransferOption
+	if parentTx != nil {
+		opts = append(opts, transferIdempotencyKey(parentTx.ID, "fee"))
+	}
 	const maxAttempts = 2
 	var transferResp *anchorclient.TransferResponse
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		transferResp, err = s.anchorClient.InitiateBookTransfer(ctx, sourceAccount.AnchorAccountID, s.adminAccountID, description, amount)
+		transferResp, err = s.anchorClient.InitiateBookTransfer(ctx, sourceAccount.AnchorAccountID, s.adminAccountID, description, amount, opts...)
 		if err == nil {
 			break
 		}
@@ -1831,12 +2398,24 @@ func (s *Service) collectTransactionFee(ctx context.Context, parentTx *domain.Tr
 	}
 	if parentTx != nil {
 		log.Printf("level=info component=service flow=fee_collection msg=\"fee transfer created\" transaction_id=%s amount=%d anchor_transfer_id=%s", parentTx.ID, amount, transferResp.Data.ID)
+		// The fee transfer's status events are matched through this record; without it
+		// a failed fee transfer goes unnoticed.
+		if err := s.repo.CreateFeeCollection(ctx, domain.FeeCollection{
+			ParentTransactionID: parentTx.ID,
+			UserID:              parentTx.SenderID,
+			Amount:              amount,
+			AnchorTransferID:    transferResp.Data.ID,
+		}); err != nil {
+			log.Printf("level=error component=service flow=fee_collection msg=\"failed to record fee transfer\" transaction_id=%s anchor_transfer_id=%s err=%v", parentTx.ID, transferResp.Data.ID, err)
+		}
 	}
 
 	return nil
 }
 
-// syncAccountBalance synchronizes the internal database balance with Anchor API
+// syncAccountBalance synchronizes the internal database balance with Anchor API. The
+// write is skipped if the account row changed after it was read, so a concurrent debit
+// or credit is never overwritten by an older Anchor balance.
 func (s *Service) syncAccountBalance(ctx context.Context, userID uuid.UUID) error {
 	// Get the account from internal database
 	account, err := s.repo.FindAccountByUserID(ctx, userID)
@@ -1853,7 +2432,7 @@ func (s *Service) syncAccountBalance(ctx context.Context, userID uuid.UUID) erro
 	// Update the internal database with the Anchor balance
 	newBalance := anchorBalance.Data.AvailableBalance
 	if account.Balance != newBalance {
-		if err := s.repo.UpdateAccountBalance(ctx, userID, newBalance); err != nil {
+		if _, err := s.repo.SyncAccountBalance(ctx, *account, newBalance); err != nil {
 			return fmt.Errorf("failed to update account balance: %w", err)
 		}
 	}
@@ -1892,6 +2471,10 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 	if req.TotalAmount <= 0 {
 		return nil, ErrInvalidMoneyDropTotalAmount
 	}
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
 	if req.NumberOfPeople <= 0 {
 		return nil, ErrInvalidMoneyDropPeopleCount
 	}
@@ -1984,11 +2567,11 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 
 	// 3. Transfer funds from primary account to money drop account via Book Transfer
 	reason := fmt.Sprintf("Money Drop Funding - Total: %d kobo", req.TotalAmount)
-	_, err = s.anchorClient.InitiateBookTransfer(ctx, primaryAccount.AnchorAccountID, moneyDropAccount.AnchorAccountID, reason, req.TotalAmount)
+	fundingResp, err := s.anchorClient.InitiateBookTransfer(ctx, primaryAccount.AnchorAccountID, moneyDropAccount.AnchorAccountID, reason, req.TotalAmount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transfer funds to money drop account: %w", err)
 	}
-	log.Printf("level=info component=service flow=money_drop_create msg=\"funding transfer created\" user_id=%s amount=%d", userID, req.TotalAmount)
+	log.Printf("level=info component=service flow=money_drop_create msg=\"funding transfer created\" user_id=%s amount=%d anchor_transfer_id=%s", userID, req.TotalAmount, fundingResp.Data.ID)
 
 	if err := s.syncMoneyDropAccountBalance(ctx, moneyDropAccount.ID, moneyDropAccount.AnchorAccountID); err != nil {
 		log.Printf("level=warn component=service flow=money_drop_create msg=\"money-drop account sync failed after funding\" account_id=%s err=%v", moneyDropAccount.ID, err)
@@ -2027,6 +2610,7 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 		FeePercentage:          s.moneyDropFeePercent,
 		FundingSourceAccountID: primaryAccount.ID,
 		MoneyDropAccountID:     moneyDropAccount.ID,
+		Currency:               currency,
 	}
 
 	createdDrop, err := s.repo.CreateMoneyDrop(ctx, drop)
@@ -2047,7 +2631,9 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 		return nil, fmt.Errorf("failed to create money drop record: %w", err)
 	}
 
-	// 6. Log the funding transaction
+	// 6. Log the funding transaction. It carries the funding transfer's ID so a failed
+	// funding transfer is matched back to it.
+	fundingTransferID := fundingResp.Data.ID
 	fundingTx := &domain.Transaction{
 		ID:                   uuid.New(),
 		SenderID:             userID,
@@ -2058,7 +2644,10 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 		Status:               "completed",
 		Amount:               req.TotalAmount,
 		Fee:                  feeAmount,
+		Currency:             currency,
 		Description:          fmt.Sprintf("Funding for Money Drop #%s", createdDrop.ID.String()),
+		AnchorTransferID:     &fundingTransferID,
+		TransferType:         "book",
 	}
 	if err := s.repo.CreateTransaction(ctx, fundingTx); err != nil {
 		log.Printf("level=warn component=service flow=money_drop_create msg=\"failed to persist funding transaction log\" user_id=%s err=%v", userID, err)
@@ -2066,19 +2655,9 @@ func (s *Service) CreateMoneyDrop(ctx context.Context, userID uuid.UUID, req dom
 
 	// 6.5. Collect money drop creation fee to admin account (if fee > 0).
 	// This is intentionally after drop creation so rollback paths for failed creates are deterministic.
+	// The fee is charged on the funding transaction, which carries it.
 	if feeAmount > 0 {
-		tempFeeTx := &domain.Transaction{
-			ID:              uuid.New(),
-			SenderID:        userID,
-			SourceAccountID: primaryAccount.ID,
-			Type:            "money_drop_fee",
-			Category:        "Money Drop",
-			Status:          "pending",
-			Amount:          0,
-			Fee:             feeAmount,
-			Description:     "Money Drop Creation Fee",
-		}
-		if err := s.collectTransactionFee(ctx, tempFeeTx, primaryAccount, feeAmount, "Money Drop Creation Fee"); err != nil {
+		if err := s.collectTransactionFee(ctx, fundingTx, primaryAccount, feeAmount, "Money Drop Creation Fee"); err != nil {
 			log.Printf("level=warn component=service flow=money_drop_create msg=\"money-drop creation fee collection failed\" user_id=%s err=%v", userID, err)
 		}
 	}
@@ -2352,14 +2931,20 @@ func (s *Service) ClaimMoneyDrop(ctx context.Context, claimantID uuid.UUID, drop
 	if err != nil {
 		return nil, fmt.Errorf("could not find claimant's primary account: %w", err)
 	}
+	if claimantAccount.AnchorAccountID == "" {
+		return nil, fmt.Errorf("claimant account does not have an Anchor account ID")
+	}
 
 	// 3. Get money drop account
 	moneyDropAccount, err := s.repo.FindMoneyDropAccountByUserID(ctx, drop.CreatorID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find money drop account: %w", err)
 	}
+	if moneyDropAccount.AnchorAccountID == "" {
+		return nil, fmt.Errorf("money drop account does not have an Anchor account ID")
+	}
 
-	// 4. Perform atomic claim in database
+	// 4. Perform atomic claim in database. The claim is recorded as payout_pending.
 	claimTxID, err = s.repo.ClaimMoneyDropAtomic(ctx, dropID, claimantID, claimantAccount.ID, moneyDropAccount.ID, drop.AmountPerClaim)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process claim: %w", err)
@@ -2371,93 +2956,18 @@ func (s *Service) ClaimMoneyDrop(ctx context.Context, claimantID uuid.UUID, drop
 		return nil, fmt.Errorf("could not find creator's user record: %w", err)
 	}
 
-	// 6. Verify money drop account has Anchor account ID
-	if moneyDropAccount.AnchorAccountID == "" {
-		return nil, fmt.Errorf("money drop account does not have an Anchor account ID")
-	}
-
-	// 7. Initiate BookTransfer from money drop account to claimant's account.
-	// Include deterministic transaction token so webhook reconciliation can recover from unknown initiation outcomes.
-	reason := buildMoneyDropClaimTransferReason(claimTxID, creator.Username)
+	// 6. Hand the payout to the payout workers so a slow Anchor does not hold up the
+	// claimant's request. The claim is already recorded, so the sweep pays it out even
+	// if this instance stops before a worker reaches it.
+	s.enqueueMoneyDropPayout(claimTxID)
+	s.publishMoneyDropClaimed(ctx, drop, claimantID, claimTxID)
+	s.notifyMoneyDropClaimed(ctx, drop, claimantID, claimTxID)
 
-	transferResp, err := s.anchorClient.InitiateBookTransfer(
-		ctx,
-		moneyDropAccount.AnchorAccountID,
-		claimantAccount.AnchorAccountID,
-		reason,
-		drop.AmountPerClaim,
-	)
-	if err != nil {
-		log.Printf("level=error component=service flow=money_drop_claim msg=\"anchor transfer initiation failed\" money_drop_id=%s claimant_id=%s err=%v", dropID, claimantID, err)
-		// Only compensate/revert when Anchor explicitly rejected the request.
-		// Transport/timeouts are ambiguous and may still settle, so claims must remain pending.
-		var anchorErr *anchorclient.ErrorResponse
-		if errors.As(err, &anchorErr) && anchorErr.IsExplicitRejection() {
-			if revertErr := s.repo.RevertMoneyDropClaimAtomic(ctx, dropID, claimantID, claimTxID); revertErr != nil {
-				log.Printf("level=error component=service flow=money_drop_claim msg=\"failed to revert claim after explicit anchor rejection\" money_drop_id=%s claimant_id=%s claim_transaction_id=%s err=%v", dropID, claimantID, claimTxID, revertErr)
-				return nil, fmt.Errorf("failed to initiate payout and failed to rollback claim: %w", revertErr)
-			}
-			return nil, fmt.Errorf("failed to initiate payout transfer: %w", err)
-		}
-
-		log.Printf("level=warn component=service flow=money_drop_claim msg=\"payout initiation state is unknown; claim kept pending\" money_drop_id=%s claimant_id=%s claim_transaction_id=%s err=%v", dropID, claimantID, claimTxID, err)
-		response := &domain.ClaimMoneyDropResponse{
-			Message:         "Claim received. Payout is being processed and will reflect shortly.",
-			AmountClaimed:   drop.AmountPerClaim,
-			CreatorUsername: creator.Username,
-		}
-		if idempotencyStore != nil && idempotencyAcquired {
-			if completeErr := idempotencyStore.CompleteMoneyDropClaimIdempotency(ctx, dropID, claimantID, idempotencyKey, claimTxID, *response); completeErr != nil {
-				log.Printf("level=warn component=service flow=money_drop_claim msg=\"failed to persist idempotent completion state for pending payout\" money_drop_id=%s claimant_id=%s claim_transaction_id=%s err=%v", dropID, claimantID, claimTxID, completeErr)
-			}
-		}
-		claimSucceeded = true
-		return response, nil
-	}
-	anchorTransferID := strings.TrimSpace(transferResp.Data.ID)
-	transferType := "book"
-	anchorReason := buildMoneyDropClaimAnchorReason(dropID, "transfer_initiated")
-	clearedFailureReason := ""
-	metadata := store.UpdateTransactionMetadataParams{
-		TransferType:  &transferType,
-		FailureReason: &clearedFailureReason,
-		AnchorReason:  &anchorReason,
-	}
-	if anchorTransferID != "" {
-		metadata.AnchorTransferID = &anchorTransferID
-	}
-	if metaErr := s.repo.UpdateTransactionMetadata(ctx, claimTxID, metadata); metaErr != nil {
-		// Fallback to the simpler status update path when full metadata update fails.
-		// This still persists anchor_transfer_id, which prevents duplicate reconciliation retries.
-		if anchorTransferID != "" {
-			if fallbackErr := s.repo.UpdateTransactionStatus(ctx, claimTxID, anchorTransferID, "pending"); fallbackErr == nil {
-				log.Printf("level=warn component=service flow=money_drop_claim msg=\"metadata update failed; persisted anchor transfer id via fallback\" money_drop_id=%s claimant_id=%s claim_transaction_id=%s anchor_transfer_id=%s err=%v", dropID, claimantID, claimTxID, anchorTransferID, metaErr)
-			} else {
-				return nil, fmt.Errorf("failed to persist claim payout transfer reference: metadata_err=%v fallback_err=%w", metaErr, fallbackErr)
-			}
-		} else {
-			return nil, fmt.Errorf("failed to persist claim payout metadata: %w", metaErr)
-		}
-	}
-	log.Printf("level=info component=service flow=money_drop_claim msg=\"anchor transfer created\" money_drop_id=%s claimant_id=%s amount=%d anchor_transfer_id=%s", dropID, claimantID, drop.AmountPerClaim, anchorTransferID)
-
-	// 7.5. Sync money drop account balance after claim
-	if err := s.syncMoneyDropAccountBalance(ctx, moneyDropAccount.ID, moneyDropAccount.AnchorAccountID); err != nil {
-		log.Printf("level=warn component=service flow=money_drop_claim msg=\"money-drop account sync failed after claim\" account_id=%s err=%v", moneyDropAccount.ID, err)
-		// Don't fail the operation, balance will sync later
-	}
-
-	// 7.6. Sync claimant's account balance after receiving funds
-	if err := s.syncAccountBalance(ctx, claimantID); err != nil {
-		log.Printf("level=warn component=service flow=money_drop_claim msg=\"claimant balance sync failed\" claimant_id=%s err=%v", claimantID, err)
-		// Don't fail the operation, balance will sync later
-	}
-
-	// 8. Prepare and return response
 	response := &domain.ClaimMoneyDropResponse{
-		Message:         "Money drop claimed successfully!",
+		Message:         "Claim received. Your payout is on its way.",
 		AmountClaimed:   drop.AmountPerClaim,
 		CreatorUsername: creator.Username,
+		PayoutStatus:    domain.MoneyDropPayoutPending,
 	}
 
 	if idempotencyStore != nil && idempotencyAcquired {
@@ -2467,10 +2977,34 @@ func (s *Service) ClaimMoneyDrop(ctx context.Context, claimantID uuid.UUID, drop
 	}
 	claimSucceeded = true
 
-	log.Printf("level=info component=service flow=money_drop_claim msg=\"claim completed\" money_drop_id=%s claimant_id=%s", dropID, claimantID)
+	log.Printf("level=info component=service flow=money_drop_claim msg=\"claim recorded; payout queued\" money_drop_id=%s claimant_id=%s claim_transaction_id=%s", dropID, claimantID, claimTxID)
 	return response, nil
 }
 
+// publishMoneyDropClaimed emits the money_drop.claimed event in the background so the
+// creator can be notified. The claim is already recorded, so a failure is only logged.
+func (s *Service) publishMoneyDropClaimed(ctx context.Context, drop *domain.MoneyDrop, claimantID uuid.UUID, claimTxID uuid.UUID) {
+	if s.eventProducer == nil || drop == nil {
+		return
+	}
+
+	payload := domain.MoneyDropClaimedPayload{
+		DropID:        drop.ID,
+		Title:         drop.Title,
+		CreatorID:     drop.CreatorID,
+		ClaimantID:    claimantID,
+		Amount:        drop.AmountPerClaim,
+		TransactionID: claimTxID,
+	}
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	go func() {
+		defer cancel()
+		if err := s.eventProducer.Publish(publishCtx, "transfa.events", "money_drop.claimed", payload); err != nil {
+			log.Printf("level=warn component=service flow=money_drop_claim msg=\"money drop claimed event publish failed\" money_drop_id=%s claim_transaction_id=%s err=%v", payload.DropID, payload.TransactionID, err)
+		}
+	}()
+}
+
 // GetMoneyDropDetails retrieves details about a money drop for display.
 func (s *Service) GetMoneyDropDetails(ctx context.Context, dropID uuid.UUID) (*domain.MoneyDropDetails, error) {
 	drop, err := s.repo.FindMoneyDropByID(ctx, dropID)
@@ -2520,28 +3054,16 @@ func (s *Service) GetMoneyDropDetails(ctx context.Context, dropID uuid.UUID) (*d
 }
 
 func (s *Service) GetMoneyDropDashboard(ctx context.Context, creatorID uuid.UUID) (*domain.MoneyDropDashboardResponse, error) {
-	currentBalance := int64(0)
-	moneyDropAccount, err := s.repo.FindMoneyDropAccountByUserID(ctx, creatorID)
-	if err != nil && !errors.Is(err, store.ErrAccountNotFound) {
-		return nil, fmt.Errorf("failed to fetch money drop account: %w", err)
-	}
-	if moneyDropAccount != nil {
-		currentBalance = moneyDropAccount.Balance
-		if moneyDropAccount.AnchorAccountID != "" {
-			if syncErr := s.syncMoneyDropAccountBalance(ctx, moneyDropAccount.ID, moneyDropAccount.AnchorAccountID); syncErr == nil {
-				updatedAccount, refetchErr := s.repo.FindMoneyDropAccountByUserID(ctx, creatorID)
-				if refetchErr == nil && updatedAccount != nil {
-					currentBalance = updatedAccount.Balance
-				}
-			}
-		}
+	currentBalance, err := s.currentMoneyDropBalance(ctx, "money_drop_dashboard", creatorID)
+	if err != nil {
+		return nil, err
 	}
 
 	activeDrops, err := s.repo.ListActiveMoneyDropsByCreator(ctx, creatorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch active drops: %w", err)
 	}
-	endedDrops, err := s.repo.ListEndedMoneyDropsByCreator(ctx, creatorID, 20)
+	endedDrops, err := s.repo.ListEndedMoneyDropsByCreator(ctx, creatorID, moneyDropDashboardHistoryLimit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch drop history: %w", err)
 	}
@@ -2564,6 +3086,39 @@ func (s *Service) GetMoneyDropDashboard(ctx context.Context, creatorID uuid.UUID
 	}, nil
 }
 
+// currentMoneyDropBalance syncs the creator's money drop account from Anchor and
+// returns its balance, or 0 when the creator has no money drop account yet.
+func (s *Service) currentMoneyDropBalance(ctx context.Context, flow string, creatorID uuid.UUID) (int64, error) {
+	moneyDropAccount, err := s.repo.FindMoneyDropAccountByUserID(ctx, creatorID)
+	if err != nil {
+		if errors.Is(err, store.ErrAccountNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to fetch money drop account: %w", err)
+	}
+	if moneyDropAccount.AnchorAccountID == "" {
+		return moneyDropAccount.Balance, nil
+	}
+
+	// A slow or failing Anchor call must not hold up the caller; fall back to the
+	// balance already stored for the account.
+	syncCtx, cancel := context.WithTimeout(ctx, moneyDropBalanceSyncTimeout)
+	syncErr := s.syncMoneyDropAccountBalance(syncCtx, moneyDropAccount.ID, moneyDropAccount.AnchorAccountID)
+	cancel()
+	if syncErr != nil {
+		log.Printf("level=warn component=service flow=%s msg=\"balance sync failed; using stored balance\" user_id=%s err=%v", flow, creatorID, syncErr)
+		return moneyDropAccount.Balance, nil
+	}
+	updatedAccount, refetchErr := s.repo.FindMoneyDropAccountByUserID(ctx, creatorID)
+	if refetchErr != nil || updatedAccount == nil {
+		return moneyDropAccount.Balance, nil
+	}
+	return updatedAccount.Balance, nil
+}
+
+// GetMoneyDropOwnerDetails returns the creator's view of one of their drops with the
+// first claimersLimit claimers. A drop that has passed its expiry but is still active
+// is finalized first, as the expiry job would, so the creator never sees it as live.
 func (s *Service) GetMoneyDropOwnerDetails(
 	ctx context.Context,
 	ownerID uuid.UUID,
@@ -2575,10 +3130,30 @@ func (s *Service) GetMoneyDropOwnerDetails(
 		return nil, err
 	}
 	if claimersLimit <= 0 {
-		claimersLimit = 20
+		claimersLimit = defaultMoneyDropOwnerClaimers
+	}
+	if claimersLimit > maxMoneyDropOwnerClaimers {
+		claimersLimit = maxMoneyDropOwnerClaimers
 	}
 
-	claimers, _, err := s.repo.ListMoneyDropClaimsByDropID(ctx, dropID, "", claimersLimit, 0)
+	if drop.Status == "active" && !time.Now().UTC().Before(drop.ExpiryTimestamp) {
+		status, _, _, finalizeErr := s.finalizeMoneyDropWithRefund(ctx, dropID, ownerID, "expired")
+		if finalizeErr != nil {
+			log.Printf("level=warn component=service flow=money_drop_owner_details msg=\"expired drop finalization failed\" money_drop_id=%s creator_id=%s err=%v", dropID, ownerID, finalizeErr)
+		} else {
+			log.Printf("level=info component=service flow=money_drop_owner_details msg=\"expired drop finalized\" money_drop_id=%s creator_id=%s status=%s", dropID, ownerID, status)
+			if refreshed, refetchErr := s.repo.FindMoneyDropByIDAndCreatorID(ctx, dropID, ownerID); refetchErr == nil {
+				drop = refreshed
+			}
+		}
+	}
+
+	currentBalance, err := s.currentMoneyDropBalance(ctx, "money_drop_owner_details", ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	claimers, totalClaimers, err := s.repo.ListMoneyDropClaimsByDropID(ctx, dropID, "", claimersLimit, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch money drop claimers: %w", err)
 	}
@@ -2608,12 +3183,17 @@ func (s *Service) GetMoneyDropOwnerDetails(
 		ShareableLink:      shareableLink,
 		QRCodeContent:      qrCode,
 		Claimers:           claimers,
+		ClaimersHasMore:    len(claimers) < totalClaimers,
+		CurrentBalance:     currentBalance,
 		CanEndDrop:         canEndDrop,
 		EndedAt:            drop.EndedAt,
 		EndedReason:        drop.EndedReason,
 	}, nil
 }
 
+// RevealMoneyDropPassword decrypts the lock password of one of ownerID's drops. A drop
+// that belongs to someone else is reported as not found, and a drop created without a
+// lock returns ErrMoneyDropNotLocked.
 func (s *Service) RevealMoneyDropPassword(ctx context.Context, ownerID uuid.UUID, dropID uuid.UUID) (string, error) {
 	if len(s.moneyDropPasswordKey) != 32 {
 		return "", ErrMoneyDropPasswordEncryptionUnavailable
@@ -2622,8 +3202,11 @@ func (s *Service) RevealMoneyDropPassword(ctx context.Context, ownerID uuid.UUID
 	if err != nil {
 		return "", err
 	}
+	if drop.CreatorID != ownerID {
+		return "", store.ErrMoneyDropNotFound
+	}
 	if !drop.LockEnabled {
-		return "", errors.New("this drop is not password protected")
+		return "", ErrMoneyDropNotLocked
 	}
 	if drop.LockPasswordEncrypted == nil || strings.TrimSpace(*drop.LockPasswordEncrypted) == "" {
 		return "", errors.New("drop password is unavailable")
@@ -2664,6 +3247,9 @@ func (s *Service) GetMoneyDropClaimers(
 	}, nil
 }
 
+// EndMoneyDrop lets a creator end their active drop early. Whatever has not been
+// claimed is refunded to the creator's wallet; a drop with nothing left is finalized
+// without a transfer.
 func (s *Service) EndMoneyDrop(ctx context.Context, ownerID uuid.UUID, dropID uuid.UUID) (*domain.EndMoneyDropResponse, error) {
 	drop, err := s.repo.FindMoneyDropByIDAndCreatorID(ctx, dropID, ownerID)
 	if err != nil {
@@ -2687,11 +3273,16 @@ func (s *Service) EndMoneyDrop(ctx context.Context, ownerID uuid.UUID, dropID uu
 	}, nil
 }
 
+// GetClaimedMoneyDropHistory returns the user's most recent money drop claims, newest
+// first, whatever state the drop has reached since.
 func (s *Service) GetClaimedMoneyDropHistory(ctx context.Context, userID uuid.UUID) (*domain.ClaimedMoneyDropHistoryResponse, error) {
-	items, err := s.repo.ListClaimedMoneyDropsByUserID(ctx, userID, 50)
+	items, err := s.repo.ListClaimedMoneyDropsByUserID(ctx, userID, claimedMoneyDropHistoryLimit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch claimed money drop history: %w", err)
 	}
+	if items == nil {
+		items = []domain.ClaimedMoneyDropHistoryItem{}
+	}
 	return &domain.ClaimedMoneyDropHistoryResponse{Items: items}, nil
 }
 
@@ -3021,6 +3612,10 @@ func sanitizeMoneyDropUsernameSegment(username string) string {
 
 func buildMoneyDropDashboardItem(drop domain.MoneyDrop, now time.Time) domain.MoneyDropDashboardItem {
 	statusLabel := moneyDropStatusLabel(drop.Status, drop.ExpiryTimestamp, drop.ClaimsMadeCount, drop.TotalClaimsAllowed)
+	remainingSlots := drop.TotalClaimsAllowed - drop.ClaimsMadeCount
+	if remainingSlots < 0 {
+		remainingSlots = 0
+	}
 	return domain.MoneyDropDashboardItem{
 		ID:                 drop.ID,
 		Title:              drop.Title,
@@ -3029,6 +3624,7 @@ func buildMoneyDropDashboardItem(drop domain.MoneyDrop, now time.Time) domain.Mo
 		AmountPerPerson:    drop.AmountPerClaim,
 		NumberOfPeople:     drop.TotalClaimsAllowed,
 		ClaimsMadeCount:    drop.ClaimsMadeCount,
+		RemainingSlots:     remainingSlots,
 		TimeLeftLabel:      humanizeMoneyDropTimeLeft(drop.ExpiryTimestamp, now),
 		UsersClaimedLabel:  fmt.Sprintf("%d/%d", drop.ClaimsMadeCount, drop.TotalClaimsAllowed),
 		ExpiryTimestamp:    drop.ExpiryTimestamp,
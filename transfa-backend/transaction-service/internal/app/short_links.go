@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+const (
+	shortLinkCodeLength   = 8
+	shortLinkCodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	shortLinkCodeAttempts = 5
+)
+
+// GenerateShareableLink creates a short link to the claim URL of an active money drop
+// owned by creatorID. The link expires together with the drop.
+func (s *Service) GenerateShareableLink(ctx context.Context, dropID uuid.UUID, creatorID uuid.UUID) (*domain.ShortLink, error) {
+	drop, err := s.repo.FindMoneyDropByIDAndCreatorID(ctx, dropID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	if drop.Status != "active" || !time.Now().Before(drop.ExpiryTimestamp) {
+		return nil, ErrMoneyDropShareNotAllowed
+	}
+
+	targetURL, _ := s.buildMoneyDropLinks(ctx, drop.ID.String(), creatorID)
+	for attempt := 0; attempt < shortLinkCodeAttempts; attempt++ {
+		code, err := generateShortLinkCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate short link code: %w", err)
+		}
+
+		link, err := s.repo.CreateShortLink(ctx, domain.ShortLink{
+			Code:        code,
+			MoneyDropID: drop.ID,
+			CreatedBy:   creatorID,
+			TargetURL:   targetURL,
+			ExpiresAt:   drop.ExpiryTimestamp,
+		})
+		if errors.Is(err, store.ErrShortLinkCodeExists) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create short link: %w", err)
+		}
+
+		link.ShortURL = fmt.Sprintf("%s/s/%s", s.moneyDropShareBaseURL, link.Code)
+		return link, nil
+	}
+
+	return nil, errors.New("failed to allocate a unique short link code")
+}
+
+// ResolveShortLink returns the target URL for a short link code and counts the visit.
+// Unknown codes return store.ErrShortLinkNotFound and expired ones ErrShortLinkExpired.
+func (s *Service) ResolveShortLink(ctx context.Context, code string) (string, error) {
+	if !isShortLinkCode(code) {
+		return "", store.ErrShortLinkNotFound
+	}
+
+	link, err := s.repo.FindShortLinkByCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	if !time.Now().Before(link.ExpiresAt) {
+		return "", ErrShortLinkExpired
+	}
+
+	if err := s.repo.IncrementShortLinkClickCount(ctx, code); err != nil {
+		log.Printf("level=warn component=service flow=short_link msg=\"failed to record click\" code=%s err=%v", code, err)
+	}
+	return link.TargetURL, nil
+}
+
+func generateShortLinkCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(shortLinkCodeAlphabet)))
+	code := make([]byte, shortLinkCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shortLinkCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func isShortLinkCode(code string) bool {
+	if len(code) != shortLinkCodeLength {
+		return false
+	}
+	for _, c := range code {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
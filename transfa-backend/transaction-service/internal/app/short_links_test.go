@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// shareLinkRepoStub serves one money drop and rejects the first taken codes like the
+// unique constraint on short_links.code.
+type shareLinkRepoStub struct {
+	store.Repository
+
+	drop       domain.MoneyDrop
+	collisions int
+	created    []domain.ShortLink
+}
+
+func (s *shareLinkRepoStub) FindMoneyDropByIDAndCreatorID(ctx context.Context, dropID, creatorID uuid.UUID) (*domain.MoneyDrop, error) {
+	if dropID != s.drop.ID || creatorID != s.drop.CreatorID {
+		return nil, store.ErrMoneyDropNotFound
+	}
+	drop := s.drop
+	return &drop, nil
+}
+
+func (s *shareLinkRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: userID, Username: "ada"}, nil
+}
+
+func (s *shareLinkRepoStub) CreateShortLink(ctx context.Context, link domain.ShortLink) (*domain.ShortLink, error) {
+	if s.collisions > 0 {
+		s.collisions--
+		return nil, store.ErrShortLinkCodeExists
+	}
+	link.ID = uuid.New()
+	s.created = append(s.created, link)
+	return &link, nil
+}
+
+func newShareLinkTestService(status string, expiry time.Time) (*Service, *shareLinkRepoStub) {
+	repo := &shareLinkRepoStub{drop: domain.MoneyDrop{ID: uuid.New(), CreatorID: uuid.New(), Status: status, ExpiryTimestamp: expiry}}
+	return &Service{repo: repo, moneyDropShareBaseURL: "https://trytransfa.com"}, repo
+}
+
+func TestGenerateShareableLink_RetriesTakenCodes(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	svc, repo := newShareLinkTestService("active", expiry)
+	repo.collisions = 2
+
+	link, err := svc.GenerateShareableLink(context.Background(), repo.drop.ID, repo.drop.CreatorID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 short link, got %d", len(repo.created))
+	}
+	if !isShortLinkCode(link.Code) {
+		t.Fatalf("expected an 8-character alphanumeric code, got %q", link.Code)
+	}
+	if link.ShortURL != "https://trytransfa.com/s/"+link.Code {
+		t.Fatalf("unexpected short URL %q", link.ShortURL)
+	}
+	if link.TargetURL != "https://trytransfa.com/_ada_?drop_id="+repo.drop.ID.String() {
+		t.Fatalf("unexpected target URL %q", link.TargetURL)
+	}
+	if !link.ExpiresAt.Equal(expiry) {
+		t.Fatalf("expected link to expire with the drop at %s, got %s", expiry, link.ExpiresAt)
+	}
+}
+
+func TestGenerateShareableLink_RejectsEndedDrop(t *testing.T) {
+	svc, repo := newShareLinkTestService("completed", time.Now().Add(time.Hour))
+
+	_, err := svc.GenerateShareableLink(context.Background(), repo.drop.ID, repo.drop.CreatorID)
+	if !errors.Is(err, ErrMoneyDropShareNotAllowed) {
+		t.Fatalf("expected ErrMoneyDropShareNotAllowed, got %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected no short link, got %d", len(repo.created))
+	}
+}
+
+func TestGenerateShareableLink_RejectsOtherUsersDrop(t *testing.T) {
+	svc, repo := newShareLinkTestService("active", time.Now().Add(time.Hour))
+
+	_, err := svc.GenerateShareableLink(context.Background(), repo.drop.ID, uuid.New())
+	if !errors.Is(err, store.ErrMoneyDropNotFound) {
+		t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+	}
+}
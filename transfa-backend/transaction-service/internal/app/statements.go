@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/pdf"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const maxStatementPeriodDays = 366
+
+// statementLocation is West Africa Time; statement days run midnight to midnight WAT.
+var statementLocation = time.FixedZone("WAT", 60*60)
+
+// GenerateAccountStatement builds the caller's primary wallet statement for the
+// inclusive period in the request. Only completed transactions are listed. The
+// opening balance is worked back from the current wallet balance by reversing every
+// completed transaction since the start of the period, archived ones included.
+func (s *Service) GenerateAccountStatement(ctx context.Context, userID uuid.UUID, req domain.AccountStatementRequest) (*domain.AccountStatement, error) {
+	from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(req.From), statementLocation)
+	if err != nil {
+		return nil, ErrInvalidStatementPeriod
+	}
+	to, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(req.To), statementLocation)
+	if err != nil || to.Before(from) {
+		return nil, ErrInvalidStatementPeriod
+	}
+	periodEnd := to.AddDate(0, 0, 1)
+	if periodEnd.Sub(from) > maxStatementPeriodDays*24*time.Hour {
+		return nil, ErrStatementPeriodTooLong
+	}
+	if req.Password != "" && (len(req.Password) > pdf.MaxPasswordLength || strings.TrimFunc(req.Password, isPrintableASCII) != "") {
+		return nil, ErrInvalidStatementPassword
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.repo.FindAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.transactionHistory(ctx, userID, domain.TransactionHistoryFilter{From: &from})
+	if err != nil {
+		return nil, err
+	}
+
+	statement := buildAccountStatement(account, history, from, periodEnd)
+	statement.AccountName = user.Username
+	if fullName := optionalTrimmedString(user.FullName); fullName != nil {
+		statement.AccountName = *fullName
+	}
+	statement.PeriodStart = from
+	statement.PeriodEnd = to
+	return statement, nil
+}
+
+// buildAccountStatement lists the completed transactions that moved money in or out
+// of account within [from, periodEnd) and derives the balances around them.
+func buildAccountStatement(account *domain.Account, history []domain.Transaction, from, periodEnd time.Time) *domain.AccountStatement {
+	statement := &domain.AccountStatement{AccountNumber: account.AccountNumber, Entries: []domain.AccountStatementEntry{}}
+
+	var inPeriod []domain.Transaction
+	netSinceStart := int64(0)
+	for _, tx := range history {
+		if tx.Status != "completed" || tx.CreatedAt.Before(from) {
+			continue
+		}
+		debit, credit := statementAmounts(account.ID, tx)
+		if debit == 0 && credit == 0 {
+			continue
+		}
+		netSinceStart += credit - debit
+		statement.IncludesArchived = statement.IncludesArchived || tx.Archived
+		if tx.CreatedAt.Before(periodEnd) {
+			inPeriod = append(inPeriod, tx)
+		}
+	}
+	sort.SliceStable(inPeriod, func(i, j int) bool {
+		return inPeriod[i].CreatedAt.Before(inPeriod[j].CreatedAt)
+	})
+
+	statement.OpeningBalance = account.Balance - netSinceStart
+	balance := statement.OpeningBalance
+	for _, tx := range inPeriod {
+		debit, credit := statementAmounts(account.ID, tx)
+		balance += credit - debit
+		statement.TotalDebits += debit
+		statement.TotalCredits += credit
+		statement.Entries = append(statement.Entries, domain.AccountStatementEntry{
+			TransactionID: tx.ID,
+			Date:          tx.CreatedAt.In(statementLocation),
+			Description:   statementDescription(tx),
+			Debit:         debit,
+			Credit:        credit,
+			Balance:       balance,
+		})
+	}
+	statement.ClosingBalance = balance
+	return statement
+}
+
+// statementAmounts returns how much tx took out of and put into accountID. The
+// sending side pays the fee as well as the amount.
+func statementAmounts(accountID uuid.UUID, tx domain.Transaction) (debit, credit int64) {
+	if tx.SourceAccountID == accountID {
+		debit = tx.Amount + tx.Fee
+	}
+	if tx.DestinationAccountID != nil && *tx.DestinationAccountID == accountID {
+		credit = tx.Amount
+	}
+	return debit, credit
+}
+
+func statementDescription(tx domain.Transaction) string {
+	if description := strings.TrimSpace(tx.Description); description != "" {
+		return description
+	}
+	if category := strings.TrimSpace(tx.Category); category != "" {
+		return category
+	}
+	return strings.ReplaceAll(tx.Type, "_", " ")
+}
+
+func isPrintableASCII(r rune) bool {
+	return r >= 0x20 && r <= 0x7e
+}
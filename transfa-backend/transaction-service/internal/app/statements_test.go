@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type statementRepoStub struct {
+	store.Repository
+
+	user     *domain.User
+	account  *domain.Account
+	history  []domain.Transaction
+	accounts int
+
+	latestArchived *time.Time
+	filter         domain.TransactionHistoryFilter
+}
+
+func (s *statementRepoStub) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return s.user, nil
+}
+
+func (s *statementRepoStub) FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
+	s.accounts++
+	return s.account, nil
+}
+
+func (s *statementRepoStub) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error) {
+	s.filter = filter
+	return s.history, nil
+}
+
+func (s *statementRepoStub) LatestArchivedTransactionAt(ctx context.Context) (*time.Time, error) {
+	return s.latestArchived, nil
+}
+
+func watTime(day, hour int) time.Time {
+	return time.Date(2026, time.March, day, hour, 0, 0, 0, statementLocation)
+}
+
+func TestGenerateAccountStatement_ComputesBalancesAndTotals(t *testing.T) {
+	fullName := "Ada Obi"
+	accountID := uuid.New()
+	otherAccount := uuid.New()
+	userID := uuid.New()
+
+	credit := func(id uuid.UUID, at time.Time, amount int64) domain.Transaction {
+		return domain.Transaction{ID: id, SourceAccountID: otherAccount, DestinationAccountID: &accountID, Status: "completed", Amount: amount, Type: "p2p", CreatedAt: at}
+	}
+	debit := func(id uuid.UUID, at time.Time, amount, fee int64) domain.Transaction {
+		return domain.Transaction{ID: id, SourceAccountID: accountID, DestinationAccountID: &otherAccount, Status: "completed", Amount: amount, Fee: fee, Type: "self_transfer", Category: "Withdrawal", CreatedAt: at}
+	}
+	salary, rent, lunch, later := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	failed := debit(uuid.New(), watTime(6, 9), 999999, 0)
+	failed.Status = "failed"
+
+	// History is newest first, as returned by the repository.
+	repo := &statementRepoStub{
+		user:    &domain.User{ID: userID, Username: "ada", FullName: &fullName},
+		account: &domain.Account{ID: accountID, UserID: userID, AccountNumber: "0123456789", Balance: 500000},
+		history: []domain.Transaction{
+			credit(later, watTime(20, 10), 50000),                                           // after the period
+			debit(lunch, time.Date(2026, time.March, 10, 22, 30, 0, 0, time.UTC), 20000, 0), // 23:30 WAT on the 10th
+			failed,
+			debit(rent, watTime(5, 12), 300000, 1000),
+			credit(salary, watTime(1, 0), 400000),
+			credit(uuid.New(), watTime(28, 23).AddDate(0, -1, 0), 100000), // before the period
+		},
+	}
+	svc := &Service{repo: repo}
+
+	statement, err := svc.GenerateAccountStatement(context.Background(), userID, domain.AccountStatementRequest{From: "2026-03-01", To: "2026-03-10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Current balance 500,000 less everything since 1 March: +50,000 +400,000 -301,000 -20,000.
+	if statement.OpeningBalance != 371000 {
+		t.Fatalf("expected opening balance 371000, got %d", statement.OpeningBalance)
+	}
+	if statement.TotalCredits != 400000 || statement.TotalDebits != 321000 {
+		t.Fatalf("expected credits 400000 and debits 321000, got %d and %d", statement.TotalCredits, statement.TotalDebits)
+	}
+	if statement.ClosingBalance != 450000 {
+		t.Fatalf("expected closing balance 450000, got %d", statement.ClosingBalance)
+	}
+	if statement.ClosingBalance != statement.OpeningBalance+statement.TotalCredits-statement.TotalDebits {
+		t.Fatal("expected closing balance to equal opening balance plus credits less debits")
+	}
+
+	wantIDs := []uuid.UUID{salary, rent, lunch}
+	if len(statement.Entries) != len(wantIDs) {
+		t.Fatalf("expected %d entries, got %d", len(wantIDs), len(statement.Entries))
+	}
+	for i, id := range wantIDs {
+		if statement.Entries[i].TransactionID != id {
+			t.Fatalf("entry %d: expected transaction %s, got %s", i, id, statement.Entries[i].TransactionID)
+		}
+	}
+	if last := statement.Entries[2]; last.Balance != statement.ClosingBalance || last.Debit != 20000 {
+		t.Fatalf("expected last entry to carry the closing balance, got %+v", last)
+	}
+	if statement.Entries[1].Description != "Withdrawal" || statement.Entries[1].Debit != 301000 {
+		t.Fatalf("expected rent debit to include the fee, got %+v", statement.Entries[1])
+	}
+	if statement.AccountName != "Ada Obi" || statement.AccountNumber != "0123456789" {
+		t.Fatalf("unexpected account details %q %q", statement.AccountName, statement.AccountNumber)
+	}
+}
+
+func TestGenerateAccountStatement_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		req  domain.AccountStatementRequest
+		want error
+	}{
+		{name: "bad date", req: domain.AccountStatementRequest{From: "01/03/2026", To: "2026-03-10"}, want: ErrInvalidStatementPeriod},
+		{name: "reversed period", req: domain.AccountStatementRequest{From: "2026-03-10", To: "2026-03-01"}, want: ErrInvalidStatementPeriod},
+		{name: "too long", req: domain.AccountStatementRequest{From: "2025-01-01", To: "2026-03-01"}, want: ErrStatementPeriodTooLong},
+		{name: "long password", req: domain.AccountStatementRequest{From: "2026-03-01", To: "2026-03-10", Password: strings.Repeat("x", 33)}, want: ErrInvalidStatementPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &statementRepoStub{}
+			svc := &Service{repo: repo}
+			if _, err := svc.GenerateAccountStatement(context.Background(), uuid.New(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if repo.accounts != 0 {
+				t.Fatal("expected validation to fail before loading the account")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// cancellableTransactionTypes are the sender-initiated transfers that can be undone
+// before they reach Anchor.
+var cancellableTransactionTypes = []string{"p2p", "self_transfer"}
+
+// CancelTransaction cancels one of the sender's transfers that has not been handed to
+// Anchor yet and refunds the debit and fee to their wallet. A transfer that has
+// progressed cannot be cancelled and store.ErrTransactionNotCancellable is returned;
+// the initiation path claims the transaction before its fee or transfer reaches
+// Anchor, so a cancellation never refunds money Anchor has already moved.
+func (s *Service) CancelTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*domain.Transaction, error) {
+	tx, err := s.repo.FindTransactionByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.SenderID != userID {
+		return nil, store.ErrTransactionNotFound
+	}
+	if tx.Archived || !slices.Contains(cancellableTransactionTypes, tx.Type) {
+		return nil, store.ErrTransactionNotCancellable
+	}
+
+	if err := s.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		refund, err := txRepo.CancelPendingTransaction(ctx, tx.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.CreditWallet(ctx, userID, refund); err != nil {
+			return fmt.Errorf("refund wallet: %w", err)
+		}
+		// A payment request this transfer was paying becomes payable again.
+		return txRepo.ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx, tx.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	tx.Status = "cancelled"
+	log.Printf("level=info component=service flow=transaction_cancel msg=\"transfer cancelled\" transaction_id=%s sender_id=%s", tx.ID, userID)
+	return tx, nil
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+type cancelTransactionRepoStub struct {
+	store.Repository
+
+	tx        *domain.Transaction
+	cancelErr error
+
+	credited int64
+	released []uuid.UUID
+}
+
+func (s *cancelTransactionRepoStub) FindTransactionByID(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error) {
+	if s.tx == nil || s.tx.ID != transactionID {
+		return nil, store.ErrTransactionNotFound
+	}
+	tx := *s.tx
+	return &tx, nil
+}
+
+func (s *cancelTransactionRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	return fn(s)
+}
+
+func (s *cancelTransactionRepoStub) CancelPendingTransaction(ctx context.Context, transactionID uuid.UUID, senderID uuid.UUID) (int64, error) {
+	if s.cancelErr != nil {
+		return 0, s.cancelErr
+	}
+	return s.tx.Amount + s.tx.Fee, nil
+}
+
+func (s *cancelTransactionRepoStub) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	s.credited += amount
+	return nil
+}
+
+func (s *cancelTransactionRepoStub) ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error {
+	s.released = append(s.released, settledTransactionID)
+	return nil
+}
+
+func newCancelTransactionRepoStub(txType string) *cancelTransactionRepoStub {
+	return &cancelTransactionRepoStub{tx: &domain.Transaction{
+		ID:       uuid.New(),
+		SenderID: uuid.New(),
+		Type:     txType,
+		Status:   "pending",
+		Amount:   5000,
+		Fee:      100,
+	}}
+}
+
+func TestCancelTransaction_RefundsAmountAndFee(t *testing.T) {
+	repo := newCancelTransactionRepoStub("p2p")
+	svc := &Service{repo: repo}
+
+	tx, err := svc.CancelTransaction(context.Background(), repo.tx.SenderID, repo.tx.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.Status != "cancelled" {
+		t.Fatalf("expected cancelled status, got %q", tx.Status)
+	}
+	if repo.credited != 5100 {
+		t.Fatalf("expected 5100 refunded, got %d", repo.credited)
+	}
+	if len(repo.released) != 1 || repo.released[0] != repo.tx.ID {
+		t.Fatalf("expected a payment request paid by the transfer to be released, got %v", repo.released)
+	}
+}
+
+func TestCancelTransaction_Rejections(t *testing.T) {
+	tests := []struct {
+		name      string
+		txType    string
+		cancelErr error
+		otherUser bool
+		wantErr   error
+	}{
+		{name: "not the sender", txType: "p2p", otherUser: true, wantErr: store.ErrTransactionNotFound},
+		{name: "already progressed", txType: "self_transfer", cancelErr: store.ErrTransactionNotCancellable, wantErr: store.ErrTransactionNotCancellable},
+		{name: "not a sender transfer", txType: "money_drop_claim", wantErr: store.ErrTransactionNotCancellable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newCancelTransactionRepoStub(tt.txType)
+			repo.cancelErr = tt.cancelErr
+			svc := &Service{repo: repo}
+
+			userID := repo.tx.SenderID
+			if tt.otherUser {
+				userID = uuid.New()
+			}
+			if _, err := svc.CancelTransaction(context.Background(), userID, repo.tx.ID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.credited != 0 {
+				t.Fatalf("expected no refund, got %d", repo.credited)
+			}
+		})
+	}
+}
+
+func TestProcessP2PTransfer_CancelledBeforeAnchorCallIsNotSent(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	// The sender's cancellation commits between the debit and the Anchor call.
+	repo.beforeClaim = func() { repo.cancelled = true }
+
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	if !errors.Is(err, ErrTransactionCancelled) {
+		t.Fatalf("expected ErrTransactionCancelled, got %v", err)
+	}
+	if got := repo.anchorTransfers.Load(); got != 0 {
+		t.Fatalf("expected no Anchor transfer for a cancelled transaction, got %d", got)
+	}
+	if repo.credits != 0 || len(repo.statusUpdates) != 0 {
+		t.Fatalf("expected the cancellation's refund to be the only one, got %d credits and status updates %v", repo.credits, repo.statusUpdates)
+	}
+	if got := len(publisher.find("transfer.initiated.p2p")); got != 0 {
+		t.Fatalf("expected no transfer.initiated.p2p event, got %d", got)
+	}
+}
+
+func TestProcessP2PTransfer_ClaimsTransactionBeforeAnchorCall(t *testing.T) {
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, &recordingPublisher{})
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	claimed := false
+	repo.beforeClaim = func() {
+		claimed = true
+		if got := repo.anchorTransfers.Load(); got != 0 {
+			t.Errorf("expected the claim before the Anchor call, got %d transfers first", got)
+		}
+	}
+
+	if _, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	}); err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+	if !claimed || repo.anchorTransfers.Load() != 1 {
+		t.Fatalf("expected one claimed Anchor transfer, got claimed=%v transfers=%d", claimed, repo.anchorTransfers.Load())
+	}
+}
+
+func TestProcessP2PTransfer_CancelAfterFeeCollectionIsRejected(t *testing.T) {
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, &recordingPublisher{})
+	svc.adminAccountID = "anc_admin"
+	svc.transactionFeeKobo = 100
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	// The sender cancels while Anchor is moving the fee to the admin account.
+	var cancelErr error
+	repo.onAnchorTransfer = func(n int32) {
+		if n == 1 {
+			_, cancelErr = svc.CancelTransaction(context.Background(), repo.sender.ID, repo.createdTx.ID)
+		}
+	}
+
+	if _, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	}); err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+	if !errors.Is(cancelErr, store.ErrTransactionNotCancellable) {
+		t.Fatalf("expected the cancel to be rejected once the fee was sent, got %v", cancelErr)
+	}
+	if len(repo.feeCollections) != 1 || repo.anchorTransfers.Load() != 2 {
+		t.Fatalf("expected the fee and the transfer on Anchor, got %d fee collections and %d transfers", len(repo.feeCollections), repo.anchorTransfers.Load())
+	}
+	if repo.credits != 0 {
+		t.Fatalf("expected no refund, got %d credits", repo.credits)
+	}
+}
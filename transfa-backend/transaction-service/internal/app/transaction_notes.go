@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// UpdateTransactionNote sets the caller's private note on a transaction they sent or
+// received. An empty note removes it. The other party never sees it.
+func (s *Service) UpdateTransactionNote(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, payload domain.UpdateTransactionNotePayload) (*domain.TransactionNote, error) {
+	note := strings.TrimSpace(payload.Note)
+	if utf8.RuneCountInString(note) > maxTransactionNoteLen {
+		return nil, ErrInvalidTransactionNote
+	}
+
+	if _, err := s.GetTransactionByID(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	if note == "" {
+		if err := s.repo.DeleteTransactionNote(ctx, transactionID, userID); err != nil {
+			return nil, err
+		}
+		return &domain.TransactionNote{TransactionID: transactionID}, nil
+	}
+	return s.repo.UpsertTransactionNote(ctx, transactionID, userID, note)
+}
+
+// AddTransactionAttachment records an image the app has already uploaded to storage
+// against a transaction the caller sent or received. Each party may attach up to
+// maxTransactionAttachmentsPerUser images.
+func (s *Service) AddTransactionAttachment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, payload domain.AddTransactionAttachmentPayload) (*domain.TransactionAttachment, error) {
+	rawURL := strings.TrimSpace(payload.URL)
+	if !s.isAllowedAttachmentURL(rawURL) {
+		return nil, ErrInvalidTransactionAttachmentURL
+	}
+
+	if _, err := s.GetTransactionByID(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.AddTransactionAttachment(ctx, domain.TransactionAttachment{
+		TransactionID: transactionID,
+		UserID:        userID,
+		URL:           rawURL,
+	}, maxTransactionAttachmentsPerUser)
+}
+
+// isAllowedAttachmentURL reports whether rawURL is an https URL on one of the
+// configured storage hosts.
+func (s *Service) isAllowedAttachmentURL(rawURL string) bool {
+	if rawURL == "" || len(rawURL) > 2048 {
+		return false
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.User != nil || parsed.Path == "" {
+		return false
+	}
+	return s.attachmentHosts[strings.ToLower(parsed.Hostname())]
+}
+
+// annotateTransactions fills in the user's own notes and attachments. It is best
+// effort: on a lookup failure the transactions are served without them.
+func (s *Service) annotateTransactions(ctx context.Context, userID uuid.UUID, transactions []domain.Transaction) {
+	if len(transactions) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, len(transactions))
+	for i := range transactions {
+		ids[i] = transactions[i].ID
+	}
+
+	notes, err := s.repo.FindTransactionNotes(ctx, userID, ids)
+	if err != nil {
+		log.Printf("level=warn component=service flow=transaction_notes msg=\"note lookup failed\" user_id=%s err=%v", userID, err)
+		return
+	}
+	attachments, err := s.repo.FindTransactionAttachments(ctx, userID, ids)
+	if err != nil {
+		log.Printf("level=warn component=service flow=transaction_notes msg=\"attachment lookup failed\" user_id=%s err=%v", userID, err)
+		return
+	}
+
+	for i := range transactions {
+		if note, ok := notes[transactions[i].ID]; ok {
+			transactions[i].Note = &note
+		}
+		transactions[i].Attachments = attachments[transactions[i].ID]
+	}
+}
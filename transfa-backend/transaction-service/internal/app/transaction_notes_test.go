@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// transactionNotesRepoStub keeps notes and attachments in memory per transaction and
+// user, on top of the single transaction served by disputesRepoStub.
+type transactionNotesRepoStub struct {
+	*disputesRepoStub
+
+	notes       map[uuid.UUID]map[uuid.UUID]string
+	attachments []domain.TransactionAttachment
+}
+
+func (s *transactionNotesRepoStub) UpsertTransactionNote(ctx context.Context, transactionID, userID uuid.UUID, note string) (*domain.TransactionNote, error) {
+	if s.notes[userID] == nil {
+		s.notes[userID] = map[uuid.UUID]string{}
+	}
+	s.notes[userID][transactionID] = note
+	return &domain.TransactionNote{TransactionID: transactionID, Note: &note, UpdatedAt: time.Now()}, nil
+}
+
+func (s *transactionNotesRepoStub) DeleteTransactionNote(ctx context.Context, transactionID, userID uuid.UUID) error {
+	delete(s.notes[userID], transactionID)
+	return nil
+}
+
+func (s *transactionNotesRepoStub) AddTransactionAttachment(ctx context.Context, attachment domain.TransactionAttachment, limit int) (*domain.TransactionAttachment, error) {
+	count := 0
+	for _, existing := range s.attachments {
+		if existing.TransactionID == attachment.TransactionID && existing.UserID == attachment.UserID {
+			count++
+		}
+	}
+	if count >= limit {
+		return nil, store.ErrTransactionAttachmentLimit
+	}
+	attachment.ID = uuid.New()
+	attachment.CreatedAt = time.Now()
+	s.attachments = append(s.attachments, attachment)
+	return &attachment, nil
+}
+
+func (s *transactionNotesRepoStub) FindTransactionNotes(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	notes := map[uuid.UUID]string{}
+	for _, id := range transactionIDs {
+		if note, ok := s.notes[userID][id]; ok {
+			notes[id] = note
+		}
+	}
+	return notes, nil
+}
+
+func (s *transactionNotesRepoStub) FindTransactionAttachments(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID][]domain.TransactionAttachment, error) {
+	attachments := map[uuid.UUID][]domain.TransactionAttachment{}
+	for _, attachment := range s.attachments {
+		if attachment.UserID == userID {
+			attachments[attachment.TransactionID] = append(attachments[attachment.TransactionID], attachment)
+		}
+	}
+	return attachments, nil
+}
+
+// newTransactionNotesTestService serves a completed transaction from a sender to a
+// recipient, with attachments allowed on the Transfa storage host.
+func newTransactionNotesTestService() (*Service, *transactionNotesRepoStub, uuid.UUID, uuid.UUID) {
+	svc, disputes, _, senderID := newDisputesTestService("completed")
+	recipientID := uuid.New()
+	disputes.transaction.RecipientID = &recipientID
+
+	repo := &transactionNotesRepoStub{disputesRepoStub: disputes, notes: map[uuid.UUID]map[uuid.UUID]string{}}
+	svc.repo = repo
+	svc.ConfigureTransactionAttachmentHosts([]string{" Storage.TryTransfa.com ", ""})
+	return svc, repo, senderID, recipientID
+}
+
+func TestUpdateTransactionNote_IsPrivateToEachParty(t *testing.T) {
+	svc, repo, senderID, recipientID := newTransactionNotesTestService()
+	ctx := context.Background()
+	transactionID := repo.transaction.ID
+
+	if _, err := svc.UpdateTransactionNote(ctx, senderID, transactionID, domain.UpdateTransactionNotePayload{Note: "  Rent for March  "}); err != nil {
+		t.Fatalf("UpdateTransactionNote: %v", err)
+	}
+	if _, err := svc.AddTransactionAttachment(ctx, senderID, transactionID, domain.AddTransactionAttachmentPayload{URL: "https://storage.trytransfa.com/receipts/rent.jpg"}); err != nil {
+		t.Fatalf("AddTransactionAttachment: %v", err)
+	}
+
+	tx, err := svc.GetTransactionDetail(ctx, senderID, transactionID)
+	if err != nil {
+		t.Fatalf("GetTransactionDetail: %v", err)
+	}
+	if tx.Note == nil || *tx.Note != "Rent for March" {
+		t.Fatalf("expected the sender's trimmed note, got %v", tx.Note)
+	}
+	if len(tx.Attachments) != 1 {
+		t.Fatalf("expected the sender's attachment, got %d", len(tx.Attachments))
+	}
+
+	tx, err = svc.GetTransactionDetail(ctx, recipientID, transactionID)
+	if err != nil {
+		t.Fatalf("GetTransactionDetail: %v", err)
+	}
+	if tx.Note != nil || len(tx.Attachments) != 0 {
+		t.Fatalf("expected the recipient not to see the sender's note or attachments, got note=%v attachments=%d", tx.Note, len(tx.Attachments))
+	}
+
+	if _, err := svc.UpdateTransactionNote(ctx, senderID, transactionID, domain.UpdateTransactionNotePayload{Note: " "}); err != nil {
+		t.Fatalf("UpdateTransactionNote: %v", err)
+	}
+	if _, ok := repo.notes[senderID][transactionID]; ok {
+		t.Fatalf("expected an empty note to remove the sender's note")
+	}
+}
+
+func TestUpdateTransactionNote_Validates(t *testing.T) {
+	svc, repo, senderID, _ := newTransactionNotesTestService()
+	ctx := context.Background()
+
+	_, err := svc.UpdateTransactionNote(ctx, senderID, repo.transaction.ID, domain.UpdateTransactionNotePayload{Note: strings.Repeat("é", maxTransactionNoteLen+1)})
+	if !errors.Is(err, ErrInvalidTransactionNote) {
+		t.Fatalf("expected ErrInvalidTransactionNote, got %v", err)
+	}
+	if _, err := svc.UpdateTransactionNote(ctx, senderID, repo.transaction.ID, domain.UpdateTransactionNotePayload{Note: strings.Repeat("é", maxTransactionNoteLen)}); err != nil {
+		t.Fatalf("expected a note of exactly %d characters to be accepted, got %v", maxTransactionNoteLen, err)
+	}
+
+	_, err = svc.UpdateTransactionNote(ctx, uuid.New(), repo.transaction.ID, domain.UpdateTransactionNotePayload{Note: "mine"})
+	if !errors.Is(err, store.ErrTransactionNotFound) {
+		t.Fatalf("expected ErrTransactionNotFound for a user outside the transaction, got %v", err)
+	}
+}
+
+func TestAddTransactionAttachment_RejectsURLsOutsideStorage(t *testing.T) {
+	svc, repo, senderID, _ := newTransactionNotesTestService()
+
+	for _, rawURL := range []string{
+		"",
+		"http://storage.trytransfa.com/receipts/a.jpg",
+		"https://evil.example.com/receipts/a.jpg",
+		"https://storage.trytransfa.com.evil.example.com/a.jpg",
+		"https://user@storage.trytransfa.com/a.jpg",
+		"https://storage.trytransfa.com",
+	} {
+		_, err := svc.AddTransactionAttachment(context.Background(), senderID, repo.transaction.ID, domain.AddTransactionAttachmentPayload{URL: rawURL})
+		if !errors.Is(err, ErrInvalidTransactionAttachmentURL) {
+			t.Fatalf("%q: expected ErrInvalidTransactionAttachmentURL, got %v", rawURL, err)
+		}
+	}
+	if len(repo.attachments) != 0 {
+		t.Fatalf("expected no attachments to be recorded, got %d", len(repo.attachments))
+	}
+}
+
+func TestAddTransactionAttachment_LimitsEachParty(t *testing.T) {
+	svc, repo, senderID, recipientID := newTransactionNotesTestService()
+	ctx := context.Background()
+	payload := domain.AddTransactionAttachmentPayload{URL: "https://storage.trytransfa.com/receipts/a.jpg"}
+
+	for i := 0; i < maxTransactionAttachmentsPerUser; i++ {
+		if _, err := svc.AddTransactionAttachment(ctx, senderID, repo.transaction.ID, payload); err != nil {
+			t.Fatalf("attachment %d: %v", i, err)
+		}
+	}
+	if _, err := svc.AddTransactionAttachment(ctx, senderID, repo.transaction.ID, payload); !errors.Is(err, store.ErrTransactionAttachmentLimit) {
+		t.Fatalf("expected ErrTransactionAttachmentLimit, got %v", err)
+	}
+	if _, err := svc.AddTransactionAttachment(ctx, recipientID, repo.transaction.ID, payload); err != nil {
+		t.Fatalf("expected the recipient to have their own allowance, got %v", err)
+	}
+}
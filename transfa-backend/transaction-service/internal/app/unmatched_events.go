@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+var (
+	ErrUnmatchedTransferEventAlreadyReplayed = errors.New("unmatched transfer event has already been replayed")
+	ErrUnmatchedTransferEventStillUnmatched  = errors.New("no transaction matches the transfer event yet")
+)
+
+// ListUnmatchedTransferEvents returns parked transfer events for the internal query
+// endpoint, newest first. An empty status lists pending and replayed events.
+func (s *Service) ListUnmatchedTransferEvents(ctx context.Context, status string, limit int) ([]domain.UnmatchedTransferEvent, error) {
+	if limit <= 0 {
+		limit = defaultUnmatchedEventsLimit
+	}
+	if limit > maxUnmatchedEventsLimit {
+		limit = maxUnmatchedEventsLimit
+	}
+	return s.repo.ListUnmatchedTransferEvents(ctx, strings.TrimSpace(status), limit)
+}
+
+// UnmatchedTransferEventCount returns how many events this instance has parked since start.
+func (s *Service) UnmatchedTransferEventCount() int64 {
+	return s.transferConsumer.UnmatchedEvents()
+}
+
+// ReplayUnmatchedTransferEvent re-runs matching for a parked transfer event, e.g. after
+// a fix to the matching logic, and applies it to the transaction it now matches.
+func (s *Service) ReplayUnmatchedTransferEvent(ctx context.Context, id uuid.UUID) (*domain.UnmatchedTransferEvent, error) {
+	parked, err := s.repo.FindUnmatchedTransferEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if parked.Status == domain.UnmatchedTransferEventReplayed {
+		return nil, ErrUnmatchedTransferEventAlreadyReplayed
+	}
+
+	var event domain.TransferStatusEvent
+	if err := json.Unmarshal(parked.Payload, &event); err != nil {
+		return nil, fmt.Errorf("decode unmatched transfer event %s: %w", id, err)
+	}
+
+	tx, err := s.transferConsumer.replay(ctx, event)
+	if err != nil {
+		if recordErr := s.repo.RecordUnmatchedTransferEventReplayFailure(ctx, id, err.Error()); recordErr != nil {
+			log.Printf("level=warn component=service msg=\"failed to record unmatched event replay failure\" unmatched_event_id=%s err=%v", id, recordErr)
+		}
+		if errors.Is(err, store.ErrTransactionNotFound) {
+			return nil, ErrUnmatchedTransferEventStillUnmatched
+		}
+		return nil, err
+	}
+
+	if err := s.repo.MarkUnmatchedTransferEventReplayed(ctx, id, tx.ID); err != nil {
+		return nil, err
+	}
+	log.Printf("level=info component=service msg=\"replayed unmatched transfer event\" unmatched_event_id=%s anchor_transfer_id=%s transaction_id=%s", id, parked.AnchorTransferID, tx.ID)
+	return s.repo.FindUnmatchedTransferEventByID(ctx, id)
+}
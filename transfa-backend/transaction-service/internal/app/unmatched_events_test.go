@@ -0,0 +1,222 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/pkg/anchorclient"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// unmatchedEventsRepoStub matches transactions only by ID, so events resolve through
+// the reason token or not at all.
+type unmatchedEventsRepoStub struct {
+	store.Repository
+
+	transactions map[uuid.UUID]*domain.Transaction
+	parked       map[uuid.UUID]*domain.UnmatchedTransferEvent
+
+	metadataUpdates int
+	replayFailures  []string
+}
+
+func newUnmatchedEventsRepoStub() *unmatchedEventsRepoStub {
+	return &unmatchedEventsRepoStub{
+		transactions: map[uuid.UUID]*domain.Transaction{},
+		parked:       map[uuid.UUID]*domain.UnmatchedTransferEvent{},
+	}
+}
+
+func (s *unmatchedEventsRepoStub) FindTransactionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.Transaction, error) {
+	return nil, store.ErrTransactionNotFound
+}
+
+func (s *unmatchedEventsRepoStub) FindTransactionByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	tx, ok := s.transactions[id]
+	if !ok {
+		return nil, store.ErrTransactionNotFound
+	}
+	return tx, nil
+}
+
+func (s *unmatchedEventsRepoStub) FindPendingMoneyDropClaimByAnchorParticipantsAndAmount(ctx context.Context, sourceAnchorAccountID string, destinationAnchorAccountID string, amount int64) (*domain.Transaction, error) {
+	return nil, store.ErrTransactionNotFound
+}
+
+func (s *unmatchedEventsRepoStub) FindFeeCollectionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.FeeCollection, error) {
+	return nil, store.ErrFeeCollectionNotFound
+}
+
+func (s *unmatchedEventsRepoStub) UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata store.UpdateTransactionMetadataParams) error {
+	s.metadataUpdates++
+	return nil
+}
+
+func (s *unmatchedEventsRepoStub) ParkUnmatchedTransferEvent(ctx context.Context, event domain.UnmatchedTransferEvent) (*domain.UnmatchedTransferEvent, error) {
+	event.ID = uuid.New()
+	event.Status = domain.UnmatchedTransferEventPending
+	event.ParkCount = 1
+	s.parked[event.ID] = &event
+	return &event, nil
+}
+
+func (s *unmatchedEventsRepoStub) FindUnmatchedTransferEventByID(ctx context.Context, id uuid.UUID) (*domain.UnmatchedTransferEvent, error) {
+	event, ok := s.parked[id]
+	if !ok {
+		return nil, store.ErrUnmatchedTransferEventNotFound
+	}
+	copied := *event
+	return &copied, nil
+}
+
+func (s *unmatchedEventsRepoStub) MarkUnmatchedTransferEventReplayed(ctx context.Context, id uuid.UUID, transactionID uuid.UUID) error {
+	event := s.parked[id]
+	event.Status = domain.UnmatchedTransferEventReplayed
+	event.TransactionID = &transactionID
+	return nil
+}
+
+func (s *unmatchedEventsRepoStub) RecordUnmatchedTransferEventReplayFailure(ctx context.Context, id uuid.UUID, lastError string) error {
+	s.replayFailures = append(s.replayFailures, lastError)
+	return nil
+}
+
+type transferLookupStub struct {
+	transfer *anchorclient.TransferDetails
+	err      error
+	calls    int
+}
+
+func (s *transferLookupStub) GetTransfer(ctx context.Context, transferID string) (*anchorclient.TransferDetails, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.transfer, nil
+}
+
+func pendingMoneyDropClaim() *domain.Transaction {
+	return &domain.Transaction{
+		ID:          uuid.New(),
+		SenderID:    uuid.New(),
+		RecipientID: ptrUUID(uuid.New()),
+		Type:        "money_drop_claim",
+		Status:      "pending",
+		Amount:      2500,
+	}
+}
+
+// exhaustRetries leaves the consumer one attempt short of giving up on a transfer, so
+// the next HandleMessage call takes the unmatched path without sleeping.
+func exhaustRetries(consumer *TransferStatusConsumer, anchorTransferID string) {
+	consumer.missingTxAttempts[anchorTransferID] = maxMissingTransferRetries - 1
+}
+
+func TestHandleMessage_ParksEventAfterRetriesAndAnchorLookupFail(t *testing.T) {
+	repo := newUnmatchedEventsRepoStub()
+	anchor := &transferLookupStub{err: &anchorclient.APIError{StatusCode: 404}}
+	consumer := NewTransferStatusConsumer(repo, anchor)
+	exhaustRetries(consumer, "atr_unknown")
+
+	body := []byte(`{"event_id":"evt-1","event_type":"nip.transfer.successful","status":"successful","anchor_transfer_id":"atr_unknown","amount":2500}`)
+	if !consumer.HandleMessage(body) {
+		t.Fatal("expected parked event to be acked")
+	}
+
+	if len(repo.parked) != 1 {
+		t.Fatalf("expected one parked event, got %d", len(repo.parked))
+	}
+	for _, parked := range repo.parked {
+		if parked.AnchorTransferID != "atr_unknown" || parked.EventKey != "evt-1" || parked.EventType != "nip.transfer.successful" {
+			t.Fatalf("unexpected parked event: %+v", parked)
+		}
+		if string(parked.Payload) != string(body) {
+			t.Fatalf("expected raw payload to be parked, got %s", parked.Payload)
+		}
+	}
+	if consumer.UnmatchedEvents() != 1 {
+		t.Fatalf("expected unmatched counter 1, got %d", consumer.UnmatchedEvents())
+	}
+	if anchor.calls != 1 {
+		t.Fatalf("expected one anchor lookup, got %d", anchor.calls)
+	}
+	if _, pending := consumer.missingTxAttempts["atr_unknown"]; pending {
+		t.Fatal("expected retry counter to be cleared after parking")
+	}
+}
+
+func TestHandleMessage_ResolvesViaAnchorTransferDescription(t *testing.T) {
+	repo := newUnmatchedEventsRepoStub()
+	claim := pendingMoneyDropClaim()
+	repo.transactions[claim.ID] = claim
+	anchor := &transferLookupStub{transfer: &anchorclient.TransferDetails{
+		ID:     "atr_claim",
+		Amount: 2500,
+		Reason: buildMoneyDropClaimTransferReason(claim.ID, ""),
+	}}
+	consumer := NewTransferStatusConsumer(repo, anchor)
+	exhaustRetries(consumer, "atr_claim")
+
+	body := []byte(`{"event_type":"book.transfer.processing","status":"processing","anchor_transfer_id":"atr_claim","amount":2500}`)
+	if !consumer.HandleMessage(body) {
+		t.Fatal("expected resolved event to be acked")
+	}
+
+	if len(repo.parked) != 0 {
+		t.Fatalf("expected no parked events, got %d", len(repo.parked))
+	}
+	if repo.metadataUpdates != 1 {
+		t.Fatalf("expected the event to be applied to the claim, got %d metadata updates", repo.metadataUpdates)
+	}
+	if consumer.UnmatchedEvents() != 0 {
+		t.Fatalf("expected unmatched counter 0, got %d", consumer.UnmatchedEvents())
+	}
+}
+
+func TestReplayUnmatchedTransferEvent(t *testing.T) {
+	repo := newUnmatchedEventsRepoStub()
+	consumer := NewTransferStatusConsumer(repo, nil)
+	svc := &Service{repo: repo, transferConsumer: consumer}
+
+	claim := pendingMoneyDropClaim()
+	payload, _ := json.Marshal(domain.TransferStatusEvent{
+		EventType:        "book.transfer.processing",
+		Status:           "processing",
+		AnchorTransferID: "atr_parked",
+		Amount:           2500,
+		Reason:           buildMoneyDropClaimTransferReason(claim.ID, ""),
+	})
+	parked, _ := repo.ParkUnmatchedTransferEvent(context.Background(), domain.UnmatchedTransferEvent{
+		AnchorTransferID: "atr_parked",
+		EventKey:         "book.transfer.processing:pending",
+		Payload:          payload,
+	})
+
+	// The claim does not exist yet, so the event stays parked.
+	if _, err := svc.ReplayUnmatchedTransferEvent(context.Background(), parked.ID); !errors.Is(err, ErrUnmatchedTransferEventStillUnmatched) {
+		t.Fatalf("expected ErrUnmatchedTransferEventStillUnmatched, got %v", err)
+	}
+	if len(repo.replayFailures) != 1 {
+		t.Fatalf("expected replay failure to be recorded, got %v", repo.replayFailures)
+	}
+
+	repo.transactions[claim.ID] = claim
+	replayed, err := svc.ReplayUnmatchedTransferEvent(context.Background(), parked.ID)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if replayed.Status != domain.UnmatchedTransferEventReplayed || replayed.TransactionID == nil || *replayed.TransactionID != claim.ID {
+		t.Fatalf("expected event replayed onto claim %s, got %+v", claim.ID, replayed)
+	}
+	if repo.metadataUpdates != 1 {
+		t.Fatalf("expected the event to be applied once, got %d metadata updates", repo.metadataUpdates)
+	}
+
+	if _, err := svc.ReplayUnmatchedTransferEvent(context.Background(), parked.ID); !errors.Is(err, ErrUnmatchedTransferEventAlreadyReplayed) {
+		t.Fatalf("expected ErrUnmatchedTransferEventAlreadyReplayed, got %v", err)
+	}
+}
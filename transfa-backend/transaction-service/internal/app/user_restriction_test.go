@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// freezeUser mirrors what account-service's freeze endpoint writes to the users row.
+func freezeUser(u *domain.User) {
+	u.AllowSending = false
+	u.AllowReceiving = false
+}
+
+func TestProcessP2PTransfer_RejectsFrozenRecipient(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+	freezeUser(repo.recipient)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	if !errors.Is(err, store.ErrReceivingRestricted) {
+		t.Fatalf("expected %v, got %v", store.ErrReceivingRestricted, err)
+	}
+	if repo.debits != 0 || repo.createdTx != nil {
+		t.Fatalf("expected no debit or transaction, got debits=%d tx=%v", repo.debits, repo.createdTx)
+	}
+}
+
+func TestProcessP2PTransfer_FreezeRacingTransfer(t *testing.T) {
+	tests := []struct {
+		name    string
+		frozen  func(repo *p2pTransferRepoStub) *domain.User
+		wantErr error
+	}{
+		{name: "sender frozen", frozen: func(repo *p2pTransferRepoStub) *domain.User { return repo.sender }, wantErr: store.ErrSendingRestricted},
+		{name: "recipient frozen", frozen: func(repo *p2pTransferRepoStub) *domain.User { return repo.recipient }, wantErr: store.ErrReceivingRestricted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+			ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+			// The freeze commits after the transfer has passed its up-front checks but
+			// before the debit acquires the users row lock.
+			repo.beforeDebit = func() { freezeUser(tt.frozen(repo)) }
+
+			_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+				RecipientUsername: "bob",
+				Amount:            5000,
+				Description:       "Airtime",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.debits != 0 {
+				t.Fatalf("expected no debit, got %d", repo.debits)
+			}
+			if repo.createdTx != nil {
+				t.Fatalf("expected no transaction record")
+			}
+			if got := len(publisher.find("transfer.initiated.p2p")); got != 0 {
+				t.Fatalf("expected no transfer.initiated.p2p event, got %d", got)
+			}
+		})
+	}
+}
+
+func TestProcessP2PTransfer_FreezeAfterDebitDoesNotUnwindTransfer(t *testing.T) {
+	publisher := &recordingPublisher{}
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, publisher)
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	// A freeze that waits on the debit's row lock is applied once the debit commits.
+	repo.afterDebit = func() { freezeUser(repo.recipient) }
+
+	tx, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	if err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+	if repo.debits != 1 || tx.Status != "pending" {
+		t.Fatalf("expected one debit and a pending transfer, got debits=%d status=%s", repo.debits, tx.Status)
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// renamedRecipientRepoStub behaves as if the recipient recently renamed themselves
+// from one of the usernames in previous.
+type renamedRecipientRepoStub struct {
+	*p2pTransferRepoStub
+	previous map[string]string
+}
+
+func (s *renamedRecipientRepoStub) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if _, ok := s.previous[username]; ok {
+		return nil, store.ErrUserNotFound
+	}
+	return s.p2pTransferRepoStub.FindUserByUsername(ctx, username)
+}
+
+func (s *renamedRecipientRepoStub) FindCurrentUsernameByPreviousUsername(ctx context.Context, username string) (string, error) {
+	current, ok := s.previous[username]
+	if !ok {
+		return "", store.ErrUserNotFound
+	}
+	return current, nil
+}
+
+func TestProcessP2PTransfer_OldUsernameReturnsNewHandle(t *testing.T) {
+	svc, repo := newP2PTransferTestService(t, http.StatusCreated, &recordingPublisher{})
+	svc.repo = &renamedRecipientRepoStub{p2pTransferRepoStub: repo, previous: map[string]string{"bobby": "bob"}}
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bobby",
+		Amount:            5000,
+		Description:       "Airtime",
+	})
+	var renamed *RecipientUsernameChangedError
+	if !errors.As(err, &renamed) {
+		t.Fatalf("expected RecipientUsernameChangedError, got %v", err)
+	}
+	if renamed.NewUsername != "bob" || renamed.OldUsername != "bobby" {
+		t.Fatalf("unexpected rename: %+v", renamed)
+	}
+	if repo.debits != 0 || repo.createdTx != nil {
+		t.Fatalf("expected no debit or transaction, got debits=%d tx=%v", repo.debits, repo.createdTx)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// debitAndRecordTransaction runs debit and inserts txRecord in one database
+// transaction, so a failure between the two cannot leave a debit with no record.
+// A non-nil recorded runs last in the same transaction, for writes that must
+// commit or roll back together with the record.
+func (s *Service) debitAndRecordTransaction(ctx context.Context, txRecord *domain.Transaction, debit func(txRepo store.Repository) error, recorded func(txRepo store.Repository) error) error {
+	return s.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		if err := debit(txRepo); err != nil {
+			return fmt.Errorf("failed to debit sender wallet: %w", err)
+		}
+		if err := txRepo.CreateTransaction(ctx, txRecord); err != nil {
+			return fmt.Errorf("failed to create transaction record: %w", err)
+		}
+		if recorded != nil {
+			return recorded(txRepo)
+		}
+		return nil
+	})
+}
+
+// failAndRefundTransaction marks a transaction failed and credits amount back to
+// userID together, so the refund happens exactly when the status changes.
+func (s *Service) failAndRefundTransaction(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, amount int64) error {
+	return s.repo.WithTx(ctx, func(txRepo store.Repository) error {
+		if err := txRepo.UpdateTransactionStatus(ctx, transactionID, "", "failed"); err != nil {
+			return fmt.Errorf("failed to mark transaction failed: %w", err)
+		}
+		if err := txRepo.CreditWallet(ctx, userID, amount); err != nil {
+			return fmt.Errorf("failed to refund wallet: %w", err)
+		}
+		return nil
+	})
+}
+
+// claimTransactionForTransfer closes the cancellation window before any money for a
+// transaction moves on Anchor, fee collection included. A transaction the sender cancelled first has already been
+// refunded, so ErrTransactionCancelled is returned and the caller must neither send
+// nor refund it.
+func (s *Service) claimTransactionForTransfer(ctx context.Context, transactionID uuid.UUID) error {
+	if err := s.repo.ClaimTransactionForTransfer(ctx, transactionID); err != nil {
+		if errors.Is(err, store.ErrTransactionNotPending) {
+			return ErrTransactionCancelled
+		}
+		return fmt.Errorf("failed to claim transaction for transfer: %w", err)
+	}
+	return nil
+}
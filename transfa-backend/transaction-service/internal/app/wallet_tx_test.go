@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+	"github.com/transfa/transaction-service/internal/store"
+)
+
+// ledgerState is the wallet and transaction state a ledgerRepoStub transaction
+// stages and commits.
+type ledgerState struct {
+	balances     map[uuid.UUID]int64
+	transactions map[uuid.UUID]domain.Transaction
+}
+
+func (l *ledgerState) clone() *ledgerState {
+	next := &ledgerState{
+		balances:     make(map[uuid.UUID]int64, len(l.balances)),
+		transactions: make(map[uuid.UUID]domain.Transaction, len(l.transactions)),
+	}
+	for id, balance := range l.balances {
+		next.balances[id] = balance
+	}
+	for id, tx := range l.transactions {
+		next.transactions[id] = tx
+	}
+	return next
+}
+
+// ledgerRepoStub tracks balances and transaction records, and applies writes made
+// inside WithTx only when the callback succeeds, as a database transaction would.
+type ledgerRepoStub struct {
+	*p2pTransferRepoStub
+
+	state *ledgerState
+
+	createTransactionErr error
+	creditWalletErr      error
+}
+
+func (s *ledgerRepoStub) WithTx(ctx context.Context, fn func(txRepo store.Repository) error) error {
+	staged := &ledgerRepoStub{
+		p2pTransferRepoStub:  s.p2pTransferRepoStub,
+		state:                s.state.clone(),
+		createTransactionErr: s.createTransactionErr,
+		creditWalletErr:      s.creditWalletErr,
+	}
+	if err := fn(staged); err != nil {
+		return err
+	}
+	s.state = staged.state
+	return nil
+}
+
+func (s *ledgerRepoStub) DebitWalletForP2PTransfer(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64) error {
+	if s.state.balances[senderID] < amount {
+		return store.ErrInsufficientFunds
+	}
+	s.state.balances[senderID] -= amount
+	return nil
+}
+
+func (s *ledgerRepoStub) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	if s.creditWalletErr != nil {
+		return s.creditWalletErr
+	}
+	s.state.balances[userID] += amount
+	return nil
+}
+
+func (s *ledgerRepoStub) CreateTransaction(ctx context.Context, tx *domain.Transaction) error {
+	if s.createTransactionErr != nil {
+		return s.createTransactionErr
+	}
+	s.state.transactions[tx.ID] = *tx
+	return nil
+}
+
+func (s *ledgerRepoStub) UpdateTransactionStatus(ctx context.Context, transactionID uuid.UUID, anchorTransferID, status string) error {
+	tx, ok := s.state.transactions[transactionID]
+	if !ok {
+		return store.ErrTransactionNotFound
+	}
+	tx.Status = status
+	s.state.transactions[transactionID] = tx
+	return nil
+}
+
+func newLedgerTestService(t *testing.T, transferStatus int) (*Service, *ledgerRepoStub) {
+	t.Helper()
+	svc, p2pRepo := newP2PTransferTestService(t, transferStatus, &recordingPublisher{})
+	svc.transactionFeeKobo = 500
+	repo := &ledgerRepoStub{
+		p2pTransferRepoStub: p2pRepo,
+		state: &ledgerState{
+			balances:     map[uuid.UUID]int64{p2pRepo.sender.ID: 10000},
+			transactions: map[uuid.UUID]domain.Transaction{},
+		},
+	}
+	svc.repo = repo
+	return svc, repo
+}
+
+func processLedgerTestTransfer(svc *Service, repo *ledgerRepoStub) error {
+	ctx := context.WithValue(context.Background(), skipAnchorBalanceCheckCtxKey, true)
+	_, err := svc.ProcessP2PTransfer(ctx, repo.sender.ID, domain.P2PTransferRequest{
+		RecipientUsername: "bob",
+		Amount:            2000,
+		Description:       "Lunch money",
+	})
+	return err
+}
+
+func TestProcessP2PTransfer_RecordFailureLeavesNoDebit(t *testing.T) {
+	svc, repo := newLedgerTestService(t, http.StatusCreated)
+	insertErr := errors.New("insert failed")
+	repo.createTransactionErr = insertErr
+
+	if err := processLedgerTestTransfer(svc, repo); !errors.Is(err, insertErr) {
+		t.Fatalf("expected the insert error, got %v", err)
+	}
+
+	if got := repo.state.balances[repo.sender.ID]; got != 10000 {
+		t.Fatalf("expected the debit to be rolled back, balance is %d", got)
+	}
+	if len(repo.state.transactions) != 0 {
+		t.Fatalf("expected no transaction record, got %d", len(repo.state.transactions))
+	}
+}
+
+func TestProcessP2PTransfer_DebitAndRecordCommitTogether(t *testing.T) {
+	svc, repo := newLedgerTestService(t, http.StatusCreated)
+
+	if err := processLedgerTestTransfer(svc, repo); err != nil {
+		t.Fatalf("expected transfer to succeed, got %v", err)
+	}
+
+	if got := repo.state.balances[repo.sender.ID]; got != 7500 {
+		t.Fatalf("expected amount and fee to be debited, balance is %d", got)
+	}
+	if len(repo.state.transactions) != 1 {
+		t.Fatalf("expected one transaction record, got %d", len(repo.state.transactions))
+	}
+}
+
+func TestProcessP2PTransfer_AnchorFailureMarksFailedAndRefunds(t *testing.T) {
+	svc, repo := newLedgerTestService(t, http.StatusBadRequest)
+
+	if err := processLedgerTestTransfer(svc, repo); err == nil {
+		t.Fatal("expected the transfer to fail")
+	}
+	if len(repo.state.transactions) != 1 {
+		t.Fatalf("expected the transaction record to be kept, got %d", len(repo.state.transactions))
+	}
+
+	if got := repo.state.balances[repo.sender.ID]; got != 10000 {
+		t.Fatalf("expected the debit to be refunded, balance is %d", got)
+	}
+	for _, tx := range repo.state.transactions {
+		if tx.Status != "failed" {
+			t.Fatalf("expected the record to be marked failed, got %s", tx.Status)
+		}
+	}
+}
+
+func TestProcessP2PTransfer_RefundFailureLeavesTransactionPending(t *testing.T) {
+	svc, repo := newLedgerTestService(t, http.StatusBadRequest)
+	repo.creditWalletErr = errors.New("credit failed")
+
+	if err := processLedgerTestTransfer(svc, repo); err == nil {
+		t.Fatal("expected the transfer to fail")
+	}
+	if len(repo.state.transactions) != 1 {
+		t.Fatalf("expected the transaction record to be kept, got %d", len(repo.state.transactions))
+	}
+
+	// The status change and refund are one unit: a failed refund must not leave a
+	// "failed" record that reconciliation would treat as already refunded.
+	if got := repo.state.balances[repo.sender.ID]; got != 7500 {
+		t.Fatalf("expected the debit to stand, balance is %d", got)
+	}
+	for _, tx := range repo.state.transactions {
+		if tx.Status != "pending" {
+			t.Fatalf("expected the record to stay pending, got %s", tx.Status)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/**
+ * @description
+ * Non-blocking writer for the audit_events trail. Callers on the payment path hand
+ * events to Record, which only ever does a channel send; a single background goroutine
+ * batches them into the database. When the buffer is full or a write fails the events
+ * are dropped and counted rather than slowing down or failing the request.
+ */
+package audit
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// DefaultBufferSize is the number of events that can wait for the writer before new
+// ones are dropped.
+const DefaultBufferSize = 1024
+
+const (
+	maxBatchSize  = 100
+	flushInterval = time.Second
+	writeTimeout  = 5 * time.Second
+)
+
+// Sink persists batches of audit events. The batch slice is reused after the call
+// returns, so implementations must not keep it.
+type Sink interface {
+	InsertAuditEvents(ctx context.Context, events []domain.AuditEvent) error
+}
+
+// Recorder queues audit events for a background writer. A nil *Recorder is valid and
+// discards everything, which keeps auditing optional in tests and tools.
+type Recorder struct {
+	sink    Sink
+	events  chan domain.AuditEvent
+	dropped atomic.Uint64
+}
+
+// NewRecorder returns a Recorder buffering up to bufferSize events. Run must be
+// started for events to be written.
+func NewRecorder(sink Sink, bufferSize int) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Recorder{sink: sink, events: make(chan domain.AuditEvent, bufferSize)}
+}
+
+// Record queues event without blocking. CreatedAt defaults to now.
+func (r *Recorder) Record(event domain.AuditEvent) {
+	if r == nil {
+		return
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	select {
+	case r.events <- event:
+	default:
+		r.drop(1, "buffer_full", event.Action, nil)
+	}
+}
+
+// Dropped reports how many events were lost to a full buffer or a failed write since
+// the recorder was created.
+func (r *Recorder) Dropped() uint64 {
+	if r == nil {
+		return 0
+	}
+	return r.dropped.Load()
+}
+
+// Run writes queued events until ctx is cancelled, then flushes whatever is still
+// buffered and returns.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.AuditEvent, 0, maxBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		r.write(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event := <-r.events:
+			batch = append(batch, event)
+			if len(batch) >= maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-r.events:
+					batch = append(batch, event)
+					if len(batch) >= maxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *Recorder) write(batch []domain.AuditEvent) {
+	// The writer outlives request contexts and must still flush during shutdown, so
+	// each write gets its own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.sink.InsertAuditEvents(ctx, batch); err != nil {
+		r.drop(len(batch), "write_failed", batch[0].Action, err)
+	}
+}
+
+func (r *Recorder) drop(count int, reason, action string, err error) {
+	total := r.dropped.Add(uint64(count))
+	// Log the first loss and then periodically, so a stuck database does not flood
+	// the logs from the request path.
+	if total == uint64(count) || total/100 != (total-uint64(count))/100 {
+		log.Printf("level=warn component=audit msg=\"audit events dropped\" reason=%s action=%s count=%d dropped_total=%d err=%v", reason, action, count, total, err)
+	}
+}
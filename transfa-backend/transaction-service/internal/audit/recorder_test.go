@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+type sinkStub struct {
+	mu      sync.Mutex
+	batches [][]domain.AuditEvent
+	err     error
+
+	// block, when set, holds every write until it is closed.
+	block chan struct{}
+}
+
+func (s *sinkStub) InsertAuditEvents(ctx context.Context, events []domain.AuditEvent) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]domain.AuditEvent(nil), events...))
+	return s.err
+}
+
+func (s *sinkStub) written() []domain.AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []domain.AuditEvent
+	for _, batch := range s.batches {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestRecorder_FlushesBufferedEventsOnShutdown(t *testing.T) {
+	sink := &sinkStub{}
+	recorder := NewRecorder(sink, 10)
+	recorder.Record(domain.AuditEvent{Action: "transfer.p2p", SubjectID: "tx-1"})
+	recorder.Record(domain.AuditEvent{Action: "money_drop.refund", SubjectID: "drop-1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	events := sink.written()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events written, got %d", len(events))
+	}
+	if events[0].Action != "transfer.p2p" || events[1].Action != "money_drop.refund" {
+		t.Fatalf("expected events in recording order, got %s, %s", events[0].Action, events[1].Action)
+	}
+	if events[0].CreatedAt.IsZero() {
+		t.Fatal("expected CreatedAt to default to the time of recording")
+	}
+	if recorder.Dropped() != 0 {
+		t.Fatalf("expected no drops, got %d", recorder.Dropped())
+	}
+}
+
+func TestRecorder_DropsWithoutBlockingWhenBufferIsFull(t *testing.T) {
+	recorder := NewRecorder(&sinkStub{}, 2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			recorder.Record(domain.AuditEvent{Action: "transfer.p2p"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full buffer")
+	}
+
+	if got := recorder.Dropped(); got != 3 {
+		t.Fatalf("expected 3 dropped events, got %d", got)
+	}
+}
+
+func TestRecorder_WritesInBatchesWhileRunning(t *testing.T) {
+	sink := &sinkStub{}
+	recorder := NewRecorder(sink, 500)
+	for i := 0; i < maxBatchSize+1; i++ {
+		recorder.Record(domain.AuditEvent{Action: "transfer.p2p"})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.batches) != 2 || len(sink.batches[0]) != maxBatchSize || len(sink.batches[1]) != 1 {
+		sizes := make([]int, len(sink.batches))
+		for i, batch := range sink.batches {
+			sizes[i] = len(batch)
+		}
+		t.Fatalf("expected batches of %d and 1, got %v", maxBatchSize, sizes)
+	}
+}
+
+func TestRecorder_CountsFailedWritesAsDropped(t *testing.T) {
+	sink := &sinkStub{err: errors.New("database unavailable")}
+	recorder := NewRecorder(sink, 10)
+	recorder.Record(domain.AuditEvent{Action: "platform_fee.debit"})
+	recorder.Record(domain.AuditEvent{Action: "platform_fee.debit"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	if got := recorder.Dropped(); got != 2 {
+		t.Fatalf("expected 2 dropped events after a failed write, got %d", got)
+	}
+}
+
+func TestRecorder_RecordDoesNotWaitForSlowWrites(t *testing.T) {
+	sink := &sinkStub{block: make(chan struct{})}
+	recorder := NewRecorder(sink, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		recorder.Run(ctx)
+	}()
+
+	recorder.Record(domain.AuditEvent{Action: "transfer.self"})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		recorder.Record(domain.AuditEvent{Action: "transfer.self"})
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("Record waited %s on a slow database write", elapsed)
+	}
+
+	cancel()
+	close(sink.block)
+	<-finished
+	if got := len(sink.written()) + int(recorder.Dropped()); got != 6 {
+		t.Fatalf("expected every event to be written or counted as dropped, got %d", got)
+	}
+}
+
+func TestRecorder_NilRecorderDiscardsEvents(t *testing.T) {
+	var recorder *Recorder
+	recorder.Record(domain.AuditEvent{Action: "transfer.p2p"})
+	if recorder.Dropped() != 0 {
+		t.Fatal("expected a nil recorder to report no drops")
+	}
+}
@@ -16,8 +16,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/transfa/pkg/dbpool"
 )
 
 // Config holds all the configuration variables for the transaction-service.
@@ -34,6 +36,8 @@ type Config struct {
 	ClerkJWKSURL                       string  `mapstructure:"CLERK_JWKS_URL"`
 	AccountServiceURL                  string  `mapstructure:"ACCOUNT_SERVICE_URL"`
 	AccountServiceInternalAPIKey       string  `mapstructure:"ACCOUNT_SERVICE_INTERNAL_API_KEY"`
+	AuthServiceURL                     string  `mapstructure:"AUTH_SERVICE_URL"`
+	AuthServiceInternalAPIKey          string  `mapstructure:"AUTH_SERVICE_INTERNAL_API_KEY"`
 	AdminAccountID                     string  `mapstructure:"ADMIN_ACCOUNT_ID"`
 	InternalAPIKey                     string  `mapstructure:"INTERNAL_API_KEY"`
 	P2PTransactionFeeKobo              int64   `mapstructure:"P2P_TRANSACTION_FEE_KOBO"`
@@ -46,10 +50,30 @@ type Config struct {
 	MoneyDropPasswordMaxAttempts       int     `mapstructure:"MONEY_DROP_PASSWORD_MAX_ATTEMPTS"`
 	MoneyDropPasswordLockoutSeconds    int     `mapstructure:"MONEY_DROP_PASSWORD_LOCKOUT_SECONDS"`
 	MoneyDropClaimIdempotencyTTLMin    int     `mapstructure:"MONEY_DROP_CLAIM_IDEMPOTENCY_TTL_MINUTES"`
+	MoneyDropPayoutWorkers             int     `mapstructure:"MONEY_DROP_PAYOUT_WORKERS"`
+	PlatformFeeEnforcement             string  `mapstructure:"PLATFORM_FEE_ENFORCEMENT"`
+	LogLevel                           string  `mapstructure:"LOG_LEVEL"`
+	TransactionArchiveAfterMonths      int     `mapstructure:"TRANSACTION_ARCHIVE_AFTER_MONTHS"`
+	AppEnv                             string  `mapstructure:"APP_ENV"`
+	AllowedOrigins                     string  `mapstructure:"ALLOWED_ORIGINS"`
+	OpenAPIDocsEnabled                 bool    `mapstructure:"OPENAPI_DOCS_ENABLED"`
+	AttachmentAllowedHosts             string  `mapstructure:"ATTACHMENT_ALLOWED_HOSTS"`
+
+	// DatabasePool is read from the DB_* pool variables, see databasePoolDefaults.
+	DatabasePool dbpool.Config `mapstructure:"-"`
 }
 
 // LoadConfig reads configuration from environment variables from the given path.
 // It uses Viper to automatically bind environment variables to the Config struct.
+
+// databasePoolDefaults sizes the connection pool when the DB_* pool variables are unset.
+var databasePoolDefaults = dbpool.Config{
+	MaxConns:        100,
+	MinConns:        20,
+	MaxConnLifetime: 30 * time.Minute,
+	MaxConnIdleTime: 5 * time.Minute,
+}
+
 func LoadConfig(path string) (config Config, err error) {
 	// Tell viper the path to look for the optional .env file.
 	viper.AddConfigPath(path)
@@ -74,6 +98,11 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetDefault("MONEY_DROP_PASSWORD_MAX_ATTEMPTS", 5)
 	viper.SetDefault("MONEY_DROP_PASSWORD_LOCKOUT_SECONDS", 600)
 	viper.SetDefault("MONEY_DROP_CLAIM_IDEMPOTENCY_TTL_MINUTES", 1440)
+	viper.SetDefault("MONEY_DROP_PAYOUT_WORKERS", 4)
+	viper.SetDefault("PLATFORM_FEE_ENFORCEMENT", "log_only")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("TRANSACTION_ARCHIVE_AFTER_MONTHS", 12)
+	viper.SetDefault("APP_ENV", "development")
 
 	// Bind environment variables explicitly to ensure they appear in Unmarshal
 	_ = viper.BindEnv("SERVER_PORT")
@@ -88,6 +117,8 @@ func LoadConfig(path string) (config Config, err error) {
 	_ = viper.BindEnv("CLERK_JWKS_URL")
 	_ = viper.BindEnv("ACCOUNT_SERVICE_URL")
 	_ = viper.BindEnv("ACCOUNT_SERVICE_INTERNAL_API_KEY")
+	_ = viper.BindEnv("AUTH_SERVICE_URL")
+	_ = viper.BindEnv("AUTH_SERVICE_INTERNAL_API_KEY")
 	_ = viper.BindEnv("ADMIN_ACCOUNT_ID")
 	_ = viper.BindEnv("INTERNAL_API_KEY", "INTERNAL_API_KEY", "TRANSACTION_SERVICE_INTERNAL_API_KEY")
 	_ = viper.BindEnv("P2P_TRANSACTION_FEE_KOBO")
@@ -105,6 +136,14 @@ func LoadConfig(path string) (config Config, err error) {
 	_ = viper.BindEnv("MONEY_DROP_PASSWORD_MAX_ATTEMPTS")
 	_ = viper.BindEnv("MONEY_DROP_PASSWORD_LOCKOUT_SECONDS")
 	_ = viper.BindEnv("MONEY_DROP_CLAIM_IDEMPOTENCY_TTL_MINUTES")
+	_ = viper.BindEnv("MONEY_DROP_PAYOUT_WORKERS")
+	_ = viper.BindEnv("PLATFORM_FEE_ENFORCEMENT")
+	_ = viper.BindEnv("LOG_LEVEL")
+	_ = viper.BindEnv("TRANSACTION_ARCHIVE_AFTER_MONTHS")
+	_ = viper.BindEnv("APP_ENV")
+	_ = viper.BindEnv("ALLOWED_ORIGINS")
+	_ = viper.BindEnv("OPENAPI_DOCS_ENABLED")
+	_ = viper.BindEnv("ATTACHMENT_ALLOWED_HOSTS")
 
 	// Attempt to read the config file. It's okay if it doesn't exist.
 	if err = viper.ReadInConfig(); err != nil {
@@ -131,6 +170,10 @@ func LoadConfig(path string) (config Config, err error) {
 	if config.AccountServiceInternalAPIKey == "" {
 		config.AccountServiceInternalAPIKey = config.InternalAPIKey
 	}
+	config.AuthServiceInternalAPIKey = strings.TrimSpace(config.AuthServiceInternalAPIKey)
+	if config.AuthServiceInternalAPIKey == "" {
+		config.AuthServiceInternalAPIKey = config.InternalAPIKey
+	}
 	config.RedisURL = strings.TrimSpace(config.RedisURL)
 	config.RedisRateLimitPrefix = strings.TrimSpace(config.RedisRateLimitPrefix)
 	if config.RedisRateLimitPrefix == "" {
@@ -231,6 +274,30 @@ func LoadConfig(path string) (config Config, err error) {
 	if config.MoneyDropClaimIdempotencyTTLMin <= 0 {
 		config.MoneyDropClaimIdempotencyTTLMin = 1440
 	}
+	if config.MoneyDropPayoutWorkers <= 0 {
+		config.MoneyDropPayoutWorkers = 4
+	}
+
+	// Delinquent senders are only logged until enforcement is switched on explicitly.
+	config.PlatformFeeEnforcement = strings.ToLower(strings.TrimSpace(config.PlatformFeeEnforcement))
+	if config.PlatformFeeEnforcement != "enforce" && config.PlatformFeeEnforcement != "log_only" {
+		log.Printf("level=warn component=config msg=\"invalid PLATFORM_FEE_ENFORCEMENT; using log_only\" value=%q", config.PlatformFeeEnforcement)
+		config.PlatformFeeEnforcement = "log_only"
+	}
+
+	config.LogLevel = strings.ToLower(strings.TrimSpace(config.LogLevel))
+	switch config.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		log.Printf("level=warn component=config msg=\"invalid LOG_LEVEL; using info\" value=%q", config.LogLevel)
+		config.LogLevel = "info"
+	}
+
+	if config.TransactionArchiveAfterMonths <= 0 {
+		log.Printf("level=warn component=config msg=\"invalid TRANSACTION_ARCHIVE_AFTER_MONTHS; using 12\" value=%d", config.TransactionArchiveAfterMonths)
+		config.TransactionArchiveAfterMonths = 12
+	}
 
+	config.DatabasePool, err = dbpool.Load(databasePoolDefaults, viper.GetString)
 	return
 }
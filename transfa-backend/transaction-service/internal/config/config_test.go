@@ -55,6 +55,109 @@ func TestLoadConfig_DefaultMoneyDropFeePercentIsZero(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_PlatformFeeEnforcement(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *string
+		want  string
+	}{
+		{name: "defaults to log_only", want: "log_only"},
+		{name: "accepts enforce", value: strPtr(" Enforce "), want: "enforce"},
+		{name: "falls back to log_only on unknown value", value: strPtr("block"), want: "log_only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+
+			if tt.value == nil {
+				unsetEnvWithCleanup(t, "PLATFORM_FEE_ENFORCEMENT")
+			} else {
+				setEnvWithCleanup(t, "PLATFORM_FEE_ENFORCEMENT", *tt.value)
+			}
+
+			cfg, err := LoadConfig(t.TempDir())
+			if err != nil {
+				t.Fatalf("LoadConfig returned error: %v", err)
+			}
+			if cfg.PlatformFeeEnforcement != tt.want {
+				t.Fatalf("expected PlatformFeeEnforcement %q, got %q", tt.want, cfg.PlatformFeeEnforcement)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_LogLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *string
+		want  string
+	}{
+		{name: "defaults to info", want: "info"},
+		{name: "accepts debug", value: strPtr(" DEBUG "), want: "debug"},
+		{name: "falls back to info on unknown value", value: strPtr("verbose"), want: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+
+			if tt.value == nil {
+				unsetEnvWithCleanup(t, "LOG_LEVEL")
+			} else {
+				setEnvWithCleanup(t, "LOG_LEVEL", *tt.value)
+			}
+
+			cfg, err := LoadConfig(t.TempDir())
+			if err != nil {
+				t.Fatalf("LoadConfig returned error: %v", err)
+			}
+			if cfg.LogLevel != tt.want {
+				t.Fatalf("expected LogLevel %q, got %q", tt.want, cfg.LogLevel)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_TransactionArchiveAfterMonths(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *string
+		want  int
+	}{
+		{name: "defaults to 12", want: 12},
+		{name: "accepts a positive value", value: strPtr("18"), want: 18},
+		{name: "falls back to 12 on zero", value: strPtr("0"), want: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+
+			if tt.value == nil {
+				unsetEnvWithCleanup(t, "TRANSACTION_ARCHIVE_AFTER_MONTHS")
+			} else {
+				setEnvWithCleanup(t, "TRANSACTION_ARCHIVE_AFTER_MONTHS", *tt.value)
+			}
+
+			cfg, err := LoadConfig(t.TempDir())
+			if err != nil {
+				t.Fatalf("LoadConfig returned error: %v", err)
+			}
+			if cfg.TransactionArchiveAfterMonths != tt.want {
+				t.Fatalf("expected TransactionArchiveAfterMonths %d, got %d", tt.want, cfg.TransactionArchiveAfterMonths)
+			}
+		})
+	}
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
 func setEnvWithCleanup(t *testing.T, key string, value string) {
 	t.Helper()
 	prev, hadPrev := os.LookupEnv(key)
@@ -14,6 +14,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,19 +39,93 @@ type Transaction struct {
 	Status                   string     `json:"status"`   // e.g., 'pending', 'completed', 'failed'
 	Amount                   int64      `json:"amount"`   // in kobo
 	Fee                      int64      `json:"fee"`      // in kobo
+	Currency                 string     `json:"currency,omitempty"`
 	Description              string     `json:"description"`
-	CreatedAt                time.Time  `json:"created_at"`
-	UpdatedAt                time.Time  `json:"updated_at"`
+	// RoutingDecision and RoutingReason record where a P2P transfer was delivered
+	// (internal or external) and why. The detail endpoint only returns RoutingReason
+	// to the recipient.
+	RoutingDecision string    `json:"routing_decision,omitempty"`
+	RoutingReason   string    `json:"routing_reason,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	// Dispute is the caller's latest dispute on this transaction; only set on the detail response.
+	Dispute *TransactionDisputeSummary `json:"dispute,omitempty"`
+	// Archived is set when the row was read from transactions_archive.
+	Archived bool `json:"archived,omitempty"`
+	// Note and Attachments are the caller's own; other parties never see them.
+	Note        *string                 `json:"note,omitempty"`
+	Attachments []TransactionAttachment `json:"attachments,omitempty"`
+}
+
+// DefaultCurrency is the currency of every wallet today. Amounts are in its minor
+// unit (kobo).
+const DefaultCurrency = "NGN"
+
+// TransactionHistoryFilter bounds a user's transaction history. Nil bounds are open.
+// Types and Status narrow the result when set; an empty Types matches every type.
+// IncludeArchive also reads transactions_archive; the service sets it only when the
+// range reaches back to archived data.
+type TransactionHistoryFilter struct {
+	From           *time.Time
+	To             *time.Time
+	Types          []string
+	Status         string
+	IncludeArchive bool
+}
+
+// TransactionStatuses lists the statuses a transaction history can be filtered by.
+var TransactionStatuses = []string{"pending", "processing", "completed", "failed", "cancelled"}
+
+// TransactionArchiveResult summarizes one archival run.
+type TransactionArchiveResult struct {
+	Cutoff   time.Time `json:"cutoff"`
+	Archived int64     `json:"archived"`
+	Batches  int       `json:"batches"`
 }
 
 // P2PTransferRequest is the DTO for incoming peer-to-peer transfer API requests.
 type P2PTransferRequest struct {
 	RecipientUsername string `json:"recipient_username"`
-	Amount            int64  `json:"amount"` // in kobo
+	Amount            int64  `json:"amount"`             // in kobo
+	Currency          string `json:"currency,omitempty"` // defaults to NGN
 	Description       string `json:"description"`
 	TransactionPIN    string `json:"transaction_pin"`
+	// Delivery is "internal" to credit the recipient's Transfa wallet whatever their
+	// receiving preference, or "recipient_preference" (the default) to follow it.
+	Delivery string `json:"delivery,omitempty"`
 }
 
+// P2P delivery options a sender can choose.
+const (
+	P2PDeliveryInternal            = "internal"
+	P2PDeliveryRecipientPreference = "recipient_preference"
+)
+
+// Routing decisions recorded on P2P transactions, and the reasons behind them.
+const (
+	RoutingInternal = "internal"
+	RoutingExternal = "external"
+
+	// RoutingReasonSenderOverride: the sender asked for internal delivery.
+	RoutingReasonSenderOverride = "sender_override"
+	// RoutingReasonExternalPreferred: delivered to the recipient's preferred external account.
+	RoutingReasonExternalPreferred = "external_preferred"
+	// RoutingReasonInternalPreferred: the recipient prefers their wallet.
+	RoutingReasonInternalPreferred = "internal_preferred"
+	// RoutingReasonQuotaExceeded: the recipient prefers an external account but the
+	// sender is behind on platform fees, which keeps the transfer off external rails.
+	RoutingReasonQuotaExceeded = "quota_exceeded"
+	// RoutingReasonNotEligible: the recipient prefers an external account but is not
+	// eligible for external delivery, or their eligibility could not be checked.
+	RoutingReasonNotEligible = "not_eligible"
+	// RoutingReasonBeneficiaryMissing: the recipient prefers an external account but
+	// has no usable beneficiary.
+	RoutingReasonBeneficiaryMissing = "beneficiary_missing"
+	// RoutingReasonPreferenceLookupFailed: the recipient's receiving preference could
+	// not be read, so the transfer was delivered internally.
+	RoutingReasonPreferenceLookupFailed = "preference_lookup_failed"
+)
+
 // BulkP2PTransferRequest is the DTO for initiating multiple P2P transfers in one request.
 type BulkP2PTransferRequest struct {
 	Transfers      []BulkP2PTransferItem `json:"transfers"`
@@ -111,11 +186,35 @@ type TransferBatchItem struct {
 // SelfTransferRequest is the DTO for incoming self-transfer (withdrawal) API requests.
 type SelfTransferRequest struct {
 	BeneficiaryID  uuid.UUID `json:"beneficiary_id"`
-	Amount         int64     `json:"amount"` // in kobo
+	Amount         int64     `json:"amount"`             // in kobo
+	Currency       string    `json:"currency,omitempty"` // defaults to NGN
 	Description    string    `json:"description"`
 	TransactionPIN string    `json:"transaction_pin"`
 }
 
+// Pot transfer directions.
+const (
+	PotTransferToPot   = "to_pot"
+	PotTransferFromPot = "from_pot"
+)
+
+// PotTransferRequest is the DTO for moving funds between a user's primary account and one of their pots.
+type PotTransferRequest struct {
+	Amount      int64  `json:"amount"`    // in kobo
+	Direction   string `json:"direction"` // "to_pot" or "from_pot"
+	Description string `json:"description"`
+}
+
+// FeeSchedule describes one fee the service charges. Percent is set for fees that
+// also scale with the amount moved, on top of AmountKobo.
+type FeeSchedule struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	AmountKobo  int64   `json:"amount_kobo"`
+	Percent     float64 `json:"percent,omitempty"`
+	AppliesTo   string  `json:"applies_to"`
+}
+
 // User represents a simplified view of a user, containing only the data
 // needed by the transaction-service.
 type User struct {
@@ -123,6 +222,7 @@ type User struct {
 	Username         string    `json:"username"`
 	FullName         *string   `json:"full_name,omitempty"`
 	AllowSending     bool      `json:"allow_sending"`
+	AllowReceiving   bool      `json:"allow_receiving"`
 	AnchorCustomerID string    `json:"anchor_customer_id"`
 }
 
@@ -140,7 +240,12 @@ type Account struct {
 	ID              uuid.UUID `json:"id"`
 	UserID          uuid.UUID `json:"user_id"`
 	AnchorAccountID string    `json:"anchor_account_id"`
-	Balance         int64     `json:"balance"` // in kobo
+	AccountNumber   string    `json:"account_number,omitempty"` // virtual NUBAN
+	Balance         int64     `json:"balance"`                  // in kobo
+	Currency        string    `json:"currency,omitempty"`
+	// UpdatedAt is when the row last changed. Balance syncs use it as the snapshot
+	// they guard their write against.
+	UpdatedAt time.Time `json:"-"`
 }
 
 // Beneficiary represents a user's saved external bank account.
@@ -152,10 +257,24 @@ type Beneficiary struct {
 	AccountNumberMasked  string    `json:"account_number_masked"`
 	BankName             string    `json:"bank_name"`
 	IsDefault            bool      `json:"is_default"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
+	// VerificationStatus is the outcome of the last time the account was resolved
+	// through Anchor; see the BeneficiaryVerification constants.
+	VerificationStatus string     `json:"verification_status"`
+	LastVerifiedAt     *time.Time `json:"last_verified_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
+// Beneficiary verification statuses.
+const (
+	BeneficiaryVerificationUnverified   = "unverified"
+	BeneficiaryVerificationVerified     = "verified"
+	BeneficiaryVerificationNameMismatch = "name_mismatch"
+	// BeneficiaryVerificationInvalid: the account no longer resolves; transfers to it
+	// are rejected until the user updates it.
+	BeneficiaryVerificationInvalid = "invalid"
+)
+
 // ReroutedInternalPayload is the message payload published to RabbitMQ
 // when a P2P transfer is rerouted to the recipient's internal wallet.
 type ReroutedInternalPayload struct {
@@ -165,6 +284,15 @@ type ReroutedInternalPayload struct {
 	Reason      string    `json:"reason"`
 }
 
+// P2PTransferInitiatedPayload is the message payload published to RabbitMQ
+// once a P2P transfer has been accepted by Anchor and is awaiting settlement.
+type P2PTransferInitiatedPayload struct {
+	SenderUsername string    `json:"sender_username"`
+	RecipientID    uuid.UUID `json:"recipient_id"`
+	Amount         int64     `json:"amount"`
+	TransactionID  uuid.UUID `json:"transaction_id"`
+}
+
 // AccountBalance represents the balance information for a user's account.
 type AccountBalance struct {
 	AvailableBalance int64 `json:"available_balance"` // in kobo
@@ -229,6 +357,109 @@ type ToggleTransferListMemberResult struct {
 	Username string              `json:"username"`
 }
 
+// RecentRecipient is a distinct user the caller has completed a P2P transfer to.
+type RecentRecipient struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	FullName   *string   `json:"full_name,omitempty"`
+	LastSentAt time.Time `json:"last_sent_at"`
+}
+
+// FavoriteRecipient is a user pinned by the caller for quick transfers.
+// CanReceive is false when the pinned user no longer has an active wallet.
+type FavoriteRecipient struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	FullName   *string   `json:"full_name,omitempty"`
+	CanReceive bool      `json:"can_receive"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AddFavoriteRecipientPayload struct {
+	Username string `json:"username"`
+}
+
+// TransactionDispute is a transaction flagged for review by one of its participants.
+type TransactionDispute struct {
+	ID             uuid.UUID  `json:"id"`
+	TransactionID  uuid.UUID  `json:"transaction_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Reason         string     `json:"dispute_reason"` // 'unauthorized', 'wrong_amount', 'not_received', 'other'
+	Description    *string    `json:"description,omitempty"`
+	Status         string     `json:"status"` // 'open', 'investigating', 'resolved', 'rejected'
+	ResolutionNote *string    `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TransactionDisputeSummary is the dispute shown on a transaction's detail response.
+type TransactionDisputeSummary struct {
+	ID             uuid.UUID `json:"id"`
+	Reason         string    `json:"dispute_reason"`
+	Status         string    `json:"status"`
+	ResolutionNote *string   `json:"resolution_note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TransactionNote is a user's private note on a transaction they took part in.
+type TransactionNote struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Note          *string   `json:"note"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpdateTransactionNotePayload sets the caller's note. An empty note removes it.
+type UpdateTransactionNotePayload struct {
+	Note string `json:"note"`
+}
+
+// TransactionAttachment is an image, such as a receipt, a user attached to a
+// transaction. The app uploads the file to storage and records only its URL.
+type TransactionAttachment struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	UserID        uuid.UUID `json:"-"`
+	URL           string    `json:"url"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AddTransactionAttachmentPayload struct {
+	URL string `json:"url"`
+}
+
+type CreateTransactionDisputePayload struct {
+	Reason      string `json:"dispute_reason"`
+	Description string `json:"description"`
+}
+
+// UpdateTransactionDisputeStatusPayload moves a dispute along its review workflow.
+// ResolutionNote is required when closing a dispute.
+type UpdateTransactionDisputeStatusPayload struct {
+	Status         string `json:"status"`
+	ResolutionNote string `json:"resolution_note"`
+}
+
+// TransactionDisputeStatusChangedEvent is published on dispute.status_changed.
+type TransactionDisputeStatusChangedEvent struct {
+	DisputeID      uuid.UUID `json:"dispute_id"`
+	TransactionID  uuid.UUID `json:"transaction_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	ResolutionNote *string   `json:"resolution_note,omitempty"`
+}
+
+// TransactionDisputeFilter narrows the internal disputes listing. Zero values are ignored.
+type TransactionDisputeFilter struct {
+	Status string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
 // PaymentRequest represents a payment request record in the database.
 // It aligns with the `payment_requests` table schema.
 type PaymentRequest struct {
@@ -244,6 +475,11 @@ type PaymentRequest struct {
 	RecipientUsername *string    `json:"recipient_username,omitempty" db:"recipient_username"`
 	RecipientFullName *string    `json:"recipient_full_name,omitempty" db:"recipient_full_name"`
 	Amount            int64      `json:"amount" db:"amount"`
+	AllowPartial      bool       `json:"allow_partial" db:"allow_partial"`
+	AmountPaid        int64      `json:"amount_paid" db:"amount_paid"`
+	RemainingAmount   int64      `json:"remaining_amount"`
+	ShortCode         *string    `json:"short_code,omitempty" db:"short_code"`
+	SplitID           *uuid.UUID `json:"split_id,omitempty" db:"split_id"`
 	Description       *string    `json:"description,omitempty" db:"description"`
 	ImageURL          *string    `json:"image_url,omitempty" db:"image_url"`
 	FulfilledByUserID *uuid.UUID `json:"fulfilled_by_user_id,omitempty" db:"fulfilled_by_user_id"`
@@ -251,11 +487,37 @@ type PaymentRequest struct {
 	ProcessingStarted *time.Time `json:"processing_started_at,omitempty" db:"processing_started_at"`
 	RespondedAt       *time.Time `json:"responded_at,omitempty" db:"responded_at"`
 	DeclinedReason    *string    `json:"declined_reason,omitempty" db:"declined_reason"`
+	DeclinedAt        *time.Time `json:"declined_at,omitempty" db:"declined_at"`
 	ShareableLink     string     `json:"shareable_link,omitempty"`
 	QRCodeContent     string     `json:"qr_code_content,omitempty"`
 	DeletedAt         *time.Time `json:"-" db:"deleted_at"`
 	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	// Settlements lists the transfers made toward the request, oldest first. Only
+	// the request detail endpoints load it.
+	Settlements []PaymentRequestSettlement `json:"settlements,omitempty"`
+}
+
+// PublicPaymentRequest is the unauthenticated view of a payment request that a pay
+// page is rendered from. It never carries the creator's user or account identifiers.
+type PublicPaymentRequest struct {
+	ID              uuid.UUID `json:"id"`
+	ShortCode       *string   `json:"short_code,omitempty"`
+	CreatorUsername *string   `json:"creator_username,omitempty"`
+	Title           string    `json:"title"`
+	Amount          int64     `json:"amount"`
+	RemainingAmount int64     `json:"remaining_amount"`
+	Description     *string   `json:"description,omitempty"`
+	Status          string    `json:"status"`
+}
+
+// PaymentRequestSettlement is one transfer made toward a payment request. A request
+// that allows partial payment can have several.
+type PaymentRequestSettlement struct {
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	Amount        int64     `json:"amount" db:"amount"`
+	Status        string    `json:"status" db:"status"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
 
 // CreatePaymentRequestPayload defines the structure for creating a new payment request.
@@ -264,10 +526,97 @@ type CreatePaymentRequestPayload struct {
 	Title             string  `json:"title"`
 	RecipientUsername *string `json:"recipient_username,omitempty"`
 	Amount            int64   `json:"amount" validate:"required,gt=0"`
+	AllowPartial      bool    `json:"allow_partial,omitempty"`
 	Description       *string `json:"description,omitempty"`
 	ImageURL          *string `json:"image_url,omitempty"`
 }
 
+// CreatePaymentRequestSplitPayload splits a bill among several users. Each recipient
+// gets an individual payment request for their share.
+type CreatePaymentRequestSplitPayload struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+	TotalAmount int64   `json:"total_amount"`
+	// Strategy is "equal" or "custom". Custom splits take each recipient's amount,
+	// which must add up to TotalAmount; equal splits ignore them.
+	Strategy   string                         `json:"strategy"`
+	Recipients []PaymentRequestSplitRecipient `json:"recipients"`
+}
+
+// PaymentRequestSplitRecipient is one person in a split and, for custom splits,
+// their share.
+type PaymentRequestSplitRecipient struct {
+	Username string `json:"username"`
+	Amount   int64  `json:"amount,omitempty"`
+}
+
+// PaymentRequestSplit is a bill split among several users. The split itself is only
+// bookkeeping: each member is paid through their own payment request.
+type PaymentRequestSplit struct {
+	ID              uuid.UUID                   `json:"id" db:"id"`
+	CreatorID       uuid.UUID                   `json:"creator_id" db:"creator_id"`
+	Title           string                      `json:"title" db:"title"`
+	Description     *string                     `json:"description,omitempty" db:"description"`
+	TotalAmount     int64                       `json:"total_amount" db:"total_amount"`
+	Strategy        string                      `json:"strategy" db:"strategy"`
+	CollectedAmount int64                       `json:"collected_amount"`
+	Members         []PaymentRequestSplitMember `json:"members"`
+	CreatedAt       time.Time                   `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time                   `json:"updated_at" db:"updated_at"`
+}
+
+// PaymentRequestSplitMember is one recipient's payment request within a split.
+// Status is "pending", "paid" or "declined".
+type PaymentRequestSplitMember struct {
+	RequestID       uuid.UUID  `json:"request_id" db:"id"`
+	RecipientUserID *uuid.UUID `json:"recipient_user_id,omitempty" db:"recipient_user_id"`
+	Username        *string    `json:"username,omitempty" db:"recipient_username"`
+	FullName        *string    `json:"full_name,omitempty" db:"recipient_full_name"`
+	Amount          int64      `json:"amount" db:"amount"`
+	AmountPaid      int64      `json:"amount_paid" db:"amount_paid"`
+	RemainingAmount int64      `json:"remaining_amount"`
+	Status          string     `json:"status" db:"status"`
+}
+
+// PaymentRequestReceivedPayload is the message payload published to RabbitMQ
+// once an individual payment request has been created for a recipient.
+type PaymentRequestReceivedPayload struct {
+	CreatorUsername string    `json:"creator_username"`
+	RecipientID     uuid.UUID `json:"recipient_id"`
+	Amount          int64     `json:"amount"`
+	Description     *string   `json:"description,omitempty"`
+	RequestID       uuid.UUID `json:"request_id"`
+}
+
+// PaymentRequestProcessingPayload is the message payload published to RabbitMQ
+// once the recipient of an individual payment request has started paying it.
+type PaymentRequestProcessingPayload struct {
+	RequestID     uuid.UUID `json:"request_id"`
+	CreatorID     uuid.UUID `json:"creator_id"`
+	PayerID       uuid.UUID `json:"payer_id"`
+	Amount        int64     `json:"amount"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// PaymentRequestDeclinedPayload is the message payload published to RabbitMQ
+// once the recipient of an individual payment request has declined it.
+type PaymentRequestDeclinedPayload struct {
+	RequestID   uuid.UUID `json:"request_id"`
+	CreatorID   uuid.UUID `json:"creator_id"`
+	RecipientID uuid.UUID `json:"recipient_id"`
+	Amount      int64     `json:"amount"`
+	Reason      *string   `json:"reason,omitempty"`
+	DeclinedAt  time.Time `json:"declined_at"`
+}
+
+// PaymentRequestDeletedPayload is the message payload published to RabbitMQ
+// once a creator has deleted one of their payment requests.
+type PaymentRequestDeletedPayload struct {
+	RequestID uuid.UUID `json:"request_id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
 // PaymentRequestListOptions controls pagination and search for creator-owned requests.
 type PaymentRequestListOptions struct {
 	Limit  int
@@ -279,6 +628,9 @@ type PaymentRequestListOptions struct {
 
 type PayIncomingPaymentRequestPayload struct {
 	TransactionPIN string `json:"transaction_pin"`
+	// Amount is the part of the remaining balance to pay now. It defaults to the
+	// whole remaining balance; less is only accepted when the request allows partial payment.
+	Amount *int64 `json:"amount,omitempty"`
 }
 
 type DeclineIncomingPaymentRequestPayload struct {
@@ -296,6 +648,14 @@ type NotificationListOptions struct {
 	Search   string
 	Category string
 	Status   string
+	// Cursor, when set, returns only notifications older than it.
+	Cursor *NotificationCursor
+}
+
+// NotificationCursor marks a position in a newest-first notification list.
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
 }
 
 type InAppNotification struct {
@@ -322,6 +682,30 @@ type NotificationUnreadCounts struct {
 	System     int64 `json:"system"`
 }
 
+// MarkInAppNotificationsReadPayload is the body of POST /notifications/read.
+type MarkInAppNotificationsReadPayload struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// NotificationCleanupResult summarizes one run of the read notification cleanup.
+type NotificationCleanupResult struct {
+	Cutoff  time.Time `json:"cutoff"`
+	Deleted int64     `json:"deleted"`
+	Batches int       `json:"batches"`
+}
+
+// PlatformFeeEvent is the payload platform-fee-service publishes when an invoice
+// changes state.
+type PlatformFeeEvent struct {
+	UserID     string    `json:"user_id"`
+	InvoiceID  string    `json:"invoice_id"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	DueAt      time.Time `json:"due_at"`
+	GraceUntil time.Time `json:"grace_until"`
+}
+
 // MoneyDrop represents the state of a money drop in the database.
 type MoneyDrop struct {
 	ID                     uuid.UUID  `json:"id" db:"id"`
@@ -343,6 +727,7 @@ type MoneyDrop struct {
 	EndedReason            *string    `json:"ended_reason,omitempty" db:"ended_reason"`
 	FundingSourceAccountID uuid.UUID  `json:"funding_source_account_id" db:"funding_source_account_id"`
 	MoneyDropAccountID     uuid.UUID  `json:"money_drop_account_id" db:"money_drop_account_id"`
+	Currency               string     `json:"currency,omitempty" db:"currency"`
 	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
 }
 
@@ -358,6 +743,7 @@ type MoneyDropClaim struct {
 type CreateMoneyDropRequest struct {
 	Title           string `json:"title"`
 	TotalAmount     int64  `json:"total_amount" binding:"required,gt=0"`
+	Currency        string `json:"currency,omitempty"` // defaults to NGN
 	NumberOfPeople  int    `json:"number_of_people" binding:"required,gt=0"`
 	ExpiryInMinutes int    `json:"expiry_in_minutes" binding:"required,gt=0"`
 	LockDrop        bool   `json:"lock_drop"`
@@ -388,6 +774,19 @@ type CreateMoneyDropResponse struct {
 	ExpiryTimestamp time.Time `json:"expiry_timestamp"`
 }
 
+// ShortLink maps a short shareable code to a money drop claim URL.
+type ShortLink struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`
+	ShortURL    string    `json:"short_url"`
+	MoneyDropID uuid.UUID `json:"money_drop_id"`
+	CreatedBy   uuid.UUID `json:"created_by"`
+	TargetURL   string    `json:"target_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ClickCount  int64     `json:"click_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // ClaimMoneyDropRequest defines claim payload. Lock password is required for password-protected drops.
 type ClaimMoneyDropRequest struct {
 	LockPassword   string `json:"lock_password"`
@@ -399,6 +798,39 @@ type ClaimMoneyDropResponse struct {
 	Message         string `json:"message"`
 	AmountClaimed   int64  `json:"amount_claimed"`
 	CreatorUsername string `json:"creator_username"`
+	PayoutStatus    string `json:"payout_status,omitempty"`
+}
+
+// MoneyDropClaimedPayload is the message payload published to RabbitMQ once a claim
+// on a money drop has been recorded.
+type MoneyDropClaimedPayload struct {
+	DropID        uuid.UUID `json:"drop_id"`
+	Title         string    `json:"title"`
+	CreatorID     uuid.UUID `json:"creator_id"`
+	ClaimantID    uuid.UUID `json:"claimant_id"`
+	Amount        int64     `json:"amount"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// Money drop claim payout statuses. A claim is payout_pending from the moment it is
+// recorded until the payout worker has sent the transfer to Anchor.
+const (
+	MoneyDropPayoutPending    = "payout_pending"
+	MoneyDropPayoutProcessing = "processing"
+	MoneyDropPayoutCompleted  = "completed"
+	MoneyDropPayoutFailed     = "failed"
+)
+
+// MoneyDropClaimPayout is what the payout worker needs to pay out one claim.
+type MoneyDropClaimPayout struct {
+	TransactionID              uuid.UUID
+	DropID                     uuid.UUID
+	ClaimantID                 uuid.UUID
+	Amount                     int64
+	MoneyDropAccountID         uuid.UUID
+	SourceAnchorAccountID      string
+	DestinationAnchorAccountID string
+	CreatorUsername            string
 }
 
 type RevealMoneyDropPasswordRequest struct {
@@ -432,6 +864,7 @@ type MoneyDropDashboardItem struct {
 	AmountPerPerson    int64     `json:"amount_per_person"`
 	NumberOfPeople     int       `json:"number_of_people"`
 	ClaimsMadeCount    int       `json:"claims_made_count"`
+	RemainingSlots     int       `json:"remaining_slots"`
 	TimeLeftLabel      string    `json:"time_left_label"`
 	UsersClaimedLabel  string    `json:"users_claimed_label"`
 	ExpiryTimestamp    time.Time `json:"expiry_timestamp"`
@@ -453,6 +886,7 @@ type MoneyDropClaimer struct {
 	ProfilePictureURL *string   `json:"profile_picture_url,omitempty"`
 	AmountClaimed     int64     `json:"amount_claimed"`
 	ClaimedAt         time.Time `json:"claimed_at"`
+	PayoutStatus      string    `json:"payout_status,omitempty"`
 }
 
 type MoneyDropOwnerDetails struct {
@@ -471,6 +905,8 @@ type MoneyDropOwnerDetails struct {
 	ShareableLink      string             `json:"shareable_link"`
 	QRCodeContent      string             `json:"qr_code_content"`
 	Claimers           []MoneyDropClaimer `json:"claimers"`
+	ClaimersHasMore    bool               `json:"claimers_has_more"`
+	CurrentBalance     int64              `json:"current_balance"`
 	CanEndDrop         bool               `json:"can_end_drop"`
 	EndedAt            *time.Time         `json:"ended_at,omitempty"`
 	EndedReason        *string            `json:"ended_reason,omitempty"`
@@ -492,12 +928,16 @@ type EndMoneyDropResponse struct {
 	Message          string    `json:"message"`
 }
 
+// ClaimedMoneyDropHistoryItem is one of the user's money drop claims. DropStatus is the
+// drop's current status (active, completed or expired_and_refunded), not its status
+// at the time of the claim.
 type ClaimedMoneyDropHistoryItem struct {
 	DropID          uuid.UUID `json:"drop_id"`
 	Title           string    `json:"title"`
 	CreatorUsername string    `json:"creator_username"`
 	AmountClaimed   int64     `json:"amount_claimed"`
 	ClaimedAt       time.Time `json:"claimed_at"`
+	DropStatus      string    `json:"drop_status"`
 }
 
 type ClaimedMoneyDropHistoryResponse struct {
@@ -513,6 +953,15 @@ type PendingMoneyDropClaimReconciliationCandidate struct {
 	Amount                     int64
 }
 
+// BalanceSyncResult summarizes a full account balance sync against Anchor.
+type BalanceSyncResult struct {
+	Scanned   int `json:"scanned"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"` // balance moved locally while Anchor was being queried
+	Failed    int `json:"failed"`
+}
+
 // MoneyDropClaimReconcileResponse summarizes an internal reconciliation run.
 type MoneyDropClaimReconcileResponse struct {
 	Processed             int `json:"processed"`
@@ -521,3 +970,222 @@ type MoneyDropClaimReconcileResponse struct {
 	ExplicitAnchorRejects int `json:"explicit_anchor_rejects"`
 	AmbiguousFailures     int `json:"ambiguous_failures"`
 }
+
+// MoneyDropExpiryResponse summarizes a batch finalization of expired or fully claimed drops.
+// Expired also counts drops another caller was already finalizing.
+type MoneyDropExpiryResponse struct {
+	Processed      int                      `json:"processed"`
+	Expired        int                      `json:"expired"`
+	RefundedAmount int64                    `json:"refunded_amount"`
+	Failed         int                      `json:"failed"`
+	Failures       []MoneyDropExpiryFailure `json:"failures"`
+}
+
+// MoneyDropExpiryFailure records a drop that could not be finalized in a batch.
+type MoneyDropExpiryFailure struct {
+	DropID uuid.UUID `json:"drop_id"`
+	Error  string    `json:"error"`
+}
+
+// ExpiredMoneyDrop is an active drop whose expiry has passed but which has not been
+// finalized yet, as listed for the scheduler.
+type ExpiredMoneyDrop struct {
+	ID                 uuid.UUID `json:"id"`
+	CreatorID          uuid.UUID `json:"creator_id"`
+	ExpiryTimestamp    time.Time `json:"expiry_timestamp"`
+	ClaimsMadeCount    int       `json:"claims_made_count"`
+	TotalClaimsAllowed int       `json:"total_claims_allowed"`
+	RefundedAmount     int64     `json:"refunded_amount"`
+}
+
+// AccountStatementRequest is the body of POST /transactions/statements. From and To
+// are inclusive YYYY-MM-DD dates; a non-empty Password encrypts the PDF.
+type AccountStatementRequest struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Password string `json:"password,omitempty"`
+}
+
+// AccountStatement is a user's wallet activity for a period. Amounts are in kobo.
+type AccountStatement struct {
+	AccountName    string                  `json:"account_name"`
+	AccountNumber  string                  `json:"account_number"`
+	PeriodStart    time.Time               `json:"period_start"`
+	PeriodEnd      time.Time               `json:"period_end"`
+	OpeningBalance int64                   `json:"opening_balance"`
+	TotalCredits   int64                   `json:"total_credits"`
+	TotalDebits    int64                   `json:"total_debits"`
+	ClosingBalance int64                   `json:"closing_balance"`
+	Entries        []AccountStatementEntry `json:"entries"`
+	// IncludesArchived is set when any transaction behind the statement came from
+	// transactions_archive.
+	IncludesArchived bool `json:"includes_archived"`
+}
+
+// AccountStatementEntry is one completed transaction on a statement, with the
+// running balance after it.
+type AccountStatementEntry struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	Description   string    `json:"description"`
+	Debit         int64     `json:"debit"`
+	Credit        int64     `json:"credit"`
+	Balance       int64     `json:"balance"`
+}
+
+// Audit actor types.
+const (
+	AuditActorUser     = "user"
+	AuditActorInternal = "internal"
+)
+
+// AuditEvent is one entry in the append-only audit trail.
+type AuditEvent struct {
+	ID          uuid.UUID              `json:"id"`
+	ActorType   string                 `json:"actor_type"`
+	ActorID     string                 `json:"actor_id,omitempty"`
+	Action      string                 `json:"action"`
+	SubjectType string                 `json:"subject_type,omitempty"`
+	SubjectID   string                 `json:"subject_id,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	IP          string                 `json:"ip,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// AuditEventFilter narrows the internal audit listing. Zero values are ignored.
+type AuditEventFilter struct {
+	SubjectType string
+	SubjectID   string
+	ActorID     string
+	Action      string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+// Unmatched transfer event statuses.
+const (
+	UnmatchedTransferEventPending  = "pending"
+	UnmatchedTransferEventReplayed = "replayed"
+)
+
+// UnmatchedTransferEvent is a transfer status event no transaction matched, parked
+// with its raw payload so it can be replayed later.
+type UnmatchedTransferEvent struct {
+	ID               uuid.UUID       `json:"id"`
+	AnchorTransferID string          `json:"anchor_transfer_id"`
+	EventKey         string          `json:"event_key"`
+	EventType        string          `json:"event_type"`
+	Payload          json.RawMessage `json:"payload"`
+	Status           string          `json:"status"`
+	ParkCount        int             `json:"park_count"`
+	ReplayAttempts   int             `json:"replay_attempts"`
+	LastError        string          `json:"last_error,omitempty"`
+	TransactionID    *uuid.UUID      `json:"transaction_id,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	ReplayedAt       *time.Time      `json:"replayed_at,omitempty"`
+}
+
+// Fee collection statuses.
+const (
+	FeeCollectionPending   = "pending"
+	FeeCollectionCompleted = "completed"
+	FeeCollectionFailed    = "failed"
+)
+
+// FeeCollection is the book transfer moving a transaction's fee to the admin account,
+// kept by its Anchor transfer ID so the transfer's status events can be applied.
+type FeeCollection struct {
+	ID                  uuid.UUID `json:"id"`
+	ParentTransactionID uuid.UUID `json:"parent_transaction_id"`
+	UserID              uuid.UUID `json:"user_id"`
+	Amount              int64     `json:"amount"`
+	AnchorTransferID    string    `json:"anchor_transfer_id"`
+	Status              string    `json:"status"`
+	FailureReason       string    `json:"failure_reason,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Transfer compensation kinds, named after the transfer that failed.
+const (
+	CompensationFeeCollection    = "fee_collection"
+	CompensationMoneyDropFunding = "money_drop_funding"
+)
+
+// TransferCompensation is a queued wallet credit for a user whose wallet was debited
+// for a fee collection or money drop funding transfer that then failed.
+type TransferCompensation struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Kind          string     `json:"kind"`
+	Amount        int64      `json:"amount"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// Reconciliation report statuses. A partial report could not read Anchor for some of
+// the accounts it checked.
+const (
+	ReconciliationCompleted = "completed"
+	ReconciliationPartial   = "partial"
+)
+
+// Reasons a reconciliation item did not match.
+const (
+	ReconciliationMissingInternal = "missing_internal" // on Anchor, not recorded here
+	ReconciliationMissingAnchor   = "missing_anchor"   // recorded here, unknown to Anchor
+	ReconciliationAmountMismatch  = "amount_mismatch"
+	ReconciliationLookupFailed    = "lookup_failed" // Anchor could not be asked about it
+)
+
+// Sources of internal reconciliation entries.
+const (
+	ReconciliationSourceTransaction   = "transaction"
+	ReconciliationSourceFeeCollection = "fee_collection"
+)
+
+// ReconciliationEntry is a transaction or fee collection with an Anchor transfer,
+// as compared against Anchor during reconciliation.
+type ReconciliationEntry struct {
+	ID               uuid.UUID
+	Source           string
+	AnchorTransferID string
+	Amount           int64
+}
+
+// ReconciliationItem is one transfer that did not match between Anchor and our
+// records. TransactionID and Amount are empty when nothing was recorded here, and
+// AnchorAmount when Anchor does not know the transfer.
+type ReconciliationItem struct {
+	AnchorTransferID string     `json:"anchor_transfer_id"`
+	Reason           string     `json:"reason"`
+	TransactionID    *uuid.UUID `json:"transaction_id,omitempty"`
+	Source           string     `json:"source,omitempty"`
+	Amount           int64      `json:"amount,omitempty"`
+	AnchorAccountID  string     `json:"anchor_account_id,omitempty"`
+	AnchorAmount     int64      `json:"anchor_amount,omitempty"`
+	AnchorStatus     string     `json:"anchor_status,omitempty"`
+}
+
+// ReconciliationReport is the outcome of comparing one day of Anchor transfers with
+// our transactions. SampleSize is 0 when every account was checked.
+type ReconciliationReport struct {
+	ID                uuid.UUID            `json:"id"`
+	PeriodStart       time.Time            `json:"period_start"`
+	PeriodEnd         time.Time            `json:"period_end"`
+	Status            string               `json:"status"`
+	SampleSize        int                  `json:"sample_size"`
+	AccountsChecked   int                  `json:"accounts_checked"`
+	AccountsFailed    int                  `json:"accounts_failed"`
+	AnchorEntries     int                  `json:"anchor_entries"`
+	InternalEntries   int                  `json:"internal_entries"`
+	Matched           int                  `json:"matched"`
+	UnmatchedAnchor   []ReconciliationItem `json:"unmatched_anchor"`
+	UnmatchedInternal []ReconciliationItem `json:"unmatched_internal"`
+	CreatedAt         time.Time            `json:"created_at"`
+}
@@ -32,20 +32,38 @@ var (
 	ErrAccountNotFound                     = errors.New("account not found")
 	ErrBeneficiaryNotFound                 = errors.New("beneficiary not found")
 	ErrInsufficientFunds                   = errors.New("insufficient funds")
+	ErrSendingRestricted                   = errors.New("sender account is not permitted to send funds")
+	ErrReceivingRestricted                 = errors.New("recipient account cannot receive funds at this time")
 	ErrPlatformFeeDelinquent               = errors.New("platform fee delinquent")
 	ErrTransactionNotFound                 = errors.New("transaction not found")
+	ErrTransactionAlreadyFinal             = errors.New("transaction is already completed or failed")
+	ErrTransactionNotCancellable           = errors.New("transaction can no longer be cancelled")
+	ErrTransactionNotPending               = errors.New("transaction is no longer pending")
+	ErrTransactionDisputeExists            = errors.New("transaction already has an open dispute")
+	ErrTransactionDisputeNotFound          = errors.New("transaction dispute not found")
+	ErrTransactionDisputeStatusChanged     = errors.New("transaction dispute status changed concurrently")
 	ErrTransactionPINNotSet                = errors.New("transaction pin not set")
+	ErrTransactionAttachmentLimit          = errors.New("transaction attachment limit reached")
 	ErrPaymentRequestNotFound              = errors.New("payment request not found")
 	ErrPaymentRequestNotReady              = errors.New("payment request is not payable")
+	ErrPaymentRequestInProcessing          = errors.New("payment request has a payment in progress")
+	ErrPaymentRequestShortCodeExists       = errors.New("payment request short code already exists")
+	ErrPaymentRequestSplitNotFound         = errors.New("payment request split not found")
 	ErrTransferListNotFound                = errors.New("transfer list not found")
 	ErrMoneyDropNotFound                   = errors.New("money drop not found")
+	ErrMoneyDropRefundExceedsTotal         = errors.New("money drop refund would exceed the drop total")
 	ErrMoneyDropClaimIdempotencyConflict   = errors.New("money drop claim idempotency key conflict")
 	ErrMoneyDropClaimIdempotencyInProgress = errors.New("money drop claim idempotency request in progress")
+	ErrShortLinkNotFound                   = errors.New("short link not found")
+	ErrShortLinkCodeExists                 = errors.New("short link code already exists")
+	ErrUnmatchedTransferEventNotFound      = errors.New("unmatched transfer event not found")
+	ErrReconciliationReportNotFound        = errors.New("reconciliation report not found")
+	ErrFeeCollectionNotFound               = errors.New("fee collection not found")
 )
 
 // PostgresRepository is a concrete implementation of the Repository interface for PostgreSQL.
 type PostgresRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 // NewPostgresRepository creates a new instance of PostgresRepository.
@@ -68,11 +86,25 @@ func (r *PostgresRepository) FindUserIDByClerkUserID(ctx context.Context, clerkU
 	return id, nil
 }
 
+// IsUserClosedByClerkUserID reports whether the user has closed their account.
+// Unknown users are reported as not closed.
+func (r *PostgresRepository) IsUserClosedByClerkUserID(ctx context.Context, clerkUserID string) (bool, error) {
+	var closed bool
+	err := r.db.QueryRow(ctx, "SELECT closed_at IS NOT NULL FROM users WHERE clerk_user_id = $1", clerkUserID).Scan(&closed)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return closed, nil
+}
+
 // FindUserByUsername retrieves a user from the database by their username.
 func (r *PostgresRepository) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
-	query := `SELECT id, btrim(username), full_name, allow_sending, anchor_customer_id FROM users WHERE lower(btrim(username)) = lower(btrim($1))`
-	err := r.db.QueryRow(ctx, query, username).Scan(&user.ID, &user.Username, &user.FullName, &user.AllowSending, &user.AnchorCustomerID)
+	query := `SELECT id, btrim(username), full_name, allow_sending, allow_receiving, anchor_customer_id FROM users WHERE lower(btrim(username)) = lower(btrim($1))`
+	err := r.db.QueryRow(ctx, query, username).Scan(&user.ID, &user.Username, &user.FullName, &user.AllowSending, &user.AllowReceiving, &user.AnchorCustomerID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrUserNotFound
@@ -82,11 +114,36 @@ func (r *PostgresRepository) FindUserByUsername(ctx context.Context, username st
 	return &user, nil
 }
 
+// FindCurrentUsernameByPreviousUsername returns the current username of the user who
+// gave up the given username within the last 30 days.
+func (r *PostgresRepository) FindCurrentUsernameByPreviousUsername(ctx context.Context, username string) (string, error) {
+	var current string
+	query := `
+		SELECT btrim(u.username)
+		FROM username_history h
+		JOIN users u ON u.id = h.user_id
+		WHERE lower(h.old_username) = lower(btrim($1))
+		  AND h.changed_at > NOW() - INTERVAL '30 days'
+		  AND u.username IS NOT NULL
+		  AND u.closed_at IS NULL
+		ORDER BY h.changed_at DESC
+		LIMIT 1
+	`
+	err := r.db.QueryRow(ctx, query, username).Scan(&current)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	return current, nil
+}
+
 // FindUserByID retrieves a user from the database by their ID.
 func (r *PostgresRepository) FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
 	var user domain.User
-	query := `SELECT id, btrim(username), full_name, allow_sending, anchor_customer_id FROM users WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Username, &user.FullName, &user.AllowSending, &user.AnchorCustomerID)
+	query := `SELECT id, btrim(username), full_name, allow_sending, allow_receiving, anchor_customer_id FROM users WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Username, &user.FullName, &user.AllowSending, &user.AllowReceiving, &user.AnchorCustomerID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrUserNotFound
@@ -184,8 +241,8 @@ func (r *PostgresRepository) ResetTransactionPINFailureState(ctx context.Context
 // FindAccountByUserID retrieves a user's primary account from the database.
 func (r *PostgresRepository) FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error) {
 	var account domain.Account
-	query := `SELECT id, user_id, anchor_account_id, balance FROM accounts WHERE user_id = $1 AND account_type = 'primary'`
-	err := r.db.QueryRow(ctx, query, userID).Scan(&account.ID, &account.UserID, &account.AnchorAccountID, &account.Balance)
+	query := `SELECT id, user_id, anchor_account_id, COALESCE(virtual_nuban, ''), balance, currency, updated_at FROM accounts WHERE user_id = $1 AND account_type = 'primary'`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&account.ID, &account.UserID, &account.AnchorAccountID, &account.AccountNumber, &account.Balance, &account.Currency, &account.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrAccountNotFound
@@ -195,35 +252,55 @@ func (r *PostgresRepository) FindAccountByUserID(ctx context.Context, userID uui
 	return &account, nil
 }
 
-// UpdateAccountBalance updates the balance for a user's account
-func (r *PostgresRepository) UpdateAccountBalance(ctx context.Context, userID uuid.UUID, balance int64) error {
-	query := `UPDATE accounts SET balance = $1, updated_at = NOW() WHERE user_id = $2 AND account_type = 'primary'`
-	result, err := r.db.Exec(ctx, query, balance, userID)
-	if err != nil {
-		return err
+// transactionHistoryQuery selects one user's transactions, newest first. The sender
+// and recipient sides are separate UNION ALL branches so each can use its
+// (party, created_at DESC) index; a single "sender_id = $1 OR recipient_id = $1"
+// predicate forces a bitmap OR plus a full sort. The recipient branch skips rows
+// where the user is also the sender so self-transfers are returned once. Range,
+// type and status predicates are only added when set, keeping the range usable as
+// index bounds.
+func transactionHistoryQuery(filter domain.TransactionHistoryFilter) (string, []interface{}) {
+	args := []interface{}{}
+	filterClause := ""
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		filterClause += fmt.Sprintf(" AND created_at >= $%d", len(args)+1)
 	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return ErrAccountNotFound
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		filterClause += fmt.Sprintf(" AND created_at < $%d", len(args)+1)
+	}
+	if len(filter.Types) > 0 {
+		args = append(args, filter.Types)
+		filterClause += fmt.Sprintf(" AND type = ANY($%d)", len(args)+1)
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		filterClause += fmt.Sprintf(" AND status = $%d", len(args)+1)
 	}
 
-	return nil
-}
-
-// FindTransactionsByUserID retrieves all transactions for a user (as sender or recipient).
-func (r *PostgresRepository) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
+	source := transactionHistorySource(filter.IncludeArchive)
 	query := `
 		SELECT id, anchor_transfer_id, sender_id, recipient_id, source_account_id, destination_account_id,
 		       destination_beneficiary_id, type, COALESCE(category, '') AS category, status, amount, fee,
-		       COALESCE(description, '') AS description,
-		       created_at, updated_at
-		FROM transactions
-		WHERE sender_id = $1 OR recipient_id = $1
+		       currency, COALESCE(description, '') AS description,
+		       created_at, updated_at, archived
+		FROM (
+			SELECT * FROM ` + source + ` WHERE sender_id = $1` + filterClause + `
+			UNION ALL
+			SELECT * FROM ` + source + ` WHERE recipient_id = $1 AND sender_id IS DISTINCT FROM $1` + filterClause + `
+		) history
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.Query(ctx, query, userID)
+	return query, args
+}
+
+// FindTransactionsByUserID retrieves a user's transactions (as sender or recipient)
+// matching filter, newest first.
+func (r *PostgresRepository) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	query, filterArgs := transactionHistoryQuery(filter)
+	rows, err := r.db.Query(ctx, query, append([]interface{}{userID}, filterArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +311,7 @@ func (r *PostgresRepository) FindTransactionsByUserID(ctx context.Context, userI
 		err := rows.Scan(
 			&tx.ID, &tx.AnchorTransferID, &tx.SenderID, &tx.RecipientID, &tx.SourceAccountID,
 			&tx.DestinationAccountID, &tx.DestinationBeneficiaryID, &tx.Type, &tx.Category,
-			&tx.Status, &tx.Amount, &tx.Fee, &tx.Description, &tx.CreatedAt, &tx.UpdatedAt,
+			&tx.Status, &tx.Amount, &tx.Fee, &tx.Currency, &tx.Description, &tx.CreatedAt, &tx.UpdatedAt, &tx.Archived,
 		)
 		if err != nil {
 			return nil, err
@@ -242,11 +319,12 @@ func (r *PostgresRepository) FindTransactionsByUserID(ctx context.Context, userI
 		transactions = append(transactions, tx)
 	}
 
-	return transactions, nil
+	return transactions, rows.Err()
 }
 
 // FindTransactionsBetweenUsers retrieves transactions where user and counterparty are the two parties.
-func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, userID uuid.UUID, counterpartyID uuid.UUID, limit int, offset int) ([]domain.Transaction, error) {
+// includeArchive also reads transactions_archive.
+func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, userID uuid.UUID, counterpartyID uuid.UUID, limit int, offset int, includeArchive bool) ([]domain.Transaction, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -261,9 +339,9 @@ func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, u
 	query := `
 		SELECT id, anchor_transfer_id, sender_id, recipient_id, source_account_id, destination_account_id,
 		       destination_beneficiary_id, type, COALESCE(category, '') AS category, status, amount, fee,
-		       COALESCE(description, '') AS description, COALESCE(transfer_type, '') AS transfer_type,
-		       failure_reason, anchor_session_id, anchor_reason, created_at, updated_at
-		FROM transactions
+		       currency, COALESCE(description, '') AS description, COALESCE(transfer_type, '') AS transfer_type,
+		       failure_reason, anchor_session_id, anchor_reason, created_at, updated_at, archived
+		FROM ` + transactionHistorySource(includeArchive) + `
 		WHERE
 		  (
 		    sender_id = $1 AND recipient_id = $2
@@ -296,6 +374,7 @@ func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, u
 			&tx.Status,
 			&tx.Amount,
 			&tx.Fee,
+			&tx.Currency,
 			&tx.Description,
 			&tx.TransferType,
 			&tx.FailureReason,
@@ -303,6 +382,7 @@ func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, u
 			&tx.AnchorReason,
 			&tx.CreatedAt,
 			&tx.UpdatedAt,
+			&tx.Archived,
 		)
 		if err != nil {
 			return nil, err
@@ -310,17 +390,18 @@ func (r *PostgresRepository) FindTransactionsBetweenUsers(ctx context.Context, u
 		transactions = append(transactions, tx)
 	}
 
-	return transactions, nil
+	return transactions, rows.Err()
 }
 
 // FindBeneficiaryByID retrieves a specific beneficiary owned by a user.
 func (r *PostgresRepository) FindBeneficiaryByID(ctx context.Context, beneficiaryID uuid.UUID, userID uuid.UUID) (*domain.Beneficiary, error) {
 	var beneficiary domain.Beneficiary
-	query := `SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, created_at, updated_at FROM beneficiaries WHERE id = $1 AND user_id = $2`
+	query := `SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at, created_at, updated_at FROM beneficiaries WHERE id = $1 AND user_id = $2`
 	err := r.db.QueryRow(ctx, query, beneficiaryID, userID).Scan(
 		&beneficiary.ID, &beneficiary.UserID, &beneficiary.AnchorCounterpartyID,
 		&beneficiary.AccountName, &beneficiary.AccountNumberMasked, &beneficiary.BankName,
-		&beneficiary.IsDefault, &beneficiary.CreatedAt, &beneficiary.UpdatedAt)
+		&beneficiary.IsDefault, &beneficiary.VerificationStatus, &beneficiary.LastVerifiedAt,
+		&beneficiary.CreatedAt, &beneficiary.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrBeneficiaryNotFound
@@ -334,7 +415,7 @@ func (r *PostgresRepository) FindBeneficiaryByID(ctx context.Context, beneficiar
 func (r *PostgresRepository) FindBeneficiariesByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Beneficiary, error) {
 	var beneficiaries []domain.Beneficiary
 	query := `
-		SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, created_at, updated_at 
+		SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at, created_at, updated_at 
 		FROM beneficiaries 
 		WHERE user_id = $1 
 		ORDER BY is_default DESC, created_at DESC
@@ -350,7 +431,8 @@ func (r *PostgresRepository) FindBeneficiariesByUserID(ctx context.Context, user
 		err := rows.Scan(
 			&beneficiary.ID, &beneficiary.UserID, &beneficiary.AnchorCounterpartyID,
 			&beneficiary.AccountName, &beneficiary.AccountNumberMasked, &beneficiary.BankName,
-			&beneficiary.IsDefault, &beneficiary.CreatedAt, &beneficiary.UpdatedAt)
+			&beneficiary.IsDefault, &beneficiary.VerificationStatus, &beneficiary.LastVerifiedAt,
+			&beneficiary.CreatedAt, &beneficiary.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -364,14 +446,15 @@ func (r *PostgresRepository) FindBeneficiariesByUserID(ctx context.Context, user
 func (r *PostgresRepository) FindDefaultBeneficiaryByUserID(ctx context.Context, userID uuid.UUID) (*domain.Beneficiary, error) {
 	var beneficiary domain.Beneficiary
 	query := `
-		SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, created_at, updated_at 
+		SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at, created_at, updated_at 
 		FROM beneficiaries 
 		WHERE user_id = $1 AND is_default = true
 	`
 	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&beneficiary.ID, &beneficiary.UserID, &beneficiary.AnchorCounterpartyID,
 		&beneficiary.AccountName, &beneficiary.AccountNumberMasked, &beneficiary.BankName,
-		&beneficiary.IsDefault, &beneficiary.CreatedAt, &beneficiary.UpdatedAt)
+		&beneficiary.IsDefault, &beneficiary.VerificationStatus, &beneficiary.LastVerifiedAt,
+		&beneficiary.CreatedAt, &beneficiary.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrBeneficiaryNotFound
@@ -388,6 +471,7 @@ func (r *PostgresRepository) IsUserDelinquent(ctx context.Context, userID uuid.U
 			SELECT 1
 			FROM platform_fee_invoices
 			WHERE user_id = $1
+			  AND amount > 0
 			  AND (
 				status = 'delinquent'
 				OR (status IN ('pending', 'failed') AND grace_until < NOW())
@@ -428,9 +512,10 @@ func (r *PostgresRepository) CreateTransaction(ctx context.Context, tx *domain.T
 			transfer_type,
 			failure_reason,
 			anchor_session_id,
-			anchor_reason
+			anchor_reason,
+			currency
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, COALESCE(NULLIF($18, ''), 'NGN'))
 	`
 	_, err := r.db.Exec(ctx, query,
 		tx.ID,
@@ -450,6 +535,7 @@ func (r *PostgresRepository) CreateTransaction(ctx context.Context, tx *domain.T
 		tx.FailureReason,
 		tx.AnchorSessionID,
 		tx.AnchorReason,
+		tx.Currency,
 	)
 	return err
 }
@@ -479,8 +565,10 @@ func (r *PostgresRepository) UpdateTransactionMetadata(ctx context.Context, tran
 			failure_reason = COALESCE($4, failure_reason),
 			anchor_session_id = COALESCE($5, anchor_session_id),
 			anchor_reason = COALESCE($6, anchor_reason),
+			routing_decision = COALESCE($7, routing_decision),
+			routing_reason = COALESCE($8, routing_reason),
 			updated_at = NOW()
-		WHERE id = $7
+		WHERE id = $9
 	`
 	_, err := r.db.Exec(ctx, query,
 		metadata.Status,
@@ -489,6 +577,8 @@ func (r *PostgresRepository) UpdateTransactionMetadata(ctx context.Context, tran
 		metadata.FailureReason,
 		metadata.AnchorSessionID,
 		metadata.AnchorReason,
+		metadata.RoutingDecision,
+		metadata.RoutingReason,
 		transactionID,
 	)
 	return err
@@ -499,7 +589,7 @@ func (r *PostgresRepository) FindTransactionByAnchorTransferID(ctx context.Conte
 		SELECT id, anchor_transfer_id, sender_id, recipient_id, source_account_id,
 		       destination_account_id, destination_beneficiary_id, type, category, status,
 		       amount, fee, description, transfer_type, failure_reason, anchor_session_id,
-		       anchor_reason, created_at, updated_at
+		       anchor_reason, currency, created_at, updated_at
 		FROM transactions
 		WHERE anchor_transfer_id = $1
 	`
@@ -522,6 +612,7 @@ func (r *PostgresRepository) FindTransactionByAnchorTransferID(ctx context.Conte
 		&tx.FailureReason,
 		&tx.AnchorSessionID,
 		&tx.AnchorReason,
+		&tx.Currency,
 		&tx.CreatedAt,
 		&tx.UpdatedAt,
 	)
@@ -588,6 +679,7 @@ func (r *PostgresRepository) FindPendingMoneyDropClaimByAnchorParticipantsAndAmo
 			&tx.FailureReason,
 			&tx.AnchorSessionID,
 			&tx.AnchorReason,
+			&tx.Currency,
 			&tx.CreatedAt,
 			&tx.UpdatedAt,
 		); err != nil {
@@ -609,14 +701,21 @@ func (r *PostgresRepository) FindPendingMoneyDropClaimByAnchorParticipantsAndAmo
 	return &matches[0], nil
 }
 
+// FindTransactionByID looks a transaction up in the live table first and then in
+// transactions_archive, so links to archived transactions keep resolving.
 func (r *PostgresRepository) FindTransactionByID(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error) {
 	query := `
         SELECT id, anchor_transfer_id, sender_id, recipient_id, source_account_id,
                destination_account_id, destination_beneficiary_id, type, category, status,
                amount, fee, description, transfer_type, failure_reason, anchor_session_id,
-               anchor_reason, created_at, updated_at
-        FROM transactions
-        WHERE id = $1
+               anchor_reason, currency, COALESCE(routing_decision, ''),
+               COALESCE(routing_reason::text, ''), created_at, updated_at, archived
+        FROM (
+            SELECT *, FALSE AS archived FROM transactions WHERE id = $1
+            UNION ALL
+            SELECT *, TRUE AS archived FROM transactions_archive WHERE id = $1
+        ) t
+        LIMIT 1
     `
 	var tx domain.Transaction
 	err := r.db.QueryRow(ctx, query, transactionID).Scan(
@@ -637,8 +736,12 @@ func (r *PostgresRepository) FindTransactionByID(ctx context.Context, transactio
 		&tx.FailureReason,
 		&tx.AnchorSessionID,
 		&tx.AnchorReason,
+		&tx.Currency,
+		&tx.RoutingDecision,
+		&tx.RoutingReason,
 		&tx.CreatedAt,
 		&tx.UpdatedAt,
+		&tx.Archived,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -655,7 +758,7 @@ func (r *PostgresRepository) FindLikelyPaymentRequestSettlementTransaction(ctx c
         SELECT id, anchor_transfer_id, sender_id, recipient_id, source_account_id,
                destination_account_id, destination_beneficiary_id, type, category, status,
                amount, fee, description, transfer_type, failure_reason, anchor_session_id,
-               anchor_reason, created_at, updated_at
+               anchor_reason, currency, created_at, updated_at
         FROM transactions
         WHERE sender_id = $1
           AND recipient_id = $2
@@ -699,18 +802,112 @@ func (r *PostgresRepository) FindLikelyPaymentRequestSettlementTransaction(ctx c
 	return &tx, nil
 }
 
-func (r *PostgresRepository) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) error {
+// RefundTransactionFee credits fee back to userID's primary account and zeroes the
+// transaction's stored fee in one database transaction. The fee is only refunded while
+// the stored fee is still positive, so a redelivered failure cannot credit it twice;
+// refunded is false when it had already been refunded (or was never charged). If the
+// user has no primary account nothing changes and ErrAccountNotFound is returned.
+func (r *PostgresRepository) RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) (bool, error) {
 	if fee <= 0 {
-		return nil
+		return false, nil
 	}
-	_, err := r.db.Exec(ctx, "UPDATE accounts SET balance = balance + $1 WHERE user_id = $2 AND account_type = 'primary'", fee, userID)
-	return err
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, "UPDATE transactions SET fee = 0, updated_at = NOW() WHERE id = $1 AND fee > 0", transactionID)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	result, err = tx.Exec(ctx, "UPDATE accounts SET balance = balance + $2 WHERE user_id = $1 AND account_type = 'primary'", userID, fee)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, ErrAccountNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
+// MarkTransactionAsFailed moves a pending or processing transaction to failed and
+// records why. A completed, failed or cancelled transaction is left untouched and
+// ErrTransactionAlreadyFinal is returned, so a caller refunding alongside the status
+// change (in the same database transaction) cannot refund twice. An empty
+// anchorTransferID keeps the stored one.
 func (r *PostgresRepository) MarkTransactionAsFailed(ctx context.Context, transactionID uuid.UUID, anchorTransferID, failureReason string) error {
-	query := `UPDATE transactions SET status = 'failed', anchor_transfer_id = COALESCE($2, anchor_transfer_id), failure_reason = COALESCE($3, failure_reason), updated_at = NOW() WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, transactionID, anchorTransferID, failureReason)
-	return err
+	query := `
+		UPDATE transactions
+		SET status = 'failed',
+		    anchor_transfer_id = COALESCE(NULLIF($2, ''), anchor_transfer_id),
+		    failure_reason = $3,
+		    updated_at = NOW()
+		WHERE id = $1 AND status NOT IN ('completed', 'failed', 'cancelled')
+	`
+	result, err := r.db.Exec(ctx, query, transactionID, anchorTransferID, failureReason)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrTransactionAlreadyFinal
+	}
+	return nil
+}
+
+// CancelPendingTransaction moves one of senderID's pending transactions to cancelled
+// and returns its amount plus fee for the caller to refund. The status change is a
+// compare-and-set: a transaction that has progressed, has an Anchor transfer, or was
+// claimed by ClaimTransactionForTransfer is left untouched and
+// ErrTransactionNotCancellable is returned.
+func (r *PostgresRepository) CancelPendingTransaction(ctx context.Context, transactionID uuid.UUID, senderID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE transactions
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1
+		  AND sender_id = $2
+		  AND status = 'pending'
+		  AND anchor_transfer_id IS NULL
+		  AND transfer_initiated_at IS NULL
+		RETURNING amount + fee
+	`
+	var refund int64
+	if err := r.db.QueryRow(ctx, query, transactionID, senderID).Scan(&refund); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrTransactionNotCancellable
+		}
+		return 0, err
+	}
+	return refund, nil
+}
+
+// ClaimTransactionForTransfer stamps transfer_initiated_at on a pending transaction
+// before its fee or transfer is sent to Anchor, closing the cancellation window. It
+// serializes with CancelPendingTransaction on the row, so exactly one of them wins;
+// ErrTransactionNotPending is returned when the transaction was cancelled first.
+func (r *PostgresRepository) ClaimTransactionForTransfer(ctx context.Context, transactionID uuid.UUID) error {
+	query := `
+		UPDATE transactions
+		SET transfer_initiated_at = COALESCE(transfer_initiated_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := r.db.Exec(ctx, query, transactionID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrTransactionNotPending
+	}
+	return nil
 }
 
 func (r *PostgresRepository) MarkTransactionAsCompleted(ctx context.Context, transactionID uuid.UUID, anchorTransferID string) error {
@@ -726,53 +923,107 @@ func (r *PostgresRepository) UpdateTransactionDestinations(ctx context.Context,
 	return err
 }
 
-// DebitWallet performs an atomic debit operation on a user's account.
+// DebitWallet performs an atomic debit operation on a user's account. The balance check
+// and the debit are one conditional UPDATE, so concurrent debits can never take the
+// balance below zero.
 func (r *PostgresRepository) DebitWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
-	tx, err := r.db.Begin(ctx)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback(ctx)
+	return debitPrimaryAccount(ctx, r.db, userID, amount)
+}
 
+// debitPrimaryAccount subtracts amount from the user's primary balance only if the
+// balance covers it. When no row is updated it tells a missing account apart from an
+// insufficient balance; the chk_accounts_balance_non_negative constraint is the backstop.
+func debitPrimaryAccount(ctx context.Context, db dbtx, userID uuid.UUID, amount int64) error {
 	var balance int64
-	// Use FOR UPDATE to lock the row, preventing race conditions.
-	err = tx.QueryRow(ctx, "SELECT balance FROM accounts WHERE user_id = $1 AND account_type = 'primary' FOR UPDATE", userID).Scan(&balance)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return ErrAccountNotFound
-		}
-		return err
+	err := db.QueryRow(ctx, `
+		UPDATE accounts
+		SET balance = balance - $1, updated_at = NOW()
+		WHERE user_id = $2 AND account_type = 'primary' AND balance >= $1
+		RETURNING balance
+	`, amount, userID).Scan(&balance)
+	if err == nil {
+		return nil
 	}
-
-	if balance < amount {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23514" {
 		return ErrInsufficientFunds
 	}
-
-	_, err = tx.Exec(ctx, "UPDATE accounts SET balance = balance - $1 WHERE user_id = $2 AND account_type = 'primary'", amount, userID)
-	if err != nil {
+	if !errors.Is(err, pgx.ErrNoRows) {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	var exists bool
+	if err := db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM accounts WHERE user_id = $1 AND account_type = 'primary')", userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAccountNotFound
+	}
+	return ErrInsufficientFunds
 }
 
-// CreditWallet performs an atomic credit operation on a user's account.
-func (r *PostgresRepository) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+// DebitWalletForP2PTransfer debits the sender like DebitWallet, but first re-reads the
+// sender's allow_sending and the recipient's allow_receiving flags under a shared row
+// lock. A concurrent freeze (which updates the users row) is therefore ordered either
+// entirely before the debit, in which case the transfer is rejected, or after it.
+func (r *PostgresRepository) DebitWalletForP2PTransfer(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	// Use FOR UPDATE to lock the row, preventing race conditions.
-	_, err = tx.Exec(ctx, "UPDATE accounts SET balance = balance + $1 WHERE user_id = $2 AND account_type = 'primary'", amount, userID)
+	// Lock in id order so concurrent transfers between the same pair cannot deadlock
+	// against a freeze waiting on either row.
+	rows, err := tx.Query(ctx, `
+		SELECT id, allow_sending, allow_receiving
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR SHARE
+	`, []uuid.UUID{senderID, recipientID})
 	if err != nil {
 		return err
 	}
+	found := 0
+	for rows.Next() {
+		var id uuid.UUID
+		var allowSending, allowReceiving bool
+		if err := rows.Scan(&id, &allowSending, &allowReceiving); err != nil {
+			rows.Close()
+			return err
+		}
+		found++
+		if id == senderID && !allowSending {
+			rows.Close()
+			return ErrSendingRestricted
+		}
+		if id == recipientID && !allowReceiving {
+			rows.Close()
+			return ErrReceivingRestricted
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if found != 2 {
+		return ErrUserNotFound
+	}
+
+	if err := debitPrimaryAccount(ctx, tx, senderID, amount); err != nil {
+		return err
+	}
 
 	return tx.Commit(ctx)
 }
 
+// CreditWallet performs an atomic credit operation on a user's account.
+func (r *PostgresRepository) CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error {
+	_, err := r.db.Exec(ctx, "UPDATE accounts SET balance = balance + $1, updated_at = NOW() WHERE user_id = $2 AND account_type = 'primary'", amount, userID)
+	return err
+}
+
 // CreateTransferBatch inserts a transfer batch audit record.
 func (r *PostgresRepository) CreateTransferBatch(ctx context.Context, batch *domain.TransferBatch) error {
 	query := `
@@ -981,7 +1232,7 @@ func (r *PostgresRepository) FindOrCreateDefaultBeneficiary(ctx context.Context,
 	// First, try to get an existing default beneficiary
 	var beneficiary domain.Beneficiary
 	query := `
-        SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, created_at, updated_at 
+        SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at, created_at, updated_at 
         FROM beneficiaries 
         WHERE user_id = $1 AND is_default = true
         LIMIT 1
@@ -989,7 +1240,8 @@ func (r *PostgresRepository) FindOrCreateDefaultBeneficiary(ctx context.Context,
 	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&beneficiary.ID, &beneficiary.UserID, &beneficiary.AnchorCounterpartyID,
 		&beneficiary.AccountName, &beneficiary.AccountNumberMasked, &beneficiary.BankName,
-		&beneficiary.IsDefault, &beneficiary.CreatedAt, &beneficiary.UpdatedAt)
+		&beneficiary.IsDefault, &beneficiary.VerificationStatus, &beneficiary.LastVerifiedAt,
+		&beneficiary.CreatedAt, &beneficiary.UpdatedAt)
 
 	if err == nil {
 		// Found an existing default beneficiary
@@ -1003,7 +1255,7 @@ func (r *PostgresRepository) FindOrCreateDefaultBeneficiary(ctx context.Context,
 	// No default beneficiary found, get the first beneficiary (which should be the default)
 	// This handles edge cases where the default flag might be missing
 	query = `
-        SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, created_at, updated_at 
+        SELECT id, user_id, anchor_counterparty_id, account_name, account_number_masked, bank_name, is_default, verification_status, last_verified_at, created_at, updated_at 
         FROM beneficiaries 
         WHERE user_id = $1 
         ORDER BY created_at ASC 
@@ -1012,7 +1264,8 @@ func (r *PostgresRepository) FindOrCreateDefaultBeneficiary(ctx context.Context,
 	err = r.db.QueryRow(ctx, query, userID).Scan(
 		&beneficiary.ID, &beneficiary.UserID, &beneficiary.AnchorCounterpartyID,
 		&beneficiary.AccountName, &beneficiary.AccountNumberMasked, &beneficiary.BankName,
-		&beneficiary.IsDefault, &beneficiary.CreatedAt, &beneficiary.UpdatedAt)
+		&beneficiary.IsDefault, &beneficiary.VerificationStatus, &beneficiary.LastVerifiedAt,
+		&beneficiary.CreatedAt, &beneficiary.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -1095,54 +1348,72 @@ func (r *PostgresRepository) SetDefaultBeneficiary(ctx context.Context, userID u
 	return tx.Commit(ctx)
 }
 
+// UpdateBeneficiaryVerification records the outcome of re-resolving a beneficiary's
+// account. An empty accountName keeps the stored name.
+func (r *PostgresRepository) UpdateBeneficiaryVerification(ctx context.Context, beneficiaryID uuid.UUID, status string, accountName string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE beneficiaries
+		SET verification_status = $2,
+		    last_verified_at = NOW(),
+		    account_name = COALESCE(NULLIF($3, ''), account_name),
+		    updated_at = NOW()
+		WHERE id = $1
+	`, beneficiaryID, status, accountName)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrBeneficiaryNotFound
+	}
+	return nil
+}
+
 // FindOrCreateReceivingPreference finds or creates a user's receiving preference.
 // Default is to use external account (beneficiary) if available, otherwise internal wallet.
+// Beneficiaries are only looked at when no preference row exists yet.
 func (r *PostgresRepository) FindOrCreateReceivingPreference(ctx context.Context, userID uuid.UUID) (*domain.UserReceivingPreference, error) {
 	var preference domain.UserReceivingPreference
 
-	// Try to find existing preference
 	query := `SELECT user_id, use_external_account, default_beneficiary_id, created_at, updated_at FROM user_receiving_preferences WHERE user_id = $1`
 	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&preference.UserID, &preference.UseExternalAccount, &preference.DefaultBeneficiaryID,
 		&preference.CreatedAt, &preference.UpdatedAt)
-
 	if err == nil {
 		return &preference, nil
 	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
 
-	if err == pgx.ErrNoRows {
-		// Create default preference: use external account if user has beneficiaries
-		beneficiaries, err := r.FindBeneficiariesByUserID(ctx, userID)
-		if err != nil {
-			return nil, err
-		}
-
-		useExternal := len(beneficiaries) > 0
-		var defaultBeneficiaryID *uuid.UUID
-		if useExternal && len(beneficiaries) > 0 {
-			defaultBeneficiaryID = &beneficiaries[0].ID
-		}
-
-		insertQuery := `
-            INSERT INTO user_receiving_preferences (user_id, use_external_account, default_beneficiary_id)
-            VALUES ($1, $2, $3)
-        `
-		_, err = r.db.Exec(ctx, insertQuery, userID, useExternal, defaultBeneficiaryID)
-		if err != nil {
-			return nil, err
-		}
+	// Create default preference: use the oldest beneficiary if the user has one.
+	var defaultBeneficiaryID *uuid.UUID
+	var beneficiaryID uuid.UUID
+	err = r.db.QueryRow(ctx, `SELECT id FROM beneficiaries WHERE user_id = $1 ORDER BY created_at ASC LIMIT 1`, userID).Scan(&beneficiaryID)
+	switch {
+	case err == nil:
+		defaultBeneficiaryID = &beneficiaryID
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+	useExternal := defaultBeneficiaryID != nil
 
-		preference = domain.UserReceivingPreference{
-			UserID:               userID,
-			UseExternalAccount:   useExternal,
-			DefaultBeneficiaryID: defaultBeneficiaryID,
-			CreatedAt:            time.Now(),
-			UpdatedAt:            time.Now(),
-		}
-		return &preference, nil
+	insertQuery := `
+        INSERT INTO user_receiving_preferences (user_id, use_external_account, default_beneficiary_id)
+        VALUES ($1, $2, $3)
+    `
+	if _, err := r.db.Exec(ctx, insertQuery, userID, useExternal, defaultBeneficiaryID); err != nil {
+		return nil, err
 	}
 
-	return nil, err
+	now := time.Now()
+	preference = domain.UserReceivingPreference{
+		UserID:               userID,
+		UseExternalAccount:   useExternal,
+		DefaultBeneficiaryID: defaultBeneficiaryID,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	return &preference, nil
 }
 
 // UpdateReceivingPreference updates a user's receiving preference.
@@ -1173,7 +1444,10 @@ func (r *PostgresRepository) UpdateReceivingPreference(ctx context.Context, user
 	return err
 }
 
-// CreatePaymentRequest inserts a new payment request record into the database.
+// CreatePaymentRequest inserts a new payment request record into the database. It
+// returns ErrPaymentRequestShortCodeExists when the short code is already taken so the
+// caller can retry with a fresh code; the insert does not fail, so a transaction
+// around it stays usable.
 func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentRequest, error) {
 	query := `
         INSERT INTO payment_requests (
@@ -1186,10 +1460,14 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
             recipient_username_snapshot,
             recipient_full_name_snapshot,
             amount,
+            allow_partial,
             description,
-            image_url
+            image_url,
+            short_code,
+            split_id
         )
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+        ON CONFLICT (short_code) WHERE short_code IS NOT NULL DO NOTHING
         RETURNING
             id,
             creator_id,
@@ -1200,6 +1478,10 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
             recipient_username_snapshot,
             recipient_full_name_snapshot,
             amount,
+            allow_partial,
+            amount_paid,
+            short_code,
+            split_id,
             description,
             image_url,
             fulfilled_by_user_id,
@@ -1207,6 +1489,7 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
             processing_started_at,
             responded_at,
             declined_reason,
+            declined_at,
             deleted_at,
             created_at,
             updated_at
@@ -1224,8 +1507,11 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
 		req.RecipientUsername,
 		req.RecipientFullName,
 		req.Amount,
+		req.AllowPartial,
 		req.Description,
 		req.ImageURL,
+		req.ShortCode,
+		req.SplitID,
 	).Scan(
 		&createdRequest.ID,
 		&createdRequest.CreatorID,
@@ -1236,6 +1522,10 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
 		&createdRequest.RecipientUsername,
 		&createdRequest.RecipientFullName,
 		&createdRequest.Amount,
+		&createdRequest.AllowPartial,
+		&createdRequest.AmountPaid,
+		&createdRequest.ShortCode,
+		&createdRequest.SplitID,
 		&createdRequest.Description,
 		&createdRequest.ImageURL,
 		&createdRequest.FulfilledByUserID,
@@ -1243,16 +1533,68 @@ func (r *PostgresRepository) CreatePaymentRequest(ctx context.Context, req *doma
 		&createdRequest.ProcessingStarted,
 		&createdRequest.RespondedAt,
 		&createdRequest.DeclinedReason,
+		&createdRequest.DeclinedAt,
 		&createdRequest.DeletedAt,
 		&createdRequest.CreatedAt,
 		&createdRequest.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestShortCodeExists
+		}
 		return nil, err
 	}
 	return &createdRequest, nil
 }
 
+// GetPublicPaymentRequestByID returns a payment request for its public pay page. Only
+// the fields a pay page shows are loaded.
+func (r *PostgresRepository) GetPublicPaymentRequestByID(ctx context.Context, requestID uuid.UUID) (*domain.PaymentRequest, error) {
+	return r.getPublicPaymentRequest(ctx, "pr.id = $1", requestID)
+}
+
+// GetPublicPaymentRequestByShortCode is GetPublicPaymentRequestByID keyed by the
+// request's short code.
+func (r *PostgresRepository) GetPublicPaymentRequestByShortCode(ctx context.Context, code string) (*domain.PaymentRequest, error) {
+	return r.getPublicPaymentRequest(ctx, "pr.short_code = $1", code)
+}
+
+func (r *PostgresRepository) getPublicPaymentRequest(ctx context.Context, condition string, arg interface{}) (*domain.PaymentRequest, error) {
+	query := `
+        SELECT
+            pr.id,
+            btrim(cu.username) AS creator_username,
+            pr.status,
+            pr.title,
+            pr.amount,
+            pr.amount_paid,
+            pr.short_code,
+            pr.description
+        FROM payment_requests pr
+        LEFT JOIN users cu ON cu.id = pr.creator_id
+        WHERE ` + condition + `
+          AND pr.deleted_at IS NULL
+    `
+	var request domain.PaymentRequest
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&request.ID,
+		&request.CreatorUsername,
+		&request.Status,
+		&request.Title,
+		&request.Amount,
+		&request.AmountPaid,
+		&request.ShortCode,
+		&request.Description,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
 // ListPaymentRequestsByCreator retrieves all payment requests created by a specific user.
 func (r *PostgresRepository) ListPaymentRequestsByCreator(ctx context.Context, creatorID uuid.UUID, opts domain.PaymentRequestListOptions) ([]domain.PaymentRequest, error) {
 	limit := opts.Limit
@@ -1280,6 +1622,10 @@ func (r *PostgresRepository) ListPaymentRequestsByCreator(ctx context.Context, c
             COALESCE(NULLIF(btrim(pr.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(pr.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             pr.amount,
+            pr.allow_partial,
+            pr.amount_paid,
+            pr.short_code,
+            pr.split_id,
             pr.description,
             pr.image_url,
             pr.fulfilled_by_user_id,
@@ -1287,6 +1633,7 @@ func (r *PostgresRepository) ListPaymentRequestsByCreator(ctx context.Context, c
             pr.processing_started_at,
             pr.responded_at,
             pr.declined_reason,
+            pr.declined_at,
             pr.deleted_at,
             pr.created_at,
             pr.updated_at
@@ -1338,6 +1685,10 @@ func (r *PostgresRepository) ListPaymentRequestsByCreator(ctx context.Context, c
 			&request.RecipientUsername,
 			&request.RecipientFullName,
 			&request.Amount,
+			&request.AllowPartial,
+			&request.AmountPaid,
+			&request.ShortCode,
+			&request.SplitID,
 			&request.Description,
 			&request.ImageURL,
 			&request.FulfilledByUserID,
@@ -1345,6 +1696,7 @@ func (r *PostgresRepository) ListPaymentRequestsByCreator(ctx context.Context, c
 			&request.ProcessingStarted,
 			&request.RespondedAt,
 			&request.DeclinedReason,
+			&request.DeclinedAt,
 			&request.DeletedAt,
 			&request.CreatedAt,
 			&request.UpdatedAt,
@@ -1373,6 +1725,10 @@ func (r *PostgresRepository) GetPaymentRequestByID(ctx context.Context, requestI
             COALESCE(NULLIF(btrim(pr.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(pr.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             pr.amount,
+            pr.allow_partial,
+            pr.amount_paid,
+            pr.short_code,
+            pr.split_id,
             pr.description,
             pr.image_url,
             pr.fulfilled_by_user_id,
@@ -1380,6 +1736,7 @@ func (r *PostgresRepository) GetPaymentRequestByID(ctx context.Context, requestI
             pr.processing_started_at,
             pr.responded_at,
             pr.declined_reason,
+            pr.declined_at,
             pr.deleted_at,
             pr.created_at,
             pr.updated_at
@@ -1403,6 +1760,10 @@ func (r *PostgresRepository) GetPaymentRequestByID(ctx context.Context, requestI
 		&request.RecipientUsername,
 		&request.RecipientFullName,
 		&request.Amount,
+		&request.AllowPartial,
+		&request.AmountPaid,
+		&request.ShortCode,
+		&request.SplitID,
 		&request.Description,
 		&request.ImageURL,
 		&request.FulfilledByUserID,
@@ -1410,6 +1771,7 @@ func (r *PostgresRepository) GetPaymentRequestByID(ctx context.Context, requestI
 		&request.ProcessingStarted,
 		&request.RespondedAt,
 		&request.DeclinedReason,
+		&request.DeclinedAt,
 		&request.DeletedAt,
 		&request.CreatedAt,
 		&request.UpdatedAt,
@@ -1423,20 +1785,41 @@ func (r *PostgresRepository) GetPaymentRequestByID(ctx context.Context, requestI
 	return &request, nil
 }
 
-// DeletePaymentRequest soft-deletes a creator-owned payment request.
-func (r *PostgresRepository) DeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error) {
+// SoftDeletePaymentRequest soft-deletes a creator-owned payment request. It reports
+// false when the creator has no such request or it was already deleted, and returns
+// ErrPaymentRequestInProcessing while a payment of the request is in flight.
+func (r *PostgresRepository) SoftDeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error) {
 	query := `
-        UPDATE payment_requests
-        SET deleted_at = NOW(), updated_at = NOW()
-        WHERE id = $1
-          AND creator_id = $2
-          AND deleted_at IS NULL
+        WITH target AS (
+            SELECT id, status
+            FROM payment_requests
+            WHERE id = $1
+              AND creator_id = $2
+              AND deleted_at IS NULL
+            FOR UPDATE
+        ), deleted AS (
+            UPDATE payment_requests pr
+            SET deleted_at = NOW(), updated_at = NOW()
+            FROM target
+            WHERE pr.id = target.id
+              AND target.status <> 'processing'
+            RETURNING pr.id
+        )
+        SELECT target.status::text, EXISTS (SELECT 1 FROM deleted)
+        FROM target
     `
-	tag, err := r.db.Exec(ctx, query, requestID, creatorID)
-	if err != nil {
+	var status string
+	var deleted bool
+	if err := r.db.QueryRow(ctx, query, requestID, creatorID).Scan(&status, &deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
 		return false, err
 	}
-	return tag.RowsAffected() > 0, nil
+	if !deleted && status == "processing" {
+		return false, ErrPaymentRequestInProcessing
+	}
+	return deleted, nil
 }
 
 // ListIncomingPaymentRequests retrieves individual requests where the authenticated user is the recipient.
@@ -1466,6 +1849,10 @@ func (r *PostgresRepository) ListIncomingPaymentRequests(ctx context.Context, re
             COALESCE(NULLIF(btrim(pr.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(pr.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             pr.amount,
+            pr.allow_partial,
+            pr.amount_paid,
+            pr.short_code,
+            pr.split_id,
             pr.description,
             pr.image_url,
             pr.fulfilled_by_user_id,
@@ -1473,6 +1860,7 @@ func (r *PostgresRepository) ListIncomingPaymentRequests(ctx context.Context, re
             pr.processing_started_at,
             pr.responded_at,
             pr.declined_reason,
+            pr.declined_at,
             pr.deleted_at,
             pr.created_at,
             pr.updated_at
@@ -1499,8 +1887,9 @@ func (r *PostgresRepository) ListIncomingPaymentRequests(ctx context.Context, re
             COALESCE(btrim(cu.username), '') ILIKE '%%' || $%d || '%%'
             OR COALESCE(cu.full_name, '') ILIKE '%%' || $%d || '%%'
             OR pr.title ILIKE '%%' || $%d || '%%'
+            OR COALESCE(pr.description, '') ILIKE '%%' || $%d || '%%'
           )
-        `, argPos, argPos, argPos)
+        `, argPos, argPos, argPos, argPos)
 		args = append(args, search)
 		argPos++
 	}
@@ -1532,6 +1921,10 @@ func (r *PostgresRepository) ListIncomingPaymentRequests(ctx context.Context, re
 			&item.RecipientUsername,
 			&item.RecipientFullName,
 			&item.Amount,
+			&item.AllowPartial,
+			&item.AmountPaid,
+			&item.ShortCode,
+			&item.SplitID,
 			&item.Description,
 			&item.ImageURL,
 			&item.FulfilledByUserID,
@@ -1539,6 +1932,7 @@ func (r *PostgresRepository) ListIncomingPaymentRequests(ctx context.Context, re
 			&item.ProcessingStarted,
 			&item.RespondedAt,
 			&item.DeclinedReason,
+			&item.DeclinedAt,
 			&item.DeletedAt,
 			&item.CreatedAt,
 			&item.UpdatedAt,
@@ -1565,6 +1959,10 @@ func (r *PostgresRepository) GetIncomingPaymentRequestByID(ctx context.Context,
             COALESCE(NULLIF(btrim(pr.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(pr.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             pr.amount,
+            pr.allow_partial,
+            pr.amount_paid,
+            pr.short_code,
+            pr.split_id,
             pr.description,
             pr.image_url,
             pr.fulfilled_by_user_id,
@@ -1572,6 +1970,7 @@ func (r *PostgresRepository) GetIncomingPaymentRequestByID(ctx context.Context,
             pr.processing_started_at,
             pr.responded_at,
             pr.declined_reason,
+            pr.declined_at,
             pr.deleted_at,
             pr.created_at,
             pr.updated_at
@@ -1597,6 +1996,10 @@ func (r *PostgresRepository) GetIncomingPaymentRequestByID(ctx context.Context,
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -1604,6 +2007,7 @@ func (r *PostgresRepository) GetIncomingPaymentRequestByID(ctx context.Context,
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -1617,8 +2021,10 @@ func (r *PostgresRepository) GetIncomingPaymentRequestByID(ctx context.Context,
 	return &item, nil
 }
 
-// ClaimIncomingPaymentRequestForPayment atomically moves an incoming request into processing state.
-func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error) {
+// ClaimIncomingPaymentRequestForPayment atomically moves an incoming request into
+// processing state for a payment of amount. The claim fails with
+// ErrPaymentRequestNotReady if amount is more than the unpaid balance.
+func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, amount int64) (*domain.PaymentRequest, error) {
 	query := `
         WITH claimed AS (
             UPDATE payment_requests
@@ -1630,9 +2036,14 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
               AND recipient_user_id = $2
               AND request_type = 'individual'
               AND deleted_at IS NULL
+              AND amount_paid + $3 <= amount
               AND (
                 status = 'pending'
-                OR (status = 'processing' AND processing_started_at < NOW() - INTERVAL '5 minutes')
+                OR (
+                    status = 'processing'
+                    AND settled_transaction_id IS NULL
+                    AND processing_started_at < NOW() - INTERVAL '5 minutes'
+                )
               )
             RETURNING *
         )
@@ -1648,6 +2059,10 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
             COALESCE(NULLIF(btrim(c.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(c.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             c.amount,
+            c.allow_partial,
+            c.amount_paid,
+            c.short_code,
+            c.split_id,
             c.description,
             c.image_url,
             c.fulfilled_by_user_id,
@@ -1655,6 +2070,7 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
             c.processing_started_at,
             c.responded_at,
             c.declined_reason,
+            c.declined_at,
             c.deleted_at,
             c.created_at,
             c.updated_at
@@ -1664,7 +2080,7 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
     `
 
 	var item domain.PaymentRequest
-	err := r.db.QueryRow(ctx, query, requestID, recipientID).Scan(
+	err := r.db.QueryRow(ctx, query, requestID, recipientID, amount).Scan(
 		&item.ID,
 		&item.CreatorID,
 		&item.CreatorUsername,
@@ -1676,6 +2092,10 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -1683,6 +2103,7 @@ func (r *PostgresRepository) ClaimIncomingPaymentRequestForPayment(ctx context.C
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -1723,6 +2144,10 @@ func (r *PostgresRepository) AttachProcessingPaymentRequestSettlementTransaction
             COALESCE(NULLIF(btrim(u.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(u.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             u.amount,
+            u.allow_partial,
+            u.amount_paid,
+            u.short_code,
+            u.split_id,
             u.description,
             u.image_url,
             u.fulfilled_by_user_id,
@@ -1730,6 +2155,7 @@ func (r *PostgresRepository) AttachProcessingPaymentRequestSettlementTransaction
             u.processing_started_at,
             u.responded_at,
             u.declined_reason,
+            u.declined_at,
             u.deleted_at,
             u.created_at,
             u.updated_at
@@ -1751,6 +2177,10 @@ func (r *PostgresRepository) AttachProcessingPaymentRequestSettlementTransaction
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -1758,6 +2188,7 @@ func (r *PostgresRepository) AttachProcessingPaymentRequestSettlementTransaction
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -1771,25 +2202,31 @@ func (r *PostgresRepository) AttachProcessingPaymentRequestSettlementTransaction
 	return &item, nil
 }
 
-// MarkPaymentRequestFulfilled finalizes a processing request after successful transfer.
+// MarkPaymentRequestFulfilled credits a processing request with its completed
+// settlement transfer. The request is fulfilled once amount_paid reaches the amount;
+// a partly paid request goes back to pending for the next payment.
 func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error) {
 	query := `
         WITH updated AS (
-            UPDATE payment_requests
+            UPDATE payment_requests pr
             SET
-                status = 'fulfilled',
-                fulfilled_by_user_id = $3,
-                settled_transaction_id = $4,
+                amount_paid = pr.amount_paid + t.amount,
+                status = (CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN 'fulfilled' ELSE 'pending' END)::payment_request_status,
+                fulfilled_by_user_id = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN $3::uuid ELSE pr.fulfilled_by_user_id END,
+                settled_transaction_id = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN $4::uuid ELSE NULL END,
                 processing_started_at = NULL,
-                responded_at = NOW(),
+                responded_at = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN NOW() ELSE pr.responded_at END,
                 declined_reason = NULL,
+                declined_at = NULL,
                 updated_at = NOW()
-            WHERE id = $1
-              AND recipient_user_id = $2
-              AND request_type = 'individual'
-              AND deleted_at IS NULL
-              AND status = 'processing'
-            RETURNING *
+            FROM transactions t
+            WHERE pr.id = $1
+              AND pr.recipient_user_id = $2
+              AND pr.request_type = 'individual'
+              AND pr.deleted_at IS NULL
+              AND pr.status = 'processing'
+              AND t.id = $4
+            RETURNING pr.*
         )
         SELECT
             u.id,
@@ -1803,6 +2240,10 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, re
             COALESCE(NULLIF(btrim(u.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(u.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             u.amount,
+            u.allow_partial,
+            u.amount_paid,
+            u.short_code,
+            u.split_id,
             u.description,
             u.image_url,
             u.fulfilled_by_user_id,
@@ -1810,6 +2251,7 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, re
             u.processing_started_at,
             u.responded_at,
             u.declined_reason,
+            u.declined_at,
             u.deleted_at,
             u.created_at,
             u.updated_at
@@ -1831,6 +2273,10 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, re
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -1838,6 +2284,7 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, re
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -1851,23 +2298,29 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilled(ctx context.Context, re
 	return &item, nil
 }
 
-// MarkPaymentRequestFulfilledBySettlementTransaction finalizes a processing request from transfer completion.
+// MarkPaymentRequestFulfilledBySettlementTransaction credits a processing request
+// from its settlement transfer's completion, like MarkPaymentRequestFulfilled.
 func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error) {
 	query := `
         WITH updated AS (
-            UPDATE payment_requests
+            UPDATE payment_requests pr
             SET
-                status = 'fulfilled',
-                fulfilled_by_user_id = recipient_user_id,
+                amount_paid = pr.amount_paid + t.amount,
+                status = (CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN 'fulfilled' ELSE 'pending' END)::payment_request_status,
+                fulfilled_by_user_id = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN pr.recipient_user_id ELSE pr.fulfilled_by_user_id END,
+                settled_transaction_id = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN pr.settled_transaction_id ELSE NULL END,
                 processing_started_at = NULL,
-                responded_at = NOW(),
+                responded_at = CASE WHEN pr.amount_paid + t.amount >= pr.amount THEN NOW() ELSE pr.responded_at END,
                 declined_reason = NULL,
+                declined_at = NULL,
                 updated_at = NOW()
-            WHERE settled_transaction_id = $1
-              AND request_type = 'individual'
-              AND deleted_at IS NULL
-              AND status = 'processing'
-            RETURNING *
+            FROM transactions t
+            WHERE pr.settled_transaction_id = $1
+              AND pr.request_type = 'individual'
+              AND pr.deleted_at IS NULL
+              AND pr.status = 'processing'
+              AND t.id = pr.settled_transaction_id
+            RETURNING pr.*
         )
         SELECT
             u.id,
@@ -1881,6 +2334,10 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(
             COALESCE(NULLIF(btrim(u.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(u.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             u.amount,
+            u.allow_partial,
+            u.amount_paid,
+            u.short_code,
+            u.split_id,
             u.description,
             u.image_url,
             u.fulfilled_by_user_id,
@@ -1888,6 +2345,7 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(
             u.processing_started_at,
             u.responded_at,
             u.declined_reason,
+            u.declined_at,
             u.deleted_at,
             u.created_at,
             u.updated_at
@@ -1909,6 +2367,10 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -1916,6 +2378,7 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -1929,22 +2392,9 @@ func (r *PostgresRepository) MarkPaymentRequestFulfilledBySettlementTransaction(
 	return &item, nil
 }
 
-// ReleasePaymentRequestFromProcessing resets request state to pending after a failed payment attempt.
-func (r *PostgresRepository) ReleasePaymentRequestFromProcessing(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) error {
-	query := `
-        UPDATE payment_requests
-        SET status = 'pending', processing_started_at = NULL, updated_at = NOW()
-        WHERE id = $1
-          AND recipient_user_id = $2
-          AND request_type = 'individual'
-          AND deleted_at IS NULL
-          AND status = 'processing'
-    `
-	_, err := r.db.Exec(ctx, query, requestID, recipientID)
-	return err
-}
-
 // ReleasePaymentRequestFromProcessingBySettlementTransaction resets a processing request after transfer failure.
+// Only the individual request still processing under settledTransactionID reverts to
+// pending; a request that settled, or is being paid by another transfer, is untouched.
 func (r *PostgresRepository) ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error {
 	query := `
         UPDATE payment_requests
@@ -1962,6 +2412,53 @@ func (r *PostgresRepository) ReleasePaymentRequestFromProcessingBySettlementTran
 	return err
 }
 
+// RecordPaymentRequestSettlement links a settlement transfer to the request it pays.
+func (r *PostgresRepository) RecordPaymentRequestSettlement(ctx context.Context, requestID uuid.UUID, transactionID uuid.UUID, amount int64) error {
+	query := `
+        INSERT INTO payment_request_settlements (request_id, transaction_id, amount)
+        VALUES ($1, $2, $3)
+    `
+	_, err := r.db.Exec(ctx, query, requestID, transactionID, amount)
+	return err
+}
+
+// ListPaymentRequestSettlements lists the transfers made toward a request, oldest
+// first, with each transfer's current status.
+func (r *PostgresRepository) ListPaymentRequestSettlements(ctx context.Context, requestID uuid.UUID) ([]domain.PaymentRequestSettlement, error) {
+	query := `
+        SELECT
+            s.transaction_id,
+            s.amount,
+            COALESCE(t.status::text, ta.status::text, 'pending') AS status,
+            s.created_at
+        FROM payment_request_settlements s
+        LEFT JOIN transactions t ON t.id = s.transaction_id
+        LEFT JOIN transactions_archive ta ON ta.id = s.transaction_id
+        WHERE s.request_id = $1
+        ORDER BY s.created_at ASC, s.transaction_id ASC
+    `
+	rows, err := r.db.Query(ctx, query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := []domain.PaymentRequestSettlement{}
+	for rows.Next() {
+		var settlement domain.PaymentRequestSettlement
+		if err := rows.Scan(
+			&settlement.TransactionID,
+			&settlement.Amount,
+			&settlement.Status,
+			&settlement.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+	return settlements, rows.Err()
+}
+
 // DeclineIncomingPaymentRequest marks an incoming request as declined.
 func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, reason *string) (*domain.PaymentRequest, error) {
 	query := `
@@ -1970,6 +2467,7 @@ func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context,
             SET
                 status = 'declined',
                 declined_reason = $3,
+                declined_at = NOW(),
                 processing_started_at = NULL,
                 responded_at = NOW(),
                 updated_at = NOW()
@@ -1992,6 +2490,10 @@ func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context,
             COALESCE(NULLIF(btrim(u.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
             COALESCE(u.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
             u.amount,
+            u.allow_partial,
+            u.amount_paid,
+            u.short_code,
+            u.split_id,
             u.description,
             u.image_url,
             u.fulfilled_by_user_id,
@@ -1999,6 +2501,7 @@ func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context,
             u.processing_started_at,
             u.responded_at,
             u.declined_reason,
+            u.declined_at,
             u.deleted_at,
             u.created_at,
             u.updated_at
@@ -2020,6 +2523,10 @@ func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context,
 		&item.RecipientUsername,
 		&item.RecipientFullName,
 		&item.Amount,
+		&item.AllowPartial,
+		&item.AmountPaid,
+		&item.ShortCode,
+		&item.SplitID,
 		&item.Description,
 		&item.ImageURL,
 		&item.FulfilledByUserID,
@@ -2027,6 +2534,7 @@ func (r *PostgresRepository) DeclineIncomingPaymentRequest(ctx context.Context,
 		&item.ProcessingStarted,
 		&item.RespondedAt,
 		&item.DeclinedReason,
+		&item.DeclinedAt,
 		&item.DeletedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -2147,8 +2655,13 @@ func (r *PostgresRepository) ListInAppNotifications(ctx context.Context, userID
 		args = append(args, search)
 		argPos++
 	}
+	if opts.Cursor != nil {
+		query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argPos, argPos+1)
+		args = append(args, opts.Cursor.CreatedAt, opts.Cursor.ID)
+		argPos += 2
+	}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.Query(ctx, query, args...)
@@ -2231,6 +2744,47 @@ func (r *PostgresRepository) MarkAllInAppNotificationsRead(ctx context.Context,
 	return tag.RowsAffected(), nil
 }
 
+// MarkInAppNotificationsRead marks the user's unread notifications among ids as read
+// and returns how many changed.
+func (r *PostgresRepository) MarkInAppNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	query := `
+        UPDATE in_app_notifications
+        SET
+            status = 'read',
+            read_at = COALESCE(read_at, NOW()),
+            updated_at = NOW()
+        WHERE user_id = $1
+          AND id = ANY($2)
+          AND status = 'unread'
+    `
+	tag, err := r.db.Exec(ctx, query, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteReadInAppNotificationsBefore deletes up to limit read notifications created
+// before cutoff and returns how many were deleted.
+func (r *PostgresRepository) DeleteReadInAppNotificationsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+        DELETE FROM in_app_notifications
+        WHERE id IN (
+            SELECT id
+            FROM in_app_notifications
+            WHERE status = 'read'
+              AND created_at < $1
+            ORDER BY created_at
+            LIMIT $2
+        )
+    `
+	tag, err := r.db.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *PostgresRepository) GetInAppNotificationUnreadCounts(ctx context.Context, userID uuid.UUID) (*domain.NotificationUnreadCounts, error) {
 	query := `
         SELECT
@@ -2298,15 +2852,15 @@ func (r *PostgresRepository) CreateMoneyDrop(ctx context.Context, drop *domain.M
 		INSERT INTO money_drops (
 			creator_id, title, status, total_amount, refunded_amount, amount_per_claim, total_claims_allowed,
 			claims_made_count, expiry_timestamp, lock_enabled, lock_password_hash, lock_password_encrypted,
-			fee_amount, fee_percentage, funding_source_account_id, money_drop_account_id
+			fee_amount, fee_percentage, funding_source_account_id, money_drop_account_id, currency
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, COALESCE(NULLIF($17, ''), 'NGN'))
 		RETURNING id, created_at
 	`
 	err := r.db.QueryRow(ctx, query,
 		drop.CreatorID, drop.Title, drop.Status, drop.TotalAmount, drop.RefundedAmount, drop.AmountPerClaim, drop.TotalClaimsAllowed,
 		drop.ClaimsMadeCount, drop.ExpiryTimestamp, drop.LockEnabled, drop.LockPasswordHash, drop.LockPasswordEncrypted,
-		drop.FeeAmount, drop.FeePercentage, drop.FundingSourceAccountID, drop.MoneyDropAccountID,
+		drop.FeeAmount, drop.FeePercentage, drop.FundingSourceAccountID, drop.MoneyDropAccountID, drop.Currency,
 	).Scan(&drop.ID, &drop.CreatedAt)
 	if err != nil {
 		return nil, err
@@ -2321,7 +2875,7 @@ func (r *PostgresRepository) FindMoneyDropByID(ctx context.Context, dropID uuid.
 		SELECT id, creator_id, title, status, total_amount, refunded_amount, amount_per_claim, total_claims_allowed,
 		       claims_made_count, expiry_timestamp, lock_enabled, lock_password_hash, lock_password_encrypted,
 		       fee_amount, fee_percentage, ended_at, ended_reason, funding_source_account_id,
-		       money_drop_account_id, created_at
+		       money_drop_account_id, currency, created_at
 		FROM money_drops
 		WHERE id = $1
 	`
@@ -2329,7 +2883,7 @@ func (r *PostgresRepository) FindMoneyDropByID(ctx context.Context, dropID uuid.
 		&drop.ID, &drop.CreatorID, &drop.Title, &drop.Status, &drop.TotalAmount, &drop.RefundedAmount, &drop.AmountPerClaim,
 		&drop.TotalClaimsAllowed, &drop.ClaimsMadeCount, &drop.ExpiryTimestamp, &drop.LockEnabled, &drop.LockPasswordHash,
 		&drop.LockPasswordEncrypted, &drop.FeeAmount, &drop.FeePercentage, &drop.EndedAt, &drop.EndedReason,
-		&drop.FundingSourceAccountID, &drop.MoneyDropAccountID, &drop.CreatedAt)
+		&drop.FundingSourceAccountID, &drop.MoneyDropAccountID, &drop.Currency, &drop.CreatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrMoneyDropNotFound
@@ -2367,8 +2921,18 @@ func (r *PostgresRepository) FindMoneyDropByIDAndCreatorID(ctx context.Context,
 // FindMoneyDropClaimDropIDByTransactionID resolves the money_drop_claims.drop_id that
 // corresponds to a money_drop_claim transaction.
 func (r *PostgresRepository) FindMoneyDropClaimDropIDByTransactionID(ctx context.Context, transactionID uuid.UUID) (uuid.UUID, error) {
-	// New money-drop claims persist a deterministic drop token in transactions.anchor_reason.
-	// Prefer this path and only fall back to legacy heuristic matching when token is absent.
+	// Claims recorded by ClaimMoneyDropAtomic carry their transaction ID directly.
+	dropID, err := findMoneyDropClaimDropIDByTransactionID(ctx, r.db, transactionID)
+	if err == nil {
+		return dropID, nil
+	}
+	if !errors.Is(err, ErrTransactionNotFound) {
+		return uuid.Nil, err
+	}
+
+	// Older claims predate money_drop_claims.transaction_id. Those transactions persist
+	// a deterministic drop token in anchor_reason; prefer it and only fall back to
+	// legacy heuristic matching when the token is absent.
 	var anchorReason *string
 	anchorReasonQuery := `
 		SELECT anchor_reason
@@ -2469,13 +3033,13 @@ func selectUniqueMoneyDropMatch(matches []uuid.UUID, transactionID uuid.UUID) (u
 func (r *PostgresRepository) FindMoneyDropCreatorByDropID(ctx context.Context, dropID uuid.UUID) (*domain.User, error) {
 	var user domain.User
 	query := `
-		SELECT u.id, btrim(u.username) AS username, u.allow_sending, u.anchor_customer_id
+		SELECT u.id, btrim(u.username) AS username, u.allow_sending, u.allow_receiving, u.anchor_customer_id
 		FROM users u
 		INNER JOIN money_drops md ON u.id = md.creator_id
 		WHERE md.id = $1
 	`
 	err := r.db.QueryRow(ctx, query, dropID).Scan(
-		&user.ID, &user.Username, &user.AllowSending, &user.AnchorCustomerID)
+		&user.ID, &user.Username, &user.AllowSending, &user.AllowReceiving, &user.AnchorCustomerID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrUserNotFound
@@ -2557,24 +3121,14 @@ func (r *PostgresRepository) ClaimMoneyDropAtomic(ctx context.Context, dropID, c
 		return uuid.Nil, fmt.Errorf("failed to update money drop claim count: %w", err)
 	}
 
-	// 4. Insert into money_drop_claims table
-	insertClaimQuery := `
-		INSERT INTO money_drop_claims (drop_id, claimant_id, claimed_at)
-		VALUES ($1, $2, NOW())
-	`
-	_, err = tx.Exec(ctx, insertClaimQuery, dropID, claimantID)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to insert claim record: %w", err)
-	}
-
-	// 5. Log the transaction within the same DB transaction for consistency
+	// 4. Log the transaction within the same DB transaction for consistency
 	var claimTxID uuid.UUID
 	logTxQuery := `
 		INSERT INTO transactions (
 			sender_id, recipient_id, source_account_id, destination_account_id,
-			type, category, status, amount, fee, description, anchor_reason
+			type, category, status, amount, fee, description, anchor_reason, currency
 		)
-		SELECT creator_id, $1, $2, $3, 'money_drop_claim', 'Money Drop', 'pending', $4, 0, 'Money Drop Claim', 'md_drop:' || $5::text || ';state:created'
+		SELECT creator_id, $1, $2, $3, 'money_drop_claim', 'Money Drop', 'pending', $4, 0, 'Money Drop Claim', 'md_drop:' || $5::text || ';state:payout_pending', currency
 		FROM money_drops
 		WHERE id = $5
 		RETURNING id
@@ -2584,6 +3138,11 @@ func (r *PostgresRepository) ClaimMoneyDropAtomic(ctx context.Context, dropID, c
 		return uuid.Nil, fmt.Errorf("failed to log money drop claim transaction: %w", err)
 	}
 
+	// 5. Insert into money_drop_claims table, linked to the claim transaction
+	if err := insertMoneyDropClaim(ctx, tx, dropID, claimantID, claimTxID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to insert claim record: %w", err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return uuid.Nil, err
 	}
@@ -2741,12 +3300,14 @@ func (r *PostgresRepository) ListEndedMoneyDropsByCreator(ctx context.Context, c
 	return drops, nil
 }
 
+// ListClaimedMoneyDropsByUserID returns the user's claims with each drop's creator and
+// current status, newest first. Drops that have since ended or expired are included.
 func (r *PostgresRepository) ListClaimedMoneyDropsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.ClaimedMoneyDropHistoryItem, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 	query := `
-		SELECT c.drop_id, md.title, btrim(u.username) AS creator_username, md.amount_per_claim, c.claimed_at
+		SELECT c.drop_id, md.title, btrim(u.username) AS creator_username, md.amount_per_claim, c.claimed_at, md.status::text
 		FROM money_drop_claims c
 		INNER JOIN money_drops md ON md.id = c.drop_id
 		INNER JOIN users u ON u.id = md.creator_id
@@ -2769,6 +3330,7 @@ func (r *PostgresRepository) ListClaimedMoneyDropsByUserID(ctx context.Context,
 			&item.CreatorUsername,
 			&item.AmountClaimed,
 			&item.ClaimedAt,
+			&item.DropStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -2811,10 +3373,19 @@ func (r *PostgresRepository) ListMoneyDropClaimsByDropID(ctx context.Context, dr
 	}
 
 	query := `
-		SELECT c.claimant_id, btrim(u.username) AS username, u.full_name, u.profile_picture_url, md.amount_per_claim, c.claimed_at
+		SELECT c.claimant_id, btrim(u.username) AS username, u.full_name, u.profile_picture_url, md.amount_per_claim, c.claimed_at,
+		       CASE
+		           WHEN t.id IS NULL THEN ''
+		           WHEN t.status = 'completed' THEN 'completed'
+		           WHEN t.status = 'failed' THEN 'failed'
+		           WHEN COALESCE(t.anchor_reason, '') LIKE '%state:payout_pending%'
+		             OR COALESCE(t.anchor_reason, '') LIKE '%state:payout_inflight%' THEN 'payout_pending'
+		           ELSE 'processing'
+		       END AS payout_status
 		FROM money_drop_claims c
 		INNER JOIN users u ON u.id = c.claimant_id
 		INNER JOIN money_drops md ON md.id = c.drop_id
+		LEFT JOIN transactions t ON t.id = c.transaction_id
 		WHERE c.drop_id = $1
 		  AND (
 		    $2 = ''
@@ -2840,6 +3411,7 @@ func (r *PostgresRepository) ListMoneyDropClaimsByDropID(ctx context.Context, dr
 			&item.ProfilePictureURL,
 			&item.AmountClaimed,
 			&item.ClaimedAt,
+			&item.PayoutStatus,
 		); err != nil {
 			return nil, 0, err
 		}
@@ -2918,11 +3490,25 @@ func (r *PostgresRepository) ListPendingMoneyDropClaimReconciliationCandidates(
 	return items, nil
 }
 
+// MarkMoneyDropClaimReconcileRequested moves a money_drop_claim transaction back to
+// pending so reconciliation resubmits it. Only failed claims, or claims that already
+// reached Anchor, qualify; completed claims and pending claims with no Anchor
+// transfer are left alone and reported as not updated.
 func (r *PostgresRepository) MarkMoneyDropClaimReconcileRequested(
 	ctx context.Context,
 	transactionID uuid.UUID,
 	anchorReason string,
 	failureReason string,
+) (bool, error) {
+	return markMoneyDropClaimReconcileRequested(ctx, r.db, transactionID, anchorReason, failureReason)
+}
+
+func markMoneyDropClaimReconcileRequested(
+	ctx context.Context,
+	db commandExecer,
+	transactionID uuid.UUID,
+	anchorReason string,
+	failureReason string,
 ) (bool, error) {
 	normalizedReason := strings.TrimSpace(anchorReason)
 	if normalizedReason == "" {
@@ -2949,7 +3535,7 @@ func (r *PostgresRepository) MarkMoneyDropClaimReconcileRequested(
 		      OR COALESCE(BTRIM(anchor_transfer_id), '') <> ''
 		  )
 	`
-	result, err := r.db.Exec(ctx, query, normalizedFailure, normalizedReason, transactionID)
+	result, err := db.Exec(ctx, query, normalizedFailure, normalizedReason, transactionID)
 	if err != nil {
 		return false, err
 	}
@@ -3022,6 +3608,33 @@ func (r *PostgresRepository) FindExpiredAndCompletedMoneyDrops(ctx context.Conte
 	return drops, nil
 }
 
+// ListExpiredActiveMoneyDrops returns up to limit active drops past their expiry,
+// oldest expiry first.
+func (r *PostgresRepository) ListExpiredActiveMoneyDrops(ctx context.Context, limit int) ([]domain.ExpiredMoneyDrop, error) {
+	query := `
+		SELECT id, creator_id, expiry_timestamp, claims_made_count, total_claims_allowed, refunded_amount
+		FROM money_drops
+		WHERE status = 'active' AND expiry_timestamp < NOW()
+		ORDER BY expiry_timestamp ASC
+		LIMIT $1
+	`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drops := []domain.ExpiredMoneyDrop{}
+	for rows.Next() {
+		var drop domain.ExpiredMoneyDrop
+		if err := rows.Scan(&drop.ID, &drop.CreatorID, &drop.ExpiryTimestamp, &drop.ClaimsMadeCount, &drop.TotalClaimsAllowed, &drop.RefundedAmount); err != nil {
+			return nil, err
+		}
+		drops = append(drops, drop)
+	}
+	return drops, rows.Err()
+}
+
 // AcquireMoneyDropFinalizationLock transitions an eligible drop into refund_processing state.
 // It returns:
 // - acquired: whether this caller acquired the lock and should proceed with finalization.
@@ -3120,32 +3733,101 @@ func (r *PostgresRepository) UpdateMoneyDropStatus(ctx context.Context, dropID u
 	return err
 }
 
+// UpdateMoneyDropEndMetadata records the terminal status of a money drop along with why and
+// when it ended. It returns ErrMoneyDropNotFound when no drop matches dropID.
 func (r *PostgresRepository) UpdateMoneyDropEndMetadata(ctx context.Context, dropID uuid.UUID, status string, endedReason string, endedAt time.Time) error {
+	return updateMoneyDropEndMetadata(ctx, r.db, dropID, status, endedReason, endedAt)
+}
+
+// commandExecer is the subset of pgxpool.Pool used by single-statement writes.
+type commandExecer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func updateMoneyDropEndMetadata(ctx context.Context, db commandExecer, dropID uuid.UUID, status string, endedReason string, endedAt time.Time) error {
 	query := `
 		UPDATE money_drops
-		SET status = $1, ended_reason = $2, ended_at = $3
-		WHERE id = $4
+		SET status = $2, ended_reason = $3, ended_at = $4, updated_at = NOW()
+		WHERE id = $1
 	`
-	_, err := r.db.Exec(ctx, query, status, endedReason, endedAt, dropID)
-	return err
+	result, err := db.Exec(ctx, query, dropID, status, endedReason, endedAt)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrMoneyDropNotFound
+	}
+	return nil
 }
 
+// AddMoneyDropRefundedAmount atomically adds amount to a drop's refunded_amount. The
+// increment is only applied while the running total stays within total_amount, so a
+// replayed or oversized refund returns ErrMoneyDropRefundExceedsTotal instead of
+// over-crediting the creator.
 func (r *PostgresRepository) AddMoneyDropRefundedAmount(ctx context.Context, dropID uuid.UUID, amount int64) error {
+	return addMoneyDropRefundedAmount(ctx, r.db, dropID, amount)
+}
+
+func insertMoneyDropClaim(ctx context.Context, db commandExecer, dropID, claimantID, claimTransactionID uuid.UUID) error {
+	query := `
+		INSERT INTO money_drop_claims (drop_id, claimant_id, transaction_id, claimed_at)
+		VALUES ($1, $2, $3, NOW())
+	`
+	_, err := db.Exec(ctx, query, dropID, claimantID, claimTransactionID)
+	return err
+}
+
+func findMoneyDropClaimDropIDByTransactionID(ctx context.Context, db rowQuerier, transactionID uuid.UUID) (uuid.UUID, error) {
+	var dropID uuid.UUID
+	err := db.QueryRow(ctx, `SELECT drop_id FROM money_drop_claims WHERE transaction_id = $1 LIMIT 1`, transactionID).Scan(&dropID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, ErrTransactionNotFound
+		}
+		return uuid.Nil, err
+	}
+	return dropID, nil
+}
+
+// rowQuerier is the subset of pgxpool.Pool used by single-row reads and RETURNING writes.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func addMoneyDropRefundedAmount(ctx context.Context, db rowQuerier, dropID uuid.UUID, amount int64) error {
 	if amount <= 0 {
 		return nil
 	}
 
 	query := `
-		UPDATE money_drops
-		SET refunded_amount = refunded_amount + $1,
-		    updated_at = NOW()
-		WHERE id = $2
+		WITH target AS (
+			SELECT id FROM money_drops WHERE id = $2
+		), updated AS (
+			UPDATE money_drops
+			SET refunded_amount = refunded_amount + $1,
+			    updated_at = NOW()
+			WHERE id = $2
+			  AND refunded_amount + $1 <= total_amount
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM target), EXISTS (SELECT 1 FROM updated)
 	`
-	_, err := r.db.Exec(ctx, query, amount, dropID)
-	return err
+	var found, applied bool
+	if err := db.QueryRow(ctx, query, amount, dropID).Scan(&found, &applied); err != nil {
+		return err
+	}
+	if !found {
+		return ErrMoneyDropNotFound
+	}
+	if !applied {
+		return ErrMoneyDropRefundExceedsTotal
+	}
+	return nil
 }
 
 // UpdateMoneyDropAccountBalance updates the balance for a money drop account by account ID.
+// It returns ErrAccountNotFound when no money drop account has that ID; other account
+// types are never touched.
 func (r *PostgresRepository) UpdateMoneyDropAccountBalance(ctx context.Context, accountID uuid.UUID, balance int64) error {
 	query := `UPDATE accounts SET balance = $1, updated_at = NOW() WHERE id = $2 AND account_type = 'money_drop'`
 	result, err := r.db.Exec(ctx, query, balance, accountID)
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// transactionHistorySource returns the FROM clause for history reads. Every row
+// carries an "archived" column so callers can tell where it came from.
+func transactionHistorySource(includeArchive bool) string {
+	if !includeArchive {
+		return `(SELECT *, FALSE AS archived FROM transactions) t`
+	}
+	return `(
+		SELECT *, FALSE AS archived FROM transactions
+		UNION ALL
+		SELECT *, TRUE AS archived FROM transactions_archive
+	) t`
+}
+
+// ArchiveTransactionsBefore moves up to limit completed or failed transactions created
+// before cutoff into transactions_archive and reports how many were moved.
+// Transactions with an open or investigating dispute stay in the live table.
+func (r *PostgresRepository) ArchiveTransactionsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+		WITH candidates AS (
+			SELECT t.id
+			FROM transactions t
+			WHERE t.status IN ('completed', 'failed')
+			  AND t.created_at < $1
+			  AND NOT EXISTS (
+			    SELECT 1
+			    FROM transaction_disputes d
+			    WHERE d.transaction_id = t.id
+			      AND d.status IN ('open', 'investigating')
+			  )
+			ORDER BY t.created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		),
+		moved AS (
+			DELETE FROM transactions t
+			USING candidates c
+			WHERE t.id = c.id
+			RETURNING t.*
+		)
+		INSERT INTO transactions_archive
+		SELECT * FROM moved
+	`
+	tag, err := r.db.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// LatestArchivedTransactionAt returns the creation time of the newest archived
+// transaction, or nil when nothing has been archived yet.
+func (r *PostgresRepository) LatestArchivedTransactionAt(ctx context.Context) (*time.Time, error) {
+	var latest *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MAX(created_at) FROM transactions_archive`).Scan(&latest); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const auditEventColumns = `
+	id, actor_type, COALESCE(actor_id, ''), action, COALESCE(subject_type, ''), COALESCE(subject_id, ''),
+	metadata, COALESCE(ip, ''), created_at
+`
+
+func scanAuditEvent(row pgx.Row) (*domain.AuditEvent, error) {
+	var item domain.AuditEvent
+	var metadata []byte
+	if err := row.Scan(
+		&item.ID,
+		&item.ActorType,
+		&item.ActorID,
+		&item.Action,
+		&item.SubjectType,
+		&item.SubjectID,
+		&metadata,
+		&item.IP,
+		&item.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	item.Metadata = map[string]interface{}{}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &item.Metadata); err != nil {
+			return nil, fmt.Errorf("decode audit metadata: %w", err)
+		}
+	}
+	return &item, nil
+}
+
+// InsertAuditEvents appends a batch of audit events in one round trip. Events keep
+// the CreatedAt they were recorded with rather than the time of the write.
+func (r *PostgresRepository) InsertAuditEvents(ctx context.Context, events []domain.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (actor_type, actor_id, action, subject_type, subject_id, metadata, ip, created_at)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), NULLIF($5, ''), $6::jsonb, NULLIF($7, ''), $8)
+	`
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		metadata := []byte("{}")
+		if len(event.Metadata) > 0 {
+			encoded, err := json.Marshal(event.Metadata)
+			if err != nil {
+				return fmt.Errorf("encode audit metadata for %s: %w", event.Action, err)
+			}
+			metadata = encoded
+		}
+		batch.Queue(query, event.ActorType, event.ActorID, event.Action, event.SubjectType, event.SubjectID, string(metadata), event.IP, event.CreatedAt)
+	}
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// ListAuditEvents returns audit events matching filter, newest first.
+func (r *PostgresRepository) ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error) {
+	query := `
+		SELECT ` + auditEventColumns + `
+		FROM audit_events
+		WHERE ($1 = '' OR subject_type = $1)
+		  AND ($2 = '' OR subject_id = $2)
+		  AND ($3 = '' OR actor_id = $3)
+		  AND ($4 = '' OR action = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		ORDER BY created_at DESC
+		LIMIT $7 OFFSET $8
+	`
+	rows, err := r.db.Query(ctx, query, filter.SubjectType, filter.SubjectID, filter.ActorID, filter.Action, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.AuditEvent, 0)
+	for rows.Next() {
+		item, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *item)
+	}
+	return results, rows.Err()
+}
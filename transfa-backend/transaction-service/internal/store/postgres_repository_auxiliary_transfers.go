@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// CreateFeeCollection records a fee transfer once Anchor accepted it. Recording the
+// same transfer again is a no-op.
+func (r *PostgresRepository) CreateFeeCollection(ctx context.Context, fee domain.FeeCollection) error {
+	query := `
+		INSERT INTO fee_collections (parent_transaction_id, user_id, amount, anchor_transfer_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (anchor_transfer_id) DO NOTHING
+	`
+	_, err := r.db.Exec(ctx, query, fee.ParentTransactionID, fee.UserID, fee.Amount, fee.AnchorTransferID)
+	return err
+}
+
+// FindFeeCollectionByAnchorTransferID returns the fee collection made by an Anchor
+// transfer, or ErrFeeCollectionNotFound.
+func (r *PostgresRepository) FindFeeCollectionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.FeeCollection, error) {
+	query := `
+		SELECT id, parent_transaction_id, user_id, amount, anchor_transfer_id, status,
+		       COALESCE(failure_reason, ''), created_at, updated_at
+		FROM fee_collections
+		WHERE anchor_transfer_id = $1
+	`
+	var fee domain.FeeCollection
+	err := r.db.QueryRow(ctx, query, anchorTransferID).Scan(
+		&fee.ID,
+		&fee.ParentTransactionID,
+		&fee.UserID,
+		&fee.Amount,
+		&fee.AnchorTransferID,
+		&fee.Status,
+		&fee.FailureReason,
+		&fee.CreatedAt,
+		&fee.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFeeCollectionNotFound
+		}
+		return nil, err
+	}
+	return &fee, nil
+}
+
+// MarkFeeCollectionCompleted moves a pending fee collection to completed. A failed
+// one stays failed.
+func (r *PostgresRepository) MarkFeeCollectionCompleted(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE fee_collections
+		SET status = 'completed', updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id)
+	return err
+}
+
+// MarkFeeCollectionFailed moves a fee collection to failed, also after it completed
+// since Anchor reports reversals as failures. It reports false if it already was
+// failed, so a redelivered event does not queue a second compensation.
+func (r *PostgresRepository) MarkFeeCollectionFailed(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE fee_collections
+		SET status = 'failed', failure_reason = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $1 AND status <> 'failed'
+	`, id, reason)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FlagTransactionFeeCollectionFailed marks the transaction whose fee could not be
+// collected. The transaction's own status is left alone.
+func (r *PostgresRepository) FlagTransactionFeeCollectionFailed(ctx context.Context, transactionID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE transactions
+		SET fee_collection_failed = TRUE, updated_at = NOW()
+		WHERE id = $1
+	`, transactionID)
+	return err
+}
+
+// MarkMoneyDropFundingFailed flags a money_drop_funding transaction whose transfer
+// failed and moves it to failed. It reports false if the transaction was already
+// flagged.
+func (r *PostgresRepository) MarkMoneyDropFundingFailed(ctx context.Context, transactionID uuid.UUID, reason string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE transactions
+		SET funding_failed = TRUE,
+		    status = 'failed',
+		    failure_reason = COALESCE(NULLIF($2, ''), failure_reason),
+		    updated_at = NOW()
+		WHERE id = $1 AND type = 'money_drop_funding' AND NOT funding_failed
+	`, transactionID, reason)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// EnqueueTransferCompensation queues a wallet credit. A transaction gets at most one
+// compensation of each kind.
+func (r *PostgresRepository) EnqueueTransferCompensation(ctx context.Context, compensation domain.TransferCompensation) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO transfer_compensations (transaction_id, user_id, kind, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transaction_id, kind) DO NOTHING
+	`, compensation.TransactionID, compensation.UserID, compensation.Kind, compensation.Amount)
+	return err
+}
+
+// ListPendingTransferCompensations returns queued compensations, oldest first.
+func (r *PostgresRepository) ListPendingTransferCompensations(ctx context.Context, limit int) ([]domain.TransferCompensation, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, transaction_id, user_id, kind, amount, status, created_at, completed_at
+		FROM transfer_compensations
+		WHERE status = 'pending'
+		ORDER BY created_at ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.TransferCompensation
+	for rows.Next() {
+		var item domain.TransferCompensation
+		if err := rows.Scan(
+			&item.ID,
+			&item.TransactionID,
+			&item.UserID,
+			&item.Kind,
+			&item.Amount,
+			&item.Status,
+			&item.CreatedAt,
+			&item.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CompleteTransferCompensation moves a pending compensation to completed. It reports
+// false if another worker completed it first; callers credit the wallet in the same
+// database transaction only when it reports true.
+func (r *PostgresRepository) CompleteTransferCompensation(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE transfer_compensations
+		SET status = 'completed', completed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
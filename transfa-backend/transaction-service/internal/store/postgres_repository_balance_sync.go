@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ListAccountsForBalanceSync returns up to limit open accounts with an Anchor account,
+// ordered by id and starting after afterID. Pass uuid.Nil to start from the beginning.
+func (r *PostgresRepository) ListAccountsForBalanceSync(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Account, error) {
+	query := `
+		SELECT id, user_id, anchor_account_id, balance, updated_at
+		FROM accounts
+		WHERE id > $1
+		  AND status <> 'closed'
+		  AND COALESCE(anchor_account_id, '') <> ''
+		ORDER BY id
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]domain.Account, 0, limit)
+	for rows.Next() {
+		var account domain.Account
+		if err := rows.Scan(&account.ID, &account.UserID, &account.AnchorAccountID, &account.Balance, &account.UpdatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// SyncAccountBalance sets an account's balance to the value fetched from Anchor, but only
+// if the row has not changed since snapshot was read: its balance still equals
+// snapshot.Balance and its updated_at is no newer than snapshot.UpdatedAt. A debit or
+// credit committed after the read leaves the row untouched so the in-flight ledger
+// change is not overwritten. It reports whether the row was updated.
+func (r *PostgresRepository) SyncAccountBalance(ctx context.Context, snapshot domain.Account, balance int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE accounts
+		SET balance = $4, updated_at = NOW()
+		WHERE id = $1 AND balance = $2 AND updated_at <= $3
+	`, snapshot.ID, snapshot.Balance, snapshot.UpdatedAt, balance)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
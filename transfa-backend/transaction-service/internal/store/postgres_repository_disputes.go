@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const transactionDisputeColumns = `
+	id, transaction_id, user_id, dispute_reason, description, status, resolution_note, resolved_at,
+	created_at, updated_at
+`
+
+func scanTransactionDispute(row pgx.Row) (*domain.TransactionDispute, error) {
+	var item domain.TransactionDispute
+	if err := row.Scan(
+		&item.ID,
+		&item.TransactionID,
+		&item.UserID,
+		&item.Reason,
+		&item.Description,
+		&item.Status,
+		&item.ResolutionNote,
+		&item.ResolvedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateTransactionDispute records a new open dispute. It returns ErrTransactionDisputeExists
+// when the transaction already has an open or investigating dispute.
+func (r *PostgresRepository) CreateTransactionDispute(ctx context.Context, dispute domain.TransactionDispute) (*domain.TransactionDispute, error) {
+	query := `
+		INSERT INTO transaction_disputes (transaction_id, user_id, dispute_reason, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transaction_id) WHERE status IN ('open', 'investigating') DO NOTHING
+		RETURNING ` + transactionDisputeColumns
+	item, err := scanTransactionDispute(r.db.QueryRow(ctx, query, dispute.TransactionID, dispute.UserID, dispute.Reason, dispute.Description))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrTransactionDisputeExists
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListTransactionDisputes returns disputes matching the filter, newest first.
+func (r *PostgresRepository) ListTransactionDisputes(ctx context.Context, filter domain.TransactionDisputeFilter) ([]domain.TransactionDispute, error) {
+	query := `
+		SELECT ` + transactionDisputeColumns + `
+		FROM transaction_disputes
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.db.Query(ctx, query, filter.Status, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.TransactionDispute, 0)
+	for rows.Next() {
+		item, err := scanTransactionDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *item)
+	}
+	return results, rows.Err()
+}
+
+// ListTransactionDisputesByUser returns the disputes a user has raised, newest first.
+func (r *PostgresRepository) ListTransactionDisputesByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.TransactionDispute, error) {
+	query := `
+		SELECT ` + transactionDisputeColumns + `
+		FROM transaction_disputes
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.TransactionDispute, 0)
+	for rows.Next() {
+		item, err := scanTransactionDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *item)
+	}
+	return results, rows.Err()
+}
+
+// FindTransactionDisputeByID returns one dispute or ErrTransactionDisputeNotFound.
+func (r *PostgresRepository) FindTransactionDisputeByID(ctx context.Context, disputeID uuid.UUID) (*domain.TransactionDispute, error) {
+	query := `SELECT ` + transactionDisputeColumns + ` FROM transaction_disputes WHERE id = $1`
+	item, err := scanTransactionDispute(r.db.QueryRow(ctx, query, disputeID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrTransactionDisputeNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// FindLatestTransactionDisputeByUser returns the user's most recent dispute on a
+// transaction or ErrTransactionDisputeNotFound.
+func (r *PostgresRepository) FindLatestTransactionDisputeByUser(ctx context.Context, transactionID, userID uuid.UUID) (*domain.TransactionDispute, error) {
+	query := `
+		SELECT ` + transactionDisputeColumns + `
+		FROM transaction_disputes
+		WHERE transaction_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	item, err := scanTransactionDispute(r.db.QueryRow(ctx, query, transactionID, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrTransactionDisputeNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateTransactionDisputeStatus moves a dispute from fromStatus to toStatus. It
+// returns ErrTransactionDisputeStatusChanged when the dispute is no longer in
+// fromStatus, so concurrent reviewers cannot both apply a transition.
+func (r *PostgresRepository) UpdateTransactionDisputeStatus(ctx context.Context, disputeID uuid.UUID, fromStatus, toStatus string, resolutionNote *string) (*domain.TransactionDispute, error) {
+	query := `
+		UPDATE transaction_disputes
+		SET status = $3,
+			resolution_note = COALESCE($4, resolution_note),
+			resolved_at = CASE WHEN $3 IN ('resolved', 'rejected') THEN NOW() ELSE resolved_at END
+		WHERE id = $1 AND status = $2
+		RETURNING ` + transactionDisputeColumns
+	item, err := scanTransactionDispute(r.db.QueryRow(ctx, query, disputeID, fromStatus, toStatus, resolutionNote))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrTransactionDisputeStatusChanged
+		}
+		return nil, err
+	}
+	return item, nil
+}
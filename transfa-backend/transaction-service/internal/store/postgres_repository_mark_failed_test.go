@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeExecDB answers every Exec with tag and records the last statement.
+type fakeExecDB struct {
+	dbtx
+
+	tag  string
+	sql  string
+	args []any
+}
+
+func (f *fakeExecDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.sql = sql
+	f.args = args
+	return pgconn.NewCommandTag(f.tag), nil
+}
+
+func TestMarkTransactionAsFailed_GuardsSettledTransactions(t *testing.T) {
+	db := &fakeExecDB{tag: "UPDATE 1"}
+	repo := &PostgresRepository{db: db}
+
+	if err := repo.MarkTransactionAsFailed(context.Background(), uuid.New(), "", "rejected"); err != nil {
+		t.Fatalf("MarkTransactionAsFailed: %v", err)
+	}
+	if !strings.Contains(db.sql, "status NOT IN ('completed', 'failed', 'cancelled')") || !strings.Contains(db.sql, "failure_reason = $3") {
+		t.Fatalf("expected a guarded update that records the reason, got %s", db.sql)
+	}
+	if db.args[2] != "rejected" {
+		t.Fatalf("expected the failure reason argument, got %v", db.args)
+	}
+
+	db.tag = "UPDATE 0"
+	if err := repo.MarkTransactionAsFailed(context.Background(), uuid.New(), "", "rejected"); !errors.Is(err, ErrTransactionAlreadyFinal) {
+		t.Fatalf("expected ErrTransactionAlreadyFinal when no row changes, got %v", err)
+	}
+}
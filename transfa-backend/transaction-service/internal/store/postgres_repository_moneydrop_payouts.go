@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ListPendingMoneyDropClaimPayouts returns claim transactions still waiting for their
+// payout: payout_pending, or payout_inflight with a worker that stopped before
+// finishing. Only claims created after createdAfter and untouched since idleBefore
+// are returned, oldest first.
+func (r *PostgresRepository) ListPendingMoneyDropClaimPayouts(ctx context.Context, createdAfter, idleBefore time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM transactions
+		WHERE type = 'money_drop_claim'
+		  AND status = 'pending'
+		  AND COALESCE(BTRIM(anchor_transfer_id), '') = ''
+		  AND (
+		      COALESCE(anchor_reason, '') LIKE '%state:payout_pending%'
+		      OR COALESCE(anchor_reason, '') LIKE '%state:payout_inflight%'
+		  )
+		  AND created_at >= $1
+		  AND updated_at <= $2
+		ORDER BY created_at ASC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, createdAfter, idleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MarkMoneyDropClaimPayoutInFlight moves a claim from payout_pending to the in-flight
+// anchorReason so only one worker sends its transfer. An in-flight claim idle since
+// before idleBefore can be taken over. It returns false when the claim is not
+// waiting for a payout.
+func (r *PostgresRepository) MarkMoneyDropClaimPayoutInFlight(ctx context.Context, transactionID uuid.UUID, anchorReason string, idleBefore time.Time) (bool, error) {
+	query := `
+		UPDATE transactions
+		SET anchor_reason = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND type = 'money_drop_claim'
+		  AND status = 'pending'
+		  AND COALESCE(BTRIM(anchor_transfer_id), '') = ''
+		  AND (
+		      COALESCE(anchor_reason, '') LIKE '%state:payout_pending%'
+		      OR (COALESCE(anchor_reason, '') LIKE '%state:payout_inflight%' AND updated_at <= $3)
+		  )
+	`
+	result, err := r.db.Exec(ctx, query, transactionID, anchorReason, idleBefore)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// FindMoneyDropClaimPayout loads the accounts, amount and creator for a claim payout.
+func (r *PostgresRepository) FindMoneyDropClaimPayout(ctx context.Context, transactionID uuid.UUID) (*domain.MoneyDropClaimPayout, error) {
+	query := `
+		SELECT t.id, c.drop_id, c.claimant_id, t.amount, src.id,
+		       COALESCE(src.anchor_account_id, ''), COALESCE(dest.anchor_account_id, ''),
+		       COALESCE(BTRIM(creator.username), '')
+		FROM transactions t
+		INNER JOIN money_drop_claims c ON c.transaction_id = t.id
+		INNER JOIN accounts src ON src.id = t.source_account_id
+		INNER JOIN accounts dest ON dest.id = t.destination_account_id
+		INNER JOIN users creator ON creator.id = t.sender_id
+		WHERE t.id = $1
+		  AND t.type = 'money_drop_claim'
+	`
+	var payout domain.MoneyDropClaimPayout
+	err := r.db.QueryRow(ctx, query, transactionID).Scan(
+		&payout.TransactionID,
+		&payout.DropID,
+		&payout.ClaimantID,
+		&payout.Amount,
+		&payout.MoneyDropAccountID,
+		&payout.SourceAnchorAccountID,
+		&payout.DestinationAnchorAccountID,
+		&payout.CreatorUsername,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return &payout, nil
+}
@@ -1,11 +1,15 @@
 package store
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func TestParseMoneyDropIDFromAnchorReason(t *testing.T) {
@@ -109,6 +113,347 @@ func TestSelectUniqueMoneyDropMatch(t *testing.T) {
 	})
 }
 
+type moneyDropEndRow struct {
+	status      string
+	endedReason string
+	endedAt     time.Time
+}
+
+// fakeMoneyDropExecer applies UpdateMoneyDropEndMetadata writes to an in-memory set of drops.
+type fakeMoneyDropExecer struct {
+	drops map[uuid.UUID]*moneyDropEndRow
+	query string
+}
+
+func (f *fakeMoneyDropExecer) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	f.query = sql
+	row, ok := f.drops[arguments[0].(uuid.UUID)]
+	if !ok {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+	row.status = arguments[1].(string)
+	row.endedReason = arguments[2].(string)
+	row.endedAt = arguments[3].(time.Time)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func TestUpdateMoneyDropEndMetadata(t *testing.T) {
+	endedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		status      string
+		endedReason string
+	}{
+		{name: "completed", status: "completed", endedReason: "completed"},
+		{name: "expired", status: "expired_and_refunded", endedReason: "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropID := uuid.New()
+			db := &fakeMoneyDropExecer{drops: map[uuid.UUID]*moneyDropEndRow{dropID: {status: "active"}}}
+
+			if err := updateMoneyDropEndMetadata(context.Background(), db, dropID, tt.status, tt.endedReason, endedAt); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			row := db.drops[dropID]
+			if row.status != tt.status || row.endedReason != tt.endedReason || !row.endedAt.Equal(endedAt) {
+				t.Fatalf("unexpected row after update: %+v", row)
+			}
+			if !strings.Contains(db.query, "updated_at = NOW()") {
+				t.Fatalf("expected updated_at to be bumped, query=%s", db.query)
+			}
+		})
+	}
+
+	t.Run("missing drop returns not found", func(t *testing.T) {
+		db := &fakeMoneyDropExecer{drops: map[uuid.UUID]*moneyDropEndRow{}}
+
+		err := updateMoneyDropEndMetadata(context.Background(), db, uuid.New(), "completed", "completed", endedAt)
+		if !errors.Is(err, ErrMoneyDropNotFound) {
+			t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+		}
+	})
+}
+
+// fakeRefundedAmountQuerier applies AddMoneyDropRefundedAmount writes to in-memory
+// drops, enforcing the same refunded_amount <= total_amount guard as the query.
+type fakeRefundedAmountQuerier struct {
+	drops map[uuid.UUID]*moneyDropRefundRow
+	query string
+}
+
+type moneyDropRefundRow struct {
+	totalAmount    int64
+	refundedAmount int64
+}
+
+type fakeRow struct {
+	values []any
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	for i := range dest {
+		*(dest[i].(*bool)) = r.values[i].(bool)
+	}
+	return nil
+}
+
+func (f *fakeRefundedAmountQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.query = sql
+	amount := args[0].(int64)
+	row, ok := f.drops[args[1].(uuid.UUID)]
+	if !ok {
+		return fakeRow{values: []any{false, false}}
+	}
+	if row.refundedAmount+amount > row.totalAmount {
+		return fakeRow{values: []any{true, false}}
+	}
+	row.refundedAmount += amount
+	return fakeRow{values: []any{true, true}}
+}
+
+func TestAddMoneyDropRefundedAmount(t *testing.T) {
+	t.Run("adds to the running total", func(t *testing.T) {
+		dropID := uuid.New()
+		db := &fakeRefundedAmountQuerier{drops: map[uuid.UUID]*moneyDropRefundRow{dropID: {totalAmount: 10000}}}
+
+		for _, amount := range []int64{4000, 6000} {
+			if err := addMoneyDropRefundedAmount(context.Background(), db, dropID, amount); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+		}
+		if got := db.drops[dropID].refundedAmount; got != 10000 {
+			t.Fatalf("expected refunded amount 10000, got %d", got)
+		}
+		if !strings.Contains(db.query, "refunded_amount + $1 <= total_amount") {
+			t.Fatalf("expected over-refund guard in query, query=%s", db.query)
+		}
+	})
+
+	t.Run("replayed refund is rejected once the total is reached", func(t *testing.T) {
+		dropID := uuid.New()
+		db := &fakeRefundedAmountQuerier{drops: map[uuid.UUID]*moneyDropRefundRow{dropID: {totalAmount: 5000}}}
+
+		if err := addMoneyDropRefundedAmount(context.Background(), db, dropID, 5000); err != nil {
+			t.Fatalf("expected first refund to apply, got %v", err)
+		}
+		err := addMoneyDropRefundedAmount(context.Background(), db, dropID, 5000)
+		if !errors.Is(err, ErrMoneyDropRefundExceedsTotal) {
+			t.Fatalf("expected ErrMoneyDropRefundExceedsTotal on replay, got %v", err)
+		}
+		if got := db.drops[dropID].refundedAmount; got != 5000 {
+			t.Fatalf("expected refunded amount to stay at 5000, got %d", got)
+		}
+	})
+
+	t.Run("over-refund is rejected without changing the total", func(t *testing.T) {
+		dropID := uuid.New()
+		db := &fakeRefundedAmountQuerier{drops: map[uuid.UUID]*moneyDropRefundRow{dropID: {totalAmount: 5000, refundedAmount: 3000}}}
+
+		err := addMoneyDropRefundedAmount(context.Background(), db, dropID, 2001)
+		if !errors.Is(err, ErrMoneyDropRefundExceedsTotal) {
+			t.Fatalf("expected ErrMoneyDropRefundExceedsTotal, got %v", err)
+		}
+		if got := db.drops[dropID].refundedAmount; got != 3000 {
+			t.Fatalf("expected refunded amount to stay at 3000, got %d", got)
+		}
+	})
+
+	t.Run("missing drop returns not found", func(t *testing.T) {
+		db := &fakeRefundedAmountQuerier{drops: map[uuid.UUID]*moneyDropRefundRow{}}
+
+		err := addMoneyDropRefundedAmount(context.Background(), db, uuid.New(), 1000)
+		if !errors.Is(err, ErrMoneyDropNotFound) {
+			t.Fatalf("expected ErrMoneyDropNotFound, got %v", err)
+		}
+	})
+
+	t.Run("non-positive amount is a no-op", func(t *testing.T) {
+		db := &fakeRefundedAmountQuerier{drops: map[uuid.UUID]*moneyDropRefundRow{}}
+
+		if err := addMoneyDropRefundedAmount(context.Background(), db, uuid.New(), 0); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if db.query != "" {
+			t.Fatalf("expected no query for zero amount")
+		}
+	})
+}
+
+// fakeMoneyDropClaimsTable stores money_drop_claims rows written by
+// insertMoneyDropClaim and answers the transaction_id lookup.
+type fakeMoneyDropClaimsTable struct {
+	dropByTransaction map[uuid.UUID]uuid.UUID
+	query             string
+}
+
+type fakeDropIDRow struct {
+	dropID uuid.UUID
+	err    error
+}
+
+func (r fakeDropIDRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*(dest[0].(*uuid.UUID)) = r.dropID
+	return nil
+}
+
+func (f *fakeMoneyDropClaimsTable) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	f.dropByTransaction[arguments[2].(uuid.UUID)] = arguments[0].(uuid.UUID)
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (f *fakeMoneyDropClaimsTable) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.query = sql
+	dropID, ok := f.dropByTransaction[args[0].(uuid.UUID)]
+	if !ok {
+		return fakeDropIDRow{err: pgx.ErrNoRows}
+	}
+	return fakeDropIDRow{dropID: dropID}
+}
+
+func TestFindMoneyDropClaimDropIDByTransactionID(t *testing.T) {
+	t.Run("returns the drop for the claim transaction", func(t *testing.T) {
+		dropID, txID := uuid.New(), uuid.New()
+		db := &fakeMoneyDropClaimsTable{dropByTransaction: map[uuid.UUID]uuid.UUID{txID: dropID, uuid.New(): uuid.New()}}
+
+		got, err := findMoneyDropClaimDropIDByTransactionID(context.Background(), db, txID)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got != dropID {
+			t.Fatalf("expected drop %s, got %s", dropID, got)
+		}
+		if !strings.Contains(db.query, "WHERE transaction_id = $1") {
+			t.Fatalf("expected lookup by transaction_id, query=%s", db.query)
+		}
+	})
+
+	t.Run("unknown transaction returns not found", func(t *testing.T) {
+		db := &fakeMoneyDropClaimsTable{dropByTransaction: map[uuid.UUID]uuid.UUID{}}
+
+		_, err := findMoneyDropClaimDropIDByTransactionID(context.Background(), db, uuid.New())
+		if !errors.Is(err, ErrTransactionNotFound) {
+			t.Fatalf("expected ErrTransactionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("resolves claims inserted by ClaimMoneyDropAtomic", func(t *testing.T) {
+		dropID, claimantID, txID := uuid.New(), uuid.New(), uuid.New()
+		db := &fakeMoneyDropClaimsTable{dropByTransaction: map[uuid.UUID]uuid.UUID{}}
+
+		if err := insertMoneyDropClaim(context.Background(), db, dropID, claimantID, txID); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		got, err := findMoneyDropClaimDropIDByTransactionID(context.Background(), db, txID)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got != dropID {
+			t.Fatalf("expected drop %s, got %s", dropID, got)
+		}
+	})
+}
+
+type claimTransactionRow struct {
+	status           string
+	anchorTransferID string
+	anchorReason     string
+	failureReason    string
+}
+
+// fakeClaimTransactionsExecer applies MarkMoneyDropClaimReconcileRequested writes to
+// in-memory money_drop_claim transactions using the same eligibility rule as the query.
+type fakeClaimTransactionsExecer struct {
+	rows  map[uuid.UUID]*claimTransactionRow
+	query string
+}
+
+func (f *fakeClaimTransactionsExecer) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	f.query = sql
+	row, ok := f.rows[arguments[2].(uuid.UUID)]
+	if !ok || row.status == "completed" {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+	if row.status != "failed" && strings.TrimSpace(row.anchorTransferID) == "" {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+	row.status = "pending"
+	row.anchorTransferID = ""
+	if failure := arguments[0].(string); failure != "" {
+		row.failureReason = failure
+	}
+	row.anchorReason = arguments[1].(string)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func TestMarkMoneyDropClaimReconcileRequested(t *testing.T) {
+	const retryReason = "md_drop:11111111-1111-1111-1111-111111111111;state:reconcile_retry_requested"
+	tests := []struct {
+		name        string
+		row         claimTransactionRow
+		wantUpdated bool
+	}{
+		{name: "pending claim submitted to anchor", row: claimTransactionRow{status: "pending", anchorTransferID: "atr_123"}, wantUpdated: true},
+		{name: "failed claim", row: claimTransactionRow{status: "failed", anchorTransferID: "atr_123"}, wantUpdated: true},
+		{name: "failed claim without transfer", row: claimTransactionRow{status: "failed"}, wantUpdated: true},
+		{name: "completed claim", row: claimTransactionRow{status: "completed", anchorTransferID: "atr_123"}, wantUpdated: false},
+		{name: "pending claim not yet submitted", row: claimTransactionRow{status: "pending", anchorReason: "md_drop:x;state:reconcile_retry_requested"}, wantUpdated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txID := uuid.New()
+			row := tt.row
+			db := &fakeClaimTransactionsExecer{rows: map[uuid.UUID]*claimTransactionRow{txID: &row}}
+
+			updated, err := markMoneyDropClaimReconcileRequested(context.Background(), db, txID, retryReason, "transfer failed")
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if updated != tt.wantUpdated {
+				t.Fatalf("expected updated=%v, got %v", tt.wantUpdated, updated)
+			}
+			if !tt.wantUpdated {
+				if row != tt.row {
+					t.Fatalf("expected row to be unchanged, got %+v", row)
+				}
+				return
+			}
+			if row.status != "pending" || row.anchorTransferID != "" {
+				t.Fatalf("expected claim reset to pending without transfer, got %+v", row)
+			}
+			if row.anchorReason != retryReason || row.failureReason != "transfer failed" {
+				t.Fatalf("unexpected reasons after mark: %+v", row)
+			}
+		})
+	}
+
+	t.Run("blank anchor reason falls back to generic retry marker", func(t *testing.T) {
+		txID := uuid.New()
+		row := claimTransactionRow{status: "failed", failureReason: "earlier failure"}
+		db := &fakeClaimTransactionsExecer{rows: map[uuid.UUID]*claimTransactionRow{txID: &row}}
+
+		updated, err := markMoneyDropClaimReconcileRequested(context.Background(), db, txID, "  ", "")
+		if err != nil || !updated {
+			t.Fatalf("expected update, got updated=%v err=%v", updated, err)
+		}
+		if row.anchorReason != "money_drop_claim;state:reconcile_retry_requested" {
+			t.Fatalf("unexpected anchor reason %q", row.anchorReason)
+		}
+		if row.failureReason != "earlier failure" {
+			t.Fatalf("expected blank failure reason to keep the existing one, got %q", row.failureReason)
+		}
+		if !strings.Contains(db.query, "type = 'money_drop_claim'") {
+			t.Fatalf("expected update to be scoped to money_drop_claim, query=%s", db.query)
+		}
+	})
+}
+
 func ptrString(value string) *string {
 	return &value
 }
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const paymentRequestSplitColumns = `
+	id, creator_id, title, description, total_amount, strategy, created_at, updated_at
+`
+
+func scanPaymentRequestSplit(row pgx.Row) (*domain.PaymentRequestSplit, error) {
+	var split domain.PaymentRequestSplit
+	if err := row.Scan(
+		&split.ID,
+		&split.CreatorID,
+		&split.Title,
+		&split.Description,
+		&split.TotalAmount,
+		&split.Strategy,
+		&split.CreatedAt,
+		&split.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &split, nil
+}
+
+// CreatePaymentRequestSplit stores the parent record of a split bill. Its payment
+// requests are created separately with the split's ID as their split_id.
+func (r *PostgresRepository) CreatePaymentRequestSplit(ctx context.Context, split *domain.PaymentRequestSplit) (*domain.PaymentRequestSplit, error) {
+	query := `
+		INSERT INTO payment_request_splits (id, creator_id, title, description, total_amount, strategy)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + paymentRequestSplitColumns
+	return scanPaymentRequestSplit(r.db.QueryRow(
+		ctx,
+		query,
+		split.ID,
+		split.CreatorID,
+		split.Title,
+		split.Description,
+		split.TotalAmount,
+		split.Strategy,
+	))
+}
+
+// GetPaymentRequestSplit returns a creator's split without its members. It returns
+// ErrPaymentRequestSplitNotFound when the split does not exist or belongs to someone else.
+func (r *PostgresRepository) GetPaymentRequestSplit(ctx context.Context, splitID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequestSplit, error) {
+	query := `SELECT ` + paymentRequestSplitColumns + ` FROM payment_request_splits WHERE id = $1 AND creator_id = $2`
+	split, err := scanPaymentRequestSplit(r.db.QueryRow(ctx, query, splitID, creatorID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestSplitNotFound
+		}
+		return nil, err
+	}
+	return split, nil
+}
+
+// ListPaymentRequestSplitMembers returns the payment requests of a split ordered by
+// recipient username. Requests the creator has deleted are left out.
+func (r *PostgresRepository) ListPaymentRequestSplitMembers(ctx context.Context, splitID uuid.UUID) ([]domain.PaymentRequestSplitMember, error) {
+	query := `
+        SELECT
+            pr.id,
+            pr.recipient_user_id,
+            COALESCE(NULLIF(btrim(pr.recipient_username_snapshot), ''), btrim(ru.username)) AS recipient_username,
+            COALESCE(pr.recipient_full_name_snapshot, ru.full_name) AS recipient_full_name,
+            pr.amount,
+            pr.amount_paid,
+            pr.status
+        FROM payment_requests pr
+        LEFT JOIN users ru ON ru.id = pr.recipient_user_id
+        WHERE pr.split_id = $1
+          AND pr.deleted_at IS NULL
+        ORDER BY recipient_username, pr.id
+    `
+	rows, err := r.db.Query(ctx, query, splitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]domain.PaymentRequestSplitMember, 0)
+	for rows.Next() {
+		var member domain.PaymentRequestSplitMember
+		if err := rows.Scan(
+			&member.RequestID,
+			&member.RecipientUserID,
+			&member.Username,
+			&member.FullName,
+			&member.Amount,
+			&member.AmountPaid,
+			&member.Status,
+		); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// FindPotByIDAndUserID retrieves an open pot owned by the user.
+func (r *PostgresRepository) FindPotByIDAndUserID(ctx context.Context, potID uuid.UUID, userID uuid.UUID) (*domain.Account, error) {
+	var account domain.Account
+	query := `
+		SELECT id, user_id, COALESCE(anchor_account_id, ''), balance
+		FROM accounts
+		WHERE id = $1 AND user_id = $2 AND account_type = 'pot' AND status = 'active'
+	`
+	err := r.db.QueryRow(ctx, query, potID, userID).Scan(&account.ID, &account.UserID, &account.AnchorAccountID, &account.Balance)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// MoveFundsBetweenAccounts atomically debits the source account and credits the destination
+// account. Both rows are locked in a stable order so concurrent moves cannot deadlock.
+func (r *PostgresRepository) MoveFundsBetweenAccounts(ctx context.Context, sourceAccountID uuid.UUID, destinationAccountID uuid.UUID, amount int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT id, balance FROM accounts WHERE id = ANY($1) ORDER BY id FOR UPDATE`, []uuid.UUID{sourceAccountID, destinationAccountID})
+	if err != nil {
+		return err
+	}
+	balances := make(map[uuid.UUID]int64, 2)
+	for rows.Next() {
+		var id uuid.UUID
+		var balance int64
+		if err := rows.Scan(&id, &balance); err != nil {
+			rows.Close()
+			return err
+		}
+		balances[id] = balance
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sourceBalance, ok := balances[sourceAccountID]
+	if !ok {
+		return ErrAccountNotFound
+	}
+	if _, ok := balances[destinationAccountID]; !ok {
+		return ErrAccountNotFound
+	}
+	if sourceBalance < amount {
+		return ErrInsufficientFunds
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE accounts SET balance = balance - $1, updated_at = NOW() WHERE id = $2", amount, sourceAccountID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE accounts SET balance = balance + $1, updated_at = NOW() WHERE id = $2", amount, destinationAccountID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
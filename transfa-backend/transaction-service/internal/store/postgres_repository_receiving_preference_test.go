@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakePreferenceDB serves user_receiving_preferences and beneficiaries from memory
+// and records every statement it runs.
+type fakePreferenceDB struct {
+	dbtx
+
+	preference    *preferenceRow
+	beneficiaryID *uuid.UUID
+	statements    []string
+	inserted      []any
+}
+
+type preferenceRow struct {
+	useExternal   bool
+	beneficiaryID *uuid.UUID
+}
+
+type scanFuncRow func(dest ...any) error
+
+func (f scanFuncRow) Scan(dest ...any) error { return f(dest...) }
+
+func (f *fakePreferenceDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.statements = append(f.statements, sql)
+	switch {
+	case strings.Contains(sql, "FROM user_receiving_preferences"):
+		return scanFuncRow(func(dest ...any) error {
+			if f.preference == nil {
+				return pgx.ErrNoRows
+			}
+			*dest[0].(*uuid.UUID) = args[0].(uuid.UUID)
+			*dest[1].(*bool) = f.preference.useExternal
+			*dest[2].(**uuid.UUID) = f.preference.beneficiaryID
+			*dest[3].(*time.Time) = time.Now()
+			*dest[4].(*time.Time) = time.Now()
+			return nil
+		})
+	case strings.Contains(sql, "FROM beneficiaries"):
+		return scanFuncRow(func(dest ...any) error {
+			if f.beneficiaryID == nil {
+				return pgx.ErrNoRows
+			}
+			*dest[0].(*uuid.UUID) = *f.beneficiaryID
+			return nil
+		})
+	}
+	return scanFuncRow(func(dest ...any) error { return pgx.ErrNoRows })
+}
+
+func (f *fakePreferenceDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.statements = append(f.statements, sql)
+	f.inserted = args
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func TestFindOrCreateReceivingPreference_ExistingPreferenceSkipsBeneficiaries(t *testing.T) {
+	beneficiaryID := uuid.New()
+	db := &fakePreferenceDB{preference: &preferenceRow{useExternal: true, beneficiaryID: &beneficiaryID}}
+	repo := &PostgresRepository{db: db}
+
+	preference, err := repo.FindOrCreateReceivingPreference(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("FindOrCreateReceivingPreference: %v", err)
+	}
+	if !preference.UseExternalAccount || preference.DefaultBeneficiaryID == nil || *preference.DefaultBeneficiaryID != beneficiaryID {
+		t.Fatalf("expected the stored preference, got %+v", preference)
+	}
+	if len(db.statements) != 1 {
+		t.Fatalf("expected a single query, got %d: %v", len(db.statements), db.statements)
+	}
+}
+
+func TestFindOrCreateReceivingPreference_CreatesWithOldestBeneficiary(t *testing.T) {
+	beneficiaryID := uuid.New()
+	db := &fakePreferenceDB{beneficiaryID: &beneficiaryID}
+	repo := &PostgresRepository{db: db}
+
+	preference, err := repo.FindOrCreateReceivingPreference(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("FindOrCreateReceivingPreference: %v", err)
+	}
+	if !preference.UseExternalAccount || preference.DefaultBeneficiaryID == nil || *preference.DefaultBeneficiaryID != beneficiaryID {
+		t.Fatalf("expected an external preference for %s, got %+v", beneficiaryID, preference)
+	}
+	if len(db.statements) != 3 || !strings.Contains(db.statements[1], "ORDER BY created_at ASC LIMIT 1") {
+		t.Fatalf("expected preference lookup, single beneficiary lookup and insert, got %v", db.statements)
+	}
+	if db.inserted[1] != true {
+		t.Fatalf("expected use_external_account=true to be inserted, got %v", db.inserted)
+	}
+}
+
+func TestFindOrCreateReceivingPreference_CreatesInternalWithoutBeneficiaries(t *testing.T) {
+	db := &fakePreferenceDB{}
+	repo := &PostgresRepository{db: db}
+
+	preference, err := repo.FindOrCreateReceivingPreference(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("FindOrCreateReceivingPreference: %v", err)
+	}
+	if preference.UseExternalAccount || preference.DefaultBeneficiaryID != nil {
+		t.Fatalf("expected the internal wallet preference, got %+v", preference)
+	}
+	if db.inserted[1] != false || db.inserted[2].(*uuid.UUID) != nil {
+		t.Fatalf("expected use_external_account=false without a beneficiary, got %v", db.inserted)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// ListRecentRecipients returns the distinct users the sender has completed P2P transfers to,
+// most recently paid first.
+func (r *PostgresRepository) ListRecentRecipients(ctx context.Context, senderID uuid.UUID, limit int) ([]domain.RecentRecipient, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	query := `
+		SELECT recent.recipient_id, btrim(u.username) AS username, u.full_name, recent.last_sent_at
+		FROM (
+			SELECT DISTINCT ON (t.recipient_id) t.recipient_id, t.created_at AS last_sent_at
+			FROM transactions t
+			WHERE t.sender_id = $1
+			  AND t.type = 'p2p'
+			  AND t.status = 'completed'
+			  AND t.recipient_id IS NOT NULL
+			  AND t.recipient_id <> $1
+			ORDER BY t.recipient_id, t.created_at DESC
+		) recent
+		JOIN users u ON u.id = recent.recipient_id
+		WHERE u.username IS NOT NULL
+		ORDER BY recent.last_sent_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, senderID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.RecentRecipient, 0, limit)
+	for rows.Next() {
+		var item domain.RecentRecipient
+		if err := rows.Scan(&item.UserID, &item.Username, &item.FullName, &item.LastSentAt); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	return results, rows.Err()
+}
+
+// favoriteRecipientSelect resolves can_receive from the recipient's primary wallet so
+// favorites of users who can no longer receive are still listed, just flagged.
+const favoriteRecipientSelect = `
+	SELECT
+		u.id,
+		COALESCE(btrim(u.username), '') AS username,
+		u.full_name,
+		(
+			u.username IS NOT NULL
+			AND EXISTS (
+				SELECT 1 FROM accounts a
+				WHERE a.user_id = u.id
+				  AND a.account_type = 'primary'
+				  AND a.status = 'active'
+			)
+		) AS can_receive,
+		f.created_at
+	FROM favorite_recipients f
+	JOIN users u ON u.id = f.recipient_user_id
+`
+
+func scanFavoriteRecipient(row pgx.Row) (*domain.FavoriteRecipient, error) {
+	var item domain.FavoriteRecipient
+	if err := row.Scan(&item.UserID, &item.Username, &item.FullName, &item.CanReceive, &item.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// AddFavoriteRecipient pins a recipient for the owner. Pinning an existing favorite is a no-op.
+func (r *PostgresRepository) AddFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (*domain.FavoriteRecipient, error) {
+	insertQuery := `
+		INSERT INTO favorite_recipients (owner_id, recipient_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (owner_id, recipient_user_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, insertQuery, ownerID, recipientID); err != nil {
+		return nil, err
+	}
+
+	query := favoriteRecipientSelect + `
+		WHERE f.owner_id = $1 AND f.recipient_user_id = $2
+	`
+	item, err := scanFavoriteRecipient(r.db.QueryRow(ctx, query, ownerID, recipientID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListFavoriteRecipients returns the owner's pinned recipients, newest first.
+func (r *PostgresRepository) ListFavoriteRecipients(ctx context.Context, ownerID uuid.UUID) ([]domain.FavoriteRecipient, error) {
+	query := favoriteRecipientSelect + `
+		WHERE f.owner_id = $1
+		ORDER BY f.created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.FavoriteRecipient, 0)
+	for rows.Next() {
+		item, err := scanFavoriteRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *item)
+	}
+	return results, rows.Err()
+}
+
+// RemoveFavoriteRecipient unpins a recipient. It reports whether a favorite was removed.
+func (r *PostgresRepository) RemoveFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (bool, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM favorite_recipients WHERE owner_id = $1 AND recipient_user_id = $2`, ownerID, recipientID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// SampleAccountsForReconciliation returns up to limit open accounts with an Anchor
+// account, drawn at random.
+func (r *PostgresRepository) SampleAccountsForReconciliation(ctx context.Context, limit int) ([]domain.Account, error) {
+	query := `
+		SELECT id, user_id, anchor_account_id, balance
+		FROM accounts
+		WHERE status <> 'closed'
+		  AND COALESCE(anchor_account_id, '') <> ''
+		ORDER BY random()
+		LIMIT $1
+	`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]domain.Account, 0, limit)
+	for rows.Next() {
+		var account domain.Account
+		if err := rows.Scan(&account.ID, &account.UserID, &account.AnchorAccountID, &account.Balance); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// ListReconciliationEntries returns the transactions created in [from, to) with an
+// Anchor transfer whose source or destination is one of anchorAccountIDs. With
+// includeAdmin it also returns the platform fees and fee collections paid into the
+// admin account in that window.
+func (r *PostgresRepository) ListReconciliationEntries(ctx context.Context, from, to time.Time, anchorAccountIDs []string, includeAdmin bool) ([]domain.ReconciliationEntry, error) {
+	query := `
+		SELECT t.id, 'transaction', t.anchor_transfer_id, t.amount
+		FROM transactions t
+		LEFT JOIN accounts src ON src.id = t.source_account_id
+		LEFT JOIN accounts dst ON dst.id = t.destination_account_id
+		WHERE t.created_at >= $1
+		  AND t.created_at < $2
+		  AND COALESCE(t.anchor_transfer_id, '') <> ''
+		  AND (
+		    src.anchor_account_id = ANY($3)
+		    OR dst.anchor_account_id = ANY($3)
+		    OR ($4 AND t.type = 'platform_fee')
+		  )
+		UNION ALL
+		SELECT f.id, 'fee_collection', f.anchor_transfer_id, f.amount
+		FROM fee_collections f
+		WHERE $4
+		  AND f.created_at >= $1
+		  AND f.created_at < $2
+	`
+	rows, err := r.db.Query(ctx, query, from, to, anchorAccountIDs, includeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.ReconciliationEntry
+	for rows.Next() {
+		var entry domain.ReconciliationEntry
+		if err := rows.Scan(&entry.ID, &entry.Source, &entry.AnchorTransferID, &entry.Amount); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+const reconciliationReportColumns = `
+	id, period_start, period_end, status, sample_size, accounts_checked, accounts_failed,
+	anchor_entries, internal_entries, matched, unmatched_anchor, unmatched_internal, created_at
+`
+
+func scanReconciliationReport(row pgx.Row) (*domain.ReconciliationReport, error) {
+	var report domain.ReconciliationReport
+	var unmatchedAnchor, unmatchedInternal []byte
+	if err := row.Scan(
+		&report.ID,
+		&report.PeriodStart,
+		&report.PeriodEnd,
+		&report.Status,
+		&report.SampleSize,
+		&report.AccountsChecked,
+		&report.AccountsFailed,
+		&report.AnchorEntries,
+		&report.InternalEntries,
+		&report.Matched,
+		&unmatchedAnchor,
+		&unmatchedInternal,
+		&report.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(unmatchedAnchor, &report.UnmatchedAnchor); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(unmatchedInternal, &report.UnmatchedInternal); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// CreateReconciliationReport stores a finished reconciliation run.
+func (r *PostgresRepository) CreateReconciliationReport(ctx context.Context, report domain.ReconciliationReport) (*domain.ReconciliationReport, error) {
+	if report.UnmatchedAnchor == nil {
+		report.UnmatchedAnchor = []domain.ReconciliationItem{}
+	}
+	if report.UnmatchedInternal == nil {
+		report.UnmatchedInternal = []domain.ReconciliationItem{}
+	}
+	unmatchedAnchor, err := json.Marshal(report.UnmatchedAnchor)
+	if err != nil {
+		return nil, err
+	}
+	unmatchedInternal, err := json.Marshal(report.UnmatchedInternal)
+	if err != nil {
+		return nil, err
+	}
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO reconciliation_reports (
+			id, period_start, period_end, status, sample_size, accounts_checked, accounts_failed,
+			anchor_entries, internal_entries, matched, unmatched_anchor, unmatched_internal
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12::jsonb)
+		RETURNING ` + reconciliationReportColumns
+	return scanReconciliationReport(r.db.QueryRow(ctx, query,
+		report.ID,
+		report.PeriodStart,
+		report.PeriodEnd,
+		report.Status,
+		report.SampleSize,
+		report.AccountsChecked,
+		report.AccountsFailed,
+		report.AnchorEntries,
+		report.InternalEntries,
+		report.Matched,
+		string(unmatchedAnchor),
+		string(unmatchedInternal),
+	))
+}
+
+// GetLatestReconciliationReport returns the most recently stored reconciliation run.
+func (r *PostgresRepository) GetLatestReconciliationReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	query := `SELECT ` + reconciliationReportColumns + ` FROM reconciliation_reports ORDER BY created_at DESC LIMIT 1`
+	report, err := scanReconciliationReport(r.db.QueryRow(ctx, query))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReconciliationReportNotFound
+		}
+		return nil, err
+	}
+	return report, nil
+}
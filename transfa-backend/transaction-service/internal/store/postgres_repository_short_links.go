@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const shortLinkColumns = `
+	id, code, money_drop_id, created_by, target_url, expires_at, click_count, created_at
+`
+
+func scanShortLink(row pgx.Row) (*domain.ShortLink, error) {
+	var item domain.ShortLink
+	if err := row.Scan(
+		&item.ID,
+		&item.Code,
+		&item.MoneyDropID,
+		&item.CreatedBy,
+		&item.TargetURL,
+		&item.ExpiresAt,
+		&item.ClickCount,
+		&item.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateShortLink stores a new short link. It returns ErrShortLinkCodeExists when the
+// code is already taken so the caller can retry with a fresh code.
+func (r *PostgresRepository) CreateShortLink(ctx context.Context, link domain.ShortLink) (*domain.ShortLink, error) {
+	query := `
+		INSERT INTO short_links (code, money_drop_id, created_by, target_url, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING ` + shortLinkColumns
+	item, err := scanShortLink(r.db.QueryRow(ctx, query, link.Code, link.MoneyDropID, link.CreatedBy, link.TargetURL, link.ExpiresAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShortLinkCodeExists
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// FindShortLinkByCode returns the short link for a code, expired or not.
+func (r *PostgresRepository) FindShortLinkByCode(ctx context.Context, code string) (*domain.ShortLink, error) {
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links WHERE code = $1`
+	item, err := scanShortLink(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShortLinkNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// IncrementShortLinkClickCount records one redirect through the short link.
+func (r *PostgresRepository) IncrementShortLinkClickCount(ctx context.Context, code string) error {
+	_, err := r.db.Exec(ctx, `UPDATE short_links SET click_count = click_count + 1 WHERE code = $1`, code)
+	return err
+}
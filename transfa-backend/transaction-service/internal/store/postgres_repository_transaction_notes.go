@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// UpsertTransactionNote sets the user's note on a transaction, replacing any earlier one.
+func (r *PostgresRepository) UpsertTransactionNote(ctx context.Context, transactionID, userID uuid.UUID, note string) (*domain.TransactionNote, error) {
+	query := `
+		INSERT INTO transaction_notes (transaction_id, user_id, note)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (transaction_id, user_id)
+		DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+		RETURNING transaction_id, note, updated_at
+	`
+	var item domain.TransactionNote
+	if err := r.db.QueryRow(ctx, query, transactionID, userID, note).Scan(&item.TransactionID, &item.Note, &item.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteTransactionNote removes the user's note on a transaction. Removing a note
+// that does not exist is not an error.
+func (r *PostgresRepository) DeleteTransactionNote(ctx context.Context, transactionID, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM transaction_notes WHERE transaction_id = $1 AND user_id = $2`, transactionID, userID)
+	return err
+}
+
+// AddTransactionAttachment records an attachment unless the user already has limit
+// attachments on the transaction, in which case it returns ErrTransactionAttachmentLimit.
+// An advisory lock on the transaction and user keeps concurrent uploads from both
+// passing the count.
+func (r *PostgresRepository) AddTransactionAttachment(ctx context.Context, attachment domain.TransactionAttachment, limit int) (*domain.TransactionAttachment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text || ':' || $2::text))`, attachment.TransactionID, attachment.UserID); err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM transaction_attachments WHERE transaction_id = $1 AND user_id = $2
+	`, attachment.TransactionID, attachment.UserID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count >= limit {
+		return nil, ErrTransactionAttachmentLimit
+	}
+
+	item := attachment
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO transaction_attachments (transaction_id, user_id, url)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, attachment.TransactionID, attachment.UserID, attachment.URL).Scan(&item.ID, &item.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindTransactionNotes returns the user's notes on the given transactions keyed by
+// transaction ID. Transactions without a note are absent from the map.
+func (r *PostgresRepository) FindTransactionNotes(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	notes := make(map[uuid.UUID]string)
+	if len(transactionIDs) == 0 {
+		return notes, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT transaction_id, note
+		FROM transaction_notes
+		WHERE user_id = $1 AND transaction_id = ANY($2)
+	`, userID, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID uuid.UUID
+		var note string
+		if err := rows.Scan(&transactionID, &note); err != nil {
+			return nil, err
+		}
+		notes[transactionID] = note
+	}
+	return notes, rows.Err()
+}
+
+// FindTransactionAttachments returns the user's attachments on the given transactions
+// keyed by transaction ID, oldest first.
+func (r *PostgresRepository) FindTransactionAttachments(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID][]domain.TransactionAttachment, error) {
+	attachments := make(map[uuid.UUID][]domain.TransactionAttachment)
+	if len(transactionIDs) == 0 {
+		return attachments, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, transaction_id, user_id, url, created_at
+		FROM transaction_attachments
+		WHERE user_id = $1 AND transaction_id = ANY($2)
+		ORDER BY created_at, id
+	`, userID, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.TransactionAttachment
+		if err := rows.Scan(&item.ID, &item.TransactionID, &item.UserID, &item.URL, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		attachments[item.TransactionID] = append(attachments[item.TransactionID], item)
+	}
+	return attachments, rows.Err()
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// dbtx is what PostgresRepository runs statements on: a pgxpool.Pool normally, or a
+// pgx.Tx inside WithTx. Begin on a pgx.Tx opens a savepoint, so methods that manage
+// their own transaction (DebitWallet and friends) still work when nested.
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// WithTx runs fn against a repository bound to a single transaction. The
+// transaction is rolled back if fn returns an error or panics.
+func (r *PostgresRepository) WithTx(ctx context.Context, fn func(txRepo Repository) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&PostgresRepository{db: tx}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+const unmatchedTransferEventColumns = `
+	id, anchor_transfer_id, event_key, event_type, payload, status, park_count, replay_attempts,
+	COALESCE(last_error, ''), transaction_id, created_at, updated_at, replayed_at
+`
+
+func scanUnmatchedTransferEvent(row pgx.Row) (*domain.UnmatchedTransferEvent, error) {
+	var item domain.UnmatchedTransferEvent
+	var payload []byte
+	if err := row.Scan(
+		&item.ID,
+		&item.AnchorTransferID,
+		&item.EventKey,
+		&item.EventType,
+		&payload,
+		&item.Status,
+		&item.ParkCount,
+		&item.ReplayAttempts,
+		&item.LastError,
+		&item.TransactionID,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.ReplayedAt,
+	); err != nil {
+		return nil, err
+	}
+	item.Payload = payload
+	return &item, nil
+}
+
+// ParkUnmatchedTransferEvent stores an event no transaction matched. Parking the same
+// transfer and event key again refreshes the payload and error, bumps park_count and
+// moves an already replayed event back to pending.
+func (r *PostgresRepository) ParkUnmatchedTransferEvent(ctx context.Context, event domain.UnmatchedTransferEvent) (*domain.UnmatchedTransferEvent, error) {
+	query := `
+		INSERT INTO unmatched_transfer_events (anchor_transfer_id, event_key, event_type, payload, last_error)
+		VALUES ($1, $2, $3, $4::jsonb, NULLIF($5, ''))
+		ON CONFLICT (anchor_transfer_id, event_key) DO UPDATE
+		SET payload = EXCLUDED.payload,
+		    event_type = EXCLUDED.event_type,
+		    last_error = EXCLUDED.last_error,
+		    status = 'pending',
+		    park_count = unmatched_transfer_events.park_count + 1,
+		    updated_at = NOW()
+		RETURNING ` + unmatchedTransferEventColumns
+	return scanUnmatchedTransferEvent(r.db.QueryRow(ctx, query, event.AnchorTransferID, event.EventKey, event.EventType, string(event.Payload), event.LastError))
+}
+
+// FindUnmatchedTransferEventByID returns a parked event, replayed or not.
+func (r *PostgresRepository) FindUnmatchedTransferEventByID(ctx context.Context, id uuid.UUID) (*domain.UnmatchedTransferEvent, error) {
+	query := `SELECT ` + unmatchedTransferEventColumns + ` FROM unmatched_transfer_events WHERE id = $1`
+	item, err := scanUnmatchedTransferEvent(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUnmatchedTransferEventNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListUnmatchedTransferEvents returns parked events with status, newest first. An
+// empty status lists every event.
+func (r *PostgresRepository) ListUnmatchedTransferEvents(ctx context.Context, status string, limit int) ([]domain.UnmatchedTransferEvent, error) {
+	query := `
+		SELECT ` + unmatchedTransferEventColumns + `
+		FROM unmatched_transfer_events
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.UnmatchedTransferEvent{}
+	for rows.Next() {
+		item, err := scanUnmatchedTransferEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *item)
+	}
+	return events, rows.Err()
+}
+
+// MarkUnmatchedTransferEventReplayed records that a replay applied the event to transactionID.
+func (r *PostgresRepository) MarkUnmatchedTransferEventReplayed(ctx context.Context, id uuid.UUID, transactionID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE unmatched_transfer_events
+		SET status = 'replayed',
+		    transaction_id = $2,
+		    replay_attempts = replay_attempts + 1,
+		    last_error = NULL,
+		    replayed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id, transactionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUnmatchedTransferEventNotFound
+	}
+	return nil
+}
+
+// RecordUnmatchedTransferEventReplayFailure keeps the event pending and stores why the replay failed.
+func (r *PostgresRepository) RecordUnmatchedTransferEventReplayFailure(ctx context.Context, id uuid.UUID, lastError string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE unmatched_transfer_events
+		SET replay_attempts = replay_attempts + 1,
+		    last_error = NULLIF($2, ''),
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id, lastError)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUnmatchedTransferEventNotFound
+	}
+	return nil
+}
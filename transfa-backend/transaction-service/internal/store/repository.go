@@ -21,89 +21,192 @@ import (
 	"github.com/transfa/transaction-service/internal/domain"
 )
 
-// Repository defines the set of methods for interacting with the database.
+// Repository defines the set of methods for interacting with the database. It is
+// composed of the focused interfaces below so that code needing only one area (and
+// its test doubles) can depend on that area alone.
 type Repository interface {
-	// User and Account methods
+	UserReader
+	SecurityCredentialStore
+	WalletStore
+	BeneficiaryStore
+	TransactionStore
+	TransferBatchStore
+	PaymentRequestStore
+	NotificationStore
+	TransferListStore
+	RecipientStore
+	DisputeStore
+	TransactionNoteStore
+	AuditStore
+	MoneyDropStore
+	ShortLinkStore
+	UnmatchedTransferEventStore
+	AuxiliaryTransferStore
+	ReconciliationStore
+
+	// WithTx runs fn with a Repository whose methods all run in one database
+	// transaction, committed only if fn returns nil. Do not make external calls
+	// (Anchor, other services) inside fn.
+	WithTx(ctx context.Context, fn func(txRepo Repository) error) error
+}
+
+// UserReader resolves users by their external and internal identifiers.
+type UserReader interface {
 	// Resolve internal UUID from Clerk user id (e.g., "user_abc123").
 	FindUserIDByClerkUserID(ctx context.Context, clerkUserID string) (string, error)
+	IsUserClosedByClerkUserID(ctx context.Context, clerkUserID string) (bool, error)
 	FindUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	FindCurrentUsernameByPreviousUsername(ctx context.Context, username string) (string, error)
 	FindUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	IsUserDelinquent(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// SecurityCredentialStore tracks transaction PIN attempts and lockouts.
+type SecurityCredentialStore interface {
 	GetUserSecurityCredentialByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserSecurityCredential, error)
 	RecordFailedTransactionPINAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockoutDurationSeconds int) (*domain.UserSecurityCredential, error)
 	ResetTransactionPINFailureState(ctx context.Context, userID uuid.UUID) error
+}
+
+// WalletStore reads and moves balances on user accounts, including savings pots.
+type WalletStore interface {
 	FindAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error)
-	UpdateAccountBalance(ctx context.Context, userID uuid.UUID, balance int64) error
+	DebitWallet(ctx context.Context, userID uuid.UUID, amount int64) error
+	DebitWalletForP2PTransfer(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64) error
+	CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error
+	ListAccountsForBalanceSync(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Account, error)
+	SyncAccountBalance(ctx context.Context, snapshot domain.Account, balance int64) (bool, error)
+	FindPotByIDAndUserID(ctx context.Context, potID uuid.UUID, userID uuid.UUID) (*domain.Account, error)
+	MoveFundsBetweenAccounts(ctx context.Context, sourceAccountID uuid.UUID, destinationAccountID uuid.UUID, amount int64) error
+}
+
+// BeneficiaryStore manages saved external accounts and where a user receives funds.
+type BeneficiaryStore interface {
 	FindBeneficiaryByID(ctx context.Context, beneficiaryID uuid.UUID, userID uuid.UUID) (*domain.Beneficiary, error)
 	FindBeneficiariesByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Beneficiary, error)
 	FindDefaultBeneficiaryByUserID(ctx context.Context, userID uuid.UUID) (*domain.Beneficiary, error)
 	FindOrCreateDefaultBeneficiary(ctx context.Context, userID uuid.UUID) (*domain.Beneficiary, error)
 	SetDefaultBeneficiary(ctx context.Context, userID uuid.UUID, beneficiaryID uuid.UUID) error
-
-	// Receiving preference methods
+	UpdateBeneficiaryVerification(ctx context.Context, beneficiaryID uuid.UUID, status string, accountName string) error
 	FindOrCreateReceivingPreference(ctx context.Context, userID uuid.UUID) (*domain.UserReceivingPreference, error)
 	UpdateReceivingPreference(ctx context.Context, userID uuid.UUID, useExternal bool, beneficiaryID *uuid.UUID) error
+}
 
-	// Platform fee methods
-	IsUserDelinquent(ctx context.Context, userID uuid.UUID) (bool, error)
-
-	// Transaction methods
+// TransactionStore records transactions, their status changes and history.
+type TransactionStore interface {
 	CreateTransaction(ctx context.Context, tx *domain.Transaction) error
 	UpdateTransactionStatus(ctx context.Context, transactionID uuid.UUID, anchorTransferID, status string) error
 	UpdateTransactionStatusAndFee(ctx context.Context, transactionID uuid.UUID, anchorTransferID, status string, fee int64) error
 	UpdateTransactionMetadata(ctx context.Context, transactionID uuid.UUID, metadata UpdateTransactionMetadataParams) error
-	DebitWallet(ctx context.Context, userID uuid.UUID, amount int64) error
-	CreditWallet(ctx context.Context, userID uuid.UUID, amount int64) error
+	UpdateTransactionDestinations(ctx context.Context, transactionID uuid.UUID, destinationAccountID *uuid.UUID, destinationBeneficiaryID *uuid.UUID) error
+	MarkTransactionAsFailed(ctx context.Context, transactionID uuid.UUID, anchorTransferID, failureReason string) error
+	MarkTransactionAsCompleted(ctx context.Context, transactionID uuid.UUID, anchorTransferID string) error
+	CancelPendingTransaction(ctx context.Context, transactionID uuid.UUID, senderID uuid.UUID) (int64, error)
+	ClaimTransactionForTransfer(ctx context.Context, transactionID uuid.UUID) error
+	RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) (bool, error)
+
+	FindTransactionsByUserID(ctx context.Context, userID uuid.UUID, filter domain.TransactionHistoryFilter) ([]domain.Transaction, error)
+	FindTransactionsBetweenUsers(ctx context.Context, userID uuid.UUID, counterpartyID uuid.UUID, limit int, offset int, includeArchive bool) ([]domain.Transaction, error)
+	FindTransactionByID(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error)
+	FindLikelyPaymentRequestSettlementTransaction(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64, description string, since time.Time) (*domain.Transaction, error)
+	FindTransactionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.Transaction, error)
+
+	ArchiveTransactionsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	LatestArchivedTransactionAt(ctx context.Context) (*time.Time, error)
+}
+
+// TransferBatchStore tracks bulk P2P transfers and their per-recipient items.
+type TransferBatchStore interface {
 	CreateTransferBatchWithItems(ctx context.Context, batch *domain.TransferBatch, items []domain.TransferBatchItem) error
 	CreateTransferBatch(ctx context.Context, batch *domain.TransferBatch) error
 	CreateTransferBatchItems(ctx context.Context, items []domain.TransferBatchItem) error
 	MarkTransferBatchItemCompleted(ctx context.Context, itemID uuid.UUID, transactionID uuid.UUID, fee int64) error
 	MarkTransferBatchItemFailed(ctx context.Context, itemID uuid.UUID, failureReason string) error
 	FinalizeTransferBatch(ctx context.Context, batchID uuid.UUID) (*domain.TransferBatch, error)
+}
 
-	// Payment Request methods
+// PaymentRequestStore manages payment requests from both the creator's and the
+// recipient's side.
+type PaymentRequestStore interface {
 	CreatePaymentRequest(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentRequest, error)
 	ListPaymentRequestsByCreator(ctx context.Context, creatorID uuid.UUID, opts domain.PaymentRequestListOptions) ([]domain.PaymentRequest, error)
 	GetPaymentRequestByID(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequest, error)
-	DeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error)
+	GetPublicPaymentRequestByID(ctx context.Context, requestID uuid.UUID) (*domain.PaymentRequest, error)
+	GetPublicPaymentRequestByShortCode(ctx context.Context, code string) (*domain.PaymentRequest, error)
+	SoftDeletePaymentRequest(ctx context.Context, requestID uuid.UUID, creatorID uuid.UUID) (bool, error)
 	ListIncomingPaymentRequests(ctx context.Context, recipientID uuid.UUID, opts domain.PaymentRequestListOptions) ([]domain.PaymentRequest, error)
 	GetIncomingPaymentRequestByID(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error)
-	ClaimIncomingPaymentRequestForPayment(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) (*domain.PaymentRequest, error)
+	ClaimIncomingPaymentRequestForPayment(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, amount int64) (*domain.PaymentRequest, error)
 	AttachProcessingPaymentRequestSettlementTransaction(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error)
 	MarkPaymentRequestFulfilled(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error)
 	MarkPaymentRequestFulfilledBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) (*domain.PaymentRequest, error)
-	ReleasePaymentRequestFromProcessing(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID) error
 	ReleasePaymentRequestFromProcessingBySettlementTransaction(ctx context.Context, settledTransactionID uuid.UUID) error
+	RecordPaymentRequestSettlement(ctx context.Context, requestID uuid.UUID, transactionID uuid.UUID, amount int64) error
+	ListPaymentRequestSettlements(ctx context.Context, requestID uuid.UUID) ([]domain.PaymentRequestSettlement, error)
 	DeclineIncomingPaymentRequest(ctx context.Context, requestID uuid.UUID, recipientID uuid.UUID, reason *string) (*domain.PaymentRequest, error)
+	CreatePaymentRequestSplit(ctx context.Context, split *domain.PaymentRequestSplit) (*domain.PaymentRequestSplit, error)
+	GetPaymentRequestSplit(ctx context.Context, splitID uuid.UUID, creatorID uuid.UUID) (*domain.PaymentRequestSplit, error)
+	ListPaymentRequestSplitMembers(ctx context.Context, splitID uuid.UUID) ([]domain.PaymentRequestSplitMember, error)
+}
 
-	// In-app notification methods
+// NotificationStore manages in-app notifications.
+type NotificationStore interface {
 	CreateInAppNotification(ctx context.Context, item domain.InAppNotification) error
 	ListInAppNotifications(ctx context.Context, userID uuid.UUID, opts domain.NotificationListOptions) ([]domain.InAppNotification, error)
 	MarkInAppNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) (bool, error)
 	MarkAllInAppNotificationsRead(ctx context.Context, userID uuid.UUID, category *string) (int64, error)
 	GetInAppNotificationUnreadCounts(ctx context.Context, userID uuid.UUID) (*domain.NotificationUnreadCounts, error)
+	MarkInAppNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	DeleteReadInAppNotificationsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
 
-	// Transfer list methods
+// TransferListStore manages saved groups of transfer recipients.
+type TransferListStore interface {
 	CreateTransferList(ctx context.Context, list *domain.TransferList, memberIDs []uuid.UUID) (*domain.TransferList, error)
 	ListTransferListsByOwner(ctx context.Context, ownerID uuid.UUID, opts domain.TransferListListOptions) ([]domain.TransferListSummary, error)
 	GetTransferListByID(ctx context.Context, ownerID uuid.UUID, listID uuid.UUID) (*domain.TransferList, error)
 	UpdateTransferList(ctx context.Context, ownerID uuid.UUID, listID uuid.UUID, name string, memberIDs []uuid.UUID) (*domain.TransferList, error)
 	DeleteTransferList(ctx context.Context, ownerID uuid.UUID, listID uuid.UUID) (bool, error)
+}
 
-	// Transaction history methods
-	FindTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Transaction, error)
-	FindTransactionsBetweenUsers(ctx context.Context, userID uuid.UUID, counterpartyID uuid.UUID, limit int, offset int) ([]domain.Transaction, error)
-	UpdateTransactionDestinations(ctx context.Context, transactionID uuid.UUID, destinationAccountID *uuid.UUID, destinationBeneficiaryID *uuid.UUID) error
-	FindTransactionByID(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error)
-	FindLikelyPaymentRequestSettlementTransaction(ctx context.Context, senderID uuid.UUID, recipientID uuid.UUID, amount int64, description string, since time.Time) (*domain.Transaction, error)
-	FindTransactionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.Transaction, error)
-	FindPendingMoneyDropClaimByAnchorParticipantsAndAmount(ctx context.Context, anchorAccountID string, counterpartyID string, amount int64) (*domain.Transaction, error)
-	MarkTransactionAsFailed(ctx context.Context, transactionID uuid.UUID, anchorTransferID, failureReason string) error
-	MarkTransactionAsCompleted(ctx context.Context, transactionID uuid.UUID, anchorTransferID string) error
-	RefundTransactionFee(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, fee int64) error
+// RecipientStore serves recent and favorite recipient shortcuts.
+type RecipientStore interface {
+	ListRecentRecipients(ctx context.Context, senderID uuid.UUID, limit int) ([]domain.RecentRecipient, error)
+	AddFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (*domain.FavoriteRecipient, error)
+	ListFavoriteRecipients(ctx context.Context, ownerID uuid.UUID) ([]domain.FavoriteRecipient, error)
+	RemoveFavoriteRecipient(ctx context.Context, ownerID uuid.UUID, recipientID uuid.UUID) (bool, error)
+}
+
+// DisputeStore manages transaction disputes.
+type DisputeStore interface {
+	CreateTransactionDispute(ctx context.Context, dispute domain.TransactionDispute) (*domain.TransactionDispute, error)
+	ListTransactionDisputes(ctx context.Context, filter domain.TransactionDisputeFilter) ([]domain.TransactionDispute, error)
+	ListTransactionDisputesByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.TransactionDispute, error)
+	FindTransactionDisputeByID(ctx context.Context, disputeID uuid.UUID) (*domain.TransactionDispute, error)
+	FindLatestTransactionDisputeByUser(ctx context.Context, transactionID, userID uuid.UUID) (*domain.TransactionDispute, error)
+	UpdateTransactionDisputeStatus(ctx context.Context, disputeID uuid.UUID, fromStatus, toStatus string, resolutionNote *string) (*domain.TransactionDispute, error)
+}
+
+// TransactionNoteStore keeps each party's private notes and attachments on transactions.
+type TransactionNoteStore interface {
+	UpsertTransactionNote(ctx context.Context, transactionID, userID uuid.UUID, note string) (*domain.TransactionNote, error)
+	DeleteTransactionNote(ctx context.Context, transactionID, userID uuid.UUID) error
+	AddTransactionAttachment(ctx context.Context, attachment domain.TransactionAttachment, limit int) (*domain.TransactionAttachment, error)
+	FindTransactionNotes(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID]string, error)
+	FindTransactionAttachments(ctx context.Context, userID uuid.UUID, transactionIDs []uuid.UUID) (map[uuid.UUID][]domain.TransactionAttachment, error)
+}
+
+// AuditStore persists and queries the audit trail.
+type AuditStore interface {
+	InsertAuditEvents(ctx context.Context, events []domain.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error)
+}
 
-	// Money Drop methods
+// MoneyDropStore manages money drops, their funding accounts and claims.
+type MoneyDropStore interface {
 	FindMoneyDropAccountByUserID(ctx context.Context, userID uuid.UUID) (*domain.Account, error)
 	CreateAccount(ctx context.Context, account *domain.Account) (*domain.Account, error)
+	UpdateMoneyDropAccountBalance(ctx context.Context, accountID uuid.UUID, balance int64) error
 	CreateMoneyDrop(ctx context.Context, drop *domain.MoneyDrop) (*domain.MoneyDrop, error)
 	FindMoneyDropByID(ctx context.Context, dropID uuid.UUID) (*domain.MoneyDrop, error)
 	FindMoneyDropByIDAndCreatorID(ctx context.Context, dropID, creatorID uuid.UUID) (*domain.MoneyDrop, error)
@@ -115,16 +218,60 @@ type Repository interface {
 	ListEndedMoneyDropsByCreator(ctx context.Context, creatorID uuid.UUID, limit int) ([]domain.MoneyDrop, error)
 	ListClaimedMoneyDropsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.ClaimedMoneyDropHistoryItem, error)
 	ListMoneyDropClaimsByDropID(ctx context.Context, dropID uuid.UUID, search string, limit int, offset int) ([]domain.MoneyDropClaimer, int, error)
+	FindPendingMoneyDropClaimByAnchorParticipantsAndAmount(ctx context.Context, anchorAccountID string, counterpartyID string, amount int64) (*domain.Transaction, error)
 	ListPendingMoneyDropClaimReconciliationCandidates(ctx context.Context, limit int, olderThan time.Time) ([]domain.PendingMoneyDropClaimReconciliationCandidate, error)
+	ListPendingMoneyDropClaimPayouts(ctx context.Context, createdAfter, idleBefore time.Time, limit int) ([]uuid.UUID, error)
+	MarkMoneyDropClaimPayoutInFlight(ctx context.Context, transactionID uuid.UUID, anchorReason string, idleBefore time.Time) (bool, error)
+	FindMoneyDropClaimPayout(ctx context.Context, transactionID uuid.UUID) (*domain.MoneyDropClaimPayout, error)
 	MarkMoneyDropClaimReconcileRequested(ctx context.Context, transactionID uuid.UUID, anchorReason string, failureReason string) (bool, error)
 	MarkMoneyDropClaimReconcileInFlight(ctx context.Context, transactionID uuid.UUID, anchorReason string) (bool, error)
 	FindExpiredAndCompletedMoneyDrops(ctx context.Context) ([]domain.MoneyDrop, error)
+	ListExpiredActiveMoneyDrops(ctx context.Context, limit int) ([]domain.ExpiredMoneyDrop, error)
 	AcquireMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID) (bool, bool, error)
 	ReleaseMoneyDropFinalizationLock(ctx context.Context, dropID uuid.UUID, restoreActive bool) error
 	UpdateMoneyDropStatus(ctx context.Context, dropID uuid.UUID, status string) error
 	UpdateMoneyDropEndMetadata(ctx context.Context, dropID uuid.UUID, status string, endedReason string, endedAt time.Time) error
 	AddMoneyDropRefundedAmount(ctx context.Context, dropID uuid.UUID, amount int64) error
-	UpdateMoneyDropAccountBalance(ctx context.Context, accountID uuid.UUID, balance int64) error
+}
+
+// ShortLinkStore manages shareable short links.
+type ShortLinkStore interface {
+	CreateShortLink(ctx context.Context, link domain.ShortLink) (*domain.ShortLink, error)
+	FindShortLinkByCode(ctx context.Context, code string) (*domain.ShortLink, error)
+	IncrementShortLinkClickCount(ctx context.Context, code string) error
+}
+
+// UnmatchedTransferEventStore parks transfer status events no transaction matched and
+// tracks their replays.
+type UnmatchedTransferEventStore interface {
+	ParkUnmatchedTransferEvent(ctx context.Context, event domain.UnmatchedTransferEvent) (*domain.UnmatchedTransferEvent, error)
+	FindUnmatchedTransferEventByID(ctx context.Context, id uuid.UUID) (*domain.UnmatchedTransferEvent, error)
+	ListUnmatchedTransferEvents(ctx context.Context, status string, limit int) ([]domain.UnmatchedTransferEvent, error)
+	MarkUnmatchedTransferEventReplayed(ctx context.Context, id uuid.UUID, transactionID uuid.UUID) error
+	RecordUnmatchedTransferEventReplayFailure(ctx context.Context, id uuid.UUID, lastError string) error
+}
+
+// AuxiliaryTransferStore tracks the fee collection and money drop funding transfers
+// made alongside a transaction, and the wallet credits owed when one fails.
+type AuxiliaryTransferStore interface {
+	CreateFeeCollection(ctx context.Context, fee domain.FeeCollection) error
+	FindFeeCollectionByAnchorTransferID(ctx context.Context, anchorTransferID string) (*domain.FeeCollection, error)
+	MarkFeeCollectionCompleted(ctx context.Context, id uuid.UUID) error
+	MarkFeeCollectionFailed(ctx context.Context, id uuid.UUID, reason string) (bool, error)
+	FlagTransactionFeeCollectionFailed(ctx context.Context, transactionID uuid.UUID) error
+	MarkMoneyDropFundingFailed(ctx context.Context, transactionID uuid.UUID, reason string) (bool, error)
+	EnqueueTransferCompensation(ctx context.Context, compensation domain.TransferCompensation) error
+	ListPendingTransferCompensations(ctx context.Context, limit int) ([]domain.TransferCompensation, error)
+	CompleteTransferCompensation(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// ReconciliationStore reads what the Anchor reconciliation compares against and keeps
+// its reports.
+type ReconciliationStore interface {
+	SampleAccountsForReconciliation(ctx context.Context, limit int) ([]domain.Account, error)
+	ListReconciliationEntries(ctx context.Context, from, to time.Time, anchorAccountIDs []string, includeAdmin bool) ([]domain.ReconciliationEntry, error)
+	CreateReconciliationReport(ctx context.Context, report domain.ReconciliationReport) (*domain.ReconciliationReport, error)
+	GetLatestReconciliationReport(ctx context.Context) (*domain.ReconciliationReport, error)
 }
 
 type UpdateTransactionMetadataParams struct {
@@ -134,4 +281,6 @@ type UpdateTransactionMetadataParams struct {
 	FailureReason    *string
 	AnchorSessionID  *string
 	AnchorReason     *string
+	RoutingDecision  *string
+	RoutingReason    *string
 }
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/transfa/transaction-service/internal/domain"
+)
+
+// planCheckDatabaseEnv names a scratch Postgres database for the plan check. The
+// test creates and drops its own schema there and never touches public tables.
+const planCheckDatabaseEnv = "TRANSACTION_HISTORY_PLAN_DATABASE_URL"
+
+const planCheckRows = 1_000_000
+
+// legacyTransactionHistoryQuery is the single-predicate query the UNION ALL form
+// replaced; kept here to compare plans.
+const legacyTransactionHistoryQuery = `
+	SELECT id, created_at
+	FROM transactions
+	WHERE (sender_id = $1 OR recipient_id = $1)
+	ORDER BY created_at DESC
+`
+
+func TestTransactionHistoryQuery_SplitsSenderAndRecipientBranches(t *testing.T) {
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	query, args := transactionHistoryQuery(domain.TransactionHistoryFilter{From: &from, To: &to})
+
+	if strings.Contains(query, " OR ") {
+		t.Fatalf("expected no OR across parties, got %s", query)
+	}
+	if strings.Count(query, "created_at >= $2 AND created_at < $3") != 2 {
+		t.Fatalf("expected both branches to carry the range as index bounds, got %s", query)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 range args, got %d", len(args))
+	}
+
+	query, args = transactionHistoryQuery(domain.TransactionHistoryFilter{})
+	if strings.Contains(query, "created_at >=") || strings.Contains(query, "created_at <") || len(args) != 0 {
+		t.Fatalf("expected no range predicates without a range, got %s (%d args)", query, len(args))
+	}
+}
+
+func TestTransactionHistoryQuery_FiltersByTypeAndStatus(t *testing.T) {
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	types := []string{"p2p", "self_transfer"}
+
+	query, args := transactionHistoryQuery(domain.TransactionHistoryFilter{From: &from, Types: types, Status: "completed"})
+	if strings.Count(query, "created_at >= $2 AND type = ANY($3) AND status = $4") != 2 {
+		t.Fatalf("expected both branches to carry the type and status filters, got %s", query)
+	}
+	if len(args) != 3 || args[2] != "completed" {
+		t.Fatalf("expected range, types and status args, got %v", args)
+	}
+
+	query, args = transactionHistoryQuery(domain.TransactionHistoryFilter{Types: types})
+	if strings.Count(query, "type = ANY($2)") != 2 || strings.Contains(query, "status =") || len(args) != 1 {
+		t.Fatalf("expected a type filter only, got %s (%d args)", query, len(args))
+	}
+
+	query, args = transactionHistoryQuery(domain.TransactionHistoryFilter{Types: []string{}, Status: "pending"})
+	if strings.Contains(query, "type = ANY") || strings.Count(query, "status = $2") != 2 || len(args) != 1 {
+		t.Fatalf("expected an empty type list to match every type, got %s (%d args)", query, len(args))
+	}
+}
+
+// TestTransactionHistoryQuery_PlanOnSeededTable seeds ~1M transactions and checks that
+// the history query is served by the per-party indexes rather than a sequential scan,
+// logging the legacy OR plan's timing next to it. Skipped unless
+// TRANSACTION_HISTORY_PLAN_DATABASE_URL is set.
+func TestTransactionHistoryQuery_PlanOnSeededTable(t *testing.T) {
+	databaseURL := os.Getenv(planCheckDatabaseEnv)
+	if databaseURL == "" {
+		t.Skipf("%s not set", planCheckDatabaseEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	schema := "history_plan_" + uuid.NewString()[:8]
+	setup := []string{
+		`CREATE SCHEMA ` + schema,
+		`SET search_path TO ` + schema,
+		`CREATE TABLE transactions (
+			id UUID PRIMARY KEY,
+			anchor_transfer_id TEXT,
+			sender_id UUID,
+			recipient_id UUID,
+			source_account_id UUID NOT NULL,
+			destination_account_id UUID,
+			destination_beneficiary_id UUID,
+			type TEXT NOT NULL,
+			category TEXT,
+			status TEXT NOT NULL,
+			amount BIGINT NOT NULL,
+			fee BIGINT NOT NULL DEFAULT 0,
+			description TEXT,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`,
+		// 20,000 users, so each has about 100 transactions on either side.
+		`INSERT INTO transactions
+		SELECT gen_random_uuid(), NULL,
+		       ('00000000-0000-4000-8000-' || lpad(to_hex(g % 20000), 12, '0'))::uuid,
+		       ('00000000-0000-4000-8000-' || lpad(to_hex((g * 7 + 1) % 20000), 12, '0'))::uuid,
+		       gen_random_uuid(), gen_random_uuid(), NULL, 'p2p', 'p2p_transfer',
+		       CASE WHEN g % 50 = 0 THEN 'failed' ELSE 'completed' END,
+		       1000 + g % 5000, 500, NULL,
+		       NOW() - (g || ' seconds')::interval, NOW()
+		FROM generate_series(1, ` + strconv.Itoa(planCheckRows) + `) AS g`,
+		`CREATE INDEX ON transactions (sender_id, created_at DESC)`,
+		`CREATE INDEX ON transactions (recipient_id, created_at DESC)`,
+		`CREATE INDEX ON transactions (status, created_at)`,
+		`ANALYZE transactions`,
+	}
+	t.Cleanup(func() {
+		_, _ = conn.Exec(context.Background(), `DROP SCHEMA IF EXISTS `+schema+` CASCADE`)
+	})
+	for _, statement := range setup {
+		if _, err := conn.Exec(ctx, statement); err != nil {
+			t.Fatalf("setup %q: %v", strings.Fields(statement)[0], err)
+		}
+	}
+
+	userID := uuid.MustParse("00000000-0000-4000-8000-000000000042")
+	query, _ := transactionHistoryQuery(domain.TransactionHistoryFilter{})
+
+	newPlan, newMS := explainAnalyze(ctx, t, conn, query, userID)
+	_, legacyMS := explainAnalyze(ctx, t, conn, legacyTransactionHistoryQuery, userID)
+	t.Logf("history query on %d rows: union all %.2fms, legacy OR %.2fms", planCheckRows, newMS, legacyMS)
+
+	if strings.Contains(newPlan, `"Seq Scan"`) {
+		t.Fatalf("expected index scans only, got plan %s", newPlan)
+	}
+	for _, index := range []string{"transactions_sender_id_created_at_idx", "transactions_recipient_id_created_at_idx"} {
+		if !strings.Contains(newPlan, index) {
+			t.Fatalf("expected plan to use %s, got %s", index, newPlan)
+		}
+	}
+}
+
+func explainAnalyze(ctx context.Context, t *testing.T, conn *pgx.Conn, query string, userID uuid.UUID) (string, float64) {
+	t.Helper()
+	var raw []byte
+	if err := conn.QueryRow(ctx, `EXPLAIN (ANALYZE, FORMAT JSON) `+query, userID).Scan(&raw); err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	var plans []struct {
+		ExecutionTime float64 `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil || len(plans) == 0 {
+		t.Fatalf("decode plan: %v", err)
+	}
+	return string(raw), plans[0].ExecutionTime
+}
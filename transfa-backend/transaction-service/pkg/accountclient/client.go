@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -78,7 +79,8 @@ func (c *Client) CreateMoneyDropAccount(ctx context.Context, userID string) (*Cr
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("account service returned error status %d", resp.StatusCode)
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("account service returned error status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
 	}
 
 	var response CreateMoneyDropAccountResponse
@@ -0,0 +1,47 @@
+package accountclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateMoneyDropAccount_ParsesAnchorAccountID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/internal/accounts/money-drop" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-Internal-API-Key") != "secret" {
+			t.Errorf("expected the internal API key header, got %q", r.Header.Get("X-Internal-API-Key"))
+		}
+		var req CreateMoneyDropAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID != "user-1" {
+			t.Errorf("unexpected body %+v (err %v)", req, err)
+		}
+		_, _ = w.Write([]byte(`{"account_id":"acct-1","anchor_account_id":"anchor-acct-1","virtual_nuban":"0123456789","bank_name":"Anchor Bank"}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(server.URL+"/", "secret").CreateMoneyDropAccount(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("CreateMoneyDropAccount: %v", err)
+	}
+	if resp.AnchorAccountID != "anchor-acct-1" || resp.AccountID != "acct-1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestCreateMoneyDropAccount_IncludesErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "anchor API request failed with status 422: Invalid customer", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "secret").CreateMoneyDropAccount(context.Background(), "user-1")
+	if err == nil || !strings.Contains(err.Error(), "502") || !strings.Contains(err.Error(), "Invalid customer") {
+		t.Fatalf("expected the status and account service message, got %v", err)
+	}
+}
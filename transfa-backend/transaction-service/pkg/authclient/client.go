@@ -0,0 +1,119 @@
+/**
+ * @description
+ * This package provides a client for communicating with the auth-service, which owns
+ * transaction PINs. Verifying through it keeps the PIN lockout counter in one place
+ * for every service that asks for a PIN.
+ */
+package authclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors returned by VerifyTransactionPIN for the auth-service's PIN error codes.
+var (
+	ErrPINNotSet  = errors.New("transaction pin is not set")
+	ErrPINLocked  = errors.New("transaction pin is temporarily locked")
+	ErrPINInvalid = errors.New("transaction pin is invalid")
+)
+
+// pinErrorsByCode also accepts the codes the auth-service sent before it moved to the
+// shared error envelope, so a rollout in either order keeps PIN errors mapped.
+var pinErrorsByCode = map[string]error{
+	"transaction_pin_not_set": ErrPINNotSet,
+	"transaction_pin_locked":  ErrPINLocked,
+	"transaction_pin_invalid": ErrPINInvalid,
+	"PIN_NOT_SET":             ErrPINNotSet,
+	"PIN_LOCKED":              ErrPINLocked,
+	"PIN_INVALID":             ErrPINInvalid,
+}
+
+// Client is a client for the auth service.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a new auth service client.
+func NewClient(baseURL string, apiKey string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type verifyTransactionPINRequest struct {
+	UserID string `json:"user_id"`
+	Pin    string `json:"pin"`
+}
+
+// errorResponse reads both the shared error envelope and the legacy flat body.
+type errorResponse struct {
+	Error  json.RawMessage `json:"error"`
+	Code   string          `json:"code"`
+	Detail string          `json:"detail"`
+}
+
+// codeAndMessage returns the error code and message from whichever body shape the
+// auth-service sent.
+func (e errorResponse) codeAndMessage() (string, string) {
+	var envelope struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if len(e.Error) > 0 && json.Unmarshal(e.Error, &envelope) == nil {
+		return envelope.Code, envelope.Message
+	}
+	return e.Code, e.Detail
+}
+
+// VerifyTransactionPIN checks pin for the internal user id. A wrong, locked or missing
+// PIN is reported as ErrPINInvalid, ErrPINLocked or ErrPINNotSet.
+func (c *Client) VerifyTransactionPIN(ctx context.Context, userID string, pin string) error {
+	if c.baseURL == "" {
+		return fmt.Errorf("auth service base url is empty")
+	}
+
+	url := fmt.Sprintf("%s/internal/verify-transaction-pin", c.baseURL)
+
+	body, err := json.Marshal(verifyTransactionPINRequest{UserID: userID, Pin: pin})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if strings.TrimSpace(c.apiKey) != "" {
+		req.Header.Set("X-Internal-API-Key", strings.TrimSpace(c.apiKey))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request to auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	var failure errorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&failure)
+	code, message := failure.codeAndMessage()
+	if pinErr, ok := pinErrorsByCode[code]; ok {
+		return pinErr
+	}
+	return fmt.Errorf("auth service returned error status %d: %s", resp.StatusCode, message)
+}